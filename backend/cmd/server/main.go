@@ -1,80 +1,260 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/joho/godotenv"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	_ "backend/docs"
+	"backend/internal/config"
 	"backend/internal/handlers"
 	"backend/internal/middleware"
 	"backend/internal/repository"
 	"backend/internal/routes"
 	"backend/internal/services"
+	"backend/pkg/cache"
 	"backend/pkg/database"
+	"backend/pkg/logger"
+	"backend/pkg/mailer"
+	"backend/pkg/payments"
+	"backend/pkg/sms"
+	"backend/pkg/storage"
+	"backend/pkg/utils"
 )
 
 // @title User Management API
 // @version 1.0
 // @description A user management REST API with authentication and role-based access control
 // @host localhost:8080
-// @BasePath /
+// @BasePath /api/v1
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
 func main() {
+	migrateOnly := flag.Bool("migrate", false, "run database migrations and exit")
+	configExample := flag.Bool("config-example", false, "print every setting with its default and exit")
+	flag.Parse()
+
+	if *configExample {
+		fmt.Print(config.Example())
+		return
+	}
+
 	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
+		slog.Info("No .env file found")
 	}
 
+	logger.Init(slog.LevelInfo)
+
+	// Refuse to start with a list of every missing or invalid setting
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+	logger.Init(cfg.LogLevel)
+	utils.ConfigureJWT(cfg.JWT)
+	utils.ConfigurePhones(cfg.PhoneCountryCode)
+	middleware.ConfigureRateLimits(cfg.RateLimit)
+	middleware.ConfigureBodyLimits(cfg.BodyLimits)
+	handlers.ConfigurePublicCache(cfg.PublicCacheMaxAge)
+	// Zone of new businesses that don't choose their own
+	services.ConfigureDefaultTimezone(cfg.BusinessTimezone)
+
 	// Connect to database
-	database.Connect()
+	database.Connect(cfg.Database)
 	defer database.Close()
 
-	// Run database migrations
-	database.Migrate()
+	// Run database migrations, unless RUN_MIGRATIONS=false because they are run
+	// separately with -migrate
+	if *migrateOnly {
+		database.Migrate()
+		return
+	}
+	if cfg.RunMigrations {
+		database.Migrate()
+	} else {
+		slog.Info("Skipping database migrations (RUN_MIGRATIONS=false)")
+	}
+
+	// Initialize repositories, all sharing the one connection pool
+	db := database.DB
+	userRepo := repository.NewUserRepository(db)
+	packageRepo := repository.NewPackageRepository(db)
+	businessRepo := repository.NewBusinessRepository(db)
+	studentRepo := repository.NewStudentRepository(db)
+	studentFieldRepo := repository.NewStudentFieldRepository(db)
+	studentAttendanceRepo := repository.NewStudentAttendanceRepository(db)
+	teacherRepo := repository.NewTeacherRepository(db)
+	teacherAttendanceRepo := repository.NewTeacherAttendanceRepository(db)
+	teacherAvailabilityRepo := repository.NewTeacherAvailabilityRepository(db)
+	teacherDocumentRepo := repository.NewTeacherDocumentRepository(db)
+	teacherStudentRepo := repository.NewTeacherStudentRepository(db)
+	subjectRepo := repository.NewSubjectRepository(db)
+	qualificationRepo := repository.NewQualificationRepository(db)
+	batchRepo := repository.NewBatchRepository(db)
+	feeRepo := repository.NewFeeRepository(db)
+	payrollRepo := repository.NewPayrollRepository(db)
+	examRepo := repository.NewExamRepository(db)
+	announcementRepo := repository.NewAnnouncementRepository(db)
+	guardianLinkRepo := repository.NewGuardianLinkRepository(db)
+	maintenanceRepo := repository.NewMaintenanceRepository(db)
+	passwordTokenRepo := repository.NewPasswordTokenRepository(db)
+	smsRepo := repository.NewSMSRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	businessArchiveRepo := repository.NewBusinessArchiveRepository(db)
+	provisioningRepo := repository.NewProvisioningRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
+	consistencyRepo := repository.NewConsistencyRepository(db)
+	businessSignupRepo := repository.NewBusinessSignupRepository(db)
+	businessSnapshotRepo := repository.NewBusinessSnapshotRepository(db)
 
-	// Initialize repositories
-	userRepo := repository.NewUserRepository()
-	packageRepo := repository.NewPackageRepository()
-	businessRepo := repository.NewBusinessRepository()
+	store := storage.NewLocalStorage(cfg.StoragePath)
+	appCache := cache.New(cfg.Cache)
+	// Handlers only enqueue emails; the queue sends them in the background with retries
+	mailQueue := mailer.NewQueue(mailer.New(cfg.SMTP), mailer.QueueConfig{})
+	// Reads of personal data are logged in the background too, dropping entries rather
+	// than slowing requests when the queue is full
+	auditService := services.NewAuditService(auditLogRepo, cfg.Audit)
 
 	// Initialize services
-	userService := services.NewUserService(userRepo)
-	packageService := services.NewPackageService(packageRepo)
-	businessService := services.NewBusinessService(businessRepo, userRepo, packageRepo)
+	passwordService := services.NewPasswordService(userRepo, passwordTokenRepo, mailQueue, cfg.PasswordLinks)
+	smsService := services.NewSMSService(smsRepo, businessRepo, sms.New(cfg.SMS), cfg.SMSLimits)
+	notificationService := services.NewNotificationService(notificationRepo, businessRepo)
+	userService := services.NewUserService(userRepo, businessRepo, teacherRepo, studentRepo)
+	packageService := services.NewPackageService(packageRepo, appCache)
+	provisioningService := services.NewProvisioningService(provisioningRepo, studentRepo, userRepo)
+	businessService := services.NewBusinessService(businessRepo, userRepo, packageRepo, appCache, passwordService, notificationService, store, provisioningService)
+	studentService := services.NewStudentService(studentRepo, userRepo, businessRepo, batchRepo, studentFieldRepo)
+	studentFieldService := services.NewStudentFieldService(studentFieldRepo, businessRepo)
+	studentAttendanceService := services.NewStudentAttendanceService(studentAttendanceRepo, studentRepo, batchRepo, businessRepo, smsService)
+	studentTimelineService := services.NewStudentTimelineService(studentRepo, feeRepo, examRepo, userRepo, businessRepo)
+	teacherService := services.NewTeacherService(teacherRepo, userRepo, businessRepo, subjectRepo, teacherAvailabilityRepo, teacherDocumentRepo, teacherStudentRepo, store, qualificationRepo)
+	purgeService := services.NewPurgeService(teacherService, studentService, cfg.PurgeRetention)
+	teacherAttendanceService := services.NewTeacherAttendanceService(teacherAttendanceRepo, teacherRepo, businessRepo)
+	teacherDocumentService := services.NewTeacherDocumentService(teacherDocumentRepo, teacherRepo, store)
+	teacherStudentService := services.NewTeacherStudentService(teacherStudentRepo, teacherRepo, studentRepo)
+	subjectService := services.NewSubjectService(subjectRepo, businessRepo)
+	qualificationService := services.NewQualificationService(qualificationRepo, businessRepo)
+	batchService := services.NewBatchService(batchRepo, studentRepo, teacherRepo, businessRepo)
+	feeService := services.NewFeeService(feeRepo, studentRepo, batchRepo, businessRepo, smsService)
+	payrollService := services.NewPayrollService(payrollRepo, teacherRepo, teacherAttendanceRepo, businessRepo)
+	webhookService := services.NewWebhookService(webhookRepo, feeService, payments.New(cfg.Payments))
+	examService := services.NewExamService(examRepo, studentRepo, batchRepo, subjectRepo, businessRepo)
+	announcementService := services.NewAnnouncementService(announcementRepo, studentRepo, teacherRepo, batchRepo, businessRepo)
+	guardianLinkService := services.NewGuardianLinkService(guardianLinkRepo, studentRepo, studentAttendanceRepo, examRepo, feeRepo, businessRepo, store)
+	maintenanceService := services.NewMaintenanceService(maintenanceRepo)
+	consistencyService := services.NewConsistencyService(consistencyRepo, businessRepo, teacherRepo, studentRepo, userRepo)
+	reportService := services.NewReportService(reportRepo, userRepo, businessRepo, studentAttendanceRepo, feeService, mailQueue, cfg.Report)
+	documentService := services.NewDocumentService(businessRepo, studentRepo, batchRepo, feeRepo, studentAttendanceRepo, examService, store)
+	businessSignupService := services.NewBusinessSignupService(businessSignupRepo, userRepo, businessService, notificationService, mailQueue, services.BusinessSignupConfig{
+		AppName: cfg.AppName,
+	})
+	businessSnapshotService := services.NewBusinessSnapshotService(businessSnapshotRepo, businessRepo, cfg.Snapshot)
+	businessArchiveService := services.NewBusinessArchiveService(businessArchiveRepo, businessRepo, userRepo, provisioningService)
+	calendarService := services.NewCalendarService(businessRepo, batchRepo, examRepo, teacherAvailabilityRepo, cfg.Calendar)
+	photoService := services.NewPhotoService(studentRepo, teacherRepo, businessRepo, store)
+	permissionService := services.NewPermissionService(businessRepo, teacherRepo, studentRepo)
+	searchService := services.NewSearchService(userRepo, businessRepo, teacherRepo, studentRepo, cfg.SearchTimeout)
 
 	// Initialize handlers
-	userHandler := handlers.NewUserHandler(userService)
-	packageHandler := handlers.NewPackageHandler(packageService)
-	businessHandler := handlers.NewBusinessHandler(businessService)
+	apiHandlers := routes.Handlers{
+		User:              handlers.NewUserHandler(userService),
+		Package:           handlers.NewPackageHandler(packageService),
+		Business:          handlers.NewBusinessHandler(businessService),
+		Student:           handlers.NewStudentHandler(studentService, auditService),
+		StudentField:      handlers.NewStudentFieldHandler(studentFieldService),
+		StudentAttendance: handlers.NewStudentAttendanceHandler(studentAttendanceService),
+		StudentTimeline:   handlers.NewStudentTimelineHandler(studentTimelineService),
+		Teacher:           handlers.NewTeacherHandler(teacherService, auditService),
+		TeacherAttendance: handlers.NewTeacherAttendanceHandler(teacherAttendanceService, teacherService),
+		TeacherDocument:   handlers.NewTeacherDocumentHandler(teacherDocumentService, teacherService),
+		TeacherStudent:    handlers.NewTeacherStudentHandler(teacherStudentService, teacherService),
+		Subject:           handlers.NewSubjectHandler(subjectService),
+		Qualification:     handlers.NewQualificationHandler(qualificationService),
+		Batch:             handlers.NewBatchHandler(batchService),
+		Fee:               handlers.NewFeeHandler(feeService),
+		Payroll:           handlers.NewPayrollHandler(payrollService),
+		Exam:              handlers.NewExamHandler(examService),
+		Announcement:      handlers.NewAnnouncementHandler(announcementService),
+		GuardianLink:      handlers.NewGuardianLinkHandler(guardianLinkService),
+		Maintenance:       handlers.NewMaintenanceHandler(maintenanceService),
+		Consistency:       handlers.NewConsistencyHandler(consistencyService),
+		Search:            handlers.NewSearchHandler(searchService),
+		Password:          handlers.NewPasswordHandler(passwordService),
+		SMS:               handlers.NewSMSHandler(smsService),
+		Report:            handlers.NewReportHandler(reportService),
+		Document:          handlers.NewDocumentHandler(documentService),
+		Notification:      handlers.NewNotificationHandler(notificationService),
+		Webhook:           handlers.NewWebhookHandler(webhookService),
+		Calendar:          handlers.NewCalendarHandler(calendarService),
+		BusinessArchive:   handlers.NewBusinessArchiveHandler(businessArchiveService, businessService, auditService),
+		BusinessSignup:    handlers.NewBusinessSignupHandler(businessSignupService),
+		BusinessSnapshot:  handlers.NewBusinessSnapshotHandler(businessSnapshotService),
+		Permission:        handlers.NewPermissionHandler(permissionService),
+		Audit:             handlers.NewAuditHandler(auditService),
+		Photo:             handlers.NewPhotoHandler(photoService, teacherService),
+	}
+	healthHandler := handlers.NewHealthHandler(map[string]handlers.DependencyCheck{
+		"database": handlers.DatabaseCheck,
+	})
+
+	// Strict mode rejects JSON bodies carrying fields the request types don't declare
+	binding.EnableDecoderDisallowUnknownFields = cfg.StrictJSON
+
+	r := gin.New()
+	middleware.ConfigureTrustedProxies(r, cfg.TrustedProxies)
+	r.Use(gin.Recovery())
 
-	r := gin.Default()
+	// Request IDs first so everything after can log with them
+	r.Use(middleware.RequestIDMiddleware())
+	r.Use(middleware.RequestLoggerMiddleware())
+
+	// Cancel database work of requests that run too long
+	r.Use(middleware.QueryTimeoutMiddleware(cfg.QueryTimeout))
 
 	// Add CORS middleware
-	r.Use(middleware.CORSMiddleware())
+	r.Use(middleware.CORSMiddleware(cfg.CORS))
+
+	// Answer 503 while maintenance mode is on, except for probes and the toggle
+	r.Use(middleware.MaintenanceMiddleware(maintenanceService, cfg.MaintenanceAdminIDs, routes.MaintenanceExemptPaths()...))
+
+	// Cap request bodies; auth, import and upload routes set their own limits
+	r.Use(middleware.BodyLimit("default"))
+
+	// Write statuses as names for clients that ask for it while they migrate off 0 and 1
+	r.Use(middleware.StatusFormatMiddleware())
+
+	// Health probes (no auth)
+	routes.SetupHealthRoutes(r, healthHandler)
 
 	// Swagger endpoint
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	api := r.Group("/api")
-	{
-		// Setup user routes
-		routes.SetupUserRoutes(api, userHandler)
-		routes.SetupPackageRoutes(api, packageHandler)
-		routes.SetupBusinessRoutes(api, businessHandler) // Add this line
-	}
+	// API routes under /api/v1, with /api kept as an alias of v1
+	routes.SetupAPIRoutes(r, apiHandlers)
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	port := cfg.Port
+
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: r,
 	}
 
 	// Graceful shutdown
@@ -82,13 +262,67 @@ func main() {
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
-		log.Printf("Server starting on port %s", port)
-		log.Printf("Swagger docs available at: http://localhost:%s/swagger/index.html", port)
-		if err := r.Run(":" + port); err != nil {
-			log.Fatal("Server failed to start:", err)
+		slog.Info("Server starting", "port", port, "swagger", "http://localhost:"+port+"/swagger/index.html")
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Server failed to start", "error", err)
+			os.Exit(1)
 		}
 	}()
 
+	// Weekly report emails; the schedule is claimed in the database, so every instance
+	// can run the scheduler without reports going out twice
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	var schedulers sync.WaitGroup
+	schedulers.Add(4)
+	go func() {
+		defer schedulers.Done()
+		if !cfg.Reports.Enabled {
+			slog.Info("Report scheduler disabled (REPORTS_ENABLED=false)")
+			return
+		}
+		reportService.RunScheduler(schedulerCtx)
+	}()
+	// Package expiry notifications; they are deduplicated, so every instance can run it too
+	go func() {
+		defer schedulers.Done()
+		notificationService.RunScheduler(schedulerCtx, cfg.NotificationInterval)
+	}()
+	// Permanent removal of teachers and students soft-deleted past SOFT_DELETE_RETENTION_DAYS
+	go func() {
+		defer schedulers.Done()
+		if !cfg.Purge.Enabled {
+			slog.Info("Soft delete purge disabled (SOFT_DELETE_PURGE_ENABLED=false)")
+			return
+		}
+		purgeService.RunScheduler(schedulerCtx, cfg.Purge.Interval)
+	}()
+	// Daily counts of active teachers and students for the trend charts. Each run replaces
+	// the day's snapshot, so runs more often than daily keep every business's day covered
+	// whatever its time zone, and every instance can run it.
+	go func() {
+		defer schedulers.Done()
+		if !cfg.Snapshots.Enabled {
+			slog.Info("Snapshot scheduler disabled (SNAPSHOTS_ENABLED=false)")
+			return
+		}
+		businessSnapshotService.RunScheduler(schedulerCtx, cfg.Snapshots.Interval)
+	}()
+	schedulerDone := make(chan struct{})
+	go func() {
+		schedulers.Wait()
+		close(schedulerDone)
+	}()
+
 	<-c
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server...")
+
+	// Stop accepting connections and let in-flight requests finish before the
+	// deferred database.Close runs
+	shutdown(srv, cfg.ShutdownTimeout, stopScheduler, schedulerDone,
+		queue{mailQueue, "Emails still queued at shutdown were dropped"},
+		queue{smsService, "Text messages still queued at shutdown were dropped"},
+		queue{auditService, "Audit log entries still queued at shutdown were dropped"},
+	)
+
+	slog.Info("Server stopped")
 }
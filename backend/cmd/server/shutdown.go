@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// drainer is a background queue that sends what it still holds on Close, giving up when
+// ctx is done
+type drainer interface {
+	Close(ctx context.Context) error
+}
+
+// queue is a drainer with the message logged when it drops work at shutdown
+type queue struct {
+	drainer
+	dropped string
+}
+
+// shutdown stops srv accepting connections and lets in-flight requests finish, then stops
+// the schedulers and waits for them, then drains the queues. Everything shares timeout,
+// so a stuck step leaves the rest less time rather than holding the process up.
+func shutdown(srv *http.Server, timeout time.Duration, stopSchedulers func(), schedulersDone <-chan struct{}, queues ...queue) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Error("Server forced to shut down", "error", err)
+	}
+	stopSchedulers()
+	select {
+	case <-schedulersDone:
+	case <-ctx.Done():
+	}
+	for _, q := range queues {
+		if err := q.Close(ctx); err != nil {
+			slog.Error(q.dropped, "error", err)
+		}
+	}
+}
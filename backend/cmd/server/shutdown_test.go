@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// recordingQueue remembers whether it was closed and whether it still had time to drain
+type recordingQueue struct {
+	closed  bool
+	expired bool
+}
+
+func (q *recordingQueue) Close(ctx context.Context) error {
+	q.closed = true
+	q.expired = ctx.Err() != nil
+	return ctx.Err()
+}
+
+// startServer serves handler on a free local port until the test ends
+func startServer(t *testing.T, handler http.HandlerFunc) (*http.Server, string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := &http.Server{Handler: handler}
+	go srv.Serve(listener)
+	t.Cleanup(func() { srv.Close() })
+	return srv, listener.Addr().String()
+}
+
+// In-flight requests finish, new connections are refused, and the schedulers and
+// queues are stopped once the server is
+func TestShutdownLetsInFlightRequestsFinish(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	srv, addr := startServer(t, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	response := make(chan error, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				err = errors.New(resp.Status)
+			}
+		}
+		response <- err
+	}()
+	<-started
+
+	var schedulersStopped bool
+	schedulersDone := make(chan struct{})
+	mail, audit := &recordingQueue{}, &recordingQueue{}
+	done := make(chan struct{})
+	go func() {
+		shutdown(srv, 5*time.Second, func() {
+			schedulersStopped = true
+			close(schedulersDone)
+		}, schedulersDone, queue{mail, "mail dropped"}, queue{audit, "audit dropped"})
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			break
+		}
+		conn.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("server still accepts connections while shutting down")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case <-done:
+		t.Fatal("shutdown returned before the in-flight request finished")
+	default:
+	}
+	if mail.closed || schedulersStopped {
+		t.Error("schedulers or queues were stopped before the in-flight request finished")
+	}
+
+	close(release)
+	if err := <-response; err != nil {
+		t.Errorf("in-flight request failed: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not return after the request finished")
+	}
+	if !schedulersStopped {
+		t.Error("schedulers were not stopped")
+	}
+	for name, q := range map[string]*recordingQueue{"mail": mail, "audit": audit} {
+		if !q.closed || q.expired {
+			t.Errorf("%s queue closed = %v, out of time = %v, want closed in time", name, q.closed, q.expired)
+		}
+	}
+}
+
+// A request or scheduler that doesn't finish holds shutdown up only for the timeout
+func TestShutdownGivesUpAfterTimeout(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	srv, addr := startServer(t, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	go func() {
+		if resp, err := http.Get("http://" + addr); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	mail := &recordingQueue{}
+	begin := time.Now()
+	shutdown(srv, 100*time.Millisecond, func() {}, make(chan struct{}), queue{mail, "mail dropped"})
+
+	if elapsed := time.Since(begin); elapsed > 2*time.Second {
+		t.Errorf("shutdown took %v, want it to give up after the timeout", elapsed)
+	}
+	if !mail.closed || !mail.expired {
+		t.Errorf("mail queue closed = %v, out of time = %v, want closed with no time left", mail.closed, mail.expired)
+	}
+}
@@ -0,0 +1,514 @@
+// Package config loads the server's settings from the environment once at startup.
+// Every setting is validated up front, so a misconfigured server refuses to start
+// with a list of what to fix instead of failing on the first request that needs it.
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/middleware"
+	"backend/internal/services"
+	"backend/pkg/cache"
+	"backend/pkg/database"
+	"backend/pkg/logger"
+	"backend/pkg/mailer"
+	"backend/pkg/payments"
+	"backend/pkg/sms"
+	"backend/pkg/utils"
+)
+
+// Config is every setting the server needs before it can take requests
+type Config struct {
+	Port            string
+	ShutdownTimeout time.Duration // how long in-flight requests get to finish on shutdown
+	LogLevel        slog.Level
+	RunMigrations   bool // false when migrations are run separately with -migrate
+	Database        database.Config
+	QueryTimeout    time.Duration // cap on a request's database work, 0 for none
+	JWT             utils.JWTConfig
+	CORS            middleware.CORSConfig
+	TrustedProxies  []string
+	StrictJSON      bool             // reject JSON bodies carrying fields the request types don't declare
+	BodyLimits      map[string]int64 // request body limits in bytes, by name
+	RateLimit       middleware.RateLimitConfig
+	// Admins who keep using the API while maintenance mode is on
+	MaintenanceAdminIDs []uint
+	PublicCacheMaxAge   int // seconds browsers may reuse public responses
+	Cache               cache.Config
+	SMTP                mailer.SMTPConfig // no Host means emails are only logged
+	StoragePath         string
+
+	AppName          string
+	PhoneCountryCode string         // calling code of numbers written without one
+	BusinessTimezone *time.Location // zone of new businesses that don't choose their own
+	SearchTimeout    time.Duration  // how long the global search waits for its sections
+
+	PasswordLinks services.PasswordLinkConfig
+	SMS           sms.Config
+	SMSLimits     services.SMSConfig
+	Payments      payments.Config
+	Audit         services.AuditConfig
+	Calendar      services.CalendarConfig
+
+	// Background jobs
+	Reports              Schedule // Interval is unused: reports go out weekly at Report.Hour
+	Report               services.ReportConfig
+	NotificationInterval time.Duration
+	Purge                Schedule
+	PurgeRetention       services.PurgeConfig
+	Snapshots            Schedule
+	Snapshot             services.SnapshotConfig
+}
+
+// Schedule is whether a background job runs and how often
+type Schedule struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+// Error lists every setting that is missing or invalid, so they can all be fixed at once
+type Error struct {
+	Missing []string // required variables that are unset
+	Invalid []string // variables whose value can't be used, with the reason
+}
+
+func (e *Error) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Invalid) > 0 {
+		parts = append(parts, "invalid "+strings.Join(e.Invalid, "; "))
+	}
+	return "configuration: " + strings.Join(parts, "; ")
+}
+
+// setting is an environment variable the server reads
+type setting struct {
+	name        string
+	fallback    string // used when the variable is unset
+	required    bool
+	description string
+}
+
+// settings lists every variable Load reads, in the order Example prints them
+func settings() []setting {
+	list := []setting{
+		{"PORT", "8080", false, "port the HTTP server listens on"},
+		{"SHUTDOWN_TIMEOUT", "15s", false, "how long in-flight requests get to finish on shutdown"},
+		{"LOG_LEVEL", "info", false, "debug, info, warn or error"},
+		{"RUN_MIGRATIONS", "true", false, "false when migrations are run separately with -migrate"},
+
+		{"DB_HOST", "", true, "Postgres host"},
+		{"DB_PORT", "5432", false, "Postgres port"},
+		{"DB_USER", "", true, "Postgres user"},
+		{"DB_PASSWORD", "", false, "Postgres password"},
+		{"DB_NAME", "", true, "Postgres database"},
+		{"DB_MAX_OPEN_CONNS", "25", false, "most open connections per pool"},
+		{"DB_MAX_IDLE_CONNS", "10", false, "most idle connections per pool"},
+		{"DB_CONN_MAX_LIFETIME", "30m", false, "how long a connection is reused"},
+		{"DB_CONNECT_RETRIES", "5", false, "connection attempts to retry at startup"},
+		{"DB_QUERY_TIMEOUT", "30s", false, "cap on a request's database work, 0 for none"},
+
+		{"JWT_SECRET", "", true, "secret every token is signed with"},
+		{"JWT_EXPIRY", "24h", false, "how long a login token lasts"},
+
+		{"CORS_ALLOWED_ORIGINS", "http://localhost:3000", false, "comma-separated origins; one wildcard such as https://*.example.com, or * for any"},
+		{"CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS,PATCH", false, "comma-separated HTTP methods"},
+		{"CORS_ALLOWED_HEADERS", "Origin,Content-Type,Authorization," + middleware.RequestIDHeader + "," + middleware.StatusFormatHeader, false, "comma-separated request headers"},
+		{"CORS_ALLOW_CREDENTIALS", "true", false, "whether browsers may send credentials; always off for *"},
+		{"CORS_MAX_AGE", "12h", false, "how long browsers may cache a preflight"},
+		{"TRUSTED_PROXIES", "", false, "comma-separated proxy IPs or CIDRs whose X-Forwarded-For is believed"},
+
+		{"STRICT_JSON", "false", false, "reject JSON bodies carrying fields the request types don't declare"},
+		{"MAINTENANCE_ALLOWED_ADMIN_IDS", "", false, "comma-separated user IDs of admins who keep access during maintenance"},
+		{"PUBLIC_CACHE_MAX_AGE", "60", false, "seconds browsers may reuse public responses"},
+
+		{"CACHE_ENABLED", "true", false, "false turns caching off"},
+		{"REDIS_URL", "", false, "redis:// or rediss:// URL of a Redis cache; in memory when unset"},
+		{"CACHE_TTL", "60s", false, "how long cached values live"},
+		{"CACHE_SIZE", "1000", false, "entries the in-memory cache holds"},
+
+		{"SMTP_HOST", "", false, "SMTP server; emails are only logged when unset"},
+		{"SMTP_PORT", "587", false, "SMTP port"},
+		{"SMTP_USERNAME", "", false, "SMTP user; no authentication when unset"},
+		{"SMTP_PASSWORD", "", false, "SMTP password"},
+		{"SMTP_FROM", "", false, "sender address, required with SMTP_HOST"},
+
+		{"STORAGE_PATH", "uploads", false, "directory uploaded files are kept in"},
+
+		{"APP_NAME", "Coaching Management", false, "name emails are signed with"},
+		{"APP_BASE_URL", "http://localhost:3000", false, "frontend URL emailed links point to"},
+		{"PHONE_DEFAULT_COUNTRY_CODE", "", false, "calling code such as 91 given to numbers written without one"},
+		{"BUSINESS_TIMEZONE", "UTC", false, "IANA time zone of new businesses that don't choose their own"},
+		{"SEARCH_TIMEOUT", "3s", false, "how long the global search waits for its sections"},
+		{"PASSWORD_RESET_TTL", "1h", false, "how long forgot-password links stay valid"},
+		{"WELCOME_LINK_TTL", "72h", false, "how long the set-password links of new business owners stay valid"},
+
+		{"SMS_PROVIDER", "", false, "twilio; text messages are only logged when unset"},
+		{"TWILIO_ACCOUNT_SID", "", false, "Twilio account, required with SMS_PROVIDER=twilio"},
+		{"TWILIO_AUTH_TOKEN", "", false, "Twilio auth token, required with SMS_PROVIDER=twilio"},
+		{"TWILIO_FROM", "", false, "sender number or messaging service SID, required with SMS_PROVIDER=twilio"},
+		{"SMS_STATUS_CALLBACK_URL", "", false, "public URL of the delivery report route"},
+		{"SMS_RECIPIENT_LIMIT", "5", false, "text messages one number may get per SMS_RECIPIENT_WINDOW"},
+		{"SMS_RECIPIENT_WINDOW", "24h", false, "time over which SMS_RECIPIENT_LIMIT applies"},
+
+		{"RAZORPAY_WEBHOOK_SECRET", "", false, "Razorpay webhook secret; Razorpay webhooks are off when unset"},
+		{"STRIPE_WEBHOOK_SECRET", "", false, "Stripe webhook secret; Stripe webhooks are off when unset"},
+
+		{"AUDIT_QUEUE_SIZE", "1000", false, "audit log entries waiting to be written"},
+		{"CALENDAR_HORIZON_DAYS", "60", false, "days a calendar feed covers unless it asks otherwise"},
+		{"CALENDAR_MAX_DAYS", "365", false, "most days a calendar feed may ask for"},
+
+		{"REPORTS_ENABLED", "true", false, "false stops the weekly report emails"},
+		{"REPORT_HOUR", "7", false, "hour of Monday, 0 to 23, weekly reports go out"},
+		{"REPORT_TIMEZONE", "UTC", false, "IANA time zone of REPORT_HOUR"},
+		{"NOTIFICATION_CHECK_INTERVAL", "1h", false, "how often package expiry is checked"},
+		{"SOFT_DELETE_PURGE_ENABLED", "true", false, "false keeps soft-deleted teachers and students forever"},
+		{"SOFT_DELETE_PURGE_INTERVAL", "24h", false, "how often soft-deleted teachers and students are purged"},
+		{"SOFT_DELETE_RETENTION_DAYS", "30", false, "days a soft-deleted teacher or student stays restorable"},
+		{"SNAPSHOTS_ENABLED", "true", false, "false stops the daily counts behind the trend charts"},
+		{"SNAPSHOT_INTERVAL", "1h", false, "how often business counts are snapshotted"},
+		{"SNAPSHOT_BATCH_SIZE", "500", false, "businesses snapshotted per query"},
+
+		{"RATE_LIMIT_ENABLED", "true", false, "false switches every rate limit off"},
+	}
+
+	for _, name := range middleware.BodyLimitNames() {
+		list = append(list, setting{bodyLimitName(name), strconv.FormatInt(middleware.DefaultBodyLimit(name), 10), false, "largest " + name + " request body in bytes"})
+	}
+
+	for _, name := range middleware.RateLimitPolicyNames() {
+		policy := middleware.DefaultRateLimitPolicy(name)
+		prefix := rateLimitPrefix(name)
+		list = append(list,
+			setting{prefix + "_REQUESTS", strconv.Itoa(policy.Requests), false, "burst of " + name + " requests allowed, 0 for no limit"},
+			setting{prefix + "_WINDOW", policy.Window.String(), false, "time over which the " + name + " allowance refills"},
+		)
+	}
+	return list
+}
+
+func bodyLimitName(name string) string {
+	return "BODY_LIMIT_" + strings.ToUpper(name)
+}
+
+func rateLimitPrefix(name string) string {
+	return "RATE_LIMIT_" + strings.ToUpper(name)
+}
+
+// Load reads and validates every setting, returning an *Error naming all the
+// problems found
+func Load() (*Config, error) {
+	l := newLoader()
+
+	appName := l.value("APP_NAME")
+	config := &Config{
+		Port:            l.value("PORT"),
+		ShutdownTimeout: l.duration("SHUTDOWN_TIMEOUT", 0),
+		LogLevel:        l.logLevel("LOG_LEVEL"),
+		RunMigrations:   l.bool("RUN_MIGRATIONS"),
+		Database: database.Config{
+			Host:     l.value("DB_HOST"),
+			Port:     l.value("DB_PORT"),
+			User:     l.value("DB_USER"),
+			Password: l.value("DB_PASSWORD"),
+			Name:     l.value("DB_NAME"),
+			Pool: database.PoolConfig{
+				MaxOpenConns:    l.int("DB_MAX_OPEN_CONNS", 0),
+				MaxIdleConns:    l.int("DB_MAX_IDLE_CONNS", 0),
+				ConnMaxLifetime: l.duration("DB_CONN_MAX_LIFETIME", 0),
+				ConnectRetries:  l.int("DB_CONNECT_RETRIES", 0),
+			},
+		},
+		QueryTimeout: l.duration("DB_QUERY_TIMEOUT", 0),
+		JWT: utils.JWTConfig{
+			Secret: l.value("JWT_SECRET"),
+			Expiry: l.duration("JWT_EXPIRY", time.Second),
+		},
+		CORS: middleware.CORSConfig{
+			AllowedOrigins:   l.list("CORS_ALLOWED_ORIGINS"),
+			AllowedMethods:   l.list("CORS_ALLOWED_METHODS"),
+			AllowedHeaders:   l.list("CORS_ALLOWED_HEADERS"),
+			AllowCredentials: l.bool("CORS_ALLOW_CREDENTIALS"),
+			MaxAge:           l.duration("CORS_MAX_AGE", 0),
+		},
+		TrustedProxies: l.list("TRUSTED_PROXIES"),
+		StrictJSON:     l.bool("STRICT_JSON"),
+		BodyLimits:     make(map[string]int64),
+		RateLimit: middleware.RateLimitConfig{
+			Enabled:  l.bool("RATE_LIMIT_ENABLED"),
+			Policies: make(map[string]middleware.RateLimitPolicy),
+		},
+		MaintenanceAdminIDs: l.ids("MAINTENANCE_ALLOWED_ADMIN_IDS"),
+		PublicCacheMaxAge:   l.int("PUBLIC_CACHE_MAX_AGE", 0),
+		Cache: cache.Config{
+			Enabled:  l.bool("CACHE_ENABLED"),
+			RedisURL: l.redisURL("REDIS_URL"),
+			TTL:      l.duration("CACHE_TTL", time.Second),
+			Size:     l.int("CACHE_SIZE", 1),
+		},
+		SMTP: mailer.SMTPConfig{
+			Host:     l.value("SMTP_HOST"),
+			Port:     l.int("SMTP_PORT", 1),
+			Username: l.value("SMTP_USERNAME"),
+			Password: l.value("SMTP_PASSWORD"),
+			From:     l.value("SMTP_FROM"),
+		},
+		StoragePath: l.value("STORAGE_PATH"),
+
+		AppName:          appName,
+		PhoneCountryCode: strings.TrimPrefix(l.value("PHONE_DEFAULT_COUNTRY_CODE"), "+"),
+		BusinessTimezone: l.location("BUSINESS_TIMEZONE"),
+		SearchTimeout:    l.duration("SEARCH_TIMEOUT", time.Millisecond),
+
+		PasswordLinks: services.PasswordLinkConfig{
+			AppName:    appName,
+			BaseURL:    l.value("APP_BASE_URL"),
+			ResetTTL:   l.duration("PASSWORD_RESET_TTL", time.Minute),
+			WelcomeTTL: l.duration("WELCOME_LINK_TTL", time.Minute),
+		},
+		SMS: sms.Config{
+			Provider: l.oneOf("SMS_PROVIDER", sms.Providers),
+			Twilio: sms.TwilioConfig{
+				AccountSID:     l.value("TWILIO_ACCOUNT_SID"),
+				AuthToken:      l.value("TWILIO_AUTH_TOKEN"),
+				From:           l.value("TWILIO_FROM"),
+				StatusCallback: l.value("SMS_STATUS_CALLBACK_URL"),
+			},
+		},
+		SMSLimits: services.SMSConfig{
+			RecipientLimit:  l.int("SMS_RECIPIENT_LIMIT", 1),
+			RecipientWindow: l.duration("SMS_RECIPIENT_WINDOW", time.Minute),
+		},
+		Payments: payments.Config{
+			RazorpayWebhookSecret: l.value("RAZORPAY_WEBHOOK_SECRET"),
+			StripeWebhookSecret:   l.value("STRIPE_WEBHOOK_SECRET"),
+		},
+		Audit: services.AuditConfig{
+			QueueSize: l.int("AUDIT_QUEUE_SIZE", 1),
+		},
+		Calendar: services.CalendarConfig{
+			DefaultDays: l.int("CALENDAR_HORIZON_DAYS", 1),
+			MaxDays:     l.int("CALENDAR_MAX_DAYS", 1),
+		},
+
+		Reports: Schedule{Enabled: l.bool("REPORTS_ENABLED")},
+		Report: services.ReportConfig{
+			AppName:  appName,
+			Weekday:  time.Monday,
+			Hour:     l.int("REPORT_HOUR", 0),
+			Location: l.location("REPORT_TIMEZONE"),
+		},
+		NotificationInterval: l.duration("NOTIFICATION_CHECK_INTERVAL", time.Second),
+		Purge: Schedule{
+			Enabled:  l.bool("SOFT_DELETE_PURGE_ENABLED"),
+			Interval: l.duration("SOFT_DELETE_PURGE_INTERVAL", time.Second),
+		},
+		PurgeRetention: services.PurgeConfig{
+			RetentionDays: l.int("SOFT_DELETE_RETENTION_DAYS", 1),
+		},
+		Snapshots: Schedule{
+			Enabled:  l.bool("SNAPSHOTS_ENABLED"),
+			Interval: l.duration("SNAPSHOT_INTERVAL", time.Second),
+		},
+		Snapshot: services.SnapshotConfig{
+			BatchSize: l.int("SNAPSHOT_BATCH_SIZE", 1),
+		},
+	}
+
+	if config.SMTP.Host != "" && config.SMTP.From == "" {
+		l.missing = append(l.missing, "SMTP_FROM")
+	}
+	if port, err := strconv.Atoi(config.Port); err != nil || port <= 0 || port > 65535 {
+		l.invalid = append(l.invalid, "PORT: must be a port number")
+	}
+	if code := config.PhoneCountryCode; code != "" {
+		if n, err := strconv.Atoi(code); err != nil || n <= 0 || len(code) > 3 {
+			l.invalid = append(l.invalid, "PHONE_DEFAULT_COUNTRY_CODE: must be a calling code such as 91")
+		}
+	}
+	if config.Report.Hour > 23 {
+		l.invalid = append(l.invalid, "REPORT_HOUR: must be an hour from 0 to 23")
+	}
+	if config.Calendar.DefaultDays > config.Calendar.MaxDays {
+		l.invalid = append(l.invalid, "CALENDAR_HORIZON_DAYS: must not exceed CALENDAR_MAX_DAYS")
+	}
+	if config.SMS.Provider == "twilio" {
+		for _, name := range []string{"TWILIO_ACCOUNT_SID", "TWILIO_AUTH_TOKEN", "TWILIO_FROM"} {
+			if l.value(name) == "" {
+				l.missing = append(l.missing, name)
+			}
+		}
+	}
+
+	for _, name := range middleware.BodyLimitNames() {
+		config.BodyLimits[name] = int64(l.int(bodyLimitName(name), 1))
+	}
+
+	for _, name := range middleware.RateLimitPolicyNames() {
+		prefix := rateLimitPrefix(name)
+		config.RateLimit.Policies[name] = middleware.RateLimitPolicy{
+			Name:     name,
+			Requests: l.int(prefix+"_REQUESTS", 0),
+			Window:   l.duration(prefix+"_WINDOW", time.Millisecond),
+		}
+	}
+
+	if len(l.missing) > 0 || len(l.invalid) > 0 {
+		return nil, &Error{Missing: l.missing, Invalid: l.invalid}
+	}
+	return config, nil
+}
+
+// Example is a .env file listing every setting with its default, required ones left
+// empty
+func Example() string {
+	var b strings.Builder
+	for _, s := range settings() {
+		description := s.description
+		if s.required {
+			description = "required: " + description
+		}
+		fmt.Fprintf(&b, "# %s\n%s=%s\n", description, s.name, s.fallback)
+	}
+	return b.String()
+}
+
+// loader reads settings, noting the missing and invalid ones instead of stopping at
+// the first
+type loader struct {
+	settings map[string]setting
+	missing  []string
+	invalid  []string
+}
+
+func newLoader() *loader {
+	l := &loader{settings: make(map[string]setting)}
+	for _, s := range settings() {
+		l.settings[s.name] = s
+	}
+	return l
+}
+
+// value returns the variable's value, or its default when it is unset
+func (l *loader) value(name string) string {
+	s, ok := l.settings[name]
+	if !ok {
+		panic("config: unknown setting " + name)
+	}
+
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		if s.required {
+			l.missing = append(l.missing, name)
+		}
+		return s.fallback
+	}
+	return value
+}
+
+func (l *loader) int(name string, min int) int {
+	value := l.value(name)
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min {
+		l.invalid = append(l.invalid, fmt.Sprintf("%s: must be a whole number of at least %d", name, min))
+		return 0
+	}
+	return n
+}
+
+func (l *loader) duration(name string, min time.Duration) time.Duration {
+	value := l.value(name)
+	d, err := time.ParseDuration(value)
+	if err != nil || d < min {
+		l.invalid = append(l.invalid, fmt.Sprintf("%s: must be a duration such as 30s of at least %s", name, min))
+		return 0
+	}
+	return d
+}
+
+func (l *loader) bool(name string) bool {
+	value := l.value(name)
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.invalid = append(l.invalid, name+": must be true or false")
+		return false
+	}
+	return b
+}
+
+// list splits a comma-separated value, dropping empty entries
+func (l *loader) list(name string) []string {
+	var values []string
+	for _, value := range strings.Split(l.value(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// ids parses a comma-separated list of user IDs
+func (l *loader) ids(name string) []uint {
+	var ids []uint
+	for _, value := range l.list(name) {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || id == 0 {
+			l.invalid = append(l.invalid, name+": must be comma-separated user IDs")
+			return nil
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids
+}
+
+// location loads the IANA time zone the variable names, such as Asia/Kolkata
+func (l *loader) location(name string) *time.Location {
+	value := l.value(name)
+	location, err := time.LoadLocation(value)
+	if err != nil {
+		l.invalid = append(l.invalid, name+": must be an IANA time zone such as Asia/Kolkata")
+		return time.UTC
+	}
+	return location
+}
+
+func (l *loader) logLevel(name string) slog.Level {
+	level, err := logger.ParseLevel(l.value(name))
+	if err != nil {
+		l.invalid = append(l.invalid, name+": must be debug, info, warn or error")
+	}
+	return level
+}
+
+// oneOf returns the value when it is empty or one of options
+func (l *loader) oneOf(name string, options []string) string {
+	value := l.value(name)
+	if value != "" && !slices.Contains(options, value) {
+		l.invalid = append(l.invalid, fmt.Sprintf("%s: must be one of %s", name, strings.Join(options, ", ")))
+		return ""
+	}
+	return value
+}
+
+// redisURL returns the value when it is empty or a redis:// or rediss:// URL
+func (l *loader) redisURL(name string) string {
+	value := l.value(name)
+	if value == "" {
+		return ""
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") || u.Host == "" {
+		l.invalid = append(l.invalid, name+": must be a redis:// or rediss:// URL")
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setEnv clears every setting, so the environment of the machine running the tests
+// doesn't leak in, then sets the required ones and the given overrides
+func setEnv(t *testing.T, overrides map[string]string) {
+	t.Helper()
+
+	for _, s := range settings() {
+		t.Setenv(s.name, "")
+	}
+	for name, value := range map[string]string{
+		"DB_HOST":    "localhost",
+		"DB_USER":    "postgres",
+		"DB_NAME":    "coaching",
+		"JWT_SECRET": "secret",
+	} {
+		t.Setenv(name, value)
+	}
+	for name, value := range overrides {
+		t.Setenv(name, value)
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	setEnv(t, nil)
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"Port", config.Port, "8080"},
+		{"ShutdownTimeout", config.ShutdownTimeout, 15 * time.Second},
+		{"LogLevel", config.LogLevel, slog.LevelInfo},
+		{"RunMigrations", config.RunMigrations, true},
+		{"QueryTimeout", config.QueryTimeout, 30 * time.Second},
+		{"StrictJSON", config.StrictJSON, false},
+		{"BodyLimits[import]", config.BodyLimits["import"], int64(10 << 20)},
+		{"MaintenanceAdminIDs", config.MaintenanceAdminIDs, []uint(nil)},
+		{"PublicCacheMaxAge", config.PublicCacheMaxAge, 60},
+		{"Cache.Enabled", config.Cache.Enabled, true},
+		{"Cache.TTL", config.Cache.TTL, time.Minute},
+		{"Cache.Size", config.Cache.Size, 1000},
+		{"BusinessTimezone", config.BusinessTimezone, time.UTC},
+		{"SearchTimeout", config.SearchTimeout, 3 * time.Second},
+		{"PasswordLinks.AppName", config.PasswordLinks.AppName, "Coaching Management"},
+		{"PasswordLinks.ResetTTL", config.PasswordLinks.ResetTTL, time.Hour},
+		{"PasswordLinks.WelcomeTTL", config.PasswordLinks.WelcomeTTL, 72 * time.Hour},
+		{"SMS.Provider", config.SMS.Provider, ""},
+		{"SMSLimits.RecipientLimit", config.SMSLimits.RecipientLimit, 5},
+		{"Audit.QueueSize", config.Audit.QueueSize, 1000},
+		{"Calendar.MaxDays", config.Calendar.MaxDays, 365},
+		{"Reports.Enabled", config.Reports.Enabled, true},
+		{"Report.Hour", config.Report.Hour, 7},
+		{"Report.Weekday", config.Report.Weekday, time.Monday},
+		{"NotificationInterval", config.NotificationInterval, time.Hour},
+		{"Purge", config.Purge, Schedule{Enabled: true, Interval: 24 * time.Hour}},
+		{"PurgeRetention.RetentionDays", config.PurgeRetention.RetentionDays, 30},
+		{"Snapshots", config.Snapshots, Schedule{Enabled: true, Interval: time.Hour}},
+		{"Snapshot.BatchSize", config.Snapshot.BatchSize, 500},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	setEnv(t, map[string]string{
+		"SHUTDOWN_TIMEOUT":              "1m",
+		"LOG_LEVEL":                     "DEBUG",
+		"RUN_MIGRATIONS":                "false",
+		"DB_QUERY_TIMEOUT":              "0",
+		"STRICT_JSON":                   "true",
+		"BODY_LIMIT_DEFAULT":            "2048",
+		"MAINTENANCE_ALLOWED_ADMIN_IDS": "1, 7",
+		"REDIS_URL":                     "redis://localhost:6379",
+		"PHONE_DEFAULT_COUNTRY_CODE":    "+91",
+		"BUSINESS_TIMEZONE":             "Asia/Kolkata",
+		"SMS_PROVIDER":                  "twilio",
+		"TWILIO_ACCOUNT_SID":            "AC123",
+		"TWILIO_AUTH_TOKEN":             "token",
+		"TWILIO_FROM":                   "+15550100",
+		"REPORT_HOUR":                   "0",
+		"SNAPSHOTS_ENABLED":             "false",
+	})
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"ShutdownTimeout", config.ShutdownTimeout, time.Minute},
+		{"LogLevel", config.LogLevel, slog.LevelDebug},
+		{"RunMigrations", config.RunMigrations, false},
+		{"QueryTimeout", config.QueryTimeout, time.Duration(0)},
+		{"StrictJSON", config.StrictJSON, true},
+		{"BodyLimits[default]", config.BodyLimits["default"], int64(2048)},
+		{"MaintenanceAdminIDs", config.MaintenanceAdminIDs, []uint{1, 7}},
+		{"Cache.RedisURL", config.Cache.RedisURL, "redis://localhost:6379"},
+		{"PhoneCountryCode", config.PhoneCountryCode, "91"},
+		{"BusinessTimezone", config.BusinessTimezone.String(), "Asia/Kolkata"},
+		{"SMS.Provider", config.SMS.Provider, "twilio"},
+		{"SMS.Twilio.From", config.SMS.Twilio.From, "+15550100"},
+		{"Report.Hour", config.Report.Hour, 0},
+		{"Snapshots.Enabled", config.Snapshots.Enabled, false},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestLoadRejectsMalformedValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"PORT", "http"},
+		{"SHUTDOWN_TIMEOUT", "15"},
+		{"LOG_LEVEL", "loud"},
+		{"RUN_MIGRATIONS", "no thanks"},
+		{"DB_QUERY_TIMEOUT", "-1s"},
+		{"JWT_EXPIRY", "10ms"},
+		{"STRICT_JSON", "yes"},
+		{"BODY_LIMIT_IMPORT", "0"},
+		{"BODY_LIMIT_UPLOAD", "10MB"},
+		{"MAINTENANCE_ALLOWED_ADMIN_IDS", "1,admin"},
+		{"PUBLIC_CACHE_MAX_AGE", "-5"},
+		{"CACHE_TTL", "0s"},
+		{"CACHE_SIZE", "0"},
+		{"REDIS_URL", "localhost:6379"},
+		{"PHONE_DEFAULT_COUNTRY_CODE", "India"},
+		{"BUSINESS_TIMEZONE", "Mars/Olympus_Mons"},
+		{"SEARCH_TIMEOUT", "soon"},
+		{"PASSWORD_RESET_TTL", "1d"},
+		{"SMS_PROVIDER", "carrier-pigeon"},
+		{"SMS_RECIPIENT_LIMIT", "0"},
+		{"AUDIT_QUEUE_SIZE", "lots"},
+		{"CALENDAR_HORIZON_DAYS", "400"},
+		{"REPORT_HOUR", "24"},
+		{"REPORT_TIMEZONE", "Nowhere"},
+		{"SOFT_DELETE_PURGE_ENABLED", "off"},
+		{"SOFT_DELETE_RETENTION_DAYS", "0"},
+		{"SNAPSHOT_INTERVAL", "0s"},
+		{"RATE_LIMIT_AUTH_WINDOW", "1ns"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, map[string]string{tt.name: tt.value})
+
+			_, err := Load()
+			var configErr *Error
+			if !errors.As(err, &configErr) {
+				t.Fatalf("Load error = %v, want an *Error", err)
+			}
+			if len(configErr.Invalid) != 1 || !strings.HasPrefix(configErr.Invalid[0], tt.name+":") {
+				t.Errorf("Invalid = %q, want only %s", configErr.Invalid, tt.name)
+			}
+		})
+	}
+}
+
+func TestLoadListsEveryProblem(t *testing.T) {
+	setEnv(t, map[string]string{
+		"JWT_SECRET":   "",
+		"SMS_PROVIDER": "twilio",
+		"CACHE_TTL":    "forever",
+		"LOG_LEVEL":    "chatty",
+	})
+
+	_, err := Load()
+	var configErr *Error
+	if !errors.As(err, &configErr) {
+		t.Fatalf("Load error = %v, want an *Error", err)
+	}
+
+	wantMissing := []string{"JWT_SECRET", "TWILIO_ACCOUNT_SID", "TWILIO_AUTH_TOKEN", "TWILIO_FROM"}
+	if !reflect.DeepEqual(configErr.Missing, wantMissing) {
+		t.Errorf("Missing = %q, want %q", configErr.Missing, wantMissing)
+	}
+	if len(configErr.Invalid) != 2 {
+		t.Errorf("Invalid = %q, want CACHE_TTL and LOG_LEVEL", configErr.Invalid)
+	}
+}
+
+func TestExampleListsEverySetting(t *testing.T) {
+	example := Example()
+	for _, s := range settings() {
+		if !strings.Contains(example, "\n"+s.name+"="+s.fallback+"\n") {
+			t.Errorf("example is missing %s=%s", s.name, s.fallback)
+		}
+	}
+}
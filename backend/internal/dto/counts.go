@@ -0,0 +1,19 @@
+package dto
+
+// Counts returned by endpoints that change or count many records at once
+
+type UnreadCount struct {
+	Unread int64 `json:"unread"`
+}
+
+type MarkedCount struct {
+	Marked int64 `json:"marked"`
+}
+
+type AssignedStudentCount struct {
+	AssignedStudentCount int64 `json:"assigned_student_count"`
+}
+
+type RemovedCount struct {
+	Removed int64 `json:"removed"`
+}
@@ -0,0 +1,135 @@
+package dto
+
+import "backend/internal/models"
+
+// Pages of the lists that can be walked with a cursor. The items are interface{} because
+// the fields query parameter can trim them; the annotations name the full item type.
+
+type BusinessPage struct {
+	Businesses interface{} `json:"businesses"`
+	CursorPage
+}
+
+type StudentPage struct {
+	Students interface{} `json:"students"`
+	CursorPage
+}
+
+type TeacherPage struct {
+	Teachers interface{} `json:"teachers"`
+	CursorPage
+}
+
+// Pages of the lists read by page number
+
+type AnnouncementPage struct {
+	Announcements []models.AnnouncementResponse `json:"announcements"`
+	OffsetPage
+}
+
+type ExamPage struct {
+	Exams []models.ExamResponse `json:"exams"`
+	OffsetPage
+}
+
+type FeePlanPage struct {
+	Plans []models.FeePlanResponse `json:"plans"`
+	OffsetPage
+}
+
+type FeePaymentPage struct {
+	Payments []models.FeePaymentResponse `json:"payments"`
+	OffsetPage
+}
+
+type FeeDuesPage struct {
+	Dues []models.StudentFeeDues `json:"dues"`
+	OffsetPage
+}
+
+type NotificationPage struct {
+	Notifications []models.NotificationResponse `json:"notifications"`
+	OffsetPage
+}
+
+type BusinessSignupPage struct {
+	Signups []models.PendingSignup `json:"signups"`
+	OffsetPage
+}
+
+type SMSMessagePage struct {
+	Messages []models.SMSMessage `json:"messages"`
+	OffsetPage
+}
+
+type StudentAttendancePage struct {
+	Attendance []models.StudentAttendanceResponse `json:"attendance"`
+	OffsetPage
+}
+
+type TeacherAttendancePage struct {
+	Attendance []models.TeacherAttendanceResponse `json:"attendance"`
+	OffsetPage
+}
+
+type TimelinePage struct {
+	Entries []models.StudentTimelineEntry `json:"entries"`
+	OffsetPage
+}
+
+// AssignedStudentPage is a page of the students assigned to a teacher
+type AssignedStudentPage struct {
+	Students []models.StudentResponse `json:"students"`
+	OffsetPage
+}
+
+// Results of the search endpoints, which return every match up to their limit
+
+type BusinessSearchResult struct {
+	Businesses []models.BusinessResponse `json:"businesses"`
+	SearchTerm string                    `json:"search_term"`
+	TotalFound int                       `json:"total_found"`
+}
+
+type StudentSearchResult struct {
+	Students   []models.StudentResponse `json:"students"`
+	SearchTerm string                   `json:"search_term"`
+	TotalFound int                      `json:"total_found"`
+}
+
+type TeacherSearchResult struct {
+	Teachers   []models.TeacherResponse `json:"teachers"`
+	SearchTerm string                   `json:"search_term"`
+	TotalFound int                      `json:"total_found"`
+}
+
+// Whole lists returned with their length
+
+type PackageListResponse struct {
+	Success bool                     `json:"success" example:"true"`
+	Data    []models.PackageResponse `json:"data"`
+	Count   int                      `json:"count"`
+}
+
+type PackagePriceRangeResponse struct {
+	Success  bool                     `json:"success" example:"true"`
+	Data     []models.PackageResponse `json:"data"`
+	Count    int                      `json:"count"`
+	MinPrice float64                  `json:"min_price"`
+	MaxPrice float64                  `json:"max_price"`
+}
+
+type PackageSearchResponse struct {
+	Success    bool                     `json:"success" example:"true"`
+	Data       []models.PackageResponse `json:"data"`
+	Count      int                      `json:"count"`
+	SearchTerm string                   `json:"search_term"`
+	Limit      int                      `json:"limit"`
+}
+
+type UsersByRoleResponse struct {
+	Success bool                  `json:"success" example:"true"`
+	Data    []models.UserResponse `json:"data"`
+	Role    models.UserRole       `json:"role"`
+	Count   int                   `json:"count"`
+}
@@ -0,0 +1,80 @@
+package dto
+
+import "backend/internal/repository"
+
+// Pagination describes the page of a list returned in a ListResponse. Page, has_prev and
+// total_pages are left out when paging by cursor, and total_pages when the total is
+// estimated or was not counted.
+type Pagination struct {
+	Total          *int64 `json:"total"`
+	TotalEstimated bool   `json:"total_estimated,omitempty"`
+	Page           int    `json:"page,omitempty"`
+	Limit          int    `json:"limit"`
+	TotalPages     int    `json:"total_pages,omitempty"`
+	HasNext        bool   `json:"has_next"`
+	HasPrev        bool   `json:"has_prev"`
+	NextCursor     string `json:"next_cursor,omitempty"`
+}
+
+// ListResponse is the body of a page of a list, with its pagination next to the data
+type ListResponse struct {
+	Success    bool        `json:"success" example:"true"`
+	Data       interface{} `json:"data"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+// NewPagination builds the pagination of a list from the page info the service returned,
+// so page and limit are the ones actually applied rather than what the client sent
+func NewPagination(info repository.PageInfo) Pagination {
+	pagination := Pagination{
+		Total:          info.Total,
+		TotalEstimated: info.TotalEstimated,
+		Page:           info.Page,
+		Limit:          info.Limit,
+		HasNext:        info.NextCursor != "",
+		HasPrev:        info.Page > 1,
+		NextCursor:     info.NextCursor,
+	}
+
+	// Page counts need the exact total, which cursor mode and with_total=false skip
+	if info.Page > 0 && info.Limit > 0 && info.Total != nil && !info.TotalEstimated {
+		pagination.TotalPages = (int(*info.Total) + info.Limit - 1) / info.Limit
+		pagination.HasNext = info.Page < pagination.TotalPages
+	}
+	return pagination
+}
+
+// CursorPage is the paging of a list that can be read by page or walked with next_cursor.
+// Total is null when counting was skipped, and an estimate when total_estimated is set.
+// The fields match Pagination.
+type CursorPage struct {
+	Total          *int64 `json:"total"`
+	TotalEstimated bool   `json:"total_estimated"`
+	Page           int    `json:"page,omitempty"` // left out when paging by cursor
+	Limit          int    `json:"limit"`
+	TotalPages     int    `json:"total_pages,omitempty"`
+	HasNext        bool   `json:"has_next"`
+	HasPrev        bool   `json:"has_prev"`
+	NextCursor     string `json:"next_cursor"` // empty on the last page
+}
+
+func NewCursorPage(info repository.PageInfo) CursorPage {
+	pagination := NewPagination(info)
+	return CursorPage{
+		Total:          pagination.Total,
+		TotalEstimated: pagination.TotalEstimated,
+		Page:           pagination.Page,
+		Limit:          pagination.Limit,
+		TotalPages:     pagination.TotalPages,
+		HasNext:        pagination.HasNext,
+		HasPrev:        pagination.HasPrev,
+		NextCursor:     pagination.NextCursor,
+	}
+}
+
+// OffsetPage is the paging of a list read by page number
+type OffsetPage struct {
+	Total int64 `json:"total"`
+	Page  int   `json:"page"`
+	Limit int   `json:"limit"`
+}
@@ -0,0 +1,54 @@
+// Package dto holds the JSON bodies the API responds with. Handlers marshal these types
+// and the swagger annotations name them, so the spec describes what is actually sent.
+package dto
+
+import "backend/internal/models"
+
+// Response is the body of a successful request. Data is typed per endpoint in the swagger
+// annotations, e.g. dto.Response{data=models.BusinessResponse}.
+type Response struct {
+	Success bool        `json:"success" example:"true"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data"`
+}
+
+// MessageResponse is the body of a successful request that returns no data
+type MessageResponse struct {
+	Success bool   `json:"success" example:"true"`
+	Message string `json:"message"`
+}
+
+// ErrorResponse is the body of every failed request other than a 401 or 403
+type ErrorResponse struct {
+	Success bool        `json:"success" example:"false"`
+	Error   string      `json:"error" example:"Invalid request data"`
+	Details interface{} `json:"details,omitempty"` // an error message, or the list of problems found
+
+	Field        string      `json:"field,omitempty"`         // request field that was not expected or not valid
+	ValidOptions []string    `json:"valid_options,omitempty"` // names fields or include accepts
+	Maintenance  bool        `json:"maintenance,omitempty"`   // the API is down for maintenance
+	Data         interface{} `json:"data,omitempty"`          // report of a rejected import, or the current record on a version conflict
+}
+
+// UnauthorizedResponse is the body of a 401: no token was sent, or it is invalid or
+// expired
+type UnauthorizedResponse struct {
+	Success bool   `json:"success" example:"false"`
+	Error   string `json:"error" example:"Invalid token"`
+}
+
+// ForbiddenResponse is the body of a 403: the caller's role lacks what the route
+// requires, or the record belongs to another business
+type ForbiddenResponse struct {
+	Success    bool   `json:"success" example:"false"`
+	Error      string `json:"error" example:"Insufficient permissions"`
+	Permission string `json:"permission,omitempty" example:"manage_businesses"` // the permission a route guard required
+}
+
+// AuthResponse is the body of a successful login or registration
+type AuthResponse struct {
+	Success bool                 `json:"success" example:"true"`
+	Message string               `json:"message"`
+	Token   string               `json:"token"`
+	User    *models.UserResponse `json:"user"`
+}
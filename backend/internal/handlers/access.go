@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authorizeTeacher parses the teacher ID path parameter and checks that the caller may manage
+// that teacher, writing the error response and returning false otherwise
+func authorizeTeacher(c *gin.Context, teacherService services.TeacherService) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid teacher ID"})
+		return 0, false
+	}
+
+	if err := teacherService.CheckTeacherAccess(c.Request.Context(), uint(id), c.GetUint("user_id"), c.GetString("user_role")); err != nil {
+		if errors.Is(err, services.ErrAccessDenied) {
+			c.JSON(http.StatusForbidden, dto.ForbiddenResponse{Error: "Insufficient permissions"})
+			return 0, false
+		}
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// businessAccessChecker is implemented by services that can tell whether a caller may manage a business
+type businessAccessChecker interface {
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+}
+
+// authorizeBusiness parses the business ID path parameter and checks that the caller may manage
+// that business, writing the error response and returning false otherwise
+func authorizeBusiness(c *gin.Context, checker businessAccessChecker) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("businessId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid business ID"})
+		return 0, false
+	}
+
+	if err := checker.CheckBusinessAccess(c.Request.Context(), uint(id), c.GetUint("user_id"), c.GetString("user_role")); err != nil {
+		c.JSON(http.StatusForbidden, dto.ForbiddenResponse{Error: "Insufficient permissions"})
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// businessOwnerResolver is implemented by services that can find the business a user owns
+type businessOwnerResolver interface {
+	GetBusinessIDByUser(ctx context.Context, userID uint) (uint, error)
+}
+
+// tokenScopeID returns an ID the auth middleware took from the caller's token, such as
+// business_id, or zero when the token doesn't carry it or the caller isn't in role
+func tokenScopeID(c *gin.Context, key string, role models.UserRole) uint {
+	if c.GetString("user_role") != string(role) {
+		return 0
+	}
+	return c.GetUint(key)
+}
+
+// myBusinessID resolves the business the caller owns from their token, for the
+// /my-business routes, writing a 404 response when they have none. Tokens issued
+// before the business existed fall back to a lookup.
+func myBusinessID(c *gin.Context, resolver businessOwnerResolver) (uint, bool) {
+	if businessID := tokenScopeID(c, "business_id", models.RoleBusiness); businessID != 0 {
+		return businessID, true
+	}
+
+	businessID, err := resolver.GetBusinessIDByUser(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return 0, false
+	}
+	return businessID, true
+}
+
+// teacherProfileResolver is implemented by services that can find a user's teacher profile
+type teacherProfileResolver interface {
+	GetTeacherByUserID(ctx context.Context, userID uint) (*models.TeacherResponse, error)
+}
+
+// myTeacherID resolves the caller's teacher profile from their token, for the
+// /my-teacher-profile routes, writing a 404 response when they have none. Tokens
+// issued before the profile existed fall back to a lookup.
+func myTeacherID(c *gin.Context, resolver teacherProfileResolver) (uint, bool) {
+	if teacherID := tokenScopeID(c, "teacher_id", models.RoleTeacher); teacherID != 0 {
+		return teacherID, true
+	}
+
+	teacher, err := resolver.GetTeacherByUserID(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Teacher profile not found"})
+		return 0, false
+	}
+	return teacher.ID, true
+}
+
+// studentAccessChecker is implemented by services that can tell whether a caller may manage a student
+type studentAccessChecker interface {
+	CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error
+}
+
+// authorizeStudent parses the student ID path parameter and checks that the caller may manage
+// that student, writing the error response and returning false otherwise
+func authorizeStudent(c *gin.Context, checker studentAccessChecker) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid student ID"})
+		return 0, false
+	}
+
+	if err := checker.CheckStudentAccess(c.Request.Context(), uint(id), c.GetUint("user_id"), c.GetString("user_role")); err != nil {
+		if errors.Is(err, services.ErrAccessDenied) {
+			c.JSON(http.StatusForbidden, dto.ForbiddenResponse{Error: "Insufficient permissions"})
+			return 0, false
+		}
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// paymentAccessChecker is implemented by services that can tell whether a caller may see a fee payment
+type paymentAccessChecker interface {
+	CheckPaymentAccess(ctx context.Context, paymentID, userID uint, role string) error
+}
+
+// authorizePayment parses the payment ID path parameter and checks that the caller may see
+// that payment, writing the error response and returning false otherwise
+func authorizePayment(c *gin.Context, checker paymentAccessChecker) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid payment ID"})
+		return 0, false
+	}
+
+	if err := checker.CheckPaymentAccess(c.Request.Context(), uint(id), c.GetUint("user_id"), c.GetString("user_role")); err != nil {
+		if errors.Is(err, services.ErrAccessDenied) {
+			c.JSON(http.StatusForbidden, dto.ForbiddenResponse{Error: "Insufficient permissions"})
+			return 0, false
+		}
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return 0, false
+	}
+
+	return uint(id), true
+}
@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnnouncementHandler struct {
+	announcementService services.AnnouncementService
+}
+
+func NewAnnouncementHandler(announcementService services.AnnouncementService) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		announcementService: announcementService,
+	}
+}
+
+// CreateAnnouncement godoc
+// @Summary Create an announcement
+// @Description Post an announcement to the students, teachers or everyone in a business, optionally limited to a batch. A future published_at schedules it; expires_at hides it afterwards. (Admin/Business only)
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.CreateAnnouncementRequest true "Announcement data"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.AnnouncementResponse} "Success response with announcement data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/announcements [post]
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.announcementService)
+	if !ok {
+		return
+	}
+
+	var req models.CreateAnnouncementRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	announcement, err := h.announcementService.CreateAnnouncement(c.Request.Context(), businessID, req, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Announcement created successfully",
+		Data:    announcement,
+	})
+}
+
+// GetAnnouncements godoc
+// @Summary Get announcements
+// @Description Get all announcements of a business, including scheduled and expired ones, newest first (Admin/Business only)
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param audience query string false "Audience (students, teachers, all)"
+// @Param batch_id query int false "Batch ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.AnnouncementPage} "Success response with announcements"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/announcements [get]
+func (h *AnnouncementHandler) GetAnnouncements(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.announcementService)
+	if !ok {
+		return
+	}
+
+	var filters repository.AnnouncementFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filters.BusinessID = &businessID
+
+	announcements, total, err := h.announcementService.GetAnnouncements(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.AnnouncementPage{
+			Announcements: announcements,
+			OffsetPage:    dto.OffsetPage{Total: total, Page: filters.Page, Limit: filters.Limit},
+		},
+	})
+}
+
+// GetAnnouncement godoc
+// @Summary Get an announcement
+// @Description Get a single announcement of a business (Admin/Business only)
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param announcementId path int true "Announcement ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.AnnouncementResponse} "Success response with announcement data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Announcement not found"
+// @Router /businesses/{businessId}/announcements/{announcementId} [get]
+func (h *AnnouncementHandler) GetAnnouncement(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.announcementService)
+	if !ok {
+		return
+	}
+
+	announcementID, ok := parseAnnouncementID(c)
+	if !ok {
+		return
+	}
+
+	announcement, err := h.announcementService.GetAnnouncement(c.Request.Context(), businessID, announcementID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    announcement,
+	})
+}
+
+// UpdateAnnouncement godoc
+// @Summary Update an announcement
+// @Description Update an announcement of a business (Admin/Business only)
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param announcementId path int true "Announcement ID"
+// @Param request body models.UpdateAnnouncementRequest true "Announcement update data"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.AnnouncementResponse} "Success response with updated announcement data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/announcements/{announcementId} [put]
+func (h *AnnouncementHandler) UpdateAnnouncement(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.announcementService)
+	if !ok {
+		return
+	}
+
+	announcementID, ok := parseAnnouncementID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateAnnouncementRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	announcement, err := h.announcementService.UpdateAnnouncement(c.Request.Context(), businessID, announcementID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Announcement updated successfully",
+		Data:    announcement,
+	})
+}
+
+// DeleteAnnouncement godoc
+// @Summary Delete an announcement
+// @Description Delete an announcement of a business together with its read marks (Admin/Business only)
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param announcementId path int true "Announcement ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/announcements/{announcementId} [delete]
+func (h *AnnouncementHandler) DeleteAnnouncement(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.announcementService)
+	if !ok {
+		return
+	}
+
+	announcementID, ok := parseAnnouncementID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.announcementService.DeleteAnnouncement(c.Request.Context(), businessID, announcementID); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Announcement deleted successfully",
+	})
+}
+
+// GetMyAnnouncements godoc
+// @Summary Get my announcements
+// @Description Get the published, unexpired announcements addressed to the current student or teacher, newest first, each flagged as read or unread (Student/Teacher only)
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.AnnouncementPage} "Success response with announcements"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /my-announcements [get]
+func (h *AnnouncementHandler) GetMyAnnouncements(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	announcements, total, err := h.announcementService.GetMyAnnouncements(c.Request.Context(), c.GetUint("user_id"), c.GetString("user_role"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.AnnouncementPage{
+			Announcements: announcements,
+			OffsetPage:    dto.OffsetPage{Total: total, Page: page, Limit: limit},
+		},
+	})
+}
+
+// GetMyUnreadAnnouncementCount godoc
+// @Summary Get my unread announcement count
+// @Description Get how many of the current student's or teacher's announcements are unread, for a badge (Student/Teacher only)
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.UnreadCount} "Success response with unread count"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /my-announcements/unread-count [get]
+func (h *AnnouncementHandler) GetMyUnreadAnnouncementCount(c *gin.Context) {
+	count, err := h.announcementService.GetMyUnreadCount(c.Request.Context(), c.GetUint("user_id"), c.GetString("user_role"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    dto.UnreadCount{Unread: count},
+	})
+}
+
+// MarkAnnouncementRead godoc
+// @Summary Mark an announcement as read
+// @Description Mark one of the current student's or teacher's announcements as read (Student/Teacher only)
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Param announcementId path int true "Announcement ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Announcement not found"
+// @Router /my-announcements/{announcementId}/read [post]
+func (h *AnnouncementHandler) MarkAnnouncementRead(c *gin.Context) {
+	announcementID, ok := parseAnnouncementID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.announcementService.MarkAnnouncementRead(c.Request.Context(), c.GetUint("user_id"), c.GetString("user_role"), announcementID); err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Announcement marked as read",
+	})
+}
+
+// MarkAllAnnouncementsRead godoc
+// @Summary Mark all announcements as read
+// @Description Mark every announcement currently addressed to the student or teacher as read (Student/Teacher only)
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /my-announcements/read-all [post]
+func (h *AnnouncementHandler) MarkAllAnnouncementsRead(c *gin.Context) {
+	if err := h.announcementService.MarkAllAnnouncementsRead(c.Request.Context(), c.GetUint("user_id"), c.GetString("user_role")); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "All announcements marked as read",
+	})
+}
+
+func parseAnnouncementID(c *gin.Context) (uint, bool) {
+	announcementID, err := strconv.ParseUint(c.Param("announcementId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid announcement ID"})
+		return 0, false
+	}
+	return uint(announcementID), true
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordingAudit keeps the entries recorded instead of writing them
+type recordingAudit struct {
+	entries []models.AuditLog
+}
+
+func (a *recordingAudit) RecordDataAccess(entry models.AuditLog) {
+	a.entries = append(a.entries, entry)
+}
+
+func (a *recordingAudit) GetDataAccessLogs(ctx context.Context, filters repository.AuditLogFilters) ([]models.AuditLog, repository.PageInfo, error) {
+	return a.entries, repository.PageInfo{}, nil
+}
+
+func (a *recordingAudit) Close(ctx context.Context) error {
+	return nil
+}
+
+// The audit log records the client behind a trusted proxy, not the proxy or an address
+// the client made up
+func TestAuditDataAccessRecordsClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	audit := &recordingAudit{}
+	r := gin.New()
+	middleware.ConfigureTrustedProxies(r, []string{"10.0.0.0/8"})
+	r.GET("/api/v1/students", func(c *gin.Context) {
+		c.Set("user_id", uint(7))
+		c.Set("user_role", "admin")
+		auditDataAccess(c, audit, models.AuditLog{Category: models.AuditCategoryDataAccess, Action: models.AuditActionList})
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/students?grade=8", nil)
+	req.RemoteAddr = "10.0.0.1:4000"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1, 203.0.113.7")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(audit.entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(audit.entries))
+	}
+	entry := audit.entries[0]
+	if entry.IPAddress != "203.0.113.7" {
+		t.Errorf("IPAddress = %q, want %q", entry.IPAddress, "203.0.113.7")
+	}
+	if entry.ActorID != 7 || entry.Path != "/api/v1/students" || entry.Scope["grade"] != "8" {
+		t.Errorf("entry = %+v, want actor 7 on /api/v1/students with grade 8", entry)
+	}
+}
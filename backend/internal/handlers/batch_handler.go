@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BatchHandler struct {
+	batchService services.BatchService
+}
+
+func NewBatchHandler(batchService services.BatchService) *BatchHandler {
+	return &BatchHandler{
+		batchService: batchService,
+	}
+}
+
+// CreateBatch godoc
+// @Summary Create a batch
+// @Description Create a student batch for a business, optionally with an assigned teacher (Admin/Business only)
+// @Tags batches
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.CreateBatchRequest true "Batch data"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.BatchResponse} "Success response with batch data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/batches [post]
+func (h *BatchHandler) CreateBatch(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.batchService)
+	if !ok {
+		return
+	}
+
+	var req models.CreateBatchRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	batch, err := h.batchService.CreateBatch(c.Request.Context(), businessID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Batch created successfully",
+		Data:    batch,
+	})
+}
+
+// GetBatchesByBusiness godoc
+// @Summary Get batches by business
+// @Description Get the batches of a business with their student counts (Admin/Business only)
+// @Tags batches
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.BatchResponse} "Success response with batches list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/batches [get]
+func (h *BatchHandler) GetBatchesByBusiness(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.batchService)
+	if !ok {
+		return
+	}
+
+	batches, err := h.batchService.GetBatchesByBusiness(c.Request.Context(), businessID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get batches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    batches,
+	})
+}
+
+// GetBatch godoc
+// @Summary Get a batch
+// @Description Get a single batch of a business (Admin/Business only)
+// @Tags batches
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param batchId path int true "Batch ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.BatchResponse} "Success response with batch data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Batch not found"
+// @Router /businesses/{businessId}/batches/{batchId} [get]
+func (h *BatchHandler) GetBatch(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.batchService)
+	if !ok {
+		return
+	}
+
+	batchID, err := strconv.ParseUint(c.Param("batchId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid batch ID"})
+		return
+	}
+
+	batch, err := h.batchService.GetBatch(c.Request.Context(), businessID, uint(batchID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    batch,
+	})
+}
+
+// UpdateBatch godoc
+// @Summary Update a batch
+// @Description Update the name, dates or assigned teacher of a batch (Admin/Business only)
+// @Tags batches
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param batchId path int true "Batch ID"
+// @Param request body models.UpdateBatchRequest true "Batch update data"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.BatchResponse} "Success response with updated batch data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/batches/{batchId} [put]
+func (h *BatchHandler) UpdateBatch(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.batchService)
+	if !ok {
+		return
+	}
+
+	batchID, err := strconv.ParseUint(c.Param("batchId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid batch ID"})
+		return
+	}
+
+	var req models.UpdateBatchRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	batch, err := h.batchService.UpdateBatch(c.Request.Context(), businessID, uint(batchID), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Batch updated successfully",
+		Data:    batch,
+	})
+}
+
+// DeleteBatch godoc
+// @Summary Delete a batch
+// @Description Delete a batch; its students stay enrolled without a batch (Admin/Business only)
+// @Tags batches
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param batchId path int true "Batch ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/batches/{batchId} [delete]
+func (h *BatchHandler) DeleteBatch(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.batchService)
+	if !ok {
+		return
+	}
+
+	batchID, err := strconv.ParseUint(c.Param("batchId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid batch ID"})
+		return
+	}
+
+	if err := h.batchService.DeleteBatch(c.Request.Context(), businessID, uint(batchID)); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Batch deleted successfully",
+	})
+}
+
+// MoveStudentBatch godoc
+// @Summary Move student to batch
+// @Description Move a student into another batch of the same business, or out of their batch with a null batch_id (Admin/Business only)
+// @Tags batches
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param request body models.MoveStudentBatchRequest true "Target batch"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.StudentResponse} "Success response with updated student data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/{id}/batch [put]
+func (h *BatchHandler) MoveStudentBatch(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.batchService)
+	if !ok {
+		return
+	}
+
+	var req models.MoveStudentBatchRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	student, err := h.batchService.MoveStudent(c.Request.Context(), studentID, req.BatchID, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Student moved successfully",
+		Data:    student,
+	})
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindJSON binds the JSON request body into obj, writing the error response and returning
+// false when the body is too large, names a field the request type does not have (in
+// strict mode) or fails validation
+func bindJSON(c *gin.Context, obj any) bool {
+	err := c.ShouldBindJSON(obj)
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{
+			Error:   "Request body too large",
+			Details: fmt.Sprintf("limit is %d bytes", tooLarge.Limit),
+		})
+		return false
+	}
+
+	if field, ok := unknownJSONField(err); ok {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request data",
+			Details: fmt.Sprintf("unknown field %q", field),
+			Field:   field,
+		})
+		return false
+	}
+
+	c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+		Error:   "Invalid request data",
+		Details: err.Error(),
+	})
+	return false
+}
+
+// unknownJSONField extracts the offending key from the decoder error produced when
+// unknown fields are disallowed; encoding/json reports no typed error for it
+func unknownJSONField(err error) (string, bool) {
+	quoted, ok := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !ok {
+		return "", false
+	}
+
+	field, unquoteErr := strconv.Unquote(quoted)
+	if unquoteErr != nil {
+		return quoted, true
+	}
+	return field, true
+}
+
+// writeFieldError writes a 400 naming the request field when err is a
+// services.FieldError, and reports whether it did
+func writeFieldError(c *gin.Context, err error) bool {
+	var fieldErr *services.FieldError
+	if !errors.As(err, &fieldErr) {
+		return false
+	}
+
+	c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+		Error:   "Invalid request data",
+		Details: fieldErr.Message,
+		Field:   fieldErr.Field,
+	})
+	return true
+}
+
+// requestVersion returns the record version an update is based on, taken from the body
+// or else the If-Match header. It writes a 428 and returns false when the client sent
+// neither, since an unversioned update could silently overwrite someone else's change.
+func requestVersion(c *gin.Context, body *uint) (uint, bool) {
+	if body != nil {
+		return *body, true
+	}
+
+	ifMatch := strings.Trim(strings.TrimPrefix(strings.TrimSpace(c.GetHeader("If-Match")), "W/"), `"`)
+	if ifMatch != "" {
+		version, err := strconv.ParseUint(ifMatch, 10, 0)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid If-Match header",
+				Details: "expected the record version",
+			})
+			return 0, false
+		}
+		return uint(version), true
+	}
+
+	c.JSON(http.StatusPreconditionRequired, dto.ErrorResponse{
+		Error:   "Record version required",
+		Details: "send the version you read as the version field or the If-Match header",
+		Field:   "version",
+	})
+	return 0, false
+}
+
+// writeVersionConflict writes a 409 with the current record when err is a
+// services.VersionConflictError, and reports whether it did
+func writeVersionConflict(c *gin.Context, err error) bool {
+	var conflict *services.VersionConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+
+	c.JSON(http.StatusConflict, dto.ErrorResponse{
+		Error:   "Record was changed by someone else",
+		Details: "reload the record and apply your changes to the current version",
+		Data:    conflict.Current,
+	})
+	return true
+}
+
+// writeEmailTaken writes a 409 naming where the address is used, "users" or
+// "business", when err is a services.EmailTakenError, and reports whether it did
+func writeEmailTaken(c *gin.Context, err error) bool {
+	var taken *services.EmailTakenError
+	if !errors.As(err, &taken) {
+		return false
+	}
+
+	c.JSON(http.StatusConflict, dto.ErrorResponse{
+		Error:   err.Error(),
+		Details: taken.Table,
+		Field:   "email",
+	})
+	return true
+}
+
+// queryStatus reads an optional status query parameter, given as the number or the name,
+// writing a 400 and returning false when it is neither
+func queryStatus(c *gin.Context, name string) (*models.Status, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+
+	status, err := models.ParseStatus(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: fmt.Sprintf("%s must be one of 0, 1, inactive, active", name),
+			Field:   name,
+		})
+		return nil, false
+	}
+	return &status, true
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	seven := uint(7)
+
+	tests := []struct {
+		name    string
+		body    *uint
+		ifMatch string
+		want    uint
+		ok      bool
+		status  int
+	}{
+		{"body", &seven, "", 7, true, http.StatusOK},
+		{"body wins over the header", &seven, `"3"`, 7, true, http.StatusOK},
+		{"quoted header", nil, `"3"`, 3, true, http.StatusOK},
+		{"weak header", nil, `W/"4"`, 4, true, http.StatusOK},
+		{"bare header", nil, "5", 5, true, http.StatusOK},
+		{"neither", nil, "", 0, false, http.StatusPreconditionRequired},
+		{"header that isn't a version", nil, `"abc"`, 0, false, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			version, ok := requestVersion(c, tt.body)
+			if ok != tt.ok || version != tt.want {
+				t.Errorf("requestVersion = %d, %v, want %d, %v", version, ok, tt.want, tt.ok)
+			}
+			if !tt.ok && w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
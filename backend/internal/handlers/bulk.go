@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeBulkResult answers a bulk update with what it did: 200 when every ID was
+// updated, 207 when some were skipped, and 400 listing the skipped IDs when an atomic
+// request was refused. err is nil or services.ErrBulkIncomplete.
+func writeBulkResult(c *gin.Context, result *models.BulkUpdateResult, err error, message string) {
+	if errors.Is(err, services.ErrBulkIncomplete) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error(), Data: result})
+		return
+	}
+
+	status := http.StatusOK
+	if len(result.Skipped) > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, dto.Response{Success: true, Message: message, Data: result})
+}
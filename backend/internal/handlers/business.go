@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"backend/internal/dto"
 	"backend/internal/models"
 	"backend/internal/repository"
 	"backend/internal/services"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,48 +26,177 @@ func NewBusinessHandler(businessService services.BusinessService) *BusinessHandl
 
 // GetBusinessBySlug godoc
 // @Summary Get business by slug (Public)
-// @Description Get a specific business by slug (no authentication required)
+// @Description Get a specific business by slug (no authentication required), with the theme of its public page. Responses carry a weak ETag and Cache-Control; send If-None-Match to get 304 when nothing changed.
 // @Tags businesses
 // @Accept json
 // @Produce json
 // @Param slug path string true "Business slug"
-// @Success 200 {object} map[string]interface{} "Success response with business data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 404 {object} map[string]string "Business not found"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/business/{slug} [get]
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Success 200 {object} dto.Response{data=models.BusinessResponse} "Success response with business data"
+// @Success 304 "Not modified"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "Business not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /business/{slug} [get]
 func (h *BusinessHandler) GetBusinessBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 	if slug == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Slug parameter is required",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Slug parameter is required"})
 		return
 	}
 
-	business, err := h.businessService.GetBusinessBySlug(slug)
+	business, err := h.businessService.GetBusinessBySlug(c.Request.Context(), slug)
 	if err != nil {
 		if err.Error() == "business not found" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"success": false,
-				"error":   "Business not found",
-			})
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Business not found"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get business",
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get business"})
+		return
+	}
+
+	if notModified(c, businessETag(business)) {
+		return
+	}
+
+	// The response may be shared through the cache, so the banner URL goes on a copy
+	response := *business
+	if business.Theme != nil && business.Theme.HasBanner {
+		theme := *business.Theme
+		theme.BannerURL = bannerURL(c, &response)
+		response.Theme = &theme
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// bannerURL is the absolute URL of a business's banner, under the same API version
+// prefix the business was requested through. The update time in the query makes
+// clients fetch a replaced banner.
+func bannerURL(c *gin.Context, business *models.BusinessResponse) string {
+	path := strings.TrimSuffix(c.FullPath(), ":slug") + url.PathEscape(business.Slug) + "/banner"
+	return requestOrigin(c) + path + "?v=" + strconv.FormatInt(business.UpdatedOn.Unix(), 10)
+}
+
+// GetBusinessBanner godoc
+// @Summary Get business banner (Public)
+// @Description Get the banner image shown on a business's public page (no authentication required)
+// @Tags businesses
+// @Produce image/jpeg,image/png,image/webp
+// @Param slug path string true "Business slug"
+// @Success 200 {file} file "Banner image"
+// @Failure 404 {object} dto.ErrorResponse "Business or banner not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /business/{slug}/banner [get]
+func (h *BusinessHandler) GetBusinessBanner(c *gin.Context) {
+	banner, err := h.businessService.GetBannerBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		switch err.Error() {
+		case "business not found", "business has no banner":
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get banner"})
+		}
+		return
+	}
+
+	// Banner URLs change with every upload, so the image can be cached for long
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, banner.ContentType, banner.Content)
+}
+
+// UploadBusinessBanner godoc
+// @Summary Upload business banner
+// @Description Set the banner (JPEG, PNG or WebP, max 5 MB) shown on the business's public page, replacing any previous one (Admin/Business only)
+// @Tags businesses
+// @Accept multipart/form-data
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param file formData file true "Banner image"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/banner [put]
+func (h *BusinessHandler) UploadBusinessBanner(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.businessService)
+	if !ok {
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "File is required",
+			Details: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    business,
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	if err := h.businessService.UploadBanner(c.Request.Context(), businessID, file, header); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Banner uploaded successfully",
+	})
+}
+
+// DeleteBusinessBanner godoc
+// @Summary Delete business banner
+// @Description Remove the banner shown on the business's public page (Admin/Business only)
+// @Tags businesses
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "No banner"
+// @Router /businesses/{businessId}/banner [delete]
+func (h *BusinessHandler) DeleteBusinessBanner(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.businessService)
+	if !ok {
+		return
+	}
+
+	if err := h.businessService.DeleteBanner(c.Request.Context(), businessID); err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Banner removed successfully",
 	})
 }
 
+// businessETag covers the business and the package and owner embedded in its response
+func businessETag(business *models.BusinessResponse) string {
+	parts := []interface{}{business.ID, business.UpdatedOn}
+	if business.Package != nil {
+		parts = append(parts, business.Package.ID, business.Package.UpdatedOn)
+	}
+	if business.User != nil {
+		parts = append(parts, *business.User)
+	}
+	return weakETag(parts...)
+}
+
 // GetMyBusiness godoc
 // @Summary Get my business profile
 // @Description Get current user's business profile (Business users only)
@@ -71,32 +204,27 @@ func (h *BusinessHandler) GetBusinessBySlug(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with business data"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Business profile not found"
-// @Router /api/my-business [get]
+// @Success 200 {object} dto.Response{data=models.BusinessResponse} "Success response with business data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business [get]
 func (h *BusinessHandler) GetMyBusiness(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   "User not authenticated",
-		})
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: "User not authenticated"})
 		return
 	}
 
-	business, err := h.businessService.GetBusinessByUserID(userID.(uint))
+	business, err := h.businessService.GetBusinessByUserID(c.Request.Context(), userID.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"error":   "Business profile not found",
-		})
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Business profile not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    business,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    business,
 	})
 }
 
@@ -106,40 +234,31 @@ func (h *BusinessHandler) GetMyBusiness(c *gin.Context) {
 // @Tags business-profile
 // @Accept json
 // @Produce json
-// @Param request body models.UpdateBusinessRequest true "Business update data"
+// @Param request body models.UpdateMyBusinessRequest true "Business update data"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with updated business data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Business profile not found"
-// @Router /api/my-business [put]
+// @Success 200 {object} dto.Response{data=models.BusinessResponse} "Success response with updated business data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Failure 409 {object} dto.ErrorResponse "Email already used by another user or business, ignoring case; details names which (users or business)"
+// @Router /my-business [put]
 func (h *BusinessHandler) UpdateMyBusiness(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   "User not authenticated",
-		})
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: "User not authenticated"})
 		return
 	}
 
 	// Get business by user ID first
-	business, err := h.businessService.GetBusinessByUserID(userID.(uint))
+	business, err := h.businessService.GetBusinessByUserID(c.Request.Context(), userID.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"error":   "Business profile not found",
-		})
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Business profile not found"})
 		return
 	}
 
-	var req models.UpdateBusinessRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	var req models.UpdateMyBusinessRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -148,9 +267,6 @@ func (h *BusinessHandler) UpdateMyBusiness(c *gin.Context) {
 	if req.Name != "" {
 		updates["name"] = req.Name
 	}
-	if req.Slug != "" {
-		updates["slug"] = req.Slug
-	}
 	if req.OwnerName != "" {
 		updates["owner_name"] = req.OwnerName
 	}
@@ -163,69 +279,99 @@ func (h *BusinessHandler) UpdateMyBusiness(c *gin.Context) {
 	if req.Location != "" {
 		updates["location"] = req.Location
 	}
-	if req.Password != "" {
-		updates["password"] = req.Password
+	if req.IsOpen != nil {
+		updates["is_open"] = *req.IsOpen
 	}
-	if req.PackageID != nil {
-		updates["package_id"] = req.PackageID
+	if req.Timezone != nil {
+		updates["timezone"] = *req.Timezone
 	}
-	if req.Status != nil {
-		updates["status"] = *req.Status
+	if req.ThemePrimaryColor != nil {
+		updates["theme_primary_color"] = *req.ThemePrimaryColor
+	}
+	if req.ThemeAccentColor != nil {
+		updates["theme_accent_color"] = *req.ThemeAccentColor
 	}
 
-	updatedBusiness, err := h.businessService.UpdateBusiness(business.ID, updates)
+	updatedBusiness, err := h.businessService.UpdateBusiness(c.Request.Context(), business.ID, updates)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		if writeFieldError(c, err) || writeEmailTaken(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Business profile updated successfully",
+		Data:    updatedBusiness,
+	})
+}
+
+// PurgeSampleData godoc
+// @Summary Purge sample data
+// @Description Permanently remove the sample students, and their accounts, that the business was created with. Sample students with fee payments can't be removed. (Business users only)
+// @Tags business-profile
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.PurgeSampleDataResponse} "Success response with the removed counts by entity type"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/purge-sample-data [post]
+func (h *BusinessHandler) PurgeSampleData(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.businessService)
+	if !ok {
+		return
+	}
+
+	result, err := h.businessService.PurgeSampleData(c.Request.Context(), businessID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Business profile updated successfully",
-		"data":    updatedBusiness,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Sample data purged successfully",
+		Data:    result,
 	})
 }
 
 // CreateBusiness godoc
 // @Summary Create a new business
-// @Description Create a new business with the provided information (Admin only)
+// @Description Create a new business with the provided information. With bootstrap, the business starts with a default batch, student fields and a welcome announcement, created in the same transaction; sample_data adds sample students that the owner removes with purge-sample-data. (Admin only)
 // @Tags businesses
 // @Accept json
 // @Produce json
 // @Param request body models.CreateBusinessRequest true "Business data"
 // @Security BearerAuth
-// @Success 201 {object} map[string]interface{} "Success response with business data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/businesses [post]
+// @Success 201 {object} dto.Response{data=models.BusinessResponse} "Success response with business data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 409 {object} dto.ErrorResponse "Email already used by another user or business, ignoring case; details names which (users or business)"
+// @Router /businesses [post]
 func (h *BusinessHandler) CreateBusiness(c *gin.Context) {
 	var req models.CreateBusinessRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	business, err := h.businessService.CreateBusiness(req)
+	business, err := h.businessService.CreateBusiness(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		if writeFieldError(c, err) || writeEmailTaken(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"success": true,
-		"message": "Business created successfully",
-		"data":    business,
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Business created successfully",
+		Data:    business,
 	})
 }
 
@@ -237,87 +383,112 @@ func (h *BusinessHandler) CreateBusiness(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
-// @Param status query int false "Filter by status (0=inactive, 1=active)"
+// @Param cursor query string false "next_cursor of the previous page, for keyset pagination instead of page; total is skipped. Needs a single sort_by column"
+// @Param with_total query bool false "Count the total; false skips the count query" default(true)
+// @Param estimate query bool false "Estimate the total from table statistics instead of counting, for large tables"
+// @Param status query string false "Filter by status (0 or inactive, 1 or active)"
+// @Param is_open query bool false "Filter by whether the centre is open to the public"
 // @Param package_id query int false "Filter by package ID"
 // @Param location query string false "Filter by location"
 // @Param search query string false "Search in name, owner name, email, location, or slug"
-// @Param sort_by query string false "Sort by field (name, owner_name, email, location, status, created_on)"
-// @Param sort_order query string false "Sort order (asc, desc)" default(desc)
+// @Param sort_by query string false "Comma-separated sort columns (name, owner_name, email, location, status, slug, package_id, package_expires_at, created_on, updated_on), e.g. status,name" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Param fields query string false "Comma-separated response fields to return, e.g. id,name"
+// @Param include query string false "Comma-separated relations to load (user, package); defaults to those named in fields, or all"
+// @Param has_package query bool false "Filter by whether a package is assigned"
+// @Param is_trial query bool false "Filter by whether the business is on a free package"
+// @Param verified query bool false "Filter by whether the owner has confirmed their email by setting a password through an emailed link"
+// @Param expires_after query string false "Only businesses whose package expires on or after this date (YYYY-MM-DD) in the business's time zone"
+// @Param expires_before query string false "Only businesses whose package expires before this date (YYYY-MM-DD) in the business's time zone"
+// @Param created_from query string false "Only businesses created on or after this date (YYYY-MM-DD) in the business's time zone"
+// @Param created_to query string false "Only businesses created on or before this date (YYYY-MM-DD) in the business's time zone"
+// @Param include_theme query bool false "Also return each business's public page theme"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with businesses list"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/businesses [get]
+// @Success 200 {object} dto.Response{data=dto.BusinessPage{businesses=[]models.BusinessResponse}} "Success response with businesses list"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /businesses [get]
 func (h *BusinessHandler) GetBusinesses(c *gin.Context) {
+	h.listBusinesses(c, nil)
+}
+
+// listBusinesses writes a page of businesses matching the query filters, after apply
+// fixes the filters of a shortcut route
+func (h *BusinessHandler) listBusinesses(c *gin.Context, apply func(*repository.BusinessFilters)) {
 	var filters repository.BusinessFilters
 	if err := c.ShouldBindQuery(&filters); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid query parameters",
-			"details": err.Error(),
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
 		})
 		return
 	}
+	if apply != nil {
+		apply(&filters)
+	}
+
+	if err := filters.Validate(); err != nil {
+		respondInvalidSelection(c, err)
+		return
+	}
 
-	businesses, total, err := h.businessService.GetBusinesses(filters)
+	selection, err := filters.Selection()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get businesses",
-		})
+		respondInvalidSelection(c, err)
+		return
+	}
+
+	businesses, pageInfo, err := h.businessService.GetBusinesses(c.Request.Context(), filters)
+	if err != nil {
+		if respondInvalidCursor(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get businesses"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"businesses": businesses,
-			"total":      total,
-			"page":       filters.Page,
-			"limit":      filters.Limit,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.BusinessPage{
+			Businesses: selectFields(businesses, selection.Fields),
+			CursorPage: dto.NewCursorPage(pageInfo),
 		},
 	})
 }
 
 // GetBusiness godoc
 // @Summary Get business by ID
-// @Description Get a specific business by ID (Admin only)
+// @Description Get a specific business by ID. package_pricing compares the price and validation period the package was assigned on with the current ones. (Admin only)
 // @Tags businesses
 // @Accept json
 // @Produce json
-// @Param id path int true "Business ID"
+// @Param businessId path int true "Business ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with business data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 404 {object} map[string]string "Business not found"
-// @Router /api/businesses/{id} [get]
+// @Success 200 {object} dto.Response{data=models.BusinessResponse} "Success response with business data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business not found"
+// @Router /businesses/{businessId} [get]
 func (h *BusinessHandler) GetBusiness(c *gin.Context) {
-	idParam := c.Param("id")
+	idParam := c.Param("businessId")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid business ID",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid business ID"})
 		return
 	}
 
-	business, err := h.businessService.GetBusinessByID(uint(id))
+	business, err := h.businessService.GetBusinessByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"error":   "Business not found",
-		})
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Business not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    business,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    business,
 	})
 }
 
@@ -327,38 +498,37 @@ func (h *BusinessHandler) GetBusiness(c *gin.Context) {
 // @Tags businesses
 // @Accept json
 // @Produce json
-// @Param id path int true "Business ID"
+// @Param businessId path int true "Business ID"
 // @Param request body models.UpdateBusinessRequest true "Business update data"
+// @Param If-Match header string false "Version the update is based on, when not sent as the version field"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with updated business data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 404 {object} map[string]string "Business not found"
-// @Router /api/businesses/{id} [put]
+// @Success 200 {object} dto.Response{data=models.BusinessResponse} "Success response with updated business data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business not found"
+// @Failure 409 {object} dto.ErrorResponse "Email already used by another user or business, ignoring case (details names which), or the record was changed since it was read (data holds the current record)"
+// @Failure 428 {object} dto.ErrorResponse "No record version sent"
+// @Router /businesses/{businessId} [put]
 func (h *BusinessHandler) UpdateBusiness(c *gin.Context) {
-	idParam := c.Param("id")
+	idParam := c.Param("businessId")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid business ID",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid business ID"})
 		return
 	}
 
 	var req models.UpdateBusinessRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
+		return
+	}
+	version, ok := requestVersion(c, req.Version)
+	if !ok {
 		return
 	}
 
 	// Convert to map for updates
-	updates := make(map[string]interface{})
+	updates := map[string]interface{}{"version": version}
 	if req.Name != "" {
 		updates["name"] = req.Name
 	}
@@ -386,129 +556,186 @@ func (h *BusinessHandler) UpdateBusiness(c *gin.Context) {
 	if req.Status != nil {
 		updates["status"] = *req.Status
 	}
+	if req.IsOpen != nil {
+		updates["is_open"] = *req.IsOpen
+	}
+	if req.Timezone != nil {
+		updates["timezone"] = *req.Timezone
+	}
+	if req.ThemePrimaryColor != nil {
+		updates["theme_primary_color"] = *req.ThemePrimaryColor
+	}
+	if req.ThemeAccentColor != nil {
+		updates["theme_accent_color"] = *req.ThemeAccentColor
+	}
 
-	updatedBusiness, err := h.businessService.UpdateBusiness(uint(id), updates)
+	updatedBusiness, err := h.businessService.UpdateBusiness(c.Request.Context(), uint(id), updates)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		if writeFieldError(c, err) || writeEmailTaken(c, err) || writeVersionConflict(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Business updated successfully",
-		"data":    updatedBusiness,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Business updated successfully",
+		Data:    updatedBusiness,
 	})
 }
 
 // DeleteBusiness godoc
 // @Summary Delete business
-// @Description Delete a business (Admin only)
+// @Description Delete a business (Admin only). A business that still has teachers, students, attendance or fee records is refused unless cascade is set, which removes all of them and their user accounts.
 // @Tags businesses
 // @Accept json
 // @Produce json
-// @Param id path int true "Business ID"
+// @Param businessId path int true "Business ID"
+// @Param cascade query bool false "Also remove everything the business owns" default(false)
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 404 {object} map[string]string "Business not found"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/businesses/{id} [delete]
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business not found"
+// @Failure 409 {object} dto.ErrorResponse "Business still has dependent records"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /businesses/{businessId} [delete]
 func (h *BusinessHandler) DeleteBusiness(c *gin.Context) {
-	idParam := c.Param("id")
+	idParam := c.Param("businessId")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid business ID",
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid business ID"})
+		return
+	}
+
+	var opts models.DeleteBusinessOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
 		})
 		return
 	}
 
-	err = h.businessService.DeleteBusiness(uint(id))
+	err = h.businessService.DeleteBusiness(c.Request.Context(), uint(id), opts)
 	if err != nil {
 		if err.Error() == "business not found" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"success": false,
-				"error":   "Business not found",
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Business not found"})
+			return
+		}
+		var dependents *models.BusinessDependentsError
+		if errors.As(err, &dependents) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   dependents.Error(),
+				Details: dependents.Counts,
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to delete business",
-		})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to delete business"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Business deleted successfully",
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Business deleted successfully",
 	})
 }
 
 // ChangeBusinessStatus godoc
 // @Summary Change business status
-// @Description Change the status of a business (Admin only)
+// @Description Change the status of a business and its owner's login. With cascade set, its teachers and students and their logins follow: deactivating takes every active one, and reactivating restores only those a cascaded deactivation took, leaving ones that were already inactive. (Admin only)
 // @Tags businesses
 // @Accept json
 // @Produce json
-// @Param id path int true "Business ID"
-// @Param request body map[string]int true "Status data"
+// @Param businessId path int true "Business ID"
+// @Param request body map[string]interface{} true "Status data with status and optional cascade"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 404 {object} map[string]string "Business not found"
-// @Router /api/businesses/{id}/status [patch]
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business not found"
+// @Router /businesses/{businessId}/status [patch]
 func (h *BusinessHandler) ChangeBusinessStatus(c *gin.Context) {
-	idParam := c.Param("id")
+	idParam := c.Param("businessId")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid business ID",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid business ID"})
 		return
 	}
 
 	var req struct {
-		Status int `json:"status" binding:"required,min=0,max=1"`
+		Status  *models.Status `json:"status" binding:"required"`
+		Cascade bool           `json:"cascade"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	err = h.businessService.ChangeBusinessStatus(uint(id), req.Status)
+	err = h.businessService.ChangeBusinessStatus(c.Request.Context(), uint(id), *req.Status, req.Cascade)
 	if err != nil {
 		if err.Error() == "business not found" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"success": false,
-				"error":   "Business not found",
-			})
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Business not found"})
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Business status updated successfully",
+	})
+}
+
+// ChangeBusinessSlug godoc
+// @Summary Change business slug
+// @Description Set a business's slug explicitly, or regenerate it from the current name (numbered when taken). Changes are recorded in the slug history. (Admin only)
+// @Tags businesses
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.ChangeSlugRequest true "Explicit slug or regenerate"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.ChangeSlugResponse} "Success response with the business and its slug history"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business not found"
+// @Failure 409 {object} dto.ErrorResponse "Slug already taken"
+// @Router /businesses/{businessId}/slug [post]
+func (h *BusinessHandler) ChangeBusinessSlug(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("businessId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid business ID"})
+		return
+	}
+
+	var req models.ChangeSlugRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.businessService.ChangeBusinessSlug(c.Request.Context(), uint(id), req, c.GetUint("user_id"))
+	if err != nil {
+		switch err.Error() {
+		case "business not found":
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Business not found"})
+		case "business slug already exists":
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Business status updated successfully",
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Business slug updated successfully",
+		Data:    result,
 	})
 }
 
@@ -518,47 +745,36 @@ func (h *BusinessHandler) ChangeBusinessStatus(c *gin.Context) {
 // @Tags businesses
 // @Accept json
 // @Produce json
-// @Param id path int true "Business ID"
+// @Param businessId path int true "Business ID"
 // @Param request body models.AssignPackageRequest true "Package assignment data"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/businesses/{id}/assign-package [post]
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/assign-package [post]
 func (h *BusinessHandler) AssignPackage(c *gin.Context) {
-	idParam := c.Param("id")
+	idParam := c.Param("businessId")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid business ID",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid business ID"})
 		return
 	}
 
 	var req models.AssignPackageRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	err = h.businessService.AssignPackage(uint(id), req.PackageID)
+	err = h.businessService.AssignPackage(c.Request.Context(), uint(id), req.PackageID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Package assigned successfully",
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Package assigned successfully",
 	})
 }
 
@@ -568,36 +784,30 @@ func (h *BusinessHandler) AssignPackage(c *gin.Context) {
 // @Tags businesses
 // @Accept json
 // @Produce json
-// @Param id path int true "Business ID"
+// @Param businessId path int true "Business ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/businesses/{id}/remove-package [delete]
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/remove-package [delete]
 func (h *BusinessHandler) RemovePackage(c *gin.Context) {
-	idParam := c.Param("id")
+	idParam := c.Param("businessId")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid business ID",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid business ID"})
 		return
 	}
 
-	err = h.businessService.RemovePackage(uint(id))
+	err = h.businessService.RemovePackage(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Package removed successfully",
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Package removed successfully",
 	})
 }
 
@@ -610,19 +820,16 @@ func (h *BusinessHandler) RemovePackage(c *gin.Context) {
 // @Param q query string true "Search term"
 // @Param limit query int false "Maximum number of results" default(10)
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with search results"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/businesses/search [get]
+// @Success 200 {object} dto.Response{data=dto.BusinessSearchResult} "Success response with search results"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /businesses/search [get]
 func (h *BusinessHandler) SearchBusinesses(c *gin.Context) {
 	searchTerm := c.Query("q")
 	if searchTerm == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Search term is required",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Search term is required"})
 		return
 	}
 
@@ -632,174 +839,180 @@ func (h *BusinessHandler) SearchBusinesses(c *gin.Context) {
 		limit = 10
 	}
 
-	businesses, err := h.businessService.SearchBusinesses(searchTerm, limit)
+	businesses, err := h.businessService.SearchBusinesses(c.Request.Context(), searchTerm, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to search businesses",
-		})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to search businesses"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"businesses":  businesses,
-			"search_term": searchTerm,
-			"total_found": len(businesses),
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.BusinessSearchResult{
+			Businesses: businesses,
+			SearchTerm: searchTerm,
+			TotalFound: len(businesses),
 		},
 	})
 }
 
 // GetBusinessStats godoc
 // @Summary Get business statistics
-// @Description Get business statistics (Admin only)
+// @Description Get business statistics. package_revenue sums the packages of active businesses at the price each was assigned on. (Admin only)
 // @Tags businesses
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with statistics"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/businesses/stats [get]
+// @Success 200 {object} dto.Response{data=object} "Success response with statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /businesses/stats [get]
 func (h *BusinessHandler) GetBusinessStats(c *gin.Context) {
-	stats, err := h.businessService.GetBusinessStats()
+	stats, err := h.businessService.GetBusinessStats(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get business statistics",
-		})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get business statistics"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    stats,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    stats,
 	})
 }
 
-// GetActiveBusinesses godoc
-// @Summary Get active businesses
-// @Description Get all active businesses (Admin only)
+// GetGrowthTimeseries godoc
+// @Summary Get growth and churn over time
+// @Description Get the businesses, teachers and students created, deactivated and reactivated per week or month, platform-wide (Admin only)
 // @Tags businesses
 // @Accept json
 // @Produce json
+// @Param granularity query string false "Period length" Enums(week, month) default(month)
+// @Param from query string false "First day of the range (YYYY-MM-DD), defaults to 12 periods before to"
+// @Param to query string false "Last day of the range (YYYY-MM-DD), defaults to today"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with active businesses list"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/businesses/active [get]
-func (h *BusinessHandler) GetActiveBusinesses(c *gin.Context) {
-	businesses, err := h.businessService.GetActiveBusinesses()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get active businesses",
+// @Success 200 {object} dto.Response{data=models.GrowthTimeseries} "Success response with the timeseries"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/stats/timeseries [get]
+func (h *BusinessHandler) GetGrowthTimeseries(c *gin.Context) {
+	var query models.GrowthTimeseriesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    businesses,
+	series, err := h.businessService.GetGrowthTimeseries(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    series,
+	})
+}
+
+// GetActiveBusinesses godoc
+// @Summary Get active businesses
+// @Description Get a page of active businesses; a shortcut for /businesses?status=1 (Admin only)
+// @Tags businesses
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /businesses" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.BusinessPage{businesses=[]models.BusinessResponse}} "Success response with active businesses list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /businesses/active [get]
+func (h *BusinessHandler) GetActiveBusinesses(c *gin.Context) {
+	h.listBusinesses(c, func(filters *repository.BusinessFilters) {
+		filters.Status = statusFilter(true)
 	})
 }
 
 // GetInactiveBusinesses godoc
 // @Summary Get inactive businesses
-// @Description Get all inactive businesses (Admin only)
+// @Description Get a page of inactive businesses; a shortcut for /businesses?status=0 (Admin only)
 // @Tags businesses
 // @Accept json
 // @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /businesses" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with inactive businesses list"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/businesses/inactive [get]
+// @Success 200 {object} dto.Response{data=dto.BusinessPage{businesses=[]models.BusinessResponse}} "Success response with inactive businesses list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /businesses/inactive [get]
 func (h *BusinessHandler) GetInactiveBusinesses(c *gin.Context) {
-	businesses, err := h.businessService.GetInactiveBusinesses()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get inactive businesses",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    businesses,
+	h.listBusinesses(c, func(filters *repository.BusinessFilters) {
+		filters.Status = statusFilter(false)
 	})
 }
 
 // GetBusinessesByPackage godoc
 // @Summary Get businesses by package
-// @Description Get all businesses assigned to a specific package (Admin only)
+// @Description Get a page of the businesses assigned to a specific package; a shortcut for /businesses?package_id= (Admin only)
 // @Tags businesses
 // @Accept json
 // @Produce json
 // @Param packageId path int true "Package ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /businesses" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with businesses list"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/businesses/package/{packageId} [get]
+// @Success 200 {object} dto.Response{data=dto.BusinessPage{businesses=[]models.BusinessResponse}} "Success response with businesses list"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /businesses/package/{packageId} [get]
 func (h *BusinessHandler) GetBusinessesByPackage(c *gin.Context) {
 	packageIDParam := c.Param("packageId")
 	packageID, err := strconv.ParseUint(packageIDParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid package ID",
-		})
-		return
-	}
-
-	businesses, err := h.businessService.GetBusinessesByPackage(uint(packageID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get businesses by package",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid package ID"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    businesses,
+	id := uint(packageID)
+	h.listBusinesses(c, func(filters *repository.BusinessFilters) {
+		filters.PackageID = &id
 	})
 }
 
 // GetBusinessesWithoutPackage godoc
 // @Summary Get businesses without package
-// @Description Get all businesses that don't have any package assigned (Admin only)
+// @Description Get a page of the businesses that don't have any package assigned; a shortcut for /businesses?has_package=false (Admin only)
 // @Tags businesses
 // @Accept json
 // @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /businesses" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with businesses list"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/businesses/no-package [get]
+// @Success 200 {object} dto.Response{data=dto.BusinessPage{businesses=[]models.BusinessResponse}} "Success response with businesses list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /businesses/no-package [get]
 func (h *BusinessHandler) GetBusinessesWithoutPackage(c *gin.Context) {
-	businesses, err := h.businessService.GetBusinessesWithoutPackage()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get businesses without package",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    businesses,
+	h.listBusinesses(c, func(filters *repository.BusinessFilters) {
+		hasPackage := false
+		filters.HasPackage = &hasPackage
 	})
 }
 
@@ -810,24 +1023,67 @@ func (h *BusinessHandler) GetBusinessesWithoutPackage(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with location statistics"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/businesses/stats/locations [get]
+// @Success 200 {object} dto.Response{data=map[string]int64} "Success response with location statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /businesses/stats/locations [get]
 func (h *BusinessHandler) GetLocationStats(c *gin.Context) {
-	stats, err := h.businessService.GetLocationStats()
+	stats, err := h.businessService.GetLocationStats(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get location statistics",
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get location statistics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+// GetAtRiskBusinesses godoc
+// @Summary List at-risk businesses
+// @Description List the active businesses likely to churn, each with the signals it triggered and a score counting them: no student added in student_days, owner not logged in for login_days, and package expiring within expiry_days or already expired. Businesses younger than a threshold don't trigger its signal; an owner with no recorded login is judged from when their account was created. (Admin only)
+// @Tags businesses
+// @Produce json
+// @Param student_days query int false "Days without a new student" default(60)
+// @Param login_days query int false "Days since the owner last logged in" default(30)
+// @Param expiry_days query int false "Days until the package expires" default(14)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param sort_by query string false "Comma-separated sort columns (score, name, created_on, last_student_at, owner_last_login_at, package_expires_at)" default(score)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.ListResponse{data=[]models.AtRiskBusiness} "Success response with at-risk businesses"
+// @Failure 400 {object} dto.ErrorResponse "Negative threshold, or unknown sort column; valid_options lists the accepted ones"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /businesses/at-risk [get]
+func (h *BusinessHandler) GetAtRiskBusinesses(c *gin.Context) {
+	var filters repository.AtRiskFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
 		})
 		return
 	}
+	if err := filters.Validate(); err != nil {
+		respondInvalidSelection(c, err)
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    stats,
+	businesses, pageInfo, err := h.businessService.GetAtRiskBusinesses(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ListResponse{
+		Success:    true,
+		Data:       businesses,
+		Pagination: dto.NewPagination(pageInfo),
 	})
 }
 
@@ -838,150 +1094,180 @@ func (h *BusinessHandler) GetLocationStats(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with package distribution statistics"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/businesses/stats/packages [get]
+// @Success 200 {object} dto.Response{data=map[string]int64} "Success response with package distribution statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /businesses/stats/packages [get]
 func (h *BusinessHandler) GetPackageDistribution(c *gin.Context) {
-	stats, err := h.businessService.GetPackageDistribution()
+	stats, err := h.businessService.GetPackageDistribution(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get package distribution",
-		})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get package distribution"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    stats,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    stats,
 	})
 }
 
 // BulkUpdateStatus godoc
 // @Summary Bulk update business status
-// @Description Update status for multiple businesses (Admin only)
+// @Description Update status for multiple businesses and their owners' logins. IDs that don't exist are skipped and listed, unless atomic is set, in which case nothing changes unless every ID can be updated. With cascade set, each business's teachers and students follow as in the single status change. (Admin only)
 // @Tags businesses
 // @Accept json
 // @Produce json
-// @Param request body map[string]interface{} true "Bulk update data with business_ids and status"
+// @Param request body map[string]interface{} true "Bulk update data with business_ids, status and optional atomic and cascade"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/businesses/bulk/status [post]
+// @Success 200 {object} dto.Response{data=models.BulkUpdateResult} "Every ID was updated"
+// @Success 207 {object} dto.Response{data=models.BulkUpdateResult} "Some IDs were skipped; skipped says why"
+// @Failure 400 {object} dto.ErrorResponse{data=models.BulkUpdateResult} "Bad request, or an atomic request that would have skipped IDs"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/bulk/status [post]
 func (h *BusinessHandler) BulkUpdateStatus(c *gin.Context) {
 	var req struct {
-		BusinessIDs []uint `json:"business_ids" binding:"required"`
-		Status      int    `json:"status" binding:"required,min=0,max=1"`
+		BusinessIDs []uint         `json:"business_ids" binding:"required"`
+		Status      *models.Status `json:"status" binding:"required"`
+		Atomic      bool           `json:"atomic"`
+		Cascade     bool           `json:"cascade"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	err := h.businessService.BulkUpdateBusinessStatus(req.BusinessIDs, req.Status)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+	result, err := h.businessService.BulkUpdateBusinessStatus(c.Request.Context(), req.BusinessIDs, *req.Status, req.Atomic, req.Cascade)
+	if err != nil && !errors.Is(err, services.ErrBulkIncomplete) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Business statuses updated successfully",
-	})
+	writeBulkResult(c, result, err, "Business statuses updated")
 }
 
 // BulkAssignPackage godoc
 // @Summary Bulk assign package to businesses
-// @Description Assign a package to multiple businesses (Admin only)
+// @Description Assign a package to multiple businesses. IDs that don't exist are skipped and listed, unless atomic is set, in which case nothing changes unless every ID can be updated. (Admin only)
 // @Tags businesses
 // @Accept json
 // @Produce json
-// @Param request body map[string]interface{} true "Bulk assignment data with business_ids and package_id"
+// @Param request body map[string]interface{} true "Bulk assignment data with business_ids, package_id and optional atomic"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/businesses/bulk/assign-package [post]
+// @Success 200 {object} dto.Response{data=models.BulkUpdateResult} "Every ID was updated"
+// @Success 207 {object} dto.Response{data=models.BulkUpdateResult} "Some IDs were skipped; skipped says why"
+// @Failure 400 {object} dto.ErrorResponse{data=models.BulkUpdateResult} "Bad request, or an atomic request that would have skipped IDs"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/bulk/assign-package [post]
 func (h *BusinessHandler) BulkAssignPackage(c *gin.Context) {
 	var req struct {
 		BusinessIDs []uint `json:"business_ids" binding:"required"`
 		PackageID   uint   `json:"package_id" binding:"required"`
+		Atomic      bool   `json:"atomic"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.businessService.BulkAssignPackage(c.Request.Context(), req.BusinessIDs, req.PackageID, req.Atomic)
+	if err != nil && !errors.Is(err, services.ErrBulkIncomplete) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	writeBulkResult(c, result, err, "Package assigned to businesses")
+}
+
+// BulkRemovePackage godoc
+// @Summary Bulk remove package from businesses
+// @Description Take the package off multiple businesses, recording each change in the package history (Admin only)
+// @Tags businesses
+// @Accept json
+// @Produce json
+// @Param request body models.BulkRemovePackageRequest true "Businesses to remove the package from"
+// @Security BearerAuth
+// @Success 200 {object} models.BulkPackageChangeResponse "Number of businesses changed"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/bulk/remove-package [post]
+func (h *BusinessHandler) BulkRemovePackage(c *gin.Context) {
+	var req models.BulkRemovePackageRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	err := h.businessService.BulkAssignPackage(req.BusinessIDs, req.PackageID)
+	updated, err := h.businessService.BulkRemovePackage(c.Request.Context(), req.BusinessIDs, c.GetUint("user_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Package assigned to businesses successfully",
-	})
+	c.JSON(http.StatusOK, models.BulkPackageChangeResponse{Updated: updated})
 }
 
-// GetBusinessesByLocation godoc
-// @Summary Get businesses by location
-// @Description Get all businesses filtered by location (Admin only)
+// SwapPackage godoc
+// @Summary Swap package
+// @Description Move every business on one package to another active package, recording each change in the package history (Admin only)
 // @Tags businesses
 // @Accept json
 // @Produce json
-// @Param location query string true "Location filter"
+// @Param request body models.SwapPackageRequest true "Package to move businesses from and to"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with businesses list"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/businesses/by-location [get]
-func (h *BusinessHandler) GetBusinessesByLocation(c *gin.Context) {
-	location := c.Query("location")
-	if location == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Location parameter is required",
-		})
+// @Success 200 {object} models.BulkPackageChangeResponse "Number of businesses changed"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Package not found"
+// @Router /businesses/bulk/swap-package [post]
+func (h *BusinessHandler) SwapPackage(c *gin.Context) {
+	var req models.SwapPackageRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	businesses, err := h.businessService.GetBusinessesByLocation(location)
+	updated, err := h.businessService.SwapPackage(c.Request.Context(), req.FromPackageID, req.ToPackageID, c.GetUint("user_id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get businesses by location",
-		})
+		status := http.StatusBadRequest
+		if err.Error() == "package not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    businesses,
-	})
+	c.JSON(http.StatusOK, models.BulkPackageChangeResponse{Updated: updated})
+}
+
+// GetBusinessesByLocation godoc
+// @Summary Get businesses by location
+// @Description Get a page of the businesses whose location contains the filter; a shortcut for /businesses?location= (Admin only)
+// @Tags businesses
+// @Accept json
+// @Produce json
+// @Param location query string true "Location filter"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /businesses" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.BusinessPage{businesses=[]models.BusinessResponse}} "Success response with businesses list"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /businesses/by-location [get]
+func (h *BusinessHandler) GetBusinessesByLocation(c *gin.Context) {
+	if c.Query("location") == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Location parameter is required"})
+		return
+	}
+
+	// The location query parameter is the listing's own location filter
+	h.listBusinesses(c, nil)
 }
 
 // GetBusinessLocations godoc
@@ -991,23 +1277,20 @@ func (h *BusinessHandler) GetBusinessesByLocation(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with locations list"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/businesses/locations [get]
+// @Success 200 {object} dto.Response{data=[]string} "Success response with locations list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /businesses/locations [get]
 func (h *BusinessHandler) GetBusinessLocations(c *gin.Context) {
-	locations, err := h.businessService.GetBusinessLocations()
+	locations, err := h.businessService.GetBusinessLocations(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get business locations",
-		})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get business locations"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    locations,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    locations,
 	})
 }
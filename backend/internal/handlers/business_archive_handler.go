@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BusinessArchiveHandler struct {
+	archiveService  services.BusinessArchiveService
+	businessService services.BusinessService
+	auditService    services.AuditService
+}
+
+func NewBusinessArchiveHandler(archiveService services.BusinessArchiveService, businessService services.BusinessService, auditService services.AuditService) *BusinessArchiveHandler {
+	return &BusinessArchiveHandler{
+		archiveService:  archiveService,
+		businessService: businessService,
+		auditService:    auditService,
+	}
+}
+
+// ExportBusinessArchive godoc
+// @Summary Export a business archive
+// @Description Download everything a business owns as a ZIP of JSON files: the business and its owner, subjects, batches, student fields and grades, teachers with their subjects and availability, students with their guardians, attendance, fee plans and payments. manifest.json names the archive format and version and counts the records in each file. Passwords, documents and gateway references are not included. (Admin only)
+// @Tags businesses
+// @Produce application/zip
+// @Param businessId path int true "Business ID"
+// @Security BearerAuth
+// @Success 200 {file} file "ZIP archive"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business not found"
+// @Router /businesses/{businessId}/export-archive [get]
+func (h *BusinessArchiveHandler) ExportBusinessArchive(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("businessId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid business ID"})
+		return
+	}
+	businessID := uint(id)
+
+	if _, err := h.businessService.GetBusinessByID(c.Request.Context(), businessID); err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Business not found"})
+		return
+	}
+
+	fileName := fmt.Sprintf("business-%d-%s.zip", businessID, time.Now().Format(models.DateFormat))
+	var records int64
+	streamDownload(c, "application/zip", fileName, "Failed to export business", func(w io.Writer) error {
+		records, err = h.archiveService.ExportArchive(c.Request.Context(), businessID, w)
+		return err
+	})
+	if err == nil {
+		auditDataAccess(c, h.auditService, models.AuditLog{Action: models.AuditActionExport, Resource: models.AuditResourceBusinessArchive, ResourceID: &businessID, BusinessID: &businessID, RowCount: records})
+	}
+}
+
+// ImportBusinessArchive godoc
+// @Summary Import a business archive
+// @Description Recreate an exported business as a new business with fresh IDs. The whole archive is validated first and nothing is created unless it is valid; the import then runs in a single transaction. Archives from newer versions of the server are rejected. Pass name, slug or email to import next to the business the archive came from; a taken slug is numbered. Imported users get no usable password and set one through forgot password. (Admin only)
+// @Tags businesses
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "ZIP archive from export-archive"
+// @Param name formData string false "Name for the new business"
+// @Param slug formData string false "Slug for the new business"
+// @Param email formData string false "Email for the new business and its owner"
+// @Param owner_email formData string false "Login email for the owner, when it differs from the business email"
+// @Param bootstrap formData bool false "Seed the defaults of a new business (batch, student fields, welcome announcement) where the archive has none"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.ImportArchiveResult} "Success response with the new business ID and imported counts"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 422 {object} dto.ErrorResponse "Archive is not valid, with the problems found"
+// @Router /businesses/import-archive [post]
+func (h *BusinessArchiveHandler) ImportBusinessArchive(c *gin.Context) {
+	var req models.ImportArchiveRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Archive file is required",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	result, err := h.archiveService.ImportArchive(c.Request.Context(), file, header.Size, req, c.GetUint("user_id"))
+	if err != nil {
+		var invalid *models.ArchiveValidationError
+		if errors.As(err, &invalid) {
+			c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error:   "Archive is not valid",
+				Details: invalid.Problems,
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Business imported successfully",
+		Data:    result,
+	})
+}
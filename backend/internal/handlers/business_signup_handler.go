@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BusinessSignupHandler struct {
+	signupService services.BusinessSignupService
+}
+
+func NewBusinessSignupHandler(signupService services.BusinessSignupService) *BusinessSignupHandler {
+	return &BusinessSignupHandler{
+		signupService: signupService,
+	}
+}
+
+// SubmitBusinessSignup godoc
+// @Summary Sign up a business
+// @Description Ask for a business account. The sign-up waits for an admin, who is notified; nothing is created until it is approved, when the owner is emailed a link to set their password. The email may not be used by a user, a business or another sign-up awaiting approval. (Public)
+// @Tags business-signups
+// @Accept json
+// @Produce json
+// @Param request body models.BusinessSignupRequest true "Business and owner details"
+// @Success 201 {object} dto.Response{data=models.BusinessSignupReceived} "Sign-up received"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 409 {object} dto.ErrorResponse "Email already used, ignoring case; details names where (users, business or pending_signups)"
+// @Failure 429 {object} dto.ErrorResponse "Too many requests"
+// @Router /business-signups [post]
+func (h *BusinessSignupHandler) SubmitBusinessSignup(c *gin.Context) {
+	var req models.BusinessSignupRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	received, err := h.signupService.Submit(c.Request.Context(), req)
+	if err != nil {
+		if writeFieldError(c, err) || writeEmailTaken(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Sign-up received, you will hear from us once it is reviewed",
+		Data:    received,
+	})
+}
+
+// GetBusinessSignups godoc
+// @Summary Get business sign-ups
+// @Description Get the businesses that signed up on their own, newest first (Admin only)
+// @Tags business-signups
+// @Produce json
+// @Param status query string false "Filter by status (pending, approved, rejected)"
+// @Param search query string false "Search in name, owner name and email"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.BusinessSignupPage} "Success response with sign-ups"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /business-signups [get]
+func (h *BusinessSignupHandler) GetBusinessSignups(c *gin.Context) {
+	var filters repository.BusinessSignupFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	signups, total, err := h.signupService.GetSignups(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to retrieve sign-ups",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.BusinessSignupPage{
+			Signups:    signups,
+			OffsetPage: dto.OffsetPage{Total: total, Page: filters.Page, Limit: filters.Limit},
+		},
+	})
+}
+
+// GetBusinessSignup godoc
+// @Summary Get a business sign-up
+// @Description Get a sign-up by ID (Admin only)
+// @Tags business-signups
+// @Produce json
+// @Param signupId path int true "Sign-up ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.PendingSignup} "Success response with the sign-up"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Sign-up not found"
+// @Router /business-signups/{signupId} [get]
+func (h *BusinessSignupHandler) GetBusinessSignup(c *gin.Context) {
+	signupID, ok := parseSignupID(c)
+	if !ok {
+		return
+	}
+
+	signup, err := h.signupService.GetSignup(c.Request.Context(), signupID)
+	if err != nil {
+		respondSignupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    signup,
+	})
+}
+
+// ApproveBusinessSignup godoc
+// @Summary Approve a business sign-up
+// @Description Create the business and its owner's account from a pending sign-up, as creating a business does, and email the owner a link to set their password. package_id, bootstrap and sample_data set up the business like they do there. (Admin only)
+// @Tags business-signups
+// @Accept json
+// @Produce json
+// @Param signupId path int true "Sign-up ID"
+// @Param request body models.ApproveBusinessSignupRequest false "How to set up the business"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.ApprovedBusinessSignup} "Success response with the sign-up and the business"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Sign-up not found"
+// @Failure 409 {object} dto.ErrorResponse "Sign-up already reviewed, or its email has been taken since"
+// @Router /business-signups/{signupId}/approve [post]
+func (h *BusinessSignupHandler) ApproveBusinessSignup(c *gin.Context) {
+	signupID, ok := parseSignupID(c)
+	if !ok {
+		return
+	}
+
+	// Every field is optional, so is the body
+	var req models.ApproveBusinessSignupRequest
+	if c.Request.ContentLength != 0 {
+		if !bindJSON(c, &req) {
+			return
+		}
+	}
+
+	approved, err := h.signupService.Approve(c.Request.Context(), signupID, req, c.GetUint("user_id"))
+	if err != nil {
+		respondSignupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Sign-up approved and business created",
+		Data:    approved,
+	})
+}
+
+// RejectBusinessSignup godoc
+// @Summary Reject a business sign-up
+// @Description Reject a pending sign-up, emailing the owner the reason (Admin only)
+// @Tags business-signups
+// @Accept json
+// @Produce json
+// @Param signupId path int true "Sign-up ID"
+// @Param request body models.RejectBusinessSignupRequest true "Reason for the owner"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.PendingSignup} "Success response with the sign-up"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Sign-up not found"
+// @Failure 409 {object} dto.ErrorResponse "Sign-up already reviewed"
+// @Router /business-signups/{signupId}/reject [post]
+func (h *BusinessSignupHandler) RejectBusinessSignup(c *gin.Context) {
+	signupID, ok := parseSignupID(c)
+	if !ok {
+		return
+	}
+
+	var req models.RejectBusinessSignupRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	signup, err := h.signupService.Reject(c.Request.Context(), signupID, req, c.GetUint("user_id"))
+	if err != nil {
+		respondSignupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Sign-up rejected",
+		Data:    signup,
+	})
+}
+
+func parseSignupID(c *gin.Context) (uint, bool) {
+	signupID, err := strconv.ParseUint(c.Param("signupId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid sign-up ID"})
+		return 0, false
+	}
+	return uint(signupID), true
+}
+
+func respondSignupError(c *gin.Context, err error) {
+	if writeFieldError(c, err) || writeEmailTaken(c, err) {
+		return
+	}
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, services.ErrSignupNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrSignupReviewed):
+		status = http.StatusConflict
+	}
+	c.JSON(status, dto.ErrorResponse{Error: err.Error()})
+}
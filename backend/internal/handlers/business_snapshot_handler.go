@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BusinessSnapshotHandler struct {
+	snapshotService services.BusinessSnapshotService
+}
+
+func NewBusinessSnapshotHandler(snapshotService services.BusinessSnapshotService) *BusinessSnapshotHandler {
+	return &BusinessSnapshotHandler{
+		snapshotService: snapshotService,
+	}
+}
+
+// GetMyBusinessTrends godoc
+// @Summary Get my business's trends
+// @Description Get the active teachers and students of the current business per day, from the daily snapshots. Days without a snapshot carry the counts of the one before and are marked filled; counts are null before the first snapshot. (Business users only)
+// @Tags trends
+// @Produce json
+// @Param from query string false "First day of the range (YYYY-MM-DD), defaults to 29 days before to"
+// @Param to query string false "Last day of the range (YYYY-MM-DD), defaults to today in the business's time zone; at most 366 days in all"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.BusinessTrends} "Success response with the trends"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/trends [get]
+func (h *BusinessSnapshotHandler) GetMyBusinessTrends(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.snapshotService)
+	if !ok {
+		return
+	}
+	h.writeTrends(c, businessID)
+}
+
+// GetBusinessTrends godoc
+// @Summary Get a business's trends
+// @Description Get the active teachers and students of a business per day, from the daily snapshots. Days without a snapshot carry the counts of the one before and are marked filled; counts are null before the first snapshot. (Admin/Business only)
+// @Tags trends
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param from query string false "First day of the range (YYYY-MM-DD), defaults to 29 days before to"
+// @Param to query string false "Last day of the range (YYYY-MM-DD), defaults to today in the business's time zone; at most 366 days in all"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.BusinessTrends} "Success response with the trends"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business not found"
+// @Router /businesses/{businessId}/trends [get]
+func (h *BusinessSnapshotHandler) GetBusinessTrends(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.snapshotService)
+	if !ok {
+		return
+	}
+	h.writeTrends(c, businessID)
+}
+
+func (h *BusinessSnapshotHandler) writeTrends(c *gin.Context, businessID uint) {
+	var query models.BusinessTrendsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	trends, err := h.snapshotService.GetTrends(c.Request.Context(), businessID, query)
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "business not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    trends,
+	})
+}
+
+// RunBusinessSnapshots godoc
+// @Summary Snapshot business counts now
+// @Description Run the daily snapshot job now, saving every business's current active teachers and students as its snapshot for today in its time zone and replacing one taken earlier today. Use it to catch up on a day the scheduler missed; past days can't be snapshotted, as only current counts are known. (Admin only)
+// @Tags trends
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.SnapshotRunResult} "Success response with the number of snapshots saved"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/business-snapshots [post]
+func (h *BusinessSnapshotHandler) RunBusinessSnapshots(c *gin.Context) {
+	result, err := h.snapshotService.Snapshot(c.Request.Context(), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Business counts snapshotted",
+		Data:    result,
+	})
+}
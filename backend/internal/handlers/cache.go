@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// publicCacheMaxAge is how long, in seconds, browsers and proxies may reuse a public
+// response without revalidating
+var publicCacheMaxAge = 60
+
+// ConfigurePublicCache sets how many seconds public responses may be reused without
+// revalidating. It must be called before the routes serve requests.
+func ConfigurePublicCache(maxAge int) {
+	publicCacheMaxAge = maxAge
+}
+
+// weakETag builds a weak ETag from the values a response is derived from, typically IDs
+// and updated_on timestamps, so it changes whenever one of the records does
+func weakETag(parts ...interface{}) string {
+	hash := sha1.New()
+	for _, part := range parts {
+		if t, ok := part.(time.Time); ok {
+			part = t.UTC().UnixNano()
+		}
+		fmt.Fprintf(hash, "%v|", part)
+	}
+	return `W/"` + hex.EncodeToString(hash.Sum(nil)) + `"`
+}
+
+// notModified sets the caching headers of a public response and, when the client's
+// If-None-Match already holds etag, answers 304 and returns true so the handler can
+// skip writing the body
+func notModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", publicCacheMaxAge))
+
+	for _, candidate := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		// Weak comparison: W/"x" and "x" match each other
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
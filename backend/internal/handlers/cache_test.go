@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fixedPackages serves the active packages it holds
+type fixedPackages struct {
+	services.PackageService
+	packages []models.PackageResponse
+}
+
+func (p *fixedPackages) GetActivePackages(ctx context.Context) ([]models.PackageResponse, error) {
+	return p.packages, nil
+}
+
+// fixedBusiness serves the business it holds for any slug
+type fixedBusiness struct {
+	services.BusinessService
+	business models.BusinessResponse
+}
+
+func (b *fixedBusiness) GetBusinessBySlug(ctx context.Context, slug string) (*models.BusinessResponse, error) {
+	business := b.business
+	return &business, nil
+}
+
+func get(r http.Handler, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// Public responses revalidate to 304 while nothing changed, and to 200 with a new ETag
+// once a record they are built from is updated
+func TestPublicResponsesRevalidate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	updated := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	packages := &fixedPackages{packages: []models.PackageResponse{{ID: 1, Name: "Basic", UpdatedOn: updated}, {ID: 2, Name: "Pro", UpdatedOn: updated}}}
+	business := &fixedBusiness{business: models.BusinessResponse{ID: 1, Name: "Sunrise Academy", Slug: "sunrise-academy", UpdatedOn: updated}}
+
+	r := gin.New()
+	r.GET("/api/packages/public", NewPackageHandler(packages).GetPublicPackages)
+	r.GET("/api/business/:slug", NewBusinessHandler(business).GetBusinessBySlug)
+
+	tests := []struct {
+		name   string
+		path   string
+		update func()
+	}{
+		{"public packages", "/api/packages/public", func() { packages.packages[1].UpdatedOn = updated.Add(time.Second) }},
+		{"business by slug", "/api/business/sunrise-academy", func() { business.business.UpdatedOn = updated.Add(time.Millisecond) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := get(r, tt.path, "")
+			etag := first.Header().Get("ETag")
+			if first.Code != http.StatusOK || !strings.HasPrefix(etag, `W/"`) {
+				t.Fatalf("first request = %d with ETag %q, want 200 with a weak ETag", first.Code, etag)
+			}
+			if got := first.Header().Get("Cache-Control"); got != "public, max-age=60" {
+				t.Errorf("Cache-Control = %q, want %q", got, "public, max-age=60")
+			}
+
+			again := get(r, tt.path, etag)
+			if again.Code != http.StatusNotModified || again.Body.Len() != 0 {
+				t.Fatalf("unchanged request = %d with %d bytes, want 304 and no body", again.Code, again.Body.Len())
+			}
+			if again.Header().Get("ETag") != etag {
+				t.Errorf("304 ETag = %q, want %q", again.Header().Get("ETag"), etag)
+			}
+
+			tt.update()
+			changed := get(r, tt.path, etag)
+			if changed.Code != http.StatusOK || changed.Body.Len() == 0 {
+				t.Fatalf("request after an update = %d, want 200 with the body", changed.Code)
+			}
+			if next := changed.Header().Get("ETag"); next == etag || next == "" {
+				t.Errorf("ETag after an update = %q, want a new one", next)
+			}
+		})
+	}
+}
+
+func TestNotModifiedComparison(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	etag := weakETag(1, time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC))
+	strong := strings.TrimPrefix(etag, "W/")
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{"no header", "", false},
+		{"same weak ETag", etag, true},
+		{"same ETag sent strong", strong, true},
+		{"any", "*", true},
+		{"one of several", `W/"stale", ` + etag + `, "other"`, true},
+		{"another ETag", `W/"stale"`, false},
+		{"W/ alone does not match", "W/", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.ifNoneMatch != "" {
+				c.Request.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			if got := notModified(c, etag); got != tt.want {
+				t.Errorf("notModified = %v, want %v", got, tt.want)
+			}
+			if w.Header().Get("ETag") != etag {
+				t.Errorf("ETag = %q, want %q", w.Header().Get("ETag"), etag)
+			}
+		})
+	}
+
+	// Timestamps are compared as instants, whatever zone they were read in
+	utc := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	if weakETag(1, utc) != weakETag(1, utc.In(time.FixedZone("IST", 19800))) {
+		t.Error("weakETag differs for the same instant in another zone")
+	}
+}
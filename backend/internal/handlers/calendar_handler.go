@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarHandler struct {
+	calendarService services.CalendarService
+}
+
+func NewCalendarHandler(calendarService services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// IssueCalendarToken godoc
+// @Summary Enable my business calendar feed
+// @Description Issue the secret link of the business's iCalendar feed, to subscribe to from Google Calendar and the like. Any earlier link stops working. (Business users only)
+// @Tags business-profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.CalendarFeedResponse} "Success response with the feed token and URL"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/calendar-token [post]
+func (h *CalendarHandler) IssueCalendarToken(c *gin.Context) {
+	token, err := h.calendarService.IssueToken(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: models.CalendarFeedResponse{
+			Token: token,
+			URL:   calendarFeedURL(c, token),
+		},
+	})
+}
+
+// RevokeCalendarToken godoc
+// @Summary Disable my business calendar feed
+// @Description Revoke the link of the business's iCalendar feed; subscribed calendars stop updating (Business users only)
+// @Tags business-profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Feed not enabled"
+// @Router /my-business/calendar-token [delete]
+func (h *CalendarHandler) RevokeCalendarToken(c *gin.Context) {
+	if err := h.calendarService.RevokeToken(c.Request.Context(), c.GetUint("user_id")); err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Calendar feed disabled",
+	})
+}
+
+// GetCalendarFeed godoc
+// @Summary Get business calendar feed
+// @Description iCalendar feed of a business's upcoming batch starts and ends, exams and teachers' weekly availability with their assigned batches. Authenticated by the token of the feed link instead of a bearer token, since calendar clients can't send one.
+// @Tags business-profile
+// @Produce text/calendar
+// @Param token query string true "Calendar feed token"
+// @Param days query int false "How many days ahead to include (default 60)"
+// @Success 200 {file} file "iCalendar document"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Invalid or revoked token"
+// @Router /my-business/calendar.ics [get]
+func (h *CalendarHandler) GetCalendarFeed(c *gin.Context) {
+	days := 0
+	if value := c.Query("days"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "days must be a positive number"})
+			return
+		}
+		days = n
+	}
+
+	feed, err := h.calendarService.GetFeed(c.Request.Context(), c.Query("token"), days)
+	if err != nil {
+		if errors.Is(err, services.ErrCalendarTokenInvalid) {
+			c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
+
+// calendarFeedURL is the absolute URL of the feed, under the same API version prefix the
+// token was requested through
+func calendarFeedURL(c *gin.Context, token string) string {
+	path := strings.TrimSuffix(c.FullPath(), "/calendar-token") + "/calendar.ics"
+	return requestOrigin(c) + path + "?token=" + url.QueryEscape(token)
+}
+
+// requestOrigin is the scheme and host the API was reached at, for building absolute URLs
+func requestOrigin(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
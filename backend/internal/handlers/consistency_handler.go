@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConsistencyHandler struct {
+	consistencyService services.ConsistencyService
+}
+
+func NewConsistencyHandler(consistencyService services.ConsistencyService) *ConsistencyHandler {
+	return &ConsistencyHandler{
+		consistencyService: consistencyService,
+	}
+}
+
+// CheckConsistency godoc
+// @Summary Check data consistency
+// @Description Look for active rows that point at rows which no longer exist, or lack the rows that should point at them: businesses without their owner user, business users without a business, and teachers and students without their business or user. Every class is listed with the number of affected rows, the IDs of the first few and what a repair does about it. Inactive rows are left out. (Admin only)
+// @Tags consistency
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.ConsistencyReport} "Success response with consistency report"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/consistency-check [get]
+func (h *ConsistencyHandler) CheckConsistency(c *gin.Context) {
+	report, err := h.consistencyService.Check(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// RepairConsistency godoc
+// @Summary Repair data consistency
+// @Description Fix the selected classes of inconsistency found by the consistency check, listing every row changed. Businesses without their owner are relinked to the business user with the same email who has no business, and deactivated when there is none; the other rows are deactivated, teachers and students without a business together with their logins. Runs as a dry run, reporting without changing anything, unless dry_run is false. (Admin only)
+// @Tags consistency
+// @Accept json
+// @Produce json
+// @Param request body models.ConsistencyRepairRequest true "Classes to repair"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.ConsistencyRepairResult} "Success response with the changes"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/consistency-repair [post]
+func (h *ConsistencyHandler) RepairConsistency(c *gin.Context) {
+	var req models.ConsistencyRepairRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.consistencyService.Repair(c.Request.Context(), req, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	message := "Consistency repaired"
+	if result.DryRun {
+		message = "Dry run, nothing was changed"
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: message,
+		Data:    result,
+	})
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/pkg/logger"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamCSV sends the CSV that write produces as a file download
+func streamCSV(c *gin.Context, fileName, failure string, write func(io.Writer) error) {
+	streamDownload(c, "text/csv; charset=utf-8", fileName, failure, write)
+}
+
+// streamDownload sends what write produces as a file download. An error before anything
+// was written still gets a JSON response; a later one can only cut the download short, so
+// it is logged.
+func streamDownload(c *gin.Context, contentType, fileName, failure string, write func(io.Writer) error) {
+	header := c.Writer.Header()
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	header.Set("Cache-Control", "private, no-store")
+
+	err := write(c.Writer)
+	if err == nil {
+		return
+	}
+
+	if !c.Writer.Written() {
+		header.Del("Content-Type")
+		header.Del("Content-Disposition")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   failure,
+			Details: err.Error(),
+		})
+		return
+	}
+	logger.FromContext(c.Request.Context()).Error("Export failed part way", "file", fileName, "error", err)
+}
+
+// exportFileName names an export download after the business and today's date
+func exportFileName(kind string, businessID uint) string {
+	return fmt.Sprintf("%s-%d-%s.csv", kind, businessID, time.Now().Format(models.DateFormat))
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocumentHandler struct {
+	documentService services.DocumentService
+}
+
+func NewDocumentHandler(documentService services.DocumentService) *DocumentHandler {
+	return &DocumentHandler{
+		documentService: documentService,
+	}
+}
+
+// GetStudentReportCard godoc
+// @Summary Print student report card
+// @Description Render a student's report card as a PDF: exam results, subject averages and attendance over the period, headed with the business's name, address and logo (Admin/Business only)
+// @Tags documents
+// @Produce application/pdf
+// @Param id path int true "Student ID"
+// @Param subject_id query int false "Subject ID"
+// @Param from query string false "From date (YYYY-MM-DD, inclusive)"
+// @Param to query string false "To date (YYYY-MM-DD, inclusive)"
+// @Security BearerAuth
+// @Success 200 {file} file "Report card PDF"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Student not found"
+// @Router /students/{id}/report-card.pdf [get]
+func (h *DocumentHandler) GetStudentReportCard(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.documentService)
+	if !ok {
+		return
+	}
+
+	var filters repository.ExamFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filters.BusinessID = nil
+
+	document, err := h.documentService.GetReportCard(c.Request.Context(), studentID, filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	sendDocument(c, document)
+}
+
+// GetPaymentReceipt godoc
+// @Summary Print fee receipt
+// @Description Render the receipt of a fee payment as a PDF, headed with the business's name, address and logo (Admin/Business only)
+// @Tags documents
+// @Produce application/pdf
+// @Param id path int true "Payment ID"
+// @Security BearerAuth
+// @Success 200 {file} file "Receipt PDF"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Payment not found"
+// @Router /payments/{id}/receipt.pdf [get]
+func (h *DocumentHandler) GetPaymentReceipt(c *gin.Context) {
+	paymentID, ok := authorizePayment(c, h.documentService)
+	if !ok {
+		return
+	}
+
+	document, err := h.documentService.GetPaymentReceipt(c.Request.Context(), paymentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to print receipt",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	sendDocument(c, document)
+}
+
+// UploadBusinessLogo godoc
+// @Summary Upload business logo
+// @Description Set the logo (JPEG or PNG, max 2 MB) printed on the business's report cards and receipts, replacing any previous one (Admin/Business only)
+// @Tags documents
+// @Accept multipart/form-data
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param file formData file true "Logo image"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/logo [put]
+func (h *DocumentHandler) UploadBusinessLogo(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.documentService)
+	if !ok {
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "File is required",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	if err := h.documentService.UploadLogo(c.Request.Context(), businessID, file, header); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Logo uploaded successfully",
+	})
+}
+
+// DeleteBusinessLogo godoc
+// @Summary Delete business logo
+// @Description Remove the logo printed on the business's report cards and receipts (Admin/Business only)
+// @Tags documents
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "No logo"
+// @Router /businesses/{businessId}/logo [delete]
+func (h *DocumentHandler) DeleteBusinessLogo(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.documentService)
+	if !ok {
+		return
+	}
+
+	if err := h.documentService.DeleteLogo(c.Request.Context(), businessID); err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Logo removed successfully",
+	})
+}
+
+// sendDocument writes a printed document as the response body, shown inline by browsers
+func sendDocument(c *gin.Context, document *models.PrintedDocument) {
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", document.FileName))
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, document.ContentType, document.Content)
+}
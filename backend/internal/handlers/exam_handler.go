@@ -0,0 +1,440 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExamHandler struct {
+	examService services.ExamService
+}
+
+func NewExamHandler(examService services.ExamService) *ExamHandler {
+	return &ExamHandler{
+		examService: examService,
+	}
+}
+
+// CreateExam godoc
+// @Summary Create an exam
+// @Description Create an exam for a business, optionally limited to a batch and tied to a subject (Admin/Business only)
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.CreateExamRequest true "Exam data"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.ExamResponse} "Success response with exam data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/exams [post]
+func (h *ExamHandler) CreateExam(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.examService)
+	if !ok {
+		return
+	}
+
+	var req models.CreateExamRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	exam, err := h.examService.CreateExam(c.Request.Context(), businessID, req, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Exam created successfully",
+		Data:    exam,
+	})
+}
+
+// GetExams godoc
+// @Summary Get exams
+// @Description Get the exams of a business with optional batch, subject and date range filters (Admin/Business only)
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param batch_id query int false "Batch ID"
+// @Param subject_id query int false "Subject ID"
+// @Param from query string false "From date (YYYY-MM-DD, inclusive)"
+// @Param to query string false "To date (YYYY-MM-DD, inclusive)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.ExamPage} "Success response with exams"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/exams [get]
+func (h *ExamHandler) GetExams(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.examService)
+	if !ok {
+		return
+	}
+
+	var filters repository.ExamFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filters.BusinessID = &businessID
+
+	exams, total, err := h.examService.GetExams(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.ExamPage{
+			Exams:      exams,
+			OffsetPage: dto.OffsetPage{Total: total, Page: filters.Page, Limit: filters.Limit},
+		},
+	})
+}
+
+// GetExam godoc
+// @Summary Get an exam
+// @Description Get a single exam of a business (Admin/Business only)
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param examId path int true "Exam ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.ExamResponse} "Success response with exam data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Exam not found"
+// @Router /businesses/{businessId}/exams/{examId} [get]
+func (h *ExamHandler) GetExam(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.examService)
+	if !ok {
+		return
+	}
+
+	examID, ok := parseExamID(c)
+	if !ok {
+		return
+	}
+
+	exam, err := h.examService.GetExam(c.Request.Context(), businessID, examID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    exam,
+	})
+}
+
+// UpdateExam godoc
+// @Summary Update an exam
+// @Description Update the details of an exam; max marks cannot drop below recorded marks (Admin/Business only)
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param examId path int true "Exam ID"
+// @Param request body models.UpdateExamRequest true "Exam update data"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.ExamResponse} "Success response with updated exam data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/exams/{examId} [put]
+func (h *ExamHandler) UpdateExam(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.examService)
+	if !ok {
+		return
+	}
+
+	examID, ok := parseExamID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateExamRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	exam, err := h.examService.UpdateExam(c.Request.Context(), businessID, examID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Exam updated successfully",
+		Data:    exam,
+	})
+}
+
+// DeleteExam godoc
+// @Summary Delete an exam
+// @Description Delete an exam together with its recorded results (Admin/Business only)
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param examId path int true "Exam ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/exams/{examId} [delete]
+func (h *ExamHandler) DeleteExam(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.examService)
+	if !ok {
+		return
+	}
+
+	examID, ok := parseExamID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.examService.DeleteExam(c.Request.Context(), businessID, examID); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Exam deleted successfully",
+	})
+}
+
+// RecordExamResults godoc
+// @Summary Record exam results
+// @Description Record or overwrite the marks of students in an exam; students must belong to the exam's business and batch (Admin/Business only)
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param examId path int true "Exam ID"
+// @Param request body models.RecordExamResultsRequest true "Results"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.ExamResultResponse} "Success response with recorded results"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/exams/{examId}/results [put]
+func (h *ExamHandler) RecordExamResults(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.examService)
+	if !ok {
+		return
+	}
+
+	examID, ok := parseExamID(c)
+	if !ok {
+		return
+	}
+
+	var req models.RecordExamResultsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	results, err := h.examService.RecordResults(c.Request.Context(), businessID, examID, req, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Results recorded successfully",
+		Data:    results,
+	})
+}
+
+// GetExamResults godoc
+// @Summary Get exam results
+// @Description Get the recorded results of an exam (Admin/Business only)
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param examId path int true "Exam ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.ExamResultResponse} "Success response with results"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Exam not found"
+// @Router /businesses/{businessId}/exams/{examId}/results [get]
+func (h *ExamHandler) GetExamResults(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.examService)
+	if !ok {
+		return
+	}
+
+	examID, ok := parseExamID(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.examService.GetResults(c.Request.Context(), businessID, examID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    results,
+	})
+}
+
+// DeleteExamResult godoc
+// @Summary Delete an exam result
+// @Description Remove the recorded result of a student from an exam (Admin/Business only)
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param examId path int true "Exam ID"
+// @Param studentId path int true "Student ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Result not found"
+// @Router /businesses/{businessId}/exams/{examId}/results/{studentId} [delete]
+func (h *ExamHandler) DeleteExamResult(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.examService)
+	if !ok {
+		return
+	}
+
+	examID, ok := parseExamID(c)
+	if !ok {
+		return
+	}
+
+	studentID, err := strconv.ParseUint(c.Param("studentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid student ID"})
+		return
+	}
+
+	if err := h.examService.DeleteResult(c.Request.Context(), businessID, examID, uint(studentID)); err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Result deleted successfully",
+	})
+}
+
+// GetExamStats godoc
+// @Summary Get exam statistics
+// @Description Get the average, highest and lowest marks and the pass percentage of an exam (Admin/Business only)
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param examId path int true "Exam ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.ExamStats} "Success response with exam statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Exam not found"
+// @Router /businesses/{businessId}/exams/{examId}/stats [get]
+func (h *ExamHandler) GetExamStats(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.examService)
+	if !ok {
+		return
+	}
+
+	examID, ok := parseExamID(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.examService.GetExamStats(c.Request.Context(), businessID, examID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+// GetStudentExamReport godoc
+// @Summary Get student exam report
+// @Description Get a student's exam results over time with overall and per-subject averages (Admin/Business only)
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param subject_id query int false "Subject ID"
+// @Param from query string false "From date (YYYY-MM-DD, inclusive)"
+// @Param to query string false "To date (YYYY-MM-DD, inclusive)"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.StudentExamReport} "Success response with exam report"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/{id}/exam-report [get]
+func (h *ExamHandler) GetStudentExamReport(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.examService)
+	if !ok {
+		return
+	}
+
+	var filters repository.ExamFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filters.BusinessID = nil
+
+	report, err := h.examService.GetStudentReport(c.Request.Context(), studentID, filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// parseExamID reads the :examId path parameter, writing a 400 response when it is invalid
+func parseExamID(c *gin.Context) (uint, bool) {
+	examID, err := strconv.ParseUint(c.Param("examId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid exam ID"})
+		return 0, false
+	}
+	return uint(examID), true
+}
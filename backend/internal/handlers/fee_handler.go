@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeeHandler struct {
+	feeService services.FeeService
+}
+
+func NewFeeHandler(feeService services.FeeService) *FeeHandler {
+	return &FeeHandler{
+		feeService: feeService,
+	}
+}
+
+// CreateFeePlan godoc
+// @Summary Create a fee plan
+// @Description Create a one-time or recurring fee for a student or for every student of a batch (Admin/Business only)
+// @Tags fees
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.CreateFeePlanRequest true "Fee plan data"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.FeePlanResponse} "Success response with fee plan"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/fees/plans [post]
+func (h *FeeHandler) CreateFeePlan(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.feeService)
+	if !ok {
+		return
+	}
+
+	var req models.CreateFeePlanRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	plan, err := h.feeService.CreatePlan(c.Request.Context(), businessID, req, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Fee plan created successfully",
+		Data:    plan,
+	})
+}
+
+// GetFeePlans godoc
+// @Summary Get fee plans
+// @Description Get the fee plans of a business with optional student, batch and status filters (Admin/Business only)
+// @Tags fees
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param student_id query int false "Student ID"
+// @Param batch_id query int false "Batch ID"
+// @Param status query int false "Status (1 = active, 0 = inactive)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.FeePlanPage} "Success response with fee plans"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/fees/plans [get]
+func (h *FeeHandler) GetFeePlans(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.feeService)
+	if !ok {
+		return
+	}
+
+	var filters repository.FeePlanFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filters.BusinessID = &businessID
+
+	plans, total, err := h.feeService.GetPlans(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.FeePlanPage{
+			Plans:      plans,
+			OffsetPage: dto.OffsetPage{Total: total, Page: filters.Page, Limit: filters.Limit},
+		},
+	})
+}
+
+// RecordFeePayment godoc
+// @Summary Record a fee payment
+// @Description Record a payment made by a student of the business, optionally against one of their fee plans (Admin/Business only)
+// @Tags fees
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.RecordFeePaymentRequest true "Payment data"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.FeePaymentResponse} "Success response with payment"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/fees/payments [post]
+func (h *FeeHandler) RecordFeePayment(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.feeService)
+	if !ok {
+		return
+	}
+
+	var req models.RecordFeePaymentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	payment, err := h.feeService.RecordPayment(c.Request.Context(), businessID, req, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Payment recorded successfully",
+		Data:    payment,
+	})
+}
+
+// GetFeePayments godoc
+// @Summary Get fee payments
+// @Description Get the payments received by a business with optional student, plan, mode and date range filters (Admin/Business only)
+// @Tags fees
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param student_id query int false "Student ID"
+// @Param fee_plan_id query int false "Fee plan ID"
+// @Param mode query string false "Payment mode (cash, card, upi, bank_transfer, cheque, other)"
+// @Param from query string false "From date (YYYY-MM-DD, inclusive)"
+// @Param to query string false "To date (YYYY-MM-DD, inclusive)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.FeePaymentPage} "Success response with payments"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/fees/payments [get]
+func (h *FeeHandler) GetFeePayments(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.feeService)
+	if !ok {
+		return
+	}
+
+	var filters repository.FeePaymentFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filters.BusinessID = &businessID
+
+	h.respondWithPayments(c, filters)
+}
+
+// GetStudentFeePayments godoc
+// @Summary Get student fee payments
+// @Description Get the payments made by a student with optional plan, mode and date range filters (Admin/Business only)
+// @Tags fees
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param fee_plan_id query int false "Fee plan ID"
+// @Param mode query string false "Payment mode (cash, card, upi, bank_transfer, cheque, other)"
+// @Param from query string false "From date (YYYY-MM-DD, inclusive)"
+// @Param to query string false "To date (YYYY-MM-DD, inclusive)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.FeePaymentPage} "Success response with payments"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/{id}/fees/payments [get]
+func (h *FeeHandler) GetStudentFeePayments(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.feeService)
+	if !ok {
+		return
+	}
+
+	var filters repository.FeePaymentFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filters.StudentID = &studentID
+	filters.BusinessID = nil
+
+	h.respondWithPayments(c, filters)
+}
+
+// GetStudentFeeDues godoc
+// @Summary Get student fee dues
+// @Description Get what a student owes on each of their fee plans and their overall balance as of a date (Admin/Business only)
+// @Tags fees
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param as_of query string false "Date (YYYY-MM-DD), defaults to today"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.StudentFeeDues} "Success response with dues"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/{id}/fees/dues [get]
+func (h *FeeHandler) GetStudentFeeDues(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.feeService)
+	if !ok {
+		return
+	}
+
+	dues, err := h.feeService.GetStudentDues(c.Request.Context(), studentID, c.Query("as_of"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    dues,
+	})
+}
+
+// GetBusinessFeeDues godoc
+// @Summary Get outstanding fee dues
+// @Description Get the active students of a business who owe fees as of a date, largest balance first (Admin/Business only)
+// @Tags fees
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param as_of query string false "Date (YYYY-MM-DD), defaults to today"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.FeeDuesPage} "Success response with dues"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/fees/dues [get]
+func (h *FeeHandler) GetBusinessFeeDues(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.feeService)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	dues, total, err := h.feeService.GetBusinessDues(c.Request.Context(), businessID, c.Query("as_of"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.FeeDuesPage{
+			Dues:       dues,
+			OffsetPage: dto.OffsetPage{Total: total, Page: page, Limit: limit},
+		},
+	})
+}
+
+// GetFeeSummary godoc
+// @Summary Get fee collection summary
+// @Description Get the fees collected and falling due in a date range, and the outstanding balance at its end (Admin/Business only)
+// @Tags fees
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param from query string false "From date (YYYY-MM-DD, inclusive), defaults to the start of the month of the to date"
+// @Param to query string false "To date (YYYY-MM-DD, inclusive), defaults to today"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.FeeSummary} "Success response with fee summary"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/fees/summary [get]
+func (h *FeeHandler) GetFeeSummary(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.feeService)
+	if !ok {
+		return
+	}
+
+	summary, err := h.feeService.GetBusinessSummary(c.Request.Context(), businessID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    summary,
+	})
+}
+
+func (h *FeeHandler) respondWithPayments(c *gin.Context, filters repository.FeePaymentFilters) {
+	payments, total, err := h.feeService.GetPayments(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.FeePaymentPage{
+			Payments:   payments,
+			OffsetPage: dto.OffsetPage{Total: total, Page: filters.Page, Limit: filters.Limit},
+		},
+	})
+}
+
+// SendFeeReminders godoc
+// @Summary Text fee due reminders
+// @Description Queue an SMS to the guardian of every active student with an outstanding balance as of a date. Each student is reminded at most once a day. The business must have SMS notifications enabled (Admin/Business only)
+// @Tags fees
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param as_of query string false "Date (YYYY-MM-DD), defaults to today"
+// @Security BearerAuth
+// @Success 202 {object} dto.Response{data=models.FeeReminderResult} "Reminders queued"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 409 {object} dto.ErrorResponse "SMS notifications are disabled"
+// @Router /businesses/{businessId}/fees/reminders [post]
+func (h *FeeHandler) SendFeeReminders(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.feeService)
+	if !ok {
+		return
+	}
+
+	result, err := h.feeService.SendDueReminders(c.Request.Context(), businessID, c.Query("as_of"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrSMSDisabled) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.Response{
+		Success: true,
+		Message: "Fee reminders queued",
+		Data:    result,
+	})
+}
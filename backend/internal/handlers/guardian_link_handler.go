@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GuardianLinkHandler struct {
+	guardianLinkService services.GuardianLinkService
+}
+
+func NewGuardianLinkHandler(guardianLinkService services.GuardianLinkService) *GuardianLinkHandler {
+	return &GuardianLinkHandler{
+		guardianLinkService: guardianLinkService,
+	}
+}
+
+// CreateGuardianLink godoc
+// @Summary Create a guardian link
+// @Description Generate a signed, expiring token that lets a student's guardians view the student's progress without an account. The token is only returned once. (Admin/Business only)
+// @Tags guardian
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param request body models.CreateGuardianLinkRequest false "Link options"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.GuardianLinkResponse} "Success response with the link and its token"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/{id}/guardian-link [post]
+func (h *GuardianLinkHandler) CreateGuardianLink(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.guardianLinkService)
+	if !ok {
+		return
+	}
+
+	var req models.CreateGuardianLinkRequest
+	if c.Request.ContentLength != 0 {
+		if !bindJSON(c, &req) {
+			return
+		}
+	}
+
+	link, err := h.guardianLinkService.CreateLink(c.Request.Context(), studentID, req, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Guardian link created successfully",
+		Data:    link,
+	})
+}
+
+// GetGuardianLinks godoc
+// @Summary Get guardian links
+// @Description Get the guardian links of a student, including expired and revoked ones (Admin/Business only)
+// @Tags guardian
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.GuardianLinkResponse} "Success response with guardian links"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/{id}/guardian-links [get]
+func (h *GuardianLinkHandler) GetGuardianLinks(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.guardianLinkService)
+	if !ok {
+		return
+	}
+
+	links, err := h.guardianLinkService.GetLinks(c.Request.Context(), studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    links,
+	})
+}
+
+// RevokeGuardianLink godoc
+// @Summary Revoke a guardian link
+// @Description Revoke a guardian link so its token stops working immediately (Admin/Business only)
+// @Tags guardian
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param linkId path int true "Guardian link ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Guardian link not found"
+// @Router /students/{id}/guardian-links/{linkId} [delete]
+func (h *GuardianLinkHandler) RevokeGuardianLink(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.guardianLinkService)
+	if !ok {
+		return
+	}
+
+	linkID, err := strconv.ParseUint(c.Param("linkId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid guardian link ID"})
+		return
+	}
+
+	if err := h.guardianLinkService.RevokeLink(c.Request.Context(), studentID, uint(linkID)); err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Guardian link revoked successfully",
+	})
+}
+
+// GetGuardianView godoc
+// @Summary View a student's progress as a guardian
+// @Description Public, read-only view of the student a guardian link was created for: name, photo, recent attendance, latest exam results and fee balance. photo_url is served under the same token.
+// @Tags guardian
+// @Accept json
+// @Produce json
+// @Param token path string true "Guardian token"
+// @Success 200 {object} dto.Response{data=models.GuardianView} "Success response with the student's progress"
+// @Failure 404 {object} dto.ErrorResponse "Invalid, expired or revoked link"
+// @Router /guardian/{token} [get]
+func (h *GuardianLinkHandler) GetGuardianView(c *gin.Context) {
+	view, err := h.guardianLinkService.GetGuardianView(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrGuardianLinkInvalid) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to load student progress"})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    view,
+	})
+}
+
+// GetGuardianPhoto godoc
+// @Summary Get the photo of a guardian link's student
+// @Description Public; the photo the business keeps for the student a guardian link was created for, authorised by the link's token
+// @Tags guardian
+// @Produce image/jpeg,image/png,image/webp
+// @Param token path string true "Guardian token"
+// @Success 200 {file} file "Photo"
+// @Failure 404 {object} dto.ErrorResponse "Invalid, expired or revoked link, or no photo"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /guardian/{token}/photo [get]
+func (h *GuardianLinkHandler) GetGuardianPhoto(c *gin.Context) {
+	photo, err := h.guardianLinkService.GetGuardianPhoto(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrGuardianLinkInvalid) || err.Error() == "student has no photo" {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get photo"})
+		return
+	}
+
+	// The link may be revoked at any time
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, photo.ContentType, photo.Content)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"backend/pkg/database"
+	"backend/pkg/version"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds each dependency check so a hung database fails the probe
+// instead of hanging it
+const readinessTimeout = 2 * time.Second
+
+// DependencyCheck reports whether a dependency the server needs is usable
+type DependencyCheck func(ctx context.Context) error
+
+type HealthHandler struct {
+	checks map[string]DependencyCheck
+}
+
+func NewHealthHandler(checks map[string]DependencyCheck) *HealthHandler {
+	return &HealthHandler{
+		checks: checks,
+	}
+}
+
+// DatabaseCheck pings the GORM database connection
+func DatabaseCheck(ctx context.Context) error {
+	if database.DB == nil {
+		return fmt.Errorf("database is not connected")
+	}
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Liveness always returns 200 while the process is running. The probes are served at the
+// server root rather than under the versioned API, so they are left out of its Swagger spec.
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
+
+// Readiness checks every dependency with a short timeout and reports each one's status,
+// along with database connection pool usage. Returns 503 if any is down.
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	status := http.StatusOK
+	dependencies := gin.H{}
+
+	for name, check := range h.checks {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		err := check(ctx)
+		cancel()
+
+		if err != nil {
+			status = http.StatusServiceUnavailable
+			dependencies[name] = gin.H{"status": "down", "error": err.Error()}
+			continue
+		}
+		dependencies[name] = gin.H{"status": "up"}
+	}
+
+	overall := "ok"
+	if status != http.StatusOK {
+		overall = "degraded"
+	}
+
+	response := gin.H{
+		"status":       overall,
+		"dependencies": dependencies,
+	}
+	if stats, err := database.PoolStats(); err == nil {
+		response["database_pool"] = stats
+	}
+
+	c.JSON(status, response)
+}
+
+// Version reports the commit and build date the server was compiled from
+func (h *HealthHandler) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"commit":     version.Commit,
+		"build_date": version.BuildDate,
+	})
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/pkg/database"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// readiness serves one probe with checks and returns the status and decoded body
+func readiness(t *testing.T, checks map[string]DependencyCheck) (int, map[string]interface{}) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/readyz", NewHealthHandler(checks).Readiness)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return w.Code, body
+}
+
+// useDatabase points the global connection at db for the rest of the test
+func useDatabase(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() { database.DB = previous })
+}
+
+// A database that doesn't answer the ping makes the server unready, naming the
+// database as the dependency that is down
+func TestReadinessFailsWhenDatabasePingFails(t *testing.T) {
+	// Nothing listens on port 1, so the connection is refused without waiting
+	db, err := gorm.Open(postgres.Open("postgres://backend@127.0.0.1:1/backend?sslmode=disable&connect_timeout=1"),
+		&gorm.Config{DisableAutomaticPing: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		db   *gorm.DB
+	}{
+		{"ping refused", db},
+		{"not connected", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useDatabase(t, tt.db)
+			status, body := readiness(t, map[string]DependencyCheck{
+				"database": DatabaseCheck,
+				"cache":    func(ctx context.Context) error { return nil },
+			})
+
+			if status != http.StatusServiceUnavailable {
+				t.Fatalf("status = %d, want %d", status, http.StatusServiceUnavailable)
+			}
+			if body["status"] != "degraded" {
+				t.Errorf("status = %v, want %q", body["status"], "degraded")
+			}
+			dependencies, _ := body["dependencies"].(map[string]interface{})
+			db, _ := dependencies["database"].(map[string]interface{})
+			if message, _ := db["error"].(string); db["status"] != "down" || message == "" {
+				t.Errorf("database = %v, want it down with the error", db)
+			}
+			cache, _ := dependencies["cache"].(map[string]interface{})
+			if cache["status"] != "up" {
+				t.Errorf("cache = %v, want it up", cache)
+			}
+		})
+	}
+}
+
+func TestReadinessSucceedsWhenEveryCheckPasses(t *testing.T) {
+	status, body := readiness(t, map[string]DependencyCheck{
+		"database": func(ctx context.Context) error { return nil },
+	})
+	if status != http.StatusOK || body["status"] != "ok" {
+		t.Errorf("readiness = %d %v, want 200 ok", status, body["status"])
+	}
+
+	status, _ = readiness(t, map[string]DependencyCheck{
+		"database": func(ctx context.Context) error { return nil },
+		"storage":  func(ctx context.Context) error { return errors.New("bucket missing") },
+	})
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("status = %d with one check failing, want %d", status, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MaintenanceHandler struct {
+	maintenanceService services.MaintenanceService
+}
+
+func NewMaintenanceHandler(maintenanceService services.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// GetMaintenance godoc
+// @Summary Get maintenance mode
+// @Description Get whether maintenance mode is on, with the message and retry time returned to clients (Admin only)
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.MaintenanceState} "Success response with the maintenance state"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/maintenance [get]
+func (h *MaintenanceHandler) GetMaintenance(c *gin.Context) {
+	state, err := h.maintenanceService.GetState(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    state,
+	})
+}
+
+// UpdateMaintenance godoc
+// @Summary Turn maintenance mode on or off
+// @Description While maintenance mode is on every API request except health checks and this endpoint gets a 503 with Retry-After, unless it comes from an admin listed in MAINTENANCE_ALLOWED_ADMIN_IDS (Admin only)
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateMaintenanceRequest true "Maintenance state"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.MaintenanceState} "Success response with the maintenance state"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/maintenance [put]
+func (h *MaintenanceHandler) UpdateMaintenance(c *gin.Context) {
+	var req models.UpdateMaintenanceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	state, err := h.maintenanceService.UpdateState(c.Request.Context(), req, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	message := "Maintenance mode disabled"
+	if state.Enabled {
+		message = "Maintenance mode enabled"
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: message,
+		Data:    state,
+	})
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationHandler(notificationService services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications godoc
+// @Summary Get my notifications
+// @Description Get the current user's notifications, newest first, each flagged as read or unread
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param type query string false "Notification type" Enums(package_expiring, package_changed, status_changed)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.NotificationPage} "Success response with notifications"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Router /notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	var filters repository.NotificationFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+	if filters.Limit < 1 || filters.Limit > 100 {
+		filters.Limit = 10
+	}
+
+	notifications, total, err := h.notificationService.GetNotifications(c.Request.Context(), c.GetUint("user_id"), filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to get notifications",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.NotificationPage{
+			Notifications: notifications,
+			OffsetPage:    dto.OffsetPage{Total: total, Page: filters.Page, Limit: filters.Limit},
+		},
+	})
+}
+
+// GetUnreadNotificationCount godoc
+// @Summary Get my unread notification count
+// @Description Get how many of the current user's notifications are unread, for a badge. Cheap enough to poll.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.UnreadCount} "Success response with unread count"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadNotificationCount(c *gin.Context) {
+	count, err := h.notificationService.GetUnreadCount(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to count notifications",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    dto.UnreadCount{Unread: count},
+	})
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark a notification as read
+// @Description Mark one of the current user's notifications as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 400 {object} dto.ErrorResponse "Invalid notification ID"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 404 {object} dto.ErrorResponse "Notification not found"
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	notificationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid notification ID"})
+		return
+	}
+
+	if err := h.notificationService.MarkRead(c.Request.Context(), c.GetUint("user_id"), uint(notificationID)); err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Notification marked as read",
+	})
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification of the current user as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.MarkedCount} "Success response with the number marked"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	count, err := h.notificationService.MarkAllRead(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to mark notifications as read",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "All notifications marked as read",
+		Data:    dto.MarkedCount{Marked: count},
+	})
+}
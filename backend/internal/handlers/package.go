@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"backend/internal/dto"
 	"backend/internal/models"
 	"backend/internal/repository"
 	"backend/internal/services"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -24,29 +26,25 @@ func NewPackageHandler(packageService services.PackageService) *PackageHandler {
 
 // CreatePackage godoc
 // @Summary Create a new package
-// @Description Create a new package with the provided information (Admin/Business only)
+// @Description Create a new package with the provided information (Admin only)
 // @Tags packages
 // @Accept json
 // @Produce json
 // @Param request body models.CreatePackageRequest true "Package data"
 // @Security BearerAuth
-// @Success 201 {object} map[string]interface{} "Success response with package data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 409 {object} map[string]string "Package name already exists"
-// @Router /api/packages [post]
+// @Success 201 {object} dto.Response{data=models.PackageResponse} "Success response with package data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 409 {object} dto.ErrorResponse "Package name already exists"
+// @Router /packages [post]
 func (h *PackageHandler) CreatePackage(c *gin.Context) {
 	var req models.CreatePackageRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	pkg, err := h.packageService.CreatePackage(req)
+	pkg, err := h.packageService.CreatePackage(c.Request.Context(), req)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "name already exists") {
@@ -55,49 +53,46 @@ func (h *PackageHandler) CreatePackage(c *gin.Context) {
 			strings.Contains(err.Error(), "must be greater than") {
 			status = http.StatusBadRequest
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Package created successfully",
-		"data":    pkg,
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Package created successfully",
+		Data:    pkg,
 	})
 }
 
 // GetPackages godoc
 // @Summary Get all packages
-// @Description Get all packages with pagination and filters
+// @Description Get all packages with pagination and filters (Admin only)
 // @Tags packages
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
-// @Param status query int false "Filter by status (0=inactive, 1=active)"
+// @Param status query string false "Filter by status (0 or inactive, 1 or active)"
 // @Param min_price query number false "Minimum price filter"
 // @Param max_price query number false "Maximum price filter"
 // @Param min_period query int false "Minimum validation period filter (days)"
 // @Param max_period query int false "Maximum validation period filter (days)"
 // @Param search query string false "Search in name or description"
-// @Param sort_by query string false "Sort by field (name, price, validation_period, created_on)"
-// @Param sort_order query string false "Sort order (asc, desc)" default(desc)
+// @Param sort_by query string false "Comma-separated sort columns (created_on, updated_on, name, price, validation_period, status), e.g. status,price" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with packages list"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Router /api/packages [get]
+// @Success 200 {object} dto.ListResponse{data=[]models.PackageResponse} "Success response with packages list"
+// @Failure 400 {object} dto.ErrorResponse "Unknown sort column or filter value; valid_options lists the accepted ones"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /packages [get]
 func (h *PackageHandler) GetPackages(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	// Handle status filter
-	var status *int
-	if statusStr := c.Query("status"); statusStr != "" {
-		if statusVal, err := strconv.Atoi(statusStr); err == nil {
-			if statusVal == 0 || statusVal == 1 {
-				status = &statusVal
-			}
-		}
+	status, ok := queryStatus(c, "status")
+	if !ok {
+		return
 	}
 
 	// Handle price filters
@@ -120,94 +115,107 @@ func (h *PackageHandler) GetPackages(c *gin.Context) {
 		SortBy:    c.Query("sort_by"),
 		SortOrder: c.Query("sort_order"),
 	}
+	if err := filters.Validate(); err != nil {
+		respondInvalidSelection(c, err)
+		return
+	}
 
-	packages, total, err := h.packageService.GetPackages(filters)
+	packages, pageInfo, err := h.packageService.GetPackages(c.Request.Context(), filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	// Calculate pagination info
-	totalPages := (int(total) + limit - 1) / limit
-	hasNext := page < totalPages
-	hasPrev := page > 1
-
-	c.JSON(http.StatusOK, gin.H{
-		"data": packages,
-		"pagination": gin.H{
-			"total":       total,
-			"page":        page,
-			"limit":       limit,
-			"total_pages": totalPages,
-			"has_next":    hasNext,
-			"has_prev":    hasPrev,
-		},
+	c.JSON(http.StatusOK, dto.ListResponse{
+		Success:    true,
+		Data:       packages,
+		Pagination: dto.NewPagination(pageInfo),
 	})
 }
 
 // GetPackage godoc
 // @Summary Get package by ID
-// @Description Get a specific package by ID
+// @Description Get a specific package by ID (Admin/Business only). Businesses only see active packages.
 // @Tags packages
 // @Accept json
 // @Produce json
 // @Param id path int true "Package ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with package data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Package not found"
-// @Router /api/packages/{id} [get]
+// @Success 200 {object} dto.Response{data=models.PackageResponse} "Success response with package data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Package not found"
+// @Router /packages/{id} [get]
 func (h *PackageHandler) GetPackage(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid package ID"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid package ID"})
 		return
 	}
 
-	pkg, err := h.packageService.GetPackageByID(uint(id))
+	pkg, err := h.packageService.GetPackageByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": pkg})
+	// Inactive packages are only visible to admins
+	if !pkg.Status.IsActive() && c.GetString("user_role") != string(models.RoleAdmin) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "package not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: pkg})
 }
 
 // UpdatePackage godoc
 // @Summary Update package
-// @Description Update package information (Admin/Business only)
+// @Description Update package information. Businesses already on the package keep the price and validation period they were assigned on; when the price changes, price_change in the response counts the active businesses still on another price. (Admin only)
 // @Tags packages
 // @Accept json
 // @Produce json
 // @Param id path int true "Package ID"
-// @Param request body map[string]interface{} true "Update data"
+// @Param request body models.UpdatePackageRequest true "Update data"
+// @Param If-Match header string false "Version the update is based on, when not sent as the version field"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with updated package data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 404 {object} map[string]string "Package not found"
-// @Failure 409 {object} map[string]string "Package name already exists"
-// @Router /api/packages/{id} [put]
+// @Success 200 {object} dto.Response{data=models.PackageResponse} "Success response with updated package data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Package not found"
+// @Failure 409 {object} dto.ErrorResponse "Package name already exists, or the package was changed since it was read; data then holds the current package"
+// @Failure 428 {object} dto.ErrorResponse "No record version sent"
+// @Router /packages/{id} [put]
 func (h *PackageHandler) UpdatePackage(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid package ID"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid package ID"})
 		return
 	}
 
 	var updates map[string]interface{}
-	if err := c.ShouldBindJSON(&updates); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &updates) {
+		return
+	}
+
+	// The body is kept as a map, so its version arrives as a JSON number
+	var bodyVersion *uint
+	if v, ok := updates["version"].(float64); ok {
+		u := uint(v)
+		bodyVersion = &u
+	}
+	version, ok := requestVersion(c, bodyVersion)
+	if !ok {
 		return
 	}
+	updates["version"] = version
 
-	pkg, err := h.packageService.UpdatePackage(uint(id), updates)
+	pkg, err := h.packageService.UpdatePackage(c.Request.Context(), uint(id), updates)
 	if err != nil {
+		if writeVersionConflict(c, err) {
+			return
+		}
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "not found") {
 			status = http.StatusNotFound
@@ -218,203 +226,233 @@ func (h *PackageHandler) UpdatePackage(c *gin.Context) {
 			strings.Contains(err.Error(), "must be greater than") {
 			status = http.StatusBadRequest
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Package updated successfully",
-		"data":    pkg,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Package updated successfully",
+		Data:    pkg,
 	})
 }
 
 // DeletePackage godoc
 // @Summary Delete package
-// @Description Delete a package (Admin/Business only)
+// @Description Delete a package (Admin only)
 // @Tags packages
 // @Accept json
 // @Produce json
 // @Param id path int true "Package ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 404 {object} map[string]string "Package not found"
-// @Router /api/packages/{id} [delete]
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Package not found"
+// @Router /packages/{id} [delete]
 func (h *PackageHandler) DeletePackage(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid package ID"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid package ID"})
 		return
 	}
 
-	if err := h.packageService.DeletePackage(uint(id)); err != nil {
+	if err := h.packageService.DeletePackage(c.Request.Context(), uint(id)); err != nil {
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "not found") {
 			status = http.StatusNotFound
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Package deleted successfully"})
+	c.JSON(http.StatusOK, dto.MessageResponse{Success: true, Message: "Package deleted successfully"})
 }
 
 // GetActivePackages godoc
 // @Summary Get active packages
-// @Description Get all active packages
+// @Description Get all active packages (Admin/Business only)
 // @Tags packages
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with active packages list"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Router /api/packages/active [get]
+// @Success 200 {object} dto.PackageListResponse "Success response with active packages list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /packages/active [get]
 func (h *PackageHandler) GetActivePackages(c *gin.Context) {
-	packages, err := h.packageService.GetActivePackages()
+	packages, err := h.packageService.GetActivePackages(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  packages,
-		"count": len(packages),
+	c.JSON(http.StatusOK, dto.PackageListResponse{
+		Success: true,
+		Data:    packages,
+		Count:   len(packages),
+	})
+}
+
+// GetPublicPackages godoc
+// @Summary Get public packages
+// @Description Get the active packages for pricing pages (no authentication required). Responses carry a weak ETag and Cache-Control; send If-None-Match to get 304 when nothing changed.
+// @Tags packages
+// @Accept json
+// @Produce json
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Success 200 {object} dto.PackageListResponse "Success response with active packages list"
+// @Success 304 "Not modified"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /packages/public [get]
+func (h *PackageHandler) GetPublicPackages(c *gin.Context) {
+	packages, err := h.packageService.GetActivePackages(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// The list changes when a package is added, updated or leaves the active set
+	parts := make([]interface{}, 0, 2*len(packages))
+	for _, pkg := range packages {
+		parts = append(parts, pkg.ID, pkg.UpdatedOn)
+	}
+	if notModified(c, weakETag(parts...)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PackageListResponse{
+		Success: true,
+		Data:    packages,
+		Count:   len(packages),
 	})
 }
 
 // GetInactivePackages godoc
 // @Summary Get inactive packages
-// @Description Get all inactive packages (Admin/Business only)
+// @Description Get all inactive packages (Admin only)
 // @Tags packages
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with inactive packages list"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/packages/inactive [get]
+// @Success 200 {object} dto.PackageListResponse "Success response with inactive packages list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /packages/inactive [get]
 func (h *PackageHandler) GetInactivePackages(c *gin.Context) {
-	packages, err := h.packageService.GetInactivePackages()
+	packages, err := h.packageService.GetInactivePackages(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  packages,
-		"count": len(packages),
+	c.JSON(http.StatusOK, dto.PackageListResponse{
+		Success: true,
+		Data:    packages,
+		Count:   len(packages),
 	})
 }
 
 // ChangePackageStatus godoc
 // @Summary Change package status
-// @Description Change the status of a package (Admin/Business only)
+// @Description Change the status of a package (Admin only)
 // @Tags packages
 // @Accept json
 // @Produce json
 // @Param id path int true "Package ID"
 // @Param request body map[string]int true "Status data"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 404 {object} map[string]string "Package not found"
-// @Router /api/packages/{id}/status [patch]
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Package not found"
+// @Router /packages/{id}/status [patch]
 func (h *PackageHandler) ChangePackageStatus(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid package ID"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid package ID"})
 		return
 	}
 
 	var req struct {
-		Status int `json:"status" binding:"required"`
+		Status *models.Status `json:"status" binding:"required"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	if err := h.packageService.ChangePackageStatus(uint(id), req.Status); err != nil {
+	if err := h.packageService.ChangePackageStatus(c.Request.Context(), uint(id), *req.Status); err != nil {
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "not found") {
 			status = http.StatusNotFound
 		} else if strings.Contains(err.Error(), "invalid") {
 			status = http.StatusBadRequest
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	statusText := "inactive"
-	if req.Status == 1 {
-		statusText = "active"
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Package status changed to %s successfully", statusText)})
+	c.JSON(http.StatusOK, dto.MessageResponse{Success: true, Message: fmt.Sprintf("Package status changed to %s successfully", *req.Status)})
 }
 
 // GetPackageStats godoc
 // @Summary Get package statistics
-// @Description Get package statistics (Admin/Business only)
+// @Description Get package statistics (Admin only)
 // @Tags packages
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with statistics"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/packages/stats [get]
+// @Success 200 {object} dto.Response{data=object} "Success response with statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /packages/stats [get]
 func (h *PackageHandler) GetPackageStats(c *gin.Context) {
-	stats, err := h.packageService.GetPackageStats()
+	stats, err := h.packageService.GetPackageStats(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": stats})
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: stats})
 }
 
 // GetPriceStatistics godoc
 // @Summary Get price statistics
-// @Description Get package price statistics (Admin/Business only)
+// @Description Get package price statistics (Admin only)
 // @Tags packages
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with price statistics"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/packages/stats/prices [get]
+// @Success 200 {object} dto.Response{data=map[string]float64} "Success response with price statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /packages/stats/prices [get]
 func (h *PackageHandler) GetPriceStatistics(c *gin.Context) {
-	stats, err := h.packageService.GetPriceStatistics()
+	stats, err := h.packageService.GetPriceStatistics(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": stats})
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: stats})
 }
 
 // GetPackagesByPriceRange godoc
 // @Summary Get packages by price range
-// @Description Get packages within a specific price range
+// @Description Get packages within a specific price range (Admin only)
 // @Tags packages
 // @Accept json
 // @Produce json
 // @Param min_price query number false "Minimum price"
 // @Param max_price query number false "Maximum price"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with packages list"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Router /api/packages/price-range [get]
+// @Success 200 {object} dto.PackagePriceRangeResponse "Success response with packages list"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /packages/price-range [get]
 func (h *PackageHandler) GetPackagesByPriceRange(c *gin.Context) {
 	minPriceStr := c.Query("min_price")
 	maxPriceStr := c.Query("max_price")
@@ -425,7 +463,7 @@ func (h *PackageHandler) GetPackagesByPriceRange(c *gin.Context) {
 	if minPriceStr != "" {
 		minPrice, err = strconv.ParseFloat(minPriceStr, 64)
 		if err != nil || minPrice < 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid minimum price"})
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid minimum price"})
 			return
 		}
 	}
@@ -433,105 +471,97 @@ func (h *PackageHandler) GetPackagesByPriceRange(c *gin.Context) {
 	if maxPriceStr != "" {
 		maxPrice, err = strconv.ParseFloat(maxPriceStr, 64)
 		if err != nil || maxPrice < 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid maximum price"})
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid maximum price"})
 			return
 		}
 	}
 
 	if minPrice > 0 && maxPrice > 0 && minPrice > maxPrice {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Minimum price cannot be greater than maximum price"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Minimum price cannot be greater than maximum price"})
 		return
 	}
 
-	packages, err := h.packageService.GetPackagesByPriceRange(minPrice, maxPrice)
+	packages, err := h.packageService.GetPackagesByPriceRange(c.Request.Context(), minPrice, maxPrice)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":      packages,
-		"count":     len(packages),
-		"min_price": minPrice,
-		"max_price": maxPrice,
+	c.JSON(http.StatusOK, dto.PackagePriceRangeResponse{
+		Success:  true,
+		Data:     packages,
+		Count:    len(packages),
+		MinPrice: minPrice,
+		MaxPrice: maxPrice,
 	})
 }
 
 // BulkUpdatePackageStatus godoc
 // @Summary Bulk update package status
-// @Description Update status for multiple packages (Admin/Business only)
+// @Description Update status for multiple packages. IDs that don't exist are skipped and listed, unless atomic is set, in which case nothing changes unless every ID can be updated. (Admin only)
 // @Tags packages
 // @Accept json
 // @Produce json
-// @Param request body map[string]interface{} true "Bulk update data with package_ids and status"
+// @Param request body map[string]interface{} true "Bulk update data with package_ids, status and optional atomic"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/packages/bulk/status [patch]
+// @Success 200 {object} dto.Response{data=models.BulkUpdateResult} "Every ID was updated"
+// @Success 207 {object} dto.Response{data=models.BulkUpdateResult} "Some IDs were skipped; skipped says why"
+// @Failure 400 {object} dto.ErrorResponse{data=models.BulkUpdateResult} "Bad request, or an atomic request that would have skipped IDs"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /packages/bulk/status [patch]
 func (h *PackageHandler) BulkUpdatePackageStatus(c *gin.Context) {
 	var req struct {
-		PackageIDs []uint `json:"package_ids" binding:"required"`
-		Status     int    `json:"status" binding:"required"`
+		PackageIDs []uint         `json:"package_ids" binding:"required"`
+		Status     *models.Status `json:"status" binding:"required"`
+		Atomic     bool           `json:"atomic"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	if len(req.PackageIDs) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Package IDs are required"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Package IDs are required"})
 		return
 	}
 
-	if req.Status < 0 || req.Status > 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status value. Must be 0 (inactive) or 1 (active)"})
+	if *req.Status < 0 || *req.Status > 1 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid status value. Must be 0 (inactive) or 1 (active)"})
 		return
 	}
 
-	if err := h.packageService.BulkUpdatePackageStatus(req.PackageIDs, req.Status); err != nil {
+	result, err := h.packageService.BulkUpdatePackageStatus(c.Request.Context(), req.PackageIDs, *req.Status, req.Atomic)
+	if err != nil && !errors.Is(err, services.ErrBulkIncomplete) {
 		status := http.StatusInternalServerError
-		if strings.Contains(err.Error(), "not found") {
-			status = http.StatusNotFound
-		} else if strings.Contains(err.Error(), "invalid") {
+		if strings.Contains(err.Error(), "invalid") {
 			status = http.StatusBadRequest
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	statusText := "inactive"
-	if req.Status == 1 {
-		statusText = "active"
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": fmt.Sprintf("%d packages status changed to %s successfully", len(req.PackageIDs), statusText),
-	})
+	writeBulkResult(c, result, err, fmt.Sprintf("%d packages status changed to %s", len(result.Updated), *req.Status))
 }
 
 // SearchPackages godoc
 // @Summary Search packages
-// @Description Search packages by name or description
+// @Description Search packages by name or description (Admin only)
 // @Tags packages
 // @Accept json
 // @Produce json
 // @Param q query string true "Search term"
 // @Param limit query int false "Maximum number of results" default(10)
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with search results"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Router /api/packages/search [get]
+// @Success 200 {object} dto.PackageSearchResponse "Success response with search results"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /packages/search [get]
 func (h *PackageHandler) SearchPackages(c *gin.Context) {
 	searchTerm := c.Query("q")
 	if searchTerm == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search term is required"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Search term is required"})
 		return
 	}
 
@@ -541,16 +571,17 @@ func (h *PackageHandler) SearchPackages(c *gin.Context) {
 		limit = 10
 	}
 
-	packages, err := h.packageService.SearchPackages(searchTerm, limit)
+	packages, err := h.packageService.SearchPackages(c.Request.Context(), searchTerm, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":        packages,
-		"count":       len(packages),
-		"search_term": searchTerm,
-		"limit":       limit,
+	c.JSON(http.StatusOK, dto.PackageSearchResponse{
+		Success:    true,
+		Data:       packages,
+		Count:      len(packages),
+		SearchTerm: searchTerm,
+		Limit:      limit,
 	})
 }
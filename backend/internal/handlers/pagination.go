@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondInvalidCursor writes the 400 response when err comes from a bad cursor query
+// parameter, reporting whether it did
+func respondInvalidCursor(c *gin.Context, err error) bool {
+	if !errors.Is(err, repository.ErrInvalidCursor) {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+		Error:   "Invalid cursor",
+		Details: err.Error(),
+	})
+	return true
+}
+
+// statusFilter is the status filter of the active and inactive list shortcuts
+func statusFilter(active bool) *models.Status {
+	status := models.StatusInactive
+	if active {
+		status = models.StatusActive
+	}
+	return &status
+}
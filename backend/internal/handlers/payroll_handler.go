@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PayrollHandler struct {
+	payrollService services.PayrollService
+}
+
+func NewPayrollHandler(payrollService services.PayrollService) *PayrollHandler {
+	return &PayrollHandler{
+		payrollService: payrollService,
+	}
+}
+
+// GetPayroll godoc
+// @Summary Get the monthly payroll
+// @Description Get what the business owes each teacher for a month, with totals. Active teachers are paid their salary prorated over the calendar days from the day they joined or transferred in, less the daily rate (the salary divided by the working days setting) for every day marked absent (leave is paid), plus the month's adjustments. Teachers who are no longer active are listed only for their adjustments. Until the month is finalized the figures follow the current salaries, attendance and adjustments; after that they are returned as finalized. (Admin/Business only)
+// @Tags payroll
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param month query string false "Month (YYYY-MM), defaults to the current month"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.PayrollSummary} "Success response with payroll"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/payroll [get]
+func (h *PayrollHandler) GetPayroll(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.payrollService)
+	if !ok {
+		return
+	}
+
+	summary, err := h.payrollService.GetPayroll(c.Request.Context(), businessID, c.Query("month"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    summary,
+	})
+}
+
+// FinalizePayroll godoc
+// @Summary Finalize the monthly payroll
+// @Description Lock a month's payroll as it stands, typically once it has been paid out. Later changes to salaries or attendance no longer change it, and its adjustments can no longer be added or removed. (Admin/Business only)
+// @Tags payroll
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.FinalizePayrollRequest true "Month to finalize"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.PayrollSummary} "Success response with the finalized payroll"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 409 {object} dto.ErrorResponse "Month is already finalized"
+// @Router /businesses/{businessId}/payroll/finalize [post]
+func (h *PayrollHandler) FinalizePayroll(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.payrollService)
+	if !ok {
+		return
+	}
+
+	var req models.FinalizePayrollRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	summary, err := h.payrollService.FinalizePayroll(c.Request.Context(), businessID, req.Month, c.GetUint("user_id"))
+	if err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Payroll finalized successfully",
+		Data:    summary,
+	})
+}
+
+// GetPayrollAdjustments godoc
+// @Summary Get payroll adjustments
+// @Description Get the one-off additions to and deductions from teachers' pay for a month, oldest first (Admin/Business only)
+// @Tags payroll
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param month query string false "Month (YYYY-MM), defaults to the current month"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.PayrollAdjustmentResponse} "Success response with adjustments"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/payroll/adjustments [get]
+func (h *PayrollHandler) GetPayrollAdjustments(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.payrollService)
+	if !ok {
+		return
+	}
+
+	adjustments, err := h.payrollService.GetAdjustments(c.Request.Context(), businessID, c.Query("month"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    adjustments,
+	})
+}
+
+// CreatePayrollAdjustment godoc
+// @Summary Add a payroll adjustment
+// @Description Add a one-off amount to a teacher's pay for a month, such as a bonus, or take one off with a negative amount, such as an advance being recovered. The month must not be finalized. (Admin/Business only)
+// @Tags payroll
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.CreatePayrollAdjustmentRequest true "Adjustment data"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.PayrollAdjustmentResponse} "Success response with adjustment"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 409 {object} dto.ErrorResponse "Month is finalized"
+// @Router /businesses/{businessId}/payroll/adjustments [post]
+func (h *PayrollHandler) CreatePayrollAdjustment(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.payrollService)
+	if !ok {
+		return
+	}
+
+	var req models.CreatePayrollAdjustmentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	adjustment, err := h.payrollService.CreateAdjustment(c.Request.Context(), businessID, req, c.GetUint("user_id"))
+	if err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Adjustment created successfully",
+		Data:    adjustment,
+	})
+}
+
+// DeletePayrollAdjustment godoc
+// @Summary Delete a payroll adjustment
+// @Description Remove an adjustment from a month that is not finalized (Admin/Business only)
+// @Tags payroll
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param adjustmentId path int true "Adjustment ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Adjustment not found"
+// @Failure 409 {object} dto.ErrorResponse "Month is finalized"
+// @Router /businesses/{businessId}/payroll/adjustments/{adjustmentId} [delete]
+func (h *PayrollHandler) DeletePayrollAdjustment(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.payrollService)
+	if !ok {
+		return
+	}
+
+	adjustmentID, err := strconv.ParseUint(c.Param("adjustmentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid adjustment ID"})
+		return
+	}
+
+	if err := h.payrollService.DeleteAdjustment(c.Request.Context(), businessID, uint(adjustmentID)); err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Adjustment deleted successfully",
+	})
+}
+
+// GetPayrollSettings godoc
+// @Summary Get payroll settings
+// @Description Get how the business's payroll is worked out: the number of working days a monthly salary is divided by for the deduction of a day of absence, 0 for the days of each month (Admin/Business only)
+// @Tags payroll
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.PayrollSettings} "Success response with payroll settings"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business not found"
+// @Router /businesses/{businessId}/payroll/settings [get]
+func (h *PayrollHandler) GetPayrollSettings(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.payrollService)
+	if !ok {
+		return
+	}
+
+	settings, err := h.payrollService.GetSettings(c.Request.Context(), businessID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    settings,
+	})
+}
+
+// UpdatePayrollSettings godoc
+// @Summary Update payroll settings
+// @Description Set the number of working days a monthly salary is divided by for the deduction of a day of absence, 0 for the days of each month. Finalized months keep the rate they were finalized with. (Admin/Business only)
+// @Tags payroll
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.UpdatePayrollSettingsRequest true "Settings"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.PayrollSettings} "Success response with payroll settings"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/payroll/settings [put]
+func (h *PayrollHandler) UpdatePayrollSettings(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.payrollService)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePayrollSettingsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	settings, err := h.payrollService.UpdateSettings(c.Request.Context(), businessID, *req.WorkingDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Settings updated successfully",
+		Data:    settings,
+	})
+}
+
+// respondPayrollError writes 409 for changes to a finalized month, 404 for a missing
+// adjustment and 400 otherwise
+func respondPayrollError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, services.ErrPayrollFinalized):
+		status = http.StatusConflict
+	case strings.Contains(err.Error(), "adjustment not found"):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, dto.ErrorResponse{Error: err.Error()})
+}
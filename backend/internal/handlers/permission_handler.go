@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PermissionHandler struct {
+	permissionService services.PermissionService
+}
+
+func NewPermissionHandler(permissionService services.PermissionService) *PermissionHandler {
+	return &PermissionHandler{
+		permissionService: permissionService,
+	}
+}
+
+// GetMyPermissions godoc
+// @Summary Get my permissions
+// @Description Get the caller's role, profile IDs and a can_<permission> flag for every permission. The flags come from the same permission map the route guards enforce.
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.PermissionsResponse} "Success response with the caller's permissions"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Unknown role"
+// @Router /me/permissions [get]
+func (h *PermissionHandler) GetMyPermissions(c *gin.Context) {
+	permissions, err := h.permissionService.GetMyPermissions(c.Request.Context(), c.GetUint("user_id"), c.GetString("user_role"))
+	if err != nil {
+		c.JSON(http.StatusForbidden, dto.ForbiddenResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: permissions})
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PhotoHandler struct {
+	photoService   services.PhotoService
+	teacherService services.TeacherService
+}
+
+func NewPhotoHandler(photoService services.PhotoService, teacherService services.TeacherService) *PhotoHandler {
+	return &PhotoHandler{
+		photoService:   photoService,
+		teacherService: teacherService,
+	}
+}
+
+// UploadStudentPhoto godoc
+// @Summary Upload student photo
+// @Description Set the photo (JPEG, PNG or WebP, max 2 MB) the business keeps on a student's record for ID cards, replacing any previous one. The photo belongs to the business, so students can't change it. (Admin/Business only)
+// @Tags photos
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param file formData file true "Photo"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.PhotoResponse} "Success response with the photo URL"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Student not found"
+// @Router /students/{id}/photo [put]
+func (h *PhotoHandler) UploadStudentPhoto(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.photoService)
+	if !ok {
+		return
+	}
+
+	uploadPhoto(c, func(file multipart.File, header *multipart.FileHeader) (*models.PhotoResponse, error) {
+		return h.photoService.UploadStudentPhoto(c.Request.Context(), studentID, file, header)
+	})
+}
+
+// GetStudentPhoto godoc
+// @Summary Get student photo
+// @Description Get the photo kept on a student's record; photo_url in student responses points here (Admin/Business only)
+// @Tags photos
+// @Produce image/jpeg,image/png,image/webp
+// @Param id path int true "Student ID"
+// @Security BearerAuth
+// @Success 200 {file} file "Photo"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Student or photo not found"
+// @Router /students/{id}/photo [get]
+func (h *PhotoHandler) GetStudentPhoto(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.photoService)
+	if !ok {
+		return
+	}
+
+	photo, err := h.photoService.GetStudentPhoto(c.Request.Context(), studentID)
+	writePhoto(c, photo, err)
+}
+
+// DeleteStudentPhoto godoc
+// @Summary Delete student photo
+// @Description Remove the photo kept on a student's record (Admin/Business only)
+// @Tags photos
+// @Produce json
+// @Param id path int true "Student ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Student or photo not found"
+// @Router /students/{id}/photo [delete]
+func (h *PhotoHandler) DeleteStudentPhoto(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.photoService)
+	if !ok {
+		return
+	}
+
+	if err := h.photoService.DeleteStudentPhoto(c.Request.Context(), studentID); err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Photo removed successfully",
+	})
+}
+
+// UploadTeacherPhoto godoc
+// @Summary Upload teacher photo
+// @Description Set the photo (JPEG, PNG or WebP, max 2 MB) the business keeps on a teacher's record for ID cards, replacing any previous one. The photo belongs to the business, so teachers can't change it. (Admin/Business only)
+// @Tags photos
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param file formData file true "Photo"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.PhotoResponse} "Success response with the photo URL"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Teacher not found"
+// @Router /teachers/{id}/photo [put]
+func (h *PhotoHandler) UploadTeacherPhoto(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	uploadPhoto(c, func(file multipart.File, header *multipart.FileHeader) (*models.PhotoResponse, error) {
+		return h.photoService.UploadTeacherPhoto(c.Request.Context(), teacherID, file, header)
+	})
+}
+
+// GetTeacherPhoto godoc
+// @Summary Get teacher photo
+// @Description Get the photo kept on a teacher's record; photo_url in teacher responses points here (Admin/Business only)
+// @Tags photos
+// @Produce image/jpeg,image/png,image/webp
+// @Param id path int true "Teacher ID"
+// @Security BearerAuth
+// @Success 200 {file} file "Photo"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Teacher or photo not found"
+// @Router /teachers/{id}/photo [get]
+func (h *PhotoHandler) GetTeacherPhoto(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	photo, err := h.photoService.GetTeacherPhoto(c.Request.Context(), teacherID)
+	writePhoto(c, photo, err)
+}
+
+// DeleteTeacherPhoto godoc
+// @Summary Delete teacher photo
+// @Description Remove the photo kept on a teacher's record (Admin/Business only)
+// @Tags photos
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Teacher or photo not found"
+// @Router /teachers/{id}/photo [delete]
+func (h *PhotoHandler) DeleteTeacherPhoto(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	if err := h.photoService.DeleteTeacherPhoto(c.Request.Context(), teacherID); err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Photo removed successfully",
+	})
+}
+
+// uploadPhoto reads the uploaded file from the form and writes the response of save
+func uploadPhoto(c *gin.Context, save func(multipart.File, *multipart.FileHeader) (*models.PhotoResponse, error)) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "File is required",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	photo, err := save(file, header)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Photo uploaded successfully",
+		Data:    photo,
+	})
+}
+
+// writePhoto sends a stored photo, or the error reading it
+func writePhoto(c *gin.Context, photo *models.Photo, err error) {
+	if err != nil {
+		switch {
+		case strings.HasSuffix(err.Error(), "not found"), strings.HasSuffix(err.Error(), "has no photo"):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get photo"})
+		}
+		return
+	}
+
+	// Photo URLs change with every upload, so the image can be cached, though only privately
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Data(http.StatusOK, photo.ContentType, photo.Content)
+}
@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QualificationHandler struct {
+	qualificationService services.QualificationService
+}
+
+func NewQualificationHandler(qualificationService services.QualificationService) *QualificationHandler {
+	return &QualificationHandler{
+		qualificationService: qualificationService,
+	}
+}
+
+// GetQualifications godoc
+// @Summary Get global qualifications
+// @Description Get the qualification entries offered to every business (Admin only)
+// @Tags qualifications
+// @Accept json
+// @Produce json
+// @Param include_pending query bool false "Also list entries waiting for review"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.Qualification} "Success response with qualifications"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /qualifications [get]
+func (h *QualificationHandler) GetQualifications(c *gin.Context) {
+	qualifications, err := h.qualificationService.GetQualifications(c.Request.Context(), c.Query("include_pending") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get qualifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    qualifications,
+	})
+}
+
+// GetBusinessQualifications godoc
+// @Summary Get a business's qualifications
+// @Description Get the global qualification entries followed by the business's own (Admin/Business only)
+// @Tags qualifications
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param include_pending query bool false "Also list entries waiting for review"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.Qualification} "Success response with qualifications"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/qualifications [get]
+func (h *QualificationHandler) GetBusinessQualifications(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.qualificationService)
+	if !ok {
+		return
+	}
+
+	qualifications, err := h.qualificationService.GetBusinessQualifications(c.Request.Context(), businessID, c.Query("include_pending") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get qualifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    qualifications,
+	})
+}
+
+// CreateQualification godoc
+// @Summary Create a global qualification
+// @Description Add an entry to the qualifications offered to every business (Admin only)
+// @Tags qualifications
+// @Accept json
+// @Produce json
+// @Param request body models.CreateQualificationRequest true "Qualification data"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.Qualification} "Success response with qualification data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /qualifications [post]
+func (h *QualificationHandler) CreateQualification(c *gin.Context) {
+	h.createQualification(c, nil)
+}
+
+// CreateBusinessQualification godoc
+// @Summary Create a business qualification
+// @Description Add a custom qualification entry for one business (Admin/Business only)
+// @Tags qualifications
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.CreateQualificationRequest true "Qualification data"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.Qualification} "Success response with qualification data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/qualifications [post]
+func (h *QualificationHandler) CreateBusinessQualification(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.qualificationService)
+	if !ok {
+		return
+	}
+	h.createQualification(c, &businessID)
+}
+
+// UpdateQualification godoc
+// @Summary Update a global qualification
+// @Description Rename or approve a global qualification entry (Admin only)
+// @Tags qualifications
+// @Accept json
+// @Produce json
+// @Param qualificationId path int true "Qualification ID"
+// @Param request body models.UpdateQualificationRequest true "Qualification update data"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.Qualification} "Success response with updated qualification data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Qualification not found"
+// @Router /qualifications/{qualificationId} [put]
+func (h *QualificationHandler) UpdateQualification(c *gin.Context) {
+	h.updateQualification(c, nil)
+}
+
+// UpdateBusinessQualification godoc
+// @Summary Update a business qualification
+// @Description Rename a business's qualification entry, or approve one added as free text on a teacher by setting pending to false (Admin/Business only)
+// @Tags qualifications
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param qualificationId path int true "Qualification ID"
+// @Param request body models.UpdateQualificationRequest true "Qualification update data"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.Qualification} "Success response with updated qualification data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Qualification not found"
+// @Router /businesses/{businessId}/qualifications/{qualificationId} [put]
+func (h *QualificationHandler) UpdateBusinessQualification(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.qualificationService)
+	if !ok {
+		return
+	}
+	h.updateQualification(c, &businessID)
+}
+
+// DeleteQualification godoc
+// @Summary Delete a global qualification
+// @Description Remove a global qualification entry; its teachers keep the name as free text (Admin only)
+// @Tags qualifications
+// @Accept json
+// @Produce json
+// @Param qualificationId path int true "Qualification ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Qualification not found"
+// @Router /qualifications/{qualificationId} [delete]
+func (h *QualificationHandler) DeleteQualification(c *gin.Context) {
+	h.deleteQualification(c, nil)
+}
+
+// DeleteBusinessQualification godoc
+// @Summary Delete a business qualification
+// @Description Remove a business's qualification entry; its teachers keep the name as free text (Admin/Business only)
+// @Tags qualifications
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param qualificationId path int true "Qualification ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Qualification not found"
+// @Router /businesses/{businessId}/qualifications/{qualificationId} [delete]
+func (h *QualificationHandler) DeleteBusinessQualification(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.qualificationService)
+	if !ok {
+		return
+	}
+	h.deleteQualification(c, &businessID)
+}
+
+// MigrateTeacherQualifications godoc
+// @Summary Map teacher qualifications to the taxonomy
+// @Description Point teachers whose free-text qualification matches an entry, ignoring case, spaces and punctuation, at that entry, and report the strings no entry matched. The free text is kept. (Admin only)
+// @Tags qualifications
+// @Accept json
+// @Produce json
+// @Param dry_run query bool false "Only report what would be mapped"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.QualificationMigrationReport} "Success response with the migration report"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /qualifications/migrate [post]
+func (h *QualificationHandler) MigrateTeacherQualifications(c *gin.Context) {
+	report, err := h.qualificationService.MigrateTeacherQualifications(c.Request.Context(), c.Query("dry_run") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    report,
+	})
+}
+
+func (h *QualificationHandler) createQualification(c *gin.Context, businessID *uint) {
+	var req models.CreateQualificationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	qualification, err := h.qualificationService.CreateQualification(c.Request.Context(), businessID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Qualification created successfully",
+		Data:    qualification,
+	})
+}
+
+func (h *QualificationHandler) updateQualification(c *gin.Context, businessID *uint) {
+	qualificationID, err := strconv.ParseUint(c.Param("qualificationId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid qualification ID"})
+		return
+	}
+
+	var req models.UpdateQualificationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	qualification, err := h.qualificationService.UpdateQualification(c.Request.Context(), businessID, uint(qualificationID), req)
+	if err != nil {
+		if err.Error() == "qualification not found" {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Qualification updated successfully",
+		Data:    qualification,
+	})
+}
+
+func (h *QualificationHandler) deleteQualification(c *gin.Context, businessID *uint) {
+	qualificationID, err := strconv.ParseUint(c.Param("qualificationId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid qualification ID"})
+		return
+	}
+
+	if err := h.qualificationService.DeleteQualification(c.Request.Context(), businessID, uint(qualificationID)); err != nil {
+		if err.Error() == "qualification not found" {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Qualification deleted successfully",
+	})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	reportService services.ReportService
+}
+
+func NewReportHandler(reportService services.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// GetReportSubscriptions godoc
+// @Summary Get report subscriptions
+// @Description Get the scheduled report emails available to the current user and whether they are subscribed (Admin/Business only)
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.ReportSubscriptionResponse} "Success response with subscriptions"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /profile/report-subscriptions [get]
+func (h *ReportHandler) GetReportSubscriptions(c *gin.Context) {
+	subscriptions, err := h.reportService.GetSubscriptions(c.Request.Context(), c.GetUint("user_id"), c.GetString("user_role"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to retrieve subscriptions",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    subscriptions,
+	})
+}
+
+// UpdateReportSubscriptions godoc
+// @Summary Update report subscriptions
+// @Description Subscribe to or unsubscribe from scheduled report emails: admin_weekly for admins, business_weekly for business users (Admin/Business only)
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateReportSubscriptionsRequest true "Subscriptions to change"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.ReportSubscriptionResponse} "Success response with subscriptions"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /profile/report-subscriptions [put]
+func (h *ReportHandler) UpdateReportSubscriptions(c *gin.Context) {
+	var req models.UpdateReportSubscriptionsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	subscriptions, err := h.reportService.UpdateSubscriptions(c.Request.Context(), c.GetUint("user_id"), c.GetString("user_role"), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Subscriptions updated successfully",
+		Data:    subscriptions,
+	})
+}
+
+// SendReport godoc
+// @Summary Send a report now
+// @Description Email a report covering the last seven days right away, to every subscriber or only to user_id, for testing templates and recipients (Admin only)
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param request body models.SendReportRequest true "Report to send"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.SendReportResult} "Success response with delivery counts"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /admin/reports/send [post]
+func (h *ReportHandler) SendReport(c *gin.Context) {
+	var req models.SendReportRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.reportService.SendNow(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Report emails queued",
+		Data:    result,
+	})
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Per-section result limits of the global search
+const (
+	defaultSearchLimit = 5
+	maxSearchLimit     = 20
+)
+
+type SearchHandler struct {
+	searchService services.SearchService
+}
+
+func NewSearchHandler(searchService services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search godoc
+// @Summary Search across users, businesses, teachers and students
+// @Description Search every entity type at once, returning a section per type with its best matches (exact name or email matches first) and total match count. Admins search everything; business users only their own teachers and students. Sections that don't finish within SEARCH_TIMEOUT come back empty with an error (Admin/Business only)
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search term"
+// @Param limit query int false "Matches per section (max 20)" default(5)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.SearchResponse} "Success response with the search sections"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Search query q is required"})
+		return
+	}
+
+	limit := defaultSearchLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid limit"})
+			return
+		}
+		limit = min(parsed, maxSearchLimit)
+	}
+
+	results, err := h.searchService.Search(c.Request.Context(), query, limit, c.GetUint("user_id"), c.GetString("user_role"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrAccessDenied) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    results,
+	})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/repository"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondInvalidSelection writes the 400 response for an unknown name in the fields,
+// include or sort_by query parameter or an unknown filter value, listing the valid options
+func respondInvalidSelection(c *gin.Context, err error) {
+	response := dto.ErrorResponse{
+		Error:   "Invalid query parameters",
+		Details: err.Error(),
+	}
+
+	var selectionErr *repository.SelectionError
+	if errors.As(err, &selectionErr) {
+		response.ValidOptions = selectionErr.Valid
+	}
+	c.JSON(http.StatusBadRequest, response)
+}
+
+// selectFields trims every item of a list to the requested response fields, returning
+// the list untouched when no fields were requested
+func selectFields(items interface{}, fields []string) interface{} {
+	if len(fields) == 0 {
+		return items
+	}
+
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return items
+	}
+	var rows []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		return items
+	}
+
+	selected := make([]map[string]json.RawMessage, len(rows))
+	for i, row := range rows {
+		selected[i] = make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := row[field]; ok {
+				selected[i][field] = value
+			}
+		}
+	}
+	return selected
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/pkg/sms"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SMSHandler struct {
+	smsService services.SMSService
+}
+
+func NewSMSHandler(smsService services.SMSService) *SMSHandler {
+	return &SMSHandler{
+		smsService: smsService,
+	}
+}
+
+// GetSMSSettings godoc
+// @Summary Get SMS settings
+// @Description Get whether guardians of the business's students are texted about absences and fee dues (Admin/Business only)
+// @Tags sms
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.SMSSettings} "Success response with SMS settings"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business not found"
+// @Router /businesses/{businessId}/sms/settings [get]
+func (h *SMSHandler) GetSMSSettings(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.smsService)
+	if !ok {
+		return
+	}
+
+	settings, err := h.smsService.GetSettings(c.Request.Context(), businessID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    settings,
+	})
+}
+
+// UpdateSMSSettings godoc
+// @Summary Update SMS settings
+// @Description Opt the business in to or out of texting guardians about absences and fee dues (Admin/Business only)
+// @Tags sms
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.UpdateSMSSettingsRequest true "Settings"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.SMSSettings} "Success response with SMS settings"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/sms/settings [put]
+func (h *SMSHandler) UpdateSMSSettings(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.smsService)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSMSSettingsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	settings, err := h.smsService.UpdateSettings(c.Request.Context(), businessID, *req.Enabled)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Settings updated successfully",
+		Data:    settings,
+	})
+}
+
+// GetSMSMessages godoc
+// @Summary Get sent text messages
+// @Description Get the text messages sent to guardians of the business's students and their delivery status, newest first (Admin/Business only)
+// @Tags sms
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param student_id query int false "Filter by student ID"
+// @Param kind query string false "Filter by kind (absence, fee_due)"
+// @Param status query string false "Filter by status (queued, sent, delivered, failed, rate_limited)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.SMSMessagePage} "Success response with messages"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/sms/messages [get]
+func (h *SMSHandler) GetSMSMessages(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.smsService)
+	if !ok {
+		return
+	}
+
+	var filters repository.SMSFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filters.BusinessID = &businessID
+
+	messages, total, err := h.smsService.GetMessages(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to retrieve messages",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.SMSMessagePage{
+			Messages:   messages,
+			OffsetPage: dto.OffsetPage{Total: total, Page: filters.Page, Limit: filters.Limit},
+		},
+	})
+}
+
+// SMSStatusCallback godoc
+// @Summary Record an SMS delivery report
+// @Description Delivery report webhook called by the SMS provider, signed in the X-Twilio-Signature header
+// @Tags sms
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Success 204 "Report recorded"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 403 {object} dto.ForbiddenResponse "Invalid signature"
+// @Router /sms/status [post]
+func (h *SMSHandler) SMSStatusCallback(c *gin.Context) {
+	if err := c.Request.ParseForm(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid form body"})
+		return
+	}
+
+	err := h.smsService.RecordStatus(c.Request.Context(), c.Request.PostForm, c.GetHeader("X-Twilio-Signature"))
+	if err != nil {
+		if errors.Is(err, sms.ErrInvalidSignature) {
+			c.JSON(http.StatusForbidden, dto.ForbiddenResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StudentAttendanceHandler struct {
+	attendanceService services.StudentAttendanceService
+}
+
+func NewStudentAttendanceHandler(attendanceService services.StudentAttendanceService) *StudentAttendanceHandler {
+	return &StudentAttendanceHandler{
+		attendanceService: attendanceService,
+	}
+}
+
+// GetStudentAttendance godoc
+// @Summary Get student attendance
+// @Description Get the attendance records of a student with optional date range and status filters (Admin/Business only)
+// @Tags student-attendance
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param from query string false "From date (YYYY-MM-DD, inclusive)"
+// @Param to query string false "To date (YYYY-MM-DD, inclusive)"
+// @Param status query string false "Status (present, absent, leave)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(31)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.StudentAttendancePage} "Success response with attendance records"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/{id}/attendance [get]
+func (h *StudentAttendanceHandler) GetStudentAttendance(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.attendanceService)
+	if !ok {
+		return
+	}
+
+	var filters repository.StudentAttendanceFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filters.StudentID = &studentID
+	filters.BusinessID = nil
+
+	h.respondWithAttendance(c, filters)
+}
+
+// GetStudentAttendanceSummary godoc
+// @Summary Get student monthly attendance summary
+// @Description Get present, absent and leave days and the attendance percentage of a student for a month (Admin/Business only)
+// @Tags student-attendance
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param month query string false "Month (YYYY-MM), defaults to the current month"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.StudentAttendanceSummary} "Success response with attendance summary"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/{id}/attendance/summary [get]
+func (h *StudentAttendanceHandler) GetStudentAttendanceSummary(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.attendanceService)
+	if !ok {
+		return
+	}
+
+	summary, err := h.attendanceService.GetStudentMonthlySummary(c.Request.Context(), studentID, c.Query("month"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    summary,
+	})
+}
+
+// BulkMarkStudentAttendance godoc
+// @Summary Bulk mark student attendance
+// @Description Mark attendance for several students of a business on one date, optionally for a single batch; marking the same date again replaces the earlier records (Admin/Business only)
+// @Tags student-attendance
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.BulkStudentAttendanceRequest true "Attendance entries"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.StudentAttendanceResponse} "Success response with attendance records"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/students/attendance [post]
+func (h *StudentAttendanceHandler) BulkMarkStudentAttendance(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.attendanceService)
+	if !ok {
+		return
+	}
+
+	var req models.BulkStudentAttendanceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	records, err := h.attendanceService.BulkMarkAttendance(c.Request.Context(), businessID, req, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Attendance marked successfully",
+		Data:    records,
+	})
+}
+
+// GetBusinessStudentAttendance godoc
+// @Summary Get business student attendance
+// @Description Get the student attendance records of a business with optional student, batch, date range and status filters (Admin/Business only)
+// @Tags student-attendance
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param student_id query int false "Student ID"
+// @Param batch_id query int false "Batch ID"
+// @Param from query string false "From date (YYYY-MM-DD, inclusive)"
+// @Param to query string false "To date (YYYY-MM-DD, inclusive)"
+// @Param status query string false "Status (present, absent, leave)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(31)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.StudentAttendancePage} "Success response with attendance records"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/students/attendance [get]
+func (h *StudentAttendanceHandler) GetBusinessStudentAttendance(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.attendanceService)
+	if !ok {
+		return
+	}
+
+	var filters repository.StudentAttendanceFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filters.BusinessID = &businessID
+
+	h.respondWithAttendance(c, filters)
+}
+
+// GetBusinessStudentAttendanceSummary godoc
+// @Summary Get business monthly student attendance summary
+// @Description Get per-student and overall present, absent and leave days and attendance percentages of a business for a month, optionally for a single batch (Admin/Business only)
+// @Tags student-attendance
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param month query string false "Month (YYYY-MM), defaults to the current month"
+// @Param batch_id query int false "Batch ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.BusinessStudentAttendanceSummary} "Success response with attendance summary"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/students/attendance/summary [get]
+func (h *StudentAttendanceHandler) GetBusinessStudentAttendanceSummary(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.attendanceService)
+	if !ok {
+		return
+	}
+
+	var batchID *uint
+	if value := c.Query("batch_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid batch ID"})
+			return
+		}
+		parsed := uint(id)
+		batchID = &parsed
+	}
+
+	summary, err := h.attendanceService.GetBusinessMonthlySummary(c.Request.Context(), businessID, batchID, c.Query("month"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    summary,
+	})
+}
+
+func (h *StudentAttendanceHandler) respondWithAttendance(c *gin.Context, filters repository.StudentAttendanceFilters) {
+	records, total, err := h.attendanceService.GetAttendance(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.StudentAttendancePage{
+			Attendance: records,
+			OffsetPage: dto.OffsetPage{Total: total, Page: filters.Page, Limit: filters.Limit},
+		},
+	})
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StudentFieldHandler struct {
+	fieldService services.StudentFieldService
+}
+
+func NewStudentFieldHandler(fieldService services.StudentFieldService) *StudentFieldHandler {
+	return &StudentFieldHandler{
+		fieldService: fieldService,
+	}
+}
+
+// GetStudentFields godoc
+// @Summary Get custom student fields
+// @Description Get the custom fields the current business validates student information against (Business users only)
+// @Tags student-fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.StudentFieldSchema} "Success response with field schema"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/student-fields [get]
+func (h *StudentFieldHandler) GetStudentFields(c *gin.Context) {
+	businessID, ok := h.myBusinessID(c)
+	if !ok {
+		return
+	}
+
+	schema, err := h.fieldService.GetSchema(c.Request.Context(), businessID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    schema,
+	})
+}
+
+// CreateStudentField godoc
+// @Summary Create a custom student field
+// @Description Define a custom student information key with its label, type (text, number, boolean, date) and whether it is required (Business users only)
+// @Tags student-fields
+// @Accept json
+// @Produce json
+// @Param request body models.CreateStudentFieldRequest true "Field definition"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.StudentField} "Success response with field data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /my-business/student-fields [post]
+func (h *StudentFieldHandler) CreateStudentField(c *gin.Context) {
+	businessID, ok := h.myBusinessID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateStudentFieldRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	field, err := h.fieldService.CreateField(c.Request.Context(), businessID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Field created successfully",
+		Data:    field,
+	})
+}
+
+// UpdateStudentField godoc
+// @Summary Update a custom student field
+// @Description Update the label or required flag of a custom student field; the key and type cannot change (Business users only)
+// @Tags student-fields
+// @Accept json
+// @Produce json
+// @Param fieldId path int true "Field ID"
+// @Param request body models.UpdateStudentFieldRequest true "Field update data"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.StudentField} "Success response with updated field data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /my-business/student-fields/{fieldId} [put]
+func (h *StudentFieldHandler) UpdateStudentField(c *gin.Context) {
+	businessID, ok := h.myBusinessID(c)
+	if !ok {
+		return
+	}
+
+	fieldID, err := strconv.ParseUint(c.Param("fieldId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid field ID"})
+		return
+	}
+
+	var req models.UpdateStudentFieldRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	field, err := h.fieldService.UpdateField(c.Request.Context(), businessID, uint(fieldID), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Field updated successfully",
+		Data:    field,
+	})
+}
+
+// DeleteStudentField godoc
+// @Summary Delete a custom student field
+// @Description Delete a custom student field definition; values already stored on students are kept (Business users only)
+// @Tags student-fields
+// @Accept json
+// @Produce json
+// @Param fieldId path int true "Field ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /my-business/student-fields/{fieldId} [delete]
+func (h *StudentFieldHandler) DeleteStudentField(c *gin.Context) {
+	businessID, ok := h.myBusinessID(c)
+	if !ok {
+		return
+	}
+
+	fieldID, err := strconv.ParseUint(c.Param("fieldId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid field ID"})
+		return
+	}
+
+	if err := h.fieldService.DeleteField(c.Request.Context(), businessID, uint(fieldID)); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Field deleted successfully",
+	})
+}
+
+// UpdateStudentFieldSettings godoc
+// @Summary Update custom student field settings
+// @Description Choose whether student information keys without a field definition are rejected (strict) or kept as they are (Business users only)
+// @Tags student-fields
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateStudentFieldSettingsRequest true "Settings"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.StudentFieldSchema} "Success response with field schema"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /my-business/student-fields/settings [put]
+func (h *StudentFieldHandler) UpdateStudentFieldSettings(c *gin.Context) {
+	businessID, ok := h.myBusinessID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateStudentFieldSettingsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	schema, err := h.fieldService.UpdateSettings(c.Request.Context(), businessID, *req.Strict)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Settings updated successfully",
+		Data:    schema,
+	})
+}
+
+// GetStudentGrades godoc
+// @Summary Get student grades
+// @Description Get the grades (class levels) students of the current business can be placed in, in order (Business users only)
+// @Tags student-fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.StudentGrade} "Success response with grades"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/student-grades [get]
+func (h *StudentFieldHandler) GetStudentGrades(c *gin.Context) {
+	businessID, ok := h.myBusinessID(c)
+	if !ok {
+		return
+	}
+
+	grades, err := h.fieldService.GetGrades(c.Request.Context(), businessID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    grades,
+	})
+}
+
+// ReplaceStudentGrades godoc
+// @Summary Replace student grades
+// @Description Set the ordered list of grades students of the current business can be placed in; an empty list allows any grade. Students keep grades that are removed from the list. (Business users only)
+// @Tags student-fields
+// @Accept json
+// @Produce json
+// @Param request body models.ReplaceStudentGradesRequest true "Grades in order"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.StudentGrade} "Success response with grades"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /my-business/student-grades [put]
+func (h *StudentFieldHandler) ReplaceStudentGrades(c *gin.Context) {
+	businessID, ok := h.myBusinessID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReplaceStudentGradesRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	grades, err := h.fieldService.ReplaceGrades(c.Request.Context(), businessID, req.Grades)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Grades updated successfully",
+		Data:    grades,
+	})
+}
+
+// myBusinessID resolves the caller's own business, writing a 404 response when they have none
+func (h *StudentFieldHandler) myBusinessID(c *gin.Context) (uint, bool) {
+	return myBusinessID(c, h.fieldService)
+}
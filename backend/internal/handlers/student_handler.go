@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"backend/internal/dto"
 	"backend/internal/models"
 	"backend/internal/repository"
 	"backend/internal/services"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -12,51 +16,48 @@ import (
 
 type StudentHandler struct {
 	studentService services.StudentService
+	auditService   services.AuditService
 }
 
-func NewStudentHandler(studentService services.StudentService) *StudentHandler {
+func NewStudentHandler(studentService services.StudentService, auditService services.AuditService) *StudentHandler {
 	return &StudentHandler{
 		studentService: studentService,
+		auditService:   auditService,
 	}
 }
 
 // CreateStudent godoc
 // @Summary Create a new student
-// @Description Create a new student (Admin/Business only)
+// @Description Create a new student (Admin only)
 // @Tags students
 // @Accept json
 // @Produce json
 // @Param request body models.CreateStudentRequest true "Student data"
 // @Security BearerAuth
-// @Success 201 {object} map[string]interface{} "Success response with student data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/students [post]
+// @Success 201 {object} dto.Response{data=models.StudentResponse} "Success response with student data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students [post]
 func (h *StudentHandler) CreateStudent(c *gin.Context) {
 	var req models.CreateStudentRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	student, err := h.studentService.CreateStudent(req)
+	student, err := h.studentService.CreateStudent(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		if writeFieldError(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"success": true,
-		"message": "Student created successfully",
-		"data":    student,
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Student created successfully",
+		Data:    student,
 	})
 }
 
@@ -68,153 +69,184 @@ func (h *StudentHandler) CreateStudent(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
-// @Param status query int false "Filter by status (0=inactive, 1=active)"
+// @Param cursor query string false "next_cursor of the previous page, for keyset pagination instead of page; total is skipped. Needs a single sort_by column"
+// @Param with_total query bool false "Count the total; false skips the count query" default(true)
+// @Param estimate query bool false "Estimate the total from table statistics instead of counting, for large tables"
+// @Param status query string false "Filter by status (0 or inactive, 1 or active)"
 // @Param business_id query int false "Filter by business ID"
-// @Param guardian_name query string false "Filter by guardian name"
-// @Param guardian_email query string false "Filter by guardian email"
-// @Param search query string false "Search in name, guardian info"
-// @Param sort_by query string false "Sort by field"
-// @Param sort_order query string false "Sort order (asc, desc)" default(desc)
+// @Param guardian_name query string false "Filter by the name of any guardian"
+// @Param guardian_email query string false "Filter by the email of any guardian"
+// @Param batch_id query int false "Filter by batch ID"
+// @Param grade query string false "Filter by grade"
+// @Param student_code query string false "Filter by student code, ignoring case"
+// @Param gender query string false "Filter by gender (male, female, other)"
+// @Param min_age query int false "Minimum age in years"
+// @Param max_age query int false "Maximum age in years"
+// @Param info_key query string false "Custom information field to filter by"
+// @Param info_value query string false "Value the custom information field must equal"
+// @Param search query string false "Search in name, student code, guardian info"
+// @Param search_info query bool false "Also search the values of custom information fields"
+// @Param search_info_key query string false "Only search this custom information field"
+// @Param sort_by query string false "Comma-separated sort columns (name, student_code, guardian_name, guardian_email, guardian_number, status, enrolled_on, date_of_birth, grade, gender, created_on, updated_on), e.g. status,name" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Param fields query string false "Comma-separated response fields to return, e.g. id,name"
+// @Param include query string false "Comma-separated relations to load (user, business, batch, guardians); defaults to those named in fields, or all"
+// @Param include_deleted query bool false "Also list soft-deleted students, which carry deleted_at"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with students list"
-// @Router /api/students [get]
+// @Success 200 {object} dto.Response{data=dto.StudentPage{students=[]models.StudentResponse}} "Success response with students list"
+// @Failure 400 {object} dto.ErrorResponse "Unknown sort column or filter value; valid_options lists the accepted ones"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students [get]
 func (h *StudentHandler) GetStudents(c *gin.Context) {
+	h.listStudents(c, nil)
+}
+
+// listStudents writes a page of students matching the query filters, with the status
+// filter fixed when the active and inactive shortcuts give one
+func (h *StudentHandler) listStudents(c *gin.Context, status *models.Status) {
 	var filters repository.StudentFilters
 	if err := c.ShouldBindQuery(&filters); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid query parameters",
-			"details": err.Error(),
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
 		})
 		return
 	}
+	if status != nil {
+		filters.Status = status
+	}
 
-	students, total, err := h.studentService.GetStudents(filters)
+	if err := filters.Validate(); err != nil {
+		respondInvalidSelection(c, err)
+		return
+	}
+
+	selection, err := filters.Selection()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get students",
-		})
+		respondInvalidSelection(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"students": students,
-			"total":    total,
-			"page":     filters.Page,
-			"limit":    filters.Limit,
+	students, pageInfo, err := h.studentService.GetStudents(c.Request.Context(), filters)
+	if err != nil {
+		if respondInvalidCursor(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get students"})
+		return
+	}
+	auditDataAccess(c, h.auditService, models.AuditLog{Action: models.AuditActionList, Resource: models.AuditResourceStudents, BusinessID: filters.BusinessID, RowCount: int64(len(students))})
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.StudentPage{
+			Students:   selectFields(students, selection.Fields),
+			CursorPage: dto.NewCursorPage(pageInfo),
 		},
 	})
 }
 
 // GetStudent godoc
 // @Summary Get student by ID
-// @Description Get a specific student by ID
+// @Description Get a specific student by ID (Admin only)
 // @Tags students
 // @Accept json
 // @Produce json
 // @Param id path int true "Student ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with student data"
-// @Failure 404 {object} map[string]string "Student not found"
-// @Router /api/students/{id} [get]
+// @Success 200 {object} dto.Response{data=models.StudentResponse} "Success response with student data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Student not found"
+// @Router /students/{id} [get]
 func (h *StudentHandler) GetStudent(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid student ID",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid student ID"})
 		return
 	}
 
-	student, err := h.studentService.GetStudentByID(uint(id))
+	student, err := h.studentService.GetStudentByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"error":   "Student not found",
-		})
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Student not found"})
 		return
 	}
+	auditDataAccess(c, h.auditService, models.AuditLog{Action: models.AuditActionView, Resource: models.AuditResourceStudents, ResourceID: &student.ID, BusinessID: &student.BusinessID, RowCount: 1})
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    student,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    student,
 	})
 }
 
 // GetMyStudentProfile godoc
 // @Summary Get my student profile
-// @Description Get current user's student profile (Student users only)
+// @Description Get current user's student profile, with a summary of their business (Student users only)
 // @Tags student-profile
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with student data"
-// @Failure 404 {object} map[string]string "Student profile not found"
-// @Router /api/my-student-profile [get]
+// @Success 200 {object} dto.Response{data=models.StudentProfileResponse} "Success response with student data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Student profile not found"
+// @Router /my-student-profile [get]
 func (h *StudentHandler) GetMyStudentProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   "User not authenticated",
-		})
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: "User not authenticated"})
 		return
 	}
 
-	student, err := h.studentService.GetStudentByUserID(userID.(uint))
+	student, err := h.studentService.GetMyStudentProfile(c.Request.Context(), userID.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"error":   "Student profile not found",
-		})
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Student profile not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    student,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    student,
 	})
 }
 
 // UpdateStudent godoc
 // @Summary Update student
-// @Description Update student information
+// @Description Update student information; information keys are merged into the stored ones and null values remove keys (Admin only)
 // @Tags students
 // @Accept json
 // @Produce json
 // @Param id path int true "Student ID"
 // @Param request body models.UpdateStudentRequest true "Student update data"
+// @Param If-Match header string false "Version the update is based on, when not sent as the version field"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with updated student data"
-// @Router /api/students/{id} [put]
+// @Success 200 {object} dto.Response{data=models.StudentResponse} "Success response with updated student data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 409 {object} dto.ErrorResponse "Record was changed since it was read; data holds the current record"
+// @Failure 428 {object} dto.ErrorResponse "No record version sent"
+// @Router /students/{id} [put]
 func (h *StudentHandler) UpdateStudent(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid student ID",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid student ID"})
 		return
 	}
 
 	var req models.UpdateStudentRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
+		return
+	}
+	version, ok := requestVersion(c, req.Version)
+	if !ok {
 		return
 	}
 
 	// Convert to map for updates
-	updates := make(map[string]interface{})
+	updates := map[string]interface{}{"version": version}
 	if req.Name != "" {
 		updates["name"] = req.Name
 	}
@@ -227,260 +259,298 @@ func (h *StudentHandler) UpdateStudent(c *gin.Context) {
 	if req.GuardianEmail != "" {
 		updates["guardian_email"] = req.GuardianEmail
 	}
+	if req.Guardians != nil {
+		updates["guardians"] = req.Guardians
+	}
 	if req.Information != nil {
 		updates["information"] = req.Information
 	}
 	if req.Status != nil {
 		updates["status"] = *req.Status
 	}
+	if req.EnrolledOn != "" {
+		updates["enrolled_on"] = req.EnrolledOn
+	}
+	if req.DateOfBirth != "" {
+		updates["date_of_birth"] = req.DateOfBirth
+	}
+	if req.Gender != "" {
+		updates["gender"] = req.Gender
+	}
+	if req.Grade != "" {
+		updates["grade"] = req.Grade
+	}
 
-	updatedStudent, err := h.studentService.UpdateStudent(uint(id), updates)
+	updatedStudent, err := h.studentService.UpdateStudent(c.Request.Context(), uint(id), updates, c.GetUint("user_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		if writeFieldError(c, err) || writeVersionConflict(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Student updated successfully",
-		"data":    updatedStudent,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Student updated successfully",
+		Data:    updatedStudent,
 	})
 }
 
 // UpdateMyStudentProfile godoc
 // @Summary Update my student profile
-// @Description Update current user's student profile (Student users only)
+// @Description Update current user's name and the information fields their business lets students edit; guardians and enrollment are changed by the business (Student users only)
 // @Tags student-profile
 // @Accept json
 // @Produce json
-// @Param request body models.UpdateStudentRequest true "Student update data"
+// @Param request body models.UpdateMyStudentProfileRequest true "Student profile update data"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with updated student data"
-// @Router /api/my-student-profile [put]
+// @Success 200 {object} dto.Response{data=models.StudentProfileResponse} "Success response with updated student data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request or information field not editable by students"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Student profile not found"
+// @Router /my-student-profile [put]
 func (h *StudentHandler) UpdateMyStudentProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   "User not authenticated",
-		})
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: "User not authenticated"})
 		return
 	}
 
-	// Get student by user ID first
-	student, err := h.studentService.GetStudentByUserID(userID.(uint))
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"error":   "Student profile not found",
-		})
+	var req models.UpdateMyStudentProfileRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	var req models.UpdateStudentRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	updatedStudent, err := h.studentService.UpdateMyStudentProfile(c.Request.Context(), userID.(uint), req)
+	if err != nil {
+		if writeFieldError(c, err) {
+			return
+		}
+		if err.Error() == "student profile not found" {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Student profile not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	// Convert to map for updates (students can update limited fields)
-	updates := make(map[string]interface{})
-	if req.Name != "" {
-		updates["name"] = req.Name
-	}
-	if req.GuardianName != "" {
-		updates["guardian_name"] = req.GuardianName
-	}
-	if req.GuardianNumber != "" {
-		updates["guardian_number"] = req.GuardianNumber
-	}
-	if req.GuardianEmail != "" {
-		updates["guardian_email"] = req.GuardianEmail
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Student profile updated successfully",
+		Data:    updatedStudent,
+	})
+}
+
+// DeleteStudent godoc
+// @Summary Delete student
+// @Description Delete a student (Admin only). The student is soft-deleted: hidden from lists, searches and stats with its
+// @Description attendance and fee history kept, and restorable until purged after the retention period. The linked user account is deactivated meanwhile.
+// @Description With permanent the student is removed right away, and the user account is deleted when delete_user is set;
+// @Description students with fee payments cannot be deleted permanently.
+// @Tags students
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param permanent query bool false "Delete permanently instead of soft-deleting; also applies to a soft-deleted student"
+// @Param delete_user query bool false "With permanent, delete the linked user account instead of deactivating it"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/{id} [delete]
+func (h *StudentHandler) DeleteStudent(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid student ID"})
+		return
 	}
-	if req.Information != nil {
-		updates["information"] = req.Information
+
+	var opts models.DeleteProfileOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
 	}
 
-	updatedStudent, err := h.studentService.UpdateStudent(student.ID, updates)
+	err = h.studentService.DeleteStudent(c.Request.Context(), uint(id), opts)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Student profile updated successfully",
-		"data":    updatedStudent,
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Student deleted successfully",
 	})
 }
 
-// DeleteStudent godoc
-// @Summary Delete student
-// @Description Delete a student (Admin only)
+// RestoreStudent godoc
+// @Summary Restore student
+// @Description Restore a soft-deleted student (Admin only). The linked user account is reactivated when the student is active.
 // @Tags students
 // @Accept json
 // @Produce json
 // @Param id path int true "Student ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Router /api/students/{id} [delete]
-func (h *StudentHandler) DeleteStudent(c *gin.Context) {
+// @Success 200 {object} dto.Response{data=models.StudentResponse} "Success response with the restored student"
+// @Failure 400 {object} dto.ErrorResponse "Student is not deleted"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Student not found"
+// @Router /students/{id}/restore [post]
+func (h *StudentHandler) RestoreStudent(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid student ID",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid student ID"})
 		return
 	}
 
-	err = h.studentService.DeleteStudent(uint(id))
+	student, err := h.studentService.RestoreStudent(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		if err.Error() == "student not found" {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Student not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Student deleted successfully",
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Student restored successfully",
+		Data:    student,
 	})
 }
 
 // GetStudentsByBusiness godoc
 // @Summary Get students by business
-// @Description Get all students for a specific business
+// @Description Get all students for a specific business (Admin/Business only)
 // @Tags students
 // @Accept json
 // @Produce json
 // @Param businessId path int true "Business ID"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param cursor query string false "next_cursor of the previous page, for keyset pagination instead of page; total is skipped. Needs a single sort_by column"
+// @Param with_total query bool false "Count the total; false skips the count query" default(true)
+// @Param estimate query bool false "Estimate the total from table statistics instead of counting, for large tables"
+// @Param batch_id query int false "Filter by batch ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with students list"
-// @Router /api/businesses/{businessId}/students [get]
+// @Success 200 {object} dto.Response{data=dto.StudentPage{students=[]models.StudentResponse}} "Success response with students list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/students [get]
 func (h *StudentHandler) GetStudentsByBusiness(c *gin.Context) {
-	businessIDParam := c.Param("businessId")
-	businessID, err := strconv.ParseUint(businessIDParam, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid business ID",
-		})
+	businessID, ok := authorizeBusiness(c, h.studentService)
+	if !ok {
 		return
 	}
+	h.listBusinessStudents(c, businessID, nil)
+}
 
+// listBusinessStudents writes a page of the business's students, read with the list filters
+func (h *StudentHandler) listBusinessStudents(c *gin.Context, businessID uint, status *models.Status) {
 	var filters repository.StudentFilters
 	if err := c.ShouldBindQuery(&filters); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid query parameters",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid query parameters"})
 		return
 	}
+	if status != nil {
+		filters.Status = status
+	}
+	filters.IncludeDeleted = false // only the admin listing shows deleted students
 
-	students, total, err := h.studentService.GetStudentsByBusiness(uint(businessID), filters)
+	if err := filters.Validate(); err != nil {
+		respondInvalidSelection(c, err)
+		return
+	}
+
+	students, pageInfo, err := h.studentService.GetStudentsByBusiness(c.Request.Context(), businessID, filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get students",
-		})
+		if respondInvalidCursor(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get students"})
 		return
 	}
+	auditDataAccess(c, h.auditService, models.AuditLog{Action: models.AuditActionList, Resource: models.AuditResourceStudents, BusinessID: &businessID, RowCount: int64(len(students))})
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"students": students,
-			"total":    total,
-			"page":     filters.Page,
-			"limit":    filters.Limit,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.StudentPage{
+			Students:   students,
+			CursorPage: dto.NewCursorPage(pageInfo),
 		},
 	})
 }
 
 // ChangeStudentStatus godoc
 // @Summary Change student status
-// @Description Change the status of a student
+// @Description Change the status of a student (Admin only)
 // @Tags students
 // @Accept json
 // @Produce json
 // @Param id path int true "Student ID"
 // @Param request body map[string]int true "Status data"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Router /api/students/{id}/status [patch]
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/{id}/status [patch]
 func (h *StudentHandler) ChangeStudentStatus(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid student ID",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid student ID"})
 		return
 	}
 
 	var req struct {
-		Status int `json:"status" binding:"required,min=0,max=1"`
+		Status *models.Status `json:"status" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	err = h.studentService.ChangeStudentStatus(uint(id), req.Status)
+	err = h.studentService.ChangeStudentStatus(c.Request.Context(), uint(id), *req.Status, c.GetUint("user_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Student status updated successfully",
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Student status updated successfully",
 	})
 }
 
 // SearchStudents godoc
 // @Summary Search students
-// @Description Search students by name, guardian name, email, or number
+// @Description Search students by name, student code, guardian name, email, or number, and optionally by custom information values. Phone numbers match regardless of spacing, punctuation or country code. (Admin only)
 // @Tags students
 // @Accept json
 // @Produce json
 // @Param q query string true "Search term"
+// @Param search_info query bool false "Also search the values of custom information fields"
+// @Param search_info_key query string false "Only search this custom information field (e.g. roll_number)"
 // @Param limit query int false "Maximum number of results" default(10)
 // @Param business_id query int false "Filter by business ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with search results"
-// @Router /api/students/search [get]
+// @Success 200 {object} dto.Response{data=dto.StudentSearchResult} "Success response with search results"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/search [get]
 func (h *StudentHandler) SearchStudents(c *gin.Context) {
 	searchTerm := c.Query("q")
 	if searchTerm == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Search term is required",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Search term is required"})
 		return
 	}
 
@@ -499,41 +569,51 @@ func (h *StudentHandler) SearchStudents(c *gin.Context) {
 		}
 	}
 
+	search := repository.StudentSearch{
+		Term:        searchTerm,
+		IncludeInfo: c.Query("search_info") == "true",
+		InfoKey:     c.Query("search_info_key"),
+	}
+
 	var students []models.StudentResponse
 	if businessID > 0 {
-		students, err = h.studentService.SearchStudents(searchTerm, limit, businessID)
+		students, err = h.studentService.SearchStudents(c.Request.Context(), search, limit, businessID)
 	} else {
-		students, err = h.studentService.SearchStudents(searchTerm, limit)
+		students, err = h.studentService.SearchStudents(c.Request.Context(), search, limit)
 	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to search students",
-		})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to search students"})
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"students":    students,
-			"search_term": searchTerm,
-			"total_found": len(students),
+	entry := models.AuditLog{Action: models.AuditActionList, Resource: models.AuditResourceStudents, RowCount: int64(len(students))}
+	if businessID > 0 {
+		entry.BusinessID = &businessID
+	}
+	auditDataAccess(c, h.auditService, entry)
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.StudentSearchResult{
+			Students:   students,
+			SearchTerm: searchTerm,
+			TotalFound: len(students),
 		},
 	})
 }
 
 // GetStudentStats godoc
 // @Summary Get student statistics
-// @Description Get student statistics
+// @Description Get student counts by status, grade and gender, and an age histogram in whole years (Admin only)
 // @Tags students
 // @Accept json
 // @Produce json
 // @Param business_id query int false "Filter by business ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with statistics"
-// @Router /api/students/stats [get]
+// @Success 200 {object} dto.Response{data=object} "Success response with statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/stats [get]
 func (h *StudentHandler) GetStudentStats(c *gin.Context) {
 	var businessID uint
 	businessIDParam := c.Query("business_id")
@@ -548,125 +628,142 @@ func (h *StudentHandler) GetStudentStats(c *gin.Context) {
 	var err error
 
 	if businessID > 0 {
-		stats, err = h.studentService.GetStudentStats(businessID)
+		stats, err = h.studentService.GetStudentStats(c.Request.Context(), businessID)
 	} else {
-		stats, err = h.studentService.GetStudentStats()
+		stats, err = h.studentService.GetStudentStats(c.Request.Context())
 	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get student statistics",
-		})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get student statistics"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    stats,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    stats,
 	})
 }
 
 // BulkUpdateStudentStatus godoc
 // @Summary Bulk update student status
-// @Description Update status for multiple students
+// @Description Update status for multiple students. IDs that don't exist are skipped and listed, unless atomic is set, in which case nothing changes unless every ID can be updated. (Admin only)
 // @Tags students
 // @Accept json
 // @Produce json
-// @Param request body map[string]interface{} true "Bulk update data"
+// @Param request body map[string]interface{} true "Bulk update data with student_ids, status and optional atomic"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Router /api/students/bulk/status [post]
+// @Success 200 {object} dto.Response{data=models.BulkUpdateResult} "Every ID was updated"
+// @Success 207 {object} dto.Response{data=models.BulkUpdateResult} "Some IDs were skipped; skipped says why"
+// @Failure 400 {object} dto.ErrorResponse{data=models.BulkUpdateResult} "Bad request, or an atomic request that would have skipped IDs"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/bulk/status [post]
 func (h *StudentHandler) BulkUpdateStudentStatus(c *gin.Context) {
 	var req struct {
-		StudentIDs []uint `json:"student_ids" binding:"required"`
-		Status     int    `json:"status" binding:"required,min=0,max=1"`
+		StudentIDs []uint         `json:"student_ids" binding:"required"`
+		Status     *models.Status `json:"status" binding:"required"`
+		Atomic     bool           `json:"atomic"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	err := h.studentService.BulkUpdateStudentStatus(req.StudentIDs, req.Status)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+	result, err := h.studentService.BulkUpdateStudentStatus(c.Request.Context(), req.StudentIDs, *req.Status, req.Atomic, c.GetUint("user_id"))
+	if err != nil && !errors.Is(err, services.ErrBulkIncomplete) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Student statuses updated successfully",
-	})
+	writeBulkResult(c, result, err, "Student statuses updated")
 }
 
-// GetActiveStudents godoc
-// @Summary Get active students
-// @Description Get all active students
+// BulkDeleteStudents godoc
+// @Summary Bulk delete students
+// @Description Delete several students in one transaction. Every ID must exist and be in the caller's scope (Admin, or Business users for their own business). Students are soft-deleted unless permanent is set; a permanent delete refuses students with dependent records unless cascade is set, and archives students with fee payments instead. data lists what happened to each ID. (Admin/Business only)
 // @Tags students
 // @Accept json
 // @Produce json
+// @Param request body models.BulkDeleteStudentsRequest true "Bulk delete data"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with active students list"
-// @Router /api/students/active [get]
-func (h *StudentHandler) GetActiveStudents(c *gin.Context) {
-	students, err := h.studentService.GetActiveStudents()
+// @Success 200 {object} dto.Response{data=[]models.BulkDeleteResult} "Success response with per-student outcomes"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/bulk/delete [post]
+func (h *StudentHandler) BulkDeleteStudents(c *gin.Context) {
+	var req models.BulkDeleteStudentsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	results, err := h.studentService.BulkDeleteStudents(c.Request.Context(), req, c.GetUint("user_id"), c.GetString("user_role"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get active students",
-		})
+		if errors.Is(err, services.ErrAccessDenied) {
+			c.JSON(http.StatusForbidden, dto.ForbiddenResponse{Error: "Insufficient permissions"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    students,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Bulk delete completed",
+		Data:    results,
 	})
 }
 
+// GetActiveStudents godoc
+// @Summary Get active students
+// @Description Get a page of the active students; a shortcut for /students?status=1 (Admin only)
+// @Tags students
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /students" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.StudentPage{students=[]models.StudentResponse}} "Success response with active students list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/active [get]
+func (h *StudentHandler) GetActiveStudents(c *gin.Context) {
+	h.listStudents(c, statusFilter(true))
+}
+
 // GetInactiveStudents godoc
 // @Summary Get inactive students
-// @Description Get all inactive students
+// @Description Get a page of the inactive students; a shortcut for /students?status=0 (Admin only)
 // @Tags students
 // @Accept json
 // @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /students" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with inactive students list"
-// @Router /api/students/inactive [get]
+// @Success 200 {object} dto.Response{data=dto.StudentPage{students=[]models.StudentResponse}} "Success response with inactive students list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/inactive [get]
 func (h *StudentHandler) GetInactiveStudents(c *gin.Context) {
-	students, err := h.studentService.GetInactiveStudents()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get inactive students",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    students,
-	})
+	h.listStudents(c, statusFilter(false))
 }
 
 // GetGuardianStats godoc
 // @Summary Get guardian statistics
-// @Description Get student guardian statistics
+// @Description Get student guardian statistics: guardian totals, students with guardian email or phone, with several or no guardians, and guardians by relation (Admin only)
 // @Tags students
 // @Accept json
 // @Produce json
 // @Param business_id query int false "Filter by business ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with guardian statistics"
-// @Router /api/students/stats/guardians [get]
+// @Success 200 {object} dto.Response{data=object} "Success response with guardian statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/stats/guardians [get]
 func (h *StudentHandler) GetGuardianStats(c *gin.Context) {
 	var businessID uint
 	businessIDParam := c.Query("business_id")
@@ -681,93 +778,484 @@ func (h *StudentHandler) GetGuardianStats(c *gin.Context) {
 	var err error
 
 	if businessID > 0 {
-		stats, err = h.studentService.GetGuardianStats(businessID)
+		stats, err = h.studentService.GetGuardianStats(c.Request.Context(), businessID)
 	} else {
-		stats, err = h.studentService.GetGuardianStats()
+		stats, err = h.studentService.GetGuardianStats(c.Request.Context())
 	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get guardian statistics",
-		})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get guardian statistics"})
 		return
 	}
+	entry := models.AuditLog{Action: models.AuditActionStats, Resource: models.AuditResourceStudents}
+	if businessID > 0 {
+		entry.BusinessID = &businessID
+	}
+	auditDataAccess(c, h.auditService, entry)
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    stats,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    stats,
 	})
 }
 
 // GetActiveStudentsByBusiness godoc
 // @Summary Get active students by business
-// @Description Get all active students for a specific business
+// @Description Get a page of the active students of a specific business; a shortcut for status=1 (Admin/Business only)
 // @Tags students
 // @Accept json
 // @Produce json
 // @Param businessId path int true "Business ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /students" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with active students list"
-// @Router /api/businesses/{businessId}/students/active [get]
+// @Success 200 {object} dto.Response{data=dto.StudentPage{students=[]models.StudentResponse}} "Success response with active students list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/students/active [get]
 func (h *StudentHandler) GetActiveStudentsByBusiness(c *gin.Context) {
-	businessIDParam := c.Param("businessId")
-	businessID, err := strconv.ParseUint(businessIDParam, 10, 32)
+	businessID, ok := authorizeBusiness(c, h.studentService)
+	if !ok {
+		return
+	}
+	h.listBusinessStudents(c, businessID, statusFilter(true))
+}
+
+// GetInactiveStudentsByBusiness godoc
+// @Summary Get inactive students by business
+// @Description Get a page of the inactive students of a specific business; a shortcut for status=0 (Admin/Business only)
+// @Tags students
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /students" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.StudentPage{students=[]models.StudentResponse}} "Success response with inactive students list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/students/inactive [get]
+func (h *StudentHandler) GetInactiveStudentsByBusiness(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.studentService)
+	if !ok {
+		return
+	}
+	h.listBusinessStudents(c, businessID, statusFilter(false))
+}
+
+// ImportStudents godoc
+// @Summary Import students from CSV
+// @Description Create students and their user accounts from a CSV file with the columns name, email, phone, guardian_name, guardian_phone, guardian_email, guardian_relation, batch (name or ID), enrolled_on and any info.<key> columns for the student's information. Each valid row is created in its own transaction; invalid rows and rows whose name and guardian phone match an existing student are skipped. Generated emails and passwords are returned in the per-row report. (Admin/Business only)
+// @Tags students
+// @Accept multipart/form-data
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param file formData file true "CSV file"
+// @Param dry_run query bool false "Validate only, without creating anything"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.StudentImportReport} "Success response with import report"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/students/import [post]
+func (h *StudentHandler) ImportStudents(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.studentService)
+	if !ok {
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+
+	header, err := c.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid business ID",
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "CSV file is required",
+			Details: err.Error(),
 		})
 		return
 	}
 
-	students, err := h.studentService.GetActiveStudentsByBusiness(uint(businessID))
+	file, err := header.Open()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get active students",
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	report, err := h.studentService.ImportStudents(c.Request.Context(), businessID, file, dryRun)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	message := fmt.Sprintf("Imported %d of %d rows", report.Created, report.TotalRows)
+	if dryRun {
+		message = fmt.Sprintf("%d of %d rows are valid", report.ValidRows, report.TotalRows)
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: message,
+		Data:    report,
+	})
+}
+
+// ExportStudents godoc
+// @Summary Export students to CSV
+// @Description Download the business's students as CSV, honoring the list filters. Columns default to id, name, email, phone, status, batch, grade, gender, date_of_birth, enrolled_on, guardian_name, guardian_phone, guardian_email, guardian_relation and created_on; pick a subset and order with columns. The guardian is the primary guardian. (Admin/Business only)
+// @Tags students
+// @Produce text/csv
+// @Param businessId path int true "Business ID"
+// @Param columns query string false "Comma-separated columns to include"
+// @Param status query string false "Filter by status (0 or inactive, 1 or active)"
+// @Param batch_id query int false "Filter by batch ID"
+// @Param grade query string false "Filter by grade"
+// @Param student_code query string false "Filter by student code, ignoring case"
+// @Param search query string false "Search term"
+// @Security BearerAuth
+// @Success 200 {file} file "CSV file"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/students/export [get]
+func (h *StudentHandler) ExportStudents(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.studentService)
+	if !ok {
+		return
+	}
+
+	var filters repository.StudentFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
 		})
 		return
 	}
+	filters.IncludeDeleted = false // deleted students are not exported
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    students,
+	if err := filters.Validate(); err != nil {
+		respondInvalidSelection(c, err)
+		return
+	}
+
+	columns, err := h.studentService.StudentExportColumns(c.Query("columns"))
+	if err != nil {
+		respondInvalidSelection(c, err)
+		return
+	}
+
+	var rows int64
+	streamCSV(c, exportFileName("students", businessID), "Failed to export students", func(w io.Writer) error {
+		rows, err = h.studentService.ExportStudents(c.Request.Context(), businessID, filters, columns, w)
+		return err
 	})
+	if err == nil {
+		auditDataAccess(c, h.auditService, models.AuditLog{Action: models.AuditActionExport, Resource: models.AuditResourceStudents, BusinessID: &businessID, RowCount: rows})
+	}
 }
 
-// GetInactiveStudentsByBusiness godoc
-// @Summary Get inactive students by business
-// @Description Get all inactive students for a specific business
+// GetStudentCodeSettings godoc
+// @Summary Get student code settings
+// @Description Get the pattern of the codes new students of the business are given, such as SUN-2024-0153: a prefix, optionally the year of enrollment, and a zero-padded sequence. No codes are given out while the prefix is empty. (Admin/Business only)
+// @Tags students
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.StudentCodeSettings} "Success response with student code settings"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business not found"
+// @Router /businesses/{businessId}/students/code-settings [get]
+func (h *StudentHandler) GetStudentCodeSettings(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.studentService)
+	if !ok {
+		return
+	}
+
+	settings, err := h.studentService.GetStudentCodeSettings(c.Request.Context(), businessID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    settings,
+	})
+}
+
+// UpdateStudentCodeSettings godoc
+// @Summary Update student code settings
+// @Description Set the pattern of the codes new students are given, in creation and in CSV imports. Sequences count per year of enrollment when the year is included. Students keep the codes they have when the pattern changes. (Admin/Business only)
 // @Tags students
 // @Accept json
 // @Produce json
 // @Param businessId path int true "Business ID"
+// @Param request body models.UpdateStudentCodeSettingsRequest true "Settings"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with inactive students list"
-// @Router /api/businesses/{businessId}/students/inactive [get]
-func (h *StudentHandler) GetInactiveStudentsByBusiness(c *gin.Context) {
-	businessIDParam := c.Param("businessId")
-	businessID, err := strconv.ParseUint(businessIDParam, 10, 32)
+// @Success 200 {object} dto.Response{data=models.StudentCodeSettings} "Success response with student code settings"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/students/code-settings [put]
+func (h *StudentHandler) UpdateStudentCodeSettings(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.studentService)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateStudentCodeSettingsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	settings, err := h.studentService.UpdateStudentCodeSettings(c.Request.Context(), businessID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid business ID",
-		})
+		if writeFieldError(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	students, err := h.studentService.GetInactiveStudentsByBusiness(uint(businessID))
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Settings updated successfully",
+		Data:    settings,
+	})
+}
+
+// GetMyBusinessStudents godoc
+// @Summary Get my business's students
+// @Description Get the students of the caller's own business, found from their token (Business users only)
+// @Tags students
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param cursor query string false "next_cursor of the previous page, for keyset pagination instead of page; total is skipped. Needs a single sort_by column"
+// @Param with_total query bool false "Count the total; false skips the count query" default(true)
+// @Param estimate query bool false "Estimate the total from table statistics instead of counting, for large tables"
+// @Param status query string false "Filter by status (0 or inactive, 1 or active)"
+// @Param batch_id query int false "Filter by batch ID"
+// @Param grade query string false "Filter by grade"
+// @Param student_code query string false "Filter by student code, ignoring case"
+// @Param gender query string false "Filter by gender (male, female, other)"
+// @Param search query string false "Search term"
+// @Param sort_by query string false "Comma-separated sort columns, as for /students" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.StudentPage{students=[]models.StudentResponse}} "Success response with students list"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/students [get]
+func (h *StudentHandler) GetMyBusinessStudents(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.studentService)
+	if !ok {
+		return
+	}
+	h.listBusinessStudents(c, businessID, nil)
+}
+
+// SearchMyBusinessStudents godoc
+// @Summary Search my business's students
+// @Description Search the students of the caller's own business by name, student code, guardian name, email, or number, and optionally by custom information values (Business users only)
+// @Tags students
+// @Accept json
+// @Produce json
+// @Param q query string true "Search term"
+// @Param search_info query bool false "Also search the values of custom information fields"
+// @Param search_info_key query string false "Only search this custom information field (e.g. roll_number)"
+// @Param limit query int false "Maximum number of results" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.StudentSearchResult} "Success response with search results"
+// @Failure 400 {object} dto.ErrorResponse "Search term is required"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/students/search [get]
+func (h *StudentHandler) SearchMyBusinessStudents(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.studentService)
+	if !ok {
+		return
+	}
+
+	searchTerm := c.Query("q")
+	if searchTerm == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Search term is required"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get inactive students",
-		})
+		limit = 10
+	}
+
+	search := repository.StudentSearch{
+		Term:        searchTerm,
+		IncludeInfo: c.Query("search_info") == "true",
+		InfoKey:     c.Query("search_info_key"),
+	}
+
+	students, err := h.studentService.SearchStudentsByBusiness(c.Request.Context(), businessID, search, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to search students"})
+		return
+	}
+	auditDataAccess(c, h.auditService, models.AuditLog{Action: models.AuditActionList, Resource: models.AuditResourceStudents, BusinessID: &businessID, RowCount: int64(len(students))})
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.StudentSearchResult{
+			Students:   students,
+			SearchTerm: searchTerm,
+			TotalFound: len(students),
+		},
+	})
+}
+
+// GetMyBusinessStudentStats godoc
+// @Summary Get my business's student statistics
+// @Description Get student counts by status, grade and gender, and an age histogram, for the caller's own business (Business users only)
+// @Tags students
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=object} "Success response with statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/students/stats [get]
+func (h *StudentHandler) GetMyBusinessStudentStats(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.studentService)
+	if !ok {
+		return
+	}
+
+	stats, err := h.studentService.GetStudentStats(c.Request.Context(), businessID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get student statistics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+// GetMyBusinessActiveStudents godoc
+// @Summary Get my business's active students
+// @Description Get a page of the active students of the caller's own business; a shortcut for status=1 (Business users only)
+// @Tags students
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /students" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.StudentPage{students=[]models.StudentResponse}} "Success response with active students list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/students/active [get]
+func (h *StudentHandler) GetMyBusinessActiveStudents(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.studentService)
+	if !ok {
+		return
+	}
+	h.listBusinessStudents(c, businessID, statusFilter(true))
+}
+
+// GetMyBusinessInactiveStudents godoc
+// @Summary Get my business's inactive students
+// @Description Get a page of the inactive students of the caller's own business; a shortcut for status=0 (Business users only)
+// @Tags students
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /students" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.StudentPage{students=[]models.StudentResponse}} "Success response with inactive students list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/students/inactive [get]
+func (h *StudentHandler) GetMyBusinessInactiveStudents(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.studentService)
+	if !ok {
+		return
+	}
+	h.listBusinessStudents(c, businessID, statusFilter(false))
+}
+
+// GetRecentStudents godoc
+// @Summary Get recently added students
+// @Description Get the latest students added across all businesses, with their business names, and how many were added in the last 7 and 30 days (Admin only)
+// @Tags students
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of students, at most 50" default(10)
+// @Param business_id query int false "Only this business's students"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.RecentProfiles} "Success response with recent students"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/recent [get]
+func (h *StudentHandler) GetRecentStudents(c *gin.Context) {
+	var businessID uint
+	if id, err := strconv.ParseUint(c.Query("business_id"), 10, 32); err == nil {
+		businessID = uint(id)
+	}
+	h.recentStudents(c, businessID)
+}
+
+// GetMyBusinessRecentStudents godoc
+// @Summary Get my business's recently added students
+// @Description Get the latest students added to the caller's own business and how many were added in the last 7 and 30 days (Business users only)
+// @Tags students
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of students, at most 50" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.RecentProfiles} "Success response with recent students"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/students/recent [get]
+func (h *StudentHandler) GetMyBusinessRecentStudents(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.studentService)
+	if !ok {
+		return
+	}
+	h.recentStudents(c, businessID)
+}
+
+// recentStudents writes the recent students widget, for every business when businessID is 0
+func (h *StudentHandler) recentStudents(c *gin.Context, businessID uint) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		limit = 10
+	}
+
+	recent, err := h.studentService.GetRecentStudents(c.Request.Context(), limit, businessID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get recent students"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    students,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    recent,
 	})
 }
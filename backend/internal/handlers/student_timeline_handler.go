@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StudentTimelineHandler struct {
+	timelineService services.StudentTimelineService
+}
+
+func NewStudentTimelineHandler(timelineService services.StudentTimelineService) *StudentTimelineHandler {
+	return &StudentTimelineHandler{
+		timelineService: timelineService,
+	}
+}
+
+// GetStudentTimeline godoc
+// @Summary Get student timeline
+// @Description Get everything that happened to a student, newest first: creation, status changes, profile edits, batch moves, payments and exam results. Each entry has a type, timestamp, actor, summary and payload. (Admin/Business only)
+// @Tags students
+// @Accept json
+// @Produce json
+// @Param id path int true "Student ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(20)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.TimelinePage} "Success response with timeline entries"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /students/{id}/timeline [get]
+func (h *StudentTimelineHandler) GetStudentTimeline(c *gin.Context) {
+	studentID, ok := authorizeStudent(c, h.timelineService)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	entries, total, err := h.timelineService.GetTimeline(c.Request.Context(), studentID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.TimelinePage{
+			Entries:    entries,
+			OffsetPage: dto.OffsetPage{Total: total, Page: page, Limit: limit},
+		},
+	})
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubjectHandler struct {
+	subjectService services.SubjectService
+}
+
+func NewSubjectHandler(subjectService services.SubjectService) *SubjectHandler {
+	return &SubjectHandler{
+		subjectService: subjectService,
+	}
+}
+
+// CreateSubject godoc
+// @Summary Create a subject
+// @Description Add a subject to a business's subject list (Admin/Business only)
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.CreateSubjectRequest true "Subject data"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.SubjectResponse} "Success response with subject data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/subjects [post]
+func (h *SubjectHandler) CreateSubject(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.subjectService)
+	if !ok {
+		return
+	}
+
+	var req models.CreateSubjectRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	subject, err := h.subjectService.CreateSubject(c.Request.Context(), businessID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Subject created successfully",
+		Data:    subject,
+	})
+}
+
+// GetSubjectsByBusiness godoc
+// @Summary Get subjects by business
+// @Description Get the subject list of a specific business (Admin/Business only)
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.SubjectResponse} "Success response with subjects list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/subjects [get]
+func (h *SubjectHandler) GetSubjectsByBusiness(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.subjectService)
+	if !ok {
+		return
+	}
+
+	subjects, err := h.subjectService.GetSubjectsByBusiness(c.Request.Context(), businessID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get subjects"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    subjects,
+	})
+}
+
+// UpdateSubject godoc
+// @Summary Update a subject
+// @Description Update a subject in a business's subject list (Admin/Business only)
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param subjectId path int true "Subject ID"
+// @Param request body models.UpdateSubjectRequest true "Subject update data"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.SubjectResponse} "Success response with updated subject data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/subjects/{subjectId} [put]
+func (h *SubjectHandler) UpdateSubject(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.subjectService)
+	if !ok {
+		return
+	}
+
+	subjectID, err := strconv.ParseUint(c.Param("subjectId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid subject ID"})
+		return
+	}
+
+	var req models.UpdateSubjectRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	subject, err := h.subjectService.UpdateSubject(c.Request.Context(), businessID, uint(subjectID), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Subject updated successfully",
+		Data:    subject,
+	})
+}
+
+// DeleteSubject godoc
+// @Summary Delete a subject
+// @Description Remove a subject from a business's subject list and unassign it from teachers (Admin/Business only)
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param subjectId path int true "Subject ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/subjects/{subjectId} [delete]
+func (h *SubjectHandler) DeleteSubject(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.subjectService)
+	if !ok {
+		return
+	}
+
+	subjectID, err := strconv.ParseUint(c.Param("subjectId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid subject ID"})
+		return
+	}
+
+	if err := h.subjectService.DeleteSubject(c.Request.Context(), businessID, uint(subjectID)); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Subject deleted successfully",
+	})
+}
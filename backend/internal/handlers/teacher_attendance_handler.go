@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TeacherAttendanceHandler struct {
+	attendanceService services.TeacherAttendanceService
+	teacherService    services.TeacherService
+}
+
+func NewTeacherAttendanceHandler(attendanceService services.TeacherAttendanceService, teacherService services.TeacherService) *TeacherAttendanceHandler {
+	return &TeacherAttendanceHandler{
+		attendanceService: attendanceService,
+		teacherService:    teacherService,
+	}
+}
+
+// MarkTeacherAttendance godoc
+// @Summary Mark teacher attendance
+// @Description Mark a teacher present, absent or on leave for a date; marking the same date again replaces the earlier record (Admin/Business only)
+// @Tags teacher-attendance
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param request body models.MarkTeacherAttendanceRequest true "Attendance data"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.TeacherAttendanceResponse} "Success response with attendance record"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/attendance [post]
+func (h *TeacherAttendanceHandler) MarkTeacherAttendance(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	var req models.MarkTeacherAttendanceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	record, err := h.attendanceService.MarkAttendance(c.Request.Context(), teacherID, req, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Attendance marked successfully",
+		Data:    record,
+	})
+}
+
+// GetTeacherAttendance godoc
+// @Summary Get teacher attendance
+// @Description Get the attendance records of a teacher with optional date range and status filters (Admin/Business only)
+// @Tags teacher-attendance
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param from query string false "From date (YYYY-MM-DD, inclusive)"
+// @Param to query string false "To date (YYYY-MM-DD, inclusive)"
+// @Param status query string false "Status (present, absent, leave)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(31)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.TeacherAttendancePage} "Success response with attendance records"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/attendance [get]
+func (h *TeacherAttendanceHandler) GetTeacherAttendance(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	var filters repository.TeacherAttendanceFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filters.TeacherID = &teacherID
+	filters.BusinessID = nil
+
+	h.respondWithAttendance(c, filters)
+}
+
+// GetTeacherAttendanceSummary godoc
+// @Summary Get teacher monthly attendance summary
+// @Description Get present, absent and leave days and the absence rate of a teacher for a month (Admin/Business only)
+// @Tags teacher-attendance
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param month query string false "Month (YYYY-MM), defaults to the current month"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.TeacherAttendanceSummary} "Success response with attendance summary"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/attendance/summary [get]
+func (h *TeacherAttendanceHandler) GetTeacherAttendanceSummary(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	summary, err := h.attendanceService.GetTeacherMonthlySummary(c.Request.Context(), teacherID, c.Query("month"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    summary,
+	})
+}
+
+// BulkMarkTeacherAttendance godoc
+// @Summary Bulk mark teacher attendance
+// @Description Mark attendance for several teachers of a business on one date (Admin/Business only)
+// @Tags teacher-attendance
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param request body models.BulkTeacherAttendanceRequest true "Attendance entries"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.TeacherAttendanceResponse} "Success response with attendance records"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/teachers/attendance [post]
+func (h *TeacherAttendanceHandler) BulkMarkTeacherAttendance(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	var req models.BulkTeacherAttendanceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	records, err := h.attendanceService.BulkMarkAttendance(c.Request.Context(), businessID, req, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Attendance marked successfully",
+		Data:    records,
+	})
+}
+
+// GetBusinessTeacherAttendance godoc
+// @Summary Get business teacher attendance
+// @Description Get the teacher attendance records of a business with optional teacher, date range and status filters (Admin/Business only)
+// @Tags teacher-attendance
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param teacher_id query int false "Teacher ID"
+// @Param from query string false "From date (YYYY-MM-DD, inclusive)"
+// @Param to query string false "To date (YYYY-MM-DD, inclusive)"
+// @Param status query string false "Status (present, absent, leave)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(31)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.TeacherAttendancePage} "Success response with attendance records"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/teachers/attendance [get]
+func (h *TeacherAttendanceHandler) GetBusinessTeacherAttendance(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	var filters repository.TeacherAttendanceFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filters.BusinessID = &businessID
+
+	h.respondWithAttendance(c, filters)
+}
+
+// GetBusinessAttendanceSummary godoc
+// @Summary Get business monthly teacher attendance summary
+// @Description Get per-teacher and overall present, absent and leave days and absence rates of a business for a month (Admin/Business only)
+// @Tags teacher-attendance
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param month query string false "Month (YYYY-MM), defaults to the current month"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.BusinessAttendanceSummary} "Success response with attendance summary"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/teachers/attendance/summary [get]
+func (h *TeacherAttendanceHandler) GetBusinessAttendanceSummary(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	summary, err := h.attendanceService.GetBusinessMonthlySummary(c.Request.Context(), businessID, c.Query("month"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    summary,
+	})
+}
+
+func (h *TeacherAttendanceHandler) respondWithAttendance(c *gin.Context, filters repository.TeacherAttendanceFilters) {
+	records, total, err := h.attendanceService.GetAttendance(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.TeacherAttendancePage{
+			Attendance: records,
+			OffsetPage: dto.OffsetPage{Total: total, Page: filters.Page, Limit: filters.Limit},
+		},
+	})
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TeacherDocumentHandler struct {
+	documentService services.TeacherDocumentService
+	teacherService  services.TeacherService
+}
+
+func NewTeacherDocumentHandler(documentService services.TeacherDocumentService, teacherService services.TeacherService) *TeacherDocumentHandler {
+	return &TeacherDocumentHandler{
+		documentService: documentService,
+		teacherService:  teacherService,
+	}
+}
+
+// UploadTeacherDocument godoc
+// @Summary Upload teacher document
+// @Description Attach a certificate, contract or other document (PDF, JPEG or PNG, max 10 MB) to a teacher (Admin/Business only)
+// @Tags teacher-documents
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param file formData file true "Document file"
+// @Param document_type formData string false "Document type (certificate, contract, other)"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.TeacherDocumentResponse} "Success response with document data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/documents [post]
+func (h *TeacherDocumentHandler) UploadTeacherDocument(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "File is required",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	document, err := h.documentService.UploadDocument(c.Request.Context(), teacherID, c.PostForm("document_type"), file, header, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Document uploaded successfully",
+		Data:    document,
+	})
+}
+
+// GetTeacherDocuments godoc
+// @Summary Get teacher documents
+// @Description Get the documents attached to a teacher (Admin/Business only)
+// @Tags teacher-documents
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.TeacherDocumentResponse} "Success response with documents list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/documents [get]
+func (h *TeacherDocumentHandler) GetTeacherDocuments(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	documents, err := h.documentService.GetDocuments(c.Request.Context(), teacherID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get documents"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    documents,
+	})
+}
+
+// DeleteTeacherDocument godoc
+// @Summary Delete teacher document
+// @Description Remove a document from a teacher (Admin/Business only)
+// @Tags teacher-documents
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param documentId path int true "Document ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/documents/{documentId} [delete]
+func (h *TeacherDocumentHandler) DeleteTeacherDocument(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	documentID, err := strconv.ParseUint(c.Param("documentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid document ID"})
+		return
+	}
+
+	if err := h.documentService.DeleteDocument(c.Request.Context(), teacherID, uint(documentID)); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Document deleted successfully",
+	})
+}
+
+// GetMyTeacherDocuments godoc
+// @Summary Get my teacher documents
+// @Description Get the documents attached to the current user's teacher profile (Teacher users only)
+// @Tags teacher-profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.TeacherDocumentResponse} "Success response with documents list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Teacher profile not found"
+// @Router /my-teacher-profile/documents [get]
+func (h *TeacherDocumentHandler) GetMyTeacherDocuments(c *gin.Context) {
+	teacherID, ok := myTeacherID(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	documents, err := h.documentService.GetDocuments(c.Request.Context(), teacherID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get documents"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    documents,
+	})
+}
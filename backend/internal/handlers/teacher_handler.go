@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"backend/internal/dto"
 	"backend/internal/models"
 	"backend/internal/repository"
 	"backend/internal/services"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -12,51 +15,45 @@ import (
 
 type TeacherHandler struct {
 	teacherService services.TeacherService
+	auditService   services.AuditService
 }
 
-func NewTeacherHandler(teacherService services.TeacherService) *TeacherHandler {
+func NewTeacherHandler(teacherService services.TeacherService, auditService services.AuditService) *TeacherHandler {
 	return &TeacherHandler{
 		teacherService: teacherService,
+		auditService:   auditService,
 	}
 }
 
 // CreateTeacher godoc
 // @Summary Create a new teacher
-// @Description Create a new teacher (Admin/Business only)
+// @Description Create a new teacher (Admin only)
 // @Tags teachers
 // @Accept json
 // @Produce json
 // @Param request body models.CreateTeacherRequest true "Teacher data"
 // @Security BearerAuth
-// @Success 201 {object} map[string]interface{} "Success response with teacher data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/teachers [post]
+// @Success 201 {object} dto.Response{data=models.TeacherResponse} "Success response with teacher data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers [post]
 func (h *TeacherHandler) CreateTeacher(c *gin.Context) {
 	var req models.CreateTeacherRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	teacher, err := h.teacherService.CreateTeacher(req)
+	teacher, err := h.teacherService.CreateTeacher(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"success": true,
-		"message": "Teacher created successfully",
-		"data":    teacher,
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Teacher created successfully",
+		Data:    teacher,
 	})
 }
 
@@ -68,82 +65,109 @@ func (h *TeacherHandler) CreateTeacher(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
-// @Param status query int false "Filter by status (0=inactive, 1=active)"
+// @Param cursor query string false "next_cursor of the previous page, for keyset pagination instead of page; total is skipped. Needs a single sort_by column"
+// @Param with_total query bool false "Count the total; false skips the count query" default(true)
+// @Param estimate query bool false "Estimate the total from table statistics instead of counting, for large tables"
+// @Param status query string false "Filter by status (0 or inactive, 1 or active)"
 // @Param business_id query int false "Filter by business ID"
 // @Param min_salary query number false "Filter by minimum salary"
 // @Param max_salary query number false "Filter by maximum salary"
-// @Param qualification query string false "Filter by qualification"
-// @Param search query string false "Search in name, qualification, or experience"
-// @Param sort_by query string false "Sort by field"
-// @Param sort_order query string false "Sort order (asc, desc)" default(desc)
+// @Param qualification query string false "Filter by qualification name"
+// @Param qualification_id query int false "Filter by qualification entry ID"
+// @Param subject_id query int false "Filter by assigned subject ID"
+// @Param subject query string false "Filter by assigned subject name"
+// @Param min_experience_years query number false "Filter by minimum years of experience"
+// @Param max_experience_years query number false "Filter by maximum years of experience"
+// @Param search query string false "Search in name or qualification"
+// @Param sort_by query string false "Comma-separated sort columns (name, salary, qualification, experience_years, status, last_salary_change_at, created_on, updated_on), e.g. status,salary" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Param fields query string false "Comma-separated response fields to return, e.g. id,name"
+// @Param include query string false "Comma-separated relations to load (user, business, previous_business, subjects); defaults to those named in fields, or all"
+// @Param include_deleted query bool false "Also list soft-deleted teachers, which carry deleted_at"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with teachers list"
-// @Router /api/teachers [get]
+// @Success 200 {object} dto.Response{data=dto.TeacherPage{teachers=[]models.TeacherResponse}} "Success response with teachers list"
+// @Failure 400 {object} dto.ErrorResponse "Unknown sort column or filter value; valid_options lists the accepted ones"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers [get]
 func (h *TeacherHandler) GetTeachers(c *gin.Context) {
+	h.listTeachers(c, nil)
+}
+
+// listTeachers writes a page of teachers matching the query filters, with the status
+// filter fixed when the active and inactive shortcuts give one
+func (h *TeacherHandler) listTeachers(c *gin.Context, status *models.Status) {
 	var filters repository.TeacherFilters
 	if err := c.ShouldBindQuery(&filters); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid query parameters",
-			"details": err.Error(),
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
 		})
 		return
 	}
+	if status != nil {
+		filters.Status = status
+	}
+
+	if err := filters.Validate(); err != nil {
+		respondInvalidSelection(c, err)
+		return
+	}
 
-	teachers, total, err := h.teacherService.GetTeachers(filters)
+	selection, err := filters.Selection()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get teachers",
-		})
+		respondInvalidSelection(c, err)
+		return
+	}
+
+	teachers, pageInfo, err := h.teacherService.GetTeachers(c.Request.Context(), filters)
+	if err != nil {
+		if respondInvalidCursor(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get teachers"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"teachers": teachers,
-			"total":    total,
-			"page":     filters.Page,
-			"limit":    filters.Limit,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.TeacherPage{
+			Teachers:   selectFields(teachers, selection.Fields),
+			CursorPage: dto.NewCursorPage(pageInfo),
 		},
 	})
 }
 
 // GetTeacher godoc
 // @Summary Get teacher by ID
-// @Description Get a specific teacher by ID
+// @Description Get a specific teacher by ID (Admin only)
 // @Tags teachers
 // @Accept json
 // @Produce json
 // @Param id path int true "Teacher ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with teacher data"
-// @Failure 404 {object} map[string]string "Teacher not found"
-// @Router /api/teachers/{id} [get]
+// @Success 200 {object} dto.Response{data=models.TeacherResponse} "Success response with teacher data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Teacher not found"
+// @Router /teachers/{id} [get]
 func (h *TeacherHandler) GetTeacher(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid teacher ID",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid teacher ID"})
 		return
 	}
 
-	teacher, err := h.teacherService.GetTeacherByID(uint(id))
+	teacher, err := h.teacherService.GetTeacherByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"error":   "Teacher not found",
-		})
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Teacher not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    teacher,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    teacher,
 	})
 }
 
@@ -154,68 +178,65 @@ func (h *TeacherHandler) GetTeacher(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with teacher data"
-// @Failure 404 {object} map[string]string "Teacher profile not found"
-// @Router /api/my-teacher-profile [get]
+// @Success 200 {object} dto.Response{data=models.TeacherResponse} "Success response with teacher data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Teacher profile not found"
+// @Router /my-teacher-profile [get]
 func (h *TeacherHandler) GetMyTeacherProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   "User not authenticated",
-		})
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: "User not authenticated"})
 		return
 	}
 
-	teacher, err := h.teacherService.GetTeacherByUserID(userID.(uint))
+	teacher, err := h.teacherService.GetTeacherByUserID(c.Request.Context(), userID.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"error":   "Teacher profile not found",
-		})
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Teacher profile not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    teacher,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    teacher,
 	})
 }
 
 // UpdateTeacher godoc
 // @Summary Update teacher
-// @Description Update teacher information
+// @Description Update teacher information (Admin only)
 // @Tags teachers
 // @Accept json
 // @Produce json
 // @Param id path int true "Teacher ID"
 // @Param request body models.UpdateTeacherRequest true "Teacher update data"
+// @Param If-Match header string false "Version the update is based on, when not sent as the version field"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with updated teacher data"
-// @Router /api/teachers/{id} [put]
+// @Success 200 {object} dto.Response{data=models.TeacherResponse} "Success response with updated teacher data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 409 {object} dto.ErrorResponse "Record was changed since it was read; data holds the current record"
+// @Failure 428 {object} dto.ErrorResponse "No record version sent"
+// @Router /teachers/{id} [put]
 func (h *TeacherHandler) UpdateTeacher(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid teacher ID",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid teacher ID"})
 		return
 	}
 
 	var req models.UpdateTeacherRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
+		return
+	}
+	version, ok := requestVersion(c, req.Version)
+	if !ok {
 		return
 	}
 
 	// Convert to map for updates
-	updates := make(map[string]interface{})
+	updates := map[string]interface{}{"version": version}
 	if req.Name != "" {
 		updates["name"] = req.Name
 	}
@@ -225,260 +246,287 @@ func (h *TeacherHandler) UpdateTeacher(c *gin.Context) {
 	if req.Qualification != "" {
 		updates["qualification"] = req.Qualification
 	}
+	if req.QualificationID != nil {
+		updates["qualification_id"] = *req.QualificationID
+	}
 	if req.Experience != "" {
 		updates["experience"] = req.Experience
 	}
+	if req.ExperienceYears != nil {
+		updates["experience_years"] = *req.ExperienceYears
+	}
 	if req.Description != "" {
 		updates["description"] = req.Description
 	}
 	if req.Status != nil {
 		updates["status"] = *req.Status
 	}
+	if req.SalaryReason != "" {
+		updates["salary_reason"] = req.SalaryReason
+	}
 
-	updatedTeacher, err := h.teacherService.UpdateTeacher(uint(id), updates)
+	updatedTeacher, err := h.teacherService.UpdateTeacher(c.Request.Context(), uint(id), updates, c.GetUint("user_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		if writeVersionConflict(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Teacher updated successfully",
-		"data":    updatedTeacher,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Teacher updated successfully",
+		Data:    updatedTeacher,
 	})
 }
 
 // UpdateMyTeacherProfile godoc
 // @Summary Update my teacher profile
-// @Description Update current user's teacher profile (Teacher users only)
+// @Description Update current user's teacher profile (Teacher users only). Only name, qualification, experience and description can be changed; salary and status are managed by the business.
 // @Tags teacher-profile
 // @Accept json
 // @Produce json
-// @Param request body models.UpdateTeacherRequest true "Teacher update data"
+// @Param request body models.UpdateTeacherSelfRequest true "Teacher profile data"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with updated teacher data"
-// @Router /api/my-teacher-profile [put]
+// @Success 200 {object} dto.Response{data=models.TeacherResponse} "Success response with updated teacher data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /my-teacher-profile [put]
 func (h *TeacherHandler) UpdateMyTeacherProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   "User not authenticated",
-		})
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: "User not authenticated"})
 		return
 	}
 
-	// Get teacher by user ID first
-	teacher, err := h.teacherService.GetTeacherByUserID(userID.(uint))
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"error":   "Teacher profile not found",
-		})
+	var req models.UpdateTeacherSelfRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	var req models.UpdateTeacherRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	updatedTeacher, err := h.teacherService.UpdateTeacherSelf(c.Request.Context(), userID.(uint), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	// Convert to map for updates
-	updates := make(map[string]interface{})
-	if req.Name != "" {
-		updates["name"] = req.Name
-	}
-	if req.Qualification != "" {
-		updates["qualification"] = req.Qualification
-	}
-	if req.Experience != "" {
-		updates["experience"] = req.Experience
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Teacher profile updated successfully",
+		Data:    updatedTeacher,
+	})
+}
+
+// DeleteTeacher godoc
+// @Summary Delete teacher
+// @Description Delete a teacher (Admin only). The teacher is soft-deleted: hidden from lists, searches and stats with its
+// @Description history kept, and restorable until purged after the retention period. The linked user account is deactivated meanwhile.
+// @Description With permanent the teacher is removed right away, and the user account is deleted when delete_user is set;
+// @Description a permanent delete is refused while attendance or documents exist unless cascade is set.
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param permanent query bool false "Delete permanently instead of soft-deleting; also applies to a soft-deleted teacher"
+// @Param delete_user query bool false "With permanent, delete the linked user account instead of deactivating it"
+// @Param cascade query bool false "With permanent, also delete attendance records and documents"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id} [delete]
+func (h *TeacherHandler) DeleteTeacher(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid teacher ID"})
+		return
 	}
-	if req.Description != "" {
-		updates["description"] = req.Description
+
+	var opts models.DeleteProfileOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
 	}
 
-	updatedTeacher, err := h.teacherService.UpdateTeacher(teacher.ID, updates)
+	err = h.teacherService.DeleteTeacher(c.Request.Context(), uint(id), opts)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Teacher profile updated successfully",
-		"data":    updatedTeacher,
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Teacher deleted successfully",
 	})
 }
 
-// DeleteTeacher godoc
-// @Summary Delete teacher
-// @Description Delete a teacher (Admin only)
+// RestoreTeacher godoc
+// @Summary Restore teacher
+// @Description Restore a soft-deleted teacher (Admin only). The linked user account is reactivated when the teacher is active.
 // @Tags teachers
 // @Accept json
 // @Produce json
 // @Param id path int true "Teacher ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Router /api/teachers/{id} [delete]
-func (h *TeacherHandler) DeleteTeacher(c *gin.Context) {
+// @Success 200 {object} dto.Response{data=models.TeacherResponse} "Success response with the restored teacher"
+// @Failure 400 {object} dto.ErrorResponse "Teacher is not deleted"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Teacher not found"
+// @Router /teachers/{id}/restore [post]
+func (h *TeacherHandler) RestoreTeacher(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid teacher ID",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid teacher ID"})
 		return
 	}
 
-	err = h.teacherService.DeleteTeacher(uint(id))
+	teacher, err := h.teacherService.RestoreTeacher(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		if err.Error() == "teacher not found" {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Teacher not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Teacher deleted successfully",
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Teacher restored successfully",
+		Data:    teacher,
 	})
 }
 
 // GetTeachersByBusiness godoc
 // @Summary Get teachers by business
-// @Description Get all teachers for a specific business
+// @Description Get all teachers for a specific business (Admin/Business only)
 // @Tags teachers
 // @Accept json
 // @Produce json
 // @Param businessId path int true "Business ID"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param cursor query string false "next_cursor of the previous page, for keyset pagination instead of page; total is skipped. Needs a single sort_by column"
+// @Param with_total query bool false "Count the total; false skips the count query" default(true)
+// @Param estimate query bool false "Estimate the total from table statistics instead of counting, for large tables"
+// @Param subject_id query int false "Filter by assigned subject ID"
+// @Param subject query string false "Filter by assigned subject name"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with teachers list"
-// @Router /api/businesses/{businessId}/teachers [get]
+// @Success 200 {object} dto.Response{data=dto.TeacherPage{teachers=[]models.TeacherResponse}} "Success response with teachers list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/teachers [get]
 func (h *TeacherHandler) GetTeachersByBusiness(c *gin.Context) {
-	businessIDParam := c.Param("businessId")
-	businessID, err := strconv.ParseUint(businessIDParam, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid business ID",
-		})
+	businessID, ok := authorizeBusiness(c, h.teacherService)
+	if !ok {
 		return
 	}
+	h.listBusinessTeachers(c, businessID, nil)
+}
 
+// listBusinessTeachers writes a page of the business's teachers, read with the list filters
+func (h *TeacherHandler) listBusinessTeachers(c *gin.Context, businessID uint, status *models.Status) {
 	var filters repository.TeacherFilters
 	if err := c.ShouldBindQuery(&filters); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid query parameters",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid query parameters"})
+		return
+	}
+	if status != nil {
+		filters.Status = status
+	}
+	filters.IncludeDeleted = false // only the admin listing shows deleted teachers
+
+	if err := filters.Validate(); err != nil {
+		respondInvalidSelection(c, err)
 		return
 	}
 
-	teachers, total, err := h.teacherService.GetTeachersByBusiness(uint(businessID), filters)
+	teachers, pageInfo, err := h.teacherService.GetTeachersByBusiness(c.Request.Context(), businessID, filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get teachers",
-		})
+		if respondInvalidCursor(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get teachers"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"teachers": teachers,
-			"total":    total,
-			"page":     filters.Page,
-			"limit":    filters.Limit,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.TeacherPage{
+			Teachers:   teachers,
+			CursorPage: dto.NewCursorPage(pageInfo),
 		},
 	})
 }
 
 // ChangeTeacherStatus godoc
 // @Summary Change teacher status
-// @Description Change the status of a teacher
+// @Description Change the status of a teacher (Admin only)
 // @Tags teachers
 // @Accept json
 // @Produce json
 // @Param id path int true "Teacher ID"
 // @Param request body map[string]int true "Status data"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Router /api/teachers/{id}/status [patch]
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/status [patch]
 func (h *TeacherHandler) ChangeTeacherStatus(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid teacher ID",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid teacher ID"})
 		return
 	}
 
 	var req struct {
-		Status int `json:"status" binding:"required,min=0,max=1"`
+		Status *models.Status `json:"status" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	err = h.teacherService.ChangeTeacherStatus(uint(id), req.Status)
+	err = h.teacherService.ChangeTeacherStatus(c.Request.Context(), uint(id), *req.Status)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Teacher status updated successfully",
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Teacher status updated successfully",
 	})
 }
 
 // SearchTeachers godoc
 // @Summary Search teachers
-// @Description Search teachers by name, qualification, or experience
+// @Description Search teachers by name or qualification (Admin only)
 // @Tags teachers
 // @Accept json
 // @Produce json
 // @Param q query string true "Search term"
 // @Param limit query int false "Maximum number of results" default(10)
 // @Param business_id query int false "Filter by business ID"
+// @Param subject_id query int false "Filter by assigned subject ID"
+// @Param subject query string false "Filter by assigned subject name"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with search results"
-// @Router /api/teachers/search [get]
+// @Success 200 {object} dto.Response{data=dto.TeacherSearchResult} "Success response with search results"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/search [get]
 func (h *TeacherHandler) SearchTeachers(c *gin.Context) {
 	searchTerm := c.Query("q")
 	if searchTerm == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Search term is required",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Search term is required"})
 		return
 	}
 
@@ -488,50 +536,48 @@ func (h *TeacherHandler) SearchTeachers(c *gin.Context) {
 		limit = 10
 	}
 
-	var businessID uint
-	businessIDParam := c.Query("business_id")
-	if businessIDParam != "" {
-		id, err := strconv.ParseUint(businessIDParam, 10, 32)
-		if err == nil {
-			businessID = uint(id)
-		}
+	var filters repository.TeacherFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
 	}
 
-	var teachers []models.TeacherResponse
-	if businessID > 0 {
-		teachers, err = h.teacherService.SearchTeachers(searchTerm, limit, businessID)
-	} else {
-		teachers, err = h.teacherService.SearchTeachers(searchTerm, limit)
+	if err := filters.Validate(); err != nil {
+		respondInvalidSelection(c, err)
+		return
 	}
 
+	teachers, err := h.teacherService.SearchTeachers(c.Request.Context(), searchTerm, limit, filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to search teachers",
-		})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to search teachers"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"teachers":    teachers,
-			"search_term": searchTerm,
-			"total_found": len(teachers),
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.TeacherSearchResult{
+			Teachers:   teachers,
+			SearchTerm: searchTerm,
+			TotalFound: len(teachers),
 		},
 	})
 }
 
 // GetTeacherStats godoc
 // @Summary Get teacher statistics
-// @Description Get teacher statistics
+// @Description Get teacher statistics (Admin only)
 // @Tags teachers
 // @Accept json
 // @Produce json
 // @Param business_id query int false "Filter by business ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with statistics"
-// @Router /api/teachers/stats [get]
+// @Success 200 {object} dto.Response{data=object} "Success response with statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/stats [get]
 func (h *TeacherHandler) GetTeacherStats(c *gin.Context) {
 	var businessID uint
 	businessIDParam := c.Query("business_id")
@@ -546,165 +592,173 @@ func (h *TeacherHandler) GetTeacherStats(c *gin.Context) {
 	var err error
 
 	if businessID > 0 {
-		stats, err = h.teacherService.GetTeacherStats(businessID)
+		stats, err = h.teacherService.GetTeacherStats(c.Request.Context(), businessID)
 	} else {
-		stats, err = h.teacherService.GetTeacherStats()
+		stats, err = h.teacherService.GetTeacherStats(c.Request.Context())
 	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get teacher statistics",
-		})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get teacher statistics"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    stats,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    stats,
 	})
 }
 
 // BulkUpdateTeacherStatus godoc
 // @Summary Bulk update teacher status
-// @Description Update status for multiple teachers
+// @Description Update status for multiple teachers. IDs that don't exist are skipped and listed, unless atomic is set, in which case nothing changes unless every ID can be updated. (Admin only)
 // @Tags teachers
 // @Accept json
 // @Produce json
-// @Param request body map[string]interface{} true "Bulk update data"
+// @Param request body map[string]interface{} true "Bulk update data with teacher_ids, status and optional atomic"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Router /api/teachers/bulk/status [post]
+// @Success 200 {object} dto.Response{data=models.BulkUpdateResult} "Every ID was updated"
+// @Success 207 {object} dto.Response{data=models.BulkUpdateResult} "Some IDs were skipped; skipped says why"
+// @Failure 400 {object} dto.ErrorResponse{data=models.BulkUpdateResult} "Bad request, or an atomic request that would have skipped IDs"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/bulk/status [post]
 func (h *TeacherHandler) BulkUpdateTeacherStatus(c *gin.Context) {
 	var req struct {
-		TeacherIDs []uint `json:"teacher_ids" binding:"required"`
-		Status     int    `json:"status" binding:"required,min=0,max=1"`
+		TeacherIDs []uint         `json:"teacher_ids" binding:"required"`
+		Status     *models.Status `json:"status" binding:"required"`
+		Atomic     bool           `json:"atomic"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	err := h.teacherService.BulkUpdateTeacherStatus(req.TeacherIDs, req.Status)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+	result, err := h.teacherService.BulkUpdateTeacherStatus(c.Request.Context(), req.TeacherIDs, *req.Status, req.Atomic)
+	if err != nil && !errors.Is(err, services.ErrBulkIncomplete) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Teacher statuses updated successfully",
-	})
+	writeBulkResult(c, result, err, "Teacher statuses updated")
 }
 
 // BulkUpdateSalary godoc
 // @Summary Bulk update teacher salary
-// @Description Update salary for multiple teachers
+// @Description Set, or increase by a percentage or fixed amount, the salary of multiple teachers, recording an optional reason in each teacher's salary history. salary is still accepted in place of value from older clients. (Admin only)
 // @Tags teachers
 // @Accept json
 // @Produce json
-// @Param request body map[string]interface{} true "Bulk salary update data"
+// @Param request body models.BulkUpdateSalaryRequest true "Bulk salary update data"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Router /api/teachers/bulk/salary [post]
+// @Success 200 {object} dto.Response{data=[]models.SalaryAdjustmentResult} "Success response with per-teacher before/after salaries"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/bulk/salary [post]
 func (h *TeacherHandler) BulkUpdateSalary(c *gin.Context) {
-	var req struct {
-		TeacherIDs []uint  `json:"teacher_ids" binding:"required"`
-		Salary     float64 `json:"salary" binding:"required,min=0"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	var req models.BulkUpdateSalaryRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	err := h.teacherService.BulkUpdateSalary(req.TeacherIDs, req.Salary)
+	results, err := h.teacherService.BulkUpdateSalary(c.Request.Context(), req, c.GetUint("user_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Teacher salaries updated successfully",
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Teacher salaries updated successfully",
+		Data:    results,
 	})
 }
 
-// GetActiveTeachers godoc
-// @Summary Get active teachers
-// @Description Get all active teachers
+// BulkDeleteTeachers godoc
+// @Summary Bulk delete teachers
+// @Description Delete several teachers in one transaction. Every ID must exist and be in the caller's scope (Admin, or Business users for their own business). Teachers are soft-deleted unless permanent is set; a permanent delete refuses teachers with dependent records unless cascade is set. data lists what happened to each ID. (Admin/Business only)
 // @Tags teachers
 // @Accept json
 // @Produce json
+// @Param request body models.BulkDeleteTeachersRequest true "Bulk delete data"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with active teachers list"
-// @Router /api/teachers/active [get]
-func (h *TeacherHandler) GetActiveTeachers(c *gin.Context) {
-	teachers, err := h.teacherService.GetActiveTeachers()
+// @Success 200 {object} dto.Response{data=[]models.BulkDeleteResult} "Success response with per-teacher outcomes"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/bulk/delete [post]
+func (h *TeacherHandler) BulkDeleteTeachers(c *gin.Context) {
+	var req models.BulkDeleteTeachersRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	results, err := h.teacherService.BulkDeleteTeachers(c.Request.Context(), req, c.GetUint("user_id"), c.GetString("user_role"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get active teachers",
-		})
+		if errors.Is(err, services.ErrAccessDenied) {
+			c.JSON(http.StatusForbidden, dto.ForbiddenResponse{Error: "Insufficient permissions"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    teachers,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Bulk delete completed",
+		Data:    results,
 	})
 }
 
+// GetActiveTeachers godoc
+// @Summary Get active teachers
+// @Description Get a page of the active teachers; a shortcut for /teachers?status=1 (Admin only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /teachers" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.TeacherPage{teachers=[]models.TeacherResponse}} "Success response with active teachers list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/active [get]
+func (h *TeacherHandler) GetActiveTeachers(c *gin.Context) {
+	h.listTeachers(c, statusFilter(true))
+}
+
 // GetInactiveTeachers godoc
 // @Summary Get inactive teachers
-// @Description Get all inactive teachers
+// @Description Get a page of the inactive teachers; a shortcut for /teachers?status=0 (Admin only)
 // @Tags teachers
 // @Accept json
 // @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /teachers" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with inactive teachers list"
-// @Router /api/teachers/inactive [get]
+// @Success 200 {object} dto.Response{data=dto.TeacherPage{teachers=[]models.TeacherResponse}} "Success response with inactive teachers list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/inactive [get]
 func (h *TeacherHandler) GetInactiveTeachers(c *gin.Context) {
-	teachers, err := h.teacherService.GetInactiveTeachers()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get inactive teachers",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    teachers,
-	})
+	h.listTeachers(c, statusFilter(false))
 }
 
 // GetSalaryStats godoc
 // @Summary Get salary statistics
-// @Description Get teacher salary statistics
+// @Description Get teacher salary statistics: min, max, average, median and quartiles, a histogram of salary buckets and the average salary per qualification (Admin only)
 // @Tags teachers
 // @Accept json
 // @Produce json
 // @Param business_id query int false "Filter by business ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with salary statistics"
-// @Router /api/teachers/stats/salary [get]
+// @Success 200 {object} dto.Response{data=object} "Success response with salary statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/stats/salary [get]
 func (h *TeacherHandler) GetSalaryStats(c *gin.Context) {
 	var businessID uint
 	businessIDParam := c.Query("business_id")
@@ -719,35 +773,34 @@ func (h *TeacherHandler) GetSalaryStats(c *gin.Context) {
 	var err error
 
 	if businessID > 0 {
-		stats, err = h.teacherService.GetSalaryStats(businessID)
+		stats, err = h.teacherService.GetSalaryStats(c.Request.Context(), businessID)
 	} else {
-		stats, err = h.teacherService.GetSalaryStats()
+		stats, err = h.teacherService.GetSalaryStats(c.Request.Context())
 	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get salary statistics",
-		})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get salary statistics"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    stats,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    stats,
 	})
 }
 
 // GetQualificationStats godoc
 // @Summary Get qualification statistics
-// @Description Get teacher qualification statistics
+// @Description Get teacher qualification statistics (Admin only)
 // @Tags teachers
 // @Accept json
 // @Produce json
 // @Param business_id query int false "Filter by business ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with qualification statistics"
-// @Router /api/teachers/stats/qualifications [get]
+// @Success 200 {object} dto.Response{data=map[string]int64} "Success response with qualification statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/stats/qualifications [get]
 func (h *TeacherHandler) GetQualificationStats(c *gin.Context) {
 	var businessID uint
 	businessIDParam := c.Query("business_id")
@@ -762,21 +815,853 @@ func (h *TeacherHandler) GetQualificationStats(c *gin.Context) {
 	var err error
 
 	if businessID > 0 {
-		stats, err = h.teacherService.GetQualificationStats(businessID)
+		stats, err = h.teacherService.GetQualificationStats(c.Request.Context(), businessID)
 	} else {
-		stats, err = h.teacherService.GetQualificationStats()
+		stats, err = h.teacherService.GetQualificationStats(c.Request.Context())
 	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to get qualification statistics",
-		})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get qualification statistics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+// GetExperienceStats godoc
+// @Summary Get experience statistics
+// @Description Get the average, range and distribution of teachers' years of experience (Admin only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param business_id query int false "Filter by business ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=object} "Success response with experience statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/stats/experience [get]
+func (h *TeacherHandler) GetExperienceStats(c *gin.Context) {
+	var businessID uint
+	businessIDParam := c.Query("business_id")
+	if businessIDParam != "" {
+		id, err := strconv.ParseUint(businessIDParam, 10, 32)
+		if err == nil {
+			businessID = uint(id)
+		}
+	}
+
+	var stats map[string]interface{}
+	var err error
+
+	if businessID > 0 {
+		stats, err = h.teacherService.GetExperienceStats(c.Request.Context(), businessID)
+	} else {
+		stats, err = h.teacherService.GetExperienceStats(c.Request.Context())
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get experience statistics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+// GetSubjectStats godoc
+// @Summary Get subject statistics
+// @Description Get the number of teachers per subject, grouped by business (Admin only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param business_id query int false "Filter by business ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=map[string]map[string]int64} "Success response with subject statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/stats/subjects [get]
+func (h *TeacherHandler) GetSubjectStats(c *gin.Context) {
+	var businessID uint
+	businessIDParam := c.Query("business_id")
+	if businessIDParam != "" {
+		id, err := strconv.ParseUint(businessIDParam, 10, 32)
+		if err == nil {
+			businessID = uint(id)
+		}
+	}
+
+	var stats map[uint]map[string]int64
+	var err error
+
+	if businessID > 0 {
+		stats, err = h.teacherService.GetSubjectStats(c.Request.Context(), businessID)
+	} else {
+		stats, err = h.teacherService.GetSubjectStats(c.Request.Context())
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get subject statistics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+// AssignTeacherSubjects godoc
+// @Summary Assign subjects to teacher
+// @Description Replace the subjects taught by a teacher with subjects from the teacher's business (Admin only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param request body models.AssignSubjectsRequest true "Subject IDs"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.TeacherResponse} "Success response with updated teacher data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/subjects [put]
+func (h *TeacherHandler) AssignTeacherSubjects(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid teacher ID"})
+		return
+	}
+
+	var req models.AssignSubjectsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	teacher, err := h.teacherService.AssignSubjects(c.Request.Context(), uint(id), req.SubjectIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Teacher subjects updated successfully",
+		Data:    teacher,
+	})
+}
+
+// GetTeacherSalaryHistory godoc
+// @Summary Get teacher salary history
+// @Description Get every recorded salary change for a teacher, newest first (Admin only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.TeacherSalaryHistoryResponse} "Success response with salary history"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Teacher not found"
+// @Router /teachers/{id}/salary-history [get]
+func (h *TeacherHandler) GetTeacherSalaryHistory(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid teacher ID"})
+		return
+	}
+
+	history, err := h.teacherService.GetSalaryHistory(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    history,
+	})
+}
+
+// GetTeacherAvailability godoc
+// @Summary Get teacher availability
+// @Description Get the weekly availability schedule of a teacher (Admin/Business only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.TeacherAvailabilityResponse} "Success response with availability slots"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/availability [get]
+func (h *TeacherHandler) GetTeacherAvailability(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	availability, err := h.teacherService.GetAvailability(c.Request.Context(), teacherID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get availability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    availability,
+	})
+}
+
+// AddTeacherAvailability godoc
+// @Summary Add teacher availability
+// @Description Add a weekly availability slot for a teacher; slots on the same weekday may not overlap (Admin/Business only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param request body models.CreateAvailabilityRequest true "Availability slot"
+// @Security BearerAuth
+// @Success 201 {object} dto.Response{data=models.TeacherAvailabilityResponse} "Success response with availability slot"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/availability [post]
+func (h *TeacherHandler) AddTeacherAvailability(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	var req models.CreateAvailabilityRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	slot, err := h.teacherService.AddAvailability(c.Request.Context(), teacherID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Availability added successfully",
+		Data:    slot,
+	})
+}
+
+// UpdateTeacherAvailability godoc
+// @Summary Update teacher availability
+// @Description Update a weekly availability slot of a teacher (Admin/Business only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param availabilityId path int true "Availability ID"
+// @Param request body models.UpdateAvailabilityRequest true "Availability update data"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.TeacherAvailabilityResponse} "Success response with availability slot"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/availability/{availabilityId} [put]
+func (h *TeacherHandler) UpdateTeacherAvailability(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	availabilityID, err := strconv.ParseUint(c.Param("availabilityId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid availability ID"})
+		return
+	}
+
+	var req models.UpdateAvailabilityRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	slot, err := h.teacherService.UpdateAvailability(c.Request.Context(), teacherID, uint(availabilityID), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Availability updated successfully",
+		Data:    slot,
+	})
+}
+
+// DeleteTeacherAvailability godoc
+// @Summary Delete teacher availability
+// @Description Remove a weekly availability slot of a teacher (Admin/Business only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param availabilityId path int true "Availability ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/availability/{availabilityId} [delete]
+func (h *TeacherHandler) DeleteTeacherAvailability(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	availabilityID, err := strconv.ParseUint(c.Param("availabilityId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid availability ID"})
+		return
+	}
+
+	if err := h.teacherService.DeleteAvailability(c.Request.Context(), teacherID, uint(availabilityID)); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Success: true,
+		Message: "Availability deleted successfully",
+	})
+}
+
+// GetAvailableTeachers godoc
+// @Summary Get available teachers
+// @Description Get active teachers of a business who are free at the given weekday and time (Admin/Business only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param weekday query int true "Weekday (0=Sunday ... 6=Saturday)"
+// @Param time query string true "Time of day (HH:MM)"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.TeacherResponse} "Success response with available teachers"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/teachers/available [get]
+func (h *TeacherHandler) GetAvailableTeachers(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	var query models.AvailableTeachersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	teachers, err := h.teacherService.GetAvailableTeachers(c.Request.Context(), businessID, *query.Weekday, query.Time)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    teachers,
+	})
+}
+
+// TransferTeacher godoc
+// @Summary Transfer teacher to another business
+// @Description Move a teacher to another active business, recording the move in the assignment history and optionally resetting salary and status (Admin only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param request body models.TransferTeacherRequest true "Transfer data"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.TeacherResponse} "Success response with updated teacher data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/transfer [post]
+func (h *TeacherHandler) TransferTeacher(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid teacher ID"})
+		return
+	}
+
+	var req models.TransferTeacherRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	teacher, err := h.teacherService.TransferTeacher(c.Request.Context(), uint(id), req, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Teacher transferred successfully",
+		Data:    teacher,
+	})
+}
+
+// GetTeacherAssignmentHistory godoc
+// @Summary Get teacher assignment history
+// @Description Get the business transfers of a teacher, newest first (Admin only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.TeacherAssignmentHistoryResponse} "Success response with assignment history"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Teacher not found"
+// @Router /teachers/{id}/assignment-history [get]
+func (h *TeacherHandler) GetTeacherAssignmentHistory(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid teacher ID"})
+		return
+	}
+
+	history, err := h.teacherService.GetAssignmentHistory(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    history,
+	})
+}
+
+// ImportTeachers godoc
+// @Summary Import teachers from CSV
+// @Description Create teachers and their user accounts from a CSV file with the columns name, email, phone, salary, qualification, experience. Every row is validated first and nothing is created unless all rows are valid. Generated passwords are returned in the per-row report. (Admin/Business only)
+// @Tags teachers
+// @Accept multipart/form-data
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param file formData file true "CSV file"
+// @Param dry_run query bool false "Validate only, without creating anything"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.TeacherImportReport} "Success response with import report"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 422 {object} dto.ErrorResponse "Import report with invalid rows"
+// @Router /businesses/{businessId}/teachers/import [post]
+func (h *TeacherHandler) ImportTeachers(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "CSV file is required",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	report, err := h.teacherService.ImportTeachers(c.Request.Context(), businessID, file, dryRun)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if report.Failed > 0 {
+		c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "Some rows are invalid; no teachers were created",
+			Data:  report,
+		})
+		return
+	}
+
+	message := "Teachers imported successfully"
+	if dryRun {
+		message = "All rows are valid"
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: message,
+		Data:    report,
+	})
+}
+
+// GetBusinessTeacherStats godoc
+// @Summary Get business teacher statistics
+// @Description Get teacher counts and student workload for a business (Admin/Business only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=object} "Success response with statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/teachers/stats [get]
+func (h *TeacherHandler) GetBusinessTeacherStats(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.teacherService)
+	if !ok {
+		return
+	}
+	h.businessTeacherStats(c, businessID)
+}
+
+func (h *TeacherHandler) businessTeacherStats(c *gin.Context, businessID uint) {
+	stats, err := h.teacherService.GetTeacherStats(c.Request.Context(), businessID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get teacher statistics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+// ExportTeachers godoc
+// @Summary Export teachers to CSV
+// @Description Download the business's teachers as CSV, honoring the list filters. Columns default to id, name, email, phone, status, salary, qualification, experience, experience_years, subjects and created_on; pick a subset and order with columns. Salaries are only exported to admins and the owning business. (Admin/Business only)
+// @Tags teachers
+// @Produce text/csv
+// @Param businessId path int true "Business ID"
+// @Param columns query string false "Comma-separated columns to include"
+// @Param status query string false "Filter by status (0 or inactive, 1 or active)"
+// @Param subject_id query int false "Filter by subject ID"
+// @Param search query string false "Search term"
+// @Security BearerAuth
+// @Success 200 {file} file "CSV file"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/teachers/export [get]
+func (h *TeacherHandler) ExportTeachers(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	var filters repository.TeacherFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+	filters.IncludeDeleted = false // deleted teachers are not exported
+
+	if err := filters.Validate(); err != nil {
+		respondInvalidSelection(c, err)
+		return
+	}
+
+	// authorizeBusiness only lets admins and the owning business through, the two
+	// callers allowed to see salaries
+	role := c.GetString("user_role")
+	includeSalary := role == "admin" || role == "business"
+
+	columns, err := h.teacherService.TeacherExportColumns(c.Query("columns"), includeSalary)
+	if err != nil {
+		respondInvalidSelection(c, err)
+		return
+	}
+
+	var rows int64
+	streamCSV(c, exportFileName("teachers", businessID), "Failed to export teachers", func(w io.Writer) error {
+		rows, err = h.teacherService.ExportTeachers(c.Request.Context(), businessID, filters, columns, includeSalary, w)
+		return err
+	})
+	if err == nil {
+		auditDataAccess(c, h.auditService, models.AuditLog{Action: models.AuditActionExport, Resource: models.AuditResourceTeachers, BusinessID: &businessID, RowCount: rows})
+	}
+}
+
+// GetActiveTeachersByBusiness godoc
+// @Summary Get active teachers by business
+// @Description Get a page of the active teachers of a business; a shortcut for status=1 (Admin/Business only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /teachers" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.TeacherPage{teachers=[]models.TeacherResponse}} "Success response with active teachers list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/teachers/active [get]
+func (h *TeacherHandler) GetActiveTeachersByBusiness(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.teacherService)
+	if !ok {
+		return
+	}
+	h.listBusinessTeachers(c, businessID, statusFilter(true))
+}
+
+// GetInactiveTeachersByBusiness godoc
+// @Summary Get inactive teachers by business
+// @Description Get a page of the inactive teachers of a business; a shortcut for status=0 (Admin/Business only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param businessId path int true "Business ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /teachers" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.TeacherPage{teachers=[]models.TeacherResponse}} "Success response with inactive teachers list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /businesses/{businessId}/teachers/inactive [get]
+func (h *TeacherHandler) GetInactiveTeachersByBusiness(c *gin.Context) {
+	businessID, ok := authorizeBusiness(c, h.teacherService)
+	if !ok {
+		return
+	}
+	h.listBusinessTeachers(c, businessID, statusFilter(false))
+}
+
+// GetMyBusinessTeachers godoc
+// @Summary Get my business's teachers
+// @Description Get the teachers of the caller's own business, found from their token (Business users only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param cursor query string false "next_cursor of the previous page, for keyset pagination instead of page; total is skipped. Needs a single sort_by column"
+// @Param with_total query bool false "Count the total; false skips the count query" default(true)
+// @Param estimate query bool false "Estimate the total from table statistics instead of counting, for large tables"
+// @Param status query string false "Filter by status (0 or inactive, 1 or active)"
+// @Param subject_id query int false "Filter by assigned subject ID"
+// @Param subject query string false "Filter by assigned subject name"
+// @Param search query string false "Search term"
+// @Param sort_by query string false "Comma-separated sort columns, as for /teachers" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.TeacherPage{teachers=[]models.TeacherResponse}} "Success response with teachers list"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/teachers [get]
+func (h *TeacherHandler) GetMyBusinessTeachers(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.teacherService)
+	if !ok {
+		return
+	}
+	h.listBusinessTeachers(c, businessID, nil)
+}
+
+// SearchMyBusinessTeachers godoc
+// @Summary Search my business's teachers
+// @Description Search the teachers of the caller's own business by name or qualification (Business users only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param q query string true "Search term"
+// @Param limit query int false "Maximum number of results" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.TeacherSearchResult} "Success response with search results"
+// @Failure 400 {object} dto.ErrorResponse "Search term is required"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/teachers/search [get]
+func (h *TeacherHandler) SearchMyBusinessTeachers(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	searchTerm := c.Query("q")
+	if searchTerm == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Search term is required"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		limit = 10
+	}
+
+	teachers, err := h.teacherService.SearchTeachersByBusiness(c.Request.Context(), businessID, searchTerm, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to search teachers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.TeacherSearchResult{
+			Teachers:   teachers,
+			SearchTerm: searchTerm,
+			TotalFound: len(teachers),
+		},
+	})
+}
+
+// GetMyBusinessTeacherStats godoc
+// @Summary Get my business's teacher statistics
+// @Description Get teacher counts and student workload for the caller's own business (Business users only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=object} "Success response with statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/teachers/stats [get]
+func (h *TeacherHandler) GetMyBusinessTeacherStats(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.teacherService)
+	if !ok {
+		return
+	}
+	h.businessTeacherStats(c, businessID)
+}
+
+// GetMyBusinessActiveTeachers godoc
+// @Summary Get my business's active teachers
+// @Description Get a page of the active teachers of the caller's own business; a shortcut for status=1 (Business users only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /teachers" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.TeacherPage{teachers=[]models.TeacherResponse}} "Success response with active teachers list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/teachers/active [get]
+func (h *TeacherHandler) GetMyBusinessActiveTeachers(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.teacherService)
+	if !ok {
+		return
+	}
+	h.listBusinessTeachers(c, businessID, statusFilter(true))
+}
+
+// GetMyBusinessInactiveTeachers godoc
+// @Summary Get my business's inactive teachers
+// @Description Get a page of the inactive teachers of the caller's own business; a shortcut for status=0 (Business users only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page, at most 100" default(10)
+// @Param sort_by query string false "Comma-separated sort columns, as for /teachers" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.TeacherPage{teachers=[]models.TeacherResponse}} "Success response with inactive teachers list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/teachers/inactive [get]
+func (h *TeacherHandler) GetMyBusinessInactiveTeachers(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.teacherService)
+	if !ok {
+		return
+	}
+	h.listBusinessTeachers(c, businessID, statusFilter(false))
+}
+
+// GetRecentTeachers godoc
+// @Summary Get recently added teachers
+// @Description Get the latest teachers added across all businesses, with their business names, and how many were added in the last 7 and 30 days (Admin only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of teachers, at most 50" default(10)
+// @Param business_id query int false "Only this business's teachers"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.RecentProfiles} "Success response with recent teachers"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/recent [get]
+func (h *TeacherHandler) GetRecentTeachers(c *gin.Context) {
+	var businessID uint
+	if id, err := strconv.ParseUint(c.Query("business_id"), 10, 32); err == nil {
+		businessID = uint(id)
+	}
+	h.recentTeachers(c, businessID)
+}
+
+// GetMyBusinessRecentTeachers godoc
+// @Summary Get my business's recently added teachers
+// @Description Get the latest teachers added to the caller's own business and how many were added in the last 7 and 30 days (Business users only)
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of teachers, at most 50" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.RecentProfiles} "Success response with recent teachers"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Business profile not found"
+// @Router /my-business/teachers/recent [get]
+func (h *TeacherHandler) GetMyBusinessRecentTeachers(c *gin.Context) {
+	businessID, ok := myBusinessID(c, h.teacherService)
+	if !ok {
+		return
+	}
+	h.recentTeachers(c, businessID)
+}
+
+// recentTeachers writes the recent teachers widget, for every business when businessID is 0
+func (h *TeacherHandler) recentTeachers(c *gin.Context, businessID uint) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		limit = 10
+	}
+
+	recent, err := h.teacherService.GetRecentTeachers(c.Request.Context(), limit, businessID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get recent teachers"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    stats,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    recent,
 	})
 }
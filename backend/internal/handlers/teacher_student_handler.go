@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TeacherStudentHandler struct {
+	assignmentService services.TeacherStudentService
+	teacherService    services.TeacherService
+}
+
+func NewTeacherStudentHandler(assignmentService services.TeacherStudentService, teacherService services.TeacherService) *TeacherStudentHandler {
+	return &TeacherStudentHandler{
+		assignmentService: assignmentService,
+		teacherService:    teacherService,
+	}
+}
+
+// AssignTeacherStudents godoc
+// @Summary Assign students to teacher
+// @Description Assign students of the teacher's business to a teacher; already assigned students are left unchanged (Admin/Business only)
+// @Tags teacher-students
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param request body models.TeacherStudentsRequest true "Student IDs"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.AssignedStudentCount} "Success response with assigned student count"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/students [post]
+func (h *TeacherStudentHandler) AssignTeacherStudents(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	var req models.TeacherStudentsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	count, err := h.assignmentService.AssignStudents(c.Request.Context(), teacherID, req.StudentIDs, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Students assigned successfully",
+		Data:    dto.AssignedStudentCount{AssignedStudentCount: count},
+	})
+}
+
+// UnassignTeacherStudents godoc
+// @Summary Unassign students from teacher
+// @Description Remove students from a teacher (Admin/Business only)
+// @Tags teacher-students
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param request body models.TeacherStudentsRequest true "Student IDs"
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.RemovedCount} "Success response with removed count"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/students [delete]
+func (h *TeacherStudentHandler) UnassignTeacherStudents(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	var req models.TeacherStudentsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	removed, err := h.assignmentService.UnassignStudents(c.Request.Context(), teacherID, req.StudentIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Students unassigned successfully",
+		Data:    dto.RemovedCount{Removed: removed},
+	})
+}
+
+// GetTeacherStudents godoc
+// @Summary Get teacher students
+// @Description Get the students assigned to a teacher with pagination (Admin/Business only)
+// @Tags teacher-students
+// @Accept json
+// @Produce json
+// @Param id path int true "Teacher ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=dto.AssignedStudentPage} "Success response with students list"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /teachers/{id}/students [get]
+func (h *TeacherStudentHandler) GetTeacherStudents(c *gin.Context) {
+	teacherID, ok := authorizeTeacher(c, h.teacherService)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	students, total, err := h.assignmentService.GetStudents(c.Request.Context(), teacherID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get teacher students"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data: dto.AssignedStudentPage{
+			Students:   students,
+			OffsetPage: dto.OffsetPage{Total: total, Page: page, Limit: limit},
+		},
+	})
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"backend/internal/dto"
+	"backend/internal/middleware"
 	"backend/internal/models"
 	"backend/internal/repository"
 	"backend/internal/services"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -28,78 +31,160 @@ func NewUserHandler(userService services.UserService) *UserHandler {
 // @Accept json
 // @Produce json
 // @Param request body models.CreateUserRequest true "User registration data"
-// @Success 201 {object} map[string]interface{} "Success response with token and user data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 409 {object} map[string]string "Email already exists"
-// @Router /api/register [post]
+// @Success 201 {object} dto.AuthResponse "Success response with token and user data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 409 {object} dto.ErrorResponse "Email already used by another user or business, ignoring case; details names which (users or business)"
+// @Router /register [post]
 func (h *UserHandler) Register(c *gin.Context) {
 	var req models.CreateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	// Validate role if provided
 	if req.Role != "" && !req.Role.IsValid() {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid role. Must be one of: admin, business, teacher, student",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid role. Must be one of: admin, business, teacher, student"})
 		return
 	}
 
-	user, token, err := h.userService.Register(req)
+	user, token, err := h.userService.Register(c.Request.Context(), req)
 	if err != nil {
+		if writeFieldError(c, err) || writeEmailTaken(c, err) {
+			return
+		}
 		status := http.StatusInternalServerError
-		if strings.Contains(err.Error(), "email already exists") {
-			status = http.StatusConflict
-		} else if strings.Contains(err.Error(), "invalid role") {
+		if strings.Contains(err.Error(), "invalid role") {
 			status = http.StatusBadRequest
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "User registered successfully",
-		"token":   token,
-		"user":    user,
+	c.JSON(http.StatusCreated, dto.AuthResponse{
+		Success: true,
+		Message: "User registered successfully",
+		Token:   token,
+		User:    user,
 	})
 }
 
 // Login godoc
 // @Summary User login
-// @Description Authenticate user and return token
+// @Description Authenticate user and return token. With business_slug the user must own that business or be one of its teachers or students.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body models.LoginRequest true "Login credentials"
-// @Success 200 {object} map[string]interface{} "Success response with token and user data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Invalid credentials"
-// @Router /api/login [post]
+// @Success 200 {object} dto.AuthResponse "Success response with token and user data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Invalid credentials"
+// @Failure 403 {object} dto.ForbiddenResponse "User does not belong to the business"
+// @Router /login [post]
 func (h *UserHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, token, err := h.userService.Login(c.Request.Context(), req, middleware.ClientIP(c))
+	if err != nil {
+		status := http.StatusUnauthorized
+		if errors.Is(err, services.ErrAccessDenied) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.AuthResponse{
+		Success: true,
+		Message: "Login successful",
+		Token:   token,
+		User:    user,
+	})
+}
+
+// RefreshToken godoc
+// @Summary Refresh token
+// @Description Issue a new token carrying the caller's current business and teacher or student profile. Tokens keep the IDs they were issued with, so refresh after creating a profile or after a business changes hands. The new token keeps acting as the active profile while the user still holds it.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.AuthResponse "Success response with token and user data"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized or inactive account"
+// @Router /refresh-token [post]
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	user, token, err := h.userService.RefreshToken(c.Request.Context(), c.GetUint("user_id"), models.UserRole(c.GetString("user_role")))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.AuthResponse{
+		Success: true,
+		Message: "Token refreshed",
+		Token:   token,
+		User:    user,
+	})
+}
+
+// GetMyProfiles godoc
+// @Summary List my profiles
+// @Description List the profiles the caller may act as: their primary role and any other profile they hold, such as a business owner's teacher profile. The profile the caller's token acts as is marked active.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=[]models.Profile} "Success response with profiles"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 404 {object} dto.ErrorResponse "User not found"
+// @Router /me/profiles [get]
+func (h *UserHandler) GetMyProfiles(c *gin.Context) {
+	profiles, err := h.userService.GetProfiles(c.Request.Context(), c.GetUint("user_id"), models.UserRole(c.GetString("user_role")))
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: profiles})
+}
+
+// SwitchProfile godoc
+// @Summary Switch profile
+// @Description Issue a new token acting as another profile the caller holds, or as their primary role again. Role and permission checks, and the business, teacher and student the caller's routes are scoped to, then follow that profile.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.SwitchProfileRequest true "Profile to act as"
+// @Security BearerAuth
+// @Success 200 {object} dto.AuthResponse "Success response with token and user data"
+// @Failure 400 {object} dto.ErrorResponse "Invalid role"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized or inactive account"
+// @Failure 403 {object} dto.ForbiddenResponse "The caller doesn't hold the profile"
+// @Router /me/profiles/switch [post]
+func (h *UserHandler) SwitchProfile(c *gin.Context) {
+	var req models.SwitchProfileRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	user, token, err := h.userService.Login(req)
+	user, token, err := h.userService.SwitchProfile(c.Request.Context(), c.GetUint("user_id"), req.Role)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, services.ErrAccessDenied):
+			c.JSON(http.StatusForbidden, dto.ForbiddenResponse{Error: err.Error()})
+		case err.Error() == "invalid role provided":
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: err.Error()})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-		"token":   token,
-		"user":    user,
+	c.JSON(http.StatusOK, dto.AuthResponse{
+		Success: true,
+		Message: "Profile switched",
+		Token:   token,
+		User:    user,
 	})
 }
 
@@ -112,68 +197,61 @@ func (h *UserHandler) Login(c *gin.Context) {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param role query string false "Filter by role (admin, business, teacher, student)"
-// @Param status query int false "Filter by status (0=inactive, 1=active)"
+// @Param status query string false "Filter by status (0 or inactive, 1 or active)"
 // @Param search query string false "Search in name or email"
+// @Param sort_by query string false "Comma-separated sort columns (created_on, updated_on, name, email, role, status), e.g. role,name" default(created_on)
+// @Param sort_order query string false "Sort order (asc, desc), for every column or one per column" default(desc)
+// @Param cursor query string false "next_cursor of the previous page, for keyset pagination instead of page; total is skipped. Needs a single sort_by column"
+// @Param with_total query bool false "Count the total; false skips the count query" default(true)
+// @Param estimate query bool false "Estimate the total from table statistics instead of counting, for large tables"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with users list"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/users [get]
+// @Success 200 {object} dto.ListResponse{data=[]models.UserResponse} "Success response with users list"
+// @Failure 400 {object} dto.ErrorResponse "Unknown sort column or filter value; valid_options lists the accepted ones"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /users [get]
 func (h *UserHandler) GetUsers(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	// Handle status filter - convert string to *int
-	var status *int
-	if statusStr := c.Query("status"); statusStr != "" {
-		if statusVal, err := strconv.Atoi(statusStr); err == nil {
-			if statusVal == 0 || statusVal == 1 {
-				status = &statusVal
-			}
-		}
-	}
-
-	// Validate role filter
-	roleFilter := c.Query("role")
-	if roleFilter != "" {
-		role := models.UserRole(roleFilter)
-		if !role.IsValid() {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid role filter. Must be one of: admin, business, teacher, student",
-			})
-			return
-		}
+	status, ok := queryStatus(c, "status")
+	if !ok {
+		return
 	}
 
 	filters := repository.UserFilters{
-		Role:   roleFilter,
-		Status: status,
-		Search: c.Query("search"),
-		Page:   page,
-		Limit:  limit,
+		Role:      c.Query("role"),
+		Status:    status,
+		Search:    c.Query("search"),
+		Page:      page,
+		Limit:     limit,
+		SortBy:    c.Query("sort_by"),
+		SortOrder: c.Query("sort_order"),
+		Cursor:    c.Query("cursor"),
+	}
+	if withTotal, err := strconv.ParseBool(c.Query("with_total")); err == nil {
+		filters.WithTotal = &withTotal
+	}
+	filters.Estimate, _ = strconv.ParseBool(c.Query("estimate"))
+
+	if err := filters.Validate(); err != nil {
+		respondInvalidSelection(c, err)
+		return
 	}
 
-	users, total, err := h.userService.GetUsers(filters)
+	users, pageInfo, err := h.userService.GetUsers(c.Request.Context(), filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if respondInvalidCursor(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	// Calculate pagination info
-	totalPages := (int(total) + limit - 1) / limit
-	hasNext := page < totalPages
-	hasPrev := page > 1
-
-	c.JSON(http.StatusOK, gin.H{
-		"data": users,
-		"pagination": gin.H{
-			"total":       total,
-			"page":        page,
-			"limit":       limit,
-			"total_pages": totalPages,
-			"has_next":    hasNext,
-			"has_prev":    hasPrev,
-		},
+	c.JSON(http.StatusOK, dto.ListResponse{
+		Success:    true,
+		Data:       users,
+		Pagination: dto.NewPagination(pageInfo),
 	})
 }
 
@@ -185,26 +263,26 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 // @Produce json
 // @Param id path int true "User ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with user data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 404 {object} map[string]string "User not found"
-// @Router /api/users/{id} [get]
+// @Success 200 {object} dto.Response{data=models.UserResponse} "Success response with user data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "User not found"
+// @Router /users/{id} [get]
 func (h *UserHandler) GetUser(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid user ID"})
 		return
 	}
 
-	user, err := h.userService.GetUserByID(uint(id))
+	user, err := h.userService.GetUserByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": user})
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: user})
 }
 
 // UpdateUser godoc
@@ -216,25 +294,22 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 // @Param id path int true "User ID"
 // @Param request body map[string]interface{} true "Update data"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with updated user data"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 404 {object} map[string]string "User not found"
-// @Router /api/users/{id} [put]
+// @Success 200 {object} dto.Response{data=models.UserResponse} "Success response with updated user data"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "User not found"
+// @Failure 409 {object} dto.ErrorResponse "Email already used by another user or business, ignoring case; details names which (users or business)"
+// @Router /users/{id} [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid user ID"})
 		return
 	}
 
 	var updates map[string]interface{}
-	if err := c.ShouldBindJSON(&updates); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &updates) {
 		return
 	}
 
@@ -243,29 +318,31 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		if roleStr, ok := role.(string); ok {
 			userRole := models.UserRole(roleStr)
 			if !userRole.IsValid() {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error": "Invalid role. Must be one of: admin, business, teacher, student",
-				})
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid role. Must be one of: admin, business, teacher, student"})
 				return
 			}
 		}
 	}
 
-	user, err := h.userService.UpdateUser(uint(id), updates)
+	user, err := h.userService.UpdateUser(c.Request.Context(), uint(id), updates)
 	if err != nil {
+		if writeFieldError(c, err) || writeEmailTaken(c, err) {
+			return
+		}
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "not found") {
 			status = http.StatusNotFound
 		} else if strings.Contains(err.Error(), "invalid") {
 			status = http.StatusBadRequest
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "User updated successfully",
-		"data":    user,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "User updated successfully",
+		Data:    user,
 	})
 }
 
@@ -277,29 +354,29 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 // @Produce json
 // @Param id path int true "User ID"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 404 {object} map[string]string "User not found"
-// @Router /api/users/{id} [delete]
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "User not found"
+// @Router /users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid user ID"})
 		return
 	}
 
-	if err := h.userService.DeleteUser(uint(id)); err != nil {
+	if err := h.userService.DeleteUser(c.Request.Context(), uint(id)); err != nil {
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "not found") {
 			status = http.StatusNotFound
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+	c.JSON(http.StatusOK, dto.MessageResponse{Success: true, Message: "User deleted successfully"})
 }
 
 // GetUsersByRole godoc
@@ -310,32 +387,31 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 // @Produce json
 // @Param role path string true "User role (admin, business, teacher, student)"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with users list"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/users/role/{role} [get]
+// @Success 200 {object} dto.UsersByRoleResponse "Success response with users list"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /users/role/{role} [get]
 func (h *UserHandler) GetUsersByRole(c *gin.Context) {
 	roleStr := c.Param("role")
 	role := models.UserRole(roleStr)
 
 	if !role.IsValid() {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid role. Must be one of: admin, business, teacher, student",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid role. Must be one of: admin, business, teacher, student"})
 		return
 	}
 
-	users, err := h.userService.GetUsersByRole(role)
+	users, err := h.userService.GetUsersByRole(c.Request.Context(), role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  users,
-		"role":  role,
-		"count": len(users),
+	c.JSON(http.StatusOK, dto.UsersByRoleResponse{
+		Success: true,
+		Data:    users,
+		Role:    role,
+		Count:   len(users),
 	})
 }
 
@@ -346,18 +422,45 @@ func (h *UserHandler) GetUsersByRole(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with statistics"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Router /api/users/stats/roles [get]
+// @Success 200 {object} dto.Response{data=map[string]int64} "Success response with statistics"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /users/stats/roles [get]
 func (h *UserHandler) GetRoleStatistics(c *gin.Context) {
-	stats, err := h.userService.GetRoleStatistics()
+	stats, err := h.userService.GetRoleStatistics(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: stats})
+}
+
+// GetRecentUsers godoc
+// @Summary Get recently registered users
+// @Description Get the latest user accounts and how many were registered in the last 7 and 30 days (Admin only)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of users, at most 50" default(10)
+// @Security BearerAuth
+// @Success 200 {object} dto.Response{data=models.RecentUsers} "Success response with recent users"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Router /users/recent [get]
+func (h *UserHandler) GetRecentUsers(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		limit = 10
+	}
+
+	recent, err := h.userService.GetRecentUsers(c.Request.Context(), limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": stats})
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: recent})
 }
 
 // PromoteUser godoc
@@ -369,47 +472,41 @@ func (h *UserHandler) GetRoleStatistics(c *gin.Context) {
 // @Param id path int true "User ID"
 // @Param request body map[string]string true "New role data"
 // @Security BearerAuth
-// @Success 200 {object} map[string]string "Success message"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 404 {object} map[string]string "User not found"
-// @Router /api/users/{id}/promote [post]
+// @Success 200 {object} dto.MessageResponse "Success message"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "User not found"
+// @Router /users/{id}/promote [post]
 func (h *UserHandler) PromoteUser(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid user ID"})
 		return
 	}
 
 	var req struct {
 		Role string `json:"role" binding:"required"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	newRole := models.UserRole(req.Role)
 	if !newRole.IsValid() {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid role. Must be one of: admin, business, teacher, student",
-		})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid role. Must be one of: admin, business, teacher, student"})
 		return
 	}
 
 	// Get current user role from context (set by middleware)
-	currentUserRole, exists := c.Get("userRole")
+	currentUserRole, exists := c.Get("user_role")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found in context"})
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: "User role not found in context"})
 		return
 	}
 
 	promotedBy := models.UserRole(currentUserRole.(string))
-	if err := h.userService.PromoteUser(uint(id), newRole, promotedBy); err != nil {
+	if err := h.userService.PromoteUser(c.Request.Context(), uint(id), newRole, promotedBy); err != nil {
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "not found") {
 			status = http.StatusNotFound
@@ -418,11 +515,11 @@ func (h *UserHandler) PromoteUser(c *gin.Context) {
 		} else if strings.Contains(err.Error(), "invalid") {
 			status = http.StatusBadRequest
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "User promoted successfully"})
+	c.JSON(http.StatusOK, dto.MessageResponse{Success: true, Message: "User promoted successfully"})
 }
 
 // GetProfile godoc
@@ -432,23 +529,23 @@ func (h *UserHandler) PromoteUser(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with user profile"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Router /api/profile [get]
+// @Success 200 {object} dto.Response{data=models.UserResponse} "Success response with user profile"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Router /profile [get]
 func (h *UserHandler) GetProfile(c *gin.Context) {
-	userID, exists := c.Get("userID")
+	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: "User ID not found in context"})
 		return
 	}
 
-	user, err := h.userService.GetUserByID(userID.(uint))
+	user, err := h.userService.GetUserByID(c.Request.Context(), userID.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "User not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": user})
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: user})
 }
 
 // UpdateProfile godoc
@@ -459,23 +556,20 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 // @Produce json
 // @Param request body map[string]interface{} true "Update data (name, phone, password)"
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{} "Success response with updated user profile"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Router /api/profile [put]
+// @Success 200 {object} dto.Response{data=models.UserResponse} "Success response with updated user profile"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Unauthorized"
+// @Failure 409 {object} dto.ErrorResponse "Email already used by another user or business, ignoring case; details names which (users or business)"
+// @Router /profile [put]
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
-	userID, exists := c.Get("userID")
+	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: "User ID not found in context"})
 		return
 	}
 
 	var updates map[string]interface{}
-	if err := c.ShouldBindJSON(&updates); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &updates) {
 		return
 	}
 
@@ -484,18 +578,22 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	delete(updates, "status")
 	delete(updates, "email") // Email changes might require verification
 
-	user, err := h.userService.UpdateUser(userID.(uint), updates)
+	user, err := h.userService.UpdateUser(c.Request.Context(), userID.(uint), updates)
 	if err != nil {
+		if writeFieldError(c, err) || writeEmailTaken(c, err) {
+			return
+		}
 		status := http.StatusInternalServerError
 		if strings.Contains(err.Error(), "invalid") {
 			status = http.StatusBadRequest
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(status, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Profile updated successfully",
-		"data":    user,
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Profile updated successfully",
+		Data:    user,
 	})
 }
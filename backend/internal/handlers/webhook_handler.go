@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"backend/internal/dto"
+	"backend/internal/services"
+	"backend/pkg/payments"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService services.WebhookService
+}
+
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// PaymentWebhook godoc
+// @Summary Receive a payment gateway webhook
+// @Description Webhook called by a payment gateway (razorpay or stripe), signed with the gateway's webhook secret. A successful payment is recorded as a fee payment of the student, and optionally the fee plan, named by student_id and fee_plan_id in the payment's metadata (notes on Razorpay). Every event is acknowledged once verified, including ones already handled and ones not acted on; a payment is never credited twice.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param provider path string true "Payment provider" Enums(razorpay, stripe)
+// @Success 200 {object} dto.Response{data=models.WebhookResult} "Event acknowledged"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.UnauthorizedResponse "Invalid signature"
+// @Failure 404 {object} dto.ErrorResponse "Unknown provider"
+// @Failure 500 {object} dto.ErrorResponse "Processing failed, retry later"
+// @Router /webhooks/{provider} [post]
+func (h *WebhookHandler) PaymentWebhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Failed to read request body"})
+		return
+	}
+
+	result, err := h.webhookService.HandlePaymentWebhook(c.Request.Context(), c.Param("provider"), c.Request.Header, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownProvider):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		case errors.Is(err, payments.ErrInvalidSignature):
+			c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to process webhook",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Data:    result,
+	})
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// APIVersionHeader tells clients which API version served the response
+const APIVersionHeader = "X-API-Version"
+
+// APIVersionMiddleware records the API version a route group belongs to, so handlers
+// shared between versions can pick the matching serializer with APIVersion
+func APIVersionMiddleware(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("api_version", version)
+		c.Header(APIVersionHeader, version)
+		c.Next()
+	}
+}
+
+// APIVersion returns the API version of the current request
+func APIVersion(c *gin.Context) string {
+	return c.GetString("api_version")
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"backend/internal/dto"
+	"backend/internal/services"
 	"backend/pkg/utils"
 	"net/http"
 	"strings"
@@ -12,7 +14,7 @@ func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: "Authorization header required"})
 			c.Abort()
 			return
 		}
@@ -20,13 +22,27 @@ func AuthMiddleware() gin.HandlerFunc {
 		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
 		claims, err := utils.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.JSON(http.StatusUnauthorized, dto.UnauthorizedResponse{Error: "Invalid token"})
 			c.Abort()
 			return
 		}
 
+		// user_role is the profile the token acts as, which the role and permission
+		// guards check; primary_role is the role the account was created with
 		c.Set("user_id", claims.UserID)
-		c.Set("user_role", claims.Role)
+		c.Set("user_role", claims.ActiveRole())
+		c.Set("primary_role", claims.Role)
+		// Tokens issued before the user had a profile carry no scope; handlers then
+		// look it up
+		if claims.BusinessID != 0 {
+			c.Set("business_id", claims.BusinessID)
+		}
+		if claims.TeacherID != 0 {
+			c.Set("teacher_id", claims.TeacherID)
+		}
+		if claims.StudentID != 0 {
+			c.Set("student_id", claims.StudentID)
+		}
 		c.Next()
 	}
 }
@@ -42,7 +58,21 @@ func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 			}
 		}
 
-		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		c.JSON(http.StatusForbidden, dto.ForbiddenResponse{Error: "Insufficient permissions"})
+		c.Abort()
+	}
+}
+
+// PermissionMiddleware lets through callers whose role holds the permission in the
+// services permission map
+func PermissionMiddleware(permission services.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if services.RoleHasPermission(c.GetString("user_role"), permission) {
+			c.Next()
+			return
+		}
+
+		c.JSON(http.StatusForbidden, dto.ForbiddenResponse{Error: "Insufficient permissions", Permission: string(permission)})
 		c.Abort()
 	}
 }
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"backend/internal/dto"
+	"net/http"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Default request body limits in bytes, each replaceable through ConfigureBodyLimits
+var defaultBodyLimits = map[string]int64{
+	// Everything not covered by a more specific limit
+	"default": 1 << 20, // 1 MB
+	// Login and registration payloads are a handful of short fields
+	"auth": 16 << 10, // 16 KB
+	// CSV imports of students and teachers
+	"import": 10 << 20, // 10 MB
+	// Document uploads; leaves room for the multipart envelope around a 10 MB file
+	"upload": 11 << 20, // 11 MB
+	// Business archives to import
+	"archive": 100 << 20, // 100 MB
+}
+
+// bodyLimitsRunKey counts the BodyLimit handlers that already ran for a request
+const bodyLimitsRunKey = "body_limits_run"
+
+// bodyLimitName is the name the runtime gives the handlers BodyLimit returns, which is
+// how a limit recognizes another one further down the chain
+var bodyLimitName string
+
+func init() {
+	bodyLimitName = runtime.FuncForPC(reflect.ValueOf(BodyLimit("default")).Pointer()).Name()
+}
+
+// routeLimitCounts caches, per method and route, how many BodyLimit handlers its chain has
+var routeLimitCounts sync.Map
+
+var (
+	bodyLimitMu     sync.Mutex
+	bodyLimitConfig map[string]int64
+)
+
+// ConfigureBodyLimits replaces default limits by name. It must be called before the
+// routes are set up.
+func ConfigureBodyLimits(limits map[string]int64) {
+	bodyLimitMu.Lock()
+	defer bodyLimitMu.Unlock()
+	bodyLimitConfig = limits
+}
+
+// BodyLimitNames lists the named limits, sorted
+func BodyLimitNames() []string {
+	names := make([]string, 0, len(defaultBodyLimits))
+	for name := range defaultBodyLimits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultBodyLimit returns the named default limit; unknown names get the default one
+func DefaultBodyLimit(name string) int64 {
+	limit, ok := defaultBodyLimits[name]
+	if !ok {
+		limit = defaultBodyLimits["default"]
+	}
+	return limit
+}
+
+// LoadBodyLimit returns the named limit as configured, or its default
+func LoadBodyLimit(name string) int64 {
+	bodyLimitMu.Lock()
+	defer bodyLimitMu.Unlock()
+
+	if limit, ok := bodyLimitConfig[name]; ok && limit > 0 {
+		return limit
+	}
+	return DefaultBodyLimit(name)
+}
+
+// BodyLimit caps the request body at the named limit. Requests that declare a larger
+// Content-Length are rejected with 413 straight away; bodies that turn out larger
+// while being read fail binding with an *http.MaxBytesError. A route-level BodyLimit
+// replaces the one applied globally, which steps aside for it, so routes such as
+// imports can take more than the default.
+func BodyLimit(name string) gin.HandlerFunc {
+	limit := LoadBodyLimit(name)
+
+	return func(c *gin.Context) {
+		if laterLimit(c) {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{
+				Error:   "Request body too large",
+				Details: "limit is " + strconv.FormatInt(limit, 10) + " bytes",
+			})
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// laterLimit reports whether another BodyLimit follows the running one in the request's
+// handler chain, which is the case for the global limit on routes with their own
+func laterLimit(c *gin.Context) bool {
+	run := c.GetInt(bodyLimitsRunKey) + 1
+	c.Set(bodyLimitsRunKey, run)
+	return run < routeLimitCount(c)
+}
+
+// routeLimitCount counts the BodyLimit handlers of the request's route
+func routeLimitCount(c *gin.Context) int {
+	key := c.Request.Method + " " + c.FullPath()
+	if cached, ok := routeLimitCounts.Load(key); ok {
+		return cached.(int)
+	}
+
+	count := 0
+	for _, name := range c.HandlerNames() {
+		if name == bodyLimitName {
+			count++
+		}
+	}
+	// Requests matching no route only run the global handlers, whatever path they ask for
+	if c.FullPath() != "" {
+		routeLimitCounts.Store(key, count)
+	}
+	return count
+}
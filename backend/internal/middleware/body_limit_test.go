@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newBodyLimitRouter mounts the global limit like main does, with an import route that
+// sets its own limit and a route that doesn't
+func newBodyLimitRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(BodyLimit("default"))
+
+	readAll := func(c *gin.Context) {
+		n, err := io.Copy(io.Discard, c.Request.Body)
+		if err != nil {
+			c.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"read": n})
+	}
+	r.POST("/api/businesses/:businessId/students/import", BodyLimit("import"), readAll)
+	r.POST("/api/students", readAll)
+	r.POST("/api/login", BodyLimit("auth"), readAll)
+	return r
+}
+
+func TestBodyLimit(t *testing.T) {
+	r := newBodyLimitRouter()
+
+	tests := []struct {
+		name   string
+		path   string
+		size   int64
+		status int
+	}{
+		{"import over the default limit", "/api/businesses/1/students/import", 3 << 20, http.StatusOK},
+		{"import just under its own limit", "/api/businesses/1/students/import", LoadBodyLimit("import") - 1, http.StatusOK},
+		{"import over its own limit", "/api/businesses/1/students/import", LoadBodyLimit("import") + 1, http.StatusRequestEntityTooLarge},
+		{"route without its own limit under the default", "/api/students", 512 << 10, http.StatusOK},
+		{"route without its own limit over the default", "/api/students", 3 << 20, http.StatusRequestEntityTooLarge},
+		{"route limit below the default", "/api/login", 64 << 10, http.StatusRequestEntityTooLarge},
+		{"unknown route over the default", "/api/missing", 3 << 20, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader(make([]byte, tt.size)))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body %s", w.Code, tt.status, w.Body.String())
+			}
+		})
+	}
+}
+
+// A body that declares no length is still cut off while it is read
+func TestBodyLimitWithoutContentLength(t *testing.T) {
+	r := newBodyLimitRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/students", io.MultiReader(bytes.NewReader(make([]byte, 3<<20))))
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+}
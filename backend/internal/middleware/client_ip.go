@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigureTrustedProxies sets which peers the engine believes about the client's
+// address, given as proxy IPs or CIDRs such as "10.0.0.0/8". X-Forwarded-For is read
+// right to left, skipping those proxies, and the first address not among them is the
+// client; a header sent by any other peer is ignored. With none configured no header is
+// trusted and the client is the connecting peer.
+func ConfigureTrustedProxies(r *gin.Engine, proxies []string) {
+	r.ForwardedByClientIP = true
+	r.RemoteIPHeaders = []string{"X-Forwarded-For", "X-Real-IP"}
+
+	if err := r.SetTrustedProxies(proxies); err != nil {
+		panic(fmt.Sprintf("invalid trusted proxies: %v", err))
+	}
+}
+
+// ClientIP is the address of the client behind any trusted proxies, with IPv4-mapped
+// IPv6 addresses written as IPv4 so one client always gets the same key
+func ClientIP(c *gin.Context) string {
+	ip := c.ClientIP()
+	if parsed := net.ParseIP(ip); parsed != nil {
+		return parsed.String()
+	}
+	return ip
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newClientIPRouter(proxies []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ConfigureTrustedProxies(r, proxies)
+	r.GET("/ip", func(c *gin.Context) {
+		c.String(http.StatusOK, ClientIP(c))
+	})
+	return r
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		peer    string
+		headers map[string]string
+		want    string
+	}{
+		{"no proxies ignores the header", nil, "203.0.113.7:4000", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "203.0.113.7"},
+		{"untrusted peer's header is ignored", []string{"10.0.0.0/8"}, "203.0.113.7:4000", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "203.0.113.7"},
+		{"untrusted peer's real IP header is ignored", []string{"10.0.0.0/8"}, "203.0.113.7:4000", map[string]string{"X-Real-IP": "198.51.100.1"}, "203.0.113.7"},
+		{"trusted proxy", []string{"10.0.0.0/8"}, "10.0.0.1:4000", map[string]string{"X-Forwarded-For": "203.0.113.7"}, "203.0.113.7"},
+		{"spoofed entry before the client is skipped", []string{"10.0.0.0/8"}, "10.0.0.1:4000", map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7"}, "203.0.113.7"},
+		{"chain of trusted proxies", []string{"10.0.0.0/8"}, "10.0.0.1:4000", map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7, 10.0.0.2"}, "203.0.113.7"},
+		{"trusted proxy without a header", []string{"10.0.0.0/8"}, "10.0.0.1:4000", nil, "10.0.0.1"},
+		{"IPv4-mapped peer", nil, "[::ffff:203.0.113.7]:4000", nil, "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newClientIPRouter(tt.proxies)
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.peer
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,16 +1,54 @@
 package middleware
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-// CORSMiddleware sets up CORS headers for the API
-func CORSMiddleware() gin.HandlerFunc {
+// CORSConfig says which browser origins may call the API and how
+type CORSConfig struct {
+	AllowedOrigins   []string      // exact origins or one wildcard such as https://*.example.com, "*" for any
+	AllowedMethods   []string      // HTTP methods
+	AllowedHeaders   []string      // request headers
+	AllowCredentials bool          // always off when any origin is allowed
+	MaxAge           time.Duration // how long browsers may cache a preflight
+}
+
+// CORSMiddleware sets up CORS headers for the API. Disallowed origins are rejected
+// with 403, preflight requests from allowed origins are answered with 204 without
+// reaching the handlers, and responses carry Vary: Origin so caches keep them apart
+// per origin.
+func CORSMiddleware(settings CORSConfig) gin.HandlerFunc {
 	config := cors.DefaultConfig()
-	config.AllowOrigins = []string{"http://localhost:3000"}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization"}
-	config.AllowCredentials = true
+
+	origins := settings.AllowedOrigins
+	if len(origins) == 1 && origins[0] == "*" {
+		config.AllowAllOrigins = true
+	} else {
+		config.AllowOrigins = origins
+		config.AllowWildcard = true
+	}
+
+	config.AllowMethods = settings.AllowedMethods
+	config.AllowHeaders = settings.AllowedHeaders
+	config.ExposeHeaders = []string{RequestIDHeader, "Retry-After"}
+
+	config.AllowCredentials = settings.AllowCredentials
+	if config.AllowAllOrigins {
+		// Browsers refuse credentialed responses with a wildcard origin
+		config.AllowCredentials = false
+	}
+
+	if settings.MaxAge > 0 {
+		config.MaxAge = settings.MaxAge
+	}
+
+	if err := config.Validate(); err != nil {
+		panic(fmt.Sprintf("invalid CORS configuration: %v", err))
+	}
+
 	return cors.New(config)
 }
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCORSRouter(origins ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CORSMiddleware(CORSConfig{
+		AllowedOrigins:   origins,
+		AllowedMethods:   []string{"GET", "POST", "PUT"},
+		AllowedHeaders:   []string{"Origin", "Content-Type", "Authorization"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}))
+	r.Any("/api/students", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	return r
+}
+
+func TestCORS(t *testing.T) {
+	listed := newCORSRouter("https://app.example.com", "https://*.tenant.example.com")
+	open := newCORSRouter("*")
+
+	tests := []struct {
+		name        string
+		router      *gin.Engine
+		method      string
+		origin      string
+		status      int
+		allowOrigin string
+		credentials string
+	}{
+		{"preflight from a listed origin", listed, http.MethodOptions, "https://app.example.com", http.StatusNoContent, "https://app.example.com", "true"},
+		{"preflight from a wildcard origin", listed, http.MethodOptions, "https://sunrise.tenant.example.com", http.StatusNoContent, "https://sunrise.tenant.example.com", "true"},
+		{"preflight from an unlisted origin", listed, http.MethodOptions, "https://evil.example.com", http.StatusForbidden, "", ""},
+		{"request from a listed origin", listed, http.MethodGet, "https://app.example.com", http.StatusOK, "https://app.example.com", "true"},
+		{"request from an unlisted origin", listed, http.MethodGet, "https://evil.example.com", http.StatusForbidden, "", ""},
+		{"request without an origin", listed, http.MethodGet, "", http.StatusOK, "", ""},
+		{"preflight with any origin allowed", open, http.MethodOptions, "https://evil.example.com", http.StatusNoContent, "*", ""},
+		{"request with any origin allowed", open, http.MethodGet, "https://evil.example.com", http.StatusOK, "*", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/students", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+				req.Header.Set("Access-Control-Request-Headers", "Authorization")
+			}
+			w := httptest.NewRecorder()
+			tt.router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tt.credentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, tt.credentials)
+			}
+			if tt.status == http.StatusNoContent {
+				if got := w.Header().Get("Access-Control-Allow-Methods"); got != "GET,POST,PUT" {
+					t.Errorf("Access-Control-Allow-Methods = %q, want %q", got, "GET,POST,PUT")
+				}
+				if got := w.Header().Get("Access-Control-Max-Age"); got != "3600" {
+					t.Errorf("Access-Control-Max-Age = %q, want %q", got, "3600")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/pkg/logger"
+	"backend/pkg/utils"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceStateProvider supplies the current maintenance state, cheaply enough to be
+// asked on every request
+type MaintenanceStateProvider interface {
+	CurrentState(ctx context.Context) (*models.MaintenanceState, error)
+}
+
+// MaintenanceMiddleware answers every request with 503 and Retry-After while maintenance
+// mode is on. Routes whose path is in exemptPaths (health checks, the toggle itself) keep
+// working, as do the admins whose user IDs are in allowedAdminIDs. It runs ahead of
+// AuthMiddleware, so it reads those admins' tokens itself. If the state can't be read
+// the request is let through rather than taking the whole API down.
+func MaintenanceMiddleware(provider MaintenanceStateProvider, allowedAdminIDs []uint, exemptPaths ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = true
+	}
+	allowedAdmins := make(map[uint]bool, len(allowedAdminIDs))
+	for _, id := range allowedAdminIDs {
+		allowedAdmins[id] = true
+	}
+
+	return func(c *gin.Context) {
+		if exempt[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		state, err := provider.CurrentState(c.Request.Context())
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Error("Failed to read maintenance state", "error", err)
+			c.Next()
+			return
+		}
+		if !state.Enabled || allowedAdmins[bearerAdminID(c)] {
+			c.Next()
+			return
+		}
+
+		message := state.Message
+		if message == "" {
+			message = models.DefaultMaintenanceMessage
+		}
+		if state.RetryAfterSeconds > 0 {
+			c.Header("Retry-After", strconv.Itoa(state.RetryAfterSeconds))
+		}
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+			Error:       message,
+			Maintenance: true,
+		})
+	}
+}
+
+// bearerAdminID returns the user ID in the request's token when it belongs to an admin, or 0
+func bearerAdminID(c *gin.Context) uint {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return 0
+	}
+
+	claims, err := utils.ValidateToken(strings.Replace(authHeader, "Bearer ", "", 1))
+	if err != nil || claims.Role != "admin" {
+		return 0
+	}
+	return claims.UserID
+}
@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"backend/internal/dto"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitPolicy is a token bucket: a client may burst up to Requests calls and
+// regains the whole allowance evenly over Window
+type RateLimitPolicy struct {
+	Name     string
+	Requests int
+	Window   time.Duration
+}
+
+// Default policies, each overridable through ConfigureRateLimits
+var defaultRateLimitPolicies = map[string]RateLimitPolicy{
+	// Login and registration, the usual target of credential stuffing
+	"auth": {Name: "auth", Requests: 10, Window: time.Minute},
+	// Other unauthenticated endpoints such as the business page and guardian view
+	"public": {Name: "public", Requests: 60, Window: time.Minute},
+	// Authenticated API traffic, keyed by user
+	"api": {Name: "api", Requests: 600, Window: time.Minute},
+}
+
+// RateLimitStore keeps the buckets behind the middleware. The in-memory store is
+// per process; a shared store such as Redis can implement this interface when the
+// API runs on more than one instance.
+type RateLimitStore interface {
+	// Allow takes a token from the bucket identified by key, returning whether the
+	// request may proceed and, when it may not, how long until a token is available
+	Allow(key string, policy RateLimitPolicy) (bool, time.Duration)
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+	window   time.Duration
+}
+
+type memoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// rateLimitSweepInterval is how often idle buckets are dropped from the in-memory store
+const rateLimitSweepInterval = 10 * time.Minute
+
+// NewMemoryRateLimitStore returns a RateLimitStore that keeps buckets in process memory
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *memoryRateLimitStore) Allow(key string, policy RateLimitPolicy) (bool, time.Duration) {
+	now := time.Now()
+	capacity := float64(policy.Requests)
+	perToken := policy.Window / time.Duration(policy.Requests)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, lastSeen: now, window: policy.Window}
+		s.buckets[key] = bucket
+	} else {
+		elapsed := now.Sub(bucket.lastSeen)
+		bucket.tokens = math.Min(capacity, bucket.tokens+float64(elapsed)/float64(perToken))
+		bucket.lastSeen = now
+	}
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+
+	return false, time.Duration((1 - bucket.tokens) * float64(perToken))
+}
+
+// sweep drops buckets that have been idle long enough to be full again, since a
+// fresh bucket behaves the same
+func (s *memoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.lastSeen) > bucket.window {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// RateLimitConfig switches the limiter off or replaces default policies by name
+type RateLimitConfig struct {
+	Enabled  bool
+	Policies map[string]RateLimitPolicy
+}
+
+var (
+	rateLimitMu     sync.Mutex
+	rateLimitStore  RateLimitStore = NewMemoryRateLimitStore()
+	rateLimitConfig                = RateLimitConfig{Enabled: true}
+)
+
+// SetRateLimitStore replaces the store shared by every rate limit policy. It must be
+// called before the routes are set up.
+func SetRateLimitStore(store RateLimitStore) {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	rateLimitStore = store
+}
+
+// ConfigureRateLimits sets the policies the limiter uses. It must be called before the
+// routes are set up.
+func ConfigureRateLimits(config RateLimitConfig) {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	rateLimitConfig = config
+}
+
+// RateLimitPolicyNames lists the policies that have defaults, sorted
+func RateLimitPolicyNames() []string {
+	names := make([]string, 0, len(defaultRateLimitPolicies))
+	for name := range defaultRateLimitPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultRateLimitPolicy returns the named default policy; unknown names get the api
+// policy under their own name
+func DefaultRateLimitPolicy(name string) RateLimitPolicy {
+	policy, ok := defaultRateLimitPolicies[name]
+	if !ok {
+		policy = defaultRateLimitPolicies["api"]
+		policy.Name = name
+	}
+	return policy
+}
+
+// LoadRateLimitPolicy returns the named policy as configured, or its default
+func LoadRateLimitPolicy(name string) RateLimitPolicy {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+
+	if policy, ok := rateLimitConfig.Policies[name]; ok {
+		policy.Name = name
+		return policy
+	}
+	return DefaultRateLimitPolicy(name)
+}
+
+// RateLimit limits requests with the named policy. Callers are keyed by user ID when
+// an earlier middleware has authenticated them, and by client IP otherwise, so it
+// should come after AuthMiddleware on protected groups. Admins are exempt, and the
+// whole limiter can be switched off through ConfigureRateLimits.
+func RateLimit(name string) gin.HandlerFunc {
+	policy := LoadRateLimitPolicy(name)
+
+	rateLimitMu.Lock()
+	store := rateLimitStore
+	disabled := !rateLimitConfig.Enabled || policy.Requests <= 0
+	rateLimitMu.Unlock()
+
+	return func(c *gin.Context) {
+		if disabled || c.GetString("user_role") == "admin" {
+			c.Next()
+			return
+		}
+
+		key := "ip:" + ClientIP(c)
+		if userID := c.GetUint("user_id"); userID != 0 {
+			key = fmt.Sprintf("user:%d", userID)
+		}
+
+		allowed, retryAfter := store.Allow(policy.Name+":"+key, policy)
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.ErrorResponse{Error: "Too many requests, please try again later"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"backend/pkg/logger"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength caps request IDs taken from clients
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware reuses the caller's X-Request-ID if it is sensible or generates
+// one, stores it in the context as "request_id", echoes it in the response and
+// attaches a logger carrying it to the request context
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		requestLogger := slog.Default().With("request_id", requestID)
+		c.Request = c.Request.WithContext(logger.WithLogger(c.Request.Context(), requestLogger))
+
+		c.Next()
+	}
+}
+
+// RequestLoggerMiddleware logs every request once it has been handled, with its ID,
+// the caller's user ID and role, the matched route, the status and the latency
+func RequestLoggerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", route,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", ClientIP(c),
+		}
+		if userID := c.GetUint("user_id"); userID != 0 {
+			attrs = append(attrs, "user_id", userID, "role", c.GetString("user_role"))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+
+		log := logger.FromContext(c.Request.Context())
+		switch status := c.Writer.Status(); {
+		case status >= 500:
+			log.Error("request", attrs...)
+		case status >= 400:
+			log.Warn("request", attrs...)
+		default:
+			log.Info("request", attrs...)
+		}
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().UTC().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"backend/internal/models"
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Clients ask for statuses as "active" and "inactive" instead of 1 and 0 with the
+// status_format=string query parameter or the X-Status-Format: string header
+const (
+	StatusFormatParam  = "status_format"
+	StatusFormatHeader = "X-Status-Format"
+	StatusFormatString = "string"
+)
+
+// StatusFormatMiddleware rewrites the numeric statuses of JSON responses as their names
+// for clients that ask for it, while they move off the numbers. Every "status" key whose
+// value is a known status number is rewritten, wherever it is nested. Other clients, and
+// responses that aren't JSON, are passed through untouched.
+func StatusFormatMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !wantsStatusNames(c) {
+			c.Next()
+			return
+		}
+
+		writer := &statusFormatWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+		writer.flush()
+	}
+}
+
+func wantsStatusNames(c *gin.Context) bool {
+	format := c.Query(StatusFormatParam)
+	if format == "" {
+		format = c.GetHeader(StatusFormatHeader)
+	}
+	return strings.EqualFold(format, StatusFormatString)
+}
+
+// statusFormatWriter holds back JSON bodies until the handler is done so their statuses
+// can be rewritten; anything else, like CSV exports, streams straight through
+type statusFormatWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	buffering *bool
+}
+
+func (w *statusFormatWriter) buffer() bool {
+	if w.buffering == nil {
+		buffering := strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+		w.buffering = &buffering
+	}
+	return *w.buffering
+}
+
+func (w *statusFormatWriter) Write(data []byte) (int, error) {
+	if w.buffer() {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *statusFormatWriter) WriteString(s string) (int, error) {
+	if w.buffer() {
+		return w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *statusFormatWriter) Written() bool {
+	return w.body.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// flush writes the held back body, with its statuses rewritten when it parses
+func (w *statusFormatWriter) flush() {
+	if w.body.Len() == 0 {
+		return
+	}
+
+	body := w.body.Bytes()
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err == nil {
+		if rewritten, err := json.Marshal(nameStatuses(value)); err == nil {
+			body = rewritten
+		}
+	}
+	w.ResponseWriter.Write(body)
+}
+
+// nameStatuses replaces the status numbers in a decoded JSON value with their names
+func nameStatuses(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if number, ok := item.(json.Number); ok && strings.EqualFold(key, "status") {
+				if n, err := number.Int64(); err == nil && models.Status(n).IsValid() {
+					v[key] = models.Status(n).String()
+					continue
+				}
+			}
+			v[key] = nameStatuses(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = nameStatuses(item)
+		}
+	}
+	return value
+}
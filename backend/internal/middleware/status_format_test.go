@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newStatusFormatRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(StatusFormatMiddleware())
+	r.GET("/api/teachers", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"status":  1,
+			"data": []gin.H{
+				{"name": "Asha", "status": 1, "business": gin.H{"status": 0}},
+				{"name": "Chitra", "status": 0, "attendance": gin.H{"status": "present"}, "code": 1},
+			},
+			"meta": gin.H{"status": 5},
+		})
+	})
+	r.GET("/api/teachers/export", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/csv", []byte("name,status\nAsha,1\n"))
+	})
+	return r
+}
+
+func TestStatusFormatMiddleware(t *testing.T) {
+	r := newStatusFormatRouter()
+	numbers := `{"data":[{"business":{"status":0},"name":"Asha","status":1},{"attendance":{"status":"present"},"code":1,"name":"Chitra","status":0}],"meta":{"status":5},"status":1,"success":true}`
+	names := `{"data":[{"business":{"status":"inactive"},"name":"Asha","status":"active"},{"attendance":{"status":"present"},"code":1,"name":"Chitra","status":"inactive"}],"meta":{"status":5},"status":"active","success":true}`
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   string
+	}{
+		{"query parameter", "/api/teachers?status_format=string", "", names},
+		{"header", "/api/teachers", "String", names},
+		{"other clients", "/api/teachers", "", numbers},
+		{"other formats", "/api/teachers?status_format=number", "", numbers},
+		{"body that isn't JSON", "/api/teachers/export?status_format=string", "", "name,status\nAsha,1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(StatusFormatHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			if w.Body.String() != tt.want {
+				t.Errorf("body = %s\nwant   %s", w.Body.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestQueryTimeoutMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		deadline bool
+	}{
+		{"timeout set", time.Minute, true},
+		{"timeout disabled", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(QueryTimeoutMiddleware(tt.timeout))
+
+			var ctx context.Context
+			r.GET("/api/students", func(c *gin.Context) {
+				ctx = c.Request.Context()
+				c.Status(http.StatusOK)
+			})
+			begin := time.Now()
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/students", nil))
+
+			deadline, ok := ctx.Deadline()
+			if ok != tt.deadline {
+				t.Fatalf("deadline set = %v, want %v", ok, tt.deadline)
+			}
+			if !ok {
+				return
+			}
+			if deadline.Before(begin.Add(tt.timeout)) || deadline.After(time.Now().Add(tt.timeout)) {
+				t.Errorf("deadline = %v, want %v after the request began", deadline, tt.timeout)
+			}
+			// Statements the handler left running are cancelled once it returns
+			if ctx.Err() != context.Canceled {
+				t.Errorf("context error after the request = %v, want %v", ctx.Err(), context.Canceled)
+			}
+		})
+	}
+
+	// A request that runs past the timeout sees its context expire
+	r := gin.New()
+	r.Use(QueryTimeoutMiddleware(10 * time.Millisecond))
+	r.GET("/api/reports", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			c.String(http.StatusGatewayTimeout, c.Request.Context().Err().Error())
+		case <-time.After(5 * time.Second):
+			c.Status(http.StatusOK)
+		}
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/reports", nil))
+	if w.Code != http.StatusGatewayTimeout || w.Body.String() != context.DeadlineExceeded.Error() {
+		t.Errorf("slow request = %d %q, want it to see %v", w.Code, w.Body.String(), context.DeadlineExceeded)
+	}
+}
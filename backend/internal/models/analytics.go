@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+)
+
+// Timeseries granularities
+const (
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+// GrowthTimeseriesQuery selects the periods of a growth timeseries
+type GrowthTimeseriesQuery struct {
+	Granularity string `form:"granularity" binding:"omitempty,oneof=week month"` // defaults to month
+	From        string `form:"from"`                                             // YYYY-MM-DD, inclusive
+	To          string `form:"to"`                                               // YYYY-MM-DD, inclusive, defaults to today
+}
+
+// TimeseriesPoint counts the changes within one period
+type TimeseriesPoint struct {
+	Period      string    `json:"period" example:"2026-10"` // ISO label, 2026-10 for months and 2026-W42 for weeks
+	Start       time.Time `json:"start"`
+	Created     int64     `json:"created"`
+	Deactivated int64     `json:"deactivated"`
+	Reactivated int64     `json:"reactivated"`
+	NetActive   int64     `json:"net_active"` // created + reactivated - deactivated
+}
+
+// AdditionCounts is how many records were created lately, for the dashboard widgets
+type AdditionCounts struct {
+	Last7Days  int64 `json:"last_7_days" gorm:"column:last_7_days"`
+	Last30Days int64 `json:"last_30_days" gorm:"column:last_30_days"`
+}
+
+// RecentProfile is a slim student or teacher for the recent-activity widgets
+type RecentProfile struct {
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	BusinessID   uint      `json:"business_id"`
+	BusinessName string    `json:"business_name"`
+	Status       Status    `json:"status"`
+	CreatedOn    time.Time `json:"created_on"`
+}
+
+// RecentProfiles is a recent-activity widget: the latest students or teachers, newest
+// first, and how many were added lately
+type RecentProfiles struct {
+	Latest    []RecentProfile `json:"latest"`
+	Additions AdditionCounts  `json:"additions"`
+}
+
+// RecentUsers is the latest user accounts, newest first, and how many were added lately
+type RecentUsers struct {
+	Latest    []UserResponse `json:"latest"`
+	Additions AdditionCounts `json:"additions"`
+}
+
+// GrowthTimeseries is the platform-wide growth and churn over a range of periods
+type GrowthTimeseries struct {
+	Granularity string            `json:"granularity" example:"month"`
+	From        string            `json:"from" example:"2025-11-01"`
+	To          string            `json:"to" example:"2026-10-31"`
+	Businesses  []TimeseriesPoint `json:"businesses"`
+	Teachers    []TimeseriesPoint `json:"teachers"`
+	Students    []TimeseriesPoint `json:"students"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+)
+
+// Announcement audiences
+const (
+	AudienceStudents = "students"
+	AudienceTeachers = "teachers"
+	AudienceAll      = "all"
+)
+
+type Announcement struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	BusinessID  uint       `json:"business_id" gorm:"not null;index"`
+	Title       string     `json:"title" gorm:"not null"`
+	Body        string     `json:"body" gorm:"type:text;not null"`
+	Audience    string     `json:"audience" gorm:"type:varchar(20);not null"` // students, teachers, all
+	BatchID     *uint      `json:"batch_id" gorm:"index;default:null"`        // only the batch's students and teacher
+	PublishedAt time.Time  `json:"published_at" gorm:"not null;index"`        // hidden from readers until then
+	ExpiresAt   *time.Time `json:"expires_at" gorm:"default:null"`
+	CreatedBy   uint       `json:"created_by" gorm:"not null"` // user ID of the actor
+	CreatedOn   time.Time  `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn   time.Time  `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+
+	// Relationships
+	Batch *Batch `json:"-" gorm:"foreignKey:BatchID"`
+}
+
+// TableName overrides the table name
+func (Announcement) TableName() string {
+	return "announcement"
+}
+
+// AnnouncementRead marks an announcement as read by a user
+type AnnouncementRead struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	AnnouncementID uint      `json:"announcement_id" gorm:"not null;uniqueIndex:idx_announcement_read_user"`
+	UserID         uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_announcement_read_user;index"`
+	ReadAt         time.Time `json:"read_at" gorm:"column:read_at;autoCreateTime"`
+}
+
+// TableName overrides the table name
+func (AnnouncementRead) TableName() string {
+	return "announcement_read"
+}
+
+type AnnouncementResponse struct {
+	ID          uint       `json:"id"`
+	BusinessID  uint       `json:"business_id"`
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	Audience    string     `json:"audience"`
+	BatchID     *uint      `json:"batch_id"`
+	BatchName   string     `json:"batch_name,omitempty"`
+	PublishedAt time.Time  `json:"published_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedBy   uint       `json:"created_by"`
+	CreatedOn   time.Time  `json:"created_on"`
+	Read        *bool      `json:"read,omitempty"` // only set for readers
+}
+
+type CreateAnnouncementRequest struct {
+	Title       string     `json:"title" binding:"required,max=200"`
+	Body        string     `json:"body" binding:"required"`
+	Audience    string     `json:"audience" binding:"required,oneof=students teachers all"`
+	BatchID     *uint      `json:"batch_id"`
+	PublishedAt *time.Time `json:"published_at"` // RFC 3339, defaults to now
+	ExpiresAt   *time.Time `json:"expires_at"`   // RFC 3339
+}
+
+type UpdateAnnouncementRequest struct {
+	Title       string     `json:"title" binding:"max=200"`
+	Body        string     `json:"body"`
+	Audience    string     `json:"audience" binding:"omitempty,oneof=students teachers all"`
+	BatchID     *uint      `json:"batch_id"`
+	PublishedAt *time.Time `json:"published_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// Audit log categories
+const (
+	AuditCategoryDataAccess = "data_access" // personal data read or exported
+)
+
+// Data access actions
+const (
+	AuditActionList   = "list"
+	AuditActionView   = "view"
+	AuditActionExport = "export"
+	AuditActionStats  = "stats" // aggregates of personal data, such as guardian contact counts
+)
+
+// Data access resources
+const (
+	AuditResourceStudents        = "students"
+	AuditResourceTeachers        = "teachers"
+	AuditResourceBusinessArchive = "business_archive"
+)
+
+// AuditLog records who read or exported which records. Entries are written in the
+// background, so CreatedOn is when the data was read rather than when the row was inserted.
+type AuditLog struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	Category   string `json:"category" gorm:"type:varchar(30);not null;index:idx_audit_log_category_created,priority:1"`
+	Action     string `json:"action" gorm:"type:varchar(20);not null"`   // list, view, export, stats
+	Resource   string `json:"resource" gorm:"type:varchar(30);not null"` // students, teachers, business_archive
+	ResourceID *uint  `json:"resource_id" gorm:"default:null"`           // the single record viewed, if any
+	// The actor is the account that made the request; ActorRole is the profile it acted
+	// as, which differs from PrimaryRole after switching profiles
+	ActorID     uint      `json:"actor_id" gorm:"not null;index"`
+	ActorRole   string    `json:"actor_role" gorm:"type:varchar(20);not null"`
+	PrimaryRole string    `json:"primary_role" gorm:"type:varchar(20);not null"`
+	BusinessID  *uint     `json:"business_id" gorm:"index;default:null"` // null when reading across businesses
+	Scope       JSONB     `json:"scope" gorm:"type:jsonb"`               // query parameters of the request
+	RowCount    int64     `json:"row_count" gorm:"not null;default:0"`
+	Path        string    `json:"path" gorm:"not null"` // route of the request, e.g. /api/v1/students/:id
+	RequestID   string    `json:"request_id"`
+	IPAddress   string    `json:"ip_address" gorm:"type:varchar(45)"` // client address behind trusted proxies
+	CreatedOn   time.Time `json:"created_on" gorm:"column:created_on;not null;index:idx_audit_log_category_created,priority:2"`
+}
+
+// TableName overrides the table name
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
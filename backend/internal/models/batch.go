@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+type Batch struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	BusinessID uint       `json:"business_id" gorm:"not null;uniqueIndex:idx_batch_business_name"`
+	Name       string     `json:"name" gorm:"not null;uniqueIndex:idx_batch_business_name"`
+	StartDate  *time.Time `json:"start_date" gorm:"type:date"`
+	EndDate    *time.Time `json:"end_date" gorm:"type:date"`
+	TeacherID  *uint      `json:"teacher_id" gorm:"index;default:null"` // assigned teacher
+	CreatedOn  time.Time  `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn  time.Time  `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+
+	// Relationships
+	Business Business `json:"business,omitempty" gorm:"foreignKey:BusinessID"`
+	Teacher  *Teacher `json:"teacher,omitempty" gorm:"foreignKey:TeacherID"`
+}
+
+// TableName overrides the table name
+func (Batch) TableName() string {
+	return "batch"
+}
+
+type BatchResponse struct {
+	ID           uint      `json:"id"`
+	BusinessID   uint      `json:"business_id"`
+	Name         string    `json:"name"`
+	StartDate    string    `json:"start_date,omitempty"`
+	EndDate      string    `json:"end_date,omitempty"`
+	TeacherID    *uint     `json:"teacher_id"`
+	TeacherName  string    `json:"teacher_name,omitempty"`
+	StudentCount int64     `json:"student_count"`
+	CreatedOn    time.Time `json:"created_on"`
+}
+
+// StudentBatchResponse is the batch summary embedded in student responses
+type StudentBatchResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	StartDate string `json:"start_date,omitempty"`
+	EndDate   string `json:"end_date,omitempty"`
+}
+
+type CreateBatchRequest struct {
+	Name      string `json:"name" binding:"required"`
+	StartDate string `json:"start_date"` // YYYY-MM-DD
+	EndDate   string `json:"end_date"`   // YYYY-MM-DD
+	TeacherID *uint  `json:"teacher_id"`
+}
+
+type UpdateBatchRequest struct {
+	Name      string `json:"name"`
+	StartDate string `json:"start_date"` // YYYY-MM-DD
+	EndDate   string `json:"end_date"`   // YYYY-MM-DD
+	TeacherID *uint  `json:"teacher_id"`
+}
+
+// MoveStudentBatchRequest moves a student into a batch; a null batch_id removes the student from their batch
+type MoveStudentBatchRequest struct {
+	BatchID *uint `json:"batch_id"`
+}
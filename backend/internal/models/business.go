@@ -1,24 +1,63 @@
 package models
 
 import (
+	"fmt"
 	"time"
 )
 
 type Business struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Name      string    `json:"name" gorm:"not null"`
-	Slug      string    `json:"slug" gorm:"uniqueIndex;not null"`
-	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex"`
-	OwnerName string    `json:"owner_name" gorm:"not null"`
-	PackageID *uint     `json:"package_id" gorm:"default:null"` // nullable
-	Email     string    `json:"email" gorm:"uniqueIndex;not null"`
-	Phone     string    `json:"phone"`
-	Location  string    `json:"location"`
-	Password  string    `json:"-" gorm:"not null"`
-	Status    int       `json:"status" gorm:"not null;default:1"` // 1=active, 0=inactive
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	Name      string `json:"name" gorm:"not null"`
+	Slug      string `json:"slug" gorm:"uniqueIndex;not null"`
+	UserID    uint   `json:"user_id" gorm:"not null;uniqueIndex"`
+	OwnerName string `json:"owner_name" gorm:"not null"`
+	PackageID *uint  `json:"package_id" gorm:"default:null"` // nullable
+	// PackageExpiresAt is when the assigned package runs out: its validation period in
+	// days after it was assigned
+	PackageExpiresAt *time.Time `json:"package_expires_at" gorm:"default:null;index"`
+	// The package's price and validation period when it was assigned, so later changes
+	// to the package don't rewrite the business's history
+	PackagePrice            *float64 `json:"package_price" gorm:"default:null"`
+	PackageValidationPeriod *int     `json:"package_validation_period" gorm:"default:null"`
+	Email                   string   `json:"email" gorm:"not null"` // unique ignoring case, by idx_business_email
+	Phone                   string   `json:"phone"`
+	Location                string   `json:"location"`
+	LogoPath                string   `json:"-"`                                // storage path of the logo printed on report cards and receipts
+	Status                  Status   `json:"status" gorm:"not null;default:1"` // account status, set by admins: 1=active, 0=inactive
+	// IsOpen is whether the centre is open to the public, set by the owner, e.g. to close
+	// for holidays. Unlike Status it doesn't affect anyone's login.
+	IsOpen    bool      `json:"is_open" gorm:"not null;default:true"`
+	Version   uint      `json:"version" gorm:"not null;default:1"` // bumped on every update, for optimistic locking
 	CreatedOn time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
 	UpdatedOn time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
 
+	// Settings
+	StrictStudentFields bool `json:"strict_student_fields" gorm:"not null;default:false"` // reject undefined student information keys
+	SMSNotifications    bool `json:"sms_notifications" gorm:"not null;default:false"`     // text guardians about absences and fee dues
+
+	// Pattern of student codes such as SUN-2024-0153: the prefix, the year of enrollment
+	// and a zero-padded sequence. No codes are given out while the prefix is empty.
+	StudentCodePrefix string `json:"student_code_prefix" gorm:"type:varchar(10);not null;default:''"`
+	StudentCodeYear   bool   `json:"student_code_year" gorm:"not null;default:true"`
+	StudentCodeDigits int    `json:"student_code_digits" gorm:"not null;default:4"`
+
+	// Salary is divided by this many days for the deduction of a day of absence; 0 divides
+	// by the days of each month
+	PayrollWorkingDays int `json:"payroll_working_days" gorm:"not null;default:0"`
+
+	// Timezone is the IANA name of the zone the centre is in, such as Asia/Kolkata. Its days
+	// decide what "today" is for attendance, fees and package expiry, and where date filters
+	// and stats cut one day from the next. Timestamps are stored in UTC regardless.
+	Timezone string `json:"timezone" gorm:"type:varchar(64);not null;default:'UTC'"`
+
+	// Calendar feed; the ID of the only feed token accepted, empty when the feed is off
+	CalendarTokenID string `json:"-" gorm:"type:varchar(64);not null;default:''"`
+
+	// Theme of the public page; colors are #rrggbb, empty for the frontend's default
+	ThemePrimaryColor string `json:"theme_primary_color" gorm:"type:varchar(7);not null;default:''"`
+	ThemeAccentColor  string `json:"theme_accent_color" gorm:"type:varchar(7);not null;default:''"`
+	BannerPath        string `json:"-"` // storage path of the banner shown on the public page
+
 	// Relationships
 	User    User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
 	Package *Package `json:"package,omitempty" gorm:"foreignKey:PackageID"`
@@ -30,44 +69,135 @@ func (Business) TableName() string {
 }
 
 type BusinessResponse struct {
-	ID        uint             `json:"id"`
-	Name      string           `json:"name"`
-	Slug      string           `json:"slug"`
-	UserID    uint             `json:"user_id"`
-	OwnerName string           `json:"owner_name"`
-	PackageID *uint            `json:"package_id"`
-	Email     string           `json:"email"`
-	Phone     string           `json:"phone"`
-	Location  string           `json:"location"`
-	Status    int              `json:"status"`
-	CreatedOn time.Time        `json:"created_on"`
-	User      *UserResponse    `json:"user,omitempty"`
-	Package   *PackageResponse `json:"package,omitempty"`
+	ID               uint             `json:"id"`
+	Name             string           `json:"name"`
+	Slug             string           `json:"slug"`
+	UserID           uint             `json:"user_id"`
+	OwnerName        string           `json:"owner_name"`
+	PackageID        *uint            `json:"package_id"`
+	PackageExpiresAt *time.Time       `json:"package_expires_at"`
+	Email            string           `json:"email"`
+	Phone            string           `json:"phone"`
+	Location         string           `json:"location"`
+	Status           Status           `json:"status"`
+	IsOpen           bool             `json:"is_open"`
+	Timezone         string           `json:"timezone"`
+	Version          uint             `json:"version"`
+	CreatedOn        time.Time        `json:"created_on"`
+	UpdatedOn        time.Time        `json:"updated_on"`
+	User             *UserResponse    `json:"user,omitempty"`
+	Package          *PackageResponse `json:"package,omitempty"`
+	Theme            *BusinessTheme   `json:"theme,omitempty"` // left out of lists unless include_theme is set
+	// PackagePricing compares the terms the package was assigned on with its current ones;
+	// only admins reading a single business get it
+	PackagePricing *BusinessPackagePricing `json:"package_pricing,omitempty"`
+}
+
+// BusinessPackagePricing is the price and validation period of a business's package, as
+// assigned and as the package stands now
+type BusinessPackagePricing struct {
+	AssignedPrice            *float64 `json:"assigned_price"`
+	AssignedValidationPeriod *int     `json:"assigned_validation_period"`
+	CurrentPrice             float64  `json:"current_price"`
+	CurrentValidationPeriod  int      `json:"current_validation_period"`
+	PriceChanged             bool     `json:"price_changed"`
+}
+
+// BusinessTheme is the branding of a business's public page
+type BusinessTheme struct {
+	PrimaryColor string `json:"primary_color"` // #rrggbb, empty for the frontend's default
+	AccentColor  string `json:"accent_color"`
+	HasBanner    bool   `json:"has_banner"`
+	// BannerURL is where the public page loads the banner from; only the public
+	// endpoint fills it in
+	BannerURL string `json:"banner_url,omitempty"`
+}
+
+// BusinessBanner is a stored banner image, served on the public page
+type BusinessBanner struct {
+	ContentType string
+	Content     []byte
+}
+
+// BusinessSummary is the part of a business shown to its students, without the owner's contact details
+type BusinessSummary struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Location string `json:"location"`
+	IsOpen   bool   `json:"is_open"`
 }
 
 type CreateBusinessRequest struct {
 	Name      string `json:"name" binding:"required"`
-	Slug      string `json:"slug" binding:"required"`
+	Slug      string `json:"slug"` // derived from the name when empty
 	OwnerName string `json:"owner_name" binding:"required"`
 	Email     string `json:"email" binding:"required,email"`
 	Phone     string `json:"phone"`
 	Location  string `json:"location"`
-	Password  string `json:"password" binding:"required,min=6"`
+	// Password is optional; the owner is emailed a link to set their own either way
+	Password  string `json:"password" binding:"omitempty,min=6"`
 	PackageID *uint  `json:"package_id"` // optional
+	Timezone  string `json:"timezone"`   // IANA name such as Asia/Kolkata; the server's default when empty
+	// Bootstrap seeds a default batch, student fields and a welcome announcement;
+	// SampleData adds sample students on top, removable through purge-sample-data
+	Bootstrap  bool `json:"bootstrap"`
+	SampleData bool `json:"sample_data"`
 }
 
 type UpdateBusinessRequest struct {
-	Name      string `json:"name"`
-	Slug      string `json:"slug"`
-	OwnerName string `json:"owner_name"`
-	Email     string `json:"email" binding:"omitempty,email"`
-	Phone     string `json:"phone"`
-	Location  string `json:"location"`
-	Password  string `json:"password" binding:"omitempty,min=6"`
-	PackageID *uint  `json:"package_id"`
-	Status    *int   `json:"status"` // pointer to allow null/zero values
+	Name      string  `json:"name"`
+	Slug      string  `json:"slug"`
+	OwnerName string  `json:"owner_name"`
+	Email     string  `json:"email" binding:"omitempty,email"`
+	Phone     string  `json:"phone"`
+	Location  string  `json:"location"`
+	Password  string  `json:"password" binding:"omitempty,min=6"`
+	PackageID *uint   `json:"package_id"`
+	Status    *Status `json:"status"` // pointer to allow null/zero values
+	IsOpen    *bool   `json:"is_open"`
+	Timezone  *string `json:"timezone"` // IANA name such as Asia/Kolkata
+	Version   *uint   `json:"version"`  // version the update is based on, unless sent as If-Match
+
+	// Theme colors as #rgb or #rrggbb; an empty string resets to the default
+	ThemePrimaryColor *string `json:"theme_primary_color"`
+	ThemeAccentColor  *string `json:"theme_accent_color"`
+}
+
+// UpdateMyBusinessRequest is what an owner may change on their own business. Account
+// status, slug and package are managed by admins; the owner's password is changed
+// on their user profile or through a reset link.
+type UpdateMyBusinessRequest struct {
+	Name      string  `json:"name"`
+	OwnerName string  `json:"owner_name"`
+	Email     string  `json:"email" binding:"omitempty,email"`
+	Phone     string  `json:"phone"`
+	Location  string  `json:"location"`
+	IsOpen    *bool   `json:"is_open"`  // open or close the centre to the public
+	Timezone  *string `json:"timezone"` // IANA name such as Asia/Kolkata
+
+	// Theme colors as #rgb or #rrggbb; an empty string resets to the default
+	ThemePrimaryColor *string `json:"theme_primary_color"`
+	ThemeAccentColor  *string `json:"theme_accent_color"`
 }
 
 type AssignPackageRequest struct {
 	PackageID uint `json:"package_id" binding:"required"`
 }
+
+// DeleteBusinessOptions controls what happens to the records a business owns
+// when it is deleted
+type DeleteBusinessOptions struct {
+	Cascade bool `form:"cascade" json:"cascade"` // also remove teachers, students, attendance and fees
+}
+
+// BusinessDependentsError refuses a business deletion that would leave records
+// pointing at a business that no longer exists
+type BusinessDependentsError struct {
+	Counts map[string]int64
+}
+
+func (e *BusinessDependentsError) Error() string {
+	return fmt.Sprintf("business has %d teachers, %d students, %d attendance records, %d fee plans and %d fee payments; delete with cascade to remove them",
+		e.Counts["teachers"], e.Counts["students"], e.Counts["attendance"], e.Counts["fee_plans"], e.Counts["fee_payments"])
+}
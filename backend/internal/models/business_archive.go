@@ -0,0 +1,199 @@
+package models
+
+import (
+	"time"
+)
+
+// BusinessArchiveFormat identifies a business archive and BusinessArchiveVersion is the
+// version of its layout written today. Bump the version whenever a file or field changes
+// meaning, and teach the importer to read the versions before it.
+const (
+	BusinessArchiveFormat  = "coaching-business-archive"
+	BusinessArchiveVersion = 1
+)
+
+// Files of a business archive, each a JSON document
+const (
+	ArchiveManifestFile          = "manifest.json"
+	ArchiveBusinessFile          = "business.json"
+	ArchiveOwnerFile             = "owner.json"
+	ArchiveSubjectsFile          = "subjects.json"
+	ArchiveBatchesFile           = "batches.json"
+	ArchiveStudentFieldsFile     = "student_fields.json"
+	ArchiveStudentGradesFile     = "student_grades.json"
+	ArchiveTeachersFile          = "teachers.json"
+	ArchiveStudentsFile          = "students.json"
+	ArchiveStudentAttendanceFile = "student_attendance.json"
+	ArchiveTeacherAttendanceFile = "teacher_attendance.json"
+	ArchiveFeePlansFile          = "fee_plans.json"
+	ArchiveFeePaymentsFile       = "fee_payments.json"
+)
+
+// ArchiveManifest describes a business archive. IDs inside the archive are the IDs of
+// the exported business and only link its records to each other.
+type ArchiveManifest struct {
+	Format           string           `json:"format"`
+	Version          int              `json:"version"`
+	ExportedAt       time.Time        `json:"exported_at"`
+	SourceBusinessID uint             `json:"source_business_id"`
+	Counts           map[string]int64 `json:"counts"` // records in each file
+}
+
+type ArchiveBusiness struct {
+	Name                string `json:"name"`
+	Slug                string `json:"slug"`
+	OwnerName           string `json:"owner_name"`
+	Email               string `json:"email"`
+	Phone               string `json:"phone"`
+	Location            string `json:"location"`
+	Status              Status `json:"status"`
+	Timezone            string `json:"timezone,omitempty"` // missing from archives written before businesses had one
+	StrictStudentFields bool   `json:"strict_student_fields"`
+	SMSNotifications    bool   `json:"sms_notifications"`
+}
+
+// ArchiveUser is a login account, without its password
+type ArchiveUser struct {
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Phone  string `json:"phone"`
+	Status Status `json:"status"`
+}
+
+type ArchiveSubject struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ArchiveBatch struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	StartDate string `json:"start_date,omitempty"` // YYYY-MM-DD
+	EndDate   string `json:"end_date,omitempty"`   // YYYY-MM-DD
+	TeacherID *uint  `json:"teacher_id"`
+}
+
+type ArchiveStudentField struct {
+	Key      string `json:"key"`
+	Label    string `json:"label"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+type ArchiveStudentGrade struct {
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+type ArchiveTeacher struct {
+	ID              uint                  `json:"id"`
+	User            ArchiveUser           `json:"user"`
+	Name            string                `json:"name"`
+	Salary          float64               `json:"salary"`
+	Qualification   string                `json:"qualification"`
+	Experience      string                `json:"experience"`
+	ExperienceYears *float64              `json:"experience_years"`
+	Description     string                `json:"description"`
+	Status          Status                `json:"status"`
+	SubjectIDs      []uint                `json:"subject_ids"`
+	Availability    []ArchiveAvailability `json:"availability"`
+}
+
+type ArchiveAvailability struct {
+	Weekday   int    `json:"weekday"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+type ArchiveStudent struct {
+	ID             uint              `json:"id"`
+	User           ArchiveUser       `json:"user"`
+	Name           string            `json:"name"`
+	BatchID        *uint             `json:"batch_id"`
+	EnrolledOn     string            `json:"enrolled_on,omitempty"`   // YYYY-MM-DD
+	DateOfBirth    string            `json:"date_of_birth,omitempty"` // YYYY-MM-DD
+	Gender         string            `json:"gender"`
+	Grade          string            `json:"grade"`
+	Information    JSONB             `json:"information"`
+	Status         Status            `json:"status"`
+	GuardianName   string            `json:"guardian_name"`
+	GuardianNumber string            `json:"guardian_number"`
+	GuardianEmail  string            `json:"guardian_email"`
+	Guardians      []ArchiveGuardian `json:"guardians"`
+}
+
+type ArchiveGuardian struct {
+	Name      string `json:"name"`
+	Relation  string `json:"relation"`
+	Phone     string `json:"phone"`
+	Email     string `json:"email"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+type ArchiveStudentAttendance struct {
+	StudentID uint   `json:"student_id"`
+	BatchID   *uint  `json:"batch_id"`
+	Date      string `json:"date"` // YYYY-MM-DD
+	Status    string `json:"status"`
+	Note      string `json:"note"`
+}
+
+type ArchiveTeacherAttendance struct {
+	TeacherID uint   `json:"teacher_id"`
+	Date      string `json:"date"` // YYYY-MM-DD
+	Status    string `json:"status"`
+	Note      string `json:"note"`
+}
+
+type ArchiveFeePlan struct {
+	ID        uint    `json:"id"`
+	StudentID *uint   `json:"student_id"`
+	BatchID   *uint   `json:"batch_id"`
+	Name      string  `json:"name"`
+	Amount    float64 `json:"amount"`
+	Frequency string  `json:"frequency"`
+	DueDay    int     `json:"due_day"`
+	StartDate string  `json:"start_date"`         // YYYY-MM-DD
+	EndDate   string  `json:"end_date,omitempty"` // YYYY-MM-DD
+	Status    int     `json:"status"`
+}
+
+type ArchiveFeePayment struct {
+	StudentID uint    `json:"student_id"`
+	FeePlanID *uint   `json:"fee_plan_id"`
+	Amount    float64 `json:"amount"`
+	PaidOn    string  `json:"paid_on"` // YYYY-MM-DD
+	Mode      string  `json:"mode"`
+	Reference string  `json:"reference"`
+	Note      string  `json:"note"`
+}
+
+// ImportArchiveRequest optionally replaces the identity of the imported business, for
+// when the archived one still exists
+type ImportArchiveRequest struct {
+	Name       string `form:"name"`
+	Slug       string `form:"slug"`
+	Email      string `form:"email" binding:"omitempty,email"`       // business and owner login email
+	OwnerEmail string `form:"owner_email" binding:"omitempty,email"` // owner login email, when it differs
+	// Bootstrap seeds the defaults of a new business the archive doesn't already cover
+	Bootstrap bool `form:"bootstrap"`
+}
+
+// ImportArchiveResult is the business an archive was imported into
+type ImportArchiveResult struct {
+	BusinessID uint             `json:"business_id"`
+	Slug       string           `json:"slug"`
+	Version    int              `json:"version"` // of the archive
+	Counts     map[string]int64 `json:"counts"`  // records imported from each file
+}
+
+// ArchiveValidationError lists everything wrong with an archive, found before anything
+// was imported
+type ArchiveValidationError struct {
+	Problems []string
+}
+
+func (e *ArchiveValidationError) Error() string {
+	return "archive is not valid"
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// BusinessPackageHistory records a change of a business's package. A nil package ID
+// means the business had no package on that side of the change.
+type BusinessPackageHistory struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	BusinessID   uint      `json:"business_id" gorm:"not null;index"`
+	OldPackageID *uint     `json:"old_package_id" gorm:"default:null"`
+	NewPackageID *uint     `json:"new_package_id" gorm:"default:null"`
+	ChangedBy    *uint     `json:"changed_by" gorm:"default:null"` // user ID of the admin, if known
+	CreatedOn    time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+}
+
+// TableName overrides the table name
+func (BusinessPackageHistory) TableName() string {
+	return "business_package_history"
+}
+
+// BulkRemovePackageRequest takes the package off each listed business
+type BulkRemovePackageRequest struct {
+	BusinessIDs []uint `json:"business_ids" binding:"required,min=1"`
+}
+
+// SwapPackageRequest moves every business on one package to another
+type SwapPackageRequest struct {
+	FromPackageID uint `json:"from_package_id" binding:"required"`
+	ToPackageID   uint `json:"to_package_id" binding:"required"`
+}
+
+// BulkPackageChangeResponse reports how many businesses a bulk package change touched
+type BulkPackageChangeResponse struct {
+	Updated int64 `json:"updated"`
+}
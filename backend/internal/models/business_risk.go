@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Signals of a business likely to churn, as listed in AtRiskBusiness.Signals
+const (
+	RiskNoNewStudents   = "no_new_students"  // no student added within the threshold
+	RiskOwnerInactive   = "owner_inactive"   // the owner hasn't logged in within the threshold
+	RiskPackageExpiring = "package_expiring" // the package expires within the threshold, or has expired
+)
+
+// AtRiskBusiness is an active business showing at least one churn signal. Score is the
+// number of signals it triggered.
+type AtRiskBusiness struct {
+	ID               uint       `json:"id"`
+	Name             string     `json:"name"`
+	Slug             string     `json:"slug"`
+	Email            string     `json:"email"`
+	OwnerName        string     `json:"owner_name"`
+	PackageID        *uint      `json:"package_id"`
+	PackageExpiresAt *time.Time `json:"package_expires_at"`
+	LastStudentAt    *time.Time `json:"last_student_at"`     // when the newest student was added, nil without students
+	OwnerLastLoginAt *time.Time `json:"owner_last_login_at"` // nil when no login was recorded
+	CreatedOn        time.Time  `json:"created_on"`
+	Signals          []string   `json:"signals" gorm:"-"`
+	Score            int        `json:"score"`
+}
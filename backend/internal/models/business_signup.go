@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+)
+
+// Sign-up statuses. A sign-up waits as pending until an admin approves it, which creates
+// the business and its owner's account, or rejects it with a reason.
+const (
+	SignupStatusPending  = "pending"
+	SignupStatusApproved = "approved"
+	SignupStatusRejected = "rejected"
+)
+
+// PendingSignup is a business that signed up on its own and waits for an admin. Nothing
+// else is created until it is approved; its email stays reserved while it is pending, by
+// idx_pending_signup_email.
+type PendingSignup struct {
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	Name      string `json:"name" gorm:"not null"`
+	Slug      string `json:"slug"` // requested slug, derived from the name on approval when empty
+	OwnerName string `json:"owner_name" gorm:"not null"`
+	Email     string `json:"email" gorm:"not null"`
+	Phone     string `json:"phone"`
+	Location  string `json:"location"`
+	Timezone  string `json:"timezone" gorm:"type:varchar(64);not null;default:'UTC'"`
+	Message   string `json:"message" gorm:"type:text"` // anything the owner wants the admins to know
+	Status    string `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	// Set when an admin reviews the sign-up
+	RejectReason string     `json:"reject_reason" gorm:"type:text"`
+	ReviewedBy   *uint      `json:"reviewed_by" gorm:"default:null"`
+	ReviewedAt   *time.Time `json:"reviewed_at" gorm:"default:null"`
+	BusinessID   *uint      `json:"business_id" gorm:"default:null"` // the business created on approval
+	CreatedOn    time.Time  `json:"created_on" gorm:"column:created_on;autoCreateTime;index"`
+	UpdatedOn    time.Time  `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+}
+
+// TableName overrides the table name
+func (PendingSignup) TableName() string {
+	return "pending_signups"
+}
+
+// BusinessSignupRequest is what a prospective business sends to sign up
+type BusinessSignupRequest struct {
+	Name      string `json:"name" binding:"required,max=255"`
+	Slug      string `json:"slug" binding:"max=100"`
+	OwnerName string `json:"owner_name" binding:"required,max=255"`
+	Email     string `json:"email" binding:"required,email,max=255"`
+	Phone     string `json:"phone"`
+	Location  string `json:"location" binding:"max=255"`
+	Timezone  string `json:"timezone"` // IANA name such as Asia/Kolkata; the server's default when empty
+	Message   string `json:"message" binding:"max=2000"`
+}
+
+// ApproveBusinessSignupRequest sets up the business an approval creates, like the same
+// fields of CreateBusinessRequest
+type ApproveBusinessSignupRequest struct {
+	PackageID  *uint `json:"package_id"`
+	Bootstrap  bool  `json:"bootstrap"`
+	SampleData bool  `json:"sample_data"`
+}
+
+// RejectBusinessSignupRequest gives the reason emailed to the owner
+type RejectBusinessSignupRequest struct {
+	Reason string `json:"reason" binding:"required,max=2000"`
+}
+
+// BusinessSignupReceived is what the public endpoint returns; the rest of the sign-up is
+// only shown to admins
+type BusinessSignupReceived struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status"`
+}
+
+// ApprovedBusinessSignup is an approved sign-up with the business it created
+type ApprovedBusinessSignup struct {
+	Signup   PendingSignup    `json:"signup"`
+	Business BusinessResponse `json:"business"`
+}
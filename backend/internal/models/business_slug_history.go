@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// BusinessSlugHistory records a change of a business's slug
+type BusinessSlugHistory struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	BusinessID uint      `json:"business_id" gorm:"not null;index"`
+	OldSlug    string    `json:"old_slug" gorm:"not null"`
+	NewSlug    string    `json:"new_slug" gorm:"not null"`
+	ChangedBy  *uint     `json:"changed_by" gorm:"default:null"` // user ID of the admin, if known
+	CreatedOn  time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+}
+
+// TableName overrides the table name
+func (BusinessSlugHistory) TableName() string {
+	return "business_slug_history"
+}
+
+// ChangeSlugRequest sets a business's slug explicitly, or derives it from the name again
+type ChangeSlugRequest struct {
+	Slug       string `json:"slug" binding:"omitempty,max=100"`
+	Regenerate bool   `json:"regenerate"`
+}
+
+type ChangeSlugResponse struct {
+	Business *BusinessResponse     `json:"business"`
+	History  []BusinessSlugHistory `json:"history"`
+}
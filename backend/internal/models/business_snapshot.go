@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// BusinessSnapshot is how many active teachers and students a business had on a day, the
+// date in the business's time zone. The snapshot job overwrites the day's row each run, so
+// it ends up holding the counts of the day's last run.
+type BusinessSnapshot struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	BusinessID     uint      `json:"business_id" gorm:"not null;uniqueIndex:idx_business_snapshot_day"`
+	Date           time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_business_snapshot_day"`
+	ActiveTeachers int64     `json:"active_teachers" gorm:"not null;default:0"`
+	ActiveStudents int64     `json:"active_students" gorm:"not null;default:0"`
+	CreatedOn      time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn      time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+}
+
+// TableName overrides the table name
+func (BusinessSnapshot) TableName() string {
+	return "business_snapshot"
+}
+
+// BusinessTrendsQuery selects the days of a business's trends
+type BusinessTrendsQuery struct {
+	From string `form:"from"` // YYYY-MM-DD, inclusive, defaults to 29 days before to
+	To   string `form:"to"`   // YYYY-MM-DD, inclusive, defaults to today in the business's time zone
+}
+
+// TrendPoint is a business's counts on one day. Days without a snapshot carry the counts
+// of the latest snapshot before them, marked as filled; counts are null before the first
+// snapshot.
+type TrendPoint struct {
+	Date           string `json:"date" example:"2026-10-16"`
+	ActiveTeachers *int64 `json:"active_teachers"`
+	ActiveStudents *int64 `json:"active_students"`
+	Filled         bool   `json:"filled"`
+}
+
+// BusinessTrends is the daily series of a business's active teachers and students
+type BusinessTrends struct {
+	BusinessID uint         `json:"business_id"`
+	From       string       `json:"from" example:"2026-09-17"`
+	To         string       `json:"to" example:"2026-10-16"`
+	Points     []TrendPoint `json:"points"`
+}
+
+// SnapshotRunResult counts what one run of the snapshot job wrote
+type SnapshotRunResult struct {
+	Businesses int       `json:"businesses"` // snapshots written, one per business
+	RanAt      time.Time `json:"ran_at"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// CalendarFeedResponse is a newly issued calendar feed token. The URL embeds it, since
+// calendar clients can't send an Authorization header.
+type CalendarFeedResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+)
+
+// Classes of inconsistency between users, businesses, teachers and students, left behind
+// by flows that once saved related rows outside a transaction
+const (
+	ConsistencyBusinessWithoutUser    = "business_without_user"          // active business whose owner user is missing
+	ConsistencyOwnerWithoutBusiness   = "business_user_without_business" // active user with the business role but no business
+	ConsistencyTeacherWithoutBusiness = "teacher_without_business"       // active teacher whose business is missing
+	ConsistencyTeacherWithoutUser     = "teacher_without_user"           // active teacher whose user is missing
+	ConsistencyStudentWithoutBusiness = "student_without_business"       // active student whose business is missing
+	ConsistencyStudentWithoutUser     = "student_without_user"           // active student whose user is missing
+)
+
+// ConsistencyClasses lists every class, in the order checks and repairs run. Relinking
+// businesses comes before deactivating owners without one, so a relinked owner is kept.
+var ConsistencyClasses = []string{
+	ConsistencyBusinessWithoutUser,
+	ConsistencyOwnerWithoutBusiness,
+	ConsistencyTeacherWithoutBusiness,
+	ConsistencyTeacherWithoutUser,
+	ConsistencyStudentWithoutBusiness,
+	ConsistencyStudentWithoutUser,
+}
+
+// Repairs applied to inconsistent rows
+const (
+	ConsistencyRepairDeactivated = "deactivated"
+	ConsistencyRepairRelinked    = "relinked"
+)
+
+// ConsistencyIssue is one class of inconsistency and how many rows it affects
+type ConsistencyIssue struct {
+	Class       string `json:"class"`
+	Description string `json:"description"`
+	Entity      string `json:"entity"` // table the affected rows are in
+	Count       int64  `json:"count"`
+	SampleIDs   []uint `json:"sample_ids"` // IDs of the first affected rows
+	Repair      string `json:"repair"`     // what a repair does about it
+}
+
+type ConsistencyReport struct {
+	CheckedAt time.Time          `json:"checked_at"`
+	Total     int64              `json:"total"`  // affected rows across all classes
+	Issues    []ConsistencyIssue `json:"issues"` // every class, including the clean ones
+}
+
+type ConsistencyRepairRequest struct {
+	Classes []string `json:"classes" binding:"required,min=1,dive,oneof=business_without_user business_user_without_business teacher_without_business teacher_without_user student_without_business student_without_user"`
+	// DryRun reports what would change without changing it; true unless set to false
+	DryRun *bool `json:"dry_run"`
+}
+
+// ConsistencyChange is a change a repair made, or would make in a dry run
+type ConsistencyChange struct {
+	Class  string `json:"class"`
+	Entity string `json:"entity"`
+	ID     uint   `json:"id"`
+	Action string `json:"action"` // deactivated, relinked
+	Detail string `json:"detail,omitempty"`
+}
+
+type ConsistencyRepairResult struct {
+	DryRun   bool                `json:"dry_run"`
+	Repaired map[string]int      `json:"repaired"` // changes by class
+	Changes  []ConsistencyChange `json:"changes"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// PrintedDocument is a rendered document ready to be sent to the client
+type PrintedDocument struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
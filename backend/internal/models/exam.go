@@ -0,0 +1,164 @@
+package models
+
+import (
+	"time"
+)
+
+// DefaultPassPercentage is the share of max marks needed to pass an exam without its own pass marks
+const DefaultPassPercentage = 40.0
+
+type Exam struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	BusinessID uint      `json:"business_id" gorm:"not null;index"`
+	BatchID    *uint     `json:"batch_id" gorm:"index;default:null"`   // only students of this batch can have results
+	SubjectID  *uint     `json:"subject_id" gorm:"index;default:null"` // subject the exam tests
+	Name       string    `json:"name" gorm:"not null"`
+	Date       time.Time `json:"date" gorm:"type:date;not null;index"`
+	MaxMarks   float64   `json:"max_marks" gorm:"type:decimal(6,2);not null"`
+	PassMarks  *float64  `json:"pass_marks" gorm:"type:decimal(6,2)"` // defaults to DefaultPassPercentage of max marks
+	CreatedBy  uint      `json:"created_by" gorm:"not null"`          // user ID of the actor
+	CreatedOn  time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn  time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+
+	// Relationships
+	Batch   *Batch   `json:"batch,omitempty" gorm:"foreignKey:BatchID"`
+	Subject *Subject `json:"subject,omitempty" gorm:"foreignKey:SubjectID"`
+}
+
+// TableName overrides the table name
+func (Exam) TableName() string {
+	return "exam"
+}
+
+// EffectivePassMarks returns the marks needed to pass the exam
+func (e Exam) EffectivePassMarks() float64 {
+	if e.PassMarks != nil {
+		return *e.PassMarks
+	}
+	return e.MaxMarks * DefaultPassPercentage / 100
+}
+
+type ExamResult struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ExamID     uint      `json:"exam_id" gorm:"not null;uniqueIndex:idx_exam_result_student"`
+	StudentID  uint      `json:"student_id" gorm:"not null;uniqueIndex:idx_exam_result_student;index"`
+	Marks      float64   `json:"marks" gorm:"type:decimal(6,2);not null"`
+	Remarks    string    `json:"remarks"`
+	RecordedBy uint      `json:"recorded_by" gorm:"not null"` // user ID of the actor
+	CreatedOn  time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn  time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+
+	// Relationships
+	Exam    Exam    `json:"-" gorm:"foreignKey:ExamID"`
+	Student Student `json:"-" gorm:"foreignKey:StudentID"`
+}
+
+// TableName overrides the table name
+func (ExamResult) TableName() string {
+	return "exam_result"
+}
+
+type ExamResponse struct {
+	ID          uint      `json:"id"`
+	BusinessID  uint      `json:"business_id"`
+	BatchID     *uint     `json:"batch_id"`
+	BatchName   string    `json:"batch_name,omitempty"`
+	SubjectID   *uint     `json:"subject_id"`
+	SubjectName string    `json:"subject_name,omitempty"`
+	Name        string    `json:"name"`
+	Date        string    `json:"date"`
+	MaxMarks    float64   `json:"max_marks"`
+	PassMarks   float64   `json:"pass_marks"`
+	ResultCount int64     `json:"result_count"`
+	CreatedBy   uint      `json:"created_by"`
+	CreatedOn   time.Time `json:"created_on"`
+}
+
+type CreateExamRequest struct {
+	Name      string   `json:"name" binding:"required"`
+	BatchID   *uint    `json:"batch_id"`
+	SubjectID *uint    `json:"subject_id"`
+	Date      string   `json:"date" binding:"required"` // YYYY-MM-DD
+	MaxMarks  float64  `json:"max_marks" binding:"required,gt=0"`
+	PassMarks *float64 `json:"pass_marks" binding:"omitempty,min=0"`
+}
+
+type UpdateExamRequest struct {
+	Name      string   `json:"name"`
+	BatchID   *uint    `json:"batch_id"`
+	SubjectID *uint    `json:"subject_id"`
+	Date      string   `json:"date"` // YYYY-MM-DD
+	MaxMarks  *float64 `json:"max_marks" binding:"omitempty,gt=0"`
+	PassMarks *float64 `json:"pass_marks" binding:"omitempty,min=0"`
+}
+
+type ExamResultEntry struct {
+	StudentID uint     `json:"student_id" binding:"required"`
+	Marks     *float64 `json:"marks" binding:"required,min=0"`
+	Remarks   string   `json:"remarks"`
+}
+
+// RecordExamResultsRequest records marks for several students; a student's earlier
+// result for the exam is replaced
+type RecordExamResultsRequest struct {
+	Results []ExamResultEntry `json:"results" binding:"required,min=1,dive"`
+}
+
+type ExamResultResponse struct {
+	ID          uint      `json:"id"`
+	ExamID      uint      `json:"exam_id"`
+	StudentID   uint      `json:"student_id"`
+	StudentName string    `json:"student_name,omitempty"`
+	Marks       float64   `json:"marks"`
+	Percentage  float64   `json:"percentage"`
+	Passed      bool      `json:"passed"`
+	Remarks     string    `json:"remarks"`
+	RecordedBy  uint      `json:"recorded_by"`
+	UpdatedOn   time.Time `json:"updated_on"`
+}
+
+type ExamStats struct {
+	ExamID            uint    `json:"exam_id"`
+	MaxMarks          float64 `json:"max_marks"`
+	PassMarks         float64 `json:"pass_marks"`
+	Appeared          int64   `json:"appeared"`
+	Passed            int64   `json:"passed"`
+	PassPercentage    float64 `json:"pass_percentage"`
+	AverageMarks      float64 `json:"average_marks"`
+	AveragePercentage float64 `json:"average_percentage"`
+	HighestMarks      float64 `json:"highest_marks"`
+	LowestMarks       float64 `json:"lowest_marks"`
+}
+
+type StudentExamReportEntry struct {
+	ExamID      uint    `json:"exam_id"`
+	Name        string  `json:"name"`
+	Date        string  `json:"date"`
+	SubjectID   *uint   `json:"subject_id"`
+	SubjectName string  `json:"subject_name,omitempty"`
+	MaxMarks    float64 `json:"max_marks"`
+	Marks       float64 `json:"marks"`
+	Percentage  float64 `json:"percentage"`
+	Passed      bool    `json:"passed"`
+	Remarks     string  `json:"remarks"`
+}
+
+type SubjectPerformance struct {
+	SubjectID         *uint   `json:"subject_id"`
+	SubjectName       string  `json:"subject_name,omitempty"`
+	ExamCount         int     `json:"exam_count"`
+	AveragePercentage float64 `json:"average_percentage"`
+}
+
+// StudentExamReport lists a student's results oldest first so progress can be charted
+type StudentExamReport struct {
+	StudentID         uint                     `json:"student_id"`
+	StudentName       string                   `json:"student_name"`
+	From              string                   `json:"from,omitempty"`
+	To                string                   `json:"to,omitempty"`
+	ExamCount         int                      `json:"exam_count"`
+	Passed            int                      `json:"passed"`
+	AveragePercentage float64                  `json:"average_percentage"`
+	Exams             []StudentExamReportEntry `json:"exams"`
+	BySubject         []SubjectPerformance     `json:"by_subject"`
+}
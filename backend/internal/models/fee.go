@@ -0,0 +1,175 @@
+package models
+
+import (
+	"time"
+)
+
+// Fee plan frequencies
+const (
+	FeeFrequencyOneTime   = "one_time"
+	FeeFrequencyMonthly   = "monthly"
+	FeeFrequencyQuarterly = "quarterly"
+	FeeFrequencyYearly    = "yearly"
+)
+
+// Payment modes
+const (
+	PaymentModeCash         = "cash"
+	PaymentModeCard         = "card"
+	PaymentModeUPI          = "upi"
+	PaymentModeBankTransfer = "bank_transfer"
+	PaymentModeCheque       = "cheque"
+	PaymentModeOther        = "other"
+)
+
+// FeePlan is a recurring or one-time fee charged to a single student or to
+// every student of a batch
+type FeePlan struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	BusinessID uint       `json:"business_id" gorm:"not null;index"`
+	StudentID  *uint      `json:"student_id" gorm:"index;default:null"`
+	BatchID    *uint      `json:"batch_id" gorm:"index;default:null"`
+	Name       string     `json:"name" gorm:"not null"`
+	Amount     float64    `json:"amount" gorm:"type:decimal(10,2);not null"`
+	Frequency  string     `json:"frequency" gorm:"type:varchar(20);not null"` // one_time, monthly, quarterly, yearly
+	DueDay     int        `json:"due_day" gorm:"not null;default:1"`          // day of the month installments fall due
+	StartDate  time.Time  `json:"start_date" gorm:"type:date;not null"`
+	EndDate    *time.Time `json:"end_date" gorm:"type:date"`
+	Status     int        `json:"status" gorm:"default:1"`    // 1 = active, 0 = inactive
+	CreatedBy  uint       `json:"created_by" gorm:"not null"` // user ID of the actor
+	CreatedOn  time.Time  `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn  time.Time  `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+
+	// Relationships
+	Student *Student `json:"-" gorm:"foreignKey:StudentID"`
+}
+
+// TableName overrides the table name
+func (FeePlan) TableName() string {
+	return "fee_plan"
+}
+
+type FeePayment struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	BusinessID uint      `json:"business_id" gorm:"not null;index"`
+	StudentID  uint      `json:"student_id" gorm:"not null;index"`
+	FeePlanID  *uint     `json:"fee_plan_id" gorm:"index;default:null"` // plan the payment is allocated to, if any
+	Amount     float64   `json:"amount" gorm:"type:decimal(10,2);not null"`
+	PaidOn     time.Time `json:"paid_on" gorm:"type:date;not null;index"`
+	Mode       string    `json:"mode" gorm:"type:varchar(20);not null"` // cash, card, upi, bank_transfer, cheque, other
+	Reference  string    `json:"reference"`
+	Note       string    `json:"note"`
+	RecordedBy uint      `json:"recorded_by" gorm:"not null"` // user ID of the actor, 0 for payments made online
+	// ExternalID is "<provider>:<payment ID>" for payments confirmed by a payment gateway,
+	// so the same payment is never credited twice
+	ExternalID string    `json:"-" gorm:"type:varchar(120);not null;default:'';uniqueIndex:idx_fee_payment_external,where:external_id <> ''"`
+	CreatedOn  time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+
+	// Relationships
+	Student Student  `json:"-" gorm:"foreignKey:StudentID"`
+	FeePlan *FeePlan `json:"-" gorm:"foreignKey:FeePlanID"`
+}
+
+// TableName overrides the table name
+func (FeePayment) TableName() string {
+	return "fee_payment"
+}
+
+type FeePlanResponse struct {
+	ID          uint      `json:"id"`
+	BusinessID  uint      `json:"business_id"`
+	StudentID   *uint     `json:"student_id"`
+	StudentName string    `json:"student_name,omitempty"`
+	BatchID     *uint     `json:"batch_id"`
+	Name        string    `json:"name"`
+	Amount      float64   `json:"amount"`
+	Frequency   string    `json:"frequency"`
+	DueDay      int       `json:"due_day"`
+	StartDate   string    `json:"start_date"`
+	EndDate     string    `json:"end_date,omitempty"`
+	Status      int       `json:"status"`
+	CreatedBy   uint      `json:"created_by"`
+	CreatedOn   time.Time `json:"created_on"`
+}
+
+type FeePaymentResponse struct {
+	ID          uint      `json:"id"`
+	BusinessID  uint      `json:"business_id"`
+	StudentID   uint      `json:"student_id"`
+	StudentName string    `json:"student_name,omitempty"`
+	FeePlanID   *uint     `json:"fee_plan_id"`
+	Amount      float64   `json:"amount"`
+	PaidOn      string    `json:"paid_on"`
+	Mode        string    `json:"mode"`
+	Reference   string    `json:"reference"`
+	Note        string    `json:"note"`
+	RecordedBy  uint      `json:"recorded_by"`
+	CreatedOn   time.Time `json:"created_on"`
+}
+
+// CreateFeePlanRequest creates a plan for exactly one of a student or a batch
+type CreateFeePlanRequest struct {
+	Name      string  `json:"name" binding:"required"`
+	StudentID *uint   `json:"student_id"`
+	BatchID   *uint   `json:"batch_id"`
+	Amount    float64 `json:"amount" binding:"required,gt=0"`
+	Frequency string  `json:"frequency" binding:"required,oneof=one_time monthly quarterly yearly"`
+	DueDay    int     `json:"due_day" binding:"omitempty,min=1,max=28"` // defaults to 1
+	StartDate string  `json:"start_date" binding:"required"`            // YYYY-MM-DD
+	EndDate   string  `json:"end_date"`                                 // YYYY-MM-DD
+}
+
+type RecordFeePaymentRequest struct {
+	StudentID uint    `json:"student_id" binding:"required"`
+	FeePlanID *uint   `json:"fee_plan_id"`
+	Amount    float64 `json:"amount" binding:"required,gt=0"`
+	PaidOn    string  `json:"paid_on" binding:"required"` // YYYY-MM-DD
+	Mode      string  `json:"mode" binding:"required,oneof=cash card upi bank_transfer cheque other"`
+	Reference string  `json:"reference"`
+	Note      string  `json:"note"`
+}
+
+// FeePlanDue is what a student owes on one plan up to a date
+type FeePlanDue struct {
+	FeePlanID       uint    `json:"fee_plan_id"`
+	Name            string  `json:"name"`
+	Amount          float64 `json:"amount"`
+	Frequency       string  `json:"frequency"`
+	InstallmentsDue int     `json:"installments_due"`
+	AmountDue       float64 `json:"amount_due"`
+	AmountPaid      float64 `json:"amount_paid"`
+	Balance         float64 `json:"balance"`
+	NextDueDate     string  `json:"next_due_date,omitempty"`
+}
+
+// StudentFeeDues totals a student's fees up to a date. Payments not allocated to a
+// plan count towards the overall balance only.
+type StudentFeeDues struct {
+	StudentID   uint         `json:"student_id"`
+	StudentName string       `json:"student_name,omitempty"`
+	BatchID     *uint        `json:"batch_id"`
+	AsOf        string       `json:"as_of"`
+	TotalDue    float64      `json:"total_due"`
+	TotalPaid   float64      `json:"total_paid"`
+	Unallocated float64      `json:"unallocated_paid"`
+	Balance     float64      `json:"balance"`
+	Plans       []FeePlanDue `json:"plans"`
+}
+
+type FeeModeTotal struct {
+	Mode   string  `json:"mode"`
+	Count  int64   `json:"count"`
+	Amount float64 `json:"amount"`
+}
+
+type FeeSummary struct {
+	BusinessID       uint           `json:"business_id"`
+	From             string         `json:"from"`
+	To               string         `json:"to"`
+	Collected        float64        `json:"collected"`     // payments received in the range
+	PaymentCount     int64          `json:"payment_count"` // number of payments received in the range
+	DueInRange       float64        `json:"due_in_range"`  // installments falling due in the range
+	Outstanding      float64        `json:"outstanding"`   // unpaid balance of all students as of the end of the range
+	StudentsWithDues int            `json:"students_with_dues"`
+	ByMode           []FeeModeTotal `json:"by_mode"`
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+)
+
+// GuardianLink is a read-only link to a student's progress shared with their guardians.
+// The signed token handed out identifies the link by TokenID, so deleting or revoking the
+// link invalidates the token before it expires.
+type GuardianLink struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	StudentID  uint       `json:"student_id" gorm:"not null;index"`
+	BusinessID uint       `json:"business_id" gorm:"not null;index"`
+	TokenID    string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Label      string     `json:"label"` // e.g. who the link was sent to
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt  *time.Time `json:"revoked_at" gorm:"default:null"`
+	CreatedBy  uint       `json:"created_by" gorm:"not null"` // user ID of the actor
+	CreatedOn  time.Time  `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+}
+
+// TableName overrides the table name
+func (GuardianLink) TableName() string {
+	return "guardian_link"
+}
+
+// Active reports whether the link can still be used at the given time
+func (l GuardianLink) Active(at time.Time) bool {
+	return l.RevokedAt == nil && at.Before(l.ExpiresAt)
+}
+
+// DefaultGuardianLinkDays is how long a guardian link lasts unless set otherwise
+const DefaultGuardianLinkDays = 30
+
+type CreateGuardianLinkRequest struct {
+	Label         string `json:"label" binding:"max=100"`
+	ExpiresInDays int    `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
+
+type GuardianLinkResponse struct {
+	ID        uint       `json:"id"`
+	StudentID uint       `json:"student_id"`
+	Label     string     `json:"label"`
+	Token     string     `json:"token,omitempty"` // only returned when the link is created
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	Active    bool       `json:"active"`
+	CreatedBy uint       `json:"created_by"`
+	CreatedOn time.Time  `json:"created_on"`
+}
+
+// GuardianView is everything a guardian link shows. It only ever describes the link's
+// student and leaves out internal IDs.
+type GuardianView struct {
+	StudentName   string               `json:"student_name"`
+	BusinessName  string               `json:"business_name"`
+	BatchName     string               `json:"batch_name,omitempty"`
+	PhotoURL      string               `json:"photo_url,omitempty"` // served under the same token
+	Attendance    GuardianAttendance   `json:"attendance"`
+	RecentResults []GuardianExamResult `json:"recent_results"`
+	Fees          GuardianFeeBalance   `json:"fees"`
+	LinkExpiresAt time.Time            `json:"link_expires_at"`
+}
+
+// GuardianAttendance summarises the student's attendance over a recent period
+type GuardianAttendance struct {
+	From           string  `json:"from"`
+	To             string  `json:"to"`
+	Present        int64   `json:"present"`
+	Absent         int64   `json:"absent"`
+	Leave          int64   `json:"leave"`
+	TotalMarked    int64   `json:"total_marked"`
+	AttendanceRate float64 `json:"attendance_rate"`
+}
+
+type GuardianExamResult struct {
+	Exam        string  `json:"exam"`
+	Date        string  `json:"date"`
+	SubjectName string  `json:"subject_name,omitempty"`
+	MaxMarks    float64 `json:"max_marks"`
+	Marks       float64 `json:"marks"`
+	Percentage  float64 `json:"percentage"`
+	Passed      bool    `json:"passed"`
+}
+
+type GuardianFeeBalance struct {
+	AsOf      string  `json:"as_of"`
+	TotalDue  float64 `json:"total_due"`
+	TotalPaid float64 `json:"total_paid"`
+	Balance   float64 `json:"balance"`
+}
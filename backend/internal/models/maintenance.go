@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// MaintenanceState is the single row that switches the API into maintenance mode
+type MaintenanceState struct {
+	ID                uint      `json:"-" gorm:"primaryKey"`
+	Enabled           bool      `json:"enabled" gorm:"not null;default:false"`
+	Message           string    `json:"message"`
+	RetryAfterSeconds int       `json:"retry_after_seconds" gorm:"not null;default:0"`
+	UpdatedBy         uint      `json:"updated_by"` // user ID of the admin who last toggled it
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (MaintenanceState) TableName() string {
+	return "maintenance_state"
+}
+
+// MaintenanceStateID is the primary key of the only maintenance_state row
+const MaintenanceStateID = 1
+
+// DefaultMaintenanceMessage is returned while maintenance is on and no message was set
+const DefaultMaintenanceMessage = "The service is undergoing maintenance, please try again shortly"
+
+type UpdateMaintenanceRequest struct {
+	Enabled           *bool  `json:"enabled" binding:"required"`
+	Message           string `json:"message" binding:"max=500"`
+	RetryAfterSeconds int    `json:"retry_after_seconds" binding:"omitempty,min=1,max=86400"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+)
+
+// Notification types
+const (
+	NotificationPackageExpiring = "package_expiring" // the business's package runs out soon
+	NotificationPackageChanged  = "package_changed"  // an admin assigned or removed the business's package
+	NotificationStatusChanged   = "status_changed"   // an admin activated or deactivated the business
+	NotificationBusinessSignup  = "business_signup"  // a business signed up and waits for an admin's approval
+)
+
+// Notification is an entry in a user's in-app notification feed
+type Notification struct {
+	ID      uint   `json:"id" gorm:"primaryKey"`
+	UserID  uint   `json:"user_id" gorm:"not null;index;index:idx_notification_unread,where:read_at IS NULL;uniqueIndex:idx_notification_dedup,where:dedup_key <> ''"`
+	Type    string `json:"type" gorm:"type:varchar(50);not null"`
+	Title   string `json:"title" gorm:"not null"`
+	Body    string `json:"body" gorm:"type:text"`
+	Payload JSONB  `json:"payload" gorm:"type:jsonb"` // IDs and values a client needs to link to the subject
+	// DedupKey makes repeated producers, such as the daily package expiry check, notify a
+	// user only once about the same thing
+	DedupKey  string     `json:"-" gorm:"type:varchar(100);not null;default:'';uniqueIndex:idx_notification_dedup,where:dedup_key <> ''"`
+	ReadAt    *time.Time `json:"read_at" gorm:"default:null"`
+	CreatedOn time.Time  `json:"created_on" gorm:"column:created_on;autoCreateTime;index"`
+}
+
+// TableName overrides the table name
+func (Notification) TableName() string {
+	return "notification"
+}
+
+// NewNotification is what other modules pass to the notification service; one
+// notification is created for each user
+type NewNotification struct {
+	UserIDs  []uint
+	Type     string
+	Title    string
+	Body     string
+	Payload  JSONB
+	DedupKey string // optional; users already notified with the same key are skipped
+}
+
+type NotificationResponse struct {
+	ID        uint       `json:"id"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Payload   JSONB      `json:"payload"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedOn time.Time  `json:"created_on"`
+}
@@ -4,13 +4,16 @@ import (
 	"time"
 )
 
+// Package is a plan of the global catalogue. Packages have no owning business: only
+// admins create and change them, businesses can read the active ones.
 type Package struct {
 	ID               uint      `json:"id" gorm:"primaryKey"`
 	Name             string    `json:"name" gorm:"not null;uniqueIndex"`
 	Price            float64   `json:"price" gorm:"not null"`
 	ValidationPeriod int       `json:"validation_period" gorm:"not null"`
 	Description      string    `json:"description"`
-	Status           int       `json:"Status" validate:"required"`
+	Status           Status    `json:"Status" validate:"required"`
+	Version          uint      `json:"version" gorm:"not null;default:1"` // bumped on every update, for optimistic locking
 	CreatedOn        time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
 	UpdatedOn        time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
 }
@@ -20,14 +23,32 @@ func (Package) TableName() string {
 	return "packages"
 }
 
+// ExpiresAt returns when the package runs out if it is assigned at the given time
+func (p Package) ExpiresAt(assignedAt time.Time) time.Time {
+	return assignedAt.AddDate(0, 0, p.ValidationPeriod)
+}
+
 type PackageResponse struct {
 	ID               uint      `json:"id"`
 	Name             string    `json:"name"`
 	Price            float64   `json:"price"`
 	ValidationPeriod int       `json:"validation_period"`
 	Description      string    `json:"description"`
-	Status           int       `json:"status"`
+	Status           Status    `json:"status"`
+	Version          uint      `json:"version"`
 	CreatedOn        time.Time `json:"created_on"`
+	UpdatedOn        time.Time `json:"updated_on"`
+	// PriceChange is only set by an update that changed the price
+	PriceChange *PackagePriceChange `json:"price_change,omitempty"`
+}
+
+// PackagePriceChange tells the admin changing a package's price how many active
+// businesses keep the terms they were assigned on
+type PackagePriceChange struct {
+	OldPrice float64 `json:"old_price"`
+	NewPrice float64 `json:"new_price"`
+	// ActiveAssignments counts the active businesses on the package at another price
+	ActiveAssignments int64 `json:"active_assignments"`
 }
 
 type CreatePackageRequest struct {
@@ -42,5 +63,6 @@ type UpdatePackageRequest struct {
 	Price            float64 `json:"price" binding:"min=0"`
 	ValidationPeriod int     `json:"validation_period" binding:"min=1"`
 	Description      string  `json:"description"`
-	Status           *int    `json:"status"` // pointer to allow null/zero values
+	Status           *Status `json:"status"`  // pointer to allow null/zero values
+	Version          *uint   `json:"version"` // version the update is based on, unless sent as If-Match
 }
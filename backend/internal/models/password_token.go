@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// Password token purposes
+const (
+	PasswordTokenWelcome = "welcome" // sent to new business owners to set their first password
+	PasswordTokenReset   = "reset"
+)
+
+// PasswordToken is a single-use link for setting a user's password. Only the SHA-256 hash
+// of the token is stored, so a leaked table can't be used to take over accounts.
+type PasswordToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Purpose   string     `json:"purpose" gorm:"type:varchar(20);not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at" gorm:"default:null"`
+	CreatedOn time.Time  `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+}
+
+// TableName overrides the table name
+func (PasswordToken) TableName() string {
+	return "password_token"
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"time"
+)
+
+// PayrollAdjustment is a one-off amount added to or taken off a teacher's pay for a
+// month, such as a bonus or an advance being recovered
+type PayrollAdjustment struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	BusinessID uint      `json:"business_id" gorm:"not null;index:idx_payroll_adjustment_month,priority:1"`
+	TeacherID  uint      `json:"teacher_id" gorm:"not null;index"`
+	Month      string    `json:"month" gorm:"type:varchar(7);not null;index:idx_payroll_adjustment_month,priority:2"` // YYYY-MM
+	Amount     float64   `json:"amount" gorm:"type:decimal(10,2);not null"`                                           // negative for deductions
+	Reason     string    `json:"reason" gorm:"not null"`
+	CreatedBy  uint      `json:"created_by" gorm:"not null"` // user ID of the actor
+	CreatedOn  time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+
+	// Relationships
+	Teacher Teacher `json:"-" gorm:"foreignKey:TeacherID"`
+}
+
+// TableName overrides the table name
+func (PayrollAdjustment) TableName() string {
+	return "payroll_adjustment"
+}
+
+type PayrollAdjustmentResponse struct {
+	ID          uint      `json:"id"`
+	BusinessID  uint      `json:"business_id"`
+	TeacherID   uint      `json:"teacher_id"`
+	TeacherName string    `json:"teacher_name,omitempty"`
+	Month       string    `json:"month"`
+	Amount      float64   `json:"amount"`
+	Reason      string    `json:"reason"`
+	CreatedBy   uint      `json:"created_by"`
+	CreatedOn   time.Time `json:"created_on"`
+}
+
+type CreatePayrollAdjustmentRequest struct {
+	TeacherID uint    `json:"teacher_id" binding:"required"`
+	Month     string  `json:"month" binding:"required"`  // YYYY-MM
+	Amount    float64 `json:"amount" binding:"required"` // negative for deductions
+	Reason    string  `json:"reason" binding:"required,max=255"`
+}
+
+// PayrollRun is a finalized month of a business. Its lines are what was paid out and
+// are returned as they were, whatever changes to salaries or attendance come later.
+type PayrollRun struct {
+	ID          uint          `json:"id" gorm:"primaryKey"`
+	BusinessID  uint          `json:"business_id" gorm:"not null;uniqueIndex:idx_payroll_run_month,priority:1"`
+	Month       string        `json:"month" gorm:"type:varchar(7);not null;uniqueIndex:idx_payroll_run_month,priority:2"` // YYYY-MM
+	WorkingDays int           `json:"working_days" gorm:"not null"`                                                       // days the daily rate was based on
+	FinalizedBy uint          `json:"finalized_by" gorm:"not null"`                                                       // user ID of the actor
+	FinalizedAt time.Time     `json:"finalized_at" gorm:"not null"`
+	Lines       []PayrollLine `json:"lines" gorm:"foreignKey:RunID"`
+}
+
+// TableName overrides the table name
+func (PayrollRun) TableName() string {
+	return "payroll_run"
+}
+
+// PayrollLine is what a teacher is owed for a month. Lines of a finalized month keep the
+// teacher's name, and aren't tied to the teacher, so they outlive the teacher's record.
+type PayrollLine struct {
+	ID               uint      `json:"-" gorm:"primaryKey"`
+	RunID            uint      `json:"-" gorm:"not null;index"`
+	TeacherID        uint      `json:"teacher_id" gorm:"not null"`
+	TeacherName      string    `json:"teacher_name" gorm:"not null"`
+	JoinedOn         time.Time `json:"joined_on" gorm:"type:date;not null"`                // joined the business, or transferred in
+	BaseSalary       float64   `json:"base_salary" gorm:"type:decimal(10,2);not null"`     // monthly salary
+	PaidDays         int       `json:"paid_days" gorm:"not null"`                          // calendar days of the month since joining
+	ProratedSalary   float64   `json:"prorated_salary" gorm:"type:decimal(10,2);not null"` // salary for the paid days
+	AbsentDays       int64     `json:"absent_days" gorm:"not null"`
+	DailyRate        float64   `json:"daily_rate" gorm:"type:decimal(10,2);not null"`
+	AbsenceDeduction float64   `json:"absence_deduction" gorm:"type:decimal(10,2);not null"`
+	Adjustments      float64   `json:"adjustments" gorm:"type:decimal(10,2);not null"` // sum of the month's adjustments
+	NetPay           float64   `json:"net_pay" gorm:"type:decimal(10,2);not null"`
+}
+
+// TableName overrides the table name
+func (PayrollLine) TableName() string {
+	return "payroll_line"
+}
+
+type PayrollTotals struct {
+	BaseSalary       float64 `json:"base_salary"`
+	ProratedSalary   float64 `json:"prorated_salary"`
+	AbsenceDeduction float64 `json:"absence_deduction"`
+	Adjustments      float64 `json:"adjustments"`
+	NetPay           float64 `json:"net_pay"`
+}
+
+// PayrollSummary is what a business owes its teachers for a month, worked out from the
+// current salaries, attendance and adjustments until the month is finalized
+type PayrollSummary struct {
+	BusinessID  uint          `json:"business_id"`
+	Month       string        `json:"month"`
+	DaysInMonth int           `json:"days_in_month"`
+	WorkingDays int           `json:"working_days"` // the daily rate is the monthly salary divided by this
+	Finalized   bool          `json:"finalized"`
+	FinalizedAt *time.Time    `json:"finalized_at,omitempty"`
+	FinalizedBy *uint         `json:"finalized_by,omitempty"`
+	Lines       []PayrollLine `json:"lines"`
+	Totals      PayrollTotals `json:"totals"`
+}
+
+type FinalizePayrollRequest struct {
+	Month string `json:"month" binding:"required"` // YYYY-MM
+}
+
+// PayrollSettings control how a business's payroll is worked out
+type PayrollSettings struct {
+	BusinessID uint `json:"business_id"`
+	// WorkingDays is what the monthly salary is divided by for the deduction of a day of
+	// absence; 0 divides by the days of each month
+	WorkingDays int `json:"working_days"`
+}
+
+type UpdatePayrollSettingsRequest struct {
+	WorkingDays *int `json:"working_days" binding:"required,min=0,max=31"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// PermissionsResponse tells a client who the caller is and what they may do, so
+// menus can follow the backend's route guards
+type PermissionsResponse struct {
+	UserID       uint            `json:"user_id"`
+	Role         string          `json:"role"`
+	BusinessID   *uint           `json:"business_id"` // own business, or the one a teacher or student belongs to
+	TeacherID    *uint           `json:"teacher_id"`
+	StudentID    *uint           `json:"student_id"`
+	Capabilities map[string]bool `json:"capabilities"` // can_<permission> for every known permission
+}
@@ -0,0 +1,12 @@
+package models
+
+// Photo is a stored student or teacher photo, served to the business and printed on ID cards
+type Photo struct {
+	ContentType string
+	Content     []byte
+}
+
+// PhotoResponse points at a newly uploaded photo
+type PhotoResponse struct {
+	PhotoURL string `json:"photo_url"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// Profile is one of the roles a user can act as: their primary role and any other
+// profile they hold, such as the teacher profile of a business owner who also teaches
+type Profile struct {
+	Role       UserRole `json:"role"`
+	Primary    bool     `json:"primary"`     // the role the user account was created with
+	Active     bool     `json:"active"`      // the profile the caller's token acts as
+	BusinessID *uint    `json:"business_id"` // own business, or the one the teacher or student belongs to
+	TeacherID  *uint    `json:"teacher_id"`
+	StudentID  *uint    `json:"student_id"`
+}
+
+// SwitchProfileRequest picks the profile a new token acts as
+type SwitchProfileRequest struct {
+	Role UserRole `json:"role" binding:"required"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// Sample record entity types
+const (
+	SampleEntityStudent = "student"
+	SampleEntityUser    = "user"
+)
+
+// SampleRecord marks a row seeded as sample data for a new business, so it can be purged
+// once the owner has had a look around
+type SampleRecord struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	BusinessID uint      `json:"business_id" gorm:"not null;index"`
+	EntityType string    `json:"entity_type" gorm:"type:varchar(20);not null"` // student, user
+	EntityID   uint      `json:"entity_id" gorm:"not null"`
+	CreatedOn  time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+}
+
+// TableName overrides the table name
+func (SampleRecord) TableName() string {
+	return "sample_record"
+}
+
+// PurgeSampleDataResponse counts the sample rows removed, by entity type
+type PurgeSampleDataResponse struct {
+	Removed map[string]int64 `json:"removed"`
+}
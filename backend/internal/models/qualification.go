@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// Qualification is a canonical teacher qualification. Global entries have no business
+// and are offered to every business; a business's custom entries only to that business.
+// Entries typed in as free text on a teacher are added as pending until reviewed.
+type Qualification struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	BusinessID *uint     `json:"business_id" gorm:"index;default:null"` // nil for global entries
+	Name       string    `json:"name" gorm:"not null"`
+	Key        string    `json:"key" gorm:"not null;index"` // name folded for matching: lowercase letters and digits only
+	Pending    bool      `json:"pending" gorm:"not null;default:false"`
+	CreatedOn  time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn  time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+}
+
+// TableName overrides the table name
+func (Qualification) TableName() string {
+	return "qualification"
+}
+
+type CreateQualificationRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type UpdateQualificationRequest struct {
+	Name    string `json:"name" binding:"max=100"`
+	Pending *bool  `json:"pending"` // false approves a pending entry
+}
+
+// QualificationMatch is a legacy qualification string the migration mapped to an entry
+type QualificationMatch struct {
+	BusinessID      uint   `json:"business_id"`
+	Value           string `json:"value"`
+	QualificationID uint   `json:"qualification_id"`
+	Name            string `json:"name"`
+	Teachers        int64  `json:"teachers"`
+}
+
+// QualificationMismatch is a legacy qualification string no entry matched
+type QualificationMismatch struct {
+	BusinessID uint   `json:"business_id"`
+	Value      string `json:"value"`
+	Teachers   int64  `json:"teachers"`
+}
+
+// QualificationMigrationReport is what mapping the teachers' legacy qualification
+// strings to canonical entries did, or would do on a dry run
+type QualificationMigrationReport struct {
+	DryRun          bool                    `json:"dry_run"`
+	TeachersUpdated int64                   `json:"teachers_updated"`
+	Matched         []QualificationMatch    `json:"matched"`
+	Unmatched       []QualificationMismatch `json:"unmatched"`
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+)
+
+// Scheduled reports
+const (
+	ReportAdminWeekly    = "admin_weekly"    // signups, expiring packages and inactive businesses
+	ReportBusinessWeekly = "business_weekly" // a business's attendance and fees
+)
+
+// ReportRoles lists which role may subscribe to each report
+var ReportRoles = map[string]UserRole{
+	ReportAdminWeekly:    RoleAdmin,
+	ReportBusinessWeekly: RoleBusiness,
+}
+
+// ReportSubscription is a user's choice to receive a scheduled report by email
+type ReportSubscription struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null;uniqueIndex:idx_report_subscription"`
+	Report     string     `json:"report" gorm:"type:varchar(30);not null;uniqueIndex:idx_report_subscription"`
+	Enabled    bool       `json:"enabled" gorm:"not null;default:true"`
+	LastSentAt *time.Time `json:"last_sent_at" gorm:"default:null"`
+	CreatedOn  time.Time  `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn  time.Time  `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+
+	// Relationships
+	User User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// TableName overrides the table name
+func (ReportSubscription) TableName() string {
+	return "report_subscription"
+}
+
+// ReportSchedule records when a scheduled report last ran, so a restart doesn't send it
+// again and only one instance sends it
+type ReportSchedule struct {
+	Name      string    `json:"name" gorm:"primaryKey;type:varchar(30)"`
+	LastRunAt time.Time `json:"last_run_at"`
+}
+
+// TableName overrides the table name
+func (ReportSchedule) TableName() string {
+	return "report_schedule"
+}
+
+type ReportSubscriptionResponse struct {
+	Report     string     `json:"report"`
+	Enabled    bool       `json:"enabled"`
+	LastSentAt *time.Time `json:"last_sent_at"`
+}
+
+type ReportSubscriptionUpdate struct {
+	Report  string `json:"report" binding:"required,oneof=admin_weekly business_weekly"`
+	Enabled *bool  `json:"enabled" binding:"required"`
+}
+
+type UpdateReportSubscriptionsRequest struct {
+	Subscriptions []ReportSubscriptionUpdate `json:"subscriptions" binding:"required,min=1,dive"`
+}
+
+// SendReportRequest sends a report immediately, to every subscriber or just to one user
+type SendReportRequest struct {
+	Report string `json:"report" binding:"required,oneof=admin_weekly business_weekly"`
+	UserID *uint  `json:"user_id"` // only this subscriber, who needn't be subscribed
+}
+
+type SendReportResult struct {
+	Report string `json:"report"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Sent   int    `json:"sent"`
+	Failed int    `json:"failed"`
+}
+
+// ReportBusiness is a business listed in a report
+type ReportBusiness struct {
+	ID               uint       `json:"id"`
+	Name             string     `json:"name"`
+	Email            string     `json:"email"`
+	PackageName      string     `json:"package_name,omitempty"`
+	PackageExpiresAt *time.Time `json:"package_expires_at,omitempty"`
+}
+
+// AdminWeeklyReport is the platform summary emailed to admins
+type AdminWeeklyReport struct {
+	From               string           `json:"from"`
+	To                 string           `json:"to"`
+	NewUsers           int              `json:"new_users"`
+	NewUsersByRole     map[string]int   `json:"new_users_by_role"`
+	NewBusinesses      []ReportBusiness `json:"new_businesses"`
+	ExpiringPackages   []ReportBusiness `json:"expiring_packages"` // packages running out in the coming week
+	InactiveBusinesses []ReportBusiness `json:"inactive_businesses"`
+	TotalBusinesses    int64            `json:"total_businesses"`
+	ActiveBusinesses   int64            `json:"active_businesses"`
+}
+
+// BusinessWeeklyReport is the attendance and fee summary emailed to a business owner
+type BusinessWeeklyReport struct {
+	BusinessName   string     `json:"business_name"`
+	From           string     `json:"from"`
+	To             string     `json:"to"`
+	Present        int64      `json:"present"`
+	Absent         int64      `json:"absent"`
+	Leave          int64      `json:"leave"`
+	AttendanceRate float64    `json:"attendance_rate"` // percentage of marked days present
+	Fees           FeeSummary `json:"fees"`
+}
@@ -0,0 +1,33 @@
+package models
+
+// Search sections, in the order they are returned
+const (
+	SearchSectionUsers      = "users"
+	SearchSectionBusinesses = "businesses"
+	SearchSectionTeachers   = "teachers"
+	SearchSectionStudents   = "students"
+)
+
+// SearchHit is one match of a global search, with just enough to tell matches apart and
+// open the full record
+type SearchHit struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	Email      string `json:"email,omitempty"`
+	BusinessID *uint  `json:"business_id,omitempty"`
+	Detail     string `json:"detail,omitempty"` // role, slug, qualification or grade
+}
+
+// SearchSection holds the matches of one entity type. A section whose search failed or
+// ran past the timeout is returned empty with Error set, instead of failing the search.
+type SearchSection struct {
+	Name  string      `json:"name"`
+	Hits  []SearchHit `json:"hits"`
+	Total int64       `json:"total"`
+	Error string      `json:"error,omitempty"`
+}
+
+type SearchResponse struct {
+	Query    string          `json:"query"`
+	Sections []SearchSection `json:"sections"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+)
+
+// SMS statuses. Queued messages are waiting to be handed to the provider; sent, delivered
+// and failed follow the provider's reports. Rate-limited messages were never sent because
+// the recipient already had too many recently.
+const (
+	SMSStatusQueued      = "queued"
+	SMSStatusSent        = "sent"
+	SMSStatusDelivered   = "delivered"
+	SMSStatusFailed      = "failed"
+	SMSStatusRateLimited = "rate_limited"
+)
+
+// SMS kinds
+const (
+	SMSKindAbsence = "absence"
+	SMSKindFeeDue  = "fee_due"
+)
+
+// SMSMessage records a text message to a guardian and what became of it
+type SMSMessage struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	BusinessID uint       `json:"business_id" gorm:"not null;index"`
+	StudentID  *uint      `json:"student_id" gorm:"index;default:null"`
+	Kind       string     `json:"kind" gorm:"type:varchar(20);not null"`
+	To         string     `json:"to" gorm:"column:recipient;type:varchar(20);not null;index:idx_sms_recipient_created"`
+	Body       string     `json:"body" gorm:"not null"`
+	DedupKey   string     `json:"-" gorm:"type:varchar(100);index"` // messages with the same key are only sent once
+	Status     string     `json:"status" gorm:"type:varchar(20);not null;index"`
+	ProviderID string     `json:"provider_id" gorm:"type:varchar(64);index"`
+	Attempts   int        `json:"attempts" gorm:"not null;default:0"`
+	Error      string     `json:"error"`
+	SentAt     *time.Time `json:"sent_at" gorm:"default:null"`
+	CreatedOn  time.Time  `json:"created_on" gorm:"column:created_on;autoCreateTime;index:idx_sms_recipient_created"`
+	UpdatedOn  time.Time  `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+}
+
+// TableName overrides the table name
+func (SMSMessage) TableName() string {
+	return "sms_message"
+}
+
+// OutgoingSMS is a message a service wants sent to a guardian
+type OutgoingSMS struct {
+	StudentID uint
+	To        string
+	Body      string
+	DedupKey  string
+}
+
+// SMSEnqueueResult counts what happened to a batch of outgoing messages
+type SMSEnqueueResult struct {
+	Queued      int `json:"queued"`
+	RateLimited int `json:"rate_limited"`
+	Duplicates  int `json:"duplicates"` // already sent earlier
+}
+
+type SMSSettings struct {
+	BusinessID uint `json:"business_id"`
+	Enabled    bool `json:"enabled"`
+}
+
+type UpdateSMSSettingsRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// FeeReminderResult reports the outcome of texting the guardians of students with
+// outstanding fees
+type FeeReminderResult struct {
+	AsOf         string `json:"as_of"`
+	StudentsDue  int    `json:"students_due"`
+	WithoutPhone int    `json:"without_phone"` // students with no guardian phone number
+	SMSEnqueueResult
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Status is the active flag of users, businesses, packages, teachers and students. It is
+// stored and serialized as 0 or 1; requests may also send "inactive" or "active", and
+// responses use the names for clients that ask for them with the status_format flag.
+type Status int
+
+const (
+	StatusInactive Status = 0
+	StatusActive   Status = 1
+)
+
+// StatusNames are the names a status is written as in string form
+var StatusNames = map[Status]string{
+	StatusInactive: "inactive",
+	StatusActive:   "active",
+}
+
+// IsValid checks if the status is one of the known values
+func (s Status) IsValid() bool {
+	_, ok := StatusNames[s]
+	return ok
+}
+
+// IsActive reports whether s is StatusActive
+func (s Status) IsActive() bool {
+	return s == StatusActive
+}
+
+// String returns the name of the status, or its number when it has none
+func (s Status) String() string {
+	if name, ok := StatusNames[s]; ok {
+		return name
+	}
+	return strconv.Itoa(int(s))
+}
+
+// ParseStatus reads a status from its number or its name
+func ParseStatus(value string) (Status, error) {
+	value = strings.TrimSpace(value)
+	for status, name := range StatusNames {
+		if strings.EqualFold(value, name) || value == strconv.Itoa(int(status)) {
+			return status, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid status %q, must be one of 0, 1, inactive, active", value)
+}
+
+// MarshalJSON writes the status as its number, which every client understands
+func (s Status) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(s))), nil
+}
+
+// UnmarshalJSON accepts the number as well as the name of a status
+func (s *Status) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		status, err := ParseStatus(name)
+		if err != nil {
+			return err
+		}
+		*s = status
+		return nil
+	}
+
+	var number int
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("invalid status %s, must be one of 0, 1, inactive, active", data)
+	}
+	if !Status(number).IsValid() {
+		return fmt.Errorf("invalid status %d, must be one of 0, 1, inactive, active", number)
+	}
+	*s = Status(number)
+	return nil
+}
+
+// UnmarshalParam lets query and form parameters use the name as well as the number
+func (s *Status) UnmarshalParam(param string) error {
+	status, err := ParseStatus(param)
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
+
+// Scan implements the sql.Scanner interface
+func (s *Status) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = StatusInactive
+	case int64:
+		*s = Status(v)
+	case int32:
+		*s = Status(v)
+	case []byte:
+		number, err := strconv.Atoi(string(v))
+		if err != nil {
+			return fmt.Errorf("cannot scan %q into Status", v)
+		}
+		*s = Status(number)
+	default:
+		return fmt.Errorf("cannot scan %T into Status", value)
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface
+func (s Status) Value() (driver.Value, error) {
+	return int64(s), nil
+}
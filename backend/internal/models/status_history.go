@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// Entities whose status changes are recorded in status_history. Student status changes
+// are part of the student timeline and stay in student_history; only the ones cascaded
+// from their business are recorded here as well, so reactivating it can undo them.
+const (
+	StatusEntityBusiness = "business"
+	StatusEntityTeacher  = "teacher"
+	StatusEntityStudent  = "student"
+)
+
+// StatusHistory records a business, teacher or student being activated or deactivated
+type StatusHistory struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	EntityType        string    `json:"entity_type" gorm:"type:varchar(20);not null;index:idx_status_history_entity_time,priority:1"`
+	EntityID          uint      `json:"entity_id" gorm:"not null;index"`
+	Status            Status    `json:"status" gorm:"not null"`                                  // the new status, 1=active, 0=inactive
+	CascadeBusinessID *uint     `json:"cascade_business_id,omitempty" gorm:"index;default:null"` // set when the change cascaded from this business's status change
+	CreatedOn         time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime;index:idx_status_history_entity_time,priority:2"`
+}
+
+// TableName overrides the table name
+func (StatusHistory) TableName() string {
+	return "status_history"
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStatusConversions(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		param string
+		want  Status
+		err   bool
+	}{
+		{"number 0", `0`, "0", StatusInactive, false},
+		{"number 1", `1`, "1", StatusActive, false},
+		{"name active", `"active"`, "active", StatusActive, false},
+		{"name inactive", `"inactive"`, "inactive", StatusInactive, false},
+		{"name padded and capitalised", `" Active "`, " Active ", StatusActive, false},
+		{"number as a string", `"1"`, "1", StatusActive, false},
+		{"unknown number", `2`, "2", 0, true},
+		{"unknown name", `"foo"`, "foo", 0, true},
+		// null isn't read as inactive, which would deactivate a record by accident
+		{"null", `null`, "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Start from a value every case changes or must leave alone
+			fromJSON := Status(7)
+			err := fromJSON.UnmarshalJSON([]byte(tt.json))
+			if (err != nil) != tt.err {
+				t.Fatalf("UnmarshalJSON(%s) error = %v, want error %v", tt.json, err, tt.err)
+			}
+			if !tt.err && fromJSON != tt.want {
+				t.Errorf("UnmarshalJSON(%s) = %d, want %d", tt.json, fromJSON, tt.want)
+			}
+
+			fromParam := Status(7)
+			err = fromParam.UnmarshalParam(tt.param)
+			if (err != nil) != tt.err {
+				t.Fatalf("UnmarshalParam(%q) error = %v, want error %v", tt.param, err, tt.err)
+			}
+			if !tt.err && fromParam != tt.want {
+				t.Errorf("UnmarshalParam(%q) = %d, want %d", tt.param, fromParam, tt.want)
+			}
+			if tt.err && (fromJSON != 7 || fromParam != 7) {
+				t.Errorf("rejected input changed the status to %d and %d", fromJSON, fromParam)
+			}
+		})
+	}
+}
+
+// Statuses are written as numbers, in requests' structs as well as on their own
+func TestStatusMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Status Status  `json:"status"`
+		Filter *Status `json:"filter"`
+	}{Status: StatusActive})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `{"status":1,"filter":null}` {
+		t.Errorf("Marshal = %s, want %s", data, `{"status":1,"filter":null}`)
+	}
+
+	var decoded struct {
+		Status Status  `json:"status"`
+		Filter *Status `json:"filter"`
+	}
+	if err := json.Unmarshal([]byte(`{"status": "active", "filter": null}`), &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded.Status != StatusActive || decoded.Filter != nil {
+		t.Errorf("Unmarshal = %d %v, want active and no filter", decoded.Status, decoded.Filter)
+	}
+}
+
+func TestStatusScan(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  Status
+		err   bool
+	}{
+		{"int64 active", int64(1), StatusActive, false},
+		{"int64 inactive", int64(0), StatusInactive, false},
+		{"int32", int32(1), StatusActive, false},
+		{"bytes", []byte("1"), StatusActive, false},
+		{"bytes inactive", []byte("0"), StatusInactive, false},
+		{"null", nil, StatusInactive, false},
+		{"bytes not a number", []byte("active"), 0, true},
+		{"unsupported type", "1", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status Status
+			err := status.Scan(tt.value)
+			if (err != nil) != tt.err {
+				t.Fatalf("Scan(%v) error = %v, want error %v", tt.value, err, tt.err)
+			}
+			if !tt.err && status != tt.want {
+				t.Errorf("Scan(%v) = %d, want %d", tt.value, status, tt.want)
+			}
+		})
+	}
+
+	value, err := StatusActive.Value()
+	if err != nil || value != int64(1) {
+		t.Errorf("Value = %v, %v, want 1", value, err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // JSONB type for PostgreSQL
@@ -32,18 +34,36 @@ type Student struct {
 	ID             uint      `json:"id" gorm:"primaryKey"`
 	Name           string    `json:"name" gorm:"not null"`
 	UserID         uint      `json:"user_id" gorm:"not null;uniqueIndex"`
-	BusinessID     uint      `json:"business_id" gorm:"not null"`
+	BusinessID     uint      `json:"business_id" gorm:"not null;uniqueIndex:idx_student_business_code,priority:1"`
 	GuardianName   string    `json:"guardian_name"`
 	GuardianNumber string    `json:"guardian_number"`
 	GuardianEmail  string    `json:"guardian_email"`
 	Information    JSONB     `json:"information" gorm:"type:jsonb"`
-	Status         int       `json:"status" gorm:"not null;default:1"` // 1=active, 0=inactive
+	Status         Status    `json:"status" gorm:"not null;default:1"`  // 1=active, 0=inactive
+	Version        uint      `json:"version" gorm:"not null;default:1"` // bumped on every update, for optimistic locking
 	CreatedOn      time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
 	UpdatedOn      time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
 
+	BatchID    *uint      `json:"batch_id" gorm:"index;default:null"`
+	EnrolledOn *time.Time `json:"enrolled_on" gorm:"type:date"`
+
+	DateOfBirth *time.Time `json:"date_of_birth" gorm:"type:date"`
+	Gender      string     `json:"gender" gorm:"type:varchar(20)"` // male, female, other
+	Grade       string     `json:"grade" gorm:"index"`             // one of the business's StudentGrade names
+
+	PhotoPath string `json:"-"` // storage path of the photo the business keeps for ID cards
+
+	// StudentCode is the business's human-friendly code for the student, e.g. SUN-2024-0153,
+	// given out on creation while the business has a code prefix; never reused
+	StudentCode *string `json:"student_code" gorm:"type:varchar(40);default:null;uniqueIndex:idx_student_business_code,priority:2"`
+
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"` // set while soft-deleted; hidden from queries unless unscoped
+
 	// Relationships
-	User     User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	Business Business `json:"business,omitempty" gorm:"foreignKey:BusinessID"`
+	User      User              `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Business  Business          `json:"business,omitempty" gorm:"foreignKey:BusinessID"`
+	Batch     *Batch            `json:"batch,omitempty" gorm:"foreignKey:BatchID"`
+	Guardians []StudentGuardian `json:"guardians,omitempty" gorm:"foreignKey:StudentID"`
 }
 
 // TableName overrides the table name
@@ -54,17 +74,33 @@ func (Student) TableName() string {
 type StudentResponse struct {
 	ID             uint              `json:"id"`
 	Name           string            `json:"name"`
+	StudentCode    string            `json:"student_code,omitempty"`
 	UserID         uint              `json:"user_id"`
 	BusinessID     uint              `json:"business_id"`
 	GuardianName   string            `json:"guardian_name"`
 	GuardianNumber string            `json:"guardian_number"`
 	GuardianEmail  string            `json:"guardian_email"`
 	Information    JSONB             `json:"information"`
-	Status         int               `json:"status"`
+	Status         Status            `json:"status"`
+	Version        uint              `json:"version"`
 	CreatedOn      time.Time         `json:"created_on"`
 	UpdatedOn      time.Time         `json:"updated_on"`
 	User           *UserResponse     `json:"user,omitempty"`
 	Business       *BusinessResponse `json:"business,omitempty"`
+
+	BatchID    *uint                     `json:"batch_id"`
+	Batch      *StudentBatchResponse     `json:"batch,omitempty"`
+	EnrolledOn string                    `json:"enrolled_on,omitempty"`
+	Guardians  []StudentGuardianResponse `json:"guardians"`
+
+	DateOfBirth string `json:"date_of_birth,omitempty"`
+	Age         *int   `json:"age,omitempty"`
+	Gender      string `json:"gender,omitempty"`
+	Grade       string `json:"grade,omitempty"`
+
+	PhotoURL string `json:"photo_url,omitempty"` // where the photo is loaded from, with the caller's token
+
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type CreateStudentRequest struct {
@@ -73,17 +109,60 @@ type CreateStudentRequest struct {
 	BusinessID     uint   `json:"business_id" binding:"required"`
 	GuardianName   string `json:"guardian_name"`
 	GuardianNumber string `json:"guardian_number"`
-	GuardianEmail  string `json:"guardian_email" binding:"omitempty,email"`
+	GuardianEmail  string `json:"guardian_email"`
 	Information    JSONB  `json:"information"`
+	BatchID        *uint  `json:"batch_id"`
+	EnrolledOn     string `json:"enrolled_on"`   // YYYY-MM-DD, defaults to today
+	DateOfBirth    string `json:"date_of_birth"` // YYYY-MM-DD
+	Gender         string `json:"gender" binding:"omitempty,oneof=male female other"`
+	Grade          string `json:"grade"`
+
+	// Guardians replaces the single guardian_* fields, which are kept for older clients
+	Guardians []GuardianRequest `json:"guardians" binding:"omitempty,dive"`
 }
 
 type UpdateStudentRequest struct {
-	Name           string `json:"name"`
-	GuardianName   string `json:"guardian_name"`
-	GuardianNumber string `json:"guardian_number"`
-	GuardianEmail  string `json:"guardian_email" binding:"omitempty,email"`
-	Information    JSONB  `json:"information"`
-	Status         *int   `json:"status"`
+	Name           string  `json:"name"`
+	GuardianName   string  `json:"guardian_name"`
+	GuardianNumber string  `json:"guardian_number"`
+	GuardianEmail  string  `json:"guardian_email"`
+	Information    JSONB   `json:"information"` // merged into the stored information; null values remove keys
+	Status         *Status `json:"status"`
+	EnrolledOn     string  `json:"enrolled_on"`   // YYYY-MM-DD
+	DateOfBirth    string  `json:"date_of_birth"` // YYYY-MM-DD
+	Gender         string  `json:"gender" binding:"omitempty,oneof=male female other"`
+	Grade          string  `json:"grade"`
+	Version        *uint   `json:"version"` // version the update is based on, unless sent as If-Match
+
+	// Guardians, when present, replaces all of the student's guardians. The single
+	// guardian_* fields update the primary guardian.
+	Guardians []GuardianRequest `json:"guardians" binding:"omitempty,dive"`
+}
+
+// BulkDeleteStudentsRequest deletes several students with the same options as a single delete
+type BulkDeleteStudentsRequest struct {
+	StudentIDs []uint `json:"student_ids" binding:"required,min=1"`
+	DeleteProfileOptions
+}
+
+// UpdateMyStudentProfileRequest is what a student may change on their own profile.
+// Guardians, enrollment and the rest stay with the business.
+type UpdateMyStudentProfileRequest struct {
+	Name        string `json:"name"`
+	Information JSONB  `json:"information"` // only keys of fields marked student_editable; merged like UpdateStudentRequest
+}
+
+// StudentProfileResponse is a student's own profile, served to the student with the
+// business cut down to a BusinessSummary
+type StudentProfileResponse struct {
+	StudentResponse
+	Business *BusinessSummary `json:"business,omitempty"`
+}
+
+// AgeCount is one bar of the student age histogram
+type AgeCount struct {
+	Age   int   `json:"age"`
+	Count int64 `json:"count"`
 }
 
 type StudentStatsResponse struct {
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+)
+
+type StudentAttendance struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	StudentID  uint      `json:"student_id" gorm:"not null;uniqueIndex:idx_student_attendance_day"`
+	BusinessID uint      `json:"business_id" gorm:"not null;index"`
+	BatchID    *uint     `json:"batch_id" gorm:"index;default:null"` // batch the student was marked in
+	Date       time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_student_attendance_day"`
+	Status     string    `json:"status" gorm:"type:varchar(10);not null"` // present, absent, leave
+	Note       string    `json:"note"`
+	MarkedBy   uint      `json:"marked_by" gorm:"not null"` // user ID of the actor
+	CreatedOn  time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn  time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+
+	// Relationships
+	Student Student `json:"-" gorm:"foreignKey:StudentID"`
+}
+
+// TableName overrides the table name
+func (StudentAttendance) TableName() string {
+	return "student_attendance"
+}
+
+type StudentAttendanceResponse struct {
+	ID          uint      `json:"id"`
+	StudentID   uint      `json:"student_id"`
+	StudentName string    `json:"student_name,omitempty"`
+	BusinessID  uint      `json:"business_id"`
+	BatchID     *uint     `json:"batch_id"`
+	Date        string    `json:"date"`
+	Status      string    `json:"status"`
+	Note        string    `json:"note"`
+	MarkedBy    uint      `json:"marked_by"`
+	UpdatedOn   time.Time `json:"updated_on"`
+}
+
+type StudentAttendanceEntry struct {
+	StudentID uint   `json:"student_id" binding:"required"`
+	Status    string `json:"status" binding:"required,oneof=present absent leave"`
+	Note      string `json:"note"`
+}
+
+// BulkStudentAttendanceRequest marks a day's attendance. When BatchID is set every
+// student must belong to that batch.
+type BulkStudentAttendanceRequest struct {
+	Date    string                   `json:"date" binding:"required"` // YYYY-MM-DD
+	BatchID *uint                    `json:"batch_id"`
+	Entries []StudentAttendanceEntry `json:"entries" binding:"required,min=1,dive"`
+}
+
+type StudentAttendanceSummary struct {
+	StudentID      uint    `json:"student_id"`
+	StudentName    string  `json:"student_name,omitempty"`
+	Month          string  `json:"month"`
+	Present        int64   `json:"present"`
+	Absent         int64   `json:"absent"`
+	Leave          int64   `json:"leave"`
+	TotalMarked    int64   `json:"total_marked"`
+	AttendanceRate float64 `json:"attendance_rate"` // percentage of marked days the student was present
+}
+
+type BusinessStudentAttendanceSummary struct {
+	BusinessID     uint                       `json:"business_id"`
+	BatchID        *uint                      `json:"batch_id,omitempty"`
+	Month          string                     `json:"month"`
+	Present        int64                      `json:"present"`
+	Absent         int64                      `json:"absent"`
+	Leave          int64                      `json:"leave"`
+	TotalMarked    int64                      `json:"total_marked"`
+	AttendanceRate float64                    `json:"attendance_rate"`
+	Students       []StudentAttendanceSummary `json:"students"`
+}
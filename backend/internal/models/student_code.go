@@ -0,0 +1,49 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StudentCodeCounter is the last sequence number a business gave out in student codes for
+// a year of enrollment. Year is 0 for codes without a year. Creating a student increments
+// it inside the creating transaction, so concurrent creations wait on the row.
+type StudentCodeCounter struct {
+	BusinessID uint  `json:"business_id" gorm:"primaryKey;autoIncrement:false"`
+	Year       int   `json:"year" gorm:"primaryKey;autoIncrement:false"`
+	Value      int64 `json:"value" gorm:"not null;default:0"`
+}
+
+// TableName overrides the table name
+func (StudentCodeCounter) TableName() string {
+	return "student_code_counter"
+}
+
+// StudentCodeSettings is the pattern a business's student codes follow
+type StudentCodeSettings struct {
+	BusinessID  uint   `json:"business_id"`
+	Enabled     bool   `json:"enabled"` // whether new students get a code, i.e. a prefix is set
+	Prefix      string `json:"prefix"`
+	IncludeYear bool   `json:"include_year"`
+	Digits      int    `json:"digits"`
+	Example     string `json:"example,omitempty"` // the first code of this year in the pattern
+}
+
+type UpdateStudentCodeSettingsRequest struct {
+	// Prefix of the codes, letters and digits; empty stops giving out codes. Existing codes
+	// are kept when the pattern changes.
+	Prefix      *string `json:"prefix" binding:"required"`
+	IncludeYear *bool   `json:"include_year"`
+	Digits      *int    `json:"digits" binding:"omitempty,min=3,max=8"`
+}
+
+// FormatStudentCode builds a student code from the business's pattern, e.g. SUN-2024-0153.
+// year is ignored unless the pattern includes it.
+func FormatStudentCode(business Business, year int, sequence int64) string {
+	parts := []string{business.StudentCodePrefix}
+	if business.StudentCodeYear {
+		parts = append(parts, fmt.Sprintf("%04d", year))
+	}
+	parts = append(parts, fmt.Sprintf("%0*d", business.StudentCodeDigits, sequence))
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+)
+
+// Custom student field types
+const (
+	StudentFieldText    = "text"
+	StudentFieldNumber  = "number"
+	StudentFieldBoolean = "boolean"
+	StudentFieldDate    = "date" // YYYY-MM-DD string
+)
+
+// StudentField defines a custom key a business keeps in Student.Information
+type StudentField struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	BusinessID uint   `json:"business_id" gorm:"not null;uniqueIndex:idx_student_field_business_key"`
+	Key        string `json:"key" gorm:"not null;uniqueIndex:idx_student_field_business_key"`
+	Label      string `json:"label" gorm:"not null"`
+	Type       string `json:"type" gorm:"not null"`
+	Required   bool   `json:"required" gorm:"not null;default:false"`
+	// StudentEditable lets students change the value themselves from their own profile
+	StudentEditable bool      `json:"student_editable" gorm:"not null;default:false"`
+	CreatedOn       time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn       time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+}
+
+// TableName overrides the table name
+func (StudentField) TableName() string {
+	return "student_field"
+}
+
+type CreateStudentFieldRequest struct {
+	Key             string `json:"key" binding:"required,max=50"`
+	Label           string `json:"label" binding:"required"`
+	Type            string `json:"type" binding:"required,oneof=text number boolean date"`
+	Required        bool   `json:"required"`
+	StudentEditable bool   `json:"student_editable"`
+}
+
+// UpdateStudentFieldRequest cannot change the key or type, as stored values depend on them
+type UpdateStudentFieldRequest struct {
+	Label           string `json:"label"`
+	Required        *bool  `json:"required"`
+	StudentEditable *bool  `json:"student_editable"`
+}
+
+type UpdateStudentFieldSettingsRequest struct {
+	Strict *bool `json:"strict" binding:"required"`
+}
+
+// StudentFieldSchema is the set of custom fields a business validates student information against
+type StudentFieldSchema struct {
+	BusinessID uint           `json:"business_id"`
+	Strict     bool           `json:"strict"` // reject keys without a definition instead of keeping them
+	Fields     []StudentField `json:"fields"`
+}
+
+// StudentGrade is a class level a business can put students in, listed by Position
+type StudentGrade struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	BusinessID uint      `json:"business_id" gorm:"not null;uniqueIndex:idx_student_grade_business_name"`
+	Name       string    `json:"name" gorm:"not null;uniqueIndex:idx_student_grade_business_name"`
+	Position   int       `json:"position" gorm:"not null;default:0"`
+	CreatedOn  time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+}
+
+// TableName overrides the table name
+func (StudentGrade) TableName() string {
+	return "student_grade"
+}
+
+// ReplaceStudentGradesRequest sets a business's grades in order; an empty list allows any grade
+type ReplaceStudentGradesRequest struct {
+	Grades []string `json:"grades" binding:"required"`
+}
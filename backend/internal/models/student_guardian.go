@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+)
+
+type StudentGuardian struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	StudentID uint      `json:"student_id" gorm:"not null;index;uniqueIndex:idx_student_guardian_primary,where:is_primary"`
+	Name      string    `json:"name" gorm:"not null"`
+	Relation  string    `json:"relation" gorm:"type:varchar(30)"` // e.g. mother, father, guardian
+	Phone     string    `json:"phone"`                            // normalized, digits with an optional leading +
+	Email     string    `json:"email"`
+	IsPrimary bool      `json:"is_primary" gorm:"not null;default:false"`
+	CreatedOn time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+
+	// Relationships
+	Student Student `json:"-" gorm:"foreignKey:StudentID"`
+}
+
+// TableName overrides the table name
+func (StudentGuardian) TableName() string {
+	return "student_guardian"
+}
+
+type StudentGuardianResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Relation  string `json:"relation"`
+	Phone     string `json:"phone"`
+	Email     string `json:"email"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+// GuardianRequest describes one guardian. When no guardian is marked primary the
+// first one becomes primary.
+type GuardianRequest struct {
+	Name      string `json:"name" binding:"required"`
+	Relation  string `json:"relation" binding:"omitempty,max=30"`
+	Phone     string `json:"phone"`
+	Email     string `json:"email"`
+	IsPrimary bool   `json:"is_primary"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+// Student timeline event types. Status, profile and batch events are stored in
+// student_history; the others are read from their own tables.
+const (
+	StudentEventCreated        = "created"
+	StudentEventStatusChanged  = "status_changed"
+	StudentEventProfileUpdated = "profile_updated"
+	StudentEventBatchMoved     = "batch_moved"
+	StudentEventPayment        = "payment"
+	StudentEventExamResult     = "exam_result"
+)
+
+// StudentHistory records a change made to a student
+type StudentHistory struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	StudentID uint      `json:"student_id" gorm:"not null;index:idx_student_history_student_time,priority:1"`
+	EventType string    `json:"event_type" gorm:"type:varchar(30);not null"`
+	Summary   string    `json:"summary" gorm:"not null"`
+	Payload   JSONB     `json:"payload" gorm:"type:jsonb"`
+	ActorID   *uint     `json:"actor_id" gorm:"default:null"` // user ID of the actor, if known
+	CreatedOn time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime;index:idx_student_history_student_time,priority:2"`
+
+	// Relationships
+	Student Student `json:"-" gorm:"foreignKey:StudentID"`
+}
+
+// TableName overrides the table name
+func (StudentHistory) TableName() string {
+	return "student_history"
+}
+
+type StudentTimelineActor struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type StudentTimelineEntry struct {
+	Type      string                `json:"type"`
+	Timestamp time.Time             `json:"timestamp"`
+	Actor     *StudentTimelineActor `json:"actor"`
+	Summary   string                `json:"summary"`
+	Payload   JSONB                 `json:"payload"`
+}
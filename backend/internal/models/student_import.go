@@ -0,0 +1,25 @@
+package models
+
+type StudentImportRowResult struct {
+	Row               int      `json:"row"` // line number in the CSV file, header is line 1
+	Name              string   `json:"name"`
+	Email             string   `json:"email"` // login email, generated when the row has none
+	Status            string   `json:"status"`
+	Errors            []string `json:"errors,omitempty"`
+	DuplicateOf       uint     `json:"duplicate_of,omitempty"` // existing student with the same name and guardian phone
+	UserID            uint     `json:"user_id,omitempty"`
+	StudentID         uint     `json:"student_id,omitempty"`
+	StudentCode       string   `json:"student_code,omitempty"`
+	GeneratedPassword string   `json:"generated_password,omitempty"`
+}
+
+type StudentImportReport struct {
+	BusinessID uint                     `json:"business_id"`
+	DryRun     bool                     `json:"dry_run"`
+	TotalRows  int                      `json:"total_rows"`
+	ValidRows  int                      `json:"valid_rows"`
+	Created    int                      `json:"created"`
+	Duplicates int                      `json:"duplicates"`
+	Failed     int                      `json:"failed"`
+	Rows       []StudentImportRowResult `json:"rows"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+)
+
+type Subject struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	BusinessID  uint      `json:"business_id" gorm:"not null;uniqueIndex:idx_subject_business_name"`
+	Name        string    `json:"name" gorm:"not null;uniqueIndex:idx_subject_business_name"`
+	Description string    `json:"description"`
+	CreatedOn   time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn   time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+
+	// Relationships
+	Business Business `json:"business,omitempty" gorm:"foreignKey:BusinessID"`
+}
+
+// TableName overrides the table name
+func (Subject) TableName() string {
+	return "subject"
+}
+
+type SubjectResponse struct {
+	ID          uint      `json:"id"`
+	BusinessID  uint      `json:"business_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedOn   time.Time `json:"created_on"`
+}
+
+type CreateSubjectRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+type UpdateSubjectRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type AssignSubjectsRequest struct {
+	SubjectIDs []uint `json:"subject_ids" binding:"required"`
+}
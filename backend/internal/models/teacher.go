@@ -2,24 +2,38 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Teacher struct {
-	ID            uint      `json:"id" gorm:"primaryKey"`
-	Name          string    `json:"name" gorm:"not null"`
-	UserID        uint      `json:"user_id" gorm:"not null;uniqueIndex"`
-	BusinessID    uint      `json:"business_id" gorm:"not null"`
-	Salary        float64   `json:"salary" gorm:"type:decimal(10,2)"`
-	Qualification string    `json:"qualification"`
-	Experience    string    `json:"experience"`
-	Description   string    `json:"description"`
-	Status        int       `json:"status" gorm:"not null;default:1"` // 1=active, 0=inactive
-	CreatedOn     time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
-	UpdatedOn     time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+	ID                 uint       `json:"id" gorm:"primaryKey"`
+	Name               string     `json:"name" gorm:"not null"`
+	UserID             uint       `json:"user_id" gorm:"not null;uniqueIndex"`
+	BusinessID         uint       `json:"business_id" gorm:"not null"`
+	Salary             float64    `json:"salary" gorm:"type:decimal(10,2)"`
+	Qualification      string     `json:"qualification"` // the entry's name, or free text from before the taxonomy
+	QualificationID    *uint      `json:"qualification_id" gorm:"index;default:null"`
+	Experience         string     `json:"experience"`                                // free-text notes
+	ExperienceYears    *float64   `json:"experience_years" gorm:"type:decimal(4,1)"` // nil when unknown
+	Description        string     `json:"description"`
+	Status             Status     `json:"status" gorm:"not null;default:1"`  // 1=active, 0=inactive
+	Version            uint       `json:"version" gorm:"not null;default:1"` // bumped on every update, for optimistic locking
+	CreatedOn          time.Time  `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn          time.Time  `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+	LastSalaryChangeAt *time.Time `json:"last_salary_change_at" gorm:"default:null"`
+	PreviousBusinessID *uint      `json:"previous_business_id" gorm:"default:null"`
+	TransferredAt      *time.Time `json:"transferred_at" gorm:"default:null"`
+
+	PhotoPath string `json:"-"` // storage path of the photo the business keeps for ID cards
+
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"` // set while soft-deleted; hidden from queries unless unscoped
 
 	// Relationships
-	User     User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	Business Business `json:"business,omitempty" gorm:"foreignKey:BusinessID"`
+	User             User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Business         Business  `json:"business,omitempty" gorm:"foreignKey:BusinessID"`
+	PreviousBusiness *Business `json:"previous_business,omitempty" gorm:"foreignKey:PreviousBusinessID"`
+	Subjects         []Subject `json:"subjects,omitempty" gorm:"many2many:teacher_subjects"`
 }
 
 // TableName overrides the table name
@@ -28,19 +42,32 @@ func (Teacher) TableName() string {
 }
 
 type TeacherResponse struct {
-	ID            uint              `json:"id"`
-	Name          string            `json:"name"`
-	UserID        uint              `json:"user_id"`
-	BusinessID    uint              `json:"business_id"`
-	Salary        float64           `json:"salary"`
-	Qualification string            `json:"qualification"`
-	Experience    string            `json:"experience"`
-	Description   string            `json:"description"`
-	Status        int               `json:"status"`
-	CreatedOn     time.Time         `json:"created_on"`
-	UpdatedOn     time.Time         `json:"updated_on"`
-	User          *UserResponse     `json:"user,omitempty"`
-	Business      *BusinessResponse `json:"business,omitempty"`
+	ID                 uint              `json:"id"`
+	Name               string            `json:"name"`
+	UserID             uint              `json:"user_id"`
+	BusinessID         uint              `json:"business_id"`
+	Salary             float64           `json:"salary"`
+	Qualification      string            `json:"qualification"`
+	QualificationID    *uint             `json:"qualification_id"`
+	Experience         string            `json:"experience"`
+	ExperienceYears    *float64          `json:"experience_years"`
+	Description        string            `json:"description"`
+	Status             Status            `json:"status"`
+	Version            uint              `json:"version"`
+	CreatedOn          time.Time         `json:"created_on"`
+	UpdatedOn          time.Time         `json:"updated_on"`
+	LastSalaryChangeAt *time.Time        `json:"last_salary_change_at"`
+	User               *UserResponse     `json:"user,omitempty"`
+	Business           *BusinessResponse `json:"business,omitempty"`
+	PreviousBusiness   *BusinessResponse `json:"previous_business,omitempty"` // shown for a grace period after a transfer
+	TransferredAt      *time.Time        `json:"transferred_at,omitempty"`
+	PhotoURL           string            `json:"photo_url,omitempty"` // where the photo is loaded from, with the caller's token
+	DeletedAt          *time.Time        `json:"deleted_at,omitempty"`
+	Subjects           []SubjectResponse `json:"subjects"`
+
+	DocumentCount        int64                         `json:"document_count"`
+	AssignedStudentCount int64                         `json:"assigned_student_count"`
+	Availability         []TeacherAvailabilityResponse `json:"availability,omitempty"`
 }
 
 type CreateTeacherRequest struct {
@@ -51,15 +78,57 @@ type CreateTeacherRequest struct {
 	Qualification string  `json:"qualification"`
 	Experience    string  `json:"experience"`
 	Description   string  `json:"description"`
+
+	ExperienceYears *float64 `json:"experience_years" binding:"omitempty,min=0,max=70"`
+	// QualificationID picks a taxonomy entry; a qualification without one is matched to
+	// an entry by name or added as a pending entry
+	QualificationID *uint `json:"qualification_id"`
+}
+
+// BulkDeleteTeachersRequest deletes several teachers with the same options as a single delete
+type BulkDeleteTeachersRequest struct {
+	TeacherIDs []uint `json:"teacher_ids" binding:"required,min=1"`
+	DeleteProfileOptions
 }
 
 type UpdateTeacherRequest struct {
 	Name          string   `json:"name"`
 	Salary        *float64 `json:"salary"`
+	SalaryReason  string   `json:"salary_reason"`
 	Qualification string   `json:"qualification"`
 	Experience    string   `json:"experience"`
 	Description   string   `json:"description"`
-	Status        *int     `json:"status"`
+	Status        *Status  `json:"status"`
+
+	ExperienceYears *float64 `json:"experience_years" binding:"omitempty,min=0,max=70"`
+	QualificationID *uint    `json:"qualification_id"` // as for CreateTeacherRequest
+	Version         *uint    `json:"version"`          // version the update is based on, unless sent as If-Match
+}
+
+// UpdateTeacherSelfRequest holds the fields a teacher may change on their own
+// profile. Salary and status are deliberately absent.
+type UpdateTeacherSelfRequest struct {
+	Name            string   `json:"name"`
+	Qualification   string   `json:"qualification"`
+	QualificationID *uint    `json:"qualification_id"`
+	Experience      string   `json:"experience"`
+	ExperienceYears *float64 `json:"experience_years" binding:"omitempty,min=0,max=70"`
+	Description     string   `json:"description"`
+}
+
+// SalaryBucket is one bar of the salary histogram. Max is nil for the open-ended top bucket.
+type SalaryBucket struct {
+	Label string   `json:"label"`
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int64    `json:"count"`
+}
+
+// QualificationSalary is the average salary of teachers sharing a qualification
+type QualificationSalary struct {
+	Qualification string  `json:"qualification"`
+	Count         int64   `json:"count"`
+	AvgSalary     float64 `json:"avg_salary"`
 }
 
 type TeacherStatsResponse struct {
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+// TeacherTransferGracePeriod is how long a transferred teacher keeps showing their previous business
+const TeacherTransferGracePeriod = 30 * 24 * time.Hour
+
+type TeacherAssignmentHistory struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	TeacherID      uint      `json:"teacher_id" gorm:"not null;index"`
+	FromBusinessID uint      `json:"from_business_id" gorm:"not null"`
+	ToBusinessID   uint      `json:"to_business_id" gorm:"not null"`
+	TransferredBy  uint      `json:"transferred_by" gorm:"not null"` // user ID of the actor
+	Reason         string    `json:"reason"`
+	TransferredAt  time.Time `json:"transferred_at" gorm:"column:transferred_at;autoCreateTime"`
+
+	// Relationships
+	Teacher      Teacher  `json:"-" gorm:"foreignKey:TeacherID"`
+	FromBusiness Business `json:"-" gorm:"foreignKey:FromBusinessID"`
+	ToBusiness   Business `json:"-" gorm:"foreignKey:ToBusinessID"`
+}
+
+// TableName overrides the table name
+func (TeacherAssignmentHistory) TableName() string {
+	return "teacher_assignment_history"
+}
+
+type TeacherAssignmentHistoryResponse struct {
+	ID               uint      `json:"id"`
+	TeacherID        uint      `json:"teacher_id"`
+	FromBusinessID   uint      `json:"from_business_id"`
+	FromBusinessName string    `json:"from_business_name"`
+	ToBusinessID     uint      `json:"to_business_id"`
+	ToBusinessName   string    `json:"to_business_name"`
+	TransferredBy    uint      `json:"transferred_by"`
+	Reason           string    `json:"reason"`
+	TransferredAt    time.Time `json:"transferred_at"`
+}
+
+type TransferTeacherRequest struct {
+	BusinessID  uint     `json:"business_id" binding:"required"`
+	Reason      string   `json:"reason"`
+	ResetSalary bool     `json:"reset_salary"` // set salary to Salary (or 0) at the new business
+	Salary      *float64 `json:"salary" binding:"omitempty,min=0"`
+	ResetStatus bool     `json:"reset_status"` // reactivate the teacher at the new business
+}
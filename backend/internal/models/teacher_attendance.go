@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+)
+
+// Attendance statuses
+const (
+	AttendancePresent = "present"
+	AttendanceAbsent  = "absent"
+	AttendanceLeave   = "leave"
+)
+
+// DateFormat is the format of date-only values in requests and responses
+const DateFormat = "2006-01-02"
+
+type TeacherAttendance struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	TeacherID  uint      `json:"teacher_id" gorm:"not null;uniqueIndex:idx_teacher_attendance_day"`
+	BusinessID uint      `json:"business_id" gorm:"not null;index"`
+	Date       time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_teacher_attendance_day"`
+	Status     string    `json:"status" gorm:"type:varchar(10);not null"` // present, absent, leave
+	Note       string    `json:"note"`
+	MarkedBy   uint      `json:"marked_by" gorm:"not null"` // user ID of the actor
+	CreatedOn  time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn  time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+
+	// Relationships
+	Teacher Teacher `json:"-" gorm:"foreignKey:TeacherID"`
+}
+
+// TableName overrides the table name
+func (TeacherAttendance) TableName() string {
+	return "teacher_attendance"
+}
+
+type TeacherAttendanceResponse struct {
+	ID          uint      `json:"id"`
+	TeacherID   uint      `json:"teacher_id"`
+	TeacherName string    `json:"teacher_name,omitempty"`
+	BusinessID  uint      `json:"business_id"`
+	Date        string    `json:"date"`
+	Status      string    `json:"status"`
+	Note        string    `json:"note"`
+	MarkedBy    uint      `json:"marked_by"`
+	UpdatedOn   time.Time `json:"updated_on"`
+}
+
+type MarkTeacherAttendanceRequest struct {
+	Date   string `json:"date" binding:"required"` // YYYY-MM-DD
+	Status string `json:"status" binding:"required,oneof=present absent leave"`
+	Note   string `json:"note"`
+}
+
+type TeacherAttendanceEntry struct {
+	TeacherID uint   `json:"teacher_id" binding:"required"`
+	Status    string `json:"status" binding:"required,oneof=present absent leave"`
+	Note      string `json:"note"`
+}
+
+type BulkTeacherAttendanceRequest struct {
+	Date    string                   `json:"date" binding:"required"` // YYYY-MM-DD
+	Entries []TeacherAttendanceEntry `json:"entries" binding:"required,min=1,dive"`
+}
+
+type TeacherAttendanceSummary struct {
+	TeacherID   uint    `json:"teacher_id"`
+	TeacherName string  `json:"teacher_name,omitempty"`
+	Month       string  `json:"month"`
+	Present     int64   `json:"present"`
+	Absent      int64   `json:"absent"`
+	Leave       int64   `json:"leave"`
+	TotalMarked int64   `json:"total_marked"`
+	AbsenceRate float64 `json:"absence_rate"` // percentage of marked days the teacher was absent
+}
+
+type BusinessAttendanceSummary struct {
+	BusinessID  uint                       `json:"business_id"`
+	Month       string                     `json:"month"`
+	Present     int64                      `json:"present"`
+	Absent      int64                      `json:"absent"`
+	Leave       int64                      `json:"leave"`
+	TotalMarked int64                      `json:"total_marked"`
+	AbsenceRate float64                    `json:"absence_rate"`
+	Teachers    []TeacherAttendanceSummary `json:"teachers"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// TeacherAvailability is a weekly time slot in which a teacher is free to take classes.
+// Times are stored as zero-padded "HH:MM" strings so they compare correctly as text.
+type TeacherAvailability struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	TeacherID uint      `json:"teacher_id" gorm:"not null;index:idx_teacher_availability_weekday"`
+	Weekday   int       `json:"weekday" gorm:"not null;index:idx_teacher_availability_weekday"` // 0=Sunday ... 6=Saturday
+	StartTime string    `json:"start_time" gorm:"type:varchar(5);not null"`
+	EndTime   string    `json:"end_time" gorm:"type:varchar(5);not null"`
+	CreatedOn time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+
+	// Relationships
+	Teacher Teacher `json:"-" gorm:"foreignKey:TeacherID"`
+}
+
+// TableName overrides the table name
+func (TeacherAvailability) TableName() string {
+	return "teacher_availability"
+}
+
+type TeacherAvailabilityResponse struct {
+	ID        uint   `json:"id"`
+	TeacherID uint   `json:"teacher_id"`
+	Weekday   int    `json:"weekday"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+type CreateAvailabilityRequest struct {
+	Weekday   *int   `json:"weekday" binding:"required,min=0,max=6"`
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+}
+
+type UpdateAvailabilityRequest struct {
+	Weekday   *int   `json:"weekday" binding:"omitempty,min=0,max=6"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+type AvailableTeachersQuery struct {
+	Weekday *int   `form:"weekday" binding:"required,min=0,max=6"`
+	Time    string `form:"time" binding:"required"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// Teacher document types
+const (
+	TeacherDocumentCertificate = "certificate"
+	TeacherDocumentContract    = "contract"
+	TeacherDocumentOther       = "other"
+)
+
+type TeacherDocument struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	TeacherID    uint      `json:"teacher_id" gorm:"not null;index"`
+	FileName     string    `json:"file_name" gorm:"not null"`
+	DocumentType string    `json:"document_type" gorm:"not null;default:other"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	StoragePath  string    `json:"-" gorm:"not null"`
+	UploadedBy   uint      `json:"uploaded_by" gorm:"not null"` // user ID of the uploader
+	UploadedAt   time.Time `json:"uploaded_at" gorm:"column:uploaded_at;autoCreateTime"`
+
+	// Relationships
+	Teacher Teacher `json:"-" gorm:"foreignKey:TeacherID"`
+}
+
+// TableName overrides the table name
+func (TeacherDocument) TableName() string {
+	return "teacher_documents"
+}
+
+type TeacherDocumentResponse struct {
+	ID           uint      `json:"id"`
+	TeacherID    uint      `json:"teacher_id"`
+	FileName     string    `json:"file_name"`
+	DocumentType string    `json:"document_type"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	UploadedBy   uint      `json:"uploaded_by"`
+	UploadedAt   time.Time `json:"uploaded_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// Import row statuses
+const (
+	ImportRowValid     = "valid"
+	ImportRowCreated   = "created"
+	ImportRowError     = "error"
+	ImportRowDuplicate = "duplicate"
+)
+
+type TeacherImportRowResult struct {
+	Row               int      `json:"row"` // line number in the CSV file, header is line 1
+	Name              string   `json:"name"`
+	Email             string   `json:"email"`
+	Status            string   `json:"status"`
+	Errors            []string `json:"errors,omitempty"`
+	UserID            uint     `json:"user_id,omitempty"`
+	TeacherID         uint     `json:"teacher_id,omitempty"`
+	GeneratedPassword string   `json:"generated_password,omitempty"`
+}
+
+type TeacherImportReport struct {
+	BusinessID uint                     `json:"business_id"`
+	DryRun     bool                     `json:"dry_run"`
+	TotalRows  int                      `json:"total_rows"`
+	ValidRows  int                      `json:"valid_rows"`
+	Created    int                      `json:"created"`
+	Failed     int                      `json:"failed"`
+	Rows       []TeacherImportRowResult `json:"rows"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+type TeacherSalaryHistory struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	TeacherID     uint      `json:"teacher_id" gorm:"not null;index"`
+	OldSalary     float64   `json:"old_salary" gorm:"type:decimal(10,2)"`
+	NewSalary     float64   `json:"new_salary" gorm:"type:decimal(10,2)"`
+	EffectiveDate time.Time `json:"effective_date" gorm:"not null"`
+	ChangedBy     uint      `json:"changed_by" gorm:"not null"` // user ID of the actor
+	Reason        string    `json:"reason"`
+	CreatedOn     time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+
+	// Relationships
+	Teacher Teacher `json:"-" gorm:"foreignKey:TeacherID"`
+}
+
+// TableName overrides the table name
+func (TeacherSalaryHistory) TableName() string {
+	return "teacher_salary_history"
+}
+
+type TeacherSalaryHistoryResponse struct {
+	ID            uint      `json:"id"`
+	TeacherID     uint      `json:"teacher_id"`
+	OldSalary     float64   `json:"old_salary"`
+	NewSalary     float64   `json:"new_salary"`
+	EffectiveDate time.Time `json:"effective_date"`
+	ChangedBy     uint      `json:"changed_by"`
+	Reason        string    `json:"reason"`
+	CreatedOn     time.Time `json:"created_on"`
+}
+
+// Salary adjustment types for bulk salary updates
+const (
+	SalaryAdjustmentSet             = "set"
+	SalaryAdjustmentIncreasePercent = "increase_percent"
+	SalaryAdjustmentIncreaseAmount  = "increase_amount"
+)
+
+type BulkUpdateSalaryRequest struct {
+	TeacherIDs     []uint     `json:"teacher_ids" binding:"required"`
+	AdjustmentType string     `json:"adjustment_type" binding:"omitempty,oneof=set increase_percent increase_amount"` // defaults to set
+	Value          *float64   `json:"value"`                                                                          // required, unless the deprecated salary is given
+	Reason         string     `json:"reason"`
+	EffectiveDate  *time.Time `json:"effective_date"`
+	// Salary is what value was called before adjustment types were added, still accepted
+	// from older clients. Deprecated: use value.
+	Salary *float64 `json:"salary,omitempty"`
+}
+
+type SalaryAdjustmentResult struct {
+	TeacherID uint    `json:"teacher_id"`
+	OldSalary float64 `json:"old_salary"`
+	NewSalary float64 `json:"new_salary"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// TeacherStudent assigns a student to a teacher of the same business
+type TeacherStudent struct {
+	TeacherID  uint      `json:"teacher_id" gorm:"primaryKey;autoIncrement:false"`
+	StudentID  uint      `json:"student_id" gorm:"primaryKey;autoIncrement:false;index"`
+	BusinessID uint      `json:"business_id" gorm:"not null;index"`
+	AssignedBy uint      `json:"assigned_by" gorm:"not null"` // user ID of the actor
+	AssignedOn time.Time `json:"assigned_on" gorm:"column:assigned_on;autoCreateTime"`
+
+	// Relationships
+	Teacher Teacher `json:"-" gorm:"foreignKey:TeacherID"`
+	Student Student `json:"-" gorm:"foreignKey:StudentID"`
+}
+
+// TableName overrides the table name
+func (TeacherStudent) TableName() string {
+	return "teacher_students"
+}
+
+type TeacherStudentsRequest struct {
+	StudentIDs []uint `json:"student_ids" binding:"required,min=1"`
+}
@@ -26,6 +26,13 @@ func (r UserRole) IsValid() bool {
 	}
 }
 
+// CanHoldProfile reports whether a user whose primary role is r may hold a profile
+// acting as role: their own role's profile, and a teacher profile for business owners
+// who also teach
+func (r UserRole) CanHoldProfile(role UserRole) bool {
+	return r == role || (r == RoleBusiness && role == RoleTeacher)
+}
+
 // String returns the string representation of UserRole
 func (r UserRole) String() string {
 	return string(r)
@@ -62,13 +69,16 @@ func (r UserRole) Value() (driver.Value, error) {
 type User struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
 	Name      string    `json:"name" gorm:"not null"`
-	Email     string    `json:"email" gorm:"uniqueIndex;not null"` // Changed from unique to uniqueIndex
+	Email     string    `json:"email" gorm:"not null"` // unique ignoring case, by idx_users_email
 	Phone     string    `json:"phone"`
 	Password  string    `json:"-" gorm:"not null"`
 	Role      UserRole  `json:"role" gorm:"type:varchar(20);not null;default:'student'"` // Added not null
-	Status    int       `json:"status" gorm:"not null;default:1"`                        // Added not null
+	Status    Status    `json:"status" gorm:"not null;default:1"`                        // Added not null
 	CreatedOn time.Time `json:"created_on" gorm:"column:created_on;autoCreateTime"`
 	UpdatedOn time.Time `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+
+	LastLoginAt *time.Time `json:"last_login_at" gorm:"default:null"`                         // nil until the user logs in
+	LastLoginIP string     `json:"last_login_ip" gorm:"type:varchar(45);not null;default:''"` // client address of that login, behind trusted proxies
 }
 
 // TableName overrides the table name
@@ -82,13 +92,19 @@ type UserResponse struct {
 	Email     string    `json:"email"`
 	Phone     string    `json:"phone"`
 	Role      UserRole  `json:"role"`
-	Status    int       `json:"status"`
+	Status    Status    `json:"status"`
 	CreatedOn time.Time `json:"created_on"`
+
+	LastLoginAt *time.Time `json:"last_login_at"`
+	LastLoginIP string     `json:"last_login_ip"`
 }
 
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	// BusinessSlug scopes the login to one business, for frontends served per business.
+	// The user must own the business or be one of its teachers or students.
+	BusinessSlug string `json:"business_slug,omitempty"`
 }
 
 type CreateUserRequest struct {
@@ -98,3 +114,41 @@ type CreateUserRequest struct {
 	Password string   `json:"password" binding:"required,min=6"`
 	Role     UserRole `json:"role" binding:"omitempty,oneof=admin business teacher student"`
 }
+
+// DeleteProfileOptions controls what happens when a teacher or student profile
+// is deleted. Profiles are soft-deleted, keeping their history restorable, unless
+// Permanent is set; DeleteUser and Cascade only apply to permanent deletes. The
+// linked user account is deactivated unless DeleteUser is set.
+type DeleteProfileOptions struct {
+	Permanent  bool `form:"permanent" json:"permanent"` // delete right away instead of soft-deleting
+	DeleteUser bool `form:"delete_user" json:"delete_user"`
+	Cascade    bool `form:"cascade" json:"cascade"` // also remove dependent records such as attendance and documents
+}
+
+// Outcomes of a bulk profile delete
+const (
+	BulkDeleteDeleted  = "deleted"  // soft-deleted, or removed for good when permanent
+	BulkDeleteArchived = "archived" // deactivated instead, as records such as fee payments are kept
+	BulkDeleteRefused  = "refused"  // has dependent records and cascade was not set
+)
+
+// BulkDeleteResult is what a bulk delete did with one teacher or student
+type BulkDeleteResult struct {
+	ID      uint   `json:"id"`
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// BulkUpdateResult is what a bulk status change or package assignment did: the IDs it
+// changed and the ones it skipped, with why. An atomic request that would skip any ID
+// changes none.
+type BulkUpdateResult struct {
+	Updated []uint          `json:"updated"`
+	Skipped []BulkSkippedID `json:"skipped"`
+}
+
+// BulkSkippedID is an ID a bulk update left alone
+type BulkSkippedID struct {
+	ID     uint   `json:"id"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+// Webhook event statuses. A received event is being, or failed to be, processed and is
+// processed again when the gateway retries it; processed and ignored events are final.
+const (
+	WebhookStatusReceived  = "received"
+	WebhookStatusProcessed = "processed"
+	WebhookStatusIgnored   = "ignored" // an event type we don't act on
+	WebhookStatusFailed    = "failed"  // rejected, e.g. the student doesn't exist; retrying won't help
+)
+
+// WebhookEvent records an inbound webhook call from a payment gateway, with its raw body
+// for debugging. A gateway's event ID is only processed once, however often it is sent.
+type WebhookEvent struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	Provider     string     `json:"provider" gorm:"type:varchar(20);not null;uniqueIndex:idx_webhook_event"`
+	EventID      string     `json:"event_id" gorm:"type:varchar(100);not null;uniqueIndex:idx_webhook_event"`
+	EventType    string     `json:"event_type" gorm:"type:varchar(100);not null"`
+	Payload      string     `json:"payload" gorm:"type:text;not null"`
+	Status       string     `json:"status" gorm:"type:varchar(20);not null;index"`
+	Error        string     `json:"error"`
+	Deliveries   int        `json:"deliveries" gorm:"not null;default:1"` // times the gateway sent it
+	FeePaymentID *uint      `json:"fee_payment_id" gorm:"default:null"`
+	ProcessedAt  *time.Time `json:"processed_at" gorm:"default:null"`
+	CreatedOn    time.Time  `json:"created_on" gorm:"column:created_on;autoCreateTime"`
+	UpdatedOn    time.Time  `json:"updated_on" gorm:"column:updated_on;autoUpdateTime"`
+}
+
+// TableName overrides the table name
+func (WebhookEvent) TableName() string {
+	return "webhook_event"
+}
+
+// GatewayPayment is an online payment confirmed by a payment gateway, to be recorded
+// as a fee payment
+type GatewayPayment struct {
+	Provider  string
+	PaymentID string // the gateway's payment ID
+	StudentID uint
+	FeePlanID *uint
+	Amount    float64
+	Currency  string
+	Mode      string
+	PaidOn    time.Time
+}
+
+// WebhookResult is what became of a webhook call
+type WebhookResult struct {
+	Status       string `json:"status"`
+	Duplicate    bool   `json:"duplicate"` // the event was already handled
+	FeePaymentID *uint  `json:"fee_payment_id,omitempty"`
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AnnouncementRepository interface {
+	// Basic CRUD operations
+	Create(ctx context.Context, announcement *models.Announcement) error
+	GetByID(ctx context.Context, id uint) (*models.Announcement, error)
+	GetAll(ctx context.Context, filters AnnouncementFilters) ([]models.Announcement, int64, error)
+	Update(ctx context.Context, announcement *models.Announcement) error
+	Delete(ctx context.Context, id uint) error
+
+	// Readers
+	GetVisible(ctx context.Context, reader AnnouncementReader, page, limit int) ([]models.Announcement, int64, error)
+	IsVisible(ctx context.Context, announcementID uint, reader AnnouncementReader) (bool, error)
+	GetReadIDs(ctx context.Context, userID uint, announcementIDs []uint) (map[uint]bool, error)
+	CountUnread(ctx context.Context, reader AnnouncementReader) (int64, error)
+	MarkRead(ctx context.Context, announcementID, userID uint) error
+	MarkAllRead(ctx context.Context, reader AnnouncementReader) error
+}
+
+type AnnouncementFilters struct {
+	BusinessID *uint  `form:"business_id" json:"business_id"`
+	Audience   string `form:"audience" json:"audience" binding:"omitempty,oneof=students teachers all"`
+	BatchID    *uint  `form:"batch_id" json:"batch_id"`
+	Page       int    `form:"page" json:"page"`
+	Limit      int    `form:"limit" json:"limit"`
+}
+
+// AnnouncementReader describes who is reading: the audience they belong to and the
+// batches whose announcements reach them
+type AnnouncementReader struct {
+	UserID     uint
+	BusinessID uint
+	Audience   string // students or teachers
+	BatchIDs   []uint
+	At         time.Time
+}
+
+type announcementRepository struct {
+	db *gorm.DB
+}
+
+func NewAnnouncementRepository(db *gorm.DB) AnnouncementRepository {
+	return &announcementRepository{
+		db: db,
+	}
+}
+
+func (r *announcementRepository) Create(ctx context.Context, announcement *models.Announcement) error {
+	if announcement == nil {
+		return fmt.Errorf("announcement cannot be nil")
+	}
+	return r.db.WithContext(ctx).Omit("Batch").Create(announcement).Error
+}
+
+func (r *announcementRepository) GetByID(ctx context.Context, id uint) (*models.Announcement, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid announcement ID")
+	}
+
+	var announcement models.Announcement
+	err := r.db.WithContext(ctx).Preload("Batch").First(&announcement, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+func (r *announcementRepository) GetAll(ctx context.Context, filters AnnouncementFilters) ([]models.Announcement, int64, error) {
+	var announcements []models.Announcement
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Announcement{})
+
+	if filters.BusinessID != nil {
+		query = query.Where("business_id = ?", *filters.BusinessID)
+	}
+	if filters.Audience != "" {
+		query = query.Where("audience = ?", filters.Audience)
+	}
+	if filters.BatchID != nil {
+		query = query.Where("batch_id = ?", *filters.BatchID)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Preload("Batch").Order("published_at DESC, id DESC")
+
+	// Apply pagination
+	if filters.Limit > 0 {
+		offset := 0
+		if filters.Page > 1 {
+			offset = (filters.Page - 1) * filters.Limit
+		}
+		query = query.Offset(offset).Limit(filters.Limit)
+	}
+
+	err := query.Find(&announcements).Error
+	return announcements, total, err
+}
+
+func (r *announcementRepository) Update(ctx context.Context, announcement *models.Announcement) error {
+	if announcement == nil {
+		return fmt.Errorf("announcement cannot be nil")
+	}
+	if announcement.ID == 0 {
+		return fmt.Errorf("announcement ID cannot be zero")
+	}
+	return r.db.WithContext(ctx).Omit("Batch").Save(announcement).Error
+}
+
+func (r *announcementRepository) Delete(ctx context.Context, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid announcement ID")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("announcement_id = ?", id).Delete(&models.AnnouncementRead{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Announcement{}, id).Error
+	})
+}
+
+func (r *announcementRepository) GetVisible(ctx context.Context, reader AnnouncementReader, page, limit int) ([]models.Announcement, int64, error) {
+	var announcements []models.Announcement
+	var total int64
+
+	query := r.visible(ctx, reader)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Preload("Batch").Order("published_at DESC, id DESC")
+
+	// Apply pagination
+	if limit > 0 {
+		offset := 0
+		if page > 1 {
+			offset = (page - 1) * limit
+		}
+		query = query.Offset(offset).Limit(limit)
+	}
+
+	err := query.Find(&announcements).Error
+	return announcements, total, err
+}
+
+func (r *announcementRepository) IsVisible(ctx context.Context, announcementID uint, reader AnnouncementReader) (bool, error) {
+	var count int64
+	err := r.visible(ctx, reader).Where("announcement.id = ?", announcementID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *announcementRepository) GetReadIDs(ctx context.Context, userID uint, announcementIDs []uint) (map[uint]bool, error) {
+	read := make(map[uint]bool)
+	if len(announcementIDs) == 0 {
+		return read, nil
+	}
+
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&models.AnnouncementRead{}).
+		Where("user_id = ? AND announcement_id IN ?", userID, announcementIDs).
+		Pluck("announcement_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		read[id] = true
+	}
+	return read, nil
+}
+
+func (r *announcementRepository) CountUnread(ctx context.Context, reader AnnouncementReader) (int64, error) {
+	var count int64
+	err := r.visible(ctx, reader).
+		Where("NOT EXISTS (SELECT 1 FROM announcement_read ar WHERE ar.announcement_id = announcement.id AND ar.user_id = ?)", reader.UserID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *announcementRepository) MarkRead(ctx context.Context, announcementID, userID uint) error {
+	read := models.AnnouncementRead{AnnouncementID: announcementID, UserID: userID}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&read).Error
+}
+
+// MarkAllRead marks every announcement currently visible to the reader as read
+func (r *announcementRepository) MarkAllRead(ctx context.Context, reader AnnouncementReader) error {
+	var ids []uint
+	if err := r.visible(ctx, reader).Pluck("announcement.id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	reads := make([]models.AnnouncementRead, 0, len(ids))
+	for _, id := range ids {
+		reads = append(reads, models.AnnouncementRead{AnnouncementID: id, UserID: reader.UserID})
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&reads).Error
+}
+
+// visible selects the published, unexpired announcements aimed at the reader
+func (r *announcementRepository) visible(ctx context.Context, reader AnnouncementReader) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Announcement{}).
+		Where("announcement.business_id = ?", reader.BusinessID).
+		Where("announcement.audience IN ?", []string{reader.Audience, models.AudienceAll}).
+		Where("announcement.published_at <= ?", reader.At).
+		Where("announcement.expires_at IS NULL OR announcement.expires_at > ?", reader.At)
+
+	if len(reader.BatchIDs) > 0 {
+		query = query.Where("announcement.batch_id IS NULL OR announcement.batch_id IN ?", reader.BatchIDs)
+	} else {
+		query = query.Where("announcement.batch_id IS NULL")
+	}
+
+	return query
+}
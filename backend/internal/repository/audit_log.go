@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	// CreateBatch inserts entries in a single statement
+	CreateBatch(ctx context.Context, entries []models.AuditLog) error
+	GetAll(ctx context.Context, filters AuditLogFilters) ([]models.AuditLog, int64, error)
+}
+
+// AuditLogFilters narrow an audit log listing; Category is set by the service
+type AuditLogFilters struct {
+	Category   string `form:"-" json:"-"`
+	ActorID    *uint  `form:"actor_id" json:"actor_id"`
+	BusinessID *uint  `form:"business_id" json:"business_id"`
+	Action     string `form:"action" json:"action" binding:"omitempty,oneof=list view export stats"`
+	Resource   string `form:"resource" json:"resource" binding:"omitempty,oneof=students teachers business_archive"`
+	ResourceID *uint  `form:"resource_id" json:"resource_id"`
+	From       string `form:"from" json:"from"` // YYYY-MM-DD, inclusive
+	To         string `form:"to" json:"to"`     // YYYY-MM-DD, inclusive
+	Page       int    `form:"page" json:"page"`
+	Limit      int    `form:"limit" json:"limit"`
+	SortOrder  string `form:"sort_order" json:"sort_order" binding:"omitempty,oneof=asc desc"` // by created_on; desc by default
+}
+
+// PageInfo describes the page of the listing that was returned
+func (f AuditLogFilters) PageInfo(total int64) PageInfo {
+	return PageInfo{Total: &total, Page: f.Page, Limit: f.Limit}
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{
+		db: db,
+	}
+}
+
+func (r *auditLogRepository) CreateBatch(ctx context.Context, entries []models.AuditLog) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&entries).Error
+}
+
+func (r *auditLogRepository) GetAll(ctx context.Context, filters AuditLogFilters) ([]models.AuditLog, int64, error) {
+	var entries []models.AuditLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.AuditLog{}).Where("category = ?", filters.Category)
+	if filters.ActorID != nil {
+		query = query.Where("actor_id = ?", *filters.ActorID)
+	}
+	if filters.BusinessID != nil {
+		query = query.Where("business_id = ?", *filters.BusinessID)
+	}
+	if filters.Action != "" {
+		query = query.Where("action = ?", filters.Action)
+	}
+	if filters.Resource != "" {
+		query = query.Where("resource = ?", filters.Resource)
+	}
+	if filters.ResourceID != nil {
+		query = query.Where("resource_id = ?", *filters.ResourceID)
+	}
+	if filters.From != "" {
+		query = query.Where("created_on >= CAST(? AS date)", filters.From)
+	}
+	if filters.To != "" {
+		query = query.Where("created_on < CAST(? AS date) + 1", filters.To)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "created_on DESC, id DESC"
+	if filters.SortOrder == "asc" {
+		order = "created_on ASC, id ASC"
+	}
+	query = query.Order(order)
+
+	if filters.Limit > 0 {
+		offset := 0
+		if filters.Page > 1 {
+			offset = (filters.Page - 1) * filters.Limit
+		}
+		query = query.Offset(offset).Limit(filters.Limit)
+	}
+
+	err := query.Find(&entries).Error
+	return entries, total, err
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type BatchRepository interface {
+	// Basic CRUD operations
+	Create(ctx context.Context, batch *models.Batch) error
+	GetByID(ctx context.Context, id uint) (*models.Batch, error)
+	GetByBusinessID(ctx context.Context, businessID uint) ([]models.Batch, error)
+	Update(ctx context.Context, batch *models.Batch) error
+	Delete(ctx context.Context, id uint) error
+
+	// Students
+	CountStudents(ctx context.Context, batchIDs []uint) (map[uint]int64, error)
+	MoveStudentWithTransaction(tx *gorm.DB, studentID uint, batchID *uint) error
+
+	// Validation
+	BatchNameExists(ctx context.Context, businessID uint, name string, excludeBatchID ...uint) (bool, error)
+}
+
+type batchRepository struct {
+	db *gorm.DB
+}
+
+func NewBatchRepository(db *gorm.DB) BatchRepository {
+	return &batchRepository{
+		db: db,
+	}
+}
+
+func (r *batchRepository) Create(ctx context.Context, batch *models.Batch) error {
+	if batch == nil {
+		return fmt.Errorf("batch cannot be nil")
+	}
+	return r.db.WithContext(ctx).Create(batch).Error
+}
+
+func (r *batchRepository) GetByID(ctx context.Context, id uint) (*models.Batch, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid batch ID")
+	}
+
+	var batch models.Batch
+	err := r.db.WithContext(ctx).Preload("Teacher").First(&batch, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *batchRepository) GetByBusinessID(ctx context.Context, businessID uint) ([]models.Batch, error) {
+	if businessID == 0 {
+		return nil, fmt.Errorf("invalid business ID")
+	}
+
+	var batches []models.Batch
+	err := r.db.WithContext(ctx).Preload("Teacher").
+		Where("business_id = ?", businessID).
+		Order("start_date DESC NULLS LAST, name ASC").
+		Find(&batches).Error
+	return batches, err
+}
+
+func (r *batchRepository) Update(ctx context.Context, batch *models.Batch) error {
+	if batch == nil {
+		return fmt.Errorf("batch cannot be nil")
+	}
+	if batch.ID == 0 {
+		return fmt.Errorf("batch ID cannot be zero")
+	}
+	return r.db.WithContext(ctx).Omit("Teacher", "Business").Save(batch).Error
+}
+
+func (r *batchRepository) Delete(ctx context.Context, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid batch ID")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Students stay enrolled, just without a batch
+		if err := tx.Model(&models.Student{}).Where("batch_id = ?", id).Update("batch_id", nil).Error; err != nil {
+			return err
+		}
+		// Fees of the batch stop applying to anyone
+		if err := tx.Model(&models.FeePlan{}).Where("batch_id = ?", id).Update("status", 0).Error; err != nil {
+			return err
+		}
+		// Exams of the batch remain as business-wide exams
+		if err := tx.Model(&models.Exam{}).Where("batch_id = ?", id).Update("batch_id", nil).Error; err != nil {
+			return err
+		}
+		// Announcements meant for the batch only would otherwise reach the whole business
+		batchAnnouncements := tx.Model(&models.Announcement{}).Select("id").Where("batch_id = ?", id)
+		if err := tx.Where("announcement_id IN (?)", batchAnnouncements).Delete(&models.AnnouncementRead{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("batch_id = ?", id).Delete(&models.Announcement{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Batch{}, id).Error
+	})
+}
+
+func (r *batchRepository) CountStudents(ctx context.Context, batchIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64)
+	if len(batchIDs) == 0 {
+		return counts, nil
+	}
+
+	var results []struct {
+		BatchID uint
+		Count   int64
+	}
+
+	err := r.db.WithContext(ctx).Model(&models.Student{}).
+		Select("batch_id, COUNT(*) as count").
+		Where("batch_id IN ?", batchIDs).
+		Group("batch_id").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		counts[result.BatchID] = result.Count
+	}
+	return counts, nil
+}
+
+func (r *batchRepository) MoveStudentWithTransaction(tx *gorm.DB, studentID uint, batchID *uint) error {
+	if studentID == 0 {
+		return fmt.Errorf("invalid student ID")
+	}
+	return tx.Model(&models.Student{}).Where("id = ?", studentID).Update("batch_id", batchID).Error
+}
+
+func (r *batchRepository) BatchNameExists(ctx context.Context, businessID uint, name string, excludeBatchID ...uint) (bool, error) {
+	if name == "" {
+		return false, fmt.Errorf("name cannot be empty")
+	}
+
+	var count int64
+	query := r.db.WithContext(ctx).Model(&models.Batch{}).Where("business_id = ? AND LOWER(name) = LOWER(?)", businessID, name)
+
+	if len(excludeBatchID) > 0 && excludeBatchID[0] > 0 {
+		query = query.Where("id != ?", excludeBatchID[0])
+	}
+
+	err := query.Count(&count).Error
+	return count > 0, err
+}
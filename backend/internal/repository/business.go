@@ -2,94 +2,176 @@ package repository
 
 import (
 	"backend/internal/models"
-	"backend/pkg/database"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"regexp"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BusinessRepository interface {
 	// Basic CRUD operations
-	Create(business *models.Business) error
+	Create(ctx context.Context, business *models.Business) error
 	CreateWithTransaction(tx *gorm.DB, business *models.Business) error
-	GetByID(id uint) (*models.Business, error)
-	GetBySlug(slug string) (*models.Business, error)
-	GetByUserID(userID uint) (*models.Business, error)
-	GetByEmail(email string) (*models.Business, error)
-	GetAll(filters BusinessFilters) ([]models.Business, int64, error)
-	GetAllWithRelations(filters BusinessFilters) ([]models.Business, int64, error)
-	Update(business *models.Business) error
+	CreateWithUniqueSlugTransaction(tx *gorm.DB, business *models.Business) error
+	UpdateSlugWithTransaction(tx *gorm.DB, business *models.Business, slug string, numbered bool, actorID uint) error
+	GetSlugHistory(ctx context.Context, businessID uint) ([]models.BusinessSlugHistory, error)
+	GetByID(ctx context.Context, id uint) (*models.Business, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]models.Business, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Business, error)
+	GetByUserID(ctx context.Context, userID uint) (*models.Business, error)
+	GetByEmail(ctx context.Context, email string) (*models.Business, error)
+	// GetTimezone reads just the business's time zone
+	GetTimezone(ctx context.Context, id uint) (string, error)
+	GetAll(ctx context.Context, filters BusinessFilters) ([]models.Business, int64, error)
+	GetAllWithRelations(ctx context.Context, filters BusinessFilters) ([]models.Business, int64, error)
+	Update(ctx context.Context, business *models.Business) error
 	UpdateWithTransaction(tx *gorm.DB, business *models.Business) error
-	Delete(id uint) error
+	UpdateVersionedWithTransaction(tx *gorm.DB, business *models.Business, expectedVersion uint) error
+	// UpdateFields writes only the given columns of a business, leaving the rest of the
+	// row as it is in the database
+	UpdateFields(ctx context.Context, id uint, updates map[string]interface{}) error
+	Delete(ctx context.Context, id uint) error
+	DeleteWithTransaction(tx *gorm.DB, id uint) error
+	CountDependentRecords(ctx context.Context, id uint) (map[string]int64, error)
 
 	// Status operations
-	UpdateBusinessStatus(businessID uint, status int) error
-	GetActiveBusinesses() ([]models.Business, error)
-	GetInactiveBusinesses() ([]models.Business, error)
+	UpdateBusinessStatus(ctx context.Context, businessID uint, status models.Status) error
+	RecordStatusChangeWithTransaction(tx *gorm.DB, businessID uint, status models.Status) error
+	RecordPackageChangeWithTransaction(tx *gorm.DB, businessID uint, oldPackageID, newPackageID *uint) error
+	GetInactiveBusinesses(ctx context.Context) ([]models.Business, error)
 
 	// Package operations
-	AssignPackage(businessID, packageID uint) error
-	RemovePackage(businessID uint) error
-	GetBusinessesByPackage(packageID uint) ([]models.Business, error)
-	GetBusinessesWithoutPackage() ([]models.Business, error)
+	AssignPackage(ctx context.Context, businessID, packageID uint) error
+	RemovePackage(ctx context.Context, businessID uint) error
+	// GetBusinessesByPackageExpiry finds the active businesses whose package runs out from
+	// now until the end of the day days after today, counting days in each business's zone
+	GetBusinessesByPackageExpiry(ctx context.Context, now time.Time, days int) ([]models.Business, error)
 
 	// Validation and utility
-	BusinessEmailExists(email string, excludeBusinessID ...uint) (bool, error)
-	BusinessSlugExists(slug string, excludeBusinessID ...uint) (bool, error)
-	BusinessNameExists(name string, excludeBusinessID ...uint) (bool, error)
+	BusinessEmailExists(ctx context.Context, email string, excludeBusinessID ...uint) (bool, error)
+	BusinessSlugExists(ctx context.Context, slug string, excludeBusinessID ...uint) (bool, error)
+	BusinessNameExists(ctx context.Context, name string, excludeBusinessID ...uint) (bool, error)
 
 	// Search
-	SearchBusinesses(searchTerm string, limit int) ([]models.Business, error)
+	SearchBusinesses(ctx context.Context, searchTerm string, limit int) ([]models.Business, error)
 
 	// Statistics
-	GetBusinessStats() (map[string]interface{}, error)
-	GetLocationStats() (map[string]int64, error)
-	GetPackageDistribution() (map[string]int64, error)
+	GetBusinessStats(ctx context.Context) (map[string]interface{}, error)
+	GetLocationStats(ctx context.Context) (map[string]int64, error)
+	GetPackageDistribution(ctx context.Context) (map[string]int64, error)
+	GetGrowthTimeseries(ctx context.Context, granularity string, from, to time.Time) (*models.GrowthTimeseries, error)
 
 	// Relationships
-	GetBusinessWithRelations(id uint) (*models.Business, error)
-	GetBySlugWithRelations(slug string) (*models.Business, error)
+	GetBusinessWithRelations(ctx context.Context, id uint) (*models.Business, error)
+	GetBySlugWithRelations(ctx context.Context, slug string) (*models.Business, error)
 
 	// Bulk operations
-	BulkUpdateStatus(businessIDs []uint, status int) error
-	BulkAssignPackage(businessIDs []uint, packageID uint) error
+	BulkUpdateStatusWithTransaction(tx *gorm.DB, businessIDs []uint, status models.Status) error
+	CascadeStatusWithTransaction(tx *gorm.DB, businessID uint, status models.Status) ([]uint, error)
+	BulkAssignPackage(ctx context.Context, businessIDs []uint, packageID uint) error
+	BulkRemovePackage(ctx context.Context, businessIDs []uint, actorID uint) ([]uint, error)
+	SwapPackage(ctx context.Context, fromPackageID, toPackageID, actorID uint) ([]uint, error)
+
+	// Reporting
+	GetAtRiskBusinesses(ctx context.Context, filters AtRiskFilters, now time.Time) ([]models.AtRiskBusiness, int64, error)
 
 	// Location operations
-	GetBusinessesByLocation(location string) ([]models.Business, error)
-	GetBusinessLocations() ([]string, error)
+	GetBusinessLocations(ctx context.Context) ([]string, error)
 
 	// Transaction support
-	BeginTransaction() *gorm.DB
+	BeginTransaction(ctx context.Context) *gorm.DB
 }
 
 type BusinessFilters struct {
-	PackageID *uint  `form:"package_id" json:"package_id"`
-	Status    *int   `form:"status" json:"status"`
-	Location  string `form:"location" json:"location"`
-	Search    string `form:"search" json:"search"`
-	Page      int    `form:"page" json:"page"`
-	Limit     int    `form:"limit" json:"limit"`
-	SortBy    string `form:"sort_by" json:"sort_by"`
-	SortOrder string `form:"sort_order" json:"sort_order"`
+	PackageID *uint          `form:"package_id" json:"package_id"`
+	Status    *models.Status `form:"status" json:"status"`
+	IsOpen    *bool          `form:"is_open" json:"is_open"`
+	Location  string         `form:"location" json:"location"`
+	Search    string         `form:"search" json:"search"`
+	Page      int            `form:"page" json:"page"`
+	Limit     int            `form:"limit" json:"limit"`
+	SortBy    string         `form:"sort_by" json:"sort_by"`
+	SortOrder string         `form:"sort_order" json:"sort_order"`
+	Fields    string         `form:"fields" json:"fields"`         // comma-separated response fields to return
+	Include   string         `form:"include" json:"include"`       // comma-separated relations to load
+	Cursor    string         `form:"cursor" json:"cursor"`         // next_cursor of the previous page, replaces page
+	WithTotal *bool          `form:"with_total" json:"with_total"` // false skips counting the total
+	Estimate  bool           `form:"estimate" json:"estimate"`     // estimate the total from table statistics, for large tables
+
+	HasPackage *bool `form:"has_package" json:"has_package"` // true for businesses with a package, false for those without
+	IsTrial    *bool `form:"is_trial" json:"is_trial"`       // true for businesses on a free package, false for paying ones and those without a package
+	Verified   *bool `form:"verified" json:"verified"`       // true for businesses whose owner has confirmed their email
+	// Package expiry window: expires on or after ExpiresAfter and before ExpiresBefore.
+	// Businesses without an expiry date match neither. The dates of these and of the
+	// creation range are days in each business's own time zone.
+	ExpiresAfter  string `form:"expires_after" json:"expires_after"`   // YYYY-MM-DD
+	ExpiresBefore string `form:"expires_before" json:"expires_before"` // YYYY-MM-DD
+	CreatedFrom   string `form:"created_from" json:"created_from"`     // YYYY-MM-DD, inclusive
+	CreatedTo     string `form:"created_to" json:"created_to"`         // YYYY-MM-DD, inclusive
+	IncludeTheme  bool   `form:"include_theme" json:"include_theme"`   // also return each business's public page theme
+}
+
+// businessSortFields are the columns businesses can be sorted by
+var businessSortFields = map[string]bool{
+	"created_on":         true,
+	"updated_on":         true,
+	"name":               true,
+	"owner_name":         true,
+	"email":              true,
+	"location":           true,
+	"status":             true,
+	"slug":               true,
+	"package_id":         true,
+	"package_expires_at": true,
+}
+
+func (f BusinessFilters) totalMode() totalMode {
+	return newTotalMode(f.Cursor, f.WithTotal, f.Estimate)
+}
+
+func (f BusinessFilters) sort() listSort {
+	return resolveSort(f.SortBy, f.SortOrder, businessSortFields)
+}
+
+// PageInfo builds the pagination metadata for businesses listed with these filters
+func (f BusinessFilters) PageInfo(total int64, businesses []models.Business) PageInfo {
+	return newPageInfo(total, businesses, f.sort(), f.Page, f.Limit, f.Cursor, f.totalMode())
+}
+
+// businessRelations are the relations GetAllWithRelations can preload
+var businessRelations = relationPreloads{
+	"user":    func(db *gorm.DB) *gorm.DB { return db.Preload("User") },
+	"package": func(db *gorm.DB) *gorm.DB { return db.Preload("Package") },
+}
+
+// Selection parses the fields and include parameters against BusinessResponse
+func (f BusinessFilters) Selection() (Selection, error) {
+	return parseSelection(f.Fields, f.Include, models.BusinessResponse{}, businessRelations)
 }
 
 type businessRepository struct {
 	db *gorm.DB
 }
 
-func NewBusinessRepository() BusinessRepository {
+func NewBusinessRepository(db *gorm.DB) BusinessRepository {
 	return &businessRepository{
-		db: database.DB,
+		db: db,
 	}
 }
 
 // Basic CRUD operations
 
-func (r *businessRepository) Create(business *models.Business) error {
+func (r *businessRepository) Create(ctx context.Context, business *models.Business) error {
 	if business == nil {
 		return fmt.Errorf("business cannot be nil")
 	}
-	return r.db.Create(business).Error
+	return r.db.WithContext(ctx).Create(business).Error
 }
 
 func (r *businessRepository) CreateWithTransaction(tx *gorm.DB, business *models.Business) error {
@@ -99,134 +181,260 @@ func (r *businessRepository) CreateWithTransaction(tx *gorm.DB, business *models
 	return tx.Create(business).Error
 }
 
-func (r *businessRepository) GetByID(id uint) (*models.Business, error) {
+// ErrSlugUnavailable is returned when no free slug was found for a new business
+var ErrSlugUnavailable = errors.New("no free slug found")
+
+// slugAttempts is how often saving a business under a free slug is tried when concurrent
+// saves keep taking it first; the first tries number the slug, later ones add a random suffix
+const (
+	slugAttempts         = 8
+	numberedSlugAttempts = 3
+)
+
+// CreateWithUniqueSlugTransaction creates a business under its slug, or the slug with the
+// next free number appended
+func (r *businessRepository) CreateWithUniqueSlugTransaction(tx *gorm.DB, business *models.Business) error {
+	if business == nil {
+		return fmt.Errorf("business cannot be nil")
+	}
+	if business.Slug == "" {
+		return fmt.Errorf("slug cannot be empty")
+	}
+
+	base := business.Slug
+	err := withUniqueSlug(tx, base, func(slug string) error {
+		business.ID = 0
+		business.Slug = slug
+		return tx.Create(business).Error
+	})
+	if err != nil {
+		business.Slug = base
+	}
+	return err
+}
+
+// UpdateSlugWithTransaction changes a business's slug and records the change in its slug
+// history. With numbered, a taken slug gets the next free number appended; otherwise it
+// fails with the unique violation.
+func (r *businessRepository) UpdateSlugWithTransaction(tx *gorm.DB, business *models.Business, slug string, numbered bool, actorID uint) error {
+	if business == nil {
+		return fmt.Errorf("business cannot be nil")
+	}
+	if slug == "" {
+		return fmt.Errorf("slug cannot be empty")
+	}
+
+	oldSlug := business.Slug
+	update := func(slug string) error {
+		return tx.Model(&models.Business{}).Where("id = ?", business.ID).Update("slug", slug).Error
+	}
+
+	var err error
+	if numbered {
+		err = withUniqueSlug(tx, slug, func(candidate string) error {
+			slug = candidate
+			return update(candidate)
+		})
+	} else {
+		err = update(slug)
+	}
+	if err != nil {
+		return err
+	}
+
+	business.Slug = slug
+	entry := &models.BusinessSlugHistory{BusinessID: business.ID, OldSlug: oldSlug, NewSlug: slug}
+	if actorID != 0 {
+		entry.ChangedBy = &actorID
+	}
+	return tx.Create(entry).Error
+}
+
+// withUniqueSlug calls save with base, or base numbered when it is taken. The unique index
+// decides: when a concurrent insert takes the slug first, save is rolled back to a
+// savepoint and retried with another one.
+func withUniqueSlug(tx *gorm.DB, base string, save func(slug string) error) error {
+	for attempt := 0; attempt < slugAttempts; attempt++ {
+		slug, err := nextFreeSlug(tx, base, attempt)
+		if err != nil {
+			return fmt.Errorf("error checking slug existence: %w", err)
+		}
+
+		if err := tx.SavePoint("business_slug").Error; err != nil {
+			return err
+		}
+		err = save(slug)
+		if err == nil {
+			return nil
+		}
+		if rollbackErr := tx.RollbackTo("business_slug").Error; rollbackErr != nil {
+			return rollbackErr
+		}
+		if constraint, ok := UniqueViolation(err); !ok || constraint != "idx_business_slug" {
+			return err
+		}
+	}
+
+	return ErrSlugUnavailable
+}
+
+// nextFreeSlug returns base when it is free, otherwise base with one more than the highest
+// number in use appended. After numberedSlugAttempts it appends a random suffix instead,
+// so concurrent creations stop racing for the same number.
+func nextFreeSlug(tx *gorm.DB, base string, attempt int) (string, error) {
+	if attempt >= numberedSlugAttempts {
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", err
+		}
+		return base + "-" + hex.EncodeToString(suffix), nil
+	}
+
+	var taken struct {
+		BaseTaken bool
+		Highest   int
+	}
+	err := tx.Model(&models.Business{}).
+		Select("COALESCE(BOOL_OR(slug = ?), FALSE) AS base_taken, "+
+			"COALESCE(MAX(CASE WHEN slug = ? THEN 0 ELSE CAST(SUBSTRING(slug FROM ?) AS int) END), 0) AS highest",
+			base, base, len(base)+2).
+		Where("slug ~ ?", "^"+regexp.QuoteMeta(base)+"(-[0-9]{1,9})?$").
+		Scan(&taken).Error
+	if err != nil {
+		return "", err
+	}
+
+	if !taken.BaseTaken {
+		return base, nil
+	}
+	return fmt.Sprintf("%s-%d", base, taken.Highest+1), nil
+}
+
+func (r *businessRepository) GetByID(ctx context.Context, id uint) (*models.Business, error) {
 	if id == 0 {
 		return nil, fmt.Errorf("invalid business ID")
 	}
 
 	var business models.Business
-	err := r.db.First(&business, id).Error
+	err := r.db.WithContext(ctx).First(&business, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &business, nil
 }
 
-func (r *businessRepository) GetBySlug(slug string) (*models.Business, error) {
+func (r *businessRepository) GetTimezone(ctx context.Context, id uint) (string, error) {
+	var business models.Business
+	err := r.db.WithContext(ctx).Select("timezone").First(&business, id).Error
+	return business.Timezone, err
+}
+
+func (r *businessRepository) GetByIDs(ctx context.Context, ids []uint) ([]models.Business, error) {
+	if len(ids) == 0 {
+		return []models.Business{}, nil
+	}
+
+	var businesses []models.Business
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&businesses).Error
+	return businesses, err
+}
+
+func (r *businessRepository) GetBySlug(ctx context.Context, slug string) (*models.Business, error) {
 	if slug == "" {
 		return nil, fmt.Errorf("slug cannot be empty")
 	}
 
 	var business models.Business
-	err := r.db.Where("slug = ?", slug).First(&business).Error
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&business).Error
 	if err != nil {
 		return nil, err
 	}
 	return &business, nil
 }
 
-func (r *businessRepository) GetByUserID(userID uint) (*models.Business, error) {
+func (r *businessRepository) GetByUserID(ctx context.Context, userID uint) (*models.Business, error) {
 	if userID == 0 {
 		return nil, fmt.Errorf("invalid user ID")
 	}
 
 	var business models.Business
-	err := r.db.Where("user_id = ?", userID).First(&business).Error
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&business).Error
 	if err != nil {
 		return nil, err
 	}
 	return &business, nil
 }
 
-func (r *businessRepository) GetByEmail(email string) (*models.Business, error) {
+func (r *businessRepository) GetByEmail(ctx context.Context, email string) (*models.Business, error) {
 	if email == "" {
 		return nil, fmt.Errorf("email cannot be empty")
 	}
 
 	var business models.Business
-	err := r.db.Where("email = ?", email).First(&business).Error
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&business).Error
 	if err != nil {
 		return nil, err
 	}
 	return &business, nil
 }
 
-func (r *businessRepository) GetAll(filters BusinessFilters) ([]models.Business, int64, error) {
+func (r *businessRepository) GetAll(ctx context.Context, filters BusinessFilters) ([]models.Business, int64, error) {
 	var businesses []models.Business
-	var total int64
 
-	query := r.db.Model(&models.Business{})
+	query := applyBusinessFilters(r.db.WithContext(ctx).Model(&models.Business{}), filters)
 
-	// Apply filters
-	if filters.PackageID != nil {
-		if *filters.PackageID == 0 {
-			query = query.Where("package_id IS NULL")
-		} else {
-			query = query.Where("package_id = ?", *filters.PackageID)
-		}
+	// Count total first (before pagination), unless skipped
+	total, err := countTotal(query, filters.totalMode())
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if filters.Status != nil {
-		query = query.Where("status = ?", *filters.Status)
+	// Apply sorting and pagination
+	query, err = paginate(query, filters.sort(), filters.Page, filters.Limit, filters.Cursor)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if filters.Location != "" {
-		query = query.Where("location ILIKE ?", "%"+filters.Location+"%")
-	}
+	err = query.Find(&businesses).Error
+	return businesses, total, err
+}
 
-	if filters.Search != "" {
-		query = query.Where("name ILIKE ? OR owner_name ILIKE ? OR email ILIKE ? OR location ILIKE ? OR slug ILIKE ?",
-			"%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%")
-	}
+func (r *businessRepository) GetAllWithRelations(ctx context.Context, filters BusinessFilters) ([]models.Business, int64, error) {
+	var businesses []models.Business
 
-	// Count total first (before pagination)
-	if err := query.Count(&total).Error; err != nil {
+	selection, err := filters.Selection()
+	if err != nil {
 		return nil, 0, err
 	}
+	query := applyBusinessFilters(r.db.WithContext(ctx).Model(&models.Business{}), filters)
 
-	// Apply sorting
-	orderBy := "created_on DESC" // default
-	if filters.SortBy != "" {
-		validSortFields := map[string]bool{
-			"created_on": true,
-			"updated_on": true,
-			"name":       true,
-			"owner_name": true,
-			"email":      true,
-			"location":   true,
-			"status":     true,
-			"slug":       true,
-		}
-		if validSortFields[filters.SortBy] {
-			sortOrder := "DESC"
-			if filters.SortOrder == "asc" {
-				sortOrder = "ASC"
-			}
-			orderBy = fmt.Sprintf("%s %s", filters.SortBy, sortOrder)
-		}
+	// Count total first (before preloads and pagination), unless skipped
+	total, err := countTotal(query, filters.totalMode())
+	if err != nil {
+		return nil, 0, err
 	}
-	query = query.Order(orderBy)
+	query = selection.preload(query, businessRelations)
 
-	// Apply pagination
-	if filters.Limit > 0 {
-		offset := 0
-		if filters.Page > 1 {
-			offset = (filters.Page - 1) * filters.Limit
-		}
-		query = query.Offset(offset).Limit(filters.Limit)
+	// Apply sorting and pagination
+	query, err = paginate(query, filters.sort(), filters.Page, filters.Limit, filters.Cursor)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	err := query.Find(&businesses).Error
+	err = query.Find(&businesses).Error
 	return businesses, total, err
 }
 
-func (r *businessRepository) GetAllWithRelations(filters BusinessFilters) ([]models.Business, int64, error) {
-	var businesses []models.Business
-	var total int64
+// applyBusinessFilters narrows a business query to the filters; the search matches any
+// of its columns, and every filter must hold
+// onTrial matches businesses on a free package, going by the price recorded when it was
+// assigned; a business assigned its package before prices were recorded is not on trial
+const onTrial = "(package_id IS NOT NULL AND COALESCE(package_price, -1) = 0)"
 
-	query := r.db.Model(&models.Business{}).Preload("User").Preload("Package")
+// ownerVerified matches businesses whose owner has used a password link
+const ownerVerified = "EXISTS (SELECT 1 FROM password_token pt WHERE pt.user_id = business.user_id AND pt.used_at IS NOT NULL)"
 
-	// Apply filters
+func applyBusinessFilters(query *gorm.DB, filters BusinessFilters) *gorm.DB {
 	if filters.PackageID != nil {
 		if *filters.PackageID == 0 {
 			query = query.Where("package_id IS NULL")
@@ -235,68 +443,76 @@ func (r *businessRepository) GetAllWithRelations(filters BusinessFilters) ([]mod
 		}
 	}
 
+	if filters.HasPackage != nil {
+		if *filters.HasPackage {
+			query = query.Where("package_id IS NOT NULL")
+		} else {
+			query = query.Where("package_id IS NULL")
+		}
+	}
+
+	if filters.IsTrial != nil {
+		if *filters.IsTrial {
+			query = query.Where(onTrial)
+		} else {
+			query = query.Where("NOT " + onTrial)
+		}
+	}
+
+	// Owners confirm their email by setting a password through an emailed link, the
+	// welcome link every new owner gets or a reset link
+	if filters.Verified != nil {
+		if *filters.Verified {
+			query = query.Where(ownerVerified)
+		} else {
+			query = query.Where("NOT " + ownerVerified)
+		}
+	}
+
 	if filters.Status != nil {
 		query = query.Where("status = ?", *filters.Status)
 	}
 
+	if filters.IsOpen != nil {
+		query = query.Where("is_open = ?", *filters.IsOpen)
+	}
+
 	if filters.Location != "" {
 		query = query.Where("location ILIKE ?", "%"+filters.Location+"%")
 	}
 
 	if filters.Search != "" {
-		query = query.Where("name ILIKE ? OR owner_name ILIKE ? OR email ILIKE ? OR location ILIKE ? OR slug ILIKE ?",
-			"%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%")
+		query = query.Where("name ILIKE ? OR owner_name ILIKE ? OR email ILIKE ? OR location ILIKE ? OR slug ILIKE ? OR "+phoneMatch,
+			"%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%", searchPhone(filters.Search))
 	}
 
-	// Count total first (before pagination)
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	// Dates are days in each business's own zone
+	if filters.ExpiresAfter != "" {
+		query = query.Where("package_expires_at >= CAST(? AS date)::timestamp AT TIME ZONE timezone", filters.ExpiresAfter)
 	}
-
-	// Apply sorting
-	orderBy := "created_on DESC"
-	if filters.SortBy != "" {
-		validSortFields := map[string]bool{
-			"created_on": true,
-			"updated_on": true,
-			"name":       true,
-			"owner_name": true,
-			"email":      true,
-			"location":   true,
-			"status":     true,
-			"slug":       true,
-		}
-		if validSortFields[filters.SortBy] {
-			sortOrder := "DESC"
-			if filters.SortOrder == "asc" {
-				sortOrder = "ASC"
-			}
-			orderBy = fmt.Sprintf("%s %s", filters.SortBy, sortOrder)
-		}
+	if filters.ExpiresBefore != "" {
+		query = query.Where("package_expires_at < CAST(? AS date)::timestamp AT TIME ZONE timezone", filters.ExpiresBefore)
 	}
-	query = query.Order(orderBy)
 
-	// Apply pagination
-	if filters.Limit > 0 {
-		offset := 0
-		if filters.Page > 1 {
-			offset = (filters.Page - 1) * filters.Limit
-		}
-		query = query.Offset(offset).Limit(filters.Limit)
+	if filters.CreatedFrom != "" {
+		query = query.Where("created_on >= CAST(? AS date)::timestamp AT TIME ZONE timezone", filters.CreatedFrom)
+	}
+	if filters.CreatedTo != "" {
+		query = query.Where("created_on < (CAST(? AS date) + 1)::timestamp AT TIME ZONE timezone", filters.CreatedTo)
 	}
 
-	err := query.Find(&businesses).Error
-	return businesses, total, err
+	return query
 }
 
-func (r *businessRepository) Update(business *models.Business) error {
+func (r *businessRepository) Update(ctx context.Context, business *models.Business) error {
 	if business == nil {
 		return fmt.Errorf("business cannot be nil")
 	}
 	if business.ID == 0 {
 		return fmt.Errorf("business ID cannot be zero")
 	}
-	return r.db.Save(business).Error
+	business.Version++
+	return r.db.WithContext(ctx).Save(business).Error
 }
 
 func (r *businessRepository) UpdateWithTransaction(tx *gorm.DB, business *models.Business) error {
@@ -306,84 +522,277 @@ func (r *businessRepository) UpdateWithTransaction(tx *gorm.DB, business *models
 	if business.ID == 0 {
 		return fmt.Errorf("business ID cannot be zero")
 	}
+	business.Version++
 	return tx.Save(business).Error
 }
 
-func (r *businessRepository) Delete(id uint) error {
+// UpdateVersionedWithTransaction saves the business only if it is still at expectedVersion,
+// returning ErrVersionConflict otherwise
+func (r *businessRepository) UpdateVersionedWithTransaction(tx *gorm.DB, business *models.Business, expectedVersion uint) error {
+	if business == nil {
+		return fmt.Errorf("business cannot be nil")
+	}
+	if business.ID == 0 {
+		return fmt.Errorf("business ID cannot be zero")
+	}
+	return saveVersioned(tx, business, &business.Version, expectedVersion)
+}
+
+func (r *businessRepository) UpdateFields(ctx context.Context, id uint, updates map[string]interface{}) error {
+	if id == 0 {
+		return fmt.Errorf("business ID cannot be zero")
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	fields := make(map[string]interface{}, len(updates)+1)
+	for column, value := range updates {
+		fields[column] = value
+	}
+	fields["version"] = bumpVersion
+	result := r.db.WithContext(ctx).Model(&models.Business{}).Where("id = ?", id).Updates(fields)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+func (r *businessRepository) Delete(ctx context.Context, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid business ID")
+	}
+	return r.db.WithContext(ctx).Delete(&models.Business{}, id).Error
+}
+
+// CountDependentRecords counts the records that would be left pointing at the
+// business if it were deleted on its own
+func (r *businessRepository) CountDependentRecords(ctx context.Context, id uint) (map[string]int64, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid business ID")
+	}
+
+	var row struct {
+		Teachers          int64
+		Students          int64
+		StudentAttendance int64
+		TeacherAttendance int64
+		FeePlans          int64
+		FeePayments       int64
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM teacher WHERE business_id = @id) AS teachers,
+			(SELECT COUNT(*) FROM student WHERE business_id = @id) AS students,
+			(SELECT COUNT(*) FROM student_attendance WHERE business_id = @id) AS student_attendance,
+			(SELECT COUNT(*) FROM teacher_attendance WHERE business_id = @id) AS teacher_attendance,
+			(SELECT COUNT(*) FROM fee_plan WHERE business_id = @id) AS fee_plans,
+			(SELECT COUNT(*) FROM fee_payment WHERE business_id = @id) AS fee_payments
+	`, map[string]interface{}{"id": id}).Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]int64{
+		"teachers":     row.Teachers,
+		"students":     row.Students,
+		"attendance":   row.StudentAttendance + row.TeacherAttendance,
+		"fee_plans":    row.FeePlans,
+		"fee_payments": row.FeePayments,
+	}, nil
+}
+
+// DeleteWithTransaction removes a business with everything it owns, including
+// the login accounts of the owner, its teachers and its students
+func (r *businessRepository) DeleteWithTransaction(tx *gorm.DB, id uint) error {
 	if id == 0 {
 		return fmt.Errorf("invalid business ID")
 	}
-	return r.db.Delete(&models.Business{}, id).Error
+
+	var business models.Business
+	if err := tx.Select("id", "user_id").First(&business, id).Error; err != nil {
+		return err
+	}
+
+	var userIDs []uint
+	err := tx.Raw(`SELECT user_id FROM teacher WHERE business_id = ? UNION SELECT user_id FROM student WHERE business_id = ?`,
+		id, id).Scan(&userIDs).Error
+	if err != nil {
+		return err
+	}
+	userIDs = append(userIDs, business.UserID)
+
+	const (
+		ofBusiness  = "business_id = ?"
+		ofTeachers  = "teacher_id IN (SELECT id FROM teacher WHERE business_id = ?)"
+		ofStudents  = "student_id IN (SELECT id FROM student WHERE business_id = ?)"
+		ofExams     = "exam_id IN (SELECT id FROM exam WHERE business_id = ?)"
+		ofUsers     = "user_id IN ?"
+		ofTransfers = ofTeachers + " OR from_business_id = ? OR to_business_id = ?"
+	)
+
+	// Children go before their parents so no foreign key blocks a delete
+	steps := []struct {
+		model interface{}
+		where string
+		args  []interface{}
+	}{
+		{&models.TeacherAvailability{}, ofTeachers, []interface{}{id}},
+		{&models.TeacherDocument{}, ofTeachers, []interface{}{id}},
+		{&models.TeacherSalaryHistory{}, ofTeachers, []interface{}{id}},
+		{&models.TeacherAssignmentHistory{}, ofTransfers, []interface{}{id, id, id}},
+		{&models.TeacherAttendance{}, ofBusiness, []interface{}{id}},
+		{&models.PayrollAdjustment{}, ofBusiness, []interface{}{id}},
+		{&models.PayrollLine{}, "run_id IN (SELECT id FROM payroll_run WHERE business_id = ?)", []interface{}{id}},
+		{&models.PayrollRun{}, ofBusiness, []interface{}{id}},
+		{&models.TeacherStudent{}, ofBusiness, []interface{}{id}},
+		{&models.StudentAttendance{}, ofBusiness, []interface{}{id}},
+		{&models.ExamResult{}, ofExams + " OR " + ofStudents, []interface{}{id, id}},
+		{&models.Exam{}, ofBusiness, []interface{}{id}},
+		{&models.FeePayment{}, ofBusiness, []interface{}{id}},
+		{&models.FeePlan{}, ofBusiness, []interface{}{id}},
+		{&models.SMSMessage{}, ofBusiness, []interface{}{id}},
+		{&models.GuardianLink{}, ofBusiness, []interface{}{id}},
+		{&models.StudentGuardian{}, ofStudents, []interface{}{id}},
+		{&models.StudentHistory{}, ofStudents, []interface{}{id}},
+		{&models.AnnouncementRead{}, "announcement_id IN (SELECT id FROM announcement WHERE business_id = ?) OR " + ofUsers, []interface{}{id, userIDs}},
+		{&models.Announcement{}, ofBusiness, []interface{}{id}},
+		{&models.StudentField{}, ofBusiness, []interface{}{id}},
+		{&models.StudentGrade{}, ofBusiness, []interface{}{id}},
+		{&models.StatusHistory{}, "entity_type = ? AND entity_id IN (SELECT id FROM teacher WHERE business_id = ?)", []interface{}{models.StatusEntityTeacher, id}},
+		{&models.StatusHistory{}, "entity_type = ? AND entity_id IN (SELECT id FROM student WHERE business_id = ?)", []interface{}{models.StatusEntityStudent, id}},
+		{&models.StatusHistory{}, "entity_type = ? AND entity_id = ?", []interface{}{models.StatusEntityBusiness, id}},
+		{&models.BusinessSlugHistory{}, ofBusiness, []interface{}{id}},
+		{&models.BusinessPackageHistory{}, ofBusiness, []interface{}{id}},
+		{&models.SampleRecord{}, ofBusiness, []interface{}{id}},
+		{&models.StudentCodeCounter{}, ofBusiness, []interface{}{id}},
+		{&models.BusinessSnapshot{}, ofBusiness, []interface{}{id}},
+	}
+	for _, step := range steps {
+		if err := tx.Where(step.where, step.args...).Delete(step.model).Error; err != nil {
+			return err
+		}
+	}
+
+	err = tx.Exec(`DELETE FROM teacher_subjects WHERE teacher_id IN (SELECT id FROM teacher WHERE business_id = ?)
+		OR subject_id IN (SELECT id FROM subject WHERE business_id = ?)`, id, id).Error
+	if err != nil {
+		return err
+	}
+
+	// Teachers who moved on keep their profile, just not the link back here
+	if err := tx.Unscoped().Model(&models.Teacher{}).Where("previous_business_id = ?", id).Update("previous_business_id", nil).Error; err != nil {
+		return err
+	}
+
+	// and keep this business's custom qualifications as free text
+	err = tx.Unscoped().Model(&models.Teacher{}).Where("qualification_id IN (SELECT id FROM qualification WHERE business_id = ?)", id).
+		Update("qualification_id", nil).Error
+	if err != nil {
+		return err
+	}
+
+	owned := []interface{}{
+		&models.Student{},
+		&models.Batch{},
+		&models.Teacher{},
+		&models.Subject{},
+		&models.Qualification{},
+	}
+	for _, model := range owned {
+		if err := tx.Unscoped().Where(ofBusiness, id).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Delete(&models.Business{}, id).Error; err != nil {
+		return err
+	}
+
+	logins := []interface{}{
+		&models.Notification{},
+		&models.PasswordToken{},
+		&models.ReportSubscription{},
+	}
+	for _, model := range logins {
+		if err := tx.Where(ofUsers, userIDs).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Where("id IN ?", userIDs).Delete(&models.User{}).Error
+}
+
+// GetSlugHistory returns the slug changes of a business, newest first
+func (r *businessRepository) GetSlugHistory(ctx context.Context, businessID uint) ([]models.BusinessSlugHistory, error) {
+	var entries []models.BusinessSlugHistory
+	err := r.db.WithContext(ctx).Where("business_id = ?", businessID).Order("created_on DESC, id DESC").Find(&entries).Error
+	return entries, err
 }
 
 // Status operations
 
-func (r *businessRepository) UpdateBusinessStatus(businessID uint, status int) error {
+func (r *businessRepository) UpdateBusinessStatus(ctx context.Context, businessID uint, status models.Status) error {
 	if businessID == 0 {
 		return fmt.Errorf("invalid business ID")
 	}
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return fmt.Errorf("invalid status value")
 	}
 
-	return r.db.Model(&models.Business{}).Where("id = ?", businessID).Update("status", status).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateStatus(tx, "business", models.StatusEntityBusiness, []uint{businessID}, status)
+	})
 }
 
-func (r *businessRepository) GetActiveBusinesses() ([]models.Business, error) {
-	var businesses []models.Business
-	err := r.db.Where("status = 1").Find(&businesses).Error
-	return businesses, err
+// RecordStatusChangeWithTransaction records a status change saved along with other updates
+func (r *businessRepository) RecordStatusChangeWithTransaction(tx *gorm.DB, businessID uint, status models.Status) error {
+	return recordStatusChanges(tx, models.StatusEntityBusiness, []uint{businessID}, status)
+}
+
+// RecordPackageChangeWithTransaction records a package change saved along with other updates
+func (r *businessRepository) RecordPackageChangeWithTransaction(tx *gorm.DB, businessID uint, oldPackageID, newPackageID *uint) error {
+	return tx.Create(&models.BusinessPackageHistory{BusinessID: businessID, OldPackageID: oldPackageID, NewPackageID: newPackageID}).Error
 }
 
-func (r *businessRepository) GetInactiveBusinesses() ([]models.Business, error) {
+func (r *businessRepository) GetInactiveBusinesses(ctx context.Context) ([]models.Business, error) {
 	var businesses []models.Business
-	err := r.db.Where("status = 0").Find(&businesses).Error
+	err := r.db.WithContext(ctx).Where("status = 0").Find(&businesses).Error
 	return businesses, err
 }
 
 // Package operations
 
-func (r *businessRepository) AssignPackage(businessID, packageID uint) error {
+func (r *businessRepository) AssignPackage(ctx context.Context, businessID, packageID uint) error {
 	if businessID == 0 || packageID == 0 {
 		return fmt.Errorf("invalid business ID or package ID")
 	}
 
-	return r.db.Model(&models.Business{}).Where("id = ?", businessID).Update("package_id", packageID).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, err := changePackage(tx, "id = ?", []interface{}{businessID}, &packageID, 0)
+		return err
+	})
 }
 
-func (r *businessRepository) RemovePackage(businessID uint) error {
+func (r *businessRepository) RemovePackage(ctx context.Context, businessID uint) error {
 	if businessID == 0 {
 		return fmt.Errorf("invalid business ID")
 	}
 
-	return r.db.Model(&models.Business{}).Where("id = ?", businessID).Update("package_id", nil).Error
-}
-
-func (r *businessRepository) GetBusinessesByPackage(packageID uint) ([]models.Business, error) {
-	if packageID == 0 {
-		return nil, fmt.Errorf("invalid package ID")
-	}
-
-	var businesses []models.Business
-	err := r.db.Where("package_id = ?", packageID).Find(&businesses).Error
-	return businesses, err
-}
-
-func (r *businessRepository) GetBusinessesWithoutPackage() ([]models.Business, error) {
-	var businesses []models.Business
-	err := r.db.Where("package_id IS NULL").Find(&businesses).Error
-	return businesses, err
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, err := changePackage(tx, "id = ?", []interface{}{businessID}, nil, 0)
+		return err
+	})
 }
 
 // Validation and utility
 
-func (r *businessRepository) BusinessEmailExists(email string, excludeBusinessID ...uint) (bool, error) {
+func (r *businessRepository) BusinessEmailExists(ctx context.Context, email string, excludeBusinessID ...uint) (bool, error) {
 	if email == "" {
 		return false, fmt.Errorf("email cannot be empty")
 	}
 
 	var count int64
-	query := r.db.Model(&models.Business{}).Where("email = ?", email)
+	query := r.db.WithContext(ctx).Model(&models.Business{}).Where("email = ?", email)
 
 	if len(excludeBusinessID) > 0 && excludeBusinessID[0] > 0 {
 		query = query.Where("id != ?", excludeBusinessID[0])
@@ -393,13 +802,13 @@ func (r *businessRepository) BusinessEmailExists(email string, excludeBusinessID
 	return count > 0, err
 }
 
-func (r *businessRepository) BusinessSlugExists(slug string, excludeBusinessID ...uint) (bool, error) {
+func (r *businessRepository) BusinessSlugExists(ctx context.Context, slug string, excludeBusinessID ...uint) (bool, error) {
 	if slug == "" {
 		return false, fmt.Errorf("slug cannot be empty")
 	}
 
 	var count int64
-	query := r.db.Model(&models.Business{}).Where("slug = ?", slug)
+	query := r.db.WithContext(ctx).Model(&models.Business{}).Where("slug = ?", slug)
 
 	if len(excludeBusinessID) > 0 && excludeBusinessID[0] > 0 {
 		query = query.Where("id != ?", excludeBusinessID[0])
@@ -409,13 +818,13 @@ func (r *businessRepository) BusinessSlugExists(slug string, excludeBusinessID .
 	return count > 0, err
 }
 
-func (r *businessRepository) BusinessNameExists(name string, excludeBusinessID ...uint) (bool, error) {
+func (r *businessRepository) BusinessNameExists(ctx context.Context, name string, excludeBusinessID ...uint) (bool, error) {
 	if name == "" {
 		return false, fmt.Errorf("name cannot be empty")
 	}
 
 	var count int64
-	query := r.db.Model(&models.Business{}).Where("name = ?", name)
+	query := r.db.WithContext(ctx).Model(&models.Business{}).Where("name = ?", name)
 
 	if len(excludeBusinessID) > 0 && excludeBusinessID[0] > 0 {
 		query = query.Where("id != ?", excludeBusinessID[0])
@@ -427,14 +836,14 @@ func (r *businessRepository) BusinessNameExists(name string, excludeBusinessID .
 
 // Search
 
-func (r *businessRepository) SearchBusinesses(searchTerm string, limit int) ([]models.Business, error) {
+func (r *businessRepository) SearchBusinesses(ctx context.Context, searchTerm string, limit int) ([]models.Business, error) {
 	if searchTerm == "" {
 		return []models.Business{}, nil
 	}
 
 	var businesses []models.Business
-	query := r.db.Where("name ILIKE ? OR owner_name ILIKE ? OR email ILIKE ? OR location ILIKE ? OR slug ILIKE ?",
-		"%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%").
+	query := r.db.WithContext(ctx).Where("name ILIKE ? OR owner_name ILIKE ? OR email ILIKE ? OR location ILIKE ? OR slug ILIKE ? OR "+phoneMatch,
+		"%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%", searchPhone(searchTerm)).
 		Order("created_on DESC")
 
 	if limit > 0 {
@@ -447,55 +856,63 @@ func (r *businessRepository) SearchBusinesses(searchTerm string, limit int) ([]m
 
 // Statistics
 
-func (r *businessRepository) GetBusinessStats() (map[string]interface{}, error) {
+func (r *businessRepository) GetBusinessStats(ctx context.Context) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
 	// Total businesses
 	var totalBusinesses int64
-	if err := r.db.Model(&models.Business{}).Count(&totalBusinesses).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Business{}).Count(&totalBusinesses).Error; err != nil {
 		return nil, err
 	}
 	stats["total_businesses"] = totalBusinesses
 
 	// Active businesses
 	var activeBusinesses int64
-	if err := r.db.Model(&models.Business{}).Where("status = 1").Count(&activeBusinesses).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Business{}).Where("status = 1").Count(&activeBusinesses).Error; err != nil {
 		return nil, err
 	}
 	stats["active_businesses"] = activeBusinesses
 
 	// Inactive businesses
 	var inactiveBusinesses int64
-	if err := r.db.Model(&models.Business{}).Where("status = 0").Count(&inactiveBusinesses).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Business{}).Where("status = 0").Count(&inactiveBusinesses).Error; err != nil {
 		return nil, err
 	}
 	stats["inactive_businesses"] = inactiveBusinesses
 
 	// Businesses with packages
 	var businessesWithPackages int64
-	if err := r.db.Model(&models.Business{}).Where("package_id IS NOT NULL").Count(&businessesWithPackages).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Business{}).Where("package_id IS NOT NULL").Count(&businessesWithPackages).Error; err != nil {
 		return nil, err
 	}
 	stats["businesses_with_packages"] = businessesWithPackages
 
 	// Businesses without packages
 	var businessesWithoutPackages int64
-	if err := r.db.Model(&models.Business{}).Where("package_id IS NULL").Count(&businessesWithoutPackages).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Business{}).Where("package_id IS NULL").Count(&businessesWithoutPackages).Error; err != nil {
 		return nil, err
 	}
 	stats["businesses_without_packages"] = businessesWithoutPackages
 
+	// Revenue of the active businesses' packages, at the price each was assigned on
+	var packageRevenue float64
+	if err := r.db.WithContext(ctx).Model(&models.Business{}).Where("status = 1 AND package_id IS NOT NULL").
+		Select("COALESCE(SUM(package_price), 0)").Scan(&packageRevenue).Error; err != nil {
+		return nil, err
+	}
+	stats["package_revenue"] = packageRevenue
+
 	return stats, nil
 }
 
-func (r *businessRepository) GetLocationStats() (map[string]int64, error) {
+func (r *businessRepository) GetLocationStats(ctx context.Context) (map[string]int64, error) {
 	type LocationStat struct {
 		Location string `json:"location"`
 		Count    int64  `json:"count"`
 	}
 
 	var stats []LocationStat
-	err := r.db.Model(&models.Business{}).
+	err := r.db.WithContext(ctx).Model(&models.Business{}).
 		Select("location, COUNT(*) as count").
 		Where("location IS NOT NULL AND location != ''").
 		Group("location").
@@ -514,16 +931,16 @@ func (r *businessRepository) GetLocationStats() (map[string]int64, error) {
 	return result, nil
 }
 
-func (r *businessRepository) GetPackageDistribution() (map[string]int64, error) {
+func (r *businessRepository) GetPackageDistribution(ctx context.Context) (map[string]int64, error) {
 	type PackageDistribution struct {
 		PackageName string `json:"package_name"`
 		Count       int64  `json:"count"`
 	}
 
 	var stats []PackageDistribution
-	err := r.db.Model(&models.Business{}).
+	err := r.db.WithContext(ctx).Model(&models.Business{}).
 		Select("COALESCE(packages.name, 'No Package') as package_name, COUNT(*) as count").
-		Joins("LEFT JOIN packages ON businesses.package_id = packages.id").
+		Joins("LEFT JOIN packages ON business.package_id = packages.id").
 		Group("packages.name").
 		Order("count DESC").
 		Scan(&stats).Error
@@ -540,28 +957,108 @@ func (r *businessRepository) GetPackageDistribution() (map[string]int64, error)
 	return result, nil
 }
 
+// growthSource says where an entity's growth is read from
+type growthSource struct {
+	table   string // rows counted as created by their created_on
+	changes string // status changes as (created_on, status) rows
+}
+
+var growthSources = map[string]growthSource{
+	"businesses": {table: "business", changes: "SELECT created_on, status FROM status_history WHERE entity_type = '" + models.StatusEntityBusiness + "'"},
+	"teachers":   {table: "teacher", changes: "SELECT created_on, status FROM status_history WHERE entity_type = '" + models.StatusEntityTeacher + "'"},
+	"students":   {table: "student", changes: "SELECT created_on, (payload->>'to')::int AS status FROM student_history WHERE event_type = '" + models.StudentEventStatusChanged + "'"},
+}
+
+// periodLabels format the start of a period as its ISO label
+var periodLabels = map[string]string{
+	models.GranularityWeek:  `IYYY-"W"IW`,
+	models.GranularityMonth: "YYYY-MM",
+}
+
+// growthQuery buckets created rows and status changes per period in one grouped query,
+// returning every period of the range even when nothing happened in it
+const growthQuery = `
+	WITH periods AS (
+		SELECT generate_series(date_trunc(@unit, @first::timestamptz), date_trunc(@unit, @last::timestamptz), @step::interval) AS start
+	), created AS (
+		SELECT date_trunc(@unit, created_on) AS start, COUNT(*) AS n
+		FROM %s
+		WHERE created_on >= @first AND created_on < @until
+		GROUP BY 1
+	), changes AS (
+		SELECT date_trunc(@unit, created_on) AS start,
+			COUNT(*) FILTER (WHERE status = 0) AS deactivated,
+			COUNT(*) FILTER (WHERE status = 1) AS reactivated
+		FROM (%s) c
+		WHERE created_on >= @first AND created_on < @until
+		GROUP BY 1
+	)
+	SELECT p.start, to_char(p.start, @label) AS period,
+		COALESCE(c.n, 0) AS created,
+		COALESCE(h.deactivated, 0) AS deactivated,
+		COALESCE(h.reactivated, 0) AS reactivated,
+		COALESCE(c.n, 0) + COALESCE(h.reactivated, 0) - COALESCE(h.deactivated, 0) AS net_active
+	FROM periods p
+	LEFT JOIN created c ON c.start = p.start
+	LEFT JOIN changes h ON h.start = p.start
+	ORDER BY p.start`
+
+// GetGrowthTimeseries counts the businesses, teachers and students created, deactivated
+// and reactivated in each week or month from the start of from's period to the end of to
+func (r *businessRepository) GetGrowthTimeseries(ctx context.Context, granularity string, from, to time.Time) (*models.GrowthTimeseries, error) {
+	label, ok := periodLabels[granularity]
+	if !ok {
+		return nil, fmt.Errorf("invalid granularity %q", granularity)
+	}
+
+	params := map[string]interface{}{
+		"unit":  granularity,
+		"step":  "1 " + granularity,
+		"label": label,
+		"first": from,
+		"last":  to,
+		"until": to.AddDate(0, 0, 1),
+	}
+
+	series := make(map[string][]models.TimeseriesPoint, len(growthSources))
+	for name, source := range growthSources {
+		points := []models.TimeseriesPoint{}
+		err := r.db.WithContext(ctx).Raw(fmt.Sprintf(growthQuery, source.table, source.changes), params).Scan(&points).Error
+		if err != nil {
+			return nil, fmt.Errorf("error counting %s: %w", name, err)
+		}
+		series[name] = points
+	}
+
+	return &models.GrowthTimeseries{
+		Businesses: series["businesses"],
+		Teachers:   series["teachers"],
+		Students:   series["students"],
+	}, nil
+}
+
 // Relationships
 
-func (r *businessRepository) GetBusinessWithRelations(id uint) (*models.Business, error) {
+func (r *businessRepository) GetBusinessWithRelations(ctx context.Context, id uint) (*models.Business, error) {
 	if id == 0 {
 		return nil, fmt.Errorf("invalid business ID")
 	}
 
 	var business models.Business
-	err := r.db.Preload("User").Preload("Package").First(&business, id).Error
+	err := r.db.WithContext(ctx).Preload("User").Preload("Package").First(&business, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &business, nil
 }
 
-func (r *businessRepository) GetBySlugWithRelations(slug string) (*models.Business, error) {
+func (r *businessRepository) GetBySlugWithRelations(ctx context.Context, slug string) (*models.Business, error) {
 	if slug == "" {
 		return nil, fmt.Errorf("slug cannot be empty")
 	}
 
 	var business models.Business
-	err := r.db.Preload("User").Preload("Package").Where("slug = ?", slug).First(&business).Error
+	err := r.db.WithContext(ctx).Preload("User").Preload("Package").Where("slug = ?", slug).First(&business).Error
 	if err != nil {
 		return nil, err
 	}
@@ -570,20 +1067,75 @@ func (r *businessRepository) GetBySlugWithRelations(slug string) (*models.Busine
 
 // Bulk operations
 
-func (r *businessRepository) BulkUpdateStatus(businessIDs []uint, status int) error {
+// BulkUpdateStatusWithTransaction changes the status of several businesses within a
+// transaction, recording each change
+func (r *businessRepository) BulkUpdateStatusWithTransaction(tx *gorm.DB, businessIDs []uint, status models.Status) error {
 	if len(businessIDs) == 0 {
 		return fmt.Errorf("no business IDs provided")
 	}
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return fmt.Errorf("invalid status value")
 	}
 
-	return r.db.Model(&models.Business{}).
-		Where("id IN ?", businessIDs).
-		Update("status", status).Error
+	return updateStatus(tx, "business", models.StatusEntityBusiness, businessIDs, status)
 }
 
-func (r *businessRepository) BulkAssignPackage(businessIDs []uint, packageID uint) error {
+// CascadeStatusWithTransaction carries a business status change over to its teachers
+// and students and their logins: deactivating takes every active one, reactivating
+// restores only those the deactivation took. Students also get a timeline entry,
+// marked with cascade_business_id in its payload. It returns the user IDs whose
+// status changed.
+func (r *businessRepository) CascadeStatusWithTransaction(tx *gorm.DB, businessID uint, status models.Status) ([]uint, error) {
+	if businessID == 0 {
+		return nil, fmt.Errorf("invalid business ID")
+	}
+	if !status.IsValid() {
+		return nil, fmt.Errorf("invalid status value")
+	}
+
+	_, teacherUsers, err := cascadeStatus(tx, "teacher", models.StatusEntityTeacher, businessID, status, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Student status changes made since the cascade are only in student_history
+	changedSince := ` AND NOT EXISTS (SELECT 1 FROM student_history sh WHERE sh.student_id = h.entity_id
+		AND sh.event_type = '` + models.StudentEventStatusChanged + `' AND sh.created_on > h.created_on
+		AND sh.payload->>'cascade_business_id' IS NULL)`
+	studentIDs, studentUsers, err := cascadeStatus(tx, "student", models.StatusEntityStudent, businessID, status, changedSince)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(studentIDs) > 0 {
+		summary := "Deactivated with business"
+		if status.IsActive() {
+			summary = "Reactivated with business"
+		}
+		history := make([]models.StudentHistory, len(studentIDs))
+		for i, studentID := range studentIDs {
+			history[i] = models.StudentHistory{
+				StudentID: studentID,
+				EventType: models.StudentEventStatusChanged,
+				Summary:   summary,
+				Payload:   models.JSONB{"from": 1 - status, "to": status, "cascade_business_id": businessID},
+			}
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	userIDs := append(teacherUsers, studentUsers...)
+	if len(userIDs) > 0 {
+		if err := tx.Model(&models.User{}).Where("id IN ?", userIDs).Update("status", status).Error; err != nil {
+			return nil, err
+		}
+	}
+	return userIDs, nil
+}
+
+func (r *businessRepository) BulkAssignPackage(ctx context.Context, businessIDs []uint, packageID uint) error {
 	if len(businessIDs) == 0 {
 		return fmt.Errorf("no business IDs provided")
 	}
@@ -591,26 +1143,107 @@ func (r *businessRepository) BulkAssignPackage(businessIDs []uint, packageID uin
 		return fmt.Errorf("invalid package ID")
 	}
 
-	return r.db.Model(&models.Business{}).
-		Where("id IN ?", businessIDs).
-		Update("package_id", packageID).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, err := changePackage(tx, "id IN ?", []interface{}{businessIDs}, &packageID, 0)
+		return err
+	})
 }
 
-// Location operations
+// BulkRemovePackage takes the package off the listed businesses that have one and
+// returns the owners of those it changed
+func (r *businessRepository) BulkRemovePackage(ctx context.Context, businessIDs []uint, actorID uint) ([]uint, error) {
+	if len(businessIDs) == 0 {
+		return nil, fmt.Errorf("no business IDs provided")
+	}
+
+	var userIDs []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		userIDs, err = changePackage(tx, "id IN ? AND package_id IS NOT NULL", []interface{}{businessIDs}, nil, actorID)
+		return err
+	})
+	return userIDs, err
+}
 
-func (r *businessRepository) GetBusinessesByLocation(location string) ([]models.Business, error) {
-	if location == "" {
-		return nil, fmt.Errorf("location cannot be empty")
+// SwapPackage moves every business on fromPackageID to toPackageID, starting the new
+// package's validation period from now, and returns the owners of those it changed
+func (r *businessRepository) SwapPackage(ctx context.Context, fromPackageID, toPackageID, actorID uint) ([]uint, error) {
+	if fromPackageID == 0 || toPackageID == 0 {
+		return nil, fmt.Errorf("invalid package ID")
 	}
 
+	var userIDs []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		userIDs, err = changePackage(tx, "package_id = ?", []interface{}{fromPackageID}, &toPackageID, actorID)
+		return err
+	})
+	return userIDs, err
+}
+
+// changePackage sets the package of the businesses matching where, nil removing it,
+// in a single UPDATE. A history row is written first for each business whose package
+// actually changes, naming actorID when it is known. It returns the owners of the
+// businesses updated.
+func changePackage(tx *gorm.DB, where string, args []interface{}, packageID *uint, actorID uint) ([]uint, error) {
+	var changedBy *uint
+	if actorID != 0 {
+		changedBy = &actorID
+	}
+	err := tx.Exec(`INSERT INTO business_package_history (business_id, old_package_id, new_package_id, changed_by, created_on)
+		SELECT id, package_id, CAST(? AS bigint), CAST(? AS bigint), NOW() FROM business
+		WHERE (`+where+`) AND package_id IS DISTINCT FROM ?`,
+		append(append([]interface{}{packageID, changedBy}, args...), packageID)...).Error
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"package_id": nil, "package_expires_at": nil, "package_price": nil, "package_validation_period": nil}
+	if packageID != nil {
+		updates = packageAssignment(*packageID)
+	}
+	var updated []models.Business
+	err = tx.Model(&updated).Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "user_id"}}}).
+		Where(where, args...).Updates(updates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uint, len(updated))
+	for i, business := range updated {
+		userIDs[i] = business.UserID
+	}
+	return userIDs, nil
+}
+
+// packageAssignment is the update assigning a package from now, expiring after its
+// validation period, and recording the price and period it was assigned on
+func packageAssignment(packageID uint) map[string]interface{} {
+	return map[string]interface{}{
+		"package_id":                packageID,
+		"package_expires_at":        gorm.Expr("NOW() + (SELECT validation_period FROM packages WHERE id = ?) * INTERVAL '1 day'", packageID),
+		"package_price":             gorm.Expr("(SELECT price FROM packages WHERE id = ?)", packageID),
+		"package_validation_period": gorm.Expr("(SELECT validation_period FROM packages WHERE id = ?)", packageID),
+	}
+}
+
+// GetBusinessesByPackageExpiry returns the active businesses whose package runs out in
+// [from, to), soonest first
+func (r *businessRepository) GetBusinessesByPackageExpiry(ctx context.Context, now time.Time, days int) ([]models.Business, error) {
 	var businesses []models.Business
-	err := r.db.Where("location ILIKE ?", "%"+location+"%").Find(&businesses).Error
+	err := r.db.WithContext(ctx).Preload("Package").
+		Where("status = 1 AND package_expires_at >= ?", now).
+		Where("(package_expires_at AT TIME ZONE timezone)::date <= (CAST(? AS timestamptz) AT TIME ZONE timezone)::date + ?", now, days).
+		Order("package_expires_at ASC, id ASC").
+		Find(&businesses).Error
 	return businesses, err
 }
 
-func (r *businessRepository) GetBusinessLocations() ([]string, error) {
+// Location operations
+
+func (r *businessRepository) GetBusinessLocations(ctx context.Context) ([]string, error) {
 	var locations []string
-	err := r.db.Model(&models.Business{}).
+	err := r.db.WithContext(ctx).Model(&models.Business{}).
 		Select("DISTINCT location").
 		Where("location IS NOT NULL AND location != ''").
 		Order("location").
@@ -620,6 +1253,6 @@ func (r *businessRepository) GetBusinessLocations() ([]string, error) {
 
 // Transaction support
 
-func (r *businessRepository) BeginTransaction() *gorm.DB {
-	return r.db.Begin()
+func (r *businessRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
 }
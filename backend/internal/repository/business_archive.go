@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BusinessArchiveRepository reads everything a business owns for an archive, and writes
+// an archive back as a new business
+type BusinessArchiveRepository interface {
+	// Export; the ForEach methods call fn batchSize rows at a time in id order
+	GetSubjects(ctx context.Context, businessID uint) ([]models.Subject, error)
+	GetBatches(ctx context.Context, businessID uint) ([]models.Batch, error)
+	GetStudentFields(ctx context.Context, businessID uint) ([]models.StudentField, error)
+	GetStudentGrades(ctx context.Context, businessID uint) ([]models.StudentGrade, error)
+	GetTeacherAvailability(ctx context.Context, businessID uint) ([]models.TeacherAvailability, error)
+	ForEachTeacher(ctx context.Context, businessID uint, batchSize int, fn func([]models.Teacher) error) error
+	ForEachStudent(ctx context.Context, businessID uint, batchSize int, fn func([]models.Student) error) error
+	ForEachStudentAttendance(ctx context.Context, businessID uint, batchSize int, fn func([]models.StudentAttendance) error) error
+	ForEachTeacherAttendance(ctx context.Context, businessID uint, batchSize int, fn func([]models.TeacherAttendance) error) error
+	ForEachFeePlan(ctx context.Context, businessID uint, batchSize int, fn func([]models.FeePlan) error) error
+	ForEachFeePayment(ctx context.Context, businessID uint, batchSize int, fn func([]models.FeePayment) error) error
+
+	// Import
+	UserEmailsInUse(ctx context.Context, emails []string) ([]string, error)
+	BusinessEmailInUse(ctx context.Context, email string) (bool, error)
+	// CreateWithTransaction inserts a slice of records, filling in their IDs, and leaves
+	// their associations alone
+	CreateWithTransaction(tx *gorm.DB, records interface{}) error
+	CreateTeacherSubjectsWithTransaction(tx *gorm.DB, teacherSubjects map[uint][]uint) error
+
+	// Transaction support
+	BeginTransaction(ctx context.Context) *gorm.DB
+}
+
+// archiveInsertBatch is how many rows each INSERT of an import carries
+const archiveInsertBatch = 500
+
+type businessArchiveRepository struct {
+	db *gorm.DB
+}
+
+func NewBusinessArchiveRepository(db *gorm.DB) BusinessArchiveRepository {
+	return &businessArchiveRepository{
+		db: db,
+	}
+}
+
+func (r *businessArchiveRepository) GetSubjects(ctx context.Context, businessID uint) ([]models.Subject, error) {
+	var subjects []models.Subject
+	err := r.db.WithContext(ctx).Where("business_id = ?", businessID).Order("id ASC").Find(&subjects).Error
+	return subjects, err
+}
+
+func (r *businessArchiveRepository) GetBatches(ctx context.Context, businessID uint) ([]models.Batch, error) {
+	var batches []models.Batch
+	err := r.db.WithContext(ctx).Where("business_id = ?", businessID).Order("id ASC").Find(&batches).Error
+	return batches, err
+}
+
+func (r *businessArchiveRepository) GetStudentFields(ctx context.Context, businessID uint) ([]models.StudentField, error) {
+	var fields []models.StudentField
+	err := r.db.WithContext(ctx).Where("business_id = ?", businessID).Order("id ASC").Find(&fields).Error
+	return fields, err
+}
+
+func (r *businessArchiveRepository) GetStudentGrades(ctx context.Context, businessID uint) ([]models.StudentGrade, error) {
+	var grades []models.StudentGrade
+	err := r.db.WithContext(ctx).Where("business_id = ?", businessID).Order("position ASC, id ASC").Find(&grades).Error
+	return grades, err
+}
+
+// GetTeacherAvailability returns the slots of every teacher of the business, active or not
+func (r *businessArchiveRepository) GetTeacherAvailability(ctx context.Context, businessID uint) ([]models.TeacherAvailability, error) {
+	var availabilities []models.TeacherAvailability
+	err := r.db.WithContext(ctx).
+		Joins("JOIN teacher ON teacher.id = teacher_availability.teacher_id").
+		Where("teacher.business_id = ?", businessID).
+		Order("teacher_availability.id ASC").
+		Find(&availabilities).Error
+	return availabilities, err
+}
+
+func (r *businessArchiveRepository) ForEachTeacher(ctx context.Context, businessID uint, batchSize int, fn func([]models.Teacher) error) error {
+	query := r.db.WithContext(ctx).Unscoped().Preload("User").Preload("Subjects")
+	return forEachInBusiness(query, businessID, batchSize, fn)
+}
+
+func (r *businessArchiveRepository) ForEachStudent(ctx context.Context, businessID uint, batchSize int, fn func([]models.Student) error) error {
+	query := r.db.WithContext(ctx).Unscoped().Preload("User").Preload("Guardians", orderGuardians)
+	return forEachInBusiness(query, businessID, batchSize, fn)
+}
+
+func (r *businessArchiveRepository) ForEachStudentAttendance(ctx context.Context, businessID uint, batchSize int, fn func([]models.StudentAttendance) error) error {
+	return forEachInBusiness(r.db.WithContext(ctx), businessID, batchSize, fn)
+}
+
+func (r *businessArchiveRepository) ForEachTeacherAttendance(ctx context.Context, businessID uint, batchSize int, fn func([]models.TeacherAttendance) error) error {
+	return forEachInBusiness(r.db.WithContext(ctx), businessID, batchSize, fn)
+}
+
+func (r *businessArchiveRepository) ForEachFeePlan(ctx context.Context, businessID uint, batchSize int, fn func([]models.FeePlan) error) error {
+	return forEachInBusiness(r.db.WithContext(ctx), businessID, batchSize, fn)
+}
+
+func (r *businessArchiveRepository) ForEachFeePayment(ctx context.Context, businessID uint, batchSize int, fn func([]models.FeePayment) error) error {
+	return forEachInBusiness(r.db.WithContext(ctx), businessID, batchSize, fn)
+}
+
+// forEachInBusiness pages through the rows of T that belong to a business in id order
+func forEachInBusiness[T any](query *gorm.DB, businessID uint, batchSize int, fn func([]T) error) error {
+	var batch []T
+	return query.Model(new(T)).Where("business_id = ?", businessID).
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// UserEmailsInUse returns which of the emails already belong to a user
+func (r *businessArchiveRepository) UserEmailsInUse(ctx context.Context, emails []string) ([]string, error) {
+	var taken []string
+	if len(emails) == 0 {
+		return taken, nil
+	}
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("email IN ?", emails).Order("email ASC").Pluck("email", &taken).Error
+	return taken, err
+}
+
+func (r *businessArchiveRepository) BusinessEmailInUse(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Business{}).Where("email = ?", email).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *businessArchiveRepository) CreateWithTransaction(tx *gorm.DB, records interface{}) error {
+	return tx.Omit(clause.Associations).CreateInBatches(records, archiveInsertBatch).Error
+}
+
+func (r *businessArchiveRepository) CreateTeacherSubjectsWithTransaction(tx *gorm.DB, teacherSubjects map[uint][]uint) error {
+	var rows []map[string]interface{}
+	for teacherID, subjectIDs := range teacherSubjects {
+		for _, subjectID := range subjectIDs {
+			rows = append(rows, map[string]interface{}{"teacher_id": teacherID, "subject_id": subjectID})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.Table("teacher_subjects").CreateInBatches(rows, archiveInsertBatch).Error
+}
+
+func (r *businessArchiveRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
+}
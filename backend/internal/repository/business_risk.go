@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"time"
+)
+
+// AtRiskFilters are the thresholds of the at-risk report, in days, and its page and sort
+type AtRiskFilters struct {
+	StudentDays int    `form:"student_days" json:"student_days"` // no student added for this many days
+	LoginDays   int    `form:"login_days" json:"login_days"`     // owner not logged in for this many days
+	ExpiryDays  int    `form:"expiry_days" json:"expiry_days"`   // package expiring within this many days
+	Page        int    `form:"page" json:"page"`
+	Limit       int    `form:"limit" json:"limit"`
+	SortBy      string `form:"sort_by" json:"sort_by"`       // comma-separated columns, score by default
+	SortOrder   string `form:"sort_order" json:"sort_order"` // asc or desc, for all columns or one per column
+}
+
+// atRiskSortFields are the columns the at-risk report can be sorted by
+var atRiskSortFields = map[string]bool{
+	"score":               true,
+	"name":                true,
+	"created_on":          true,
+	"last_student_at":     true,
+	"owner_last_login_at": true,
+	"package_expires_at":  true,
+}
+
+// PageInfo describes the page of the report that was returned
+func (f AtRiskFilters) PageInfo(total int64) PageInfo {
+	return PageInfo{Total: &total, Page: f.Page, Limit: f.Limit}
+}
+
+func (f AtRiskFilters) sort() listSort {
+	if f.SortBy == "" {
+		f.SortBy = "score"
+	}
+	return resolveSort(f.SortBy, f.SortOrder, atRiskSortFields)
+}
+
+// atRiskQuery scores every active business on the churn signals in one pass. A business
+// counts as adding no students, or its owner as inactive, only once it is older than the
+// threshold, so new businesses aren't flagged; an owner without a recorded login is
+// judged from when their account was created.
+const atRiskQuery = `WITH scored AS (
+	SELECT b.id, b.name, b.slug, b.email, b.owner_name, b.package_id, b.package_expires_at, b.created_on,
+		s.last_student_at, u.last_login_at AS owner_last_login_at,
+		COALESCE(s.last_student_at, b.created_on) < @student_cutoff AS no_new_students,
+		COALESCE(u.last_login_at, u.created_on) < @login_cutoff AS owner_inactive,
+		COALESCE(b.package_expires_at < @expiry_cutoff, false) AS package_expiring
+	FROM business b
+	JOIN users u ON u.id = b.user_id
+	LEFT JOIN (
+		SELECT business_id, MAX(created_on) AS last_student_at
+		FROM student WHERE deleted_at IS NULL
+		GROUP BY business_id
+	) s ON s.business_id = b.id
+	WHERE b.status = 1
+), at_risk AS (
+	SELECT *, no_new_students::int + owner_inactive::int + package_expiring::int AS score
+	FROM scored
+)`
+
+// GetAtRiskBusinesses returns a page of the active businesses showing at least one churn
+// signal and how many there are in all
+func (r *businessRepository) GetAtRiskBusinesses(ctx context.Context, filters AtRiskFilters, now time.Time) ([]models.AtRiskBusiness, int64, error) {
+	args := map[string]interface{}{
+		"student_cutoff": now.AddDate(0, 0, -filters.StudentDays),
+		"login_cutoff":   now.AddDate(0, 0, -filters.LoginDays),
+		"expiry_cutoff":  now.AddDate(0, 0, filters.ExpiryDays),
+	}
+	db := r.db.WithContext(ctx)
+
+	var total int64
+	if err := db.Raw(atRiskQuery+" SELECT COUNT(*) FROM at_risk WHERE score > 0", args).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := 0
+	if filters.Page > 1 {
+		offset = (filters.Page - 1) * filters.Limit
+	}
+	args["limit"] = filters.Limit
+	args["offset"] = offset
+
+	var rows []struct {
+		models.AtRiskBusiness
+		NoNewStudents   bool
+		OwnerInactive   bool
+		PackageExpiring bool
+	}
+	err := db.Raw(atRiskQuery+" SELECT * FROM at_risk WHERE score > 0 ORDER BY "+filters.sort().orderBy()+
+		" LIMIT @limit OFFSET @offset", args).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	businesses := make([]models.AtRiskBusiness, len(rows))
+	for i, row := range rows {
+		business := row.AtRiskBusiness
+		business.Signals = []string{}
+		if row.NoNewStudents {
+			business.Signals = append(business.Signals, models.RiskNoNewStudents)
+		}
+		if row.OwnerInactive {
+			business.Signals = append(business.Signals, models.RiskOwnerInactive)
+		}
+		if row.PackageExpiring {
+			business.Signals = append(business.Signals, models.RiskPackageExpiring)
+		}
+		businesses[i] = business
+	}
+	return businesses, total, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+// riskProfile is the history a business is given before it is scored, in days before now
+type riskProfile struct {
+	name        string
+	status      models.Status
+	createdAgo  int
+	studentAgo  int // 0 for no students
+	loginAgo    int // 0 for no recorded login; the owner's account is as old as the business
+	expiresIn   int // negative when the package has expired
+	wantSignals []string
+}
+
+// createRiskProfile creates a business with the given history and returns its ID
+func createRiskProfile(t *testing.T, db *gorm.DB, pkg *models.Package, now time.Time, p riskProfile) uint {
+	t.Helper()
+	business := testutil.CreateBusiness(t, db, p.name, p.status, pkg)
+	created := now.AddDate(0, 0, -p.createdAgo)
+
+	if err := db.Model(&models.Business{}).Where("id = ?", business.ID).UpdateColumns(map[string]interface{}{
+		"created_on": created, "package_expires_at": now.AddDate(0, 0, p.expiresIn),
+	}).Error; err != nil {
+		t.Fatalf("failed to date business %s: %v", p.name, err)
+	}
+	owner := map[string]interface{}{"created_on": created}
+	if p.loginAgo > 0 {
+		owner["last_login_at"] = now.AddDate(0, 0, -p.loginAgo)
+	}
+	if err := db.Model(&models.User{}).Where("id = ?", business.UserID).UpdateColumns(owner).Error; err != nil {
+		t.Fatalf("failed to date owner of %s: %v", p.name, err)
+	}
+	if p.studentAgo > 0 {
+		student := testutil.CreateStudent(t, db, p.name+" Pupil", business.ID, "8", "female", models.StatusActive)
+		if err := db.Model(&models.Student{}).Where("id = ?", student.ID).
+			UpdateColumn("created_on", now.AddDate(0, 0, -p.studentAgo)).Error; err != nil {
+			t.Fatalf("failed to date student of %s: %v", p.name, err)
+		}
+	}
+	return business.ID
+}
+
+// Each churn signal is raised by its own history, scores add up, and healthy, new and
+// inactive businesses are left out
+func TestAtRiskBusinesses(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	repo := NewBusinessRepository(db)
+	basic := f.Packages["Basic"]
+	now := time.Now()
+
+	profiles := []riskProfile{
+		{"Healthy Classes", models.StatusActive, 200, 5, 1, 60, nil},
+		{"Falling Enrolment", models.StatusActive, 200, 90, 1, 60, []string{models.RiskNoNewStudents}},
+		{"Absent Owner", models.StatusActive, 200, 5, 45, 60, []string{models.RiskOwnerInactive}},
+		{"Never Logged In", models.StatusActive, 200, 5, 0, 60, []string{models.RiskOwnerInactive}},
+		{"Expiring Soon", models.StatusActive, 200, 5, 1, 7, []string{models.RiskPackageExpiring}},
+		{"Already Expired", models.StatusActive, 200, 5, 1, -3, []string{models.RiskPackageExpiring}},
+		{"Every Signal", models.StatusActive, 200, 90, 45, 2,
+			[]string{models.RiskNoNewStudents, models.RiskOwnerInactive, models.RiskPackageExpiring}},
+		// Too new to have missed students or logins
+		{"Brand New", models.StatusActive, 10, 0, 0, 60, nil},
+		// Only active businesses are at risk of churning
+		{"Closed Down", models.StatusInactive, 200, 90, 45, 2, nil},
+	}
+	want := map[uint][]string{
+		// The seeded business's package expires in 10 days
+		f.Businesses["Sunrise Academy"].ID: {models.RiskPackageExpiring},
+	}
+	for _, p := range profiles {
+		id := createRiskProfile(t, db, &basic, now, p)
+		if p.wantSignals != nil {
+			want[id] = p.wantSignals
+		}
+	}
+
+	filters := AtRiskFilters{StudentDays: 60, LoginDays: 30, ExpiryDays: 14, Page: 1, Limit: 50}
+	businesses, total, err := repo.GetAtRiskBusinesses(context.Background(), filters, now)
+	if err != nil {
+		t.Fatalf("GetAtRiskBusinesses: %v", err)
+	}
+	if total != int64(len(want)) || len(businesses) != len(want) {
+		t.Errorf("found %d of %d businesses, want %d", len(businesses), total, len(want))
+	}
+	for i, business := range businesses {
+		signals, ok := want[business.ID]
+		if !ok {
+			t.Errorf("%s listed with %v, want it left out", business.Name, business.Signals)
+			continue
+		}
+		if !slices.Equal(business.Signals, signals) || business.Score != len(signals) {
+			t.Errorf("%s: signals %v, score %d, want %v", business.Name, business.Signals, business.Score, signals)
+		}
+		if i > 0 && business.Score > businesses[i-1].Score {
+			t.Errorf("%s scores %d after a score of %d, want the highest first", business.Name, business.Score, businesses[i-1].Score)
+		}
+	}
+
+	// Tighter thresholds drop the signals they no longer reach
+	filters = AtRiskFilters{StudentDays: 120, LoginDays: 60, ExpiryDays: 1, Page: 1, Limit: 50}
+	businesses, _, err = repo.GetAtRiskBusinesses(context.Background(), filters, now)
+	if err != nil {
+		t.Fatalf("GetAtRiskBusinesses: %v", err)
+	}
+	var names []string
+	for _, business := range businesses {
+		names = append(names, business.Name)
+	}
+	slices.Sort(names)
+	// Only the expired package and the owners who never logged in still count
+	if wantNames := []string{"Already Expired", "Never Logged In"}; !slices.Equal(names, wantNames) {
+		t.Errorf("with tighter thresholds found %v, want %v", names, wantNames)
+	}
+}
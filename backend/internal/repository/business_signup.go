@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type BusinessSignupRepository interface {
+	BeginTransaction(ctx context.Context) *gorm.DB
+	CreateInTransaction(tx *gorm.DB, signup *models.PendingSignup) error
+	GetByID(ctx context.Context, id uint) (*models.PendingSignup, error)
+	GetSignups(ctx context.Context, filters BusinessSignupFilters) ([]models.PendingSignup, int64, error)
+
+	// PendingEmailExistsInTransaction reports whether a pending sign-up uses email, ignoring case
+	PendingEmailExistsInTransaction(tx *gorm.DB, email string) (bool, error)
+
+	// Review closes a pending sign-up with updates, which set its new status. It reports
+	// false when the sign-up is missing or was reviewed already, so two admins can't both
+	// act on it.
+	Review(ctx context.Context, id uint, reviewerID uint, updates map[string]interface{}) (bool, error)
+}
+
+type BusinessSignupFilters struct {
+	Status string `form:"status" json:"status" binding:"omitempty,oneof=pending approved rejected"`
+	Search string `form:"search" json:"search"` // in name, owner name and email
+	Page   int    `form:"page" json:"page"`
+	Limit  int    `form:"limit" json:"limit"`
+}
+
+type businessSignupRepository struct {
+	db *gorm.DB
+}
+
+func NewBusinessSignupRepository(db *gorm.DB) BusinessSignupRepository {
+	return &businessSignupRepository{
+		db: db,
+	}
+}
+
+func (r *businessSignupRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
+}
+
+func (r *businessSignupRepository) CreateInTransaction(tx *gorm.DB, signup *models.PendingSignup) error {
+	return tx.Create(signup).Error
+}
+
+func (r *businessSignupRepository) GetByID(ctx context.Context, id uint) (*models.PendingSignup, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid sign-up ID")
+	}
+
+	var signup models.PendingSignup
+	err := r.db.WithContext(ctx).First(&signup, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &signup, nil
+}
+
+func (r *businessSignupRepository) GetSignups(ctx context.Context, filters BusinessSignupFilters) ([]models.PendingSignup, int64, error) {
+	var signups []models.PendingSignup
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.PendingSignup{})
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.Search != "" {
+		term := "%" + filters.Search + "%"
+		query = query.Where("name ILIKE ? OR owner_name ILIKE ? OR email ILIKE ?", term, term, term)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Order("created_on DESC, id DESC")
+
+	// Apply pagination
+	if filters.Limit > 0 {
+		offset := 0
+		if filters.Page > 1 {
+			offset = (filters.Page - 1) * filters.Limit
+		}
+		query = query.Offset(offset).Limit(filters.Limit)
+	}
+
+	err := query.Find(&signups).Error
+	return signups, total, err
+}
+
+func (r *businessSignupRepository) PendingEmailExistsInTransaction(tx *gorm.DB, email string) (bool, error) {
+	var count int64
+	err := tx.Model(&models.PendingSignup{}).
+		Where("LOWER(email) = LOWER(?) AND status = ?", email, models.SignupStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *businessSignupRepository) Review(ctx context.Context, id uint, reviewerID uint, updates map[string]interface{}) (bool, error) {
+	updates["reviewed_by"] = reviewerID
+	updates["reviewed_at"] = time.Now()
+	result := r.db.WithContext(ctx).Model(&models.PendingSignup{}).
+		Where("id = ? AND status = ?", id, models.SignupStatusPending).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"backend/internal/models"
+	"backend/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+// newBusinessWithSlug builds an unsaved business under slug, with an owner of its own
+func newBusinessWithSlug(t *testing.T, db *gorm.DB, name, slug string) *models.Business {
+	t.Helper()
+	owner := testutil.CreateUser(t, db, name+" Owner", models.RoleBusiness)
+	return &models.Business{
+		Name:      name,
+		Slug:      slug,
+		UserID:    owner.ID,
+		OwnerName: owner.Name,
+		Email:     strings.ReplaceAll(strings.ToLower(name), " ", ".") + "@example.com",
+		Status:    models.StatusActive,
+	}
+}
+
+// A taken slug is numbered after the highest number in use
+func TestCreateWithUniqueSlugNumbersTakenSlugs(t *testing.T) {
+	db := testutil.DB(t)
+	testutil.Seed(t, db) // sunrise-academy is taken
+	repo := NewBusinessRepository(db)
+
+	create := func(name, slug string) string {
+		t.Helper()
+		business := newBusinessWithSlug(t, db, name, slug)
+		if err := repo.CreateWithUniqueSlugTransaction(db, business); err != nil {
+			t.Fatalf("CreateWithUniqueSlugTransaction(%s): %v", slug, err)
+		}
+		return business.Slug
+	}
+
+	if got := create("Sunrise Two", "sunrise-academy"); got != "sunrise-academy-1" {
+		t.Errorf("slug = %q, want sunrise-academy-1", got)
+	}
+	if got := create("Sunrise Three", "sunrise-academy"); got != "sunrise-academy-2" {
+		t.Errorf("slug = %q, want sunrise-academy-2", got)
+	}
+	if got := create("Sunrise Seven", "sunrise-academy-7"); got != "sunrise-academy-7" {
+		t.Errorf("slug = %q, want the free sunrise-academy-7", got)
+	}
+	if got := create("Sunrise Eight", "sunrise-academy"); got != "sunrise-academy-8" {
+		t.Errorf("slug = %q, want sunrise-academy-8 after the highest number", got)
+	}
+	// Other slugs that merely start with the base don't count
+	if got := create("Sunrise Academy Plus", "sunrise-academy-plus"); got != "sunrise-academy-plus" {
+		t.Errorf("slug = %q, want sunrise-academy-plus", got)
+	}
+}
+
+// When another insert takes the slug between the check and the insert, the create is
+// retried under the next number and both businesses keep distinct slugs
+func TestCreateWithUniqueSlugRetriesOnConflict(t *testing.T) {
+	db := testutil.DB(t)
+	testutil.Seed(t, db)
+	repo := NewBusinessRepository(db)
+	rival := testutil.CreateBusiness(t, db, "Rival Classes", models.StatusActive, nil)
+
+	// Just before the insert's savepoint, the rival takes the slug the check found free
+	stolen := false
+	callback := "test:steal_slug"
+	if err := db.Callback().Raw().Before("gorm:raw").Register(callback, func(tx *gorm.DB) {
+		if stolen || !strings.HasPrefix(tx.Statement.SQL.String(), "SAVEPOINT business_slug") {
+			return
+		}
+		stolen = true
+		if err := tx.Session(&gorm.Session{NewDB: true}).Model(&models.Business{}).
+			Where("id = ?", rival.ID).Update("slug", "orchid-tutors").Error; err != nil {
+			tx.AddError(fmt.Errorf("failed to take the slug: %w", err))
+		}
+	}); err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+	t.Cleanup(func() { db.Callback().Raw().Remove(callback) })
+
+	business := newBusinessWithSlug(t, db, "Orchid Tutors", "orchid-tutors")
+	if err := repo.CreateWithUniqueSlugTransaction(db, business); err != nil {
+		t.Fatalf("CreateWithUniqueSlugTransaction: %v", err)
+	}
+	if !stolen {
+		t.Fatal("the slug was never taken; the test didn't race the insert")
+	}
+
+	var slugs []string
+	if err := db.Model(&models.Business{}).Where("id IN ?", []uint{rival.ID, business.ID}).
+		Order("id").Pluck("slug", &slugs).Error; err != nil {
+		t.Fatalf("failed to read slugs: %v", err)
+	}
+	if len(slugs) != 2 || slugs[0] != "orchid-tutors" || slugs[1] != "orchid-tutors-1" {
+		t.Errorf("slugs = %v, want the rival on orchid-tutors and the new business on orchid-tutors-1", slugs)
+	}
+	if business.Slug != "orchid-tutors-1" {
+		t.Errorf("business.Slug = %q, want orchid-tutors-1", business.Slug)
+	}
+}
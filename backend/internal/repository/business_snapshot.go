@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BusinessSnapshotRepository interface {
+	// GetCounts returns the current counts of up to limit businesses with IDs above
+	// afterID, in ID order, for the snapshot job to walk every business in batches
+	GetCounts(ctx context.Context, afterID uint, limit int) ([]BusinessCounts, error)
+	// Upsert saves snapshots, replacing any a business already has for the same day
+	Upsert(ctx context.Context, snapshots []models.BusinessSnapshot) error
+
+	// GetSnapshots returns a business's snapshots from from through to, oldest first
+	GetSnapshots(ctx context.Context, businessID uint, from, to time.Time) ([]models.BusinessSnapshot, error)
+	// GetLatestBefore returns the business's last snapshot before date, or nil when
+	// it has none
+	GetLatestBefore(ctx context.Context, businessID uint, date time.Time) (*models.BusinessSnapshot, error)
+}
+
+// BusinessCounts is a business's active teachers and students right now
+type BusinessCounts struct {
+	BusinessID     uint
+	Timezone       string
+	ActiveTeachers int64
+	ActiveStudents int64
+}
+
+// snapshotUpsert overwrites the counts of a business's snapshot for the same day
+var snapshotUpsert = clause.OnConflict{
+	Columns:   []clause.Column{{Name: "business_id"}, {Name: "date"}},
+	DoUpdates: clause.AssignmentColumns([]string{"active_teachers", "active_students", "updated_on"}),
+}
+
+type businessSnapshotRepository struct {
+	db *gorm.DB
+}
+
+func NewBusinessSnapshotRepository(db *gorm.DB) BusinessSnapshotRepository {
+	return &businessSnapshotRepository{
+		db: db,
+	}
+}
+
+func (r *businessSnapshotRepository) GetCounts(ctx context.Context, afterID uint, limit int) ([]BusinessCounts, error) {
+	var counts []BusinessCounts
+	err := r.db.WithContext(ctx).Raw(`SELECT b.id AS business_id, b.timezone,
+			(SELECT COUNT(*) FROM teacher t WHERE t.business_id = b.id AND t.status = ? AND t.deleted_at IS NULL) AS active_teachers,
+			(SELECT COUNT(*) FROM student s WHERE s.business_id = b.id AND s.status = ? AND s.deleted_at IS NULL) AS active_students
+		FROM business b
+		WHERE b.id > ?
+		ORDER BY b.id
+		LIMIT ?`, models.StatusActive, models.StatusActive, afterID, limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *businessSnapshotRepository) Upsert(ctx context.Context, snapshots []models.BusinessSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(snapshotUpsert).Create(&snapshots).Error
+}
+
+func (r *businessSnapshotRepository) GetSnapshots(ctx context.Context, businessID uint, from, to time.Time) ([]models.BusinessSnapshot, error) {
+	var snapshots []models.BusinessSnapshot
+	err := r.db.WithContext(ctx).
+		Where("business_id = ? AND date BETWEEN ? AND ?", businessID, from.Format(models.DateFormat), to.Format(models.DateFormat)).
+		Order("date ASC").
+		Find(&snapshots).Error
+	return snapshots, err
+}
+
+func (r *businessSnapshotRepository) GetLatestBefore(ctx context.Context, businessID uint, date time.Time) (*models.BusinessSnapshot, error) {
+	var snapshot models.BusinessSnapshot
+	err := r.db.WithContext(ctx).
+		Where("business_id = ? AND date < ?", businessID, date.Format(models.DateFormat)).
+		Order("date DESC").
+		First(&snapshot).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
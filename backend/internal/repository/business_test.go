@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/testutil"
+)
+
+func TestBusinessGetAllFilters(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	repo := NewBusinessRepository(db)
+
+	// Dawn Classes is on a free trial package; only Sunrise's owner has set a password
+	// through an emailed link
+	trial := models.Package{Name: "Trial", Price: 0, ValidationPeriod: 14, Status: models.StatusActive}
+	if err := db.Create(&trial).Error; err != nil {
+		t.Fatalf("failed to create trial package: %v", err)
+	}
+	testutil.CreateBusiness(t, db, "Dawn Classes", models.StatusActive, &trial)
+	usedAt := time.Now()
+	token := models.PasswordToken{
+		UserID:    f.Businesses["Sunrise Academy"].UserID,
+		TokenHash: "used-welcome-token",
+		Purpose:   models.PasswordTokenWelcome,
+		ExpiresAt: usedAt.Add(time.Hour),
+		UsedAt:    &usedAt,
+	}
+	if err := db.Create(&token).Error; err != nil {
+		t.Fatalf("failed to create password token: %v", err)
+	}
+
+	yes, no := true, false
+	inactive := models.StatusInactive
+	// Business dates are days in UTC, the fixtures' time zone
+	day := func(offset int) string {
+		return time.Now().UTC().AddDate(0, 0, offset).Format(models.DateFormat)
+	}
+
+	tests := []struct {
+		name    string
+		filters BusinessFilters
+		want    []string
+	}{
+		{"no filters", BusinessFilters{}, []string{"Dawn Classes", "Moonlight Tutors", "Sunrise Academy"}},
+		{"with a package", BusinessFilters{HasPackage: &yes}, []string{"Dawn Classes", "Sunrise Academy"}},
+		{"without a package", BusinessFilters{HasPackage: &no}, []string{"Moonlight Tutors"}},
+		{"on trial", BusinessFilters{IsTrial: &yes}, []string{"Dawn Classes"}},
+		{"not on trial", BusinessFilters{IsTrial: &no}, []string{"Moonlight Tutors", "Sunrise Academy"}},
+		{"verified", BusinessFilters{Verified: &yes}, []string{"Sunrise Academy"}},
+		{"not verified", BusinessFilters{Verified: &no}, []string{"Dawn Classes", "Moonlight Tutors"}},
+		{"expiring within the window", BusinessFilters{ExpiresAfter: day(0), ExpiresBefore: day(11)}, []string{"Dawn Classes", "Sunrise Academy"}},
+		{"expiring before the window ends", BusinessFilters{ExpiresBefore: day(5)}, []string{}},
+		{"expiring after the window starts", BusinessFilters{ExpiresAfter: day(11)}, []string{}},
+		{"created today", BusinessFilters{CreatedFrom: day(0), CreatedTo: day(0)}, []string{"Dawn Classes", "Moonlight Tutors", "Sunrise Academy"}},
+		{"created before today", BusinessFilters{CreatedTo: day(-1)}, []string{}},
+		{"paying and verified", BusinessFilters{IsTrial: &no, HasPackage: &yes, Verified: &yes}, []string{"Sunrise Academy"}},
+		{"search and verified", BusinessFilters{Search: "sun", Verified: &yes}, []string{"Sunrise Academy"}},
+		{"search excluded by verified", BusinessFilters{Search: "dawn", Verified: &yes}, []string{}},
+		// The search ORs several columns and must not escape the other filters
+		{"search and status", BusinessFilters{Search: "a", Status: &inactive}, []string{"Moonlight Tutors"}},
+		{"search and trial", BusinessFilters{Search: "classes", IsTrial: &yes, ExpiresBefore: day(11)}, []string{"Dawn Classes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filters.SortBy = "name"
+			tt.filters.SortOrder = "asc"
+			if err := tt.filters.Validate(); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+
+			businesses, total, err := repo.GetAll(context.Background(), tt.filters)
+			if err != nil {
+				t.Fatalf("GetAll: %v", err)
+			}
+			names := make([]string, len(businesses))
+			for i, business := range businesses {
+				names[i] = business.Name
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("businesses = %v, want %v", names, tt.want)
+			}
+			if total != int64(len(tt.want)) {
+				t.Errorf("total = %d, want %d", total, len(tt.want))
+			}
+		})
+	}
+}
+
+// UpdateFields writes only the columns it is given, so a concurrent edit of the others
+// survives, and bumps the version so editors holding the old row get a conflict
+func TestBusinessUpdateFields(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	repo := NewBusinessRepository(db)
+	ctx := context.Background()
+
+	stale := f.Businesses["Sunrise Academy"]
+	if err := db.Model(&models.Business{}).Where("id = ?", stale.ID).Update("name", "Sunrise Academy East").Error; err != nil {
+		t.Fatalf("failed to rename business: %v", err)
+	}
+
+	if err := repo.UpdateFields(ctx, stale.ID, map[string]interface{}{"calendar_token_id": "token-1"}); err != nil {
+		t.Fatalf("UpdateFields() error = %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, stale.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.CalendarTokenID != "token-1" {
+		t.Errorf("CalendarTokenID = %q, want %q", got.CalendarTokenID, "token-1")
+	}
+	if got.Name != "Sunrise Academy East" {
+		t.Errorf("Name = %q, want the concurrent rename kept", got.Name)
+	}
+	if got.Version != stale.Version+1 {
+		t.Errorf("Version = %d, want %d", got.Version, stale.Version+1)
+	}
+
+	if err := repo.UpdateFields(ctx, 0, map[string]interface{}{"calendar_token_id": ""}); err == nil {
+		t.Error("UpdateFields() with a zero ID succeeded, want an error")
+	}
+	if err := repo.UpdateFields(ctx, stale.ID+1000, map[string]interface{}{"calendar_token_id": ""}); err == nil {
+		t.Error("UpdateFields() of a missing business succeeded, want an error")
+	}
+}
+
+// Deactivating a business takes its active teachers and students with it; reactivating
+// restores only those it took and nobody changed since
+func TestCascadeStatus(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	repo := NewBusinessRepository(db)
+	sunrise := f.Businesses["Sunrise Academy"].ID
+
+	statuses := func() map[string]models.Status {
+		t.Helper()
+		var rows []struct {
+			Name   string
+			Status models.Status
+		}
+		err := db.Raw(`SELECT name, status FROM teacher UNION ALL SELECT name, status FROM student`).Scan(&rows).Error
+		if err != nil {
+			t.Fatalf("failed to read statuses: %v", err)
+		}
+		got := map[string]models.Status{}
+		for _, row := range rows {
+			got[row.Name] = row.Status
+		}
+		return got
+	}
+	userIDs := func(teachers, students []string) []uint {
+		var ids []uint
+		for _, name := range teachers {
+			ids = append(ids, f.Teachers[name].UserID)
+		}
+		for _, name := range students {
+			ids = append(ids, f.Students[name].UserID)
+		}
+		return ids
+	}
+	userStatus := func(id uint) models.Status {
+		t.Helper()
+		var user models.User
+		if err := db.First(&user, id).Error; err != nil {
+			t.Fatalf("failed to read user %d: %v", id, err)
+		}
+		return user.Status
+	}
+	const on, off = models.StatusActive, models.StatusInactive
+
+	changed, err := repo.CascadeStatusWithTransaction(db, sunrise, off)
+	if err != nil {
+		t.Fatalf("CascadeStatusWithTransaction(off) error = %v", err)
+	}
+	if want := userIDs([]string{"Asha", "Bilal"}, []string{"Aarav", "Bina", "Diya"}); !sameIDs(changed, want) {
+		t.Errorf("changed users = %v, want %v", changed, want)
+	}
+	// Esha is soft-deleted and Dev and Farah are at Moonlight
+	want := map[string]models.Status{
+		"Asha": off, "Bilal": off, "Chitra": off, "Dev": on,
+		"Aarav": off, "Bina": off, "Chen": off, "Diya": off, "Esha": on, "Farah": on,
+	}
+	if got := statuses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("statuses after deactivating = %v, want %v", got, want)
+	}
+	if status := userStatus(f.Teachers["Asha"].UserID); status != off {
+		t.Errorf("Asha's login status = %v, want inactive", status)
+	}
+	var cascaded int64
+	db.Model(&models.StudentHistory{}).Where("payload->>'cascade_business_id' = ?", fmt.Sprint(sunrise)).Count(&cascaded)
+	if cascaded != 3 {
+		t.Errorf("cascaded student history entries = %d, want 3", cascaded)
+	}
+
+	// Bilal is reactivated and deactivated again by hand, and Bina's status is changed
+	// from her own page, so neither was left inactive by the business any more
+	teachers := NewTeacherRepository(db)
+	for _, status := range []models.Status{on, off} {
+		if err := teachers.BulkUpdateStatusWithTransaction(db, []uint{f.Teachers["Bilal"].ID}, status); err != nil {
+			t.Fatalf("failed to change Bilal's status: %v", err)
+		}
+	}
+	manual := models.StudentHistory{
+		StudentID: f.Students["Bina"].ID,
+		EventType: models.StudentEventStatusChanged,
+		Summary:   "Deactivated",
+		Payload:   models.JSONB{"from": on, "to": off},
+		CreatedOn: time.Now().Add(time.Second),
+	}
+	if err := db.Create(&manual).Error; err != nil {
+		t.Fatalf("failed to record Bina's status change: %v", err)
+	}
+
+	changed, err = repo.CascadeStatusWithTransaction(db, sunrise, on)
+	if err != nil {
+		t.Fatalf("CascadeStatusWithTransaction(on) error = %v", err)
+	}
+	if want := userIDs([]string{"Asha"}, []string{"Aarav", "Diya"}); !sameIDs(changed, want) {
+		t.Errorf("changed users = %v, want %v", changed, want)
+	}
+	want = map[string]models.Status{
+		"Asha": on, "Bilal": off, "Chitra": off, "Dev": on,
+		"Aarav": on, "Bina": off, "Chen": off, "Diya": on, "Esha": on, "Farah": on,
+	}
+	if got := statuses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("statuses after reactivating = %v, want %v", got, want)
+	}
+	if status := userStatus(f.Teachers["Asha"].UserID); status != on {
+		t.Errorf("Asha's login status = %v, want active", status)
+	}
+}
+
+func sameIDs(got, want []uint) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	seen := make(map[uint]int, len(want))
+	for _, id := range want {
+		seen[id]++
+	}
+	for _, id := range got {
+		if seen[id] == 0 {
+			return false
+		}
+		seen[id]--
+	}
+	return true
+}
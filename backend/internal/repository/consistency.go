@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ConsistencyRepository interface {
+	// Count and FindIDs run the anti-join finding a class of inconsistency
+	Count(ctx context.Context, class string) (int64, error)
+	FindIDs(ctx context.Context, class string, limit int) ([]uint, error)
+	// FindIDsWithTransaction finds every row of a class and locks it for the repair
+	FindIDsWithTransaction(tx *gorm.DB, class string) ([]uint, error)
+
+	// FindBusinessRelinksWithTransaction pairs businesses whose owner is missing with the
+	// user of the business role who has the same email and no business of their own
+	FindBusinessRelinksWithTransaction(tx *gorm.DB, businessIDs []uint) ([]BusinessRelink, error)
+	RelinkBusinessWithTransaction(tx *gorm.DB, businessID, userID uint) error
+
+	// Transaction support
+	BeginTransaction(ctx context.Context) *gorm.DB
+}
+
+// BusinessRelink is an owner found for a business whose user is missing
+type BusinessRelink struct {
+	BusinessID uint
+	OldUserID  uint
+	UserID     uint
+}
+
+// consistencyCheck is the table holding the rows of a class of inconsistency and the
+// condition picking them out. Inactive rows are left out: deactivating them is how a
+// repair resolves them.
+type consistencyCheck struct {
+	table string
+	where string
+}
+
+var consistencyChecks = map[string]consistencyCheck{
+	models.ConsistencyBusinessWithoutUser: {
+		table: "business",
+		where: "business.status = 1 AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = business.user_id)",
+	},
+	models.ConsistencyOwnerWithoutBusiness: {
+		table: "users",
+		where: "users.status = 1 AND users.role = 'business' AND NOT EXISTS (SELECT 1 FROM business b WHERE b.user_id = users.id)",
+	},
+	models.ConsistencyTeacherWithoutBusiness: {
+		table: "teacher",
+		where: "teacher.status = 1 AND teacher.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM business b WHERE b.id = teacher.business_id)",
+	},
+	models.ConsistencyTeacherWithoutUser: {
+		table: "teacher",
+		where: "teacher.status = 1 AND teacher.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = teacher.user_id)",
+	},
+	models.ConsistencyStudentWithoutBusiness: {
+		table: "student",
+		where: "student.status = 1 AND student.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM business b WHERE b.id = student.business_id)",
+	},
+	models.ConsistencyStudentWithoutUser: {
+		table: "student",
+		where: "student.status = 1 AND student.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = student.user_id)",
+	},
+}
+
+type consistencyRepository struct {
+	db *gorm.DB
+}
+
+func NewConsistencyRepository(db *gorm.DB) ConsistencyRepository {
+	return &consistencyRepository{
+		db: db,
+	}
+}
+
+func (r *consistencyRepository) Count(ctx context.Context, class string) (int64, error) {
+	query, err := consistencyQuery(r.db.WithContext(ctx), class)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = query.Count(&count).Error
+	return count, err
+}
+
+func (r *consistencyRepository) FindIDs(ctx context.Context, class string, limit int) ([]uint, error) {
+	query, err := consistencyQuery(r.db.WithContext(ctx), class)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	err = query.Order("id ASC").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *consistencyRepository) FindIDsWithTransaction(tx *gorm.DB, class string) ([]uint, error) {
+	query, err := consistencyQuery(tx, class)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	err = query.Clauses(clause.Locking{Strength: "UPDATE"}).Order("id ASC").Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *consistencyRepository) FindBusinessRelinksWithTransaction(tx *gorm.DB, businessIDs []uint) ([]BusinessRelink, error) {
+	if len(businessIDs) == 0 {
+		return nil, nil
+	}
+
+	var relinks []BusinessRelink
+	err := tx.Raw(`SELECT b.id AS business_id, b.user_id AS old_user_id, u.id AS user_id
+		FROM business b
+		JOIN users u ON LOWER(u.email) = LOWER(b.email) AND u.role = ?
+		WHERE b.id IN ? AND NOT EXISTS (SELECT 1 FROM business o WHERE o.user_id = u.id)
+		ORDER BY b.id`, models.RoleBusiness, businessIDs).
+		Scan(&relinks).Error
+	return relinks, err
+}
+
+func (r *consistencyRepository) RelinkBusinessWithTransaction(tx *gorm.DB, businessID, userID uint) error {
+	if businessID == 0 || userID == 0 {
+		return fmt.Errorf("invalid business or user ID")
+	}
+	return tx.Model(&models.Business{}).Where("id = ?", businessID).
+		Updates(map[string]interface{}{"user_id": userID, "version": bumpVersion}).Error
+}
+
+func (r *consistencyRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
+}
+
+func consistencyQuery(db *gorm.DB, class string) (*gorm.DB, error) {
+	check, ok := consistencyChecks[class]
+	if !ok {
+		return nil, fmt.Errorf("unknown consistency class %q", class)
+	}
+	return db.Table(check.table).Where(check.where), nil
+}
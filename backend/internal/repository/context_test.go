@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/testutil"
+)
+
+// Repository calls stop at the request's context: one that was cancelled, because the
+// client went away, or that expired, because the query timeout passed, fails the call
+// with the context's error instead of running the statement
+func TestRepositoryCallsHonourContext(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	teachers, businesses := NewTeacherRepository(db), NewBusinessRepository(db)
+	asha, sunrise := f.Teachers["Asha"], f.Businesses["Sunrise Academy"]
+	before, err := businesses.GetByID(context.Background(), sunrise.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	calls := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{"read", func(ctx context.Context) error {
+			_, err := teachers.GetByID(ctx, asha.ID)
+			return err
+		}},
+		{"list", func(ctx context.Context) error {
+			_, _, err := teachers.GetAll(ctx, TeacherFilters{BusinessID: &sunrise.ID})
+			return err
+		}},
+		{"write", func(ctx context.Context) error {
+			return businesses.UpdateFields(ctx, sunrise.ID, map[string]interface{}{"sms_notifications": true})
+		}},
+	}
+	contexts := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"cancelled", cancelled, context.Canceled},
+		{"expired", expired, context.DeadlineExceeded},
+	}
+	for _, c := range contexts {
+		for _, call := range calls {
+			t.Run(c.name+" "+call.name, func(t *testing.T) {
+				if err := call.call(c.ctx); !errors.Is(err, c.want) {
+					t.Errorf("error = %v, want %v", err, c.want)
+				}
+			})
+		}
+	}
+
+	// Nothing was written, and the connection is still usable
+	after, err := businesses.GetByID(context.Background(), sunrise.ID)
+	if err != nil {
+		t.Fatalf("GetByID after the aborted calls: %v", err)
+	}
+	if after.SMSNotifications != before.SMSNotifications || after.Version != before.Version {
+		t.Errorf("business changed by an aborted call: sms %v version %d, want %v %d",
+			after.SMSNotifications, after.Version, before.SMSNotifications, before.Version)
+	}
+}
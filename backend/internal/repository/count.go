@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// totalMode is how a list query counts its total: not at all (cursor mode or
+// with_total=false), exactly, or estimated from the planner's statistics
+type totalMode struct {
+	Skip     bool
+	Estimate bool
+}
+
+func newTotalMode(cursor string, withTotal *bool, estimate bool) totalMode {
+	return totalMode{
+		Skip:     cursor != "" || (withTotal != nil && !*withTotal),
+		Estimate: estimate,
+	}
+}
+
+// countTotal counts the rows matched by query on a cloned session, so the count never
+// picks up preloads, ordering or pagination and doesn't leak into the page query.
+// Estimates of unfiltered lists read pg_class.reltuples, falling back to an exact count
+// for tables Postgres has no statistics on yet; filtered ones use the row estimate of
+// the query plan.
+func countTotal(query *gorm.DB, mode totalMode) (int64, error) {
+	if mode.Skip {
+		return 0, nil
+	}
+
+	count := query.Session(&gorm.Session{})
+	if mode.Estimate {
+		estimate, ok, err := estimateRows(count)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return estimate, nil
+		}
+	}
+
+	var total int64
+	err := count.Count(&total).Error
+	return total, err
+}
+
+func estimateRows(query *gorm.DB) (int64, bool, error) {
+	if _, filtered := query.Statement.Clauses["WHERE"]; !filtered {
+		if err := query.Statement.Parse(query.Statement.Model); err != nil {
+			return 0, false, err
+		}
+
+		var reltuples float64
+		err := query.Session(&gorm.Session{NewDB: true}).
+			Raw("SELECT reltuples FROM pg_class WHERE oid = to_regclass(?)", query.Statement.Schema.Table).
+			Row().Scan(&reltuples)
+		if err != nil {
+			return 0, false, err
+		}
+		// -1 means the table was never vacuumed or analyzed
+		return int64(reltuples), reltuples >= 0, nil
+	}
+
+	stmt := query.Session(&gorm.Session{DryRun: true}).Find(&[]map[string]interface{}{}).Statement
+	var plan string
+	err := query.Session(&gorm.Session{NewDB: true}).
+		Raw("EXPLAIN (FORMAT JSON) "+stmt.SQL.String(), stmt.Vars...).
+		Row().Scan(&plan)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var plans []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &plans); err != nil {
+		return 0, false, fmt.Errorf("error reading query plan: %w", err)
+	}
+	if len(plans) == 0 {
+		return 0, false, nil
+	}
+	return int64(plans[0].Plan.Rows), true, nil
+}
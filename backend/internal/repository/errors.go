@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// uniqueViolationCode is the Postgres error code for a unique constraint violation
+const uniqueViolationCode = "23505"
+
+// UniqueViolation reports whether err is a unique constraint violation and, if so, the
+// name of the violated constraint or unique index
+func UniqueViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return pgErr.ConstraintName, true
+	}
+	return "", false
+}
+
+// IsNotFound reports whether err means the requested record doesn't exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
+// ErrVersionConflict is returned when a versioned record was changed by someone else
+// after the caller read it
+var ErrVersionConflict = errors.New("record was changed by someone else")
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExamRepository interface {
+	// Basic CRUD operations
+	Create(ctx context.Context, exam *models.Exam) error
+	GetByID(ctx context.Context, id uint) (*models.Exam, error)
+	GetAll(ctx context.Context, filters ExamFilters) ([]models.Exam, int64, error)
+	Update(ctx context.Context, exam *models.Exam) error
+	Delete(ctx context.Context, id uint) error
+
+	// Results
+	UpsertResultsWithTransaction(tx *gorm.DB, results []models.ExamResult) error
+	GetResults(ctx context.Context, examID uint) ([]models.ExamResult, error)
+	GetStudentResults(ctx context.Context, studentID uint, filters ExamFilters) ([]models.ExamResult, error)
+	GetRecentStudentResults(ctx context.Context, studentID uint, limit int) ([]models.ExamResult, error)
+	CountStudentResults(ctx context.Context, studentID uint) (int64, error)
+	DeleteResult(ctx context.Context, examID, studentID uint) error
+	CountResults(ctx context.Context, examIDs []uint) (map[uint]int64, error)
+	GetHighestMarks(ctx context.Context, examID uint) (float64, error)
+	GetResultStats(ctx context.Context, examID uint, passMarks float64) (*ExamResultStats, error)
+
+	// Transaction support
+	BeginTransaction(ctx context.Context) *gorm.DB
+}
+
+type ExamFilters struct {
+	BusinessID *uint  `form:"business_id" json:"business_id"`
+	BatchID    *uint  `form:"batch_id" json:"batch_id"`
+	SubjectID  *uint  `form:"subject_id" json:"subject_id"`
+	From       string `form:"from" json:"from"` // YYYY-MM-DD, inclusive
+	To         string `form:"to" json:"to"`     // YYYY-MM-DD, inclusive
+	Page       int    `form:"page" json:"page"`
+	Limit      int    `form:"limit" json:"limit"`
+}
+
+// ExamResultStats aggregates the marks of an exam
+type ExamResultStats struct {
+	Appeared int64
+	Passed   int64
+	Average  float64
+	Highest  float64
+	Lowest   float64
+}
+
+type examRepository struct {
+	db *gorm.DB
+}
+
+func NewExamRepository(db *gorm.DB) ExamRepository {
+	return &examRepository{
+		db: db,
+	}
+}
+
+func (r *examRepository) Create(ctx context.Context, exam *models.Exam) error {
+	if exam == nil {
+		return fmt.Errorf("exam cannot be nil")
+	}
+	return r.db.WithContext(ctx).Omit("Batch", "Subject").Create(exam).Error
+}
+
+func (r *examRepository) GetByID(ctx context.Context, id uint) (*models.Exam, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid exam ID")
+	}
+
+	var exam models.Exam
+	err := r.db.WithContext(ctx).Preload("Batch").Preload("Subject").First(&exam, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &exam, nil
+}
+
+func (r *examRepository) GetAll(ctx context.Context, filters ExamFilters) ([]models.Exam, int64, error) {
+	var exams []models.Exam
+	var total int64
+
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&models.Exam{}), filters)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Preload("Batch").Preload("Subject").Order("date DESC, id DESC")
+
+	// Apply pagination
+	if filters.Limit > 0 {
+		offset := 0
+		if filters.Page > 1 {
+			offset = (filters.Page - 1) * filters.Limit
+		}
+		query = query.Offset(offset).Limit(filters.Limit)
+	}
+
+	err := query.Find(&exams).Error
+	return exams, total, err
+}
+
+func (r *examRepository) Update(ctx context.Context, exam *models.Exam) error {
+	if exam == nil {
+		return fmt.Errorf("exam cannot be nil")
+	}
+	if exam.ID == 0 {
+		return fmt.Errorf("exam ID cannot be zero")
+	}
+	return r.db.WithContext(ctx).Omit("Batch", "Subject").Save(exam).Error
+}
+
+func (r *examRepository) Delete(ctx context.Context, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid exam ID")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Remove results before the exam itself
+		if err := tx.Where("exam_id = ?", id).Delete(&models.ExamResult{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Exam{}, id).Error
+	})
+}
+
+// examResultUpsert overwrites the existing result of the same student for the exam
+var examResultUpsert = clause.OnConflict{
+	Columns:   []clause.Column{{Name: "exam_id"}, {Name: "student_id"}},
+	DoUpdates: clause.AssignmentColumns([]string{"marks", "remarks", "recorded_by", "updated_on"}),
+}
+
+func (r *examRepository) UpsertResultsWithTransaction(tx *gorm.DB, results []models.ExamResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	return tx.Omit("Exam", "Student").Clauses(examResultUpsert).Create(&results).Error
+}
+
+func (r *examRepository) GetResults(ctx context.Context, examID uint) ([]models.ExamResult, error) {
+	var results []models.ExamResult
+	err := r.db.WithContext(ctx).Preload("Student").
+		Where("exam_id = ?", examID).
+		Order("marks DESC, student_id ASC").
+		Find(&results).Error
+	return results, err
+}
+
+// GetStudentResults returns a student's results with their exams, oldest exam first
+func (r *examRepository) GetStudentResults(ctx context.Context, studentID uint, filters ExamFilters) ([]models.ExamResult, error) {
+	var results []models.ExamResult
+
+	query := r.db.WithContext(ctx).Model(&models.ExamResult{}).
+		Joins("JOIN exam ON exam.id = exam_result.exam_id").
+		Where("exam_result.student_id = ?", studentID)
+
+	if filters.SubjectID != nil {
+		query = query.Where("exam.subject_id = ?", *filters.SubjectID)
+	}
+	if filters.From != "" {
+		query = query.Where("exam.date >= ?", filters.From)
+	}
+	if filters.To != "" {
+		query = query.Where("exam.date <= ?", filters.To)
+	}
+
+	err := query.Preload("Exam.Subject").
+		Order("exam.date ASC, exam.id ASC").
+		Find(&results).Error
+	return results, err
+}
+
+// GetRecentStudentResults returns the most recently recorded results of a student, newest first
+func (r *examRepository) GetRecentStudentResults(ctx context.Context, studentID uint, limit int) ([]models.ExamResult, error) {
+	var results []models.ExamResult
+	err := r.db.WithContext(ctx).Where("student_id = ?", studentID).
+		Preload("Exam.Subject").
+		Order("updated_on DESC, id DESC").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
+}
+
+func (r *examRepository) CountStudentResults(ctx context.Context, studentID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.ExamResult{}).Where("student_id = ?", studentID).Count(&count).Error
+	return count, err
+}
+
+func (r *examRepository) DeleteResult(ctx context.Context, examID, studentID uint) error {
+	result := r.db.WithContext(ctx).Where("exam_id = ? AND student_id = ?", examID, studentID).Delete(&models.ExamResult{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *examRepository) CountResults(ctx context.Context, examIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64)
+	if len(examIDs) == 0 {
+		return counts, nil
+	}
+
+	var results []struct {
+		ExamID uint
+		Count  int64
+	}
+
+	err := r.db.WithContext(ctx).Model(&models.ExamResult{}).
+		Select("exam_id, COUNT(*) as count").
+		Where("exam_id IN ?", examIDs).
+		Group("exam_id").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		counts[result.ExamID] = result.Count
+	}
+	return counts, nil
+}
+
+func (r *examRepository) GetHighestMarks(ctx context.Context, examID uint) (float64, error) {
+	var highest float64
+	err := r.db.WithContext(ctx).Model(&models.ExamResult{}).
+		Select("COALESCE(MAX(marks), 0)").
+		Where("exam_id = ?", examID).
+		Scan(&highest).Error
+	return highest, err
+}
+
+func (r *examRepository) GetResultStats(ctx context.Context, examID uint, passMarks float64) (*ExamResultStats, error) {
+	var stats ExamResultStats
+	err := r.db.WithContext(ctx).Model(&models.ExamResult{}).
+		Select(`COUNT(*) as appeared,
+			COALESCE(SUM(CASE WHEN marks >= ? THEN 1 ELSE 0 END), 0) as passed,
+			COALESCE(AVG(marks), 0) as average,
+			COALESCE(MAX(marks), 0) as highest,
+			COALESCE(MIN(marks), 0) as lowest`, passMarks).
+		Where("exam_id = ?", examID).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (r *examRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
+}
+
+func (r *examRepository) applyFilters(query *gorm.DB, filters ExamFilters) *gorm.DB {
+	if filters.BusinessID != nil {
+		query = query.Where("business_id = ?", *filters.BusinessID)
+	}
+	if filters.BatchID != nil {
+		query = query.Where("batch_id = ?", *filters.BatchID)
+	}
+	if filters.SubjectID != nil {
+		query = query.Where("subject_id = ?", *filters.SubjectID)
+	}
+	if filters.From != "" {
+		query = query.Where("date >= ?", filters.From)
+	}
+	if filters.To != "" {
+		query = query.Where("date <= ?", filters.To)
+	}
+	return query
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FeeRepository interface {
+	// Fee plans
+	CreatePlanWithTransaction(tx *gorm.DB, plan *models.FeePlan) error
+	GetPlanByID(ctx context.Context, id uint) (*models.FeePlan, error)
+	GetPlans(ctx context.Context, filters FeePlanFilters) ([]models.FeePlan, int64, error)
+	GetActivePlansByBusiness(ctx context.Context, businessID uint) ([]models.FeePlan, error)
+
+	// Payments
+	CreatePaymentWithTransaction(tx *gorm.DB, payment *models.FeePayment) error
+	CreateExternalPayment(ctx context.Context, payment *models.FeePayment) (bool, error)
+	GetPaymentByID(ctx context.Context, id uint) (*models.FeePayment, error)
+	GetPaymentByExternalID(ctx context.Context, externalID string) (*models.FeePayment, error)
+	GetPayments(ctx context.Context, filters FeePaymentFilters) ([]models.FeePayment, int64, error)
+	GetPaymentTotals(ctx context.Context, filters FeePaymentFilters) ([]FeePaymentTotal, error)
+	GetPaymentTotalsByMode(ctx context.Context, filters FeePaymentFilters) ([]models.FeeModeTotal, error)
+	CountPaymentsByStudent(ctx context.Context, studentID uint) (int64, error)
+
+	// Transaction support
+	BeginTransaction(ctx context.Context) *gorm.DB
+}
+
+type FeePlanFilters struct {
+	BusinessID *uint `form:"business_id" json:"business_id"`
+	StudentID  *uint `form:"student_id" json:"student_id"`
+	BatchID    *uint `form:"batch_id" json:"batch_id"`
+	Status     *int  `form:"status" json:"status"`
+	Page       int   `form:"page" json:"page"`
+	Limit      int   `form:"limit" json:"limit"`
+}
+
+type FeePaymentFilters struct {
+	BusinessID *uint  `form:"business_id" json:"business_id"`
+	StudentID  *uint  `form:"student_id" json:"student_id"`
+	FeePlanID  *uint  `form:"fee_plan_id" json:"fee_plan_id"`
+	Mode       string `form:"mode" json:"mode" binding:"omitempty,oneof=cash card upi bank_transfer cheque other"`
+	From       string `form:"from" json:"from"` // YYYY-MM-DD, inclusive
+	To         string `form:"to" json:"to"`     // YYYY-MM-DD, inclusive
+	Page       int    `form:"page" json:"page"`
+	Limit      int    `form:"limit" json:"limit"`
+}
+
+// FeePaymentTotal is the amount a student paid towards a plan, or without a plan
+// when FeePlanID is nil
+type FeePaymentTotal struct {
+	StudentID uint
+	FeePlanID *uint
+	Amount    float64
+}
+
+type feeRepository struct {
+	db *gorm.DB
+}
+
+func NewFeeRepository(db *gorm.DB) FeeRepository {
+	return &feeRepository{
+		db: db,
+	}
+}
+
+func (r *feeRepository) CreatePlanWithTransaction(tx *gorm.DB, plan *models.FeePlan) error {
+	return tx.Create(plan).Error
+}
+
+func (r *feeRepository) GetPlanByID(ctx context.Context, id uint) (*models.FeePlan, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid fee plan ID")
+	}
+
+	var plan models.FeePlan
+	err := r.db.WithContext(ctx).Preload("Student").First(&plan, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *feeRepository) GetPlans(ctx context.Context, filters FeePlanFilters) ([]models.FeePlan, int64, error) {
+	var plans []models.FeePlan
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.FeePlan{})
+	if filters.BusinessID != nil {
+		query = query.Where("business_id = ?", *filters.BusinessID)
+	}
+	if filters.StudentID != nil {
+		query = query.Where("student_id = ?", *filters.StudentID)
+	}
+	if filters.BatchID != nil {
+		query = query.Where("batch_id = ?", *filters.BatchID)
+	}
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Preload("Student").Order("created_on DESC")
+
+	// Apply pagination
+	if filters.Limit > 0 {
+		offset := 0
+		if filters.Page > 1 {
+			offset = (filters.Page - 1) * filters.Limit
+		}
+		query = query.Offset(offset).Limit(filters.Limit)
+	}
+
+	err := query.Find(&plans).Error
+	return plans, total, err
+}
+
+func (r *feeRepository) GetActivePlansByBusiness(ctx context.Context, businessID uint) ([]models.FeePlan, error) {
+	var plans []models.FeePlan
+	err := r.db.WithContext(ctx).Where("business_id = ? AND status = ?", businessID, 1).Order("id ASC").Find(&plans).Error
+	return plans, err
+}
+
+func (r *feeRepository) CreatePaymentWithTransaction(tx *gorm.DB, payment *models.FeePayment) error {
+	return tx.Create(payment).Error
+}
+
+// CreateExternalPayment saves a payment confirmed by a payment gateway, reporting false
+// when a payment with the same external ID was already saved
+func (r *feeRepository) CreateExternalPayment(ctx context.Context, payment *models.FeePayment) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "external_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "external_id <> ''"}}},
+		DoNothing:   true,
+	}).Create(payment)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetPaymentByID returns a payment with its student and fee plan
+func (r *feeRepository) GetPaymentByID(ctx context.Context, id uint) (*models.FeePayment, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid fee payment ID")
+	}
+
+	var payment models.FeePayment
+	err := r.db.WithContext(ctx).Preload("Student").Preload("FeePlan").First(&payment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *feeRepository) GetPaymentByExternalID(ctx context.Context, externalID string) (*models.FeePayment, error) {
+	var payment models.FeePayment
+	err := r.db.WithContext(ctx).Preload("Student").Where("external_id = ? AND external_id <> ''", externalID).First(&payment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *feeRepository) GetPayments(ctx context.Context, filters FeePaymentFilters) ([]models.FeePayment, int64, error) {
+	var payments []models.FeePayment
+	var total int64
+
+	query := r.applyPaymentFilters(r.db.WithContext(ctx).Model(&models.FeePayment{}), filters)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Preload("Student").Order("paid_on DESC, id DESC")
+
+	// Apply pagination
+	if filters.Limit > 0 {
+		offset := 0
+		if filters.Page > 1 {
+			offset = (filters.Page - 1) * filters.Limit
+		}
+		query = query.Offset(offset).Limit(filters.Limit)
+	}
+
+	err := query.Find(&payments).Error
+	return payments, total, err
+}
+
+func (r *feeRepository) GetPaymentTotals(ctx context.Context, filters FeePaymentFilters) ([]FeePaymentTotal, error) {
+	var totals []FeePaymentTotal
+
+	err := r.applyPaymentFilters(r.db.WithContext(ctx).Model(&models.FeePayment{}), filters).
+		Select("student_id, fee_plan_id, SUM(amount) as amount").
+		Group("student_id, fee_plan_id").
+		Scan(&totals).Error
+	return totals, err
+}
+
+func (r *feeRepository) GetPaymentTotalsByMode(ctx context.Context, filters FeePaymentFilters) ([]models.FeeModeTotal, error) {
+	var totals []models.FeeModeTotal
+
+	err := r.applyPaymentFilters(r.db.WithContext(ctx).Model(&models.FeePayment{}), filters).
+		Select("mode, COUNT(*) as count, SUM(amount) as amount").
+		Group("mode").
+		Order("mode ASC").
+		Scan(&totals).Error
+	return totals, err
+}
+
+func (r *feeRepository) CountPaymentsByStudent(ctx context.Context, studentID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.FeePayment{}).Where("student_id = ?", studentID).Count(&count).Error
+	return count, err
+}
+
+func (r *feeRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
+}
+
+func (r *feeRepository) applyPaymentFilters(query *gorm.DB, filters FeePaymentFilters) *gorm.DB {
+	if filters.BusinessID != nil {
+		query = query.Where("business_id = ?", *filters.BusinessID)
+	}
+	if filters.StudentID != nil {
+		query = query.Where("student_id = ?", *filters.StudentID)
+	}
+	if filters.FeePlanID != nil {
+		query = query.Where("fee_plan_id = ?", *filters.FeePlanID)
+	}
+	if filters.Mode != "" {
+		query = query.Where("mode = ?", filters.Mode)
+	}
+	if filters.From != "" {
+		query = query.Where("paid_on >= ?", filters.From)
+	}
+	if filters.To != "" {
+		query = query.Where("paid_on <= ?", filters.To)
+	}
+	return query
+}
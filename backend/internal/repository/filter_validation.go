@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// statusValues are the values status filters accept: 0 or inactive, and 1 or active
+var statusValues = []string{"0", "1", "inactive", "active"}
+
+// validateStatus rejects a status filter that no record can have
+func validateStatus(status *models.Status) error {
+	if status == nil || status.IsValid() {
+		return nil
+	}
+	return &SelectionError{Param: "status", Unknown: strconv.Itoa(int(*status)), Valid: statusValues}
+}
+
+// validateOption rejects a filter value that isn't one of valid; empty means no filter
+func validateOption(param, value string, valid []string) error {
+	if value == "" || containsString(valid, value) {
+		return nil
+	}
+	return &SelectionError{Param: param, Unknown: value, Valid: valid}
+}
+
+// validateDateRange rejects bounds that aren't YYYY-MM-DD dates, and a range whose start
+// comes after its end; either bound may be empty
+func validateDateRange(fromParam, from, toParam, to string) error {
+	var fromDate, toDate time.Time
+	var err error
+	if from != "" {
+		if fromDate, err = time.Parse(models.DateFormat, from); err != nil {
+			return fmt.Errorf("%s must be a date in YYYY-MM-DD format", fromParam)
+		}
+	}
+	if to != "" {
+		if toDate, err = time.Parse(models.DateFormat, to); err != nil {
+			return fmt.Errorf("%s must be a date in YYYY-MM-DD format", toParam)
+		}
+	}
+	if from != "" && to != "" && fromDate.After(toDate) {
+		return fmt.Errorf("%s must not be after %s", fromParam, toParam)
+	}
+	return nil
+}
+
+// userRoles are the values the role filter accepts
+var userRoles = []string{
+	string(models.RoleAdmin), string(models.RoleBusiness), string(models.RoleTeacher), string(models.RoleStudent),
+}
+
+// genders are the values the student gender filter accepts
+var genders = []string{"male", "female", "other"}
+
+// The list filters' Validate methods reject unknown sort columns, sort directions and
+// filter values up front. The queries themselves ignore what they don't understand, so
+// without this a typo like sort_by=salaryy would quietly return the default order.
+
+// Validate checks the sort and filters of a user list
+func (f UserFilters) Validate() error {
+	if _, err := parseSort(f.SortBy, f.SortOrder, userSortFields); err != nil {
+		return err
+	}
+	if err := validateOption("role", f.Role, userRoles); err != nil {
+		return err
+	}
+	return validateStatus(f.Status)
+}
+
+// Validate checks the sort and filters of a business list
+func (f BusinessFilters) Validate() error {
+	if _, err := parseSort(f.SortBy, f.SortOrder, businessSortFields); err != nil {
+		return err
+	}
+	if err := validateDateRange("expires_after", f.ExpiresAfter, "expires_before", f.ExpiresBefore); err != nil {
+		return err
+	}
+	if err := validateDateRange("created_from", f.CreatedFrom, "created_to", f.CreatedTo); err != nil {
+		return err
+	}
+	return validateStatus(f.Status)
+}
+
+// Validate checks the sort and thresholds of the at-risk report
+func (f AtRiskFilters) Validate() error {
+	if f.SortBy == "" {
+		f.SortBy = "score"
+	}
+	if _, err := parseSort(f.SortBy, f.SortOrder, atRiskSortFields); err != nil {
+		return err
+	}
+	if f.StudentDays < 0 || f.LoginDays < 0 || f.ExpiryDays < 0 {
+		return fmt.Errorf("student_days, login_days and expiry_days must not be negative")
+	}
+	return nil
+}
+
+// Validate checks the sort and filters of a package list
+func (f PackageFilters) Validate() error {
+	if _, err := parseSort(f.SortBy, f.SortOrder, packageSortFields); err != nil {
+		return err
+	}
+	return validateStatus(f.Status)
+}
+
+// Validate checks the sort and filters of a teacher list
+func (f TeacherFilters) Validate() error {
+	if _, err := parseSort(f.SortBy, f.SortOrder, teacherSortFields); err != nil {
+		return err
+	}
+	return validateStatus(f.Status)
+}
+
+// Validate checks the sort and filters of a student list
+func (f StudentFilters) Validate() error {
+	if _, err := parseSort(f.SortBy, f.SortOrder, studentSortFields); err != nil {
+		return err
+	}
+	if err := validateOption("gender", f.Gender, genders); err != nil {
+		return err
+	}
+	return validateStatus(f.Status)
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"backend/internal/models"
+)
+
+func TestParseSort(t *testing.T) {
+	valid := map[string]bool{"created_on": true, "name": true, "status": true}
+	tests := []struct {
+		name      string
+		sortBy    string
+		sortOrder string
+		want      listSort
+		wantParam string // the SelectionError's param, for rejected sorts
+		wantErr   bool
+	}{
+		{"default", "", "", listSort{{"created_on", true}}, "", false},
+		{"one column", "name", "asc", listSort{{"name", false}}, "", false},
+		{"descending by default", "name", "", listSort{{"name", true}}, "", false},
+		{"direction in capitals", "name", "ASC", listSort{{"name", false}}, "", false},
+		{"one direction for every column", "status,name", "asc", listSort{{"status", false}, {"name", false}}, "", false},
+		{"one direction per column", "status, name", "desc,asc", listSort{{"status", true}, {"name", false}}, "", false},
+		{"unknown column", "salaryy", "", nil, "sort_by", true},
+		{"unknown column after a valid one", "name,salaryy", "", nil, "sort_by", true},
+		{"unknown direction", "name", "up", nil, "sort_order", true},
+		{"too few directions", "status,name,created_on", "asc,desc", nil, "", true},
+		{"too many directions", "name", "asc,desc", nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSort(tt.sortBy, tt.sortOrder, valid)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSort() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseSort() = %v, want %v", got, tt.want)
+			}
+			var selectionErr *SelectionError
+			if errors.As(err, &selectionErr) != (tt.wantParam != "") {
+				t.Fatalf("error = %v, want a SelectionError only for %q", err, tt.wantParam)
+			}
+			if selectionErr != nil && (selectionErr.Param != tt.wantParam || len(selectionErr.Valid) == 0) {
+				t.Errorf("SelectionError = %+v, want param %s with the valid options", selectionErr, tt.wantParam)
+			}
+		})
+	}
+}
+
+func TestResolveSortFallsBack(t *testing.T) {
+	got := resolveSort("salaryy", "sideways", map[string]bool{"name": true})
+	if !slices.Equal(got, defaultSort) {
+		t.Errorf("resolveSort() = %v, want %v", got, defaultSort)
+	}
+}
+
+func TestFiltersValidate(t *testing.T) {
+	status := func(s models.Status) *models.Status { return &s }
+	tests := []struct {
+		name    string
+		filters interface{ Validate() error }
+		wantErr bool
+	}{
+		{"users by role and status", UserFilters{Role: "teacher", Status: status(1), SortBy: "email"}, false},
+		{"users by unknown role", UserFilters{Role: "owner"}, true},
+		{"users by unknown status", UserFilters{Status: status(2)}, true},
+		{"users by unknown column", UserFilters{SortBy: "password"}, true},
+		{"businesses by date range", BusinessFilters{CreatedFrom: "2025-01-01", CreatedTo: "2025-01-31", SortBy: "package_expires_at"}, false},
+		{"businesses by backwards range", BusinessFilters{CreatedFrom: "2025-02-01", CreatedTo: "2025-01-31"}, true},
+		{"businesses by malformed date", BusinessFilters{ExpiresAfter: "01/02/2025"}, true},
+		{"businesses by unknown column", BusinessFilters{SortBy: "owner"}, true},
+		{"at-risk by default", AtRiskFilters{}, false},
+		{"at-risk by negative threshold", AtRiskFilters{LoginDays: -1}, true},
+		{"packages by price", PackageFilters{SortBy: "price", SortOrder: "asc", Status: status(0)}, false},
+		{"packages by unknown direction", PackageFilters{SortBy: "price", SortOrder: "cheapest"}, true},
+		{"teachers by salary", TeacherFilters{SortBy: "salary"}, false},
+		{"teachers by unknown status", TeacherFilters{Status: status(-1)}, true},
+		{"students by gender", StudentFilters{Gender: "female", SortBy: "grade,name"}, false},
+		{"students by unknown gender", StudentFilters{Gender: "f"}, true},
+		{"students by unknown column", StudentFilters{SortBy: "salary"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.filters.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
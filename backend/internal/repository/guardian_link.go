@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GuardianLinkRepository interface {
+	Create(ctx context.Context, link *models.GuardianLink) error
+	GetByID(ctx context.Context, id uint) (*models.GuardianLink, error)
+	GetByTokenID(ctx context.Context, tokenID string) (*models.GuardianLink, error)
+	GetByStudentID(ctx context.Context, studentID uint) ([]models.GuardianLink, error)
+	Revoke(ctx context.Context, id uint, at time.Time) error
+}
+
+type guardianLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewGuardianLinkRepository(db *gorm.DB) GuardianLinkRepository {
+	return &guardianLinkRepository{
+		db: db,
+	}
+}
+
+func (r *guardianLinkRepository) Create(ctx context.Context, link *models.GuardianLink) error {
+	if link == nil {
+		return fmt.Errorf("guardian link cannot be nil")
+	}
+	return r.db.WithContext(ctx).Create(link).Error
+}
+
+func (r *guardianLinkRepository) GetByID(ctx context.Context, id uint) (*models.GuardianLink, error) {
+	var link models.GuardianLink
+	err := r.db.WithContext(ctx).First(&link, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *guardianLinkRepository) GetByTokenID(ctx context.Context, tokenID string) (*models.GuardianLink, error) {
+	var link models.GuardianLink
+	err := r.db.WithContext(ctx).Where("token_id = ?", tokenID).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *guardianLinkRepository) GetByStudentID(ctx context.Context, studentID uint) ([]models.GuardianLink, error) {
+	var links []models.GuardianLink
+	err := r.db.WithContext(ctx).Where("student_id = ?", studentID).Order("created_on DESC, id DESC").Find(&links).Error
+	return links, err
+}
+
+func (r *guardianLinkRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.GuardianLink{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
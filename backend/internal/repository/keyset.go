@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidCursor is returned for cursors that can't be decoded or were issued for a
+// different sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PageInfo is the pagination metadata of a list. Total is nil when counting was skipped,
+// which cursor mode always does for performance. Page and Limit are the ones the query
+// used, after the service's defaults.
+type PageInfo struct {
+	Total          *int64 `json:"total"`
+	TotalEstimated bool   `json:"total_estimated,omitempty"`
+	Page           int    `json:"page,omitempty"` // 0 when paging by cursor
+	Limit          int    `json:"limit"`
+	NextCursor     string `json:"next_cursor,omitempty"` // empty on the last page
+}
+
+// sortKey is one column of a list sort
+type sortKey struct {
+	Column string
+	Desc   bool
+}
+
+// listSort is the resolved sort_by/sort_order of a list query. Rows are always ordered by
+// id as well, so pages are stable and a cursor pins an exact position. Cursors need a
+// sort on a single column; sorts on several are paged by page number only.
+type listSort []sortKey
+
+// defaultSort lists newest first
+var defaultSort = listSort{{Column: "created_on", Desc: true}}
+
+// sortOrders are the values sort_order accepts
+var sortOrders = []string{"asc", "desc"}
+
+// parseSort validates sort_by, a comma-separated list of columns such as "status,name",
+// and sort_order, either one direction for every column or one per column. Unknown
+// columns and directions are reported as a SelectionError listing the valid ones.
+func parseSort(sortBy, sortOrder string, validColumns map[string]bool) (listSort, error) {
+	orders := splitList(strings.ToLower(sortOrder))
+	for _, order := range orders {
+		if !containsString(sortOrders, order) {
+			return nil, &SelectionError{Param: "sort_order", Unknown: order, Valid: sortOrders}
+		}
+	}
+
+	columns := splitList(sortBy)
+	if len(columns) == 0 {
+		columns = []string{defaultSort[0].Column}
+	}
+	if len(orders) > 1 && len(orders) != len(columns) {
+		return nil, fmt.Errorf("sort_order has %d directions for %d sort_by columns; send one, or one per column", len(orders), len(columns))
+	}
+
+	keys := make(listSort, 0, len(columns))
+	for i, column := range columns {
+		if !validColumns[column] {
+			return nil, &SelectionError{Param: "sort_by", Unknown: column, Valid: sortColumnNames(validColumns)}
+		}
+		order := "desc"
+		if len(orders) == 1 {
+			order = orders[0]
+		} else if len(orders) > 1 {
+			order = orders[i]
+		}
+		keys = append(keys, sortKey{Column: column, Desc: order == "desc"})
+	}
+	return keys, nil
+}
+
+// resolveSort is parseSort for filters that were already validated, falling back to
+// newest first
+func resolveSort(sortBy, sortOrder string, validColumns map[string]bool) listSort {
+	keys, err := parseSort(sortBy, sortOrder, validColumns)
+	if err != nil {
+		return defaultSort
+	}
+	return keys
+}
+
+func sortColumnNames(validColumns map[string]bool) []string {
+	names := make([]string, 0, len(validColumns))
+	for name := range validColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// orderBy is the ORDER BY clause of the sort, ending with id in the last column's direction
+func (s listSort) orderBy() string {
+	parts := make([]string, 0, len(s)+1)
+	for _, key := range s {
+		parts = append(parts, key.Column+" "+key.direction())
+	}
+	return strings.Join(append(parts, "id "+s[len(s)-1].direction()), ", ")
+}
+
+// cursorKey returns the column cursors are built on, which only single-column sorts have
+func (s listSort) cursorKey() (sortKey, bool) {
+	if len(s) != 1 {
+		return sortKey{}, false
+	}
+	return s[0], true
+}
+
+func (k sortKey) direction() string {
+	if k.Desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// cursor is the decoded form of the opaque ?cursor= parameter: the sort key and id of the
+// last row of the previous page
+type cursor struct {
+	Column string          `json:"c"`
+	Desc   bool            `json:"d"`
+	Value  json.RawMessage `json:"v"`
+	ID     uint            `json:"i"`
+}
+
+func decodeCursor(encoded string, sort sortKey) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Column != sort.Column || c.Desc != sort.Desc {
+		return nil, fmt.Errorf("%w: it was issued for a different sort order", ErrInvalidCursor)
+	}
+	return &c, nil
+}
+
+// paginate orders query by sort and then id, and limits it either to the rows after
+// encodedCursor (keyset pagination) or, without a cursor, to the given page (offset
+// pagination). Keyset pagination doesn't get slower on later pages, since Postgres seeks
+// straight to the cursor instead of scanning the skipped rows.
+func paginate(query *gorm.DB, sort listSort, page, limit int, encodedCursor string) (*gorm.DB, error) {
+	query = query.Order(sort.orderBy())
+
+	if encodedCursor != "" {
+		key, ok := sort.cursorKey()
+		if !ok {
+			return nil, fmt.Errorf("%w: cursors need a single sort_by column", ErrInvalidCursor)
+		}
+		c, err := decodeCursor(encodedCursor, key)
+		if err != nil {
+			return nil, err
+		}
+		condition, args := keysetCondition(key, c)
+		query = query.Where(condition, args...)
+		if limit > 0 {
+			query = query.Limit(limit)
+		}
+		return query, nil
+	}
+
+	if limit > 0 {
+		offset := 0
+		if page > 1 {
+			offset = (page - 1) * limit
+		}
+		query = query.Offset(offset).Limit(limit)
+	}
+	return query, nil
+}
+
+// keysetCondition selects the rows after c. Postgres puts NULLs last when ascending and
+// first when descending, which the conditions for NULL sort keys mirror.
+func keysetCondition(sort sortKey, c *cursor) (string, []interface{}) {
+	column := sort.Column
+	if string(c.Value) == "null" {
+		if sort.Desc {
+			return fmt.Sprintf("((%s IS NULL AND id < ?) OR %s IS NOT NULL)", column, column), []interface{}{c.ID}
+		}
+		return fmt.Sprintf("(%s IS NULL AND id > ?)", column), []interface{}{c.ID}
+	}
+
+	value := cursorValue(c.Value)
+	if sort.Desc {
+		return fmt.Sprintf("(%s, id) < (?, ?)", column), []interface{}{value, c.ID}
+	}
+	return fmt.Sprintf("((%s, id) > (?, ?) OR %s IS NULL)", column, column), []interface{}{value, c.ID}
+}
+
+// cursorValue turns a JSON sort key back into a query argument. Strings and numbers are
+// both passed as text and left for Postgres to convert to the column's type.
+func cursorValue(raw json.RawMessage) interface{} {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// newPageInfo builds the pagination metadata for rows, a slice of models, fetched with the
+// given sort, page and limit, or after encodedCursor. A full page sorted on a single
+// column gets a next cursor, which clients can also use to switch from page/limit to
+// cursor mode.
+func newPageInfo(total int64, rows interface{}, sort listSort, page, limit int, encodedCursor string, mode totalMode) PageInfo {
+	info := PageInfo{Limit: limit}
+	if encodedCursor == "" {
+		info.Page = page
+	}
+	if !mode.Skip {
+		info.Total = &total
+		info.TotalEstimated = mode.Estimate
+	}
+
+	key, ok := sort.cursorKey()
+	v := reflect.ValueOf(rows)
+	if !ok || limit <= 0 || v.Len() < limit {
+		return info
+	}
+
+	last := reflect.Indirect(v.Index(v.Len() - 1))
+	value, ok := columnValue(last, key.Column)
+	if !ok {
+		return info
+	}
+	idValue, ok := columnValue(last, "id")
+	if !ok {
+		return info
+	}
+	id, ok := idValue.(uint)
+	if !ok {
+		return info
+	}
+
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		return info
+	}
+	raw, err := json.Marshal(cursor{Column: key.Column, Desc: key.Desc, Value: encodedValue, ID: id})
+	if err != nil {
+		return info
+	}
+	info.NextCursor = base64.RawURLEncoding.EncodeToString(raw)
+	return info
+}
+
+// columnValue reads the field of a model struct that maps to column, following the same
+// column naming as GORM
+func columnValue(model reflect.Value, column string) (interface{}, bool) {
+	naming := schema.NamingStrategy{}
+	t := model.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := naming.ColumnName("", field.Name)
+		for _, setting := range strings.Split(field.Tag.Get("gorm"), ";") {
+			if after, ok := strings.CutPrefix(setting, "column:"); ok {
+				name = after
+			}
+		}
+		if name != column {
+			continue
+		}
+
+		value := model.Field(i)
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return nil, true
+			}
+			value = value.Elem()
+		}
+		return value.Interface(), true
+	}
+	return nil, false
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type MaintenanceRepository interface {
+	Get(ctx context.Context) (*models.MaintenanceState, error)
+	Save(ctx context.Context, state *models.MaintenanceState) error
+}
+
+type maintenanceRepository struct {
+	db *gorm.DB
+}
+
+func NewMaintenanceRepository(db *gorm.DB) MaintenanceRepository {
+	return &maintenanceRepository{
+		db: db,
+	}
+}
+
+// Get returns the maintenance state, which is off until it has been saved once
+func (r *maintenanceRepository) Get(ctx context.Context) (*models.MaintenanceState, error) {
+	var state models.MaintenanceState
+	err := r.db.WithContext(ctx).First(&state, models.MaintenanceStateID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.MaintenanceState{ID: models.MaintenanceStateID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *maintenanceRepository) Save(ctx context.Context, state *models.MaintenanceState) error {
+	state.ID = models.MaintenanceStateID
+	return r.db.WithContext(ctx).Save(state).Error
+}
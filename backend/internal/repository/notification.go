@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository interface {
+	CreateBatch(ctx context.Context, notifications []models.Notification) error
+	GetByUser(ctx context.Context, userID uint, filters NotificationFilters) ([]models.Notification, int64, error)
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+	MarkRead(ctx context.Context, userID, notificationID uint, at time.Time) (bool, error)
+	MarkAllRead(ctx context.Context, userID uint, at time.Time) (int64, error)
+}
+
+type NotificationFilters struct {
+	Unread bool   `form:"unread" json:"unread"` // only notifications not read yet
+	Type   string `form:"type" json:"type"`
+	Page   int    `form:"page" json:"page"`
+	Limit  int    `form:"limit" json:"limit"`
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+// notificationDedup skips notifications whose user was already notified with the same key
+var notificationDedup = clause.OnConflict{
+	Columns:     []clause.Column{{Name: "user_id"}, {Name: "dedup_key"}},
+	TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "dedup_key <> ''"}}},
+	DoNothing:   true,
+}
+
+func (r *notificationRepository) CreateBatch(ctx context.Context, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(notificationDedup).Create(&notifications).Error
+}
+
+// GetByUser returns a user's notifications, newest first
+func (r *notificationRepository) GetByUser(ctx context.Context, userID uint, filters NotificationFilters) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Notification{}).Where("user_id = ?", userID)
+	if filters.Unread {
+		query = query.Where("read_at IS NULL")
+	}
+	if filters.Type != "" {
+		query = query.Where("type = ?", filters.Type)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Order("created_on DESC, id DESC")
+
+	// Apply pagination
+	if filters.Limit > 0 {
+		offset := 0
+		if filters.Page > 1 {
+			offset = (filters.Page - 1) * filters.Limit
+		}
+		query = query.Offset(offset).Limit(filters.Limit)
+	}
+
+	err := query.Find(&notifications).Error
+	return notifications, total, err
+}
+
+// CountUnread is served by the partial idx_notification_unread index, so clients can poll it
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks one of the user's notifications read, reporting false when the user has
+// no such notification. Marking a read notification again keeps its first read time.
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, notificationID uint, at time.Time) (bool, error) {
+	var notification models.Notification
+	err := r.db.WithContext(ctx).Select("id", "read_at").
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		First(&notification).Error
+	if err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if notification.ReadAt != nil {
+		return true, nil
+	}
+
+	err = r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("id = ? AND read_at IS NULL", notificationID).
+		Update("read_at", at).Error
+	return err == nil, err
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uint, at time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at)
+	return result.RowsAffected, result.Error
+}
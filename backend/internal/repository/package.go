@@ -2,7 +2,7 @@ package repository
 
 import (
 	"backend/internal/models"
-	"backend/pkg/database"
+	"context"
 	"fmt"
 
 	"gorm.io/gorm"
@@ -10,103 +10,133 @@ import (
 
 type PackageRepository interface {
 	// Basic CRUD operations
-	Create(pkg *models.Package) error
-	GetByID(id uint) (*models.Package, error)
-	GetByName(name string) (*models.Package, error)
-	GetAll(filters PackageFilters) ([]models.Package, int64, error)
-	Update(pkg *models.Package) error
-	Delete(id uint) error
+	Create(ctx context.Context, pkg *models.Package) error
+	GetByID(ctx context.Context, id uint) (*models.Package, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]models.Package, error)
+	GetByName(ctx context.Context, name string) (*models.Package, error)
+	GetAll(ctx context.Context, filters PackageFilters) ([]models.Package, int64, error)
+	Update(ctx context.Context, pkg *models.Package) error
+	UpdateVersioned(ctx context.Context, pkg *models.Package, expectedVersion uint) error
+	Delete(ctx context.Context, id uint) error
 
 	// Status operations
-	GetActivePackages() ([]models.Package, error)
-	GetInactivePackages() ([]models.Package, error)
-	UpdatePackageStatus(packageID uint, status int) error
+	GetActivePackages(ctx context.Context) ([]models.Package, error)
+	GetInactivePackages(ctx context.Context) ([]models.Package, error)
+	UpdatePackageStatus(ctx context.Context, packageID uint, status models.Status) error
 
 	// Validation and utility
-	PackageNameExists(name string, excludePackageID ...uint) (bool, error)
-	GetPackagesCount() (int64, error)
+	PackageNameExists(ctx context.Context, name string, excludePackageID ...uint) (bool, error)
+	GetPackagesCount(ctx context.Context) (int64, error)
 
 	// Price and period operations
-	GetPackagesByPriceRange(minPrice, maxPrice float64) ([]models.Package, error)
-	GetPackagesByValidationPeriod(minDays, maxDays int) ([]models.Package, error)
+	GetPackagesByPriceRange(ctx context.Context, minPrice, maxPrice float64) ([]models.Package, error)
+	GetPackagesByValidationPeriod(ctx context.Context, minDays, maxDays int) ([]models.Package, error)
+	// CountActiveAssignmentsAtOtherPrice counts the active businesses on the package that
+	// were assigned it at a price other than price
+	CountActiveAssignmentsAtOtherPrice(ctx context.Context, packageID uint, price float64) (int64, error)
 
 	// Bulk operations
-	BulkUpdateStatus(packageIDs []uint, status int) error
-	BulkDelete(packageIDs []uint) error
+	BulkUpdateStatus(ctx context.Context, packageIDs []uint, status models.Status) error
+	BulkDelete(ctx context.Context, packageIDs []uint) error
 
 	// Advanced queries
-	SearchPackages(searchTerm string, limit int) ([]models.Package, error)
-	GetRecentPackages(limit int) ([]models.Package, error)
-	GetPackagesByDateRange(startDate, endDate string) ([]models.Package, error)
+	SearchPackages(ctx context.Context, searchTerm string, limit int) ([]models.Package, error)
+	GetRecentPackages(ctx context.Context, limit int) ([]models.Package, error)
+	GetPackagesByDateRange(ctx context.Context, startDate, endDate string) ([]models.Package, error)
 
 	// Statistics
-	GetPackageStats() (map[string]interface{}, error)
-	GetPriceStatistics() (map[string]float64, error)
+	GetPackageStats(ctx context.Context) (map[string]interface{}, error)
+	GetPriceStatistics(ctx context.Context) (map[string]float64, error)
 }
 
 type PackageFilters struct {
-	Status    *int    `form:"status" json:"status"`
-	MinPrice  float64 `form:"min_price" json:"min_price"`
-	MaxPrice  float64 `form:"max_price" json:"max_price"`
-	MinPeriod int     `form:"min_period" json:"min_period"`
-	MaxPeriod int     `form:"max_period" json:"max_period"`
-	Search    string  `form:"search" json:"search"`
-	Page      int     `form:"page" json:"page"`
-	Limit     int     `form:"limit" json:"limit"`
-	SortBy    string  `form:"sort_by" json:"sort_by"`       // created_on, name, price, validation_period
-	SortOrder string  `form:"sort_order" json:"sort_order"` // asc, desc
+	Status    *models.Status `form:"status" json:"status"`
+	MinPrice  float64        `form:"min_price" json:"min_price"`
+	MaxPrice  float64        `form:"max_price" json:"max_price"`
+	MinPeriod int            `form:"min_period" json:"min_period"`
+	MaxPeriod int            `form:"max_period" json:"max_period"`
+	Search    string         `form:"search" json:"search"`
+	Page      int            `form:"page" json:"page"`
+	Limit     int            `form:"limit" json:"limit"`
+	SortBy    string         `form:"sort_by" json:"sort_by"`       // comma-separated columns, e.g. status,name
+	SortOrder string         `form:"sort_order" json:"sort_order"` // asc or desc, for all columns or one per column
+}
+
+// packageSortFields are the columns packages can be sorted by
+var packageSortFields = map[string]bool{
+	"created_on":        true,
+	"updated_on":        true,
+	"name":              true,
+	"price":             true,
+	"validation_period": true,
+	"status":            true,
+}
+
+// PageInfo builds the pagination metadata for packages listed with these filters
+func (f PackageFilters) PageInfo(total int64) PageInfo {
+	return PageInfo{Total: &total, Page: f.Page, Limit: f.Limit}
 }
 
 type packageRepository struct {
 	db *gorm.DB
 }
 
-func NewPackageRepository() PackageRepository {
+func NewPackageRepository(db *gorm.DB) PackageRepository {
 	return &packageRepository{
-		db: database.DB,
+		db: db,
 	}
 }
 
 // Basic CRUD operations
 
-func (r *packageRepository) Create(pkg *models.Package) error {
+func (r *packageRepository) Create(ctx context.Context, pkg *models.Package) error {
 	if pkg == nil {
 		return fmt.Errorf("package cannot be nil")
 	}
-	return r.db.Create(pkg).Error
+	return r.db.WithContext(ctx).Create(pkg).Error
 }
 
-func (r *packageRepository) GetByID(id uint) (*models.Package, error) {
+func (r *packageRepository) GetByID(ctx context.Context, id uint) (*models.Package, error) {
 	if id == 0 {
 		return nil, fmt.Errorf("invalid package ID")
 	}
 
 	var pkg models.Package
-	err := r.db.First(&pkg, id).Error
+	err := r.db.WithContext(ctx).First(&pkg, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &pkg, nil
 }
 
-func (r *packageRepository) GetByName(name string) (*models.Package, error) {
+func (r *packageRepository) GetByIDs(ctx context.Context, ids []uint) ([]models.Package, error) {
+	if len(ids) == 0 {
+		return []models.Package{}, nil
+	}
+
+	var packages []models.Package
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&packages).Error
+	return packages, err
+}
+
+func (r *packageRepository) GetByName(ctx context.Context, name string) (*models.Package, error) {
 	if name == "" {
 		return nil, fmt.Errorf("package name cannot be empty")
 	}
 
 	var pkg models.Package
-	err := r.db.Where("name = ?", name).First(&pkg).Error
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&pkg).Error
 	if err != nil {
 		return nil, err
 	}
 	return &pkg, nil
 }
 
-func (r *packageRepository) GetAll(filters PackageFilters) ([]models.Package, int64, error) {
+func (r *packageRepository) GetAll(ctx context.Context, filters PackageFilters) ([]models.Package, int64, error) {
 	var packages []models.Package
 	var total int64
 
-	query := r.db.Model(&models.Package{})
+	query := r.db.WithContext(ctx).Model(&models.Package{})
 
 	// Apply filters
 	if filters.Status != nil {
@@ -140,25 +170,7 @@ func (r *packageRepository) GetAll(filters PackageFilters) ([]models.Package, in
 	}
 
 	// Apply sorting
-	orderBy := "created_on DESC" // default
-	if filters.SortBy != "" {
-		validSortFields := map[string]bool{
-			"created_on":        true,
-			"updated_on":        true,
-			"name":              true,
-			"price":             true,
-			"validation_period": true,
-			"status":            true,
-		}
-		if validSortFields[filters.SortBy] {
-			sortOrder := "DESC"
-			if filters.SortOrder == "asc" {
-				sortOrder = "ASC"
-			}
-			orderBy = fmt.Sprintf("%s %s", filters.SortBy, sortOrder)
-		}
-	}
-	query = query.Order(orderBy)
+	query = query.Order(resolveSort(filters.SortBy, filters.SortOrder, packageSortFields).orderBy())
 
 	// Apply pagination
 	if filters.Limit > 0 {
@@ -173,57 +185,70 @@ func (r *packageRepository) GetAll(filters PackageFilters) ([]models.Package, in
 	return packages, total, err
 }
 
-func (r *packageRepository) Update(pkg *models.Package) error {
+func (r *packageRepository) Update(ctx context.Context, pkg *models.Package) error {
 	if pkg == nil {
 		return fmt.Errorf("package cannot be nil")
 	}
 	if pkg.ID == 0 {
 		return fmt.Errorf("package ID cannot be zero")
 	}
-	return r.db.Save(pkg).Error
+	pkg.Version++
+	return r.db.WithContext(ctx).Save(pkg).Error
 }
 
-func (r *packageRepository) Delete(id uint) error {
+// UpdateVersioned saves the package only if it is still at expectedVersion, returning
+// ErrVersionConflict otherwise
+func (r *packageRepository) UpdateVersioned(ctx context.Context, pkg *models.Package, expectedVersion uint) error {
+	if pkg == nil {
+		return fmt.Errorf("package cannot be nil")
+	}
+	if pkg.ID == 0 {
+		return fmt.Errorf("package ID cannot be zero")
+	}
+	return saveVersioned(r.db.WithContext(ctx), pkg, &pkg.Version, expectedVersion)
+}
+
+func (r *packageRepository) Delete(ctx context.Context, id uint) error {
 	if id == 0 {
 		return fmt.Errorf("invalid package ID")
 	}
-	return r.db.Delete(&models.Package{}, id).Error
+	return r.db.WithContext(ctx).Delete(&models.Package{}, id).Error
 }
 
 // Status operations
 
-func (r *packageRepository) GetActivePackages() ([]models.Package, error) {
+func (r *packageRepository) GetActivePackages(ctx context.Context) ([]models.Package, error) {
 	var packages []models.Package
-	err := r.db.Where("status = 1").Order("created_on DESC").Find(&packages).Error
+	err := r.db.WithContext(ctx).Where("status = 1").Order("created_on DESC").Find(&packages).Error
 	return packages, err
 }
 
-func (r *packageRepository) GetInactivePackages() ([]models.Package, error) {
+func (r *packageRepository) GetInactivePackages(ctx context.Context) ([]models.Package, error) {
 	var packages []models.Package
-	err := r.db.Where("status = 0").Order("created_on DESC").Find(&packages).Error
+	err := r.db.WithContext(ctx).Where("status = 0").Order("created_on DESC").Find(&packages).Error
 	return packages, err
 }
 
-func (r *packageRepository) UpdatePackageStatus(packageID uint, status int) error {
+func (r *packageRepository) UpdatePackageStatus(ctx context.Context, packageID uint, status models.Status) error {
 	if packageID == 0 {
 		return fmt.Errorf("invalid package ID")
 	}
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return fmt.Errorf("invalid status value")
 	}
 
-	return r.db.Model(&models.Package{}).Where("id = ?", packageID).Update("status", status).Error
+	return r.db.WithContext(ctx).Model(&models.Package{}).Where("id = ?", packageID).Update("status", status).Error
 }
 
 // Validation and utility
 
-func (r *packageRepository) PackageNameExists(name string, excludePackageID ...uint) (bool, error) {
+func (r *packageRepository) PackageNameExists(ctx context.Context, name string, excludePackageID ...uint) (bool, error) {
 	if name == "" {
 		return false, fmt.Errorf("package name cannot be empty")
 	}
 
 	var count int64
-	query := r.db.Model(&models.Package{}).Where("name = ?", name)
+	query := r.db.WithContext(ctx).Model(&models.Package{}).Where("name = ?", name)
 
 	if len(excludePackageID) > 0 && excludePackageID[0] > 0 {
 		query = query.Where("id != ?", excludePackageID[0])
@@ -233,17 +258,17 @@ func (r *packageRepository) PackageNameExists(name string, excludePackageID ...u
 	return count > 0, err
 }
 
-func (r *packageRepository) GetPackagesCount() (int64, error) {
+func (r *packageRepository) GetPackagesCount(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.Package{}).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&models.Package{}).Count(&count).Error
 	return count, err
 }
 
 // Price and period operations
 
-func (r *packageRepository) GetPackagesByPriceRange(minPrice, maxPrice float64) ([]models.Package, error) {
+func (r *packageRepository) GetPackagesByPriceRange(ctx context.Context, minPrice, maxPrice float64) ([]models.Package, error) {
 	var packages []models.Package
-	query := r.db.Where("status = 1")
+	query := r.db.WithContext(ctx).Where("status = 1")
 
 	if minPrice > 0 {
 		query = query.Where("price >= ?", minPrice)
@@ -256,9 +281,9 @@ func (r *packageRepository) GetPackagesByPriceRange(minPrice, maxPrice float64)
 	return packages, err
 }
 
-func (r *packageRepository) GetPackagesByValidationPeriod(minDays, maxDays int) ([]models.Package, error) {
+func (r *packageRepository) GetPackagesByValidationPeriod(ctx context.Context, minDays, maxDays int) ([]models.Package, error) {
 	var packages []models.Package
-	query := r.db.Where("status = 1")
+	query := r.db.WithContext(ctx).Where("status = 1")
 
 	if minDays > 0 {
 		query = query.Where("validation_period >= ?", minDays)
@@ -271,36 +296,45 @@ func (r *packageRepository) GetPackagesByValidationPeriod(minDays, maxDays int)
 	return packages, err
 }
 
+func (r *packageRepository) CountActiveAssignmentsAtOtherPrice(ctx context.Context, packageID uint, price float64) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Business{}).
+		Where("package_id = ? AND status = 1 AND package_price IS DISTINCT FROM ?", packageID, price).
+		Count(&count).Error
+	return count, err
+}
+
 // Bulk operations
 
-func (r *packageRepository) BulkUpdateStatus(packageIDs []uint, status int) error {
+func (r *packageRepository) BulkUpdateStatus(ctx context.Context, packageIDs []uint, status models.Status) error {
 	if len(packageIDs) == 0 {
 		return fmt.Errorf("no package IDs provided")
 	}
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return fmt.Errorf("invalid status value")
 	}
 
-	return r.db.Model(&models.Package{}).Where("id IN ?", packageIDs).Update("status", status).Error
+	return r.db.WithContext(ctx).Model(&models.Package{}).Where("id IN ?", packageIDs).
+		Updates(map[string]interface{}{"status": status, "version": bumpVersion}).Error
 }
 
-func (r *packageRepository) BulkDelete(packageIDs []uint) error {
+func (r *packageRepository) BulkDelete(ctx context.Context, packageIDs []uint) error {
 	if len(packageIDs) == 0 {
 		return fmt.Errorf("no package IDs provided")
 	}
 
-	return r.db.Where("id IN ?", packageIDs).Delete(&models.Package{}).Error
+	return r.db.WithContext(ctx).Where("id IN ?", packageIDs).Delete(&models.Package{}).Error
 }
 
 // Advanced queries
 
-func (r *packageRepository) SearchPackages(searchTerm string, limit int) ([]models.Package, error) {
+func (r *packageRepository) SearchPackages(ctx context.Context, searchTerm string, limit int) ([]models.Package, error) {
 	if searchTerm == "" {
 		return []models.Package{}, nil
 	}
 
 	var packages []models.Package
-	query := r.db.Where("name ILIKE ? OR description ILIKE ?", "%"+searchTerm+"%", "%"+searchTerm+"%").
+	query := r.db.WithContext(ctx).Where("name ILIKE ? OR description ILIKE ?", "%"+searchTerm+"%", "%"+searchTerm+"%").
 		Order("created_on DESC")
 
 	if limit > 0 {
@@ -311,9 +345,9 @@ func (r *packageRepository) SearchPackages(searchTerm string, limit int) ([]mode
 	return packages, err
 }
 
-func (r *packageRepository) GetRecentPackages(limit int) ([]models.Package, error) {
+func (r *packageRepository) GetRecentPackages(ctx context.Context, limit int) ([]models.Package, error) {
 	var packages []models.Package
-	query := r.db.Order("created_on DESC")
+	query := r.db.WithContext(ctx).Order("created_on DESC")
 
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -325,39 +359,39 @@ func (r *packageRepository) GetRecentPackages(limit int) ([]models.Package, erro
 	return packages, err
 }
 
-func (r *packageRepository) GetPackagesByDateRange(startDate, endDate string) ([]models.Package, error) {
+func (r *packageRepository) GetPackagesByDateRange(ctx context.Context, startDate, endDate string) ([]models.Package, error) {
 	if startDate == "" || endDate == "" {
 		return nil, fmt.Errorf("start date and end date cannot be empty")
 	}
 
 	var packages []models.Package
-	err := r.db.Where("created_on BETWEEN ? AND ?", startDate, endDate).
+	err := r.db.WithContext(ctx).Where("created_on BETWEEN ? AND ?", startDate, endDate).
 		Order("created_on DESC").Find(&packages).Error
 	return packages, err
 }
 
 // Statistics
 
-func (r *packageRepository) GetPackageStats() (map[string]interface{}, error) {
+func (r *packageRepository) GetPackageStats(ctx context.Context) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
 	// Total packages
 	var totalPackages int64
-	if err := r.db.Model(&models.Package{}).Count(&totalPackages).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Package{}).Count(&totalPackages).Error; err != nil {
 		return nil, err
 	}
 	stats["total_packages"] = totalPackages
 
 	// Active packages
 	var activePackages int64
-	if err := r.db.Model(&models.Package{}).Where("status = 1").Count(&activePackages).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Package{}).Where("status = 1").Count(&activePackages).Error; err != nil {
 		return nil, err
 	}
 	stats["active_packages"] = activePackages
 
 	// Inactive packages
 	var inactivePackages int64
-	if err := r.db.Model(&models.Package{}).Where("status = 0").Count(&inactivePackages).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Package{}).Where("status = 0").Count(&inactivePackages).Error; err != nil {
 		return nil, err
 	}
 	stats["inactive_packages"] = inactivePackages
@@ -365,7 +399,7 @@ func (r *packageRepository) GetPackageStats() (map[string]interface{}, error) {
 	return stats, nil
 }
 
-func (r *packageRepository) GetPriceStatistics() (map[string]float64, error) {
+func (r *packageRepository) GetPriceStatistics(ctx context.Context) (map[string]float64, error) {
 	stats := make(map[string]float64)
 
 	var result struct {
@@ -374,7 +408,7 @@ func (r *packageRepository) GetPriceStatistics() (map[string]float64, error) {
 		AvgPrice float64
 	}
 
-	err := r.db.Model(&models.Package{}).Where("status = 1").
+	err := r.db.WithContext(ctx).Model(&models.Package{}).Where("status = 1").
 		Select("MIN(price) as min_price, MAX(price) as max_price, AVG(price) as avg_price").
 		Scan(&result).Error
 
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PasswordTokenRepository interface {
+	Create(ctx context.Context, token *models.PasswordToken) error
+	// Consume sets the password of the user an unused, unexpired token was issued to and
+	// marks every outstanding token of that user used, returning the user ID. It returns
+	// gorm.ErrRecordNotFound when no such token exists.
+	Consume(ctx context.Context, tokenHash string, passwordHash string, at time.Time) (uint, error)
+}
+
+type passwordTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordTokenRepository(db *gorm.DB) PasswordTokenRepository {
+	return &passwordTokenRepository{
+		db: db,
+	}
+}
+
+func (r *passwordTokenRepository) Create(ctx context.Context, token *models.PasswordToken) error {
+	if token == nil {
+		return fmt.Errorf("password token cannot be nil")
+	}
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *passwordTokenRepository) Consume(ctx context.Context, tokenHash string, passwordHash string, at time.Time) (uint, error) {
+	var userID uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Claiming the token with a conditional update makes it single-use even under
+		// concurrent requests
+		var token models.PasswordToken
+		if err := tx.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, at).First(&token).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.PasswordToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", at)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Model(&models.User{}).Where("id = ?", token.UserID).Update("password", passwordHash).Error; err != nil {
+			return err
+		}
+		// Business owners have a copy of the hash on their business
+		if err := tx.Model(&models.Business{}).Where("user_id = ?", token.UserID).Update("password", passwordHash).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.PasswordToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", at).Error; err != nil {
+			return err
+		}
+
+		userID = token.UserID
+		return nil
+	})
+	return userID, err
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type PayrollRepository interface {
+	// Adjustments
+	CreateAdjustmentWithTransaction(tx *gorm.DB, adjustment *models.PayrollAdjustment) error
+	GetAdjustmentByID(ctx context.Context, id uint) (*models.PayrollAdjustment, error)
+	GetAdjustments(ctx context.Context, businessID uint, month string) ([]models.PayrollAdjustment, error)
+	DeleteAdjustmentWithTransaction(tx *gorm.DB, id uint) error
+
+	// Finalized months
+	CreateRunWithTransaction(tx *gorm.DB, run *models.PayrollRun) error
+	GetRun(ctx context.Context, businessID uint, month string) (*models.PayrollRun, error)
+	GetRunWithTransaction(tx *gorm.DB, businessID uint, month string) (*models.PayrollRun, error)
+
+	// LockBusinessWithTransaction holds the business's row until the transaction ends, so
+	// adjustments can't be changed while a month is being finalized
+	LockBusinessWithTransaction(tx *gorm.DB, businessID uint) error
+
+	// Transaction support
+	BeginTransaction(ctx context.Context) *gorm.DB
+}
+
+type payrollRepository struct {
+	db *gorm.DB
+}
+
+func NewPayrollRepository(db *gorm.DB) PayrollRepository {
+	return &payrollRepository{
+		db: db,
+	}
+}
+
+func (r *payrollRepository) CreateAdjustmentWithTransaction(tx *gorm.DB, adjustment *models.PayrollAdjustment) error {
+	if adjustment == nil {
+		return fmt.Errorf("adjustment cannot be nil")
+	}
+	return tx.Create(adjustment).Error
+}
+
+func (r *payrollRepository) GetAdjustmentByID(ctx context.Context, id uint) (*models.PayrollAdjustment, error) {
+	var adjustment models.PayrollAdjustment
+	err := r.db.WithContext(ctx).Preload("Teacher").First(&adjustment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &adjustment, nil
+}
+
+func (r *payrollRepository) GetAdjustments(ctx context.Context, businessID uint, month string) ([]models.PayrollAdjustment, error) {
+	var adjustments []models.PayrollAdjustment
+	err := r.db.WithContext(ctx).
+		Preload("Teacher", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("business_id = ? AND month = ?", businessID, month).
+		Order("created_on ASC, id ASC").
+		Find(&adjustments).Error
+	return adjustments, err
+}
+
+func (r *payrollRepository) DeleteAdjustmentWithTransaction(tx *gorm.DB, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid adjustment ID")
+	}
+	return tx.Delete(&models.PayrollAdjustment{}, id).Error
+}
+
+func (r *payrollRepository) CreateRunWithTransaction(tx *gorm.DB, run *models.PayrollRun) error {
+	if run == nil {
+		return fmt.Errorf("payroll run cannot be nil")
+	}
+	return tx.Create(run).Error
+}
+
+func (r *payrollRepository) GetRun(ctx context.Context, businessID uint, month string) (*models.PayrollRun, error) {
+	return r.GetRunWithTransaction(r.db.WithContext(ctx), businessID, month)
+}
+
+func (r *payrollRepository) GetRunWithTransaction(tx *gorm.DB, businessID uint, month string) (*models.PayrollRun, error) {
+	var run models.PayrollRun
+	err := tx.Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("teacher_name ASC, teacher_id ASC") }).
+		Where("business_id = ? AND month = ?", businessID, month).
+		First(&run).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *payrollRepository) LockBusinessWithTransaction(tx *gorm.DB, businessID uint) error {
+	return tx.Exec("SELECT id FROM business WHERE id = ? FOR UPDATE", businessID).Error
+}
+
+func (r *payrollRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
+}
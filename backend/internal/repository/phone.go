@@ -0,0 +1,20 @@
+package repository
+
+import "backend/pkg/utils"
+
+// phoneMatch compares a phone column with a search term normalized the way phones are
+// stored. NULLIF keeps terms that aren't phone numbers from matching empty phones.
+const phoneMatch = "phone = NULLIF(?, '')"
+
+// teacherPhoneMatch is phoneMatch for teachers, whose phone is on their user
+const teacherPhoneMatch = "user_id IN (SELECT id FROM users WHERE " + phoneMatch + ")"
+
+// searchPhone returns a search term normalized the way phones are stored, so
+// "098765 43210" finds "+919876543210", or "" when the term isn't a phone number
+func searchPhone(term string) string {
+	phone, err := utils.NormalizePhone(term)
+	if err != nil {
+		return ""
+	}
+	return phone
+}
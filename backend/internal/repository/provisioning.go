@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProvisioningRepository writes the defaults a new business starts with, and keeps track
+// of the sample data among them
+type ProvisioningRepository interface {
+	// HasRowsWithTransaction reports whether the business already has any rows of model
+	HasRowsWithTransaction(tx *gorm.DB, model interface{}, businessID uint) (bool, error)
+	// CreateWithTransaction inserts a slice of records, filling in their IDs, and leaves
+	// their associations alone
+	CreateWithTransaction(tx *gorm.DB, records interface{}) error
+	// TakeSampleRecordsWithTransaction deletes and returns the sample records of a business,
+	// so concurrent purges never both act on the same rows
+	TakeSampleRecordsWithTransaction(tx *gorm.DB, businessID uint) ([]models.SampleRecord, error)
+
+	// Transaction support
+	BeginTransaction(ctx context.Context) *gorm.DB
+}
+
+type provisioningRepository struct {
+	db *gorm.DB
+}
+
+func NewProvisioningRepository(db *gorm.DB) ProvisioningRepository {
+	return &provisioningRepository{
+		db: db,
+	}
+}
+
+func (r *provisioningRepository) HasRowsWithTransaction(tx *gorm.DB, model interface{}, businessID uint) (bool, error) {
+	var count int64
+	err := tx.Model(model).Where("business_id = ?", businessID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *provisioningRepository) CreateWithTransaction(tx *gorm.DB, records interface{}) error {
+	return tx.Omit(clause.Associations).Create(records).Error
+}
+
+func (r *provisioningRepository) TakeSampleRecordsWithTransaction(tx *gorm.DB, businessID uint) ([]models.SampleRecord, error) {
+	var records []models.SampleRecord
+	err := tx.Clauses(clause.Returning{}).Where("business_id = ?", businessID).Delete(&records).Error
+	return records, err
+}
+
+// BeginTransaction starts a new database transaction
+func (r *provisioningRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
+}
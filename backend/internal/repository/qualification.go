@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type QualificationRepository interface {
+	// Basic CRUD operations
+	Create(ctx context.Context, qualification *models.Qualification) error
+	GetByID(ctx context.Context, id uint) (*models.Qualification, error)
+	GetGlobal(ctx context.Context, includePending bool) ([]models.Qualification, error)
+	GetForBusiness(ctx context.Context, businessID uint, includePending bool) ([]models.Qualification, error)
+	GetAll(ctx context.Context) ([]models.Qualification, error)
+	Update(ctx context.Context, qualification *models.Qualification) error
+	Delete(ctx context.Context, id uint) error
+
+	// Matching
+	FindByKey(ctx context.Context, businessID uint, key string) (*models.Qualification, error)
+	KeyExists(ctx context.Context, businessID *uint, key string, excludeID ...uint) (bool, error)
+
+	// Legacy qualification strings
+	GetUnmappedTeacherQualifications(ctx context.Context) ([]LegacyQualification, error)
+	MapTeacherQualificationsWithTransaction(tx *gorm.DB, businessID uint, value string, qualificationID uint) (int64, error)
+
+	// Transaction support
+	BeginTransaction(ctx context.Context) *gorm.DB
+}
+
+// LegacyQualification is a free-text qualification some of a business's teachers have
+// that is not mapped to a taxonomy entry yet
+type LegacyQualification struct {
+	BusinessID uint
+	Value      string
+	Teachers   int64
+}
+
+type qualificationRepository struct {
+	db *gorm.DB
+}
+
+func NewQualificationRepository(db *gorm.DB) QualificationRepository {
+	return &qualificationRepository{
+		db: db,
+	}
+}
+
+func (r *qualificationRepository) Create(ctx context.Context, qualification *models.Qualification) error {
+	if qualification == nil {
+		return fmt.Errorf("qualification cannot be nil")
+	}
+	return r.db.WithContext(ctx).Create(qualification).Error
+}
+
+func (r *qualificationRepository) GetByID(ctx context.Context, id uint) (*models.Qualification, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid qualification ID")
+	}
+
+	var qualification models.Qualification
+	err := r.db.WithContext(ctx).First(&qualification, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &qualification, nil
+}
+
+// GetGlobal lists the entries offered to every business
+func (r *qualificationRepository) GetGlobal(ctx context.Context, includePending bool) ([]models.Qualification, error) {
+	query := r.db.WithContext(ctx).Where("business_id IS NULL")
+	if !includePending {
+		query = query.Where("pending = ?", false)
+	}
+
+	var qualifications []models.Qualification
+	err := query.Order("name ASC").Find(&qualifications).Error
+	return qualifications, err
+}
+
+// GetForBusiness lists the global entries and the business's own, global ones first
+func (r *qualificationRepository) GetForBusiness(ctx context.Context, businessID uint, includePending bool) ([]models.Qualification, error) {
+	if businessID == 0 {
+		return nil, fmt.Errorf("invalid business ID")
+	}
+
+	query := r.db.WithContext(ctx).Where("business_id IS NULL OR business_id = ?", businessID)
+	if !includePending {
+		query = query.Where("pending = ?", false)
+	}
+
+	var qualifications []models.Qualification
+	err := query.Order("business_id NULLS FIRST").Order("name ASC").Find(&qualifications).Error
+	return qualifications, err
+}
+
+func (r *qualificationRepository) GetAll(ctx context.Context) ([]models.Qualification, error) {
+	var qualifications []models.Qualification
+	err := r.db.WithContext(ctx).Order("id ASC").Find(&qualifications).Error
+	return qualifications, err
+}
+
+func (r *qualificationRepository) Update(ctx context.Context, qualification *models.Qualification) error {
+	if qualification == nil {
+		return fmt.Errorf("qualification cannot be nil")
+	}
+	if qualification.ID == 0 {
+		return fmt.Errorf("qualification ID cannot be zero")
+	}
+
+	return r.db.WithContext(ctx).Save(qualification).Error
+}
+
+func (r *qualificationRepository) Delete(ctx context.Context, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid qualification ID")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Teachers keep the name as their free-text qualification
+		err := tx.Model(&models.Teacher{}).Where("qualification_id = ?", id).
+			Updates(map[string]interface{}{"qualification_id": nil, "version": bumpVersion}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&models.Qualification{}, id).Error
+	})
+}
+
+// FindByKey returns the entry a business's teachers would mean by key, preferring
+// approved entries and, among those, global ones
+func (r *qualificationRepository) FindByKey(ctx context.Context, businessID uint, key string) (*models.Qualification, error) {
+	var qualification models.Qualification
+	err := r.db.WithContext(ctx).
+		Where("key = ? AND (business_id IS NULL OR business_id = ?)", key, businessID).
+		Order("pending ASC").Order("business_id NULLS FIRST").Order("id ASC").
+		First(&qualification).Error
+	if err != nil {
+		return nil, err
+	}
+	return &qualification, nil
+}
+
+// KeyExists reports whether the global list, or a business's own entries, already have key
+func (r *qualificationRepository) KeyExists(ctx context.Context, businessID *uint, key string, excludeID ...uint) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&models.Qualification{}).Where("key = ?", key)
+	if businessID == nil {
+		query = query.Where("business_id IS NULL")
+	} else {
+		query = query.Where("business_id = ?", *businessID)
+	}
+	if len(excludeID) > 0 && excludeID[0] > 0 {
+		query = query.Where("id != ?", excludeID[0])
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// GetUnmappedTeacherQualifications lists the distinct free-text qualifications of
+// teachers without a taxonomy entry, per business
+func (r *qualificationRepository) GetUnmappedTeacherQualifications(ctx context.Context) ([]LegacyQualification, error) {
+	var legacy []LegacyQualification
+	err := r.db.WithContext(ctx).Model(&models.Teacher{}).
+		Select("business_id, qualification AS value, COUNT(*) AS teachers").
+		Where("qualification_id IS NULL AND qualification IS NOT NULL AND qualification != ''").
+		Group("business_id, qualification").
+		Order("business_id ASC").Order("qualification ASC").
+		Scan(&legacy).Error
+	return legacy, err
+}
+
+// MapTeacherQualificationsWithTransaction points a business's unmapped teachers with
+// the free-text qualification value at an entry, keeping the text as it was
+func (r *qualificationRepository) MapTeacherQualificationsWithTransaction(tx *gorm.DB, businessID uint, value string, qualificationID uint) (int64, error) {
+	result := tx.Model(&models.Teacher{}).
+		Where("business_id = ? AND qualification = ? AND qualification_id IS NULL", businessID, value).
+		Updates(map[string]interface{}{"qualification_id": qualificationID, "version": bumpVersion})
+	return result.RowsAffected, result.Error
+}
+
+// BeginTransaction starts a new database transaction
+func (r *qualificationRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultRecentLimit is how many of the latest records a recent-activity query returns
+// when no limit is given
+const defaultRecentLimit = 10
+
+// latestFirst orders query newest first and limits it to the latest limit rows
+func latestFirst(query *gorm.DB, limit int) *gorm.DB {
+	if limit <= 0 {
+		limit = defaultRecentLimit
+	}
+	return query.Order("created_on DESC").Order("id DESC").Limit(limit)
+}
+
+// withBusinessName preloads only the ID and name of a profile's business
+func withBusinessName(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "name")
+}
+
+// countAdditions counts the rows of query created in the 7 and 30 days before now, in
+// a single pass over the last 30 days
+func countAdditions(query *gorm.DB, now time.Time) (*models.AdditionCounts, error) {
+	var counts models.AdditionCounts
+	err := query.
+		Select("COUNT(*) FILTER (WHERE created_on >= ?) AS last_7_days, COUNT(*) AS last_30_days", now.AddDate(0, 0, -7)).
+		Where("created_on >= ?", now.AddDate(0, 0, -30)).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
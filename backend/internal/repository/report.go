@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReportRepository interface {
+	// Subscriptions
+	GetSubscriptions(ctx context.Context, userID uint) ([]models.ReportSubscription, error)
+	UpsertSubscription(ctx context.Context, subscription *models.ReportSubscription) error
+	// GetSubscribers returns the enabled subscriptions of active users to a report, with
+	// their users loaded
+	GetSubscribers(ctx context.Context, report string) ([]models.ReportSubscription, error)
+	MarkSent(ctx context.Context, userID uint, report string, at time.Time) error
+
+	// Schedule
+	// ClaimRun records that a report ran for the slot starting at slot, reporting false if
+	// it already ran for that slot. The conditional update lets exactly one instance win.
+	ClaimRun(ctx context.Context, name string, slot time.Time) (bool, error)
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{
+		db: db,
+	}
+}
+
+func (r *reportRepository) GetSubscriptions(ctx context.Context, userID uint) ([]models.ReportSubscription, error) {
+	var subscriptions []models.ReportSubscription
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("report ASC").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// subscriptionUpsert updates the existing subscription of the same user to the same report
+var subscriptionUpsert = clause.OnConflict{
+	Columns:   []clause.Column{{Name: "user_id"}, {Name: "report"}},
+	DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_on"}),
+}
+
+func (r *reportRepository) UpsertSubscription(ctx context.Context, subscription *models.ReportSubscription) error {
+	if subscription == nil {
+		return fmt.Errorf("report subscription cannot be nil")
+	}
+	return r.db.WithContext(ctx).Clauses(subscriptionUpsert).Create(subscription).Error
+}
+
+func (r *reportRepository) GetSubscribers(ctx context.Context, report string) ([]models.ReportSubscription, error) {
+	var subscriptions []models.ReportSubscription
+	err := r.db.WithContext(ctx).
+		Joins("User").
+		Where("report_subscription.report = ? AND report_subscription.enabled AND \"User\".status = 1", report).
+		Order("report_subscription.id ASC").
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *reportRepository) MarkSent(ctx context.Context, userID uint, report string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.ReportSubscription{}).
+		Where("user_id = ? AND report = ?", userID, report).
+		Update("last_sent_at", at).Error
+}
+
+func (r *reportRepository) ClaimRun(ctx context.Context, name string, slot time.Time) (bool, error) {
+	db := r.db.WithContext(ctx)
+
+	// The first run ever has no row to update yet
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.ReportSchedule{Name: name}).Error; err != nil {
+		return false, err
+	}
+
+	result := db.Model(&models.ReportSchedule{}).
+		Where("name = ? AND last_run_at < ?", name, slot).
+		Update("last_run_at", slot)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Selection is the parsed form of a list endpoint's fields and include query parameters
+type Selection struct {
+	Fields    []string // response fields to return, every field when empty
+	Relations []string // relations to preload, by their JSON name
+}
+
+// SelectionError reports an unknown name in the fields or include parameter
+type SelectionError struct {
+	Param   string
+	Unknown string
+	Valid   []string
+}
+
+func (e *SelectionError) Error() string {
+	return fmt.Sprintf("unknown %s %q, valid options are: %s", e.Param, e.Unknown, strings.Join(e.Valid, ", "))
+}
+
+// relationPreloads maps a relation's JSON name to the preload that loads it
+type relationPreloads map[string]func(*gorm.DB) *gorm.DB
+
+func (p relationPreloads) names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseSelection validates fields against the JSON names of response and include against
+// the relations. Without include, the relations named in fields are loaded, or all of
+// them when fields is empty too, so existing clients keep getting full responses.
+func parseSelection(fields, include string, response interface{}, relations relationPreloads) (Selection, error) {
+	var selection Selection
+
+	validFields := jsonFieldNames(response)
+	for _, field := range splitList(fields) {
+		if !containsString(validFields, field) {
+			return Selection{}, &SelectionError{Param: "field", Unknown: field, Valid: validFields}
+		}
+		selection.Fields = append(selection.Fields, field)
+	}
+
+	switch {
+	case include != "":
+		for _, relation := range splitList(include) {
+			if _, ok := relations[relation]; !ok {
+				return Selection{}, &SelectionError{Param: "include", Unknown: relation, Valid: relations.names()}
+			}
+			selection.Relations = append(selection.Relations, relation)
+		}
+	case len(selection.Fields) > 0:
+		for _, field := range selection.Fields {
+			if _, ok := relations[field]; ok {
+				selection.Relations = append(selection.Relations, field)
+			}
+		}
+	default:
+		selection.Relations = relations.names()
+	}
+
+	return selection, nil
+}
+
+// HasField reports whether the response should contain field
+func (s Selection) HasField(field string) bool {
+	return len(s.Fields) == 0 || containsString(s.Fields, field)
+}
+
+// preload applies the preloads of the selected relations to query
+func (s Selection) preload(query *gorm.DB, relations relationPreloads) *gorm.DB {
+	for _, relation := range s.Relations {
+		query = relations[relation](query)
+	}
+	return query
+}
+
+// jsonFieldNames returns the JSON names of a struct's fields, in declaration order
+func jsonFieldNames(v interface{}) []string {
+	t := reflect.TypeOf(v)
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// splitList splits a comma-separated query parameter, dropping blanks
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SMSRepository interface {
+	Create(ctx context.Context, messages []models.SMSMessage) error
+	GetByID(ctx context.Context, id uint) (*models.SMSMessage, error)
+	Update(ctx context.Context, id uint, updates map[string]interface{}) error
+	// UpdateStatusByProviderID applies a delivery report, leaving messages that already
+	// reached a final status (delivered or failed) alone so late reports can't regress them
+	UpdateStatusByProviderID(ctx context.Context, providerID, status, errMsg string) error
+	GetMessages(ctx context.Context, filters SMSFilters) ([]models.SMSMessage, int64, error)
+
+	// CountRecentTo counts the messages queued or sent to a number since a time
+	CountRecentTo(ctx context.Context, to string, since time.Time) (int64, error)
+	// ExistingDedupKeys returns which of keys some earlier message already has
+	ExistingDedupKeys(ctx context.Context, keys []string) (map[string]bool, error)
+}
+
+type SMSFilters struct {
+	BusinessID *uint  `form:"business_id" json:"business_id"`
+	StudentID  *uint  `form:"student_id" json:"student_id"`
+	Kind       string `form:"kind" json:"kind" binding:"omitempty,oneof=absence fee_due"`
+	Status     string `form:"status" json:"status" binding:"omitempty,oneof=queued sent delivered failed rate_limited"`
+	Page       int    `form:"page" json:"page"`
+	Limit      int    `form:"limit" json:"limit"`
+}
+
+type smsRepository struct {
+	db *gorm.DB
+}
+
+func NewSMSRepository(db *gorm.DB) SMSRepository {
+	return &smsRepository{
+		db: db,
+	}
+}
+
+func (r *smsRepository) Create(ctx context.Context, messages []models.SMSMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&messages).Error
+}
+
+func (r *smsRepository) GetByID(ctx context.Context, id uint) (*models.SMSMessage, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid SMS message ID")
+	}
+
+	var message models.SMSMessage
+	err := r.db.WithContext(ctx).First(&message, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (r *smsRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&models.SMSMessage{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *smsRepository) UpdateStatusByProviderID(ctx context.Context, providerID, status, errMsg string) error {
+	updates := map[string]interface{}{"status": status}
+	if errMsg != "" {
+		updates["error"] = errMsg
+	}
+	return r.db.WithContext(ctx).Model(&models.SMSMessage{}).
+		Where("provider_id = ? AND status NOT IN ?", providerID, []string{models.SMSStatusDelivered, models.SMSStatusFailed}).
+		Updates(updates).Error
+}
+
+func (r *smsRepository) GetMessages(ctx context.Context, filters SMSFilters) ([]models.SMSMessage, int64, error) {
+	var messages []models.SMSMessage
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.SMSMessage{})
+	if filters.BusinessID != nil {
+		query = query.Where("business_id = ?", *filters.BusinessID)
+	}
+	if filters.StudentID != nil {
+		query = query.Where("student_id = ?", *filters.StudentID)
+	}
+	if filters.Kind != "" {
+		query = query.Where("kind = ?", filters.Kind)
+	}
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Order("created_on DESC, id DESC")
+
+	// Apply pagination
+	if filters.Limit > 0 {
+		offset := 0
+		if filters.Page > 1 {
+			offset = (filters.Page - 1) * filters.Limit
+		}
+		query = query.Offset(offset).Limit(filters.Limit)
+	}
+
+	err := query.Find(&messages).Error
+	return messages, total, err
+}
+
+func (r *smsRepository) CountRecentTo(ctx context.Context, to string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.SMSMessage{}).
+		Where("recipient = ? AND created_on >= ? AND status <> ?", to, since, models.SMSStatusRateLimited).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *smsRepository) ExistingDedupKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(keys) == 0 {
+		return existing, nil
+	}
+
+	var found []string
+	err := r.db.WithContext(ctx).Model(&models.SMSMessage{}).
+		Where("dedup_key IN ?", keys).
+		Distinct().Pluck("dedup_key", &found).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range found {
+		existing[key] = true
+	}
+	return existing, nil
+}
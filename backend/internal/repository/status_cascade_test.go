@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"testing"
+
+	"backend/internal/models"
+	"backend/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+// failUserStatusUpdates makes every change of a user's status fail for the rest of
+// the test's transaction
+func failUserStatusUpdates(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	for _, statement := range []string{
+		`CREATE FUNCTION fail_user_status() RETURNS trigger AS $$
+		BEGIN RAISE EXCEPTION 'user status update refused'; END $$ LANGUAGE plpgsql`,
+		`CREATE TRIGGER fail_user_status BEFORE UPDATE OF status ON users
+		FOR EACH ROW EXECUTE FUNCTION fail_user_status()`,
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("failed to install trigger: %v", err)
+		}
+	}
+}
+
+// statusRow is one row whose status a test checks
+type statusRow struct {
+	model interface{}
+	id    uint
+}
+
+// When the user side of a status cascade fails, the business, teacher or student keeps
+// its status along with the logins
+func TestStatusCascadeRollsBackOnUserFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		// change makes the status change in tx, the way its service does
+		change func(tx *gorm.DB, f *testutil.Fixtures) error
+		rows   func(f *testutil.Fixtures) []statusRow
+	}{
+		{
+			name: "business",
+			change: func(tx *gorm.DB, f *testutil.Fixtures) error {
+				repo := NewBusinessRepository(tx)
+				businessID := f.Businesses["Sunrise Academy"].ID
+				if err := repo.BulkUpdateStatusWithTransaction(tx, []uint{businessID}, models.StatusInactive); err != nil {
+					return err
+				}
+				_, err := repo.CascadeStatusWithTransaction(tx, businessID, models.StatusInactive)
+				return err
+			},
+			rows: func(f *testutil.Fixtures) []statusRow {
+				return []statusRow{
+					{&models.Business{}, f.Businesses["Sunrise Academy"].ID},
+					{&models.Teacher{}, f.Teachers["Asha"].ID},
+					{&models.Student{}, f.Students["Aarav"].ID},
+					{&models.User{}, f.Teachers["Asha"].UserID},
+					{&models.User{}, f.Students["Aarav"].UserID},
+				}
+			},
+		},
+		{
+			name: "teacher",
+			change: func(tx *gorm.DB, f *testutil.Fixtures) error {
+				asha := f.Teachers["Asha"]
+				if err := NewTeacherRepository(tx).UpdateTeacherStatusWithTransaction(tx, asha.ID, models.StatusInactive); err != nil {
+					return err
+				}
+				return NewUserRepository(tx).UpdateUserStatusInTransaction(tx, asha.UserID, models.StatusInactive)
+			},
+			rows: func(f *testutil.Fixtures) []statusRow {
+				return []statusRow{{&models.Teacher{}, f.Teachers["Asha"].ID}, {&models.User{}, f.Teachers["Asha"].UserID}}
+			},
+		},
+		{
+			name: "teachers in bulk",
+			change: func(tx *gorm.DB, f *testutil.Fixtures) error {
+				asha, bilal := f.Teachers["Asha"], f.Teachers["Bilal"]
+				if err := NewTeacherRepository(tx).BulkUpdateStatusWithTransaction(tx, []uint{asha.ID, bilal.ID}, models.StatusInactive); err != nil {
+					return err
+				}
+				return NewUserRepository(tx).BulkUpdateStatusInTransaction(tx, []uint{asha.UserID, bilal.UserID}, models.StatusInactive)
+			},
+			rows: func(f *testutil.Fixtures) []statusRow {
+				return []statusRow{{&models.Teacher{}, f.Teachers["Asha"].ID}, {&models.Teacher{}, f.Teachers["Bilal"].ID}}
+			},
+		},
+		{
+			name: "student",
+			change: func(tx *gorm.DB, f *testutil.Fixtures) error {
+				aarav := f.Students["Aarav"]
+				if err := NewStudentRepository(tx).UpdateStudentStatusWithTransaction(tx, aarav.ID, models.StatusInactive); err != nil {
+					return err
+				}
+				return NewUserRepository(tx).UpdateUserStatusInTransaction(tx, aarav.UserID, models.StatusInactive)
+			},
+			rows: func(f *testutil.Fixtures) []statusRow {
+				return []statusRow{{&models.Student{}, f.Students["Aarav"].ID}, {&models.User{}, f.Students["Aarav"].UserID}}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testutil.DB(t)
+			f := testutil.Seed(t, db)
+			failUserStatusUpdates(t, db)
+
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tt.change(tx, f)
+			})
+			if err == nil {
+				t.Fatal("status change succeeded, want the user update's error")
+			}
+
+			for _, row := range tt.rows(f) {
+				var status models.Status
+				if err := db.Model(row.model).Select("status").Where("id = ?", row.id).Scan(&status).Error; err != nil {
+					t.Fatalf("failed to read status: %v", err)
+				}
+				if status != models.StatusActive {
+					t.Errorf("%T %d status = %v, want it still active", row.model, row.id, status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// updateStatus sets the status of the given rows of table and records a status_history
+// entry for each row whose status actually changed
+func updateStatus(tx *gorm.DB, table, entityType string, ids []uint, status models.Status) error {
+	var changedIDs []uint
+	err := tx.Raw("UPDATE "+table+" SET status = ?, version = version + 1, updated_on = ? WHERE id IN ? AND status <> ? RETURNING id",
+		status, time.Now(), ids, status).
+		Scan(&changedIDs).Error
+	if err != nil {
+		return err
+	}
+
+	return recordStatusChanges(tx, entityType, changedIDs, status)
+}
+
+// recordStatusChanges adds a status_history entry for each of the given entities
+func recordStatusChanges(tx *gorm.DB, entityType string, ids []uint, status models.Status) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	entries := make([]models.StatusHistory, 0, len(ids))
+	for _, id := range ids {
+		entries = append(entries, models.StatusHistory{EntityType: entityType, EntityID: id, Status: status})
+	}
+	return tx.Create(&entries).Error
+}
+
+// cascadeStatus sets the status of the teachers or students (table) of a business to the
+// business's new status, recording each change in status_history as cascaded from the
+// business. Deactivating takes every active row. Reactivating takes only the rows whose
+// latest status_history entry is a deactivation cascaded from this business, so rows
+// that were already inactive, or whose status was changed since, stay as they are.
+// extraRestore further limits the rows reactivated. It returns the changed rows' IDs
+// and user IDs.
+func cascadeStatus(tx *gorm.DB, table, entityType string, businessID uint, status models.Status, extraRestore string) ([]uint, []uint, error) {
+	query := "UPDATE " + table + " SET status = ?, version = version + 1, updated_on = ? WHERE business_id = ? AND status <> ? AND deleted_at IS NULL"
+	args := []interface{}{status, time.Now(), businessID, status}
+	if status.IsActive() {
+		query += ` AND id IN (SELECT h.entity_id FROM status_history h
+			WHERE h.entity_type = ? AND h.cascade_business_id = ? AND h.status = 0
+			AND h.id = (SELECT MAX(l.id) FROM status_history l WHERE l.entity_type = h.entity_type AND l.entity_id = h.entity_id)` +
+			extraRestore + `)`
+		args = append(args, entityType, businessID)
+	}
+
+	var changed []struct {
+		ID     uint
+		UserID uint
+	}
+	if err := tx.Raw(query+" RETURNING id, user_id", args...).Scan(&changed).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(changed) == 0 {
+		return nil, nil, nil
+	}
+
+	ids := make([]uint, len(changed))
+	userIDs := make([]uint, len(changed))
+	entries := make([]models.StatusHistory, len(changed))
+	for i, row := range changed {
+		ids[i] = row.ID
+		userIDs[i] = row.UserID
+		entries[i] = models.StatusHistory{EntityType: entityType, EntityID: row.ID, Status: status, CascadeBusinessID: &businessID}
+	}
+	if err := tx.Create(&entries).Error; err != nil {
+		return nil, nil, err
+	}
+	return ids, userIDs, nil
+}
@@ -2,82 +2,177 @@ package repository
 
 import (
 	"backend/internal/models"
-	"backend/pkg/database"
+	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type StudentRepository interface {
 	// Basic CRUD operations
-	Create(student *models.Student) error
+	Create(ctx context.Context, student *models.Student) error
 	CreateWithTransaction(tx *gorm.DB, student *models.Student) error
-	GetByID(id uint) (*models.Student, error)
-	GetByUserID(userID uint) (*models.Student, error)
-	GetAll(filters StudentFilters) ([]models.Student, int64, error)
-	GetAllWithRelations(filters StudentFilters) ([]models.Student, int64, error)
-	Update(student *models.Student) error
+	GetByID(ctx context.Context, id uint) (*models.Student, error)
+	GetByUserID(ctx context.Context, userID uint) (*models.Student, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]models.Student, error)
+	GetAll(ctx context.Context, filters StudentFilters) ([]models.Student, int64, error)
+	GetAllWithRelations(ctx context.Context, filters StudentFilters) ([]models.Student, int64, error)
+	ForEachBatch(ctx context.Context, filters StudentFilters, batchSize int, fn func([]models.Student) error) error
+	Update(ctx context.Context, student *models.Student) error
 	UpdateWithTransaction(tx *gorm.DB, student *models.Student) error
-	Delete(id uint) error
+	UpdateVersionedWithTransaction(tx *gorm.DB, student *models.Student, expectedVersion uint) error
+	Delete(ctx context.Context, id uint) error
+	DeleteWithTransaction(tx *gorm.DB, id uint) error
+	CountDependentRecords(ctx context.Context, id uint) (map[string]int64, error)
+
+	// Soft deletion
+	SoftDeleteWithTransaction(tx *gorm.DB, id uint) error
+	RestoreWithTransaction(tx *gorm.DB, id uint) error
+	GetByIDWithDeleted(ctx context.Context, id uint) (*models.Student, error)
+	GetDeletedBefore(ctx context.Context, cutoff time.Time) ([]models.Student, error)
 
 	// Business specific operations
-	GetByBusinessID(businessID uint, filters StudentFilters) ([]models.Student, int64, error)
-	GetActiveStudentsByBusiness(businessID uint) ([]models.Student, error)
-	GetInactiveStudentsByBusiness(businessID uint) ([]models.Student, error)
+	GetByBusinessID(ctx context.Context, businessID uint, filters StudentFilters) ([]models.Student, int64, error)
+	GetActiveStudentsByBusiness(ctx context.Context, businessID uint) ([]models.Student, error)
+
+	// UpdatePhotoPath points the student at a stored photo, or at none when path is empty
+	UpdatePhotoPath(ctx context.Context, id uint, path string) error
+
+	// NextCodeSequenceWithTransaction increments the business's student code counter for a
+	// year and returns the new value. The counter row stays locked until tx ends.
+	NextCodeSequenceWithTransaction(tx *gorm.DB, businessID uint, year int) (int64, error)
 
 	// Status operations
-	UpdateStudentStatus(studentID uint, status int) error
-	GetActiveStudents() ([]models.Student, error)
-	GetInactiveStudents() ([]models.Student, error)
+	UpdateStudentStatusWithTransaction(tx *gorm.DB, studentID uint, status models.Status) error
 
 	// Search and filters
-	SearchStudents(searchTerm string, limit int, businessID ...uint) ([]models.Student, error)
-	SearchStudentsByBusiness(businessID uint, searchTerm string, limit int) ([]models.Student, error)
+	SearchStudents(ctx context.Context, search StudentSearch, limit int, businessID ...uint) ([]models.Student, error)
+	SearchStudentsByBusiness(ctx context.Context, businessID uint, search StudentSearch, limit int) ([]models.Student, error)
 
 	// Statistics
-	GetStudentStats(businessID ...uint) (map[string]interface{}, error)
-	GetGuardianStats(businessID ...uint) (map[string]interface{}, error)
+	GetStudentStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error)
+	GetGuardianStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error)
+	GetRecent(ctx context.Context, limit int, businessID ...uint) ([]models.Student, error)
+	CountAdditions(ctx context.Context, now time.Time, businessID ...uint) (*models.AdditionCounts, error)
 
 	// Relationships
-	GetStudentWithRelations(id uint) (*models.Student, error)
+	GetStudentWithRelations(ctx context.Context, id uint) (*models.Student, error)
+
+	// Guardians
+	GetGuardians(ctx context.Context, studentID uint) ([]models.StudentGuardian, error)
+	GetGuardiansByStudentIDs(ctx context.Context, studentIDs []uint) ([]models.StudentGuardian, error)
+	GetByNameAndGuardianPhone(ctx context.Context, businessID uint, name, phone string) ([]models.Student, error)
+	ReplaceGuardiansWithTransaction(tx *gorm.DB, studentID uint, guardians []models.StudentGuardian) error
+
+	// History
+	CreateHistoryWithTransaction(tx *gorm.DB, entries []models.StudentHistory) error
+	GetRecentHistory(ctx context.Context, studentID uint, limit int) ([]models.StudentHistory, error)
+	CountHistory(ctx context.Context, studentID uint) (int64, error)
 
 	// Bulk operations
-	BulkUpdateStatus(studentIDs []uint, status int) error
+	BulkUpdateStatusWithTransaction(tx *gorm.DB, studentIDs []uint, status models.Status) error
 
 	// Validation
-	StudentUserExists(userID uint, excludeStudentID ...uint) (bool, error)
+	StudentUserExists(ctx context.Context, userID uint, excludeStudentID ...uint) (bool, error)
 
 	// Transaction support
-	BeginTransaction() *gorm.DB
+	BeginTransaction(ctx context.Context) *gorm.DB
 }
 
 type StudentFilters struct {
-	BusinessID    *uint  `form:"business_id" json:"business_id"`
-	Status        *int   `form:"status" json:"status"`
-	GuardianName  string `form:"guardian_name" json:"guardian_name"`
-	GuardianEmail string `form:"guardian_email" json:"guardian_email"`
-	Search        string `form:"search" json:"search"`
-	Page          int    `form:"page" json:"page"`
-	Limit         int    `form:"limit" json:"limit"`
-	SortBy        string `form:"sort_by" json:"sort_by"`
-	SortOrder     string `form:"sort_order" json:"sort_order"`
+	BusinessID     *uint          `form:"business_id" json:"business_id"`
+	Status         *models.Status `form:"status" json:"status"`
+	GuardianName   string         `form:"guardian_name" json:"guardian_name"`
+	GuardianEmail  string         `form:"guardian_email" json:"guardian_email"`
+	BatchID        *uint          `form:"batch_id" json:"batch_id"`
+	Grade          string         `form:"grade" json:"grade"`
+	StudentCode    string         `form:"student_code" json:"student_code"` // exact, ignoring case
+	Gender         string         `form:"gender" json:"gender"`
+	MinAge         *int           `form:"min_age" json:"min_age" binding:"omitempty,min=0"`
+	MaxAge         *int           `form:"max_age" json:"max_age" binding:"omitempty,min=0"`
+	InfoKey        string         `form:"info_key" json:"info_key"`     // custom information field to match
+	InfoValue      string         `form:"info_value" json:"info_value"` // compared as text with info_key's value
+	Search         string         `form:"search" json:"search"`
+	SearchInfo     bool           `form:"search_info" json:"search_info"`         // also search the values of custom information fields
+	SearchInfoKey  string         `form:"search_info_key" json:"search_info_key"` // only search this custom information field
+	Page           int            `form:"page" json:"page"`
+	Limit          int            `form:"limit" json:"limit"`
+	SortBy         string         `form:"sort_by" json:"sort_by"`
+	SortOrder      string         `form:"sort_order" json:"sort_order"`
+	Fields         string         `form:"fields" json:"fields"`                   // comma-separated response fields to return
+	Include        string         `form:"include" json:"include"`                 // comma-separated relations to load
+	Cursor         string         `form:"cursor" json:"cursor"`                   // next_cursor of the previous page, replaces page
+	WithTotal      *bool          `form:"with_total" json:"with_total"`           // false skips counting the total
+	Estimate       bool           `form:"estimate" json:"estimate"`               // estimate the total from table statistics, for large tables
+	IncludeDeleted bool           `form:"include_deleted" json:"include_deleted"` // also list soft-deleted students, for admins
+}
+
+// studentSortFields are the columns students can be sorted by
+var studentSortFields = map[string]bool{
+	"created_on":      true,
+	"updated_on":      true,
+	"name":            true,
+	"guardian_name":   true,
+	"guardian_email":  true,
+	"guardian_number": true,
+	"status":          true,
+	"enrolled_on":     true,
+	"date_of_birth":   true,
+	"grade":           true,
+	"gender":          true,
+	"student_code":    true,
+}
+
+func (f StudentFilters) totalMode() totalMode {
+	return newTotalMode(f.Cursor, f.WithTotal, f.Estimate)
+}
+
+func (f StudentFilters) sort() listSort {
+	return resolveSort(f.SortBy, f.SortOrder, studentSortFields)
+}
+
+// PageInfo builds the pagination metadata for students listed with these filters
+func (f StudentFilters) PageInfo(total int64, students []models.Student) PageInfo {
+	return newPageInfo(total, students, f.sort(), f.Page, f.Limit, f.Cursor, f.totalMode())
+}
+
+// studentRelations are the relations GetAllWithRelations can preload
+var studentRelations = relationPreloads{
+	"user":      func(db *gorm.DB) *gorm.DB { return db.Preload("User") },
+	"business":  func(db *gorm.DB) *gorm.DB { return db.Preload("Business") },
+	"batch":     func(db *gorm.DB) *gorm.DB { return db.Preload("Batch") },
+	"guardians": func(db *gorm.DB) *gorm.DB { return db.Preload("Guardians", orderGuardians) },
+}
+
+// Selection parses the fields and include parameters against StudentResponse
+func (f StudentFilters) Selection() (Selection, error) {
+	return parseSelection(f.Fields, f.Include, models.StudentResponse{}, studentRelations)
+}
+
+// StudentSearch is a free-text search over students
+type StudentSearch struct {
+	Term        string
+	IncludeInfo bool   // also match the values of the student's information
+	InfoKey     string // only match this information key, implies IncludeInfo
 }
 
 type studentRepository struct {
 	db *gorm.DB
 }
 
-func NewStudentRepository() StudentRepository {
+func NewStudentRepository(db *gorm.DB) StudentRepository {
 	return &studentRepository{
-		db: database.DB,
+		db: db,
 	}
 }
 
-func (r *studentRepository) Create(student *models.Student) error {
+func (r *studentRepository) Create(ctx context.Context, student *models.Student) error {
 	if student == nil {
 		return fmt.Errorf("student cannot be nil")
 	}
-	return r.db.Create(student).Error
+	return r.db.WithContext(ctx).Create(student).Error
 }
 
 func (r *studentRepository) CreateWithTransaction(tx *gorm.DB, student *models.Student) error {
@@ -87,107 +182,109 @@ func (r *studentRepository) CreateWithTransaction(tx *gorm.DB, student *models.S
 	return tx.Create(student).Error
 }
 
-func (r *studentRepository) GetByID(id uint) (*models.Student, error) {
+func (r *studentRepository) GetByID(ctx context.Context, id uint) (*models.Student, error) {
 	if id == 0 {
 		return nil, fmt.Errorf("invalid student ID")
 	}
 
 	var student models.Student
-	err := r.db.First(&student, id).Error
+	err := r.db.WithContext(ctx).First(&student, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &student, nil
 }
 
-func (r *studentRepository) GetByUserID(userID uint) (*models.Student, error) {
+func (r *studentRepository) GetByUserID(ctx context.Context, userID uint) (*models.Student, error) {
 	if userID == 0 {
 		return nil, fmt.Errorf("invalid user ID")
 	}
 
 	var student models.Student
-	err := r.db.Where("user_id = ?", userID).First(&student).Error
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&student).Error
 	if err != nil {
 		return nil, err
 	}
 	return &student, nil
 }
 
-func (r *studentRepository) GetAll(filters StudentFilters) ([]models.Student, int64, error) {
+func (r *studentRepository) GetByIDs(ctx context.Context, ids []uint) ([]models.Student, error) {
+	if len(ids) == 0 {
+		return []models.Student{}, nil
+	}
+
 	var students []models.Student
-	var total int64
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&students).Error
+	return students, err
+}
 
-	query := r.db.Model(&models.Student{})
+func (r *studentRepository) GetAll(ctx context.Context, filters StudentFilters) ([]models.Student, int64, error) {
+	var students []models.Student
 
-	// Apply filters
-	if filters.BusinessID != nil {
-		query = query.Where("business_id = ?", *filters.BusinessID)
-	}
+	query := applyStudentFilters(r.db.WithContext(ctx).Model(&models.Student{}), filters)
 
-	if filters.Status != nil {
-		query = query.Where("status = ?", *filters.Status)
+	// Count total first (before pagination), unless skipped
+	total, err := countTotal(query, filters.totalMode())
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if filters.GuardianName != "" {
-		query = query.Where("guardian_name ILIKE ?", "%"+filters.GuardianName+"%")
+	// Apply sorting and pagination
+	query, err = paginate(query, filters.sort(), filters.Page, filters.Limit, filters.Cursor)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if filters.GuardianEmail != "" {
-		query = query.Where("guardian_email ILIKE ?", "%"+filters.GuardianEmail+"%")
-	}
+	err = query.Find(&students).Error
+	return students, total, err
+}
 
-	if filters.Search != "" {
-		query = query.Where("name ILIKE ? OR guardian_name ILIKE ? OR guardian_email ILIKE ? OR guardian_number ILIKE ?",
-			"%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%")
-	}
+func (r *studentRepository) GetAllWithRelations(ctx context.Context, filters StudentFilters) ([]models.Student, int64, error) {
+	var students []models.Student
 
-	// Count total
-	if err := query.Count(&total).Error; err != nil {
+	selection, err := filters.Selection()
+	if err != nil {
 		return nil, 0, err
 	}
+	query := applyStudentFilters(r.db.WithContext(ctx).Model(&models.Student{}), filters)
 
-	// Apply sorting
-	orderBy := "created_on DESC"
-	if filters.SortBy != "" {
-		validSortFields := map[string]bool{
-			"created_on":      true,
-			"updated_on":      true,
-			"name":            true,
-			"guardian_name":   true,
-			"guardian_email":  true,
-			"guardian_number": true,
-			"status":          true,
-		}
-		if validSortFields[filters.SortBy] {
-			sortOrder := "DESC"
-			if filters.SortOrder == "asc" {
-				sortOrder = "ASC"
-			}
-			orderBy = fmt.Sprintf("%s %s", filters.SortBy, sortOrder)
-		}
+	// Count total first (before preloads and pagination), unless skipped
+	total, err := countTotal(query, filters.totalMode())
+	if err != nil {
+		return nil, 0, err
 	}
-	query = query.Order(orderBy)
+	query = selection.preload(query, studentRelations)
 
-	// Apply pagination
-	if filters.Limit > 0 {
-		offset := 0
-		if filters.Page > 1 {
-			offset = (filters.Page - 1) * filters.Limit
-		}
-		query = query.Offset(offset).Limit(filters.Limit)
+	// Apply sorting and pagination
+	query, err = paginate(query, filters.sort(), filters.Page, filters.Limit, filters.Cursor)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	err := query.Find(&students).Error
+	err = query.Find(&students).Error
 	return students, total, err
 }
 
-func (r *studentRepository) GetAllWithRelations(filters StudentFilters) ([]models.Student, int64, error) {
-	var students []models.Student
-	var total int64
+// ForEachBatch calls fn with the students matching filters, batchSize at a time in id
+// order with their user, batch and guardians loaded, so exports never hold every row in
+// memory. Sorting and pagination filters are ignored.
+func (r *studentRepository) ForEachBatch(ctx context.Context, filters StudentFilters, batchSize int, fn func([]models.Student) error) error {
+	var batch []models.Student
+	query := applyStudentFilters(r.db.WithContext(ctx).Model(&models.Student{}), filters).
+		Preload("User").Preload("Batch").Preload("Guardians", orderGuardians)
+
+	return query.FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
 
-	query := r.db.Model(&models.Student{}).Preload("User").Preload("Business")
+// applyStudentFilters narrows query to the students matching filters, leaving out
+// sorting, pagination and preloads
+func applyStudentFilters(query *gorm.DB, filters StudentFilters) *gorm.DB {
+	if filters.IncludeDeleted {
+		query = query.Unscoped()
+	}
 
-	// Apply same filters as GetAll
 	if filters.BusinessID != nil {
 		query = query.Where("business_id = ?", *filters.BusinessID)
 	}
@@ -197,66 +294,53 @@ func (r *studentRepository) GetAllWithRelations(filters StudentFilters) ([]model
 	}
 
 	if filters.GuardianName != "" {
-		query = query.Where("guardian_name ILIKE ?", "%"+filters.GuardianName+"%")
+		query = query.Where(guardianExists("g.name ILIKE ?"), "%"+filters.GuardianName+"%")
 	}
 
 	if filters.GuardianEmail != "" {
-		query = query.Where("guardian_email ILIKE ?", "%"+filters.GuardianEmail+"%")
+		query = query.Where(guardianExists("g.email ILIKE ?"), "%"+filters.GuardianEmail+"%")
 	}
 
-	if filters.Search != "" {
-		query = query.Where("name ILIKE ? OR guardian_name ILIKE ? OR guardian_email ILIKE ? OR guardian_number ILIKE ?",
-			"%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%")
+	if filters.BatchID != nil {
+		query = query.Where("batch_id = ?", *filters.BatchID)
 	}
 
-	// Count total
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	if filters.Grade != "" {
+		query = query.Where("LOWER(grade) = LOWER(?)", filters.Grade)
 	}
 
-	// Apply sorting
-	orderBy := "created_on DESC"
-	if filters.SortBy != "" {
-		validSortFields := map[string]bool{
-			"created_on":      true,
-			"updated_on":      true,
-			"name":            true,
-			"guardian_name":   true,
-			"guardian_email":  true,
-			"guardian_number": true,
-			"status":          true,
-		}
-		if validSortFields[filters.SortBy] {
-			sortOrder := "DESC"
-			if filters.SortOrder == "asc" {
-				sortOrder = "ASC"
-			}
-			orderBy = fmt.Sprintf("%s %s", filters.SortBy, sortOrder)
-		}
+	if filters.StudentCode != "" {
+		query = query.Where("LOWER(student_code) = LOWER(?)", strings.TrimSpace(filters.StudentCode))
 	}
-	query = query.Order(orderBy)
 
-	// Apply pagination
-	if filters.Limit > 0 {
-		offset := 0
-		if filters.Page > 1 {
-			offset = (filters.Page - 1) * filters.Limit
-		}
-		query = query.Offset(offset).Limit(filters.Limit)
+	if filters.Gender != "" {
+		query = query.Where("gender = ?", filters.Gender)
 	}
 
-	err := query.Find(&students).Error
-	return students, total, err
+	query = applyAgeFilters(query, filters)
+
+	if filters.InfoKey != "" {
+		query = query.Where("information ->> ? = ?", filters.InfoKey, filters.InfoValue)
+	}
+
+	query = applyStudentSearch(query, StudentSearch{
+		Term:        filters.Search,
+		IncludeInfo: filters.SearchInfo,
+		InfoKey:     filters.SearchInfoKey,
+	})
+
+	return query
 }
 
-func (r *studentRepository) Update(student *models.Student) error {
+func (r *studentRepository) Update(ctx context.Context, student *models.Student) error {
 	if student == nil {
 		return fmt.Errorf("student cannot be nil")
 	}
 	if student.ID == 0 {
 		return fmt.Errorf("student ID cannot be zero")
 	}
-	return r.db.Save(student).Error
+	student.Version++
+	return r.db.WithContext(ctx).Save(student).Error
 }
 
 func (r *studentRepository) UpdateWithTransaction(tx *gorm.DB, student *models.Student) error {
@@ -266,75 +350,191 @@ func (r *studentRepository) UpdateWithTransaction(tx *gorm.DB, student *models.S
 	if student.ID == 0 {
 		return fmt.Errorf("student ID cannot be zero")
 	}
+	student.Version++
 	return tx.Save(student).Error
 }
 
-func (r *studentRepository) Delete(id uint) error {
+// UpdateVersionedWithTransaction saves the student only if it is still at expectedVersion,
+// returning ErrVersionConflict otherwise
+func (r *studentRepository) UpdateVersionedWithTransaction(tx *gorm.DB, student *models.Student, expectedVersion uint) error {
+	if student == nil {
+		return fmt.Errorf("student cannot be nil")
+	}
+	if student.ID == 0 {
+		return fmt.Errorf("student ID cannot be zero")
+	}
+	return saveVersioned(tx, student, &student.Version, expectedVersion)
+}
+
+func (r *studentRepository) Delete(ctx context.Context, id uint) error {
 	if id == 0 {
 		return fmt.Errorf("invalid student ID")
 	}
-	return r.db.Delete(&models.Student{}, id).Error
+	return r.db.WithContext(ctx).Delete(&models.Student{}, id).Error
 }
 
-func (r *studentRepository) GetByBusinessID(businessID uint, filters StudentFilters) ([]models.Student, int64, error) {
-	if businessID == 0 {
-		return nil, 0, fmt.Errorf("invalid business ID")
+// DeleteWithTransaction permanently removes a student, soft-deleted or not, together with
+// its teacher assignments, attendance, exam results, personal fee plans, guardians and history
+func (r *studentRepository) DeleteWithTransaction(tx *gorm.DB, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid student ID")
 	}
 
-	filters.BusinessID = &businessID
-	return r.GetAll(filters)
+	if err := tx.Where("student_id = ?", id).Delete(&models.StudentAttendance{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("student_id = ?", id).Delete(&models.ExamResult{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("student_id = ?", id).Delete(&models.FeePlan{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("student_id = ?", id).Delete(&models.StudentGuardian{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("student_id = ?", id).Delete(&models.StudentHistory{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("student_id = ?", id).Delete(&models.GuardianLink{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("student_id = ?", id).Delete(&models.TeacherStudent{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Delete(&models.Student{}, id).Error
 }
 
-func (r *studentRepository) GetActiveStudentsByBusiness(businessID uint) ([]models.Student, error) {
-	if businessID == 0 {
-		return nil, fmt.Errorf("invalid business ID")
+// CountDependentRecords counts the records that must not disappear when a student is deleted
+func (r *studentRepository) CountDependentRecords(ctx context.Context, id uint) (map[string]int64, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid student ID")
+	}
+
+	counts := make(map[string]int64)
+
+	var payments int64
+	if err := r.db.WithContext(ctx).Model(&models.FeePayment{}).Where("student_id = ?", id).Count(&payments).Error; err != nil {
+		return nil, err
+	}
+	counts["payments"] = payments
+
+	var attendance int64
+	if err := r.db.WithContext(ctx).Model(&models.StudentAttendance{}).Where("student_id = ?", id).Count(&attendance).Error; err != nil {
+		return nil, err
+	}
+	counts["attendance"] = attendance
+
+	return counts, nil
+}
+
+// SoftDeleteWithTransaction hides a student from every query while keeping its
+// attendance, fees and history, so it can be restored
+func (r *studentRepository) SoftDeleteWithTransaction(tx *gorm.DB, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid student ID")
+	}
+	return tx.Delete(&models.Student{}, id).Error
+}
+
+func (r *studentRepository) RestoreWithTransaction(tx *gorm.DB, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid student ID")
 	}
+	return tx.Unscoped().Model(&models.Student{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "version": bumpVersion}).Error
+}
+
+// GetByIDWithDeleted returns a student whether or not it is soft-deleted
+func (r *studentRepository) GetByIDWithDeleted(ctx context.Context, id uint) (*models.Student, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid student ID")
+	}
+
+	var student models.Student
+	err := r.db.WithContext(ctx).Unscoped().First(&student, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &student, nil
+}
 
+// GetDeletedBefore returns the students soft-deleted before cutoff. Those with fee
+// payments are left out: payments are kept for the books, so they are never purged.
+func (r *studentRepository) GetDeletedBefore(ctx context.Context, cutoff time.Time) ([]models.Student, error) {
 	var students []models.Student
-	err := r.db.Where("business_id = ? AND status = 1", businessID).Find(&students).Error
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM fee_payment p WHERE p.student_id = student.id)").
+		Order("id ASC").
+		Find(&students).Error
 	return students, err
 }
 
-func (r *studentRepository) GetInactiveStudentsByBusiness(businessID uint) ([]models.Student, error) {
+func (r *studentRepository) GetByBusinessID(ctx context.Context, businessID uint, filters StudentFilters) ([]models.Student, int64, error) {
+	if businessID == 0 {
+		return nil, 0, fmt.Errorf("invalid business ID")
+	}
+
+	filters.BusinessID = &businessID
+	return r.GetAll(ctx, filters)
+}
+
+func (r *studentRepository) GetActiveStudentsByBusiness(ctx context.Context, businessID uint) ([]models.Student, error) {
 	if businessID == 0 {
 		return nil, fmt.Errorf("invalid business ID")
 	}
 
 	var students []models.Student
-	err := r.db.Where("business_id = ? AND status = 0", businessID).Find(&students).Error
+	err := r.db.WithContext(ctx).Where("business_id = ? AND status = 1", businessID).Find(&students).Error
 	return students, err
 }
 
-func (r *studentRepository) UpdateStudentStatus(studentID uint, status int) error {
+func (r *studentRepository) NextCodeSequenceWithTransaction(tx *gorm.DB, businessID uint, year int) (int64, error) {
+	// One atomic upsert; MAX(student_code)+1 would race between concurrent creations
+	var value int64
+	err := tx.Raw(`INSERT INTO student_code_counter (business_id, year, value) VALUES (?, ?, 1)
+		ON CONFLICT (business_id, year) DO UPDATE SET value = student_code_counter.value + 1
+		RETURNING value`, businessID, year).Scan(&value).Error
+	return value, err
+}
+
+func (r *studentRepository) UpdatePhotoPath(ctx context.Context, id uint, path string) error {
+	// Bumps updated_on, which versions the photo URL, but not the version edits are checked against
+	result := r.db.WithContext(ctx).Model(&models.Student{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"photo_path": path, "updated_on": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("student not found")
+	}
+	return nil
+}
+
+func (r *studentRepository) UpdateStudentStatusWithTransaction(tx *gorm.DB, studentID uint, status models.Status) error {
 	if studentID == 0 {
 		return fmt.Errorf("invalid student ID")
 	}
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return fmt.Errorf("invalid status value")
 	}
 
-	return r.db.Model(&models.Student{}).Where("id = ?", studentID).Update("status", status).Error
-}
-
-func (r *studentRepository) GetActiveStudents() ([]models.Student, error) {
-	var students []models.Student
-	err := r.db.Where("status = 1").Find(&students).Error
-	return students, err
-}
-
-func (r *studentRepository) GetInactiveStudents() ([]models.Student, error) {
-	var students []models.Student
-	err := r.db.Where("status = 0").Find(&students).Error
-	return students, err
+	return tx.Model(&models.Student{}).Where("id = ?", studentID).Update("status", status).Error
 }
 
-func (r *studentRepository) SearchStudents(searchTerm string, limit int, businessID ...uint) ([]models.Student, error) {
-	if searchTerm == "" {
+func (r *studentRepository) SearchStudents(ctx context.Context, search StudentSearch, limit int, businessID ...uint) ([]models.Student, error) {
+	if strings.TrimSpace(search.Term) == "" {
 		return []models.Student{}, nil
 	}
 
-	query := r.db.Where("name ILIKE ? OR guardian_name ILIKE ? OR guardian_email ILIKE ? OR guardian_number ILIKE ?",
-		"%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%")
+	query := applyStudentSearch(r.db.WithContext(ctx).Model(&models.Student{}), search)
 
 	if len(businessID) > 0 && businessID[0] > 0 {
 		query = query.Where("business_id = ?", businessID[0])
@@ -351,17 +551,19 @@ func (r *studentRepository) SearchStudents(searchTerm string, limit int, busines
 	return students, err
 }
 
-func (r *studentRepository) SearchStudentsByBusiness(businessID uint, searchTerm string, limit int) ([]models.Student, error) {
-	return r.SearchStudents(searchTerm, limit, businessID)
+func (r *studentRepository) SearchStudentsByBusiness(ctx context.Context, businessID uint, search StudentSearch, limit int) ([]models.Student, error) {
+	return r.SearchStudents(ctx, search, limit, businessID)
 }
 
-func (r *studentRepository) GetStudentStats(businessID ...uint) (map[string]interface{}, error) {
+func (r *studentRepository) GetStudentStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
-	query := r.db.Model(&models.Student{})
+	query := r.db.WithContext(ctx).Model(&models.Student{})
 	if len(businessID) > 0 && businessID[0] > 0 {
 		query = query.Where("business_id = ?", businessID[0])
 	}
+	// Each count gets its own session so conditions added for one don't leak into the others
+	query = query.Session(&gorm.Session{})
 
 	// Total students
 	var totalStudents int64
@@ -372,8 +574,7 @@ func (r *studentRepository) GetStudentStats(businessID ...uint) (map[string]inte
 
 	// Active students
 	var activeStudents int64
-	activeQuery := query
-	if err := activeQuery.Where("status = 1").Count(&activeStudents).Error; err != nil {
+	if err := query.Where("status = 1").Count(&activeStudents).Error; err != nil {
 		return nil, err
 	}
 	stats["active_students"] = activeStudents
@@ -381,67 +582,167 @@ func (r *studentRepository) GetStudentStats(businessID ...uint) (map[string]inte
 	// Inactive students
 	stats["inactive_students"] = totalStudents - activeStudents
 
+	scoped := func() *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&models.Student{})
+		if len(businessID) > 0 && businessID[0] > 0 {
+			query = query.Where("business_id = ?", businessID[0])
+		}
+		return query
+	}
+
+	// Students per grade, with no grade counted as ""
+	var gradeCounts []struct {
+		Grade string
+		Count int64
+	}
+	if err := scoped().Select("grade, COUNT(*) AS count").Group("grade").Order("grade").Scan(&gradeCounts).Error; err != nil {
+		return nil, err
+	}
+	byGrade := make(map[string]int64, len(gradeCounts))
+	for _, row := range gradeCounts {
+		byGrade[row.Grade] = row.Count
+	}
+	stats["by_grade"] = byGrade
+
+	// Students per gender, with no gender counted as ""
+	var genderCounts []struct {
+		Gender string
+		Count  int64
+	}
+	if err := scoped().Select("gender, COUNT(*) AS count").Group("gender").Scan(&genderCounts).Error; err != nil {
+		return nil, err
+	}
+	byGender := make(map[string]int64, len(genderCounts))
+	for _, row := range genderCounts {
+		byGender[row.Gender] = row.Count
+	}
+	stats["by_gender"] = byGender
+
+	// Students per age in whole years
+	ageHistogram := []models.AgeCount{}
+	if err := scoped().
+		Select("date_part('year', age(CURRENT_DATE, date_of_birth))::int AS age, COUNT(*) AS count").
+		Where("date_of_birth IS NOT NULL").
+		Group("age").
+		Order("age").
+		Scan(&ageHistogram).Error; err != nil {
+		return nil, err
+	}
+	stats["age_histogram"] = ageHistogram
+
+	var withoutDateOfBirth int64
+	if err := scoped().Where("date_of_birth IS NULL").Count(&withoutDateOfBirth).Error; err != nil {
+		return nil, err
+	}
+	stats["age_unknown"] = withoutDateOfBirth
+
 	return stats, nil
 }
 
-func (r *studentRepository) GetGuardianStats(businessID ...uint) (map[string]interface{}, error) {
+func (r *studentRepository) GetGuardianStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
-	query := r.db.Model(&models.Student{})
-	if len(businessID) > 0 && businessID[0] > 0 {
-		query = query.Where("business_id = ?", businessID[0])
+	// Guardians of the students in scope
+	guardians := func() *gorm.DB {
+		query := r.db.WithContext(ctx).Table("student_guardian g").Joins("JOIN student s ON s.id = g.student_id AND s.deleted_at IS NULL")
+		if len(businessID) > 0 && businessID[0] > 0 {
+			query = query.Where("s.business_id = ?", businessID[0])
+		}
+		return query
+	}
+
+	var totalGuardians int64
+	if err := guardians().Count(&totalGuardians).Error; err != nil {
+		return nil, err
 	}
+	stats["total_guardians"] = totalGuardians
 
-	// Students with guardian email
+	// Students with at least one guardian email
 	var studentsWithEmail int64
-	if err := query.Where("guardian_email IS NOT NULL AND guardian_email != ''").Count(&studentsWithEmail).Error; err != nil {
+	if err := guardians().Where("g.email IS NOT NULL AND g.email != ''").Distinct("g.student_id").Count(&studentsWithEmail).Error; err != nil {
 		return nil, err
 	}
 	stats["students_with_guardian_email"] = studentsWithEmail
 
-	// Students with guardian phone
+	// Students with at least one guardian phone
 	var studentsWithPhone int64
-	if err := query.Where("guardian_number IS NOT NULL AND guardian_number != ''").Count(&studentsWithPhone).Error; err != nil {
+	if err := guardians().Where("g.phone IS NOT NULL AND g.phone != ''").Distinct("g.student_id").Count(&studentsWithPhone).Error; err != nil {
 		return nil, err
 	}
 	stats["students_with_guardian_phone"] = studentsWithPhone
 
+	// Students with more than one guardian
+	var studentsWithMultiple int64
+	err := r.db.WithContext(ctx).Table("(?) as counts", guardians().Select("g.student_id").Group("g.student_id").Having("COUNT(*) > 1")).
+		Count(&studentsWithMultiple).Error
+	if err != nil {
+		return nil, err
+	}
+	stats["students_with_multiple_guardians"] = studentsWithMultiple
+
+	// Students without any guardian
+	students := r.db.WithContext(ctx).Model(&models.Student{}).Where("NOT " + guardianExists("TRUE"))
+	if len(businessID) > 0 && businessID[0] > 0 {
+		students = students.Where("business_id = ?", businessID[0])
+	}
+	var studentsWithout int64
+	if err := students.Count(&studentsWithout).Error; err != nil {
+		return nil, err
+	}
+	stats["students_without_guardians"] = studentsWithout
+
+	// Guardians by relation
+	var relations []struct {
+		Relation string
+		Count    int64
+	}
+	if err := guardians().Select("COALESCE(NULLIF(g.relation, ''), 'unspecified') as relation, COUNT(*) as count").
+		Group("1").Scan(&relations).Error; err != nil {
+		return nil, err
+	}
+	byRelation := make(map[string]int64)
+	for _, relation := range relations {
+		byRelation[relation.Relation] = relation.Count
+	}
+	stats["guardians_by_relation"] = byRelation
+
 	return stats, nil
 }
 
-func (r *studentRepository) GetStudentWithRelations(id uint) (*models.Student, error) {
+func (r *studentRepository) GetStudentWithRelations(ctx context.Context, id uint) (*models.Student, error) {
 	if id == 0 {
 		return nil, fmt.Errorf("invalid student ID")
 	}
 
 	var student models.Student
-	err := r.db.Preload("User").Preload("Business").First(&student, id).Error
+	err := r.db.WithContext(ctx).Preload("User").Preload("Business").Preload("Batch").Preload("Guardians", orderGuardians).First(&student, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &student, nil
 }
 
-func (r *studentRepository) BulkUpdateStatus(studentIDs []uint, status int) error {
+func (r *studentRepository) BulkUpdateStatusWithTransaction(tx *gorm.DB, studentIDs []uint, status models.Status) error {
 	if len(studentIDs) == 0 {
 		return fmt.Errorf("no student IDs provided")
 	}
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return fmt.Errorf("invalid status value")
 	}
 
-	return r.db.Model(&models.Student{}).
+	return tx.Model(&models.Student{}).
 		Where("id IN ?", studentIDs).
-		Update("status", status).Error
+		Updates(map[string]interface{}{"status": status, "version": bumpVersion}).Error
 }
 
-func (r *studentRepository) StudentUserExists(userID uint, excludeStudentID ...uint) (bool, error) {
+func (r *studentRepository) StudentUserExists(ctx context.Context, userID uint, excludeStudentID ...uint) (bool, error) {
 	if userID == 0 {
 		return false, fmt.Errorf("user ID cannot be zero")
 	}
 
+	// A soft-deleted student still holds its user
 	var count int64
-	query := r.db.Model(&models.Student{}).Where("user_id = ?", userID)
+	query := r.db.WithContext(ctx).Unscoped().Model(&models.Student{}).Where("user_id = ?", userID)
 
 	if len(excludeStudentID) > 0 && excludeStudentID[0] > 0 {
 		query = query.Where("id != ?", excludeStudentID[0])
@@ -451,6 +752,191 @@ func (r *studentRepository) StudentUserExists(userID uint, excludeStudentID ...u
 	return count > 0, err
 }
 
-func (r *studentRepository) BeginTransaction() *gorm.DB {
-	return r.db.Begin()
+func (r *studentRepository) GetGuardians(ctx context.Context, studentID uint) ([]models.StudentGuardian, error) {
+	var guardians []models.StudentGuardian
+	err := orderGuardians(r.db.WithContext(ctx).Where("student_id = ?", studentID)).Find(&guardians).Error
+	return guardians, err
+}
+
+// GetGuardiansByStudentIDs returns the guardians of several students, primary guardians first
+func (r *studentRepository) GetGuardiansByStudentIDs(ctx context.Context, studentIDs []uint) ([]models.StudentGuardian, error) {
+	var guardians []models.StudentGuardian
+	if len(studentIDs) == 0 {
+		return guardians, nil
+	}
+	err := orderGuardians(r.db.WithContext(ctx).Where("student_id IN ?", studentIDs)).Find(&guardians).Error
+	return guardians, err
+}
+
+// GetByNameAndGuardianPhone finds students of a business with the given name (ignoring
+// case) and a guardian with the given normalized phone
+func (r *studentRepository) GetByNameAndGuardianPhone(ctx context.Context, businessID uint, name, phone string) ([]models.Student, error) {
+	var students []models.Student
+	err := r.db.WithContext(ctx).Where("business_id = ? AND LOWER(name) = LOWER(?)", businessID, name).
+		Where(guardianExists("g.phone = ?"), phone).
+		Order("id ASC").
+		Find(&students).Error
+	return students, err
+}
+
+// ReplaceGuardiansWithTransaction swaps all guardians of a student for the given ones
+func (r *studentRepository) ReplaceGuardiansWithTransaction(tx *gorm.DB, studentID uint, guardians []models.StudentGuardian) error {
+	if studentID == 0 {
+		return fmt.Errorf("invalid student ID")
+	}
+
+	if err := tx.Where("student_id = ?", studentID).Delete(&models.StudentGuardian{}).Error; err != nil {
+		return err
+	}
+
+	if len(guardians) == 0 {
+		return nil
+	}
+	for i := range guardians {
+		guardians[i].ID = 0
+		guardians[i].StudentID = studentID
+	}
+	return tx.Omit("Student").Create(&guardians).Error
+}
+
+// CreateHistoryWithTransaction records student changes within a transaction
+func (r *studentRepository) CreateHistoryWithTransaction(tx *gorm.DB, entries []models.StudentHistory) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return tx.Omit("Student").Create(&entries).Error
+}
+
+// GetRecentHistory returns the latest history entries of a student, newest first
+func (r *studentRepository) GetRecentHistory(ctx context.Context, studentID uint, limit int) ([]models.StudentHistory, error) {
+	if studentID == 0 {
+		return nil, fmt.Errorf("invalid student ID")
+	}
+
+	var entries []models.StudentHistory
+	err := r.db.WithContext(ctx).Where("student_id = ?", studentID).
+		Order("created_on DESC, id DESC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *studentRepository) CountHistory(ctx context.Context, studentID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.StudentHistory{}).Where("student_id = ?", studentID).Count(&count).Error
+	return count, err
+}
+
+func (r *studentRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
+}
+
+// guardianSearch matches a search term against a guardian's name, email or phone
+const guardianSearch = "g.name ILIKE ? OR g.email ILIKE ? OR g.phone ILIKE ?"
+
+// guardianPhoneSearch matches the digits of a phone number against guardian phones, which
+// are stored as digits with an optional leading +. The second part catches terms that
+// carry a country code or trunk prefix the stored number doesn't have; whole numbers
+// written in any format are matched through searchPhone.
+const guardianPhoneSearch = "g.phone LIKE ? OR (g.phone != '' AND ? LIKE '%' || LTRIM(g.phone, '+'))"
+
+// applyStudentSearch matches a search term against the student's name, code and guardians
+// and, if asked, the values of the student's information
+func applyStudentSearch(query *gorm.DB, search StudentSearch) *gorm.DB {
+	term := strings.TrimSpace(search.Term)
+	if term == "" {
+		return query
+	}
+	like := "%" + term + "%"
+
+	conditions := []string{"student.name ILIKE ?", "student.student_code ILIKE ?", guardianExists(guardianSearch)}
+	args := []interface{}{like, like, like, like, like}
+
+	if digits := searchPhoneDigits(term); digits != "" {
+		conditions = append(conditions, guardianExists(guardianPhoneSearch))
+		args = append(args, "%"+digits+"%", digits)
+	}
+	if phone := searchPhone(term); phone != "" {
+		conditions = append(conditions, guardianExists("g.phone = ?"))
+		args = append(args, phone)
+	}
+
+	if search.InfoKey != "" {
+		conditions = append(conditions, "student.information ->> ? ILIKE ?")
+		args = append(args, search.InfoKey, like)
+	} else if search.IncludeInfo {
+		// The text match can use the trigram index to narrow rows before the values are checked
+		conditions = append(conditions, "(student.information::text ILIKE ? AND EXISTS (SELECT 1 FROM jsonb_each_text(student.information) AS info WHERE info.value ILIKE ?))")
+		args = append(args, like, like)
+	}
+
+	return query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// searchPhoneDigits returns the digits of a search term that looks like a phone number,
+// written with any of the formatting normalizePhone accepts, or "" otherwise
+func searchPhoneDigits(term string) string {
+	var digits strings.Builder
+	for i, r := range term {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return ""
+		}
+	}
+
+	// Shorter terms are already covered by the plain phone match
+	if digits.Len() < 4 {
+		return ""
+	}
+	return digits.String()
+}
+
+// applyAgeFilters limits students to an age range in whole years, as of today
+func applyAgeFilters(query *gorm.DB, filters StudentFilters) *gorm.DB {
+	today := time.Now().UTC()
+	if filters.MinAge != nil {
+		// Born on or before this date to be at least MinAge
+		query = query.Where("date_of_birth <= ?", today.AddDate(-*filters.MinAge, 0, 0).Format(models.DateFormat))
+	}
+	if filters.MaxAge != nil {
+		// Born after this date to be at most MaxAge
+		query = query.Where("date_of_birth > ?", today.AddDate(-*filters.MaxAge-1, 0, 0).Format(models.DateFormat))
+	}
+	return query
+}
+
+// guardianExists wraps a condition on the guardian alias g so it matches students
+// with at least one such guardian
+func guardianExists(condition string) string {
+	return "EXISTS (SELECT 1 FROM student_guardian g WHERE g.student_id = student.id AND (" + condition + "))"
+}
+
+// orderGuardians lists the primary guardian first
+func orderGuardians(db *gorm.DB) *gorm.DB {
+	return db.Order("is_primary DESC, id ASC")
+}
+
+// GetRecent returns the latest students added, with only their business's name loaded
+func (r *studentRepository) GetRecent(ctx context.Context, limit int, businessID ...uint) ([]models.Student, error) {
+	query := r.db.WithContext(ctx).Preload("Business", withBusinessName)
+	if len(businessID) > 0 && businessID[0] > 0 {
+		query = query.Where("business_id = ?", businessID[0])
+	}
+
+	var students []models.Student
+	err := latestFirst(query, limit).Find(&students).Error
+	return students, err
+}
+
+// CountAdditions counts the students added in the 7 and 30 days before now
+func (r *studentRepository) CountAdditions(ctx context.Context, now time.Time, businessID ...uint) (*models.AdditionCounts, error) {
+	query := r.db.WithContext(ctx).Model(&models.Student{})
+	if len(businessID) > 0 && businessID[0] > 0 {
+		query = query.Where("business_id = ?", businessID[0])
+	}
+	return countAdditions(query, now)
 }
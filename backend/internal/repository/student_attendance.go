@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StudentAttendanceRepository interface {
+	// Marking attendance
+	UpsertWithTransaction(tx *gorm.DB, records []models.StudentAttendance) error
+
+	// Queries
+	GetAll(ctx context.Context, filters StudentAttendanceFilters) ([]models.StudentAttendance, int64, error)
+	GetStatusCounts(ctx context.Context, filters StudentAttendanceFilters) ([]StudentAttendanceCount, error)
+
+	// Transaction support
+	BeginTransaction(ctx context.Context) *gorm.DB
+}
+
+type StudentAttendanceFilters struct {
+	BusinessID *uint  `form:"business_id" json:"business_id"`
+	StudentID  *uint  `form:"student_id" json:"student_id"`
+	BatchID    *uint  `form:"batch_id" json:"batch_id"`
+	Status     string `form:"status" json:"status" binding:"omitempty,oneof=present absent leave"`
+	From       string `form:"from" json:"from"` // YYYY-MM-DD, inclusive
+	To         string `form:"to" json:"to"`     // YYYY-MM-DD, inclusive
+	Page       int    `form:"page" json:"page"`
+	Limit      int    `form:"limit" json:"limit"`
+}
+
+// StudentAttendanceCount is the number of days a student was marked with a status
+type StudentAttendanceCount struct {
+	StudentID uint
+	Status    string
+	Count     int64
+}
+
+type studentAttendanceRepository struct {
+	db *gorm.DB
+}
+
+func NewStudentAttendanceRepository(db *gorm.DB) StudentAttendanceRepository {
+	return &studentAttendanceRepository{
+		db: db,
+	}
+}
+
+// studentAttendanceUpsert overwrites the existing record for the same student and day
+var studentAttendanceUpsert = clause.OnConflict{
+	Columns:   []clause.Column{{Name: "student_id"}, {Name: "date"}},
+	DoUpdates: clause.AssignmentColumns([]string{"status", "note", "marked_by", "business_id", "batch_id", "updated_on"}),
+}
+
+func (r *studentAttendanceRepository) UpsertWithTransaction(tx *gorm.DB, records []models.StudentAttendance) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return tx.Clauses(studentAttendanceUpsert).Create(&records).Error
+}
+
+func (r *studentAttendanceRepository) GetAll(ctx context.Context, filters StudentAttendanceFilters) ([]models.StudentAttendance, int64, error) {
+	var records []models.StudentAttendance
+	var total int64
+
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&models.StudentAttendance{}), filters)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Preload("Student").Order("date DESC, student_id ASC")
+
+	// Apply pagination
+	if filters.Limit > 0 {
+		offset := 0
+		if filters.Page > 1 {
+			offset = (filters.Page - 1) * filters.Limit
+		}
+		query = query.Offset(offset).Limit(filters.Limit)
+	}
+
+	err := query.Find(&records).Error
+	return records, total, err
+}
+
+func (r *studentAttendanceRepository) GetStatusCounts(ctx context.Context, filters StudentAttendanceFilters) ([]StudentAttendanceCount, error) {
+	var counts []StudentAttendanceCount
+
+	err := r.applyFilters(r.db.WithContext(ctx).Model(&models.StudentAttendance{}), filters).
+		Select("student_id, status, COUNT(*) as count").
+		Group("student_id, status").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *studentAttendanceRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
+}
+
+func (r *studentAttendanceRepository) applyFilters(query *gorm.DB, filters StudentAttendanceFilters) *gorm.DB {
+	if filters.BusinessID != nil {
+		query = query.Where("business_id = ?", *filters.BusinessID)
+	}
+	if filters.StudentID != nil {
+		query = query.Where("student_id = ?", *filters.StudentID)
+	}
+	if filters.BatchID != nil {
+		query = query.Where("batch_id = ?", *filters.BatchID)
+	}
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.From != "" {
+		query = query.Where("date >= ?", filters.From)
+	}
+	if filters.To != "" {
+		query = query.Where("date <= ?", filters.To)
+	}
+	return query
+}
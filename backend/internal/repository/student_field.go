@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type StudentFieldRepository interface {
+	Create(ctx context.Context, field *models.StudentField) error
+	GetByID(ctx context.Context, id uint) (*models.StudentField, error)
+	GetByBusinessID(ctx context.Context, businessID uint) ([]models.StudentField, error)
+	Update(ctx context.Context, field *models.StudentField) error
+	Delete(ctx context.Context, id uint) error
+
+	// Validation
+	FieldKeyExists(ctx context.Context, businessID uint, key string) (bool, error)
+
+	// Grades
+	GetGrades(ctx context.Context, businessID uint) ([]models.StudentGrade, error)
+	ReplaceGrades(ctx context.Context, businessID uint, grades []models.StudentGrade) error
+}
+
+type studentFieldRepository struct {
+	db *gorm.DB
+}
+
+func NewStudentFieldRepository(db *gorm.DB) StudentFieldRepository {
+	return &studentFieldRepository{
+		db: db,
+	}
+}
+
+func (r *studentFieldRepository) Create(ctx context.Context, field *models.StudentField) error {
+	if field == nil {
+		return fmt.Errorf("student field cannot be nil")
+	}
+	return r.db.WithContext(ctx).Create(field).Error
+}
+
+func (r *studentFieldRepository) GetByID(ctx context.Context, id uint) (*models.StudentField, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid student field ID")
+	}
+
+	var field models.StudentField
+	err := r.db.WithContext(ctx).First(&field, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+func (r *studentFieldRepository) GetByBusinessID(ctx context.Context, businessID uint) ([]models.StudentField, error) {
+	if businessID == 0 {
+		return nil, fmt.Errorf("invalid business ID")
+	}
+
+	var fields []models.StudentField
+	err := r.db.WithContext(ctx).Where("business_id = ?", businessID).Order("id ASC").Find(&fields).Error
+	return fields, err
+}
+
+func (r *studentFieldRepository) Update(ctx context.Context, field *models.StudentField) error {
+	if field == nil {
+		return fmt.Errorf("student field cannot be nil")
+	}
+	if field.ID == 0 {
+		return fmt.Errorf("student field ID cannot be zero")
+	}
+	return r.db.WithContext(ctx).Save(field).Error
+}
+
+// Delete removes the definition only; values already stored in student information are kept
+func (r *studentFieldRepository) Delete(ctx context.Context, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid student field ID")
+	}
+	return r.db.WithContext(ctx).Delete(&models.StudentField{}, id).Error
+}
+
+func (r *studentFieldRepository) FieldKeyExists(ctx context.Context, businessID uint, key string) (bool, error) {
+	if key == "" {
+		return false, fmt.Errorf("key cannot be empty")
+	}
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.StudentField{}).Where("business_id = ? AND key = ?", businessID, key).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *studentFieldRepository) GetGrades(ctx context.Context, businessID uint) ([]models.StudentGrade, error) {
+	if businessID == 0 {
+		return nil, fmt.Errorf("invalid business ID")
+	}
+
+	var grades []models.StudentGrade
+	err := r.db.WithContext(ctx).Where("business_id = ?", businessID).Order("position ASC, id ASC").Find(&grades).Error
+	return grades, err
+}
+
+// ReplaceGrades swaps all grades of a business for the given ones. Students keep their
+// grade even when it is no longer listed.
+func (r *studentFieldRepository) ReplaceGrades(ctx context.Context, businessID uint, grades []models.StudentGrade) error {
+	if businessID == 0 {
+		return fmt.Errorf("invalid business ID")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("business_id = ?", businessID).Delete(&models.StudentGrade{}).Error; err != nil {
+			return err
+		}
+		if len(grades) == 0 {
+			return nil
+		}
+		return tx.Create(&grades).Error
+	})
+}
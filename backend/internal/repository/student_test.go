@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"backend/internal/models"
+	"backend/internal/testutil"
+)
+
+func TestGetStudentStats(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	repo := NewStudentRepository(db)
+
+	tests := []struct {
+		name       string
+		businessID []uint
+		total      int64
+		active     int64
+		byGrade    map[string]int64
+		byGender   map[string]int64
+	}{
+		{
+			name:     "all businesses",
+			total:    5,
+			active:   4,
+			byGrade:  map[string]int64{"": 1, "8": 2, "9": 1, "10": 1},
+			byGender: map[string]int64{"male": 2, "female": 3},
+		},
+		{
+			name:       "one business",
+			businessID: []uint{f.Businesses["Sunrise Academy"].ID},
+			total:      4,
+			active:     3,
+			byGrade:    map[string]int64{"": 1, "8": 2, "9": 1},
+			byGender:   map[string]int64{"male": 2, "female": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Twice, as the counts once shared a query that each of them narrowed further
+			for run := 1; run <= 2; run++ {
+				stats, err := repo.GetStudentStats(context.Background(), tt.businessID...)
+				if err != nil {
+					t.Fatalf("GetStudentStats: %v", err)
+				}
+
+				want := map[string]interface{}{
+					"total_students":    tt.total,
+					"active_students":   tt.active,
+					"inactive_students": tt.total - tt.active,
+					"by_grade":          tt.byGrade,
+					"by_gender":         tt.byGender,
+					"age_unknown":       tt.total,
+				}
+				for key, value := range want {
+					if !reflect.DeepEqual(stats[key], value) {
+						t.Errorf("run %d: %s = %v, want %v", run, key, stats[key], value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestStudentGetAll(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	repo := NewStudentRepository(db)
+
+	sunrise := f.Businesses["Sunrise Academy"].ID
+	inactive := models.StatusInactive
+
+	tests := []struct {
+		name    string
+		filters StudentFilters
+		want    []string
+		total   int64
+	}{
+		{"all by name", StudentFilters{SortBy: "name"}, []string{"Aarav", "Bina", "Chen", "Diya", "Farah"}, 5},
+		{"descending", StudentFilters{SortBy: "name", SortOrder: "desc", Limit: 2}, []string{"Farah", "Diya"}, 5},
+		{"business", StudentFilters{BusinessID: &sunrise, SortBy: "name"}, []string{"Aarav", "Bina", "Chen", "Diya"}, 4},
+		{"status", StudentFilters{Status: &inactive}, []string{"Chen"}, 1},
+		{"grade", StudentFilters{Grade: "8", SortBy: "name"}, []string{"Aarav", "Bina"}, 2},
+		{"gender", StudentFilters{Gender: "male", SortBy: "name"}, []string{"Aarav", "Chen"}, 2},
+		{"search ignoring case", StudentFilters{Search: "AR", SortBy: "name"}, []string{"Aarav", "Farah"}, 2},
+		{"search within a business", StudentFilters{BusinessID: &sunrise, Search: "ar"}, []string{"Aarav"}, 1},
+		{"first page", StudentFilters{SortBy: "name", Page: 1, Limit: 2}, []string{"Aarav", "Bina"}, 5},
+		{"second page", StudentFilters{SortBy: "name", Page: 2, Limit: 2}, []string{"Chen", "Diya"}, 5},
+		{"last page", StudentFilters{SortBy: "name", Page: 3, Limit: 2}, []string{"Farah"}, 5},
+		{"past the last page", StudentFilters{SortBy: "name", Page: 4, Limit: 2}, []string{}, 5},
+		{"including deleted", StudentFilters{Grade: "9", IncludeDeleted: true, SortBy: "name"}, []string{"Chen", "Esha"}, 2},
+		{"unknown sort column", StudentFilters{SortBy: "password", Status: &inactive}, []string{"Chen"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			students, total, err := repo.GetAll(context.Background(), tt.filters)
+			if err != nil {
+				t.Fatalf("GetAll: %v", err)
+			}
+			if got := studentNames(students); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("students = %v, want %v", got, tt.want)
+			}
+			if total != tt.total {
+				t.Errorf("total = %d, want %d", total, tt.total)
+			}
+		})
+	}
+}
+
+func TestStudentGetAllWithRelations(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	repo := NewStudentRepository(db)
+
+	sunrise := f.Businesses["Sunrise Academy"].ID
+	students, total, err := repo.GetAllWithRelations(context.Background(), StudentFilters{
+		BusinessID: &sunrise,
+		Include:    "user,business",
+		SortBy:     "name",
+		Page:       2,
+		Limit:      3,
+	})
+	if err != nil {
+		t.Fatalf("GetAllWithRelations: %v", err)
+	}
+
+	// The total counts every match, not the page or the preloaded rows
+	if total != 4 {
+		t.Errorf("total = %d, want 4", total)
+	}
+	if got := studentNames(students); !reflect.DeepEqual(got, []string{"Diya"}) {
+		t.Fatalf("students = %v, want [Diya]", got)
+	}
+	if students[0].User.ID != f.Students["Diya"].UserID {
+		t.Errorf("user not preloaded: %+v", students[0].User)
+	}
+	if students[0].Business.Name != "Sunrise Academy" {
+		t.Errorf("business not preloaded: %+v", students[0].Business)
+	}
+}
+
+func studentNames(students []models.Student) []string {
+	names := make([]string, len(students))
+	for i, student := range students {
+		names[i] = student.Name
+	}
+	return names
+}
+
+// Each business and year counts on its own, one increment per call
+func TestNextCodeSequence(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	repo := NewStudentRepository(db)
+	sunrise, moonlight := f.Businesses["Sunrise Academy"].ID, f.Businesses["Moonlight Tutors"].ID
+
+	steps := []struct {
+		businessID uint
+		year       int
+		want       int64
+	}{
+		{sunrise, 2026, 1},
+		{sunrise, 2026, 2},
+		{sunrise, 2027, 1},
+		{moonlight, 2026, 1},
+		{sunrise, 2026, 3},
+		{sunrise, 0, 1},
+	}
+	for _, step := range steps {
+		got, err := repo.NextCodeSequenceWithTransaction(db, step.businessID, step.year)
+		if err != nil {
+			t.Fatalf("NextCodeSequenceWithTransaction(%d, %d) error = %v", step.businessID, step.year, err)
+		}
+		if got != step.want {
+			t.Errorf("NextCodeSequenceWithTransaction(%d, %d) = %d, want %d", step.businessID, step.year, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type SubjectRepository interface {
+	// Basic CRUD operations
+	Create(ctx context.Context, subject *models.Subject) error
+	GetByID(ctx context.Context, id uint) (*models.Subject, error)
+	GetByBusinessID(ctx context.Context, businessID uint) ([]models.Subject, error)
+	GetByIDsForBusiness(ctx context.Context, businessID uint, subjectIDs []uint) ([]models.Subject, error)
+	Update(ctx context.Context, subject *models.Subject) error
+	Delete(ctx context.Context, id uint) error
+
+	// Validation
+	SubjectNameExists(ctx context.Context, businessID uint, name string, excludeSubjectID ...uint) (bool, error)
+}
+
+type subjectRepository struct {
+	db *gorm.DB
+}
+
+func NewSubjectRepository(db *gorm.DB) SubjectRepository {
+	return &subjectRepository{
+		db: db,
+	}
+}
+
+func (r *subjectRepository) Create(ctx context.Context, subject *models.Subject) error {
+	if subject == nil {
+		return fmt.Errorf("subject cannot be nil")
+	}
+	return r.db.WithContext(ctx).Create(subject).Error
+}
+
+func (r *subjectRepository) GetByID(ctx context.Context, id uint) (*models.Subject, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid subject ID")
+	}
+
+	var subject models.Subject
+	err := r.db.WithContext(ctx).First(&subject, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subject, nil
+}
+
+func (r *subjectRepository) GetByBusinessID(ctx context.Context, businessID uint) ([]models.Subject, error) {
+	if businessID == 0 {
+		return nil, fmt.Errorf("invalid business ID")
+	}
+
+	var subjects []models.Subject
+	err := r.db.WithContext(ctx).Where("business_id = ?", businessID).Order("name ASC").Find(&subjects).Error
+	return subjects, err
+}
+
+func (r *subjectRepository) GetByIDsForBusiness(ctx context.Context, businessID uint, subjectIDs []uint) ([]models.Subject, error) {
+	if businessID == 0 {
+		return nil, fmt.Errorf("invalid business ID")
+	}
+	if len(subjectIDs) == 0 {
+		return []models.Subject{}, nil
+	}
+
+	var subjects []models.Subject
+	err := r.db.WithContext(ctx).Where("business_id = ? AND id IN ?", businessID, subjectIDs).Find(&subjects).Error
+	return subjects, err
+}
+
+func (r *subjectRepository) Update(ctx context.Context, subject *models.Subject) error {
+	if subject == nil {
+		return fmt.Errorf("subject cannot be nil")
+	}
+	if subject.ID == 0 {
+		return fmt.Errorf("subject ID cannot be zero")
+	}
+	return r.db.WithContext(ctx).Save(subject).Error
+}
+
+func (r *subjectRepository) Delete(ctx context.Context, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid subject ID")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Remove teacher assignments before the subject itself
+		if err := tx.Exec("DELETE FROM teacher_subjects WHERE subject_id = ?", id).Error; err != nil {
+			return err
+		}
+		// Exams keep their results without a subject
+		if err := tx.Model(&models.Exam{}).Where("subject_id = ?", id).Update("subject_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Subject{}, id).Error
+	})
+}
+
+func (r *subjectRepository) SubjectNameExists(ctx context.Context, businessID uint, name string, excludeSubjectID ...uint) (bool, error) {
+	if name == "" {
+		return false, fmt.Errorf("name cannot be empty")
+	}
+
+	var count int64
+	query := r.db.WithContext(ctx).Model(&models.Subject{}).Where("business_id = ? AND LOWER(name) = LOWER(?)", businessID, name)
+
+	if len(excludeSubjectID) > 0 && excludeSubjectID[0] > 0 {
+		query = query.Where("id != ?", excludeSubjectID[0])
+	}
+
+	err := query.Count(&count).Error
+	return count > 0, err
+}
@@ -2,85 +2,163 @@ package repository
 
 import (
 	"backend/internal/models"
-	"backend/pkg/database"
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
 type TeacherRepository interface {
 	// Basic CRUD operations
-	Create(teacher *models.Teacher) error
+	Create(ctx context.Context, teacher *models.Teacher) error
 	CreateWithTransaction(tx *gorm.DB, teacher *models.Teacher) error
-	GetByID(id uint) (*models.Teacher, error)
-	GetByUserID(userID uint) (*models.Teacher, error)
-	GetAll(filters TeacherFilters) ([]models.Teacher, int64, error)
-	GetAllWithRelations(filters TeacherFilters) ([]models.Teacher, int64, error)
-	Update(teacher *models.Teacher) error
+	GetByID(ctx context.Context, id uint) (*models.Teacher, error)
+	GetByUserID(ctx context.Context, userID uint) (*models.Teacher, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]models.Teacher, error)
+	GetAll(ctx context.Context, filters TeacherFilters) ([]models.Teacher, int64, error)
+	GetAllWithRelations(ctx context.Context, filters TeacherFilters) ([]models.Teacher, int64, error)
+	ForEachBatch(ctx context.Context, filters TeacherFilters, batchSize int, fn func([]models.Teacher) error) error
+	Update(ctx context.Context, teacher *models.Teacher) error
 	UpdateWithTransaction(tx *gorm.DB, teacher *models.Teacher) error
-	Delete(id uint) error
+	UpdateVersionedWithTransaction(tx *gorm.DB, teacher *models.Teacher, expectedVersion uint) error
+	Delete(ctx context.Context, id uint) error
+	DeleteWithTransaction(tx *gorm.DB, id uint) error
+	CountDependentRecords(ctx context.Context, id uint) (map[string]int64, error)
+
+	// Soft deletion
+	SoftDeleteWithTransaction(tx *gorm.DB, id uint) error
+	RestoreWithTransaction(tx *gorm.DB, id uint) error
+	GetByIDWithDeleted(ctx context.Context, id uint) (*models.Teacher, error)
+	GetDeletedBefore(ctx context.Context, cutoff time.Time) ([]models.Teacher, error)
 
 	// Business specific operations
-	GetByBusinessID(businessID uint, filters TeacherFilters) ([]models.Teacher, int64, error)
-	GetActiveTeachersByBusiness(businessID uint) ([]models.Teacher, error)
-	GetInactiveTeachersByBusiness(businessID uint) ([]models.Teacher, error)
+	GetByBusinessID(ctx context.Context, businessID uint, filters TeacherFilters) ([]models.Teacher, int64, error)
+	GetActiveTeachersByBusiness(ctx context.Context, businessID uint) ([]models.Teacher, error)
+
+	// UpdatePhotoPath points the teacher at a stored photo, or at none when path is empty
+	UpdatePhotoPath(ctx context.Context, id uint, path string) error
 
 	// Status operations
-	UpdateTeacherStatus(teacherID uint, status int) error
-	GetActiveTeachers() ([]models.Teacher, error)
-	GetInactiveTeachers() ([]models.Teacher, error)
+	UpdateTeacherStatusWithTransaction(tx *gorm.DB, teacherID uint, status models.Status) error
+	RecordStatusChangeWithTransaction(tx *gorm.DB, teacherID uint, status models.Status) error
 
 	// Search and filters
-	SearchTeachers(searchTerm string, limit int, businessID ...uint) ([]models.Teacher, error)
-	SearchTeachersByBusiness(businessID uint, searchTerm string, limit int) ([]models.Teacher, error)
+	SearchTeachers(ctx context.Context, searchTerm string, limit int, filters TeacherFilters) ([]models.Teacher, error)
+	SearchTeachersByBusiness(ctx context.Context, businessID uint, searchTerm string, limit int) ([]models.Teacher, error)
 
 	// Statistics
-	GetTeacherStats(businessID ...uint) (map[string]interface{}, error)
-	GetSalaryStats(businessID ...uint) (map[string]interface{}, error)
-	GetQualificationStats(businessID ...uint) (map[string]int64, error)
+	GetTeacherStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error)
+	GetSalaryStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error)
+	GetQualificationStats(ctx context.Context, businessID ...uint) (map[string]int64, error)
+	GetExperienceStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error)
+	GetSubjectStats(ctx context.Context, businessID ...uint) (map[uint]map[string]int64, error)
+	GetRecent(ctx context.Context, limit int, businessID ...uint) ([]models.Teacher, error)
+	CountAdditions(ctx context.Context, now time.Time, businessID ...uint) (*models.AdditionCounts, error)
 
 	// Relationships
-	GetTeacherWithRelations(id uint) (*models.Teacher, error)
+	GetTeacherWithRelations(ctx context.Context, id uint) (*models.Teacher, error)
+	ReplaceSubjects(ctx context.Context, teacherID uint, subjects []models.Subject) error
 
 	// Bulk operations
-	BulkUpdateStatus(teacherIDs []uint, status int) error
-	BulkUpdateSalary(teacherIDs []uint, salary float64) error
+	BulkUpdateStatusWithTransaction(tx *gorm.DB, teacherIDs []uint, status models.Status) error
+	BulkUpdateSalary(ctx context.Context, teacherIDs []uint, salary float64) error
+	BulkAdjustSalaryWithTransaction(tx *gorm.DB, teacherIDs []uint, adjustmentType string, value float64, changedAt time.Time) ([]models.SalaryAdjustmentResult, error)
+
+	// Transfers
+	ClearSubjectsWithTransaction(tx *gorm.DB, teacherID uint) error
+	CreateAssignmentHistoryWithTransaction(tx *gorm.DB, entry *models.TeacherAssignmentHistory) error
+	GetAssignmentHistory(ctx context.Context, teacherID uint) ([]models.TeacherAssignmentHistory, error)
+
+	// Salary history
+	CreateSalaryHistoryWithTransaction(tx *gorm.DB, entries []models.TeacherSalaryHistory) error
+	GetSalaryHistory(ctx context.Context, teacherID uint) ([]models.TeacherSalaryHistory, error)
 
 	// Validation
-	TeacherUserExists(userID uint, excludeTeacherID ...uint) (bool, error)
+	TeacherUserExists(ctx context.Context, userID uint, excludeTeacherID ...uint) (bool, error)
 
 	// Transaction support
-	BeginTransaction() *gorm.DB
+	BeginTransaction(ctx context.Context) *gorm.DB
 }
 
 type TeacherFilters struct {
-	BusinessID    *uint    `form:"business_id" json:"business_id"`
-	Status        *int     `form:"status" json:"status"`
-	MinSalary     *float64 `form:"min_salary" json:"min_salary"`
-	MaxSalary     *float64 `form:"max_salary" json:"max_salary"`
-	Qualification string   `form:"qualification" json:"qualification"`
-	Search        string   `form:"search" json:"search"`
-	Page          int      `form:"page" json:"page"`
-	Limit         int      `form:"limit" json:"limit"`
-	SortBy        string   `form:"sort_by" json:"sort_by"`
-	SortOrder     string   `form:"sort_order" json:"sort_order"`
+	BusinessID      *uint          `form:"business_id" json:"business_id"`
+	Status          *models.Status `form:"status" json:"status"`
+	MinSalary       *float64       `form:"min_salary" json:"min_salary"`
+	MaxSalary       *float64       `form:"max_salary" json:"max_salary"`
+	Qualification   string         `form:"qualification" json:"qualification"`
+	QualificationID *uint          `form:"qualification_id" json:"qualification_id"`
+	MinExperience   *float64       `form:"min_experience_years" json:"min_experience_years"`
+	MaxExperience   *float64       `form:"max_experience_years" json:"max_experience_years"`
+	SubjectID       *uint          `form:"subject_id" json:"subject_id"`
+	Subject         string         `form:"subject" json:"subject"`
+	Search          string         `form:"search" json:"search"`
+	Page            int            `form:"page" json:"page"`
+	Limit           int            `form:"limit" json:"limit"`
+	SortBy          string         `form:"sort_by" json:"sort_by"`
+	SortOrder       string         `form:"sort_order" json:"sort_order"`
+	Fields          string         `form:"fields" json:"fields"`                   // comma-separated response fields to return
+	Include         string         `form:"include" json:"include"`                 // comma-separated relations to load
+	Cursor          string         `form:"cursor" json:"cursor"`                   // next_cursor of the previous page, replaces page
+	WithTotal       *bool          `form:"with_total" json:"with_total"`           // false skips counting the total
+	Estimate        bool           `form:"estimate" json:"estimate"`               // estimate the total from table statistics, for large tables
+	IncludeDeleted  bool           `form:"include_deleted" json:"include_deleted"` // also list soft-deleted teachers, for admins
+}
+
+// teacherSortFields are the columns teachers can be sorted by
+var teacherSortFields = map[string]bool{
+	"created_on":            true,
+	"updated_on":            true,
+	"name":                  true,
+	"salary":                true,
+	"qualification":         true,
+	"experience_years":      true,
+	"status":                true,
+	"last_salary_change_at": true,
+}
+
+func (f TeacherFilters) totalMode() totalMode {
+	return newTotalMode(f.Cursor, f.WithTotal, f.Estimate)
+}
+
+func (f TeacherFilters) sort() listSort {
+	return resolveSort(f.SortBy, f.SortOrder, teacherSortFields)
+}
+
+// PageInfo builds the pagination metadata for teachers listed with these filters
+func (f TeacherFilters) PageInfo(total int64, teachers []models.Teacher) PageInfo {
+	return newPageInfo(total, teachers, f.sort(), f.Page, f.Limit, f.Cursor, f.totalMode())
+}
+
+// teacherRelations are the relations GetAllWithRelations can preload
+var teacherRelations = relationPreloads{
+	"user":              func(db *gorm.DB) *gorm.DB { return db.Preload("User") },
+	"business":          func(db *gorm.DB) *gorm.DB { return db.Preload("Business") },
+	"previous_business": func(db *gorm.DB) *gorm.DB { return db.Preload("PreviousBusiness") },
+	"subjects":          func(db *gorm.DB) *gorm.DB { return db.Preload("Subjects") },
+}
+
+// Selection parses the fields and include parameters against TeacherResponse
+func (f TeacherFilters) Selection() (Selection, error) {
+	return parseSelection(f.Fields, f.Include, models.TeacherResponse{}, teacherRelations)
 }
 
 type teacherRepository struct {
 	db *gorm.DB
 }
 
-func NewTeacherRepository() TeacherRepository {
+func NewTeacherRepository(db *gorm.DB) TeacherRepository {
 	return &teacherRepository{
-		db: database.DB,
+		db: db,
 	}
 }
 
-func (r *teacherRepository) Create(teacher *models.Teacher) error {
+func (r *teacherRepository) Create(ctx context.Context, teacher *models.Teacher) error {
 	if teacher == nil {
 		return fmt.Errorf("teacher cannot be nil")
 	}
-	return r.db.Create(teacher).Error
+	return r.db.WithContext(ctx).Create(teacher).Error
 }
 
 func (r *teacherRepository) CreateWithTransaction(tx *gorm.DB, teacher *models.Teacher) error {
@@ -90,111 +168,109 @@ func (r *teacherRepository) CreateWithTransaction(tx *gorm.DB, teacher *models.T
 	return tx.Create(teacher).Error
 }
 
-func (r *teacherRepository) GetByID(id uint) (*models.Teacher, error) {
+func (r *teacherRepository) GetByID(ctx context.Context, id uint) (*models.Teacher, error) {
 	if id == 0 {
 		return nil, fmt.Errorf("invalid teacher ID")
 	}
 
 	var teacher models.Teacher
-	err := r.db.First(&teacher, id).Error
+	err := r.db.WithContext(ctx).First(&teacher, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &teacher, nil
 }
 
-func (r *teacherRepository) GetByUserID(userID uint) (*models.Teacher, error) {
+func (r *teacherRepository) GetByUserID(ctx context.Context, userID uint) (*models.Teacher, error) {
 	if userID == 0 {
 		return nil, fmt.Errorf("invalid user ID")
 	}
 
 	var teacher models.Teacher
-	err := r.db.Where("user_id = ?", userID).First(&teacher).Error
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&teacher).Error
 	if err != nil {
 		return nil, err
 	}
 	return &teacher, nil
 }
 
-func (r *teacherRepository) GetAll(filters TeacherFilters) ([]models.Teacher, int64, error) {
-	var teachers []models.Teacher
-	var total int64
+func (r *teacherRepository) GetByIDs(ctx context.Context, ids []uint) ([]models.Teacher, error) {
+	if len(ids) == 0 {
+		return []models.Teacher{}, nil
+	}
 
-	query := r.db.Model(&models.Teacher{})
+	var teachers []models.Teacher
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&teachers).Error
+	return teachers, err
+}
 
-	// Apply filters
-	if filters.BusinessID != nil {
-		query = query.Where("business_id = ?", *filters.BusinessID)
-	}
+func (r *teacherRepository) GetAll(ctx context.Context, filters TeacherFilters) ([]models.Teacher, int64, error) {
+	var teachers []models.Teacher
 
-	if filters.Status != nil {
-		query = query.Where("status = ?", *filters.Status)
-	}
+	query := applyTeacherFilters(r.db.WithContext(ctx).Model(&models.Teacher{}), filters)
 
-	if filters.MinSalary != nil {
-		query = query.Where("salary >= ?", *filters.MinSalary)
+	// Count total first (before pagination), unless skipped
+	total, err := countTotal(query, filters.totalMode())
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if filters.MaxSalary != nil {
-		query = query.Where("salary <= ?", *filters.MaxSalary)
+	// Apply sorting and pagination
+	query, err = paginate(query, filters.sort(), filters.Page, filters.Limit, filters.Cursor)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if filters.Qualification != "" {
-		query = query.Where("qualification ILIKE ?", "%"+filters.Qualification+"%")
-	}
+	err = query.Find(&teachers).Error
+	return teachers, total, err
+}
 
-	if filters.Search != "" {
-		query = query.Where("name ILIKE ? OR qualification ILIKE ? OR experience ILIKE ?",
-			"%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%")
-	}
+func (r *teacherRepository) GetAllWithRelations(ctx context.Context, filters TeacherFilters) ([]models.Teacher, int64, error) {
+	var teachers []models.Teacher
 
-	// Count total
-	if err := query.Count(&total).Error; err != nil {
+	selection, err := filters.Selection()
+	if err != nil {
 		return nil, 0, err
 	}
+	query := applyTeacherFilters(r.db.WithContext(ctx).Model(&models.Teacher{}), filters)
 
-	// Apply sorting
-	orderBy := "created_on DESC"
-	if filters.SortBy != "" {
-		validSortFields := map[string]bool{
-			"created_on":    true,
-			"updated_on":    true,
-			"name":          true,
-			"salary":        true,
-			"qualification": true,
-			"experience":    true,
-			"status":        true,
-		}
-		if validSortFields[filters.SortBy] {
-			sortOrder := "DESC"
-			if filters.SortOrder == "asc" {
-				sortOrder = "ASC"
-			}
-			orderBy = fmt.Sprintf("%s %s", filters.SortBy, sortOrder)
-		}
+	// Count total first (before preloads and pagination), unless skipped
+	total, err := countTotal(query, filters.totalMode())
+	if err != nil {
+		return nil, 0, err
 	}
-	query = query.Order(orderBy)
+	query = selection.preload(query, teacherRelations)
 
-	// Apply pagination
-	if filters.Limit > 0 {
-		offset := 0
-		if filters.Page > 1 {
-			offset = (filters.Page - 1) * filters.Limit
-		}
-		query = query.Offset(offset).Limit(filters.Limit)
+	// Apply sorting and pagination
+	query, err = paginate(query, filters.sort(), filters.Page, filters.Limit, filters.Cursor)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	err := query.Find(&teachers).Error
+	err = query.Find(&teachers).Error
 	return teachers, total, err
 }
 
-func (r *teacherRepository) GetAllWithRelations(filters TeacherFilters) ([]models.Teacher, int64, error) {
-	var teachers []models.Teacher
-	var total int64
+// ForEachBatch calls fn with the teachers matching filters, batchSize at a time in id
+// order with their user and subjects loaded, so exports never hold every row in memory.
+// Sorting and pagination filters are ignored.
+func (r *teacherRepository) ForEachBatch(ctx context.Context, filters TeacherFilters, batchSize int, fn func([]models.Teacher) error) error {
+	var batch []models.Teacher
+	query := applyTeacherFilters(r.db.WithContext(ctx).Model(&models.Teacher{}), filters).
+		Preload("User").Preload("Subjects")
+
+	return query.FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
 
-	query := r.db.Model(&models.Teacher{}).Preload("User").Preload("Business")
+// applyTeacherFilters narrows query to the teachers matching filters, leaving out
+// sorting, pagination and preloads
+func applyTeacherFilters(query *gorm.DB, filters TeacherFilters) *gorm.DB {
+	if filters.IncludeDeleted {
+		query = query.Unscoped()
+	}
 
-	// Apply same filters as GetAll
 	if filters.BusinessID != nil {
 		query = query.Where("business_id = ?", *filters.BusinessID)
 	}
@@ -212,62 +288,40 @@ func (r *teacherRepository) GetAllWithRelations(filters TeacherFilters) ([]model
 	}
 
 	if filters.Qualification != "" {
-		query = query.Where("qualification ILIKE ?", "%"+filters.Qualification+"%")
+		query = query.Where(canonicalQualification+" ILIKE ?", "%"+filters.Qualification+"%")
 	}
 
-	if filters.Search != "" {
-		query = query.Where("name ILIKE ? OR qualification ILIKE ? OR experience ILIKE ?",
-			"%"+filters.Search+"%", "%"+filters.Search+"%", "%"+filters.Search+"%")
+	if filters.QualificationID != nil {
+		query = query.Where("qualification_id = ?", *filters.QualificationID)
 	}
 
-	// Count total
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	if filters.MinExperience != nil {
+		query = query.Where("experience_years >= ?", *filters.MinExperience)
 	}
 
-	// Apply sorting
-	orderBy := "created_on DESC"
-	if filters.SortBy != "" {
-		validSortFields := map[string]bool{
-			"created_on":    true,
-			"updated_on":    true,
-			"name":          true,
-			"salary":        true,
-			"qualification": true,
-			"experience":    true,
-			"status":        true,
-		}
-		if validSortFields[filters.SortBy] {
-			sortOrder := "DESC"
-			if filters.SortOrder == "asc" {
-				sortOrder = "ASC"
-			}
-			orderBy = fmt.Sprintf("%s %s", filters.SortBy, sortOrder)
-		}
+	if filters.MaxExperience != nil {
+		query = query.Where("experience_years <= ?", *filters.MaxExperience)
 	}
-	query = query.Order(orderBy)
 
-	// Apply pagination
-	if filters.Limit > 0 {
-		offset := 0
-		if filters.Page > 1 {
-			offset = (filters.Page - 1) * filters.Limit
-		}
-		query = query.Offset(offset).Limit(filters.Limit)
+	query = applySubjectFilter(query, filters)
+
+	if filters.Search != "" {
+		query = query.Where("name ILIKE ? OR qualification ILIKE ? OR "+teacherPhoneMatch,
+			"%"+filters.Search+"%", "%"+filters.Search+"%", searchPhone(filters.Search))
 	}
 
-	err := query.Find(&teachers).Error
-	return teachers, total, err
+	return query
 }
 
-func (r *teacherRepository) Update(teacher *models.Teacher) error {
+func (r *teacherRepository) Update(ctx context.Context, teacher *models.Teacher) error {
 	if teacher == nil {
 		return fmt.Errorf("teacher cannot be nil")
 	}
 	if teacher.ID == 0 {
 		return fmt.Errorf("teacher ID cannot be zero")
 	}
-	return r.db.Save(teacher).Error
+	teacher.Version++
+	return r.db.WithContext(ctx).Save(teacher).Error
 }
 
 func (r *teacherRepository) UpdateWithTransaction(tx *gorm.DB, teacher *models.Teacher) error {
@@ -277,80 +331,190 @@ func (r *teacherRepository) UpdateWithTransaction(tx *gorm.DB, teacher *models.T
 	if teacher.ID == 0 {
 		return fmt.Errorf("teacher ID cannot be zero")
 	}
+	teacher.Version++
 	return tx.Save(teacher).Error
 }
 
-func (r *teacherRepository) Delete(id uint) error {
+// UpdateVersionedWithTransaction saves the teacher only if it is still at expectedVersion,
+// returning ErrVersionConflict otherwise
+func (r *teacherRepository) UpdateVersionedWithTransaction(tx *gorm.DB, teacher *models.Teacher, expectedVersion uint) error {
+	if teacher == nil {
+		return fmt.Errorf("teacher cannot be nil")
+	}
+	if teacher.ID == 0 {
+		return fmt.Errorf("teacher ID cannot be zero")
+	}
+	return saveVersioned(tx, teacher, &teacher.Version, expectedVersion)
+}
+
+func (r *teacherRepository) Delete(ctx context.Context, id uint) error {
 	if id == 0 {
 		return fmt.Errorf("invalid teacher ID")
 	}
-	return r.db.Delete(&models.Teacher{}, id).Error
+	return r.db.WithContext(ctx).Delete(&models.Teacher{}, id).Error
 }
 
-func (r *teacherRepository) GetByBusinessID(businessID uint, filters TeacherFilters) ([]models.Teacher, int64, error) {
-	if businessID == 0 {
-		return nil, 0, fmt.Errorf("invalid business ID")
+// DeleteWithTransaction permanently removes a teacher, soft-deleted or not, together
+// with everything that references it, so the foreign keys never block the delete
+func (r *teacherRepository) DeleteWithTransaction(tx *gorm.DB, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid teacher ID")
 	}
 
-	filters.BusinessID = &businessID
-	return r.GetAll(filters)
+	dependents := []interface{}{
+		&models.TeacherAvailability{},
+		&models.TeacherDocument{},
+		&models.TeacherAttendance{},
+		&models.PayrollAdjustment{},
+		&models.TeacherSalaryHistory{},
+		&models.TeacherAssignmentHistory{},
+		&models.TeacherStudent{},
+	}
+	for _, model := range dependents {
+		if err := tx.Where("teacher_id = ?", id).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := r.ClearSubjectsWithTransaction(tx, id); err != nil {
+		return err
+	}
+
+	// Batches keep running without an assigned teacher
+	if err := tx.Model(&models.Batch{}).Where("teacher_id = ?", id).Update("teacher_id", nil).Error; err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Delete(&models.Teacher{}, id).Error
 }
 
-func (r *teacherRepository) GetActiveTeachersByBusiness(businessID uint) ([]models.Teacher, error) {
-	if businessID == 0 {
-		return nil, fmt.Errorf("invalid business ID")
+// CountDependentRecords counts the records that should not disappear silently
+// when a teacher is deleted
+func (r *teacherRepository) CountDependentRecords(ctx context.Context, id uint) (map[string]int64, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid teacher ID")
+	}
+
+	counts := make(map[string]int64)
+
+	var attendance int64
+	if err := r.db.WithContext(ctx).Model(&models.TeacherAttendance{}).Where("teacher_id = ?", id).Count(&attendance).Error; err != nil {
+		return nil, err
+	}
+	counts["attendance"] = attendance
+
+	var documents int64
+	if err := r.db.WithContext(ctx).Model(&models.TeacherDocument{}).Where("teacher_id = ?", id).Count(&documents).Error; err != nil {
+		return nil, err
 	}
+	counts["documents"] = documents
 
+	return counts, nil
+}
+
+// SoftDeleteWithTransaction hides a teacher from every query while keeping its
+// attendance, documents and history, so it can be restored
+func (r *teacherRepository) SoftDeleteWithTransaction(tx *gorm.DB, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid teacher ID")
+	}
+	return tx.Delete(&models.Teacher{}, id).Error
+}
+
+func (r *teacherRepository) RestoreWithTransaction(tx *gorm.DB, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid teacher ID")
+	}
+	return tx.Unscoped().Model(&models.Teacher{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "version": bumpVersion}).Error
+}
+
+// GetByIDWithDeleted returns a teacher whether or not it is soft-deleted
+func (r *teacherRepository) GetByIDWithDeleted(ctx context.Context, id uint) (*models.Teacher, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid teacher ID")
+	}
+
+	var teacher models.Teacher
+	err := r.db.WithContext(ctx).Unscoped().First(&teacher, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &teacher, nil
+}
+
+// GetDeletedBefore returns the teachers soft-deleted before cutoff
+func (r *teacherRepository) GetDeletedBefore(ctx context.Context, cutoff time.Time) ([]models.Teacher, error) {
 	var teachers []models.Teacher
-	err := r.db.Where("business_id = ? AND status = 1", businessID).Find(&teachers).Error
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Order("id ASC").
+		Find(&teachers).Error
 	return teachers, err
 }
 
-func (r *teacherRepository) GetInactiveTeachersByBusiness(businessID uint) ([]models.Teacher, error) {
+func (r *teacherRepository) GetByBusinessID(ctx context.Context, businessID uint, filters TeacherFilters) ([]models.Teacher, int64, error) {
+	if businessID == 0 {
+		return nil, 0, fmt.Errorf("invalid business ID")
+	}
+
+	filters.BusinessID = &businessID
+	return r.GetAll(ctx, filters)
+}
+
+func (r *teacherRepository) GetActiveTeachersByBusiness(ctx context.Context, businessID uint) ([]models.Teacher, error) {
 	if businessID == 0 {
 		return nil, fmt.Errorf("invalid business ID")
 	}
 
 	var teachers []models.Teacher
-	err := r.db.Where("business_id = ? AND status = 0", businessID).Find(&teachers).Error
+	err := r.db.WithContext(ctx).Where("business_id = ? AND status = 1", businessID).Find(&teachers).Error
 	return teachers, err
 }
 
-func (r *teacherRepository) UpdateTeacherStatus(teacherID uint, status int) error {
+func (r *teacherRepository) UpdatePhotoPath(ctx context.Context, id uint, path string) error {
+	// Bumps updated_on, which versions the photo URL, but not the version edits are checked against
+	result := r.db.WithContext(ctx).Model(&models.Teacher{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"photo_path": path, "updated_on": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("teacher not found")
+	}
+	return nil
+}
+
+func (r *teacherRepository) UpdateTeacherStatusWithTransaction(tx *gorm.DB, teacherID uint, status models.Status) error {
 	if teacherID == 0 {
 		return fmt.Errorf("invalid teacher ID")
 	}
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return fmt.Errorf("invalid status value")
 	}
 
-	return r.db.Model(&models.Teacher{}).Where("id = ?", teacherID).Update("status", status).Error
-}
-
-func (r *teacherRepository) GetActiveTeachers() ([]models.Teacher, error) {
-	var teachers []models.Teacher
-	err := r.db.Where("status = 1").Find(&teachers).Error
-	return teachers, err
+	return updateStatus(tx, "teacher", models.StatusEntityTeacher, []uint{teacherID}, status)
 }
 
-func (r *teacherRepository) GetInactiveTeachers() ([]models.Teacher, error) {
-	var teachers []models.Teacher
-	err := r.db.Where("status = 0").Find(&teachers).Error
-	return teachers, err
+// RecordStatusChangeWithTransaction records a status change saved along with other updates
+func (r *teacherRepository) RecordStatusChangeWithTransaction(tx *gorm.DB, teacherID uint, status models.Status) error {
+	return recordStatusChanges(tx, models.StatusEntityTeacher, []uint{teacherID}, status)
 }
 
-func (r *teacherRepository) SearchTeachers(searchTerm string, limit int, businessID ...uint) ([]models.Teacher, error) {
+func (r *teacherRepository) SearchTeachers(ctx context.Context, searchTerm string, limit int, filters TeacherFilters) ([]models.Teacher, error) {
 	if searchTerm == "" {
 		return []models.Teacher{}, nil
 	}
 
-	query := r.db.Where("name ILIKE ? OR qualification ILIKE ? OR experience ILIKE ?",
-		"%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%")
+	query := r.db.WithContext(ctx).Where("name ILIKE ? OR qualification ILIKE ? OR "+teacherPhoneMatch,
+		"%"+searchTerm+"%", "%"+searchTerm+"%", searchPhone(searchTerm))
 
-	if len(businessID) > 0 && businessID[0] > 0 {
-		query = query.Where("business_id = ?", businessID[0])
+	if filters.BusinessID != nil && *filters.BusinessID > 0 {
+		query = query.Where("business_id = ?", *filters.BusinessID)
 	}
 
+	query = applySubjectFilter(query, filters)
+
 	query = query.Order("created_on DESC")
 
 	if limit > 0 {
@@ -362,17 +526,19 @@ func (r *teacherRepository) SearchTeachers(searchTerm string, limit int, busines
 	return teachers, err
 }
 
-func (r *teacherRepository) SearchTeachersByBusiness(businessID uint, searchTerm string, limit int) ([]models.Teacher, error) {
-	return r.SearchTeachers(searchTerm, limit, businessID)
+func (r *teacherRepository) SearchTeachersByBusiness(ctx context.Context, businessID uint, searchTerm string, limit int) ([]models.Teacher, error) {
+	return r.SearchTeachers(ctx, searchTerm, limit, TeacherFilters{BusinessID: &businessID})
 }
 
-func (r *teacherRepository) GetTeacherStats(businessID ...uint) (map[string]interface{}, error) {
+func (r *teacherRepository) GetTeacherStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
-	query := r.db.Model(&models.Teacher{})
+	query := r.db.WithContext(ctx).Model(&models.Teacher{})
 	if len(businessID) > 0 && businessID[0] > 0 {
 		query = query.Where("business_id = ?", businessID[0])
 	}
+	// Each count gets its own session so conditions added for one don't leak into the others
+	query = query.Session(&gorm.Session{})
 
 	// Total teachers
 	var totalTeachers int64
@@ -383,8 +549,7 @@ func (r *teacherRepository) GetTeacherStats(businessID ...uint) (map[string]inte
 
 	// Active teachers
 	var activeTeachers int64
-	activeQuery := query
-	if err := activeQuery.Where("status = 1").Count(&activeTeachers).Error; err != nil {
+	if err := query.Where("status = 1").Count(&activeTeachers).Error; err != nil {
 		return nil, err
 	}
 	stats["active_teachers"] = activeTeachers
@@ -395,22 +560,76 @@ func (r *teacherRepository) GetTeacherStats(businessID ...uint) (map[string]inte
 	return stats, nil
 }
 
-func (r *teacherRepository) GetSalaryStats(businessID ...uint) (map[string]interface{}, error) {
-	type SalaryStats struct {
-		MinSalary float64 `json:"min_salary"`
-		MaxSalary float64 `json:"max_salary"`
-		AvgSalary float64 `json:"avg_salary"`
-	}
+// salaryBucketEdges are the lower bounds of the salary histogram buckets
+var salaryBucketEdges = []float64{0, 10000, 25000, 50000, 75000, 100000}
 
-	query := r.db.Model(&models.Teacher{})
-	if len(businessID) > 0 && businessID[0] > 0 {
-		query = query.Where("business_id = ?", businessID[0])
+func (r *teacherRepository) GetSalaryStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error) {
+	type SalaryStats struct {
+		MinSalary    float64 `json:"min_salary"`
+		MaxSalary    float64 `json:"max_salary"`
+		AvgSalary    float64 `json:"avg_salary"`
+		MedianSalary float64 `json:"median_salary"`
+		P25Salary    float64 `json:"p25_salary" gorm:"column:p25_salary"`
+		P75Salary    float64 `json:"p75_salary" gorm:"column:p75_salary"`
+	}
+
+	scoped := func() *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&models.Teacher{})
+		if len(businessID) > 0 && businessID[0] > 0 {
+			query = query.Where("business_id = ?", businessID[0])
+		}
+		return query
 	}
 
 	var stats SalaryStats
-	err := query.Select("MIN(salary) as min_salary, MAX(salary) as max_salary, AVG(salary) as avg_salary").
+	err := scoped().Select(`
+		COALESCE(MIN(salary), 0) as min_salary,
+		COALESCE(MAX(salary), 0) as max_salary,
+		COALESCE(AVG(salary), 0) as avg_salary,
+		COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY salary), 0) as median_salary,
+		COALESCE(percentile_cont(0.25) WITHIN GROUP (ORDER BY salary), 0) as p25_salary,
+		COALESCE(percentile_cont(0.75) WITHIN GROUP (ORDER BY salary), 0) as p75_salary`).
 		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Histogram in one grouped query; width_bucket returns the 1-based index
+	// of the last edge that is <= salary
+	var bucketCounts []struct {
+		Bucket int
+		Count  int64
+	}
+	err = scoped().Select("width_bucket(salary, ?::numeric[]) as bucket, COUNT(*) as count", pq.Array(salaryBucketEdges)).
+		Where("salary IS NOT NULL").
+		Group("bucket").
+		Scan(&bucketCounts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]models.SalaryBucket, len(salaryBucketEdges))
+	for i, edge := range salaryBucketEdges {
+		buckets[i] = models.SalaryBucket{Min: edge}
+		if i+1 < len(salaryBucketEdges) {
+			upper := salaryBucketEdges[i+1]
+			buckets[i].Max = &upper
+			buckets[i].Label = fmt.Sprintf("%.0f-%.0f", edge, upper)
+		} else {
+			buckets[i].Label = fmt.Sprintf("%.0f+", edge)
+		}
+	}
+	for _, bucketCount := range bucketCounts {
+		if bucketCount.Bucket >= 1 && bucketCount.Bucket <= len(buckets) {
+			buckets[bucketCount.Bucket-1].Count = bucketCount.Count
+		}
+	}
 
+	var byQualification []models.QualificationSalary
+	err = scoped().Select("COALESCE(" + canonicalQualification + ", 'unspecified') as qualification, COUNT(*) as count, AVG(salary) as avg_salary").
+		Group("1").
+		Order("avg_salary DESC").
+		Scan(&byQualification).Error
 	if err != nil {
 		return nil, err
 	}
@@ -419,25 +638,36 @@ func (r *teacherRepository) GetSalaryStats(businessID ...uint) (map[string]inter
 	result["min_salary"] = stats.MinSalary
 	result["max_salary"] = stats.MaxSalary
 	result["avg_salary"] = stats.AvgSalary
+	result["median_salary"] = stats.MedianSalary
+	result["p25_salary"] = stats.P25Salary
+	result["p75_salary"] = stats.P75Salary
+	result["buckets"] = buckets
+	result["by_qualification"] = byQualification
 
 	return result, nil
 }
 
-func (r *teacherRepository) GetQualificationStats(businessID ...uint) (map[string]int64, error) {
+// canonicalQualification is a teacher's taxonomy entry name, falling back to the legacy
+// free-text qualification for teachers not mapped to an entry yet
+const canonicalQualification = "COALESCE((SELECT q.name FROM qualification q WHERE q.id = teacher.qualification_id), NULLIF(teacher.qualification, ''))"
+
+// GetQualificationStats counts teachers per qualification, grouping mapped teachers by
+// their taxonomy entry so spellings of the same qualification count together
+func (r *teacherRepository) GetQualificationStats(ctx context.Context, businessID ...uint) (map[string]int64, error) {
 	type QualificationStat struct {
 		Qualification string `json:"qualification"`
 		Count         int64  `json:"count"`
 	}
 
-	query := r.db.Model(&models.Teacher{})
+	query := r.db.WithContext(ctx).Model(&models.Teacher{})
 	if len(businessID) > 0 && businessID[0] > 0 {
 		query = query.Where("business_id = ?", businessID[0])
 	}
 
 	var stats []QualificationStat
-	err := query.Select("qualification, COUNT(*) as count").
-		Where("qualification IS NOT NULL AND qualification != ''").
-		Group("qualification").
+	err := query.Select(canonicalQualification + " as qualification, COUNT(*) as count").
+		Where(canonicalQualification + " IS NOT NULL").
+		Group("1").
 		Order("count DESC").
 		Scan(&stats).Error
 
@@ -453,33 +683,123 @@ func (r *teacherRepository) GetQualificationStats(businessID ...uint) (map[strin
 	return result, nil
 }
 
-func (r *teacherRepository) GetTeacherWithRelations(id uint) (*models.Teacher, error) {
+func (r *teacherRepository) GetExperienceStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error) {
+	type ExperienceStats struct {
+		AvgYears     float64 `json:"avg_years"`
+		MinYears     float64 `json:"min_years"`
+		MaxYears     float64 `json:"max_years"`
+		Under2       int64   `json:"under_2" gorm:"column:under_2"`
+		From2To5     int64   `json:"from_2_to_5" gorm:"column:from_2_to_5"`
+		From5To10    int64   `json:"from_5_to_10" gorm:"column:from_5_to_10"`
+		Over10       int64   `json:"over_10" gorm:"column:over_10"`
+		NotSpecified int64   `json:"not_specified"`
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.Teacher{})
+	if len(businessID) > 0 && businessID[0] > 0 {
+		query = query.Where("business_id = ?", businessID[0])
+	}
+
+	var stats ExperienceStats
+	err := query.Select(`
+		COALESCE(AVG(experience_years), 0) as avg_years,
+		COALESCE(MIN(experience_years), 0) as min_years,
+		COALESCE(MAX(experience_years), 0) as max_years,
+		COUNT(*) FILTER (WHERE experience_years < 2) as under_2,
+		COUNT(*) FILTER (WHERE experience_years >= 2 AND experience_years < 5) as from_2_to_5,
+		COUNT(*) FILTER (WHERE experience_years >= 5 AND experience_years < 10) as from_5_to_10,
+		COUNT(*) FILTER (WHERE experience_years >= 10) as over_10,
+		COUNT(*) FILTER (WHERE experience_years IS NULL) as not_specified`).
+		Scan(&stats).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{})
+	result["avg_years"] = stats.AvgYears
+	result["min_years"] = stats.MinYears
+	result["max_years"] = stats.MaxYears
+	result["buckets"] = map[string]int64{
+		"0-2":  stats.Under2,
+		"2-5":  stats.From2To5,
+		"5-10": stats.From5To10,
+		"10+":  stats.Over10,
+	}
+	result["not_specified"] = stats.NotSpecified
+
+	return result, nil
+}
+
+func (r *teacherRepository) GetSubjectStats(ctx context.Context, businessID ...uint) (map[uint]map[string]int64, error) {
+	type SubjectStat struct {
+		BusinessID uint   `json:"business_id"`
+		Subject    string `json:"subject"`
+		Count      int64  `json:"count"`
+	}
+
+	query := r.db.WithContext(ctx).Table("subject").
+		Select("subject.business_id, subject.name as subject, COUNT(teacher.id) as count").
+		Joins("LEFT JOIN teacher_subjects ON teacher_subjects.subject_id = subject.id").
+		Joins("LEFT JOIN teacher ON teacher.id = teacher_subjects.teacher_id AND teacher.deleted_at IS NULL")
+	if len(businessID) > 0 && businessID[0] > 0 {
+		query = query.Where("subject.business_id = ?", businessID[0])
+	}
+
+	var stats []SubjectStat
+	err := query.Group("subject.business_id, subject.name").
+		Order("count DESC").
+		Scan(&stats).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]map[string]int64)
+	for _, stat := range stats {
+		if result[stat.BusinessID] == nil {
+			result[stat.BusinessID] = make(map[string]int64)
+		}
+		result[stat.BusinessID][stat.Subject] = stat.Count
+	}
+
+	return result, nil
+}
+
+func (r *teacherRepository) GetTeacherWithRelations(ctx context.Context, id uint) (*models.Teacher, error) {
 	if id == 0 {
 		return nil, fmt.Errorf("invalid teacher ID")
 	}
 
 	var teacher models.Teacher
-	err := r.db.Preload("User").Preload("Business").First(&teacher, id).Error
+	err := r.db.WithContext(ctx).Preload("User").Preload("Business").Preload("PreviousBusiness").Preload("Subjects").First(&teacher, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &teacher, nil
 }
 
-func (r *teacherRepository) BulkUpdateStatus(teacherIDs []uint, status int) error {
+func (r *teacherRepository) ReplaceSubjects(ctx context.Context, teacherID uint, subjects []models.Subject) error {
+	if teacherID == 0 {
+		return fmt.Errorf("invalid teacher ID")
+	}
+
+	teacher := models.Teacher{ID: teacherID}
+	return r.db.WithContext(ctx).Model(&teacher).Association("Subjects").Replace(subjects)
+}
+
+func (r *teacherRepository) BulkUpdateStatusWithTransaction(tx *gorm.DB, teacherIDs []uint, status models.Status) error {
 	if len(teacherIDs) == 0 {
 		return fmt.Errorf("no teacher IDs provided")
 	}
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return fmt.Errorf("invalid status value")
 	}
 
-	return r.db.Model(&models.Teacher{}).
-		Where("id IN ?", teacherIDs).
-		Update("status", status).Error
+	return updateStatus(tx, "teacher", models.StatusEntityTeacher, teacherIDs, status)
 }
 
-func (r *teacherRepository) BulkUpdateSalary(teacherIDs []uint, salary float64) error {
+func (r *teacherRepository) BulkUpdateSalary(ctx context.Context, teacherIDs []uint, salary float64) error {
 	if len(teacherIDs) == 0 {
 		return fmt.Errorf("no teacher IDs provided")
 	}
@@ -487,18 +807,117 @@ func (r *teacherRepository) BulkUpdateSalary(teacherIDs []uint, salary float64)
 		return fmt.Errorf("invalid salary value")
 	}
 
-	return r.db.Model(&models.Teacher{}).
+	return r.db.WithContext(ctx).Model(&models.Teacher{}).
 		Where("id IN ?", teacherIDs).
-		Update("salary", salary).Error
+		Updates(map[string]interface{}{"salary": salary, "version": bumpVersion}).Error
+}
+
+// BulkAdjustSalaryWithTransaction applies a salary adjustment to all given teachers in a
+// single UPDATE and returns the salary of each teacher before and after the change
+func (r *teacherRepository) BulkAdjustSalaryWithTransaction(tx *gorm.DB, teacherIDs []uint, adjustmentType string, value float64, changedAt time.Time) ([]models.SalaryAdjustmentResult, error) {
+	if len(teacherIDs) == 0 {
+		return nil, fmt.Errorf("no teacher IDs provided")
+	}
+
+	var expr string
+	switch adjustmentType {
+	case models.SalaryAdjustmentSet:
+		expr = "CAST(? AS decimal(10,2))"
+	case models.SalaryAdjustmentIncreasePercent:
+		expr = "ROUND(old.salary * (100 + CAST(? AS numeric)) / 100, 2)"
+	case models.SalaryAdjustmentIncreaseAmount:
+		expr = "old.salary + CAST(? AS numeric)"
+	default:
+		return nil, fmt.Errorf("invalid adjustment type")
+	}
+
+	// Reject the whole batch if any teacher would end up with a negative salary. The rows
+	// are locked while they are checked, so the salaries can't change before the update.
+	var negative int64
+	err := tx.Raw(`
+		SELECT COUNT(*)
+		FROM (SELECT id, COALESCE(salary, 0) AS salary FROM teacher WHERE id IN ? ORDER BY id FOR UPDATE) AS old
+		WHERE `+expr+` < 0
+	`, teacherIDs, value).Scan(&negative).Error
+	if err != nil {
+		return nil, err
+	}
+	if negative > 0 {
+		return nil, fmt.Errorf("adjustment would result in a negative salary for %d teacher(s)", negative)
+	}
+
+	var results []models.SalaryAdjustmentResult
+	err = tx.Raw(`
+		UPDATE teacher AS t
+		SET salary = `+expr+`,
+			last_salary_change_at = CASE WHEN `+expr+` <> old.salary THEN ? ELSE t.last_salary_change_at END,
+			version = t.version + 1,
+			updated_on = ?
+		FROM (SELECT id, COALESCE(salary, 0) AS salary FROM teacher WHERE id IN ? FOR UPDATE) AS old
+		WHERE t.id = old.id
+		RETURNING t.id AS teacher_id, old.salary AS old_salary, t.salary AS new_salary
+	`, value, value, changedAt, changedAt, teacherIDs).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r *teacherRepository) ClearSubjectsWithTransaction(tx *gorm.DB, teacherID uint) error {
+	if teacherID == 0 {
+		return fmt.Errorf("invalid teacher ID")
+	}
+	return tx.Exec("DELETE FROM teacher_subjects WHERE teacher_id = ?", teacherID).Error
+}
+
+func (r *teacherRepository) CreateAssignmentHistoryWithTransaction(tx *gorm.DB, entry *models.TeacherAssignmentHistory) error {
+	if entry == nil {
+		return fmt.Errorf("assignment history entry cannot be nil")
+	}
+	return tx.Create(entry).Error
+}
+
+func (r *teacherRepository) GetAssignmentHistory(ctx context.Context, teacherID uint) ([]models.TeacherAssignmentHistory, error) {
+	if teacherID == 0 {
+		return nil, fmt.Errorf("invalid teacher ID")
+	}
+
+	var history []models.TeacherAssignmentHistory
+	err := r.db.WithContext(ctx).Preload("FromBusiness").Preload("ToBusiness").
+		Where("teacher_id = ?", teacherID).
+		Order("transferred_at DESC, id DESC").
+		Find(&history).Error
+	return history, err
+}
+
+func (r *teacherRepository) CreateSalaryHistoryWithTransaction(tx *gorm.DB, entries []models.TeacherSalaryHistory) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return tx.Create(&entries).Error
+}
+
+func (r *teacherRepository) GetSalaryHistory(ctx context.Context, teacherID uint) ([]models.TeacherSalaryHistory, error) {
+	if teacherID == 0 {
+		return nil, fmt.Errorf("invalid teacher ID")
+	}
+
+	var history []models.TeacherSalaryHistory
+	err := r.db.WithContext(ctx).Where("teacher_id = ?", teacherID).
+		Order("effective_date DESC, id DESC").
+		Find(&history).Error
+	return history, err
 }
 
-func (r *teacherRepository) TeacherUserExists(userID uint, excludeTeacherID ...uint) (bool, error) {
+func (r *teacherRepository) TeacherUserExists(ctx context.Context, userID uint, excludeTeacherID ...uint) (bool, error) {
 	if userID == 0 {
 		return false, fmt.Errorf("user ID cannot be zero")
 	}
 
+	// A soft-deleted teacher still holds its user
 	var count int64
-	query := r.db.Model(&models.Teacher{}).Where("user_id = ?", userID)
+	query := r.db.WithContext(ctx).Unscoped().Model(&models.Teacher{}).Where("user_id = ?", userID)
 
 	if len(excludeTeacherID) > 0 && excludeTeacherID[0] > 0 {
 		query = query.Where("id != ?", excludeTeacherID[0])
@@ -508,6 +927,42 @@ func (r *teacherRepository) TeacherUserExists(userID uint, excludeTeacherID ...u
 	return count > 0, err
 }
 
-func (r *teacherRepository) BeginTransaction() *gorm.DB {
-	return r.db.Begin()
+func (r *teacherRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
+}
+
+// applySubjectFilter restricts a teacher query to teachers assigned a matching subject
+func applySubjectFilter(query *gorm.DB, filters TeacherFilters) *gorm.DB {
+	if filters.SubjectID != nil {
+		query = query.Where("id IN (SELECT teacher_id FROM teacher_subjects WHERE subject_id = ?)", *filters.SubjectID)
+	}
+
+	if filters.Subject != "" {
+		query = query.Where(`id IN (SELECT teacher_subjects.teacher_id FROM teacher_subjects
+			JOIN subject ON subject.id = teacher_subjects.subject_id
+			WHERE subject.name ILIKE ?)`, "%"+filters.Subject+"%")
+	}
+
+	return query
+}
+
+// GetRecent returns the latest teachers added, with only their business's name loaded
+func (r *teacherRepository) GetRecent(ctx context.Context, limit int, businessID ...uint) ([]models.Teacher, error) {
+	query := r.db.WithContext(ctx).Preload("Business", withBusinessName)
+	if len(businessID) > 0 && businessID[0] > 0 {
+		query = query.Where("business_id = ?", businessID[0])
+	}
+
+	var teachers []models.Teacher
+	err := latestFirst(query, limit).Find(&teachers).Error
+	return teachers, err
+}
+
+// CountAdditions counts the teachers added in the 7 and 30 days before now
+func (r *teacherRepository) CountAdditions(ctx context.Context, now time.Time, businessID ...uint) (*models.AdditionCounts, error) {
+	query := r.db.WithContext(ctx).Model(&models.Teacher{})
+	if len(businessID) > 0 && businessID[0] > 0 {
+		query = query.Where("business_id = ?", businessID[0])
+	}
+	return countAdditions(query, now)
 }
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TeacherAttendanceRepository interface {
+	// Marking attendance
+	Upsert(ctx context.Context, attendance *models.TeacherAttendance) error
+	UpsertWithTransaction(tx *gorm.DB, records []models.TeacherAttendance) error
+
+	// Queries
+	GetAll(ctx context.Context, filters TeacherAttendanceFilters) ([]models.TeacherAttendance, int64, error)
+	GetStatusCounts(ctx context.Context, filters TeacherAttendanceFilters) ([]TeacherAttendanceCount, error)
+
+	// Transaction support
+	BeginTransaction(ctx context.Context) *gorm.DB
+}
+
+type TeacherAttendanceFilters struct {
+	BusinessID *uint  `form:"business_id" json:"business_id"`
+	TeacherID  *uint  `form:"teacher_id" json:"teacher_id"`
+	Status     string `form:"status" json:"status" binding:"omitempty,oneof=present absent leave"`
+	From       string `form:"from" json:"from"` // YYYY-MM-DD, inclusive
+	To         string `form:"to" json:"to"`     // YYYY-MM-DD, inclusive
+	Page       int    `form:"page" json:"page"`
+	Limit      int    `form:"limit" json:"limit"`
+}
+
+// TeacherAttendanceCount is the number of days a teacher was marked with a status
+type TeacherAttendanceCount struct {
+	TeacherID uint
+	Status    string
+	Count     int64
+}
+
+type teacherAttendanceRepository struct {
+	db *gorm.DB
+}
+
+func NewTeacherAttendanceRepository(db *gorm.DB) TeacherAttendanceRepository {
+	return &teacherAttendanceRepository{
+		db: db,
+	}
+}
+
+// attendanceUpsert overwrites the existing record for the same teacher and day
+var attendanceUpsert = clause.OnConflict{
+	Columns:   []clause.Column{{Name: "teacher_id"}, {Name: "date"}},
+	DoUpdates: clause.AssignmentColumns([]string{"status", "note", "marked_by", "business_id", "updated_on"}),
+}
+
+func (r *teacherAttendanceRepository) Upsert(ctx context.Context, attendance *models.TeacherAttendance) error {
+	if attendance == nil {
+		return fmt.Errorf("attendance cannot be nil")
+	}
+	return r.db.WithContext(ctx).Clauses(attendanceUpsert).Create(attendance).Error
+}
+
+func (r *teacherAttendanceRepository) UpsertWithTransaction(tx *gorm.DB, records []models.TeacherAttendance) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return tx.Clauses(attendanceUpsert).Create(&records).Error
+}
+
+func (r *teacherAttendanceRepository) GetAll(ctx context.Context, filters TeacherAttendanceFilters) ([]models.TeacherAttendance, int64, error) {
+	var records []models.TeacherAttendance
+	var total int64
+
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&models.TeacherAttendance{}), filters)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Preload("Teacher").Order("date DESC, teacher_id ASC")
+
+	// Apply pagination
+	if filters.Limit > 0 {
+		offset := 0
+		if filters.Page > 1 {
+			offset = (filters.Page - 1) * filters.Limit
+		}
+		query = query.Offset(offset).Limit(filters.Limit)
+	}
+
+	err := query.Find(&records).Error
+	return records, total, err
+}
+
+func (r *teacherAttendanceRepository) GetStatusCounts(ctx context.Context, filters TeacherAttendanceFilters) ([]TeacherAttendanceCount, error) {
+	var counts []TeacherAttendanceCount
+
+	err := r.applyFilters(r.db.WithContext(ctx).Model(&models.TeacherAttendance{}), filters).
+		Select("teacher_id, status, COUNT(*) as count").
+		Group("teacher_id, status").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *teacherAttendanceRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
+}
+
+func (r *teacherAttendanceRepository) applyFilters(query *gorm.DB, filters TeacherAttendanceFilters) *gorm.DB {
+	if filters.BusinessID != nil {
+		query = query.Where("business_id = ?", *filters.BusinessID)
+	}
+	if filters.TeacherID != nil {
+		query = query.Where("teacher_id = ?", *filters.TeacherID)
+	}
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.From != "" {
+		query = query.Where("date >= ?", filters.From)
+	}
+	if filters.To != "" {
+		query = query.Where("date <= ?", filters.To)
+	}
+	return query
+}
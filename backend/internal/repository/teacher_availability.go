@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type TeacherAvailabilityRepository interface {
+	// Basic CRUD operations
+	Create(ctx context.Context, availability *models.TeacherAvailability) error
+	GetByID(ctx context.Context, id uint) (*models.TeacherAvailability, error)
+	GetByTeacherID(ctx context.Context, teacherID uint) ([]models.TeacherAvailability, error)
+	GetByBusinessID(ctx context.Context, businessID uint) ([]models.TeacherAvailability, error)
+	Update(ctx context.Context, availability *models.TeacherAvailability) error
+	Delete(ctx context.Context, id uint) error
+
+	// Validation
+	HasOverlap(ctx context.Context, teacherID uint, weekday int, startTime, endTime string, excludeAvailabilityID ...uint) (bool, error)
+
+	// Scheduling
+	GetAvailableTeachers(ctx context.Context, businessID uint, weekday int, at string) ([]models.Teacher, error)
+}
+
+type teacherAvailabilityRepository struct {
+	db *gorm.DB
+}
+
+func NewTeacherAvailabilityRepository(db *gorm.DB) TeacherAvailabilityRepository {
+	return &teacherAvailabilityRepository{
+		db: db,
+	}
+}
+
+func (r *teacherAvailabilityRepository) Create(ctx context.Context, availability *models.TeacherAvailability) error {
+	if availability == nil {
+		return fmt.Errorf("availability cannot be nil")
+	}
+	return r.db.WithContext(ctx).Create(availability).Error
+}
+
+func (r *teacherAvailabilityRepository) GetByID(ctx context.Context, id uint) (*models.TeacherAvailability, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid availability ID")
+	}
+
+	var availability models.TeacherAvailability
+	err := r.db.WithContext(ctx).First(&availability, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &availability, nil
+}
+
+func (r *teacherAvailabilityRepository) GetByTeacherID(ctx context.Context, teacherID uint) ([]models.TeacherAvailability, error) {
+	if teacherID == 0 {
+		return nil, fmt.Errorf("invalid teacher ID")
+	}
+
+	var slots []models.TeacherAvailability
+	err := r.db.WithContext(ctx).Where("teacher_id = ?", teacherID).
+		Order("weekday ASC, start_time ASC").
+		Find(&slots).Error
+	return slots, err
+}
+
+func (r *teacherAvailabilityRepository) Update(ctx context.Context, availability *models.TeacherAvailability) error {
+	if availability == nil {
+		return fmt.Errorf("availability cannot be nil")
+	}
+	if availability.ID == 0 {
+		return fmt.Errorf("availability ID cannot be zero")
+	}
+	return r.db.WithContext(ctx).Save(availability).Error
+}
+
+func (r *teacherAvailabilityRepository) Delete(ctx context.Context, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid availability ID")
+	}
+	return r.db.WithContext(ctx).Delete(&models.TeacherAvailability{}, id).Error
+}
+
+func (r *teacherAvailabilityRepository) HasOverlap(ctx context.Context, teacherID uint, weekday int, startTime, endTime string, excludeAvailabilityID ...uint) (bool, error) {
+	if teacherID == 0 {
+		return false, fmt.Errorf("invalid teacher ID")
+	}
+
+	var count int64
+	query := r.db.WithContext(ctx).Model(&models.TeacherAvailability{}).
+		Where("teacher_id = ? AND weekday = ?", teacherID, weekday).
+		Where("start_time < ? AND end_time > ?", endTime, startTime)
+
+	if len(excludeAvailabilityID) > 0 && excludeAvailabilityID[0] > 0 {
+		query = query.Where("id != ?", excludeAvailabilityID[0])
+	}
+
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// GetByBusinessID returns the slots of the active teachers of a business, with their teacher
+func (r *teacherAvailabilityRepository) GetByBusinessID(ctx context.Context, businessID uint) ([]models.TeacherAvailability, error) {
+	if businessID == 0 {
+		return nil, fmt.Errorf("invalid business ID")
+	}
+
+	var availabilities []models.TeacherAvailability
+	err := r.db.WithContext(ctx).
+		Joins("JOIN teacher ON teacher.id = teacher_availability.teacher_id").
+		Where("teacher.business_id = ? AND teacher.status = ? AND teacher.deleted_at IS NULL", businessID, 1).
+		Preload("Teacher").
+		Order("teacher_availability.weekday ASC, teacher_availability.start_time ASC, teacher_availability.id ASC").
+		Find(&availabilities).Error
+	return availabilities, err
+}
+
+// GetAvailableTeachers returns active teachers of a business with a slot covering the given time
+func (r *teacherAvailabilityRepository) GetAvailableTeachers(ctx context.Context, businessID uint, weekday int, at string) ([]models.Teacher, error) {
+	if businessID == 0 {
+		return nil, fmt.Errorf("invalid business ID")
+	}
+
+	var teachers []models.Teacher
+	err := r.db.WithContext(ctx).Model(&models.Teacher{}).
+		Preload("User").
+		Preload("Subjects").
+		Where("teacher.business_id = ? AND teacher.status = ?", businessID, 1).
+		Where("EXISTS (SELECT 1 FROM teacher_availability ta WHERE ta.teacher_id = teacher.id AND ta.weekday = ? AND ta.start_time <= ? AND ta.end_time > ?)", weekday, at, at).
+		Order("teacher.name ASC").
+		Find(&teachers).Error
+	return teachers, err
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type TeacherDocumentRepository interface {
+	Create(ctx context.Context, document *models.TeacherDocument) error
+	GetByID(ctx context.Context, id uint) (*models.TeacherDocument, error)
+	GetByTeacherID(ctx context.Context, teacherID uint) ([]models.TeacherDocument, error)
+	Delete(ctx context.Context, id uint) error
+
+	// Statistics
+	CountByTeacherIDs(ctx context.Context, teacherIDs []uint) (map[uint]int64, error)
+}
+
+type teacherDocumentRepository struct {
+	db *gorm.DB
+}
+
+func NewTeacherDocumentRepository(db *gorm.DB) TeacherDocumentRepository {
+	return &teacherDocumentRepository{
+		db: db,
+	}
+}
+
+func (r *teacherDocumentRepository) Create(ctx context.Context, document *models.TeacherDocument) error {
+	if document == nil {
+		return fmt.Errorf("document cannot be nil")
+	}
+	return r.db.WithContext(ctx).Create(document).Error
+}
+
+func (r *teacherDocumentRepository) GetByID(ctx context.Context, id uint) (*models.TeacherDocument, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("invalid document ID")
+	}
+
+	var document models.TeacherDocument
+	err := r.db.WithContext(ctx).First(&document, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+func (r *teacherDocumentRepository) GetByTeacherID(ctx context.Context, teacherID uint) ([]models.TeacherDocument, error) {
+	if teacherID == 0 {
+		return nil, fmt.Errorf("invalid teacher ID")
+	}
+
+	var documents []models.TeacherDocument
+	err := r.db.WithContext(ctx).Where("teacher_id = ?", teacherID).Order("uploaded_at DESC").Find(&documents).Error
+	return documents, err
+}
+
+func (r *teacherDocumentRepository) Delete(ctx context.Context, id uint) error {
+	if id == 0 {
+		return fmt.Errorf("invalid document ID")
+	}
+	return r.db.WithContext(ctx).Delete(&models.TeacherDocument{}, id).Error
+}
+
+func (r *teacherDocumentRepository) CountByTeacherIDs(ctx context.Context, teacherIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64)
+	if len(teacherIDs) == 0 {
+		return counts, nil
+	}
+
+	var results []struct {
+		TeacherID uint
+		Count     int64
+	}
+
+	err := r.db.WithContext(ctx).Model(&models.TeacherDocument{}).
+		Select("teacher_id, COUNT(*) as count").
+		Where("teacher_id IN ?", teacherIDs).
+		Group("teacher_id").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		counts[result.TeacherID] = result.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TeacherStudentRepository interface {
+	Assign(ctx context.Context, assignments []models.TeacherStudent) error
+	Unassign(ctx context.Context, teacherID uint, studentIDs []uint) (int64, error)
+	GetStudents(ctx context.Context, teacherID uint, page, limit int) ([]models.Student, int64, error)
+
+	// Statistics
+	CountByTeacherIDs(ctx context.Context, teacherIDs []uint) (map[uint]int64, error)
+	GetWorkloadStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error)
+}
+
+type teacherStudentRepository struct {
+	db *gorm.DB
+}
+
+func NewTeacherStudentRepository(db *gorm.DB) TeacherStudentRepository {
+	return &teacherStudentRepository{
+		db: db,
+	}
+}
+
+func (r *teacherStudentRepository) Assign(ctx context.Context, assignments []models.TeacherStudent) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+	// Students that are already assigned keep their original assignment
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&assignments).Error
+}
+
+func (r *teacherStudentRepository) Unassign(ctx context.Context, teacherID uint, studentIDs []uint) (int64, error) {
+	if teacherID == 0 {
+		return 0, fmt.Errorf("invalid teacher ID")
+	}
+	if len(studentIDs) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).Where("teacher_id = ? AND student_id IN ?", teacherID, studentIDs).
+		Delete(&models.TeacherStudent{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *teacherStudentRepository) GetStudents(ctx context.Context, teacherID uint, page, limit int) ([]models.Student, int64, error) {
+	if teacherID == 0 {
+		return nil, 0, fmt.Errorf("invalid teacher ID")
+	}
+
+	var students []models.Student
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Student{}).
+		Joins("JOIN teacher_students ts ON ts.student_id = student.id").
+		Where("ts.teacher_id = ?", teacherID)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Preload("User").Order("student.name ASC")
+
+	// Apply pagination
+	if limit > 0 {
+		offset := 0
+		if page > 1 {
+			offset = (page - 1) * limit
+		}
+		query = query.Offset(offset).Limit(limit)
+	}
+
+	err := query.Find(&students).Error
+	return students, total, err
+}
+
+func (r *teacherStudentRepository) CountByTeacherIDs(ctx context.Context, teacherIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64)
+	if len(teacherIDs) == 0 {
+		return counts, nil
+	}
+
+	var results []struct {
+		TeacherID uint
+		Count     int64
+	}
+
+	err := r.db.WithContext(ctx).Model(&models.TeacherStudent{}).
+		Select("teacher_id, COUNT(*) as count").
+		Where("teacher_id IN ?", teacherIDs).
+		Where("student_id IN (SELECT id FROM student WHERE deleted_at IS NULL)").
+		Group("teacher_id").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		counts[result.TeacherID] = result.Count
+	}
+	return counts, nil
+}
+
+func (r *teacherStudentRepository) GetWorkloadStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error) {
+	var result struct {
+		Assignments         int64
+		TeachersWithStudent int64
+		AssignedStudents    int64
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.TeacherStudent{})
+	if len(businessID) > 0 && businessID[0] > 0 {
+		query = query.Where("business_id = ?", businessID[0])
+	}
+
+	err := query.Select("COUNT(*) as assignments, COUNT(DISTINCT teacher_id) as teachers_with_student, COUNT(DISTINCT student_id) as assigned_students").
+		Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := map[string]interface{}{
+		"student_assignments":      result.Assignments,
+		"teachers_with_students":   result.TeachersWithStudent,
+		"assigned_students":        result.AssignedStudents,
+		"avg_students_per_teacher": 0.0,
+	}
+	if result.TeachersWithStudent > 0 {
+		stats["avg_students_per_teacher"] = float64(result.Assignments) / float64(result.TeachersWithStudent)
+	}
+	return stats, nil
+}
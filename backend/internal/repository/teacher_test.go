@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/testutil"
+)
+
+func TestGetTeacherStats(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	repo := NewTeacherRepository(db)
+
+	tests := []struct {
+		name       string
+		businessID []uint
+		want       map[string]int64
+	}{
+		{"all businesses", nil, map[string]int64{"total_teachers": 4, "active_teachers": 3, "inactive_teachers": 1}},
+		{"one business", []uint{f.Businesses["Sunrise Academy"].ID}, map[string]int64{"total_teachers": 3, "active_teachers": 2, "inactive_teachers": 1}},
+		{"business without inactive teachers", []uint{f.Businesses["Moonlight Tutors"].ID}, map[string]int64{"total_teachers": 1, "active_teachers": 1, "inactive_teachers": 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Twice, as the counts once shared a query that each of them narrowed further
+			for run := 1; run <= 2; run++ {
+				stats, err := repo.GetTeacherStats(context.Background(), tt.businessID...)
+				if err != nil {
+					t.Fatalf("GetTeacherStats: %v", err)
+				}
+				for key, value := range tt.want {
+					if stats[key] != value {
+						t.Errorf("run %d: %s = %v, want %d", run, key, stats[key], value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTeacherGetAll(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	repo := NewTeacherRepository(db)
+
+	sunrise := f.Businesses["Sunrise Academy"].ID
+	active := models.StatusActive
+	minSalary, maxSalary := 25000.0, 40000.0
+
+	tests := []struct {
+		name    string
+		filters TeacherFilters
+		want    []string
+		total   int64
+	}{
+		{"all by name", TeacherFilters{SortBy: "name"}, []string{"Asha", "Bilal", "Chitra", "Dev"}, 4},
+		{"by salary descending", TeacherFilters{SortBy: "salary", SortOrder: "desc"}, []string{"Bilal", "Asha", "Dev", "Chitra"}, 4},
+		{"business and status", TeacherFilters{BusinessID: &sunrise, Status: &active, SortBy: "name"}, []string{"Asha", "Bilal"}, 2},
+		{"salary range", TeacherFilters{MinSalary: &minSalary, MaxSalary: &maxSalary, SortBy: "name"}, []string{"Asha", "Dev"}, 2},
+		{"search ignoring case", TeacherFilters{Search: "A", SortBy: "name"}, []string{"Asha", "Bilal", "Chitra"}, 3},
+		// Search is ORed over several columns and must not escape the business filter
+		{"search within a business", TeacherFilters{BusinessID: &sunrise, Search: "dev"}, []string{}, 0},
+		{"second page", TeacherFilters{SortBy: "name", Page: 2, Limit: 3}, []string{"Dev"}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teachers, total, err := repo.GetAll(context.Background(), tt.filters)
+			if err != nil {
+				t.Fatalf("GetAll: %v", err)
+			}
+			names := make([]string, len(teachers))
+			for i, teacher := range teachers {
+				names[i] = teacher.Name
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("teachers = %v, want %v", names, tt.want)
+			}
+			if total != tt.total {
+				t.Errorf("total = %d, want %d", total, tt.total)
+			}
+		})
+	}
+}
+
+func TestBulkAdjustSalary(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	repo := NewTeacherRepository(db)
+	asha, bilal, chitra := f.Teachers["Asha"].ID, f.Teachers["Bilal"].ID, f.Teachers["Chitra"].ID
+
+	salaries := func() map[uint]float64 {
+		t.Helper()
+		var teachers []models.Teacher
+		if err := db.Where("id IN ?", []uint{asha, bilal, chitra}).Find(&teachers).Error; err != nil {
+			t.Fatalf("failed to read salaries: %v", err)
+		}
+		got := map[uint]float64{}
+		for _, teacher := range teachers {
+			got[teacher.ID] = teacher.Salary
+		}
+		return got
+	}
+
+	// Chitra's 20000 would go negative, so neither salary changes
+	if _, err := repo.BulkAdjustSalaryWithTransaction(db, []uint{asha, chitra}, models.SalaryAdjustmentIncreaseAmount, -25000, time.Now()); err == nil {
+		t.Fatal("BulkAdjustSalaryWithTransaction() to a negative salary succeeded, want an error")
+	}
+	if got, want := salaries(), map[uint]float64{asha: 30000, bilal: 45000, chitra: 20000}; !reflect.DeepEqual(got, want) {
+		t.Errorf("salaries after the rejected batch = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name           string
+		ids            []uint
+		adjustmentType string
+		value          float64
+		want           map[uint]float64
+	}{
+		{"decrease by an amount", []uint{asha, chitra}, models.SalaryAdjustmentIncreaseAmount, -5000, map[uint]float64{asha: 25000, bilal: 45000, chitra: 15000}},
+		{"increase by a percentage", []uint{bilal}, models.SalaryAdjustmentIncreasePercent, 10, map[uint]float64{asha: 25000, bilal: 49500, chitra: 15000}},
+		{"set", []uint{asha, bilal, chitra}, models.SalaryAdjustmentSet, 40000, map[uint]float64{asha: 40000, bilal: 40000, chitra: 40000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := repo.BulkAdjustSalaryWithTransaction(db, tt.ids, tt.adjustmentType, tt.value, time.Now())
+			if err != nil {
+				t.Fatalf("BulkAdjustSalaryWithTransaction() error = %v", err)
+			}
+			if len(results) != len(tt.ids) {
+				t.Errorf("got %d results, want %d", len(results), len(tt.ids))
+			}
+			if got := salaries(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("salaries = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
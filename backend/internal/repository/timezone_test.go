@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+// createInNewYork creates an active New York business for each instant, with column set
+// to it, and returns their IDs by instant
+func createInNewYork(t *testing.T, db *gorm.DB, column string, instants []string) map[string]uint {
+	t.Helper()
+	ids := make(map[string]uint, len(instants))
+	for i, instant := range instants {
+		at, err := time.Parse(time.RFC3339, instant)
+		if err != nil {
+			t.Fatal(err)
+		}
+		business := testutil.CreateBusiness(t, db, fmt.Sprintf("Eastside %s %d", column, i), models.StatusActive, nil)
+		if err := db.Model(&models.Business{}).Where("id = ?", business.ID).
+			UpdateColumns(map[string]interface{}{"timezone": "America/New_York", column: at}).Error; err != nil {
+			t.Fatalf("failed to set %s: %v", column, err)
+		}
+		ids[instant] = business.ID
+	}
+	return ids
+}
+
+func idsOf(businesses []models.Business) []uint {
+	ids := make([]uint, len(businesses))
+	for i, business := range businesses {
+		ids[i] = business.ID
+	}
+	return ids
+}
+
+// The creation date filter cuts registrations into the business's local days, including
+// the 23 and 25 hour days DST makes. New York's midnights are 05:00 UTC in winter and
+// 04:00 UTC in summer.
+func TestCreatedDateFilterAcrossDST(t *testing.T) {
+	db := testutil.DB(t)
+	testutil.Seed(t, db)
+	repo := NewBusinessRepository(db)
+
+	ids := createInNewYork(t, db, "created_on", []string{
+		"2025-03-09T04:59:00Z", // 8 March, 23:59 EST
+		"2025-03-09T05:00:00Z", // 9 March, 00:00 EST
+		"2025-03-10T03:59:00Z", // 9 March, 23:59 EDT
+		"2025-03-10T04:00:00Z", // 10 March, 00:00 EDT
+		"2025-11-02T03:59:00Z", // 1 November, 23:59 EDT
+		"2025-11-02T04:00:00Z", // 2 November, 00:00 EDT
+		"2025-11-03T04:59:00Z", // 2 November, 23:59 EST
+		"2025-11-03T05:00:00Z", // 3 November, 00:00 EST
+	})
+
+	tests := []struct {
+		day  string
+		want []string
+	}{
+		{"2025-03-09", []string{"2025-03-09T05:00:00Z", "2025-03-10T03:59:00Z"}},
+		{"2025-03-10", []string{"2025-03-10T04:00:00Z"}},
+		{"2025-11-02", []string{"2025-11-02T04:00:00Z", "2025-11-03T04:59:00Z"}},
+		{"2025-11-03", []string{"2025-11-03T05:00:00Z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.day, func(t *testing.T) {
+			businesses, _, err := repo.GetAll(context.Background(), BusinessFilters{Search: "Eastside", CreatedFrom: tt.day, CreatedTo: tt.day})
+			if err != nil {
+				t.Fatalf("GetAll: %v", err)
+			}
+			want := make([]uint, len(tt.want))
+			for i, instant := range tt.want {
+				want[i] = ids[instant]
+			}
+			if got := idsOf(businesses); !sameIDs(got, want) {
+				t.Errorf("created on %s = %v, want %v", tt.day, got, want)
+			}
+		})
+	}
+}
+
+// Expiry filters and notices count local days too: "within a day" from the morning of
+// 1 November in New York runs to the end of the 25 hour 2 November
+func TestPackageExpiryAcrossDST(t *testing.T) {
+	db := testutil.DB(t)
+	testutil.Seed(t, db)
+	repo := NewBusinessRepository(db)
+
+	ids := createInNewYork(t, db, "package_expires_at", []string{
+		"2025-11-01T11:00:00Z", // 1 November, 07:00 EDT, already expired
+		"2025-11-03T04:30:00Z", // 2 November, 23:30 EST
+		"2025-11-03T05:30:00Z", // 3 November, 00:30 EST
+	})
+	now := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC) // 1 November, 08:00 EDT
+
+	businesses, err := repo.GetBusinessesByPackageExpiry(context.Background(), now, 1)
+	if err != nil {
+		t.Fatalf("GetBusinessesByPackageExpiry: %v", err)
+	}
+	var got []uint
+	for _, id := range idsOf(businesses) {
+		for _, created := range ids {
+			if id == created {
+				got = append(got, id)
+			}
+		}
+	}
+	if want := []uint{ids["2025-11-03T04:30:00Z"]}; !sameIDs(got, want) {
+		t.Errorf("expiring within a day = %v, want %v", got, want)
+	}
+
+	businesses, _, err = repo.GetAll(context.Background(), BusinessFilters{Search: "Eastside", ExpiresAfter: "2025-11-02", ExpiresBefore: "2025-11-03"})
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	if got, want := idsOf(businesses), []uint{ids["2025-11-03T04:30:00Z"]}; !sameIDs(got, want) {
+		t.Errorf("expiring on 2 November = %v, want %v", got, want)
+	}
+}
@@ -2,130 +2,169 @@ package repository
 
 import (
 	"backend/internal/models"
-	"backend/pkg/database"
+	"context"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 )
 
-func (r *userRepository) UpdateUser(user *models.User) error {
-	return r.Update(user)
+func (r *userRepository) UpdateUser(ctx context.Context, user *models.User) error {
+	return r.Update(ctx, user)
 }
 
 type UserRepository interface {
 	// Basic CRUD operations
-	Create(user *models.User) error
-	GetByEmail(email string) (*models.User, error)
-	GetByID(id uint) (*models.User, error)
-	GetAll(filters UserFilters) ([]models.User, int64, error)
-	Update(user *models.User) error
-	UpdateUser(user *models.User) error
-	Delete(id uint) error
+	Create(ctx context.Context, user *models.User) error
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByID(ctx context.Context, id uint) (*models.User, error)
+	GetAll(ctx context.Context, filters UserFilters) ([]models.User, int64, error)
+	Update(ctx context.Context, user *models.User) error
+	UpdateUser(ctx context.Context, user *models.User) error
+	Delete(ctx context.Context, id uint) error
 
 	// Role-based operations
-	GetByRole(role models.UserRole) ([]models.User, error)
-	UpdateUserRole(userID uint, newRole models.UserRole) error
+	GetByRole(ctx context.Context, role models.UserRole) ([]models.User, error)
+	GetUsersByRoleAndStatus(ctx context.Context, role models.UserRole, status models.Status) ([]models.User, error)
+	UpdateUserRole(ctx context.Context, userID uint, newRole models.UserRole) error
 
 	// Status operations
-	GetActiveByEmail(email string) (*models.User, error)
-	GetUsersByStatus(status int) ([]models.User, error)
-	UpdateUserStatus(userID uint, status int) error
+	GetActiveByEmail(ctx context.Context, email string) (*models.User, error)
+	GetUsersByStatus(ctx context.Context, status models.Status) ([]models.User, error)
+	UpdateUserStatus(ctx context.Context, userID uint, status models.Status) error
+	RecordLogin(ctx context.Context, userID uint, clientIP string) (time.Time, error)
 
 	// Statistics and reporting
-	GetUserStats() (map[string]interface{}, error)
-	GetRoleCount(role models.UserRole) (int64, error)
-	GetStatusCount(status int) (int64, error)
+	GetUserStats(ctx context.Context) (map[string]interface{}, error)
+	GetRoleCount(ctx context.Context, role models.UserRole) (int64, error)
+	GetStatusCount(ctx context.Context, status models.Status) (int64, error)
 
 	// Validation and utility
-	EmailExists(email string, excludeUserID ...uint) (bool, error)
-	GetUsersCount() (int64, error)
+	EmailExists(ctx context.Context, email string, excludeUserID ...uint) (bool, error)
+	EmailInUseInTransaction(tx *gorm.DB, email string, ownerID uint) (string, error)
+	GetUsersCount(ctx context.Context) (int64, error)
+	HasDeletedProfile(ctx context.Context, userID uint) (bool, error)
 
 	// Bulk operations
-	BulkUpdateStatus(userIDs []uint, status int) error
-	BulkDelete(userIDs []uint) error
+	BulkUpdateStatus(ctx context.Context, userIDs []uint, status models.Status) error
+	BulkDelete(ctx context.Context, userIDs []uint) error
 
 	// Transactional operations
+	BeginTransaction(ctx context.Context) *gorm.DB
 	CreateUserInTransaction(tx *gorm.DB, user *models.User) error
+	UpdateUserInTransaction(tx *gorm.DB, user *models.User) error
+	UpdateUserStatusInTransaction(tx *gorm.DB, userID uint, status models.Status) error
+	BulkUpdateStatusInTransaction(tx *gorm.DB, userIDs []uint, status models.Status) error
+	UpdateUserNameInTransaction(tx *gorm.DB, userID uint, name string) error
+	UpdateUserFieldsInTransaction(tx *gorm.DB, userID uint, fields map[string]interface{}) error
+	DeleteUserInTransaction(tx *gorm.DB, userID uint) error
+	BulkDeleteInTransaction(tx *gorm.DB, userIDs []uint) error
 
 	// Advanced queries
-	SearchUsers(searchTerm string, limit int) ([]models.User, error)
-	GetRecentUsers(limit int) ([]models.User, error)
-	GetUsersByDateRange(startDate, endDate string) ([]models.User, error)
+	SearchUsers(ctx context.Context, searchTerm string, limit int) ([]models.User, error)
+	GetRecentUsers(ctx context.Context, limit int) ([]models.User, error)
+	CountAdditions(ctx context.Context, now time.Time) (*models.AdditionCounts, error)
+	GetUsersByDateRange(ctx context.Context, startDate, endDate string) ([]models.User, error)
 }
 
 type UserFilters struct {
-	Role      string `form:"role" json:"role"`
-	Status    *int   `form:"status" json:"status"`
-	Search    string `form:"search" json:"search"`
-	Page      int    `form:"page" json:"page"`
-	Limit     int    `form:"limit" json:"limit"`
-	SortBy    string `form:"sort_by" json:"sort_by"`       // created_on, name, email
-	SortOrder string `form:"sort_order" json:"sort_order"` // asc, desc
+	Role      string         `form:"role" json:"role"`
+	Status    *models.Status `form:"status" json:"status"`
+	Search    string         `form:"search" json:"search"`
+	Page      int            `form:"page" json:"page"`
+	Limit     int            `form:"limit" json:"limit"`
+	SortBy    string         `form:"sort_by" json:"sort_by"`       // comma-separated columns, e.g. status,name
+	SortOrder string         `form:"sort_order" json:"sort_order"` // asc or desc, for all columns or one per column
+	Cursor    string         `form:"cursor" json:"cursor"`         // next_cursor of the previous page, replaces page
+	WithTotal *bool          `form:"with_total" json:"with_total"` // false skips counting the total
+	Estimate  bool           `form:"estimate" json:"estimate"`     // estimate the total from table statistics, for large tables
+}
+
+// userSortFields are the columns users can be sorted by
+var userSortFields = map[string]bool{
+	"created_on": true,
+	"updated_on": true,
+	"name":       true,
+	"email":      true,
+	"role":       true,
+	"status":     true,
+}
+
+func (f UserFilters) totalMode() totalMode {
+	return newTotalMode(f.Cursor, f.WithTotal, f.Estimate)
+}
+
+func (f UserFilters) sort() listSort {
+	return resolveSort(f.SortBy, f.SortOrder, userSortFields)
+}
+
+// PageInfo builds the pagination metadata for users listed with these filters
+func (f UserFilters) PageInfo(total int64, users []models.User) PageInfo {
+	return newPageInfo(total, users, f.sort(), f.Page, f.Limit, f.Cursor, f.totalMode())
 }
 
 type userRepository struct {
 	db *gorm.DB
 }
 
-func NewUserRepository() UserRepository {
+func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{
-		db: database.DB,
+		db: db,
 	}
 }
 
 // Basic CRUD operations
 
-func (r *userRepository) Create(user *models.User) error {
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 	if user == nil {
 		return fmt.Errorf("user cannot be nil")
 	}
-	return r.db.Create(user).Error
+	return r.db.WithContext(ctx).Create(user).Error
 }
 
-func (r *userRepository) GetByEmail(email string) (*models.User, error) {
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	if email == "" {
 		return nil, fmt.Errorf("email cannot be empty")
 	}
 
 	var user models.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) GetActiveByEmail(email string) (*models.User, error) {
+func (r *userRepository) GetActiveByEmail(ctx context.Context, email string) (*models.User, error) {
 	if email == "" {
 		return nil, fmt.Errorf("email cannot be empty")
 	}
 
 	var user models.User
-	err := r.db.Where("email = ? AND status = 1", email).First(&user).Error
+	err := r.db.WithContext(ctx).Where("email = ? AND status = 1", email).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) GetByID(id uint) (*models.User, error) {
+func (r *userRepository) GetByID(ctx context.Context, id uint) (*models.User, error) {
 	if id == 0 {
 		return nil, fmt.Errorf("invalid user ID")
 	}
 
 	var user models.User
-	err := r.db.First(&user, id).Error
+	err := r.db.WithContext(ctx).First(&user, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) GetAll(filters UserFilters) ([]models.User, int64, error) {
+func (r *userRepository) GetAll(ctx context.Context, filters UserFilters) ([]models.User, int64, error) {
 	var users []models.User
-	var total int64
 
-	query := r.db.Model(&models.User{})
+	query := r.db.WithContext(ctx).Model(&models.User{})
 
 	// Apply filters
 	if filters.Role != "" {
@@ -140,80 +179,57 @@ func (r *userRepository) GetAll(filters UserFilters) ([]models.User, int64, erro
 	}
 
 	if filters.Search != "" {
-		query = query.Where("name ILIKE ? OR email ILIKE ?",
-			"%"+filters.Search+"%", "%"+filters.Search+"%")
+		query = query.Where("name ILIKE ? OR email ILIKE ? OR "+phoneMatch,
+			"%"+filters.Search+"%", "%"+filters.Search+"%", searchPhone(filters.Search))
 	}
 
-	// Count total first (before pagination)
-	if err := query.Count(&total).Error; err != nil {
+	// Count total first (before pagination), unless skipped
+	total, err := countTotal(query, filters.totalMode())
+	if err != nil {
 		return nil, 0, err
 	}
 
-	// Apply sorting
-	orderBy := "created_on DESC" // default
-	if filters.SortBy != "" {
-		validSortFields := map[string]bool{
-			"created_on": true,
-			"updated_on": true,
-			"name":       true,
-			"email":      true,
-			"role":       true,
-			"status":     true,
-		}
-		if validSortFields[filters.SortBy] {
-			sortOrder := "DESC"
-			if filters.SortOrder == "asc" {
-				sortOrder = "ASC"
-			}
-			orderBy = fmt.Sprintf("%s %s", filters.SortBy, sortOrder)
-		}
-	}
-	query = query.Order(orderBy)
-
-	// Apply pagination
-	if filters.Limit > 0 {
-		offset := 0
-		if filters.Page > 1 {
-			offset = (filters.Page - 1) * filters.Limit
-		}
-		query = query.Offset(offset).Limit(filters.Limit)
+	// Apply sorting and pagination
+	query, err = paginate(query, filters.sort(), filters.Page, filters.Limit, filters.Cursor)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	err := query.Find(&users).Error
+	err = query.Find(&users).Error
 	return users, total, err
 }
 
-func (r *userRepository) Update(user *models.User) error {
+func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 	if user == nil {
 		return fmt.Errorf("user cannot be nil")
 	}
 	if user.ID == 0 {
 		return fmt.Errorf("user ID cannot be zero")
 	}
-	return r.db.Save(user).Error
+	return r.db.WithContext(ctx).Save(user).Error
 }
 
-func (r *userRepository) Delete(id uint) error {
+func (r *userRepository) Delete(ctx context.Context, id uint) error {
 	if id == 0 {
 		return fmt.Errorf("invalid user ID")
 	}
-	return r.db.Delete(&models.User{}, id).Error
+	return r.db.WithContext(ctx).Delete(&models.User{}, id).Error
 }
 
 // Role-based operations
 
-func (r *userRepository) GetByRole(role models.UserRole) ([]models.User, error) {
+func (r *userRepository) GetByRole(ctx context.Context, role models.UserRole) ([]models.User, error) {
 	var users []models.User
 
 	if !role.IsValid() {
 		return users, gorm.ErrInvalidValue
 	}
 
-	err := r.db.Where("role = ?", string(role)).Order("created_on DESC").Find(&users).Error
+	err := r.db.WithContext(ctx).Where("role = ?", string(role)).Order("created_on DESC").Find(&users).Error
 	return users, err
 }
 
-func (r *userRepository) UpdateUserRole(userID uint, newRole models.UserRole) error {
+func (r *userRepository) UpdateUserRole(ctx context.Context, userID uint, newRole models.UserRole) error {
 	if userID == 0 {
 		return fmt.Errorf("invalid user ID")
 	}
@@ -221,75 +237,87 @@ func (r *userRepository) UpdateUserRole(userID uint, newRole models.UserRole) er
 		return gorm.ErrInvalidValue
 	}
 
-	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("role", newRole).Error
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Update("role", newRole).Error
 }
 
-func (r *userRepository) GetRoleCount(role models.UserRole) (int64, error) {
+func (r *userRepository) GetRoleCount(ctx context.Context, role models.UserRole) (int64, error) {
 	if !role.IsValid() {
 		return 0, gorm.ErrInvalidValue
 	}
 
 	var count int64
-	err := r.db.Model(&models.User{}).Where("role = ?", string(role)).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("role = ?", string(role)).Count(&count).Error
 	return count, err
 }
 
 // Status operations
 
-func (r *userRepository) GetUsersByStatus(status int) ([]models.User, error) {
+func (r *userRepository) GetUsersByStatus(ctx context.Context, status models.Status) ([]models.User, error) {
 	var users []models.User
 
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return users, gorm.ErrInvalidValue
 	}
 
-	err := r.db.Where("status = ?", status).Order("created_on DESC").Find(&users).Error
+	err := r.db.WithContext(ctx).Where("status = ?", status).Order("created_on DESC").Find(&users).Error
 	return users, err
 }
 
-func (r *userRepository) UpdateUserStatus(userID uint, status int) error {
+func (r *userRepository) UpdateUserStatus(ctx context.Context, userID uint, status models.Status) error {
 	if userID == 0 {
 		return fmt.Errorf("invalid user ID")
 	}
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return gorm.ErrInvalidValue
 	}
 
-	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("status", status).Error
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Update("status", status).Error
 }
 
-func (r *userRepository) GetStatusCount(status int) (int64, error) {
-	if status < 0 || status > 1 {
+// RecordLogin sets the user's last login time to now and the address it came from, and
+// returns the time. updated_on is left alone, as logging in doesn't change the account.
+func (r *userRepository) RecordLogin(ctx context.Context, userID uint, clientIP string) (time.Time, error) {
+	if userID == 0 {
+		return time.Time{}, fmt.Errorf("invalid user ID")
+	}
+	now := time.Now()
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).
+		UpdateColumns(map[string]interface{}{"last_login_at": now, "last_login_ip": clientIP}).Error
+	return now, err
+}
+
+func (r *userRepository) GetStatusCount(ctx context.Context, status models.Status) (int64, error) {
+	if !status.IsValid() {
 		return 0, gorm.ErrInvalidValue
 	}
 
 	var count int64
-	err := r.db.Model(&models.User{}).Where("status = ?", status).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("status = ?", status).Count(&count).Error
 	return count, err
 }
 
 // Statistics and reporting
 
-func (r *userRepository) GetUserStats() (map[string]interface{}, error) {
+func (r *userRepository) GetUserStats(ctx context.Context) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
 	// Total users
 	var totalUsers int64
-	if err := r.db.Model(&models.User{}).Count(&totalUsers).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Count(&totalUsers).Error; err != nil {
 		return nil, err
 	}
 	stats["total_users"] = totalUsers
 
 	// Active users
 	var activeUsers int64
-	if err := r.db.Model(&models.User{}).Where("status = 1").Count(&activeUsers).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Where("status = 1").Count(&activeUsers).Error; err != nil {
 		return nil, err
 	}
 	stats["active_users"] = activeUsers
 
 	// Inactive users
 	var inactiveUsers int64
-	if err := r.db.Model(&models.User{}).Where("status = 0").Count(&inactiveUsers).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Where("status = 0").Count(&inactiveUsers).Error; err != nil {
 		return nil, err
 	}
 	stats["inactive_users"] = inactiveUsers
@@ -300,7 +328,7 @@ func (r *userRepository) GetUserStats() (map[string]interface{}, error) {
 
 	for _, role := range roles {
 		var count int64
-		if err := r.db.Model(&models.User{}).Where("role = ?", string(role)).Count(&count).Error; err != nil {
+		if err := r.db.WithContext(ctx).Model(&models.User{}).Where("role = ?", string(role)).Count(&count).Error; err != nil {
 			return nil, err
 		}
 		roleStats[string(role)] = count
@@ -311,7 +339,7 @@ func (r *userRepository) GetUserStats() (map[string]interface{}, error) {
 	activeRoleStats := make(map[string]int64)
 	for _, role := range roles {
 		var count int64
-		if err := r.db.Model(&models.User{}).Where("role = ? AND status = 1", string(role)).Count(&count).Error; err != nil {
+		if err := r.db.WithContext(ctx).Model(&models.User{}).Where("role = ? AND status = 1", string(role)).Count(&count).Error; err != nil {
 			return nil, err
 		}
 		activeRoleStats[string(role)] = count
@@ -321,21 +349,21 @@ func (r *userRepository) GetUserStats() (map[string]interface{}, error) {
 	return stats, nil
 }
 
-func (r *userRepository) GetUsersCount() (int64, error) {
+func (r *userRepository) GetUsersCount(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.User{}).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&models.User{}).Count(&count).Error
 	return count, err
 }
 
 // Validation and utility
 
-func (r *userRepository) EmailExists(email string, excludeUserID ...uint) (bool, error) {
+func (r *userRepository) EmailExists(ctx context.Context, email string, excludeUserID ...uint) (bool, error) {
 	if email == "" {
 		return false, fmt.Errorf("email cannot be empty")
 	}
 
 	var count int64
-	query := r.db.Model(&models.User{}).Where("email = ?", email)
+	query := r.db.WithContext(ctx).Model(&models.User{}).Where("email = ?", email)
 
 	if len(excludeUserID) > 0 && excludeUserID[0] > 0 {
 		query = query.Where("id != ?", excludeUserID[0])
@@ -345,36 +373,58 @@ func (r *userRepository) EmailExists(email string, excludeUserID ...uint) (bool,
 	return count > 0, err
 }
 
+// EmailInUseInTransaction finds another user or business using an email address,
+// ignoring case, and returns the table it is in ("users" or "business"), or "" when it
+// is free. ownerID is the user whose own account and business don't count. The address
+// stays locked until the transaction ends, so a concurrent request checking it waits
+// and then sees this transaction's writes.
+func (r *userRepository) EmailInUseInTransaction(tx *gorm.DB, email string, ownerID uint) (string, error) {
+	if email == "" {
+		return "", fmt.Errorf("email cannot be empty")
+	}
+
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(LOWER(?)))", email).Error; err != nil {
+		return "", err
+	}
+
+	var tables []string
+	err := tx.Raw(`SELECT 'users' FROM users WHERE LOWER(email) = LOWER(?) AND id <> ?
+		UNION ALL
+		SELECT 'business' FROM business WHERE LOWER(email) = LOWER(?) AND user_id <> ?
+		LIMIT 1`, email, ownerID, email, ownerID).
+		Scan(&tables).Error
+	if err != nil || len(tables) == 0 {
+		return "", err
+	}
+	return tables[0], nil
+}
+
 // Bulk operations
 
-func (r *userRepository) BulkUpdateStatus(userIDs []uint, status int) error {
+func (r *userRepository) BulkUpdateStatus(ctx context.Context, userIDs []uint, status models.Status) error {
 	if len(userIDs) == 0 {
 		return fmt.Errorf("no user IDs provided")
 	}
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return gorm.ErrInvalidValue
 	}
 
-	return r.db.Model(&models.User{}).Where("id IN ?", userIDs).Update("status", status).Error
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id IN ?", userIDs).Update("status", status).Error
 }
 
-func (r *userRepository) BulkDelete(userIDs []uint) error {
-	if len(userIDs) == 0 {
-		return fmt.Errorf("no user IDs provided")
-	}
-
-	return r.db.Where("id IN ?", userIDs).Delete(&models.User{}).Error
+func (r *userRepository) BulkDelete(ctx context.Context, userIDs []uint) error {
+	return r.BulkDeleteInTransaction(r.db.WithContext(ctx), userIDs)
 }
 
 // Advanced queries
 
-func (r *userRepository) SearchUsers(searchTerm string, limit int) ([]models.User, error) {
+func (r *userRepository) SearchUsers(ctx context.Context, searchTerm string, limit int) ([]models.User, error) {
 	if searchTerm == "" {
 		return []models.User{}, nil
 	}
 
 	var users []models.User
-	query := r.db.Where("name ILIKE ? OR email ILIKE ?", "%"+searchTerm+"%", "%"+searchTerm+"%").
+	query := r.db.WithContext(ctx).Where("name ILIKE ? OR email ILIKE ? OR "+phoneMatch, "%"+searchTerm+"%", "%"+searchTerm+"%", searchPhone(searchTerm)).
 		Order("created_on DESC")
 
 	if limit > 0 {
@@ -385,27 +435,24 @@ func (r *userRepository) SearchUsers(searchTerm string, limit int) ([]models.Use
 	return users, err
 }
 
-func (r *userRepository) GetRecentUsers(limit int) ([]models.User, error) {
+func (r *userRepository) GetRecentUsers(ctx context.Context, limit int) ([]models.User, error) {
 	var users []models.User
-	query := r.db.Order("created_on DESC")
-
-	if limit > 0 {
-		query = query.Limit(limit)
-	} else {
-		query = query.Limit(10) // default limit
-	}
-
-	err := query.Find(&users).Error
+	err := latestFirst(r.db.WithContext(ctx), limit).Find(&users).Error
 	return users, err
 }
 
-func (r *userRepository) GetUsersByDateRange(startDate, endDate string) ([]models.User, error) {
+// CountAdditions counts the users registered in the 7 and 30 days before now
+func (r *userRepository) CountAdditions(ctx context.Context, now time.Time) (*models.AdditionCounts, error) {
+	return countAdditions(r.db.WithContext(ctx).Model(&models.User{}), now)
+}
+
+func (r *userRepository) GetUsersByDateRange(ctx context.Context, startDate, endDate string) ([]models.User, error) {
 	if startDate == "" || endDate == "" {
 		return nil, fmt.Errorf("start date and end date cannot be empty")
 	}
 
 	var users []models.User
-	err := r.db.Where("created_on BETWEEN ? AND ?", startDate, endDate).
+	err := r.db.WithContext(ctx).Where("created_on BETWEEN ? AND ?", startDate, endDate).
 		Order("created_on DESC").Find(&users).Error
 	return users, err
 }
@@ -413,22 +460,22 @@ func (r *userRepository) GetUsersByDateRange(startDate, endDate string) ([]model
 // Additional utility methods
 
 // GetUsersByRoleAndStatus gets users by both role and status
-func (r *userRepository) GetUsersByRoleAndStatus(role models.UserRole, status int) ([]models.User, error) {
+func (r *userRepository) GetUsersByRoleAndStatus(ctx context.Context, role models.UserRole, status models.Status) ([]models.User, error) {
 	if !role.IsValid() {
 		return nil, gorm.ErrInvalidValue
 	}
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return nil, gorm.ErrInvalidValue
 	}
 
 	var users []models.User
-	err := r.db.Where("role = ? AND status = ?", string(role), status).
+	err := r.db.WithContext(ctx).Where("role = ? AND status = ?", string(role), status).
 		Order("created_on DESC").Find(&users).Error
 	return users, err
 }
 
 // UpdateUserEmail updates only the user's email
-func (r *userRepository) UpdateUserEmail(userID uint, newEmail string) error {
+func (r *userRepository) UpdateUserEmail(ctx context.Context, userID uint, newEmail string) error {
 	if userID == 0 {
 		return fmt.Errorf("invalid user ID")
 	}
@@ -437,7 +484,7 @@ func (r *userRepository) UpdateUserEmail(userID uint, newEmail string) error {
 	}
 
 	// Check if email already exists for another user
-	exists, err := r.EmailExists(newEmail, userID)
+	exists, err := r.EmailExists(ctx, newEmail, userID)
 	if err != nil {
 		return err
 	}
@@ -445,11 +492,11 @@ func (r *userRepository) UpdateUserEmail(userID uint, newEmail string) error {
 		return fmt.Errorf("email already exists")
 	}
 
-	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("email", newEmail).Error
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Update("email", newEmail).Error
 }
 
 // UpdateUserPassword updates only the user's password
-func (r *userRepository) UpdateUserPassword(userID uint, hashedPassword string) error {
+func (r *userRepository) UpdateUserPassword(ctx context.Context, userID uint, hashedPassword string) error {
 	if userID == 0 {
 		return fmt.Errorf("invalid user ID")
 	}
@@ -457,20 +504,20 @@ func (r *userRepository) UpdateUserPassword(userID uint, hashedPassword string)
 		return fmt.Errorf("password cannot be empty")
 	}
 
-	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("password", hashedPassword).Error
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Update("password", hashedPassword).Error
 }
 
 // GetUserByEmailAndStatus gets user by email and specific status
-func (r *userRepository) GetUserByEmailAndStatus(email string, status int) (*models.User, error) {
+func (r *userRepository) GetUserByEmailAndStatus(ctx context.Context, email string, status models.Status) (*models.User, error) {
 	if email == "" {
 		return nil, fmt.Errorf("email cannot be empty")
 	}
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return nil, gorm.ErrInvalidValue
 	}
 
 	var user models.User
-	err := r.db.Where("email = ? AND status = ?", email, status).First(&user).Error
+	err := r.db.WithContext(ctx).Where("email = ? AND status = ?", email, status).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -478,57 +525,57 @@ func (r *userRepository) GetUserByEmailAndStatus(email string, status int) (*mod
 }
 
 // GetUsersWithPagination gets users with simple pagination (offset/limit)
-func (r *userRepository) GetUsersWithPagination(offset, limit int) ([]models.User, int64, error) {
+func (r *userRepository) GetUsersWithPagination(ctx context.Context, offset, limit int) ([]models.User, int64, error) {
 	var users []models.User
 	var total int64
 
 	// Get total count
-	if err := r.db.Model(&models.User{}).Count(&total).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get users with pagination
-	err := r.db.Offset(offset).Limit(limit).Order("created_on DESC").Find(&users).Error
+	err := r.db.WithContext(ctx).Offset(offset).Limit(limit).Order("created_on DESC").Find(&users).Error
 	return users, total, err
 }
 
 // SoftDeleteUser performs soft delete (sets status to inactive instead of deleting)
-func (r *userRepository) SoftDeleteUser(userID uint) error {
+func (r *userRepository) SoftDeleteUser(ctx context.Context, userID uint) error {
 	if userID == 0 {
 		return fmt.Errorf("invalid user ID")
 	}
 
-	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("status", 0).Error
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Update("status", 0).Error
 }
 
 // RestoreUser restores a soft-deleted user (sets status back to active)
-func (r *userRepository) RestoreUser(userID uint) error {
+func (r *userRepository) RestoreUser(ctx context.Context, userID uint) error {
 	if userID == 0 {
 		return fmt.Errorf("invalid user ID")
 	}
 
-	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("status", 1).Error
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Update("status", 1).Error
 }
 
 // GetUserCountByDateRange gets count of users created within a date range
-func (r *userRepository) GetUserCountByDateRange(startDate, endDate string) (int64, error) {
+func (r *userRepository) GetUserCountByDateRange(ctx context.Context, startDate, endDate string) (int64, error) {
 	if startDate == "" || endDate == "" {
 		return 0, fmt.Errorf("start date and end date cannot be empty")
 	}
 
 	var count int64
-	err := r.db.Model(&models.User{}).Where("created_on BETWEEN ? AND ?", startDate, endDate).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("created_on BETWEEN ? AND ?", startDate, endDate).Count(&count).Error
 	return count, err
 }
 
 // GetTopUsersByRole gets top N users by role (most recently created)
-func (r *userRepository) GetTopUsersByRole(role models.UserRole, limit int) ([]models.User, error) {
+func (r *userRepository) GetTopUsersByRole(ctx context.Context, role models.UserRole, limit int) ([]models.User, error) {
 	if !role.IsValid() {
 		return nil, gorm.ErrInvalidValue
 	}
 
 	var users []models.User
-	query := r.db.Where("role = ?", string(role)).Order("created_on DESC")
+	query := r.db.WithContext(ctx).Where("role = ?", string(role)).Order("created_on DESC")
 
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -541,7 +588,7 @@ func (r *userRepository) GetTopUsersByRole(role models.UserRole, limit int) ([]m
 }
 
 // BatchUpdateUsers updates multiple users with the same data
-func (r *userRepository) BatchUpdateUsers(userIDs []uint, updates map[string]interface{}) error {
+func (r *userRepository) BatchUpdateUsers(ctx context.Context, userIDs []uint, updates map[string]interface{}) error {
 	if len(userIDs) == 0 {
 		return fmt.Errorf("no user IDs provided")
 	}
@@ -549,11 +596,11 @@ func (r *userRepository) BatchUpdateUsers(userIDs []uint, updates map[string]int
 		return fmt.Errorf("no updates provided")
 	}
 
-	return r.db.Model(&models.User{}).Where("id IN ?", userIDs).Updates(updates).Error
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id IN ?", userIDs).Updates(updates).Error
 }
 
 // GetUsersByMultipleRoles gets users by multiple roles
-func (r *userRepository) GetUsersByMultipleRoles(roles []models.UserRole) ([]models.User, error) {
+func (r *userRepository) GetUsersByMultipleRoles(ctx context.Context, roles []models.UserRole) ([]models.User, error) {
 	if len(roles) == 0 {
 		return []models.User{}, nil
 	}
@@ -571,12 +618,12 @@ func (r *userRepository) GetUsersByMultipleRoles(roles []models.UserRole) ([]mod
 	}
 
 	var users []models.User
-	err := r.db.Where("role IN ?", roleStrings).Order("created_on DESC").Find(&users).Error
+	err := r.db.WithContext(ctx).Where("role IN ?", roleStrings).Order("created_on DESC").Find(&users).Error
 	return users, err
 }
 
 // GetUsersByMultipleStatuses gets users by multiple statuses
-func (r *userRepository) GetUsersByMultipleStatuses(statuses []int) ([]models.User, error) {
+func (r *userRepository) GetUsersByMultipleStatuses(ctx context.Context, statuses []int) ([]models.User, error) {
 	if len(statuses) == 0 {
 		return []models.User{}, nil
 	}
@@ -594,51 +641,60 @@ func (r *userRepository) GetUsersByMultipleStatuses(statuses []int) ([]models.Us
 	}
 
 	var users []models.User
-	err := r.db.Where("status IN ?", validStatuses).Order("created_on DESC").Find(&users).Error
+	err := r.db.WithContext(ctx).Where("status IN ?", validStatuses).Order("created_on DESC").Find(&users).Error
 	return users, err
 }
 
 // CheckUserExists checks if a user exists by ID
-func (r *userRepository) CheckUserExists(userID uint) (bool, error) {
+func (r *userRepository) CheckUserExists(ctx context.Context, userID uint) (bool, error) {
 	if userID == 0 {
 		return false, fmt.Errorf("invalid user ID")
 	}
 
 	var count int64
-	err := r.db.Model(&models.User{}).Where("id = ?", userID).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Count(&count).Error
 	return count > 0, err
 }
 
+// HasDeletedProfile reports whether the user's teacher or student profile is soft-deleted
+func (r *userRepository) HasDeletedProfile(ctx context.Context, userID uint) (bool, error) {
+	var deleted bool
+	err := r.db.WithContext(ctx).Raw(`SELECT EXISTS (SELECT 1 FROM teacher WHERE user_id = @id AND deleted_at IS NOT NULL)
+		OR EXISTS (SELECT 1 FROM student WHERE user_id = @id AND deleted_at IS NOT NULL)`,
+		map[string]interface{}{"id": userID}).Scan(&deleted).Error
+	return deleted, err
+}
+
 // GetUserIDsByRole gets only user IDs for a specific role (lightweight query)
-func (r *userRepository) GetUserIDsByRole(role models.UserRole) ([]uint, error) {
+func (r *userRepository) GetUserIDsByRole(ctx context.Context, role models.UserRole) ([]uint, error) {
 	if !role.IsValid() {
 		return nil, gorm.ErrInvalidValue
 	}
 
 	var userIDs []uint
-	err := r.db.Model(&models.User{}).Where("role = ?", string(role)).Pluck("id", &userIDs).Error
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("role = ?", string(role)).Pluck("id", &userIDs).Error
 	return userIDs, err
 }
 
 // GetUserIDsByStatus gets only user IDs for a specific status (lightweight query)
-func (r *userRepository) GetUserIDsByStatus(status int) ([]uint, error) {
-	if status < 0 || status > 1 {
+func (r *userRepository) GetUserIDsByStatus(ctx context.Context, status models.Status) ([]uint, error) {
+	if !status.IsValid() {
 		return nil, gorm.ErrInvalidValue
 	}
 
 	var userIDs []uint
-	err := r.db.Model(&models.User{}).Where("status = ?", status).Pluck("id", &userIDs).Error
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("status = ?", status).Pluck("id", &userIDs).Error
 	return userIDs, err
 }
 
 // GetUserEmailsByRole gets only user emails for a specific role (for notifications, etc.)
-func (r *userRepository) GetUserEmailsByRole(role models.UserRole) ([]string, error) {
+func (r *userRepository) GetUserEmailsByRole(ctx context.Context, role models.UserRole) ([]string, error) {
 	if !role.IsValid() {
 		return nil, gorm.ErrInvalidValue
 	}
 
 	var emails []string
-	err := r.db.Model(&models.User{}).Where("role = ? AND status = 1", string(role)).Pluck("email", &emails).Error
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("role = ? AND status = 1", string(role)).Pluck("email", &emails).Error
 	return emails, err
 }
 
@@ -663,20 +719,78 @@ func (r *userRepository) UpdateUserInTransaction(tx *gorm.DB, user *models.User)
 	return tx.Save(user).Error
 }
 
+// UpdateUserStatusInTransaction changes a user's status within a transaction
+func (r *userRepository) UpdateUserStatusInTransaction(tx *gorm.DB, userID uint, status models.Status) error {
+	if userID == 0 {
+		return fmt.Errorf("invalid user ID")
+	}
+	if !status.IsValid() {
+		return gorm.ErrInvalidValue
+	}
+	return tx.Model(&models.User{}).Where("id = ?", userID).Update("status", status).Error
+}
+
+// BulkUpdateStatusInTransaction changes the status of several users within a transaction
+func (r *userRepository) BulkUpdateStatusInTransaction(tx *gorm.DB, userIDs []uint, status models.Status) error {
+	if len(userIDs) == 0 {
+		return fmt.Errorf("no user IDs provided")
+	}
+	if !status.IsValid() {
+		return gorm.ErrInvalidValue
+	}
+	return tx.Model(&models.User{}).Where("id IN ?", userIDs).Update("status", status).Error
+}
+
+// UpdateUserNameInTransaction changes a user's name within a transaction
+func (r *userRepository) UpdateUserNameInTransaction(tx *gorm.DB, userID uint, name string) error {
+	if userID == 0 {
+		return fmt.Errorf("invalid user ID")
+	}
+	return tx.Model(&models.User{}).Where("id = ?", userID).Update("name", name).Error
+}
+
+// UpdateUserFieldsInTransaction changes only the given columns of a user within a
+// transaction, leaving the rest of the row as it is
+func (r *userRepository) UpdateUserFieldsInTransaction(tx *gorm.DB, userID uint, fields map[string]interface{}) error {
+	if userID == 0 {
+		return fmt.Errorf("invalid user ID")
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return tx.Model(&models.User{}).Where("id = ?", userID).Updates(fields).Error
+}
+
+// DeleteUserInTransaction deletes a user within a transaction
+func (r *userRepository) DeleteUserInTransaction(tx *gorm.DB, userID uint) error {
+	if userID == 0 {
+		return fmt.Errorf("invalid user ID")
+	}
+	return tx.Delete(&models.User{}, userID).Error
+}
+
+// BulkDeleteInTransaction deletes several users within a transaction
+func (r *userRepository) BulkDeleteInTransaction(tx *gorm.DB, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return fmt.Errorf("no user IDs provided")
+	}
+	return tx.Where("id IN ?", userIDs).Delete(&models.User{}).Error
+}
+
 // BeginTransaction starts a new database transaction
-func (r *userRepository) BeginTransaction() *gorm.DB {
-	return r.db.Begin()
+func (r *userRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Begin()
 }
 
 // Performance and analytics methods
 
 // GetDailyUserRegistrations gets count of users registered per day for the last N days
-func (r *userRepository) GetDailyUserRegistrations(days int) (map[string]int64, error) {
+func (r *userRepository) GetDailyUserRegistrations(ctx context.Context, days int) (map[string]int64, error) {
 	if days <= 0 {
 		days = 7 // default to last 7 days
 	}
 
-	rows, err := r.db.Raw(`
+	rows, err := r.db.WithContext(ctx).Raw(`
 		SELECT DATE(created_on) as date, COUNT(*) as count 
 		FROM users 
 		WHERE created_on >= NOW() - INTERVAL ? DAY 
@@ -703,12 +817,12 @@ func (r *userRepository) GetDailyUserRegistrations(days int) (map[string]int64,
 }
 
 // GetMonthlyUserStats gets user statistics grouped by month
-func (r *userRepository) GetMonthlyUserStats(months int) (map[string]map[string]int64, error) {
+func (r *userRepository) GetMonthlyUserStats(ctx context.Context, months int) (map[string]map[string]int64, error) {
 	if months <= 0 {
 		months = 6 // default to last 6 months
 	}
 
-	rows, err := r.db.Raw(`
+	rows, err := r.db.WithContext(ctx).Raw(`
 		SELECT 
 			DATE_TRUNC('month', created_on) as month,
 			role,
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/testutil"
+)
+
+func TestUserCreateAndLookup(t *testing.T) {
+	repo := NewUserRepository(testutil.DB(t))
+	ctx := context.Background()
+
+	user := &models.User{
+		Name:     "Meera",
+		Email:    "meera@example.com",
+		Password: "not-a-real-hash",
+		Role:     models.RoleTeacher,
+		Status:   models.StatusActive,
+	}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	found, err := repo.GetByEmail(ctx, "meera@example.com")
+	if err != nil {
+		t.Fatalf("GetByEmail: %v", err)
+	}
+	if found.ID != user.ID || found.Role != models.RoleTeacher {
+		t.Errorf("GetByEmail = %+v, want user %d with role teacher", found, user.ID)
+	}
+
+	exists, err := repo.EmailExists(ctx, "meera@example.com")
+	if err != nil || !exists {
+		t.Errorf("EmailExists = %v, %v; want true", exists, err)
+	}
+	exists, err = repo.EmailExists(ctx, "meera@example.com", user.ID)
+	if err != nil || exists {
+		t.Errorf("EmailExists excluding the user = %v, %v; want false", exists, err)
+	}
+}
+
+func TestUserLookupStartsEmpty(t *testing.T) {
+	repo := NewUserRepository(testutil.DB(t))
+
+	// The user created by the test above was rolled back with its transaction
+	count, err := repo.GetUsersCount(context.Background())
+	if err != nil {
+		t.Fatalf("GetUsersCount: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("GetUsersCount = %d, want 0", count)
+	}
+}
+
+func TestUserRecordLogin(t *testing.T) {
+	db := testutil.DB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+	user := testutil.CreateUser(t, db, "Meera", models.RoleTeacher)
+
+	loggedInAt, err := repo.RecordLogin(ctx, user.ID, "203.0.113.7")
+	if err != nil {
+		t.Fatalf("RecordLogin: %v", err)
+	}
+
+	found, err := repo.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if found.LastLoginAt == nil || found.LastLoginAt.Sub(loggedInAt).Abs() > time.Millisecond {
+		t.Errorf("LastLoginAt = %v, want %v", found.LastLoginAt, loggedInAt)
+	}
+	if found.LastLoginIP != "203.0.113.7" {
+		t.Errorf("LastLoginIP = %q, want %q", found.LastLoginIP, "203.0.113.7")
+	}
+	if found.UpdatedOn.Sub(user.UpdatedOn).Abs() > time.Millisecond {
+		t.Errorf("UpdatedOn = %v, want it left at %v", found.UpdatedOn, user.UpdatedOn)
+	}
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// saveVersioned writes every column of record, like Save, but only while its stored
+// version is still expected, and bumps the version. version points at the record's
+// Version field. It returns ErrVersionConflict when someone else updated it first.
+func saveVersioned(tx *gorm.DB, record interface{}, version *uint, expected uint) error {
+	*version = expected + 1
+	result := tx.Model(record).Where("version = ?", expected).
+		Select("*").Omit(clause.Associations).Updates(record)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		*version = expected
+	}
+	return result.Error
+}
+
+// bumpVersion is the update of the version column made by writes that skip the check,
+// such as bulk operations, so editors holding an older version still get a conflict
+var bumpVersion = gorm.Expr("version + 1")
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	Record(ctx context.Context, event *models.WebhookEvent) (bool, error)
+	UpdateStatus(ctx context.Context, id uint, updates models.WebhookEvent) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+// Record saves a newly received event and reports true. When the provider already sent
+// the same event ID it counts the delivery, loads the stored event into event and
+// reports false.
+func (r *webhookRepository) Record(ctx context.Context, event *models.WebhookEvent) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "provider"}, {Name: "event_id"}},
+		DoNothing: true,
+	}).Create(event)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.RowsAffected > 0, result.Error
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.WebhookEvent{}).
+		Where("provider = ? AND event_id = ?", event.Provider, event.EventID)
+	if err := query.Update("deliveries", gorm.Expr("deliveries + 1")).Error; err != nil {
+		return false, err
+	}
+	err := r.db.WithContext(ctx).Where("provider = ? AND event_id = ?", event.Provider, event.EventID).First(event).Error
+	return false, err
+}
+
+// UpdateStatus saves the outcome of processing an event: its status, error, fee payment
+// and processing time
+func (r *webhookRepository) UpdateStatus(ctx context.Context, id uint, updates models.WebhookEvent) error {
+	return r.db.WithContext(ctx).Model(&models.WebhookEvent{}).Where("id = ?", id).
+		Select("status", "error", "fee_payment_id", "processed_at").
+		Updates(&updates).Error
+}
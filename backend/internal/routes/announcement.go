@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAnnouncementRoutes(router *gin.RouterGroup, announcementHandler *handlers.AnnouncementHandler) {
+	// Announcements managed by a business
+	businessAnnouncements := router.Group("/businesses/:businessId/announcements")
+	businessAnnouncements.Use(middleware.AuthMiddleware())
+	businessAnnouncements.Use(middleware.RateLimit("api"))
+	businessAnnouncements.Use(middleware.PermissionMiddleware(services.PermManageAnnouncements))
+	{
+		businessAnnouncements.GET("", announcementHandler.GetAnnouncements)
+		businessAnnouncements.POST("", announcementHandler.CreateAnnouncement)
+		businessAnnouncements.GET("/:announcementId", announcementHandler.GetAnnouncement)
+		businessAnnouncements.PUT("/:announcementId", announcementHandler.UpdateAnnouncement)
+		businessAnnouncements.DELETE("/:announcementId", announcementHandler.DeleteAnnouncement)
+	}
+
+	// Announcements addressed to the current student or teacher
+	myAnnouncements := router.Group("/my-announcements")
+	myAnnouncements.Use(middleware.AuthMiddleware())
+	myAnnouncements.Use(middleware.RateLimit("api"))
+	myAnnouncements.Use(middleware.PermissionMiddleware(services.PermReadAnnouncements))
+	{
+		myAnnouncements.GET("", announcementHandler.GetMyAnnouncements)
+		myAnnouncements.GET("/unread-count", announcementHandler.GetMyUnreadAnnouncementCount)
+		myAnnouncements.POST("/read-all", announcementHandler.MarkAllAnnouncementsRead)
+		myAnnouncements.POST("/:announcementId/read", announcementHandler.MarkAnnouncementRead)
+	}
+}
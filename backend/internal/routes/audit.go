@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAuditRoutes(router *gin.RouterGroup, auditHandler *handlers.AuditHandler) {
+	// Compliance reporting (admin only)
+	auditLogs := router.Group("/audit-logs")
+	auditLogs.Use(middleware.AuthMiddleware())
+	auditLogs.Use(middleware.RateLimit("api"))
+	auditLogs.Use(middleware.PermissionMiddleware(services.PermViewAuditLogs))
+	{
+		auditLogs.GET("/data-access", auditHandler.GetDataAccessLogs)
+	}
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupBatchRoutes(router *gin.RouterGroup, batchHandler *handlers.BatchHandler) {
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+
+	// Business batch management (for admins and business owners)
+	businessBatches := protected.Group("/businesses/:businessId/batches")
+	businessBatches.Use(middleware.PermissionMiddleware(services.PermManageBatches))
+	{
+		businessBatches.GET("", batchHandler.GetBatchesByBusiness)
+		businessBatches.POST("", batchHandler.CreateBatch)
+		businessBatches.GET("/:batchId", batchHandler.GetBatch)
+		businessBatches.PUT("/:batchId", batchHandler.UpdateBatch)
+		businessBatches.DELETE("/:batchId", batchHandler.DeleteBatch)
+	}
+
+	// Moving students between batches
+	studentBatch := protected.Group("/students/:id/batch")
+	studentBatch.Use(middleware.PermissionMiddleware(services.PermManageBatches))
+	{
+		studentBatch.PUT("", batchHandler.MoveStudentBatch)
+	}
+}
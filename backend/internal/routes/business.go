@@ -3,43 +3,60 @@ package routes
 import (
 	"backend/internal/handlers"
 	"backend/internal/middleware"
+	"backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupBusinessRoutes(router *gin.RouterGroup, businessHandler *handlers.BusinessHandler) {
 	// Public route - get business by slug (no auth required)
-	router.GET("/business/:slug", businessHandler.GetBusinessBySlug)
+	router.GET("/business/:slug", middleware.RateLimit("public"), businessHandler.GetBusinessBySlug)
+	router.GET("/business/:slug/banner", middleware.RateLimit("public"), businessHandler.GetBusinessBanner)
 
 	// Business profile routes (for business users)
 	businessProfile := router.Group("/my-business")
 	businessProfile.Use(middleware.AuthMiddleware())
-	businessProfile.Use(middleware.RoleMiddleware("business"))
+	businessProfile.Use(middleware.RateLimit("api"))
+	businessProfile.Use(middleware.PermissionMiddleware(services.PermManageOwnBusiness))
 	{
 		businessProfile.GET("", businessHandler.GetMyBusiness)
 		businessProfile.PUT("", businessHandler.UpdateMyBusiness)
+		businessProfile.POST("/purge-sample-data", businessHandler.PurgeSampleData)
+	}
+
+	// Public page banner; owners manage their own business, admins any
+	branding := router.Group("/businesses/:businessId/banner")
+	branding.Use(middleware.AuthMiddleware())
+	branding.Use(middleware.RateLimit("api"))
+	branding.Use(middleware.PermissionMiddleware(services.PermManageBranding))
+	{
+		branding.PUT("", middleware.BodyLimit("upload"), businessHandler.UploadBusinessBanner)
+		branding.DELETE("", businessHandler.DeleteBusinessBanner)
 	}
 
 	// Admin business management routes
 	businesses := router.Group("/businesses")
 	businesses.Use(middleware.AuthMiddleware())
-	businesses.Use(middleware.RoleMiddleware("admin"))
+	businesses.Use(middleware.RateLimit("api"))
+	businesses.Use(middleware.PermissionMiddleware(services.PermManageBusinesses))
 	{
 		// Essential CRUD operations
 		businesses.POST("", businessHandler.CreateBusiness)
 		businesses.GET("", businessHandler.GetBusinesses)
-		businesses.GET("/:id", businessHandler.GetBusiness)
-		businesses.PUT("/:id", businessHandler.UpdateBusiness)
-		businesses.DELETE("/:id", businessHandler.DeleteBusiness)
+		businesses.GET("/:businessId", businessHandler.GetBusiness)
+		businesses.PUT("/:businessId", businessHandler.UpdateBusiness)
+		businesses.DELETE("/:businessId", businessHandler.DeleteBusiness)
 
 		// Status management
-		businesses.PATCH("/:id/status", businessHandler.ChangeBusinessStatus)
+		businesses.PATCH("/:businessId/status", businessHandler.ChangeBusinessStatus)
+		businesses.POST("/:businessId/slug", businessHandler.ChangeBusinessSlug)
 		businesses.GET("/active", businessHandler.GetActiveBusinesses)
 		businesses.GET("/inactive", businessHandler.GetInactiveBusinesses)
+		businesses.GET("/at-risk", businessHandler.GetAtRiskBusinesses)
 
 		// Package management
-		businesses.POST("/:id/assign-package", businessHandler.AssignPackage)
-		businesses.DELETE("/:id/remove-package", businessHandler.RemovePackage)
+		businesses.POST("/:businessId/assign-package", businessHandler.AssignPackage)
+		businesses.DELETE("/:businessId/remove-package", businessHandler.RemovePackage)
 		businesses.GET("/package/:packageId", businessHandler.GetBusinessesByPackage)
 		businesses.GET("/no-package", businessHandler.GetBusinessesWithoutPackage)
 
@@ -50,13 +67,22 @@ func SetupBusinessRoutes(router *gin.RouterGroup, businessHandler *handlers.Busi
 		businesses.GET("/by-location", businessHandler.GetBusinessesByLocation)
 		businesses.GET("/locations", businessHandler.GetBusinessLocations)
 
-		// Statistics and reporting
-		businesses.GET("/stats", businessHandler.GetBusinessStats)
-		businesses.GET("/stats/locations", businessHandler.GetLocationStats)
-		businesses.GET("/stats/packages", businessHandler.GetPackageDistribution)
-
 		// Bulk operations
 		businesses.POST("/bulk/status", businessHandler.BulkUpdateStatus)
 		businesses.POST("/bulk/assign-package", businessHandler.BulkAssignPackage)
+		businesses.POST("/bulk/remove-package", businessHandler.BulkRemovePackage)
+		businesses.POST("/bulk/swap-package", businessHandler.SwapPackage)
+	}
+
+	// Platform statistics and reporting
+	stats := router.Group("/businesses/stats")
+	stats.Use(middleware.AuthMiddleware())
+	stats.Use(middleware.RateLimit("api"))
+	stats.Use(middleware.PermissionMiddleware(services.PermViewAdminStats))
+	{
+		stats.GET("", businessHandler.GetBusinessStats)
+		stats.GET("/locations", businessHandler.GetLocationStats)
+		stats.GET("/packages", businessHandler.GetPackageDistribution)
+		stats.GET("/timeseries", businessHandler.GetGrowthTimeseries)
 	}
 }
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupBusinessArchiveRoutes(router *gin.RouterGroup, archiveHandler *handlers.BusinessArchiveHandler) {
+	// Backup and restore of a whole business (admin only)
+	archives := router.Group("/businesses")
+	archives.Use(middleware.AuthMiddleware())
+	archives.Use(middleware.RateLimit("api"))
+	archives.Use(middleware.PermissionMiddleware(services.PermManageBusinessArchives))
+	{
+		archives.GET("/:businessId/export-archive", archiveHandler.ExportBusinessArchive)
+		archives.POST("/import-archive", middleware.BodyLimit("archive"), archiveHandler.ImportBusinessArchive)
+	}
+}
@@ -0,0 +1,103 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"backend/internal/handlers"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/internal/testutil"
+	"backend/pkg/cache"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// packageSnapshot is every business's package and expiry, and the package history size
+type packageSnapshot struct {
+	Packages map[uint]string
+	History  int64
+}
+
+func snapshotPackages(t *testing.T, db *gorm.DB) packageSnapshot {
+	t.Helper()
+	var businesses []models.Business
+	if err := db.Find(&businesses).Error; err != nil {
+		t.Fatalf("failed to read businesses: %v", err)
+	}
+	snapshot := packageSnapshot{Packages: map[uint]string{}}
+	for _, business := range businesses {
+		var packageID, expires interface{} = "none", "none"
+		if business.PackageID != nil {
+			packageID = *business.PackageID
+		}
+		if business.PackageExpiresAt != nil {
+			expires = business.PackageExpiresAt.UTC()
+		}
+		snapshot.Packages[business.ID] = fmt.Sprint(packageID, " ", expires)
+	}
+	if err := db.Model(&models.BusinessPackageHistory{}).Count(&snapshot.History).Error; err != nil {
+		t.Fatalf("failed to count package history: %v", err)
+	}
+	return snapshot
+}
+
+// Removing or swapping packages that match no business answers with nothing updated,
+// or the reason it can't, and leaves every business and the history as they were
+func TestPackageChangesWithNoMatches(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	basic, premium, legacy := f.Packages["Basic"], f.Packages["Premium"], f.Packages["Legacy"]
+	unpackaged := testutil.CreateBusiness(t, db, "Starlight Classes", models.StatusActive, nil)
+
+	service := services.NewBusinessService(repository.NewBusinessRepository(db), repository.NewUserRepository(db),
+		repository.NewPackageRepository(db), cache.Noop{}, nil, nil, nil, nil)
+	r := gin.New()
+	SetupBusinessRoutes(r.Group("/api"), handlers.NewBusinessHandler(service))
+	token := tokenFor(t, "admin")
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"remove from a business without a package", "/api/businesses/bulk/remove-package",
+			fmt.Sprintf(`{"business_ids": [%d]}`, unpackaged.ID), http.StatusOK},
+		{"remove from businesses that don't exist", "/api/businesses/bulk/remove-package",
+			`{"business_ids": [999998, 999999]}`, http.StatusOK},
+		{"remove from no businesses", "/api/businesses/bulk/remove-package",
+			`{"business_ids": []}`, http.StatusBadRequest},
+		{"swap from a package no business is on", "/api/businesses/bulk/swap-package",
+			fmt.Sprintf(`{"from_package_id": %d, "to_package_id": %d}`, premium.ID, basic.ID), http.StatusOK},
+		{"swap from a package that doesn't exist", "/api/businesses/bulk/swap-package",
+			fmt.Sprintf(`{"from_package_id": 999999, "to_package_id": %d}`, premium.ID), http.StatusOK},
+		{"swap to a package that doesn't exist", "/api/businesses/bulk/swap-package",
+			fmt.Sprintf(`{"from_package_id": %d, "to_package_id": 999999}`, basic.ID), http.StatusNotFound},
+		{"swap to an inactive package", "/api/businesses/bulk/swap-package",
+			fmt.Sprintf(`{"from_package_id": %d, "to_package_id": %d}`, basic.ID, legacy.ID), http.StatusBadRequest},
+		{"swap to the same package", "/api/businesses/bulk/swap-package",
+			fmt.Sprintf(`{"from_package_id": %d, "to_package_id": %d}`, basic.ID, basic.ID), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := snapshotPackages(t, db)
+
+			w := serve(r, http.MethodPost, tt.path, token, tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body %s", w.Code, tt.status, w.Body.String())
+			}
+			if tt.status == http.StatusOK && w.Body.String() != `{"updated":0}` {
+				t.Errorf("body = %s, want nothing updated", w.Body.String())
+			}
+
+			if after := snapshotPackages(t, db); !reflect.DeepEqual(after, before) {
+				t.Errorf("packages changed from %+v to %+v", before, after)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupBusinessSignupRoutes(router *gin.RouterGroup, signupHandler *handlers.BusinessSignupHandler) {
+	// Public route, limited like the login since anyone can store a sign-up and notify
+	// the admins with it
+	router.POST("/business-signups", middleware.RateLimit("auth"), middleware.BodyLimit("auth"), signupHandler.SubmitBusinessSignup)
+
+	// Admin only routes
+	signups := router.Group("/business-signups")
+	signups.Use(middleware.AuthMiddleware())
+	signups.Use(middleware.RateLimit("api"))
+	signups.Use(middleware.PermissionMiddleware(services.PermManageBusinessSignups))
+	{
+		signups.GET("", signupHandler.GetBusinessSignups)
+		signups.GET("/:signupId", signupHandler.GetBusinessSignup)
+		signups.POST("/:signupId/approve", signupHandler.ApproveBusinessSignup)
+		signups.POST("/:signupId/reject", signupHandler.RejectBusinessSignup)
+	}
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupBusinessSnapshotRoutes(router *gin.RouterGroup, snapshotHandler *handlers.BusinessSnapshotHandler) {
+	// Trends of the current business (for business users)
+	myTrends := router.Group("/my-business/trends")
+	myTrends.Use(middleware.AuthMiddleware())
+	myTrends.Use(middleware.RateLimit("api"))
+	myTrends.Use(middleware.PermissionMiddleware(services.PermViewTrends))
+	{
+		myTrends.GET("", snapshotHandler.GetMyBusinessTrends)
+	}
+
+	// Trends of any business for admins, of their own for business users
+	trends := router.Group("/businesses/:businessId/trends")
+	trends.Use(middleware.AuthMiddleware())
+	trends.Use(middleware.RateLimit("api"))
+	trends.Use(middleware.PermissionMiddleware(services.PermViewTrends))
+	{
+		trends.GET("", snapshotHandler.GetBusinessTrends)
+	}
+
+	// Admin only routes
+	admin := router.Group("/admin")
+	admin.Use(middleware.AuthMiddleware())
+	admin.Use(middleware.RateLimit("api"))
+	admin.Use(middleware.PermissionMiddleware(services.PermManageSnapshots))
+	{
+		admin.POST("/business-snapshots", snapshotHandler.RunBusinessSnapshots)
+	}
+}
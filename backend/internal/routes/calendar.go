@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupCalendarRoutes(router *gin.RouterGroup, calendarHandler *handlers.CalendarHandler) {
+	// Public route, authenticated by the token in the feed URL
+	router.GET("/my-business/calendar.ics", middleware.RateLimit("public"), calendarHandler.GetCalendarFeed)
+
+	// Feed link management (for business users)
+	calendarToken := router.Group("/my-business/calendar-token")
+	calendarToken.Use(middleware.AuthMiddleware())
+	calendarToken.Use(middleware.RateLimit("api"))
+	calendarToken.Use(middleware.PermissionMiddleware(services.PermManageCalendarFeed))
+	{
+		calendarToken.POST("", calendarHandler.IssueCalendarToken)
+		calendarToken.DELETE("", calendarHandler.RevokeCalendarToken)
+	}
+}
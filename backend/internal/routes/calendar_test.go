@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"backend/internal/handlers"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/internal/testutil"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// A feed link works until it is replaced or revoked, and only then stops
+func TestCalendarFeedTokens(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	sunrise := f.Businesses["Sunrise Academy"]
+
+	service := services.NewCalendarService(repository.NewBusinessRepository(db), repository.NewBatchRepository(db),
+		repository.NewExamRepository(db), repository.NewTeacherAvailabilityRepository(db), services.CalendarConfig{})
+	r := gin.New()
+	SetupCalendarRoutes(r.Group("/api"), handlers.NewCalendarHandler(service))
+	owner := ownerToken(t, sunrise)
+
+	issue := func() string {
+		t.Helper()
+		w := serve(r, http.MethodPost, "/api/my-business/calendar-token", owner, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("issue: status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+		}
+		var response struct {
+			Data models.CalendarFeedResponse `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Data.Token == "" {
+			t.Fatalf("issue: no token in %s", w.Body.String())
+		}
+		return response.Data.Token
+	}
+	feed := func(token string) int {
+		return serve(r, http.MethodGet, "/api/my-business/calendar.ics?token="+url.QueryEscape(token), "", "").Code
+	}
+
+	first := issue()
+	w := serve(r, http.MethodGet, "/api/my-business/calendar.ics?token="+url.QueryEscape(first), "", "")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/calendar") {
+		t.Fatalf("feed = %d %q, want 200 text/calendar", w.Code, w.Header().Get("Content-Type"))
+	}
+	if !strings.HasPrefix(w.Body.String(), "BEGIN:VCALENDAR\r\n") || !strings.Contains(w.Body.String(), "X-WR-CALNAME:Sunrise Academy\r\n") {
+		t.Errorf("feed isn't Sunrise's calendar: %q", w.Body.String())
+	}
+
+	// Issuing again rotates the link: the old one stops working
+	second := issue()
+	if status := feed(first); status != http.StatusUnauthorized {
+		t.Errorf("rotated-out token: status = %d, want %d", status, http.StatusUnauthorized)
+	}
+	if status := feed(second); status != http.StatusOK {
+		t.Errorf("current token: status = %d, want %d", status, http.StatusOK)
+	}
+
+	// A token signed for the business but never issued to it is refused too
+	forged, err := utils.GenerateCalendarToken(sunrise.ID, "never-issued")
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	for name, token := range map[string]string{"never issued": forged, "malformed": "not-a-token", "missing": ""} {
+		if status := feed(token); status != http.StatusUnauthorized {
+			t.Errorf("%s token: status = %d, want %d", name, status, http.StatusUnauthorized)
+		}
+	}
+
+	if w := serve(r, http.MethodDelete, "/api/my-business/calendar-token", owner, ""); w.Code != http.StatusOK {
+		t.Fatalf("revoke: status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if status := feed(second); status != http.StatusUnauthorized {
+		t.Errorf("revoked token: status = %d, want %d", status, http.StatusUnauthorized)
+	}
+	// Nothing left to revoke
+	if w := serve(r, http.MethodDelete, "/api/my-business/calendar-token", owner, ""); w.Code != http.StatusNotFound {
+		t.Errorf("second revoke: status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,87 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend/internal/dto"
+	"backend/internal/handlers"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/internal/testutil"
+	"backend/pkg/cache"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// An email already used by a user or business, or a slug another business has, is a
+// 409 and not a server error
+func TestUniqueConflicts(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	sunrise, moonlight := f.Businesses["Sunrise Academy"], f.Businesses["Moonlight Tutors"]
+
+	users, businesses := repository.NewUserRepository(db), repository.NewBusinessRepository(db)
+	r := gin.New()
+	api := r.Group("/api")
+	SetupUserRoutes(api, handlers.NewUserHandler(services.NewUserService(users, businesses,
+		repository.NewTeacherRepository(db), repository.NewStudentRepository(db))))
+	SetupBusinessRoutes(api, handlers.NewBusinessHandler(services.NewBusinessService(businesses, users,
+		repository.NewPackageRepository(db), cache.Noop{}, nil, nil, nil, nil)))
+	token, err := utils.GenerateToken(f.Admin.ID, f.Admin.Email, string(models.RoleAdmin), utils.TokenScope{})
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		error   string
+		details string
+	}{
+		{"user email in another case", "/api/register", `{"name": "Asha", "email": "ASHA.teacher@example.com", "password": "secret123"}`,
+			"user with this email already exists", "users"},
+		{"business email", "/api/register", fmt.Sprintf(`{"name": "Owner", "email": %q, "password": "secret123"}`, sunrise.Email),
+			"business email already exists", "business"},
+		{"slug of another business", fmt.Sprintf("/api/businesses/%d/slug", moonlight.ID), fmt.Sprintf(`{"slug": %q}`, sunrise.Slug),
+			"business slug already exists", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusConflict {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusConflict, w.Body.String())
+			}
+			var resp dto.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Error != tt.error {
+				t.Errorf("error = %q, want %q", resp.Error, tt.error)
+			}
+			if details, _ := resp.Details.(string); details != tt.details {
+				t.Errorf("details = %v, want %q", resp.Details, tt.details)
+			}
+		})
+	}
+
+	var slug string
+	if err := db.Model(&models.Business{}).Where("id = ?", moonlight.ID).Pluck("slug", &slug).Error; err != nil {
+		t.Fatalf("failed to read slug: %v", err)
+	}
+	if slug != moonlight.Slug {
+		t.Errorf("slug = %q, want it unchanged", slug)
+	}
+}
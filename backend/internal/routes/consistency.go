@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupConsistencyRoutes(router *gin.RouterGroup, consistencyHandler *handlers.ConsistencyHandler) {
+	// Admin only routes
+	admin := router.Group("/admin")
+	admin.Use(middleware.AuthMiddleware())
+	admin.Use(middleware.RateLimit("api"))
+	admin.Use(middleware.PermissionMiddleware(services.PermManageDataConsistency))
+	{
+		admin.GET("/consistency-check", consistencyHandler.CheckConsistency)
+		admin.POST("/consistency-repair", consistencyHandler.RepairConsistency)
+	}
+}
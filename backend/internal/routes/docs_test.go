@@ -0,0 +1,157 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// swaggerSpec is the part of docs/swagger.json the checks read
+type swaggerSpec struct {
+	BasePath string                                 `json:"basePath"`
+	Paths    map[string]map[string]swaggerOperation `json:"paths"`
+}
+
+type swaggerOperation struct {
+	Responses map[string]json.RawMessage `json:"responses"`
+}
+
+func loadSwagger(t *testing.T) swaggerSpec {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "docs", "swagger.json"))
+	if err != nil {
+		t.Fatalf("failed to read swagger.json: %v", err)
+	}
+	var spec swaggerSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("failed to decode swagger.json: %v", err)
+	}
+	return spec
+}
+
+// swaggerPath turns a route such as /api/v1/students/:id into its documented path,
+// /students/{id}
+func swaggerPath(basePath, route string) string {
+	parts := strings.Split(strings.TrimPrefix(route, basePath), "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// Every route of the versioned API is documented, and documents the 401 and 403 its
+// guards can answer with
+func TestDocsMatchRouteGuards(t *testing.T) {
+	spec := loadSwagger(t)
+	r := newGuardedRouter()
+	none := tokenFor(t, "nobody")
+
+	checked := 0
+	for _, route := range r.Routes() {
+		if !strings.HasPrefix(route.Path, spec.BasePath+"/") {
+			continue
+		}
+		name := route.Method + " " + route.Path
+		operation, ok := spec.Paths[swaggerPath(spec.BasePath, route.Path)][strings.ToLower(route.Method)]
+		if !ok {
+			t.Errorf("%s is not documented", name)
+			continue
+		}
+		checked++
+
+		path := requestPath(route.Path)
+		if serve(r, route.Method, path, "", "{}").Code == http.StatusUnauthorized {
+			if _, ok := operation.Responses["401"]; !ok {
+				t.Errorf("%s requires a token but doesn't document 401", name)
+			}
+		}
+		if serve(r, route.Method, path, none, "{}").Code == http.StatusForbidden {
+			if _, ok := operation.Responses["403"]; !ok {
+				t.Errorf("%s is guarded by a permission but doesn't document 403", name)
+			}
+		}
+	}
+	if checked == 0 {
+		t.Fatal("no documented routes found")
+	}
+}
+
+// untypedMap matches a map[string]interface{} response in an annotation, bare or as the
+// data of a dto.Response
+var untypedMap = regexp.MustCompile(`@(Success|Failure)\s.*map\[string\]interface\{\}`)
+
+// Responses are typed: no annotation names map[string]interface{}, and no documented
+// response is an object of arbitrary values
+func TestDocsHaveNoUntypedResponses(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "handlers", "*.go"))
+	if err != nil {
+		t.Fatalf("failed to list handlers: %v", err)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		for i, line := range strings.Split(string(data), "\n") {
+			if untypedMap.MatchString(line) {
+				t.Errorf("%s:%d: untyped response annotation: %s", filepath.Base(file), i+1, strings.TrimSpace(line))
+			}
+		}
+	}
+
+	spec := loadSwagger(t)
+	for path, operations := range spec.Paths {
+		for method, operation := range operations {
+			for code, response := range operation.Responses {
+				if untypedObject(response) {
+					t.Errorf("%s %s responds %s with an object of arbitrary values", strings.ToUpper(method), path, code)
+				}
+			}
+		}
+	}
+}
+
+// untypedObject reports whether a schema, or any schema nested in it, allows values of
+// any type, which is how swag renders interface{} map values
+func untypedObject(raw json.RawMessage) bool {
+	var node interface{}
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return false
+	}
+	var walk func(node interface{}) bool
+	walk = func(node interface{}) bool {
+		switch node := node.(type) {
+		case map[string]interface{}:
+			if values, ok := node["additionalProperties"]; ok {
+				if values == true {
+					return true
+				}
+				if schema, ok := values.(map[string]interface{}); ok && len(schema) == 0 {
+					return true
+				}
+			}
+			for _, child := range node {
+				if walk(child) {
+					return true
+				}
+			}
+		case []interface{}:
+			for _, child := range node {
+				if walk(child) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return walk(node)
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupDocumentRoutes(router *gin.RouterGroup, documentHandler *handlers.DocumentHandler) {
+	// Protected routes; business owners print for their own students only
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+	protected.Use(middleware.PermissionMiddleware(services.PermPrintDocuments))
+	{
+		protected.GET("/students/:id/report-card.pdf", documentHandler.GetStudentReportCard)
+		protected.GET("/payments/:id/receipt.pdf", documentHandler.GetPaymentReceipt)
+		protected.PUT("/businesses/:businessId/logo", middleware.BodyLimit("upload"), documentHandler.UploadBusinessLogo)
+		protected.DELETE("/businesses/:businessId/logo", documentHandler.DeleteBusinessLogo)
+	}
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupExamRoutes(router *gin.RouterGroup, examHandler *handlers.ExamHandler) {
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+	protected.Use(middleware.PermissionMiddleware(services.PermManageExams))
+
+	// Exams and results of a business
+	businessExams := protected.Group("/businesses/:businessId/exams")
+	{
+		businessExams.GET("", examHandler.GetExams)
+		businessExams.POST("", examHandler.CreateExam)
+		businessExams.GET("/:examId", examHandler.GetExam)
+		businessExams.PUT("/:examId", examHandler.UpdateExam)
+		businessExams.DELETE("/:examId", examHandler.DeleteExam)
+		businessExams.GET("/:examId/results", examHandler.GetExamResults)
+		businessExams.PUT("/:examId/results", examHandler.RecordExamResults)
+		businessExams.DELETE("/:examId/results/:studentId", examHandler.DeleteExamResult)
+		businessExams.GET("/:examId/stats", examHandler.GetExamStats)
+	}
+
+	// Exam report of a single student
+	studentExams := protected.Group("/students/:id")
+	{
+		studentExams.GET("/exam-report", examHandler.GetStudentExamReport)
+	}
+}
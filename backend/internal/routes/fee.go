@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupFeeRoutes(router *gin.RouterGroup, feeHandler *handlers.FeeHandler) {
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+	protected.Use(middleware.PermissionMiddleware(services.PermManageFees))
+
+	// Fees of a single student
+	studentFees := protected.Group("/students/:id/fees")
+	{
+		studentFees.GET("/dues", feeHandler.GetStudentFeeDues)
+		studentFees.GET("/payments", feeHandler.GetStudentFeePayments)
+	}
+
+	// Fees of a business
+	businessFees := protected.Group("/businesses/:businessId/fees")
+	{
+		businessFees.GET("/plans", feeHandler.GetFeePlans)
+		businessFees.POST("/plans", feeHandler.CreateFeePlan)
+		businessFees.GET("/payments", feeHandler.GetFeePayments)
+		businessFees.POST("/payments", feeHandler.RecordFeePayment)
+		businessFees.GET("/dues", feeHandler.GetBusinessFeeDues)
+		businessFees.GET("/summary", feeHandler.GetFeeSummary)
+		businessFees.POST("/reminders", feeHandler.SendFeeReminders)
+	}
+}
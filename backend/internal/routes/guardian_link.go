@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupGuardianLinkRoutes(router *gin.RouterGroup, guardianLinkHandler *handlers.GuardianLinkHandler) {
+	// Public routes, authorised by the guardian token itself
+	router.GET("/guardian/:token", middleware.RateLimit("public"), guardianLinkHandler.GetGuardianView)
+	router.GET("/guardian/:token/photo", middleware.RateLimit("public"), guardianLinkHandler.GetGuardianPhoto)
+
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+	protected.Use(middleware.PermissionMiddleware(services.PermManageGuardianLinks))
+
+	// Guardian links of a single student
+	studentLinks := protected.Group("/students/:id")
+	{
+		studentLinks.POST("/guardian-link", guardianLinkHandler.CreateGuardianLink)
+		studentLinks.GET("/guardian-links", guardianLinkHandler.GetGuardianLinks)
+		studentLinks.DELETE("/guardian-links/:linkId", guardianLinkHandler.RevokeGuardianLink)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthPaths are the probe endpoints, which stay reachable during maintenance
+var HealthPaths = []string{"/healthz", "/readyz", "/version"}
+
+// SetupHealthRoutes registers the probes at the root, outside /api and without auth,
+// so load balancers can reach them
+func SetupHealthRoutes(router *gin.Engine, healthHandler *handlers.HealthHandler) {
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+	router.GET("/version", healthHandler.Version)
+}
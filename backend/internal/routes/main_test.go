@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"backend/internal/middleware"
+	"backend/internal/models"
+	"backend/internal/testutil"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	utils.ConfigureJWT(utils.JWTConfig{Secret: "routes-test-secret"})
+	middleware.ConfigureRateLimits(middleware.RateLimitConfig{Enabled: false})
+	os.Exit(testutil.Main(m))
+}
+
+// tokenFor returns a bearer token for a user acting as role, with a business and
+// profile so guards that read them find some
+func tokenFor(t *testing.T, role string) string {
+	t.Helper()
+
+	token, err := utils.GenerateToken(1, role+"@example.com", role, utils.TokenScope{BusinessID: 1, TeacherID: 1, StudentID: 1})
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	return token
+}
+
+// ownerToken returns a bearer token for the owner of a fixture business
+func ownerToken(t *testing.T, business models.Business) string {
+	t.Helper()
+
+	token, err := utils.GenerateToken(business.UserID, business.Email, string(models.RoleBusiness), utils.TokenScope{BusinessID: business.ID})
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	return token
+}
+
+// serve sends a request to r, with the token when it isn't empty
+func serve(r http.Handler, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenancePath is the toggle endpoint, relative to the API version group. The
+// maintenance middleware leaves it reachable so the mode can be switched off again.
+const MaintenancePath = "/admin/maintenance"
+
+func SetupMaintenanceRoutes(router *gin.RouterGroup, maintenanceHandler *handlers.MaintenanceHandler) {
+	maintenance := router.Group(MaintenancePath)
+	maintenance.Use(middleware.AuthMiddleware())
+	maintenance.Use(middleware.PermissionMiddleware(services.PermManageMaintenance))
+	{
+		maintenance.GET("", maintenanceHandler.GetMaintenance)
+		maintenance.PUT("", maintenanceHandler.UpdateMaintenance)
+	}
+}
@@ -0,0 +1,111 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"backend/internal/handlers"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+// businessIDs collects every business_id in a decoded JSON body
+func businessIDs(value interface{}) []uint {
+	var ids []uint
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if id, ok := field.(float64); ok && key == "business_id" {
+				ids = append(ids, uint(id))
+				continue
+			}
+			ids = append(ids, businessIDs(field)...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			ids = append(ids, businessIDs(item)...)
+		}
+	}
+	return ids
+}
+
+// The /my-business listings show the owner's own teachers and students, whatever business
+// the query names, and no one but business owners may use them
+func TestMyBusinessListingsAreScoped(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	sunrise, moonlight := f.Businesses["Sunrise Academy"], f.Businesses["Moonlight Tutors"]
+
+	teacherService := services.NewTeacherService(repository.NewTeacherRepository(db), repository.NewUserRepository(db),
+		repository.NewBusinessRepository(db), repository.NewSubjectRepository(db), repository.NewTeacherAvailabilityRepository(db),
+		repository.NewTeacherDocumentRepository(db), repository.NewTeacherStudentRepository(db), nil, repository.NewQualificationRepository(db))
+	studentService := services.NewStudentService(repository.NewStudentRepository(db), repository.NewUserRepository(db),
+		repository.NewBusinessRepository(db), repository.NewBatchRepository(db), repository.NewStudentFieldRepository(db))
+	r := gin.New()
+	SetupTeacherRoutes(r.Group("/api"), handlers.NewTeacherHandler(teacherService, nil))
+	SetupStudentRoutes(r.Group("/api"), handlers.NewStudentHandler(studentService, nil))
+
+	// Each listing would find Moonlight's Dev or Farah if it weren't scoped
+	listings := []string{
+		"/api/my-business/teachers",
+		"/api/my-business/teachers/active",
+		"/api/my-business/teachers/inactive",
+		"/api/my-business/teachers/recent",
+		"/api/my-business/teachers/search?q=a",
+		"/api/my-business/students",
+		"/api/my-business/students/active",
+		"/api/my-business/students/inactive",
+		"/api/my-business/students/recent",
+		"/api/my-business/students/search?q=a",
+	}
+
+	t.Run("owner", func(t *testing.T) {
+		token := ownerToken(t, sunrise)
+		for _, path := range listings {
+			// A business_id naming another business is ignored
+			separator := "?"
+			if strings.Contains(path, "?") {
+				separator = "&"
+			}
+			path := path + fmt.Sprintf("%sbusiness_id=%d", separator, moonlight.ID)
+
+			w := serve(r, http.MethodGet, path, token, "")
+			if w.Code != http.StatusOK {
+				t.Errorf("GET %s: status = %d, want %d; body %s", path, w.Code, http.StatusOK, w.Body.String())
+				continue
+			}
+			var body interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("GET %s: failed to decode body: %v", path, err)
+			}
+			for _, id := range businessIDs(body) {
+				if id != sunrise.ID {
+					t.Errorf("GET %s: found a row of business %d, want only %d's", path, id, sunrise.ID)
+				}
+			}
+			for _, name := range []string{`"Dev"`, `"Farah"`} {
+				if strings.Contains(w.Body.String(), name) {
+					t.Errorf("GET %s: found Moonlight's %s", path, name)
+				}
+			}
+		}
+	})
+
+	for _, role := range []models.UserRole{models.RoleAdmin, models.RoleTeacher, models.RoleStudent} {
+		t.Run(string(role), func(t *testing.T) {
+			token := tokenFor(t, string(role))
+			for _, path := range listings {
+				if w := serve(r, http.MethodGet, path, token, ""); w.Code != http.StatusForbidden {
+					t.Errorf("GET %s: status = %d, want %d", path, w.Code, http.StatusForbidden)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupNotificationRoutes(router *gin.RouterGroup, notificationHandler *handlers.NotificationHandler) {
+	// Notification feed of the current user, whatever their role
+	notifications := router.Group("/notifications")
+	notifications.Use(middleware.AuthMiddleware())
+	notifications.Use(middleware.RateLimit("api"))
+	{
+		notifications.GET("", notificationHandler.GetNotifications)
+		notifications.GET("/unread-count", notificationHandler.GetUnreadNotificationCount)
+		notifications.POST("/read-all", notificationHandler.MarkAllNotificationsRead)
+		notifications.POST("/:id/read", notificationHandler.MarkNotificationRead)
+	}
+}
@@ -3,32 +3,41 @@ package routes
 import (
 	"backend/internal/handlers"
 	"backend/internal/middleware"
+	"backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupPackageRoutes(router *gin.RouterGroup, packageHandler *handlers.PackageHandler) {
+	// Public route - active packages for pricing pages (no auth required)
+	router.GET("/packages/public", middleware.RateLimit("public"), packageHandler.GetPublicPackages)
+
 	packages := router.Group("/packages")
 	packages.Use(middleware.AuthMiddleware())
+	packages.Use(middleware.RateLimit("api"))
 
-	// Public routes (for authenticated users)
-	packages.GET("", packageHandler.GetPackages)
-	packages.GET("/:id", packageHandler.GetPackage)
-	packages.GET("/active", packageHandler.GetActivePackages)
-	packages.GET("/search", packageHandler.SearchPackages)
-	packages.GET("/price-range", packageHandler.GetPackagesByPriceRange)
+	// Package catalogue for the business upgrade screen; inactive packages stay hidden from businesses
+	catalogue := packages.Group("")
+	catalogue.Use(middleware.PermissionMiddleware(services.PermViewPackages))
+	{
+		catalogue.GET("/active", packageHandler.GetActivePackages)
+		catalogue.GET("/:id", packageHandler.GetPackage)
+	}
 
-	// Admin and Business only routes
-	adminBusinessRoutes := packages.Group("")
-	adminBusinessRoutes.Use(middleware.RoleMiddleware("admin", "business"))
+	// Admin package management
+	adminRoutes := packages.Group("")
+	adminRoutes.Use(middleware.PermissionMiddleware(services.PermManagePackages))
 	{
-		adminBusinessRoutes.POST("", packageHandler.CreatePackage)
-		adminBusinessRoutes.PUT("/:id", packageHandler.UpdatePackage)
-		adminBusinessRoutes.DELETE("/:id", packageHandler.DeletePackage)
-		adminBusinessRoutes.GET("/inactive", packageHandler.GetInactivePackages)
-		adminBusinessRoutes.PATCH("/:id/status", packageHandler.ChangePackageStatus)
-		adminBusinessRoutes.GET("/stats", packageHandler.GetPackageStats)
-		adminBusinessRoutes.GET("/stats/prices", packageHandler.GetPriceStatistics)
-		adminBusinessRoutes.PATCH("/bulk/status", packageHandler.BulkUpdatePackageStatus)
+		adminRoutes.GET("", packageHandler.GetPackages)
+		adminRoutes.GET("/search", packageHandler.SearchPackages)
+		adminRoutes.GET("/price-range", packageHandler.GetPackagesByPriceRange)
+		adminRoutes.POST("", packageHandler.CreatePackage)
+		adminRoutes.PUT("/:id", packageHandler.UpdatePackage)
+		adminRoutes.DELETE("/:id", packageHandler.DeletePackage)
+		adminRoutes.GET("/inactive", packageHandler.GetInactivePackages)
+		adminRoutes.PATCH("/:id/status", packageHandler.ChangePackageStatus)
+		adminRoutes.GET("/stats", packageHandler.GetPackageStats)
+		adminRoutes.GET("/stats/prices", packageHandler.GetPriceStatistics)
+		adminRoutes.PATCH("/bulk/status", packageHandler.BulkUpdatePackageStatus)
 	}
 }
@@ -0,0 +1,106 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"backend/internal/handlers"
+	"backend/internal/models"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Packages are a global catalogue, so only admins may change them
+func TestPackageMutationsAreAdminOnly(t *testing.T) {
+	r := gin.New()
+	// The guard rejects callers before the handler needs its service
+	SetupPackageRoutes(r.Group("/api"), handlers.NewPackageHandler(nil))
+
+	mutations := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/api/packages"},
+		{http.MethodPut, "/api/packages/1"},
+		{http.MethodDelete, "/api/packages/1"},
+		{http.MethodPatch, "/api/packages/1/status"},
+		{http.MethodPatch, "/api/packages/bulk/status"},
+	}
+	for _, role := range []string{"business", "teacher", "student"} {
+		token := tokenFor(t, role)
+		for _, m := range mutations {
+			t.Run(role+" "+m.method+" "+m.path, func(t *testing.T) {
+				w := serve(r, m.method, m.path, token, `{}`)
+				if w.Code != http.StatusForbidden {
+					t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+				}
+			})
+		}
+	}
+
+	// Admins get past the guard to the handler, which rejects the empty body
+	w := serve(r, http.MethodPost, "/api/packages", tokenFor(t, "admin"), `{}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("admin create: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+// catalogue serves a fixed active and inactive package
+type catalogue struct {
+	services.PackageService
+}
+
+var cataloguePackages = map[uint]models.PackageResponse{
+	1: {ID: 1, Name: "Basic", Status: models.StatusActive},
+	2: {ID: 2, Name: "Legacy", Status: models.StatusInactive},
+}
+
+func (catalogue) GetActivePackages(ctx context.Context) ([]models.PackageResponse, error) {
+	return []models.PackageResponse{cataloguePackages[1]}, nil
+}
+
+func (catalogue) GetPackageByID(ctx context.Context, id uint) (*models.PackageResponse, error) {
+	pkg, ok := cataloguePackages[id]
+	if !ok {
+		return nil, errors.New("package not found")
+	}
+	return &pkg, nil
+}
+
+// Businesses read the active packages to choose an upgrade; inactive ones stay hidden
+// from them, and teachers and students don't see the catalogue at all
+func TestPackageCatalogueReads(t *testing.T) {
+	r := gin.New()
+	SetupPackageRoutes(r.Group("/api"), handlers.NewPackageHandler(catalogue{}))
+
+	tests := []struct {
+		role   string
+		path   string
+		status int
+	}{
+		{"business", "/api/packages/active", http.StatusOK},
+		{"business", "/api/packages/1", http.StatusOK},
+		{"business", "/api/packages/2", http.StatusNotFound},
+		{"admin", "/api/packages/active", http.StatusOK},
+		{"admin", "/api/packages/2", http.StatusOK},
+		{"teacher", "/api/packages/active", http.StatusForbidden},
+		{"teacher", "/api/packages/1", http.StatusForbidden},
+		{"student", "/api/packages/active", http.StatusForbidden},
+		{"student", "/api/packages/1", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role+" "+tt.path, func(t *testing.T) {
+			w := serve(r, http.MethodGet, tt.path, tokenFor(t, tt.role), "")
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			if tt.status == http.StatusOK && !strings.Contains(w.Body.String(), `"name":"`) {
+				t.Errorf("body = %s, want the package data", w.Body.String())
+			}
+		})
+	}
+}
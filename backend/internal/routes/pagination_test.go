@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"backend/internal/dto"
+	"backend/internal/handlers"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pagedPackages has 25 packages and records the filters of the last list
+type pagedPackages struct {
+	repository.PackageRepository
+	filters repository.PackageFilters
+}
+
+func (p *pagedPackages) GetAll(ctx context.Context, filters repository.PackageFilters) ([]models.Package, int64, error) {
+	p.filters = filters
+	return nil, 25, nil
+}
+
+// A list without a usable page or limit gets the first page of the default size, a limit
+// over the maximum is cut down to it, and the pagination in the response says so
+func TestListPaginationDefaults(t *testing.T) {
+	packages := &pagedPackages{}
+	r := gin.New()
+	SetupPackageRoutes(r.Group("/api"), handlers.NewPackageHandler(services.NewPackageService(packages, nil)))
+	token := tokenFor(t, "admin")
+
+	tests := []struct {
+		name      string
+		query     string
+		wantPage  int
+		wantLimit int
+	}{
+		{"missing", "", 1, 10},
+		{"zero", "?page=0&limit=0", 1, 10},
+		{"negative", "?page=-2&limit=-5", 1, 10},
+		{"not numbers", "?page=abc&limit=ten", 1, 10},
+		{"given", "?page=2&limit=5", 2, 5},
+		{"over the maximum", "?limit=500", 1, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, http.MethodGet, "/api/packages"+tt.query, token, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+			}
+			if packages.filters.Page != tt.wantPage || packages.filters.Limit != tt.wantLimit {
+				t.Errorf("query page, limit = %d, %d, want %d, %d",
+					packages.filters.Page, packages.filters.Limit, tt.wantPage, tt.wantLimit)
+			}
+
+			var response dto.ListResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			pagination := response.Pagination
+			wantPages := (25 + tt.wantLimit - 1) / tt.wantLimit
+			if pagination.Page != tt.wantPage || pagination.Limit != tt.wantLimit || pagination.TotalPages != wantPages {
+				t.Errorf("pagination = %+v, want page %d, limit %d of %d pages", pagination, tt.wantPage, tt.wantLimit, wantPages)
+			}
+			if pagination.HasPrev != (tt.wantPage > 1) || pagination.HasNext != (tt.wantPage < wantPages) {
+				t.Errorf("has_prev, has_next = %v, %v for page %d of %d", pagination.HasPrev, pagination.HasNext, tt.wantPage, wantPages)
+			}
+		})
+	}
+}
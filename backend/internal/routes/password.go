@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPasswordRoutes(router *gin.RouterGroup, passwordHandler *handlers.PasswordHandler) {
+	// Public routes
+	router.POST("/forgot-password", middleware.RateLimit("auth"), middleware.BodyLimit("auth"), passwordHandler.ForgotPassword)
+	router.POST("/reset-password", middleware.RateLimit("auth"), middleware.BodyLimit("auth"), passwordHandler.ResetPassword)
+}
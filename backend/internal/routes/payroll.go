@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPayrollRoutes(router *gin.RouterGroup, payrollHandler *handlers.PayrollHandler) {
+	// Protected routes
+	protected := router.Group("/businesses/:businessId/payroll")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+	protected.Use(middleware.PermissionMiddleware(services.PermManagePayroll))
+	{
+		protected.GET("", payrollHandler.GetPayroll)
+		protected.POST("/finalize", payrollHandler.FinalizePayroll)
+		protected.GET("/adjustments", payrollHandler.GetPayrollAdjustments)
+		protected.POST("/adjustments", payrollHandler.CreatePayrollAdjustment)
+		protected.DELETE("/adjustments/:adjustmentId", payrollHandler.DeletePayrollAdjustment)
+		protected.GET("/settings", payrollHandler.GetPayrollSettings)
+		protected.PUT("/settings", payrollHandler.UpdatePayrollSettings)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPermissionRoutes(router *gin.RouterGroup, permissionHandler *handlers.PermissionHandler) {
+	me := router.Group("/me")
+	me.Use(middleware.AuthMiddleware())
+	me.Use(middleware.RateLimit("api"))
+	{
+		me.GET("/permissions", permissionHandler.GetMyPermissions)
+	}
+}
@@ -0,0 +1,194 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"backend/internal/dto"
+	"backend/internal/handlers"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The profile lookups of /me/permissions find no profile, which still lists capabilities
+type noBusiness struct{ repository.BusinessRepository }
+type noTeacher struct{ repository.TeacherRepository }
+type noStudent struct{ repository.StudentRepository }
+
+var errNoProfile = errors.New("record not found")
+
+func (noBusiness) GetByUserID(ctx context.Context, userID uint) (*models.Business, error) {
+	return nil, errNoProfile
+}
+
+func (noTeacher) GetByUserID(ctx context.Context, userID uint) (*models.Teacher, error) {
+	return nil, errNoProfile
+}
+
+func (noStudent) GetByUserID(ctx context.Context, userID uint) (*models.Student, error) {
+	return nil, errNoProfile
+}
+
+// newGuardedRouter mounts the whole API. Only the permissions handler has a service:
+// the other handlers are never reached by callers the guards stop, and the panics of
+// those reached are recovered.
+func newGuardedRouter() *gin.Engine {
+	r := gin.New()
+	r.Use(gin.RecoveryWithWriter(io.Discard))
+	permissions := services.NewPermissionService(noBusiness{}, noTeacher{}, noStudent{})
+	SetupAPIRoutes(r, Handlers{Permission: handlers.NewPermissionHandler(permissions)})
+	return r
+}
+
+// requestPath fills a route's parameters with 1
+func requestPath(route string) string {
+	parts := strings.Split(route, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			parts[i] = "1"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// guardedBy is the permission a route guard refused the request for, if any
+func guardedBy(w *http.Response, body []byte) (services.Permission, bool) {
+	if w.StatusCode != http.StatusForbidden {
+		return "", false
+	}
+	var forbidden dto.ForbiddenResponse
+	if err := json.Unmarshal(body, &forbidden); err != nil || forbidden.Permission == "" {
+		return "", false
+	}
+	return services.Permission(forbidden.Permission), true
+}
+
+func send(r http.Handler, method, path, token string) (services.Permission, bool) {
+	w := serve(r, method, path, token, "{}")
+	return guardedBy(w.Result(), w.Body.Bytes())
+}
+
+// Every role is stopped by exactly the guards whose permission it lacks, and
+// /me/permissions reports the same for every permission
+func TestPermissionsMatchRouteGuards(t *testing.T) {
+	r := newGuardedRouter()
+	roles := []string{string(models.RoleAdmin), string(models.RoleBusiness), string(models.RoleTeacher), string(models.RoleStudent)}
+
+	// A role holding no permission learns the outermost guard of every route
+	guards := map[string]services.Permission{}
+	none := tokenFor(t, "nobody")
+	for _, route := range r.Routes() {
+		if permission, ok := send(r, route.Method, requestPath(route.Path), none); ok {
+			guards[route.Method+" "+route.Path] = permission
+		}
+	}
+	if len(guards) == 0 {
+		t.Fatal("no guarded routes found")
+	}
+
+	used := map[services.Permission]bool{}
+	for _, permission := range guards {
+		used[permission] = true
+	}
+	for _, permission := range services.Permissions() {
+		if !used[permission] {
+			t.Errorf("permission %s guards no route", permission)
+		}
+	}
+
+	for _, role := range roles {
+		token := tokenFor(t, role)
+
+		w := serve(r, http.MethodGet, "/api/v1/me/permissions", token, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: /me/permissions status = %d; body %s", role, w.Code, w.Body.String())
+		}
+		var response struct {
+			Data models.PermissionsResponse `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: failed to decode /me/permissions: %v", role, err)
+		}
+		capabilities := response.Data.Capabilities
+		if response.Data.Role != role || len(capabilities) != len(services.Permissions()) {
+			t.Errorf("%s: /me/permissions = %+v, want the role and every permission", role, response.Data)
+		}
+
+		for route, permission := range guards {
+			method, path, _ := strings.Cut(route, " ")
+			can := capabilities["can_"+string(permission)]
+			if can != services.RoleHasPermission(role, permission) {
+				t.Errorf("%s: can_%s = %v, want what the guards enforce", role, permission, can)
+			}
+
+			refused, ok := send(r, method, requestPath(path), token)
+			switch {
+			case !can && (!ok || refused != permission):
+				t.Errorf("%s %s: %s lacks %s but was not refused for it", method, path, role, permission)
+			case can && ok && capabilities["can_"+string(refused)]:
+				t.Errorf("%s %s: %s was refused for %s, which /me/permissions says it holds", method, path, role, refused)
+			}
+		}
+	}
+}
+
+// A token acting as a profile passes the guards of that profile's routes and no
+// others, whatever the user's primary role
+func TestActiveProfileGuards(t *testing.T) {
+	r := newGuardedRouter()
+
+	tests := []struct {
+		name    string
+		role    models.UserRole // the user's primary role
+		profile models.UserRole // the profile the token acts as, empty for the primary role
+		allowed []string
+		denied  []string
+	}{
+		{"admin", models.RoleAdmin, "",
+			[]string{"/businesses", "/teachers", "/students"},
+			[]string{"/my-business", "/my-teacher-profile", "/my-student-profile"}},
+		{"business", models.RoleBusiness, "",
+			[]string{"/my-business", "/my-business/teachers", "/my-business/students"},
+			[]string{"/businesses", "/teachers", "/my-teacher-profile", "/my-student-profile"}},
+		{"business acting as teacher", models.RoleBusiness, models.RoleTeacher,
+			[]string{"/my-teacher-profile"},
+			[]string{"/my-business", "/my-business/teachers", "/my-business/students", "/teachers", "/my-student-profile"}},
+		{"teacher", models.RoleTeacher, "",
+			[]string{"/my-teacher-profile"},
+			[]string{"/my-business", "/teachers", "/my-student-profile"}},
+		{"student", models.RoleStudent, "",
+			[]string{"/my-student-profile"},
+			[]string{"/my-business", "/students", "/my-teacher-profile"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := utils.GenerateToken(1, "user@example.com", string(tt.role),
+				utils.TokenScope{Profile: string(tt.profile), BusinessID: 1, TeacherID: 1, StudentID: 1})
+			if err != nil {
+				t.Fatalf("failed to generate token: %v", err)
+			}
+
+			for _, path := range tt.allowed {
+				w := serve(r, http.MethodGet, "/api/v1"+path, token, "")
+				if w.Code == http.StatusUnauthorized || w.Code == http.StatusForbidden {
+					t.Errorf("GET %s: status = %d, want it let through; body %s", path, w.Code, w.Body.String())
+				}
+			}
+			for _, path := range tt.denied {
+				w := serve(r, http.MethodGet, "/api/v1"+path, token, "")
+				if w.Code != http.StatusForbidden {
+					t.Errorf("GET %s: status = %d, want %d", path, w.Code, http.StatusForbidden)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPhotoRoutes(router *gin.RouterGroup, photoHandler *handlers.PhotoHandler) {
+	// Protected routes; photos belong to the business, which manages them for its own people
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+
+	studentPhoto := protected.Group("/students/:id/photo")
+	studentPhoto.Use(middleware.PermissionMiddleware(services.PermManageStudents))
+	{
+		studentPhoto.GET("", photoHandler.GetStudentPhoto)
+		studentPhoto.PUT("", middleware.BodyLimit("upload"), photoHandler.UploadStudentPhoto)
+		studentPhoto.DELETE("", photoHandler.DeleteStudentPhoto)
+	}
+
+	teacherPhoto := protected.Group("/teachers/:id/photo")
+	teacherPhoto.Use(middleware.PermissionMiddleware(services.PermManageTeachers))
+	{
+		teacherPhoto.GET("", photoHandler.GetTeacherPhoto)
+		teacherPhoto.PUT("", middleware.BodyLimit("upload"), photoHandler.UploadTeacherPhoto)
+		teacherPhoto.DELETE("", photoHandler.DeleteTeacherPhoto)
+	}
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupQualificationRoutes(router *gin.RouterGroup, qualificationHandler *handlers.QualificationHandler) {
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+
+	// Global qualification taxonomy (admin only)
+	qualifications := protected.Group("/qualifications")
+	qualifications.Use(middleware.PermissionMiddleware(services.PermManageQualifications))
+	{
+		qualifications.GET("", qualificationHandler.GetQualifications)
+		qualifications.POST("", qualificationHandler.CreateQualification)
+		qualifications.POST("/migrate", qualificationHandler.MigrateTeacherQualifications)
+		qualifications.PUT("/:qualificationId", qualificationHandler.UpdateQualification)
+		qualifications.DELETE("/:qualificationId", qualificationHandler.DeleteQualification)
+	}
+
+	// A business's custom qualifications (for admins and business owners)
+	businessQualifications := protected.Group("/businesses/:businessId/qualifications")
+	businessQualifications.Use(middleware.PermissionMiddleware(services.PermManageTeachers))
+	{
+		businessQualifications.GET("", qualificationHandler.GetBusinessQualifications)
+		businessQualifications.POST("", qualificationHandler.CreateBusinessQualification)
+		businessQualifications.PUT("/:qualificationId", qualificationHandler.UpdateBusinessQualification)
+		businessQualifications.DELETE("/:qualificationId", qualificationHandler.DeleteBusinessQualification)
+	}
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupReportRoutes(router *gin.RouterGroup, reportHandler *handlers.ReportHandler) {
+	// Subscriptions of the current user
+	subscriptions := router.Group("/profile/report-subscriptions")
+	subscriptions.Use(middleware.AuthMiddleware())
+	subscriptions.Use(middleware.RateLimit("api"))
+	subscriptions.Use(middleware.PermissionMiddleware(services.PermManageReportSubscriptions))
+	{
+		subscriptions.GET("", reportHandler.GetReportSubscriptions)
+		subscriptions.PUT("", reportHandler.UpdateReportSubscriptions)
+	}
+
+	// Admin only routes
+	admin := router.Group("/admin/reports")
+	admin.Use(middleware.AuthMiddleware())
+	admin.Use(middleware.RateLimit("api"))
+	admin.Use(middleware.PermissionMiddleware(services.PermSendReports))
+	{
+		admin.POST("/send", reportHandler.SendReport)
+	}
+}
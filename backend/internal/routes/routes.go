@@ -0,0 +1,128 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers holds every handler served by the versioned API
+type Handlers struct {
+	User              *handlers.UserHandler
+	Package           *handlers.PackageHandler
+	Business          *handlers.BusinessHandler
+	Student           *handlers.StudentHandler
+	StudentField      *handlers.StudentFieldHandler
+	StudentAttendance *handlers.StudentAttendanceHandler
+	StudentTimeline   *handlers.StudentTimelineHandler
+	Teacher           *handlers.TeacherHandler
+	TeacherAttendance *handlers.TeacherAttendanceHandler
+	TeacherDocument   *handlers.TeacherDocumentHandler
+	TeacherStudent    *handlers.TeacherStudentHandler
+	Subject           *handlers.SubjectHandler
+	Qualification     *handlers.QualificationHandler
+	Batch             *handlers.BatchHandler
+	Fee               *handlers.FeeHandler
+	Payroll           *handlers.PayrollHandler
+	Exam              *handlers.ExamHandler
+	Announcement      *handlers.AnnouncementHandler
+	GuardianLink      *handlers.GuardianLinkHandler
+	Maintenance       *handlers.MaintenanceHandler
+	Consistency       *handlers.ConsistencyHandler
+	Search            *handlers.SearchHandler
+	Password          *handlers.PasswordHandler
+	SMS               *handlers.SMSHandler
+	Report            *handlers.ReportHandler
+	Document          *handlers.DocumentHandler
+	Notification      *handlers.NotificationHandler
+	Webhook           *handlers.WebhookHandler
+	Calendar          *handlers.CalendarHandler
+	BusinessArchive   *handlers.BusinessArchiveHandler
+	BusinessSignup    *handlers.BusinessSignupHandler
+	BusinessSnapshot  *handlers.BusinessSnapshotHandler
+	Permission        *handlers.PermissionHandler
+	Audit             *handlers.AuditHandler
+	Photo             *handlers.PhotoHandler
+}
+
+// APIVersion is one version of the API, mounted at /api/<Name>. A later version reuses
+// the same handlers through its own Register; handlers that serialize differently
+// per version read it with middleware.APIVersion.
+type APIVersion struct {
+	Name     string
+	Register func(router *gin.RouterGroup, h Handlers)
+}
+
+// Versions lists the API versions being served
+var Versions = []APIVersion{
+	{Name: "v1", Register: RegisterV1Routes},
+}
+
+// LegacyVersion is also served unprefixed under /api, for clients that predate versioning
+const LegacyVersion = "v1"
+
+// SetupAPIRoutes mounts every API version, plus the legacy /api alias
+func SetupAPIRoutes(router *gin.Engine, h Handlers) {
+	for _, version := range Versions {
+		group := router.Group("/api/" + version.Name)
+		group.Use(middleware.APIVersionMiddleware(version.Name))
+		version.Register(group, h)
+
+		if version.Name == LegacyVersion {
+			legacy := router.Group("/api")
+			legacy.Use(middleware.APIVersionMiddleware(version.Name))
+			version.Register(legacy, h)
+		}
+	}
+}
+
+// RegisterV1Routes registers the v1 API on router
+func RegisterV1Routes(router *gin.RouterGroup, h Handlers) {
+	SetupUserRoutes(router, h.User)
+	SetupPackageRoutes(router, h.Package)
+	SetupBusinessRoutes(router, h.Business)
+	SetupStudentRoutes(router, h.Student)
+	SetupStudentFieldRoutes(router, h.StudentField)
+	SetupStudentAttendanceRoutes(router, h.StudentAttendance)
+	SetupStudentTimelineRoutes(router, h.StudentTimeline)
+	SetupTeacherRoutes(router, h.Teacher)
+	SetupTeacherAttendanceRoutes(router, h.TeacherAttendance)
+	SetupTeacherDocumentRoutes(router, h.TeacherDocument)
+	SetupTeacherStudentRoutes(router, h.TeacherStudent)
+	SetupSubjectRoutes(router, h.Subject)
+	SetupQualificationRoutes(router, h.Qualification)
+	SetupBatchRoutes(router, h.Batch)
+	SetupFeeRoutes(router, h.Fee)
+	SetupPayrollRoutes(router, h.Payroll)
+	SetupExamRoutes(router, h.Exam)
+	SetupAnnouncementRoutes(router, h.Announcement)
+	SetupGuardianLinkRoutes(router, h.GuardianLink)
+	SetupMaintenanceRoutes(router, h.Maintenance)
+	SetupConsistencyRoutes(router, h.Consistency)
+	SetupSearchRoutes(router, h.Search)
+	SetupPasswordRoutes(router, h.Password)
+	SetupSMSRoutes(router, h.SMS)
+	SetupReportRoutes(router, h.Report)
+	SetupDocumentRoutes(router, h.Document)
+	SetupNotificationRoutes(router, h.Notification)
+	SetupWebhookRoutes(router, h.Webhook)
+	SetupCalendarRoutes(router, h.Calendar)
+	SetupBusinessArchiveRoutes(router, h.BusinessArchive)
+	SetupBusinessSignupRoutes(router, h.BusinessSignup)
+	SetupBusinessSnapshotRoutes(router, h.BusinessSnapshot)
+	SetupPermissionRoutes(router, h.Permission)
+	SetupAuditRoutes(router, h.Audit)
+	SetupPhotoRoutes(router, h.Photo)
+}
+
+// MaintenanceExemptPaths lists the routes the maintenance middleware lets through: the
+// health probes and the maintenance toggle of every version
+func MaintenanceExemptPaths() []string {
+	paths := append([]string{}, HealthPaths...)
+	paths = append(paths, "/api"+MaintenancePath)
+	for _, version := range Versions {
+		paths = append(paths, "/api/"+version.Name+MaintenancePath)
+	}
+	return paths
+}
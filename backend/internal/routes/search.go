@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSearchRoutes(router *gin.RouterGroup, searchHandler *handlers.SearchHandler) {
+	search := router.Group("/search")
+	search.Use(middleware.AuthMiddleware())
+	search.Use(middleware.RateLimit("api"))
+	search.Use(middleware.PermissionMiddleware(services.PermSearch))
+	{
+		search.GET("", searchHandler.Search)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSMSRoutes(router *gin.RouterGroup, smsHandler *handlers.SMSHandler) {
+	// Public route, authenticated by the provider's signature
+	router.POST("/sms/status", middleware.RateLimit("public"), smsHandler.SMSStatusCallback)
+
+	// Protected routes
+	protected := router.Group("/businesses/:businessId/sms")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+	protected.Use(middleware.PermissionMiddleware(services.PermManageSMS))
+	{
+		protected.GET("/settings", smsHandler.GetSMSSettings)
+		protected.PUT("/settings", smsHandler.UpdateSMSSettings)
+		protected.GET("/messages", smsHandler.GetSMSMessages)
+	}
+}
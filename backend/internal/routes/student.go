@@ -3,23 +3,23 @@ package routes
 import (
 	"backend/internal/handlers"
 	"backend/internal/middleware"
+	"backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupStudentRoutes(router *gin.Engine, studentHandler *handlers.StudentHandler) {
-	api := router.Group("/api")
-
+func SetupStudentRoutes(router *gin.RouterGroup, studentHandler *handlers.StudentHandler) {
 	// Public routes (if any)
 	// None for students - all require authentication
 
 	// Protected routes
-	protected := api.Group("")
+	protected := router.Group("")
 	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
 
 	// Student profile routes (for student users)
 	studentProfile := protected.Group("/my-student-profile")
-	studentProfile.Use(middleware.RoleMiddleware("student"))
+	studentProfile.Use(middleware.PermissionMiddleware(services.PermManageOwnStudentProfile))
 	{
 		studentProfile.GET("", studentHandler.GetMyStudentProfile)
 		studentProfile.PUT("", studentHandler.UpdateMyStudentProfile)
@@ -27,12 +27,13 @@ func SetupStudentRoutes(router *gin.Engine, studentHandler *handlers.StudentHand
 
 	// Admin-only student management routes
 	adminStudents := protected.Group("/students")
-	adminStudents.Use(middleware.RoleMiddleware("admin"))
+	adminStudents.Use(middleware.PermissionMiddleware(services.PermManageAllStudents))
 	{
 		adminStudents.POST("", studentHandler.CreateStudent)
 		adminStudents.GET("", studentHandler.GetStudents)
 		adminStudents.GET("/search", studentHandler.SearchStudents)
 		adminStudents.GET("/stats", studentHandler.GetStudentStats)
+		adminStudents.GET("/recent", studentHandler.GetRecentStudents)
 		adminStudents.GET("/stats/guardians", studentHandler.GetGuardianStats)
 		adminStudents.GET("/active", studentHandler.GetActiveStudents)
 		adminStudents.GET("/inactive", studentHandler.GetInactiveStudents)
@@ -40,15 +41,35 @@ func SetupStudentRoutes(router *gin.Engine, studentHandler *handlers.StudentHand
 		adminStudents.GET("/:id", studentHandler.GetStudent)
 		adminStudents.PUT("/:id", studentHandler.UpdateStudent)
 		adminStudents.DELETE("/:id", studentHandler.DeleteStudent)
+		adminStudents.POST("/:id/restore", studentHandler.RestoreStudent)
 		adminStudents.PATCH("/:id/status", studentHandler.ChangeStudentStatus)
 	}
 
+	// Bulk delete is scoped per caller in the service, so business owners may use it too
+	protected.POST("/students/bulk/delete", middleware.PermissionMiddleware(services.PermManageStudents), studentHandler.BulkDeleteStudents)
+
 	// Business-specific student routes (for business owners)
 	businessStudents := protected.Group("/businesses/:businessId/students")
-	businessStudents.Use(middleware.RoleMiddleware("admin", "business"))
+	businessStudents.Use(middleware.PermissionMiddleware(services.PermManageStudents))
 	{
 		businessStudents.GET("", studentHandler.GetStudentsByBusiness)
 		businessStudents.GET("/active", studentHandler.GetActiveStudentsByBusiness)
 		businessStudents.GET("/inactive", studentHandler.GetInactiveStudentsByBusiness)
+		businessStudents.POST("/import", middleware.BodyLimit("import"), studentHandler.ImportStudents)
+		businessStudents.GET("/export", studentHandler.ExportStudents)
+		businessStudents.GET("/code-settings", studentHandler.GetStudentCodeSettings)
+		businessStudents.PUT("/code-settings", studentHandler.UpdateStudentCodeSettings)
+	}
+
+	// The caller's own business's students, resolved from the token (for business owners)
+	myBusinessStudents := protected.Group("/my-business/students")
+	myBusinessStudents.Use(middleware.PermissionMiddleware(services.PermManageOwnBusiness))
+	{
+		myBusinessStudents.GET("", studentHandler.GetMyBusinessStudents)
+		myBusinessStudents.GET("/search", studentHandler.SearchMyBusinessStudents)
+		myBusinessStudents.GET("/stats", studentHandler.GetMyBusinessStudentStats)
+		myBusinessStudents.GET("/recent", studentHandler.GetMyBusinessRecentStudents)
+		myBusinessStudents.GET("/active", studentHandler.GetMyBusinessActiveStudents)
+		myBusinessStudents.GET("/inactive", studentHandler.GetMyBusinessInactiveStudents)
 	}
 }
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupStudentAttendanceRoutes(router *gin.RouterGroup, attendanceHandler *handlers.StudentAttendanceHandler) {
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+	protected.Use(middleware.PermissionMiddleware(services.PermManageAttendance))
+
+	// Attendance of a single student
+	studentAttendance := protected.Group("/students/:id/attendance")
+	{
+		studentAttendance.GET("", attendanceHandler.GetStudentAttendance)
+		studentAttendance.GET("/summary", attendanceHandler.GetStudentAttendanceSummary)
+	}
+
+	// Attendance of all students of a business
+	businessAttendance := protected.Group("/businesses/:businessId/students/attendance")
+	{
+		businessAttendance.GET("", attendanceHandler.GetBusinessStudentAttendance)
+		businessAttendance.POST("", attendanceHandler.BulkMarkStudentAttendance)
+		businessAttendance.GET("/summary", attendanceHandler.GetBusinessStudentAttendanceSummary)
+	}
+}
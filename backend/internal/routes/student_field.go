@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupStudentFieldRoutes(router *gin.RouterGroup, fieldHandler *handlers.StudentFieldHandler) {
+	// Custom student fields of the current business (for business users)
+	studentFields := router.Group("/my-business/student-fields")
+	studentFields.Use(middleware.AuthMiddleware())
+	studentFields.Use(middleware.RateLimit("api"))
+	studentFields.Use(middleware.PermissionMiddleware(services.PermManageStudentFields))
+	{
+		studentFields.GET("", fieldHandler.GetStudentFields)
+		studentFields.POST("", fieldHandler.CreateStudentField)
+		studentFields.PUT("/settings", fieldHandler.UpdateStudentFieldSettings)
+		studentFields.PUT("/:fieldId", fieldHandler.UpdateStudentField)
+		studentFields.DELETE("/:fieldId", fieldHandler.DeleteStudentField)
+	}
+
+	// Grades students of the current business can be placed in
+	studentGrades := router.Group("/my-business/student-grades")
+	studentGrades.Use(middleware.AuthMiddleware())
+	studentGrades.Use(middleware.RateLimit("api"))
+	studentGrades.Use(middleware.PermissionMiddleware(services.PermManageStudentFields))
+	{
+		studentGrades.GET("", fieldHandler.GetStudentGrades)
+		studentGrades.PUT("", fieldHandler.ReplaceStudentGrades)
+	}
+}
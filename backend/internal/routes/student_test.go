@@ -0,0 +1,112 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"backend/internal/handlers"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/internal/testutil"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Students edit only their name and the information fields opened to them, and see their
+// business without the owner's contact details
+func TestStudentSelfUpdate(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	sunrise, aarav := f.Businesses["Sunrise Academy"], f.Students["Aarav"]
+
+	if err := db.Model(&models.Student{}).Where("id = ?", aarav.ID).Updates(map[string]interface{}{
+		"guardian_name": "Meera", "guardian_number": "+919800000001", "information": models.JSONB{"blood_group": "B+"},
+	}).Error; err != nil {
+		t.Fatalf("failed to set guardian: %v", err)
+	}
+	for _, field := range []models.StudentField{
+		{BusinessID: sunrise.ID, Key: "nickname", Label: "Nickname", Type: models.StudentFieldText, StudentEditable: true},
+		{BusinessID: sunrise.ID, Key: "blood_group", Label: "Blood group", Type: models.StudentFieldText},
+	} {
+		if err := db.Create(&field).Error; err != nil {
+			t.Fatalf("failed to create field %s: %v", field.Key, err)
+		}
+	}
+
+	service := services.NewStudentService(repository.NewStudentRepository(db), repository.NewUserRepository(db),
+		repository.NewBusinessRepository(db), repository.NewBatchRepository(db), repository.NewStudentFieldRepository(db))
+	r := gin.New()
+	SetupStudentRoutes(r.Group("/api"), handlers.NewStudentHandler(service, nil))
+	token, err := utils.GenerateToken(aarav.UserID, "aarav.student@example.com", string(models.RoleStudent),
+		utils.TokenScope{BusinessID: sunrise.ID, StudentID: aarav.ID})
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	read := func() models.Student {
+		t.Helper()
+		var student models.Student
+		if err := db.First(&student, aarav.ID).Error; err != nil {
+			t.Fatalf("failed to read student: %v", err)
+		}
+		return student
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		check  func(t *testing.T, student models.Student)
+	}{
+		{"guardian fields are ignored", `{"name": "Aarav Shah", "guardian_name": "Someone", "guardian_number": "+911111111111", "guardian_email": "x@example.com"}`, http.StatusOK, func(t *testing.T, student models.Student) {
+			if student.Name != "Aarav Shah" {
+				t.Errorf("Name = %q, want %q", student.Name, "Aarav Shah")
+			}
+			if student.GuardianName != "Meera" || student.GuardianNumber != "+919800000001" || student.GuardianEmail != "" {
+				t.Errorf("guardian = %q %q %q, want it unchanged", student.GuardianName, student.GuardianNumber, student.GuardianEmail)
+			}
+		}},
+		{"field not open to students", `{"information": {"blood_group": "O+"}}`, http.StatusBadRequest, func(t *testing.T, student models.Student) {
+			if student.Information["blood_group"] != "B+" {
+				t.Errorf("blood_group = %v, want it unchanged", student.Information["blood_group"])
+			}
+		}},
+		{"field open to students", `{"information": {"nickname": "Ari"}}`, http.StatusOK, func(t *testing.T, student models.Student) {
+			if student.Information["nickname"] != "Ari" || student.Information["blood_group"] != "B+" {
+				t.Errorf("information = %v, want the nickname added", student.Information)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, http.MethodPut, "/api/my-student-profile", token, tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body %s", w.Code, tt.status, w.Body.String())
+			}
+			tt.check(t, read())
+		})
+	}
+
+	t.Run("owner details are hidden", func(t *testing.T) {
+		var owner models.User
+		if err := db.First(&owner, sunrise.UserID).Error; err != nil {
+			t.Fatalf("failed to read owner: %v", err)
+		}
+		w := serve(r, http.MethodGet, "/api/my-student-profile", token, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d; body %s", w.Code, http.StatusOK, w.Body.String())
+		}
+		body := w.Body.String()
+		if !strings.Contains(body, `"name":"Sunrise Academy"`) {
+			t.Errorf("body %s, want the business summary", body)
+		}
+		for _, detail := range []string{sunrise.Email, owner.Email, `"owner_name"`} {
+			if strings.Contains(body, detail) {
+				t.Errorf("body contains %s: %s", detail, body)
+			}
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupStudentTimelineRoutes(router *gin.RouterGroup, timelineHandler *handlers.StudentTimelineHandler) {
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+
+	// Activity feed of a single student (for admins and the owning business)
+	studentTimeline := protected.Group("/students/:id/timeline")
+	studentTimeline.Use(middleware.PermissionMiddleware(services.PermManageStudents))
+	{
+		studentTimeline.GET("", timelineHandler.GetStudentTimeline)
+	}
+}
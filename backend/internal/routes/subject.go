@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSubjectRoutes(router *gin.RouterGroup, subjectHandler *handlers.SubjectHandler) {
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+
+	// Business subject list management (for admins and business owners)
+	businessSubjects := protected.Group("/businesses/:businessId/subjects")
+	businessSubjects.Use(middleware.PermissionMiddleware(services.PermManageSubjects))
+	{
+		businessSubjects.GET("", subjectHandler.GetSubjectsByBusiness)
+		businessSubjects.POST("", subjectHandler.CreateSubject)
+		businessSubjects.PUT("/:subjectId", subjectHandler.UpdateSubject)
+		businessSubjects.DELETE("/:subjectId", subjectHandler.DeleteSubject)
+	}
+}
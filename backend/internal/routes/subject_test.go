@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"backend/internal/handlers"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+// A business manages only its own subject list; another business's is forbidden
+func TestSubjectsAreScopedToTheOwner(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	sunrise, moonlight := f.Businesses["Sunrise Academy"], f.Businesses["Moonlight Tutors"]
+
+	subject := models.Subject{BusinessID: moonlight.ID, Name: "Physics"}
+	if err := db.Create(&subject).Error; err != nil {
+		t.Fatalf("failed to create subject: %v", err)
+	}
+
+	service := services.NewSubjectService(repository.NewSubjectRepository(db), repository.NewBusinessRepository(db))
+	r := gin.New()
+	SetupSubjectRoutes(r.Group("/api"), handlers.NewSubjectHandler(service))
+	token := ownerToken(t, sunrise)
+
+	others := fmt.Sprintf("/api/businesses/%d/subjects", moonlight.ID)
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, others, ""},
+		{http.MethodPost, others, `{"name": "Chemistry"}`},
+		{http.MethodPut, fmt.Sprintf("%s/%d", others, subject.ID), `{"name": "Renamed"}`},
+		{http.MethodDelete, fmt.Sprintf("%s/%d", others, subject.ID), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" another business", func(t *testing.T) {
+			if w := serve(r, tt.method, tt.path, token, tt.body); w.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want %d: %s", w.Code, http.StatusForbidden, w.Body.String())
+			}
+		})
+	}
+
+	var subjects []models.Subject
+	if err := db.Where("business_id = ?", moonlight.ID).Find(&subjects).Error; err != nil {
+		t.Fatalf("failed to read subjects: %v", err)
+	}
+	if len(subjects) != 1 || subjects[0].Name != "Physics" {
+		t.Errorf("subjects = %+v, want only Physics, unchanged", subjects)
+	}
+
+	own := fmt.Sprintf("/api/businesses/%d/subjects", sunrise.ID)
+	if w := serve(r, http.MethodPost, own, token, `{"name": "Chemistry"}`); w.Code != http.StatusCreated {
+		t.Errorf("own business create: status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	if w := serve(r, http.MethodGet, own, token, ""); w.Code != http.StatusOK {
+		t.Errorf("own business list: status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w := serve(r, http.MethodGet, others, tokenFor(t, string(models.RoleAdmin)), ""); w.Code != http.StatusOK {
+		t.Errorf("admin list: status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
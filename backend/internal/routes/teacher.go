@@ -3,23 +3,23 @@ package routes
 import (
 	"backend/internal/handlers"
 	"backend/internal/middleware"
+	"backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupTeacherRoutes(router *gin.Engine, teacherHandler *handlers.TeacherHandler) {
-	api := router.Group("/api")
-
+func SetupTeacherRoutes(router *gin.RouterGroup, teacherHandler *handlers.TeacherHandler) {
 	// Public routes (if any)
 	// None for teachers - all require authentication
 
 	// Protected routes
-	protected := api.Group("")
+	protected := router.Group("")
 	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
 
 	// Teacher profile routes (for teacher users)
 	teacherProfile := protected.Group("/my-teacher-profile")
-	teacherProfile.Use(middleware.RoleMiddleware("teacher"))
+	teacherProfile.Use(middleware.PermissionMiddleware(services.PermManageOwnTeacherProfile))
 	{
 		teacherProfile.GET("", teacherHandler.GetMyTeacherProfile)
 		teacherProfile.PUT("", teacherHandler.UpdateMyTeacherProfile)
@@ -27,14 +27,17 @@ func SetupTeacherRoutes(router *gin.Engine, teacherHandler *handlers.TeacherHand
 
 	// Admin-only teacher management routes
 	adminTeachers := protected.Group("/teachers")
-	adminTeachers.Use(middleware.RoleMiddleware("admin"))
+	adminTeachers.Use(middleware.PermissionMiddleware(services.PermManageAllTeachers))
 	{
 		adminTeachers.POST("", teacherHandler.CreateTeacher)
 		adminTeachers.GET("", teacherHandler.GetTeachers)
 		adminTeachers.GET("/search", teacherHandler.SearchTeachers)
 		adminTeachers.GET("/stats", teacherHandler.GetTeacherStats)
+		adminTeachers.GET("/recent", teacherHandler.GetRecentTeachers)
 		adminTeachers.GET("/stats/salary", teacherHandler.GetSalaryStats)
 		adminTeachers.GET("/stats/qualifications", teacherHandler.GetQualificationStats)
+		adminTeachers.GET("/stats/experience", teacherHandler.GetExperienceStats)
+		adminTeachers.GET("/stats/subjects", teacherHandler.GetSubjectStats)
 		adminTeachers.GET("/active", teacherHandler.GetActiveTeachers)
 		adminTeachers.GET("/inactive", teacherHandler.GetInactiveTeachers)
 		adminTeachers.POST("/bulk/status", teacherHandler.BulkUpdateTeacherStatus)
@@ -42,22 +45,49 @@ func SetupTeacherRoutes(router *gin.Engine, teacherHandler *handlers.TeacherHand
 		adminTeachers.GET("/:id", teacherHandler.GetTeacher)
 		adminTeachers.PUT("/:id", teacherHandler.UpdateTeacher)
 		adminTeachers.DELETE("/:id", teacherHandler.DeleteTeacher)
+		adminTeachers.POST("/:id/restore", teacherHandler.RestoreTeacher)
 		adminTeachers.PATCH("/:id/status", teacherHandler.ChangeTeacherStatus)
+		adminTeachers.PUT("/:id/subjects", teacherHandler.AssignTeacherSubjects)
+		adminTeachers.GET("/:id/salary-history", teacherHandler.GetTeacherSalaryHistory)
+		adminTeachers.POST("/:id/transfer", teacherHandler.TransferTeacher)
+		adminTeachers.GET("/:id/assignment-history", teacherHandler.GetTeacherAssignmentHistory)
+	}
+
+	// Teacher availability routes (business owners manage their own teachers)
+	teacherAvailability := protected.Group("/teachers/:id/availability")
+	teacherAvailability.Use(middleware.PermissionMiddleware(services.PermManageTeachers))
+	{
+		teacherAvailability.GET("", teacherHandler.GetTeacherAvailability)
+		teacherAvailability.POST("", teacherHandler.AddTeacherAvailability)
+		teacherAvailability.PUT("/:availabilityId", teacherHandler.UpdateTeacherAvailability)
+		teacherAvailability.DELETE("/:availabilityId", teacherHandler.DeleteTeacherAvailability)
 	}
 
+	// Bulk delete is scoped per caller in the service, so business owners may use it too
+	protected.POST("/teachers/bulk/delete", middleware.PermissionMiddleware(services.PermManageTeachers), teacherHandler.BulkDeleteTeachers)
+
 	// Business-specific teacher routes (for business owners)
 	businessTeachers := protected.Group("/businesses/:businessId/teachers")
-	businessTeachers.Use(middleware.RoleMiddleware("admin", "business"))
+	businessTeachers.Use(middleware.PermissionMiddleware(services.PermManageTeachers))
 	{
 		businessTeachers.GET("", teacherHandler.GetTeachersByBusiness)
-		businessTeachers.GET("/active", func(c *gin.Context) {
-			// This would need a separate handler method or modify existing one
-			// For now, redirect to general active teachers with business filter
-			teacherHandler.GetActiveTeachers(c)
-		})
-		businessTeachers.GET("/inactive", func(c *gin.Context) {
-			// Similar to above
-			teacherHandler.GetInactiveTeachers(c)
-		})
+		businessTeachers.GET("/available", teacherHandler.GetAvailableTeachers)
+		businessTeachers.GET("/stats", teacherHandler.GetBusinessTeacherStats)
+		businessTeachers.POST("/import", middleware.BodyLimit("import"), teacherHandler.ImportTeachers)
+		businessTeachers.GET("/export", teacherHandler.ExportTeachers)
+		businessTeachers.GET("/active", teacherHandler.GetActiveTeachersByBusiness)
+		businessTeachers.GET("/inactive", teacherHandler.GetInactiveTeachersByBusiness)
+	}
+
+	// The caller's own business's teachers, resolved from the token (for business owners)
+	myBusinessTeachers := protected.Group("/my-business/teachers")
+	myBusinessTeachers.Use(middleware.PermissionMiddleware(services.PermManageOwnBusiness))
+	{
+		myBusinessTeachers.GET("", teacherHandler.GetMyBusinessTeachers)
+		myBusinessTeachers.GET("/search", teacherHandler.SearchMyBusinessTeachers)
+		myBusinessTeachers.GET("/stats", teacherHandler.GetMyBusinessTeacherStats)
+		myBusinessTeachers.GET("/recent", teacherHandler.GetMyBusinessRecentTeachers)
+		myBusinessTeachers.GET("/active", teacherHandler.GetMyBusinessActiveTeachers)
+		myBusinessTeachers.GET("/inactive", teacherHandler.GetMyBusinessInactiveTeachers)
 	}
 }
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTeacherAttendanceRoutes(router *gin.RouterGroup, attendanceHandler *handlers.TeacherAttendanceHandler) {
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+	protected.Use(middleware.PermissionMiddleware(services.PermManageAttendance))
+
+	// Attendance of a single teacher
+	teacherAttendance := protected.Group("/teachers/:id/attendance")
+	{
+		teacherAttendance.GET("", attendanceHandler.GetTeacherAttendance)
+		teacherAttendance.POST("", attendanceHandler.MarkTeacherAttendance)
+		teacherAttendance.GET("/summary", attendanceHandler.GetTeacherAttendanceSummary)
+	}
+
+	// Attendance of all teachers of a business
+	businessAttendance := protected.Group("/businesses/:businessId/teachers/attendance")
+	{
+		businessAttendance.GET("", attendanceHandler.GetBusinessTeacherAttendance)
+		businessAttendance.POST("", attendanceHandler.BulkMarkTeacherAttendance)
+		businessAttendance.GET("/summary", attendanceHandler.GetBusinessAttendanceSummary)
+	}
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTeacherDocumentRoutes(router *gin.RouterGroup, documentHandler *handlers.TeacherDocumentHandler) {
+	// Protected routes
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
+
+	// Read-only access for the teacher themselves
+	teacherProfile := protected.Group("/my-teacher-profile")
+	teacherProfile.Use(middleware.PermissionMiddleware(services.PermManageOwnTeacherProfile))
+	{
+		teacherProfile.GET("/documents", documentHandler.GetMyTeacherDocuments)
+	}
+
+	// Document management (business owners manage their own teachers)
+	teacherDocuments := protected.Group("/teachers/:id/documents")
+	teacherDocuments.Use(middleware.PermissionMiddleware(services.PermManageTeachers))
+	{
+		teacherDocuments.GET("", documentHandler.GetTeacherDocuments)
+		teacherDocuments.POST("", middleware.BodyLimit("upload"), documentHandler.UploadTeacherDocument)
+		teacherDocuments.DELETE("/:documentId", documentHandler.DeleteTeacherDocument)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTeacherStudentRoutes(router *gin.RouterGroup, assignmentHandler *handlers.TeacherStudentHandler) {
+	// Protected routes (business owners manage their own teachers)
+	teacherStudents := router.Group("/teachers/:id/students")
+	teacherStudents.Use(middleware.AuthMiddleware())
+	teacherStudents.Use(middleware.RateLimit("api"))
+	teacherStudents.Use(middleware.PermissionMiddleware(services.PermManageTeachers))
+	{
+		teacherStudents.GET("", assignmentHandler.GetTeacherStudents)
+		teacherStudents.POST("", assignmentHandler.AssignTeacherStudents)
+		teacherStudents.DELETE("", assignmentHandler.UnassignTeacherStudents)
+	}
+}
@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"backend/internal/handlers"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/internal/testutil"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Teachers edit their own name and background, never their salary, status or business
+func TestTeacherSelfUpdate(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	sunrise, moonlight, asha := f.Businesses["Sunrise Academy"], f.Businesses["Moonlight Tutors"], f.Teachers["Asha"]
+
+	service := services.NewTeacherService(repository.NewTeacherRepository(db), repository.NewUserRepository(db),
+		repository.NewBusinessRepository(db), repository.NewSubjectRepository(db), repository.NewTeacherAvailabilityRepository(db),
+		repository.NewTeacherDocumentRepository(db), repository.NewTeacherStudentRepository(db), nil, repository.NewQualificationRepository(db))
+	r := gin.New()
+	SetupTeacherRoutes(r.Group("/api"), handlers.NewTeacherHandler(service, nil))
+	token, err := utils.GenerateToken(asha.UserID, "asha.teacher@example.com", string(models.RoleTeacher),
+		utils.TokenScope{BusinessID: sunrise.ID, TeacherID: asha.ID})
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	body := fmt.Sprintf(`{"name": "Asha Iyer", "description": "Maths and physics", "salary": 99000, "status": 0, "business_id": %d}`, moonlight.ID)
+	w := serve(r, http.MethodPut, "/api/my-teacher-profile", token, body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var teacher models.Teacher
+	if err := db.First(&teacher, asha.ID).Error; err != nil {
+		t.Fatalf("failed to read teacher: %v", err)
+	}
+	if teacher.Name != "Asha Iyer" || teacher.Description != "Maths and physics" {
+		t.Errorf("name, description = %q, %q, want them updated", teacher.Name, teacher.Description)
+	}
+	if teacher.Salary != asha.Salary {
+		t.Errorf("Salary = %v, want it unchanged at %v", teacher.Salary, asha.Salary)
+	}
+	if teacher.Status != models.StatusActive {
+		t.Errorf("Status = %v, want it unchanged", teacher.Status)
+	}
+	if teacher.BusinessID != sunrise.ID {
+		t.Errorf("BusinessID = %d, want it unchanged at %d", teacher.BusinessID, sunrise.ID)
+	}
+}
@@ -3,25 +3,30 @@ package routes
 import (
 	"backend/internal/handlers"
 	"backend/internal/middleware"
+	"backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupUserRoutes(router *gin.RouterGroup, userHandler *handlers.UserHandler) {
 	// Public routes
-	router.POST("/register", userHandler.Register)
-	router.POST("/login", userHandler.Login)
+	router.POST("/register", middleware.RateLimit("auth"), middleware.BodyLimit("auth"), userHandler.Register)
+	router.POST("/login", middleware.RateLimit("auth"), middleware.BodyLimit("auth"), userHandler.Login)
 
 	// Protected routes
 	protected := router.Group("/")
 	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.RateLimit("api"))
 	{
+		protected.POST("/refresh-token", userHandler.RefreshToken)
+		protected.GET("/me/profiles", userHandler.GetMyProfiles)
+		protected.POST("/me/profiles/switch", userHandler.SwitchProfile)
 		protected.GET("/profile", userHandler.GetProfile)
 		protected.PUT("/profile", userHandler.UpdateProfile)
 
 		// Admin only routes
 		admin := protected.Group("/")
-		admin.Use(middleware.RoleMiddleware("admin"))
+		admin.Use(middleware.PermissionMiddleware(services.PermManageUsers))
 		{
 			admin.GET("/users", userHandler.GetUsers)
 			admin.GET("/users/:id", userHandler.GetUser)
@@ -29,6 +34,7 @@ func SetupUserRoutes(router *gin.RouterGroup, userHandler *handlers.UserHandler)
 			admin.DELETE("/users/:id", userHandler.DeleteUser)
 			admin.GET("/users/role/:role", userHandler.GetUsersByRole)
 			admin.GET("/users/stats/roles", userHandler.GetRoleStatistics)
+			admin.GET("/users/recent", userHandler.GetRecentUsers)
 			admin.POST("/users/:id/promote", userHandler.PromoteUser)
 		}
 	}
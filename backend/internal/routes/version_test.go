@@ -0,0 +1,125 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend/internal/dto"
+	"backend/internal/handlers"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/services"
+	"backend/internal/testutil"
+	"backend/pkg/cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+// racingPackages lets another writer in between a writer's version check and its save,
+// the window two editors of the same version race through
+type racingPackages struct {
+	repository.PackageRepository
+	race func()
+}
+
+func (p *racingPackages) UpdateVersioned(ctx context.Context, pkg *models.Package, expectedVersion uint) error {
+	if race := p.race; race != nil {
+		p.race = nil
+		race()
+	}
+	return p.PackageRepository.UpdateVersioned(ctx, pkg, expectedVersion)
+}
+
+// Updates must say which version they are based on, and one based on an older version
+// than the stored one is refused with the current record, whether the record changed
+// before the request or while it ran
+func TestConcurrentEditsConflict(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	basic := f.Packages["Basic"]
+
+	packages := &racingPackages{PackageRepository: repository.NewPackageRepository(db)}
+	r := gin.New()
+	SetupPackageRoutes(r.Group("/api"), handlers.NewPackageHandler(services.NewPackageService(packages, cache.Noop{})))
+	admin := tokenFor(t, string(models.RoleAdmin))
+	path := fmt.Sprintf("/api/packages/%d", basic.ID)
+
+	stored := func() models.Package {
+		t.Helper()
+		var pkg models.Package
+		if err := db.First(&pkg, basic.ID).Error; err != nil {
+			t.Fatalf("failed to read package: %v", err)
+		}
+		return pkg
+	}
+	conflict := func(t *testing.T, w *httptest.ResponseRecorder) models.PackageResponse {
+		t.Helper()
+		if w.Code != http.StatusConflict {
+			t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusConflict, w.Body.String())
+		}
+		var response struct {
+			dto.ErrorResponse
+			Data models.PackageResponse `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return response.Data
+	}
+	start := stored()
+
+	t.Run("no version", func(t *testing.T) {
+		w := serve(r, http.MethodPut, path, admin, `{"name": "Unversioned"}`)
+		if w.Code != http.StatusPreconditionRequired {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusPreconditionRequired)
+		}
+		if pkg := stored(); pkg.Name != start.Name || pkg.Version != start.Version {
+			t.Errorf("package = %q v%d, want it unchanged", pkg.Name, pkg.Version)
+		}
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		w := serve(r, http.MethodPut, path, admin, fmt.Sprintf(`{"name": "Stale", "version": %d}`, start.Version+5))
+		current := conflict(t, w)
+		if current.Name != start.Name || current.Version != start.Version {
+			t.Errorf("current = %q v%d, want %q v%d", current.Name, current.Version, start.Name, start.Version)
+		}
+	})
+
+	t.Run("current version in If-Match", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(`{"description": "Starter plan"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+admin)
+		req.Header.Set("If-Match", fmt.Sprintf(`W/"%d"`, start.Version))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+		}
+	})
+
+	t.Run("two writers of the same version", func(t *testing.T) {
+		read := stored()
+		var winner *httptest.ResponseRecorder
+		packages.race = func() {
+			winner = serve(r, http.MethodPut, path, admin, fmt.Sprintf(`{"name": "Winner", "version": %d}`, read.Version))
+		}
+		loser := serve(r, http.MethodPut, path, admin, fmt.Sprintf(`{"name": "Loser", "price": 1, "version": %d}`, read.Version))
+
+		if winner == nil || winner.Code != http.StatusOK {
+			t.Fatalf("winner did not save: %v", winner)
+		}
+		current := conflict(t, loser)
+		if current.Name != "Winner" || current.Version != read.Version+1 {
+			t.Errorf("current = %q v%d, want the winner's at v%d", current.Name, current.Version, read.Version+1)
+		}
+		if pkg := stored(); pkg.Name != "Winner" || pkg.Price != read.Price || pkg.Version != read.Version+1 {
+			t.Errorf("package = %q %v v%d, want the winner's row untouched by the loser", pkg.Name, pkg.Price, pkg.Version)
+		}
+	})
+}
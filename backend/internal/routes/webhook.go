@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupWebhookRoutes(router *gin.RouterGroup, webhookHandler *handlers.WebhookHandler) {
+	// Public route, authenticated by the gateway's signature
+	router.POST("/webhooks/:provider", middleware.RateLimit("public"), webhookHandler.PaymentWebhook)
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"backend/internal/repository"
+	"context"
+	"errors"
+)
+
+// ErrAccessDenied is returned when a caller tries to act on data that belongs to another business
+var ErrAccessDenied = errors.New("access denied")
+
+// ownBusinessID returns the ID of the business the user owns
+func ownBusinessID(ctx context.Context, businessRepo repository.BusinessRepository, userID uint) (uint, error) {
+	business, err := businessRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return 0, errors.New("business profile not found")
+	}
+	return business.ID, nil
+}
+
+// checkBusinessAccess verifies that a business-role caller owns the given business.
+// Admins may access every business; other roles are never granted access here.
+func checkBusinessAccess(ctx context.Context, businessRepo repository.BusinessRepository, businessID, userID uint, role string) error {
+	switch role {
+	case "admin":
+		return nil
+	case "business":
+		business, err := businessRepo.GetByUserID(ctx, userID)
+		if err != nil || business.ID != businessID {
+			return ErrAccessDenied
+		}
+		return nil
+	default:
+		return ErrAccessDenied
+	}
+}
+
+// checkBusinessesAccess runs checkBusinessAccess for every distinct business in businessIDs
+func checkBusinessesAccess(ctx context.Context, businessRepo repository.BusinessRepository, businessIDs []uint, userID uint, role string) error {
+	for _, businessID := range uniqueIDs(businessIDs) {
+		if err := checkBusinessAccess(ctx, businessRepo, businessID, userID, role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
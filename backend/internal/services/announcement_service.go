@@ -0,0 +1,338 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type AnnouncementService interface {
+	// Business management
+	CreateAnnouncement(ctx context.Context, businessID uint, req models.CreateAnnouncementRequest, actorID uint) (*models.AnnouncementResponse, error)
+	GetAnnouncements(ctx context.Context, filters repository.AnnouncementFilters) ([]models.AnnouncementResponse, int64, error)
+	GetAnnouncement(ctx context.Context, businessID, announcementID uint) (*models.AnnouncementResponse, error)
+	UpdateAnnouncement(ctx context.Context, businessID, announcementID uint, req models.UpdateAnnouncementRequest) (*models.AnnouncementResponse, error)
+	DeleteAnnouncement(ctx context.Context, businessID, announcementID uint) error
+
+	// Readers (students and teachers)
+	GetMyAnnouncements(ctx context.Context, userID uint, role string, page, limit int) ([]models.AnnouncementResponse, int64, error)
+	GetMyUnreadCount(ctx context.Context, userID uint, role string) (int64, error)
+	MarkAnnouncementRead(ctx context.Context, userID uint, role string, announcementID uint) error
+	MarkAllAnnouncementsRead(ctx context.Context, userID uint, role string) error
+
+	// Access control
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+}
+
+type announcementService struct {
+	announcementRepo repository.AnnouncementRepository
+	studentRepo      repository.StudentRepository
+	teacherRepo      repository.TeacherRepository
+	batchRepo        repository.BatchRepository
+	businessRepo     repository.BusinessRepository
+}
+
+func NewAnnouncementService(announcementRepo repository.AnnouncementRepository, studentRepo repository.StudentRepository, teacherRepo repository.TeacherRepository, batchRepo repository.BatchRepository, businessRepo repository.BusinessRepository) AnnouncementService {
+	return &announcementService{
+		announcementRepo: announcementRepo,
+		studentRepo:      studentRepo,
+		teacherRepo:      teacherRepo,
+		batchRepo:        batchRepo,
+		businessRepo:     businessRepo,
+	}
+}
+
+func (s *announcementService) CreateAnnouncement(ctx context.Context, businessID uint, req models.CreateAnnouncementRequest, actorID uint) (*models.AnnouncementResponse, error) {
+	title := strings.TrimSpace(req.Title)
+	body := strings.TrimSpace(req.Body)
+	if title == "" || body == "" {
+		return nil, fmt.Errorf("title and body are required")
+	}
+
+	// Check if business exists
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	if err := s.validateBatch(ctx, businessID, req.BatchID); err != nil {
+		return nil, err
+	}
+
+	announcement := &models.Announcement{
+		BusinessID:  businessID,
+		Title:       title,
+		Body:        body,
+		Audience:    req.Audience,
+		BatchID:     req.BatchID,
+		PublishedAt: time.Now().UTC(),
+		ExpiresAt:   req.ExpiresAt,
+		CreatedBy:   actorID,
+	}
+	if req.PublishedAt != nil {
+		announcement.PublishedAt = req.PublishedAt.UTC()
+	}
+	if err := validateAnnouncementWindow(announcement); err != nil {
+		return nil, err
+	}
+
+	if err := s.announcementRepo.Create(ctx, announcement); err != nil {
+		return nil, fmt.Errorf("failed to create announcement: %v", err)
+	}
+
+	return s.GetAnnouncement(ctx, businessID, announcement.ID)
+}
+
+func (s *announcementService) GetAnnouncements(ctx context.Context, filters repository.AnnouncementFilters) ([]models.AnnouncementResponse, int64, error) {
+	// Set default pagination
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.Limit == 0 {
+		filters.Limit = 10
+	}
+
+	announcements, total, err := s.announcementRepo.GetAll(ctx, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get announcements: %v", err)
+	}
+
+	responses := []models.AnnouncementResponse{}
+	for _, announcement := range announcements {
+		responses = append(responses, toAnnouncementResponse(announcement))
+	}
+
+	return responses, total, nil
+}
+
+func (s *announcementService) GetAnnouncement(ctx context.Context, businessID, announcementID uint) (*models.AnnouncementResponse, error) {
+	announcement, err := s.getBusinessAnnouncement(ctx, businessID, announcementID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toAnnouncementResponse(*announcement)
+	return &response, nil
+}
+
+func (s *announcementService) UpdateAnnouncement(ctx context.Context, businessID, announcementID uint, req models.UpdateAnnouncementRequest) (*models.AnnouncementResponse, error) {
+	announcement, err := s.getBusinessAnnouncement(ctx, businessID, announcementID)
+	if err != nil {
+		return nil, err
+	}
+
+	if title := strings.TrimSpace(req.Title); title != "" {
+		announcement.Title = title
+	}
+	if body := strings.TrimSpace(req.Body); body != "" {
+		announcement.Body = body
+	}
+	if req.Audience != "" {
+		announcement.Audience = req.Audience
+	}
+	if req.BatchID != nil {
+		if err := s.validateBatch(ctx, businessID, req.BatchID); err != nil {
+			return nil, err
+		}
+		announcement.BatchID = req.BatchID
+		announcement.Batch = nil
+	}
+	if req.PublishedAt != nil {
+		announcement.PublishedAt = req.PublishedAt.UTC()
+	}
+	if req.ExpiresAt != nil {
+		announcement.ExpiresAt = req.ExpiresAt
+	}
+	if err := validateAnnouncementWindow(announcement); err != nil {
+		return nil, err
+	}
+
+	if err := s.announcementRepo.Update(ctx, announcement); err != nil {
+		return nil, fmt.Errorf("failed to update announcement: %v", err)
+	}
+
+	return s.GetAnnouncement(ctx, businessID, announcement.ID)
+}
+
+func (s *announcementService) DeleteAnnouncement(ctx context.Context, businessID, announcementID uint) error {
+	announcement, err := s.getBusinessAnnouncement(ctx, businessID, announcementID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.announcementRepo.Delete(ctx, announcement.ID); err != nil {
+		return fmt.Errorf("failed to delete announcement: %v", err)
+	}
+
+	return nil
+}
+
+func (s *announcementService) GetMyAnnouncements(ctx context.Context, userID uint, role string, page, limit int) ([]models.AnnouncementResponse, int64, error) {
+	reader, err := s.reader(ctx, userID, role)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	announcements, total, err := s.announcementRepo.GetVisible(ctx, *reader, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get announcements: %v", err)
+	}
+
+	ids := make([]uint, 0, len(announcements))
+	for _, announcement := range announcements {
+		ids = append(ids, announcement.ID)
+	}
+	readIDs, err := s.announcementRepo.GetReadIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get read announcements: %v", err)
+	}
+
+	responses := []models.AnnouncementResponse{}
+	for _, announcement := range announcements {
+		response := toAnnouncementResponse(announcement)
+		read := readIDs[announcement.ID]
+		response.Read = &read
+		responses = append(responses, response)
+	}
+
+	return responses, total, nil
+}
+
+func (s *announcementService) GetMyUnreadCount(ctx context.Context, userID uint, role string) (int64, error) {
+	reader, err := s.reader(ctx, userID, role)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.announcementRepo.CountUnread(ctx, *reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread announcements: %v", err)
+	}
+	return count, nil
+}
+
+func (s *announcementService) MarkAnnouncementRead(ctx context.Context, userID uint, role string, announcementID uint) error {
+	reader, err := s.reader(ctx, userID, role)
+	if err != nil {
+		return err
+	}
+
+	visible, err := s.announcementRepo.IsVisible(ctx, announcementID, *reader)
+	if err != nil {
+		return fmt.Errorf("failed to get announcement: %v", err)
+	}
+	if !visible {
+		return fmt.Errorf("announcement not found")
+	}
+
+	if err := s.announcementRepo.MarkRead(ctx, announcementID, userID); err != nil {
+		return fmt.Errorf("failed to mark announcement as read: %v", err)
+	}
+	return nil
+}
+
+func (s *announcementService) MarkAllAnnouncementsRead(ctx context.Context, userID uint, role string) error {
+	reader, err := s.reader(ctx, userID, role)
+	if err != nil {
+		return err
+	}
+
+	if err := s.announcementRepo.MarkAllRead(ctx, *reader); err != nil {
+		return fmt.Errorf("failed to mark announcements as read: %v", err)
+	}
+	return nil
+}
+
+func (s *announcementService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
+
+// reader works out which announcements reach a student or teacher: students see their
+// batch's announcements, teachers those of the batches they teach
+func (s *announcementService) reader(ctx context.Context, userID uint, role string) (*repository.AnnouncementReader, error) {
+	reader := &repository.AnnouncementReader{
+		UserID: userID,
+		At:     time.Now().UTC(),
+	}
+
+	switch role {
+	case "student":
+		student, err := s.studentRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("student profile not found")
+		}
+		reader.BusinessID = student.BusinessID
+		reader.Audience = models.AudienceStudents
+		if student.BatchID != nil {
+			reader.BatchIDs = []uint{*student.BatchID}
+		}
+	case "teacher":
+		teacher, err := s.teacherRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("teacher profile not found")
+		}
+		reader.BusinessID = teacher.BusinessID
+		reader.Audience = models.AudienceTeachers
+
+		batches, err := s.batchRepo.GetByBusinessID(ctx, teacher.BusinessID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get batches: %v", err)
+		}
+		for _, batch := range batches {
+			if batch.TeacherID != nil && *batch.TeacherID == teacher.ID {
+				reader.BatchIDs = append(reader.BatchIDs, batch.ID)
+			}
+		}
+	default:
+		return nil, ErrAccessDenied
+	}
+
+	return reader, nil
+}
+
+func (s *announcementService) getBusinessAnnouncement(ctx context.Context, businessID, announcementID uint) (*models.Announcement, error) {
+	announcement, err := s.announcementRepo.GetByID(ctx, announcementID)
+	if err != nil || announcement.BusinessID != businessID {
+		return nil, fmt.Errorf("announcement not found")
+	}
+	return announcement, nil
+}
+
+func (s *announcementService) validateBatch(ctx context.Context, businessID uint, batchID *uint) error {
+	if batchID == nil {
+		return nil
+	}
+	batch, err := s.batchRepo.GetByID(ctx, *batchID)
+	if err != nil || batch.BusinessID != businessID {
+		return fmt.Errorf("batch not found in this business")
+	}
+	return nil
+}
+
+func validateAnnouncementWindow(announcement *models.Announcement) error {
+	if announcement.ExpiresAt != nil && !announcement.ExpiresAt.After(announcement.PublishedAt) {
+		return fmt.Errorf("expiry must be after the publish time")
+	}
+	return nil
+}
+
+func toAnnouncementResponse(announcement models.Announcement) models.AnnouncementResponse {
+	response := models.AnnouncementResponse{
+		ID:          announcement.ID,
+		BusinessID:  announcement.BusinessID,
+		Title:       announcement.Title,
+		Body:        announcement.Body,
+		Audience:    announcement.Audience,
+		BatchID:     announcement.BatchID,
+		PublishedAt: announcement.PublishedAt,
+		ExpiresAt:   announcement.ExpiresAt,
+		CreatedBy:   announcement.CreatedBy,
+		CreatedOn:   announcement.CreatedOn,
+	}
+	if announcement.Batch != nil {
+		response.BatchName = announcement.Batch.Name
+	}
+	return response
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type AuditService interface {
+	// RecordDataAccess queues a data access entry and returns at once. When the queue is
+	// full the entry is dropped and counted, rather than holding up the request.
+	RecordDataAccess(entry models.AuditLog)
+	GetDataAccessLogs(ctx context.Context, filters repository.AuditLogFilters) ([]models.AuditLog, repository.PageInfo, error)
+
+	// Close stops accepting entries and waits for the queued ones to be written
+	Close(ctx context.Context) error
+}
+
+// AuditConfig controls the background writer of the audit log
+type AuditConfig struct {
+	QueueSize     int           // entries waiting to be written, 1000 by default
+	BatchSize     int           // entries written per INSERT, 100 by default
+	FlushInterval time.Duration // longest an entry waits for its batch to fill, 2s by default
+}
+
+type auditService struct {
+	auditRepo repository.AuditLogRepository
+	config    AuditConfig
+
+	entries chan models.AuditLog
+	dropped atomic.Int64 // entries lost to a full queue since the last flush
+	wg      sync.WaitGroup
+	mu      sync.RWMutex
+	closed  bool
+	closing sync.Once
+}
+
+func NewAuditService(auditRepo repository.AuditLogRepository, config AuditConfig) AuditService {
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1000
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 2 * time.Second
+	}
+
+	s := &auditService{
+		auditRepo: auditRepo,
+		config:    config,
+		entries:   make(chan models.AuditLog, config.QueueSize),
+	}
+	s.wg.Add(1)
+	go s.work()
+	return s
+}
+
+func (s *auditService) RecordDataAccess(entry models.AuditLog) {
+	entry.Category = models.AuditCategoryDataAccess
+	if entry.CreatedOn.IsZero() {
+		entry.CreatedOn = time.Now()
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		s.dropped.Add(1)
+		return
+	}
+
+	select {
+	case s.entries <- entry:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+func (s *auditService) GetDataAccessLogs(ctx context.Context, filters repository.AuditLogFilters) ([]models.AuditLog, repository.PageInfo, error) {
+	applyPageDefaults(&filters.Page, &filters.Limit)
+	filters.Category = models.AuditCategoryDataAccess
+
+	if filters.From != "" {
+		if _, err := parseDate(filters.From); err != nil {
+			return nil, repository.PageInfo{}, fmt.Errorf("invalid from date: %v", err)
+		}
+	}
+	if filters.To != "" {
+		if _, err := parseDate(filters.To); err != nil {
+			return nil, repository.PageInfo{}, fmt.Errorf("invalid to date: %v", err)
+		}
+	}
+	if filters.From != "" && filters.To != "" && filters.From > filters.To {
+		return nil, repository.PageInfo{}, fmt.Errorf("from date must not be after to date")
+	}
+
+	entries, total, err := s.auditRepo.GetAll(ctx, filters)
+	if err != nil {
+		return nil, repository.PageInfo{}, fmt.Errorf("failed to get audit logs: %w", err)
+	}
+	return entries, filters.PageInfo(total), nil
+}
+
+func (s *auditService) Close(ctx context.Context) error {
+	s.closing.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		close(s.entries)
+		s.mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work writes the queued entries in batches, whenever a batch fills up or the flush
+// interval passes, until the queue is closed and drained
+func (s *auditService) work() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.AuditLog, 0, s.config.BatchSize)
+	for {
+		select {
+		case entry, ok := <-s.entries:
+			if !ok {
+				s.flush(batch)
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) < s.config.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+		}
+		s.flush(batch)
+		batch = batch[:0]
+	}
+}
+
+func (s *auditService) flush(batch []models.AuditLog) {
+	if dropped := s.dropped.Swap(0); dropped > 0 {
+		slog.Warn("Audit log queue was full, entries dropped", "dropped", dropped)
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := s.auditRepo.CreateBatch(ctx, batch); err != nil {
+		slog.Error("Failed to write audit log entries", "entries", len(batch), "error", err)
+	}
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type BatchService interface {
+	CreateBatch(ctx context.Context, businessID uint, req models.CreateBatchRequest) (*models.BatchResponse, error)
+	GetBatchesByBusiness(ctx context.Context, businessID uint) ([]models.BatchResponse, error)
+	GetBatch(ctx context.Context, businessID, batchID uint) (*models.BatchResponse, error)
+	UpdateBatch(ctx context.Context, businessID, batchID uint, req models.UpdateBatchRequest) (*models.BatchResponse, error)
+	DeleteBatch(ctx context.Context, businessID, batchID uint) error
+
+	// Students
+	MoveStudent(ctx context.Context, studentID uint, batchID *uint, actorID uint) (*models.StudentResponse, error)
+
+	// Access control
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+	CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error
+}
+
+type batchService struct {
+	batchRepo    repository.BatchRepository
+	studentRepo  repository.StudentRepository
+	teacherRepo  repository.TeacherRepository
+	businessRepo repository.BusinessRepository
+}
+
+func NewBatchService(batchRepo repository.BatchRepository, studentRepo repository.StudentRepository, teacherRepo repository.TeacherRepository, businessRepo repository.BusinessRepository) BatchService {
+	return &batchService{
+		batchRepo:    batchRepo,
+		studentRepo:  studentRepo,
+		teacherRepo:  teacherRepo,
+		businessRepo: businessRepo,
+	}
+}
+
+func (s *batchService) CreateBatch(ctx context.Context, businessID uint, req models.CreateBatchRequest) (*models.BatchResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	// Check if business exists
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	exists, err := s.batchRepo.BatchNameExists(ctx, businessID, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check batch name: %v", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("batch already exists for this business")
+	}
+
+	batch := &models.Batch{
+		BusinessID: businessID,
+		Name:       name,
+	}
+
+	if batch.StartDate, err = parseOptionalDate(req.StartDate); err != nil {
+		return nil, err
+	}
+	if batch.EndDate, err = parseOptionalDate(req.EndDate); err != nil {
+		return nil, err
+	}
+	if err := validateBatchDates(batch); err != nil {
+		return nil, err
+	}
+
+	if req.TeacherID != nil {
+		if err := s.validateTeacher(ctx, businessID, *req.TeacherID); err != nil {
+			return nil, err
+		}
+		batch.TeacherID = req.TeacherID
+	}
+
+	if err := s.batchRepo.Create(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to create batch: %v", err)
+	}
+
+	return s.GetBatch(ctx, businessID, batch.ID)
+}
+
+func (s *batchService) GetBatchesByBusiness(ctx context.Context, businessID uint) ([]models.BatchResponse, error) {
+	batches, err := s.batchRepo.GetByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batches: %v", err)
+	}
+
+	batchIDs := make([]uint, 0, len(batches))
+	for _, batch := range batches {
+		batchIDs = append(batchIDs, batch.ID)
+	}
+
+	counts, err := s.batchRepo.CountStudents(ctx, batchIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count batch students: %v", err)
+	}
+
+	responses := []models.BatchResponse{}
+	for _, batch := range batches {
+		response := toBatchResponse(batch)
+		response.StudentCount = counts[batch.ID]
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+func (s *batchService) GetBatch(ctx context.Context, businessID, batchID uint) (*models.BatchResponse, error) {
+	batch, err := s.getBusinessBatch(ctx, businessID, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.batchRepo.CountStudents(ctx, []uint{batch.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count batch students: %v", err)
+	}
+
+	response := toBatchResponse(*batch)
+	response.StudentCount = counts[batch.ID]
+	return &response, nil
+}
+
+func (s *batchService) UpdateBatch(ctx context.Context, businessID, batchID uint, req models.UpdateBatchRequest) (*models.BatchResponse, error) {
+	batch, err := s.getBusinessBatch(ctx, businessID, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		exists, err := s.batchRepo.BatchNameExists(ctx, businessID, name, batch.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check batch name: %v", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("batch already exists for this business")
+		}
+		batch.Name = name
+	}
+
+	if req.StartDate != "" {
+		if batch.StartDate, err = parseOptionalDate(req.StartDate); err != nil {
+			return nil, err
+		}
+	}
+	if req.EndDate != "" {
+		if batch.EndDate, err = parseOptionalDate(req.EndDate); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateBatchDates(batch); err != nil {
+		return nil, err
+	}
+
+	if req.TeacherID != nil {
+		if err := s.validateTeacher(ctx, businessID, *req.TeacherID); err != nil {
+			return nil, err
+		}
+		batch.TeacherID = req.TeacherID
+		batch.Teacher = nil
+	}
+
+	if err := s.batchRepo.Update(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to update batch: %v", err)
+	}
+
+	return s.GetBatch(ctx, businessID, batch.ID)
+}
+
+func (s *batchService) DeleteBatch(ctx context.Context, businessID, batchID uint) error {
+	batch, err := s.getBusinessBatch(ctx, businessID, batchID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.batchRepo.Delete(ctx, batch.ID); err != nil {
+		return fmt.Errorf("failed to delete batch: %v", err)
+	}
+
+	return nil
+}
+
+func (s *batchService) MoveStudent(ctx context.Context, studentID uint, batchID *uint, actorID uint) (*models.StudentResponse, error) {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("student not found")
+	}
+
+	summary := "Removed from batch"
+	if batchID != nil {
+		// The batch must belong to the student's own business
+		batch, err := s.getBusinessBatch(ctx, student.BusinessID, *batchID)
+		if err != nil {
+			return nil, err
+		}
+		summary = "Moved to batch " + batch.Name
+	}
+
+	tx := s.studentRepo.BeginTransaction(ctx)
+
+	if err := s.batchRepo.MoveStudentWithTransaction(tx, student.ID, batchID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to move student: %v", err)
+	}
+
+	history := []models.StudentHistory{newStudentHistory(student.ID, models.StudentEventBatchMoved, summary,
+		models.JSONB{"from_batch_id": student.BatchID, "to_batch_id": batchID}, actorID)}
+	if err := s.studentRepo.CreateHistoryWithTransaction(tx, history); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record student history: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit batch move: %v", err)
+	}
+
+	updatedStudent, err := s.studentRepo.GetStudentWithRelations(ctx, student.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated student")
+	}
+
+	return toStudentResponse(updatedStudent), nil
+}
+
+func (s *batchService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
+
+func (s *batchService) CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("student not found")
+	}
+	return checkBusinessAccess(ctx, s.businessRepo, student.BusinessID, userID, role)
+}
+
+// Helper methods
+func (s *batchService) getBusinessBatch(ctx context.Context, businessID, batchID uint) (*models.Batch, error) {
+	batch, err := s.batchRepo.GetByID(ctx, batchID)
+	if err != nil || batch.BusinessID != businessID {
+		return nil, fmt.Errorf("batch not found")
+	}
+	return batch, nil
+}
+
+func (s *batchService) validateTeacher(ctx context.Context, businessID, teacherID uint) error {
+	teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
+	if err != nil || teacher.BusinessID != businessID {
+		return fmt.Errorf("teacher not found in this business")
+	}
+	return nil
+}
+
+func validateBatchDates(batch *models.Batch) error {
+	if batch.StartDate != nil && batch.EndDate != nil && batch.EndDate.Before(*batch.StartDate) {
+		return fmt.Errorf("end date must not be before start date")
+	}
+	return nil
+}
+
+// parseOptionalDate parses a YYYY-MM-DD value, returning nil for an empty string
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := parseDate(value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// formatOptionalDate formats a date-only value, returning "" for nil
+func formatOptionalDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(models.DateFormat)
+}
+
+func toBatchResponse(batch models.Batch) models.BatchResponse {
+	response := models.BatchResponse{
+		ID:         batch.ID,
+		BusinessID: batch.BusinessID,
+		Name:       batch.Name,
+		StartDate:  formatOptionalDate(batch.StartDate),
+		EndDate:    formatOptionalDate(batch.EndDate),
+		TeacherID:  batch.TeacherID,
+		CreatedOn:  batch.CreatedOn,
+	}
+
+	if batch.Teacher != nil {
+		response.TeacherName = batch.Teacher.Name
+	}
+
+	return response
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"backend/internal/models"
+	"errors"
+)
+
+// ErrBulkIncomplete is returned by an atomic bulk update that would have skipped some
+// IDs; nothing was changed and the result lists the IDs and why
+var ErrBulkIncomplete = errors.New("some IDs can't be updated, so none were")
+
+// newBulkResult sorts the requested IDs, without duplicates, into those found and those
+// skipped as not found
+func newBulkResult(ids []uint, found map[uint]bool, entity string) *models.BulkUpdateResult {
+	result := &models.BulkUpdateResult{Updated: []uint{}, Skipped: []models.BulkSkippedID{}}
+	for _, id := range uniqueIDs(ids) {
+		if found[id] {
+			result.Updated = append(result.Updated, id)
+		} else {
+			result.Skipped = append(result.Skipped, models.BulkSkippedID{ID: id, Reason: entity + " not found"})
+		}
+	}
+	return result
+}
+
+// checkAtomic fails an atomic bulk update that would skip IDs, clearing what it would
+// have updated. It also reports whether there is anything left to update.
+func checkAtomic(result *models.BulkUpdateResult, atomic bool) (bool, error) {
+	if atomic && len(result.Skipped) > 0 {
+		result.Updated = []uint{}
+		return false, ErrBulkIncomplete
+	}
+	return len(result.Updated) > 0, nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/testutil"
+)
+
+// missingID is an ID no fixture has
+const missingID = 999999
+
+// Bulk status changes with an ID that doesn't exist: the others are changed and the
+// missing one reported, unless the request is atomic, when nothing changes
+func TestBulkStatusWithMissingIDs(t *testing.T) {
+	tests := []struct {
+		name  string
+		model interface{}
+		ids   func(f *testutil.Fixtures) []uint
+		// users are the logins that follow the profiles' status
+		users  func(f *testutil.Fixtures) []uint
+		update func(db *gorm.DB, ids []uint, atomic bool) (*models.BulkUpdateResult, error)
+	}{
+		{
+			name:  "teachers",
+			model: &models.Teacher{},
+			ids:   func(f *testutil.Fixtures) []uint { return []uint{f.Teachers["Asha"].ID, f.Teachers["Bilal"].ID} },
+			users: func(f *testutil.Fixtures) []uint {
+				return []uint{f.Teachers["Asha"].UserID, f.Teachers["Bilal"].UserID}
+			},
+			update: func(db *gorm.DB, ids []uint, atomic bool) (*models.BulkUpdateResult, error) {
+				service := NewTeacherService(repository.NewTeacherRepository(db), repository.NewUserRepository(db), nil, nil, nil, nil, nil, nil, nil)
+				return service.BulkUpdateTeacherStatus(context.Background(), ids, models.StatusInactive, atomic)
+			},
+		},
+		{
+			name:  "students",
+			model: &models.Student{},
+			ids:   func(f *testutil.Fixtures) []uint { return []uint{f.Students["Aarav"].ID, f.Students["Bina"].ID} },
+			users: func(f *testutil.Fixtures) []uint {
+				return []uint{f.Students["Aarav"].UserID, f.Students["Bina"].UserID}
+			},
+			update: func(db *gorm.DB, ids []uint, atomic bool) (*models.BulkUpdateResult, error) {
+				service := NewStudentService(repository.NewStudentRepository(db), repository.NewUserRepository(db), nil, nil, nil)
+				return service.BulkUpdateStudentStatus(context.Background(), ids, models.StatusInactive, atomic, 0)
+			},
+		},
+		{
+			name:  "businesses",
+			model: &models.Business{},
+			ids:   func(f *testutil.Fixtures) []uint { return []uint{f.Businesses["Sunrise Academy"].ID} },
+			users: func(f *testutil.Fixtures) []uint { return []uint{f.Businesses["Sunrise Academy"].UserID} },
+			update: func(db *gorm.DB, ids []uint, atomic bool) (*models.BulkUpdateResult, error) {
+				service, _ := newBusinessService(db)
+				return service.BulkUpdateBusinessStatus(context.Background(), ids, models.StatusInactive, atomic, false)
+			},
+		},
+	}
+	for _, tt := range tests {
+		for _, atomic := range []bool{false, true} {
+			name := tt.name + "/partial"
+			if atomic {
+				name = tt.name + "/atomic"
+			}
+			t.Run(name, func(t *testing.T) {
+				db := testutil.DB(t)
+				f := testutil.Seed(t, db)
+				ids := tt.ids(f)
+
+				result, err := tt.update(db, append(slices.Clone(ids), missingID), atomic)
+				if atomic && !errors.Is(err, ErrBulkIncomplete) {
+					t.Fatalf("error = %v, want ErrBulkIncomplete", err)
+				}
+				if !atomic && err != nil {
+					t.Fatalf("error = %v", err)
+				}
+
+				if len(result.Skipped) != 1 || result.Skipped[0].ID != missingID || result.Skipped[0].Reason == "" {
+					t.Errorf("Skipped = %+v, want only %d with a reason", result.Skipped, missingID)
+				}
+				want := models.StatusActive
+				wantUpdated := []uint{}
+				if !atomic {
+					want = models.StatusInactive
+					wantUpdated = ids
+				}
+				if !slices.Equal(result.Updated, wantUpdated) {
+					t.Errorf("Updated = %v, want %v", result.Updated, wantUpdated)
+				}
+
+				check := func(model interface{}, ids []uint) {
+					var statuses []models.Status
+					if err := db.Model(model).Where("id IN ?", ids).Pluck("status", &statuses).Error; err != nil {
+						t.Fatalf("failed to read statuses: %v", err)
+					}
+					if len(statuses) != len(ids) {
+						t.Fatalf("found %d of %d rows", len(statuses), len(ids))
+					}
+					for _, status := range statuses {
+						if status != want {
+							t.Errorf("%T status = %v, want %v", model, status, want)
+						}
+					}
+				}
+				check(tt.model, ids)
+				check(&models.User{}, tt.users(f))
+			})
+		}
+	}
+}
+
+func TestCheckAtomic(t *testing.T) {
+	found := map[uint]bool{1: true, 2: true}
+	tests := []struct {
+		name        string
+		ids         []uint
+		atomic      bool
+		wantOK      bool
+		wantErr     error
+		wantUpdated []uint
+		wantSkipped []uint
+	}{
+		{"all found", []uint{2, 1, 2}, false, true, nil, []uint{2, 1}, nil},
+		{"all found atomic", []uint{1, 2}, true, true, nil, []uint{1, 2}, nil},
+		{"some missing", []uint{1, 3}, false, true, nil, []uint{1}, []uint{3}},
+		{"some missing atomic", []uint{1, 3}, true, false, ErrBulkIncomplete, []uint{}, []uint{3}},
+		{"none found", []uint{3, 4}, false, false, nil, []uint{}, []uint{3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newBulkResult(tt.ids, found, "teacher")
+			ok, err := checkAtomic(result, tt.atomic)
+			if ok != tt.wantOK || !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkAtomic() = %v, %v, want %v, %v", ok, err, tt.wantOK, tt.wantErr)
+			}
+			if !slices.Equal(result.Updated, tt.wantUpdated) {
+				t.Errorf("Updated = %v, want %v", result.Updated, tt.wantUpdated)
+			}
+			skipped := make([]uint, 0, len(result.Skipped))
+			for _, skip := range result.Skipped {
+				if skip.Reason != "teacher not found" {
+					t.Errorf("reason = %q, want %q", skip.Reason, "teacher not found")
+				}
+				skipped = append(skipped, skip.ID)
+			}
+			if !slices.Equal(skipped, tt.wantSkipped) {
+				t.Errorf("Skipped = %v, want %v", skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
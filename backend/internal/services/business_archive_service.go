@@ -0,0 +1,983 @@
+package services
+
+import (
+	"archive/zip"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxArchiveFileSize caps how much any one file of an imported archive may inflate to
+const maxArchiveFileSize = 256 << 20 // 256 MB
+
+// maxArchiveProblems is how many problems an invalid archive reports at most
+const maxArchiveProblems = 50
+
+type BusinessArchiveService interface {
+	// ExportArchive writes a ZIP of JSON files with everything the business owns, streamed
+	// as it is read, and returns how many records the files hold together
+	ExportArchive(ctx context.Context, businessID uint, w io.Writer) (int64, error)
+	// ImportArchive validates a whole archive, then recreates it as a new business with
+	// fresh IDs in a single transaction. Validation failures are an
+	// *models.ArchiveValidationError listing every problem found.
+	ImportArchive(ctx context.Context, r io.ReaderAt, size int64, req models.ImportArchiveRequest, actorID uint) (*models.ImportArchiveResult, error)
+}
+
+type businessArchiveService struct {
+	archiveRepo  repository.BusinessArchiveRepository
+	businessRepo repository.BusinessRepository
+	userRepo     repository.UserRepository
+	provisioning ProvisioningService
+}
+
+func NewBusinessArchiveService(archiveRepo repository.BusinessArchiveRepository, businessRepo repository.BusinessRepository, userRepo repository.UserRepository, provisioning ProvisioningService) BusinessArchiveService {
+	return &businessArchiveService{
+		archiveRepo:  archiveRepo,
+		businessRepo: businessRepo,
+		userRepo:     userRepo,
+		provisioning: provisioning,
+	}
+}
+
+// businessArchive is the content of an archive, whatever its version
+type businessArchive struct {
+	Manifest          models.ArchiveManifest
+	Business          models.ArchiveBusiness
+	Owner             models.ArchiveUser
+	Subjects          []models.ArchiveSubject
+	Batches           []models.ArchiveBatch
+	StudentFields     []models.ArchiveStudentField
+	StudentGrades     []models.ArchiveStudentGrade
+	Teachers          []models.ArchiveTeacher
+	Students          []models.ArchiveStudent
+	StudentAttendance []models.ArchiveStudentAttendance
+	TeacherAttendance []models.ArchiveTeacherAttendance
+	FeePlans          []models.ArchiveFeePlan
+	FeePayments       []models.ArchiveFeePayment
+}
+
+func (s *businessArchiveService) ExportArchive(ctx context.Context, businessID uint, w io.Writer) (int64, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return 0, fmt.Errorf("business not found")
+	}
+	owner, err := s.userRepo.GetByID(ctx, business.UserID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get business owner: %v", err)
+	}
+
+	subjects, err := s.archiveRepo.GetSubjects(ctx, businessID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get subjects: %v", err)
+	}
+	batches, err := s.archiveRepo.GetBatches(ctx, businessID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get batches: %v", err)
+	}
+	fields, err := s.archiveRepo.GetStudentFields(ctx, businessID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get student fields: %v", err)
+	}
+	grades, err := s.archiveRepo.GetStudentGrades(ctx, businessID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get student grades: %v", err)
+	}
+	availabilities, err := s.archiveRepo.GetTeacherAvailability(ctx, businessID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get teacher availability: %v", err)
+	}
+	availabilityByTeacher := make(map[uint][]models.ArchiveAvailability)
+	for _, availability := range availabilities {
+		availabilityByTeacher[availability.TeacherID] = append(availabilityByTeacher[availability.TeacherID], models.ArchiveAvailability{
+			Weekday:   availability.Weekday,
+			StartTime: availability.StartTime,
+			EndTime:   availability.EndTime,
+		})
+	}
+
+	archive := newArchiveWriter(w)
+	if err := archive.writeFile(models.ArchiveBusinessFile, toArchiveBusiness(*business)); err != nil {
+		return 0, err
+	}
+	if err := archive.writeFile(models.ArchiveOwnerFile, toArchiveUser(*owner)); err != nil {
+		return 0, err
+	}
+	if err := writeArchiveSlice(archive, models.ArchiveSubjectsFile, subjects, toArchiveSubject); err != nil {
+		return 0, err
+	}
+	if err := writeArchiveSlice(archive, models.ArchiveBatchesFile, batches, toArchiveBatch); err != nil {
+		return 0, err
+	}
+	if err := writeArchiveSlice(archive, models.ArchiveStudentFieldsFile, fields, toArchiveStudentField); err != nil {
+		return 0, err
+	}
+	if err := writeArchiveSlice(archive, models.ArchiveStudentGradesFile, grades, toArchiveStudentGrade); err != nil {
+		return 0, err
+	}
+
+	err = writeArchiveBatches(ctx, archive, models.ArchiveTeachersFile, s.archiveRepo.ForEachTeacher, businessID, func(teacher models.Teacher) models.ArchiveTeacher {
+		return toArchiveTeacher(teacher, availabilityByTeacher[teacher.ID])
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := writeArchiveBatches(ctx, archive, models.ArchiveStudentsFile, s.archiveRepo.ForEachStudent, businessID, toArchiveStudent); err != nil {
+		return 0, err
+	}
+	if err := writeArchiveBatches(ctx, archive, models.ArchiveStudentAttendanceFile, s.archiveRepo.ForEachStudentAttendance, businessID, toArchiveStudentAttendance); err != nil {
+		return 0, err
+	}
+	if err := writeArchiveBatches(ctx, archive, models.ArchiveTeacherAttendanceFile, s.archiveRepo.ForEachTeacherAttendance, businessID, toArchiveTeacherAttendance); err != nil {
+		return 0, err
+	}
+	if err := writeArchiveBatches(ctx, archive, models.ArchiveFeePlansFile, s.archiveRepo.ForEachFeePlan, businessID, toArchiveFeePlan); err != nil {
+		return 0, err
+	}
+	if err := writeArchiveBatches(ctx, archive, models.ArchiveFeePaymentsFile, s.archiveRepo.ForEachFeePayment, businessID, toArchiveFeePayment); err != nil {
+		return 0, err
+	}
+
+	// Last, once every file has been counted
+	manifest := models.ArchiveManifest{
+		Format:           models.BusinessArchiveFormat,
+		Version:          models.BusinessArchiveVersion,
+		ExportedAt:       time.Now().UTC(),
+		SourceBusinessID: business.ID,
+		Counts:           archive.counts,
+	}
+	if err := archive.writeFile(models.ArchiveManifestFile, manifest); err != nil {
+		return 0, err
+	}
+	if err := archive.zip.Close(); err != nil {
+		return 0, err
+	}
+
+	var records int64
+	for _, count := range archive.counts {
+		records += count
+	}
+	return records, nil
+}
+
+func (s *businessArchiveService) ImportArchive(ctx context.Context, r io.ReaderAt, size int64, req models.ImportArchiveRequest, actorID uint) (*models.ImportArchiveResult, error) {
+	archive, err := readBusinessArchive(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replace the business's identity where asked to
+	if req.Name != "" {
+		archive.Business.Name = req.Name
+	}
+	if req.Slug != "" {
+		archive.Business.Slug = req.Slug
+	}
+	if req.Email != "" {
+		archive.Business.Email = req.Email
+		archive.Owner.Email = req.Email
+	}
+	if req.OwnerEmail != "" {
+		archive.Owner.Email = req.OwnerEmail
+	}
+
+	if err := s.validateArchive(ctx, archive); err != nil {
+		return nil, err
+	}
+	return s.importArchive(ctx, archive, actorID, req.Bootstrap)
+}
+
+// validateArchive checks every reference inside the archive and that none of its emails
+// are taken, so the import transaction doesn't fail half way on bad data
+func (s *businessArchiveService) validateArchive(ctx context.Context, archive *businessArchive) error {
+	var problems []string
+	problem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if strings.TrimSpace(archive.Business.Name) == "" {
+		problem("business name is required")
+	}
+	if archive.Business.Email == "" {
+		problem("business email is required")
+	} else if taken, err := s.archiveRepo.BusinessEmailInUse(ctx, archive.Business.Email); err != nil {
+		return fmt.Errorf("failed to check business email: %v", err)
+	} else if taken {
+		problem("business email %s is already in use", archive.Business.Email)
+	}
+
+	subjects := archiveIDs(archive.Subjects, func(subject models.ArchiveSubject) uint { return subject.ID }, "subject", problem)
+	batches := archiveIDs(archive.Batches, func(batch models.ArchiveBatch) uint { return batch.ID }, "batch", problem)
+	teachers := archiveIDs(archive.Teachers, func(teacher models.ArchiveTeacher) uint { return teacher.ID }, "teacher", problem)
+	students := archiveIDs(archive.Students, func(student models.ArchiveStudent) uint { return student.ID }, "student", problem)
+	plans := archiveIDs(archive.FeePlans, func(plan models.ArchiveFeePlan) uint { return plan.ID }, "fee plan", problem)
+
+	checkDate := func(value, what string, required bool) {
+		if value == "" {
+			if required {
+				problem("%s: date is required", what)
+			}
+			return
+		}
+		if _, err := parseDate(value); err != nil {
+			problem("%s: invalid date %q", what, value)
+		}
+	}
+	checkRef := func(ids map[uint]bool, id *uint, what, kind string) {
+		if id != nil && !ids[*id] {
+			problem("%s refers to unknown %s %d", what, kind, *id)
+		}
+	}
+
+	if _, err := normalizeTimezone(archive.Business.Timezone); err != nil {
+		problem("business: invalid timezone %q", archive.Business.Timezone)
+	}
+	for _, batch := range archive.Batches {
+		what := fmt.Sprintf("batch %d", batch.ID)
+		checkRef(teachers, batch.TeacherID, what, "teacher")
+		checkDate(batch.StartDate, what, false)
+		checkDate(batch.EndDate, what, false)
+	}
+	for _, teacher := range archive.Teachers {
+		for _, subjectID := range teacher.SubjectIDs {
+			checkRef(subjects, &subjectID, fmt.Sprintf("teacher %d", teacher.ID), "subject")
+		}
+	}
+	for _, student := range archive.Students {
+		what := fmt.Sprintf("student %d", student.ID)
+		checkRef(batches, student.BatchID, what, "batch")
+		checkDate(student.EnrolledOn, what, false)
+		checkDate(student.DateOfBirth, what, false)
+	}
+	studentDays := make(map[string]bool, len(archive.StudentAttendance))
+	for i, attendance := range archive.StudentAttendance {
+		what := fmt.Sprintf("student attendance #%d", i+1)
+		checkRef(students, &attendance.StudentID, what, "student")
+		checkRef(batches, attendance.BatchID, what, "batch")
+		checkDate(attendance.Date, what, true)
+		if !validAttendanceStatus(attendance.Status) {
+			problem("%s: invalid status %q", what, attendance.Status)
+		}
+		if key := fmt.Sprintf("%d/%s", attendance.StudentID, attendance.Date); studentDays[key] {
+			problem("%s: student %d is marked twice on %s", what, attendance.StudentID, attendance.Date)
+		} else {
+			studentDays[key] = true
+		}
+	}
+	teacherDays := make(map[string]bool, len(archive.TeacherAttendance))
+	for i, attendance := range archive.TeacherAttendance {
+		what := fmt.Sprintf("teacher attendance #%d", i+1)
+		checkRef(teachers, &attendance.TeacherID, what, "teacher")
+		checkDate(attendance.Date, what, true)
+		if !validAttendanceStatus(attendance.Status) {
+			problem("%s: invalid status %q", what, attendance.Status)
+		}
+		if key := fmt.Sprintf("%d/%s", attendance.TeacherID, attendance.Date); teacherDays[key] {
+			problem("%s: teacher %d is marked twice on %s", what, attendance.TeacherID, attendance.Date)
+		} else {
+			teacherDays[key] = true
+		}
+	}
+	for _, plan := range archive.FeePlans {
+		what := fmt.Sprintf("fee plan %d", plan.ID)
+		if (plan.StudentID == nil) == (plan.BatchID == nil) {
+			problem("%s must be for exactly one of a student or a batch", what)
+		}
+		checkRef(students, plan.StudentID, what, "student")
+		checkRef(batches, plan.BatchID, what, "batch")
+		checkDate(plan.StartDate, what, true)
+		checkDate(plan.EndDate, what, false)
+	}
+	for i, payment := range archive.FeePayments {
+		what := fmt.Sprintf("fee payment #%d", i+1)
+		checkRef(students, &payment.StudentID, what, "student")
+		checkRef(plans, payment.FeePlanID, what, "fee plan")
+		checkDate(payment.PaidOn, what, true)
+	}
+
+	// Every login email must be new
+	emails := []string{archive.Owner.Email}
+	for _, teacher := range archive.Teachers {
+		emails = append(emails, teacher.User.Email)
+	}
+	for _, student := range archive.Students {
+		emails = append(emails, student.User.Email)
+	}
+	seen := make(map[string]bool, len(emails))
+	unique := make([]string, 0, len(emails))
+	for _, email := range emails {
+		switch {
+		case email == "":
+			problem("every user needs an email")
+		case seen[email]:
+			problem("email %s is used by more than one user", email)
+		default:
+			seen[email] = true
+			unique = append(unique, email)
+		}
+	}
+	for start := 0; start < len(unique); start += 1000 {
+		taken, err := s.archiveRepo.UserEmailsInUse(ctx, unique[start:min(start+1000, len(unique))])
+		if err != nil {
+			return fmt.Errorf("failed to check user emails: %v", err)
+		}
+		for _, email := range taken {
+			problem("email %s is already in use", email)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	if len(problems) > maxArchiveProblems {
+		problems = append(problems[:maxArchiveProblems], fmt.Sprintf("and %d more", len(problems)-maxArchiveProblems))
+	}
+	return &models.ArchiveValidationError{Problems: problems}
+}
+
+// importArchive creates the archive's records with fresh IDs, mapping every reference
+// inside the archive onto the new IDs
+func (s *businessArchiveService) importArchive(ctx context.Context, archive *businessArchive, actorID uint, bootstrap bool) (*models.ImportArchiveResult, error) {
+	// Imported accounts get a random password nobody knows; users set theirs through
+	// "forgot password". One hash serves them all, since nobody ever learns the password.
+	password, err := generatePasswordToken()
+	if err != nil {
+		return nil, fmt.Errorf("error generating password: %w", err)
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing password: %w", err)
+	}
+	newUser := func(user models.ArchiveUser, role models.UserRole) models.User {
+		return models.User{Name: user.Name, Email: user.Email, Phone: user.Phone, Password: string(hashedPassword), Role: role, Status: user.Status}
+	}
+
+	// Taken slugs are numbered like the slugs of new businesses
+	slug := generateSlugFromName(archive.Business.Slug)
+	if slug == "" {
+		slug = generateSlugFromName(archive.Business.Name)
+	}
+
+	// Checked along with the rest of the archive
+	timezone, _ := normalizeTimezone(archive.Business.Timezone)
+
+	counts := make(map[string]int64)
+	tx := s.archiveRepo.BeginTransaction(ctx)
+	create := func(file string, records interface{}, n int) error {
+		if n == 0 {
+			return nil
+		}
+		if err := s.archiveRepo.CreateWithTransaction(tx, records); err != nil {
+			return fmt.Errorf("failed to import %s: %w", file, err)
+		}
+		counts[archiveCountKey(file)] += int64(n)
+		return nil
+	}
+
+	importAll := func() (*models.Business, error) {
+		owner := []models.User{newUser(archive.Owner, models.RoleBusiness)}
+		if err := create(models.ArchiveOwnerFile, &owner, 1); err != nil {
+			return nil, err
+		}
+
+		business := models.Business{
+			Name:                archive.Business.Name,
+			Slug:                slug,
+			UserID:              owner[0].ID,
+			OwnerName:           archive.Business.OwnerName,
+			Email:               archive.Business.Email,
+			Phone:               archive.Business.Phone,
+			Location:            archive.Business.Location,
+			Status:              archive.Business.Status,
+			Timezone:            timezone,
+			StrictStudentFields: archive.Business.StrictStudentFields,
+			SMSNotifications:    archive.Business.SMSNotifications,
+		}
+		if err := s.businessRepo.CreateWithUniqueSlugTransaction(tx, &business); err != nil {
+			return nil, fmt.Errorf("failed to import %s: %w", models.ArchiveBusinessFile, err)
+		}
+		counts[archiveCountKey(models.ArchiveBusinessFile)]++
+		businessID := business.ID
+
+		subjects := make([]models.Subject, len(archive.Subjects))
+		for i, subject := range archive.Subjects {
+			subjects[i] = models.Subject{BusinessID: businessID, Name: subject.Name, Description: subject.Description}
+		}
+		if err := create(models.ArchiveSubjectsFile, &subjects, len(subjects)); err != nil {
+			return nil, err
+		}
+		subjectIDs := remapIDs(archive.Subjects, subjects, func(subject models.ArchiveSubject) uint { return subject.ID }, func(subject models.Subject) uint { return subject.ID })
+
+		fields := make([]models.StudentField, len(archive.StudentFields))
+		for i, field := range archive.StudentFields {
+			fields[i] = models.StudentField{BusinessID: businessID, Key: field.Key, Label: field.Label, Type: field.Type, Required: field.Required}
+		}
+		if err := create(models.ArchiveStudentFieldsFile, &fields, len(fields)); err != nil {
+			return nil, err
+		}
+		grades := make([]models.StudentGrade, len(archive.StudentGrades))
+		for i, grade := range archive.StudentGrades {
+			grades[i] = models.StudentGrade{BusinessID: businessID, Name: grade.Name, Position: grade.Position}
+		}
+		if err := create(models.ArchiveStudentGradesFile, &grades, len(grades)); err != nil {
+			return nil, err
+		}
+
+		// Teachers, with their accounts, subjects and weekly availability
+		teacherUsers := make([]models.User, len(archive.Teachers))
+		for i, teacher := range archive.Teachers {
+			teacherUsers[i] = newUser(teacher.User, models.RoleTeacher)
+		}
+		if err := create("teacher users", &teacherUsers, len(teacherUsers)); err != nil {
+			return nil, err
+		}
+		teachers := make([]models.Teacher, len(archive.Teachers))
+		for i, teacher := range archive.Teachers {
+			teachers[i] = models.Teacher{
+				Name:            teacher.Name,
+				UserID:          teacherUsers[i].ID,
+				BusinessID:      businessID,
+				Salary:          teacher.Salary,
+				Qualification:   teacher.Qualification,
+				Experience:      teacher.Experience,
+				ExperienceYears: teacher.ExperienceYears,
+				Description:     teacher.Description,
+				Status:          teacher.Status,
+			}
+		}
+		if err := create(models.ArchiveTeachersFile, &teachers, len(teachers)); err != nil {
+			return nil, err
+		}
+		teacherIDs := remapIDs(archive.Teachers, teachers, func(teacher models.ArchiveTeacher) uint { return teacher.ID }, func(teacher models.Teacher) uint { return teacher.ID })
+
+		teacherSubjects := make(map[uint][]uint)
+		var availabilities []models.TeacherAvailability
+		for i, teacher := range archive.Teachers {
+			for _, subjectID := range teacher.SubjectIDs {
+				teacherSubjects[teachers[i].ID] = append(teacherSubjects[teachers[i].ID], subjectIDs[subjectID])
+			}
+			for _, slot := range teacher.Availability {
+				availabilities = append(availabilities, models.TeacherAvailability{TeacherID: teachers[i].ID, Weekday: slot.Weekday, StartTime: slot.StartTime, EndTime: slot.EndTime})
+			}
+		}
+		if err := s.archiveRepo.CreateTeacherSubjectsWithTransaction(tx, teacherSubjects); err != nil {
+			return nil, fmt.Errorf("failed to import teacher subjects: %w", err)
+		}
+		if err := create("teacher availability", &availabilities, len(availabilities)); err != nil {
+			return nil, err
+		}
+
+		batches := make([]models.Batch, len(archive.Batches))
+		for i, batch := range archive.Batches {
+			startDate, _ := parseOptionalDate(batch.StartDate)
+			endDate, _ := parseOptionalDate(batch.EndDate)
+			batches[i] = models.Batch{BusinessID: businessID, Name: batch.Name, StartDate: startDate, EndDate: endDate, TeacherID: remapID(teacherIDs, batch.TeacherID)}
+		}
+		if err := create(models.ArchiveBatchesFile, &batches, len(batches)); err != nil {
+			return nil, err
+		}
+		batchIDs := remapIDs(archive.Batches, batches, func(batch models.ArchiveBatch) uint { return batch.ID }, func(batch models.Batch) uint { return batch.ID })
+
+		// Students, with their accounts and guardians
+		studentUsers := make([]models.User, len(archive.Students))
+		for i, student := range archive.Students {
+			studentUsers[i] = newUser(student.User, models.RoleStudent)
+		}
+		if err := create("student users", &studentUsers, len(studentUsers)); err != nil {
+			return nil, err
+		}
+		students := make([]models.Student, len(archive.Students))
+		for i, student := range archive.Students {
+			enrolledOn, _ := parseOptionalDate(student.EnrolledOn)
+			dateOfBirth, _ := parseOptionalDate(student.DateOfBirth)
+			students[i] = models.Student{
+				Name:           student.Name,
+				UserID:         studentUsers[i].ID,
+				BusinessID:     businessID,
+				GuardianName:   student.GuardianName,
+				GuardianNumber: student.GuardianNumber,
+				GuardianEmail:  student.GuardianEmail,
+				Information:    student.Information,
+				Status:         student.Status,
+				BatchID:        remapID(batchIDs, student.BatchID),
+				EnrolledOn:     enrolledOn,
+				DateOfBirth:    dateOfBirth,
+				Gender:         student.Gender,
+				Grade:          student.Grade,
+			}
+		}
+		if err := create(models.ArchiveStudentsFile, &students, len(students)); err != nil {
+			return nil, err
+		}
+		studentIDs := remapIDs(archive.Students, students, func(student models.ArchiveStudent) uint { return student.ID }, func(student models.Student) uint { return student.ID })
+
+		var guardians []models.StudentGuardian
+		for i, student := range archive.Students {
+			for _, guardian := range student.Guardians {
+				guardians = append(guardians, models.StudentGuardian{StudentID: students[i].ID, Name: guardian.Name, Relation: guardian.Relation, Phone: guardian.Phone, Email: guardian.Email, IsPrimary: guardian.IsPrimary})
+			}
+		}
+		if err := create("student guardians", &guardians, len(guardians)); err != nil {
+			return nil, err
+		}
+
+		studentAttendance := make([]models.StudentAttendance, len(archive.StudentAttendance))
+		for i, attendance := range archive.StudentAttendance {
+			date, _ := parseDate(attendance.Date)
+			studentAttendance[i] = models.StudentAttendance{StudentID: studentIDs[attendance.StudentID], BusinessID: businessID, BatchID: remapID(batchIDs, attendance.BatchID), Date: date, Status: attendance.Status, Note: attendance.Note, MarkedBy: actorID}
+		}
+		if err := create(models.ArchiveStudentAttendanceFile, &studentAttendance, len(studentAttendance)); err != nil {
+			return nil, err
+		}
+		teacherAttendance := make([]models.TeacherAttendance, len(archive.TeacherAttendance))
+		for i, attendance := range archive.TeacherAttendance {
+			date, _ := parseDate(attendance.Date)
+			teacherAttendance[i] = models.TeacherAttendance{TeacherID: teacherIDs[attendance.TeacherID], BusinessID: businessID, Date: date, Status: attendance.Status, Note: attendance.Note, MarkedBy: actorID}
+		}
+		if err := create(models.ArchiveTeacherAttendanceFile, &teacherAttendance, len(teacherAttendance)); err != nil {
+			return nil, err
+		}
+
+		plans := make([]models.FeePlan, len(archive.FeePlans))
+		for i, plan := range archive.FeePlans {
+			startDate, _ := parseDate(plan.StartDate)
+			endDate, _ := parseOptionalDate(plan.EndDate)
+			plans[i] = models.FeePlan{
+				BusinessID: businessID,
+				StudentID:  remapID(studentIDs, plan.StudentID),
+				BatchID:    remapID(batchIDs, plan.BatchID),
+				Name:       plan.Name,
+				Amount:     plan.Amount,
+				Frequency:  plan.Frequency,
+				DueDay:     plan.DueDay,
+				StartDate:  startDate,
+				EndDate:    endDate,
+				Status:     plan.Status,
+				CreatedBy:  actorID,
+			}
+		}
+		if err := create(models.ArchiveFeePlansFile, &plans, len(plans)); err != nil {
+			return nil, err
+		}
+		planIDs := remapIDs(archive.FeePlans, plans, func(plan models.ArchiveFeePlan) uint { return plan.ID }, func(plan models.FeePlan) uint { return plan.ID })
+
+		payments := make([]models.FeePayment, len(archive.FeePayments))
+		for i, payment := range archive.FeePayments {
+			paidOn, _ := parseDate(payment.PaidOn)
+			payments[i] = models.FeePayment{BusinessID: businessID, StudentID: studentIDs[payment.StudentID], FeePlanID: remapID(planIDs, payment.FeePlanID), Amount: payment.Amount, PaidOn: paidOn, Mode: payment.Mode, Reference: payment.Reference, Note: payment.Note, RecordedBy: actorID}
+		}
+		if err := create(models.ArchiveFeePaymentsFile, &payments, len(payments)); err != nil {
+			return nil, err
+		}
+
+		// Only what the archive left empty gets a default
+		if bootstrap {
+			if err := s.provisioning.ProvisionWithTransaction(tx, business, ProvisionOptions{}); err != nil {
+				return nil, fmt.Errorf("failed to provision business: %w", err)
+			}
+		}
+
+		return &business, nil
+	}
+
+	business, err := importAll()
+	if err != nil {
+		tx.Rollback()
+		if conflict := conflictError(err); conflict != nil {
+			return nil, conflict
+		}
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return &models.ImportArchiveResult{
+		BusinessID: business.ID,
+		Slug:       business.Slug,
+		Version:    archive.Manifest.Version,
+		Counts:     counts,
+	}, nil
+}
+
+// readBusinessArchive reads the manifest first, then the files of the layout its version
+// describes
+func readBusinessArchive(r io.ReaderAt, size int64) (*businessArchive, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("file is not a ZIP archive")
+	}
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[path.Clean(file.Name)] = file
+	}
+
+	archive := &businessArchive{}
+	if err := readArchiveFile(files, models.ArchiveManifestFile, &archive.Manifest, true); err != nil {
+		return nil, err
+	}
+	if archive.Manifest.Format != models.BusinessArchiveFormat {
+		return nil, fmt.Errorf("file is not a business archive")
+	}
+
+	switch version := archive.Manifest.Version; {
+	case version == 1:
+		err = readArchiveV1(files, archive)
+	case version > models.BusinessArchiveVersion:
+		return nil, fmt.Errorf("archive version %d is newer than this server supports (%d)", version, models.BusinessArchiveVersion)
+	default:
+		return nil, fmt.Errorf("unknown archive version %d", version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// readArchiveV1 reads the files of a version 1 archive. The business and its owner are
+// required; the other files may be missing when empty.
+func readArchiveV1(files map[string]*zip.File, archive *businessArchive) error {
+	targets := []struct {
+		name     string
+		value    interface{}
+		required bool
+	}{
+		{models.ArchiveBusinessFile, &archive.Business, true},
+		{models.ArchiveOwnerFile, &archive.Owner, true},
+		{models.ArchiveSubjectsFile, &archive.Subjects, false},
+		{models.ArchiveBatchesFile, &archive.Batches, false},
+		{models.ArchiveStudentFieldsFile, &archive.StudentFields, false},
+		{models.ArchiveStudentGradesFile, &archive.StudentGrades, false},
+		{models.ArchiveTeachersFile, &archive.Teachers, false},
+		{models.ArchiveStudentsFile, &archive.Students, false},
+		{models.ArchiveStudentAttendanceFile, &archive.StudentAttendance, false},
+		{models.ArchiveTeacherAttendanceFile, &archive.TeacherAttendance, false},
+		{models.ArchiveFeePlansFile, &archive.FeePlans, false},
+		{models.ArchiveFeePaymentsFile, &archive.FeePayments, false},
+	}
+	for _, target := range targets {
+		if err := readArchiveFile(files, target.name, target.value, target.required); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readArchiveFile(files map[string]*zip.File, name string, value interface{}, required bool) error {
+	file, ok := files[name]
+	if !ok {
+		if required {
+			return fmt.Errorf("archive has no %s", name)
+		}
+		return nil
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", name, err)
+	}
+	defer rc.Close()
+
+	// Stop a small archive from inflating into something huge
+	limited := &io.LimitedReader{R: rc, N: maxArchiveFileSize + 1}
+	if err := json.NewDecoder(limited).Decode(value); err != nil {
+		if limited.N <= 0 {
+			return fmt.Errorf("%s is too large", name)
+		}
+		return fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return nil
+}
+
+// archiveIDs collects the archive IDs of one kind of record, reporting missing and
+// duplicate ones
+func archiveIDs[T any](records []T, id func(T) uint, kind string, problem func(string, ...interface{})) map[uint]bool {
+	ids := make(map[uint]bool, len(records))
+	for _, record := range records {
+		switch recordID := id(record); {
+		case recordID == 0:
+			problem("every %s needs an id", kind)
+		case ids[recordID]:
+			problem("%s id %d is used more than once", kind, recordID)
+		default:
+			ids[recordID] = true
+		}
+	}
+	return ids
+}
+
+// remapIDs maps archive IDs to the IDs the same records were created with
+func remapIDs[A, M any](archived []A, created []M, archiveID func(A) uint, newID func(M) uint) map[uint]uint {
+	ids := make(map[uint]uint, len(archived))
+	for i, record := range archived {
+		ids[archiveID(record)] = newID(created[i])
+	}
+	return ids
+}
+
+func remapID(ids map[uint]uint, id *uint) *uint {
+	if id == nil {
+		return nil
+	}
+	newID := ids[*id]
+	return &newID
+}
+
+// archiveCountKey names a file in the manifest counts, e.g. "students" for students.json
+func archiveCountKey(file string) string {
+	return strings.TrimSuffix(file, ".json")
+}
+
+func validAttendanceStatus(status string) bool {
+	return status == models.AttendancePresent || status == models.AttendanceAbsent || status == models.AttendanceLeave
+}
+
+// archiveWriter writes the files of an archive to a ZIP stream, counting their records
+type archiveWriter struct {
+	zip    *zip.Writer
+	counts map[string]int64
+}
+
+func newArchiveWriter(w io.Writer) *archiveWriter {
+	return &archiveWriter{zip: zip.NewWriter(w), counts: make(map[string]int64)}
+}
+
+func (a *archiveWriter) writeFile(name string, value interface{}) error {
+	w, err := a.zip.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	return nil
+}
+
+// archiveArray writes a JSON array one element at a time
+type archiveArray struct {
+	w     io.Writer
+	count int64
+}
+
+func (a *archiveArray) add(value interface{}) error {
+	separator := ",\n"
+	if a.count == 0 {
+		separator = "[\n"
+	}
+	if _, err := io.WriteString(a.w, separator); err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if _, err := a.w.Write(data); err != nil {
+		return err
+	}
+	a.count++
+	return nil
+}
+
+func (a *archiveArray) close() error {
+	end := "\n]\n"
+	if a.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(a.w, end)
+	return err
+}
+
+func (a *archiveWriter) startArray(name string) (*archiveArray, error) {
+	w, err := a.zip.Create(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	return &archiveArray{w: w}, nil
+}
+
+func (a *archiveWriter) finishArray(name string, array *archiveArray) error {
+	if err := array.close(); err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	a.counts[archiveCountKey(name)] = array.count
+	return nil
+}
+
+func writeArchiveSlice[T, R any](archive *archiveWriter, name string, records []T, convert func(T) R) error {
+	array, err := archive.startArray(name)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := array.add(convert(record)); err != nil {
+			return fmt.Errorf("failed to write %s: %v", name, err)
+		}
+	}
+	return archive.finishArray(name, array)
+}
+
+// writeArchiveBatches streams a table into an archive file as forEach reads it
+func writeArchiveBatches[T, R any](ctx context.Context, archive *archiveWriter, name string, forEach func(context.Context, uint, int, func([]T) error) error, businessID uint, convert func(T) R) error {
+	array, err := archive.startArray(name)
+	if err != nil {
+		return err
+	}
+	err = forEach(ctx, businessID, exportBatchSize, func(records []T) error {
+		for _, record := range records {
+			if err := array.add(convert(record)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	return archive.finishArray(name, array)
+}
+
+func toArchiveBusiness(business models.Business) models.ArchiveBusiness {
+	return models.ArchiveBusiness{
+		Name:                business.Name,
+		Slug:                business.Slug,
+		OwnerName:           business.OwnerName,
+		Email:               business.Email,
+		Phone:               business.Phone,
+		Location:            business.Location,
+		Status:              business.Status,
+		Timezone:            business.Timezone,
+		StrictStudentFields: business.StrictStudentFields,
+		SMSNotifications:    business.SMSNotifications,
+	}
+}
+
+func toArchiveUser(user models.User) models.ArchiveUser {
+	return models.ArchiveUser{Name: user.Name, Email: user.Email, Phone: user.Phone, Status: user.Status}
+}
+
+func toArchiveSubject(subject models.Subject) models.ArchiveSubject {
+	return models.ArchiveSubject{ID: subject.ID, Name: subject.Name, Description: subject.Description}
+}
+
+func toArchiveBatch(batch models.Batch) models.ArchiveBatch {
+	return models.ArchiveBatch{
+		ID:        batch.ID,
+		Name:      batch.Name,
+		StartDate: formatOptionalDate(batch.StartDate),
+		EndDate:   formatOptionalDate(batch.EndDate),
+		TeacherID: batch.TeacherID,
+	}
+}
+
+func toArchiveStudentField(field models.StudentField) models.ArchiveStudentField {
+	return models.ArchiveStudentField{Key: field.Key, Label: field.Label, Type: field.Type, Required: field.Required}
+}
+
+func toArchiveStudentGrade(grade models.StudentGrade) models.ArchiveStudentGrade {
+	return models.ArchiveStudentGrade{Name: grade.Name, Position: grade.Position}
+}
+
+func toArchiveTeacher(teacher models.Teacher, availability []models.ArchiveAvailability) models.ArchiveTeacher {
+	subjectIDs := make([]uint, len(teacher.Subjects))
+	for i, subject := range teacher.Subjects {
+		subjectIDs[i] = subject.ID
+	}
+	sort.Slice(subjectIDs, func(i, j int) bool { return subjectIDs[i] < subjectIDs[j] })
+	if availability == nil {
+		availability = []models.ArchiveAvailability{}
+	}
+
+	return models.ArchiveTeacher{
+		ID:              teacher.ID,
+		User:            toArchiveUser(teacher.User),
+		Name:            teacher.Name,
+		Salary:          teacher.Salary,
+		Qualification:   teacher.Qualification,
+		Experience:      teacher.Experience,
+		ExperienceYears: teacher.ExperienceYears,
+		Description:     teacher.Description,
+		Status:          teacher.Status,
+		SubjectIDs:      subjectIDs,
+		Availability:    availability,
+	}
+}
+
+func toArchiveStudent(student models.Student) models.ArchiveStudent {
+	guardians := make([]models.ArchiveGuardian, len(student.Guardians))
+	for i, guardian := range student.Guardians {
+		guardians[i] = models.ArchiveGuardian{Name: guardian.Name, Relation: guardian.Relation, Phone: guardian.Phone, Email: guardian.Email, IsPrimary: guardian.IsPrimary}
+	}
+
+	return models.ArchiveStudent{
+		ID:             student.ID,
+		User:           toArchiveUser(student.User),
+		Name:           student.Name,
+		BatchID:        student.BatchID,
+		EnrolledOn:     formatOptionalDate(student.EnrolledOn),
+		DateOfBirth:    formatOptionalDate(student.DateOfBirth),
+		Gender:         student.Gender,
+		Grade:          student.Grade,
+		Information:    student.Information,
+		Status:         student.Status,
+		GuardianName:   student.GuardianName,
+		GuardianNumber: student.GuardianNumber,
+		GuardianEmail:  student.GuardianEmail,
+		Guardians:      guardians,
+	}
+}
+
+func toArchiveStudentAttendance(attendance models.StudentAttendance) models.ArchiveStudentAttendance {
+	return models.ArchiveStudentAttendance{
+		StudentID: attendance.StudentID,
+		BatchID:   attendance.BatchID,
+		Date:      attendance.Date.Format(models.DateFormat),
+		Status:    attendance.Status,
+		Note:      attendance.Note,
+	}
+}
+
+func toArchiveTeacherAttendance(attendance models.TeacherAttendance) models.ArchiveTeacherAttendance {
+	return models.ArchiveTeacherAttendance{
+		TeacherID: attendance.TeacherID,
+		Date:      attendance.Date.Format(models.DateFormat),
+		Status:    attendance.Status,
+		Note:      attendance.Note,
+	}
+}
+
+func toArchiveFeePlan(plan models.FeePlan) models.ArchiveFeePlan {
+	return models.ArchiveFeePlan{
+		ID:        plan.ID,
+		StudentID: plan.StudentID,
+		BatchID:   plan.BatchID,
+		Name:      plan.Name,
+		Amount:    plan.Amount,
+		Frequency: plan.Frequency,
+		DueDay:    plan.DueDay,
+		StartDate: plan.StartDate.Format(models.DateFormat),
+		EndDate:   formatOptionalDate(plan.EndDate),
+		Status:    plan.Status,
+	}
+}
+
+func toArchiveFeePayment(payment models.FeePayment) models.ArchiveFeePayment {
+	return models.ArchiveFeePayment{
+		StudentID: payment.StudentID,
+		FeePlanID: payment.FeePlanID,
+		Amount:    payment.Amount,
+		PaidOn:    payment.PaidOn.Format(models.DateFormat),
+		Mode:      payment.Mode,
+		Reference: payment.Reference,
+		Note:      payment.Note,
+	}
+}
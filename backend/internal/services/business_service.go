@@ -3,12 +3,18 @@ package services
 import (
 	"backend/internal/models"
 	"backend/internal/repository"
+	"backend/pkg/cache"
+	"backend/pkg/logger"
+	"backend/pkg/storage"
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
 	"regexp"
 	"strings"
-
-	"golang.org/x/crypto/bcrypt"
+	"time"
 )
 
 func generateSlugFromName(name string) string {
@@ -25,217 +31,298 @@ func generateSlugFromName(name string) string {
 	return slug
 }
 
+// slugPattern is the form of a valid slug: lowercase letters and digits in hyphen-separated words
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// themeColorPattern is the form of a theme color: #rgb or #rrggbb in hex
+var themeColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// MaxBusinessBannerSize is the largest accepted banner upload
+const MaxBusinessBannerSize = 5 << 20 // 5 MB
+
+var businessBannerRules = storage.UploadRules{
+	MaxSize:      MaxBusinessBannerSize,
+	AllowedTypes: []string{"image/jpeg", "image/png", "image/webp"},
+}
+
 type BusinessService interface {
-	CreateBusiness(req models.CreateBusinessRequest) (*models.BusinessResponse, error)
-	GetBusinesses(filters repository.BusinessFilters) ([]models.BusinessResponse, int64, error)
-	GetBusinessByID(id uint) (*models.BusinessResponse, error)
-	GetBusinessBySlug(slug string) (*models.BusinessResponse, error)
-	GetBusinessByUserID(userID uint) (*models.BusinessResponse, error)
-	UpdateBusiness(id uint, updates map[string]interface{}) (*models.BusinessResponse, error)
-	DeleteBusiness(id uint) error
-	GetActiveBusinesses() ([]models.BusinessResponse, error)
-	GetInactiveBusinesses() ([]models.BusinessResponse, error)
-	ChangeBusinessStatus(businessID uint, status int) error
-	AssignPackage(businessID, packageID uint) error
-	RemovePackage(businessID uint) error
-	GetBusinessesByPackage(packageID uint) ([]models.BusinessResponse, error)
-	GetBusinessesWithoutPackage() ([]models.BusinessResponse, error)
-	BusinessEmailExists(email string, excludeBusinessID ...uint) (bool, error)
-	BusinessNameExists(name string, excludeBusinessID ...uint) (bool, error)
-	GetBusinessStats() (map[string]interface{}, error)
-	GetLocationStats() (map[string]int64, error)
-	GetPackageDistribution() (map[string]int64, error)
-	SearchBusinesses(searchTerm string, limit int) ([]models.BusinessResponse, error)
-	BulkUpdateBusinessStatus(businessIDs []uint, status int) error
-	BulkAssignPackage(businessIDs []uint, packageID uint) error
-	GetBusinessesByLocation(location string) ([]models.BusinessResponse, error)
-	GetBusinessLocations() ([]string, error)
+	CreateBusiness(ctx context.Context, req models.CreateBusinessRequest) (*models.BusinessResponse, error)
+	GetBusinesses(ctx context.Context, filters repository.BusinessFilters) ([]models.BusinessResponse, repository.PageInfo, error)
+	GetBusinessByID(ctx context.Context, id uint) (*models.BusinessResponse, error)
+	GetBusinessBySlug(ctx context.Context, slug string) (*models.BusinessResponse, error)
+	GetBusinessByUserID(ctx context.Context, userID uint) (*models.BusinessResponse, error)
+	UpdateBusiness(ctx context.Context, id uint, updates map[string]interface{}) (*models.BusinessResponse, error)
+	DeleteBusiness(ctx context.Context, id uint, opts models.DeleteBusinessOptions) error
+	ChangeBusinessStatus(ctx context.Context, businessID uint, status models.Status, cascade bool) error
+	ChangeBusinessSlug(ctx context.Context, businessID uint, req models.ChangeSlugRequest, actorID uint) (*models.ChangeSlugResponse, error)
+	AssignPackage(ctx context.Context, businessID, packageID uint) error
+	RemovePackage(ctx context.Context, businessID uint) error
+	BusinessEmailExists(ctx context.Context, email string, excludeBusinessID ...uint) (bool, error)
+	BusinessNameExists(ctx context.Context, name string, excludeBusinessID ...uint) (bool, error)
+	GetBusinessStats(ctx context.Context) (map[string]interface{}, error)
+	GetLocationStats(ctx context.Context) (map[string]int64, error)
+	GetPackageDistribution(ctx context.Context) (map[string]int64, error)
+	GetAtRiskBusinesses(ctx context.Context, filters repository.AtRiskFilters) ([]models.AtRiskBusiness, repository.PageInfo, error)
+	GetGrowthTimeseries(ctx context.Context, query models.GrowthTimeseriesQuery) (*models.GrowthTimeseries, error)
+	SearchBusinesses(ctx context.Context, searchTerm string, limit int) ([]models.BusinessResponse, error)
+	BulkUpdateBusinessStatus(ctx context.Context, businessIDs []uint, status models.Status, atomic, cascade bool) (*models.BulkUpdateResult, error)
+	BulkAssignPackage(ctx context.Context, businessIDs []uint, packageID uint, atomic bool) (*models.BulkUpdateResult, error)
+	BulkRemovePackage(ctx context.Context, businessIDs []uint, actorID uint) (int64, error)
+	SwapPackage(ctx context.Context, fromPackageID, toPackageID, actorID uint) (int64, error)
+	GetBusinessLocations(ctx context.Context) ([]string, error)
+	UploadBanner(ctx context.Context, businessID uint, file multipart.File, header *multipart.FileHeader) error
+	DeleteBanner(ctx context.Context, businessID uint) error
+	GetBannerBySlug(ctx context.Context, slug string) (*models.BusinessBanner, error)
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+	PurgeSampleData(ctx context.Context, businessID uint) (*models.PurgeSampleDataResponse, error)
+	// GetBusinessIDByUser resolves the business owned by a business user
+	GetBusinessIDByUser(ctx context.Context, userID uint) (uint, error)
 }
 
 type businessService struct {
-	businessRepo repository.BusinessRepository
-	userRepo     repository.UserRepository
-	packageRepo  repository.PackageRepository
+	businessRepo  repository.BusinessRepository
+	userRepo      repository.UserRepository
+	packageRepo   repository.PackageRepository
+	cache         cache.Cache
+	passwords     PasswordService
+	notifications NotificationService
+	storage       storage.Storage
+	provisioning  ProvisioningService
 }
 
-func NewBusinessService(businessRepo repository.BusinessRepository, userRepo repository.UserRepository, packageRepo repository.PackageRepository) BusinessService {
+func NewBusinessService(businessRepo repository.BusinessRepository, userRepo repository.UserRepository, packageRepo repository.PackageRepository, cache cache.Cache, passwords PasswordService, notifications NotificationService, store storage.Storage, provisioning ProvisioningService) BusinessService {
 	return &businessService{
-		businessRepo: businessRepo,
-		userRepo:     userRepo,
-		packageRepo:  packageRepo,
+		businessRepo:  businessRepo,
+		userRepo:      userRepo,
+		packageRepo:   packageRepo,
+		cache:         cache,
+		passwords:     passwords,
+		notifications: notifications,
+		storage:       store,
+		provisioning:  provisioning,
 	}
 }
 
-func (s *businessService) CreateBusiness(req models.CreateBusinessRequest) (*models.BusinessResponse, error) {
-	// Check if business email already exists
-	exists, err := s.businessRepo.BusinessEmailExists(req.Email)
+func (s *businessService) CreateBusiness(ctx context.Context, req models.CreateBusinessRequest) (*models.BusinessResponse, error) {
+	phone, err := normalizePhoneField("phone", req.Phone)
 	if err != nil {
-		return nil, fmt.Errorf("error checking business email existence: %w", err)
+		return nil, err
 	}
-	if exists {
-		return nil, errors.New("business email already exists")
+	if req.SampleData && !req.Bootstrap {
+		return nil, &FieldError{Field: "sample_data", Message: "requires bootstrap"}
 	}
-
-	// Check if user email already exists (for creating user account)
-	exists, err = s.userRepo.EmailExists(req.Email)
+	timezone, err := normalizeTimezone(req.Timezone)
 	if err != nil {
-		return nil, fmt.Errorf("error checking user email existence: %w", err)
-	}
-	if exists {
-		return nil, errors.New("user with this email already exists")
+		return nil, err
 	}
 
 	// Validate package if provided
+	var pkg *models.Package
 	if req.PackageID != nil {
-		_, err := s.packageRepo.GetByID(*req.PackageID)
-		if err != nil {
+		if pkg, err = s.packageRepo.GetByID(ctx, *req.PackageID); err != nil {
 			return nil, errors.New("invalid package ID")
 		}
 	}
 
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	// Without a password the account gets a random one nobody knows, until the owner
+	// sets theirs through the welcome email
+	password := req.Password
+	if password == "" {
+		if password, err = generatePasswordToken(); err != nil {
+			return nil, fmt.Errorf("error generating password: %w", err)
+		}
+	}
+
+	// The owner signs in with their user account, which alone holds the password
+	hashedPassword, err := hashPassword(password)
 	if err != nil {
-		return nil, fmt.Errorf("error hashing password: %w", err)
+		return nil, err
 	}
 
 	// Start transaction
-	tx := s.businessRepo.BeginTransaction()
+	tx := s.businessRepo.BeginTransaction(ctx)
+
+	// The owner's account and the business share the email, which no other user or
+	// business may use
+	if err := checkEmailAvailability(tx, s.userRepo, req.Email, 0); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
 	// Create user account first
 	user := &models.User{
 		Name:     req.OwnerName,
 		Email:    req.Email,
-		Phone:    req.Phone,
-		Password: string(hashedPassword),
+		Phone:    phone,
+		Password: hashedPassword,
 		Role:     models.RoleBusiness,
-		Status:   1, // Active by default
+		Status:   models.StatusActive, // Active by default
 	}
 
 	if err := s.userRepo.CreateUserInTransaction(tx, user); err != nil {
 		tx.Rollback()
+		if conflict := conflictError(err); conflict != nil {
+			return nil, conflict
+		}
 		return nil, fmt.Errorf("error creating user account: %w", err)
 	}
 
 	slug := generateSlugFromName(req.Slug)
-
-	fmt.Println("Slug:", slug)
-
-	originalSlug := slug
-	counter := 1
-	for {
-		exists, err := s.businessRepo.BusinessSlugExists(slug)
-		if err != nil {
-			return nil, fmt.Errorf("error checking slug existence: %w", err)
-		}
-		if !exists {
-			break
-		}
-		slug = fmt.Sprintf("%s-%d", originalSlug, counter)
-		counter++
+	if slug == "" {
+		slug = generateSlugFromName(req.Name)
 	}
 
 	// Create business
 	business := &models.Business{
 		Name:      req.Name,
-		Slug:      slug, // Add this line
+		Slug:      slug, // numbered by the repository when taken
 		UserID:    user.ID,
 		OwnerName: req.OwnerName,
-		PackageID: req.PackageID,
 		Email:     req.Email,
-		Phone:     req.Phone,
+		Phone:     phone,
 		Location:  req.Location,
-		Password:  string(hashedPassword),
-		Status:    1,
+		Status:    models.StatusActive,
+		Timezone:  timezone,
+	}
+	if pkg != nil {
+		assignPackage(business, *pkg, time.Now())
 	}
 
-	if err := s.businessRepo.CreateWithTransaction(tx, business); err != nil {
+	if err := s.businessRepo.CreateWithUniqueSlugTransaction(tx, business); err != nil {
 		tx.Rollback()
+		if errors.Is(err, repository.ErrSlugUnavailable) {
+			return nil, errors.New("could not find a free slug for this business, choose another slug")
+		}
+		if conflict := conflictError(err); conflict != nil {
+			return nil, conflict
+		}
 		return nil, fmt.Errorf("error creating business: %w", err)
 	}
 
+	if req.Bootstrap {
+		if err := s.provisioning.ProvisionWithTransaction(tx, *business, ProvisionOptions{SampleData: req.SampleData}); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("error provisioning business: %w", err)
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	// The business exists either way, and the owner can still use "forgot password"
+	if err := s.passwords.SendWelcome(ctx, *user, business.Name); err != nil {
+		logger.FromContext(ctx).Error("Error sending welcome email", "business_id", business.ID, "error", err)
+	}
+
 	businessResponse := s.toBusinessResponse(*business)
+	businessResponse.Theme = businessTheme(*business)
 	return &businessResponse, nil
 }
 
-func (s *businessService) GetBusinesses(filters repository.BusinessFilters) ([]models.BusinessResponse, int64, error) {
-	// Set default pagination if not provided
-	if filters.Limit <= 0 {
-		filters.Limit = 10
-	}
-	if filters.Page <= 0 {
-		filters.Page = 1
-	}
+func (s *businessService) GetBusinesses(ctx context.Context, filters repository.BusinessFilters) ([]models.BusinessResponse, repository.PageInfo, error) {
+	applyPageDefaults(&filters.Page, &filters.Limit)
 
-	businesses, total, err := s.businessRepo.GetAllWithRelations(filters)
+	businesses, total, err := s.businessRepo.GetAllWithRelations(ctx, filters)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error fetching businesses: %w", err)
+		return nil, repository.PageInfo{}, fmt.Errorf("error fetching businesses: %w", err)
 	}
 
+	// Lists stay slim; the theme is only for those asking for it
 	var businessResponses []models.BusinessResponse
 	for _, business := range businesses {
-		businessResponses = append(businessResponses, s.toBusinessResponseWithRelations(business))
+		response := s.toBusinessResponseWithRelations(business)
+		if filters.IncludeTheme {
+			response.Theme = businessTheme(business)
+		}
+		businessResponses = append(businessResponses, response)
 	}
 
-	return businessResponses, total, nil
+	return businessResponses, filters.PageInfo(total, businesses), nil
 }
 
-func (s *businessService) GetBusinessByID(id uint) (*models.BusinessResponse, error) {
+func (s *businessService) GetBusinessByID(ctx context.Context, id uint) (*models.BusinessResponse, error) {
 	if id == 0 {
 		return nil, errors.New("invalid business ID")
 	}
 
-	business, err := s.businessRepo.GetBusinessWithRelations(id)
+	business, err := s.businessRepo.GetBusinessWithRelations(ctx, id)
 	if err != nil {
 		return nil, errors.New("business not found")
 	}
 
 	businessResponse := s.toBusinessResponseWithRelations(*business)
+	businessResponse.Theme = businessTheme(*business)
+	if business.Package != nil && business.Package.ID != 0 {
+		businessResponse.PackagePricing = &models.BusinessPackagePricing{
+			AssignedPrice:            business.PackagePrice,
+			AssignedValidationPeriod: business.PackageValidationPeriod,
+			CurrentPrice:             business.Package.Price,
+			CurrentValidationPeriod:  business.Package.ValidationPeriod,
+			PriceChanged:             business.PackagePrice != nil && *business.PackagePrice != business.Package.Price,
+		}
+	}
 	return &businessResponse, nil
 }
 
-func (s *businessService) GetBusinessBySlug(slug string) (*models.BusinessResponse, error) {
+func (s *businessService) GetBusinessBySlug(ctx context.Context, slug string) (*models.BusinessResponse, error) {
 	if slug == "" {
 		return nil, errors.New("slug cannot be empty")
 	}
 
-	business, err := s.businessRepo.GetBySlugWithRelations(slug)
-	if err != nil {
-		return nil, errors.New("business not found")
-	}
+	return readThrough(ctx, s.cache, businessSlugCachePrefix+slug, func() (*models.BusinessResponse, error) {
+		business, err := s.businessRepo.GetBySlugWithRelations(ctx, slug)
+		if err != nil {
+			return nil, errors.New("business not found")
+		}
 
-	businessResponse := s.toBusinessResponseWithRelations(*business)
-	return &businessResponse, nil
+		businessResponse := s.toBusinessResponseWithRelations(*business)
+		businessResponse.Theme = businessTheme(*business)
+		return &businessResponse, nil
+	})
 }
 
-func (s *businessService) GetBusinessByUserID(userID uint) (*models.BusinessResponse, error) {
+func (s *businessService) GetBusinessByUserID(ctx context.Context, userID uint) (*models.BusinessResponse, error) {
 	if userID == 0 {
 		return nil, errors.New("invalid user ID")
 	}
 
-	business, err := s.businessRepo.GetByUserID(userID)
+	business, err := s.businessRepo.GetByUserID(ctx, userID)
 	if err != nil {
 		return nil, errors.New("business not found")
 	}
 
 	businessResponse := s.toBusinessResponse(*business)
+	businessResponse.Theme = businessTheme(*business)
 	return &businessResponse, nil
 }
 
-func (s *businessService) UpdateBusiness(id uint, updates map[string]interface{}) (*models.BusinessResponse, error) {
+func (s *businessService) GetBusinessIDByUser(ctx context.Context, userID uint) (uint, error) {
+	return ownBusinessID(ctx, s.businessRepo, userID)
+}
+
+// PurgeSampleData removes the sample students a bootstrapped business was created with
+func (s *businessService) PurgeSampleData(ctx context.Context, businessID uint) (*models.PurgeSampleDataResponse, error) {
+	return s.provisioning.PurgeSampleData(ctx, businessID)
+}
+
+func (s *businessService) UpdateBusiness(ctx context.Context, id uint, updates map[string]interface{}) (*models.BusinessResponse, error) {
 	if id == 0 {
 		return nil, errors.New("invalid business ID")
 	}
 
-	business, err := s.businessRepo.GetByID(id)
+	business, err := s.businessRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("business not found")
 	}
+	oldStatus := business.Status
+	oldPackageID := business.PackageID
+
+	version, versioned := expectedVersion(updates)
+	if versioned && version != business.Version {
+		return nil, &VersionConflictError{Current: s.toBusinessResponse(*business)}
+	}
 
 	// Track if any updates were made
 	hasUpdates := false
@@ -247,7 +334,7 @@ func (s *businessService) UpdateBusiness(id uint, updates map[string]interface{}
 	// Apply updates with validation
 	if name, ok := updates["name"].(string); ok && name != "" {
 		// Check if name already exists for another business
-		exists, err := s.businessRepo.BusinessNameExists(name, business.ID)
+		exists, err := s.businessRepo.BusinessNameExists(ctx, name, business.ID)
 		if err != nil {
 			return nil, fmt.Errorf("error checking business name existence: %w", err)
 		}
@@ -265,25 +352,10 @@ func (s *businessService) UpdateBusiness(id uint, updates map[string]interface{}
 		hasUserUpdates = true
 	}
 
+	// A new email is checked when the business is saved
+	emailChanged := false
 	if email, ok := updates["email"].(string); ok && email != "" {
-		// Check if email already exists for another business
-		exists, err := s.businessRepo.BusinessEmailExists(email, business.ID)
-		if err != nil {
-			return nil, fmt.Errorf("error checking business email existence: %w", err)
-		}
-		if exists {
-			return nil, errors.New("business email already exists")
-		}
-
-		// Check if email already exists for another user
-		exists, err = s.userRepo.EmailExists(email, business.UserID)
-		if err != nil {
-			return nil, fmt.Errorf("error checking user email existence: %w", err)
-		}
-		if exists {
-			return nil, errors.New("user with this email already exists")
-		}
-
+		emailChanged = email != business.Email
 		business.Email = email
 		userUpdates["email"] = email
 		hasUpdates = true
@@ -291,6 +363,10 @@ func (s *businessService) UpdateBusiness(id uint, updates map[string]interface{}
 	}
 
 	if phone, ok := updates["phone"].(string); ok {
+		phone, err := normalizePhoneField("phone", phone)
+		if err != nil {
+			return nil, err
+		}
 		business.Phone = phone
 		userUpdates["phone"] = phone
 		hasUpdates = true
@@ -305,51 +381,73 @@ func (s *businessService) UpdateBusiness(id uint, updates map[string]interface{}
 	if packageID, ok := updates["package_id"]; ok {
 		if packageID == nil {
 			business.PackageID = nil
+			business.PackageExpiresAt = nil
+			business.PackagePrice = nil
+			business.PackageValidationPeriod = nil
 		} else {
 			packageIDUint := uint(packageID.(float64))
 			// Validate package exists
-			_, err := s.packageRepo.GetByID(packageIDUint)
+			pkg, err := s.packageRepo.GetByID(ctx, packageIDUint)
 			if err != nil {
 				return nil, errors.New("invalid package ID")
 			}
-			business.PackageID = &packageIDUint
+			// Re-assigning the current package keeps its expiry and the terms it was
+			// assigned on
+			if business.PackageID == nil || *business.PackageID != packageIDUint {
+				assignPackage(business, *pkg, time.Now())
+			}
 		}
 		hasUpdates = true
 	}
 
-	if status, ok := updates["status"].(int); ok {
-		if status < 0 || status > 1 {
-			return nil, errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
-		}
+	if status, ok, err := updatedStatus(updates); err != nil {
+		return nil, err
+	} else if ok {
 		business.Status = status
 		userUpdates["status"] = status
 		hasUpdates = true
 		hasUserUpdates = true
 	}
 
-	// Handle float64 status (from JSON)
-	if status, ok := updates["status"].(float64); ok {
-		statusInt := int(status)
-		if statusInt < 0 || statusInt > 1 {
-			return nil, errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
+	if isOpen, ok := updates["is_open"].(bool); ok {
+		business.IsOpen = isOpen
+		hasUpdates = true
+	}
+
+	if timezone, ok := updates["timezone"].(string); ok {
+		timezone, err := normalizeTimezone(timezone)
+		if err != nil {
+			return nil, err
 		}
-		business.Status = statusInt
-		userUpdates["status"] = statusInt
+		business.Timezone = timezone
 		hasUpdates = true
-		hasUserUpdates = true
 	}
 
-	// Hash new password if provided
-	if password, ok := updates["password"].(string); ok && password != "" {
-		if len(password) < 6 {
-			return nil, errors.New("password must be at least 6 characters")
+	if color, ok := updates["theme_primary_color"].(string); ok {
+		color, err := normalizeThemeColor("theme_primary_color", color)
+		if err != nil {
+			return nil, err
 		}
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		business.ThemePrimaryColor = color
+		hasUpdates = true
+	}
+
+	if color, ok := updates["theme_accent_color"].(string); ok {
+		color, err := normalizeThemeColor("theme_accent_color", color)
 		if err != nil {
-			return nil, fmt.Errorf("error hashing password: %w", err)
+			return nil, err
 		}
-		business.Password = string(hashedPassword)
-		userUpdates["password"] = string(hashedPassword)
+		business.ThemeAccentColor = color
+		hasUpdates = true
+	}
+
+	// An admin resetting the owner's password changes the user account only
+	if password, ok := updates["password"].(string); ok && password != "" {
+		hashedPassword, err := hashPassword(password)
+		if err != nil {
+			return nil, err
+		}
+		userUpdates["password"] = hashedPassword
 		hasUpdates = true
 		hasUserUpdates = true
 	}
@@ -359,36 +457,58 @@ func (s *businessService) UpdateBusiness(id uint, updates map[string]interface{}
 	}
 
 	// Start transaction
-	tx := s.businessRepo.BeginTransaction()
+	tx := s.businessRepo.BeginTransaction(ctx)
+
+	if emailChanged {
+		if err := checkEmailAvailability(tx, s.userRepo, business.Email, business.UserID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
 
 	// Update business
-	if err := s.businessRepo.UpdateWithTransaction(tx, business); err != nil {
+	if versioned {
+		err = s.businessRepo.UpdateVersionedWithTransaction(tx, business, version)
+	} else {
+		err = s.businessRepo.UpdateWithTransaction(tx, business)
+	}
+	if errors.Is(err, repository.ErrVersionConflict) {
 		tx.Rollback()
+		if current, getErr := s.businessRepo.GetByID(ctx, id); getErr == nil {
+			return nil, &VersionConflictError{Current: s.toBusinessResponse(*current)}
+		}
+		return nil, err
+	}
+	if err != nil {
+		tx.Rollback()
+		if conflict := conflictError(err); conflict != nil {
+			return nil, conflict
+		}
 		return nil, fmt.Errorf("error updating business: %w", err)
 	}
 
-	// Update user if needed
-	if hasUserUpdates {
-		user := &models.User{ID: business.UserID}
-		if name, ok := userUpdates["name"].(string); ok {
-			user.Name = name
-		}
-		if email, ok := userUpdates["email"].(string); ok {
-			user.Email = email
-		}
-		if phone, ok := userUpdates["phone"].(string); ok {
-			user.Phone = phone
-		}
-		if password, ok := userUpdates["password"].(string); ok {
-			user.Password = password
+	if business.Status != oldStatus {
+		if err := s.businessRepo.RecordStatusChangeWithTransaction(tx, business.ID, business.Status); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("error recording status change: %w", err)
 		}
-		if status, ok := userUpdates["status"].(int); ok {
-			user.Status = status
-		} else if statusF, ok := userUpdates["status"].(float64); ok {
-			user.Status = int(statusF)
+	}
+
+	if !samePackage(business.PackageID, oldPackageID) {
+		if err := s.businessRepo.RecordPackageChangeWithTransaction(tx, business.ID, oldPackageID, business.PackageID); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("error recording package change: %w", err)
 		}
-		if err := s.userRepo.UpdateUser(user); err != nil {
+	}
+
+	// Only the changed columns are written, so the owner's role and anything not
+	// being updated stay as they are
+	if hasUserUpdates {
+		if err := s.userRepo.UpdateUserFieldsInTransaction(tx, business.UserID, userUpdates); err != nil {
 			tx.Rollback()
+			if conflict := conflictError(err); conflict != nil {
+				return nil, conflict
+			}
 			return nil, fmt.Errorf("error updating user: %w", err)
 		}
 	}
@@ -397,231 +517,370 @@ func (s *businessService) UpdateBusiness(id uint, updates map[string]interface{}
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
+	s.invalidateBusiness(ctx, business.Slug)
 
 	businessResponse := s.toBusinessResponse(*business)
+	businessResponse.Theme = businessTheme(*business)
 	return &businessResponse, nil
 }
 
-func (s *businessService) DeleteBusiness(id uint) error {
+func (s *businessService) DeleteBusiness(ctx context.Context, id uint, opts models.DeleteBusinessOptions) error {
 	if id == 0 {
 		return errors.New("invalid business ID")
 	}
 
-	business, err := s.businessRepo.GetByID(id)
+	business, err := s.businessRepo.GetByID(ctx, id)
 	if err != nil {
 		return errors.New("business not found")
 	}
 
+	// Teachers, students, attendance and fees are only removed when asked for explicitly
+	if !opts.Cascade {
+		counts, err := s.businessRepo.CountDependentRecords(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to check business records: %w", err)
+		}
+		for _, count := range counts {
+			if count > 0 {
+				return &models.BusinessDependentsError{Counts: counts}
+			}
+		}
+	}
+
 	// Start transaction
-	tx := s.businessRepo.BeginTransaction()
+	tx := s.businessRepo.BeginTransaction(ctx)
 
-	// Delete business first
-	if err := s.businessRepo.Delete(id); err != nil {
+	// Delete the business, everything it owns and the associated user accounts
+	if err := s.businessRepo.DeleteWithTransaction(tx, id); err != nil {
 		tx.Rollback()
 		return fmt.Errorf("error deleting business: %w", err)
 	}
 
-	// Delete associated user account
-	if err := s.userRepo.Delete(business.UserID); err != nil {
-		tx.Rollback()
-		return fmt.Errorf("error deleting user account: %w", err)
-	}
-
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
 	}
+	s.invalidateBusiness(ctx, business.Slug)
 
 	return nil
 }
 
-func (s *businessService) GetActiveBusinesses() ([]models.BusinessResponse, error) {
-	businesses, err := s.businessRepo.GetActiveBusinesses()
-	if err != nil {
-		return nil, fmt.Errorf("error fetching active businesses: %w", err)
-	}
-
-	var businessResponses []models.BusinessResponse
-	for _, business := range businesses {
-		businessResponses = append(businessResponses, s.toBusinessResponse(business))
-	}
-
-	return businessResponses, nil
-}
-
-func (s *businessService) GetInactiveBusinesses() ([]models.BusinessResponse, error) {
-	businesses, err := s.businessRepo.GetInactiveBusinesses()
-	if err != nil {
-		return nil, fmt.Errorf("error fetching inactive businesses: %w", err)
-	}
-
-	var businessResponses []models.BusinessResponse
-	for _, business := range businesses {
-		businessResponses = append(businessResponses, s.toBusinessResponse(business))
-	}
-
-	return businessResponses, nil
-}
-
-func (s *businessService) ChangeBusinessStatus(businessID uint, status int) error {
+// ChangeBusinessStatus sets a business's status and its owner's login. With cascade set
+// its teachers and students follow too: deactivating takes every active one, and
+// reactivating restores only those a cascaded deactivation took.
+func (s *businessService) ChangeBusinessStatus(ctx context.Context, businessID uint, status models.Status, cascade bool) error {
 	if businessID == 0 {
 		return errors.New("invalid business ID")
 	}
 
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
 	}
 
-	business, err := s.businessRepo.GetByID(businessID)
+	business, err := s.businessRepo.GetByID(ctx, businessID)
 	if err != nil {
 		return errors.New("business not found")
 	}
 
 	// Start transaction
-	tx := s.businessRepo.BeginTransaction()
+	tx := s.businessRepo.BeginTransaction(ctx)
 
 	// Update business status
-	if err := s.businessRepo.UpdateBusinessStatus(businessID, status); err != nil {
+	if err := s.businessRepo.BulkUpdateStatusWithTransaction(tx, []uint{businessID}, status); err != nil {
 		tx.Rollback()
 		return fmt.Errorf("error updating business status: %w", err)
 	}
 
 	// Update associated user status
-	if err := s.userRepo.UpdateUserStatus(business.UserID, status); err != nil {
+	if err := s.userRepo.BulkUpdateStatusInTransaction(tx, []uint{business.UserID}, status); err != nil {
 		tx.Rollback()
 		return fmt.Errorf("error updating user status: %w", err)
 	}
 
+	notify := []uint{business.UserID}
+	if cascade {
+		cascaded, err := s.businessRepo.CascadeStatusWithTransaction(tx, businessID, status)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error cascading status to teachers and students: %w", err)
+		}
+		notify = append(notify, cascaded...)
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
 	}
+	s.invalidateBusiness(ctx, business.Slug)
+	s.notifyStatusChanged(ctx, notify, status)
 
 	return nil
 }
 
-func (s *businessService) AssignPackage(businessID, packageID uint) error {
+// ChangeBusinessSlug sets an explicit slug, which must be free, or derives the slug from
+// the current name, numbered when taken. Every change is recorded in the slug history.
+func (s *businessService) ChangeBusinessSlug(ctx context.Context, businessID uint, req models.ChangeSlugRequest, actorID uint) (*models.ChangeSlugResponse, error) {
+	if businessID == 0 {
+		return nil, errors.New("invalid business ID")
+	}
+	if req.Regenerate == (req.Slug != "") {
+		return nil, errors.New("provide either a slug or regenerate, not both")
+	}
+
+	slug := req.Slug
+	if req.Regenerate {
+		slug = ""
+	} else if !slugPattern.MatchString(slug) {
+		return nil, errors.New("slug may only contain lowercase letters, digits and single hyphens between them")
+	}
+
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, errors.New("business not found")
+	}
+	oldSlug := business.Slug
+
+	if req.Regenerate {
+		if slug = generateSlugFromName(business.Name); slug == "" {
+			return nil, errors.New("business name has no characters a slug can be made of")
+		}
+		// A numbered variant of the name's slug is already as close as it gets
+		if oldSlug == slug || regexp.MustCompile(`^`+regexp.QuoteMeta(slug)+`-[0-9]+$`).MatchString(oldSlug) {
+			return s.slugResponse(ctx, business)
+		}
+	} else if slug == oldSlug {
+		return s.slugResponse(ctx, business)
+	}
+
+	tx := s.businessRepo.BeginTransaction(ctx)
+	if err := s.businessRepo.UpdateSlugWithTransaction(tx, business, slug, req.Regenerate, actorID); err != nil {
+		tx.Rollback()
+		if errors.Is(err, repository.ErrSlugUnavailable) {
+			return nil, errors.New("could not find a free slug for this business, choose another slug")
+		}
+		if conflict := conflictError(err); conflict != nil {
+			return nil, conflict
+		}
+		return nil, fmt.Errorf("error updating business slug: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	s.invalidateBusiness(ctx, oldSlug)
+	s.invalidateBusiness(ctx, business.Slug)
+
+	return s.slugResponse(ctx, business)
+}
+
+func (s *businessService) slugResponse(ctx context.Context, business *models.Business) (*models.ChangeSlugResponse, error) {
+	history, err := s.businessRepo.GetSlugHistory(ctx, business.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting slug history: %w", err)
+	}
+
+	businessResponse := s.toBusinessResponse(*business)
+	return &models.ChangeSlugResponse{Business: &businessResponse, History: history}, nil
+}
+
+func (s *businessService) AssignPackage(ctx context.Context, businessID, packageID uint) error {
 	if businessID == 0 || packageID == 0 {
 		return errors.New("invalid business ID or package ID")
 	}
 
 	// Check if business exists
-	_, err := s.businessRepo.GetByID(businessID)
+	business, err := s.businessRepo.GetByID(ctx, businessID)
 	if err != nil {
 		return errors.New("business not found")
 	}
 
 	// Check if package exists
-	_, err = s.packageRepo.GetByID(packageID)
+	pkg, err := s.packageRepo.GetByID(ctx, packageID)
 	if err != nil {
 		return errors.New("package not found")
 	}
 
-	if err := s.businessRepo.AssignPackage(businessID, packageID); err != nil {
+	if err := s.businessRepo.AssignPackage(ctx, businessID, packageID); err != nil {
 		return fmt.Errorf("error assigning package: %w", err)
 	}
+	s.invalidateBusiness(ctx, business.Slug)
+	s.notifyPackageChanged(ctx, []uint{business.UserID}, pkg)
 
 	return nil
 }
 
-func (s *businessService) RemovePackage(businessID uint) error {
+func (s *businessService) RemovePackage(ctx context.Context, businessID uint) error {
 	if businessID == 0 {
 		return errors.New("invalid business ID")
 	}
 
-	_, err := s.businessRepo.GetByID(businessID)
+	business, err := s.businessRepo.GetByID(ctx, businessID)
 	if err != nil {
 		return errors.New("business not found")
 	}
 
-	if err := s.businessRepo.RemovePackage(businessID); err != nil {
+	if err := s.businessRepo.RemovePackage(ctx, businessID); err != nil {
 		return fmt.Errorf("error removing package: %w", err)
 	}
+	s.invalidateBusiness(ctx, business.Slug)
+	s.notifyPackageChanged(ctx, []uint{business.UserID}, nil)
 
 	return nil
 }
 
-func (s *businessService) GetBusinessesByPackage(packageID uint) ([]models.BusinessResponse, error) {
-	if packageID == 0 {
-		return nil, errors.New("invalid package ID")
+func (s *businessService) BusinessEmailExists(ctx context.Context, email string, excludeBusinessID ...uint) (bool, error) {
+	if email == "" {
+		return false, errors.New("email cannot be empty")
 	}
 
-	businesses, err := s.businessRepo.GetBusinessesByPackage(packageID)
-	if err != nil {
-		return nil, fmt.Errorf("error fetching businesses by package: %w", err)
-	}
+	return s.businessRepo.BusinessEmailExists(ctx, email, excludeBusinessID...)
+}
 
-	var businessResponses []models.BusinessResponse
-	for _, business := range businesses {
-		businessResponses = append(businessResponses, s.toBusinessResponse(business))
+func (s *businessService) BusinessNameExists(ctx context.Context, name string, excludeBusinessID ...uint) (bool, error) {
+	if name == "" {
+		return false, errors.New("business name cannot be empty")
 	}
 
-	return businessResponses, nil
+	return s.businessRepo.BusinessNameExists(ctx, name, excludeBusinessID...)
 }
 
-func (s *businessService) GetBusinessesWithoutPackage() ([]models.BusinessResponse, error) {
-	businesses, err := s.businessRepo.GetBusinessesWithoutPackage()
+func (s *businessService) GetBusinessStats(ctx context.Context) (map[string]interface{}, error) {
+	stats, err := s.businessRepo.GetBusinessStats(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching businesses without package: %w", err)
-	}
-
-	var businessResponses []models.BusinessResponse
-	for _, business := range businesses {
-		businessResponses = append(businessResponses, s.toBusinessResponse(business))
+		return nil, fmt.Errorf("error getting business statistics: %w", err)
 	}
 
-	return businessResponses, nil
+	return stats, nil
 }
 
-func (s *businessService) BusinessEmailExists(email string, excludeBusinessID ...uint) (bool, error) {
-	if email == "" {
-		return false, errors.New("email cannot be empty")
+func (s *businessService) GetLocationStats(ctx context.Context) (map[string]int64, error) {
+	stats, err := s.businessRepo.GetLocationStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting location statistics: %w", err)
 	}
 
-	return s.businessRepo.BusinessEmailExists(email, excludeBusinessID...)
+	return stats, nil
 }
 
-func (s *businessService) BusinessNameExists(name string, excludeBusinessID ...uint) (bool, error) {
-	if name == "" {
-		return false, errors.New("business name cannot be empty")
+func (s *businessService) GetPackageDistribution(ctx context.Context) (map[string]int64, error) {
+	stats, err := s.businessRepo.GetPackageDistribution(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting package distribution: %w", err)
 	}
 
-	return s.businessRepo.BusinessNameExists(name, excludeBusinessID...)
+	return stats, nil
 }
 
-func (s *businessService) GetBusinessStats() (map[string]interface{}, error) {
-	stats, err := s.businessRepo.GetBusinessStats()
+// Default at-risk thresholds, in days
+const (
+	defaultAtRiskStudentDays = 60
+	defaultAtRiskLoginDays   = 30
+	defaultAtRiskExpiryDays  = 14
+)
+
+// GetAtRiskBusinesses lists the active businesses likely to churn: no student added,
+// owner not logged in, or package expiring within the thresholds, which default when
+// left at zero
+func (s *businessService) GetAtRiskBusinesses(ctx context.Context, filters repository.AtRiskFilters) ([]models.AtRiskBusiness, repository.PageInfo, error) {
+	applyPageDefaults(&filters.Page, &filters.Limit)
+	if filters.StudentDays <= 0 {
+		filters.StudentDays = defaultAtRiskStudentDays
+	}
+	if filters.LoginDays <= 0 {
+		filters.LoginDays = defaultAtRiskLoginDays
+	}
+	if filters.ExpiryDays <= 0 {
+		filters.ExpiryDays = defaultAtRiskExpiryDays
+	}
+
+	businesses, total, err := s.businessRepo.GetAtRiskBusinesses(ctx, filters, time.Now())
 	if err != nil {
-		return nil, fmt.Errorf("error getting business statistics: %w", err)
+		return nil, repository.PageInfo{}, fmt.Errorf("error getting at-risk businesses: %w", err)
 	}
 
-	return stats, nil
+	return businesses, filters.PageInfo(total), nil
 }
 
-func (s *businessService) GetLocationStats() (map[string]int64, error) {
-	stats, err := s.businessRepo.GetLocationStats()
+// maxTimeseriesPeriods caps how many periods one timeseries request may cover
+const maxTimeseriesPeriods = 260
+
+// GetGrowthTimeseries returns platform-wide growth per week or month. The range defaults
+// to the 12 periods up to today; from and to are widened to whole periods.
+func (s *businessService) GetGrowthTimeseries(ctx context.Context, query models.GrowthTimeseriesQuery) (*models.GrowthTimeseries, error) {
+	granularity := query.Granularity
+	if granularity == "" {
+		granularity = models.GranularityMonth
+	}
+	if granularity != models.GranularityWeek && granularity != models.GranularityMonth {
+		return nil, errors.New("granularity must be week or month")
+	}
+
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if query.To != "" {
+		date, err := parseDate(query.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date: %v", err)
+		}
+		to = date
+	}
+
+	start, end := periodBounds(granularity, to)
+	to = end
+	from := start
+	if granularity == models.GranularityWeek {
+		from = from.AddDate(0, 0, -7*11)
+	} else {
+		from = from.AddDate(0, -11, 0)
+	}
+	if query.From != "" {
+		date, err := parseDate(query.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date: %v", err)
+		}
+		from, _ = periodBounds(granularity, date)
+	}
+	if from.After(to) {
+		return nil, errors.New("from date must not be after to date")
+	}
+
+	periods := int(to.Sub(from).Hours()/24/7) + 1
+	if granularity == models.GranularityMonth {
+		periods = (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	}
+	if periods > maxTimeseriesPeriods {
+		return nil, fmt.Errorf("range covers %d periods; at most %d are allowed", periods, maxTimeseriesPeriods)
+	}
+
+	series, err := s.businessRepo.GetGrowthTimeseries(ctx, granularity, from, to)
 	if err != nil {
-		return nil, fmt.Errorf("error getting location statistics: %w", err)
+		return nil, fmt.Errorf("error getting growth timeseries: %w", err)
 	}
 
-	return stats, nil
+	series.Granularity = granularity
+	series.From = from.Format(models.DateFormat)
+	series.To = to.Format(models.DateFormat)
+	return series, nil
 }
 
-func (s *businessService) GetPackageDistribution() (map[string]int64, error) {
-	stats, err := s.businessRepo.GetPackageDistribution()
-	if err != nil {
-		return nil, fmt.Errorf("error getting package distribution: %w", err)
+// periodBounds returns the first and last day of the ISO week (starting Monday) or the
+// month that date falls in
+func periodBounds(granularity string, date time.Time) (time.Time, time.Time) {
+	if granularity == models.GranularityWeek {
+		offset := (int(date.Weekday()) + 6) % 7
+		start := date.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 6)
 	}
 
-	return stats, nil
+	start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, -1)
 }
 
-func (s *businessService) SearchBusinesses(searchTerm string, limit int) ([]models.BusinessResponse, error) {
+func (s *businessService) SearchBusinesses(ctx context.Context, searchTerm string, limit int) ([]models.BusinessResponse, error) {
 	if searchTerm == "" {
 		return []models.BusinessResponse{}, nil
 	}
 
-	businesses, err := s.businessRepo.SearchBusinesses(searchTerm, limit)
+	businesses, err := s.businessRepo.SearchBusinesses(ctx, searchTerm, limit)
 	if err != nil {
 		return nil, fmt.Errorf("error searching businesses: %w", err)
 	}
@@ -634,97 +893,181 @@ func (s *businessService) SearchBusinesses(searchTerm string, limit int) ([]mode
 	return businessResponses, nil
 }
 
-func (s *businessService) BulkUpdateBusinessStatus(businessIDs []uint, status int) error {
+// BulkUpdateBusinessStatus changes the status of the listed businesses and their owners'
+// logins, skipping IDs that don't exist unless atomic is set. With cascade set their
+// teachers and students follow, as in ChangeBusinessStatus.
+func (s *businessService) BulkUpdateBusinessStatus(ctx context.Context, businessIDs []uint, status models.Status, atomic, cascade bool) (*models.BulkUpdateResult, error) {
 	if len(businessIDs) == 0 {
-		return errors.New("no business IDs provided")
+		return nil, errors.New("no business IDs provided")
 	}
 
-	if status < 0 || status > 1 {
-		return errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
+	if !status.IsValid() {
+		return nil, errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
 	}
 
-	// Validate that all businesses exist and get their user IDs
-	var userIDs []uint
-	for _, businessID := range businessIDs {
-		business, err := s.businessRepo.GetByID(businessID)
-		if err != nil {
-			return fmt.Errorf("business with ID %d not found", businessID)
-		}
-		userIDs = append(userIDs, business.UserID)
+	result, owners, err := s.bulkBusinesses(ctx, businessIDs, atomic)
+	if result == nil || err != nil || len(owners) == 0 {
+		return result, err
 	}
 
-	// Start transaction
-	tx := s.businessRepo.BeginTransaction()
+	// The owners' logins follow the businesses' status
+	tx := s.businessRepo.BeginTransaction(ctx)
 
-	// Update business statuses
-	if err := s.businessRepo.BulkUpdateStatus(businessIDs, status); err != nil {
+	if err := s.businessRepo.BulkUpdateStatusWithTransaction(tx, result.Updated, status); err != nil {
 		tx.Rollback()
-		return fmt.Errorf("error updating business statuses: %w", err)
+		return nil, fmt.Errorf("error updating business statuses: %w", err)
 	}
 
-	// Update associated user statuses
-	if err := s.userRepo.BulkUpdateStatus(userIDs, status); err != nil {
+	if err := s.userRepo.BulkUpdateStatusInTransaction(tx, owners, status); err != nil {
 		tx.Rollback()
-		return fmt.Errorf("error updating user statuses: %w", err)
+		return nil, fmt.Errorf("error updating user statuses: %w", err)
+	}
+
+	notify := owners
+	if cascade {
+		for _, businessID := range result.Updated {
+			cascaded, err := s.businessRepo.CascadeStatusWithTransaction(tx, businessID, status)
+			if err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("error cascading status to teachers and students: %w", err)
+			}
+			notify = append(notify, cascaded...)
+		}
 	}
 
-	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("error committing transaction: %w", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
+	invalidateCache(ctx, s.cache, nil, businessSlugCachePrefix)
+	s.notifyStatusChanged(ctx, notify, status)
 
-	return nil
+	return result, nil
 }
 
-func (s *businessService) BulkAssignPackage(businessIDs []uint, packageID uint) error {
+// BulkAssignPackage assigns a package to the listed businesses, skipping IDs that don't
+// exist unless atomic is set
+func (s *businessService) BulkAssignPackage(ctx context.Context, businessIDs []uint, packageID uint, atomic bool) (*models.BulkUpdateResult, error) {
 	if len(businessIDs) == 0 {
-		return errors.New("no business IDs provided")
+		return nil, errors.New("no business IDs provided")
 	}
 
 	if packageID == 0 {
-		return errors.New("invalid package ID")
+		return nil, errors.New("invalid package ID")
 	}
 
 	// Check if package exists
-	_, err := s.packageRepo.GetByID(packageID)
+	pkg, err := s.packageRepo.GetByID(ctx, packageID)
 	if err != nil {
-		return errors.New("package not found")
+		return nil, errors.New("package not found")
 	}
 
-	// Validate that all businesses exist
-	for _, businessID := range businessIDs {
-		if _, err := s.businessRepo.GetByID(businessID); err != nil {
-			return fmt.Errorf("business with ID %d not found", businessID)
-		}
+	result, owners, err := s.bulkBusinesses(ctx, businessIDs, atomic)
+	if result == nil || err != nil || len(owners) == 0 {
+		return result, err
 	}
 
-	if err := s.businessRepo.BulkAssignPackage(businessIDs, packageID); err != nil {
-		return fmt.Errorf("error assigning package to businesses: %w", err)
+	if err := s.businessRepo.BulkAssignPackage(ctx, result.Updated, packageID); err != nil {
+		return nil, fmt.Errorf("error assigning package to businesses: %w", err)
 	}
+	invalidateCache(ctx, s.cache, nil, businessSlugCachePrefix)
+	s.notifyPackageChanged(ctx, owners, pkg)
 
-	return nil
+	return result, nil
+}
+
+// bulkBusinesses looks up the businesses of a bulk update in one query, returning which
+// IDs will be updated and their owners' user IDs. There are no owners when nothing is
+// left to update.
+func (s *businessService) bulkBusinesses(ctx context.Context, businessIDs []uint, atomic bool) (*models.BulkUpdateResult, []uint, error) {
+	businesses, err := s.businessRepo.GetByIDs(ctx, businessIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error fetching businesses: %w", err)
+	}
+
+	found := make(map[uint]bool, len(businesses))
+	owners := make([]uint, 0, len(businesses))
+	for _, business := range businesses {
+		found[business.ID] = true
+		owners = append(owners, business.UserID)
+	}
+
+	result := newBulkResult(businessIDs, found, "business")
+	if ok, err := checkAtomic(result, atomic); !ok {
+		return result, nil, err
+	}
+	return result, owners, nil
 }
 
-func (s *businessService) GetBusinessesByLocation(location string) ([]models.BusinessResponse, error) {
-	if location == "" {
-		return nil, errors.New("location cannot be empty")
+// assignPackage puts the business on pkg from now, recording the price and validation
+// period it was assigned on
+func assignPackage(business *models.Business, pkg models.Package, now time.Time) {
+	expiresAt := pkg.ExpiresAt(now)
+	price, period := pkg.Price, pkg.ValidationPeriod
+	business.PackageID = &pkg.ID
+	business.PackageExpiresAt = &expiresAt
+	business.PackagePrice = &price
+	business.PackageValidationPeriod = &period
+}
+
+// samePackage reports whether two optional package IDs name the same package
+func samePackage(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// BulkRemovePackage takes the package off the listed businesses and returns how many
+// had one
+func (s *businessService) BulkRemovePackage(ctx context.Context, businessIDs []uint, actorID uint) (int64, error) {
+	if len(businessIDs) == 0 {
+		return 0, errors.New("no business IDs provided")
 	}
 
-	businesses, err := s.businessRepo.GetBusinessesByLocation(location)
+	userIDs, err := s.businessRepo.BulkRemovePackage(ctx, businessIDs, actorID)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching businesses by location: %w", err)
+		return 0, fmt.Errorf("error removing package from businesses: %w", err)
+	}
+	if len(userIDs) > 0 {
+		invalidateCache(ctx, s.cache, nil, businessSlugCachePrefix)
+		s.notifyPackageChanged(ctx, userIDs, nil)
 	}
 
-	var businessResponses []models.BusinessResponse
-	for _, business := range businesses {
-		businessResponses = append(businessResponses, s.toBusinessResponse(business))
+	return int64(len(userIDs)), nil
+}
+
+// SwapPackage moves every business on one package to another, active, package and
+// returns how many moved
+func (s *businessService) SwapPackage(ctx context.Context, fromPackageID, toPackageID, actorID uint) (int64, error) {
+	if fromPackageID == 0 || toPackageID == 0 {
+		return 0, errors.New("invalid package ID")
+	}
+	if fromPackageID == toPackageID {
+		return 0, errors.New("packages must differ")
 	}
 
-	return businessResponses, nil
+	pkg, err := s.packageRepo.GetByID(ctx, toPackageID)
+	if err != nil {
+		return 0, errors.New("package not found")
+	}
+	if !pkg.Status.IsActive() {
+		return 0, errors.New("package is not active")
+	}
+
+	userIDs, err := s.businessRepo.SwapPackage(ctx, fromPackageID, toPackageID, actorID)
+	if err != nil {
+		return 0, fmt.Errorf("error swapping package: %w", err)
+	}
+	if len(userIDs) > 0 {
+		invalidateCache(ctx, s.cache, nil, businessSlugCachePrefix)
+		s.notifyPackageChanged(ctx, userIDs, pkg)
+	}
+
+	return int64(len(userIDs)), nil
 }
 
-func (s *businessService) GetBusinessLocations() ([]string, error) {
-	locations, err := s.businessRepo.GetBusinessLocations()
+func (s *businessService) GetBusinessLocations(ctx context.Context) ([]string, error) {
+	locations, err := s.businessRepo.GetBusinessLocations(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching business locations: %w", err)
 	}
@@ -732,35 +1075,209 @@ func (s *businessService) GetBusinessLocations() ([]string, error) {
 	return locations, nil
 }
 
+func (s *businessService) UploadBanner(ctx context.Context, businessID uint, file multipart.File, header *multipart.FileHeader) error {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return errors.New("business not found")
+	}
+
+	if _, err := businessBannerRules.Validate(file, header); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("businesses/%d/banner_%d_%s", business.ID, time.Now().UnixNano(), storage.SafeFileName(header.Filename))
+	if err := s.storage.Save(path, file); err != nil {
+		return fmt.Errorf("failed to store banner: %v", err)
+	}
+
+	previous := business.BannerPath
+	if err := s.businessRepo.UpdateFields(ctx, business.ID, map[string]interface{}{"banner_path": path}); err != nil {
+		// Don't leave orphaned files behind
+		s.removeBanner(ctx, path)
+		return fmt.Errorf("failed to save banner: %v", err)
+	}
+	s.invalidateBusiness(ctx, business.Slug)
+
+	if previous != "" {
+		s.removeBanner(ctx, previous)
+	}
+	return nil
+}
+
+func (s *businessService) DeleteBanner(ctx context.Context, businessID uint) error {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return errors.New("business not found")
+	}
+	if business.BannerPath == "" {
+		return errors.New("business has no banner")
+	}
+
+	previous := business.BannerPath
+	if err := s.businessRepo.UpdateFields(ctx, business.ID, map[string]interface{}{"banner_path": ""}); err != nil {
+		return fmt.Errorf("failed to remove banner: %v", err)
+	}
+	s.invalidateBusiness(ctx, business.Slug)
+
+	s.removeBanner(ctx, previous)
+	return nil
+}
+
+// GetBannerBySlug reads the banner of a business for its public page
+func (s *businessService) GetBannerBySlug(ctx context.Context, slug string) (*models.BusinessBanner, error) {
+	business, err := s.businessRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, errors.New("business not found")
+	}
+	if business.BannerPath == "" {
+		return nil, errors.New("business has no banner")
+	}
+
+	file, err := s.storage.Open(business.BannerPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open banner: %v", err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, MaxBusinessBannerSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read banner: %v", err)
+	}
+	if len(content) > MaxBusinessBannerSize {
+		return nil, errors.New("stored banner is too large")
+	}
+
+	return &models.BusinessBanner{
+		ContentType: http.DetectContentType(content),
+		Content:     content,
+	}, nil
+}
+
+func (s *businessService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
+
 // Helper methods
 
+// removeBanner deletes a stored banner; the business no longer points at it, so a
+// failure is only logged
+func (s *businessService) removeBanner(ctx context.Context, path string) {
+	if err := s.storage.Delete(path); err != nil {
+		logger.FromContext(ctx).Warn("failed to remove stored banner", "path", path, "error", err)
+	}
+}
+
+// normalizeThemeColor checks a theme color and returns it as lowercase #rrggbb. An
+// empty color is kept, resetting the theme to the frontend's default.
+func normalizeThemeColor(field, value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return "", nil
+	}
+	if !themeColorPattern.MatchString(value) {
+		return "", &FieldError{Field: field, Message: "must be a hex color such as #1a73e8"}
+	}
+	if len(value) == 4 {
+		value = string([]byte{'#', value[1], value[1], value[2], value[2], value[3], value[3]})
+	}
+	return value, nil
+}
+
+// businessTheme is the public page theme of a business. The banner URL is left to the
+// public endpoint, which knows the address the API is reached at.
+func businessTheme(business models.Business) *models.BusinessTheme {
+	return &models.BusinessTheme{
+		PrimaryColor: business.ThemePrimaryColor,
+		AccentColor:  business.ThemeAccentColor,
+		HasBanner:    business.BannerPath != "",
+	}
+}
+
+// invalidateBusiness drops the cached public read of a business after a committed write
+func (s *businessService) invalidateBusiness(ctx context.Context, slug string) {
+	invalidateCache(ctx, s.cache, []string{businessSlugCachePrefix + slug})
+}
+
+// notifyStatusChanged tells the business owners their account was activated or
+// deactivated. The change is already committed, so a failure is only logged.
+func (s *businessService) notifyStatusChanged(ctx context.Context, userIDs []uint, status models.Status) {
+	title, body := "Your account has been deactivated", "Contact support if you think this is a mistake."
+	if status.IsActive() {
+		title, body = "Your account has been activated", "You can now use all the features of your package."
+	}
+
+	err := s.notifications.Notify(ctx, models.NewNotification{
+		UserIDs: userIDs,
+		Type:    models.NotificationStatusChanged,
+		Title:   title,
+		Body:    body,
+		Payload: models.JSONB{"status": status},
+	})
+	if err != nil {
+		logger.FromContext(ctx).Warn("Failed to notify status change", "error", err)
+	}
+}
+
+// notifyPackageChanged tells the business owners a package was assigned to them, or
+// removed when pkg is nil
+func (s *businessService) notifyPackageChanged(ctx context.Context, userIDs []uint, pkg *models.Package) {
+	notification := models.NewNotification{
+		UserIDs: userIDs,
+		Type:    models.NotificationPackageChanged,
+		Title:   "Your package has been removed",
+		Body:    "Contact support to choose a new package.",
+		Payload: models.JSONB{"package_id": nil},
+	}
+	if pkg != nil {
+		notification.Title = fmt.Sprintf("You are now on the %s package", pkg.Name)
+		notification.Body = fmt.Sprintf("It is valid for %s from today.", pluralDays(pkg.ValidationPeriod))
+		notification.Payload = models.JSONB{"package_id": pkg.ID}
+	}
+
+	if err := s.notifications.Notify(ctx, notification); err != nil {
+		logger.FromContext(ctx).Warn("Failed to notify package change", "error", err)
+	}
+}
+
 func (s *businessService) toBusinessResponse(business models.Business) models.BusinessResponse {
 	return models.BusinessResponse{
-		ID:        business.ID,
-		Name:      business.Name,
-		UserID:    business.UserID,
-		OwnerName: business.OwnerName,
-		PackageID: business.PackageID,
-		Email:     business.Email,
-		Phone:     business.Phone,
-		Location:  business.Location,
-		Status:    business.Status,
-		CreatedOn: business.CreatedOn,
+		ID:               business.ID,
+		Name:             business.Name,
+		Slug:             business.Slug,
+		UserID:           business.UserID,
+		OwnerName:        business.OwnerName,
+		PackageID:        business.PackageID,
+		PackageExpiresAt: business.PackageExpiresAt,
+		Email:            business.Email,
+		Phone:            business.Phone,
+		Location:         business.Location,
+		Status:           business.Status,
+		Version:          business.Version,
+		IsOpen:           business.IsOpen,
+		Timezone:         business.Timezone,
+		CreatedOn:        business.CreatedOn,
+		UpdatedOn:        business.UpdatedOn,
 	}
 }
 
 func (s *businessService) toBusinessResponseWithRelations(business models.Business) models.BusinessResponse {
 	response := models.BusinessResponse{
-		ID:        business.ID,
-		Name:      business.Name,
-		UserID:    business.UserID,
-		OwnerName: business.OwnerName,
-		PackageID: business.PackageID,
-		Email:     business.Email,
-		Phone:     business.Phone,
-		Location:  business.Location,
-		Status:    business.Status,
-		CreatedOn: business.CreatedOn,
+		ID:               business.ID,
+		Name:             business.Name,
+		Slug:             business.Slug,
+		UserID:           business.UserID,
+		OwnerName:        business.OwnerName,
+		PackageID:        business.PackageID,
+		PackageExpiresAt: business.PackageExpiresAt,
+		Email:            business.Email,
+		Phone:            business.Phone,
+		Location:         business.Location,
+		Status:           business.Status,
+		Version:          business.Version,
+		IsOpen:           business.IsOpen,
+		Timezone:         business.Timezone,
+		CreatedOn:        business.CreatedOn,
+		UpdatedOn:        business.UpdatedOn,
 	}
 
 	// Add user relation if loaded
@@ -787,6 +1304,7 @@ func (s *businessService) toBusinessResponseWithRelations(business models.Busine
 			Description:      business.Package.Description,
 			Status:           business.Package.Status,
 			CreatedOn:        business.Package.CreatedOn,
+			UpdatedOn:        business.Package.UpdatedOn,
 		}
 		response.Package = &packageResponse
 	}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/testutil"
+	"backend/pkg/cache"
+
+	"gorm.io/gorm"
+)
+
+func newBusinessService(db *gorm.DB) (BusinessService, *recordingNotifications) {
+	notifications := &recordingNotifications{}
+	service := NewBusinessService(repository.NewBusinessRepository(db), repository.NewUserRepository(db),
+		repository.NewPackageRepository(db), cache.Noop{}, nil, notifications, nil, nil)
+	return service, notifications
+}
+
+// recordingNotifications keeps who was notified instead of writing feeds
+type recordingNotifications struct {
+	NotificationService
+	notified []models.NewNotification
+}
+
+func (n *recordingNotifications) Notify(ctx context.Context, notification models.NewNotification) error {
+	n.notified = append(n.notified, notification)
+	return nil
+}
+
+// Editing some of a business's owner details writes only those columns of the owner
+func TestUpdateBusinessLeavesOtherOwnerColumns(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	ctx := context.Background()
+	service, _ := newBusinessService(db)
+	sunrise := f.Businesses["Sunrise Academy"]
+
+	var before models.User
+	if err := db.First(&before, sunrise.UserID).Error; err != nil {
+		t.Fatalf("failed to read owner: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		updates map[string]interface{}
+		check   func(t *testing.T, owner models.User)
+	}{
+		{"owner name", map[string]interface{}{"owner_name": "Ravi Kumar"}, func(t *testing.T, owner models.User) {
+			if owner.Name != "Ravi Kumar" {
+				t.Errorf("Name = %q, want %q", owner.Name, "Ravi Kumar")
+			}
+		}},
+		{"phone", map[string]interface{}{"phone": "9876543210"}, func(t *testing.T, owner models.User) {
+			if owner.Phone == before.Phone {
+				t.Errorf("Phone = %q, want it changed", owner.Phone)
+			}
+		}},
+		{"business columns only", map[string]interface{}{"location": "Pune"}, func(t *testing.T, owner models.User) {
+			if owner.Name != "Ravi Kumar" {
+				t.Errorf("Name = %q, want the earlier update kept", owner.Name)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.UpdateBusiness(ctx, sunrise.ID, tt.updates); err != nil {
+				t.Fatalf("UpdateBusiness() error = %v", err)
+			}
+
+			var owner models.User
+			if err := db.First(&owner, sunrise.UserID).Error; err != nil {
+				t.Fatalf("failed to read owner: %v", err)
+			}
+			if owner.Role != models.RoleBusiness || owner.Email != before.Email ||
+				owner.Status != models.StatusActive || owner.Password != before.Password {
+				t.Errorf("owner = %s %s %v, want %s %s %v with the password kept",
+					owner.Role, owner.Email, owner.Status, before.Role, before.Email, before.Status)
+			}
+			tt.check(t, owner)
+		})
+	}
+}
+
+func TestDeleteBusiness(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	ctx := context.Background()
+	service, _ := newBusinessService(db)
+	sunrise, moonlight := f.Businesses["Sunrise Academy"], f.Businesses["Moonlight Tutors"]
+
+	count := func(model interface{}, where string, args ...interface{}) int64 {
+		t.Helper()
+		var n int64
+		if err := db.Unscoped().Model(model).Where(where, args...).Count(&n).Error; err != nil {
+			t.Fatalf("failed to count %T: %v", model, err)
+		}
+		return n
+	}
+
+	t.Run("refused while teachers and students remain", func(t *testing.T) {
+		err := service.DeleteBusiness(ctx, sunrise.ID, models.DeleteBusinessOptions{})
+		var dependents *models.BusinessDependentsError
+		if !errors.As(err, &dependents) {
+			t.Fatalf("DeleteBusiness() error = %v, want a BusinessDependentsError", err)
+		}
+		// The soft-deleted student still points at the business
+		want := map[string]int64{"teachers": 3, "students": 5, "attendance": 0, "fee_plans": 0, "fee_payments": 0}
+		if !reflect.DeepEqual(dependents.Counts, want) {
+			t.Errorf("Counts = %v, want %v", dependents.Counts, want)
+		}
+		if n := count(&models.Business{}, "id = ?", sunrise.ID); n != 1 {
+			t.Errorf("business rows = %d, want it kept", n)
+		}
+	})
+
+	t.Run("business without records", func(t *testing.T) {
+		dawn := testutil.CreateBusiness(t, db, "Dawn Classes", models.StatusActive, nil)
+		if err := service.DeleteBusiness(ctx, dawn.ID, models.DeleteBusinessOptions{}); err != nil {
+			t.Fatalf("DeleteBusiness() error = %v", err)
+		}
+		if n := count(&models.Business{}, "id = ?", dawn.ID); n != 0 {
+			t.Errorf("business rows = %d, want 0", n)
+		}
+		if n := count(&models.User{}, "id = ?", dawn.UserID); n != 0 {
+			t.Errorf("owner rows = %d, want 0", n)
+		}
+	})
+
+	t.Run("cascade removes the records and their accounts", func(t *testing.T) {
+		var userIDs []uint
+		userIDs = append(userIDs, sunrise.UserID)
+		for _, teacher := range f.Teachers {
+			if teacher.BusinessID == sunrise.ID {
+				userIDs = append(userIDs, teacher.UserID)
+			}
+		}
+		for _, student := range f.Students {
+			if student.BusinessID == sunrise.ID {
+				userIDs = append(userIDs, student.UserID)
+			}
+		}
+
+		if err := service.DeleteBusiness(ctx, sunrise.ID, models.DeleteBusinessOptions{Cascade: true}); err != nil {
+			t.Fatalf("DeleteBusiness() error = %v", err)
+		}
+		if n := count(&models.Business{}, "id = ?", sunrise.ID); n != 0 {
+			t.Errorf("business rows = %d, want 0", n)
+		}
+		if n := count(&models.Teacher{}, "business_id = ?", sunrise.ID); n != 0 {
+			t.Errorf("teacher rows = %d, want 0", n)
+		}
+		if n := count(&models.Student{}, "business_id = ?", sunrise.ID); n != 0 {
+			t.Errorf("student rows = %d, want 0", n)
+		}
+		if n := count(&models.User{}, "id IN ?", userIDs); n != 0 {
+			t.Errorf("user rows = %d, want the %d accounts removed", n, len(userIDs))
+		}
+
+		// The other business is untouched
+		if n := count(&models.Teacher{}, "business_id = ?", moonlight.ID); n != 1 {
+			t.Errorf("Moonlight teacher rows = %d, want 1", n)
+		}
+		if n := count(&models.Student{}, "business_id = ?", moonlight.ID); n != 1 {
+			t.Errorf("Moonlight student rows = %d, want 1", n)
+		}
+	})
+}
+
+func TestChangeBusinessStatusCascade(t *testing.T) {
+	const on, off = models.StatusActive, models.StatusInactive
+
+	tests := []struct {
+		name     string
+		cascade  bool
+		teachers map[string]models.Status
+		students map[string]models.Status
+		notified []string // besides the owner
+	}{
+		{
+			name:     "without cascade",
+			teachers: map[string]models.Status{"Asha": on, "Bilal": on, "Chitra": off},
+			students: map[string]models.Status{"Aarav": on, "Bina": on, "Chen": off, "Diya": on},
+		},
+		{
+			name:     "with cascade",
+			cascade:  true,
+			teachers: map[string]models.Status{"Asha": off, "Bilal": off, "Chitra": off},
+			students: map[string]models.Status{"Aarav": off, "Bina": off, "Chen": off, "Diya": off},
+			notified: []string{"Asha", "Bilal", "Aarav", "Bina", "Diya"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testutil.DB(t)
+			f := testutil.Seed(t, db)
+			ctx := context.Background()
+			service, notifications := newBusinessService(db)
+			sunrise := f.Businesses["Sunrise Academy"]
+
+			if err := service.ChangeBusinessStatus(ctx, sunrise.ID, off, tt.cascade); err != nil {
+				t.Fatalf("ChangeBusinessStatus() error = %v", err)
+			}
+
+			var business models.Business
+			var owner models.User
+			if err := db.First(&business, sunrise.ID).Error; err != nil {
+				t.Fatalf("failed to read business: %v", err)
+			}
+			if err := db.First(&owner, sunrise.UserID).Error; err != nil {
+				t.Fatalf("failed to read owner: %v", err)
+			}
+			if business.Status != off || owner.Status != off {
+				t.Errorf("business status = %v, owner status = %v, want both inactive", business.Status, owner.Status)
+			}
+
+			for name, want := range tt.teachers {
+				var teacher models.Teacher
+				if err := db.First(&teacher, f.Teachers[name].ID).Error; err != nil {
+					t.Fatalf("failed to read %s: %v", name, err)
+				}
+				if teacher.Status != want {
+					t.Errorf("%s status = %v, want %v", name, teacher.Status, want)
+				}
+			}
+			for name, want := range tt.students {
+				var student models.Student
+				if err := db.First(&student, f.Students[name].ID).Error; err != nil {
+					t.Fatalf("failed to read %s: %v", name, err)
+				}
+				if student.Status != want {
+					t.Errorf("%s status = %v, want %v", name, student.Status, want)
+				}
+			}
+
+			want := map[uint]bool{sunrise.UserID: true}
+			for _, name := range tt.notified {
+				if teacher, ok := f.Teachers[name]; ok {
+					want[teacher.UserID] = true
+				} else {
+					want[f.Students[name].UserID] = true
+				}
+			}
+			if len(notifications.notified) != 1 {
+				t.Fatalf("got %d notifications, want 1", len(notifications.notified))
+			}
+			got := map[uint]bool{}
+			for _, id := range notifications.notified[0].UserIDs {
+				got[id] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("notified users = %v, want %v", got, want)
+			}
+		})
+	}
+}
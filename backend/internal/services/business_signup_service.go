@@ -0,0 +1,245 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/logger"
+	"backend/pkg/mailer"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSignupNotFound is returned for a sign-up that doesn't exist
+var ErrSignupNotFound = errors.New("sign-up not found")
+
+// ErrSignupReviewed is returned for approving or rejecting a sign-up that was already
+// approved or rejected
+var ErrSignupReviewed = errors.New("sign-up has already been reviewed")
+
+// BusinessSignupConfig sets what the sign-up emails are signed with
+type BusinessSignupConfig struct {
+	AppName string
+}
+
+type BusinessSignupService interface {
+	// Submit records a sign-up for the admins to review and tells them about it
+	Submit(ctx context.Context, req models.BusinessSignupRequest) (*models.BusinessSignupReceived, error)
+	GetSignups(ctx context.Context, filters repository.BusinessSignupFilters) ([]models.PendingSignup, int64, error)
+	GetSignup(ctx context.Context, id uint) (*models.PendingSignup, error)
+	// Approve creates the business and its owner's account like an admin creating it,
+	// which emails the owner a link to set their password
+	Approve(ctx context.Context, id uint, req models.ApproveBusinessSignupRequest, reviewerID uint) (*models.ApprovedBusinessSignup, error)
+	// Reject closes the sign-up and emails the owner the reason
+	Reject(ctx context.Context, id uint, req models.RejectBusinessSignupRequest, reviewerID uint) (*models.PendingSignup, error)
+}
+
+type businessSignupService struct {
+	signupRepo    repository.BusinessSignupRepository
+	userRepo      repository.UserRepository
+	businesses    BusinessService
+	notifications NotificationService
+	mailer        mailer.Mailer
+	config        BusinessSignupConfig
+}
+
+func NewBusinessSignupService(signupRepo repository.BusinessSignupRepository, userRepo repository.UserRepository, businesses BusinessService, notifications NotificationService, m mailer.Mailer, config BusinessSignupConfig) BusinessSignupService {
+	return &businessSignupService{
+		signupRepo:    signupRepo,
+		userRepo:      userRepo,
+		businesses:    businesses,
+		notifications: notifications,
+		mailer:        m,
+		config:        config,
+	}
+}
+
+func (s *businessSignupService) Submit(ctx context.Context, req models.BusinessSignupRequest) (*models.BusinessSignupReceived, error) {
+	phone, err := normalizePhoneField("phone", req.Phone)
+	if err != nil {
+		return nil, err
+	}
+	timezone, err := normalizeTimezone(req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	signup := &models.PendingSignup{
+		Name:      strings.TrimSpace(req.Name),
+		Slug:      generateSlugFromName(req.Slug),
+		OwnerName: strings.TrimSpace(req.OwnerName),
+		Email:     strings.TrimSpace(req.Email),
+		Phone:     phone,
+		Location:  strings.TrimSpace(req.Location),
+		Timezone:  timezone,
+		Message:   strings.TrimSpace(req.Message),
+		Status:    models.SignupStatusPending,
+	}
+
+	tx := s.signupRepo.BeginTransaction(ctx)
+
+	// The email becomes the owner's login on approval, so it must be free now. Checking
+	// users and businesses locks the address, which keeps a concurrent sign-up with the
+	// same address waiting until this one is saved.
+	if err := checkEmailAvailability(tx, s.userRepo, signup.Email, 0); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	pending, err := s.signupRepo.PendingEmailExistsInTransaction(tx, signup.Email)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error checking pending sign-ups: %w", err)
+	}
+	if pending {
+		tx.Rollback()
+		return nil, &EmailTakenError{Table: "pending_signups"}
+	}
+
+	if err := s.signupRepo.CreateInTransaction(tx, signup); err != nil {
+		tx.Rollback()
+		if conflict := conflictError(err); conflict != nil {
+			return nil, conflict
+		}
+		return nil, fmt.Errorf("error saving sign-up: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	s.notifyAdmins(ctx, *signup)
+
+	return &models.BusinessSignupReceived{ID: signup.ID, Status: signup.Status}, nil
+}
+
+func (s *businessSignupService) GetSignups(ctx context.Context, filters repository.BusinessSignupFilters) ([]models.PendingSignup, int64, error) {
+	applyPageDefaults(&filters.Page, &filters.Limit)
+
+	signups, total, err := s.signupRepo.GetSignups(ctx, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching sign-ups: %w", err)
+	}
+	return signups, total, nil
+}
+
+func (s *businessSignupService) GetSignup(ctx context.Context, id uint) (*models.PendingSignup, error) {
+	signup, err := s.signupRepo.GetByID(ctx, id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrSignupNotFound
+		}
+		return nil, fmt.Errorf("error fetching sign-up: %w", err)
+	}
+	return signup, nil
+}
+
+func (s *businessSignupService) Approve(ctx context.Context, id uint, req models.ApproveBusinessSignupRequest, reviewerID uint) (*models.ApprovedBusinessSignup, error) {
+	signup, err := s.GetSignup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if signup.Status != models.SignupStatusPending {
+		return nil, ErrSignupReviewed
+	}
+
+	// A second approval of the same sign-up fails here, as the email is taken by then
+	business, err := s.businesses.CreateBusiness(ctx, models.CreateBusinessRequest{
+		Name:       signup.Name,
+		Slug:       signup.Slug,
+		OwnerName:  signup.OwnerName,
+		Email:      signup.Email,
+		Phone:      signup.Phone,
+		Location:   signup.Location,
+		PackageID:  req.PackageID,
+		Timezone:   signup.Timezone,
+		Bootstrap:  req.Bootstrap,
+		SampleData: req.SampleData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The business exists either way; a sign-up left pending is one an admin can still
+	// reject, while the owner already has their account
+	reviewed, err := s.signupRepo.Review(ctx, id, reviewerID, map[string]interface{}{
+		"status":      models.SignupStatusApproved,
+		"business_id": business.ID,
+	})
+	if err != nil || !reviewed {
+		logger.FromContext(ctx).Error("Error marking sign-up approved", "signup_id", id, "business_id", business.ID, "error", err)
+	}
+
+	if signup, err = s.GetSignup(ctx, id); err != nil {
+		return nil, err
+	}
+	return &models.ApprovedBusinessSignup{Signup: *signup, Business: *business}, nil
+}
+
+func (s *businessSignupService) Reject(ctx context.Context, id uint, req models.RejectBusinessSignupRequest, reviewerID uint) (*models.PendingSignup, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, &FieldError{Field: "reason", Message: "is required"}
+	}
+
+	reviewed, err := s.signupRepo.Review(ctx, id, reviewerID, map[string]interface{}{
+		"status":        models.SignupStatusRejected,
+		"reject_reason": reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error rejecting sign-up: %w", err)
+	}
+
+	signup, err := s.GetSignup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !reviewed {
+		return nil, ErrSignupReviewed
+	}
+
+	// The sign-up is rejected either way
+	if err := s.sendRejection(ctx, *signup); err != nil {
+		logger.FromContext(ctx).Error("Error sending sign-up rejection email", "signup_id", id, "error", err)
+	}
+	return signup, nil
+}
+
+// notifyAdmins tells every active admin about a new sign-up. It is already saved, so a
+// failure is only logged.
+func (s *businessSignupService) notifyAdmins(ctx context.Context, signup models.PendingSignup) {
+	admins, err := s.userRepo.GetUsersByRoleAndStatus(ctx, models.RoleAdmin, models.StatusActive)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Failed to load admins to notify of sign-up", "signup_id", signup.ID, "error", err)
+		return
+	}
+
+	userIDs := make([]uint, 0, len(admins))
+	for _, admin := range admins {
+		userIDs = append(userIDs, admin.ID)
+	}
+
+	err = s.notifications.Notify(ctx, models.NewNotification{
+		UserIDs: userIDs,
+		Type:    models.NotificationBusinessSignup,
+		Title:   fmt.Sprintf("%s signed up", signup.Name),
+		Body:    fmt.Sprintf("%s (%s) is waiting for approval.", signup.OwnerName, signup.Email),
+		Payload: models.JSONB{"signup_id": signup.ID},
+	})
+	if err != nil {
+		logger.FromContext(ctx).Warn("Failed to notify sign-up", "signup_id", signup.ID, "error", err)
+	}
+}
+
+func (s *businessSignupService) sendRejection(ctx context.Context, signup models.PendingSignup) error {
+	msg, err := mailer.Render("business_signup_rejected", signup.Email, map[string]interface{}{
+		"AppName":      s.config.AppName,
+		"Name":         signup.OwnerName,
+		"BusinessName": signup.Name,
+		"Reason":       signup.RejectReason,
+	})
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, msg)
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// maxTrendDays caps how many days one trends request may cover
+const maxTrendDays = 366
+
+// SnapshotConfig controls the snapshot job
+type SnapshotConfig struct {
+	BatchSize int // businesses counted and saved per query, 500 by default
+}
+
+type BusinessSnapshotService interface {
+	// Snapshot saves every business's current counts of active teachers and students as
+	// its snapshot for the day now falls on in its time zone. Running it again the same
+	// day replaces the counts, so it can be run as often as needed, such as to catch up
+	// after the scheduler was down.
+	Snapshot(ctx context.Context, now time.Time) (*models.SnapshotRunResult, error)
+	// RunScheduler snapshots every interval until ctx is done. Snapshots are saved per
+	// business and day, so every instance can run it.
+	RunScheduler(ctx context.Context, interval time.Duration)
+
+	// GetTrends returns a business's daily counts over a range of days, filling the days
+	// without a snapshot from the one before
+	GetTrends(ctx context.Context, businessID uint, query models.BusinessTrendsQuery) (*models.BusinessTrends, error)
+
+	// Access control
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+	GetBusinessIDByUser(ctx context.Context, userID uint) (uint, error)
+}
+
+type businessSnapshotService struct {
+	snapshotRepo repository.BusinessSnapshotRepository
+	businessRepo repository.BusinessRepository
+	config       SnapshotConfig
+}
+
+func NewBusinessSnapshotService(snapshotRepo repository.BusinessSnapshotRepository, businessRepo repository.BusinessRepository, config SnapshotConfig) BusinessSnapshotService {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 500
+	}
+	return &businessSnapshotService{
+		snapshotRepo: snapshotRepo,
+		businessRepo: businessRepo,
+		config:       config,
+	}
+}
+
+func (s *businessSnapshotService) Snapshot(ctx context.Context, now time.Time) (*models.SnapshotRunResult, error) {
+	result := &models.SnapshotRunResult{RanAt: now}
+
+	var afterID uint
+	for {
+		counts, err := s.snapshotRepo.GetCounts(ctx, afterID, s.config.BatchSize)
+		if err != nil {
+			return result, fmt.Errorf("error counting teachers and students: %w", err)
+		}
+		if len(counts) == 0 {
+			return result, nil
+		}
+
+		snapshots := make([]models.BusinessSnapshot, len(counts))
+		for i, count := range counts {
+			snapshots[i] = models.BusinessSnapshot{
+				BusinessID:     count.BusinessID,
+				Date:           dateIn(now, timezoneLocation(count.Timezone)),
+				ActiveTeachers: count.ActiveTeachers,
+				ActiveStudents: count.ActiveStudents,
+			}
+		}
+		if err := s.snapshotRepo.Upsert(ctx, snapshots); err != nil {
+			return result, fmt.Errorf("error saving snapshots: %w", err)
+		}
+
+		result.Businesses += len(snapshots)
+		afterID = counts[len(counts)-1].BusinessID
+	}
+}
+
+func (s *businessSnapshotService) RunScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Snapshot(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			slog.Error("Failed to snapshot business counts", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetTrends returns one point per day. The range defaults to the 30 days up to today
+// in the business's time zone.
+func (s *businessSnapshotService) GetTrends(ctx context.Context, businessID uint, query models.BusinessTrendsQuery) (*models.BusinessTrends, error) {
+	timezone, err := s.businessRepo.GetTimezone(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	to := todayIn(timezone)
+	if query.To != "" {
+		if to, err = parseDate(query.To); err != nil {
+			return nil, fmt.Errorf("invalid to date: %v", err)
+		}
+	}
+	from := to.AddDate(0, 0, -29)
+	if query.From != "" {
+		if from, err = parseDate(query.From); err != nil {
+			return nil, fmt.Errorf("invalid from date: %v", err)
+		}
+	}
+	if from.After(to) {
+		return nil, errors.New("from date must not be after to date")
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days > maxTrendDays {
+		return nil, fmt.Errorf("range covers %d days; at most %d are allowed", days, maxTrendDays)
+	}
+
+	snapshots, err := s.snapshotRepo.GetSnapshots(ctx, businessID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching snapshots: %w", err)
+	}
+	// The days at the start of the range carry the snapshot before it
+	last, err := s.snapshotRepo.GetLatestBefore(ctx, businessID, from)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching snapshots: %w", err)
+	}
+
+	byDate := make(map[string]models.BusinessSnapshot, len(snapshots))
+	for _, snapshot := range snapshots {
+		byDate[snapshot.Date.Format(models.DateFormat)] = snapshot
+	}
+
+	points := make([]models.TrendPoint, 0, days)
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day := date.Format(models.DateFormat)
+		point := models.TrendPoint{Date: day}
+		if snapshot, ok := byDate[day]; ok {
+			last = &snapshot
+		} else {
+			point.Filled = last != nil
+		}
+		if last != nil {
+			teachers, students := last.ActiveTeachers, last.ActiveStudents
+			point.ActiveTeachers = &teachers
+			point.ActiveStudents = &students
+		}
+		points = append(points, point)
+	}
+
+	return &models.BusinessTrends{
+		BusinessID: businessID,
+		From:       from.Format(models.DateFormat),
+		To:         to.Format(models.DateFormat),
+		Points:     points,
+	}, nil
+}
+
+func (s *businessSnapshotService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
+
+func (s *businessSnapshotService) GetBusinessIDByUser(ctx context.Context, userID uint) (uint, error) {
+	return ownBusinessID(ctx, s.businessRepo, userID)
+}
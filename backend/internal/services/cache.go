@@ -0,0 +1,59 @@
+package services
+
+import (
+	"backend/pkg/cache"
+	"backend/pkg/logger"
+	"context"
+	"encoding/json"
+)
+
+// Cache keys of the hot public reads. Business responses embed their package, so package
+// writes drop every cached business too.
+const (
+	activePackagesCacheKey  = "packages:active"
+	businessSlugCachePrefix = "business:slug:"
+)
+
+// readThrough returns the cached value of key, or loads it and caches it. Cache errors
+// are logged and fall back to load, so an unreachable cache only costs performance.
+func readThrough[T any](ctx context.Context, c cache.Cache, key string, load func() (T, error)) (T, error) {
+	log := logger.FromContext(ctx)
+
+	if raw, ok, err := c.Get(ctx, key); err != nil {
+		log.Warn("Cache read failed", "key", key, "error", err)
+	} else if ok {
+		var value T
+		if err := json.Unmarshal(raw, &value); err == nil {
+			return value, nil
+		}
+		log.Warn("Dropping undecodable cache entry", "key", key)
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	if raw, err := json.Marshal(value); err == nil {
+		if err := c.Set(ctx, key, raw); err != nil {
+			log.Warn("Cache write failed", "key", key, "error", err)
+		}
+	}
+	return value, nil
+}
+
+// invalidateCache deletes keys and every key under prefixes. Writes call it only after
+// their transaction committed; invalidating earlier would let a concurrent read cache the
+// old row again before the commit.
+func invalidateCache(ctx context.Context, c cache.Cache, keys []string, prefixes ...string) {
+	log := logger.FromContext(ctx)
+
+	if err := c.Delete(ctx, keys...); err != nil {
+		log.Error("Cache invalidation failed", "keys", keys, "error", err)
+	}
+	for _, prefix := range prefixes {
+		if err := c.DeletePrefix(ctx, prefix); err != nil {
+			log.Error("Cache invalidation failed", "prefix", prefix, "error", err)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/ical"
+	"backend/pkg/utils"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// calendarWeekdays are the iCalendar names of time.Weekday values
+var calendarWeekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ErrCalendarTokenInvalid is returned for unknown and revoked calendar tokens alike
+var ErrCalendarTokenInvalid = fmt.Errorf("calendar link is invalid or has been revoked")
+
+// CalendarConfig bounds how far ahead the calendar feed looks
+type CalendarConfig struct {
+	DefaultDays int // used when the feed URL doesn't ask for a horizon
+	MaxDays     int
+}
+
+type CalendarService interface {
+	// IssueToken turns the business's calendar feed on, replacing any earlier token
+	IssueToken(ctx context.Context, userID uint) (string, error)
+	// RevokeToken turns the feed off; the current token stops working at once
+	RevokeToken(ctx context.Context, userID uint) error
+	// GetFeed returns the iCalendar document of the business the token was issued for,
+	// covering the next days (0 for the default)
+	GetFeed(ctx context.Context, token string, days int) ([]byte, error)
+}
+
+type calendarService struct {
+	businessRepo     repository.BusinessRepository
+	batchRepo        repository.BatchRepository
+	examRepo         repository.ExamRepository
+	availabilityRepo repository.TeacherAvailabilityRepository
+	config           CalendarConfig
+}
+
+func NewCalendarService(businessRepo repository.BusinessRepository, batchRepo repository.BatchRepository, examRepo repository.ExamRepository, availabilityRepo repository.TeacherAvailabilityRepository, config CalendarConfig) CalendarService {
+	if config.MaxDays <= 0 {
+		config.MaxDays = 365
+	}
+	if config.DefaultDays <= 0 || config.DefaultDays > config.MaxDays {
+		config.DefaultDays = min(60, config.MaxDays)
+	}
+	return &calendarService{
+		businessRepo:     businessRepo,
+		batchRepo:        batchRepo,
+		examRepo:         examRepo,
+		availabilityRepo: availabilityRepo,
+		config:           config,
+	}
+}
+
+func (s *calendarService) IssueToken(ctx context.Context, userID uint) (string, error) {
+	business, err := s.businessRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("business not found")
+	}
+
+	tokenID, err := generateTokenID()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %v", err)
+	}
+	token, err := utils.GenerateCalendarToken(business.ID, tokenID)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %v", err)
+	}
+
+	if err := s.businessRepo.UpdateFields(ctx, business.ID, map[string]interface{}{"calendar_token_id": tokenID}); err != nil {
+		return "", fmt.Errorf("failed to save calendar token: %v", err)
+	}
+	return token, nil
+}
+
+func (s *calendarService) RevokeToken(ctx context.Context, userID uint) error {
+	business, err := s.businessRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("business not found")
+	}
+	if business.CalendarTokenID == "" {
+		return fmt.Errorf("calendar feed is not enabled")
+	}
+
+	if err := s.businessRepo.UpdateFields(ctx, business.ID, map[string]interface{}{"calendar_token_id": ""}); err != nil {
+		return fmt.Errorf("failed to revoke calendar token: %v", err)
+	}
+	return nil
+}
+
+// GetFeed checks the token's signature, then that it is still the business's current
+// token, before building the calendar from that business only
+func (s *calendarService) GetFeed(ctx context.Context, token string, days int) ([]byte, error) {
+	claims, err := utils.ValidateCalendarToken(token)
+	if err != nil || claims.ID == "" {
+		return nil, ErrCalendarTokenInvalid
+	}
+
+	business, err := s.businessRepo.GetByID(ctx, claims.BusinessID)
+	if err != nil || !business.Status.IsActive() || business.CalendarTokenID != claims.ID {
+		return nil, ErrCalendarTokenInvalid
+	}
+
+	if days <= 0 {
+		days = s.config.DefaultDays
+	}
+	if days > s.config.MaxDays {
+		return nil, fmt.Errorf("days cannot be more than %d", s.config.MaxDays)
+	}
+
+	// Today where the centre is through the last day of the horizon
+	from := todayIn(business.Timezone)
+	until := from.AddDate(0, 0, days)
+
+	batches, err := s.batchRepo.GetByBusinessID(ctx, business.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batches: %v", err)
+	}
+	exams, _, err := s.examRepo.GetAll(ctx, repository.ExamFilters{
+		BusinessID: &business.ID,
+		From:       from.Format(models.DateFormat),
+		To:         until.AddDate(0, 0, -1).Format(models.DateFormat),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exams: %v", err)
+	}
+	availabilities, err := s.availabilityRepo.GetByBusinessID(ctx, business.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teacher availability: %v", err)
+	}
+
+	calendar := ical.Calendar{
+		ProdID: "-//Coaching Management//Business Calendar//EN",
+		Name:   business.Name,
+	}
+	calendar.Events = append(calendar.Events, batchEvents(business.ID, batches, from, until)...)
+	calendar.Events = append(calendar.Events, examEvents(business.ID, exams)...)
+	calendar.Events = append(calendar.Events, availabilityEvents(business.ID, availabilities, batches, from, until)...)
+	sort.SliceStable(calendar.Events, func(i, j int) bool {
+		return calendar.Events[i].Start.Before(calendar.Events[j].Start)
+	})
+
+	return calendar.Bytes(), nil
+}
+
+// calendarUID gives an event a UID that stays the same across refreshes
+func calendarUID(businessID uint, kind string, id uint) string {
+	return fmt.Sprintf("%s-%d.business-%d@coaching-management", kind, id, businessID)
+}
+
+// batchEvents marks the days batches start and end within [from, until)
+func batchEvents(businessID uint, batches []models.Batch, from, until time.Time) []ical.Event {
+	var events []ical.Event
+	inRange := func(date *time.Time) bool {
+		return date != nil && !date.Before(from) && date.Before(until)
+	}
+
+	for _, batch := range batches {
+		if inRange(batch.StartDate) {
+			events = append(events, ical.Event{
+				UID:     calendarUID(businessID, "batch-start", batch.ID),
+				Summary: fmt.Sprintf("Batch %s starts", batch.Name),
+				Start:   *batch.StartDate,
+				End:     batch.StartDate.AddDate(0, 0, 1),
+				AllDay:  true,
+			})
+		}
+		if inRange(batch.EndDate) {
+			events = append(events, ical.Event{
+				UID:     calendarUID(businessID, "batch-end", batch.ID),
+				Summary: fmt.Sprintf("Batch %s ends", batch.Name),
+				Start:   *batch.EndDate,
+				End:     batch.EndDate.AddDate(0, 0, 1),
+				AllDay:  true,
+			})
+		}
+	}
+	return events
+}
+
+// examEvents puts exams on their day; exams have a date but no time
+func examEvents(businessID uint, exams []models.Exam) []ical.Event {
+	events := make([]ical.Event, 0, len(exams))
+	for _, exam := range exams {
+		var details []string
+		if exam.Batch != nil {
+			details = append(details, "Batch: "+exam.Batch.Name)
+		}
+		if exam.Subject != nil {
+			details = append(details, "Subject: "+exam.Subject.Name)
+		}
+		details = append(details, "Max marks: "+formatMarks(exam.MaxMarks))
+
+		events = append(events, ical.Event{
+			UID:         calendarUID(businessID, "exam", exam.ID),
+			Summary:     "Exam: " + exam.Name,
+			Description: strings.Join(details, "\n"),
+			Start:       exam.Date,
+			End:         exam.Date.AddDate(0, 0, 1),
+			AllDay:      true,
+		})
+	}
+	return events
+}
+
+// availabilityEvents repeats each teacher's weekly slots until the end of the horizon,
+// listing the batches the teacher is assigned to. Slots have no time zone, so they are
+// floating times.
+func availabilityEvents(businessID uint, availabilities []models.TeacherAvailability, batches []models.Batch, from, until time.Time) []ical.Event {
+	batchNames := make(map[uint][]string)
+	for _, batch := range batches {
+		if batch.TeacherID != nil {
+			batchNames[*batch.TeacherID] = append(batchNames[*batch.TeacherID], batch.Name)
+		}
+	}
+
+	var events []ical.Event
+	for _, availability := range availabilities {
+		// First occurrence on or after from
+		day := from.AddDate(0, 0, (availability.Weekday-int(from.Weekday())+7)%7)
+		if !day.Before(until) {
+			continue
+		}
+		start, err := clockTime(day, availability.StartTime)
+		if err != nil {
+			continue
+		}
+		end, err := clockTime(day, availability.EndTime)
+		if err != nil {
+			continue
+		}
+
+		description := "No batches assigned"
+		if names := batchNames[availability.TeacherID]; len(names) > 0 {
+			description = "Batches: " + strings.Join(names, ", ")
+		}
+
+		events = append(events, ical.Event{
+			UID:         calendarUID(businessID, "availability", availability.ID),
+			Summary:     fmt.Sprintf("%s available", availability.Teacher.Name),
+			Description: description,
+			Start:       start,
+			End:         end,
+			Floating:    true,
+			RRule:       fmt.Sprintf("FREQ=WEEKLY;BYDAY=%s;UNTIL=%s", calendarWeekdays[availability.Weekday], ical.FormatFloating(until.Add(-time.Second))),
+		})
+	}
+	return events
+}
+
+// clockTime returns the "HH:MM" time on the given day
+func clockTime(day time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return day.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute), nil
+}
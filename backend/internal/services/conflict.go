@@ -0,0 +1,94 @@
+package services
+
+import (
+	"backend/internal/repository"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// uniqueConflicts maps the unique indexes to the errors the pre-insert checks return,
+// so a request that loses a race gets the same response as one that was caught early
+var uniqueConflicts = map[string]string{
+	"idx_business_slug":    "business slug already exists",
+	"idx_business_user_id": "user already has a business",
+	"idx_teacher_user_id":  "user is already a teacher",
+	"idx_student_user_id":  "user is already a student",
+}
+
+// conflictError returns the "already exists" error for a unique violation, or nil if
+// err isn't one
+func conflictError(err error) error {
+	constraint, ok := repository.UniqueViolation(err)
+	if !ok {
+		return nil
+	}
+	if table, ok := emailIndexes[constraint]; ok {
+		return &EmailTakenError{Table: table}
+	}
+	if message, ok := uniqueConflicts[constraint]; ok {
+		return errors.New(message)
+	}
+	return errors.New("record already exists")
+}
+
+// emailIndexes maps the unique email indexes to the table each covers
+var emailIndexes = map[string]string{
+	"idx_users_email":          "users",
+	"idx_business_email":       "business",
+	"idx_pending_signup_email": "pending_signups",
+}
+
+// EmailTakenError is returned when an email address is already used by another user or
+// business, or by a sign-up awaiting approval. Table is where it was found, "users",
+// "business" or "pending_signups".
+type EmailTakenError struct {
+	Table string
+}
+
+func (e *EmailTakenError) Error() string {
+	switch e.Table {
+	case "business":
+		return "business email already exists"
+	case "pending_signups":
+		return "a sign-up with this email is already awaiting approval"
+	}
+	return "user with this email already exists"
+}
+
+// checkEmailAvailability fails with an EmailTakenError when another user or business uses
+// email, ignoring case. It must run in the transaction that saves the address, which
+// keeps the address locked until it ends. ownerID is the user whose own account and
+// business don't count, zero for a new account.
+func checkEmailAvailability(tx *gorm.DB, users repository.UserRepository, email string, ownerID uint) error {
+	table, err := users.EmailInUseInTransaction(tx, email, ownerID)
+	if err != nil {
+		return fmt.Errorf("error checking email availability: %w", err)
+	}
+	if table != "" {
+		return &EmailTakenError{Table: table}
+	}
+	return nil
+}
+
+// VersionConflictError is returned when an update was based on an older version of a
+// record than the stored one. Current is the record as it is now, so the client can
+// merge and retry.
+type VersionConflictError struct {
+	Current interface{}
+}
+
+func (e *VersionConflictError) Error() string {
+	return repository.ErrVersionConflict.Error()
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return repository.ErrVersionConflict
+}
+
+// expectedVersion returns the version an update was based on, if the caller sent one
+func expectedVersion(updates map[string]interface{}) (uint, bool) {
+	version, ok := updates["version"].(uint)
+	return version, ok
+}
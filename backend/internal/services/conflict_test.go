@@ -0,0 +1,58 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// A request that loses the race to a unique index gets the error the pre-insert check
+// would have returned
+func TestConflictError(t *testing.T) {
+	violation := func(constraint string) error {
+		return fmt.Errorf("error creating record: %w", &pgconn.PgError{Code: "23505", ConstraintName: constraint})
+	}
+
+	tests := []struct {
+		name  string
+		err   error
+		want  string
+		table string
+	}{
+		{"user email", violation("idx_users_email"), "user with this email already exists", "users"},
+		{"business email", violation("idx_business_email"), "business email already exists", "business"},
+		{"pending sign-up email", violation("idx_pending_signup_email"), "a sign-up with this email is already awaiting approval", "pending_signups"},
+		{"business slug", violation("idx_business_slug"), "business slug already exists", ""},
+		{"teacher user", violation("idx_teacher_user_id"), "user is already a teacher", ""},
+		{"unknown index", violation("idx_something_else"), "record already exists", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflict := conflictError(tt.err)
+			if conflict == nil {
+				t.Fatal("conflictError = nil, want a conflict")
+			}
+			if conflict.Error() != tt.want {
+				t.Errorf("conflictError = %q, want %q", conflict.Error(), tt.want)
+			}
+			var taken *EmailTakenError
+			if errors.As(conflict, &taken) != (tt.table != "") {
+				t.Fatalf("conflictError = %T, want an EmailTakenError only for email indexes", conflict)
+			}
+			if taken != nil && taken.Table != tt.table {
+				t.Errorf("Table = %q, want %q", taken.Table, tt.table)
+			}
+		})
+	}
+
+	for _, err := range []error{
+		errors.New("connection refused"),
+		&pgconn.PgError{Code: "23503", ConstraintName: "fk_business_user"},
+	} {
+		if conflict := conflictError(err); conflict != nil {
+			t.Errorf("conflictError(%v) = %v, want nil", err, conflict)
+		}
+	}
+}
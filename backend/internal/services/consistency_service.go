@@ -0,0 +1,289 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// consistencySampleSize is how many IDs of the affected rows a check lists per class
+const consistencySampleSize = 10
+
+// consistencyClass describes a class of inconsistency and its repair
+type consistencyClass struct {
+	entity      string
+	description string
+	repair      string
+}
+
+var consistencyClasses = map[string]consistencyClass{
+	models.ConsistencyBusinessWithoutUser: {
+		entity:      "business",
+		description: "Active businesses whose owner user no longer exists",
+		repair:      "Relinked to the business-role user with the business's email who has no business, otherwise deactivated",
+	},
+	models.ConsistencyOwnerWithoutBusiness: {
+		entity:      "users",
+		description: "Active users with the business role who have no business",
+		repair:      "Deactivated",
+	},
+	models.ConsistencyTeacherWithoutBusiness: {
+		entity:      "teacher",
+		description: "Active teachers whose business no longer exists",
+		repair:      "Deactivated together with their login",
+	},
+	models.ConsistencyTeacherWithoutUser: {
+		entity:      "teacher",
+		description: "Active teachers whose user no longer exists",
+		repair:      "Deactivated",
+	},
+	models.ConsistencyStudentWithoutBusiness: {
+		entity:      "student",
+		description: "Active students whose business no longer exists",
+		repair:      "Deactivated together with their login",
+	},
+	models.ConsistencyStudentWithoutUser: {
+		entity:      "student",
+		description: "Active students whose user no longer exists",
+		repair:      "Deactivated",
+	},
+}
+
+// ConsistencyService finds and repairs rows of users, businesses, teachers and students
+// that point at rows which don't exist, or lack the rows that should point at them
+type ConsistencyService interface {
+	Check(ctx context.Context) (*models.ConsistencyReport, error)
+	// Repair fixes the selected classes in one transaction, which is rolled back after
+	// reporting the changes when req is a dry run
+	Repair(ctx context.Context, req models.ConsistencyRepairRequest, actorID uint) (*models.ConsistencyRepairResult, error)
+}
+
+type consistencyService struct {
+	consistencyRepo repository.ConsistencyRepository
+	businessRepo    repository.BusinessRepository
+	teacherRepo     repository.TeacherRepository
+	studentRepo     repository.StudentRepository
+	userRepo        repository.UserRepository
+}
+
+func NewConsistencyService(consistencyRepo repository.ConsistencyRepository, businessRepo repository.BusinessRepository, teacherRepo repository.TeacherRepository, studentRepo repository.StudentRepository, userRepo repository.UserRepository) ConsistencyService {
+	return &consistencyService{
+		consistencyRepo: consistencyRepo,
+		businessRepo:    businessRepo,
+		teacherRepo:     teacherRepo,
+		studentRepo:     studentRepo,
+		userRepo:        userRepo,
+	}
+}
+
+func (s *consistencyService) Check(ctx context.Context) (*models.ConsistencyReport, error) {
+	report := &models.ConsistencyReport{
+		CheckedAt: time.Now(),
+		Issues:    make([]models.ConsistencyIssue, 0, len(models.ConsistencyClasses)),
+	}
+
+	for _, class := range models.ConsistencyClasses {
+		count, err := s.consistencyRepo.Count(ctx, class)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s: %v", class, err)
+		}
+
+		sample := []uint{}
+		if count > 0 {
+			if sample, err = s.consistencyRepo.FindIDs(ctx, class, consistencySampleSize); err != nil {
+				return nil, fmt.Errorf("failed to check %s: %v", class, err)
+			}
+		}
+
+		info := consistencyClasses[class]
+		report.Issues = append(report.Issues, models.ConsistencyIssue{
+			Class:       class,
+			Description: info.description,
+			Entity:      info.entity,
+			Count:       count,
+			SampleIDs:   sample,
+			Repair:      info.repair,
+		})
+		report.Total += count
+	}
+
+	return report, nil
+}
+
+func (s *consistencyService) Repair(ctx context.Context, req models.ConsistencyRepairRequest, actorID uint) (*models.ConsistencyRepairResult, error) {
+	selected := make(map[string]bool, len(req.Classes))
+	for _, class := range req.Classes {
+		if _, ok := consistencyClasses[class]; !ok {
+			return nil, fmt.Errorf("unknown consistency class %q", class)
+		}
+		selected[class] = true
+	}
+
+	result := &models.ConsistencyRepairResult{
+		DryRun:   req.DryRun == nil || *req.DryRun,
+		Repaired: make(map[string]int, len(selected)),
+		Changes:  []models.ConsistencyChange{},
+	}
+
+	// A dry run makes the same changes and rolls them back, so later classes see the
+	// effect of earlier ones just as they would for real
+	tx := s.consistencyRepo.BeginTransaction(ctx)
+
+	for _, class := range models.ConsistencyClasses {
+		if !selected[class] {
+			continue
+		}
+
+		changes, err := s.repairClass(ctx, tx, class, actorID)
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to repair %s: %v", class, err)
+		}
+		result.Repaired[class] = len(changes)
+		result.Changes = append(result.Changes, changes...)
+	}
+
+	if result.DryRun {
+		tx.Rollback()
+		return result, nil
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit repair: %v", err)
+	}
+	return result, nil
+}
+
+func (s *consistencyService) repairClass(ctx context.Context, tx *gorm.DB, class string, actorID uint) ([]models.ConsistencyChange, error) {
+	ids, err := s.consistencyRepo.FindIDsWithTransaction(tx, class)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	entity := consistencyClasses[class].entity
+	var changes []models.ConsistencyChange
+
+	if class == models.ConsistencyBusinessWithoutUser {
+		relinks, err := s.consistencyRepo.FindBusinessRelinksWithTransaction(tx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		relinked := make(map[uint]bool, len(relinks))
+		for _, relink := range relinks {
+			if err := s.consistencyRepo.RelinkBusinessWithTransaction(tx, relink.BusinessID, relink.UserID); err != nil {
+				return nil, err
+			}
+			relinked[relink.BusinessID] = true
+			changes = append(changes, models.ConsistencyChange{
+				Class:  class,
+				Entity: entity,
+				ID:     relink.BusinessID,
+				Action: models.ConsistencyRepairRelinked,
+				Detail: fmt.Sprintf("user_id %d -> %d", relink.OldUserID, relink.UserID),
+			})
+		}
+
+		remaining := ids[:0]
+		for _, id := range ids {
+			if !relinked[id] {
+				remaining = append(remaining, id)
+			}
+		}
+		ids = remaining
+		if len(ids) == 0 {
+			return changes, nil
+		}
+	}
+
+	detail, err := s.deactivate(ctx, tx, class, ids, actorID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		changes = append(changes, models.ConsistencyChange{
+			Class:  class,
+			Entity: entity,
+			ID:     id,
+			Action: models.ConsistencyRepairDeactivated,
+			Detail: detail[id],
+		})
+	}
+	return changes, nil
+}
+
+// deactivate sets the rows of a class inactive the way the status endpoints do. Teachers
+// and students without a business take their logins with them. It returns a note per row
+// on what else changed.
+func (s *consistencyService) deactivate(ctx context.Context, tx *gorm.DB, class string, ids []uint, actorID uint) (map[uint]string, error) {
+	detail := make(map[uint]string)
+
+	switch class {
+	case models.ConsistencyBusinessWithoutUser:
+		return detail, s.businessRepo.BulkUpdateStatusWithTransaction(tx, ids, 0)
+
+	case models.ConsistencyOwnerWithoutBusiness:
+		return detail, s.userRepo.BulkUpdateStatusInTransaction(tx, ids, 0)
+
+	case models.ConsistencyTeacherWithoutBusiness, models.ConsistencyTeacherWithoutUser:
+		if err := s.teacherRepo.BulkUpdateStatusWithTransaction(tx, ids, 0); err != nil {
+			return nil, err
+		}
+		if class == models.ConsistencyTeacherWithoutUser {
+			return detail, nil
+		}
+
+		teachers, err := s.teacherRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		userIDs := make([]uint, 0, len(teachers))
+		for _, teacher := range teachers {
+			userIDs = append(userIDs, teacher.UserID)
+			detail[teacher.ID] = fmt.Sprintf("user %d deactivated", teacher.UserID)
+		}
+		if len(userIDs) > 0 {
+			return detail, s.userRepo.BulkUpdateStatusInTransaction(tx, userIDs, 0)
+		}
+		return detail, nil
+
+	case models.ConsistencyStudentWithoutBusiness, models.ConsistencyStudentWithoutUser:
+		if err := s.studentRepo.BulkUpdateStatusWithTransaction(tx, ids, 0); err != nil {
+			return nil, err
+		}
+
+		history := make([]models.StudentHistory, 0, len(ids))
+		for _, id := range ids {
+			history = append(history, statusChangeHistory(id, 1, 0, actorID))
+		}
+		if err := s.studentRepo.CreateHistoryWithTransaction(tx, history); err != nil {
+			return nil, err
+		}
+		if class == models.ConsistencyStudentWithoutUser {
+			return detail, nil
+		}
+
+		students, err := s.studentRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		userIDs := make([]uint, 0, len(students))
+		for _, student := range students {
+			userIDs = append(userIDs, student.UserID)
+			detail[student.ID] = fmt.Sprintf("user %d deactivated", student.UserID)
+		}
+		if len(userIDs) > 0 {
+			return detail, s.userRepo.BulkUpdateStatusInTransaction(tx, userIDs, 0)
+		}
+		return detail, nil
+	}
+
+	return nil, fmt.Errorf("unknown consistency class %q", class)
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+// dropForeignKeys lets the test leave the orphans the schema otherwise prevents, as
+// older databases have them. The change is undone with the test's transaction.
+func dropForeignKeys(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	err := db.Exec(`DO $$
+	DECLARE c record;
+	BEGIN
+		FOR c IN SELECT conrelid::regclass AS tbl, conname FROM pg_constraint
+			WHERE contype = 'f' AND conrelid IN ('business'::regclass, 'teacher'::regclass, 'student'::regclass)
+		LOOP
+			EXECUTE format('ALTER TABLE %s DROP CONSTRAINT %I', c.tbl, c.conname);
+		END LOOP;
+	END $$`).Error
+	if err != nil {
+		t.Fatalf("failed to drop foreign keys: %v", err)
+	}
+}
+
+// deleteUser removes a user row outright, leaving whatever pointed at it behind
+func deleteUser(t *testing.T, db *gorm.DB, userID uint) {
+	t.Helper()
+	if err := db.Exec("DELETE FROM users WHERE id = ?", userID).Error; err != nil {
+		t.Fatalf("failed to delete user %d: %v", userID, err)
+	}
+}
+
+// missingBusinessID is a business ID no fixture has
+const missingBusinessID = 999999
+
+// Each detector finds the one inconsistency seeded for it and nothing else, and data
+// without inconsistencies reports none
+func TestConsistencyCheck(t *testing.T) {
+	tests := []struct {
+		class string
+		// seed leaves one inconsistency of the class behind and returns the affected row's ID
+		seed func(t *testing.T, db *gorm.DB, f *testutil.Fixtures) uint
+	}{
+		{"", nil},
+		{models.ConsistencyBusinessWithoutUser, func(t *testing.T, db *gorm.DB, f *testutil.Fixtures) uint {
+			business := testutil.CreateBusiness(t, db, "Orphaned Academy", models.StatusActive, nil)
+			deleteUser(t, db, business.UserID)
+			return business.ID
+		}},
+		{models.ConsistencyOwnerWithoutBusiness, func(t *testing.T, db *gorm.DB, f *testutil.Fixtures) uint {
+			return testutil.CreateUser(t, db, "Lone Owner", models.RoleBusiness).ID
+		}},
+		{models.ConsistencyTeacherWithoutBusiness, func(t *testing.T, db *gorm.DB, f *testutil.Fixtures) uint {
+			return testutil.CreateTeacher(t, db, "Stray Teacher", missingBusinessID, 10000, models.StatusActive).ID
+		}},
+		{models.ConsistencyTeacherWithoutUser, func(t *testing.T, db *gorm.DB, f *testutil.Fixtures) uint {
+			deleteUser(t, db, f.Teachers["Asha"].UserID)
+			return f.Teachers["Asha"].ID
+		}},
+		{models.ConsistencyStudentWithoutBusiness, func(t *testing.T, db *gorm.DB, f *testutil.Fixtures) uint {
+			return testutil.CreateStudent(t, db, "Stray Student", missingBusinessID, "8", "male", models.StatusActive).ID
+		}},
+		{models.ConsistencyStudentWithoutUser, func(t *testing.T, db *gorm.DB, f *testutil.Fixtures) uint {
+			deleteUser(t, db, f.Students["Aarav"].UserID)
+			return f.Students["Aarav"].ID
+		}},
+	}
+	for _, tt := range tests {
+		name := tt.class
+		if name == "" {
+			name = "clean"
+		}
+		t.Run(name, func(t *testing.T) {
+			db := testutil.DB(t)
+			f := testutil.Seed(t, db)
+			var want []uint
+			if tt.seed != nil {
+				dropForeignKeys(t, db)
+				want = []uint{tt.seed(t, db, f)}
+			}
+
+			service := NewConsistencyService(repository.NewConsistencyRepository(db), repository.NewBusinessRepository(db),
+				repository.NewTeacherRepository(db), repository.NewStudentRepository(db), repository.NewUserRepository(db))
+			report, err := service.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+
+			if report.Total != int64(len(want)) {
+				t.Errorf("Total = %d, want %d", report.Total, len(want))
+			}
+			if len(report.Issues) != len(models.ConsistencyClasses) {
+				t.Errorf("reported %d classes, want all %d", len(report.Issues), len(models.ConsistencyClasses))
+			}
+			for _, issue := range report.Issues {
+				if issue.Class != tt.class {
+					if issue.Count != 0 || len(issue.SampleIDs) != 0 {
+						t.Errorf("%s: count %d, samples %v, want none", issue.Class, issue.Count, issue.SampleIDs)
+					}
+					continue
+				}
+				if issue.Count != 1 || !slices.Equal(issue.SampleIDs, want) {
+					t.Errorf("%s: count %d, samples %v, want %v", issue.Class, issue.Count, issue.SampleIDs, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"backend/pkg/utils"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// validateEmail checks that value is a bare address such as "name@example.com",
+// rejecting display names and domains without a dot
+func validateEmail(value string) error {
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Address != value {
+		return fmt.Errorf("invalid email format")
+	}
+	at := strings.LastIndex(value, "@")
+	domain := value[at+1:]
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return fmt.Errorf("invalid email format")
+	}
+	return nil
+}
+
+// FieldError is a validation error about one field of a request
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// normalizePhone formats a phone number the way every phone is stored, in E.164 form
+// with the default country code added to numbers written without one
+func normalizePhone(value string) (string, error) {
+	return utils.NormalizePhone(value)
+}
+
+// normalizePhoneField normalizes the phone in a request field, reporting an invalid
+// number as a FieldError. An empty value stays empty.
+func normalizePhoneField(field, value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	phone, err := normalizePhone(value)
+	if err != nil {
+		return "", &FieldError{Field: field, Message: err.Error()}
+	}
+	return phone, nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exportBatchSize is how many rows a CSV export reads from the database at a time
+const exportBatchSize = 500
+
+// csvColumn is one column of a CSV export
+type csvColumn[T any] struct {
+	Name  string
+	Value func(T) string
+}
+
+// resolveCSVColumns returns the named columns in the requested order, or every column when
+// none were named. Unknown names are reported as a SelectionError listing the valid ones.
+func resolveCSVColumns[T any](names []string, available []csvColumn[T]) ([]csvColumn[T], error) {
+	if len(names) == 0 {
+		return available, nil
+	}
+
+	byName := make(map[string]csvColumn[T], len(available))
+	valid := make([]string, len(available))
+	for i, column := range available {
+		byName[column.Name] = column
+		valid[i] = column.Name
+	}
+
+	columns := make([]csvColumn[T], 0, len(names))
+	for _, name := range names {
+		column, ok := byName[name]
+		if !ok {
+			return nil, &repository.SelectionError{Param: "column", Unknown: name, Valid: valid}
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// parseCSVColumns splits the comma-separated columns query parameter
+func parseCSVColumns(columns string) []string {
+	var names []string
+	for _, name := range strings.Split(columns, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// csvExport writes rows to a CSV stream, flushing after every batch so the client starts
+// receiving data before the export finishes
+type csvExport[T any] struct {
+	writer  *csv.Writer
+	columns []csvColumn[T]
+	record  []string
+	rows    int64 // data rows written, not counting the header
+}
+
+func newCSVExport[T any](w io.Writer, columns []csvColumn[T]) (*csvExport[T], error) {
+	export := &csvExport[T]{writer: csv.NewWriter(w), columns: columns, record: make([]string, len(columns))}
+	for i, column := range columns {
+		export.record[i] = column.Name
+	}
+	if err := export.writer.Write(export.record); err != nil {
+		return nil, err
+	}
+	// Send the header even when no rows match
+	export.writer.Flush()
+	if err := export.writer.Error(); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (e *csvExport[T]) writeBatch(rows []T) error {
+	for _, row := range rows {
+		for i, column := range e.columns {
+			e.record[i] = csvSafe(column.Value(row))
+		}
+		if err := e.writer.Write(e.record); err != nil {
+			return err
+		}
+		e.rows++
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// csvSafe keeps spreadsheet applications from evaluating a text cell as a formula
+func csvSafe(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + value
+}
+
+func formatExportDate(value *time.Time, layout string) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format(layout)
+}
+
+func formatExportStatus(status models.Status) string {
+	if status.IsActive() {
+		return models.StatusNames[models.StatusActive]
+	}
+	return models.StatusNames[models.StatusInactive]
+}
+
+func csvColumnNames[T any](columns []csvColumn[T]) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	return names
+}
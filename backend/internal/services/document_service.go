@@ -0,0 +1,466 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/logger"
+	"backend/pkg/pdf"
+	"backend/pkg/storage"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxBusinessLogoSize is the largest accepted business logo upload
+const MaxBusinessLogoSize = 2 << 20 // 2 MB
+
+var businessLogoRules = storage.UploadRules{
+	MaxSize:      MaxBusinessLogoSize,
+	AllowedTypes: []string{"image/jpeg", "image/png"},
+}
+
+// DocumentService prints report cards and fee receipts carrying the business's branding
+type DocumentService interface {
+	GetReportCard(ctx context.Context, studentID uint, filters repository.ExamFilters) (*models.PrintedDocument, error)
+	GetReportCardLayout(ctx context.Context, studentID uint, filters repository.ExamFilters) (*pdf.Layout, error)
+	GetPaymentReceipt(ctx context.Context, paymentID uint) (*models.PrintedDocument, error)
+	GetPaymentReceiptLayout(ctx context.Context, paymentID uint) (*pdf.Layout, error)
+	UploadLogo(ctx context.Context, businessID uint, file multipart.File, header *multipart.FileHeader) error
+	DeleteLogo(ctx context.Context, businessID uint) error
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+	CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error
+	CheckPaymentAccess(ctx context.Context, paymentID, userID uint, role string) error
+}
+
+type documentService struct {
+	businessRepo   repository.BusinessRepository
+	studentRepo    repository.StudentRepository
+	batchRepo      repository.BatchRepository
+	feeRepo        repository.FeeRepository
+	attendanceRepo repository.StudentAttendanceRepository
+	examService    ExamService
+	storage        storage.Storage
+}
+
+func NewDocumentService(businessRepo repository.BusinessRepository, studentRepo repository.StudentRepository, batchRepo repository.BatchRepository, feeRepo repository.FeeRepository, attendanceRepo repository.StudentAttendanceRepository, examService ExamService, store storage.Storage) DocumentService {
+	return &documentService{
+		businessRepo:   businessRepo,
+		studentRepo:    studentRepo,
+		batchRepo:      batchRepo,
+		feeRepo:        feeRepo,
+		attendanceRepo: attendanceRepo,
+		examService:    examService,
+		storage:        store,
+	}
+}
+
+func (s *documentService) GetReportCard(ctx context.Context, studentID uint, filters repository.ExamFilters) (*models.PrintedDocument, error) {
+	layout, err := s.GetReportCardLayout(ctx, studentID, filters)
+	if err != nil {
+		return nil, err
+	}
+	return renderDocument(*layout, fmt.Sprintf("report-card-%d.pdf", studentID))
+}
+
+// GetReportCardLayout assembles a student's exam results, subject averages and attendance
+// over the filtered period
+func (s *documentService) GetReportCardLayout(ctx context.Context, studentID uint, filters repository.ExamFilters) (*pdf.Layout, error) {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("student not found")
+	}
+
+	report, err := s.examService.GetStudentReport(ctx, student.ID, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.attendanceRepo.GetStatusCounts(ctx, repository.StudentAttendanceFilters{
+		StudentID: &student.ID,
+		From:      filters.From,
+		To:        filters.To,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance summary: %v", err)
+	}
+	var present, absent, leave int64
+	for _, count := range counts {
+		addAttendanceCount(&present, &absent, &leave, count.Status, count.Count)
+	}
+	totalMarked, attendanceRate := studentAttendanceTotals(present, absent, leave)
+
+	layout, err := s.brandedLayout(ctx, student.BusinessID, "Report Card")
+	if err != nil {
+		return nil, err
+	}
+	layout.Header.TitleInfo = []string{
+		"Period: " + documentPeriod(filters.From, filters.To),
+		"Issued: " + businessToday(ctx, s.businessRepo, student.BusinessID).Format(models.DateFormat),
+	}
+
+	layout.Sections = append(layout.Sections, pdf.Section{
+		Heading: "Student",
+		Fields:  s.studentFields(ctx, *student),
+	})
+
+	layout.Sections = append(layout.Sections, pdf.Section{
+		Heading: "Attendance",
+		Fields: []pdf.Field{
+			{Label: "Days marked", Value: strconv.FormatInt(totalMarked, 10)},
+			{Label: "Attendance rate", Value: formatPercentage(attendanceRate)},
+			{Label: "Present", Value: strconv.FormatInt(present, 10)},
+			{Label: "Absent", Value: strconv.FormatInt(absent, 10)},
+			{Label: "Leave", Value: strconv.FormatInt(leave, 10)},
+		},
+	})
+
+	results := pdf.Section{Heading: "Exam Results"}
+	if report.ExamCount == 0 {
+		results.Note = "No exam results were recorded in this period."
+	} else {
+		table := &pdf.Table{
+			Columns: []pdf.Column{
+				{Title: "Date", Width: 1.3},
+				{Title: "Exam", Width: 3},
+				{Title: "Subject", Width: 2},
+				{Title: "Marks", Width: 1.4, Right: true},
+				{Title: "Percentage", Width: 1.3, Right: true},
+				{Title: "Result", Width: 1, Right: true},
+			},
+			Rows: [][]string{},
+			Totals: []string{
+				"", "Overall", "",
+				fmt.Sprintf("%d of %d passed", report.Passed, report.ExamCount),
+				formatPercentage(report.AveragePercentage), "",
+			},
+		}
+		var remarks []string
+		for _, exam := range report.Exams {
+			subject := exam.SubjectName
+			if subject == "" {
+				subject = "-"
+			}
+			result := "Fail"
+			if exam.Passed {
+				result = "Pass"
+			}
+			table.Rows = append(table.Rows, []string{
+				exam.Date, exam.Name, subject,
+				formatMarks(exam.Marks) + " / " + formatMarks(exam.MaxMarks),
+				formatPercentage(exam.Percentage), result,
+			})
+			if remark := strings.TrimSpace(exam.Remarks); remark != "" {
+				remarks = append(remarks, exam.Name+": "+remark)
+			}
+		}
+		results.Table = table
+		if len(remarks) > 0 {
+			results.Note = "Remarks\n" + strings.Join(remarks, "\n")
+		}
+	}
+	layout.Sections = append(layout.Sections, results)
+
+	if len(report.BySubject) > 0 {
+		subjects := &pdf.Table{
+			Columns: []pdf.Column{
+				{Title: "Subject", Width: 4},
+				{Title: "Exams", Width: 1, Right: true},
+				{Title: "Average", Width: 1.3, Right: true},
+			},
+			Rows: [][]string{},
+		}
+		for _, performance := range report.BySubject {
+			subject := performance.SubjectName
+			if subject == "" {
+				subject = "General"
+			}
+			subjects.Rows = append(subjects.Rows, []string{
+				subject, strconv.Itoa(performance.ExamCount), formatPercentage(performance.AveragePercentage),
+			})
+		}
+		layout.Sections = append(layout.Sections, pdf.Section{Heading: "Subject Averages", Table: subjects})
+	}
+
+	return layout, nil
+}
+
+func (s *documentService) GetPaymentReceipt(ctx context.Context, paymentID uint) (*models.PrintedDocument, error) {
+	layout, err := s.GetPaymentReceiptLayout(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	return renderDocument(*layout, fmt.Sprintf("receipt-%s.pdf", receiptNumber(paymentID)))
+}
+
+// GetPaymentReceiptLayout assembles the receipt of a single fee payment
+func (s *documentService) GetPaymentReceiptLayout(ctx context.Context, paymentID uint) (*pdf.Layout, error) {
+	payment, err := s.feeRepo.GetPaymentByID(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("payment not found")
+	}
+
+	layout, err := s.brandedLayout(ctx, payment.BusinessID, "Fee Receipt")
+	if err != nil {
+		return nil, err
+	}
+	layout.Header.TitleInfo = []string{
+		"Receipt No: " + receiptNumber(payment.ID),
+		"Date: " + payment.PaidOn.Format(models.DateFormat),
+	}
+
+	layout.Sections = append(layout.Sections, pdf.Section{
+		Heading: "Received From",
+		Fields:  s.studentFields(ctx, payment.Student),
+	})
+
+	description := "Fee payment"
+	if payment.FeePlan != nil {
+		description = payment.FeePlan.Name
+	}
+	amount := formatMoney(payment.Amount)
+
+	details := []pdf.Field{{Label: "Payment mode", Value: paymentModeLabel(payment.Mode)}}
+	if payment.Reference != "" {
+		details = append(details, pdf.Field{Label: "Reference", Value: payment.Reference})
+	}
+	details = append(details, pdf.Field{Label: "Recorded on", Value: payment.CreatedOn.Format(models.DateFormat)})
+
+	section := pdf.Section{
+		Heading: "Payment",
+		Fields:  details,
+		Table: &pdf.Table{
+			Columns: []pdf.Column{
+				{Title: "Description", Width: 4},
+				{Title: "Amount", Width: 1.5, Right: true},
+			},
+			Rows:   [][]string{{description, amount}},
+			Totals: []string{"Total paid", amount},
+		},
+	}
+	if payment.Note != "" {
+		section.Note = "Note: " + payment.Note
+	}
+	layout.Sections = append(layout.Sections, section)
+	layout.Sections = append(layout.Sections, pdf.Section{
+		Note: "This is a computer-generated receipt and does not require a signature.",
+	})
+
+	return layout, nil
+}
+
+func (s *documentService) UploadLogo(ctx context.Context, businessID uint, file multipart.File, header *multipart.FileHeader) error {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return fmt.Errorf("business not found")
+	}
+
+	if _, err := businessLogoRules.Validate(file, header); err != nil {
+		return err
+	}
+	if err := pdf.CheckImage(file); err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read file: %v", err)
+	}
+
+	path := fmt.Sprintf("businesses/%d/logo_%d_%s", business.ID, time.Now().UnixNano(), storage.SafeFileName(header.Filename))
+	if err := s.storage.Save(path, file); err != nil {
+		return fmt.Errorf("failed to store logo: %v", err)
+	}
+
+	previous := business.LogoPath
+	if err := s.businessRepo.UpdateFields(ctx, business.ID, map[string]interface{}{"logo_path": path}); err != nil {
+		// Don't leave orphaned files behind
+		s.removeLogo(ctx, path)
+		return fmt.Errorf("failed to save logo: %v", err)
+	}
+
+	if previous != "" {
+		s.removeLogo(ctx, previous)
+	}
+	return nil
+}
+
+func (s *documentService) DeleteLogo(ctx context.Context, businessID uint) error {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return fmt.Errorf("business not found")
+	}
+	if business.LogoPath == "" {
+		return fmt.Errorf("business has no logo")
+	}
+
+	previous := business.LogoPath
+	if err := s.businessRepo.UpdateFields(ctx, business.ID, map[string]interface{}{"logo_path": ""}); err != nil {
+		return fmt.Errorf("failed to remove logo: %v", err)
+	}
+
+	s.removeLogo(ctx, previous)
+	return nil
+}
+
+func (s *documentService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
+
+func (s *documentService) CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("student not found")
+	}
+	return checkBusinessAccess(ctx, s.businessRepo, student.BusinessID, userID, role)
+}
+
+func (s *documentService) CheckPaymentAccess(ctx context.Context, paymentID, userID uint, role string) error {
+	payment, err := s.feeRepo.GetPaymentByID(ctx, paymentID)
+	if err != nil {
+		return fmt.Errorf("payment not found")
+	}
+	return checkBusinessAccess(ctx, s.businessRepo, payment.BusinessID, userID, role)
+}
+
+// brandedLayout starts a document headed with the business's name, address, contact
+// details and logo
+func (s *documentService) brandedLayout(ctx context.Context, businessID uint, title string) (*pdf.Layout, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	header := pdf.Header{Name: business.Name, Title: title}
+	if business.Location != "" {
+		header.Lines = append(header.Lines, business.Location)
+	}
+	var contact []string
+	for _, value := range []string{business.Phone, business.Email} {
+		if value != "" {
+			contact = append(contact, value)
+		}
+	}
+	if len(contact) > 0 {
+		header.Lines = append(header.Lines, strings.Join(contact, "  |  "))
+	}
+	header.Logo = s.loadLogo(ctx, business.LogoPath)
+
+	return &pdf.Layout{
+		Header:   header,
+		Sections: []pdf.Section{},
+		Footer:   fmt.Sprintf("%s  |  Generated on %s", business.Name, todayIn(business.Timezone).Format(models.DateFormat)),
+	}, nil
+}
+
+// loadLogo reads a stored logo. A missing or unreadable logo is logged and the document
+// printed without it rather than failing.
+func (s *documentService) loadLogo(ctx context.Context, path string) []byte {
+	if path == "" {
+		return nil
+	}
+
+	file, err := s.storage.Open(path)
+	if err != nil {
+		logger.FromContext(ctx).Warn("failed to open business logo", "path", path, "error", err)
+		return nil
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxBusinessLogoSize+1))
+	if err != nil || len(data) > MaxBusinessLogoSize {
+		logger.FromContext(ctx).Warn("failed to read business logo", "path", path, "error", err)
+		return nil
+	}
+	if err := pdf.CheckImage(bytes.NewReader(data)); err != nil {
+		logger.FromContext(ctx).Warn("skipping unusable business logo", "path", path, "error", err)
+		return nil
+	}
+	return data
+}
+
+func (s *documentService) removeLogo(ctx context.Context, path string) {
+	if err := s.storage.Delete(path); err != nil {
+		logger.FromContext(ctx).Warn("failed to remove stored logo", "path", path, "error", err)
+	}
+}
+
+// studentFields describes a student at the top of a document
+func (s *documentService) studentFields(ctx context.Context, student models.Student) []pdf.Field {
+	fields := []pdf.Field{
+		{Label: "Name", Value: student.Name},
+		{Label: "Student ID", Value: strconv.FormatUint(uint64(student.ID), 10)},
+	}
+	if student.BatchID != nil {
+		if batch, err := s.batchRepo.GetByID(ctx, *student.BatchID); err == nil {
+			fields = append(fields, pdf.Field{Label: "Batch", Value: batch.Name})
+		}
+	}
+	if student.Grade != "" {
+		fields = append(fields, pdf.Field{Label: "Grade", Value: student.Grade})
+	}
+	if student.GuardianName != "" {
+		fields = append(fields, pdf.Field{Label: "Guardian", Value: student.GuardianName})
+	}
+	if student.EnrolledOn != nil {
+		fields = append(fields, pdf.Field{Label: "Enrolled on", Value: student.EnrolledOn.Format(models.DateFormat)})
+	}
+	return fields
+}
+
+func renderDocument(layout pdf.Layout, fileName string) (*models.PrintedDocument, error) {
+	content, err := pdf.Render(layout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render document: %v", err)
+	}
+	return &models.PrintedDocument{
+		FileName:    fileName,
+		ContentType: "application/pdf",
+		Content:     content,
+	}, nil
+}
+
+// receiptNumber is the printed number of a payment's receipt
+func receiptNumber(paymentID uint) string {
+	return fmt.Sprintf("R-%06d", paymentID)
+}
+
+func documentPeriod(from, to string) string {
+	switch {
+	case from != "" && to != "":
+		return from + " to " + to
+	case from != "":
+		return "from " + from
+	case to != "":
+		return "until " + to
+	default:
+		return "all time"
+	}
+}
+
+func paymentModeLabel(mode string) string {
+	switch mode {
+	case models.PaymentModeUPI:
+		return "UPI"
+	case models.PaymentModeBankTransfer:
+		return "Bank transfer"
+	case "":
+		return "-"
+	default:
+		return strings.ToUpper(mode[:1]) + mode[1:]
+	}
+}
+
+func formatMoney(amount float64) string {
+	return strconv.FormatFloat(roundMoney(amount), 'f', 2, 64)
+}
+
+func formatMarks(marks float64) string {
+	return strconv.FormatFloat(marks, 'f', -1, 64)
+}
+
+func formatPercentage(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64) + "%"
+}
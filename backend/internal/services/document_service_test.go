@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/testutil"
+	"backend/pkg/pdf"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGoldenLayout compares a document's layout, as JSON, with testdata/name, or
+// rewrites the file with -update
+func checkGoldenLayout(t *testing.T, name string, layout *pdf.Layout) {
+	t.Helper()
+	got, err := json.MarshalIndent(layout, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode layout: %v", err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v; run with -update to create it", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("layout differs from %s; run with -update if the change is intended\ngot:\n%s", path, got)
+	}
+}
+
+// The receipt of a payment lays out the business, the student and the payment. IDs
+// and the print date change from run to run, so they are replaced by placeholders.
+func TestPaymentReceiptLayout(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	ctx := context.Background()
+	sunrise, aarav := f.Businesses["Sunrise Academy"], f.Students["Aarav"]
+
+	plan := models.FeePlan{BusinessID: sunrise.ID, StudentID: &aarav.ID, Name: "Monthly tuition", Amount: 1500,
+		Frequency: "monthly", DueDay: 5, StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Status: 1, CreatedBy: f.Admin.ID}
+	if err := db.Create(&plan).Error; err != nil {
+		t.Fatalf("failed to create fee plan: %v", err)
+	}
+	payment := models.FeePayment{BusinessID: sunrise.ID, StudentID: aarav.ID, FeePlanID: &plan.ID, Amount: 1500,
+		PaidOn: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Mode: models.PaymentModeUPI, Reference: "UPI-1234",
+		Note: "paid for January", RecordedBy: f.Admin.ID, CreatedOn: time.Date(2025, 1, 16, 12, 0, 0, 0, time.UTC)}
+	if err := db.Create(&payment).Error; err != nil {
+		t.Fatalf("failed to create payment: %v", err)
+	}
+
+	service := NewDocumentService(repository.NewBusinessRepository(db), repository.NewStudentRepository(db),
+		repository.NewBatchRepository(db), repository.NewFeeRepository(db), nil, nil, nil)
+	layout, err := service.GetPaymentReceiptLayout(ctx, payment.ID)
+	if err != nil {
+		t.Fatalf("GetPaymentReceiptLayout: %v", err)
+	}
+
+	for i, line := range layout.Header.TitleInfo {
+		layout.Header.TitleInfo[i] = strings.Replace(line, receiptNumber(payment.ID), "{payment}", 1)
+	}
+	for _, section := range layout.Sections {
+		for i, field := range section.Fields {
+			if field.Label == "Student ID" {
+				section.Fields[i].Value = "{student}"
+			}
+		}
+	}
+	layout.Footer = strings.Replace(layout.Footer, todayIn(sunrise.Timezone).Format(models.DateFormat), "{today}", 1)
+
+	checkGoldenLayout(t, "receipt.golden.json", layout)
+}
@@ -0,0 +1,544 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+type ExamService interface {
+	// Exams
+	CreateExam(ctx context.Context, businessID uint, req models.CreateExamRequest, actorID uint) (*models.ExamResponse, error)
+	GetExams(ctx context.Context, filters repository.ExamFilters) ([]models.ExamResponse, int64, error)
+	GetExam(ctx context.Context, businessID, examID uint) (*models.ExamResponse, error)
+	UpdateExam(ctx context.Context, businessID, examID uint, req models.UpdateExamRequest) (*models.ExamResponse, error)
+	DeleteExam(ctx context.Context, businessID, examID uint) error
+
+	// Results
+	RecordResults(ctx context.Context, businessID, examID uint, req models.RecordExamResultsRequest, actorID uint) ([]models.ExamResultResponse, error)
+	GetResults(ctx context.Context, businessID, examID uint) ([]models.ExamResultResponse, error)
+	DeleteResult(ctx context.Context, businessID, examID, studentID uint) error
+
+	// Reporting
+	GetExamStats(ctx context.Context, businessID, examID uint) (*models.ExamStats, error)
+	GetStudentReport(ctx context.Context, studentID uint, filters repository.ExamFilters) (*models.StudentExamReport, error)
+
+	// Access control
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+	CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error
+}
+
+type examService struct {
+	examRepo     repository.ExamRepository
+	studentRepo  repository.StudentRepository
+	batchRepo    repository.BatchRepository
+	subjectRepo  repository.SubjectRepository
+	businessRepo repository.BusinessRepository
+}
+
+func NewExamService(examRepo repository.ExamRepository, studentRepo repository.StudentRepository, batchRepo repository.BatchRepository, subjectRepo repository.SubjectRepository, businessRepo repository.BusinessRepository) ExamService {
+	return &examService{
+		examRepo:     examRepo,
+		studentRepo:  studentRepo,
+		batchRepo:    batchRepo,
+		subjectRepo:  subjectRepo,
+		businessRepo: businessRepo,
+	}
+}
+
+func (s *examService) CreateExam(ctx context.Context, businessID uint, req models.CreateExamRequest, actorID uint) (*models.ExamResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	// Check if business exists
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	date, err := parseDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateBatchAndSubject(ctx, businessID, req.BatchID, req.SubjectID); err != nil {
+		return nil, err
+	}
+
+	if req.PassMarks != nil && *req.PassMarks > req.MaxMarks {
+		return nil, fmt.Errorf("pass marks cannot exceed max marks")
+	}
+
+	exam := &models.Exam{
+		BusinessID: businessID,
+		BatchID:    req.BatchID,
+		SubjectID:  req.SubjectID,
+		Name:       name,
+		Date:       date,
+		MaxMarks:   req.MaxMarks,
+		PassMarks:  req.PassMarks,
+		CreatedBy:  actorID,
+	}
+
+	if err := s.examRepo.Create(ctx, exam); err != nil {
+		return nil, fmt.Errorf("failed to create exam: %v", err)
+	}
+
+	return s.GetExam(ctx, businessID, exam.ID)
+}
+
+func (s *examService) GetExams(ctx context.Context, filters repository.ExamFilters) ([]models.ExamResponse, int64, error) {
+	// Set default pagination
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.Limit == 0 {
+		filters.Limit = 10
+	}
+
+	if err := validateDateRange(filters.From, filters.To); err != nil {
+		return nil, 0, err
+	}
+
+	exams, total, err := s.examRepo.GetAll(ctx, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get exams: %v", err)
+	}
+
+	examIDs := make([]uint, 0, len(exams))
+	for _, exam := range exams {
+		examIDs = append(examIDs, exam.ID)
+	}
+
+	counts, err := s.examRepo.CountResults(ctx, examIDs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count exam results: %v", err)
+	}
+
+	responses := []models.ExamResponse{}
+	for _, exam := range exams {
+		response := toExamResponse(exam)
+		response.ResultCount = counts[exam.ID]
+		responses = append(responses, response)
+	}
+
+	return responses, total, nil
+}
+
+func (s *examService) GetExam(ctx context.Context, businessID, examID uint) (*models.ExamResponse, error) {
+	exam, err := s.getBusinessExam(ctx, businessID, examID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.examRepo.CountResults(ctx, []uint{exam.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count exam results: %v", err)
+	}
+
+	response := toExamResponse(*exam)
+	response.ResultCount = counts[exam.ID]
+	return &response, nil
+}
+
+func (s *examService) UpdateExam(ctx context.Context, businessID, examID uint, req models.UpdateExamRequest) (*models.ExamResponse, error) {
+	exam, err := s.getBusinessExam(ctx, businessID, examID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		exam.Name = name
+	}
+
+	if req.Date != "" {
+		date, err := parseDate(req.Date)
+		if err != nil {
+			return nil, err
+		}
+		exam.Date = date
+	}
+
+	if err := s.validateBatchAndSubject(ctx, businessID, req.BatchID, req.SubjectID); err != nil {
+		return nil, err
+	}
+	if req.SubjectID != nil {
+		exam.SubjectID = req.SubjectID
+	}
+
+	counts, err := s.examRepo.CountResults(ctx, []uint{exam.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count exam results: %v", err)
+	}
+
+	// Results were recorded for the old batch's students
+	if req.BatchID != nil && (exam.BatchID == nil || *exam.BatchID != *req.BatchID) {
+		if counts[exam.ID] > 0 {
+			return nil, fmt.Errorf("cannot change the batch of an exam that has results")
+		}
+		exam.BatchID = req.BatchID
+	}
+
+	if req.MaxMarks != nil {
+		highest, err := s.examRepo.GetHighestMarks(ctx, exam.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check exam results: %v", err)
+		}
+		if *req.MaxMarks < highest {
+			return nil, fmt.Errorf("max marks cannot be lower than the highest recorded marks (%.2f)", highest)
+		}
+		exam.MaxMarks = *req.MaxMarks
+	}
+
+	if req.PassMarks != nil {
+		exam.PassMarks = req.PassMarks
+	}
+	if exam.PassMarks != nil && *exam.PassMarks > exam.MaxMarks {
+		return nil, fmt.Errorf("pass marks cannot exceed max marks")
+	}
+
+	if err := s.examRepo.Update(ctx, exam); err != nil {
+		return nil, fmt.Errorf("failed to update exam: %v", err)
+	}
+
+	return s.GetExam(ctx, businessID, exam.ID)
+}
+
+func (s *examService) DeleteExam(ctx context.Context, businessID, examID uint) error {
+	exam, err := s.getBusinessExam(ctx, businessID, examID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.examRepo.Delete(ctx, exam.ID); err != nil {
+		return fmt.Errorf("failed to delete exam: %v", err)
+	}
+
+	return nil
+}
+
+func (s *examService) RecordResults(ctx context.Context, businessID, examID uint, req models.RecordExamResultsRequest, actorID uint) ([]models.ExamResultResponse, error) {
+	exam, err := s.getBusinessExam(ctx, businessID, examID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Results) == 0 {
+		return nil, fmt.Errorf("no results provided")
+	}
+
+	studentIDs := make([]uint, 0, len(req.Results))
+	seen := make(map[uint]bool, len(req.Results))
+	for _, entry := range req.Results {
+		if seen[entry.StudentID] {
+			return nil, fmt.Errorf("student %d appears more than once", entry.StudentID)
+		}
+		seen[entry.StudentID] = true
+		studentIDs = append(studentIDs, entry.StudentID)
+	}
+
+	students, err := s.studentRepo.GetByIDs(ctx, studentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get students: %v", err)
+	}
+
+	studentsByID := make(map[uint]models.Student, len(students))
+	for _, student := range students {
+		if student.BusinessID == businessID {
+			studentsByID[student.ID] = student
+		}
+	}
+
+	results := make([]models.ExamResult, 0, len(req.Results))
+	for _, entry := range req.Results {
+		student, ok := studentsByID[entry.StudentID]
+		if !ok {
+			return nil, fmt.Errorf("student %d not found in this business", entry.StudentID)
+		}
+		if exam.BatchID != nil && (student.BatchID == nil || *student.BatchID != *exam.BatchID) {
+			return nil, fmt.Errorf("student %d is not in the exam's batch", entry.StudentID)
+		}
+		if *entry.Marks > exam.MaxMarks {
+			return nil, fmt.Errorf("marks of student %d exceed the max marks of %.2f", entry.StudentID, exam.MaxMarks)
+		}
+
+		results = append(results, models.ExamResult{
+			ExamID:     exam.ID,
+			StudentID:  entry.StudentID,
+			Marks:      *entry.Marks,
+			Remarks:    entry.Remarks,
+			RecordedBy: actorID,
+		})
+	}
+
+	tx := s.examRepo.BeginTransaction(ctx)
+
+	if err := s.examRepo.UpsertResultsWithTransaction(tx, results); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record results: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit results: %v", err)
+	}
+
+	responses := make([]models.ExamResultResponse, 0, len(results))
+	for _, result := range results {
+		result.Student = studentsByID[result.StudentID]
+		responses = append(responses, toExamResultResponse(result, *exam))
+	}
+
+	return responses, nil
+}
+
+func (s *examService) GetResults(ctx context.Context, businessID, examID uint) ([]models.ExamResultResponse, error) {
+	exam, err := s.getBusinessExam(ctx, businessID, examID)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.examRepo.GetResults(ctx, exam.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get results: %v", err)
+	}
+
+	responses := []models.ExamResultResponse{}
+	for _, result := range results {
+		responses = append(responses, toExamResultResponse(result, *exam))
+	}
+
+	return responses, nil
+}
+
+func (s *examService) DeleteResult(ctx context.Context, businessID, examID, studentID uint) error {
+	exam, err := s.getBusinessExam(ctx, businessID, examID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.examRepo.DeleteResult(ctx, exam.ID, studentID); err != nil {
+		return fmt.Errorf("result not found")
+	}
+
+	return nil
+}
+
+func (s *examService) GetExamStats(ctx context.Context, businessID, examID uint) (*models.ExamStats, error) {
+	exam, err := s.getBusinessExam(ctx, businessID, examID)
+	if err != nil {
+		return nil, err
+	}
+
+	passMarks := exam.EffectivePassMarks()
+	result, err := s.examRepo.GetResultStats(ctx, exam.ID, passMarks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exam statistics: %v", err)
+	}
+
+	stats := &models.ExamStats{
+		ExamID:       exam.ID,
+		MaxMarks:     exam.MaxMarks,
+		PassMarks:    passMarks,
+		Appeared:     result.Appeared,
+		Passed:       result.Passed,
+		AverageMarks: roundPercentage(result.Average),
+		HighestMarks: result.Highest,
+		LowestMarks:  result.Lowest,
+	}
+	if result.Appeared > 0 {
+		stats.PassPercentage = roundPercentage(float64(result.Passed) / float64(result.Appeared) * 100)
+		stats.AveragePercentage = roundPercentage(result.Average / exam.MaxMarks * 100)
+	}
+
+	return stats, nil
+}
+
+func (s *examService) GetStudentReport(ctx context.Context, studentID uint, filters repository.ExamFilters) (*models.StudentExamReport, error) {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("student not found")
+	}
+
+	if err := validateDateRange(filters.From, filters.To); err != nil {
+		return nil, err
+	}
+
+	results, err := s.examRepo.GetStudentResults(ctx, student.ID, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get results: %v", err)
+	}
+
+	report := &models.StudentExamReport{
+		StudentID:   student.ID,
+		StudentName: student.Name,
+		From:        filters.From,
+		To:          filters.To,
+		Exams:       []models.StudentExamReportEntry{},
+		BySubject:   []models.SubjectPerformance{},
+	}
+
+	// Percentages are averaged so exams with different max marks weigh the same
+	var totalPercentage float64
+	bySubject := make(map[uint]*models.SubjectPerformance)
+	subjectTotals := make(map[uint]float64)
+	var subjectOrder []uint
+
+	for _, result := range results {
+		exam := result.Exam
+		var percentage float64
+		if exam.MaxMarks > 0 {
+			percentage = result.Marks / exam.MaxMarks * 100
+		}
+		passed := result.Marks >= exam.EffectivePassMarks()
+
+		entry := models.StudentExamReportEntry{
+			ExamID:     exam.ID,
+			Name:       exam.Name,
+			Date:       exam.Date.Format(models.DateFormat),
+			SubjectID:  exam.SubjectID,
+			MaxMarks:   exam.MaxMarks,
+			Marks:      result.Marks,
+			Percentage: roundPercentage(percentage),
+			Passed:     passed,
+			Remarks:    result.Remarks,
+		}
+		if exam.Subject != nil {
+			entry.SubjectName = exam.Subject.Name
+		}
+		report.Exams = append(report.Exams, entry)
+
+		totalPercentage += percentage
+		if passed {
+			report.Passed++
+		}
+
+		// Exams without a subject are grouped under subject 0
+		var subjectKey uint
+		if exam.SubjectID != nil {
+			subjectKey = *exam.SubjectID
+		}
+		if _, ok := bySubject[subjectKey]; !ok {
+			bySubject[subjectKey] = &models.SubjectPerformance{SubjectID: exam.SubjectID, SubjectName: entry.SubjectName}
+			subjectOrder = append(subjectOrder, subjectKey)
+		}
+		bySubject[subjectKey].ExamCount++
+		subjectTotals[subjectKey] += percentage
+	}
+
+	report.ExamCount = len(results)
+	if report.ExamCount > 0 {
+		report.AveragePercentage = roundPercentage(totalPercentage / float64(report.ExamCount))
+	}
+	for _, key := range subjectOrder {
+		performance := bySubject[key]
+		performance.AveragePercentage = roundPercentage(subjectTotals[key] / float64(performance.ExamCount))
+		report.BySubject = append(report.BySubject, *performance)
+	}
+
+	return report, nil
+}
+
+func (s *examService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
+
+func (s *examService) CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("student not found")
+	}
+	return checkBusinessAccess(ctx, s.businessRepo, student.BusinessID, userID, role)
+}
+
+func (s *examService) getBusinessExam(ctx context.Context, businessID, examID uint) (*models.Exam, error) {
+	exam, err := s.examRepo.GetByID(ctx, examID)
+	if err != nil || exam.BusinessID != businessID {
+		return nil, fmt.Errorf("exam not found")
+	}
+	return exam, nil
+}
+
+func (s *examService) validateBatchAndSubject(ctx context.Context, businessID uint, batchID, subjectID *uint) error {
+	if batchID != nil {
+		batch, err := s.batchRepo.GetByID(ctx, *batchID)
+		if err != nil || batch.BusinessID != businessID {
+			return fmt.Errorf("batch not found in this business")
+		}
+	}
+	if subjectID != nil {
+		subject, err := s.subjectRepo.GetByID(ctx, *subjectID)
+		if err != nil || subject.BusinessID != businessID {
+			return fmt.Errorf("subject not found in this business")
+		}
+	}
+	return nil
+}
+
+// Helper functions
+
+// validateDateRange checks optional YYYY-MM-DD bounds and that from is not after to
+func validateDateRange(from, to string) error {
+	if from != "" {
+		if _, err := parseDate(from); err != nil {
+			return fmt.Errorf("invalid from date: %v", err)
+		}
+	}
+	if to != "" {
+		if _, err := parseDate(to); err != nil {
+			return fmt.Errorf("invalid to date: %v", err)
+		}
+	}
+	if from != "" && to != "" && from > to {
+		return fmt.Errorf("from date must not be after to date")
+	}
+	return nil
+}
+
+// roundPercentage rounds a percentage or mark average to two decimals
+func roundPercentage(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func toExamResponse(exam models.Exam) models.ExamResponse {
+	response := models.ExamResponse{
+		ID:         exam.ID,
+		BusinessID: exam.BusinessID,
+		BatchID:    exam.BatchID,
+		SubjectID:  exam.SubjectID,
+		Name:       exam.Name,
+		Date:       exam.Date.Format(models.DateFormat),
+		MaxMarks:   exam.MaxMarks,
+		PassMarks:  exam.EffectivePassMarks(),
+		CreatedBy:  exam.CreatedBy,
+		CreatedOn:  exam.CreatedOn,
+	}
+	if exam.Batch != nil {
+		response.BatchName = exam.Batch.Name
+	}
+	if exam.Subject != nil {
+		response.SubjectName = exam.Subject.Name
+	}
+	return response
+}
+
+func toExamResultResponse(result models.ExamResult, exam models.Exam) models.ExamResultResponse {
+	response := models.ExamResultResponse{
+		ID:          result.ID,
+		ExamID:      result.ExamID,
+		StudentID:   result.StudentID,
+		StudentName: result.Student.Name,
+		Marks:       result.Marks,
+		Passed:      result.Marks >= exam.EffectivePassMarks(),
+		Remarks:     result.Remarks,
+		RecordedBy:  result.RecordedBy,
+		UpdatedOn:   result.UpdatedOn,
+	}
+	if exam.MaxMarks > 0 {
+		response.Percentage = roundPercentage(result.Marks / exam.MaxMarks * 100)
+	}
+	return response
+}
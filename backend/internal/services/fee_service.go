@@ -0,0 +1,752 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ErrPaymentRejected wraps the reasons a gateway payment can't be recorded, such as an
+// unknown student; retrying the same payment won't help
+var ErrPaymentRejected = errors.New("payment rejected")
+
+type FeeService interface {
+	// Fee plans
+	CreatePlan(ctx context.Context, businessID uint, req models.CreateFeePlanRequest, actorID uint) (*models.FeePlanResponse, error)
+	GetPlans(ctx context.Context, filters repository.FeePlanFilters) ([]models.FeePlanResponse, int64, error)
+
+	// Payments
+	RecordPayment(ctx context.Context, businessID uint, req models.RecordFeePaymentRequest, actorID uint) (*models.FeePaymentResponse, error)
+	// RecordGatewayPayment records a payment confirmed by a payment gateway once, reporting
+	// false with the payment recorded earlier when it was already recorded
+	RecordGatewayPayment(ctx context.Context, payment models.GatewayPayment) (*models.FeePaymentResponse, bool, error)
+	GetPayments(ctx context.Context, filters repository.FeePaymentFilters) ([]models.FeePaymentResponse, int64, error)
+
+	// Dues and reporting
+	GetStudentDues(ctx context.Context, studentID uint, asOf string) (*models.StudentFeeDues, error)
+	GetBusinessDues(ctx context.Context, businessID uint, asOf string, page, limit int) ([]models.StudentFeeDues, int64, error)
+	GetBusinessSummary(ctx context.Context, businessID uint, from, to string) (*models.FeeSummary, error)
+
+	// Reminders
+	SendDueReminders(ctx context.Context, businessID uint, asOf string) (*models.FeeReminderResult, error)
+
+	// Access control
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+	CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error
+}
+
+type feeService struct {
+	feeRepo      repository.FeeRepository
+	studentRepo  repository.StudentRepository
+	batchRepo    repository.BatchRepository
+	businessRepo repository.BusinessRepository
+	smsService   SMSService
+}
+
+func NewFeeService(feeRepo repository.FeeRepository, studentRepo repository.StudentRepository, batchRepo repository.BatchRepository, businessRepo repository.BusinessRepository, smsService SMSService) FeeService {
+	return &feeService{
+		feeRepo:      feeRepo,
+		studentRepo:  studentRepo,
+		batchRepo:    batchRepo,
+		businessRepo: businessRepo,
+		smsService:   smsService,
+	}
+}
+
+func (s *feeService) CreatePlan(ctx context.Context, businessID uint, req models.CreateFeePlanRequest, actorID uint) (*models.FeePlanResponse, error) {
+	if (req.StudentID == nil) == (req.BatchID == nil) {
+		return nil, fmt.Errorf("exactly one of student_id or batch_id is required")
+	}
+
+	// Check if business exists
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	startDate, err := parseDate(req.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %v", err)
+	}
+	endDate, err := parseOptionalDate(req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %v", err)
+	}
+	if endDate != nil && endDate.Before(startDate) {
+		return nil, fmt.Errorf("end date must not be before start date")
+	}
+
+	var student *models.Student
+	if req.StudentID != nil {
+		student, err = s.studentRepo.GetByID(ctx, *req.StudentID)
+		if err != nil || student.BusinessID != businessID {
+			return nil, fmt.Errorf("student not found in this business")
+		}
+	}
+	if req.BatchID != nil {
+		batch, err := s.batchRepo.GetByID(ctx, *req.BatchID)
+		if err != nil || batch.BusinessID != businessID {
+			return nil, fmt.Errorf("batch not found in this business")
+		}
+	}
+
+	dueDay := req.DueDay
+	if dueDay == 0 {
+		dueDay = 1
+	}
+
+	plan := &models.FeePlan{
+		BusinessID: businessID,
+		StudentID:  req.StudentID,
+		BatchID:    req.BatchID,
+		Name:       req.Name,
+		Amount:     roundMoney(req.Amount),
+		Frequency:  req.Frequency,
+		DueDay:     dueDay,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Status:     1,
+		CreatedBy:  actorID,
+	}
+
+	tx := s.feeRepo.BeginTransaction(ctx)
+
+	if err := s.feeRepo.CreatePlanWithTransaction(tx, plan); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create fee plan: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit fee plan: %v", err)
+	}
+
+	plan.Student = student
+	response := toFeePlanResponse(*plan)
+	return &response, nil
+}
+
+func (s *feeService) GetPlans(ctx context.Context, filters repository.FeePlanFilters) ([]models.FeePlanResponse, int64, error) {
+	// Set default pagination
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.Limit == 0 {
+		filters.Limit = 10
+	}
+
+	plans, total, err := s.feeRepo.GetPlans(ctx, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get fee plans: %v", err)
+	}
+
+	responses := []models.FeePlanResponse{}
+	for _, plan := range plans {
+		responses = append(responses, toFeePlanResponse(plan))
+	}
+
+	return responses, total, nil
+}
+
+func (s *feeService) RecordPayment(ctx context.Context, businessID uint, req models.RecordFeePaymentRequest, actorID uint) (*models.FeePaymentResponse, error) {
+	paidOn, err := parseDate(req.PaidOn)
+	if err != nil {
+		return nil, err
+	}
+	if paidOn.After(businessToday(ctx, s.businessRepo, businessID)) {
+		return nil, fmt.Errorf("payment date cannot be in the future")
+	}
+
+	student, err := s.studentRepo.GetByID(ctx, req.StudentID)
+	if err != nil || student.BusinessID != businessID {
+		return nil, fmt.Errorf("student not found in this business")
+	}
+
+	if err := s.checkPaymentPlan(ctx, req.FeePlanID, *student); err != nil {
+		return nil, err
+	}
+
+	payment := &models.FeePayment{
+		BusinessID: businessID,
+		StudentID:  student.ID,
+		FeePlanID:  req.FeePlanID,
+		Amount:     roundMoney(req.Amount),
+		PaidOn:     paidOn,
+		Mode:       req.Mode,
+		Reference:  req.Reference,
+		Note:       req.Note,
+		RecordedBy: actorID,
+	}
+
+	tx := s.feeRepo.BeginTransaction(ctx)
+
+	if err := s.feeRepo.CreatePaymentWithTransaction(tx, payment); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record payment: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit payment: %v", err)
+	}
+
+	payment.Student = *student
+	response := toFeePaymentResponse(*payment)
+	return &response, nil
+}
+
+func (s *feeService) RecordGatewayPayment(ctx context.Context, gatewayPayment models.GatewayPayment) (*models.FeePaymentResponse, bool, error) {
+	externalID := gatewayPayment.Provider + ":" + gatewayPayment.PaymentID
+	if existing, err := s.feeRepo.GetPaymentByExternalID(ctx, externalID); err == nil {
+		response := toFeePaymentResponse(*existing)
+		return &response, false, nil
+	} else if !repository.IsNotFound(err) {
+		return nil, false, fmt.Errorf("failed to look up payment: %w", err)
+	}
+
+	if gatewayPayment.Amount <= 0 {
+		return nil, false, fmt.Errorf("%w: amount must be positive", ErrPaymentRejected)
+	}
+	student, err := s.studentRepo.GetByID(ctx, gatewayPayment.StudentID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, false, fmt.Errorf("%w: student %d not found", ErrPaymentRejected, gatewayPayment.StudentID)
+		}
+		return nil, false, fmt.Errorf("failed to get student: %w", err)
+	}
+	if err := s.checkPaymentPlan(ctx, gatewayPayment.FeePlanID, *student); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrPaymentRejected, err)
+	}
+
+	payment := &models.FeePayment{
+		BusinessID: student.BusinessID,
+		StudentID:  student.ID,
+		FeePlanID:  gatewayPayment.FeePlanID,
+		Amount:     roundMoney(gatewayPayment.Amount),
+		PaidOn:     gatewayPayment.PaidOn,
+		Mode:       gatewayPayment.Mode,
+		Reference:  gatewayPayment.PaymentID,
+		Note:       fmt.Sprintf("Paid online via %s (%s)", gatewayPayment.Provider, gatewayPayment.Currency),
+		ExternalID: externalID,
+	}
+
+	created, err := s.feeRepo.CreateExternalPayment(ctx, payment)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to record payment: %w", err)
+	}
+	if !created {
+		// Another delivery of the same payment got there first
+		existing, err := s.feeRepo.GetPaymentByExternalID(ctx, externalID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to look up payment: %w", err)
+		}
+		payment = existing
+	} else {
+		payment.Student = *student
+	}
+
+	response := toFeePaymentResponse(*payment)
+	return &response, created, nil
+}
+
+// checkPaymentPlan checks that the plan a payment is allocated to, if any, is one of the
+// student's
+func (s *feeService) checkPaymentPlan(ctx context.Context, feePlanID *uint, student models.Student) error {
+	if feePlanID == nil {
+		return nil
+	}
+	plan, err := s.feeRepo.GetPlanByID(ctx, *feePlanID)
+	if err != nil || plan.BusinessID != student.BusinessID {
+		return fmt.Errorf("fee plan not found in this business")
+	}
+	if !feePlanAppliesTo(*plan, student) {
+		return fmt.Errorf("fee plan does not apply to this student")
+	}
+	return nil
+}
+
+func (s *feeService) GetPayments(ctx context.Context, filters repository.FeePaymentFilters) ([]models.FeePaymentResponse, int64, error) {
+	// Set default pagination
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.Limit == 0 {
+		filters.Limit = 10
+	}
+
+	if filters.From != "" {
+		if _, err := parseDate(filters.From); err != nil {
+			return nil, 0, fmt.Errorf("invalid from date: %v", err)
+		}
+	}
+	if filters.To != "" {
+		if _, err := parseDate(filters.To); err != nil {
+			return nil, 0, fmt.Errorf("invalid to date: %v", err)
+		}
+	}
+	if filters.From != "" && filters.To != "" && filters.From > filters.To {
+		return nil, 0, fmt.Errorf("from date must not be after to date")
+	}
+
+	payments, total, err := s.feeRepo.GetPayments(ctx, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get payments: %v", err)
+	}
+
+	responses := []models.FeePaymentResponse{}
+	for _, payment := range payments {
+		responses = append(responses, toFeePaymentResponse(payment))
+	}
+
+	return responses, total, nil
+}
+
+func (s *feeService) GetStudentDues(ctx context.Context, studentID uint, asOf string) (*models.StudentFeeDues, error) {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("student not found")
+	}
+
+	date, err := parseAsOfDate(asOf, businessToday(ctx, s.businessRepo, student.BusinessID))
+	if err != nil {
+		return nil, err
+	}
+
+	plans, err := s.feeRepo.GetActivePlansByBusiness(ctx, student.BusinessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee plans: %v", err)
+	}
+
+	totals, err := s.feeRepo.GetPaymentTotals(ctx, repository.FeePaymentFilters{
+		StudentID: &student.ID,
+		To:        date.Format(models.DateFormat),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments: %v", err)
+	}
+
+	dues := computeStudentDues(*student, plans, totals, date)
+	return &dues, nil
+}
+
+func (s *feeService) GetBusinessDues(ctx context.Context, businessID uint, asOf string, page, limit int) ([]models.StudentFeeDues, int64, error) {
+	date, err := parseAsOfDate(asOf, businessToday(ctx, s.businessRepo, businessID))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Set default pagination
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	allDues, err := s.businessDues(ctx, businessID, date)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Only students who still owe money, largest balance first
+	outstanding := []models.StudentFeeDues{}
+	for _, dues := range allDues {
+		if dues.Balance > 0 {
+			outstanding = append(outstanding, dues)
+		}
+	}
+	sort.SliceStable(outstanding, func(i, j int) bool {
+		return outstanding[i].Balance > outstanding[j].Balance
+	})
+
+	total := int64(len(outstanding))
+	start := (page - 1) * limit
+	if start > len(outstanding) {
+		start = len(outstanding)
+	}
+	end := start + limit
+	if end > len(outstanding) {
+		end = len(outstanding)
+	}
+
+	return outstanding[start:end], total, nil
+}
+
+func (s *feeService) GetBusinessSummary(ctx context.Context, businessID uint, from, to string) (*models.FeeSummary, error) {
+	toDate, err := parseAsOfDate(to, businessToday(ctx, s.businessRepo, businessID))
+	if err != nil {
+		return nil, fmt.Errorf("invalid to date: %v", err)
+	}
+	fromDate := time.Date(toDate.Year(), toDate.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if from != "" {
+		fromDate, err = parseDate(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date: %v", err)
+		}
+	}
+	if fromDate.After(toDate) {
+		return nil, fmt.Errorf("from date must not be after to date")
+	}
+
+	// Check if business exists
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	byMode, err := s.feeRepo.GetPaymentTotalsByMode(ctx, repository.FeePaymentFilters{
+		BusinessID: &businessID,
+		From:       fromDate.Format(models.DateFormat),
+		To:         toDate.Format(models.DateFormat),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment totals: %v", err)
+	}
+
+	summary := &models.FeeSummary{
+		BusinessID: businessID,
+		From:       fromDate.Format(models.DateFormat),
+		To:         toDate.Format(models.DateFormat),
+		ByMode:     []models.FeeModeTotal{},
+	}
+	for _, mode := range byMode {
+		mode.Amount = roundMoney(mode.Amount)
+		summary.Collected += mode.Amount
+		summary.PaymentCount += mode.Count
+		summary.ByMode = append(summary.ByMode, mode)
+	}
+	summary.Collected = roundMoney(summary.Collected)
+
+	allDues, err := s.businessDues(ctx, businessID, toDate)
+	if err != nil {
+		return nil, err
+	}
+	for _, dues := range allDues {
+		if dues.Balance > 0 {
+			summary.Outstanding += dues.Balance
+			summary.StudentsWithDues++
+		}
+	}
+	summary.Outstanding = roundMoney(summary.Outstanding)
+
+	// Installments falling due in the range are those due by the end of the range
+	// minus those already due the day before it starts
+	plans, err := s.feeRepo.GetActivePlansByBusiness(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee plans: %v", err)
+	}
+	students, err := s.activeStudents(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+	dayBefore := fromDate.AddDate(0, 0, -1)
+	for _, student := range students {
+		for _, plan := range plans {
+			if !feePlanAppliesTo(plan, student) {
+				continue
+			}
+			start := feePlanStartFor(plan, student)
+			untilEnd, _ := feeInstallments(plan, start, toDate)
+			beforeStart, _ := feeInstallments(plan, start, dayBefore)
+			summary.DueInRange += float64(untilEnd-beforeStart) * plan.Amount
+		}
+	}
+	summary.DueInRange = roundMoney(summary.DueInRange)
+
+	return summary, nil
+}
+
+// SendDueReminders texts the guardians of every active student with an outstanding
+// balance as of a date. Each student is reminded at most once a day, so repeating the
+// request only texts students missed the first time.
+func (s *feeService) SendDueReminders(ctx context.Context, businessID uint, asOf string) (*models.FeeReminderResult, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+	if !business.SMSNotifications {
+		return nil, ErrSMSDisabled
+	}
+
+	date, err := parseAsOfDate(asOf, todayIn(business.Timezone))
+	if err != nil {
+		return nil, err
+	}
+
+	allDues, err := s.businessDues(ctx, businessID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	var outstanding []models.StudentFeeDues
+	studentIDs := make([]uint, 0, len(allDues))
+	for _, dues := range allDues {
+		if dues.Balance > 0 {
+			outstanding = append(outstanding, dues)
+			studentIDs = append(studentIDs, dues.StudentID)
+		}
+	}
+
+	guardians, err := s.studentRepo.GetGuardiansByStudentIDs(ctx, studentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guardians: %v", err)
+	}
+	phones := guardianPhones(guardians)
+
+	result := &models.FeeReminderResult{
+		AsOf:        date.Format(models.DateFormat),
+		StudentsDue: len(outstanding),
+	}
+	today := todayIn(business.Timezone).Format(models.DateFormat)
+	messages := make([]models.OutgoingSMS, 0, len(outstanding))
+	for _, dues := range outstanding {
+		phone, ok := phones[dues.StudentID]
+		if !ok {
+			result.WithoutPhone++
+			continue
+		}
+		messages = append(messages, models.OutgoingSMS{
+			StudentID: dues.StudentID,
+			To:        phone,
+			Body:      fmt.Sprintf("%s: fees of %.2f are due for %s as of %s. Please ignore this message if already paid.", business.Name, dues.Balance, dues.StudentName, result.AsOf),
+			DedupKey:  fmt.Sprintf("%s:%d:%s", models.SMSKindFeeDue, dues.StudentID, today),
+		})
+	}
+
+	enqueued, err := s.smsService.Enqueue(ctx, businessID, models.SMSKindFeeDue, messages)
+	if err != nil {
+		return nil, err
+	}
+	result.SMSEnqueueResult = *enqueued
+	return result, nil
+}
+
+func (s *feeService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
+
+func (s *feeService) CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("student not found")
+	}
+	return checkBusinessAccess(ctx, s.businessRepo, student.BusinessID, userID, role)
+}
+
+// businessDues computes the dues of every active student of a business as of a date
+func (s *feeService) businessDues(ctx context.Context, businessID uint, asOf time.Time) ([]models.StudentFeeDues, error) {
+	// Check if business exists
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	plans, err := s.feeRepo.GetActivePlansByBusiness(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee plans: %v", err)
+	}
+
+	totals, err := s.feeRepo.GetPaymentTotals(ctx, repository.FeePaymentFilters{
+		BusinessID: &businessID,
+		To:         asOf.Format(models.DateFormat),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments: %v", err)
+	}
+
+	totalsByStudent := make(map[uint][]repository.FeePaymentTotal)
+	for _, total := range totals {
+		totalsByStudent[total.StudentID] = append(totalsByStudent[total.StudentID], total)
+	}
+
+	students, err := s.activeStudents(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	allDues := make([]models.StudentFeeDues, 0, len(students))
+	for _, student := range students {
+		allDues = append(allDues, computeStudentDues(student, plans, totalsByStudent[student.ID], asOf))
+	}
+
+	return allDues, nil
+}
+
+func (s *feeService) activeStudents(ctx context.Context, businessID uint) ([]models.Student, error) {
+	students, err := s.studentRepo.GetActiveStudentsByBusiness(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get students: %v", err)
+	}
+	return students, nil
+}
+
+// Helper functions
+
+// computeStudentDues adds up what a student owes on every plan that applies to them
+// as of a date, and what they have paid by then
+func computeStudentDues(student models.Student, plans []models.FeePlan, totals []repository.FeePaymentTotal, asOf time.Time) models.StudentFeeDues {
+	dues := models.StudentFeeDues{
+		StudentID:   student.ID,
+		StudentName: student.Name,
+		BatchID:     student.BatchID,
+		AsOf:        asOf.Format(models.DateFormat),
+		Plans:       []models.FeePlanDue{},
+	}
+
+	paidByPlan := make(map[uint]float64)
+	for _, total := range totals {
+		dues.TotalPaid += total.Amount
+		if total.FeePlanID == nil {
+			dues.Unallocated += total.Amount
+		} else {
+			paidByPlan[*total.FeePlanID] += total.Amount
+		}
+	}
+
+	for _, plan := range plans {
+		if !feePlanAppliesTo(plan, student) {
+			continue
+		}
+
+		installments, next := feeInstallments(plan, feePlanStartFor(plan, student), asOf)
+		planDue := models.FeePlanDue{
+			FeePlanID:       plan.ID,
+			Name:            plan.Name,
+			Amount:          plan.Amount,
+			Frequency:       plan.Frequency,
+			InstallmentsDue: installments,
+			AmountDue:       roundMoney(float64(installments) * plan.Amount),
+			AmountPaid:      roundMoney(paidByPlan[plan.ID]),
+		}
+		planDue.Balance = roundMoney(planDue.AmountDue - planDue.AmountPaid)
+		if next != nil {
+			planDue.NextDueDate = next.Format(models.DateFormat)
+		}
+
+		dues.TotalDue += planDue.AmountDue
+		dues.Plans = append(dues.Plans, planDue)
+	}
+
+	dues.TotalDue = roundMoney(dues.TotalDue)
+	dues.TotalPaid = roundMoney(dues.TotalPaid)
+	dues.Unallocated = roundMoney(dues.Unallocated)
+	dues.Balance = roundMoney(dues.TotalDue - dues.TotalPaid)
+
+	return dues
+}
+
+// feePlanAppliesTo reports whether a plan charges a student, either directly or
+// through the student's batch
+func feePlanAppliesTo(plan models.FeePlan, student models.Student) bool {
+	if plan.StudentID != nil {
+		return *plan.StudentID == student.ID
+	}
+	return plan.BatchID != nil && student.BatchID != nil && *plan.BatchID == *student.BatchID
+}
+
+// feePlanStartFor is the date a plan starts charging a student. Batch plans don't
+// charge students for periods before they enrolled.
+func feePlanStartFor(plan models.FeePlan, student models.Student) time.Time {
+	if plan.StudentID == nil && student.EnrolledOn != nil && student.EnrolledOn.After(plan.StartDate) {
+		return *student.EnrolledOn
+	}
+	return plan.StartDate
+}
+
+// feeInstallments counts the installments of a plan that fall due between start and
+// upTo (inclusive) and returns the next due date after upTo, if any. A one-time fee
+// is due on the start date; recurring fees are due on the plan's due day, the first
+// installment on the first due day on or after the start date.
+func feeInstallments(plan models.FeePlan, start, upTo time.Time) (int, *time.Time) {
+	step := 0
+	switch plan.Frequency {
+	case models.FeeFrequencyMonthly:
+		step = 1
+	case models.FeeFrequencyQuarterly:
+		step = 3
+	case models.FeeFrequencyYearly:
+		step = 12
+	}
+
+	first := start
+	if step > 0 {
+		dueDay := plan.DueDay
+		if dueDay < 1 {
+			dueDay = 1
+		}
+		first = time.Date(start.Year(), start.Month(), dueDay, 0, 0, 0, 0, time.UTC)
+		if first.Before(start) {
+			first = first.AddDate(0, 1, 0)
+		}
+	}
+
+	count := 0
+	for due := first; ; due = first.AddDate(0, count*step, 0) {
+		if plan.EndDate != nil && due.After(*plan.EndDate) {
+			return count, nil
+		}
+		if due.After(upTo) {
+			return count, &due
+		}
+		count++
+		if step == 0 {
+			return count, nil
+		}
+	}
+}
+
+// parseAsOfDate parses an optional YYYY-MM-DD date, defaulting to today
+func parseAsOfDate(value string, today time.Time) (time.Time, error) {
+	if value == "" {
+		return today, nil
+	}
+	return parseDate(value)
+}
+
+// currentDate returns today's date at midnight UTC, matching dates parsed by parseDate
+func currentDate() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func toFeePlanResponse(plan models.FeePlan) models.FeePlanResponse {
+	response := models.FeePlanResponse{
+		ID:         plan.ID,
+		BusinessID: plan.BusinessID,
+		StudentID:  plan.StudentID,
+		BatchID:    plan.BatchID,
+		Name:       plan.Name,
+		Amount:     plan.Amount,
+		Frequency:  plan.Frequency,
+		DueDay:     plan.DueDay,
+		StartDate:  plan.StartDate.Format(models.DateFormat),
+		EndDate:    formatOptionalDate(plan.EndDate),
+		Status:     plan.Status,
+		CreatedBy:  plan.CreatedBy,
+		CreatedOn:  plan.CreatedOn,
+	}
+	if plan.Student != nil {
+		response.StudentName = plan.Student.Name
+	}
+	return response
+}
+
+func toFeePaymentResponse(payment models.FeePayment) models.FeePaymentResponse {
+	return models.FeePaymentResponse{
+		ID:          payment.ID,
+		BusinessID:  payment.BusinessID,
+		StudentID:   payment.StudentID,
+		StudentName: payment.Student.Name,
+		FeePlanID:   payment.FeePlanID,
+		Amount:      payment.Amount,
+		PaidOn:      payment.PaidOn.Format(models.DateFormat),
+		Mode:        payment.Mode,
+		Reference:   payment.Reference,
+		Note:        payment.Note,
+		RecordedBy:  payment.RecordedBy,
+		CreatedOn:   payment.CreatedOn,
+	}
+}
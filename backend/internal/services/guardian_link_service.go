@@ -0,0 +1,270 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/storage"
+	"backend/pkg/utils"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// guardianAttendanceDays is the period the guardian view summarises attendance over
+const guardianAttendanceDays = 30
+
+// guardianRecentResults is how many of the latest exam results the guardian view shows
+const guardianRecentResults = 5
+
+// ErrGuardianLinkInvalid is returned for unknown, expired and revoked guardian tokens alike
+var ErrGuardianLinkInvalid = fmt.Errorf("guardian link is invalid or has expired")
+
+type GuardianLinkService interface {
+	// Managing links
+	CreateLink(ctx context.Context, studentID uint, req models.CreateGuardianLinkRequest, actorID uint) (*models.GuardianLinkResponse, error)
+	GetLinks(ctx context.Context, studentID uint) ([]models.GuardianLinkResponse, error)
+	RevokeLink(ctx context.Context, studentID, linkID uint) error
+
+	// Public view
+	GetGuardianView(ctx context.Context, token string) (*models.GuardianView, error)
+	GetGuardianPhoto(ctx context.Context, token string) (*models.Photo, error)
+
+	// Access control
+	CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error
+}
+
+type guardianLinkService struct {
+	linkRepo       repository.GuardianLinkRepository
+	studentRepo    repository.StudentRepository
+	attendanceRepo repository.StudentAttendanceRepository
+	examRepo       repository.ExamRepository
+	feeRepo        repository.FeeRepository
+	businessRepo   repository.BusinessRepository
+	storage        storage.Storage
+}
+
+func NewGuardianLinkService(linkRepo repository.GuardianLinkRepository, studentRepo repository.StudentRepository, attendanceRepo repository.StudentAttendanceRepository, examRepo repository.ExamRepository, feeRepo repository.FeeRepository, businessRepo repository.BusinessRepository, store storage.Storage) GuardianLinkService {
+	return &guardianLinkService{
+		linkRepo:       linkRepo,
+		studentRepo:    studentRepo,
+		attendanceRepo: attendanceRepo,
+		examRepo:       examRepo,
+		feeRepo:        feeRepo,
+		businessRepo:   businessRepo,
+		storage:        store,
+	}
+}
+
+func (s *guardianLinkService) CreateLink(ctx context.Context, studentID uint, req models.CreateGuardianLinkRequest, actorID uint) (*models.GuardianLinkResponse, error) {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("student not found")
+	}
+
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = models.DefaultGuardianLinkDays
+	}
+
+	tokenID, err := generateTokenID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %v", err)
+	}
+
+	link := &models.GuardianLink{
+		StudentID:  student.ID,
+		BusinessID: student.BusinessID,
+		TokenID:    tokenID,
+		Label:      req.Label,
+		ExpiresAt:  time.Now().UTC().AddDate(0, 0, days),
+		CreatedBy:  actorID,
+	}
+
+	token, err := utils.GenerateGuardianToken(student.ID, link.TokenID, link.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %v", err)
+	}
+
+	if err := s.linkRepo.Create(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to create guardian link: %v", err)
+	}
+
+	response := toGuardianLinkResponse(*link)
+	response.Token = token
+	return &response, nil
+}
+
+func (s *guardianLinkService) GetLinks(ctx context.Context, studentID uint) ([]models.GuardianLinkResponse, error) {
+	links, err := s.linkRepo.GetByStudentID(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guardian links: %v", err)
+	}
+
+	responses := []models.GuardianLinkResponse{}
+	for _, link := range links {
+		responses = append(responses, toGuardianLinkResponse(link))
+	}
+	return responses, nil
+}
+
+func (s *guardianLinkService) RevokeLink(ctx context.Context, studentID, linkID uint) error {
+	link, err := s.linkRepo.GetByID(ctx, linkID)
+	if err != nil || link.StudentID != studentID {
+		return fmt.Errorf("guardian link not found")
+	}
+
+	if err := s.linkRepo.Revoke(ctx, link.ID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to revoke guardian link: %v", err)
+	}
+	return nil
+}
+
+// GetGuardianView builds the view from the token's student only
+func (s *guardianLinkService) GetGuardianView(ctx context.Context, token string) (*models.GuardianView, error) {
+	link, student, err := s.linkedStudent(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &models.GuardianView{
+		StudentName:   student.Name,
+		BusinessName:  student.Business.Name,
+		PhotoURL:      guardianPhotoURL(token, student),
+		RecentResults: []models.GuardianExamResult{},
+		LinkExpiresAt: link.ExpiresAt,
+	}
+	if student.Batch != nil {
+		view.BatchName = student.Batch.Name
+	}
+
+	// Attendance over the last days, today included, where the centre is
+	today := todayIn(student.Business.Timezone)
+	from := today.AddDate(0, 0, -(guardianAttendanceDays - 1))
+	counts, err := s.attendanceRepo.GetStatusCounts(ctx, repository.StudentAttendanceFilters{
+		StudentID: &student.ID,
+		From:      from.Format(models.DateFormat),
+		To:        today.Format(models.DateFormat),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance: %v", err)
+	}
+	attendance := models.GuardianAttendance{
+		From: from.Format(models.DateFormat),
+		To:   today.Format(models.DateFormat),
+	}
+	for _, count := range counts {
+		addAttendanceCount(&attendance.Present, &attendance.Absent, &attendance.Leave, count.Status, count.Count)
+	}
+	attendance.TotalMarked, attendance.AttendanceRate = studentAttendanceTotals(attendance.Present, attendance.Absent, attendance.Leave)
+	view.Attendance = attendance
+
+	results, err := s.examRepo.GetRecentStudentResults(ctx, student.ID, guardianRecentResults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exam results: %v", err)
+	}
+	for _, result := range results {
+		exam := result.Exam
+		var percentage float64
+		if exam.MaxMarks > 0 {
+			percentage = result.Marks / exam.MaxMarks * 100
+		}
+		entry := models.GuardianExamResult{
+			Exam:       exam.Name,
+			Date:       exam.Date.Format(models.DateFormat),
+			MaxMarks:   exam.MaxMarks,
+			Marks:      result.Marks,
+			Percentage: roundPercentage(percentage),
+			Passed:     result.Marks >= exam.EffectivePassMarks(),
+		}
+		if exam.Subject != nil {
+			entry.SubjectName = exam.Subject.Name
+		}
+		view.RecentResults = append(view.RecentResults, entry)
+	}
+
+	plans, err := s.feeRepo.GetActivePlansByBusiness(ctx, student.BusinessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee plans: %v", err)
+	}
+	totals, err := s.feeRepo.GetPaymentTotals(ctx, repository.FeePaymentFilters{
+		StudentID: &student.ID,
+		To:        today.Format(models.DateFormat),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments: %v", err)
+	}
+	dues := computeStudentDues(*student, plans, totals, today)
+	view.Fees = models.GuardianFeeBalance{
+		AsOf:      dues.AsOf,
+		TotalDue:  dues.TotalDue,
+		TotalPaid: dues.TotalPaid,
+		Balance:   dues.Balance,
+	}
+
+	return view, nil
+}
+
+// GetGuardianPhoto reads the photo of the token's student
+func (s *guardianLinkService) GetGuardianPhoto(ctx context.Context, token string) (*models.Photo, error) {
+	_, student, err := s.linkedStudent(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if student.PhotoPath == "" {
+		return nil, errors.New("student has no photo")
+	}
+	return readPhoto(s.storage, student.PhotoPath)
+}
+
+// linkedStudent checks the token's signature, then that its link still exists and is
+// active, and returns the link with its student
+func (s *guardianLinkService) linkedStudent(ctx context.Context, token string) (*models.GuardianLink, *models.Student, error) {
+	claims, err := utils.ValidateGuardianToken(token)
+	if err != nil || claims.ID == "" {
+		return nil, nil, ErrGuardianLinkInvalid
+	}
+
+	link, err := s.linkRepo.GetByTokenID(ctx, claims.ID)
+	if err != nil || link.StudentID != claims.StudentID || !link.Active(time.Now()) {
+		return nil, nil, ErrGuardianLinkInvalid
+	}
+
+	student, err := s.studentRepo.GetByID(ctx, link.StudentID)
+	if err != nil || student.BusinessID != link.BusinessID {
+		return nil, nil, ErrGuardianLinkInvalid
+	}
+	return link, student, nil
+}
+
+func (s *guardianLinkService) CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("student not found")
+	}
+	return checkBusinessAccess(ctx, s.businessRepo, student.BusinessID, userID, role)
+}
+
+// generateTokenID returns a random identifier for a guardian link's token
+func generateTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func toGuardianLinkResponse(link models.GuardianLink) models.GuardianLinkResponse {
+	return models.GuardianLinkResponse{
+		ID:        link.ID,
+		StudentID: link.StudentID,
+		Label:     link.Label,
+		ExpiresAt: link.ExpiresAt,
+		RevokedAt: link.RevokedAt,
+		Active:    link.Active(time.Now()),
+		CreatedBy: link.CreatedBy,
+		CreatedOn: link.CreatedOn,
+	}
+}
@@ -0,0 +1,12 @@
+package services
+
+import (
+	"os"
+	"testing"
+
+	"backend/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.Main(m))
+}
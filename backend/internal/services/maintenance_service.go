@@ -0,0 +1,93 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/logger"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maintenanceCacheTTL is how long the maintenance state is served from memory. The
+// middleware reads it on every request; other instances pick up a toggle within this long.
+const maintenanceCacheTTL = 5 * time.Second
+
+// defaultMaintenanceRetryAfter is suggested to clients when no retry time was set
+const defaultMaintenanceRetryAfter = 300
+
+type MaintenanceService interface {
+	GetState(ctx context.Context) (*models.MaintenanceState, error)
+	UpdateState(ctx context.Context, req models.UpdateMaintenanceRequest, actorID uint) (*models.MaintenanceState, error)
+
+	// CurrentState is the cached state consulted by the maintenance middleware
+	CurrentState(ctx context.Context) (*models.MaintenanceState, error)
+}
+
+type maintenanceService struct {
+	maintenanceRepo repository.MaintenanceRepository
+
+	mu       sync.Mutex
+	cached   *models.MaintenanceState
+	cachedAt time.Time
+}
+
+func NewMaintenanceService(maintenanceRepo repository.MaintenanceRepository) MaintenanceService {
+	return &maintenanceService{
+		maintenanceRepo: maintenanceRepo,
+	}
+}
+
+func (s *maintenanceService) GetState(ctx context.Context) (*models.MaintenanceState, error) {
+	state, err := s.maintenanceRepo.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance state: %w", err)
+	}
+	return state, nil
+}
+
+func (s *maintenanceService) UpdateState(ctx context.Context, req models.UpdateMaintenanceRequest, actorID uint) (*models.MaintenanceState, error) {
+	state := &models.MaintenanceState{
+		Enabled:           *req.Enabled,
+		Message:           req.Message,
+		RetryAfterSeconds: req.RetryAfterSeconds,
+		UpdatedBy:         actorID,
+	}
+	if state.Message == "" {
+		state.Message = models.DefaultMaintenanceMessage
+	}
+	if state.RetryAfterSeconds == 0 {
+		state.RetryAfterSeconds = defaultMaintenanceRetryAfter
+	}
+
+	if err := s.maintenanceRepo.Save(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to update maintenance state: %w", err)
+	}
+
+	s.mu.Lock()
+	s.cached = state
+	s.cachedAt = time.Now()
+	s.mu.Unlock()
+
+	logger.FromContext(ctx).Info("Maintenance mode updated", "enabled", state.Enabled, "actor_id", actorID)
+	return state, nil
+}
+
+func (s *maintenanceService) CurrentState(ctx context.Context) (*models.MaintenanceState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && time.Since(s.cachedAt) < maintenanceCacheTTL {
+		return s.cached, nil
+	}
+
+	state, err := s.maintenanceRepo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cached = state
+	s.cachedAt = time.Now()
+	return state, nil
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/logger"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// packageExpiryNotices are how many days before its package runs out a business is
+// reminded, each reminder sent once
+var packageExpiryNotices = []int{1, 7}
+
+type NotificationService interface {
+	// Notify adds a notification to the feed of each of the users. Producers call it once
+	// their change is committed; a failure is the producer's to log, never to fail on.
+	Notify(ctx context.Context, notification models.NewNotification) error
+	NotifyBusiness(ctx context.Context, businessID uint, notification models.NewNotification) error
+
+	// Feed of the current user
+	GetNotifications(ctx context.Context, userID uint, filters repository.NotificationFilters) ([]models.NotificationResponse, int64, error)
+	GetUnreadCount(ctx context.Context, userID uint) (int64, error)
+	MarkRead(ctx context.Context, userID, notificationID uint) error
+	MarkAllRead(ctx context.Context, userID uint) (int64, error)
+
+	// RunScheduler produces the time-based notifications, such as expiring packages, every
+	// interval until ctx is done. Notifications are deduplicated, so every instance can
+	// run it.
+	RunScheduler(ctx context.Context, interval time.Duration)
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	businessRepo     repository.BusinessRepository
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository, businessRepo repository.BusinessRepository) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		businessRepo:     businessRepo,
+	}
+}
+
+func (s *notificationService) Notify(ctx context.Context, notification models.NewNotification) error {
+	notifications := make([]models.Notification, 0, len(notification.UserIDs))
+	seen := make(map[uint]bool, len(notification.UserIDs))
+	for _, userID := range notification.UserIDs {
+		if userID == 0 || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		notifications = append(notifications, models.Notification{
+			UserID:   userID,
+			Type:     notification.Type,
+			Title:    notification.Title,
+			Body:     notification.Body,
+			Payload:  notification.Payload,
+			DedupKey: notification.DedupKey,
+		})
+	}
+
+	if err := s.notificationRepo.CreateBatch(ctx, notifications); err != nil {
+		return fmt.Errorf("failed to save notifications: %w", err)
+	}
+	return nil
+}
+
+// NotifyBusiness notifies the owner account of a business
+func (s *notificationService) NotifyBusiness(ctx context.Context, businessID uint, notification models.NewNotification) error {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return fmt.Errorf("business not found")
+	}
+	notification.UserIDs = []uint{business.UserID}
+	return s.Notify(ctx, notification)
+}
+
+func (s *notificationService) GetNotifications(ctx context.Context, userID uint, filters repository.NotificationFilters) ([]models.NotificationResponse, int64, error) {
+	notifications, total, err := s.notificationRepo.GetByUser(ctx, userID, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	responses := make([]models.NotificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		responses = append(responses, toNotificationResponse(notification))
+	}
+	return responses, total, nil
+}
+
+func (s *notificationService) GetUnreadCount(ctx context.Context, userID uint) (int64, error) {
+	count, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+	return count, nil
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, userID, notificationID uint) error {
+	found, err := s.notificationRepo.MarkRead(ctx, userID, notificationID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, userID uint) (int64, error) {
+	count, err := s.notificationRepo.MarkAllRead(ctx, userID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return count, nil
+}
+
+func (s *notificationService) RunScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.notifyExpiringPackages(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			slog.Error("Failed to notify expiring packages", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// notifyExpiringPackages reminds businesses whose package runs out within one of the
+// packageExpiryNotices. Only the closest notice that applies is sent, so a business first
+// seen a day before expiry isn't also told it has a week left.
+func (s *notificationService) notifyExpiringPackages(ctx context.Context, now time.Time) error {
+	notified := make(map[uint]bool)
+	for _, days := range packageExpiryNotices {
+		businesses, err := s.businessRepo.GetBusinessesByPackageExpiry(ctx, now, days)
+		if err != nil {
+			return err
+		}
+
+		for _, business := range businesses {
+			if notified[business.ID] || business.PackageExpiresAt == nil {
+				continue
+			}
+			notified[business.ID] = true
+
+			// Days are counted in the business's zone, so a package running out tomorrow
+			// there has a day left whatever the hour
+			location := timezoneLocation(business.Timezone)
+			expiresAt := *business.PackageExpiresAt
+			daysLeft := max(daysBetween(now, expiresAt, location), 1)
+			expiresOn := expiresAt.In(location).Format(models.DateFormat)
+			packageName := "Your package"
+			if business.Package != nil {
+				packageName = fmt.Sprintf("Your %s package", business.Package.Name)
+			}
+
+			payload := models.JSONB{
+				"business_id": business.ID,
+				"expires_at":  expiresAt,
+				"days_left":   daysLeft,
+			}
+			if business.PackageID != nil {
+				payload["package_id"] = *business.PackageID
+			}
+
+			err := s.Notify(ctx, models.NewNotification{
+				UserIDs:  []uint{business.UserID},
+				Type:     models.NotificationPackageExpiring,
+				Title:    fmt.Sprintf("%s expires in %s", packageName, pluralDays(daysLeft)),
+				Body:     fmt.Sprintf("%s expires on %s. Renew it to keep using the service without interruption.", packageName, expiresOn),
+				Payload:  payload,
+				DedupKey: fmt.Sprintf("%s:%d:%s:%d", models.NotificationPackageExpiring, business.ID, expiresOn, days),
+			})
+			if err != nil {
+				logger.FromContext(ctx).Warn("Failed to notify expiring package", "business_id", business.ID, "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+func pluralDays(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+func toNotificationResponse(notification models.Notification) models.NotificationResponse {
+	return models.NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		Payload:   notification.Payload,
+		Read:      notification.ReadAt != nil,
+		ReadAt:    notification.ReadAt,
+		CreatedOn: notification.CreatedOn,
+	}
+}
@@ -3,40 +3,44 @@ package services
 import (
 	"backend/internal/models"
 	"backend/internal/repository"
+	"backend/pkg/cache"
+	"context"
 	"errors"
 	"fmt"
 )
 
 type PackageService interface {
-	CreatePackage(req models.CreatePackageRequest) (*models.PackageResponse, error)
-	GetPackages(filters repository.PackageFilters) ([]models.PackageResponse, int64, error)
-	GetPackageByID(id uint) (*models.PackageResponse, error)
-	UpdatePackage(id uint, updates map[string]interface{}) (*models.PackageResponse, error)
-	DeletePackage(id uint) error
-	GetActivePackages() ([]models.PackageResponse, error)
-	GetInactivePackages() ([]models.PackageResponse, error)
-	ChangePackageStatus(packageID uint, status int) error
-	PackageNameExists(name string, excludePackageID ...uint) (bool, error)
-	GetPackageStats() (map[string]interface{}, error)
-	GetPriceStatistics() (map[string]float64, error)
-	GetPackagesByPriceRange(minPrice, maxPrice float64) ([]models.PackageResponse, error)
-	BulkUpdatePackageStatus(packageIDs []uint, status int) error
-	SearchPackages(searchTerm string, limit int) ([]models.PackageResponse, error)
+	CreatePackage(ctx context.Context, req models.CreatePackageRequest) (*models.PackageResponse, error)
+	GetPackages(ctx context.Context, filters repository.PackageFilters) ([]models.PackageResponse, repository.PageInfo, error)
+	GetPackageByID(ctx context.Context, id uint) (*models.PackageResponse, error)
+	UpdatePackage(ctx context.Context, id uint, updates map[string]interface{}) (*models.PackageResponse, error)
+	DeletePackage(ctx context.Context, id uint) error
+	GetActivePackages(ctx context.Context) ([]models.PackageResponse, error)
+	GetInactivePackages(ctx context.Context) ([]models.PackageResponse, error)
+	ChangePackageStatus(ctx context.Context, packageID uint, status models.Status) error
+	PackageNameExists(ctx context.Context, name string, excludePackageID ...uint) (bool, error)
+	GetPackageStats(ctx context.Context) (map[string]interface{}, error)
+	GetPriceStatistics(ctx context.Context) (map[string]float64, error)
+	GetPackagesByPriceRange(ctx context.Context, minPrice, maxPrice float64) ([]models.PackageResponse, error)
+	BulkUpdatePackageStatus(ctx context.Context, packageIDs []uint, status models.Status, atomic bool) (*models.BulkUpdateResult, error)
+	SearchPackages(ctx context.Context, searchTerm string, limit int) ([]models.PackageResponse, error)
 }
 
 type packageService struct {
-	repo repository.PackageRepository
+	repo  repository.PackageRepository
+	cache cache.Cache
 }
 
-func NewPackageService(repo repository.PackageRepository) PackageService {
+func NewPackageService(repo repository.PackageRepository, cache cache.Cache) PackageService {
 	return &packageService{
-		repo: repo,
+		repo:  repo,
+		cache: cache,
 	}
 }
 
-func (s *packageService) CreatePackage(req models.CreatePackageRequest) (*models.PackageResponse, error) {
+func (s *packageService) CreatePackage(ctx context.Context, req models.CreatePackageRequest) (*models.PackageResponse, error) {
 	// Check if package name already exists
-	exists, err := s.repo.PackageNameExists(req.Name)
+	exists, err := s.repo.PackageNameExists(ctx, req.Name)
 	if err != nil {
 		return nil, fmt.Errorf("error checking package name existence: %w", err)
 	}
@@ -59,29 +63,24 @@ func (s *packageService) CreatePackage(req models.CreatePackageRequest) (*models
 		Price:            req.Price,
 		ValidationPeriod: req.ValidationPeriod,
 		Description:      req.Description,
-		Status:           1, // Active by default
+		Status:           models.StatusActive, // Active by default
 	}
 
-	if err := s.repo.Create(pkg); err != nil {
+	if err := s.repo.Create(ctx, pkg); err != nil {
 		return nil, fmt.Errorf("error creating package: %w", err)
 	}
+	s.invalidatePackages(ctx)
 
 	packageResponse := s.toPackageResponse(*pkg)
 	return &packageResponse, nil
 }
 
-func (s *packageService) GetPackages(filters repository.PackageFilters) ([]models.PackageResponse, int64, error) {
-	// Set default pagination if not provided
-	if filters.Limit <= 0 {
-		filters.Limit = 10
-	}
-	if filters.Page <= 0 {
-		filters.Page = 1
-	}
+func (s *packageService) GetPackages(ctx context.Context, filters repository.PackageFilters) ([]models.PackageResponse, repository.PageInfo, error) {
+	applyPageDefaults(&filters.Page, &filters.Limit)
 
-	packages, total, err := s.repo.GetAll(filters)
+	packages, total, err := s.repo.GetAll(ctx, filters)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error fetching packages: %w", err)
+		return nil, repository.PageInfo{}, fmt.Errorf("error fetching packages: %w", err)
 	}
 
 	var packageResponses []models.PackageResponse
@@ -89,15 +88,15 @@ func (s *packageService) GetPackages(filters repository.PackageFilters) ([]model
 		packageResponses = append(packageResponses, s.toPackageResponse(pkg))
 	}
 
-	return packageResponses, total, nil
+	return packageResponses, filters.PageInfo(total), nil
 }
 
-func (s *packageService) GetPackageByID(id uint) (*models.PackageResponse, error) {
+func (s *packageService) GetPackageByID(ctx context.Context, id uint) (*models.PackageResponse, error) {
 	if id == 0 {
 		return nil, errors.New("invalid package ID")
 	}
 
-	pkg, err := s.repo.GetByID(id)
+	pkg, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("package not found")
 	}
@@ -106,15 +105,21 @@ func (s *packageService) GetPackageByID(id uint) (*models.PackageResponse, error
 	return &packageResponse, nil
 }
 
-func (s *packageService) UpdatePackage(id uint, updates map[string]interface{}) (*models.PackageResponse, error) {
+func (s *packageService) UpdatePackage(ctx context.Context, id uint, updates map[string]interface{}) (*models.PackageResponse, error) {
 	if id == 0 {
 		return nil, errors.New("invalid package ID")
 	}
 
-	pkg, err := s.repo.GetByID(id)
+	pkg, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("package not found")
 	}
+	oldPrice := pkg.Price
+
+	version, versioned := expectedVersion(updates)
+	if versioned && version != pkg.Version {
+		return nil, &VersionConflictError{Current: s.toPackageResponse(*pkg)}
+	}
 
 	// Track if any updates were made
 	hasUpdates := false
@@ -122,7 +127,7 @@ func (s *packageService) UpdatePackage(id uint, updates map[string]interface{})
 	// Apply updates with validation
 	if name, ok := updates["name"].(string); ok && name != "" {
 		// Check if name already exists for another package
-		exists, err := s.repo.PackageNameExists(name, pkg.ID)
+		exists, err := s.repo.PackageNameExists(ctx, name, pkg.ID)
 		if err != nil {
 			return nil, fmt.Errorf("error checking package name existence: %w", err)
 		}
@@ -163,69 +168,82 @@ func (s *packageService) UpdatePackage(id uint, updates map[string]interface{})
 		hasUpdates = true
 	}
 
-	if status, ok := updates["status"].(int); ok {
-		if status < 0 || status > 1 {
-			return nil, errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
-		}
+	if status, ok, err := updatedStatus(updates); err != nil {
+		return nil, err
+	} else if ok {
 		pkg.Status = status
 		hasUpdates = true
 	}
 
-	// Handle float64 status (from JSON)
-	if status, ok := updates["status"].(float64); ok {
-		statusInt := int(status)
-		if statusInt < 0 || statusInt > 1 {
-			return nil, errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
-		}
-		pkg.Status = statusInt
-		hasUpdates = true
-	}
-
 	if !hasUpdates {
 		return nil, errors.New("no valid updates provided")
 	}
 
-	if err := s.repo.Update(pkg); err != nil {
+	if versioned {
+		err = s.repo.UpdateVersioned(ctx, pkg, version)
+	} else {
+		err = s.repo.Update(ctx, pkg)
+	}
+	if errors.Is(err, repository.ErrVersionConflict) {
+		if current, getErr := s.repo.GetByID(ctx, id); getErr == nil {
+			return nil, &VersionConflictError{Current: s.toPackageResponse(*current)}
+		}
+		return nil, err
+	}
+	if err != nil {
 		return nil, fmt.Errorf("error updating package: %w", err)
 	}
+	s.invalidatePackages(ctx)
 
 	packageResponse := s.toPackageResponse(*pkg)
+
+	// Businesses keep the price they were assigned on; the admin is told how many that is
+	if pkg.Price != oldPrice {
+		count, err := s.repo.CountActiveAssignmentsAtOtherPrice(ctx, pkg.ID, pkg.Price)
+		if err != nil {
+			return nil, fmt.Errorf("error counting package assignments: %w", err)
+		}
+		packageResponse.PriceChange = &models.PackagePriceChange{OldPrice: oldPrice, NewPrice: pkg.Price, ActiveAssignments: count}
+	}
 	return &packageResponse, nil
 }
 
-func (s *packageService) DeletePackage(id uint) error {
+func (s *packageService) DeletePackage(ctx context.Context, id uint) error {
 	if id == 0 {
 		return errors.New("invalid package ID")
 	}
 
-	_, err := s.repo.GetByID(id)
+	_, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return errors.New("package not found")
 	}
 
-	if err := s.repo.Delete(id); err != nil {
+	if err := s.repo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("error deleting package: %w", err)
 	}
+	s.invalidatePackages(ctx)
 
 	return nil
 }
 
-func (s *packageService) GetActivePackages() ([]models.PackageResponse, error) {
-	packages, err := s.repo.GetActivePackages()
-	if err != nil {
-		return nil, fmt.Errorf("error fetching active packages: %w", err)
-	}
+func (s *packageService) GetActivePackages(ctx context.Context) ([]models.PackageResponse, error) {
+	return readThrough(ctx, s.cache, activePackagesCacheKey, func() ([]models.PackageResponse, error) {
+		packages, err := s.repo.GetActivePackages(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching active packages: %w", err)
+		}
 
-	var packageResponses []models.PackageResponse
-	for _, pkg := range packages {
-		packageResponses = append(packageResponses, s.toPackageResponse(pkg))
-	}
+		var packageResponses []models.PackageResponse
+		for _, pkg := range packages {
+			packageResponses = append(packageResponses, s.toPackageResponse(pkg))
+		}
 
-	return packageResponses, nil
+		return packageResponses, nil
+	})
 }
 
-func (s *packageService) GetInactivePackages() ([]models.PackageResponse, error) {
-	packages, err := s.repo.GetInactivePackages()
+func (s *packageService) GetInactivePackages(ctx context.Context) ([]models.PackageResponse, error) {
+	packages, err := s.repo.GetInactivePackages(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching inactive packages: %w", err)
 	}
@@ -238,37 +256,38 @@ func (s *packageService) GetInactivePackages() ([]models.PackageResponse, error)
 	return packageResponses, nil
 }
 
-func (s *packageService) ChangePackageStatus(packageID uint, status int) error {
+func (s *packageService) ChangePackageStatus(ctx context.Context, packageID uint, status models.Status) error {
 	if packageID == 0 {
 		return errors.New("invalid package ID")
 	}
 
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
 	}
 
-	_, err := s.repo.GetByID(packageID)
+	_, err := s.repo.GetByID(ctx, packageID)
 	if err != nil {
 		return errors.New("package not found")
 	}
 
-	if err := s.repo.UpdatePackageStatus(packageID, status); err != nil {
+	if err := s.repo.UpdatePackageStatus(ctx, packageID, status); err != nil {
 		return fmt.Errorf("error updating package status: %w", err)
 	}
+	s.invalidatePackages(ctx)
 
 	return nil
 }
 
-func (s *packageService) PackageNameExists(name string, excludePackageID ...uint) (bool, error) {
+func (s *packageService) PackageNameExists(ctx context.Context, name string, excludePackageID ...uint) (bool, error) {
 	if name == "" {
 		return false, errors.New("package name cannot be empty")
 	}
 
-	return s.repo.PackageNameExists(name, excludePackageID...)
+	return s.repo.PackageNameExists(ctx, name, excludePackageID...)
 }
 
-func (s *packageService) GetPackageStats() (map[string]interface{}, error) {
-	stats, err := s.repo.GetPackageStats()
+func (s *packageService) GetPackageStats(ctx context.Context) (map[string]interface{}, error) {
+	stats, err := s.repo.GetPackageStats(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting package statistics: %w", err)
 	}
@@ -276,8 +295,8 @@ func (s *packageService) GetPackageStats() (map[string]interface{}, error) {
 	return stats, nil
 }
 
-func (s *packageService) GetPriceStatistics() (map[string]float64, error) {
-	stats, err := s.repo.GetPriceStatistics()
+func (s *packageService) GetPriceStatistics(ctx context.Context) (map[string]float64, error) {
+	stats, err := s.repo.GetPriceStatistics(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting price statistics: %w", err)
 	}
@@ -285,7 +304,7 @@ func (s *packageService) GetPriceStatistics() (map[string]float64, error) {
 	return stats, nil
 }
 
-func (s *packageService) GetPackagesByPriceRange(minPrice, maxPrice float64) ([]models.PackageResponse, error) {
+func (s *packageService) GetPackagesByPriceRange(ctx context.Context, minPrice, maxPrice float64) ([]models.PackageResponse, error) {
 	if minPrice < 0 || maxPrice < 0 {
 		return nil, errors.New("price values cannot be negative")
 	}
@@ -294,7 +313,7 @@ func (s *packageService) GetPackagesByPriceRange(minPrice, maxPrice float64) ([]
 		return nil, errors.New("minimum price cannot be greater than maximum price")
 	}
 
-	packages, err := s.repo.GetPackagesByPriceRange(minPrice, maxPrice)
+	packages, err := s.repo.GetPackagesByPriceRange(ctx, minPrice, maxPrice)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching packages by price range: %w", err)
 	}
@@ -307,32 +326,42 @@ func (s *packageService) GetPackagesByPriceRange(minPrice, maxPrice float64) ([]
 	return packageResponses, nil
 }
 
-func (s *packageService) BulkUpdatePackageStatus(packageIDs []uint, status int) error {
+// BulkUpdatePackageStatus changes the status of the listed packages, skipping IDs that
+// don't exist unless atomic is set
+func (s *packageService) BulkUpdatePackageStatus(ctx context.Context, packageIDs []uint, status models.Status, atomic bool) (*models.BulkUpdateResult, error) {
 	if len(packageIDs) == 0 {
-		return errors.New("no package IDs provided")
+		return nil, errors.New("no package IDs provided")
 	}
 
-	if status < 0 || status > 1 {
-		return errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
+	if !status.IsValid() {
+		return nil, errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
 	}
 
-	// Validate that all packages exist
-	for _, packageID := range packageIDs {
-		if _, err := s.repo.GetByID(packageID); err != nil {
-			return fmt.Errorf("package with ID %d not found", packageID)
-		}
+	packages, err := s.repo.GetByIDs(ctx, packageIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching packages: %w", err)
 	}
 
-	if err := s.repo.BulkUpdateStatus(packageIDs, status); err != nil {
-		return fmt.Errorf("error updating package statuses: %w", err)
+	found := make(map[uint]bool, len(packages))
+	for _, pkg := range packages {
+		found[pkg.ID] = true
+	}
+	result := newBulkResult(packageIDs, found, "package")
+	if ok, err := checkAtomic(result, atomic); !ok {
+		return result, err
 	}
 
-	return nil
+	if err := s.repo.BulkUpdateStatus(ctx, result.Updated, status); err != nil {
+		return nil, fmt.Errorf("error updating package statuses: %w", err)
+	}
+	s.invalidatePackages(ctx)
+
+	return result, nil
 }
 
 // Helper methods
 
-func (s *packageService) GetPackagesByValidationPeriod(minDays, maxDays int) ([]models.PackageResponse, error) {
+func (s *packageService) GetPackagesByValidationPeriod(ctx context.Context, minDays, maxDays int) ([]models.PackageResponse, error) {
 	if minDays < 0 || maxDays < 0 {
 		return nil, errors.New("validation period values cannot be negative")
 	}
@@ -341,7 +370,7 @@ func (s *packageService) GetPackagesByValidationPeriod(minDays, maxDays int) ([]
 		return nil, errors.New("minimum validation period cannot be greater than maximum validation period")
 	}
 
-	packages, err := s.repo.GetPackagesByValidationPeriod(minDays, maxDays)
+	packages, err := s.repo.GetPackagesByValidationPeriod(ctx, minDays, maxDays)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching packages by validation period: %w", err)
 	}
@@ -354,12 +383,12 @@ func (s *packageService) GetPackagesByValidationPeriod(minDays, maxDays int) ([]
 	return packageResponses, nil
 }
 
-func (s *packageService) SearchPackages(searchTerm string, limit int) ([]models.PackageResponse, error) {
+func (s *packageService) SearchPackages(ctx context.Context, searchTerm string, limit int) ([]models.PackageResponse, error) {
 	if searchTerm == "" {
 		return []models.PackageResponse{}, nil
 	}
 
-	packages, err := s.repo.SearchPackages(searchTerm, limit)
+	packages, err := s.repo.SearchPackages(ctx, searchTerm, limit)
 	if err != nil {
 		return nil, fmt.Errorf("error searching packages: %w", err)
 	}
@@ -372,8 +401,8 @@ func (s *packageService) SearchPackages(searchTerm string, limit int) ([]models.
 	return packageResponses, nil
 }
 
-func (s *packageService) GetRecentPackages(limit int) ([]models.PackageResponse, error) {
-	packages, err := s.repo.GetRecentPackages(limit)
+func (s *packageService) GetRecentPackages(ctx context.Context, limit int) ([]models.PackageResponse, error) {
+	packages, err := s.repo.GetRecentPackages(ctx, limit)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching recent packages: %w", err)
 	}
@@ -407,6 +436,12 @@ func (s *packageService) ValidatePackageData(req models.CreatePackageRequest) er
 	return nil
 }
 
+// invalidatePackages drops the cached active packages, and the cached businesses since
+// they embed their package, after a committed write
+func (s *packageService) invalidatePackages(ctx context.Context) {
+	invalidateCache(ctx, s.cache, []string{activePackagesCacheKey}, businessSlugCachePrefix)
+}
+
 func (s *packageService) toPackageResponse(pkg models.Package) models.PackageResponse {
 	return models.PackageResponse{
 		ID:               pkg.ID,
@@ -415,6 +450,8 @@ func (s *packageService) toPackageResponse(pkg models.Package) models.PackageRes
 		ValidationPeriod: pkg.ValidationPeriod,
 		Description:      pkg.Description,
 		Status:           pkg.Status,
+		Version:          pkg.Version,
 		CreatedOn:        pkg.CreatedOn,
+		UpdatedOn:        pkg.UpdatedOn,
 	}
 }
@@ -0,0 +1,36 @@
+package services
+
+// defaultPageLimit is the page size of a list request that doesn't give one
+const defaultPageLimit = 10
+
+// maxPageLimit caps the page size a list request may ask for
+const maxPageLimit = 100
+
+// maxRecentLimit caps how many of the latest records a recent-activity widget may ask for
+const maxRecentLimit = 50
+
+// recentLimit keeps a recent-activity limit between the default and maxRecentLimit
+func recentLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	if limit > maxRecentLimit {
+		return maxRecentLimit
+	}
+	return limit
+}
+
+// applyPageDefaults fills in the page and limit a list request left out or sent as zero
+// or less, and caps the limit at maxPageLimit. The filters are returned with the results,
+// so the pagination the client sees is the one the query used.
+func applyPageDefaults(page, limit *int) {
+	if *page <= 0 {
+		*page = 1
+	}
+	if *limit <= 0 {
+		*limit = defaultPageLimit
+	}
+	if *limit > maxPageLimit {
+		*limit = maxPageLimit
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import "testing"
+
+func TestApplyPageDefaults(t *testing.T) {
+	tests := []struct {
+		name      string
+		page      int
+		limit     int
+		wantPage  int
+		wantLimit int
+	}{
+		{"missing", 0, 0, 1, defaultPageLimit},
+		{"negative", -2, -5, 1, defaultPageLimit},
+		{"page only", 3, 0, 3, defaultPageLimit},
+		{"limit only", 0, 25, 1, 25},
+		{"both given", 4, 50, 4, 50},
+		{"at the maximum", 1, maxPageLimit, 1, maxPageLimit},
+		{"over the maximum", 1, maxPageLimit + 1, 1, maxPageLimit},
+		{"far over the maximum", 2, 100000, 2, maxPageLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, limit := tt.page, tt.limit
+			applyPageDefaults(&page, &limit)
+			if page != tt.wantPage || limit != tt.wantLimit {
+				t.Errorf("applyPageDefaults(%d, %d) = %d, %d, want %d, %d",
+					tt.page, tt.limit, page, limit, tt.wantPage, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestRecentLimit(t *testing.T) {
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{0, defaultPageLimit},
+		{-1, defaultPageLimit},
+		{20, 20},
+		{maxRecentLimit, maxRecentLimit},
+		{maxRecentLimit + 1, maxRecentLimit},
+	}
+	for _, tt := range tests {
+		if got := recentLimit(tt.limit); got != tt.want {
+			t.Errorf("recentLimit(%d) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
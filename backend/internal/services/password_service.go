@@ -0,0 +1,165 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/logger"
+	"backend/pkg/mailer"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrPasswordTokenInvalid is returned for unknown, expired and used password tokens alike
+var ErrPasswordTokenInvalid = errors.New("password link is invalid or has expired")
+
+// PasswordLinkConfig controls the password links emailed to users
+type PasswordLinkConfig struct {
+	AppName    string
+	BaseURL    string        // frontend URL; links point to <BaseURL>/reset-password?token=...
+	ResetTTL   time.Duration // lifetime of forgot-password links
+	WelcomeTTL time.Duration // lifetime of the set-password links of new business owners
+}
+
+type PasswordService interface {
+	// RequestReset emails a reset link to the active user with email. It succeeds whether
+	// or not the account exists, so the endpoint can't be used to find out who has one.
+	RequestReset(ctx context.Context, email string) error
+	// ResetPassword sets the password of the user the token was issued to and uses up
+	// every outstanding link of that user
+	ResetPassword(ctx context.Context, req models.ResetPasswordRequest) error
+	// SendWelcome emails a new business owner a link to set their password
+	SendWelcome(ctx context.Context, user models.User, businessName string) error
+}
+
+type passwordService struct {
+	userRepo  repository.UserRepository
+	tokenRepo repository.PasswordTokenRepository
+	mailer    mailer.Mailer
+	config    PasswordLinkConfig
+}
+
+func NewPasswordService(userRepo repository.UserRepository, tokenRepo repository.PasswordTokenRepository, m mailer.Mailer, config PasswordLinkConfig) PasswordService {
+	return &passwordService{
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		mailer:    m,
+		config:    config,
+	}
+}
+
+func (s *passwordService) RequestReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetActiveByEmail(ctx, email)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			logger.FromContext(ctx).Info("Password reset requested for unknown or inactive email")
+			return nil
+		}
+		return fmt.Errorf("error fetching user: %w", err)
+	}
+
+	if err := s.sendLink(ctx, *user, models.PasswordTokenReset, "password_reset", s.config.ResetTTL, nil); err != nil {
+		// Failing here would tell the caller the account exists
+		logger.FromContext(ctx).Error("Error sending password reset email", "user_id", user.ID, "error", err)
+	}
+	return nil
+}
+
+func (s *passwordService) ResetPassword(ctx context.Context, req models.ResetPasswordRequest) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %w", err)
+	}
+
+	userID, err := s.tokenRepo.Consume(ctx, hashToken(req.Token), string(hashedPassword), time.Now())
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return ErrPasswordTokenInvalid
+		}
+		return fmt.Errorf("error resetting password: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("Password reset", "user_id", userID)
+	return nil
+}
+
+func (s *passwordService) SendWelcome(ctx context.Context, user models.User, businessName string) error {
+	return s.sendLink(ctx, user, models.PasswordTokenWelcome, "welcome_business", s.config.WelcomeTTL, map[string]interface{}{
+		"BusinessName": businessName,
+	})
+}
+
+// sendLink issues a token for user and queues the named email template with a link to it
+func (s *passwordService) sendLink(ctx context.Context, user models.User, purpose, template string, ttl time.Duration, extra map[string]interface{}) error {
+	token, err := generatePasswordToken()
+	if err != nil {
+		return fmt.Errorf("error generating token: %w", err)
+	}
+
+	record := &models.PasswordToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		Purpose:   purpose,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.tokenRepo.Create(ctx, record); err != nil {
+		return fmt.Errorf("error saving token: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"AppName":   s.config.AppName,
+		"Name":      user.Name,
+		"Email":     user.Email,
+		"Link":      strings.TrimRight(s.config.BaseURL, "/") + "/reset-password?token=" + url.QueryEscape(token),
+		"ExpiresIn": formatTTL(ttl),
+	}
+	for key, value := range extra {
+		data[key] = value
+	}
+
+	msg, err := mailer.Render(template, user.Email, data)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, msg)
+}
+
+// generatePasswordToken returns 32 random bytes, hex encoded; only its hash is stored
+func generatePasswordToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// formatTTL renders ttl for humans, e.g. "1 hour" or "3 days"
+func formatTTL(ttl time.Duration) string {
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s", unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	switch {
+	case ttl >= 24*time.Hour && ttl%(24*time.Hour) == 0:
+		return plural(int(ttl/(24*time.Hour)), "day")
+	case ttl >= time.Hour:
+		return plural(int(ttl/time.Hour), "hour")
+	default:
+		return plural(int(ttl/time.Minute), "minute")
+	}
+}
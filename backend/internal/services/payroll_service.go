@@ -0,0 +1,437 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrPayrollFinalized is returned for changes to a month whose payroll was finalized
+var ErrPayrollFinalized = errors.New("payroll for this month is finalized")
+
+type PayrollService interface {
+	// Monthly payroll
+	GetPayroll(ctx context.Context, businessID uint, month string) (*models.PayrollSummary, error)
+	// FinalizePayroll records the month's payroll as it stands, so later changes to
+	// salaries, attendance or adjustments no longer change it
+	FinalizePayroll(ctx context.Context, businessID uint, month string, actorID uint) (*models.PayrollSummary, error)
+
+	// Adjustments
+	CreateAdjustment(ctx context.Context, businessID uint, req models.CreatePayrollAdjustmentRequest, actorID uint) (*models.PayrollAdjustmentResponse, error)
+	GetAdjustments(ctx context.Context, businessID uint, month string) ([]models.PayrollAdjustmentResponse, error)
+	DeleteAdjustment(ctx context.Context, businessID, adjustmentID uint) error
+
+	// Settings
+	GetSettings(ctx context.Context, businessID uint) (*models.PayrollSettings, error)
+	UpdateSettings(ctx context.Context, businessID uint, workingDays int) (*models.PayrollSettings, error)
+
+	// Access control
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+}
+
+type payrollService struct {
+	payrollRepo    repository.PayrollRepository
+	teacherRepo    repository.TeacherRepository
+	attendanceRepo repository.TeacherAttendanceRepository
+	businessRepo   repository.BusinessRepository
+}
+
+func NewPayrollService(payrollRepo repository.PayrollRepository, teacherRepo repository.TeacherRepository, attendanceRepo repository.TeacherAttendanceRepository, businessRepo repository.BusinessRepository) PayrollService {
+	return &payrollService{
+		payrollRepo:    payrollRepo,
+		teacherRepo:    teacherRepo,
+		attendanceRepo: attendanceRepo,
+		businessRepo:   businessRepo,
+	}
+}
+
+func (s *payrollService) GetPayroll(ctx context.Context, businessID uint, month string) (*models.PayrollSummary, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	start, end, err := parseAttendanceMonth(month, todayIn(business.Timezone))
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.payrollRepo.GetRun(ctx, businessID, start.Format("2006-01"))
+	if err == nil {
+		return toPayrollSummary(run, end), nil
+	}
+	if !repository.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get payroll: %v", err)
+	}
+
+	run, err = s.calculate(ctx, business, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return toPayrollSummary(run, end), nil
+}
+
+func (s *payrollService) FinalizePayroll(ctx context.Context, businessID uint, month string, actorID uint) (*models.PayrollSummary, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	today := todayIn(business.Timezone)
+	start, end, err := parseAttendanceMonth(month, today)
+	if err != nil {
+		return nil, err
+	}
+	if start.After(today) {
+		return nil, fmt.Errorf("month has not started yet")
+	}
+
+	tx := s.payrollRepo.BeginTransaction(ctx)
+
+	if err := s.lockMonth(tx, businessID, start.Format("2006-01")); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Adjustments can't change while the lock is held, so the run is what the month
+	// looked like when it was finalized
+	run, err := s.calculate(ctx, business, start, end)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	run.FinalizedBy = actorID
+	run.FinalizedAt = time.Now()
+
+	if err := s.payrollRepo.CreateRunWithTransaction(tx, run); err != nil {
+		tx.Rollback()
+		if _, ok := repository.UniqueViolation(err); ok {
+			return nil, ErrPayrollFinalized
+		}
+		return nil, fmt.Errorf("failed to finalize payroll: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit payroll: %v", err)
+	}
+
+	return toPayrollSummary(run, end), nil
+}
+
+func (s *payrollService) CreateAdjustment(ctx context.Context, businessID uint, req models.CreatePayrollAdjustmentRequest, actorID uint) (*models.PayrollAdjustmentResponse, error) {
+	start, _, err := parseAttendanceMonth(req.Month, businessToday(ctx, s.businessRepo, businessID))
+	if err != nil {
+		return nil, err
+	}
+
+	amount := roundMoney(req.Amount)
+	if amount == 0 {
+		return nil, fmt.Errorf("amount must not be zero")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	teacher, err := s.teacherRepo.GetByID(ctx, req.TeacherID)
+	if err != nil || teacher.BusinessID != businessID {
+		return nil, fmt.Errorf("teacher not found in this business")
+	}
+
+	adjustment := &models.PayrollAdjustment{
+		BusinessID: businessID,
+		TeacherID:  teacher.ID,
+		Month:      start.Format("2006-01"),
+		Amount:     amount,
+		Reason:     reason,
+		CreatedBy:  actorID,
+	}
+
+	tx := s.payrollRepo.BeginTransaction(ctx)
+
+	if err := s.lockMonth(tx, businessID, adjustment.Month); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.payrollRepo.CreateAdjustmentWithTransaction(tx, adjustment); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create adjustment: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit adjustment: %v", err)
+	}
+
+	adjustment.Teacher = *teacher
+	response := toPayrollAdjustmentResponse(*adjustment)
+	return &response, nil
+}
+
+func (s *payrollService) GetAdjustments(ctx context.Context, businessID uint, month string) ([]models.PayrollAdjustmentResponse, error) {
+	start, _, err := parseAttendanceMonth(month, businessToday(ctx, s.businessRepo, businessID))
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments, err := s.payrollRepo.GetAdjustments(ctx, businessID, start.Format("2006-01"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get adjustments: %v", err)
+	}
+
+	responses := []models.PayrollAdjustmentResponse{}
+	for _, adjustment := range adjustments {
+		responses = append(responses, toPayrollAdjustmentResponse(adjustment))
+	}
+	return responses, nil
+}
+
+func (s *payrollService) DeleteAdjustment(ctx context.Context, businessID, adjustmentID uint) error {
+	adjustment, err := s.payrollRepo.GetAdjustmentByID(ctx, adjustmentID)
+	if err != nil || adjustment.BusinessID != businessID {
+		return fmt.Errorf("adjustment not found")
+	}
+
+	tx := s.payrollRepo.BeginTransaction(ctx)
+
+	if err := s.lockMonth(tx, businessID, adjustment.Month); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.payrollRepo.DeleteAdjustmentWithTransaction(tx, adjustment.ID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete adjustment: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit adjustment: %v", err)
+	}
+	return nil
+}
+
+func (s *payrollService) GetSettings(ctx context.Context, businessID uint) (*models.PayrollSettings, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+	return &models.PayrollSettings{BusinessID: business.ID, WorkingDays: business.PayrollWorkingDays}, nil
+}
+
+func (s *payrollService) UpdateSettings(ctx context.Context, businessID uint, workingDays int) (*models.PayrollSettings, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	if err := s.businessRepo.UpdateFields(ctx, business.ID, map[string]interface{}{"payroll_working_days": workingDays}); err != nil {
+		return nil, fmt.Errorf("failed to update settings: %w", err)
+	}
+	return &models.PayrollSettings{BusinessID: business.ID, WorkingDays: workingDays}, nil
+}
+
+func (s *payrollService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
+
+// lockMonth takes the business's payroll lock for the rest of the transaction and fails
+// when the month is already finalized
+func (s *payrollService) lockMonth(tx *gorm.DB, businessID uint, month string) error {
+	if err := s.payrollRepo.LockBusinessWithTransaction(tx, businessID); err != nil {
+		return fmt.Errorf("failed to lock payroll: %v", err)
+	}
+
+	_, err := s.payrollRepo.GetRunWithTransaction(tx, businessID, month)
+	if err == nil {
+		return ErrPayrollFinalized
+	}
+	if !repository.IsNotFound(err) {
+		return fmt.Errorf("failed to get payroll: %v", err)
+	}
+	return nil
+}
+
+// calculate works out the month's payroll from the current salaries, attendance and
+// adjustments. Active teachers are paid their salary for the days since they joined, less
+// a day's rate for every day marked absent; leave is paid. Teachers who are no longer
+// active are listed only when they have adjustments, and are paid just those.
+//
+// The two use different days on purpose. Joining mid-month prorates over the calendar
+// days of the month, as which of them the business works isn't known, while the daily
+// rate divides by the working days setting, so an absence costs a working day's pay.
+// Without the setting both use the calendar days. The deduction is capped at the prorated
+// salary, so a teacher absent on every working day is paid nothing but adjustments.
+func (s *payrollService) calculate(ctx context.Context, business *models.Business, start, end time.Time) (*models.PayrollRun, error) {
+	month := start.Format("2006-01")
+
+	teachers, err := s.teacherRepo.GetActiveTeachersByBusiness(ctx, business.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teachers: %v", err)
+	}
+
+	adjustments, err := s.payrollRepo.GetAdjustments(ctx, business.ID, month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get adjustments: %v", err)
+	}
+
+	counts, err := s.attendanceRepo.GetStatusCounts(ctx, repository.TeacherAttendanceFilters{
+		BusinessID: &business.ID,
+		Status:     models.AttendanceAbsent,
+		From:       start.Format(models.DateFormat),
+		To:         end.Format(models.DateFormat),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance: %v", err)
+	}
+
+	absences := make(map[uint]int64, len(counts))
+	for _, count := range counts {
+		absences[count.TeacherID] += count.Count
+	}
+
+	adjusted := make(map[uint]float64)
+	var others []models.Teacher
+	active := make(map[uint]bool, len(teachers))
+	for _, teacher := range teachers {
+		active[teacher.ID] = true
+	}
+	for _, adjustment := range adjustments {
+		if _, ok := adjusted[adjustment.TeacherID]; !ok && !active[adjustment.TeacherID] {
+			others = append(others, adjustment.Teacher)
+		}
+		adjusted[adjustment.TeacherID] += adjustment.Amount
+	}
+
+	workingDays := business.PayrollWorkingDays
+	if workingDays <= 0 {
+		workingDays = end.Day()
+	}
+
+	run := &models.PayrollRun{
+		BusinessID:  business.ID,
+		Month:       month,
+		WorkingDays: workingDays,
+		Lines:       []models.PayrollLine{},
+	}
+	for _, teacher := range teachers {
+		line := payrollLine(teacher, start, end)
+		_, hasAdjustments := adjusted[teacher.ID]
+		if line.PaidDays == 0 && !hasAdjustments {
+			continue // joined after the month
+		}
+		if line.PaidDays > 0 {
+			line.AbsentDays = absences[teacher.ID]
+			line.DailyRate = roundMoney(teacher.Salary / float64(workingDays))
+			line.AbsenceDeduction = math.Min(roundMoney(line.DailyRate*float64(line.AbsentDays)), line.ProratedSalary)
+		}
+		line.Adjustments = roundMoney(adjusted[teacher.ID])
+		line.NetPay = roundMoney(line.ProratedSalary - line.AbsenceDeduction + line.Adjustments)
+		run.Lines = append(run.Lines, line)
+	}
+	for _, teacher := range others {
+		line := models.PayrollLine{
+			TeacherID:   teacher.ID,
+			TeacherName: teacher.Name,
+			JoinedOn:    joinedOn(teacher),
+			Adjustments: roundMoney(adjusted[teacher.ID]),
+		}
+		line.NetPay = line.Adjustments
+		run.Lines = append(run.Lines, line)
+	}
+
+	sort.SliceStable(run.Lines, func(i, j int) bool {
+		if run.Lines[i].TeacherName != run.Lines[j].TeacherName {
+			return run.Lines[i].TeacherName < run.Lines[j].TeacherName
+		}
+		return run.Lines[i].TeacherID < run.Lines[j].TeacherID
+	})
+	return run, nil
+}
+
+// payrollLine is a teacher's salary for the calendar days of the month since they
+// joined; the salary isn't paid at all when they joined after the month
+func payrollLine(teacher models.Teacher, start, end time.Time) models.PayrollLine {
+	line := models.PayrollLine{
+		TeacherID:   teacher.ID,
+		TeacherName: teacher.Name,
+		JoinedOn:    joinedOn(teacher),
+	}
+
+	from := start
+	if line.JoinedOn.After(start) {
+		from = line.JoinedOn
+	}
+	if from.After(end) {
+		return line
+	}
+
+	line.BaseSalary = roundMoney(teacher.Salary)
+	line.PaidDays = int(end.Sub(from).Hours()/24) + 1
+	line.ProratedSalary = roundMoney(teacher.Salary * float64(line.PaidDays) / float64(end.Day()))
+	return line
+}
+
+// joinedOn is the day a teacher joined their business: when they were added, or when
+// they were transferred in
+func joinedOn(teacher models.Teacher) time.Time {
+	joined := teacher.CreatedOn
+	if teacher.TransferredAt != nil && teacher.TransferredAt.After(joined) {
+		joined = *teacher.TransferredAt
+	}
+	return time.Date(joined.Year(), joined.Month(), joined.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func toPayrollSummary(run *models.PayrollRun, end time.Time) *models.PayrollSummary {
+	summary := &models.PayrollSummary{
+		BusinessID:  run.BusinessID,
+		Month:       run.Month,
+		DaysInMonth: end.Day(),
+		WorkingDays: run.WorkingDays,
+		Lines:       run.Lines,
+	}
+	if run.ID != 0 {
+		summary.Finalized = true
+		summary.FinalizedAt = &run.FinalizedAt
+		summary.FinalizedBy = &run.FinalizedBy
+	}
+	if summary.Lines == nil {
+		summary.Lines = []models.PayrollLine{}
+	}
+
+	for _, line := range summary.Lines {
+		summary.Totals.BaseSalary += line.BaseSalary
+		summary.Totals.ProratedSalary += line.ProratedSalary
+		summary.Totals.AbsenceDeduction += line.AbsenceDeduction
+		summary.Totals.Adjustments += line.Adjustments
+		summary.Totals.NetPay += line.NetPay
+	}
+	summary.Totals.BaseSalary = roundMoney(summary.Totals.BaseSalary)
+	summary.Totals.ProratedSalary = roundMoney(summary.Totals.ProratedSalary)
+	summary.Totals.AbsenceDeduction = roundMoney(summary.Totals.AbsenceDeduction)
+	summary.Totals.Adjustments = roundMoney(summary.Totals.Adjustments)
+	summary.Totals.NetPay = roundMoney(summary.Totals.NetPay)
+
+	return summary
+}
+
+func toPayrollAdjustmentResponse(adjustment models.PayrollAdjustment) models.PayrollAdjustmentResponse {
+	return models.PayrollAdjustmentResponse{
+		ID:          adjustment.ID,
+		BusinessID:  adjustment.BusinessID,
+		TeacherID:   adjustment.TeacherID,
+		TeacherName: adjustment.Teacher.Name,
+		Month:       adjustment.Month,
+		Amount:      adjustment.Amount,
+		Reason:      adjustment.Reason,
+		CreatedBy:   adjustment.CreatedBy,
+		CreatedOn:   adjustment.CreatedOn,
+	}
+}
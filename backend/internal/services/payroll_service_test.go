@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/testutil"
+)
+
+func TestPayrollLine(t *testing.T) {
+	// April 2025 has 30 days
+	start := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 9, 30, 0, 0, time.UTC) }
+	at := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name        string
+		createdOn   time.Time
+		transferred *time.Time
+		paidDays    int
+		prorated    float64
+	}{
+		{"joined before the month", day(1, 10), nil, 30, 30000},
+		{"joined on the first", day(4, 1), nil, 30, 30000},
+		{"joined mid-month", day(4, 16), nil, 15, 15000},
+		{"joined on the last day", day(4, 30), nil, 1, 1000},
+		{"joined after the month", day(5, 1), nil, 0, 0},
+		{"transferred in mid-month", day(1, 10), at(day(4, 21)), 10, 10000},
+		{"transferred in before the month", day(3, 1), at(day(3, 15)), 30, 30000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teacher := models.Teacher{ID: 1, Name: "Asha", Salary: 30000, CreatedOn: tt.createdOn, TransferredAt: tt.transferred}
+			line := payrollLine(teacher, start, end)
+
+			if line.PaidDays != tt.paidDays {
+				t.Errorf("PaidDays = %d, want %d", line.PaidDays, tt.paidDays)
+			}
+			if line.ProratedSalary != tt.prorated {
+				t.Errorf("ProratedSalary = %v, want %v", line.ProratedSalary, tt.prorated)
+			}
+			wantBase := 30000.0
+			if tt.paidDays == 0 {
+				wantBase = 0
+			}
+			if line.BaseSalary != wantBase {
+				t.Errorf("BaseSalary = %v, want %v", line.BaseSalary, wantBase)
+			}
+		})
+	}
+}
+
+// Asha works all of April 2025 and Bilal joins on the 16th. Joining prorates over the 30
+// calendar days whatever the working days setting, while absences cost the salary divided
+// by the setting.
+func TestPayrollCalculationAndFinalizing(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	ctx := context.Background()
+	businessRepo := repository.NewBusinessRepository(db)
+	service := NewPayrollService(repository.NewPayrollRepository(db), repository.NewTeacherRepository(db),
+		repository.NewTeacherAttendanceRepository(db), businessRepo)
+
+	sunrise := f.Businesses["Sunrise Academy"]
+	asha, bilal := f.Teachers["Asha"], f.Teachers["Bilal"]
+	setJoined := func(teacher models.Teacher, joined time.Time) {
+		if err := db.Model(&models.Teacher{}).Where("id = ?", teacher.ID).Update("created_on", joined).Error; err != nil {
+			t.Fatalf("failed to set when %s joined: %v", teacher.Name, err)
+		}
+	}
+	setJoined(asha, time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC))
+	setJoined(bilal, time.Date(2025, 4, 16, 0, 0, 0, 0, time.UTC))
+
+	for _, mark := range []struct {
+		teacher models.Teacher
+		day     int
+		status  string
+	}{
+		{asha, 7, models.AttendanceAbsent},
+		{asha, 8, models.AttendanceAbsent},
+		{asha, 9, models.AttendanceLeave},
+		{bilal, 22, models.AttendanceAbsent},
+	} {
+		attendance := models.TeacherAttendance{
+			TeacherID:  mark.teacher.ID,
+			BusinessID: sunrise.ID,
+			Date:       time.Date(2025, 4, mark.day, 0, 0, 0, 0, time.UTC),
+			Status:     mark.status,
+			MarkedBy:   f.Admin.ID,
+		}
+		if err := db.Omit("Teacher").Create(&attendance).Error; err != nil {
+			t.Fatalf("failed to mark attendance: %v", err)
+		}
+	}
+
+	type want struct {
+		paidDays  int
+		prorated  float64
+		dailyRate float64
+		deduction float64
+		net       float64
+	}
+	check := func(t *testing.T, summary *models.PayrollSummary, lines map[string]want) {
+		t.Helper()
+		if len(summary.Lines) != len(lines) {
+			t.Fatalf("got %d lines, want %d: %+v", len(summary.Lines), len(lines), summary.Lines)
+		}
+		for _, line := range summary.Lines {
+			w, ok := lines[line.TeacherName]
+			if !ok {
+				t.Errorf("unexpected line for %s", line.TeacherName)
+				continue
+			}
+			got := want{line.PaidDays, line.ProratedSalary, line.DailyRate, line.AbsenceDeduction, line.NetPay}
+			if got != w {
+				t.Errorf("%s: got %+v, want %+v", line.TeacherName, got, w)
+			}
+		}
+	}
+
+	t.Run("calendar days without a setting", func(t *testing.T) {
+		summary, err := service.GetPayroll(ctx, sunrise.ID, "2025-04")
+		if err != nil {
+			t.Fatalf("GetPayroll() error = %v", err)
+		}
+		if summary.DaysInMonth != 30 || summary.WorkingDays != 30 {
+			t.Errorf("days = %d, working days = %d, want 30 and 30", summary.DaysInMonth, summary.WorkingDays)
+		}
+		check(t, summary, map[string]want{
+			"Asha":  {30, 30000, 1000, 2000, 28000},
+			"Bilal": {15, 22500, 1500, 1500, 21000},
+		})
+	})
+
+	if _, err := service.UpdateSettings(ctx, sunrise.ID, 25); err != nil {
+		t.Fatalf("UpdateSettings() error = %v", err)
+	}
+	adjustment := models.CreatePayrollAdjustmentRequest{TeacherID: bilal.ID, Month: "2025-04", Amount: 500, Reason: "Joining bonus"}
+	if _, err := service.CreateAdjustment(ctx, sunrise.ID, adjustment, f.Admin.ID); err != nil {
+		t.Fatalf("CreateAdjustment() error = %v", err)
+	}
+
+	t.Run("working days setting", func(t *testing.T) {
+		summary, err := service.GetPayroll(ctx, sunrise.ID, "2025-04")
+		if err != nil {
+			t.Fatalf("GetPayroll() error = %v", err)
+		}
+		if summary.WorkingDays != 25 {
+			t.Errorf("WorkingDays = %d, want 25", summary.WorkingDays)
+		}
+		check(t, summary, map[string]want{
+			"Asha":  {30, 30000, 1200, 2400, 27600},
+			"Bilal": {15, 22500, 1800, 1800, 21200},
+		})
+		if summary.Totals.NetPay != 48800 {
+			t.Errorf("Totals.NetPay = %v, want 48800", summary.Totals.NetPay)
+		}
+	})
+
+	t.Run("finalizing freezes the month", func(t *testing.T) {
+		finalized, err := service.FinalizePayroll(ctx, sunrise.ID, "2025-04", f.Admin.ID)
+		if err != nil {
+			t.Fatalf("FinalizePayroll() error = %v", err)
+		}
+		if !finalized.Finalized || finalized.FinalizedBy == nil || *finalized.FinalizedBy != f.Admin.ID {
+			t.Errorf("summary not marked finalized by the admin: %+v", finalized)
+		}
+
+		// Neither a raise nor a new setting changes a finalized month
+		if err := db.Model(&models.Teacher{}).Where("id = ?", asha.ID).Update("salary", 60000).Error; err != nil {
+			t.Fatalf("failed to raise salary: %v", err)
+		}
+		if _, err := service.UpdateSettings(ctx, sunrise.ID, 0); err != nil {
+			t.Fatalf("UpdateSettings() error = %v", err)
+		}
+		summary, err := service.GetPayroll(ctx, sunrise.ID, "2025-04")
+		if err != nil {
+			t.Fatalf("GetPayroll() error = %v", err)
+		}
+		if !summary.Finalized || summary.WorkingDays != 25 {
+			t.Errorf("Finalized = %v, WorkingDays = %d, want true and 25", summary.Finalized, summary.WorkingDays)
+		}
+		check(t, summary, map[string]want{
+			"Asha":  {30, 30000, 1200, 2400, 27600},
+			"Bilal": {15, 22500, 1800, 1800, 21200},
+		})
+
+		if _, err := service.CreateAdjustment(ctx, sunrise.ID, adjustment, f.Admin.ID); !errors.Is(err, ErrPayrollFinalized) {
+			t.Errorf("CreateAdjustment() error = %v, want ErrPayrollFinalized", err)
+		}
+		if _, err := service.FinalizePayroll(ctx, sunrise.ID, "2025-04", f.Admin.ID); !errors.Is(err, ErrPayrollFinalized) {
+			t.Errorf("FinalizePayroll() again error = %v, want ErrPayrollFinalized", err)
+		}
+	})
+
+	// The settings update wrote only its column
+	business, err := businessRepo.GetByID(ctx, sunrise.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if business.Name != sunrise.Name || business.PayrollWorkingDays != 0 {
+		t.Errorf("business = %q with %d working days, want %q with 0", business.Name, business.PayrollWorkingDays, sunrise.Name)
+	}
+}
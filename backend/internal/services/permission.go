@@ -0,0 +1,189 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Permission is something a role may do. Route guards and the permissions discovery
+// endpoint both read rolePermissions, so a change here changes both.
+type Permission string
+
+const (
+	PermManageUsers               Permission = "manage_users"
+	PermManagePackages            Permission = "manage_packages"
+	PermViewPackages              Permission = "view_packages"
+	PermManageBusinesses          Permission = "manage_businesses"
+	PermManageBusinessArchives    Permission = "manage_business_archives"
+	PermManageBusinessSignups     Permission = "manage_business_signups"
+	PermViewAdminStats            Permission = "view_admin_stats"
+	PermViewAuditLogs             Permission = "view_audit_logs"
+	PermManageMaintenance         Permission = "manage_maintenance"
+	PermManageDataConsistency     Permission = "manage_data_consistency"
+	PermManageSnapshots           Permission = "manage_snapshots"
+	PermSendReports               Permission = "send_reports"
+	PermManageAllStudents         Permission = "manage_all_students"
+	PermManageAllTeachers         Permission = "manage_all_teachers"
+	PermManageOwnBusiness         Permission = "manage_own_business"
+	PermManageBranding            Permission = "manage_branding"
+	PermManageCalendarFeed        Permission = "manage_calendar_feed"
+	PermManageStudentFields       Permission = "manage_student_fields"
+	PermManageStudents            Permission = "manage_students"
+	PermManageTeachers            Permission = "manage_teachers"
+	PermManageSubjects            Permission = "manage_subjects"
+	PermManageQualifications      Permission = "manage_qualifications"
+	PermManageBatches             Permission = "manage_batches"
+	PermManageAttendance          Permission = "manage_attendance"
+	PermManageFees                Permission = "manage_fees"
+	PermManagePayroll             Permission = "manage_payroll"
+	PermManageExams               Permission = "manage_exams"
+	PermManageAnnouncements       Permission = "manage_announcements"
+	PermManageGuardianLinks       Permission = "manage_guardian_links"
+	PermManageSMS                 Permission = "manage_sms"
+	PermManageReportSubscriptions Permission = "manage_report_subscriptions"
+	PermPrintDocuments            Permission = "print_documents"
+	PermViewTrends                Permission = "view_trends"
+	PermSearch                    Permission = "search"
+	PermReadAnnouncements         Permission = "read_announcements"
+	PermManageOwnTeacherProfile   Permission = "manage_own_teacher_profile"
+	PermManageOwnStudentProfile   Permission = "manage_own_student_profile"
+)
+
+var (
+	adminOnly        = []models.UserRole{models.RoleAdmin}
+	businessOnly     = []models.UserRole{models.RoleBusiness}
+	adminAndBusiness = []models.UserRole{models.RoleAdmin, models.RoleBusiness}
+	teachersOnly     = []models.UserRole{models.RoleTeacher}
+	studentsOnly     = []models.UserRole{models.RoleStudent}
+	teachersStudents = []models.UserRole{models.RoleStudent, models.RoleTeacher}
+)
+
+// rolePermissions is the single source of truth for which roles hold which permission
+var rolePermissions = map[Permission][]models.UserRole{
+	PermManageUsers:               adminOnly,
+	PermManagePackages:            adminOnly,
+	PermViewPackages:              adminAndBusiness,
+	PermManageBusinesses:          adminOnly,
+	PermManageBusinessArchives:    adminOnly,
+	PermManageBusinessSignups:     adminOnly,
+	PermViewAdminStats:            adminOnly,
+	PermViewAuditLogs:             adminOnly,
+	PermManageMaintenance:         adminOnly,
+	PermManageDataConsistency:     adminOnly,
+	PermManageSnapshots:           adminOnly,
+	PermSendReports:               adminOnly,
+	PermManageAllStudents:         adminOnly,
+	PermManageAllTeachers:         adminOnly,
+	PermManageOwnBusiness:         businessOnly,
+	PermManageBranding:            adminAndBusiness,
+	PermManageCalendarFeed:        businessOnly,
+	PermManageStudentFields:       businessOnly,
+	PermManageStudents:            adminAndBusiness,
+	PermManageTeachers:            adminAndBusiness,
+	PermManageSubjects:            adminAndBusiness,
+	PermManageQualifications:      adminOnly,
+	PermManageBatches:             adminAndBusiness,
+	PermManageAttendance:          adminAndBusiness,
+	PermManageFees:                adminAndBusiness,
+	PermManagePayroll:             adminAndBusiness,
+	PermManageExams:               adminAndBusiness,
+	PermManageAnnouncements:       adminAndBusiness,
+	PermManageGuardianLinks:       adminAndBusiness,
+	PermManageSMS:                 adminAndBusiness,
+	PermManageReportSubscriptions: adminAndBusiness,
+	PermPrintDocuments:            adminAndBusiness,
+	PermViewTrends:                adminAndBusiness,
+	PermSearch:                    adminAndBusiness,
+	PermReadAnnouncements:         teachersStudents,
+	PermManageOwnTeacherProfile:   teachersOnly,
+	PermManageOwnStudentProfile:   studentsOnly,
+}
+
+var permissionsByRole = rolesByPermission()
+
+// rolesByPermission inverts rolePermissions so lookups by role stay cheap
+func rolesByPermission() map[models.UserRole]map[Permission]bool {
+	byRole := make(map[models.UserRole]map[Permission]bool)
+	for permission, roles := range rolePermissions {
+		for _, role := range roles {
+			if byRole[role] == nil {
+				byRole[role] = make(map[Permission]bool)
+			}
+			byRole[role][permission] = true
+		}
+	}
+	return byRole
+}
+
+// RoleHasPermission reports whether a role holds a permission
+func RoleHasPermission(role string, permission Permission) bool {
+	return permissionsByRole[models.UserRole(role)][permission]
+}
+
+// Permissions lists every known permission, sorted by name
+func Permissions() []Permission {
+	permissions := make([]Permission, 0, len(rolePermissions))
+	for permission := range rolePermissions {
+		permissions = append(permissions, permission)
+	}
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i] < permissions[j] })
+	return permissions
+}
+
+type PermissionService interface {
+	GetMyPermissions(ctx context.Context, userID uint, role string) (*models.PermissionsResponse, error)
+}
+
+type permissionService struct {
+	businessRepo repository.BusinessRepository
+	teacherRepo  repository.TeacherRepository
+	studentRepo  repository.StudentRepository
+}
+
+func NewPermissionService(businessRepo repository.BusinessRepository, teacherRepo repository.TeacherRepository, studentRepo repository.StudentRepository) PermissionService {
+	return &permissionService{
+		businessRepo: businessRepo,
+		teacherRepo:  teacherRepo,
+		studentRepo:  studentRepo,
+	}
+}
+
+// GetMyPermissions resolves the caller's profile and lists every capability with
+// whether the caller's role holds it
+func (s *permissionService) GetMyPermissions(ctx context.Context, userID uint, role string) (*models.PermissionsResponse, error) {
+	response := &models.PermissionsResponse{
+		UserID:       userID,
+		Role:         role,
+		Capabilities: make(map[string]bool, len(rolePermissions)),
+	}
+
+	// A role without its profile yet still gets its capabilities
+	switch models.UserRole(role) {
+	case models.RoleBusiness:
+		if business, err := s.businessRepo.GetByUserID(ctx, userID); err == nil {
+			response.BusinessID = &business.ID
+		}
+	case models.RoleTeacher:
+		if teacher, err := s.teacherRepo.GetByUserID(ctx, userID); err == nil {
+			response.TeacherID = &teacher.ID
+			response.BusinessID = &teacher.BusinessID
+		}
+	case models.RoleStudent:
+		if student, err := s.studentRepo.GetByUserID(ctx, userID); err == nil {
+			response.StudentID = &student.ID
+			response.BusinessID = &student.BusinessID
+		}
+	case models.RoleAdmin:
+	default:
+		return nil, fmt.Errorf("unknown role %q", role)
+	}
+
+	for _, permission := range Permissions() {
+		response.Capabilities["can_"+string(permission)] = RoleHasPermission(role, permission)
+	}
+
+	return response, nil
+}
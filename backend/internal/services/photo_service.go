@@ -0,0 +1,235 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/logger"
+	"backend/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// MaxPhotoSize is the largest accepted student or teacher photo
+const MaxPhotoSize = 2 << 20 // 2 MB
+
+var photoRules = storage.UploadRules{
+	MaxSize:      MaxPhotoSize,
+	AllowedTypes: []string{"image/jpeg", "image/png", "image/webp"},
+}
+
+// photoURLPrefix is the API prefix photo URLs are built under
+const photoURLPrefix = "/api/v1"
+
+// PhotoService manages the photos a business keeps on its student and teacher records.
+// They belong to the business rather than the person, so only the business manages them.
+type PhotoService interface {
+	UploadStudentPhoto(ctx context.Context, studentID uint, file multipart.File, header *multipart.FileHeader) (*models.PhotoResponse, error)
+	DeleteStudentPhoto(ctx context.Context, studentID uint) error
+	GetStudentPhoto(ctx context.Context, studentID uint) (*models.Photo, error)
+	UploadTeacherPhoto(ctx context.Context, teacherID uint, file multipart.File, header *multipart.FileHeader) (*models.PhotoResponse, error)
+	DeleteTeacherPhoto(ctx context.Context, teacherID uint) error
+	GetTeacherPhoto(ctx context.Context, teacherID uint) (*models.Photo, error)
+	CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error
+}
+
+type photoService struct {
+	studentRepo  repository.StudentRepository
+	teacherRepo  repository.TeacherRepository
+	businessRepo repository.BusinessRepository
+	storage      storage.Storage
+}
+
+func NewPhotoService(studentRepo repository.StudentRepository, teacherRepo repository.TeacherRepository, businessRepo repository.BusinessRepository, store storage.Storage) PhotoService {
+	return &photoService{
+		studentRepo:  studentRepo,
+		teacherRepo:  teacherRepo,
+		businessRepo: businessRepo,
+		storage:      store,
+	}
+}
+
+func (s *photoService) UploadStudentPhoto(ctx context.Context, studentID uint, file multipart.File, header *multipart.FileHeader) (*models.PhotoResponse, error) {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, errors.New("student not found")
+	}
+
+	path := fmt.Sprintf("businesses/%d/students/%d/photo_%d_%s", student.BusinessID, student.ID, time.Now().UnixNano(), storage.SafeFileName(header.Filename))
+	if err := s.savePhoto(ctx, path, file, header, func() error {
+		return s.studentRepo.UpdatePhotoPath(ctx, student.ID, path)
+	}); err != nil {
+		return nil, err
+	}
+	if student.PhotoPath != "" {
+		s.removePhoto(ctx, student.PhotoPath)
+	}
+
+	student, err = s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, errors.New("student not found")
+	}
+	return &models.PhotoResponse{PhotoURL: studentPhotoURL(student)}, nil
+}
+
+func (s *photoService) DeleteStudentPhoto(ctx context.Context, studentID uint) error {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return errors.New("student not found")
+	}
+	if student.PhotoPath == "" {
+		return errors.New("student has no photo")
+	}
+
+	if err := s.studentRepo.UpdatePhotoPath(ctx, student.ID, ""); err != nil {
+		return fmt.Errorf("failed to remove photo: %v", err)
+	}
+	s.removePhoto(ctx, student.PhotoPath)
+	return nil
+}
+
+func (s *photoService) GetStudentPhoto(ctx context.Context, studentID uint) (*models.Photo, error) {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, errors.New("student not found")
+	}
+	if student.PhotoPath == "" {
+		return nil, errors.New("student has no photo")
+	}
+	return readPhoto(s.storage, student.PhotoPath)
+}
+
+func (s *photoService) UploadTeacherPhoto(ctx context.Context, teacherID uint, file multipart.File, header *multipart.FileHeader) (*models.PhotoResponse, error) {
+	teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, errors.New("teacher not found")
+	}
+
+	path := fmt.Sprintf("businesses/%d/teachers/%d/photo_%d_%s", teacher.BusinessID, teacher.ID, time.Now().UnixNano(), storage.SafeFileName(header.Filename))
+	if err := s.savePhoto(ctx, path, file, header, func() error {
+		return s.teacherRepo.UpdatePhotoPath(ctx, teacher.ID, path)
+	}); err != nil {
+		return nil, err
+	}
+	if teacher.PhotoPath != "" {
+		s.removePhoto(ctx, teacher.PhotoPath)
+	}
+
+	teacher, err = s.teacherRepo.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, errors.New("teacher not found")
+	}
+	return &models.PhotoResponse{PhotoURL: teacherPhotoURL(teacher)}, nil
+}
+
+func (s *photoService) DeleteTeacherPhoto(ctx context.Context, teacherID uint) error {
+	teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
+	if err != nil {
+		return errors.New("teacher not found")
+	}
+	if teacher.PhotoPath == "" {
+		return errors.New("teacher has no photo")
+	}
+
+	if err := s.teacherRepo.UpdatePhotoPath(ctx, teacher.ID, ""); err != nil {
+		return fmt.Errorf("failed to remove photo: %v", err)
+	}
+	s.removePhoto(ctx, teacher.PhotoPath)
+	return nil
+}
+
+func (s *photoService) GetTeacherPhoto(ctx context.Context, teacherID uint) (*models.Photo, error) {
+	teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, errors.New("teacher not found")
+	}
+	if teacher.PhotoPath == "" {
+		return nil, errors.New("teacher has no photo")
+	}
+	return readPhoto(s.storage, teacher.PhotoPath)
+}
+
+func (s *photoService) CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("student not found")
+	}
+	return checkBusinessAccess(ctx, s.businessRepo, student.BusinessID, userID, role)
+}
+
+// savePhoto validates and stores an uploaded photo, then runs attach to point the record
+// at it, removing the stored file again when that fails
+func (s *photoService) savePhoto(ctx context.Context, path string, file multipart.File, header *multipart.FileHeader, attach func() error) error {
+	if _, err := photoRules.Validate(file, header); err != nil {
+		return err
+	}
+	if err := s.storage.Save(path, file); err != nil {
+		return fmt.Errorf("failed to store photo: %v", err)
+	}
+	if err := attach(); err != nil {
+		// Don't leave orphaned files behind
+		s.removePhoto(ctx, path)
+		return fmt.Errorf("failed to save photo: %v", err)
+	}
+	return nil
+}
+
+// removePhoto deletes a stored photo; no record points at it anymore, so a failure is only logged
+func (s *photoService) removePhoto(ctx context.Context, path string) {
+	if err := s.storage.Delete(path); err != nil {
+		logger.FromContext(ctx).Warn("failed to remove stored photo", "path", path, "error", err)
+	}
+}
+
+// readPhoto reads a stored photo, sniffing its content type
+func readPhoto(store storage.Storage, path string) (*models.Photo, error) {
+	file, err := store.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open photo: %v", err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, MaxPhotoSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read photo: %v", err)
+	}
+	if len(content) > MaxPhotoSize {
+		return nil, errors.New("stored photo is too large")
+	}
+
+	return &models.Photo{
+		ContentType: http.DetectContentType(content),
+		Content:     content,
+	}, nil
+}
+
+// studentPhotoURL is where a student's photo is served, or empty when they have none. The
+// update time in the query makes clients fetch a replaced photo.
+func studentPhotoURL(student *models.Student) string {
+	if student.PhotoPath == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/students/%d/photo?v=%d", photoURLPrefix, student.ID, student.UpdatedOn.Unix())
+}
+
+// teacherPhotoURL is where a teacher's photo is served, or empty when they have none
+func teacherPhotoURL(teacher *models.Teacher) string {
+	if teacher.PhotoPath == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/teachers/%d/photo?v=%d", photoURLPrefix, teacher.ID, teacher.UpdatedOn.Unix())
+}
+
+// guardianPhotoURL is where a guardian link serves its student's photo, authorised by the
+// link's token like the view itself
+func guardianPhotoURL(token string, student *models.Student) string {
+	if student.PhotoPath == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/guardian/%s/photo?v=%d", photoURLPrefix, url.PathEscape(token), student.UpdatedOn.Unix())
+}
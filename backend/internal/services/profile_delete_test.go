@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/testutil"
+)
+
+// failingUsers fails every user step of a profile delete, after the profile
+// itself has been removed in the same transaction
+type failingUsers struct {
+	repository.UserRepository
+}
+
+var errUserStep = errors.New("user step failed")
+
+func (failingUsers) UpdateUserStatusInTransaction(*gorm.DB, uint, models.Status) error {
+	return errUserStep
+}
+
+func (failingUsers) BulkUpdateStatusInTransaction(*gorm.DB, []uint, models.Status) error {
+	return errUserStep
+}
+
+func (failingUsers) DeleteUserInTransaction(*gorm.DB, uint) error {
+	return errUserStep
+}
+
+// profileDeleter deletes one seeded profile and reports the login behind it
+type profileDeleter struct {
+	name   string
+	userID func(f *testutil.Fixtures) uint
+	delete func(db *gorm.DB, users repository.UserRepository, f *testutil.Fixtures, opts models.DeleteProfileOptions) error
+	exists func(db *gorm.DB, f *testutil.Fixtures) bool
+}
+
+var profileDeleters = []profileDeleter{
+	{
+		name:   "teacher",
+		userID: func(f *testutil.Fixtures) uint { return f.Teachers["Asha"].UserID },
+		delete: func(db *gorm.DB, users repository.UserRepository, f *testutil.Fixtures, opts models.DeleteProfileOptions) error {
+			service := NewTeacherService(repository.NewTeacherRepository(db), users, nil, nil, nil,
+				repository.NewTeacherDocumentRepository(db), nil, nil, nil)
+			return service.DeleteTeacher(context.Background(), f.Teachers["Asha"].ID, opts)
+		},
+		exists: func(db *gorm.DB, f *testutil.Fixtures) bool {
+			_, err := repository.NewTeacherRepository(db).GetByID(context.Background(), f.Teachers["Asha"].ID)
+			return err == nil
+		},
+	},
+	{
+		name:   "student",
+		userID: func(f *testutil.Fixtures) uint { return f.Students["Aarav"].UserID },
+		delete: func(db *gorm.DB, users repository.UserRepository, f *testutil.Fixtures, opts models.DeleteProfileOptions) error {
+			service := NewStudentService(repository.NewStudentRepository(db), users, nil, nil, nil)
+			return service.DeleteStudent(context.Background(), f.Students["Aarav"].ID, opts)
+		},
+		exists: func(db *gorm.DB, f *testutil.Fixtures) bool {
+			_, err := repository.NewStudentRepository(db).GetByID(context.Background(), f.Students["Aarav"].ID)
+			return err == nil
+		},
+	},
+}
+
+var deleteModes = []struct {
+	name string
+	opts models.DeleteProfileOptions
+}{
+	{"soft", models.DeleteProfileOptions{}},
+	{"permanent", models.DeleteProfileOptions{Permanent: true, Cascade: true}},
+	{"permanent with user", models.DeleteProfileOptions{Permanent: true, Cascade: true, DeleteUser: true}},
+}
+
+// Deleting a profile deactivates its login, or deletes it when asked to
+func TestDeleteProfileUpdatesUser(t *testing.T) {
+	for _, profile := range profileDeleters {
+		for _, mode := range deleteModes {
+			t.Run(profile.name+"/"+mode.name, func(t *testing.T) {
+				db := testutil.DB(t)
+				f := testutil.Seed(t, db)
+				users := repository.NewUserRepository(db)
+
+				if err := profile.delete(db, users, f, mode.opts); err != nil {
+					t.Fatalf("delete: %v", err)
+				}
+				if profile.exists(db, f) {
+					t.Errorf("%s still found after delete", profile.name)
+				}
+
+				user, err := users.GetByID(context.Background(), profile.userID(f))
+				if mode.opts.DeleteUser {
+					if err == nil {
+						t.Errorf("user %d still exists", user.ID)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("GetByID: %v", err)
+				}
+				if user.Status != models.StatusInactive {
+					t.Errorf("user status = %v, want inactive", user.Status)
+				}
+			})
+		}
+	}
+}
+
+// A failing user step rolls the profile delete back with it
+func TestDeleteProfileRollsBackOnUserFailure(t *testing.T) {
+	for _, profile := range profileDeleters {
+		for _, mode := range deleteModes {
+			t.Run(profile.name+"/"+mode.name, func(t *testing.T) {
+				db := testutil.DB(t)
+				f := testutil.Seed(t, db)
+				users := repository.NewUserRepository(db)
+
+				err := profile.delete(db, failingUsers{users}, f, mode.opts)
+				if err == nil {
+					t.Fatal("delete succeeded, want the user step's error")
+				}
+				if !profile.exists(db, f) {
+					t.Errorf("%s deleted although its user step failed", profile.name)
+				}
+
+				user, err := users.GetByID(context.Background(), profile.userID(f))
+				if err != nil {
+					t.Fatalf("GetByID: %v", err)
+				}
+				if user.Status != models.StatusActive {
+					t.Errorf("user status = %v, want active", user.Status)
+				}
+			})
+		}
+	}
+}
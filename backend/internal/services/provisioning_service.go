@@ -0,0 +1,203 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ProvisioningService seeds the defaults a new business starts with, whether it was created
+// from scratch or imported from an archive
+type ProvisioningService interface {
+	// ProvisionWithTransaction seeds the defaults inside the transaction creating the
+	// business; a default set is skipped when the business already has rows of that kind
+	ProvisionWithTransaction(tx *gorm.DB, business models.Business, opts ProvisionOptions) error
+	// PurgeSampleData removes the sample students seeded for a business, with their accounts
+	PurgeSampleData(ctx context.Context, businessID uint) (*models.PurgeSampleDataResponse, error)
+}
+
+// ProvisionOptions choose the optional parts of provisioning
+type ProvisionOptions struct {
+	SampleData bool // a few sample students, removable with PurgeSampleData
+}
+
+// defaultBatchName is the batch a provisioned business puts its first students in
+const defaultBatchName = "General"
+
+// defaultStudentFields are the custom student fields a provisioned business starts with
+var defaultStudentFields = []models.StudentField{
+	{Key: "school", Label: "School", Type: models.StudentFieldText},
+	{Key: "address", Label: "Address", Type: models.StudentFieldText, StudentEditable: true},
+	{Key: "emergency_contact", Label: "Emergency contact", Type: models.StudentFieldText, StudentEditable: true},
+}
+
+// sampleStudentNames are the students seeded as sample data
+var sampleStudentNames = []string{"Sample Student One", "Sample Student Two", "Sample Student Three"}
+
+type provisioningService struct {
+	provisioningRepo repository.ProvisioningRepository
+	studentRepo      repository.StudentRepository
+	userRepo         repository.UserRepository
+}
+
+func NewProvisioningService(provisioningRepo repository.ProvisioningRepository, studentRepo repository.StudentRepository, userRepo repository.UserRepository) ProvisioningService {
+	return &provisioningService{
+		provisioningRepo: provisioningRepo,
+		studentRepo:      studentRepo,
+		userRepo:         userRepo,
+	}
+}
+
+func (s *provisioningService) ProvisionWithTransaction(tx *gorm.DB, business models.Business, opts ProvisionOptions) error {
+	hasRows := func(model interface{}) (bool, error) {
+		return s.provisioningRepo.HasRowsWithTransaction(tx, model, business.ID)
+	}
+
+	var batchID *uint
+	if exists, err := hasRows(&models.Batch{}); err != nil {
+		return fmt.Errorf("failed to check batches: %w", err)
+	} else if !exists {
+		batches := []models.Batch{{BusinessID: business.ID, Name: defaultBatchName}}
+		if err := s.provisioningRepo.CreateWithTransaction(tx, &batches); err != nil {
+			return fmt.Errorf("failed to create default batch: %w", err)
+		}
+		batchID = &batches[0].ID
+	}
+
+	if exists, err := hasRows(&models.StudentField{}); err != nil {
+		return fmt.Errorf("failed to check student fields: %w", err)
+	} else if !exists {
+		fields := make([]models.StudentField, len(defaultStudentFields))
+		for i, field := range defaultStudentFields {
+			field.BusinessID = business.ID
+			fields[i] = field
+		}
+		if err := s.provisioningRepo.CreateWithTransaction(tx, &fields); err != nil {
+			return fmt.Errorf("failed to create default student fields: %w", err)
+		}
+	}
+
+	// Addressed to the owner, and kept to teachers so students never see it
+	announcements := []models.Announcement{{
+		BusinessID: business.ID,
+		Title:      fmt.Sprintf("Welcome to %s", business.Name),
+		Body: fmt.Sprintf("Hi %s, %s is ready. Add your teachers and students, set up fees and batches, "+
+			"and post announcements like this one. Edit or delete this announcement any time.", business.OwnerName, business.Name),
+		Audience:    models.AudienceTeachers,
+		PublishedAt: time.Now(),
+		CreatedBy:   business.UserID,
+	}}
+	if err := s.provisioningRepo.CreateWithTransaction(tx, &announcements); err != nil {
+		return fmt.Errorf("failed to create welcome announcement: %w", err)
+	}
+
+	if opts.SampleData {
+		if err := s.createSampleStudents(tx, business, batchID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createSampleStudents seeds students whose accounts are inactive, so nobody can sign in
+// to them, and records them for PurgeSampleData
+func (s *provisioningService) createSampleStudents(tx *gorm.DB, business models.Business, batchID *uint) error {
+	password, err := generatePasswordToken()
+	if err != nil {
+		return fmt.Errorf("error generating password: %w", err)
+	}
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	users := make([]models.User, len(sampleStudentNames))
+	for i, name := range sampleStudentNames {
+		users[i] = models.User{
+			Name:     name,
+			Email:    fmt.Sprintf("sample-%d-%d@sample.invalid", business.ID, i+1),
+			Password: hashedPassword,
+			Role:     models.RoleStudent,
+			Status:   models.StatusInactive,
+		}
+	}
+	if err := s.provisioningRepo.CreateWithTransaction(tx, &users); err != nil {
+		return fmt.Errorf("failed to create sample student accounts: %w", err)
+	}
+
+	today := todayIn(business.Timezone)
+	students := make([]models.Student, len(users))
+	for i, user := range users {
+		students[i] = models.Student{
+			Name:        user.Name,
+			UserID:      user.ID,
+			BusinessID:  business.ID,
+			Information: models.JSONB{"school": "Sample School"},
+			Status:      models.StatusActive,
+			BatchID:     batchID,
+			EnrolledOn:  &today,
+		}
+	}
+	if err := s.provisioningRepo.CreateWithTransaction(tx, &students); err != nil {
+		return fmt.Errorf("failed to create sample students: %w", err)
+	}
+
+	var records []models.SampleRecord
+	for i := range students {
+		records = append(records,
+			models.SampleRecord{BusinessID: business.ID, EntityType: models.SampleEntityStudent, EntityID: students[i].ID},
+			models.SampleRecord{BusinessID: business.ID, EntityType: models.SampleEntityUser, EntityID: users[i].ID},
+		)
+	}
+	if err := s.provisioningRepo.CreateWithTransaction(tx, &records); err != nil {
+		return fmt.Errorf("failed to record sample data: %w", err)
+	}
+	return nil
+}
+
+func (s *provisioningService) PurgeSampleData(ctx context.Context, businessID uint) (*models.PurgeSampleDataResponse, error) {
+	tx := s.provisioningRepo.BeginTransaction(ctx)
+
+	records, err := s.provisioningRepo.TakeSampleRecordsWithTransaction(tx, businessID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to get sample data: %w", err)
+	}
+
+	// Students go before their accounts; deleting a row the owner already deleted is a no-op
+	removed := make(map[string]int64)
+	for _, entityType := range []string{models.SampleEntityStudent, models.SampleEntityUser} {
+		for _, record := range records {
+			if record.EntityType != entityType {
+				continue
+			}
+			switch entityType {
+			case models.SampleEntityStudent:
+				// Payment history is kept for the books, as when deleting any student
+				var counts map[string]int64
+				if counts, err = s.studentRepo.CountDependentRecords(ctx, record.EntityID); err == nil && counts["payments"] > 0 {
+					err = fmt.Errorf("it has %d fee payments", counts["payments"])
+				}
+				if err == nil {
+					err = s.studentRepo.DeleteWithTransaction(tx, record.EntityID)
+				}
+			case models.SampleEntityUser:
+				err = s.userRepo.DeleteUserInTransaction(tx, record.EntityID)
+			}
+			if err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("failed to delete sample %s %d: %v", entityType, record.EntityID, err)
+			}
+			removed[entityType]++
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit sample data purge: %v", err)
+	}
+	return &models.PurgeSampleDataResponse{Removed: removed}, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// PurgeConfig controls how long soft-deleted profiles are kept
+type PurgeConfig struct {
+	RetentionDays int // days a soft-deleted teacher or student stays restorable, 30 by default
+}
+
+type PurgeService interface {
+	// Purge permanently removes the teachers and students soft-deleted more than the
+	// retention period before now
+	Purge(ctx context.Context, now time.Time) error
+	// RunScheduler purges every interval until ctx is done. A purge only removes rows
+	// that are still there, so every instance can run it.
+	RunScheduler(ctx context.Context, interval time.Duration)
+}
+
+type purgeService struct {
+	teacherService TeacherService
+	studentService StudentService
+	config         PurgeConfig
+}
+
+func NewPurgeService(teacherService TeacherService, studentService StudentService, config PurgeConfig) PurgeService {
+	if config.RetentionDays <= 0 {
+		config.RetentionDays = 30
+	}
+	return &purgeService{
+		teacherService: teacherService,
+		studentService: studentService,
+		config:         config,
+	}
+}
+
+func (s *purgeService) Purge(ctx context.Context, now time.Time) error {
+	cutoff := now.AddDate(0, 0, -s.config.RetentionDays)
+
+	teachers, err := s.teacherService.PurgeDeletedTeachers(ctx, cutoff)
+	if teachers > 0 {
+		slog.Info("Purged deleted teachers", "count", teachers, "deleted_before", cutoff)
+	}
+	if err != nil {
+		return err
+	}
+
+	students, err := s.studentService.PurgeDeletedStudents(ctx, cutoff)
+	if students > 0 {
+		slog.Info("Purged deleted students", "count", students, "deleted_before", cutoff)
+	}
+	return err
+}
+
+func (s *purgeService) RunScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Purge(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			slog.Error("Failed to purge deleted profiles", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type QualificationService interface {
+	GetQualifications(ctx context.Context, includePending bool) ([]models.Qualification, error)
+	GetBusinessQualifications(ctx context.Context, businessID uint, includePending bool) ([]models.Qualification, error)
+	CreateQualification(ctx context.Context, businessID *uint, req models.CreateQualificationRequest) (*models.Qualification, error)
+	UpdateQualification(ctx context.Context, businessID *uint, qualificationID uint, req models.UpdateQualificationRequest) (*models.Qualification, error)
+	DeleteQualification(ctx context.Context, businessID *uint, qualificationID uint) error
+	MigrateTeacherQualifications(ctx context.Context, dryRun bool) (*models.QualificationMigrationReport, error)
+
+	// Access control
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+}
+
+type qualificationService struct {
+	qualificationRepo repository.QualificationRepository
+	businessRepo      repository.BusinessRepository
+}
+
+func NewQualificationService(qualificationRepo repository.QualificationRepository, businessRepo repository.BusinessRepository) QualificationService {
+	return &qualificationService{
+		qualificationRepo: qualificationRepo,
+		businessRepo:      businessRepo,
+	}
+}
+
+func (s *qualificationService) GetQualifications(ctx context.Context, includePending bool) ([]models.Qualification, error) {
+	qualifications, err := s.qualificationRepo.GetGlobal(ctx, includePending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get qualifications: %v", err)
+	}
+	return qualifications, nil
+}
+
+func (s *qualificationService) GetBusinessQualifications(ctx context.Context, businessID uint, includePending bool) ([]models.Qualification, error) {
+	qualifications, err := s.qualificationRepo.GetForBusiness(ctx, businessID, includePending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get qualifications: %v", err)
+	}
+	return qualifications, nil
+}
+
+// CreateQualification adds a global entry when businessID is nil, else a custom entry
+// for the business. Entries added here are approved.
+func (s *qualificationService) CreateQualification(ctx context.Context, businessID *uint, req models.CreateQualificationRequest) (*models.Qualification, error) {
+	name, key, err := qualificationName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if businessID != nil {
+		if _, err := s.businessRepo.GetByID(ctx, *businessID); err != nil {
+			return nil, fmt.Errorf("business not found")
+		}
+	}
+
+	if err := s.checkKeyFree(ctx, businessID, key); err != nil {
+		return nil, err
+	}
+
+	qualification := &models.Qualification{
+		BusinessID: businessID,
+		Name:       name,
+		Key:        key,
+	}
+	if err := s.qualificationRepo.Create(ctx, qualification); err != nil {
+		return nil, fmt.Errorf("failed to create qualification: %v", err)
+	}
+
+	return qualification, nil
+}
+
+// UpdateQualification renames or approves an entry of the given scope: the global list
+// when businessID is nil, else the business's own entries
+func (s *qualificationService) UpdateQualification(ctx context.Context, businessID *uint, qualificationID uint, req models.UpdateQualificationRequest) (*models.Qualification, error) {
+	qualification, err := s.getScopedQualification(ctx, businessID, qualificationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(req.Name) != "" {
+		name, key, err := qualificationName(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkKeyFree(ctx, businessID, key, qualification.ID); err != nil {
+			return nil, err
+		}
+		qualification.Name = name
+		qualification.Key = key
+	}
+	if req.Pending != nil {
+		qualification.Pending = *req.Pending
+	}
+
+	if err := s.qualificationRepo.Update(ctx, qualification); err != nil {
+		return nil, fmt.Errorf("failed to update qualification: %v", err)
+	}
+
+	return qualification, nil
+}
+
+// DeleteQualification removes an entry of the given scope. Its teachers keep the name
+// as their free-text qualification.
+func (s *qualificationService) DeleteQualification(ctx context.Context, businessID *uint, qualificationID uint) error {
+	qualification, err := s.getScopedQualification(ctx, businessID, qualificationID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.qualificationRepo.Delete(ctx, qualification.ID); err != nil {
+		return fmt.Errorf("failed to delete qualification: %v", err)
+	}
+	return nil
+}
+
+// MigrateTeacherQualifications maps the free-text qualifications of teachers without an
+// entry to the entry with the same key, global or of the teacher's business, and reports
+// the strings nothing matched. The free text is kept. A dry run only reports.
+func (s *qualificationService) MigrateTeacherQualifications(ctx context.Context, dryRun bool) (*models.QualificationMigrationReport, error) {
+	legacy, err := s.qualificationRepo.GetUnmappedTeacherQualifications(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teacher qualifications: %v", err)
+	}
+
+	entries, err := s.qualificationRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get qualifications: %v", err)
+	}
+
+	report := &models.QualificationMigrationReport{
+		DryRun:    dryRun,
+		Matched:   []models.QualificationMatch{},
+		Unmatched: []models.QualificationMismatch{},
+	}
+	for _, value := range legacy {
+		entry := matchQualification(entries, value.BusinessID, QualificationKey(value.Value))
+		if entry == nil {
+			report.Unmatched = append(report.Unmatched, models.QualificationMismatch{
+				BusinessID: value.BusinessID,
+				Value:      value.Value,
+				Teachers:   value.Teachers,
+			})
+			continue
+		}
+		report.Matched = append(report.Matched, models.QualificationMatch{
+			BusinessID:      value.BusinessID,
+			Value:           value.Value,
+			QualificationID: entry.ID,
+			Name:            entry.Name,
+			Teachers:        value.Teachers,
+		})
+	}
+
+	if dryRun || len(report.Matched) == 0 {
+		return report, nil
+	}
+
+	tx := s.qualificationRepo.BeginTransaction(ctx)
+	for _, match := range report.Matched {
+		updated, err := s.qualificationRepo.MapTeacherQualificationsWithTransaction(tx, match.BusinessID, match.Value, match.QualificationID)
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to map qualification %q: %v", match.Value, err)
+		}
+		report.TeachersUpdated += updated
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit qualification migration: %v", err)
+	}
+
+	return report, nil
+}
+
+func (s *qualificationService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
+
+// Helper methods
+
+// getScopedQualification loads an entry, which must be global when businessID is nil
+// and the business's own otherwise
+func (s *qualificationService) getScopedQualification(ctx context.Context, businessID *uint, qualificationID uint) (*models.Qualification, error) {
+	qualification, err := s.qualificationRepo.GetByID(ctx, qualificationID)
+	if err != nil {
+		return nil, fmt.Errorf("qualification not found")
+	}
+
+	inScope := qualification.BusinessID == nil && businessID == nil ||
+		qualification.BusinessID != nil && businessID != nil && *qualification.BusinessID == *businessID
+	if !inScope {
+		return nil, fmt.Errorf("qualification not found")
+	}
+	return qualification, nil
+}
+
+func (s *qualificationService) checkKeyFree(ctx context.Context, businessID *uint, key string, excludeID ...uint) error {
+	exists, err := s.qualificationRepo.KeyExists(ctx, businessID, key, excludeID...)
+	if err != nil {
+		return fmt.Errorf("failed to check qualification name: %v", err)
+	}
+	if exists {
+		return fmt.Errorf("qualification already exists")
+	}
+	return nil
+}
+
+// QualificationKey folds a qualification name for matching, keeping only lowercase
+// letters and digits, so "B.Sc", "BSc" and "bsc." share the key "bsc"
+func QualificationKey(name string) string {
+	var key strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			key.WriteRune(r)
+		}
+	}
+	return key.String()
+}
+
+// qualificationName trims a qualification name and returns it with its key
+func qualificationName(value string) (string, string, error) {
+	name := strings.TrimSpace(value)
+	key := QualificationKey(name)
+	if key == "" {
+		return "", "", fmt.Errorf("qualification name must contain letters or digits")
+	}
+	return name, key, nil
+}
+
+// matchQualification picks the entry a business's teachers mean by key, in the order
+// of QualificationRepository.FindByKey: approved before pending, global before custom
+func matchQualification(entries []models.Qualification, businessID uint, key string) *models.Qualification {
+	var best *models.Qualification
+	rank := func(entry *models.Qualification) int {
+		r := 0
+		if entry.Pending {
+			r += 2
+		}
+		if entry.BusinessID != nil {
+			r++
+		}
+		return r
+	}
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Key != key || (entry.BusinessID != nil && *entry.BusinessID != businessID) {
+			continue
+		}
+		if best == nil || rank(entry) < rank(best) {
+			best = entry
+		}
+	}
+	return best
+}
+
+// resolveQualification finds the entry a teacher's qualification refers to: the entry
+// with id when one is given, else the entry matching text, else a new pending entry of
+// the business named text. It returns nil when both are empty.
+func resolveQualification(ctx context.Context, qualificationRepo repository.QualificationRepository, businessID uint, id *uint, text string) (*models.Qualification, error) {
+	if id != nil && *id != 0 {
+		qualification, err := qualificationRepo.GetByID(ctx, *id)
+		if err != nil || (qualification.BusinessID != nil && *qualification.BusinessID != businessID) {
+			return nil, &FieldError{Field: "qualification_id", Message: "qualification not found"}
+		}
+		return qualification, nil
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	name, key, err := qualificationName(text)
+	if err != nil {
+		return nil, &FieldError{Field: "qualification", Message: err.Error()}
+	}
+
+	qualification, err := qualificationRepo.FindByKey(ctx, businessID, key)
+	if err == nil {
+		return qualification, nil
+	}
+	if !repository.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to look up qualification: %v", err)
+	}
+
+	qualification = &models.Qualification{
+		BusinessID: &businessID,
+		Name:       name,
+		Key:        key,
+		Pending:    true,
+	}
+	if err := qualificationRepo.Create(ctx, qualification); err != nil {
+		return nil, fmt.Errorf("failed to add qualification: %v", err)
+	}
+	return qualification, nil
+}
@@ -0,0 +1,371 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/logger"
+	"backend/pkg/mailer"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// reportListLimit caps the businesses listed in each section of a report email
+const reportListLimit = 20
+
+// ReportConfig sets when the weekly reports go out
+type ReportConfig struct {
+	AppName       string
+	Weekday       time.Weekday   // Monday by default
+	Hour          int            // hour of the day, 7 by default
+	Location      *time.Location // time zone of Weekday and Hour, UTC by default
+	CheckInterval time.Duration  // how often the scheduler wakes up, a minute by default
+}
+
+type ReportService interface {
+	// Subscriptions of the current user, one per report their role can receive
+	GetSubscriptions(ctx context.Context, userID uint, role string) ([]models.ReportSubscriptionResponse, error)
+	UpdateSubscriptions(ctx context.Context, userID uint, role string, req models.UpdateReportSubscriptionsRequest) ([]models.ReportSubscriptionResponse, error)
+
+	// SendNow emails a report covering the last seven days right away
+	SendNow(ctx context.Context, req models.SendReportRequest) (*models.SendReportResult, error)
+
+	// RunScheduler sends the weekly reports at their scheduled time until ctx is done.
+	// A run missed by less than a day, e.g. during a deploy, is sent late; older runs are
+	// skipped.
+	RunScheduler(ctx context.Context)
+}
+
+type reportService struct {
+	reportRepo     repository.ReportRepository
+	userRepo       repository.UserRepository
+	businessRepo   repository.BusinessRepository
+	attendanceRepo repository.StudentAttendanceRepository
+	feeService     FeeService
+	mailer         mailer.Mailer
+	config         ReportConfig
+}
+
+func NewReportService(reportRepo repository.ReportRepository, userRepo repository.UserRepository, businessRepo repository.BusinessRepository, attendanceRepo repository.StudentAttendanceRepository, feeService FeeService, m mailer.Mailer, config ReportConfig) ReportService {
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+	if config.Hour < 0 || config.Hour > 23 {
+		config.Hour = 7
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = time.Minute
+	}
+	return &reportService{
+		reportRepo:     reportRepo,
+		userRepo:       userRepo,
+		businessRepo:   businessRepo,
+		attendanceRepo: attendanceRepo,
+		feeService:     feeService,
+		mailer:         m,
+		config:         config,
+	}
+}
+
+func (s *reportService) GetSubscriptions(ctx context.Context, userID uint, role string) ([]models.ReportSubscriptionResponse, error) {
+	subscriptions, err := s.reportRepo.GetSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+
+	byReport := make(map[string]models.ReportSubscription, len(subscriptions))
+	for _, subscription := range subscriptions {
+		byReport[subscription.Report] = subscription
+	}
+
+	responses := []models.ReportSubscriptionResponse{}
+	for _, report := range []string{models.ReportAdminWeekly, models.ReportBusinessWeekly} {
+		if string(models.ReportRoles[report]) != role {
+			continue
+		}
+		subscription := byReport[report]
+		responses = append(responses, models.ReportSubscriptionResponse{
+			Report:     report,
+			Enabled:    subscription.ID != 0 && subscription.Enabled,
+			LastSentAt: subscription.LastSentAt,
+		})
+	}
+	return responses, nil
+}
+
+func (s *reportService) UpdateSubscriptions(ctx context.Context, userID uint, role string, req models.UpdateReportSubscriptionsRequest) ([]models.ReportSubscriptionResponse, error) {
+	for _, update := range req.Subscriptions {
+		if string(models.ReportRoles[update.Report]) != role {
+			return nil, fmt.Errorf("report %s is not available to %s users", update.Report, role)
+		}
+	}
+
+	for _, update := range req.Subscriptions {
+		subscription := &models.ReportSubscription{
+			UserID:  userID,
+			Report:  update.Report,
+			Enabled: *update.Enabled,
+		}
+		if err := s.reportRepo.UpsertSubscription(ctx, subscription); err != nil {
+			return nil, fmt.Errorf("failed to update subscription: %w", err)
+		}
+	}
+
+	return s.GetSubscriptions(ctx, userID, role)
+}
+
+func (s *reportService) SendNow(ctx context.Context, req models.SendReportRequest) (*models.SendReportResult, error) {
+	// The seven days up to and including today
+	now := time.Now().In(s.config.Location)
+	to := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, s.config.Location)
+	from := to.AddDate(0, 0, -7)
+
+	if req.UserID == nil {
+		return s.send(ctx, req.Report, from, to)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, *req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	if models.ReportRoles[req.Report] != user.Role {
+		return nil, fmt.Errorf("report %s is not available to %s users", req.Report, user.Role)
+	}
+
+	result := newSendReportResult(req.Report, from, to)
+	if err := s.sendTo(ctx, req.Report, *user, from, to); err != nil {
+		return nil, err
+	}
+	result.Sent++
+	return result, nil
+}
+
+func (s *reportService) RunScheduler(ctx context.Context) {
+	ticker := time.NewTicker(s.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.runDue(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue sends every report whose latest slot has passed and wasn't sent yet
+func (s *reportService) runDue(ctx context.Context, now time.Time) {
+	slot := s.latestSlot(now)
+	if now.Sub(slot) >= 24*time.Hour {
+		return
+	}
+
+	for _, report := range []string{models.ReportAdminWeekly, models.ReportBusinessWeekly} {
+		claimed, err := s.reportRepo.ClaimRun(ctx, report, slot)
+		if err != nil {
+			slog.Error("Failed to check the report schedule", "report", report, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		result, err := s.send(ctx, report, slot.AddDate(0, 0, -7), slot)
+		if err != nil {
+			slog.Error("Scheduled report failed", "report", report, "error", err)
+			continue
+		}
+		slog.Info("Scheduled report sent", "report", report, "sent", result.Sent, "failed", result.Failed)
+	}
+}
+
+// latestSlot returns the most recent scheduled time at or before now
+func (s *reportService) latestSlot(now time.Time) time.Time {
+	local := now.In(s.config.Location)
+	slot := time.Date(local.Year(), local.Month(), local.Day(), s.config.Hour, 0, 0, 0, s.config.Location)
+	slot = slot.AddDate(0, 0, -((int(local.Weekday()) - int(s.config.Weekday) + 7) % 7))
+	if slot.After(local) {
+		slot = slot.AddDate(0, 0, -7)
+	}
+	return slot
+}
+
+// send emails a report covering [from, to) to all of its subscribers. Failures of single
+// subscribers are logged and counted rather than stopping the others.
+func (s *reportService) send(ctx context.Context, report string, from, to time.Time) (*models.SendReportResult, error) {
+	subscriptions, err := s.reportRepo.GetSubscribers(ctx, report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscribers: %w", err)
+	}
+
+	result := newSendReportResult(report, from, to)
+	for _, subscription := range subscriptions {
+		if subscription.User.Role != models.ReportRoles[report] {
+			continue
+		}
+		if err := s.sendTo(ctx, report, subscription.User, from, to); err != nil {
+			logger.FromContext(ctx).Error("Failed to send report", "report", report, "user_id", subscription.UserID, "error", err)
+			result.Failed++
+			continue
+		}
+		result.Sent++
+	}
+	return result, nil
+}
+
+func (s *reportService) sendTo(ctx context.Context, report string, user models.User, from, to time.Time) error {
+	var data interface{}
+	var err error
+	switch report {
+	case models.ReportAdminWeekly:
+		data, err = s.adminReport(ctx, from, to)
+	case models.ReportBusinessWeekly:
+		data, err = s.businessReport(ctx, user.ID, from, to)
+	default:
+		return fmt.Errorf("unknown report %q", report)
+	}
+	if err != nil {
+		return err
+	}
+
+	msg, err := mailer.Render(report+"_report", user.Email, map[string]interface{}{
+		"AppName": s.config.AppName,
+		"Name":    user.Name,
+		"Report":  data,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to queue email: %w", err)
+	}
+
+	if err := s.reportRepo.MarkSent(ctx, user.ID, report, time.Now()); err != nil {
+		logger.FromContext(ctx).Warn("Failed to record report delivery", "report", report, "user_id", user.ID, "error", err)
+	}
+	return nil
+}
+
+func (s *reportService) adminReport(ctx context.Context, from, to time.Time) (*models.AdminWeeklyReport, error) {
+	report := &models.AdminWeeklyReport{
+		From:               from.Format(models.DateFormat),
+		To:                 to.AddDate(0, 0, -1).Format(models.DateFormat),
+		NewUsersByRole:     map[string]int{},
+		NewBusinesses:      []models.ReportBusiness{},
+		ExpiringPackages:   []models.ReportBusiness{},
+		InactiveBusinesses: []models.ReportBusiness{},
+	}
+
+	users, err := s.userRepo.GetUsersByDateRange(ctx, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get new users: %w", err)
+	}
+	report.NewUsers = len(users)
+	for _, user := range users {
+		report.NewUsersByRole[string(user.Role)]++
+		if user.Role == models.RoleBusiness && len(report.NewBusinesses) < reportListLimit {
+			if business, err := s.businessRepo.GetByUserID(ctx, user.ID); err == nil {
+				report.NewBusinesses = append(report.NewBusinesses, toReportBusiness(*business))
+			}
+		}
+	}
+
+	expiring, err := s.businessRepo.GetBusinessesByPackageExpiry(ctx, to, 7)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expiring packages: %w", err)
+	}
+	for i, business := range expiring {
+		if i == reportListLimit {
+			break
+		}
+		report.ExpiringPackages = append(report.ExpiringPackages, toReportBusiness(business))
+	}
+
+	inactive, err := s.businessRepo.GetInactiveBusinesses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inactive businesses: %w", err)
+	}
+	for i, business := range inactive {
+		if i == reportListLimit {
+			break
+		}
+		report.InactiveBusinesses = append(report.InactiveBusinesses, toReportBusiness(business))
+	}
+
+	stats, err := s.businessRepo.GetBusinessStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business stats: %w", err)
+	}
+	report.TotalBusinesses, _ = stats["total_businesses"].(int64)
+	report.ActiveBusinesses, _ = stats["active_businesses"].(int64)
+
+	return report, nil
+}
+
+func (s *reportService) businessReport(ctx context.Context, userID uint, from, to time.Time) (*models.BusinessWeeklyReport, error) {
+	business, err := s.businessRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("business profile not found")
+	}
+
+	fromDate := from.Format(models.DateFormat)
+	toDate := to.AddDate(0, 0, -1).Format(models.DateFormat)
+	report := &models.BusinessWeeklyReport{
+		BusinessName: business.Name,
+		From:         fromDate,
+		To:           toDate,
+	}
+
+	counts, err := s.attendanceRepo.GetStatusCounts(ctx, repository.StudentAttendanceFilters{
+		BusinessID: &business.ID,
+		From:       fromDate,
+		To:         toDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance: %w", err)
+	}
+	for _, count := range counts {
+		switch count.Status {
+		case "present":
+			report.Present += count.Count
+		case "absent":
+			report.Absent += count.Count
+		case "leave":
+			report.Leave += count.Count
+		}
+	}
+	_, report.AttendanceRate = studentAttendanceTotals(report.Present, report.Absent, report.Leave)
+
+	fees, err := s.feeService.GetBusinessSummary(ctx, business.ID, fromDate, toDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee summary: %w", err)
+	}
+	report.Fees = *fees
+
+	return report, nil
+}
+
+func newSendReportResult(report string, from, to time.Time) *models.SendReportResult {
+	return &models.SendReportResult{
+		Report: report,
+		From:   from.Format(models.DateFormat),
+		To:     to.AddDate(0, 0, -1).Format(models.DateFormat),
+	}
+}
+
+func toReportBusiness(business models.Business) models.ReportBusiness {
+	reportBusiness := models.ReportBusiness{
+		ID:               business.ID,
+		Name:             business.Name,
+		Email:            business.Email,
+		PackageExpiresAt: business.PackageExpiresAt,
+	}
+	if business.Package != nil {
+		reportBusiness.PackageName = business.Package.Name
+	}
+	return reportBusiness
+}
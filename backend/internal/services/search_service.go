@@ -0,0 +1,244 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/logger"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// maxConcurrentSearches bounds how many section searches of one request hit the database at once
+const maxConcurrentSearches = 4
+
+type SearchService interface {
+	// Search finds users, businesses, teachers and students matching query, at most limit
+	// per section. Admins search everything; business users only their own teachers and
+	// students.
+	Search(ctx context.Context, query string, limit int, userID uint, role string) (*models.SearchResponse, error)
+}
+
+type searchService struct {
+	userRepo     repository.UserRepository
+	businessRepo repository.BusinessRepository
+	teacherRepo  repository.TeacherRepository
+	studentRepo  repository.StudentRepository
+	timeout      time.Duration
+}
+
+// NewSearchService returns a search service that answers within timeout, leaving sections
+// that haven't finished by then empty
+func NewSearchService(userRepo repository.UserRepository, businessRepo repository.BusinessRepository, teacherRepo repository.TeacherRepository, studentRepo repository.StudentRepository, timeout time.Duration) SearchService {
+	return &searchService{
+		userRepo:     userRepo,
+		businessRepo: businessRepo,
+		teacherRepo:  teacherRepo,
+		studentRepo:  studentRepo,
+		timeout:      timeout,
+	}
+}
+
+// searchSection runs the search of one section, returning its hits and total match count
+type searchSection struct {
+	name string
+	run  func(ctx context.Context) ([]models.SearchHit, int64, error)
+}
+
+func (s *searchService) Search(ctx context.Context, query string, limit int, userID uint, role string) (*models.SearchResponse, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("search query cannot be empty")
+	}
+
+	sections, err := s.sectionsFor(ctx, query, limit, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// Sections start out as timed out and are filled in as their searches finish
+	var mu sync.Mutex
+	results := make([]models.SearchSection, len(sections))
+	for i, section := range sections {
+		results[i] = models.SearchSection{Name: section.name, Hits: []models.SearchHit{}, Error: "search timed out"}
+	}
+
+	var group errgroup.Group
+	group.SetLimit(maxConcurrentSearches)
+	for i, section := range sections {
+		group.Go(func() error {
+			hits, total, err := section.run(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.FromContext(ctx).Error("Search section failed", "section", section.name, "error", err)
+					results[i].Error = "search failed"
+				}
+				return nil
+			}
+			rankHits(query, hits)
+			results[i] = models.SearchSection{Name: section.name, Hits: hits, Total: total}
+			return nil
+		})
+	}
+
+	// Return at the timeout even if a search ignores the cancelled context
+	done := make(chan struct{})
+	go func() {
+		group.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return &models.SearchResponse{
+		Query:    query,
+		Sections: append([]models.SearchSection(nil), results...),
+	}, nil
+}
+
+// sectionsFor returns the sections the caller may search: all four for admins, and the
+// teachers and students of their own business for business users
+func (s *searchService) sectionsFor(ctx context.Context, query string, limit int, userID uint, role string) ([]searchSection, error) {
+	switch role {
+	case "admin":
+		return []searchSection{
+			s.userSection(query, limit),
+			s.businessSection(query, limit),
+			s.teacherSection(query, limit, nil),
+			s.studentSection(query, limit, nil),
+		}, nil
+	case "business":
+		business, err := s.businessRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			return nil, ErrAccessDenied
+		}
+		return []searchSection{
+			s.teacherSection(query, limit, &business.ID),
+			s.studentSection(query, limit, &business.ID),
+		}, nil
+	default:
+		return nil, ErrAccessDenied
+	}
+}
+
+func (s *searchService) userSection(query string, limit int) searchSection {
+	return searchSection{name: models.SearchSectionUsers, run: func(ctx context.Context) ([]models.SearchHit, int64, error) {
+		users, err := s.userRepo.SearchUsers(ctx, query, limit)
+		if err != nil {
+			return nil, 0, err
+		}
+		hits := make([]models.SearchHit, 0, len(users))
+		for _, user := range users {
+			hits = append(hits, models.SearchHit{ID: user.ID, Name: user.Name, Email: user.Email, Detail: string(user.Role)})
+		}
+		total, err := sectionTotal(len(hits), limit, func() (int64, error) {
+			_, total, err := s.userRepo.GetAll(ctx, repository.UserFilters{Search: query, Limit: 1})
+			return total, err
+		})
+		return hits, total, err
+	}}
+}
+
+func (s *searchService) businessSection(query string, limit int) searchSection {
+	return searchSection{name: models.SearchSectionBusinesses, run: func(ctx context.Context) ([]models.SearchHit, int64, error) {
+		businesses, err := s.businessRepo.SearchBusinesses(ctx, query, limit)
+		if err != nil {
+			return nil, 0, err
+		}
+		hits := make([]models.SearchHit, 0, len(businesses))
+		for _, business := range businesses {
+			hits = append(hits, models.SearchHit{ID: business.ID, Name: business.Name, Email: business.Email, Detail: business.Slug})
+		}
+		total, err := sectionTotal(len(hits), limit, func() (int64, error) {
+			_, total, err := s.businessRepo.GetAll(ctx, repository.BusinessFilters{Search: query, Limit: 1})
+			return total, err
+		})
+		return hits, total, err
+	}}
+}
+
+func (s *searchService) teacherSection(query string, limit int, businessID *uint) searchSection {
+	return searchSection{name: models.SearchSectionTeachers, run: func(ctx context.Context) ([]models.SearchHit, int64, error) {
+		teachers, err := s.teacherRepo.SearchTeachers(ctx, query, limit, repository.TeacherFilters{BusinessID: businessID})
+		if err != nil {
+			return nil, 0, err
+		}
+		hits := make([]models.SearchHit, 0, len(teachers))
+		for _, teacher := range teachers {
+			teacherBusinessID := teacher.BusinessID
+			hits = append(hits, models.SearchHit{ID: teacher.ID, Name: teacher.Name, BusinessID: &teacherBusinessID, Detail: teacher.Qualification})
+		}
+		total, err := sectionTotal(len(hits), limit, func() (int64, error) {
+			_, total, err := s.teacherRepo.GetAll(ctx, repository.TeacherFilters{Search: query, BusinessID: businessID, Limit: 1})
+			return total, err
+		})
+		return hits, total, err
+	}}
+}
+
+func (s *searchService) studentSection(query string, limit int, businessID *uint) searchSection {
+	return searchSection{name: models.SearchSectionStudents, run: func(ctx context.Context) ([]models.SearchHit, int64, error) {
+		var scope []uint
+		if businessID != nil {
+			scope = append(scope, *businessID)
+		}
+		students, err := s.studentRepo.SearchStudents(ctx, repository.StudentSearch{Term: query}, limit, scope...)
+		if err != nil {
+			return nil, 0, err
+		}
+		hits := make([]models.SearchHit, 0, len(students))
+		for _, student := range students {
+			studentBusinessID := student.BusinessID
+			hits = append(hits, models.SearchHit{ID: student.ID, Name: student.Name, BusinessID: &studentBusinessID, Detail: student.Grade})
+		}
+		total, err := sectionTotal(len(hits), limit, func() (int64, error) {
+			_, total, err := s.studentRepo.GetAll(ctx, repository.StudentFilters{Search: query, BusinessID: businessID, Limit: 1})
+			return total, err
+		})
+		return hits, total, err
+	}}
+}
+
+// sectionTotal is the number of hits, unless the section came back full and there may be
+// more, when the matches are counted
+func sectionTotal(found, limit int, count func() (int64, error)) (int64, error) {
+	if found < limit {
+		return int64(found), nil
+	}
+	return count()
+}
+
+// rankHits puts exact name or email matches first and name prefix matches second,
+// keeping the newest-first order of the repositories within each group
+func rankHits(query string, hits []models.SearchHit) {
+	query = strings.ToLower(query)
+	rank := func(hit models.SearchHit) int {
+		name := strings.ToLower(hit.Name)
+		switch {
+		case name == query || strings.ToLower(hit.Email) == query:
+			return 0
+		case strings.HasPrefix(name, query):
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return rank(hits[i]) < rank(hits[j])
+	})
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/testutil"
+)
+
+// A business searches only its own teachers and students, even for names another
+// business has too; admins search every business, and every section
+func TestSearchScope(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	ctx := context.Background()
+	sunrise, moonlight := f.Businesses["Sunrise Academy"], f.Businesses["Moonlight Tutors"]
+
+	// Namesakes of Sunrise's Asha and Aarav at Moonlight
+	otherAsha := testutil.CreateTeacher(t, db, "Asha Rao", moonlight.ID, 28000, models.StatusActive)
+	otherAarav := testutil.CreateStudent(t, db, "Aarav Mehta", moonlight.ID, "10", "male", models.StatusActive)
+
+	service := NewSearchService(repository.NewUserRepository(db), repository.NewBusinessRepository(db),
+		repository.NewTeacherRepository(db), repository.NewStudentRepository(db), 5*time.Second)
+
+	sections := func(t *testing.T, query string, userID uint, role string) map[string][]models.SearchHit {
+		t.Helper()
+		response, err := service.Search(ctx, query, 20, userID, role)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		found := map[string][]models.SearchHit{}
+		for _, section := range response.Sections {
+			if section.Error != "" {
+				t.Fatalf("section %s: %s", section.Name, section.Error)
+			}
+			found[section.Name] = section.Hits
+		}
+		return found
+	}
+	// ids are the sorted IDs of hits, to compare with sorted wants
+	ids := func(hits []models.SearchHit) []uint {
+		ids := make([]uint, len(hits))
+		for i, hit := range hits {
+			ids[i] = hit.ID
+		}
+		slices.Sort(ids)
+		return ids
+	}
+	sorted := func(ids ...uint) []uint {
+		slices.Sort(ids)
+		return ids
+	}
+
+	t.Run("business", func(t *testing.T) {
+		for _, query := range []string{"Asha", "Aarav", "a"} {
+			found := sections(t, query, sunrise.UserID, string(models.RoleBusiness))
+			if len(found) != 2 || found[models.SearchSectionUsers] != nil || found[models.SearchSectionBusinesses] != nil {
+				t.Errorf("%q: sections = %v, want only teachers and students", query, found)
+			}
+			for name, hits := range found {
+				for _, hit := range hits {
+					if hit.BusinessID == nil || *hit.BusinessID != sunrise.ID {
+						t.Errorf("%q: %s hit %q belongs to business %v, want %d", query, name, hit.Name, hit.BusinessID, sunrise.ID)
+					}
+				}
+			}
+		}
+		found := sections(t, "Asha", sunrise.UserID, string(models.RoleBusiness))
+		if got := ids(found[models.SearchSectionTeachers]); !slices.Equal(got, sorted(f.Teachers["Asha"].ID)) {
+			t.Errorf("teachers = %v, want only Sunrise's Asha", got)
+		}
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		found := sections(t, "Asha", f.Admin.ID, string(models.RoleAdmin))
+		if got := ids(found[models.SearchSectionTeachers]); !slices.Equal(got, sorted(f.Teachers["Asha"].ID, otherAsha.ID)) {
+			t.Errorf("teachers = %v, want both Ashas", got)
+		}
+		found = sections(t, "Aarav", f.Admin.ID, string(models.RoleAdmin))
+		if got := ids(found[models.SearchSectionStudents]); !slices.Equal(got, sorted(f.Students["Aarav"].ID, otherAarav.ID)) {
+			t.Errorf("students = %v, want both Aaravs", got)
+		}
+		found = sections(t, "Moonlight", f.Admin.ID, string(models.RoleAdmin))
+		if got := ids(found[models.SearchSectionBusinesses]); !slices.Equal(got, sorted(moonlight.ID)) {
+			t.Errorf("businesses = %v, want Moonlight", got)
+		}
+	})
+
+	t.Run("other roles", func(t *testing.T) {
+		for _, role := range []models.UserRole{models.RoleTeacher, models.RoleStudent} {
+			if _, err := service.Search(ctx, "Asha", 20, 1, string(role)); !errors.Is(err, ErrAccessDenied) {
+				t.Errorf("%s: error = %v, want %v", role, err, ErrAccessDenied)
+			}
+		}
+		// A business user without a business finds nothing rather than everything
+		if _, err := service.Search(ctx, "Asha", 20, f.Admin.ID, string(models.RoleBusiness)); !errors.Is(err, ErrAccessDenied) {
+			t.Errorf("business user without a business: error = %v, want %v", err, ErrAccessDenied)
+		}
+	})
+}
@@ -0,0 +1,356 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/logger"
+	"backend/pkg/sms"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrSMSDisabled is returned when a business hasn't opted in to SMS notifications
+var ErrSMSDisabled = errors.New("SMS notifications are disabled for this business")
+
+// SMSConfig controls sending and rate limiting of guardian text messages
+type SMSConfig struct {
+	RecipientLimit  int           // messages one number may get per RecipientWindow, 5 by default
+	RecipientWindow time.Duration // 24h by default
+	QueueSize       int           // messages waiting to be sent, 500 by default
+	Workers         int           // concurrent sends, 2 by default
+	MaxRetries      int           // attempts after the first failure, 3 by default
+	Backoff         time.Duration // delay before the first retry, doubled for each retry, 5s by default
+}
+
+type SMSService interface {
+	// Enqueue records the messages of a business and queues them for sending in the
+	// background. Messages whose DedupKey was used before are skipped, and those to a
+	// number over its rate limit are recorded as rate limited without being sent.
+	Enqueue(ctx context.Context, businessID uint, kind string, messages []models.OutgoingSMS) (*models.SMSEnqueueResult, error)
+	// RecordStatus applies a delivery report posted by the provider
+	RecordStatus(ctx context.Context, form url.Values, signature string) error
+	GetMessages(ctx context.Context, filters repository.SMSFilters) ([]models.SMSMessage, int64, error)
+
+	// Settings
+	GetSettings(ctx context.Context, businessID uint) (*models.SMSSettings, error)
+	UpdateSettings(ctx context.Context, businessID uint, enabled bool) (*models.SMSSettings, error)
+
+	// Close stops accepting messages and waits for the queued ones to be sent
+	Close(ctx context.Context) error
+
+	// Access control
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+}
+
+type smsService struct {
+	smsRepo      repository.SMSRepository
+	businessRepo repository.BusinessRepository
+	sender       sms.Sender
+	config       SMSConfig
+
+	jobs    chan uint
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	mu      sync.RWMutex
+	closed  bool
+	closing sync.Once
+}
+
+func NewSMSService(smsRepo repository.SMSRepository, businessRepo repository.BusinessRepository, sender sms.Sender, config SMSConfig) SMSService {
+	if config.RecipientLimit <= 0 {
+		config.RecipientLimit = 5
+	}
+	if config.RecipientWindow <= 0 {
+		config.RecipientWindow = 24 * time.Hour
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 500
+	}
+	if config.Workers <= 0 {
+		config.Workers = 2
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 3
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = 5 * time.Second
+	}
+
+	s := &smsService{
+		smsRepo:      smsRepo,
+		businessRepo: businessRepo,
+		sender:       sender,
+		config:       config,
+		jobs:         make(chan uint, config.QueueSize),
+		stop:         make(chan struct{}),
+	}
+	for i := 0; i < config.Workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+	return s
+}
+
+func (s *smsService) Enqueue(ctx context.Context, businessID uint, kind string, messages []models.OutgoingSMS) (*models.SMSEnqueueResult, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+	if !business.SMSNotifications {
+		return nil, ErrSMSDisabled
+	}
+
+	result := &models.SMSEnqueueResult{}
+	if len(messages) == 0 {
+		return result, nil
+	}
+
+	keys := make([]string, 0, len(messages))
+	for _, message := range messages {
+		if message.DedupKey != "" {
+			keys = append(keys, message.DedupKey)
+		}
+	}
+	existing, err := s.smsRepo.ExistingDedupKeys(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check earlier messages: %w", err)
+	}
+
+	since := time.Now().Add(-s.config.RecipientWindow)
+	recent := make(map[string]int64)
+	records := make([]models.SMSMessage, 0, len(messages))
+	for _, message := range messages {
+		if message.DedupKey != "" && existing[message.DedupKey] {
+			result.Duplicates++
+			continue
+		}
+		if message.DedupKey != "" {
+			existing[message.DedupKey] = true
+		}
+
+		count, counted := recent[message.To]
+		if !counted {
+			if count, err = s.smsRepo.CountRecentTo(ctx, message.To, since); err != nil {
+				return nil, fmt.Errorf("failed to check the rate limit: %w", err)
+			}
+		}
+
+		status := models.SMSStatusQueued
+		if count >= int64(s.config.RecipientLimit) {
+			status = models.SMSStatusRateLimited
+			result.RateLimited++
+		} else {
+			count++
+			result.Queued++
+		}
+		recent[message.To] = count
+
+		studentID := message.StudentID
+		records = append(records, models.SMSMessage{
+			BusinessID: businessID,
+			StudentID:  &studentID,
+			Kind:       kind,
+			To:         message.To,
+			Body:       message.Body,
+			DedupKey:   message.DedupKey,
+			Status:     status,
+		})
+	}
+
+	if err := s.smsRepo.Create(ctx, records); err != nil {
+		return nil, fmt.Errorf("failed to record messages: %w", err)
+	}
+
+	for _, record := range records {
+		if record.Status == models.SMSStatusQueued {
+			s.push(ctx, record.ID)
+		}
+	}
+	return result, nil
+}
+
+// push hands a recorded message to the workers, failing it if the queue is full or closed
+func (s *smsService) push(ctx context.Context, id uint) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reason := "SMS queue is closed"
+	if !s.closed {
+		select {
+		case s.jobs <- id:
+			return
+		default:
+			reason = "SMS queue is full"
+		}
+	}
+
+	logger.FromContext(ctx).Error("Dropping SMS", "sms_id", id, "reason", reason)
+	if err := s.smsRepo.Update(ctx, id, map[string]interface{}{"status": models.SMSStatusFailed, "error": reason}); err != nil {
+		logger.FromContext(ctx).Error("Failed to record dropped SMS", "sms_id", id, "error", err)
+	}
+}
+
+func (s *smsService) work() {
+	defer s.wg.Done()
+	for id := range s.jobs {
+		s.deliver(id)
+	}
+}
+
+// deliver sends one message, retrying failures that may be temporary with exponential
+// backoff, and records the outcome
+func (s *smsService) deliver(id uint) {
+	ctx := context.Background()
+	message, err := s.smsRepo.GetByID(ctx, id)
+	if err != nil {
+		slog.Error("Failed to load queued SMS", "sms_id", id, "error", err)
+		return
+	}
+
+	backoff := s.config.Backoff
+	for attempt := 1; ; attempt++ {
+		sendCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		result, err := s.sender.Send(sendCtx, sms.Message{To: message.To, Body: message.Body})
+		cancel()
+
+		if err == nil {
+			now := time.Now()
+			s.update(id, map[string]interface{}{
+				"status":      result.Status,
+				"provider_id": result.ProviderID,
+				"attempts":    attempt,
+				"error":       "",
+				"sent_at":     &now,
+			})
+			return
+		}
+
+		if sms.IsPermanent(err) || attempt > s.config.MaxRetries {
+			slog.Error("Giving up on SMS", "sms_id", id, "attempts", attempt, "error", err)
+			s.update(id, map[string]interface{}{"status": models.SMSStatusFailed, "attempts": attempt, "error": err.Error()})
+			return
+		}
+		slog.Warn("SMS send failed, retrying", "sms_id", id, "attempt", attempt, "retry_in", backoff.String(), "error", err)
+		s.update(id, map[string]interface{}{"attempts": attempt, "error": err.Error()})
+
+		select {
+		case <-time.After(backoff):
+		case <-s.stop:
+			s.update(id, map[string]interface{}{"status": models.SMSStatusFailed, "error": "dropped at shutdown: " + err.Error()})
+			return
+		}
+		backoff *= 2
+	}
+}
+
+func (s *smsService) update(id uint, updates map[string]interface{}) {
+	if err := s.smsRepo.Update(context.Background(), id, updates); err != nil {
+		slog.Error("Failed to record SMS status", "sms_id", id, "error", err)
+	}
+}
+
+func (s *smsService) RecordStatus(ctx context.Context, form url.Values, signature string) error {
+	update, err := s.sender.ParseStatusCallback(form, signature)
+	if err != nil {
+		return err
+	}
+
+	status := update.Status
+	switch status {
+	case sms.StatusSent, sms.StatusDelivered, sms.StatusFailed:
+	default:
+		return fmt.Errorf("unknown SMS status %q", status)
+	}
+
+	if err := s.smsRepo.UpdateStatusByProviderID(ctx, update.ProviderID, status, update.Error); err != nil {
+		return fmt.Errorf("failed to record SMS status: %w", err)
+	}
+	return nil
+}
+
+func (s *smsService) GetMessages(ctx context.Context, filters repository.SMSFilters) ([]models.SMSMessage, int64, error) {
+	// Set default pagination if not provided
+	if filters.Limit <= 0 {
+		filters.Limit = 10
+	}
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+
+	messages, total, err := s.smsRepo.GetMessages(ctx, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get messages: %w", err)
+	}
+	return messages, total, nil
+}
+
+func (s *smsService) GetSettings(ctx context.Context, businessID uint) (*models.SMSSettings, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+	return &models.SMSSettings{BusinessID: business.ID, Enabled: business.SMSNotifications}, nil
+}
+
+func (s *smsService) UpdateSettings(ctx context.Context, businessID uint, enabled bool) (*models.SMSSettings, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	if err := s.businessRepo.UpdateFields(ctx, business.ID, map[string]interface{}{"sms_notifications": enabled}); err != nil {
+		return nil, fmt.Errorf("failed to update settings: %w", err)
+	}
+	return &models.SMSSettings{BusinessID: business.ID, Enabled: enabled}, nil
+}
+
+func (s *smsService) Close(ctx context.Context) error {
+	s.closing.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		close(s.jobs)
+		s.mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		select {
+		case <-s.stop:
+		default:
+			close(s.stop)
+		}
+		return ctx.Err()
+	}
+}
+
+func (s *smsService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
+
+// guardianPhones picks the number each student's guardians are texted at: the primary
+// guardian's, or the first other guardian with a phone. guardians must be ordered
+// primary first.
+func guardianPhones(guardians []models.StudentGuardian) map[uint]string {
+	phones := make(map[uint]string)
+	for _, guardian := range guardians {
+		if guardian.Phone == "" {
+			continue
+		}
+		if _, ok := phones[guardian.StudentID]; !ok {
+			phones[guardian.StudentID] = guardian.Phone
+		}
+	}
+	return phones
+}
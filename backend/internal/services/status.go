@@ -0,0 +1,49 @@
+package services
+
+import (
+	"backend/internal/models"
+	"errors"
+)
+
+var errInvalidStatus = errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
+
+// validateStatus rejects a status other than active or inactive
+func validateStatus(status models.Status) error {
+	if !status.IsValid() {
+		return errInvalidStatus
+	}
+	return nil
+}
+
+// updatedStatus reads the status out of a map of updates. Handlers put a models.Status
+// there, while bodies bound straight into the map hold the number as a float64 or the
+// name as a string. ok is false when the updates don't set a status.
+func updatedStatus(updates map[string]interface{}) (status models.Status, ok bool, err error) {
+	value, ok := updates["status"]
+	if !ok || value == nil {
+		return 0, false, nil
+	}
+
+	switch v := value.(type) {
+	case models.Status:
+		status = v
+	case int:
+		status = models.Status(v)
+	case float64:
+		if v != float64(int(v)) {
+			return 0, false, errInvalidStatus
+		}
+		status = models.Status(v)
+	case string:
+		if status, err = models.ParseStatus(v); err != nil {
+			return 0, false, errInvalidStatus
+		}
+	default:
+		return 0, false, errInvalidStatus
+	}
+
+	if err := validateStatus(status); err != nil {
+		return 0, false, err
+	}
+	return status, true, nil
+}
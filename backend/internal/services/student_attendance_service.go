@@ -0,0 +1,377 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+type StudentAttendanceService interface {
+	BulkMarkAttendance(ctx context.Context, businessID uint, req models.BulkStudentAttendanceRequest, actorID uint) ([]models.StudentAttendanceResponse, error)
+	GetAttendance(ctx context.Context, filters repository.StudentAttendanceFilters) ([]models.StudentAttendanceResponse, int64, error)
+	GetStudentMonthlySummary(ctx context.Context, studentID uint, month string) (*models.StudentAttendanceSummary, error)
+	GetBusinessMonthlySummary(ctx context.Context, businessID uint, batchID *uint, month string) (*models.BusinessStudentAttendanceSummary, error)
+
+	// Access control
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+	CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error
+}
+
+type studentAttendanceService struct {
+	attendanceRepo repository.StudentAttendanceRepository
+	studentRepo    repository.StudentRepository
+	batchRepo      repository.BatchRepository
+	businessRepo   repository.BusinessRepository
+	smsService     SMSService
+}
+
+func NewStudentAttendanceService(attendanceRepo repository.StudentAttendanceRepository, studentRepo repository.StudentRepository, batchRepo repository.BatchRepository, businessRepo repository.BusinessRepository, smsService SMSService) StudentAttendanceService {
+	return &studentAttendanceService{
+		attendanceRepo: attendanceRepo,
+		studentRepo:    studentRepo,
+		batchRepo:      batchRepo,
+		businessRepo:   businessRepo,
+		smsService:     smsService,
+	}
+}
+
+func (s *studentAttendanceService) BulkMarkAttendance(ctx context.Context, businessID uint, req models.BulkStudentAttendanceRequest, actorID uint) ([]models.StudentAttendanceResponse, error) {
+	date, err := parseDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Entries) == 0 {
+		return nil, fmt.Errorf("no attendance entries provided")
+	}
+
+	// Check if business exists
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	if req.BatchID != nil {
+		batch, err := s.batchRepo.GetByID(ctx, *req.BatchID)
+		if err != nil || batch.BusinessID != businessID {
+			return nil, fmt.Errorf("batch not found in this business")
+		}
+	}
+
+	studentIDs := make([]uint, 0, len(req.Entries))
+	seen := make(map[uint]bool, len(req.Entries))
+	for _, entry := range req.Entries {
+		if seen[entry.StudentID] {
+			return nil, fmt.Errorf("student %d appears more than once", entry.StudentID)
+		}
+		seen[entry.StudentID] = true
+		studentIDs = append(studentIDs, entry.StudentID)
+	}
+
+	students, err := s.studentRepo.GetByIDs(ctx, studentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get students: %v", err)
+	}
+
+	studentsByID := make(map[uint]models.Student, len(students))
+	for _, student := range students {
+		if student.BusinessID == businessID {
+			studentsByID[student.ID] = student
+		}
+	}
+
+	records := make([]models.StudentAttendance, 0, len(req.Entries))
+	for _, entry := range req.Entries {
+		student, ok := studentsByID[entry.StudentID]
+		if !ok {
+			return nil, fmt.Errorf("student %d not found in this business", entry.StudentID)
+		}
+
+		batchID := student.BatchID
+		if req.BatchID != nil {
+			if student.BatchID == nil || *student.BatchID != *req.BatchID {
+				return nil, fmt.Errorf("student %d is not in this batch", entry.StudentID)
+			}
+			batchID = req.BatchID
+		}
+
+		records = append(records, models.StudentAttendance{
+			StudentID:  entry.StudentID,
+			BusinessID: businessID,
+			BatchID:    batchID,
+			Date:       date,
+			Status:     entry.Status,
+			Note:       entry.Note,
+			MarkedBy:   actorID,
+		})
+	}
+
+	tx := s.attendanceRepo.BeginTransaction(ctx)
+
+	if err := s.attendanceRepo.UpsertWithTransaction(tx, records); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to mark attendance: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit attendance: %v", err)
+	}
+
+	if business.SMSNotifications {
+		s.notifyAbsences(ctx, *business, records, studentsByID)
+	}
+
+	responses := make([]models.StudentAttendanceResponse, 0, len(records))
+	for _, record := range records {
+		record.Student = studentsByID[record.StudentID]
+		responses = append(responses, toStudentAttendanceResponse(record))
+	}
+
+	return responses, nil
+}
+
+// notifyAbsences texts the guardians of students marked absent. Only the current day's
+// attendance triggers messages, so back-filling old registers doesn't text anyone, and
+// each absence is texted once however often the day is re-marked. Failures are logged
+// rather than failing the attendance that was already saved.
+func (s *studentAttendanceService) notifyAbsences(ctx context.Context, business models.Business, records []models.StudentAttendance, studentsByID map[uint]models.Student) {
+	log := logger.FromContext(ctx)
+
+	today := todayIn(business.Timezone)
+	var absentIDs []uint
+	for _, record := range records {
+		if record.Status == "absent" && record.Date.Equal(today) {
+			absentIDs = append(absentIDs, record.StudentID)
+		}
+	}
+	if len(absentIDs) == 0 {
+		return
+	}
+
+	guardians, err := s.studentRepo.GetGuardiansByStudentIDs(ctx, absentIDs)
+	if err != nil {
+		log.Error("Failed to load guardians for absence SMS", "business_id", business.ID, "error", err)
+		return
+	}
+	phones := guardianPhones(guardians)
+
+	date := today.Format(models.DateFormat)
+	messages := make([]models.OutgoingSMS, 0, len(absentIDs))
+	for _, studentID := range absentIDs {
+		phone, ok := phones[studentID]
+		if !ok {
+			continue
+		}
+		messages = append(messages, models.OutgoingSMS{
+			StudentID: studentID,
+			To:        phone,
+			Body:      fmt.Sprintf("%s: %s was marked absent today (%s).", business.Name, studentsByID[studentID].Name, date),
+			DedupKey:  fmt.Sprintf("%s:%d:%s", models.SMSKindAbsence, studentID, date),
+		})
+	}
+
+	if _, err := s.smsService.Enqueue(ctx, business.ID, models.SMSKindAbsence, messages); err != nil && !errors.Is(err, ErrSMSDisabled) {
+		log.Error("Failed to queue absence SMS", "business_id", business.ID, "error", err)
+	}
+}
+
+func (s *studentAttendanceService) GetAttendance(ctx context.Context, filters repository.StudentAttendanceFilters) ([]models.StudentAttendanceResponse, int64, error) {
+	// Set default pagination
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.Limit == 0 {
+		filters.Limit = 31
+	}
+
+	if filters.From != "" {
+		if _, err := parseDate(filters.From); err != nil {
+			return nil, 0, fmt.Errorf("invalid from date: %v", err)
+		}
+	}
+	if filters.To != "" {
+		if _, err := parseDate(filters.To); err != nil {
+			return nil, 0, fmt.Errorf("invalid to date: %v", err)
+		}
+	}
+	if filters.From != "" && filters.To != "" && filters.From > filters.To {
+		return nil, 0, fmt.Errorf("from date must not be after to date")
+	}
+
+	records, total, err := s.attendanceRepo.GetAll(ctx, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get attendance: %v", err)
+	}
+
+	responses := []models.StudentAttendanceResponse{}
+	for _, record := range records {
+		responses = append(responses, toStudentAttendanceResponse(record))
+	}
+
+	return responses, total, nil
+}
+
+func (s *studentAttendanceService) GetStudentMonthlySummary(ctx context.Context, studentID uint, month string) (*models.StudentAttendanceSummary, error) {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("student not found")
+	}
+
+	start, end, err := parseAttendanceMonth(month, businessToday(ctx, s.businessRepo, student.BusinessID))
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.attendanceRepo.GetStatusCounts(ctx, repository.StudentAttendanceFilters{
+		StudentID: &student.ID,
+		From:      start.Format(models.DateFormat),
+		To:        end.Format(models.DateFormat),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance summary: %v", err)
+	}
+
+	summary := &models.StudentAttendanceSummary{
+		StudentID:   student.ID,
+		StudentName: student.Name,
+		Month:       start.Format("2006-01"),
+	}
+	for _, count := range counts {
+		addAttendanceCount(&summary.Present, &summary.Absent, &summary.Leave, count.Status, count.Count)
+	}
+	summary.TotalMarked, summary.AttendanceRate = studentAttendanceTotals(summary.Present, summary.Absent, summary.Leave)
+
+	return summary, nil
+}
+
+func (s *studentAttendanceService) GetBusinessMonthlySummary(ctx context.Context, businessID uint, batchID *uint, month string) (*models.BusinessStudentAttendanceSummary, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	start, end, err := parseAttendanceMonth(month, todayIn(business.Timezone))
+	if err != nil {
+		return nil, err
+	}
+
+	if batchID != nil {
+		batch, err := s.batchRepo.GetByID(ctx, *batchID)
+		if err != nil || batch.BusinessID != businessID {
+			return nil, fmt.Errorf("batch not found in this business")
+		}
+	}
+
+	counts, err := s.attendanceRepo.GetStatusCounts(ctx, repository.StudentAttendanceFilters{
+		BusinessID: &businessID,
+		BatchID:    batchID,
+		From:       start.Format(models.DateFormat),
+		To:         end.Format(models.DateFormat),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance summary: %v", err)
+	}
+
+	// Active students are listed even without any marked days
+	active := models.StatusActive
+	students, _, err := s.studentRepo.GetAll(ctx, repository.StudentFilters{
+		BusinessID: &businessID,
+		BatchID:    batchID,
+		Status:     &active,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get students: %v", err)
+	}
+
+	monthLabel := start.Format("2006-01")
+	byStudent := make(map[uint]*models.StudentAttendanceSummary)
+	var order []uint
+	for _, student := range students {
+		byStudent[student.ID] = &models.StudentAttendanceSummary{StudentID: student.ID, StudentName: student.Name, Month: monthLabel}
+		order = append(order, student.ID)
+	}
+
+	var missing []uint
+	for _, count := range counts {
+		if _, ok := byStudent[count.StudentID]; !ok {
+			byStudent[count.StudentID] = &models.StudentAttendanceSummary{StudentID: count.StudentID, Month: monthLabel}
+			order = append(order, count.StudentID)
+			missing = append(missing, count.StudentID)
+		}
+		studentSummary := byStudent[count.StudentID]
+		addAttendanceCount(&studentSummary.Present, &studentSummary.Absent, &studentSummary.Leave, count.Status, count.Count)
+	}
+
+	// Name students who have attendance this month but are no longer active or in the batch
+	if len(missing) > 0 {
+		others, err := s.studentRepo.GetByIDs(ctx, missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get students: %v", err)
+		}
+		for _, student := range others {
+			byStudent[student.ID].StudentName = student.Name
+		}
+	}
+
+	summary := &models.BusinessStudentAttendanceSummary{
+		BusinessID: businessID,
+		BatchID:    batchID,
+		Month:      monthLabel,
+		Students:   []models.StudentAttendanceSummary{},
+	}
+	for _, studentID := range order {
+		studentSummary := byStudent[studentID]
+		studentSummary.TotalMarked, studentSummary.AttendanceRate = studentAttendanceTotals(studentSummary.Present, studentSummary.Absent, studentSummary.Leave)
+
+		summary.Present += studentSummary.Present
+		summary.Absent += studentSummary.Absent
+		summary.Leave += studentSummary.Leave
+		summary.Students = append(summary.Students, *studentSummary)
+	}
+	summary.TotalMarked, summary.AttendanceRate = studentAttendanceTotals(summary.Present, summary.Absent, summary.Leave)
+
+	return summary, nil
+}
+
+func (s *studentAttendanceService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
+
+func (s *studentAttendanceService) CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("student not found")
+	}
+	return checkBusinessAccess(ctx, s.businessRepo, student.BusinessID, userID, role)
+}
+
+// Helper functions
+
+// studentAttendanceTotals returns the number of marked days and the share of them
+// the student was present, as a percentage
+func studentAttendanceTotals(present, absent, leave int64) (int64, float64) {
+	total := present + absent + leave
+	if total == 0 {
+		return 0, 0
+	}
+	return total, math.Round(float64(present)/float64(total)*10000) / 100
+}
+
+func toStudentAttendanceResponse(record models.StudentAttendance) models.StudentAttendanceResponse {
+	return models.StudentAttendanceResponse{
+		ID:          record.ID,
+		StudentID:   record.StudentID,
+		StudentName: record.Student.Name,
+		BusinessID:  record.BusinessID,
+		BatchID:     record.BatchID,
+		Date:        record.Date.Format(models.DateFormat),
+		Status:      record.Status,
+		Note:        record.Note,
+		MarkedBy:    record.MarkedBy,
+		UpdatedOn:   record.UpdatedOn,
+	}
+}
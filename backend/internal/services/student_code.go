@@ -0,0 +1,98 @@
+package services
+
+import (
+	"backend/internal/models"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// studentCodePrefixPattern is the form of a student code prefix: letters and digits
+var studentCodePrefixPattern = regexp.MustCompile(`^[A-Z0-9]{1,10}$`)
+
+// defaultStudentCodeDigits is how long the sequence of a student code is padded to by default
+const defaultStudentCodeDigits = 4
+
+func (s *studentService) GetStudentCodeSettings(ctx context.Context, businessID uint) (*models.StudentCodeSettings, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+	return toStudentCodeSettings(business), nil
+}
+
+func (s *studentService) UpdateStudentCodeSettings(ctx context.Context, businessID uint, req models.UpdateStudentCodeSettingsRequest) (*models.StudentCodeSettings, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	prefix := strings.ToUpper(strings.TrimSpace(*req.Prefix))
+	if prefix != "" && !studentCodePrefixPattern.MatchString(prefix) {
+		return nil, &FieldError{Field: "prefix", Message: "must be up to 10 letters and digits"}
+	}
+	business.StudentCodePrefix = prefix
+	if req.IncludeYear != nil {
+		business.StudentCodeYear = *req.IncludeYear
+	}
+	if req.Digits != nil {
+		business.StudentCodeDigits = *req.Digits
+	}
+
+	updates := map[string]interface{}{
+		"student_code_prefix": business.StudentCodePrefix,
+		"student_code_year":   business.StudentCodeYear,
+		"student_code_digits": business.StudentCodeDigits,
+	}
+	if err := s.businessRepo.UpdateFields(ctx, business.ID, updates); err != nil {
+		return nil, fmt.Errorf("failed to update settings: %w", err)
+	}
+	return toStudentCodeSettings(business), nil
+}
+
+// assignStudentCode gives the student the next code of the business's pattern, inside the
+// transaction creating the student, so a rolled back creation doesn't use up a number.
+// Students of a business without a code prefix get none.
+func (s *studentService) assignStudentCode(tx *gorm.DB, business *models.Business, student *models.Student) error {
+	if business.StudentCodePrefix == "" {
+		return nil
+	}
+
+	year := 0
+	if business.StudentCodeYear {
+		year = time.Now().Year()
+		if student.EnrolledOn != nil {
+			year = student.EnrolledOn.Year()
+		}
+	}
+
+	sequence, err := s.studentRepo.NextCodeSequenceWithTransaction(tx, business.ID, year)
+	if err != nil {
+		return fmt.Errorf("failed to number student: %v", err)
+	}
+	code := models.FormatStudentCode(*business, year, sequence)
+	student.StudentCode = &code
+	return nil
+}
+
+func toStudentCodeSettings(business *models.Business) *models.StudentCodeSettings {
+	if business.StudentCodeDigits <= 0 {
+		business.StudentCodeDigits = defaultStudentCodeDigits
+	}
+
+	settings := &models.StudentCodeSettings{
+		BusinessID:  business.ID,
+		Enabled:     business.StudentCodePrefix != "",
+		Prefix:      business.StudentCodePrefix,
+		IncludeYear: business.StudentCodeYear,
+		Digits:      business.StudentCodeDigits,
+	}
+	if settings.Enabled {
+		settings.Example = models.FormatStudentCode(*business, time.Now().Year(), 1)
+	}
+	return settings
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// studentExportColumns are the columns of a student CSV export. The names match the
+// import columns where they overlap, so an export can be edited and imported again.
+var studentExportColumns = []csvColumn[models.Student]{
+	{Name: "id", Value: func(s models.Student) string { return strconv.FormatUint(uint64(s.ID), 10) }},
+	{Name: "name", Value: func(s models.Student) string { return s.Name }},
+	{Name: "student_code", Value: func(s models.Student) string {
+		if s.StudentCode == nil {
+			return ""
+		}
+		return *s.StudentCode
+	}},
+	{Name: "email", Value: func(s models.Student) string { return s.User.Email }},
+	{Name: "phone", Value: func(s models.Student) string { return s.User.Phone }},
+	{Name: "status", Value: func(s models.Student) string { return formatExportStatus(s.Status) }},
+	{Name: "batch", Value: func(s models.Student) string {
+		if s.Batch == nil {
+			return ""
+		}
+		return s.Batch.Name
+	}},
+	{Name: "grade", Value: func(s models.Student) string { return s.Grade }},
+	{Name: "gender", Value: func(s models.Student) string { return s.Gender }},
+	{Name: "date_of_birth", Value: func(s models.Student) string { return formatExportDate(s.DateOfBirth, models.DateFormat) }},
+	{Name: "enrolled_on", Value: func(s models.Student) string { return formatExportDate(s.EnrolledOn, models.DateFormat) }},
+	{Name: "guardian_name", Value: func(s models.Student) string { return exportGuardian(s).Name }},
+	{Name: "guardian_phone", Value: func(s models.Student) string { return exportGuardian(s).Phone }},
+	{Name: "guardian_email", Value: func(s models.Student) string { return exportGuardian(s).Email }},
+	{Name: "guardian_relation", Value: func(s models.Student) string { return exportGuardian(s).Relation }},
+	{Name: "created_on", Value: func(s models.Student) string { return formatExportDate(&s.CreatedOn, models.DateFormat) }},
+}
+
+// StudentExportColumns validates the comma-separated columns parameter of a student
+// export, returning every column when it is empty
+func (s *studentService) StudentExportColumns(columns string) ([]string, error) {
+	resolved, err := resolveCSVColumns(parseCSVColumns(columns), studentExportColumns)
+	if err != nil {
+		return nil, err
+	}
+	return csvColumnNames(resolved), nil
+}
+
+// ExportStudents writes the business's students matching filters to w as CSV, reading
+// them in batches, and returns how many rows were written
+func (s *studentService) ExportStudents(ctx context.Context, businessID uint, filters repository.StudentFilters, columns []string, w io.Writer) (int64, error) {
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return 0, fmt.Errorf("business not found")
+	}
+
+	resolved, err := resolveCSVColumns(columns, studentExportColumns)
+	if err != nil {
+		return 0, err
+	}
+
+	export, err := newCSVExport(w, resolved)
+	if err != nil {
+		return 0, err
+	}
+
+	filters.BusinessID = &businessID
+	err = s.studentRepo.ForEachBatch(ctx, filters, exportBatchSize, export.writeBatch)
+	return export.rows, err
+}
+
+// exportGuardian is the guardian printed in an export: the primary guardian, or the
+// guardian fields of students saved before guardians had their own table
+func exportGuardian(student models.Student) models.StudentGuardian {
+	if len(student.Guardians) > 0 {
+		// Guardians are loaded primary first
+		return student.Guardians[0]
+	}
+	return models.StudentGuardian{
+		Name:  student.GuardianName,
+		Phone: student.GuardianNumber,
+		Email: student.GuardianEmail,
+	}
+}
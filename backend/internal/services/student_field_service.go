@@ -0,0 +1,299 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var studentFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type StudentFieldService interface {
+	GetSchema(ctx context.Context, businessID uint) (*models.StudentFieldSchema, error)
+	CreateField(ctx context.Context, businessID uint, req models.CreateStudentFieldRequest) (*models.StudentField, error)
+	UpdateField(ctx context.Context, businessID, fieldID uint, req models.UpdateStudentFieldRequest) (*models.StudentField, error)
+	DeleteField(ctx context.Context, businessID, fieldID uint) error
+	UpdateSettings(ctx context.Context, businessID uint, strict bool) (*models.StudentFieldSchema, error)
+
+	// Grades
+	GetGrades(ctx context.Context, businessID uint) ([]models.StudentGrade, error)
+	ReplaceGrades(ctx context.Context, businessID uint, names []string) ([]models.StudentGrade, error)
+
+	// GetBusinessIDByUser resolves the business owned by a business user
+	GetBusinessIDByUser(ctx context.Context, userID uint) (uint, error)
+}
+
+type studentFieldService struct {
+	fieldRepo    repository.StudentFieldRepository
+	businessRepo repository.BusinessRepository
+}
+
+func NewStudentFieldService(fieldRepo repository.StudentFieldRepository, businessRepo repository.BusinessRepository) StudentFieldService {
+	return &studentFieldService{
+		fieldRepo:    fieldRepo,
+		businessRepo: businessRepo,
+	}
+}
+
+func (s *studentFieldService) GetSchema(ctx context.Context, businessID uint) (*models.StudentFieldSchema, error) {
+	return loadStudentFieldSchema(ctx, s.fieldRepo, s.businessRepo, businessID)
+}
+
+func (s *studentFieldService) CreateField(ctx context.Context, businessID uint, req models.CreateStudentFieldRequest) (*models.StudentField, error) {
+	key := strings.TrimSpace(req.Key)
+	if !studentFieldKeyPattern.MatchString(key) {
+		return nil, fmt.Errorf("key must start with a lowercase letter and contain only lowercase letters, digits and underscores")
+	}
+
+	label := strings.TrimSpace(req.Label)
+	if label == "" {
+		return nil, fmt.Errorf("label is required")
+	}
+
+	exists, err := s.fieldRepo.FieldKeyExists(ctx, businessID, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check field key: %v", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("field %q already exists for this business", key)
+	}
+
+	field := &models.StudentField{
+		BusinessID:      businessID,
+		Key:             key,
+		Label:           label,
+		Type:            req.Type,
+		Required:        req.Required,
+		StudentEditable: req.StudentEditable,
+	}
+
+	if err := s.fieldRepo.Create(ctx, field); err != nil {
+		return nil, fmt.Errorf("failed to create field: %v", err)
+	}
+
+	return field, nil
+}
+
+func (s *studentFieldService) UpdateField(ctx context.Context, businessID, fieldID uint, req models.UpdateStudentFieldRequest) (*models.StudentField, error) {
+	field, err := s.getBusinessField(ctx, businessID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	if label := strings.TrimSpace(req.Label); label != "" {
+		field.Label = label
+	}
+	if req.Required != nil {
+		field.Required = *req.Required
+	}
+	if req.StudentEditable != nil {
+		field.StudentEditable = *req.StudentEditable
+	}
+
+	if err := s.fieldRepo.Update(ctx, field); err != nil {
+		return nil, fmt.Errorf("failed to update field: %v", err)
+	}
+
+	return field, nil
+}
+
+func (s *studentFieldService) DeleteField(ctx context.Context, businessID, fieldID uint) error {
+	field, err := s.getBusinessField(ctx, businessID, fieldID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.fieldRepo.Delete(ctx, field.ID); err != nil {
+		return fmt.Errorf("failed to delete field: %v", err)
+	}
+
+	return nil
+}
+
+func (s *studentFieldService) UpdateSettings(ctx context.Context, businessID uint, strict bool) (*models.StudentFieldSchema, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	if err := s.businessRepo.UpdateFields(ctx, business.ID, map[string]interface{}{"strict_student_fields": strict}); err != nil {
+		return nil, fmt.Errorf("failed to update settings: %v", err)
+	}
+
+	return s.GetSchema(ctx, businessID)
+}
+
+func (s *studentFieldService) GetGrades(ctx context.Context, businessID uint) ([]models.StudentGrade, error) {
+	grades, err := s.fieldRepo.GetGrades(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grades: %v", err)
+	}
+	if grades == nil {
+		grades = []models.StudentGrade{}
+	}
+	return grades, nil
+}
+
+func (s *studentFieldService) ReplaceGrades(ctx context.Context, businessID uint, names []string) ([]models.StudentGrade, error) {
+	grades := make([]models.StudentGrade, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("grade %d has no name", i+1)
+		}
+		if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("grade %q is listed more than once", name)
+		}
+		seen[strings.ToLower(name)] = true
+
+		grades = append(grades, models.StudentGrade{
+			BusinessID: businessID,
+			Name:       name,
+			Position:   i + 1,
+		})
+	}
+
+	if err := s.fieldRepo.ReplaceGrades(ctx, businessID, grades); err != nil {
+		return nil, fmt.Errorf("failed to save grades: %v", err)
+	}
+
+	return s.GetGrades(ctx, businessID)
+}
+
+func (s *studentFieldService) GetBusinessIDByUser(ctx context.Context, userID uint) (uint, error) {
+	return ownBusinessID(ctx, s.businessRepo, userID)
+}
+
+func (s *studentFieldService) getBusinessField(ctx context.Context, businessID, fieldID uint) (*models.StudentField, error) {
+	field, err := s.fieldRepo.GetByID(ctx, fieldID)
+	if err != nil || field.BusinessID != businessID {
+		return nil, fmt.Errorf("field not found")
+	}
+	return field, nil
+}
+
+// Helper functions
+
+func loadStudentFieldSchema(ctx context.Context, fieldRepo repository.StudentFieldRepository, businessRepo repository.BusinessRepository, businessID uint) (*models.StudentFieldSchema, error) {
+	business, err := businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	fields, err := fieldRepo.GetByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get student fields: %v", err)
+	}
+	if fields == nil {
+		fields = []models.StudentField{}
+	}
+
+	return &models.StudentFieldSchema{
+		BusinessID: businessID,
+		Strict:     business.StrictStudentFields,
+		Fields:     fields,
+	}, nil
+}
+
+// validateInformation checks student information against a business's field definitions:
+// defined keys must hold a value of their type, required keys must be present, and
+// undefined keys are rejected when the schema is strict.
+func validateInformation(info models.JSONB, schema *models.StudentFieldSchema) error {
+	defined := make(map[string]bool, len(schema.Fields))
+	for _, field := range schema.Fields {
+		defined[field.Key] = true
+
+		value, ok := info[field.Key]
+		if !ok || value == nil || value == "" {
+			if field.Required {
+				return fmt.Errorf("information field %q (%s) is required", field.Key, field.Label)
+			}
+			continue
+		}
+		if err := checkStudentFieldValue(field, value); err != nil {
+			return err
+		}
+	}
+
+	if schema.Strict {
+		var unknown []string
+		for key := range info {
+			if !defined[key] {
+				unknown = append(unknown, key)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return fmt.Errorf("unknown information fields: %s", strings.Join(unknown, ", "))
+		}
+	}
+
+	return nil
+}
+
+func checkStudentFieldValue(field models.StudentField, value interface{}) error {
+	valid := false
+	switch field.Type {
+	case models.StudentFieldText:
+		_, valid = value.(string)
+	case models.StudentFieldNumber:
+		switch value.(type) {
+		case float64, float32, int, int64:
+			valid = true
+		}
+	case models.StudentFieldBoolean:
+		_, valid = value.(bool)
+	case models.StudentFieldDate:
+		if date, ok := value.(string); ok {
+			_, err := parseDate(date)
+			valid = err == nil
+		}
+	}
+	if !valid {
+		return fmt.Errorf("information field %q must be a %s", field.Key, field.Type)
+	}
+	return nil
+}
+
+// parseStudentFieldValue converts a raw text value, e.g. from a CSV cell, to the field's type
+func parseStudentFieldValue(field models.StudentField, raw string) (interface{}, error) {
+	switch field.Type {
+	case models.StudentFieldNumber:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("information field %q must be a number", field.Key)
+		}
+		return number, nil
+	case models.StudentFieldBoolean:
+		switch strings.ToLower(raw) {
+		case "true", "yes", "1":
+			return true, nil
+		case "false", "no", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("information field %q must be a boolean", field.Key)
+	}
+	return raw, nil
+}
+
+// mergeInformation applies a partial update to stored information; null values remove keys
+func mergeInformation(current, patch models.JSONB) models.JSONB {
+	merged := make(models.JSONB, len(current)+len(patch))
+	for key, value := range current {
+		merged[key] = value
+	}
+	for key, value := range patch {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
@@ -0,0 +1,396 @@
+package services
+
+import (
+	"backend/internal/models"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxStudentImportRows limits the size of a single CSV import
+const MaxStudentImportRows = 1000
+
+// studentImportInfoPrefix marks columns that go into the student's information, e.g. "info.school"
+const studentImportInfoPrefix = "info."
+
+var studentImportColumns = []string{"name", "email", "phone", "guardian_name", "guardian_phone", "guardian_email", "guardian_relation", "batch", "enrolled_on", studentImportInfoPrefix + "<key>"}
+
+// studentImportRow is a parsed and validated CSV row
+type studentImportRow struct {
+	result      *models.StudentImportRowResult
+	name        string
+	email       string
+	phone       string
+	guardians   []models.StudentGuardian
+	batchID     *uint
+	enrolledOn  time.Time
+	information models.JSONB
+}
+
+// ImportStudents validates every CSV row and, unless dryRun is set, creates a user and
+// student for each valid row in its own transaction. Invalid rows and rows matching an
+// existing student's name and guardian phone are skipped.
+func (s *studentService) ImportStudents(ctx context.Context, businessID uint, content io.Reader, dryRun bool) (*models.StudentImportReport, error) {
+	// Check if business exists
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	rows, err := s.parseStudentImport(ctx, business, content)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.StudentImportReport{
+		BusinessID: businessID,
+		DryRun:     dryRun,
+		TotalRows:  len(rows),
+		Rows:       make([]models.StudentImportRowResult, 0, len(rows)),
+	}
+
+	for _, row := range rows {
+		switch {
+		case len(row.result.Errors) > 0:
+			row.result.Status = models.ImportRowError
+			report.Failed++
+		case row.result.Status == models.ImportRowDuplicate:
+			report.Duplicates++
+		default:
+			row.result.Status = models.ImportRowValid
+			report.ValidRows++
+
+			if !dryRun {
+				if err := s.createImportedStudent(ctx, business, row); err != nil {
+					row.result.Status = models.ImportRowError
+					row.result.Errors = append(row.result.Errors, err.Error())
+					report.Failed++
+				} else {
+					report.Created++
+				}
+			}
+		}
+
+		report.Rows = append(report.Rows, *row.result)
+	}
+
+	return report, nil
+}
+
+func (s *studentService) parseStudentImport(ctx context.Context, business *models.Business, content io.Reader) ([]studentImportRow, error) {
+	businessID := business.ID
+
+	reader := csv.NewReader(content)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+
+	columns := make(map[string]int)
+	infoColumns := make(map[string]int)
+	for i, column := range header {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if strings.HasPrefix(name, studentImportInfoPrefix) {
+			// Keep the key as written, only the prefix is case-insensitive
+			key := strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))[len(studentImportInfoPrefix):]
+			if key != "" {
+				infoColumns[key] = i
+			}
+			continue
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("CSV header must include the name column (supported columns: %s)", strings.Join(studentImportColumns, ", "))
+	}
+
+	field := func(record []string, i int, ok bool) string {
+		if ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	column := func(record []string, name string) string {
+		i, ok := columns[name]
+		return field(record, i, ok)
+	}
+
+	// Information columns are typed by the business's custom student fields
+	schema, err := loadStudentFieldSchema(ctx, s.fieldRepo, s.businessRepo, businessID)
+	if err != nil {
+		return nil, err
+	}
+	fieldsByKey := make(map[string]models.StudentField, len(schema.Fields))
+	for _, field := range schema.Fields {
+		fieldsByKey[field.Key] = field
+	}
+
+	// Batches may be given by name or ID
+	batches, err := s.batchRepo.GetByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batches: %v", err)
+	}
+	batchIDs := make(map[string]uint, len(batches)*2)
+	for _, batch := range batches {
+		batchIDs[strings.ToLower(batch.Name)] = batch.ID
+		batchIDs[strconv.FormatUint(uint64(batch.ID), 10)] = batch.ID
+	}
+
+	// Enrollment defaults to today where the centre is
+	today := todayIn(business.Timezone)
+
+	var rows []studentImportRow
+	seenEmails := make(map[string]int)
+	seenStudents := make(map[string]int)
+	line := 1
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV on line %d: %v", line, err)
+		}
+
+		// Skip blank lines
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		if len(rows) >= MaxStudentImportRows {
+			return nil, fmt.Errorf("CSV file has more than %d rows", MaxStudentImportRows)
+		}
+
+		row := studentImportRow{
+			name:        column(record, "name"),
+			email:       strings.ToLower(column(record, "email")),
+			phone:       column(record, "phone"),
+			enrolledOn:  today,
+			information: make(models.JSONB),
+		}
+		row.result = &models.StudentImportRowResult{
+			Row:   line,
+			Name:  row.name,
+			Email: row.email,
+		}
+		addError := func(message string) {
+			row.result.Errors = append(row.result.Errors, message)
+		}
+
+		if row.name == "" {
+			addError("name is required")
+		}
+
+		if row.email != "" {
+			if err := validateEmail(row.email); err != nil {
+				addError("email is invalid")
+			} else if firstLine, ok := seenEmails[row.email]; ok {
+				addError(fmt.Sprintf("email is duplicated on line %d", firstLine))
+			} else {
+				seenEmails[row.email] = line
+				exists, err := s.userRepo.EmailExists(ctx, row.email)
+				if err != nil {
+					return nil, fmt.Errorf("failed to check email: %v", err)
+				}
+				if exists {
+					addError("email is already in use")
+				}
+			}
+		}
+
+		if row.phone != "" {
+			phone, err := normalizePhone(row.phone)
+			if err != nil {
+				addError(fmt.Sprintf("phone: %v", err))
+			}
+			row.phone = phone
+		}
+
+		guardianReqs := legacyGuardianRequest(column(record, "guardian_name"), column(record, "guardian_phone"), column(record, "guardian_email"))
+		if len(guardianReqs) > 0 {
+			guardianReqs[0].Relation = column(record, "guardian_relation")
+			guardians, err := buildGuardians(guardianReqs)
+			if err != nil {
+				addError(err.Error())
+			}
+			row.guardians = guardians
+		}
+
+		if batch := column(record, "batch"); batch != "" {
+			if id, ok := batchIDs[strings.ToLower(batch)]; ok {
+				row.batchID = &id
+			} else {
+				addError(fmt.Sprintf("batch %q not found in this business", batch))
+			}
+		}
+
+		if enrolledOn := column(record, "enrolled_on"); enrolledOn != "" {
+			date, err := parseDate(enrolledOn)
+			if err != nil {
+				addError(fmt.Sprintf("enrolled_on: %v", err))
+			}
+			row.enrolledOn = date
+		}
+
+		for key, i := range infoColumns {
+			value := field(record, i, true)
+			if value == "" {
+				continue
+			}
+			if definition, ok := fieldsByKey[key]; ok {
+				typed, err := parseStudentFieldValue(definition, value)
+				if err != nil {
+					addError(err.Error())
+					continue
+				}
+				row.information[key] = typed
+				continue
+			}
+			row.information[key] = value
+		}
+		if err := validateInformation(row.information, schema); err != nil {
+			addError(err.Error())
+		}
+
+		// Flag twins of existing students or of earlier rows instead of creating them
+		if len(row.result.Errors) == 0 && len(row.guardians) > 0 && row.guardians[0].Phone != "" {
+			phone := row.guardians[0].Phone
+			key := strings.ToLower(row.name) + "|" + phone
+			if firstLine, ok := seenStudents[key]; ok {
+				addError(fmt.Sprintf("same name and guardian phone as line %d", firstLine))
+			} else {
+				seenStudents[key] = line
+				existing, err := s.studentRepo.GetByNameAndGuardianPhone(ctx, businessID, row.name, phone)
+				if err != nil {
+					return nil, fmt.Errorf("failed to check for duplicates: %v", err)
+				}
+				if len(existing) > 0 {
+					row.result.Status = models.ImportRowDuplicate
+					row.result.DuplicateOf = existing[0].ID
+				}
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("CSV file has no data rows")
+	}
+
+	return rows, nil
+}
+
+// createImportedStudent creates the user, student and guardians of one row in a transaction
+func (s *studentService) createImportedStudent(ctx context.Context, business *models.Business, row studentImportRow) error {
+	password, err := generatePassword(12)
+	if err != nil {
+		return fmt.Errorf("failed to generate password: %v", err)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	email := row.email
+	if email == "" {
+		if email, err = generateStudentEmail(business, row.name); err != nil {
+			return fmt.Errorf("failed to generate email: %v", err)
+		}
+	}
+
+	tx := s.studentRepo.BeginTransaction(ctx)
+
+	user := &models.User{
+		Name:     row.name,
+		Email:    email,
+		Phone:    row.phone,
+		Password: string(hashedPassword),
+		Role:     models.RoleStudent,
+		Status:   models.StatusActive,
+	}
+	if err := s.userRepo.CreateUserInTransaction(tx, user); err != nil {
+		tx.Rollback()
+		if conflict := conflictError(err); conflict != nil {
+			return conflict
+		}
+		return fmt.Errorf("failed to create user: %v", err)
+	}
+
+	enrolledOn := row.enrolledOn
+	student := &models.Student{
+		Name:        row.name,
+		UserID:      user.ID,
+		BusinessID:  business.ID,
+		Information: row.information,
+		Status:      models.StatusActive,
+		BatchID:     row.batchID,
+		EnrolledOn:  &enrolledOn,
+	}
+	setLegacyGuardian(student, row.guardians)
+
+	// Rows are created one after another, so codes follow the order of the file
+	if err := s.assignStudentCode(tx, business, student); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.studentRepo.CreateWithTransaction(tx, student); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to create student: %v", err)
+	}
+
+	if err := s.studentRepo.ReplaceGuardiansWithTransaction(tx, student.ID, row.guardians); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to save guardians: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit student: %v", err)
+	}
+
+	row.result.Status = models.ImportRowCreated
+	row.result.Email = email
+	row.result.UserID = user.ID
+	row.result.StudentID = student.ID
+	if student.StudentCode != nil {
+		row.result.StudentCode = *student.StudentCode
+	}
+	row.result.GeneratedPassword = password
+
+	return nil
+}
+
+// generateStudentEmail makes up a login email for students imported without one
+func generateStudentEmail(business *models.Business, name string) (string, error) {
+	suffix, err := generatePassword(6)
+	if err != nil {
+		return "", err
+	}
+
+	local := generateSlugFromName(name)
+	if local == "" {
+		local = "student"
+	}
+	domain := business.Slug
+	if domain == "" {
+		domain = fmt.Sprintf("business-%d", business.ID)
+	}
+
+	return fmt.Sprintf("%s.%s@%s.students.local", local, strings.ToLower(suffix), domain), nil
+}
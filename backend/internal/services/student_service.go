@@ -3,39 +3,58 @@ package services
 import (
 	"backend/internal/models"
 	"backend/internal/repository"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 )
 
 type StudentService interface {
 	// Basic CRUD operations
-	CreateStudent(req models.CreateStudentRequest) (*models.StudentResponse, error)
-	GetStudentByID(id uint) (*models.StudentResponse, error)
-	GetStudentByUserID(userID uint) (*models.StudentResponse, error)
-	GetStudents(filters repository.StudentFilters) ([]models.StudentResponse, int64, error)
-	UpdateStudent(studentID uint, updates map[string]interface{}) (*models.StudentResponse, error)
-	DeleteStudent(studentID uint) error
+	CreateStudent(ctx context.Context, req models.CreateStudentRequest) (*models.StudentResponse, error)
+	GetStudentByID(ctx context.Context, id uint) (*models.StudentResponse, error)
+	GetStudentByUserID(ctx context.Context, userID uint) (*models.StudentResponse, error)
+	GetMyStudentProfile(ctx context.Context, userID uint) (*models.StudentProfileResponse, error)
+	UpdateMyStudentProfile(ctx context.Context, userID uint, req models.UpdateMyStudentProfileRequest) (*models.StudentProfileResponse, error)
+	GetStudents(ctx context.Context, filters repository.StudentFilters) ([]models.StudentResponse, repository.PageInfo, error)
+	UpdateStudent(ctx context.Context, studentID uint, updates map[string]interface{}, actorID uint) (*models.StudentResponse, error)
+	DeleteStudent(ctx context.Context, studentID uint, opts models.DeleteProfileOptions) error
+	RestoreStudent(ctx context.Context, studentID uint) (*models.StudentResponse, error)
+	// PurgeDeletedStudents permanently removes the students soft-deleted before cutoff,
+	// except those with fee payments
+	PurgeDeletedStudents(ctx context.Context, cutoff time.Time) (int, error)
 
 	// Business specific operations
-	GetStudentsByBusiness(businessID uint, filters repository.StudentFilters) ([]models.StudentResponse, int64, error)
-	GetActiveStudentsByBusiness(businessID uint) ([]models.StudentResponse, error)
-	GetInactiveStudentsByBusiness(businessID uint) ([]models.StudentResponse, error)
+	GetStudentsByBusiness(ctx context.Context, businessID uint, filters repository.StudentFilters) ([]models.StudentResponse, repository.PageInfo, error)
 
 	// Status operations
-	ChangeStudentStatus(studentID uint, status int) error
-	GetActiveStudents() ([]models.StudentResponse, error)
-	GetInactiveStudents() ([]models.StudentResponse, error)
+	ChangeStudentStatus(ctx context.Context, studentID uint, status models.Status, actorID uint) error
 
 	// Search
-	SearchStudents(searchTerm string, limit int, businessID ...uint) ([]models.StudentResponse, error)
-	SearchStudentsByBusiness(businessID uint, searchTerm string, limit int) ([]models.StudentResponse, error)
+	SearchStudents(ctx context.Context, search repository.StudentSearch, limit int, businessID ...uint) ([]models.StudentResponse, error)
+	SearchStudentsByBusiness(ctx context.Context, businessID uint, search repository.StudentSearch, limit int) ([]models.StudentResponse, error)
 
 	// Statistics
-	GetStudentStats(businessID ...uint) (map[string]interface{}, error)
-	GetGuardianStats(businessID ...uint) (map[string]interface{}, error)
+	GetRecentStudents(ctx context.Context, limit int, businessID ...uint) (*models.RecentProfiles, error)
+	GetStudentStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error)
+	GetGuardianStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error)
 
 	// Bulk operations
-	BulkUpdateStudentStatus(studentIDs []uint, status int) error
+	BulkUpdateStudentStatus(ctx context.Context, studentIDs []uint, status models.Status, atomic bool, actorID uint) (*models.BulkUpdateResult, error)
+	BulkDeleteStudents(ctx context.Context, req models.BulkDeleteStudentsRequest, userID uint, role string) ([]models.BulkDeleteResult, error)
+	ImportStudents(ctx context.Context, businessID uint, content io.Reader, dryRun bool) (*models.StudentImportReport, error)
+	StudentExportColumns(columns string) ([]string, error)
+	ExportStudents(ctx context.Context, businessID uint, filters repository.StudentFilters, columns []string, w io.Writer) (int64, error)
+
+	// Student codes
+	GetStudentCodeSettings(ctx context.Context, businessID uint) (*models.StudentCodeSettings, error)
+	UpdateStudentCodeSettings(ctx context.Context, businessID uint, req models.UpdateStudentCodeSettingsRequest) (*models.StudentCodeSettings, error)
+
+	// Access control
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+	GetBusinessIDByUser(ctx context.Context, userID uint) (uint, error)
 
 	// Validation
 	ValidateCreateStudentRequest(req models.CreateStudentRequest) error
@@ -46,29 +65,43 @@ type studentService struct {
 	studentRepo  repository.StudentRepository
 	userRepo     repository.UserRepository
 	businessRepo repository.BusinessRepository
+	batchRepo    repository.BatchRepository
+	fieldRepo    repository.StudentFieldRepository
 }
 
-func NewStudentService(studentRepo repository.StudentRepository, userRepo repository.UserRepository, businessRepo repository.BusinessRepository) StudentService {
+func NewStudentService(studentRepo repository.StudentRepository, userRepo repository.UserRepository, businessRepo repository.BusinessRepository, batchRepo repository.BatchRepository, fieldRepo repository.StudentFieldRepository) StudentService {
 	return &studentService{
 		studentRepo:  studentRepo,
 		userRepo:     userRepo,
 		businessRepo: businessRepo,
+		batchRepo:    batchRepo,
+		fieldRepo:    fieldRepo,
 	}
 }
 
-func (s *studentService) CreateStudent(req models.CreateStudentRequest) (*models.StudentResponse, error) {
+func (s *studentService) CreateStudent(ctx context.Context, req models.CreateStudentRequest) (*models.StudentResponse, error) {
 	// Validate request
 	if err := s.ValidateCreateStudentRequest(req); err != nil {
 		return nil, err
 	}
 
 	// Check if user exists and is not already a student
-	if _, err := s.userRepo.GetByID(req.UserID); err != nil {
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
 		return nil, fmt.Errorf("user not found")
 	}
 
+	// Only student accounts hold a student profile; a teacher can't be a student
+	// as well
+	if !user.Role.CanHoldProfile(models.RoleStudent) {
+		if user.Role == models.RoleTeacher {
+			return nil, fmt.Errorf("user is already a teacher")
+		}
+		return nil, fmt.Errorf("a %s user can't hold a student profile", user.Role)
+	}
+
 	// Check if user is already a student
-	exists, err := s.studentRepo.StudentUserExists(req.UserID)
+	exists, err := s.studentRepo.StudentUserExists(ctx, req.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check if user is already a student")
 	}
@@ -77,7 +110,7 @@ func (s *studentService) CreateStudent(req models.CreateStudentRequest) (*models
 	}
 
 	// Check if business exists
-	_, err = s.businessRepo.GetByID(req.BusinessID)
+	business, err := s.businessRepo.GetByID(ctx, req.BusinessID)
 	if err != nil {
 		return nil, fmt.Errorf("business not found")
 	}
@@ -86,25 +119,87 @@ func (s *studentService) CreateStudent(req models.CreateStudentRequest) (*models
 	if req.Information == nil {
 		req.Information = make(models.JSONB)
 	}
+	if err := s.validateStudentInformation(ctx, req.BusinessID, req.Information); err != nil {
+		return nil, err
+	}
+
+	// Enrollment defaults to today where the centre is
+	enrolledOn := businessToday(ctx, s.businessRepo, req.BusinessID)
+	if req.EnrolledOn != "" {
+		date, err := parseDate(req.EnrolledOn)
+		if err != nil {
+			return nil, err
+		}
+		enrolledOn = date
+	}
+
+	if req.BatchID != nil {
+		batch, err := s.batchRepo.GetByID(ctx, *req.BatchID)
+		if err != nil || batch.BusinessID != req.BusinessID {
+			return nil, fmt.Errorf("batch not found in this business")
+		}
+	}
+
+	dateOfBirth, err := parseDateOfBirth(req.DateOfBirth)
+	if err != nil {
+		return nil, err
+	}
+	grade, err := s.validateGrade(ctx, req.BusinessID, req.Grade)
+	if err != nil {
+		return nil, err
+	}
+
+	// Older clients send a single guardian in the guardian_* fields
+	guardianReqs := req.Guardians
+	if len(guardianReqs) == 0 {
+		guardianReqs = legacyGuardianRequest(req.GuardianName, req.GuardianNumber, req.GuardianEmail)
+	}
+	guardians, err := buildGuardians(guardianReqs)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create student
 	student := &models.Student{
-		Name:           req.Name,
-		UserID:         req.UserID,
-		BusinessID:     req.BusinessID,
-		GuardianName:   req.GuardianName,
-		GuardianNumber: req.GuardianNumber,
-		GuardianEmail:  req.GuardianEmail,
-		Information:    req.Information,
-		Status:         1, // Active by default
+		Name:        req.Name,
+		UserID:      req.UserID,
+		BusinessID:  req.BusinessID,
+		Information: req.Information,
+		Status:      models.StatusActive, // Active by default
+		BatchID:     req.BatchID,
+		EnrolledOn:  &enrolledOn,
+		DateOfBirth: dateOfBirth,
+		Gender:      req.Gender,
+		Grade:       grade,
 	}
+	setLegacyGuardian(student, guardians)
+
+	tx := s.studentRepo.BeginTransaction(ctx)
 
-	if err := s.studentRepo.Create(student); err != nil {
+	if err := s.assignStudentCode(tx, business, student); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.studentRepo.CreateWithTransaction(tx, student); err != nil {
+		tx.Rollback()
+		if conflict := conflictError(err); conflict != nil {
+			return nil, conflict
+		}
 		return nil, fmt.Errorf("failed to create student: %v", err)
 	}
 
+	if err := s.studentRepo.ReplaceGuardiansWithTransaction(tx, student.ID, guardians); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to save guardians: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit student: %v", err)
+	}
+
 	// Get student with relations
-	studentWithRelations, err := s.studentRepo.GetStudentWithRelations(student.ID)
+	studentWithRelations, err := s.studentRepo.GetStudentWithRelations(ctx, student.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get created student")
 	}
@@ -112,8 +207,8 @@ func (s *studentService) CreateStudent(req models.CreateStudentRequest) (*models
 	return s.toStudentResponse(studentWithRelations), nil
 }
 
-func (s *studentService) GetStudentByID(id uint) (*models.StudentResponse, error) {
-	student, err := s.studentRepo.GetStudentWithRelations(id)
+func (s *studentService) GetStudentByID(ctx context.Context, id uint) (*models.StudentResponse, error) {
+	student, err := s.studentRepo.GetStudentWithRelations(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("student not found")
 	}
@@ -121,14 +216,14 @@ func (s *studentService) GetStudentByID(id uint) (*models.StudentResponse, error
 	return s.toStudentResponse(student), nil
 }
 
-func (s *studentService) GetStudentByUserID(userID uint) (*models.StudentResponse, error) {
-	student, err := s.studentRepo.GetByUserID(userID)
+func (s *studentService) GetStudentByUserID(ctx context.Context, userID uint) (*models.StudentResponse, error) {
+	student, err := s.studentRepo.GetByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("student profile not found")
 	}
 
 	// Get with relations
-	studentWithRelations, err := s.studentRepo.GetStudentWithRelations(student.ID)
+	studentWithRelations, err := s.studentRepo.GetStudentWithRelations(ctx, student.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get student details")
 	}
@@ -136,18 +231,72 @@ func (s *studentService) GetStudentByUserID(userID uint) (*models.StudentRespons
 	return s.toStudentResponse(studentWithRelations), nil
 }
 
-func (s *studentService) GetStudents(filters repository.StudentFilters) ([]models.StudentResponse, int64, error) {
-	// Set default pagination
-	if filters.Page == 0 {
-		filters.Page = 1
+// GetMyStudentProfile returns the caller's own student profile as shown to the student
+func (s *studentService) GetMyStudentProfile(ctx context.Context, userID uint) (*models.StudentProfileResponse, error) {
+	student, err := s.studentRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("student profile not found")
 	}
-	if filters.Limit == 0 {
-		filters.Limit = 10
+
+	studentWithRelations, err := s.studentRepo.GetStudentWithRelations(ctx, student.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get student details")
 	}
 
-	students, total, err := s.studentRepo.GetAllWithRelations(filters)
+	return toStudentProfileResponse(studentWithRelations), nil
+}
+
+// UpdateMyStudentProfile lets a student change their name and the information fields
+// their business marked student_editable. Guardians and enrollment are staff-controlled.
+func (s *studentService) UpdateMyStudentProfile(ctx context.Context, userID uint, req models.UpdateMyStudentProfileRequest) (*models.StudentProfileResponse, error) {
+	student, err := s.studentRepo.GetByUserID(ctx, userID)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get students: %v", err)
+		return nil, fmt.Errorf("student profile not found")
+	}
+
+	updates := make(map[string]interface{})
+	if name := strings.TrimSpace(req.Name); name != "" {
+		updates["name"] = name
+	}
+	if len(req.Information) > 0 {
+		if err := s.checkStudentEditable(ctx, student.BusinessID, req.Information); err != nil {
+			return nil, err
+		}
+		updates["information"] = req.Information
+	}
+
+	if _, err := s.UpdateStudent(ctx, student.ID, updates, userID); err != nil {
+		return nil, err
+	}
+
+	return s.GetMyStudentProfile(ctx, userID)
+}
+
+// checkStudentEditable rejects information keys the business did not open to students
+func (s *studentService) checkStudentEditable(ctx context.Context, businessID uint, info models.JSONB) error {
+	fields, err := s.fieldRepo.GetByBusinessID(ctx, businessID)
+	if err != nil {
+		return fmt.Errorf("failed to get student fields: %v", err)
+	}
+
+	editable := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		editable[field.Key] = field.StudentEditable
+	}
+	for key := range info {
+		if !editable[key] {
+			return &FieldError{Field: "information." + key, Message: "cannot be changed from the student profile"}
+		}
+	}
+	return nil
+}
+
+func (s *studentService) GetStudents(ctx context.Context, filters repository.StudentFilters) ([]models.StudentResponse, repository.PageInfo, error) {
+	applyPageDefaults(&filters.Page, &filters.Limit)
+
+	students, total, err := s.studentRepo.GetAllWithRelations(ctx, filters)
+	if err != nil {
+		return nil, repository.PageInfo{}, fmt.Errorf("failed to get students: %w", err)
 	}
 
 	var responses []models.StudentResponse
@@ -155,60 +304,148 @@ func (s *studentService) GetStudents(filters repository.StudentFilters) ([]model
 		responses = append(responses, *s.toStudentResponse(&student))
 	}
 
-	return responses, total, nil
+	return responses, filters.PageInfo(total, students), nil
 }
 
-func (s *studentService) UpdateStudent(studentID uint, updates map[string]interface{}) (*models.StudentResponse, error) {
+func (s *studentService) UpdateStudent(ctx context.Context, studentID uint, updates map[string]interface{}, actorID uint) (*models.StudentResponse, error) {
 	// Get existing student
-	student, err := s.studentRepo.GetByID(studentID)
+	student, err := s.studentRepo.GetByID(ctx, studentID)
 	if err != nil {
 		return nil, fmt.Errorf("student not found")
 	}
+	previousStatus := student.Status
+
+	version, versioned := expectedVersion(updates)
+	if versioned && version != student.Version {
+		return nil, s.versionConflict(ctx, student.ID)
+	}
+
+	// Fields named in the profile_updated history entry
+	var changedFields []string
 
 	// Update fields
 	if name, ok := updates["name"]; ok {
-		if nameStr, ok := name.(string); ok && nameStr != "" {
+		if nameStr, ok := name.(string); ok && nameStr != "" && nameStr != student.Name {
 			student.Name = nameStr
+			changedFields = append(changedFields, "name")
 		}
 	}
 
-	if guardianName, ok := updates["guardian_name"]; ok {
-		if guardianNameStr, ok := guardianName.(string); ok {
-			student.GuardianName = guardianNameStr
-		}
+	guardians, guardiansChanged, err := s.updatedGuardians(ctx, student.ID, updates)
+	if err != nil {
+		return nil, err
+	}
+	if guardiansChanged {
+		setLegacyGuardian(student, guardians)
+		changedFields = append(changedFields, "guardians")
 	}
 
-	if guardianNumber, ok := updates["guardian_number"]; ok {
-		if guardianNumberStr, ok := guardianNumber.(string); ok {
-			student.GuardianNumber = guardianNumberStr
+	// Information is patched key by key so a partial payload keeps the other custom fields
+	if information, ok := updates["information"]; ok {
+		if infoMap, ok := information.(models.JSONB); ok {
+			merged := mergeInformation(student.Information, infoMap)
+			if err := s.validateStudentInformation(ctx, student.BusinessID, merged); err != nil {
+				return nil, err
+			}
+			student.Information = merged
+			changedFields = append(changedFields, "information")
 		}
 	}
 
-	if guardianEmail, ok := updates["guardian_email"]; ok {
-		if guardianEmailStr, ok := guardianEmail.(string); ok {
-			student.GuardianEmail = guardianEmailStr
+	if status, ok, err := updatedStatus(updates); err != nil {
+		return nil, err
+	} else if ok {
+		student.Status = status
+	}
+
+	if enrolledOn, ok := updates["enrolled_on"]; ok {
+		if dateStr, ok := enrolledOn.(string); ok {
+			date, err := parseDate(dateStr)
+			if err != nil {
+				return nil, err
+			}
+			student.EnrolledOn = &date
+			changedFields = append(changedFields, "enrolled_on")
 		}
 	}
 
-	if information, ok := updates["information"]; ok {
-		if infoMap, ok := information.(models.JSONB); ok {
-			student.Information = infoMap
+	if dateOfBirth, ok := updates["date_of_birth"].(string); ok {
+		date, err := parseDateOfBirth(dateOfBirth)
+		if err != nil {
+			return nil, err
 		}
+		student.DateOfBirth = date
+		changedFields = append(changedFields, "date_of_birth")
+	}
+
+	if gender, ok := updates["gender"].(string); ok && gender != student.Gender {
+		student.Gender = gender
+		changedFields = append(changedFields, "gender")
 	}
 
-	if status, ok := updates["status"]; ok {
-		if statusInt, ok := status.(int); ok && (statusInt == 0 || statusInt == 1) {
-			student.Status = statusInt
+	if grade, ok := updates["grade"].(string); ok {
+		grade, err := s.validateGrade(ctx, student.BusinessID, grade)
+		if err != nil {
+			return nil, err
+		}
+		if grade != student.Grade {
+			student.Grade = grade
+			changedFields = append(changedFields, "grade")
 		}
 	}
 
+	var history []models.StudentHistory
+	if len(changedFields) > 0 {
+		history = append(history, newStudentHistory(student.ID, models.StudentEventProfileUpdated,
+			"Updated "+strings.Join(changedFields, ", "), models.JSONB{"fields": changedFields}, actorID))
+	}
+	if student.Status != previousStatus {
+		history = append(history, statusChangeHistory(student.ID, previousStatus, student.Status, actorID))
+	}
+
 	// Save updates
-	if err := s.studentRepo.Update(student); err != nil {
+	tx := s.studentRepo.BeginTransaction(ctx)
+
+	if versioned {
+		err = s.studentRepo.UpdateVersionedWithTransaction(tx, student, version)
+	} else {
+		err = s.studentRepo.UpdateWithTransaction(tx, student)
+	}
+	if errors.Is(err, repository.ErrVersionConflict) {
+		tx.Rollback()
+		return nil, s.versionConflict(ctx, student.ID)
+	}
+	if err != nil {
+		tx.Rollback()
 		return nil, fmt.Errorf("failed to update student: %v", err)
 	}
 
+	if guardiansChanged {
+		if err := s.studentRepo.ReplaceGuardiansWithTransaction(tx, student.ID, guardians); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to save guardians: %v", err)
+		}
+	}
+
+	// The student's login follows the student's status
+	if student.Status != previousStatus {
+		if err := s.userRepo.UpdateUserStatusInTransaction(tx, student.UserID, student.Status); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to update user status: %v", err)
+		}
+	}
+
+	if err := s.studentRepo.CreateHistoryWithTransaction(tx, history); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record student history: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit student update: %v", err)
+	}
+
 	// Get updated student with relations
-	updatedStudent, err := s.studentRepo.GetStudentWithRelations(student.ID)
+	updatedStudent, err := s.studentRepo.GetStudentWithRelations(ctx, student.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get updated student")
 	}
@@ -216,106 +453,200 @@ func (s *studentService) UpdateStudent(studentID uint, updates map[string]interf
 	return s.toStudentResponse(updatedStudent), nil
 }
 
-func (s *studentService) DeleteStudent(studentID uint) error {
-	// Check if student exists
-	_, err := s.studentRepo.GetByID(studentID)
+func (s *studentService) DeleteStudent(ctx context.Context, studentID uint, opts models.DeleteProfileOptions) error {
+	if !opts.Permanent {
+		student, err := s.studentRepo.GetByID(ctx, studentID)
+		if err != nil {
+			return fmt.Errorf("student not found")
+		}
+		return s.softDeleteStudents(ctx, []models.Student{*student})
+	}
+
+	// A permanent delete may also finish off a soft-deleted student
+	student, err := s.studentRepo.GetByIDWithDeleted(ctx, studentID)
 	if err != nil {
 		return fmt.Errorf("student not found")
 	}
 
-	if err := s.studentRepo.Delete(studentID); err != nil {
+	// Payment history is kept for the books, so such students are archived instead
+	counts, err := s.studentRepo.CountDependentRecords(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("failed to check student records: %v", err)
+	}
+	if counts["payments"] > 0 {
+		return fmt.Errorf("student has %d fee payments and cannot be deleted; deactivate the student instead", counts["payments"])
+	}
+
+	tx := s.studentRepo.BeginTransaction(ctx)
+
+	if err := s.studentRepo.DeleteWithTransaction(tx, studentID); err != nil {
+		tx.Rollback()
 		return fmt.Errorf("failed to delete student: %v", err)
 	}
 
+	// Never leave a student login without a profile behind
+	if opts.DeleteUser {
+		err = s.userRepo.DeleteUserInTransaction(tx, student.UserID)
+	} else {
+		err = s.userRepo.UpdateUserStatusInTransaction(tx, student.UserID, 0)
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update user account: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit student deletion: %v", err)
+	}
+
 	return nil
 }
 
-func (s *studentService) GetStudentsByBusiness(businessID uint, filters repository.StudentFilters) ([]models.StudentResponse, int64, error) {
-	// Set default pagination
-	if filters.Page == 0 {
-		filters.Page = 1
+// softDeleteStudents hides the students, keeping their attendance, fees and history,
+// and deactivates their logins until they are restored
+func (s *studentService) softDeleteStudents(ctx context.Context, students []models.Student) error {
+	userIDs := make([]uint, 0, len(students))
+	tx := s.studentRepo.BeginTransaction(ctx)
+
+	for _, student := range students {
+		if err := s.studentRepo.SoftDeleteWithTransaction(tx, student.ID); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to delete student %d: %v", student.ID, err)
+		}
+		userIDs = append(userIDs, student.UserID)
+	}
+
+	if err := s.userRepo.BulkUpdateStatusInTransaction(tx, userIDs, 0); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to deactivate user accounts: %v", err)
 	}
-	if filters.Limit == 0 {
-		filters.Limit = 10
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit student deletion: %v", err)
 	}
+	return nil
+}
 
-	students, total, err := s.studentRepo.GetByBusinessID(businessID, filters)
+// RestoreStudent brings back a soft-deleted student, with its login active again
+// when the student is
+func (s *studentService) RestoreStudent(ctx context.Context, studentID uint) (*models.StudentResponse, error) {
+	student, err := s.studentRepo.GetByIDWithDeleted(ctx, studentID)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get students by business: %v", err)
+		return nil, fmt.Errorf("student not found")
+	}
+	if !student.DeletedAt.Valid {
+		return nil, fmt.Errorf("student is not deleted")
 	}
 
-	var responses []models.StudentResponse
-	for _, student := range students {
-		// Get with relations for complete data
-		studentWithRelations, err := s.studentRepo.GetStudentWithRelations(student.ID)
-		if err != nil {
-			continue // Skip this one if there's an issue
-		}
-		responses = append(responses, *s.toStudentResponse(studentWithRelations))
+	tx := s.studentRepo.BeginTransaction(ctx)
+
+	if err := s.studentRepo.RestoreWithTransaction(tx, studentID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to restore student: %v", err)
+	}
+
+	if err := s.userRepo.UpdateUserStatusInTransaction(tx, student.UserID, student.Status); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update user account: %v", err)
 	}
 
-	return responses, total, nil
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit student restore: %v", err)
+	}
+
+	return s.GetStudentByID(ctx, studentID)
 }
 
-func (s *studentService) GetActiveStudentsByBusiness(businessID uint) ([]models.StudentResponse, error) {
-	students, err := s.studentRepo.GetActiveStudentsByBusiness(businessID)
+// PurgeDeletedStudents permanently removes each student soft-deleted before cutoff with
+// its attendance, results and history. Students with fee payments stay soft-deleted, as
+// payments are kept for the books. The logins stay deactivated.
+func (s *studentService) PurgeDeletedStudents(ctx context.Context, cutoff time.Time) (int, error) {
+	students, err := s.studentRepo.GetDeletedBefore(ctx, cutoff)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get active students: %v", err)
+		return 0, fmt.Errorf("failed to get deleted students: %v", err)
 	}
 
-	var responses []models.StudentResponse
+	purged := 0
 	for _, student := range students {
-		studentWithRelations, err := s.studentRepo.GetStudentWithRelations(student.ID)
-		if err != nil {
-			continue
+		tx := s.studentRepo.BeginTransaction(ctx)
+		if err := s.studentRepo.DeleteWithTransaction(tx, student.ID); err != nil {
+			tx.Rollback()
+			return purged, fmt.Errorf("failed to purge student %d: %v", student.ID, err)
 		}
-		responses = append(responses, *s.toStudentResponse(studentWithRelations))
+		if err := tx.Commit().Error; err != nil {
+			return purged, fmt.Errorf("failed to commit student purge: %v", err)
+		}
+		purged++
 	}
 
-	return responses, nil
+	return purged, nil
 }
 
-func (s *studentService) GetInactiveStudentsByBusiness(businessID uint) ([]models.StudentResponse, error) {
-	students, err := s.studentRepo.GetInactiveStudentsByBusiness(businessID)
+func (s *studentService) GetStudentsByBusiness(ctx context.Context, businessID uint, filters repository.StudentFilters) ([]models.StudentResponse, repository.PageInfo, error) {
+	applyPageDefaults(&filters.Page, &filters.Limit)
+
+	students, total, err := s.studentRepo.GetByBusinessID(ctx, businessID, filters)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get inactive students: %v", err)
+		return nil, repository.PageInfo{}, fmt.Errorf("failed to get students by business: %w", err)
 	}
 
 	var responses []models.StudentResponse
 	for _, student := range students {
-		studentWithRelations, err := s.studentRepo.GetStudentWithRelations(student.ID)
+		// Get with relations for complete data
+		studentWithRelations, err := s.studentRepo.GetStudentWithRelations(ctx, student.ID)
 		if err != nil {
-			continue
+			continue // Skip this one if there's an issue
 		}
 		responses = append(responses, *s.toStudentResponse(studentWithRelations))
 	}
 
-	return responses, nil
+	return responses, filters.PageInfo(total, students), nil
 }
 
-func (s *studentService) ChangeStudentStatus(studentID uint, status int) error {
+func (s *studentService) ChangeStudentStatus(ctx context.Context, studentID uint, status models.Status, actorID uint) error {
 	// Check if student exists
-	_, err := s.studentRepo.GetByID(studentID)
+	student, err := s.studentRepo.GetByID(ctx, studentID)
 	if err != nil {
 		return fmt.Errorf("student not found")
 	}
 
-	if err := s.studentRepo.UpdateStudentStatus(studentID, status); err != nil {
+	// The student's login follows the student's status
+	tx := s.studentRepo.BeginTransaction(ctx)
+
+	if err := s.studentRepo.UpdateStudentStatusWithTransaction(tx, studentID, status); err != nil {
+		tx.Rollback()
 		return fmt.Errorf("failed to update student status: %v", err)
 	}
 
+	if err := s.userRepo.UpdateUserStatusInTransaction(tx, student.UserID, status); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update user status: %v", err)
+	}
+
+	if student.Status != status {
+		history := []models.StudentHistory{statusChangeHistory(student.ID, student.Status, status, actorID)}
+		if err := s.studentRepo.CreateHistoryWithTransaction(tx, history); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record student history: %v", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit status change: %v", err)
+	}
+
 	return nil
 }
 
-func (s *studentService) GetActiveStudents() ([]models.StudentResponse, error) {
-	students, err := s.studentRepo.GetActiveStudents()
+func (s *studentService) SearchStudents(ctx context.Context, search repository.StudentSearch, limit int, businessID ...uint) ([]models.StudentResponse, error) {
+	students, err := s.studentRepo.SearchStudents(ctx, search, limit, businessID...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get active students: %v", err)
+		return nil, fmt.Errorf("failed to search students: %v", err)
 	}
 
 	var responses []models.StudentResponse
 	for _, student := range students {
-		studentWithRelations, err := s.studentRepo.GetStudentWithRelations(student.ID)
+		studentWithRelations, err := s.studentRepo.GetStudentWithRelations(ctx, student.ID)
 		if err != nil {
 			continue
 		}
@@ -325,64 +656,223 @@ func (s *studentService) GetActiveStudents() ([]models.StudentResponse, error) {
 	return responses, nil
 }
 
-func (s *studentService) GetInactiveStudents() ([]models.StudentResponse, error) {
-	students, err := s.studentRepo.GetInactiveStudents()
+func (s *studentService) SearchStudentsByBusiness(ctx context.Context, businessID uint, search repository.StudentSearch, limit int) ([]models.StudentResponse, error) {
+	return s.SearchStudents(ctx, search, limit, businessID)
+}
+
+func (s *studentService) GetStudentStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error) {
+	return s.studentRepo.GetStudentStats(ctx, businessID...)
+}
+
+func (s *studentService) GetGuardianStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error) {
+	return s.studentRepo.GetGuardianStats(ctx, businessID...)
+}
+
+// BulkUpdateStudentStatus changes the status of the listed students and their logins,
+// skipping IDs that don't exist unless atomic is set
+func (s *studentService) BulkUpdateStudentStatus(ctx context.Context, studentIDs []uint, status models.Status, atomic bool, actorID uint) (*models.BulkUpdateResult, error) {
+	if len(studentIDs) == 0 {
+		return nil, fmt.Errorf("no student IDs provided")
+	}
+
+	students, err := s.studentRepo.GetByIDs(ctx, studentIDs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get inactive students: %v", err)
+		return nil, fmt.Errorf("failed to get students: %v", err)
 	}
 
-	var responses []models.StudentResponse
+	found := make(map[uint]bool, len(students))
+	userIDs := make([]uint, 0, len(students))
+	var history []models.StudentHistory
 	for _, student := range students {
-		studentWithRelations, err := s.studentRepo.GetStudentWithRelations(student.ID)
-		if err != nil {
-			continue
+		found[student.ID] = true
+		userIDs = append(userIDs, student.UserID)
+		if student.Status != status {
+			history = append(history, statusChangeHistory(student.ID, student.Status, status, actorID))
 		}
-		responses = append(responses, *s.toStudentResponse(studentWithRelations))
+	}
+	result := newBulkResult(studentIDs, found, "student")
+	if ok, err := checkAtomic(result, atomic); !ok {
+		return result, err
 	}
 
-	return responses, nil
+	// The students' logins follow the students' status
+	tx := s.studentRepo.BeginTransaction(ctx)
+
+	if err := s.studentRepo.BulkUpdateStatusWithTransaction(tx, result.Updated, status); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to bulk update student status: %v", err)
+	}
+
+	if err := s.userRepo.BulkUpdateStatusInTransaction(tx, userIDs, status); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to bulk update user status: %v", err)
+	}
+
+	if err := s.studentRepo.CreateHistoryWithTransaction(tx, history); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record student history: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit status change: %v", err)
+	}
+
+	return result, nil
 }
 
-func (s *studentService) SearchStudents(searchTerm string, limit int, businessID ...uint) ([]models.StudentResponse, error) {
-	students, err := s.studentRepo.SearchStudents(searchTerm, limit, businessID...)
+// BulkDeleteStudents deletes students in one transaction. Every ID must exist and be in
+// the caller's scope. Students with attendance or fee payments are refused unless
+// Cascade is set; then their attendance goes with them, but those with payments are
+// archived instead, as payment history is kept for the books.
+func (s *studentService) BulkDeleteStudents(ctx context.Context, req models.BulkDeleteStudentsRequest, userID uint, role string) ([]models.BulkDeleteResult, error) {
+	studentIDs := uniqueIDs(req.StudentIDs)
+	if len(studentIDs) == 0 {
+		return nil, fmt.Errorf("no student IDs provided")
+	}
+
+	students, err := s.studentRepo.GetByIDs(ctx, studentIDs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search students: %v", err)
+		return nil, fmt.Errorf("failed to get students: %v", err)
+	}
+	if len(students) != len(studentIDs) {
+		return nil, fmt.Errorf("one or more students not found")
 	}
 
-	var responses []models.StudentResponse
+	byID := make(map[uint]models.Student, len(students))
+	businessIDs := make([]uint, 0, len(students))
 	for _, student := range students {
-		studentWithRelations, err := s.studentRepo.GetStudentWithRelations(student.ID)
+		byID[student.ID] = student
+		businessIDs = append(businessIDs, student.BusinessID)
+	}
+	if err := checkBusinessesAccess(ctx, s.businessRepo, businessIDs, userID, role); err != nil {
+		return nil, err
+	}
+
+	results := make([]models.BulkDeleteResult, 0, len(studentIDs))
+	if !req.Permanent {
+		if err := s.softDeleteStudents(ctx, students); err != nil {
+			return nil, err
+		}
+		for _, id := range studentIDs {
+			results = append(results, models.BulkDeleteResult{ID: id, Outcome: models.BulkDeleteDeleted})
+		}
+		return results, nil
+	}
+
+	var deleteIDs, archiveIDs, deactivateUserIDs, deleteUserIDs []uint
+	var history []models.StudentHistory
+	for _, id := range studentIDs {
+		student := byID[id]
+		counts, err := s.studentRepo.CountDependentRecords(ctx, id)
 		if err != nil {
-			continue
+			return nil, fmt.Errorf("failed to check student records: %v", err)
 		}
-		responses = append(responses, *s.toStudentResponse(studentWithRelations))
+
+		result := models.BulkDeleteResult{ID: id}
+		switch {
+		case !req.Cascade && (counts["attendance"] > 0 || counts["payments"] > 0):
+			result.Outcome = models.BulkDeleteRefused
+			result.Reason = fmt.Sprintf("student has %d attendance records and %d fee payments; delete with cascade to remove them",
+				counts["attendance"], counts["payments"])
+		case counts["payments"] > 0:
+			result.Outcome = models.BulkDeleteArchived
+			result.Reason = fmt.Sprintf("student has %d fee payments, which are kept; the student was deactivated instead", counts["payments"])
+			archiveIDs = append(archiveIDs, id)
+			deactivateUserIDs = append(deactivateUserIDs, student.UserID)
+			if student.Status != 0 {
+				history = append(history, statusChangeHistory(id, student.Status, 0, userID))
+			}
+		default:
+			result.Outcome = models.BulkDeleteDeleted
+			deleteIDs = append(deleteIDs, id)
+			// Never leave a student login without a profile behind
+			if req.DeleteUser {
+				deleteUserIDs = append(deleteUserIDs, student.UserID)
+			} else {
+				deactivateUserIDs = append(deactivateUserIDs, student.UserID)
+			}
+		}
+		results = append(results, result)
 	}
 
-	return responses, nil
-}
+	tx := s.studentRepo.BeginTransaction(ctx)
 
-func (s *studentService) SearchStudentsByBusiness(businessID uint, searchTerm string, limit int) ([]models.StudentResponse, error) {
-	return s.SearchStudents(searchTerm, limit, businessID)
-}
+	for _, id := range deleteIDs {
+		if err := s.studentRepo.DeleteWithTransaction(tx, id); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to delete student %d: %v", id, err)
+		}
+	}
 
-func (s *studentService) GetStudentStats(businessID ...uint) (map[string]interface{}, error) {
-	return s.studentRepo.GetStudentStats(businessID...)
-}
+	if len(archiveIDs) > 0 {
+		if err := s.studentRepo.BulkUpdateStatusWithTransaction(tx, archiveIDs, 0); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to archive students: %v", err)
+		}
+	}
+
+	if len(deactivateUserIDs) > 0 {
+		if err := s.userRepo.BulkUpdateStatusInTransaction(tx, deactivateUserIDs, 0); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to deactivate user accounts: %v", err)
+		}
+	}
+	if len(deleteUserIDs) > 0 {
+		if err := s.userRepo.BulkDeleteInTransaction(tx, deleteUserIDs); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to delete user accounts: %v", err)
+		}
+	}
+
+	if err := s.studentRepo.CreateHistoryWithTransaction(tx, history); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record student history: %v", err)
+	}
 
-func (s *studentService) GetGuardianStats(businessID ...uint) (map[string]interface{}, error) {
-	return s.studentRepo.GetGuardianStats(businessID...)
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit student deletion: %v", err)
+	}
+
+	return results, nil
 }
 
-func (s *studentService) BulkUpdateStudentStatus(studentIDs []uint, status int) error {
-	if len(studentIDs) == 0 {
-		return fmt.Errorf("no student IDs provided")
+// GetRecentStudents returns the latest students added, with their business names, and how
+// many were added in the last 7 and 30 days, across all businesses or for one
+func (s *studentService) GetRecentStudents(ctx context.Context, limit int, businessID ...uint) (*models.RecentProfiles, error) {
+	students, err := s.studentRepo.GetRecent(ctx, recentLimit(limit), businessID...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent students: %v", err)
 	}
 
-	if err := s.studentRepo.BulkUpdateStatus(studentIDs, status); err != nil {
-		return fmt.Errorf("failed to bulk update student status: %v", err)
+	additions, err := s.studentRepo.CountAdditions(ctx, time.Now(), businessID...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recent students: %v", err)
 	}
 
-	return nil
+	recent := &models.RecentProfiles{
+		Latest:    make([]models.RecentProfile, 0, len(students)),
+		Additions: *additions,
+	}
+	for _, student := range students {
+		recent.Latest = append(recent.Latest, models.RecentProfile{
+			ID:           student.ID,
+			Name:         student.Name,
+			BusinessID:   student.BusinessID,
+			BusinessName: student.Business.Name,
+			Status:       student.Status,
+			CreatedOn:    student.CreatedOn,
+		})
+	}
+
+	return recent, nil
+}
+
+func (s *studentService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
+
+func (s *studentService) GetBusinessIDByUser(ctx context.Context, userID uint) (uint, error) {
+	return ownBusinessID(ctx, s.businessRepo, userID)
 }
 
 func (s *studentService) ValidateCreateStudentRequest(req models.CreateStudentRequest) error {
@@ -398,12 +888,13 @@ func (s *studentService) ValidateCreateStudentRequest(req models.CreateStudentRe
 		return fmt.Errorf("business ID is required")
 	}
 
-	// Validate guardian email if provided
-	if req.GuardianEmail != "" {
-		// Basic email validation
-		if !strings.Contains(req.GuardianEmail, "@") {
-			return fmt.Errorf("invalid guardian email format")
-		}
+	// Validate guardian contacts if provided
+	guardians := req.Guardians
+	if len(guardians) == 0 {
+		guardians = legacyGuardianRequest(req.GuardianName, req.GuardianNumber, req.GuardianEmail)
+	}
+	if _, err := buildGuardians(guardians); err != nil {
+		return err
 	}
 
 	return nil
@@ -418,16 +909,265 @@ func (s *studentService) ValidateUpdateStudentRequest(req models.UpdateStudentRe
 		return fmt.Errorf("invalid status value")
 	}
 
-	// Validate guardian email if provided
-	if req.GuardianEmail != "" && !strings.Contains(req.GuardianEmail, "@") {
-		return fmt.Errorf("invalid guardian email format")
+	// Validate guardian contacts if provided
+	if req.Guardians != nil {
+		if _, err := buildGuardians(req.Guardians); err != nil {
+			return err
+		}
+	}
+	if req.GuardianEmail != "" {
+		if err := validateEmail(strings.TrimSpace(req.GuardianEmail)); err != nil {
+			return fmt.Errorf("invalid guardian email format")
+		}
+	}
+	if req.GuardianNumber != "" {
+		if _, err := normalizePhoneField("guardian_number", req.GuardianNumber); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// Helper methods
+// updatedGuardians works out a student's guardians after an update. A "guardians"
+// list replaces them all; the single guardian_* fields edit the primary guardian,
+// adding one if the student has none. The bool reports whether anything changed.
+func (s *studentService) updatedGuardians(ctx context.Context, studentID uint, updates map[string]interface{}) ([]models.StudentGuardian, bool, error) {
+	if replacement, ok := updates["guardians"].([]models.GuardianRequest); ok {
+		guardians, err := buildGuardians(replacement)
+		return guardians, true, err
+	}
+
+	name, hasName := updates["guardian_name"].(string)
+	number, hasNumber := updates["guardian_number"].(string)
+	email, hasEmail := updates["guardian_email"].(string)
+	if !hasName && !hasNumber && !hasEmail {
+		return nil, false, nil
+	}
+	if hasNumber {
+		if _, err := normalizePhoneField("guardian_number", number); err != nil {
+			return nil, false, err
+		}
+	}
+
+	current, err := s.studentRepo.GetGuardians(ctx, studentID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get guardians: %v", err)
+	}
+
+	reqs := make([]models.GuardianRequest, 0, len(current)+1)
+	for _, guardian := range current {
+		reqs = append(reqs, models.GuardianRequest{
+			Name:      guardian.Name,
+			Relation:  guardian.Relation,
+			Phone:     guardian.Phone,
+			Email:     guardian.Email,
+			IsPrimary: guardian.IsPrimary,
+		})
+	}
+
+	// Guardians are ordered primary first
+	if len(reqs) == 0 || !reqs[0].IsPrimary {
+		reqs = append([]models.GuardianRequest{{IsPrimary: true}}, reqs...)
+	}
+	if hasName {
+		reqs[0].Name = name
+	}
+	if hasNumber {
+		reqs[0].Phone = number
+	}
+	if hasEmail {
+		reqs[0].Email = email
+	}
+
+	guardians, err := buildGuardians(reqs)
+	return guardians, true, err
+}
+
+// validateGrade checks a grade against the business's grade list, returning the listed
+// spelling. Any grade is accepted while the business has no list.
+func (s *studentService) validateGrade(ctx context.Context, businessID uint, grade string) (string, error) {
+	grade = strings.TrimSpace(grade)
+	if grade == "" {
+		return "", nil
+	}
+
+	grades, err := s.fieldRepo.GetGrades(ctx, businessID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get grades: %v", err)
+	}
+	if len(grades) == 0 {
+		return grade, nil
+	}
+
+	names := make([]string, 0, len(grades))
+	for _, listed := range grades {
+		if strings.EqualFold(listed.Name, grade) {
+			return listed.Name, nil
+		}
+		names = append(names, listed.Name)
+	}
+	return "", fmt.Errorf("grade must be one of: %s", strings.Join(names, ", "))
+}
+
+// validateStudentInformation checks information against the business's custom student fields
+func (s *studentService) validateStudentInformation(ctx context.Context, businessID uint, info models.JSONB) error {
+	schema, err := loadStudentFieldSchema(ctx, s.fieldRepo, s.businessRepo, businessID)
+	if err != nil {
+		return err
+	}
+	return validateInformation(info, schema)
+}
+
+// Helper functions
+
+// parseDateOfBirth parses an optional YYYY-MM-DD date of birth, which cannot be in the future
+func parseDateOfBirth(value string) (*time.Time, error) {
+	date, err := parseOptionalDate(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date of birth: %v", err)
+	}
+	if date != nil && date.After(currentDate()) {
+		return nil, fmt.Errorf("date of birth cannot be in the future")
+	}
+	return date, nil
+}
+
+// ageOn returns the age in whole years of someone born on dateOfBirth
+func ageOn(dateOfBirth, date time.Time) int {
+	age := date.Year() - dateOfBirth.Year()
+	if date.Month() < dateOfBirth.Month() || (date.Month() == dateOfBirth.Month() && date.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
+
+// newStudentHistory builds a history entry; an actorID of 0 means the actor is unknown
+func newStudentHistory(studentID uint, eventType, summary string, payload models.JSONB, actorID uint) models.StudentHistory {
+	entry := models.StudentHistory{
+		StudentID: studentID,
+		EventType: eventType,
+		Summary:   summary,
+		Payload:   payload,
+	}
+	if actorID != 0 {
+		entry.ActorID = &actorID
+	}
+	return entry
+}
+
+func statusChangeHistory(studentID uint, from, to models.Status, actorID uint) models.StudentHistory {
+	summary := "Deactivated"
+	if to == 1 {
+		summary = "Activated"
+	}
+	return newStudentHistory(studentID, models.StudentEventStatusChanged, summary,
+		models.JSONB{"from": from, "to": to}, actorID)
+}
+
+// buildGuardians validates and normalizes guardian details, making sure exactly one
+// guardian is primary
+func buildGuardians(reqs []models.GuardianRequest) ([]models.StudentGuardian, error) {
+	guardians := make([]models.StudentGuardian, 0, len(reqs))
+	primaries := 0
+
+	for i, req := range reqs {
+		guardian := models.StudentGuardian{
+			Name:      strings.TrimSpace(req.Name),
+			Relation:  strings.ToLower(strings.TrimSpace(req.Relation)),
+			Email:     strings.TrimSpace(req.Email),
+			IsPrimary: req.IsPrimary,
+		}
+
+		if guardian.Email != "" {
+			if err := validateEmail(guardian.Email); err != nil {
+				return nil, fmt.Errorf("guardian %d: invalid email format", i+1)
+			}
+		}
+
+		if strings.TrimSpace(req.Phone) != "" {
+			phone, err := normalizePhoneField(fmt.Sprintf("guardians[%d].phone", i), req.Phone)
+			if err != nil {
+				return nil, err
+			}
+			guardian.Phone = phone
+		}
+
+		if guardian.Name == "" && guardian.Phone == "" && guardian.Email == "" {
+			return nil, fmt.Errorf("guardian %d: name, phone or email is required", i+1)
+		}
+
+		if guardian.IsPrimary {
+			primaries++
+		}
+		guardians = append(guardians, guardian)
+	}
+
+	if primaries > 1 {
+		return nil, fmt.Errorf("only one guardian can be primary")
+	}
+	if primaries == 0 && len(guardians) > 0 {
+		guardians[0].IsPrimary = true
+	}
+
+	return guardians, nil
+}
+
+// legacyGuardianRequest turns the single guardian_* fields into a guardian list
+func legacyGuardianRequest(name, number, email string) []models.GuardianRequest {
+	if name == "" && number == "" && email == "" {
+		return nil
+	}
+	return []models.GuardianRequest{{Name: name, Phone: number, Email: email, IsPrimary: true}}
+}
+
+// setLegacyGuardian keeps the guardian_* columns in step with the primary guardian
+func setLegacyGuardian(student *models.Student, guardians []models.StudentGuardian) {
+	student.GuardianName = ""
+	student.GuardianNumber = ""
+	student.GuardianEmail = ""
+	for _, guardian := range guardians {
+		if guardian.IsPrimary {
+			student.GuardianName = guardian.Name
+			student.GuardianNumber = guardian.Phone
+			student.GuardianEmail = guardian.Email
+			return
+		}
+	}
+}
+
+// versionConflict reports a stale update with the student as currently stored
+func (s *studentService) versionConflict(ctx context.Context, studentID uint) error {
+	current, err := s.studentRepo.GetStudentWithRelations(ctx, studentID)
+	if err != nil {
+		return repository.ErrVersionConflict
+	}
+	return &VersionConflictError{Current: s.toStudentResponse(current)}
+}
+
 func (s *studentService) toStudentResponse(student *models.Student) *models.StudentResponse {
+	return toStudentResponse(student)
+}
+
+// toStudentProfileResponse is toStudentResponse with the business's owner details left out
+func toStudentProfileResponse(student *models.Student) *models.StudentProfileResponse {
+	response := &models.StudentProfileResponse{StudentResponse: *toStudentResponse(student)}
+	response.StudentResponse.Business = nil
+
+	if student.Business.ID != 0 {
+		response.Business = &models.BusinessSummary{
+			ID:       student.Business.ID,
+			Name:     student.Business.Name,
+			Slug:     student.Business.Slug,
+			Location: student.Business.Location,
+			IsOpen:   student.Business.IsOpen,
+		}
+	}
+
+	return response
+}
+
+func toStudentResponse(student *models.Student) *models.StudentResponse {
 	response := &models.StudentResponse{
 		ID:             student.ID,
 		Name:           student.Name,
@@ -438,8 +1178,49 @@ func (s *studentService) toStudentResponse(student *models.Student) *models.Stud
 		GuardianEmail:  student.GuardianEmail,
 		Information:    student.Information,
 		Status:         student.Status,
+		Version:        student.Version,
 		CreatedOn:      student.CreatedOn,
 		UpdatedOn:      student.UpdatedOn,
+		BatchID:        student.BatchID,
+		EnrolledOn:     formatOptionalDate(student.EnrolledOn),
+		Guardians:      []models.StudentGuardianResponse{},
+		DateOfBirth:    formatOptionalDate(student.DateOfBirth),
+		Gender:         student.Gender,
+		Grade:          student.Grade,
+		PhotoURL:       studentPhotoURL(student),
+	}
+	if student.StudentCode != nil {
+		response.StudentCode = *student.StudentCode
+	}
+
+	if student.DateOfBirth != nil {
+		age := ageOn(*student.DateOfBirth, currentDate())
+		response.Age = &age
+	}
+
+	for _, guardian := range student.Guardians {
+		response.Guardians = append(response.Guardians, models.StudentGuardianResponse{
+			ID:        guardian.ID,
+			Name:      guardian.Name,
+			Relation:  guardian.Relation,
+			Phone:     guardian.Phone,
+			Email:     guardian.Email,
+			IsPrimary: guardian.IsPrimary,
+		})
+	}
+
+	if student.DeletedAt.Valid {
+		response.DeletedAt = &student.DeletedAt.Time
+	}
+
+	// Add batch details if loaded
+	if student.Batch != nil {
+		response.Batch = &models.StudentBatchResponse{
+			ID:        student.Batch.ID,
+			Name:      student.Batch.Name,
+			StartDate: formatOptionalDate(student.Batch.StartDate),
+			EndDate:   formatOptionalDate(student.Batch.EndDate),
+		}
 	}
 
 	// Add user details if loaded
@@ -467,6 +1248,7 @@ func (s *studentService) toStudentResponse(student *models.Student) *models.Stud
 			Location:  student.Business.Location,
 			Status:    student.Business.Status,
 			CreatedOn: student.Business.CreatedOn,
+			UpdatedOn: student.Business.UpdatedOn,
 		}
 	}
 
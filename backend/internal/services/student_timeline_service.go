@@ -0,0 +1,171 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"sort"
+)
+
+// maxStudentTimelineWindow bounds how many entries of each source a timeline page may read
+const maxStudentTimelineWindow = 1000
+
+type StudentTimelineService interface {
+	GetTimeline(ctx context.Context, studentID uint, page, limit int) ([]models.StudentTimelineEntry, int64, error)
+
+	// Access control
+	CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error
+}
+
+type studentTimelineService struct {
+	studentRepo  repository.StudentRepository
+	feeRepo      repository.FeeRepository
+	examRepo     repository.ExamRepository
+	userRepo     repository.UserRepository
+	businessRepo repository.BusinessRepository
+}
+
+func NewStudentTimelineService(studentRepo repository.StudentRepository, feeRepo repository.FeeRepository, examRepo repository.ExamRepository, userRepo repository.UserRepository, businessRepo repository.BusinessRepository) StudentTimelineService {
+	return &studentTimelineService{
+		studentRepo:  studentRepo,
+		feeRepo:      feeRepo,
+		examRepo:     examRepo,
+		userRepo:     userRepo,
+		businessRepo: businessRepo,
+	}
+}
+
+// GetTimeline merges a student's history, payments and exam results into one feed, newest
+// first. Every source is sorted by time, so page N only needs the newest N*limit entries
+// of each one.
+func (s *studentTimelineService) GetTimeline(ctx context.Context, studentID uint, page, limit int) ([]models.StudentTimelineEntry, int64, error) {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("student not found")
+	}
+
+	window := page * limit
+	if window > maxStudentTimelineWindow {
+		return nil, 0, fmt.Errorf("page is too deep, at most %d timeline entries can be listed", maxStudentTimelineWindow)
+	}
+
+	history, err := s.studentRepo.GetRecentHistory(ctx, student.ID, window)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get student history: %v", err)
+	}
+	historyCount, err := s.studentRepo.CountHistory(ctx, student.ID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count student history: %v", err)
+	}
+
+	payments, paymentCount, err := s.feeRepo.GetPayments(ctx, repository.FeePaymentFilters{StudentID: &student.ID, Page: 1, Limit: window})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get payments: %v", err)
+	}
+
+	results, err := s.examRepo.GetRecentStudentResults(ctx, student.ID, window)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get exam results: %v", err)
+	}
+	resultCount, err := s.examRepo.CountStudentResults(ctx, student.ID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count exam results: %v", err)
+	}
+
+	entries := make([]models.StudentTimelineEntry, 0, len(history)+len(payments)+len(results)+1)
+	actors := make(map[uint]*models.StudentTimelineActor)
+
+	entries = append(entries, models.StudentTimelineEntry{
+		Type:      models.StudentEventCreated,
+		Timestamp: student.CreatedOn,
+		Summary:   "Student created",
+		Payload:   models.JSONB{"business_id": student.BusinessID},
+	})
+
+	for _, entry := range history {
+		var actor *models.StudentTimelineActor
+		if entry.ActorID != nil {
+			actor = s.actor(ctx, actors, *entry.ActorID)
+		}
+		entries = append(entries, models.StudentTimelineEntry{
+			Type:      entry.EventType,
+			Timestamp: entry.CreatedOn,
+			Actor:     actor,
+			Summary:   entry.Summary,
+			Payload:   entry.Payload,
+		})
+	}
+
+	for _, payment := range payments {
+		entries = append(entries, models.StudentTimelineEntry{
+			Type:      models.StudentEventPayment,
+			Timestamp: payment.PaidOn,
+			Actor:     s.actor(ctx, actors, payment.RecordedBy),
+			Summary:   fmt.Sprintf("Paid %.2f by %s", payment.Amount, payment.Mode),
+			Payload: models.JSONB{
+				"payment_id":  payment.ID,
+				"fee_plan_id": payment.FeePlanID,
+				"amount":      payment.Amount,
+				"mode":        payment.Mode,
+				"reference":   payment.Reference,
+			},
+		})
+	}
+
+	for _, result := range results {
+		entries = append(entries, models.StudentTimelineEntry{
+			Type:      models.StudentEventExamResult,
+			Timestamp: result.UpdatedOn,
+			Actor:     s.actor(ctx, actors, result.RecordedBy),
+			Summary:   fmt.Sprintf("Scored %.2f/%.2f in %s", result.Marks, result.Exam.MaxMarks, result.Exam.Name),
+			Payload: models.JSONB{
+				"exam_id":   result.ExamID,
+				"marks":     result.Marks,
+				"max_marks": result.Exam.MaxMarks,
+				"passed":    result.Marks >= result.Exam.EffectivePassMarks(),
+			},
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+
+	total := historyCount + paymentCount + resultCount + 1
+	start := (page - 1) * limit
+	if start >= len(entries) {
+		return []models.StudentTimelineEntry{}, total, nil
+	}
+	end := start + limit
+	if end > len(entries) {
+		end = len(entries)
+	}
+
+	return entries[start:end], total, nil
+}
+
+func (s *studentTimelineService) CheckStudentAccess(ctx context.Context, studentID, userID uint, role string) error {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("student not found")
+	}
+	return checkBusinessAccess(ctx, s.businessRepo, student.BusinessID, userID, role)
+}
+
+// actor looks up a user once per timeline request; unknown users are shown by ID only
+func (s *studentTimelineService) actor(ctx context.Context, cache map[uint]*models.StudentTimelineActor, userID uint) *models.StudentTimelineActor {
+	if userID == 0 {
+		return nil
+	}
+	if actor, ok := cache[userID]; ok {
+		return actor
+	}
+
+	actor := &models.StudentTimelineActor{ID: userID}
+	if user, err := s.userRepo.GetByID(ctx, userID); err == nil {
+		actor.Name = user.Name
+	}
+	cache[userID] = actor
+	return actor
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+type SubjectService interface {
+	CreateSubject(ctx context.Context, businessID uint, req models.CreateSubjectRequest) (*models.SubjectResponse, error)
+	GetSubjectsByBusiness(ctx context.Context, businessID uint) ([]models.SubjectResponse, error)
+	UpdateSubject(ctx context.Context, businessID, subjectID uint, req models.UpdateSubjectRequest) (*models.SubjectResponse, error)
+	DeleteSubject(ctx context.Context, businessID, subjectID uint) error
+
+	// Access control
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+}
+
+type subjectService struct {
+	subjectRepo  repository.SubjectRepository
+	businessRepo repository.BusinessRepository
+}
+
+func NewSubjectService(subjectRepo repository.SubjectRepository, businessRepo repository.BusinessRepository) SubjectService {
+	return &subjectService{
+		subjectRepo:  subjectRepo,
+		businessRepo: businessRepo,
+	}
+}
+
+func (s *subjectService) CreateSubject(ctx context.Context, businessID uint, req models.CreateSubjectRequest) (*models.SubjectResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	// Check if business exists
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	exists, err := s.subjectRepo.SubjectNameExists(ctx, businessID, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check subject name: %v", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("subject already exists for this business")
+	}
+
+	subject := &models.Subject{
+		BusinessID:  businessID,
+		Name:        name,
+		Description: req.Description,
+	}
+
+	if err := s.subjectRepo.Create(ctx, subject); err != nil {
+		return nil, fmt.Errorf("failed to create subject: %v", err)
+	}
+
+	response := toSubjectResponse(*subject)
+	return &response, nil
+}
+
+func (s *subjectService) GetSubjectsByBusiness(ctx context.Context, businessID uint) ([]models.SubjectResponse, error) {
+	subjects, err := s.subjectRepo.GetByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subjects: %v", err)
+	}
+
+	responses := []models.SubjectResponse{}
+	for _, subject := range subjects {
+		responses = append(responses, toSubjectResponse(subject))
+	}
+
+	return responses, nil
+}
+
+func (s *subjectService) UpdateSubject(ctx context.Context, businessID, subjectID uint, req models.UpdateSubjectRequest) (*models.SubjectResponse, error) {
+	subject, err := s.getBusinessSubject(ctx, businessID, subjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		exists, err := s.subjectRepo.SubjectNameExists(ctx, businessID, name, subject.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check subject name: %v", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("subject already exists for this business")
+		}
+		subject.Name = name
+	}
+
+	if req.Description != "" {
+		subject.Description = req.Description
+	}
+
+	if err := s.subjectRepo.Update(ctx, subject); err != nil {
+		return nil, fmt.Errorf("failed to update subject: %v", err)
+	}
+
+	response := toSubjectResponse(*subject)
+	return &response, nil
+}
+
+func (s *subjectService) DeleteSubject(ctx context.Context, businessID, subjectID uint) error {
+	subject, err := s.getBusinessSubject(ctx, businessID, subjectID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.subjectRepo.Delete(ctx, subject.ID); err != nil {
+		return fmt.Errorf("failed to delete subject: %v", err)
+	}
+
+	return nil
+}
+
+// Helper methods
+func (s *subjectService) getBusinessSubject(ctx context.Context, businessID, subjectID uint) (*models.Subject, error) {
+	subject, err := s.subjectRepo.GetByID(ctx, subjectID)
+	if err != nil || subject.BusinessID != businessID {
+		return nil, fmt.Errorf("subject not found")
+	}
+	return subject, nil
+}
+
+func toSubjectResponse(subject models.Subject) models.SubjectResponse {
+	return models.SubjectResponse{
+		ID:          subject.ID,
+		BusinessID:  subject.BusinessID,
+		Name:        subject.Name,
+		Description: subject.Description,
+		CreatedOn:   subject.CreatedOn,
+	}
+}
+
+// uniqueIDs returns the distinct IDs in the order they first appear
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func (s *subjectService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
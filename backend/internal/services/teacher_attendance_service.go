@@ -0,0 +1,335 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+type TeacherAttendanceService interface {
+	MarkAttendance(ctx context.Context, teacherID uint, req models.MarkTeacherAttendanceRequest, actorID uint) (*models.TeacherAttendanceResponse, error)
+	BulkMarkAttendance(ctx context.Context, businessID uint, req models.BulkTeacherAttendanceRequest, actorID uint) ([]models.TeacherAttendanceResponse, error)
+	GetAttendance(ctx context.Context, filters repository.TeacherAttendanceFilters) ([]models.TeacherAttendanceResponse, int64, error)
+	GetTeacherMonthlySummary(ctx context.Context, teacherID uint, month string) (*models.TeacherAttendanceSummary, error)
+	GetBusinessMonthlySummary(ctx context.Context, businessID uint, month string) (*models.BusinessAttendanceSummary, error)
+}
+
+type teacherAttendanceService struct {
+	attendanceRepo repository.TeacherAttendanceRepository
+	teacherRepo    repository.TeacherRepository
+	businessRepo   repository.BusinessRepository
+}
+
+func NewTeacherAttendanceService(attendanceRepo repository.TeacherAttendanceRepository, teacherRepo repository.TeacherRepository, businessRepo repository.BusinessRepository) TeacherAttendanceService {
+	return &teacherAttendanceService{
+		attendanceRepo: attendanceRepo,
+		teacherRepo:    teacherRepo,
+		businessRepo:   businessRepo,
+	}
+}
+
+func (s *teacherAttendanceService) MarkAttendance(ctx context.Context, teacherID uint, req models.MarkTeacherAttendanceRequest, actorID uint) (*models.TeacherAttendanceResponse, error) {
+	date, err := parseDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("teacher not found")
+	}
+
+	record := &models.TeacherAttendance{
+		TeacherID:  teacher.ID,
+		BusinessID: teacher.BusinessID,
+		Date:       date,
+		Status:     req.Status,
+		Note:       req.Note,
+		MarkedBy:   actorID,
+	}
+
+	if err := s.attendanceRepo.Upsert(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to mark attendance: %v", err)
+	}
+
+	record.Teacher = *teacher
+	response := toTeacherAttendanceResponse(*record)
+	return &response, nil
+}
+
+func (s *teacherAttendanceService) BulkMarkAttendance(ctx context.Context, businessID uint, req models.BulkTeacherAttendanceRequest, actorID uint) ([]models.TeacherAttendanceResponse, error) {
+	date, err := parseDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Entries) == 0 {
+		return nil, fmt.Errorf("no attendance entries provided")
+	}
+
+	// Check if business exists
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	teacherIDs := make([]uint, 0, len(req.Entries))
+	seen := make(map[uint]bool, len(req.Entries))
+	for _, entry := range req.Entries {
+		if seen[entry.TeacherID] {
+			return nil, fmt.Errorf("teacher %d appears more than once", entry.TeacherID)
+		}
+		seen[entry.TeacherID] = true
+		teacherIDs = append(teacherIDs, entry.TeacherID)
+	}
+
+	teachers, err := s.teacherRepo.GetByIDs(ctx, teacherIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teachers: %v", err)
+	}
+
+	teachersByID := make(map[uint]models.Teacher, len(teachers))
+	for _, teacher := range teachers {
+		if teacher.BusinessID == businessID {
+			teachersByID[teacher.ID] = teacher
+		}
+	}
+
+	records := make([]models.TeacherAttendance, 0, len(req.Entries))
+	for _, entry := range req.Entries {
+		if _, ok := teachersByID[entry.TeacherID]; !ok {
+			return nil, fmt.Errorf("teacher %d not found in this business", entry.TeacherID)
+		}
+		records = append(records, models.TeacherAttendance{
+			TeacherID:  entry.TeacherID,
+			BusinessID: businessID,
+			Date:       date,
+			Status:     entry.Status,
+			Note:       entry.Note,
+			MarkedBy:   actorID,
+		})
+	}
+
+	tx := s.attendanceRepo.BeginTransaction(ctx)
+
+	if err := s.attendanceRepo.UpsertWithTransaction(tx, records); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to mark attendance: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit attendance: %v", err)
+	}
+
+	responses := make([]models.TeacherAttendanceResponse, 0, len(records))
+	for _, record := range records {
+		record.Teacher = teachersByID[record.TeacherID]
+		responses = append(responses, toTeacherAttendanceResponse(record))
+	}
+
+	return responses, nil
+}
+
+func (s *teacherAttendanceService) GetAttendance(ctx context.Context, filters repository.TeacherAttendanceFilters) ([]models.TeacherAttendanceResponse, int64, error) {
+	// Set default pagination
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.Limit == 0 {
+		filters.Limit = 31
+	}
+
+	if filters.From != "" {
+		if _, err := parseDate(filters.From); err != nil {
+			return nil, 0, fmt.Errorf("invalid from date: %v", err)
+		}
+	}
+	if filters.To != "" {
+		if _, err := parseDate(filters.To); err != nil {
+			return nil, 0, fmt.Errorf("invalid to date: %v", err)
+		}
+	}
+	if filters.From != "" && filters.To != "" && filters.From > filters.To {
+		return nil, 0, fmt.Errorf("from date must not be after to date")
+	}
+
+	records, total, err := s.attendanceRepo.GetAll(ctx, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get attendance: %v", err)
+	}
+
+	responses := []models.TeacherAttendanceResponse{}
+	for _, record := range records {
+		responses = append(responses, toTeacherAttendanceResponse(record))
+	}
+
+	return responses, total, nil
+}
+
+func (s *teacherAttendanceService) GetTeacherMonthlySummary(ctx context.Context, teacherID uint, month string) (*models.TeacherAttendanceSummary, error) {
+	teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("teacher not found")
+	}
+
+	start, end, err := parseAttendanceMonth(month, businessToday(ctx, s.businessRepo, teacher.BusinessID))
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.attendanceRepo.GetStatusCounts(ctx, repository.TeacherAttendanceFilters{
+		TeacherID: &teacher.ID,
+		From:      start.Format(models.DateFormat),
+		To:        end.Format(models.DateFormat),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance summary: %v", err)
+	}
+
+	summary := &models.TeacherAttendanceSummary{
+		TeacherID:   teacher.ID,
+		TeacherName: teacher.Name,
+		Month:       start.Format("2006-01"),
+	}
+	for _, count := range counts {
+		addAttendanceCount(&summary.Present, &summary.Absent, &summary.Leave, count.Status, count.Count)
+	}
+	summary.TotalMarked, summary.AbsenceRate = attendanceTotals(summary.Present, summary.Absent, summary.Leave)
+
+	return summary, nil
+}
+
+func (s *teacherAttendanceService) GetBusinessMonthlySummary(ctx context.Context, businessID uint, month string) (*models.BusinessAttendanceSummary, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	start, end, err := parseAttendanceMonth(month, todayIn(business.Timezone))
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.attendanceRepo.GetStatusCounts(ctx, repository.TeacherAttendanceFilters{
+		BusinessID: &businessID,
+		From:       start.Format(models.DateFormat),
+		To:         end.Format(models.DateFormat),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance summary: %v", err)
+	}
+
+	// Active teachers are listed even without any marked days
+	teachers, err := s.teacherRepo.GetActiveTeachersByBusiness(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teachers: %v", err)
+	}
+
+	monthLabel := start.Format("2006-01")
+	byTeacher := make(map[uint]*models.TeacherAttendanceSummary)
+	var order []uint
+	for _, teacher := range teachers {
+		byTeacher[teacher.ID] = &models.TeacherAttendanceSummary{TeacherID: teacher.ID, TeacherName: teacher.Name, Month: monthLabel}
+		order = append(order, teacher.ID)
+	}
+
+	var missing []uint
+	for _, count := range counts {
+		if _, ok := byTeacher[count.TeacherID]; !ok {
+			byTeacher[count.TeacherID] = &models.TeacherAttendanceSummary{TeacherID: count.TeacherID, Month: monthLabel}
+			order = append(order, count.TeacherID)
+			missing = append(missing, count.TeacherID)
+		}
+		teacherSummary := byTeacher[count.TeacherID]
+		addAttendanceCount(&teacherSummary.Present, &teacherSummary.Absent, &teacherSummary.Leave, count.Status, count.Count)
+	}
+
+	// Name teachers who have attendance this month but are no longer active
+	if len(missing) > 0 {
+		others, err := s.teacherRepo.GetByIDs(ctx, missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get teachers: %v", err)
+		}
+		for _, teacher := range others {
+			byTeacher[teacher.ID].TeacherName = teacher.Name
+		}
+	}
+
+	summary := &models.BusinessAttendanceSummary{
+		BusinessID: businessID,
+		Month:      monthLabel,
+		Teachers:   []models.TeacherAttendanceSummary{},
+	}
+	for _, teacherID := range order {
+		teacherSummary := byTeacher[teacherID]
+		teacherSummary.TotalMarked, teacherSummary.AbsenceRate = attendanceTotals(teacherSummary.Present, teacherSummary.Absent, teacherSummary.Leave)
+
+		summary.Present += teacherSummary.Present
+		summary.Absent += teacherSummary.Absent
+		summary.Leave += teacherSummary.Leave
+		summary.Teachers = append(summary.Teachers, *teacherSummary)
+	}
+	summary.TotalMarked, summary.AbsenceRate = attendanceTotals(summary.Present, summary.Absent, summary.Leave)
+
+	return summary, nil
+}
+
+// Helper functions
+func parseDate(value string) (time.Time, error) {
+	date, err := time.Parse(models.DateFormat, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date must be in YYYY-MM-DD format")
+	}
+	return date, nil
+}
+
+// parseAttendanceMonth returns the first and last day of a "YYYY-MM" month, defaulting to the current month
+func parseAttendanceMonth(value string, today time.Time) (time.Time, time.Time, error) {
+	var start time.Time
+	if value == "" {
+		start = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	} else {
+		parsed, err := time.Parse("2006-01", value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("month must be in YYYY-MM format")
+		}
+		start = parsed
+	}
+	return start, start.AddDate(0, 1, -1), nil
+}
+
+func addAttendanceCount(present, absent, leave *int64, status string, count int64) {
+	switch status {
+	case models.AttendancePresent:
+		*present += count
+	case models.AttendanceAbsent:
+		*absent += count
+	case models.AttendanceLeave:
+		*leave += count
+	}
+}
+
+// attendanceTotals returns the number of marked days and the absence rate as a percentage
+func attendanceTotals(present, absent, leave int64) (int64, float64) {
+	total := present + absent + leave
+	if total == 0 {
+		return 0, 0
+	}
+	return total, math.Round(float64(absent)/float64(total)*10000) / 100
+}
+
+func toTeacherAttendanceResponse(record models.TeacherAttendance) models.TeacherAttendanceResponse {
+	return models.TeacherAttendanceResponse{
+		ID:          record.ID,
+		TeacherID:   record.TeacherID,
+		TeacherName: record.Teacher.Name,
+		BusinessID:  record.BusinessID,
+		Date:        record.Date.Format(models.DateFormat),
+		Status:      record.Status,
+		Note:        record.Note,
+		MarkedBy:    record.MarkedBy,
+		UpdatedOn:   record.UpdatedOn,
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/logger"
+	"backend/pkg/storage"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"time"
+)
+
+// MaxTeacherDocumentSize is the largest accepted teacher document upload
+const MaxTeacherDocumentSize = 10 << 20 // 10 MB
+
+var teacherDocumentRules = storage.UploadRules{
+	MaxSize:      MaxTeacherDocumentSize,
+	AllowedTypes: []string{"application/pdf", "image/jpeg", "image/png"},
+}
+
+type TeacherDocumentService interface {
+	UploadDocument(ctx context.Context, teacherID uint, documentType string, file multipart.File, header *multipart.FileHeader, uploadedBy uint) (*models.TeacherDocumentResponse, error)
+	GetDocuments(ctx context.Context, teacherID uint) ([]models.TeacherDocumentResponse, error)
+	DeleteDocument(ctx context.Context, teacherID, documentID uint) error
+}
+
+type teacherDocumentService struct {
+	documentRepo repository.TeacherDocumentRepository
+	teacherRepo  repository.TeacherRepository
+	storage      storage.Storage
+}
+
+func NewTeacherDocumentService(documentRepo repository.TeacherDocumentRepository, teacherRepo repository.TeacherRepository, store storage.Storage) TeacherDocumentService {
+	return &teacherDocumentService{
+		documentRepo: documentRepo,
+		teacherRepo:  teacherRepo,
+		storage:      store,
+	}
+}
+
+func (s *teacherDocumentService) UploadDocument(ctx context.Context, teacherID uint, documentType string, file multipart.File, header *multipart.FileHeader, uploadedBy uint) (*models.TeacherDocumentResponse, error) {
+	if _, err := s.teacherRepo.GetByID(ctx, teacherID); err != nil {
+		return nil, fmt.Errorf("teacher not found")
+	}
+
+	if documentType == "" {
+		documentType = models.TeacherDocumentOther
+	}
+	switch documentType {
+	case models.TeacherDocumentCertificate, models.TeacherDocumentContract, models.TeacherDocumentOther:
+	default:
+		return nil, fmt.Errorf("invalid document type")
+	}
+
+	contentType, err := teacherDocumentRules.Validate(file, header)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := storage.SafeFileName(header.Filename)
+	path := fmt.Sprintf("teachers/%d/%d_%s", teacherID, time.Now().UnixNano(), fileName)
+
+	if err := s.storage.Save(path, file); err != nil {
+		return nil, fmt.Errorf("failed to store document: %v", err)
+	}
+
+	document := &models.TeacherDocument{
+		TeacherID:    teacherID,
+		FileName:     fileName,
+		DocumentType: documentType,
+		ContentType:  contentType,
+		Size:         header.Size,
+		StoragePath:  path,
+		UploadedBy:   uploadedBy,
+	}
+
+	if err := s.documentRepo.Create(ctx, document); err != nil {
+		// Don't leave orphaned files behind
+		if delErr := s.storage.Delete(path); delErr != nil {
+			logger.FromContext(ctx).Warn("failed to remove stored document", "path", path, "error", delErr)
+		}
+		return nil, fmt.Errorf("failed to save document: %v", err)
+	}
+
+	response := toTeacherDocumentResponse(*document)
+	return &response, nil
+}
+
+func (s *teacherDocumentService) GetDocuments(ctx context.Context, teacherID uint) ([]models.TeacherDocumentResponse, error) {
+	documents, err := s.documentRepo.GetByTeacherID(ctx, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get documents: %v", err)
+	}
+
+	responses := []models.TeacherDocumentResponse{}
+	for _, document := range documents {
+		responses = append(responses, toTeacherDocumentResponse(document))
+	}
+
+	return responses, nil
+}
+
+func (s *teacherDocumentService) DeleteDocument(ctx context.Context, teacherID, documentID uint) error {
+	document, err := s.documentRepo.GetByID(ctx, documentID)
+	if err != nil || document.TeacherID != teacherID {
+		return fmt.Errorf("document not found")
+	}
+
+	if err := s.documentRepo.Delete(ctx, document.ID); err != nil {
+		return fmt.Errorf("failed to delete document: %v", err)
+	}
+
+	// The record is gone; a leftover file is only logged
+	if err := s.storage.Delete(document.StoragePath); err != nil {
+		logger.FromContext(ctx).Warn("failed to remove stored document", "path", document.StoragePath, "error", err)
+	}
+
+	return nil
+}
+
+func toTeacherDocumentResponse(document models.TeacherDocument) models.TeacherDocumentResponse {
+	return models.TeacherDocumentResponse{
+		ID:           document.ID,
+		TeacherID:    document.TeacherID,
+		FileName:     document.FileName,
+		DocumentType: document.DocumentType,
+		ContentType:  document.ContentType,
+		Size:         document.Size,
+		UploadedBy:   document.UploadedBy,
+		UploadedAt:   document.UploadedAt,
+	}
+}
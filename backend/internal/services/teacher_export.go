@@ -0,0 +1,87 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// teacherSalaryColumn is left out of exports for callers who may not see salaries
+const teacherSalaryColumn = "salary"
+
+// teacherExportColumns are the columns of a teacher CSV export. The names match the
+// import columns where they overlap, so an export can be edited and imported again.
+var teacherExportColumns = []csvColumn[models.Teacher]{
+	{Name: "id", Value: func(t models.Teacher) string { return strconv.FormatUint(uint64(t.ID), 10) }},
+	{Name: "name", Value: func(t models.Teacher) string { return t.Name }},
+	{Name: "email", Value: func(t models.Teacher) string { return t.User.Email }},
+	{Name: "phone", Value: func(t models.Teacher) string { return t.User.Phone }},
+	{Name: "status", Value: func(t models.Teacher) string { return formatExportStatus(t.Status) }},
+	{Name: teacherSalaryColumn, Value: func(t models.Teacher) string { return strconv.FormatFloat(t.Salary, 'f', 2, 64) }},
+	{Name: "qualification", Value: func(t models.Teacher) string { return t.Qualification }},
+	{Name: "experience", Value: func(t models.Teacher) string { return t.Experience }},
+	{Name: "experience_years", Value: func(t models.Teacher) string {
+		if t.ExperienceYears == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*t.ExperienceYears, 'f', -1, 64)
+	}},
+	{Name: "subjects", Value: func(t models.Teacher) string {
+		names := make([]string, len(t.Subjects))
+		for i, subject := range t.Subjects {
+			names[i] = subject.Name
+		}
+		return strings.Join(names, "; ")
+	}},
+	{Name: "created_on", Value: func(t models.Teacher) string { return formatExportDate(&t.CreatedOn, models.DateFormat) }},
+}
+
+// TeacherExportColumns validates the comma-separated columns parameter of a teacher
+// export, returning every column the caller may see when it is empty
+func (s *teacherService) TeacherExportColumns(columns string, includeSalary bool) ([]string, error) {
+	resolved, err := resolveCSVColumns(parseCSVColumns(columns), teacherExportColumnsFor(includeSalary))
+	if err != nil {
+		return nil, err
+	}
+	return csvColumnNames(resolved), nil
+}
+
+// ExportTeachers writes the business's teachers matching filters to w as CSV, reading
+// them in batches, and returns how many rows were written. Salaries are only written when includeSalary is set.
+func (s *teacherService) ExportTeachers(ctx context.Context, businessID uint, filters repository.TeacherFilters, columns []string, includeSalary bool, w io.Writer) (int64, error) {
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return 0, fmt.Errorf("business not found")
+	}
+
+	resolved, err := resolveCSVColumns(columns, teacherExportColumnsFor(includeSalary))
+	if err != nil {
+		return 0, err
+	}
+
+	export, err := newCSVExport(w, resolved)
+	if err != nil {
+		return 0, err
+	}
+
+	filters.BusinessID = &businessID
+	err = s.teacherRepo.ForEachBatch(ctx, filters, exportBatchSize, export.writeBatch)
+	return export.rows, err
+}
+
+func teacherExportColumnsFor(includeSalary bool) []csvColumn[models.Teacher] {
+	if includeSalary {
+		return teacherExportColumns
+	}
+
+	columns := make([]csvColumn[models.Teacher], 0, len(teacherExportColumns))
+	for _, column := range teacherExportColumns {
+		if column.Name != teacherSalaryColumn {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
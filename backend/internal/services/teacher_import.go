@@ -0,0 +1,268 @@
+package services
+
+import (
+	"backend/internal/models"
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxTeacherImportRows limits the size of a single CSV import
+const MaxTeacherImportRows = 1000
+
+var teacherImportColumns = []string{"name", "email", "phone", "salary", "qualification", "experience"}
+
+// teacherImportRow is a parsed and validated CSV row
+type teacherImportRow struct {
+	result        *models.TeacherImportRowResult
+	name          string
+	email         string
+	phone         string
+	salary        float64
+	qualification string
+	experience    string
+}
+
+// ImportTeachers validates every CSV row and, unless dryRun is set or a row is invalid,
+// creates a user and teacher for each row in a single transaction
+func (s *teacherService) ImportTeachers(ctx context.Context, businessID uint, content io.Reader, dryRun bool) (*models.TeacherImportReport, error) {
+	// Check if business exists
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	rows, err := s.parseTeacherImport(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.TeacherImportReport{
+		BusinessID: businessID,
+		DryRun:     dryRun,
+		TotalRows:  len(rows),
+		Rows:       make([]models.TeacherImportRowResult, 0, len(rows)),
+	}
+
+	for _, row := range rows {
+		if len(row.result.Errors) > 0 {
+			row.result.Status = models.ImportRowError
+			report.Failed++
+		} else {
+			row.result.Status = models.ImportRowValid
+			report.ValidRows++
+		}
+	}
+
+	// Nothing is created unless every row is valid
+	if !dryRun && report.Failed == 0 {
+		if err := s.createImportedTeachers(ctx, businessID, rows); err != nil {
+			return nil, err
+		}
+		report.Created = len(rows)
+	}
+
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row.result)
+	}
+
+	return report, nil
+}
+
+func (s *teacherService) parseTeacherImport(ctx context.Context, content io.Reader) ([]teacherImportRow, error) {
+	reader := csv.NewReader(content)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+
+	columns := make(map[string]int)
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))] = i
+	}
+	for _, required := range []string{"name", "email"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header must include the %s column (supported columns: %s)", required, strings.Join(teacherImportColumns, ", "))
+		}
+	}
+
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []teacherImportRow
+	seenEmails := make(map[string]int)
+	line := 1
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV on line %d: %v", line, err)
+		}
+
+		// Skip blank lines
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		if len(rows) >= MaxTeacherImportRows {
+			return nil, fmt.Errorf("CSV file has more than %d rows", MaxTeacherImportRows)
+		}
+
+		row := teacherImportRow{
+			name:          field(record, "name"),
+			email:         strings.ToLower(field(record, "email")),
+			phone:         field(record, "phone"),
+			qualification: field(record, "qualification"),
+			experience:    field(record, "experience"),
+		}
+		row.result = &models.TeacherImportRowResult{
+			Row:   line,
+			Name:  row.name,
+			Email: row.email,
+		}
+
+		if row.name == "" {
+			row.result.Errors = append(row.result.Errors, "name is required")
+		}
+
+		if row.email == "" {
+			row.result.Errors = append(row.result.Errors, "email is required")
+		} else if _, err := mail.ParseAddress(row.email); err != nil {
+			row.result.Errors = append(row.result.Errors, "email is invalid")
+		} else if firstLine, ok := seenEmails[row.email]; ok {
+			row.result.Errors = append(row.result.Errors, fmt.Sprintf("email is duplicated on line %d", firstLine))
+		} else {
+			seenEmails[row.email] = line
+			exists, err := s.userRepo.EmailExists(ctx, row.email)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check email: %v", err)
+			}
+			if exists {
+				row.result.Errors = append(row.result.Errors, "email is already in use")
+			}
+		}
+
+		if row.phone != "" {
+			phone, err := normalizePhone(row.phone)
+			if err != nil {
+				row.result.Errors = append(row.result.Errors, fmt.Sprintf("phone: %v", err))
+			}
+			row.phone = phone
+		}
+
+		if salary := field(record, "salary"); salary != "" {
+			value, err := strconv.ParseFloat(salary, 64)
+			if err != nil {
+				row.result.Errors = append(row.result.Errors, "salary must be a number")
+			} else if value < 0 {
+				row.result.Errors = append(row.result.Errors, "salary cannot be negative")
+			} else {
+				row.salary = value
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("CSV file has no data rows")
+	}
+
+	return rows, nil
+}
+
+func (s *teacherService) createImportedTeachers(ctx context.Context, businessID uint, rows []teacherImportRow) error {
+	tx := s.teacherRepo.BeginTransaction(ctx)
+
+	for _, row := range rows {
+		password, err := generatePassword(12)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to generate password: %v", err)
+		}
+
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to hash password: %v", err)
+		}
+
+		user := &models.User{
+			Name:     row.name,
+			Email:    row.email,
+			Phone:    row.phone,
+			Password: string(hashedPassword),
+			Role:     models.RoleTeacher,
+			Status:   models.StatusActive,
+		}
+		if err := s.userRepo.CreateUserInTransaction(tx, user); err != nil {
+			tx.Rollback()
+			if conflict := conflictError(err); conflict != nil {
+				return fmt.Errorf("line %d: %v", row.result.Row, conflict)
+			}
+			return fmt.Errorf("failed to create user on line %d: %v", row.result.Row, err)
+		}
+
+		teacher := &models.Teacher{
+			Name:          row.name,
+			UserID:        user.ID,
+			BusinessID:    businessID,
+			Salary:        row.salary,
+			Qualification: row.qualification,
+			Experience:    row.experience,
+			Status:        models.StatusActive,
+		}
+		if err := s.teacherRepo.CreateWithTransaction(tx, teacher); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to create teacher on line %d: %v", row.result.Row, err)
+		}
+
+		row.result.Status = models.ImportRowCreated
+		row.result.UserID = user.ID
+		row.result.TeacherID = teacher.ID
+		row.result.GeneratedPassword = password
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit teacher import: %v", err)
+	}
+
+	return nil
+}
+
+const passwordAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+
+// generatePassword returns a random password that avoids easily confused characters
+func generatePassword(length int) (string, error) {
+	password := make([]byte, length)
+	max := big.NewInt(int64(len(passwordAlphabet)))
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		password[i] = passwordAlphabet[n.Int64()]
+	}
+	return string(password), nil
+}
@@ -3,41 +3,80 @@ package services
 import (
 	"backend/internal/models"
 	"backend/internal/repository"
+	"backend/pkg/logger"
+	"backend/pkg/storage"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 )
 
 type TeacherService interface {
 	// Basic CRUD operations
-	CreateTeacher(req models.CreateTeacherRequest) (*models.TeacherResponse, error)
-	GetTeacherByID(id uint) (*models.TeacherResponse, error)
-	GetTeacherByUserID(userID uint) (*models.TeacherResponse, error)
-	GetTeachers(filters repository.TeacherFilters) ([]models.TeacherResponse, int64, error)
-	UpdateTeacher(teacherID uint, updates map[string]interface{}) (*models.TeacherResponse, error)
-	DeleteTeacher(teacherID uint) error
+	CreateTeacher(ctx context.Context, req models.CreateTeacherRequest) (*models.TeacherResponse, error)
+	GetTeacherByID(ctx context.Context, id uint) (*models.TeacherResponse, error)
+	GetTeacherByUserID(ctx context.Context, userID uint) (*models.TeacherResponse, error)
+	GetTeachers(ctx context.Context, filters repository.TeacherFilters) ([]models.TeacherResponse, repository.PageInfo, error)
+	UpdateTeacher(ctx context.Context, teacherID uint, updates map[string]interface{}, actorID uint) (*models.TeacherResponse, error)
+	DeleteTeacher(ctx context.Context, teacherID uint, opts models.DeleteProfileOptions) error
+	RestoreTeacher(ctx context.Context, teacherID uint) (*models.TeacherResponse, error)
+	// PurgeDeletedTeachers permanently removes the teachers soft-deleted before cutoff
+	PurgeDeletedTeachers(ctx context.Context, cutoff time.Time) (int, error)
+
+	// Self-service
+	UpdateTeacherSelf(ctx context.Context, userID uint, req models.UpdateTeacherSelfRequest) (*models.TeacherResponse, error)
 
 	// Business specific operations
-	GetTeachersByBusiness(businessID uint, filters repository.TeacherFilters) ([]models.TeacherResponse, int64, error)
-	GetActiveTeachersByBusiness(businessID uint) ([]models.TeacherResponse, error)
-	GetInactiveTeachersByBusiness(businessID uint) ([]models.TeacherResponse, error)
+	GetTeachersByBusiness(ctx context.Context, businessID uint, filters repository.TeacherFilters) ([]models.TeacherResponse, repository.PageInfo, error)
 
 	// Status operations
-	ChangeTeacherStatus(teacherID uint, status int) error
-	GetActiveTeachers() ([]models.TeacherResponse, error)
-	GetInactiveTeachers() ([]models.TeacherResponse, error)
+	ChangeTeacherStatus(ctx context.Context, teacherID uint, status models.Status) error
 
 	// Search
-	SearchTeachers(searchTerm string, limit int, businessID ...uint) ([]models.TeacherResponse, error)
-	SearchTeachersByBusiness(businessID uint, searchTerm string, limit int) ([]models.TeacherResponse, error)
+	SearchTeachers(ctx context.Context, searchTerm string, limit int, filters repository.TeacherFilters) ([]models.TeacherResponse, error)
+	SearchTeachersByBusiness(ctx context.Context, businessID uint, searchTerm string, limit int) ([]models.TeacherResponse, error)
 
 	// Statistics
-	GetTeacherStats(businessID ...uint) (map[string]interface{}, error)
-	GetSalaryStats(businessID ...uint) (map[string]interface{}, error)
-	GetQualificationStats(businessID ...uint) (map[string]int64, error)
+	GetRecentTeachers(ctx context.Context, limit int, businessID ...uint) (*models.RecentProfiles, error)
+	GetTeacherStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error)
+	GetSalaryStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error)
+	GetQualificationStats(ctx context.Context, businessID ...uint) (map[string]int64, error)
+	GetExperienceStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error)
+	GetSubjectStats(ctx context.Context, businessID ...uint) (map[uint]map[string]int64, error)
+
+	// Subjects
+	AssignSubjects(ctx context.Context, teacherID uint, subjectIDs []uint) (*models.TeacherResponse, error)
 
 	// Bulk operations
-	BulkUpdateTeacherStatus(teacherIDs []uint, status int) error
-	BulkUpdateSalary(teacherIDs []uint, salary float64) error
+	BulkUpdateTeacherStatus(ctx context.Context, teacherIDs []uint, status models.Status, atomic bool) (*models.BulkUpdateResult, error)
+	BulkDeleteTeachers(ctx context.Context, req models.BulkDeleteTeachersRequest, userID uint, role string) ([]models.BulkDeleteResult, error)
+	BulkUpdateSalary(ctx context.Context, req models.BulkUpdateSalaryRequest, actorID uint) ([]models.SalaryAdjustmentResult, error)
+
+	// Salary history
+	GetSalaryHistory(ctx context.Context, teacherID uint) ([]models.TeacherSalaryHistoryResponse, error)
+
+	// Import
+	ImportTeachers(ctx context.Context, businessID uint, content io.Reader, dryRun bool) (*models.TeacherImportReport, error)
+	TeacherExportColumns(columns string, includeSalary bool) ([]string, error)
+	ExportTeachers(ctx context.Context, businessID uint, filters repository.TeacherFilters, columns []string, includeSalary bool, w io.Writer) (int64, error)
+
+	// Transfers
+	TransferTeacher(ctx context.Context, teacherID uint, req models.TransferTeacherRequest, actorID uint) (*models.TeacherResponse, error)
+	GetAssignmentHistory(ctx context.Context, teacherID uint) ([]models.TeacherAssignmentHistoryResponse, error)
+
+	// Availability
+	GetAvailability(ctx context.Context, teacherID uint) ([]models.TeacherAvailabilityResponse, error)
+	AddAvailability(ctx context.Context, teacherID uint, req models.CreateAvailabilityRequest) (*models.TeacherAvailabilityResponse, error)
+	UpdateAvailability(ctx context.Context, teacherID, availabilityID uint, req models.UpdateAvailabilityRequest) (*models.TeacherAvailabilityResponse, error)
+	DeleteAvailability(ctx context.Context, teacherID, availabilityID uint) error
+	GetAvailableTeachers(ctx context.Context, businessID uint, weekday int, at string) ([]models.TeacherResponse, error)
+
+	// Access control
+	CheckTeacherAccess(ctx context.Context, teacherID, userID uint, role string) error
+	CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error
+	GetBusinessIDByUser(ctx context.Context, userID uint) (uint, error)
 
 	// Validation
 	ValidateCreateTeacherRequest(req models.CreateTeacherRequest) error
@@ -48,30 +87,54 @@ type teacherService struct {
 	teacherRepo  repository.TeacherRepository
 	userRepo     repository.UserRepository
 	businessRepo repository.BusinessRepository
+	subjectRepo  repository.SubjectRepository
+
+	availabilityRepo repository.TeacherAvailabilityRepository
+	documentRepo     repository.TeacherDocumentRepository
+	assignmentRepo   repository.TeacherStudentRepository
+	storage          storage.Storage
+
+	qualificationRepo repository.QualificationRepository
 }
 
-func NewTeacherService(teacherRepo repository.TeacherRepository, userRepo repository.UserRepository, businessRepo repository.BusinessRepository) TeacherService {
+func NewTeacherService(teacherRepo repository.TeacherRepository, userRepo repository.UserRepository, businessRepo repository.BusinessRepository, subjectRepo repository.SubjectRepository, availabilityRepo repository.TeacherAvailabilityRepository, documentRepo repository.TeacherDocumentRepository, assignmentRepo repository.TeacherStudentRepository, store storage.Storage, qualificationRepo repository.QualificationRepository) TeacherService {
 	return &teacherService{
-		teacherRepo:  teacherRepo,
-		userRepo:     userRepo,
-		businessRepo: businessRepo,
+		teacherRepo:      teacherRepo,
+		userRepo:         userRepo,
+		businessRepo:     businessRepo,
+		subjectRepo:      subjectRepo,
+		availabilityRepo: availabilityRepo,
+		documentRepo:     documentRepo,
+		assignmentRepo:   assignmentRepo,
+		storage:          store,
+
+		qualificationRepo: qualificationRepo,
 	}
 }
 
-func (s *teacherService) CreateTeacher(req models.CreateTeacherRequest) (*models.TeacherResponse, error) {
+func (s *teacherService) CreateTeacher(ctx context.Context, req models.CreateTeacherRequest) (*models.TeacherResponse, error) {
 	// Validate request
 	if err := s.ValidateCreateTeacherRequest(req); err != nil {
 		return nil, err
 	}
 
 	// Check if user exists and is not already a teacher
-	_, err := s.userRepo.GetByID(req.UserID)
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("user not found")
 	}
 
+	// Teachers and business owners who also teach may hold a teacher profile; a
+	// student can't be a teacher as well
+	if !user.Role.CanHoldProfile(models.RoleTeacher) {
+		if user.Role == models.RoleStudent {
+			return nil, fmt.Errorf("user is already a student")
+		}
+		return nil, fmt.Errorf("a %s user can't hold a teacher profile", user.Role)
+	}
+
 	// Check if user is already a teacher
-	exists, err := s.teacherRepo.TeacherUserExists(req.UserID)
+	exists, err := s.teacherRepo.TeacherUserExists(ctx, req.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check if user is already a teacher")
 	}
@@ -80,11 +143,16 @@ func (s *teacherService) CreateTeacher(req models.CreateTeacherRequest) (*models
 	}
 
 	// Check if business exists
-	_, err = s.businessRepo.GetByID(req.BusinessID)
+	_, err = s.businessRepo.GetByID(ctx, req.BusinessID)
 	if err != nil {
 		return nil, fmt.Errorf("business not found")
 	}
 
+	qualification, err := resolveQualification(ctx, s.qualificationRepo, req.BusinessID, req.QualificationID, req.Qualification)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create teacher
 	teacher := &models.Teacher{
 		Name:          req.Name,
@@ -94,15 +162,21 @@ func (s *teacherService) CreateTeacher(req models.CreateTeacherRequest) (*models
 		Qualification: req.Qualification,
 		Experience:    req.Experience,
 		Description:   req.Description,
-		Status:        1, // Active by default
+		Status:        models.StatusActive, // Active by default
+
+		ExperienceYears: req.ExperienceYears,
 	}
+	setTeacherQualification(teacher, qualification)
 
-	if err := s.teacherRepo.Create(teacher); err != nil {
+	if err := s.teacherRepo.Create(ctx, teacher); err != nil {
+		if conflict := conflictError(err); conflict != nil {
+			return nil, conflict
+		}
 		return nil, fmt.Errorf("failed to create teacher: %v", err)
 	}
 
 	// Get teacher with relations
-	teacherWithRelations, err := s.teacherRepo.GetTeacherWithRelations(teacher.ID)
+	teacherWithRelations, err := s.teacherRepo.GetTeacherWithRelations(ctx, teacher.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get created teacher")
 	}
@@ -110,42 +184,48 @@ func (s *teacherService) CreateTeacher(req models.CreateTeacherRequest) (*models
 	return s.toTeacherResponse(teacherWithRelations), nil
 }
 
-func (s *teacherService) GetTeacherByID(id uint) (*models.TeacherResponse, error) {
-	teacher, err := s.teacherRepo.GetTeacherWithRelations(id)
+func (s *teacherService) GetTeacherByID(ctx context.Context, id uint) (*models.TeacherResponse, error) {
+	teacher, err := s.teacherRepo.GetTeacherWithRelations(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("teacher not found")
 	}
 
-	return s.toTeacherResponse(teacher), nil
+	response := s.toTeacherResponse(teacher)
+
+	// Include the weekly schedule
+	availability, err := s.GetAvailability(ctx, teacher.ID)
+	if err != nil {
+		return nil, err
+	}
+	response.Availability = availability
+
+	counted := s.withCounts(ctx, []models.TeacherResponse{*response})
+	return &counted[0], nil
 }
 
-func (s *teacherService) GetTeacherByUserID(userID uint) (*models.TeacherResponse, error) {
-	teacher, err := s.teacherRepo.GetByUserID(userID)
+func (s *teacherService) GetTeacherByUserID(ctx context.Context, userID uint) (*models.TeacherResponse, error) {
+	teacher, err := s.teacherRepo.GetByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("teacher profile not found")
 	}
 
 	// Get with relations
-	teacherWithRelations, err := s.teacherRepo.GetTeacherWithRelations(teacher.ID)
+	teacherWithRelations, err := s.teacherRepo.GetTeacherWithRelations(ctx, teacher.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get teacher details")
 	}
 
-	return s.toTeacherResponse(teacherWithRelations), nil
+	response := s.toTeacherResponse(teacherWithRelations)
+	counted := s.withCounts(ctx, []models.TeacherResponse{*response})
+	return &counted[0], nil
 }
 
-func (s *teacherService) GetTeachers(filters repository.TeacherFilters) ([]models.TeacherResponse, int64, error) {
-	// Set default pagination
-	if filters.Page == 0 {
-		filters.Page = 1
-	}
-	if filters.Limit == 0 {
-		filters.Limit = 10
-	}
+func (s *teacherService) GetTeachers(ctx context.Context, filters repository.TeacherFilters) ([]models.TeacherResponse, repository.PageInfo, error) {
+	applyPageDefaults(&filters.Page, &filters.Limit)
 
-	teachers, total, err := s.teacherRepo.GetAllWithRelations(filters)
+	teachers, total, err := s.teacherRepo.GetAllWithRelations(ctx, filters)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get teachers: %v", err)
+		return nil, repository.PageInfo{}, fmt.Errorf("failed to get teachers: %w", err)
 	}
 
 	var responses []models.TeacherResponse
@@ -153,15 +233,26 @@ func (s *teacherService) GetTeachers(filters repository.TeacherFilters) ([]model
 		responses = append(responses, *s.toTeacherResponse(&teacher))
 	}
 
-	return responses, total, nil
+	// The counts cost extra queries, skip them when the caller selected other fields
+	if selection, _ := filters.Selection(); !selection.HasField("document_count") && !selection.HasField("assigned_student_count") {
+		return responses, filters.PageInfo(total, teachers), nil
+	}
+	return s.withCounts(ctx, responses), filters.PageInfo(total, teachers), nil
 }
 
-func (s *teacherService) UpdateTeacher(teacherID uint, updates map[string]interface{}) (*models.TeacherResponse, error) {
+func (s *teacherService) UpdateTeacher(ctx context.Context, teacherID uint, updates map[string]interface{}, actorID uint) (*models.TeacherResponse, error) {
 	// Get existing teacher
-	teacher, err := s.teacherRepo.GetByID(teacherID)
+	teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
 	if err != nil {
 		return nil, fmt.Errorf("teacher not found")
 	}
+	oldSalary := teacher.Salary
+	oldStatus := teacher.Status
+
+	version, versioned := expectedVersion(updates)
+	if versioned && version != teacher.Version {
+		return nil, s.versionConflict(ctx, teacher.ID)
+	}
 
 	// Update fields
 	if name, ok := updates["name"]; ok {
@@ -176,10 +267,14 @@ func (s *teacherService) UpdateTeacher(teacherID uint, updates map[string]interf
 		}
 	}
 
-	if qualification, ok := updates["qualification"]; ok {
-		if qualStr, ok := qualification.(string); ok {
-			teacher.Qualification = qualStr
+	qualificationID, _ := updates["qualification_id"].(uint)
+	qualificationText, _ := updates["qualification"].(string)
+	if qualificationID != 0 || qualificationText != "" {
+		qualification, err := resolveQualification(ctx, s.qualificationRepo, teacher.BusinessID, &qualificationID, qualificationText)
+		if err != nil {
+			return nil, err
 		}
+		setTeacherQualification(teacher, qualification)
 	}
 
 	if experience, ok := updates["experience"]; ok {
@@ -188,25 +283,75 @@ func (s *teacherService) UpdateTeacher(teacherID uint, updates map[string]interf
 		}
 	}
 
+	if experienceYears, ok := updates["experience_years"]; ok {
+		if years, ok := experienceYears.(float64); ok && years >= 0 {
+			teacher.ExperienceYears = &years
+		}
+	}
+
 	if description, ok := updates["description"]; ok {
 		if descStr, ok := description.(string); ok {
 			teacher.Description = descStr
 		}
 	}
 
-	if status, ok := updates["status"]; ok {
-		if statusInt, ok := status.(int); ok && (statusInt == 0 || statusInt == 1) {
-			teacher.Status = statusInt
-		}
+	if status, ok, err := updatedStatus(updates); err != nil {
+		return nil, err
+	} else if ok {
+		teacher.Status = status
+	}
+
+	// Record salary changes alongside the update
+	var history []models.TeacherSalaryHistory
+	if teacher.Salary != oldSalary {
+		now := time.Now()
+		reason, _ := updates["salary_reason"].(string)
+		teacher.LastSalaryChangeAt = &now
+		history = append(history, models.TeacherSalaryHistory{
+			TeacherID:     teacher.ID,
+			OldSalary:     oldSalary,
+			NewSalary:     teacher.Salary,
+			EffectiveDate: now,
+			ChangedBy:     actorID,
+			Reason:        reason,
+		})
 	}
 
 	// Save updates
-	if err := s.teacherRepo.Update(teacher); err != nil {
+	tx := s.teacherRepo.BeginTransaction(ctx)
+
+	if versioned {
+		err = s.teacherRepo.UpdateVersionedWithTransaction(tx, teacher, version)
+	} else {
+		err = s.teacherRepo.UpdateWithTransaction(tx, teacher)
+	}
+	if errors.Is(err, repository.ErrVersionConflict) {
+		tx.Rollback()
+		return nil, s.versionConflict(ctx, teacher.ID)
+	}
+	if err != nil {
+		tx.Rollback()
 		return nil, fmt.Errorf("failed to update teacher: %v", err)
 	}
 
+	if err := s.teacherRepo.CreateSalaryHistoryWithTransaction(tx, history); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record salary history: %v", err)
+	}
+
+	if teacher.Status != oldStatus {
+		if err := s.teacherRepo.RecordStatusChangeWithTransaction(tx, teacher.ID, teacher.Status); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to record status change: %v", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit teacher update: %v", err)
+	}
+
 	// Get updated teacher with relations
-	updatedTeacher, err := s.teacherRepo.GetTeacherWithRelations(teacher.ID)
+	updatedTeacher, err := s.teacherRepo.GetTeacherWithRelations(ctx, teacher.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get updated teacher")
 	}
@@ -214,193 +359,495 @@ func (s *teacherService) UpdateTeacher(teacherID uint, updates map[string]interf
 	return s.toTeacherResponse(updatedTeacher), nil
 }
 
-func (s *teacherService) DeleteTeacher(teacherID uint) error {
-	// Check if teacher exists
-	_, err := s.teacherRepo.GetByID(teacherID)
+// UpdateTeacherSelf applies a teacher's own profile changes. Only the fields of
+// UpdateTeacherSelfRequest can change; a name change is mirrored to the user.
+func (s *teacherService) UpdateTeacherSelf(ctx context.Context, userID uint, req models.UpdateTeacherSelfRequest) (*models.TeacherResponse, error) {
+	teacher, err := s.teacherRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("teacher profile not found")
+	}
+
+	if req.Name != "" && strings.TrimSpace(req.Name) == "" {
+		return nil, fmt.Errorf("name cannot be empty")
+	}
+
+	nameChanged := false
+	if name := strings.TrimSpace(req.Name); name != "" && name != teacher.Name {
+		teacher.Name = name
+		nameChanged = true
+	}
+	if req.QualificationID != nil || req.Qualification != "" {
+		qualification, err := resolveQualification(ctx, s.qualificationRepo, teacher.BusinessID, req.QualificationID, req.Qualification)
+		if err != nil {
+			return nil, err
+		}
+		setTeacherQualification(teacher, qualification)
+	}
+	if req.Experience != "" {
+		teacher.Experience = req.Experience
+	}
+	if req.ExperienceYears != nil {
+		teacher.ExperienceYears = req.ExperienceYears
+	}
+	if req.Description != "" {
+		teacher.Description = req.Description
+	}
+
+	tx := s.teacherRepo.BeginTransaction(ctx)
+
+	if err := s.teacherRepo.UpdateWithTransaction(tx, teacher); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update teacher: %v", err)
+	}
+
+	if nameChanged {
+		if err := s.userRepo.UpdateUserNameInTransaction(tx, teacher.UserID, teacher.Name); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to update user name: %v", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit teacher update: %v", err)
+	}
+
+	return s.GetTeacherByUserID(ctx, userID)
+}
+
+func (s *teacherService) DeleteTeacher(ctx context.Context, teacherID uint, opts models.DeleteProfileOptions) error {
+	if !opts.Permanent {
+		teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
+		if err != nil {
+			return fmt.Errorf("teacher not found")
+		}
+		return s.softDeleteTeachers(ctx, []models.Teacher{*teacher})
+	}
+
+	// A permanent delete may also finish off a soft-deleted teacher
+	teacher, err := s.teacherRepo.GetByIDWithDeleted(ctx, teacherID)
 	if err != nil {
 		return fmt.Errorf("teacher not found")
 	}
 
-	if err := s.teacherRepo.Delete(teacherID); err != nil {
+	// Attendance and documents are only removed when asked for explicitly
+	if !opts.Cascade {
+		counts, err := s.teacherRepo.CountDependentRecords(ctx, teacherID)
+		if err != nil {
+			return fmt.Errorf("failed to check teacher records: %v", err)
+		}
+		if counts["attendance"] > 0 || counts["documents"] > 0 {
+			return fmt.Errorf("teacher has %d attendance records and %d documents; delete with cascade to remove them",
+				counts["attendance"], counts["documents"])
+		}
+	}
+
+	documents, err := s.documentRepo.GetByTeacherID(ctx, teacherID)
+	if err != nil {
+		return fmt.Errorf("failed to get teacher documents: %v", err)
+	}
+
+	tx := s.teacherRepo.BeginTransaction(ctx)
+
+	if err := s.teacherRepo.DeleteWithTransaction(tx, teacherID); err != nil {
+		tx.Rollback()
 		return fmt.Errorf("failed to delete teacher: %v", err)
 	}
 
+	// Never leave a teacher login without a profile behind
+	if opts.DeleteUser {
+		err = s.userRepo.DeleteUserInTransaction(tx, teacher.UserID)
+	} else {
+		err = s.userRepo.UpdateUserStatusInTransaction(tx, teacher.UserID, 0)
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update user account: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit teacher deletion: %v", err)
+	}
+
+	// The records are gone; leftover files are only logged
+	for _, document := range documents {
+		if err := s.storage.Delete(document.StoragePath); err != nil {
+			logger.FromContext(ctx).Warn("failed to remove stored document", "path", document.StoragePath, "error", err)
+		}
+	}
+
 	return nil
 }
 
-func (s *teacherService) GetTeachersByBusiness(businessID uint, filters repository.TeacherFilters) ([]models.TeacherResponse, int64, error) {
-	// Set default pagination
-	if filters.Page == 0 {
-		filters.Page = 1
+// softDeleteTeachers hides the teachers, keeping their attendance and documents, and
+// deactivates their logins until they are restored
+func (s *teacherService) softDeleteTeachers(ctx context.Context, teachers []models.Teacher) error {
+	userIDs := make([]uint, 0, len(teachers))
+	tx := s.teacherRepo.BeginTransaction(ctx)
+
+	for _, teacher := range teachers {
+		if err := s.teacherRepo.SoftDeleteWithTransaction(tx, teacher.ID); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to delete teacher %d: %v", teacher.ID, err)
+		}
+		userIDs = append(userIDs, teacher.UserID)
 	}
-	if filters.Limit == 0 {
-		filters.Limit = 10
+
+	if err := s.userRepo.BulkUpdateStatusInTransaction(tx, userIDs, 0); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to deactivate user accounts: %v", err)
 	}
 
-	teachers, total, err := s.teacherRepo.GetByBusinessID(businessID, filters)
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit teacher deletion: %v", err)
+	}
+	return nil
+}
+
+// RestoreTeacher brings back a soft-deleted teacher, with its login active again
+// when the teacher is
+func (s *teacherService) RestoreTeacher(ctx context.Context, teacherID uint) (*models.TeacherResponse, error) {
+	teacher, err := s.teacherRepo.GetByIDWithDeleted(ctx, teacherID)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get teachers by business: %v", err)
+		return nil, fmt.Errorf("teacher not found")
+	}
+	if !teacher.DeletedAt.Valid {
+		return nil, fmt.Errorf("teacher is not deleted")
 	}
 
-	var responses []models.TeacherResponse
-	for _, teacher := range teachers {
-		// Get with relations for complete data
-		teacherWithRelations, err := s.teacherRepo.GetTeacherWithRelations(teacher.ID)
-		if err != nil {
-			continue // Skip this one if there's an issue
-		}
-		responses = append(responses, *s.toTeacherResponse(teacherWithRelations))
+	tx := s.teacherRepo.BeginTransaction(ctx)
+
+	if err := s.teacherRepo.RestoreWithTransaction(tx, teacherID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to restore teacher: %v", err)
 	}
 
-	return responses, total, nil
+	if err := s.userRepo.UpdateUserStatusInTransaction(tx, teacher.UserID, teacher.Status); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update user account: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit teacher restore: %v", err)
+	}
+
+	return s.GetTeacherByID(ctx, teacherID)
 }
 
-func (s *teacherService) GetActiveTeachersByBusiness(businessID uint) ([]models.TeacherResponse, error) {
-	teachers, err := s.teacherRepo.GetActiveTeachersByBusiness(businessID)
+// PurgeDeletedTeachers permanently removes each teacher soft-deleted before cutoff with
+// its attendance and documents, as a cascading permanent delete would. The logins stay
+// deactivated.
+func (s *teacherService) PurgeDeletedTeachers(ctx context.Context, cutoff time.Time) (int, error) {
+	teachers, err := s.teacherRepo.GetDeletedBefore(ctx, cutoff)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get active teachers: %v", err)
+		return 0, fmt.Errorf("failed to get deleted teachers: %v", err)
 	}
 
-	var responses []models.TeacherResponse
+	purged := 0
 	for _, teacher := range teachers {
-		teacherWithRelations, err := s.teacherRepo.GetTeacherWithRelations(teacher.ID)
+		documents, err := s.documentRepo.GetByTeacherID(ctx, teacher.ID)
 		if err != nil {
-			continue
+			return purged, fmt.Errorf("failed to get teacher documents: %v", err)
+		}
+
+		tx := s.teacherRepo.BeginTransaction(ctx)
+		if err := s.teacherRepo.DeleteWithTransaction(tx, teacher.ID); err != nil {
+			tx.Rollback()
+			return purged, fmt.Errorf("failed to purge teacher %d: %v", teacher.ID, err)
+		}
+		if err := tx.Commit().Error; err != nil {
+			return purged, fmt.Errorf("failed to commit teacher purge: %v", err)
+		}
+		purged++
+
+		for _, document := range documents {
+			if err := s.storage.Delete(document.StoragePath); err != nil {
+				logger.FromContext(ctx).Warn("failed to remove stored document", "path", document.StoragePath, "error", err)
+			}
 		}
-		responses = append(responses, *s.toTeacherResponse(teacherWithRelations))
 	}
 
-	return responses, nil
+	return purged, nil
 }
 
-func (s *teacherService) GetInactiveTeachersByBusiness(businessID uint) ([]models.TeacherResponse, error) {
-	teachers, err := s.teacherRepo.GetInactiveTeachersByBusiness(businessID)
+func (s *teacherService) GetTeachersByBusiness(ctx context.Context, businessID uint, filters repository.TeacherFilters) ([]models.TeacherResponse, repository.PageInfo, error) {
+	applyPageDefaults(&filters.Page, &filters.Limit)
+
+	teachers, total, err := s.teacherRepo.GetByBusinessID(ctx, businessID, filters)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get inactive teachers: %v", err)
+		return nil, repository.PageInfo{}, fmt.Errorf("failed to get teachers by business: %w", err)
 	}
 
 	var responses []models.TeacherResponse
 	for _, teacher := range teachers {
-		teacherWithRelations, err := s.teacherRepo.GetTeacherWithRelations(teacher.ID)
+		// Get with relations for complete data
+		teacherWithRelations, err := s.teacherRepo.GetTeacherWithRelations(ctx, teacher.ID)
 		if err != nil {
-			continue
+			continue // Skip this one if there's an issue
 		}
 		responses = append(responses, *s.toTeacherResponse(teacherWithRelations))
 	}
 
-	return responses, nil
+	return s.withCounts(ctx, responses), filters.PageInfo(total, teachers), nil
 }
 
-func (s *teacherService) ChangeTeacherStatus(teacherID uint, status int) error {
+func (s *teacherService) ChangeTeacherStatus(ctx context.Context, teacherID uint, status models.Status) error {
 	// Check if teacher exists
-	_, err := s.teacherRepo.GetByID(teacherID)
+	teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
 	if err != nil {
 		return fmt.Errorf("teacher not found")
 	}
 
-	if err := s.teacherRepo.UpdateTeacherStatus(teacherID, status); err != nil {
+	// The teacher's login follows the teacher's status
+	tx := s.teacherRepo.BeginTransaction(ctx)
+
+	if err := s.teacherRepo.UpdateTeacherStatusWithTransaction(tx, teacherID, status); err != nil {
+		tx.Rollback()
 		return fmt.Errorf("failed to update teacher status: %v", err)
 	}
 
-	return nil
-}
-
-func (s *teacherService) GetActiveTeachers() ([]models.TeacherResponse, error) {
-	teachers, err := s.teacherRepo.GetActiveTeachers()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get active teachers: %v", err)
+	if err := s.userRepo.UpdateUserStatusInTransaction(tx, teacher.UserID, status); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update user status: %v", err)
 	}
 
-	var responses []models.TeacherResponse
-	for _, teacher := range teachers {
-		teacherWithRelations, err := s.teacherRepo.GetTeacherWithRelations(teacher.ID)
-		if err != nil {
-			continue
-		}
-		responses = append(responses, *s.toTeacherResponse(teacherWithRelations))
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit status change: %v", err)
 	}
 
-	return responses, nil
+	return nil
 }
 
-func (s *teacherService) GetInactiveTeachers() ([]models.TeacherResponse, error) {
-	teachers, err := s.teacherRepo.GetInactiveTeachers()
+func (s *teacherService) SearchTeachers(ctx context.Context, searchTerm string, limit int, filters repository.TeacherFilters) ([]models.TeacherResponse, error) {
+	teachers, err := s.teacherRepo.SearchTeachers(ctx, searchTerm, limit, filters)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get inactive teachers: %v", err)
+		return nil, fmt.Errorf("failed to search teachers: %v", err)
 	}
 
 	var responses []models.TeacherResponse
 	for _, teacher := range teachers {
-		teacherWithRelations, err := s.teacherRepo.GetTeacherWithRelations(teacher.ID)
+		teacherWithRelations, err := s.teacherRepo.GetTeacherWithRelations(ctx, teacher.ID)
 		if err != nil {
 			continue
 		}
 		responses = append(responses, *s.toTeacherResponse(teacherWithRelations))
 	}
 
-	return responses, nil
+	return s.withCounts(ctx, responses), nil
+}
+
+func (s *teacherService) SearchTeachersByBusiness(ctx context.Context, businessID uint, searchTerm string, limit int) ([]models.TeacherResponse, error) {
+	return s.SearchTeachers(ctx, searchTerm, limit, repository.TeacherFilters{BusinessID: &businessID})
 }
 
-func (s *teacherService) SearchTeachers(searchTerm string, limit int, businessID ...uint) ([]models.TeacherResponse, error) {
-	teachers, err := s.teacherRepo.SearchTeachers(searchTerm, limit, businessID...)
+func (s *teacherService) GetTeacherStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error) {
+	stats, err := s.teacherRepo.GetTeacherStats(ctx, businessID...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search teachers: %v", err)
+		return nil, err
 	}
 
-	var responses []models.TeacherResponse
-	for _, teacher := range teachers {
-		teacherWithRelations, err := s.teacherRepo.GetTeacherWithRelations(teacher.ID)
-		if err != nil {
-			continue
-		}
-		responses = append(responses, *s.toTeacherResponse(teacherWithRelations))
+	// Include student workload
+	workload, err := s.assignmentRepo.GetWorkloadStats(ctx, businessID...)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range workload {
+		stats[key] = value
 	}
 
-	return responses, nil
+	return stats, nil
 }
 
-func (s *teacherService) SearchTeachersByBusiness(businessID uint, searchTerm string, limit int) ([]models.TeacherResponse, error) {
-	return s.SearchTeachers(searchTerm, limit, businessID)
+func (s *teacherService) GetSalaryStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error) {
+	return s.teacherRepo.GetSalaryStats(ctx, businessID...)
 }
 
-func (s *teacherService) GetTeacherStats(businessID ...uint) (map[string]interface{}, error) {
-	return s.teacherRepo.GetTeacherStats(businessID...)
+func (s *teacherService) GetQualificationStats(ctx context.Context, businessID ...uint) (map[string]int64, error) {
+	return s.teacherRepo.GetQualificationStats(ctx, businessID...)
 }
 
-func (s *teacherService) GetSalaryStats(businessID ...uint) (map[string]interface{}, error) {
-	return s.teacherRepo.GetSalaryStats(businessID...)
+func (s *teacherService) GetExperienceStats(ctx context.Context, businessID ...uint) (map[string]interface{}, error) {
+	return s.teacherRepo.GetExperienceStats(ctx, businessID...)
 }
 
-func (s *teacherService) GetQualificationStats(businessID ...uint) (map[string]int64, error) {
-	return s.teacherRepo.GetQualificationStats(businessID...)
+func (s *teacherService) GetSubjectStats(ctx context.Context, businessID ...uint) (map[uint]map[string]int64, error) {
+	return s.teacherRepo.GetSubjectStats(ctx, businessID...)
 }
 
-func (s *teacherService) BulkUpdateTeacherStatus(teacherIDs []uint, status int) error {
+func (s *teacherService) AssignSubjects(ctx context.Context, teacherID uint, subjectIDs []uint) (*models.TeacherResponse, error) {
+	teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("teacher not found")
+	}
+
+	// Only subjects from the teacher's own business can be assigned
+	subjects, err := s.subjectRepo.GetByIDsForBusiness(ctx, teacher.BusinessID, subjectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subjects: %v", err)
+	}
+	if len(subjects) != len(uniqueIDs(subjectIDs)) {
+		return nil, fmt.Errorf("one or more subjects do not belong to the teacher's business")
+	}
+
+	if err := s.teacherRepo.ReplaceSubjects(ctx, teacher.ID, subjects); err != nil {
+		return nil, fmt.Errorf("failed to assign subjects: %v", err)
+	}
+
+	updatedTeacher, err := s.teacherRepo.GetTeacherWithRelations(ctx, teacher.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated teacher")
+	}
+
+	return s.toTeacherResponse(updatedTeacher), nil
+}
+
+// BulkUpdateTeacherStatus changes the status of the listed teachers and their logins,
+// skipping IDs that don't exist unless atomic is set
+func (s *teacherService) BulkUpdateTeacherStatus(ctx context.Context, teacherIDs []uint, status models.Status, atomic bool) (*models.BulkUpdateResult, error) {
 	if len(teacherIDs) == 0 {
-		return fmt.Errorf("no teacher IDs provided")
+		return nil, fmt.Errorf("no teacher IDs provided")
 	}
 
-	if err := s.teacherRepo.BulkUpdateStatus(teacherIDs, status); err != nil {
-		return fmt.Errorf("failed to bulk update teacher status: %v", err)
+	teachers, err := s.teacherRepo.GetByIDs(ctx, teacherIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teachers: %v", err)
 	}
 
-	return nil
+	found := make(map[uint]bool, len(teachers))
+	userIDs := make([]uint, 0, len(teachers))
+	for _, teacher := range teachers {
+		found[teacher.ID] = true
+		userIDs = append(userIDs, teacher.UserID)
+	}
+	result := newBulkResult(teacherIDs, found, "teacher")
+	if ok, err := checkAtomic(result, atomic); !ok {
+		return result, err
+	}
+
+	// The teachers' logins follow the teachers' status
+	tx := s.teacherRepo.BeginTransaction(ctx)
+
+	if err := s.teacherRepo.BulkUpdateStatusWithTransaction(tx, result.Updated, status); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to bulk update teacher status: %v", err)
+	}
+
+	if err := s.userRepo.BulkUpdateStatusInTransaction(tx, userIDs, status); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to bulk update user status: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit status change: %v", err)
+	}
+
+	return result, nil
 }
 
-func (s *teacherService) BulkUpdateSalary(teacherIDs []uint, salary float64) error {
+func (s *teacherService) BulkUpdateSalary(ctx context.Context, req models.BulkUpdateSalaryRequest, actorID uint) ([]models.SalaryAdjustmentResult, error) {
+	teacherIDs := uniqueIDs(req.TeacherIDs)
 	if len(teacherIDs) == 0 {
-		return fmt.Errorf("no teacher IDs provided")
+		return nil, fmt.Errorf("no teacher IDs provided")
 	}
 
-	if salary < 0 {
-		return fmt.Errorf("salary cannot be negative")
+	if req.Value == nil {
+		req.Value = req.Salary
+	}
+	if req.Value == nil {
+		return nil, fmt.Errorf("value is required")
 	}
+	value := *req.Value
 
-	if err := s.teacherRepo.BulkUpdateSalary(teacherIDs, salary); err != nil {
-		return fmt.Errorf("failed to bulk update teacher salary: %v", err)
+	adjustmentType := req.AdjustmentType
+	if adjustmentType == "" {
+		adjustmentType = models.SalaryAdjustmentSet
 	}
 
-	return nil
+	switch adjustmentType {
+	case models.SalaryAdjustmentSet:
+		if value < 0 {
+			return nil, fmt.Errorf("salary cannot be negative")
+		}
+	case models.SalaryAdjustmentIncreasePercent:
+		if value < -100 {
+			return nil, fmt.Errorf("percentage cannot be less than -100")
+		}
+	case models.SalaryAdjustmentIncreaseAmount:
+		// Negative amounts are allowed; the repository rejects negative results
+	default:
+		return nil, fmt.Errorf("invalid adjustment type")
+	}
+
+	now := time.Now()
+	effectiveDate := now
+	if req.EffectiveDate != nil {
+		effectiveDate = *req.EffectiveDate
+	}
+
+	tx := s.teacherRepo.BeginTransaction(ctx)
+
+	results, err := s.teacherRepo.BulkAdjustSalaryWithTransaction(tx, teacherIDs, adjustmentType, value, now)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to bulk update teacher salary: %v", err)
+	}
+
+	if len(results) != len(teacherIDs) {
+		tx.Rollback()
+		return nil, fmt.Errorf("one or more teachers not found")
+	}
+
+	var history []models.TeacherSalaryHistory
+	for _, result := range results {
+		if result.OldSalary == result.NewSalary {
+			continue
+		}
+		history = append(history, models.TeacherSalaryHistory{
+			TeacherID:     result.TeacherID,
+			OldSalary:     result.OldSalary,
+			NewSalary:     result.NewSalary,
+			EffectiveDate: effectiveDate,
+			ChangedBy:     actorID,
+			Reason:        req.Reason,
+		})
+	}
+
+	if err := s.teacherRepo.CreateSalaryHistoryWithTransaction(tx, history); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record salary history: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit salary update: %v", err)
+	}
+
+	return results, nil
+}
+
+func (s *teacherService) GetSalaryHistory(ctx context.Context, teacherID uint) ([]models.TeacherSalaryHistoryResponse, error) {
+	if _, err := s.teacherRepo.GetByID(ctx, teacherID); err != nil {
+		return nil, fmt.Errorf("teacher not found")
+	}
+
+	history, err := s.teacherRepo.GetSalaryHistory(ctx, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get salary history: %v", err)
+	}
+
+	responses := []models.TeacherSalaryHistoryResponse{}
+	for _, entry := range history {
+		responses = append(responses, models.TeacherSalaryHistoryResponse{
+			ID:            entry.ID,
+			TeacherID:     entry.TeacherID,
+			OldSalary:     entry.OldSalary,
+			NewSalary:     entry.NewSalary,
+			EffectiveDate: entry.EffectiveDate,
+			ChangedBy:     entry.ChangedBy,
+			Reason:        entry.Reason,
+			CreatedOn:     entry.CreatedOn,
+		})
+	}
+
+	return responses, nil
 }
 
 func (s *teacherService) ValidateCreateTeacherRequest(req models.CreateTeacherRequest) error {
@@ -439,20 +886,523 @@ func (s *teacherService) ValidateUpdateTeacherRequest(req models.UpdateTeacherRe
 	return nil
 }
 
+func (s *teacherService) TransferTeacher(ctx context.Context, teacherID uint, req models.TransferTeacherRequest, actorID uint) (*models.TeacherResponse, error) {
+	teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("teacher not found")
+	}
+
+	if req.BusinessID == teacher.BusinessID {
+		return nil, fmt.Errorf("teacher already belongs to this business")
+	}
+
+	// Check if target business exists and is active
+	target, err := s.businessRepo.GetByID(ctx, req.BusinessID)
+	if err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+	if !target.Status.IsActive() {
+		return nil, fmt.Errorf("target business is not active")
+	}
+
+	now := time.Now()
+	fromBusinessID := teacher.BusinessID
+	oldSalary := teacher.Salary
+	oldStatus := teacher.Status
+
+	teacher.BusinessID = target.ID
+	teacher.PreviousBusinessID = &fromBusinessID
+	teacher.TransferredAt = &now
+
+	if req.ResetSalary {
+		teacher.Salary = 0
+		if req.Salary != nil {
+			teacher.Salary = *req.Salary
+		}
+	}
+	if req.ResetStatus {
+		teacher.Status = models.StatusActive
+	}
+
+	var salaryHistory []models.TeacherSalaryHistory
+	if teacher.Salary != oldSalary {
+		teacher.LastSalaryChangeAt = &now
+		salaryHistory = append(salaryHistory, models.TeacherSalaryHistory{
+			TeacherID:     teacher.ID,
+			OldSalary:     oldSalary,
+			NewSalary:     teacher.Salary,
+			EffectiveDate: now,
+			ChangedBy:     actorID,
+			Reason:        "Transfer to " + target.Name,
+		})
+	}
+
+	tx := s.teacherRepo.BeginTransaction(ctx)
+
+	if err := s.teacherRepo.UpdateWithTransaction(tx, teacher); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to transfer teacher: %v", err)
+	}
+
+	// Subjects are per business, so the old assignments no longer apply
+	if err := s.teacherRepo.ClearSubjectsWithTransaction(tx, teacher.ID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to clear teacher subjects: %v", err)
+	}
+
+	entry := &models.TeacherAssignmentHistory{
+		TeacherID:      teacher.ID,
+		FromBusinessID: fromBusinessID,
+		ToBusinessID:   target.ID,
+		TransferredBy:  actorID,
+		Reason:         req.Reason,
+	}
+	if err := s.teacherRepo.CreateAssignmentHistoryWithTransaction(tx, entry); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record transfer: %v", err)
+	}
+
+	if err := s.teacherRepo.CreateSalaryHistoryWithTransaction(tx, salaryHistory); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record salary history: %v", err)
+	}
+
+	if teacher.Status != oldStatus {
+		if err := s.teacherRepo.RecordStatusChangeWithTransaction(tx, teacher.ID, teacher.Status); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to record status change: %v", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit teacher transfer: %v", err)
+	}
+
+	return s.GetTeacherByID(ctx, teacher.ID)
+}
+
+func (s *teacherService) GetAssignmentHistory(ctx context.Context, teacherID uint) ([]models.TeacherAssignmentHistoryResponse, error) {
+	if _, err := s.teacherRepo.GetByID(ctx, teacherID); err != nil {
+		return nil, fmt.Errorf("teacher not found")
+	}
+
+	history, err := s.teacherRepo.GetAssignmentHistory(ctx, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignment history: %v", err)
+	}
+
+	responses := []models.TeacherAssignmentHistoryResponse{}
+	for _, entry := range history {
+		responses = append(responses, models.TeacherAssignmentHistoryResponse{
+			ID:               entry.ID,
+			TeacherID:        entry.TeacherID,
+			FromBusinessID:   entry.FromBusinessID,
+			FromBusinessName: entry.FromBusiness.Name,
+			ToBusinessID:     entry.ToBusinessID,
+			ToBusinessName:   entry.ToBusiness.Name,
+			TransferredBy:    entry.TransferredBy,
+			Reason:           entry.Reason,
+			TransferredAt:    entry.TransferredAt,
+		})
+	}
+
+	return responses, nil
+}
+
+func (s *teacherService) GetAvailability(ctx context.Context, teacherID uint) ([]models.TeacherAvailabilityResponse, error) {
+	slots, err := s.availabilityRepo.GetByTeacherID(ctx, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability: %v", err)
+	}
+
+	responses := []models.TeacherAvailabilityResponse{}
+	for _, slot := range slots {
+		responses = append(responses, toAvailabilityResponse(slot))
+	}
+
+	return responses, nil
+}
+
+func (s *teacherService) AddAvailability(ctx context.Context, teacherID uint, req models.CreateAvailabilityRequest) (*models.TeacherAvailabilityResponse, error) {
+	if _, err := s.teacherRepo.GetByID(ctx, teacherID); err != nil {
+		return nil, fmt.Errorf("teacher not found")
+	}
+
+	if req.Weekday == nil {
+		return nil, fmt.Errorf("weekday is required")
+	}
+
+	slot := &models.TeacherAvailability{
+		TeacherID: teacherID,
+		Weekday:   *req.Weekday,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+	}
+
+	if err := s.validateAvailability(ctx, slot); err != nil {
+		return nil, err
+	}
+
+	if err := s.availabilityRepo.Create(ctx, slot); err != nil {
+		return nil, fmt.Errorf("failed to create availability: %v", err)
+	}
+
+	response := toAvailabilityResponse(*slot)
+	return &response, nil
+}
+
+func (s *teacherService) UpdateAvailability(ctx context.Context, teacherID, availabilityID uint, req models.UpdateAvailabilityRequest) (*models.TeacherAvailabilityResponse, error) {
+	slot, err := s.getTeacherAvailability(ctx, teacherID, availabilityID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Weekday != nil {
+		slot.Weekday = *req.Weekday
+	}
+	if req.StartTime != "" {
+		slot.StartTime = req.StartTime
+	}
+	if req.EndTime != "" {
+		slot.EndTime = req.EndTime
+	}
+
+	if err := s.validateAvailability(ctx, slot); err != nil {
+		return nil, err
+	}
+
+	if err := s.availabilityRepo.Update(ctx, slot); err != nil {
+		return nil, fmt.Errorf("failed to update availability: %v", err)
+	}
+
+	response := toAvailabilityResponse(*slot)
+	return &response, nil
+}
+
+func (s *teacherService) DeleteAvailability(ctx context.Context, teacherID, availabilityID uint) error {
+	slot, err := s.getTeacherAvailability(ctx, teacherID, availabilityID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.availabilityRepo.Delete(ctx, slot.ID); err != nil {
+		return fmt.Errorf("failed to delete availability: %v", err)
+	}
+
+	return nil
+}
+
+func (s *teacherService) GetAvailableTeachers(ctx context.Context, businessID uint, weekday int, at string) ([]models.TeacherResponse, error) {
+	if weekday < 0 || weekday > 6 {
+		return nil, fmt.Errorf("weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+
+	at, err := normalizeClockTime(at)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if business exists
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return nil, fmt.Errorf("business not found")
+	}
+
+	teachers, err := s.availabilityRepo.GetAvailableTeachers(ctx, businessID, weekday, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get available teachers: %v", err)
+	}
+
+	responses := []models.TeacherResponse{}
+	for _, teacher := range teachers {
+		responses = append(responses, *s.toTeacherResponse(&teacher))
+	}
+
+	return s.withCounts(ctx, responses), nil
+}
+
+func (s *teacherService) CheckTeacherAccess(ctx context.Context, teacherID, userID uint, role string) error {
+	teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
+	if err != nil {
+		return fmt.Errorf("teacher not found")
+	}
+
+	return checkBusinessAccess(ctx, s.businessRepo, teacher.BusinessID, userID, role)
+}
+
+// BulkDeleteTeachers deletes teachers in one transaction. Every ID must exist and be in
+// the caller's scope. Teachers with attendance or documents are refused unless Cascade
+// is set, as for DeleteTeacher.
+func (s *teacherService) BulkDeleteTeachers(ctx context.Context, req models.BulkDeleteTeachersRequest, userID uint, role string) ([]models.BulkDeleteResult, error) {
+	teacherIDs := uniqueIDs(req.TeacherIDs)
+	if len(teacherIDs) == 0 {
+		return nil, fmt.Errorf("no teacher IDs provided")
+	}
+
+	teachers, err := s.teacherRepo.GetByIDs(ctx, teacherIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teachers: %v", err)
+	}
+	if len(teachers) != len(teacherIDs) {
+		return nil, fmt.Errorf("one or more teachers not found")
+	}
+
+	byID := make(map[uint]models.Teacher, len(teachers))
+	businessIDs := make([]uint, 0, len(teachers))
+	for _, teacher := range teachers {
+		byID[teacher.ID] = teacher
+		businessIDs = append(businessIDs, teacher.BusinessID)
+	}
+	if err := checkBusinessesAccess(ctx, s.businessRepo, businessIDs, userID, role); err != nil {
+		return nil, err
+	}
+
+	results := make([]models.BulkDeleteResult, 0, len(teacherIDs))
+	if !req.Permanent {
+		if err := s.softDeleteTeachers(ctx, teachers); err != nil {
+			return nil, err
+		}
+		for _, id := range teacherIDs {
+			results = append(results, models.BulkDeleteResult{ID: id, Outcome: models.BulkDeleteDeleted})
+		}
+		return results, nil
+	}
+
+	var deleteIDs, userIDs []uint
+	var documents []models.TeacherDocument
+	for _, id := range teacherIDs {
+		result := models.BulkDeleteResult{ID: id}
+
+		if !req.Cascade {
+			counts, err := s.teacherRepo.CountDependentRecords(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check teacher records: %v", err)
+			}
+			if counts["attendance"] > 0 || counts["documents"] > 0 {
+				result.Outcome = models.BulkDeleteRefused
+				result.Reason = fmt.Sprintf("teacher has %d attendance records and %d documents; delete with cascade to remove them",
+					counts["attendance"], counts["documents"])
+				results = append(results, result)
+				continue
+			}
+		}
+
+		teacherDocuments, err := s.documentRepo.GetByTeacherID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get teacher documents: %v", err)
+		}
+		documents = append(documents, teacherDocuments...)
+
+		result.Outcome = models.BulkDeleteDeleted
+		deleteIDs = append(deleteIDs, id)
+		userIDs = append(userIDs, byID[id].UserID)
+		results = append(results, result)
+	}
+
+	if len(deleteIDs) == 0 {
+		return results, nil
+	}
+
+	tx := s.teacherRepo.BeginTransaction(ctx)
+
+	for _, id := range deleteIDs {
+		if err := s.teacherRepo.DeleteWithTransaction(tx, id); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to delete teacher %d: %v", id, err)
+		}
+	}
+
+	// Never leave a teacher login without a profile behind
+	if req.DeleteUser {
+		err = s.userRepo.BulkDeleteInTransaction(tx, userIDs)
+	} else {
+		err = s.userRepo.BulkUpdateStatusInTransaction(tx, userIDs, 0)
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update user accounts: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit teacher deletion: %v", err)
+	}
+
+	// The records are gone; leftover files are only logged
+	for _, document := range documents {
+		if err := s.storage.Delete(document.StoragePath); err != nil {
+			logger.FromContext(ctx).Warn("failed to remove stored document", "path", document.StoragePath, "error", err)
+		}
+	}
+
+	return results, nil
+}
+
+// GetRecentTeachers returns the latest teachers added, with their business names, and how
+// many were added in the last 7 and 30 days, across all businesses or for one
+func (s *teacherService) GetRecentTeachers(ctx context.Context, limit int, businessID ...uint) (*models.RecentProfiles, error) {
+	teachers, err := s.teacherRepo.GetRecent(ctx, recentLimit(limit), businessID...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent teachers: %v", err)
+	}
+
+	additions, err := s.teacherRepo.CountAdditions(ctx, time.Now(), businessID...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recent teachers: %v", err)
+	}
+
+	recent := &models.RecentProfiles{
+		Latest:    make([]models.RecentProfile, 0, len(teachers)),
+		Additions: *additions,
+	}
+	for _, teacher := range teachers {
+		recent.Latest = append(recent.Latest, models.RecentProfile{
+			ID:           teacher.ID,
+			Name:         teacher.Name,
+			BusinessID:   teacher.BusinessID,
+			BusinessName: teacher.Business.Name,
+			Status:       teacher.Status,
+			CreatedOn:    teacher.CreatedOn,
+		})
+	}
+
+	return recent, nil
+}
+
+func (s *teacherService) CheckBusinessAccess(ctx context.Context, businessID, userID uint, role string) error {
+	return checkBusinessAccess(ctx, s.businessRepo, businessID, userID, role)
+}
+
+func (s *teacherService) GetBusinessIDByUser(ctx context.Context, userID uint) (uint, error) {
+	return ownBusinessID(ctx, s.businessRepo, userID)
+}
+
 // Helper methods
+func (s *teacherService) getTeacherAvailability(ctx context.Context, teacherID, availabilityID uint) (*models.TeacherAvailability, error) {
+	slot, err := s.availabilityRepo.GetByID(ctx, availabilityID)
+	if err != nil || slot.TeacherID != teacherID {
+		return nil, fmt.Errorf("availability not found")
+	}
+	return slot, nil
+}
+
+// validateAvailability normalizes the slot times and rejects invalid or overlapping slots
+func (s *teacherService) validateAvailability(ctx context.Context, slot *models.TeacherAvailability) error {
+	if slot.Weekday < 0 || slot.Weekday > 6 {
+		return fmt.Errorf("weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+
+	start, err := normalizeClockTime(slot.StartTime)
+	if err != nil {
+		return fmt.Errorf("invalid start time: %v", err)
+	}
+	end, err := normalizeClockTime(slot.EndTime)
+	if err != nil {
+		return fmt.Errorf("invalid end time: %v", err)
+	}
+	if start >= end {
+		return fmt.Errorf("start time must be before end time")
+	}
+	slot.StartTime = start
+	slot.EndTime = end
+
+	overlaps, err := s.availabilityRepo.HasOverlap(ctx, slot.TeacherID, slot.Weekday, start, end, slot.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check availability overlap: %v", err)
+	}
+	if overlaps {
+		return fmt.Errorf("availability overlaps an existing slot")
+	}
+
+	return nil
+}
+
+// normalizeClockTime parses an "H:MM" or "HH:MM" time and returns it zero-padded
+func normalizeClockTime(value string) (string, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("time must be in HH:MM format")
+	}
+	return parsed.Format("15:04"), nil
+}
+
+// withCounts fills the document and assigned student counts of each teacher, one query per count
+func (s *teacherService) withCounts(ctx context.Context, responses []models.TeacherResponse) []models.TeacherResponse {
+	if len(responses) == 0 {
+		return responses
+	}
+
+	teacherIDs := make([]uint, len(responses))
+	for i, response := range responses {
+		teacherIDs[i] = response.ID
+	}
+
+	// Counts are informational only, so failures leave them at zero
+	if counts, err := s.documentRepo.CountByTeacherIDs(ctx, teacherIDs); err == nil {
+		for i := range responses {
+			responses[i].DocumentCount = counts[responses[i].ID]
+		}
+	}
+	if counts, err := s.assignmentRepo.CountByTeacherIDs(ctx, teacherIDs); err == nil {
+		for i := range responses {
+			responses[i].AssignedStudentCount = counts[responses[i].ID]
+		}
+	}
+	return responses
+}
+
+func toAvailabilityResponse(slot models.TeacherAvailability) models.TeacherAvailabilityResponse {
+	return models.TeacherAvailabilityResponse{
+		ID:        slot.ID,
+		TeacherID: slot.TeacherID,
+		Weekday:   slot.Weekday,
+		StartTime: slot.StartTime,
+		EndTime:   slot.EndTime,
+	}
+}
+
+// versionConflict reports a stale update with the teacher as currently stored
+func (s *teacherService) versionConflict(ctx context.Context, teacherID uint) error {
+	current, err := s.teacherRepo.GetTeacherWithRelations(ctx, teacherID)
+	if err != nil {
+		return repository.ErrVersionConflict
+	}
+	return &VersionConflictError{Current: s.toTeacherResponse(current)}
+}
+
+// setTeacherQualification points a teacher at a taxonomy entry, showing its name as the
+// qualification; a nil entry leaves the teacher as is
+func setTeacherQualification(teacher *models.Teacher, qualification *models.Qualification) {
+	if qualification == nil {
+		return
+	}
+	teacher.QualificationID = &qualification.ID
+	teacher.Qualification = qualification.Name
+}
+
 func (s *teacherService) toTeacherResponse(teacher *models.Teacher) *models.TeacherResponse {
 	response := &models.TeacherResponse{
-		ID:            teacher.ID,
-		Name:          teacher.Name,
-		UserID:        teacher.UserID,
-		BusinessID:    teacher.BusinessID,
-		Salary:        teacher.Salary,
-		Qualification: teacher.Qualification,
-		Experience:    teacher.Experience,
-		Description:   teacher.Description,
-		Status:        teacher.Status,
-		CreatedOn:     teacher.CreatedOn,
-		UpdatedOn:     teacher.UpdatedOn,
+		ID:              teacher.ID,
+		Name:            teacher.Name,
+		UserID:          teacher.UserID,
+		BusinessID:      teacher.BusinessID,
+		Salary:          teacher.Salary,
+		Qualification:   teacher.Qualification,
+		QualificationID: teacher.QualificationID,
+		Experience:      teacher.Experience,
+		ExperienceYears: teacher.ExperienceYears,
+		Description:     teacher.Description,
+		Status:          teacher.Status,
+		Version:         teacher.Version,
+		CreatedOn:       teacher.CreatedOn,
+		UpdatedOn:       teacher.UpdatedOn,
+		Subjects:        []models.SubjectResponse{},
+		PhotoURL:        teacherPhotoURL(teacher),
+
+		LastSalaryChangeAt: teacher.LastSalaryChangeAt,
+	}
+
+	// Add subjects if loaded
+	for _, subject := range teacher.Subjects {
+		response.Subjects = append(response.Subjects, toSubjectResponse(subject))
 	}
 
 	// Add user details if loaded
@@ -469,19 +1419,35 @@ func (s *teacherService) toTeacherResponse(teacher *models.Teacher) *models.Teac
 
 	// Add business details if loaded
 	if teacher.Business.ID != 0 {
-		response.Business = &models.BusinessResponse{
-			ID:        teacher.Business.ID,
-			Name:      teacher.Business.Name,
-			Slug:      teacher.Business.Slug,
-			UserID:    teacher.Business.UserID,
-			OwnerName: teacher.Business.OwnerName,
-			Email:     teacher.Business.Email,
-			Phone:     teacher.Business.Phone,
-			Location:  teacher.Business.Location,
-			Status:    teacher.Business.Status,
-			CreatedOn: teacher.Business.CreatedOn,
-		}
+		response.Business = toTeacherBusinessResponse(teacher.Business)
+	}
+
+	// Keep showing the previous business for a while after a transfer
+	if teacher.PreviousBusiness != nil && teacher.TransferredAt != nil &&
+		time.Since(*teacher.TransferredAt) < models.TeacherTransferGracePeriod {
+		response.PreviousBusiness = toTeacherBusinessResponse(*teacher.PreviousBusiness)
+		response.TransferredAt = teacher.TransferredAt
+	}
+
+	if teacher.DeletedAt.Valid {
+		response.DeletedAt = &teacher.DeletedAt.Time
 	}
 
 	return response
 }
+
+func toTeacherBusinessResponse(business models.Business) *models.BusinessResponse {
+	return &models.BusinessResponse{
+		ID:        business.ID,
+		Name:      business.Name,
+		Slug:      business.Slug,
+		UserID:    business.UserID,
+		OwnerName: business.OwnerName,
+		Email:     business.Email,
+		Phone:     business.Phone,
+		Location:  business.Location,
+		Status:    business.Status,
+		CreatedOn: business.CreatedOn,
+		UpdatedOn: business.UpdatedOn,
+	}
+}
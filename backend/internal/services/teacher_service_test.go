@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/internal/testutil"
+)
+
+// Older clients send the new salary as salary rather than value
+func TestBulkUpdateSalaryAcceptsSalary(t *testing.T) {
+	db := testutil.DB(t)
+	f := testutil.Seed(t, db)
+	ctx := context.Background()
+	teacherRepo := repository.NewTeacherRepository(db)
+	service := NewTeacherService(teacherRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+	asha := f.Teachers["Asha"]
+
+	tests := []struct {
+		name    string
+		body    string
+		want    float64
+		wantErr bool
+	}{
+		{"value", `{"teacher_ids": [%d], "value": 31000}`, 31000, false},
+		{"salary", `{"teacher_ids": [%d], "salary": 32000}`, 32000, false},
+		{"value over salary", `{"teacher_ids": [%d], "value": 33000, "salary": 1}`, 33000, false},
+		{"neither", `{"teacher_ids": [%d]}`, 33000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req models.BulkUpdateSalaryRequest
+			if err := json.Unmarshal([]byte(fmt.Sprintf(tt.body, asha.ID)), &req); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+
+			_, err := service.BulkUpdateSalary(ctx, req, f.Admin.ID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BulkUpdateSalary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			teacher, err := teacherRepo.GetByID(ctx, asha.ID)
+			if err != nil {
+				t.Fatalf("GetByID() error = %v", err)
+			}
+			if teacher.Salary != tt.want {
+				t.Errorf("Salary = %v, want %v", teacher.Salary, tt.want)
+			}
+		})
+	}
+}
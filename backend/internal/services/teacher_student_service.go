@@ -0,0 +1,122 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+)
+
+type TeacherStudentService interface {
+	AssignStudents(ctx context.Context, teacherID uint, studentIDs []uint, actorID uint) (int64, error)
+	UnassignStudents(ctx context.Context, teacherID uint, studentIDs []uint) (int64, error)
+	GetStudents(ctx context.Context, teacherID uint, page, limit int) ([]models.StudentResponse, int64, error)
+}
+
+type teacherStudentService struct {
+	assignmentRepo repository.TeacherStudentRepository
+	teacherRepo    repository.TeacherRepository
+	studentRepo    repository.StudentRepository
+}
+
+func NewTeacherStudentService(assignmentRepo repository.TeacherStudentRepository, teacherRepo repository.TeacherRepository, studentRepo repository.StudentRepository) TeacherStudentService {
+	return &teacherStudentService{
+		assignmentRepo: assignmentRepo,
+		teacherRepo:    teacherRepo,
+		studentRepo:    studentRepo,
+	}
+}
+
+// AssignStudents assigns students of the teacher's business to the teacher and
+// returns the teacher's assigned student count
+func (s *teacherStudentService) AssignStudents(ctx context.Context, teacherID uint, studentIDs []uint, actorID uint) (int64, error) {
+	teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
+	if err != nil {
+		return 0, fmt.Errorf("teacher not found")
+	}
+
+	studentIDs = uniqueIDs(studentIDs)
+	if len(studentIDs) == 0 {
+		return 0, fmt.Errorf("no student IDs provided")
+	}
+
+	students, err := s.studentRepo.GetByIDs(ctx, studentIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get students: %v", err)
+	}
+	if len(students) != len(studentIDs) {
+		return 0, fmt.Errorf("one or more students not found")
+	}
+
+	assignments := make([]models.TeacherStudent, 0, len(students))
+	for _, student := range students {
+		if student.BusinessID != teacher.BusinessID {
+			return 0, fmt.Errorf("student %d does not belong to the teacher's business", student.ID)
+		}
+		assignments = append(assignments, models.TeacherStudent{
+			TeacherID:  teacher.ID,
+			StudentID:  student.ID,
+			BusinessID: teacher.BusinessID,
+			AssignedBy: actorID,
+		})
+	}
+
+	if err := s.assignmentRepo.Assign(ctx, assignments); err != nil {
+		return 0, fmt.Errorf("failed to assign students: %v", err)
+	}
+
+	return s.assignedCount(ctx, teacher.ID)
+}
+
+// UnassignStudents removes students from the teacher and returns how many were removed
+func (s *teacherStudentService) UnassignStudents(ctx context.Context, teacherID uint, studentIDs []uint) (int64, error) {
+	if _, err := s.teacherRepo.GetByID(ctx, teacherID); err != nil {
+		return 0, fmt.Errorf("teacher not found")
+	}
+
+	studentIDs = uniqueIDs(studentIDs)
+	if len(studentIDs) == 0 {
+		return 0, fmt.Errorf("no student IDs provided")
+	}
+
+	removed, err := s.assignmentRepo.Unassign(ctx, teacherID, studentIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to unassign students: %v", err)
+	}
+
+	return removed, nil
+}
+
+func (s *teacherStudentService) GetStudents(ctx context.Context, teacherID uint, page, limit int) ([]models.StudentResponse, int64, error) {
+	if _, err := s.teacherRepo.GetByID(ctx, teacherID); err != nil {
+		return nil, 0, fmt.Errorf("teacher not found")
+	}
+
+	// Set default pagination
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = 10
+	}
+
+	students, total, err := s.assignmentRepo.GetStudents(ctx, teacherID, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get teacher students: %v", err)
+	}
+
+	responses := []models.StudentResponse{}
+	for _, student := range students {
+		responses = append(responses, *toStudentResponse(&student))
+	}
+
+	return responses, total, nil
+}
+
+func (s *teacherStudentService) assignedCount(ctx context.Context, teacherID uint) (int64, error) {
+	counts, err := s.assignmentRepo.CountByTeacherIDs(ctx, []uint{teacherID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count assigned students: %v", err)
+	}
+	return counts[teacherID], nil
+}
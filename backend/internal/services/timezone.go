@@ -0,0 +1,71 @@
+package services
+
+import (
+	"backend/internal/repository"
+	"context"
+	"strings"
+	"time"
+)
+
+// defaultTimezone is the zone of new businesses that don't choose one
+var defaultTimezone = "UTC"
+
+// ConfigureDefaultTimezone sets the zone new businesses get when they don't choose one
+func ConfigureDefaultTimezone(location *time.Location) {
+	defaultTimezone = location.String()
+}
+
+// normalizeTimezone checks that value is an IANA time zone name such as Asia/Kolkata,
+// giving the default zone when it is empty
+func normalizeTimezone(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return defaultTimezone, nil
+	}
+	// "Local" would follow whatever zone the server runs in
+	if strings.EqualFold(value, "Local") {
+		return "", &FieldError{Field: "timezone", Message: "must be an IANA time zone such as Asia/Kolkata"}
+	}
+	location, err := time.LoadLocation(value)
+	if err != nil {
+		return "", &FieldError{Field: "timezone", Message: "must be an IANA time zone such as Asia/Kolkata"}
+	}
+	return location.String(), nil
+}
+
+// timezoneLocation loads a business's zone, falling back to UTC for one the zone
+// database doesn't know
+func timezoneLocation(timezone string) *time.Location {
+	if location, err := time.LoadLocation(timezone); err == nil && timezone != "" {
+		return location
+	}
+	return time.UTC
+}
+
+// dateIn is the calendar date t falls on in location, at midnight UTC like the dates
+// parseDate returns
+func dateIn(t time.Time, location *time.Location) time.Time {
+	local := t.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween counts the calendar days from from to to in location, so a day that DST
+// makes 23 or 25 hours long still counts as one
+func daysBetween(from, to time.Time, location *time.Location) int {
+	return int(dateIn(to, location).Sub(dateIn(from, location)).Hours() / 24)
+}
+
+// todayIn is the current date in the zone
+func todayIn(timezone string) time.Time {
+	return dateIn(time.Now(), timezoneLocation(timezone))
+}
+
+// businessToday is the current date where the business is. A business whose zone can't
+// be read counts as being in UTC.
+func businessToday(ctx context.Context, businessRepo repository.BusinessRepository, businessID uint) time.Time {
+	timezone, err := businessRepo.GetTimezone(ctx, businessID)
+	if err != nil {
+		return currentDate()
+	}
+	return todayIn(timezone)
+}
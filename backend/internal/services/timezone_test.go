@@ -0,0 +1,105 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// New York springs forward on 9 March 2025, at 07:00 UTC, and falls back on 2 November,
+// at 06:00 UTC; its midnights are 05:00 UTC in winter and 04:00 UTC in summer
+func TestDateInAcrossDST(t *testing.T) {
+	newYork := timezoneLocation("America/New_York")
+
+	tests := []struct {
+		name string
+		at   string
+		want string
+	}{
+		{"last minute of the day before spring forward", "2025-03-09T04:59:00Z", "2025-03-08"},
+		{"first minute of spring forward day", "2025-03-09T05:00:00Z", "2025-03-09"},
+		{"after the clocks went forward", "2025-03-09T07:30:00Z", "2025-03-09"},
+		{"last minute of spring forward day", "2025-03-10T03:59:00Z", "2025-03-09"},
+		{"first minute after spring forward day", "2025-03-10T04:00:00Z", "2025-03-10"},
+		{"first minute of fall back day", "2025-11-02T04:00:00Z", "2025-11-02"},
+		{"repeated hour after falling back", "2025-11-02T06:30:00Z", "2025-11-02"},
+		{"last minute of fall back day", "2025-11-03T04:59:00Z", "2025-11-02"},
+		{"first minute after fall back day", "2025-11-03T05:00:00Z", "2025-11-03"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := dateIn(at, newYork)
+			if got.Format("2006-01-02") != tt.want || got.Location() != time.UTC || got.Hour() != 0 {
+				t.Errorf("dateIn(%s) = %v, want %s at midnight UTC", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+// The default month of an attendance summary is the one it is where the business is
+func TestDefaultAttendanceMonthFollowsZone(t *testing.T) {
+	// Still 31 March in New York, already April in UTC and India
+	now := time.Date(2025, 4, 1, 3, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		timezone string
+		start    string
+		end      string
+	}{
+		{"America/New_York", "2025-03-01", "2025-03-31"},
+		{"UTC", "2025-04-01", "2025-04-30"},
+		{"Asia/Kolkata", "2025-04-01", "2025-04-30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			start, end, err := parseAttendanceMonth("", dateIn(now, timezoneLocation(tt.timezone)))
+			if err != nil {
+				t.Fatalf("parseAttendanceMonth: %v", err)
+			}
+			if start.Format("2006-01-02") != tt.start || end.Format("2006-01-02") != tt.end {
+				t.Errorf("month = %s to %s, want %s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"), tt.start, tt.end)
+			}
+		})
+	}
+}
+
+// Days left before a package expires are local calendar days, whatever the length of the
+// days in between
+func TestDaysBetweenAcrossDST(t *testing.T) {
+	newYork := timezoneLocation("America/New_York")
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		location *time.Location
+		want     int
+	}{
+		{"over the 25 hour day", "2025-11-01T12:00:00Z", "2025-11-03T04:30:00Z", newYork, 1},
+		{"just past local midnight after it", "2025-11-01T12:00:00Z", "2025-11-03T05:30:00Z", newYork, 2},
+		{"same instants in UTC", "2025-11-01T12:00:00Z", "2025-11-03T04:30:00Z", time.UTC, 2},
+		{"over the 23 hour day", "2025-03-08T23:00:00Z", "2025-03-10T03:30:00Z", newYork, 1},
+		{"same local day", "2025-03-09T05:00:00Z", "2025-03-10T03:59:00Z", newYork, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daysBetween(at(tt.from), at(tt.to), tt.location); got != tt.want {
+				t.Errorf("daysBetween = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// An unknown zone counts as UTC rather than the server's zone
+	if timezoneLocation("Mars/Olympus") != time.UTC || timezoneLocation("") != time.UTC {
+		t.Error("timezoneLocation falls back to something other than UTC")
+	}
+}
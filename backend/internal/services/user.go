@@ -4,50 +4,54 @@ import (
 	"backend/internal/models"
 	"backend/internal/repository"
 	"backend/pkg/utils"
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService interface {
-	Register(req models.CreateUserRequest) (*models.UserResponse, string, error)
-	Login(req models.LoginRequest) (*models.UserResponse, string, error)
-	GetUsers(filters repository.UserFilters) ([]models.UserResponse, int64, error)
-	GetUserByID(id uint) (*models.UserResponse, error)
-	UpdateUser(id uint, updates map[string]interface{}) (*models.UserResponse, error)
-	DeleteUser(id uint) error
+	Register(ctx context.Context, req models.CreateUserRequest) (*models.UserResponse, string, error)
+	// Login checks the credentials and records the login as coming from clientIP
+	Login(ctx context.Context, req models.LoginRequest, clientIP string) (*models.UserResponse, string, error)
+	RefreshToken(ctx context.Context, userID uint, active models.UserRole) (*models.UserResponse, string, error)
+	GetProfiles(ctx context.Context, userID uint, active models.UserRole) ([]models.Profile, error)
+	SwitchProfile(ctx context.Context, userID uint, role models.UserRole) (*models.UserResponse, string, error)
+	GetUsers(ctx context.Context, filters repository.UserFilters) ([]models.UserResponse, repository.PageInfo, error)
+	GetUserByID(ctx context.Context, id uint) (*models.UserResponse, error)
+	UpdateUser(ctx context.Context, id uint, updates map[string]interface{}) (*models.UserResponse, error)
+	DeleteUser(ctx context.Context, id uint) error
 	ValidateRole(role string) bool
-	GetUsersByRole(role models.UserRole) ([]models.UserResponse, error)
-	GetRoleStatistics() (map[models.UserRole]int64, error)
-	PromoteUser(userID uint, newRole models.UserRole, promotedBy models.UserRole) error
+	GetUsersByRole(ctx context.Context, role models.UserRole) ([]models.UserResponse, error)
+	GetRoleStatistics(ctx context.Context) (map[models.UserRole]int64, error)
+	PromoteUser(ctx context.Context, userID uint, newRole models.UserRole, promotedBy models.UserRole) error
 	HasRolePermission(userRole models.UserRole, requiredRoles []models.UserRole) bool
 	CanAccessRole(userRole models.UserRole, targetRole models.UserRole) bool
-	ChangeUserStatus(userID uint, status int) error
-	EmailExists(email string, excludeUserID ...uint) (bool, error)
-	GetUserStats() (map[string]interface{}, error)
+	ChangeUserStatus(ctx context.Context, userID uint, status models.Status) error
+	EmailExists(ctx context.Context, email string, excludeUserID ...uint) (bool, error)
+	GetUserStats(ctx context.Context) (map[string]interface{}, error)
+	GetRecentUsers(ctx context.Context, limit int) (*models.RecentUsers, error)
 }
 
 type userService struct {
-	repo repository.UserRepository
+	repo         repository.UserRepository
+	businessRepo repository.BusinessRepository
+	teacherRepo  repository.TeacherRepository
+	studentRepo  repository.StudentRepository
 }
 
-func NewUserService(repo repository.UserRepository) UserService {
+func NewUserService(repo repository.UserRepository, businessRepo repository.BusinessRepository, teacherRepo repository.TeacherRepository, studentRepo repository.StudentRepository) UserService {
 	return &userService{
-		repo: repo,
+		repo:         repo,
+		businessRepo: businessRepo,
+		teacherRepo:  teacherRepo,
+		studentRepo:  studentRepo,
 	}
 }
 
-func (s *userService) Register(req models.CreateUserRequest) (*models.UserResponse, string, error) {
-	// Check if user already exists
-	exists, err := s.repo.EmailExists(req.Email)
-	if err != nil {
-		return nil, "", fmt.Errorf("error checking email existence: %w", err)
-	}
-	if exists {
-		return nil, "", errors.New("email already exists")
-	}
-
+func (s *userService) Register(ctx context.Context, req models.CreateUserRequest) (*models.UserResponse, string, error) {
 	// Set default role if not provided
 	if req.Role == "" {
 		req.Role = models.RoleStudent
@@ -58,6 +62,11 @@ func (s *userService) Register(req models.CreateUserRequest) (*models.UserRespon
 		return nil, "", errors.New("invalid role provided")
 	}
 
+	phone, err := normalizePhoneField("phone", req.Phone)
+	if err != nil {
+		return nil, "", err
+	}
+
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -67,18 +76,35 @@ func (s *userService) Register(req models.CreateUserRequest) (*models.UserRespon
 	user := &models.User{
 		Name:     req.Name,
 		Email:    req.Email,
-		Phone:    req.Phone,
+		Phone:    phone,
 		Password: string(hashedPassword),
 		Role:     req.Role,
-		Status:   1, // Active by default
+		Status:   models.StatusActive, // Active by default
+	}
+
+	// The email is checked against users and businesses in the transaction that
+	// creates the account
+	tx := s.repo.BeginTransaction(ctx)
+
+	if err := checkEmailAvailability(tx, s.repo, user.Email, 0); err != nil {
+		tx.Rollback()
+		return nil, "", err
+	}
+
+	if err := s.repo.CreateUserInTransaction(tx, user); err != nil {
+		tx.Rollback()
+		if conflict := conflictError(err); conflict != nil {
+			return nil, "", conflict
+		}
+		return nil, "", fmt.Errorf("error creating user: %w", err)
 	}
 
-	if err := s.repo.Create(user); err != nil {
+	if err := tx.Commit().Error; err != nil {
 		return nil, "", fmt.Errorf("error creating user: %w", err)
 	}
 
-	// Generate token
-	token, err := utils.GenerateToken(user.ID, user.Email, string(user.Role))
+	// Generate token; a new user has no profile, so no business yet
+	token, err := utils.GenerateToken(user.ID, user.Email, string(user.Role), utils.TokenScope{})
 	if err != nil {
 		return nil, "", fmt.Errorf("error generating token: %w", err)
 	}
@@ -87,14 +113,14 @@ func (s *userService) Register(req models.CreateUserRequest) (*models.UserRespon
 	return &userResponse, token, nil
 }
 
-func (s *userService) Login(req models.LoginRequest) (*models.UserResponse, string, error) {
-	user, err := s.repo.GetByEmail(req.Email)
+func (s *userService) Login(ctx context.Context, req models.LoginRequest, clientIP string) (*models.UserResponse, string, error) {
+	user, err := s.repo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, "", errors.New("invalid credentials")
 	}
 
 	// Check if user is active
-	if user.Status != 1 {
+	if !user.Status.IsActive() {
 		return nil, "", errors.New("account is inactive")
 	}
 
@@ -102,7 +128,44 @@ func (s *userService) Login(req models.LoginRequest) (*models.UserResponse, stri
 		return nil, "", errors.New("invalid credentials")
 	}
 
-	token, err := utils.GenerateToken(user.ID, user.Email, string(user.Role))
+	scope := s.tokenScope(ctx, user.ID, user.Role)
+	if req.BusinessSlug != "" {
+		business, err := s.businessRepo.GetBySlug(ctx, req.BusinessSlug)
+		if err != nil || scope.BusinessID == 0 || business.ID != scope.BusinessID {
+			return nil, "", ErrAccessDenied
+		}
+	}
+
+	token, err := utils.GenerateToken(user.ID, user.Email, string(user.Role), scope)
+	if err != nil {
+		return nil, "", fmt.Errorf("error generating token: %w", err)
+	}
+
+	// A failure to record the login time doesn't fail the login
+	if loggedInAt, err := s.repo.RecordLogin(ctx, user.ID, clientIP); err == nil {
+		user.LastLoginAt = &loggedInAt
+		user.LastLoginIP = clientIP
+	}
+
+	userResponse := s.toUserResponse(*user)
+	return &userResponse, token, nil
+}
+
+// RefreshToken issues the caller a new token with their current business and profile,
+// for after a profile is created or a business changes hands since they logged in. The
+// token keeps acting as the active profile while the user still holds it.
+func (s *userService) RefreshToken(ctx context.Context, userID uint, active models.UserRole) (*models.UserResponse, string, error) {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	scope, held := s.profileScope(ctx, user, active)
+	if !held {
+		scope = s.tokenScope(ctx, user.ID, user.Role)
+	}
+
+	token, err := utils.GenerateToken(user.ID, user.Email, string(user.Role), scope)
 	if err != nil {
 		return nil, "", fmt.Errorf("error generating token: %w", err)
 	}
@@ -111,18 +174,120 @@ func (s *userService) Login(req models.LoginRequest) (*models.UserResponse, stri
 	return &userResponse, token, nil
 }
 
-func (s *userService) GetUsers(filters repository.UserFilters) ([]models.UserResponse, int64, error) {
-	// Set default pagination if not provided
-	if filters.Limit <= 0 {
-		filters.Limit = 10
+// GetProfiles lists the profiles the user may act as, marking the one their token
+// is acting as
+func (s *userService) GetProfiles(ctx context.Context, userID uint, active models.UserRole) ([]models.Profile, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	var profiles []models.Profile
+	for _, role := range []models.UserRole{models.RoleAdmin, models.RoleBusiness, models.RoleTeacher, models.RoleStudent} {
+		scope, held := s.profileScope(ctx, user, role)
+		if !held {
+			continue
+		}
+		profiles = append(profiles, models.Profile{
+			Role:       role,
+			Primary:    role == user.Role,
+			Active:     role == active,
+			BusinessID: optionalID(scope.BusinessID),
+			TeacherID:  optionalID(scope.TeacherID),
+			StudentID:  optionalID(scope.StudentID),
+		})
+	}
+	return profiles, nil
+}
+
+// SwitchProfile issues the caller a token acting as another profile they hold, or as
+// their primary role again
+func (s *userService) SwitchProfile(ctx context.Context, userID uint, role models.UserRole) (*models.UserResponse, string, error) {
+	if !role.IsValid() {
+		return nil, "", errors.New("invalid role provided")
+	}
+
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, "", err
 	}
-	if filters.Page <= 0 {
-		filters.Page = 1
+
+	scope, held := s.profileScope(ctx, user, role)
+	if !held {
+		return nil, "", fmt.Errorf("user has no %s profile: %w", role, ErrAccessDenied)
 	}
 
-	users, total, err := s.repo.GetAll(filters)
+	token, err := utils.GenerateToken(user.ID, user.Email, string(user.Role), scope)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error fetching users: %w", err)
+		return nil, "", fmt.Errorf("error generating token: %w", err)
+	}
+
+	userResponse := s.toUserResponse(*user)
+	return &userResponse, token, nil
+}
+
+// activeUser loads a user that may be issued a token
+func (s *userService) activeUser(ctx context.Context, userID uint) (*models.User, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !user.Status.IsActive() {
+		return nil, errors.New("account is inactive")
+	}
+	return user, nil
+}
+
+// profileScope resolves the token scope for acting as role, and whether the user holds
+// that profile. The primary role is always held, even before its profile is created;
+// another role only once the user has its profile.
+func (s *userService) profileScope(ctx context.Context, user *models.User, role models.UserRole) (utils.TokenScope, bool) {
+	scope := s.tokenScope(ctx, user.ID, role)
+	if role == user.Role {
+		return scope, true
+	}
+	if !user.Role.CanHoldProfile(role) || scope == (utils.TokenScope{}) {
+		return utils.TokenScope{}, false
+	}
+	scope.Profile = string(role)
+	return scope, true
+}
+
+// tokenScope resolves the business a user owns or, for teachers and students, belongs
+// to along with their profile, for acting as role. It is empty for admins and when the
+// user has no profile for the role yet.
+func (s *userService) tokenScope(ctx context.Context, userID uint, role models.UserRole) utils.TokenScope {
+	switch role {
+	case models.RoleBusiness:
+		if business, err := s.businessRepo.GetByUserID(ctx, userID); err == nil {
+			return utils.TokenScope{BusinessID: business.ID}
+		}
+	case models.RoleTeacher:
+		if teacher, err := s.teacherRepo.GetByUserID(ctx, userID); err == nil {
+			return utils.TokenScope{BusinessID: teacher.BusinessID, TeacherID: teacher.ID}
+		}
+	case models.RoleStudent:
+		if student, err := s.studentRepo.GetByUserID(ctx, userID); err == nil {
+			return utils.TokenScope{BusinessID: student.BusinessID, StudentID: student.ID}
+		}
+	}
+	return utils.TokenScope{}
+}
+
+// optionalID is nil for a zero ID, so unset scope IDs are null in responses
+func optionalID(id uint) *uint {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+func (s *userService) GetUsers(ctx context.Context, filters repository.UserFilters) ([]models.UserResponse, repository.PageInfo, error) {
+	applyPageDefaults(&filters.Page, &filters.Limit)
+
+	users, total, err := s.repo.GetAll(ctx, filters)
+	if err != nil {
+		return nil, repository.PageInfo{}, fmt.Errorf("error fetching users: %w", err)
 	}
 
 	var userResponses []models.UserResponse
@@ -130,15 +295,15 @@ func (s *userService) GetUsers(filters repository.UserFilters) ([]models.UserRes
 		userResponses = append(userResponses, s.toUserResponse(user))
 	}
 
-	return userResponses, total, nil
+	return userResponses, filters.PageInfo(total, users), nil
 }
 
-func (s *userService) GetUserByID(id uint) (*models.UserResponse, error) {
+func (s *userService) GetUserByID(ctx context.Context, id uint) (*models.UserResponse, error) {
 	if id == 0 {
 		return nil, errors.New("invalid user ID")
 	}
 
-	user, err := s.repo.GetByID(id)
+	user, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("user not found")
 	}
@@ -147,12 +312,12 @@ func (s *userService) GetUserByID(id uint) (*models.UserResponse, error) {
 	return &userResponse, nil
 }
 
-func (s *userService) UpdateUser(id uint, updates map[string]interface{}) (*models.UserResponse, error) {
+func (s *userService) UpdateUser(ctx context.Context, id uint, updates map[string]interface{}) (*models.UserResponse, error) {
 	if id == 0 {
 		return nil, errors.New("invalid user ID")
 	}
 
-	user, err := s.repo.GetByID(id)
+	user, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("user not found")
 	}
@@ -167,19 +332,18 @@ func (s *userService) UpdateUser(id uint, updates map[string]interface{}) (*mode
 	}
 
 	if phone, ok := updates["phone"].(string); ok {
-		user.Phone = phone
+		normalized, err := normalizePhoneField("phone", phone)
+		if err != nil {
+			return nil, err
+		}
+		user.Phone = normalized
 		hasUpdates = true
 	}
 
+	// A new email is checked when the user is saved
+	emailChanged := false
 	if email, ok := updates["email"].(string); ok && email != "" {
-		// Check if email already exists for another user
-		exists, err := s.repo.EmailExists(email, user.ID)
-		if err != nil {
-			return nil, fmt.Errorf("error checking email existence: %w", err)
-		}
-		if exists {
-			return nil, errors.New("email already exists")
-		}
+		emailChanged = email != user.Email
 		user.Email = email
 		hasUpdates = true
 	}
@@ -193,34 +357,20 @@ func (s *userService) UpdateUser(id uint, updates map[string]interface{}) (*mode
 		hasUpdates = true
 	}
 
-	if status, ok := updates["status"].(int); ok {
-		if status < 0 || status > 1 {
-			return nil, errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
-		}
+	if status, ok, err := updatedStatus(updates); err != nil {
+		return nil, err
+	} else if ok {
 		user.Status = status
 		hasUpdates = true
 	}
 
-	// Handle float64 status (from JSON)
-	if status, ok := updates["status"].(float64); ok {
-		statusInt := int(status)
-		if statusInt < 0 || statusInt > 1 {
-			return nil, errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
-		}
-		user.Status = statusInt
-		hasUpdates = true
-	}
-
 	// Hash new password if provided
 	if password, ok := updates["password"].(string); ok && password != "" {
-		if len(password) < 6 {
-			return nil, errors.New("password must be at least 6 characters")
-		}
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		hashedPassword, err := hashPassword(password)
 		if err != nil {
-			return nil, fmt.Errorf("error hashing password: %w", err)
+			return nil, err
 		}
-		user.Password = string(hashedPassword)
+		user.Password = hashedPassword
 		hasUpdates = true
 	}
 
@@ -228,7 +378,30 @@ func (s *userService) UpdateUser(id uint, updates map[string]interface{}) (*mode
 		return nil, errors.New("no valid updates provided")
 	}
 
-	if err := s.repo.Update(user); err != nil {
+	if user.Status.IsActive() {
+		if err := s.checkReactivation(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	tx := s.repo.BeginTransaction(ctx)
+
+	if emailChanged {
+		if err := checkEmailAvailability(tx, s.repo, user.Email, user.ID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateUserInTransaction(tx, user); err != nil {
+		tx.Rollback()
+		if conflict := conflictError(err); conflict != nil {
+			return nil, conflict
+		}
+		return nil, fmt.Errorf("error updating user: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("error updating user: %w", err)
 	}
 
@@ -236,17 +409,17 @@ func (s *userService) UpdateUser(id uint, updates map[string]interface{}) (*mode
 	return &userResponse, nil
 }
 
-func (s *userService) DeleteUser(id uint) error {
+func (s *userService) DeleteUser(ctx context.Context, id uint) error {
 	if id == 0 {
 		return errors.New("invalid user ID")
 	}
 
-	_, err := s.repo.GetByID(id)
+	_, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return errors.New("user not found")
 	}
 
-	if err := s.repo.Delete(id); err != nil {
+	if err := s.repo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("error deleting user: %w", err)
 	}
 
@@ -258,12 +431,12 @@ func (s *userService) ValidateRole(role string) bool {
 	return userRole.IsValid()
 }
 
-func (s *userService) GetUsersByRole(role models.UserRole) ([]models.UserResponse, error) {
+func (s *userService) GetUsersByRole(ctx context.Context, role models.UserRole) ([]models.UserResponse, error) {
 	if !role.IsValid() {
 		return nil, errors.New("invalid role provided")
 	}
 
-	users, err := s.repo.GetByRole(role)
+	users, err := s.repo.GetByRole(ctx, role)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching users by role: %w", err)
 	}
@@ -276,7 +449,7 @@ func (s *userService) GetUsersByRole(role models.UserRole) ([]models.UserRespons
 	return userResponses, nil
 }
 
-func (s *userService) GetRoleStatistics() (map[models.UserRole]int64, error) {
+func (s *userService) GetRoleStatistics(ctx context.Context) (map[models.UserRole]int64, error) {
 	stats := make(map[models.UserRole]int64)
 
 	roles := []models.UserRole{
@@ -290,7 +463,7 @@ func (s *userService) GetRoleStatistics() (map[models.UserRole]int64, error) {
 		filters := repository.UserFilters{
 			Role: string(role),
 		}
-		_, count, err := s.repo.GetAll(filters)
+		_, count, err := s.repo.GetAll(ctx, filters)
 		if err != nil {
 			return nil, fmt.Errorf("error getting statistics for role %s: %w", role, err)
 		}
@@ -300,7 +473,7 @@ func (s *userService) GetRoleStatistics() (map[models.UserRole]int64, error) {
 	return stats, nil
 }
 
-func (s *userService) PromoteUser(userID uint, newRole models.UserRole, promotedBy models.UserRole) error {
+func (s *userService) PromoteUser(ctx context.Context, userID uint, newRole models.UserRole, promotedBy models.UserRole) error {
 	if userID == 0 {
 		return errors.New("invalid user ID")
 	}
@@ -314,7 +487,7 @@ func (s *userService) PromoteUser(userID uint, newRole models.UserRole, promoted
 		return errors.New("insufficient permissions to promote to this role")
 	}
 
-	user, err := s.repo.GetByID(userID)
+	user, err := s.repo.GetByID(ctx, userID)
 	if err != nil {
 		return errors.New("user not found")
 	}
@@ -329,7 +502,7 @@ func (s *userService) PromoteUser(userID uint, newRole models.UserRole, promoted
 		return errors.New("user already has this role")
 	}
 
-	if err := s.repo.UpdateUserRole(userID, newRole); err != nil {
+	if err := s.repo.UpdateUserRole(ctx, userID, newRole); err != nil {
 		return fmt.Errorf("error promoting user: %w", err)
 	}
 
@@ -365,37 +538,69 @@ func (s *userService) CanAccessRole(userRole models.UserRole, targetRole models.
 	return userLevel >= targetLevel
 }
 
-func (s *userService) ChangeUserStatus(userID uint, status int) error {
+// hashPassword checks a new password and returns its bcrypt hash. Passwords set by an
+// admin or a user, for any role, go through here so they are stored the same way.
+func hashPassword(password string) (string, error) {
+	if len(password) < 6 {
+		return "", errors.New("password must be at least 6 characters")
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("error hashing password: %w", err)
+	}
+	return string(hashedPassword), nil
+}
+
+// checkReactivation refuses to activate a user whose teacher or student profile is
+// soft-deleted; restoring the profile reactivates the user
+func (s *userService) checkReactivation(ctx context.Context, userID uint) error {
+	deleted, err := s.repo.HasDeletedProfile(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("error checking user profile: %w", err)
+	}
+	if deleted {
+		return errors.New("user's profile is deleted; restore the profile to reactivate the user")
+	}
+	return nil
+}
+
+func (s *userService) ChangeUserStatus(ctx context.Context, userID uint, status models.Status) error {
 	if userID == 0 {
 		return errors.New("invalid user ID")
 	}
 
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
 	}
 
-	_, err := s.repo.GetByID(userID)
+	_, err := s.repo.GetByID(ctx, userID)
 	if err != nil {
 		return errors.New("user not found")
 	}
 
-	if err := s.repo.UpdateUserStatus(userID, status); err != nil {
+	if status.IsActive() {
+		if err := s.checkReactivation(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.UpdateUserStatus(ctx, userID, status); err != nil {
 		return fmt.Errorf("error updating user status: %w", err)
 	}
 
 	return nil
 }
 
-func (s *userService) EmailExists(email string, excludeUserID ...uint) (bool, error) {
+func (s *userService) EmailExists(ctx context.Context, email string, excludeUserID ...uint) (bool, error) {
 	if email == "" {
 		return false, errors.New("email cannot be empty")
 	}
 
-	return s.repo.EmailExists(email, excludeUserID...)
+	return s.repo.EmailExists(ctx, email, excludeUserID...)
 }
 
-func (s *userService) GetUserStats() (map[string]interface{}, error) {
-	stats, err := s.repo.GetUserStats()
+func (s *userService) GetUserStats(ctx context.Context) (map[string]interface{}, error) {
+	stats, err := s.repo.GetUserStats(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting user statistics: %w", err)
 	}
@@ -404,8 +609,8 @@ func (s *userService) GetUserStats() (map[string]interface{}, error) {
 }
 
 // Helper methods for user management
-func (s *userService) GetActiveUsers() ([]models.UserResponse, error) {
-	users, err := s.repo.GetUsersByStatus(1)
+func (s *userService) GetActiveUsers(ctx context.Context) ([]models.UserResponse, error) {
+	users, err := s.repo.GetUsersByStatus(ctx, 1)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching active users: %w", err)
 	}
@@ -418,8 +623,8 @@ func (s *userService) GetActiveUsers() ([]models.UserResponse, error) {
 	return userResponses, nil
 }
 
-func (s *userService) GetInactiveUsers() ([]models.UserResponse, error) {
-	users, err := s.repo.GetUsersByStatus(0)
+func (s *userService) GetInactiveUsers(ctx context.Context) ([]models.UserResponse, error) {
+	users, err := s.repo.GetUsersByStatus(ctx, 0)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching inactive users: %w", err)
 	}
@@ -432,17 +637,17 @@ func (s *userService) GetInactiveUsers() ([]models.UserResponse, error) {
 	return userResponses, nil
 }
 
-func (s *userService) BulkUpdateUserStatus(userIDs []uint, status int) error {
+func (s *userService) BulkUpdateUserStatus(ctx context.Context, userIDs []uint, status models.Status) error {
 	if len(userIDs) == 0 {
 		return errors.New("no user IDs provided")
 	}
 
-	if status < 0 || status > 1 {
+	if !status.IsValid() {
 		return errors.New("invalid status value. Must be 0 (inactive) or 1 (active)")
 	}
 
 	for _, userID := range userIDs {
-		if err := s.ChangeUserStatus(userID, status); err != nil {
+		if err := s.ChangeUserStatus(ctx, userID, status); err != nil {
 			return fmt.Errorf("error updating status for user ID %d: %w", userID, err)
 		}
 	}
@@ -451,8 +656,8 @@ func (s *userService) BulkUpdateUserStatus(userIDs []uint, status int) error {
 }
 
 // Helper method to check if a user can perform actions on another user
-func (s *userService) CanManageUser(managerRole models.UserRole, targetUserID uint) (bool, error) {
-	targetUser, err := s.repo.GetByID(targetUserID)
+func (s *userService) CanManageUser(ctx context.Context, managerRole models.UserRole, targetUserID uint) (bool, error) {
+	targetUser, err := s.repo.GetByID(ctx, targetUserID)
 	if err != nil {
 		return false, errors.New("target user not found")
 	}
@@ -460,6 +665,30 @@ func (s *userService) CanManageUser(managerRole models.UserRole, targetUserID ui
 	return s.CanAccessRole(managerRole, targetUser.Role), nil
 }
 
+// GetRecentUsers returns the latest user accounts and how many were registered in the
+// last 7 and 30 days
+func (s *userService) GetRecentUsers(ctx context.Context, limit int) (*models.RecentUsers, error) {
+	users, err := s.repo.GetRecentUsers(ctx, recentLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent users: %w", err)
+	}
+
+	additions, err := s.repo.CountAdditions(ctx, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recent users: %w", err)
+	}
+
+	recent := &models.RecentUsers{
+		Latest:    make([]models.UserResponse, 0, len(users)),
+		Additions: *additions,
+	}
+	for _, user := range users {
+		recent.Latest = append(recent.Latest, s.toUserResponse(user))
+	}
+
+	return recent, nil
+}
+
 func (s *userService) toUserResponse(user models.User) models.UserResponse {
 	return models.UserResponse{
 		ID:        user.ID,
@@ -469,6 +698,9 @@ func (s *userService) toUserResponse(user models.User) models.UserResponse {
 		Role:      user.Role,
 		Status:    user.Status,
 		CreatedOn: user.CreatedOn,
+
+		LastLoginAt: user.LastLoginAt,
+		LastLoginIP: user.LastLoginIP,
 	}
 }
 
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/logger"
+	"backend/pkg/payments"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrUnknownProvider is returned for webhooks of a payment gateway that isn't configured
+var ErrUnknownProvider = errors.New("unknown payment provider")
+
+type WebhookService interface {
+	// HandlePaymentWebhook verifies, records and processes a payment gateway's webhook call.
+	// Events already handled, and events we don't act on, are acknowledged without effect.
+	// An error other than a bad signature or provider means the call should be retried.
+	HandlePaymentWebhook(ctx context.Context, provider string, header http.Header, body []byte) (*models.WebhookResult, error)
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+	feeService  FeeService
+	gateways    map[string]payments.Gateway
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository, feeService FeeService, gateways map[string]payments.Gateway) WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		feeService:  feeService,
+		gateways:    gateways,
+	}
+}
+
+func (s *webhookService) HandlePaymentWebhook(ctx context.Context, provider string, header http.Header, body []byte) (*models.WebhookResult, error) {
+	gateway, ok := s.gateways[provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	event, err := gateway.ParseWebhook(header, body)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.WebhookEvent{
+		Provider:  provider,
+		EventID:   event.ID,
+		EventType: event.Type,
+		Payload:   string(body),
+		Status:    models.WebhookStatusReceived,
+	}
+	created, err := s.webhookRepo.Record(ctx, record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record webhook event: %w", err)
+	}
+	// Retries of an event that was still being processed, or failed on our side, go
+	// through again; the payment itself is only ever credited once
+	if !created && record.Status != models.WebhookStatusReceived {
+		return &models.WebhookResult{Status: record.Status, Duplicate: true, FeePaymentID: record.FeePaymentID}, nil
+	}
+
+	outcome := s.process(ctx, provider, event)
+	if outcome.Status != models.WebhookStatusReceived {
+		now := time.Now()
+		outcome.ProcessedAt = &now
+	}
+	if err := s.webhookRepo.UpdateStatus(ctx, record.ID, outcome); err != nil {
+		return nil, fmt.Errorf("failed to update webhook event: %w", err)
+	}
+	if outcome.Status == models.WebhookStatusReceived {
+		// Leave it to the gateway's retry
+		return nil, fmt.Errorf("failed to process webhook event: %s", outcome.Error)
+	}
+	return &models.WebhookResult{Status: outcome.Status, FeePaymentID: outcome.FeePaymentID}, nil
+}
+
+// process acts on an event, returning its final status, or received when it failed in a
+// way a retry may fix
+func (s *webhookService) process(ctx context.Context, provider string, event payments.Event) models.WebhookEvent {
+	if event.Kind != payments.KindPaymentSucceeded || event.Payment == nil {
+		return models.WebhookEvent{Status: models.WebhookStatusIgnored}
+	}
+
+	payment, err := toGatewayPayment(provider, *event.Payment)
+	if err != nil {
+		return models.WebhookEvent{Status: models.WebhookStatusFailed, Error: err.Error()}
+	}
+
+	recorded, _, err := s.feeService.RecordGatewayPayment(ctx, payment)
+	if err != nil {
+		if errors.Is(err, ErrPaymentRejected) {
+			logger.FromContext(ctx).Warn("Payment webhook rejected", "provider", provider, "event_id", event.ID, "error", err)
+			return models.WebhookEvent{Status: models.WebhookStatusFailed, Error: err.Error()}
+		}
+		return models.WebhookEvent{Status: models.WebhookStatusReceived, Error: err.Error()}
+	}
+	return models.WebhookEvent{Status: models.WebhookStatusProcessed, FeePaymentID: &recorded.ID}
+}
+
+// toGatewayPayment reads the student and fee plan the payment's metadata refers to
+func toGatewayPayment(provider string, payment payments.Payment) (models.GatewayPayment, error) {
+	studentID, err := strconv.ParseUint(payment.StudentID, 10, 32)
+	if err != nil || studentID == 0 {
+		return models.GatewayPayment{}, fmt.Errorf("payment %s has no valid student_id in its metadata", payment.ID)
+	}
+
+	gatewayPayment := models.GatewayPayment{
+		Provider:  provider,
+		PaymentID: payment.ID,
+		StudentID: uint(studentID),
+		Amount:    payment.Amount,
+		Currency:  payment.Currency,
+		Mode:      payment.Method,
+		PaidOn:    payment.PaidAt,
+	}
+	if gatewayPayment.PaidOn.IsZero() {
+		gatewayPayment.PaidOn = time.Now()
+	}
+	if payment.FeePlanID != "" {
+		feePlanID, err := strconv.ParseUint(payment.FeePlanID, 10, 32)
+		if err != nil || feePlanID == 0 {
+			return models.GatewayPayment{}, fmt.Errorf("payment %s has an invalid fee_plan_id in its metadata", payment.ID)
+		}
+		planID := uint(feePlanID)
+		gatewayPayment.FeePlanID = &planID
+	}
+	return gatewayPayment, nil
+}
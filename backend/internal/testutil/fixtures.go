@@ -0,0 +1,189 @@
+package testutil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Fixtures are the rows Seed creates, looked up by name in tests:
+//
+//   - packages Basic and Premium, active, and Legacy, inactive
+//   - business Sunrise Academy on Basic, whose package expires in 10 days, and Moonlight
+//     Tutors, inactive and without a package
+//   - at Sunrise, teachers Asha and Bilal, active, and Chitra, inactive; at Moonlight Dev
+//   - at Sunrise, students Aarav (grade 8, male), Bina (grade 8, female), Chen (grade 9,
+//     male, inactive) and Diya (no grade, female), and Esha (grade 9), soft-deleted; at
+//     Moonlight Farah (grade 10, female)
+type Fixtures struct {
+	Admin      models.User
+	Packages   map[string]models.Package
+	Businesses map[string]models.Business
+	Teachers   map[string]models.Teacher
+	Students   map[string]models.Student
+}
+
+// Seed creates the fixtures in db, which is normally a transaction from DB
+func Seed(t testing.TB, db *gorm.DB) *Fixtures {
+	t.Helper()
+
+	f := &Fixtures{
+		Admin:      CreateUser(t, db, "Admin", models.RoleAdmin),
+		Packages:   map[string]models.Package{},
+		Businesses: map[string]models.Business{},
+		Teachers:   map[string]models.Teacher{},
+		Students:   map[string]models.Student{},
+	}
+
+	for _, p := range []struct {
+		name   string
+		price  float64
+		days   int
+		status models.Status
+	}{
+		{"Basic", 1000, 30, models.StatusActive},
+		{"Premium", 5000, 365, models.StatusActive},
+		{"Legacy", 500, 30, models.StatusInactive},
+	} {
+		pkg := models.Package{Name: p.name, Price: p.price, ValidationPeriod: p.days, Status: p.status}
+		create(t, db, &pkg, p.status)
+		f.Packages[p.name] = pkg
+	}
+
+	basic := f.Packages["Basic"]
+	sunrise := CreateBusiness(t, db, "Sunrise Academy", models.StatusActive, &basic)
+	moonlight := CreateBusiness(t, db, "Moonlight Tutors", models.StatusInactive, nil)
+	f.Businesses[sunrise.Name] = sunrise
+	f.Businesses[moonlight.Name] = moonlight
+
+	for _, teacher := range []struct {
+		name     string
+		business models.Business
+		salary   float64
+		status   models.Status
+	}{
+		{"Asha", sunrise, 30000, models.StatusActive},
+		{"Bilal", sunrise, 45000, models.StatusActive},
+		{"Chitra", sunrise, 20000, models.StatusInactive},
+		{"Dev", moonlight, 25000, models.StatusActive},
+	} {
+		f.Teachers[teacher.name] = CreateTeacher(t, db, teacher.name, teacher.business.ID, teacher.salary, teacher.status)
+	}
+
+	for _, student := range []struct {
+		name     string
+		business models.Business
+		grade    string
+		gender   string
+		status   models.Status
+	}{
+		{"Aarav", sunrise, "8", "male", models.StatusActive},
+		{"Bina", sunrise, "8", "female", models.StatusActive},
+		{"Chen", sunrise, "9", "male", models.StatusInactive},
+		{"Diya", sunrise, "", "female", models.StatusActive},
+		{"Esha", sunrise, "9", "female", models.StatusActive},
+		{"Farah", moonlight, "10", "female", models.StatusActive},
+	} {
+		f.Students[student.name] = CreateStudent(t, db, student.name, student.business.ID, student.grade, student.gender, student.status)
+	}
+	if err := db.Delete(&models.Student{}, f.Students["Esha"].ID).Error; err != nil {
+		t.Fatalf("failed to soft-delete student Esha: %v", err)
+	}
+
+	return f
+}
+
+// CreateUser creates an active user with an email made from name
+func CreateUser(t testing.TB, db *gorm.DB, name string, role models.UserRole) models.User {
+	t.Helper()
+
+	user := models.User{
+		Name:     name,
+		Email:    fmt.Sprintf("%s.%s@example.com", strings.ToLower(strings.ReplaceAll(name, " ", ".")), role),
+		Password: "not-a-real-hash",
+		Role:     role,
+		Status:   models.StatusActive,
+	}
+	create(t, db, &user, models.StatusActive)
+	return user
+}
+
+// CreateBusiness creates a business and its owner. With a package, the package is
+// assigned as of now and expires in 10 days.
+func CreateBusiness(t testing.TB, db *gorm.DB, name string, status models.Status, pkg *models.Package) models.Business {
+	t.Helper()
+
+	owner := CreateUser(t, db, name+" Owner", models.RoleBusiness)
+	slug := strings.ToLower(strings.ReplaceAll(name, " ", "-"))
+	business := models.Business{
+		Name:      name,
+		Slug:      slug,
+		UserID:    owner.ID,
+		OwnerName: owner.Name,
+		Email:     slug + "@example.com",
+		Status:    status,
+		User:      owner,
+	}
+	if pkg != nil {
+		expires := time.Now().AddDate(0, 0, 10)
+		business.PackageID = &pkg.ID
+		business.PackageExpiresAt = &expires
+		business.PackagePrice = &pkg.Price
+		business.PackageValidationPeriod = &pkg.ValidationPeriod
+	}
+	create(t, db, &business, status)
+	return business
+}
+
+// CreateTeacher creates a teacher and the teacher's user at a business
+func CreateTeacher(t testing.TB, db *gorm.DB, name string, businessID uint, salary float64, status models.Status) models.Teacher {
+	t.Helper()
+
+	user := CreateUser(t, db, name, models.RoleTeacher)
+	teacher := models.Teacher{
+		Name:       name,
+		UserID:     user.ID,
+		BusinessID: businessID,
+		Salary:     salary,
+		Status:     status,
+	}
+	create(t, db, &teacher, status)
+	return teacher
+}
+
+// CreateStudent creates a student and the student's user at a business
+func CreateStudent(t testing.TB, db *gorm.DB, name string, businessID uint, grade, gender string, status models.Status) models.Student {
+	t.Helper()
+
+	user := CreateUser(t, db, name, models.RoleStudent)
+	student := models.Student{
+		Name:       name,
+		UserID:     user.ID,
+		BusinessID: businessID,
+		Grade:      grade,
+		Gender:     gender,
+		Status:     status,
+	}
+	create(t, db, &student, status)
+	return student
+}
+
+// create inserts value. Status columns default to active, so GORM leaves the zero
+// inactive status out of the insert; it is written separately.
+func create(t testing.TB, db *gorm.DB, value interface{}, status models.Status) {
+	t.Helper()
+
+	if err := db.Omit("User", "Package", "Business").Create(value).Error; err != nil {
+		t.Fatalf("failed to create %T: %v", value, err)
+	}
+	if status == models.StatusInactive {
+		if err := db.Model(value).Update("status", status).Error; err != nil {
+			t.Fatalf("failed to deactivate %T: %v", value, err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Cache is the shared interface for caching hot reads. Values expire after the TTL the
+// cache was created with.
+type Cache interface {
+	// Get returns the value of key, reporting whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte) error
+	Delete(ctx context.Context, keys ...string) error
+	// DeletePrefix deletes every key starting with prefix
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// Config picks the cache the server uses
+type Config struct {
+	Enabled  bool          // false turns caching off
+	RedisURL string        // a Redis server to cache in; empty for the in-memory cache
+	TTL      time.Duration // how long values live
+	Size     int           // entries the in-memory cache holds
+}
+
+// New returns a Redis cache when RedisURL is set and an in-memory LRU cache of Size
+// entries otherwise, or a cache that stores nothing when caching is off
+func New(config Config) Cache {
+	if !config.Enabled {
+		slog.Info("Caching disabled (CACHE_ENABLED=false)")
+		return Noop{}
+	}
+
+	if config.RedisURL != "" {
+		redis, err := NewRedis(config.RedisURL, config.TTL)
+		if err != nil {
+			slog.Error("Invalid REDIS_URL, using the in-memory cache", "error", err)
+		} else {
+			slog.Info("Using the Redis cache", "ttl", config.TTL.String())
+			return redis
+		}
+	}
+
+	return NewLRU(config.Size, config.TTL)
+}
+
+// Noop is a cache that stores nothing, so every read goes to the database
+type Noop struct{}
+
+func (Noop) Get(ctx context.Context, key string) ([]byte, bool, error) { return nil, false, nil }
+func (Noop) Set(ctx context.Context, key string, value []byte) error   { return nil }
+func (Noop) Delete(ctx context.Context, keys ...string) error          { return nil }
+func (Noop) DeletePrefix(ctx context.Context, prefix string) error     { return nil }
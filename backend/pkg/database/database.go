@@ -4,9 +4,14 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
-	"os"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"backend/internal/models"
+	"backend/pkg/utils"
 
 	_ "github.com/lib/pq"
 	"gorm.io/driver/postgres"
@@ -17,38 +22,125 @@ import (
 var DB *gorm.DB
 var SqlDB *sql.DB
 
-func Connect() {
+// Config is where the database is and how large its connection pools are
+type Config struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+	Pool     PoolConfig
+}
+
+func Connect(config Config) {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
+		config.Host,
+		config.User,
+		config.Password,
+		config.Name,
+		config.Port,
 	)
+	pool := config.Pool
+
+	// Postgres may still be starting when the app boots, so retry before giving up
+	err := withRetry(pool.ConnectRetries, func() error {
+		var err error
+
+		// Connect with database/sql for raw queries if needed
+		SqlDB, err = sql.Open("postgres", dsn)
+		if err != nil {
+			return fmt.Errorf("failed to open database/sql connection: %v", err)
+		}
 
-	// Connect with database/sql for raw queries if needed
-	var err error
-	SqlDB, err = sql.Open("postgres", dsn)
+		// Test connection
+		if err := SqlDB.Ping(); err != nil {
+			SqlDB.Close()
+			return fmt.Errorf("failed to ping database: %v", err)
+		}
+		return nil
+	})
 	if err != nil {
 		log.Fatal("Failed to connect to database with database/sql:", err)
 	}
-
-	// Test connection
-	if err := SqlDB.Ping(); err != nil {
-		log.Fatal("Failed to ping database:", err)
-	}
+	pool.apply(SqlDB)
 
 	// Connect with GORM using separate connection (not shared)
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	}
 
-	DB, err = gorm.Open(postgres.Open(dsn), gormConfig)
+	err = withRetry(pool.ConnectRetries, func() error {
+		var err error
+		DB, err = gorm.Open(postgres.Open(dsn), gormConfig)
+		return err
+	})
 	if err != nil {
 		log.Fatal("Failed to connect to database with GORM:", err)
 	}
 
-	log.Println("Database connected successfully")
+	gormSQL, err := DB.DB()
+	if err != nil {
+		log.Fatal("Failed to get GORM connection pool:", err)
+	}
+	pool.apply(gormSQL)
+
+	log.Printf("Database connected successfully (max open %d, max idle %d, max lifetime %s)",
+		pool.MaxOpenConns, pool.MaxIdleConns, pool.ConnMaxLifetime)
+}
+
+// PoolConfig sizes the connection pools and sets how often connecting is retried at startup
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnectRetries  int
+}
+
+func (p PoolConfig) apply(db *sql.DB) {
+	db.SetMaxOpenConns(p.MaxOpenConns)
+	db.SetMaxIdleConns(p.MaxIdleConns)
+	db.SetConnMaxLifetime(p.ConnMaxLifetime)
+}
+
+// withRetry runs connect until it succeeds or has been retried the given number of
+// times, waiting 1s, 2s, 4s... (at most 30s) in between
+func withRetry(retries int, connect func() error) error {
+	delay := time.Second
+	for attempt := 0; ; attempt++ {
+		err := connect()
+		if err == nil || attempt >= retries {
+			return err
+		}
+
+		log.Printf("Database not available (attempt %d of %d): %v. Retrying in %s", attempt+1, retries+1, err, delay)
+		time.Sleep(delay)
+		if delay *= 2; delay > 30*time.Second {
+			delay = 30 * time.Second
+		}
+	}
+}
+
+// PoolStats reports the GORM connection pool's usage
+func PoolStats() (map[string]interface{}, error) {
+	if DB == nil {
+		return nil, fmt.Errorf("GORM DB is nil")
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := sqlDB.Stats()
+	return map[string]interface{}{
+		"max_open_connections": stats.MaxOpenConnections,
+		"open_connections":     stats.OpenConnections,
+		"in_use":               stats.InUse,
+		"idle":                 stats.Idle,
+		"wait_count":           stats.WaitCount,
+		"wait_duration_ms":     stats.WaitDuration.Milliseconds(),
+		"max_idle_closed":      stats.MaxIdleClosed,
+		"max_lifetime_closed":  stats.MaxLifetimeClosed,
+	}, nil
 }
 
 func Close() {
@@ -79,6 +171,18 @@ func Migrate() {
 		if err := addConstraintsAndIndexes(); err != nil {
 			log.Printf("Warning: Failed to add some constraints/indexes: %v", err)
 		}
+		if err := backfillExperienceYears(); err != nil {
+			log.Printf("Warning: Failed to backfill teacher experience years: %v", err)
+		}
+		if err := backfillStudentGuardians(); err != nil {
+			log.Printf("Warning: Failed to backfill student guardians: %v", err)
+		}
+		if err := normalizePhones(); err != nil {
+			log.Printf("Warning: Failed to normalize phones: %v", err)
+		}
+		if err := dropBusinessPasswords(); err != nil {
+			log.Printf("Warning: Failed to drop business passwords: %v", err)
+		}
 		log.Println("Database migration completed successfully")
 		return
 	}
@@ -91,6 +195,45 @@ func Migrate() {
 		&models.Business{},
 		&models.Student{},
 		&models.Teacher{},
+		&models.Subject{},
+		&models.Qualification{},
+		&models.Batch{},
+		&models.TeacherSalaryHistory{},
+		&models.TeacherAvailability{},
+		&models.TeacherDocument{},
+		&models.TeacherAssignmentHistory{},
+		&models.TeacherAttendance{},
+		&models.PayrollAdjustment{},
+		&models.PayrollRun{},
+		&models.PayrollLine{},
+		&models.TeacherStudent{},
+		&models.StudentAttendance{},
+		&models.StudentGuardian{},
+		&models.StudentField{},
+		&models.StudentGrade{},
+		&models.StudentHistory{},
+		&models.FeePlan{},
+		&models.FeePayment{},
+		&models.Exam{},
+		&models.ExamResult{},
+		&models.Announcement{},
+		&models.AnnouncementRead{},
+		&models.GuardianLink{},
+		&models.MaintenanceState{},
+		&models.PasswordToken{},
+		&models.SMSMessage{},
+		&models.ReportSubscription{},
+		&models.ReportSchedule{},
+		&models.Notification{},
+		&models.WebhookEvent{},
+		&models.StatusHistory{},
+		&models.BusinessSlugHistory{},
+		&models.BusinessPackageHistory{},
+		&models.SampleRecord{},
+		&models.AuditLog{},
+		&models.StudentCodeCounter{},
+		&models.PendingSignup{},
+		&models.BusinessSnapshot{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
@@ -101,6 +244,31 @@ func Migrate() {
 		log.Printf("Warning: Failed to add some constraints/indexes: %v", err)
 	}
 
+	// Fill structured experience from the legacy free-text field
+	if err := backfillExperienceYears(); err != nil {
+		log.Printf("Warning: Failed to backfill teacher experience years: %v", err)
+	}
+
+	// Move single guardians from the legacy student columns into their own table
+	if err := backfillStudentGuardians(); err != nil {
+		log.Printf("Warning: Failed to backfill student guardians: %v", err)
+	}
+
+	// Strip formatting from phones saved before they were normalized on write
+	if err := normalizePhones(); err != nil {
+		log.Printf("Warning: Failed to normalize phones: %v", err)
+	}
+
+	// Owners sign in with their user account; businesses no longer keep a copy of the hash
+	if err := dropBusinessPasswords(); err != nil {
+		log.Printf("Warning: Failed to drop business passwords: %v", err)
+	}
+
+	// Record the terms of package assignments made before they were kept on the business
+	if err := backfillPackageTerms(); err != nil {
+		log.Printf("Warning: Failed to backfill package terms: %v", err)
+	}
+
 	log.Println("Database migration completed successfully")
 }
 
@@ -121,7 +289,7 @@ func needsMigration() bool {
 	}
 
 	// Check if all expected columns exist
-	expectedColumns := []string{"id", "name", "email", "phone", "password", "role", "status", "created_on", "updated_on"}
+	expectedColumns := []string{"id", "name", "email", "phone", "password", "role", "status", "created_on", "updated_on", "last_login_at"}
 	var existingColumnCount int64
 
 	err = DB.Raw(`
@@ -191,14 +359,32 @@ func addConstraintsAndIndexes() error {
 		log.Println("Status constraint added/verified successfully")
 	}
 
-	// Create indexes for better performance
+	// Create indexes for better performance. The unique ones back the email, slug and
+	// one-profile-per-user checks, so concurrent inserts can't create duplicates; the
+	// services map violations of them back to "already exists" errors by name.
 	indexes := map[string]string{
-		"idx_users_email":      "CREATE INDEX IF NOT EXISTS idx_users_email ON users(email)",
+		"idx_users_email":      "CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(LOWER(email))",
 		"idx_users_role":       "CREATE INDEX IF NOT EXISTS idx_users_role ON users(role)",
 		"idx_users_status":     "CREATE INDEX IF NOT EXISTS idx_users_status ON users(status)",
 		"idx_users_created_on": "CREATE INDEX IF NOT EXISTS idx_users_created_on ON users(created_on)",
+
+		"idx_business_email": "CREATE UNIQUE INDEX IF NOT EXISTS idx_business_email ON business(LOWER(email))",
+		// A sign-up holds its email only while it waits for review
+		"idx_pending_signup_email": "CREATE UNIQUE INDEX IF NOT EXISTS idx_pending_signup_email ON pending_signups(LOWER(email)) WHERE status = 'pending'",
+		"idx_business_slug":        "CREATE UNIQUE INDEX IF NOT EXISTS idx_business_slug ON business(slug)",
+		"idx_business_user_id":     "CREATE UNIQUE INDEX IF NOT EXISTS idx_business_user_id ON business(user_id)",
+		"idx_teacher_user_id":      "CREATE UNIQUE INDEX IF NOT EXISTS idx_teacher_user_id ON teacher(user_id)",
+		"idx_student_user_id":      "CREATE UNIQUE INDEX IF NOT EXISTS idx_student_user_id ON student(user_id)",
+
+		"idx_business_status":          "CREATE INDEX IF NOT EXISTS idx_business_status ON business(status)",
+		"idx_business_package_id":      "CREATE INDEX IF NOT EXISTS idx_business_package_id ON business(package_id)",
+		"idx_teacher_business_status":  "CREATE INDEX IF NOT EXISTS idx_teacher_business_status ON teacher(business_id, status)",
+		"idx_student_business_status":  "CREATE INDEX IF NOT EXISTS idx_student_business_status ON student(business_id, status)",
+		"idx_student_business_created": "CREATE INDEX IF NOT EXISTS idx_student_business_created ON student(business_id, created_on)",
 	}
 
+	lowerEmailIndexes(indexes)
+
 	for indexName, indexSQL := range indexes {
 		if err := DB.Exec(indexSQL).Error; err != nil {
 			log.Printf("Warning: Failed to create index %s: %v", indexName, err)
@@ -207,18 +393,206 @@ func addConstraintsAndIndexes() error {
 		}
 	}
 
+	addStudentSearchIndexes()
+
 	return nil
 }
 
-// Helper function to get database connection info
-func GetConnectionInfo() map[string]string {
-	return map[string]string{
-		"host":    os.Getenv("DB_HOST"),
-		"user":    os.Getenv("DB_USER"),
-		"dbname":  os.Getenv("DB_NAME"),
-		"port":    os.Getenv("DB_PORT"),
-		"sslmode": "disable",
+// lowerEmailIndexes rebuilds the unique email indexes created before they compared
+// addresses case-insensitively. The old index is kept when the new one can't be built,
+// which happens while addresses differing only in case are both stored.
+func lowerEmailIndexes(indexes map[string]string) {
+	for _, indexName := range []string{"idx_users_email", "idx_business_email"} {
+		var definition string
+		err := DB.Raw("SELECT indexdef FROM pg_indexes WHERE schemaname = CURRENT_SCHEMA() AND indexname = ?", indexName).
+			Scan(&definition).Error
+		if err != nil || definition == "" || strings.Contains(strings.ToLower(definition), "lower(") {
+			continue
+		}
+
+		err = DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("DROP INDEX " + indexName).Error; err != nil {
+				return err
+			}
+			return tx.Exec(indexes[indexName]).Error
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to make index %s case-insensitive, merge emails differing only in case first: %v", indexName, err)
+		} else {
+			log.Printf("Index %s now compares emails case-insensitively", indexName)
+		}
+	}
+}
+
+// addStudentSearchIndexes adds trigram indexes so the ILIKE '%term%' student search,
+// including its information values, doesn't scan every row. They need the pg_trgm
+// extension; without it search still works, just slower.
+func addStudentSearchIndexes() {
+	if err := DB.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		log.Printf("Warning: pg_trgm is not available, student search will not be indexed. Run CREATE EXTENSION pg_trgm as a superuser to enable it: %v", err)
+		return
 	}
+
+	indexes := map[string]string{
+		"idx_student_name_trgm":           "CREATE INDEX IF NOT EXISTS idx_student_name_trgm ON student USING gin (name gin_trgm_ops)",
+		"idx_student_information_trgm":    "CREATE INDEX IF NOT EXISTS idx_student_information_trgm ON student USING gin ((information::text) gin_trgm_ops)",
+		"idx_student_guardian_name_trgm":  "CREATE INDEX IF NOT EXISTS idx_student_guardian_name_trgm ON student_guardian USING gin (name gin_trgm_ops)",
+		"idx_student_guardian_email_trgm": "CREATE INDEX IF NOT EXISTS idx_student_guardian_email_trgm ON student_guardian USING gin (email gin_trgm_ops)",
+		"idx_student_guardian_phone_trgm": "CREATE INDEX IF NOT EXISTS idx_student_guardian_phone_trgm ON student_guardian USING gin (phone gin_trgm_ops)",
+	}
+
+	for indexName, indexSQL := range indexes {
+		if err := DB.Exec(indexSQL).Error; err != nil {
+			log.Printf("Warning: Failed to create index %s: %v", indexName, err)
+		} else {
+			log.Printf("Index %s added/verified successfully", indexName)
+		}
+	}
+}
+
+var experiencePattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*\+?\s*(years?|yrs?|months?|mos?)?`)
+
+// parseExperienceYears makes a best-effort guess at the number of years in a
+// free-text experience value such as "5 years", "3+ yrs" or "18 months"
+func parseExperienceYears(text string) (float64, bool) {
+	match := experiencePattern.FindStringSubmatch(strings.ToLower(text))
+	if match == nil {
+		return 0, false
+	}
+
+	years, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	if strings.HasPrefix(match[2], "mo") {
+		years = years / 12
+	}
+	if years < 0 || years > 70 {
+		return 0, false
+	}
+
+	return math.Round(years*10) / 10, true
+}
+
+// backfillExperienceYears sets experience_years for teachers that only have the
+// legacy free-text experience. Values that can't be parsed are left empty.
+func backfillExperienceYears() error {
+	var teachers []models.Teacher
+	err := DB.Select("id", "experience").
+		Where("experience_years IS NULL AND experience IS NOT NULL AND experience != ''").
+		Find(&teachers).Error
+	if err != nil {
+		return err
+	}
+
+	updated := 0
+	for _, teacher := range teachers {
+		years, ok := parseExperienceYears(teacher.Experience)
+		if !ok {
+			continue
+		}
+		if err := DB.Model(&models.Teacher{}).Where("id = ?", teacher.ID).Update("experience_years", years).Error; err != nil {
+			return err
+		}
+		updated++
+	}
+
+	if updated > 0 {
+		log.Printf("Backfilled experience years for %d teachers", updated)
+	}
+	return nil
+}
+
+// backfillStudentGuardians creates a primary guardian from the legacy guardian_*
+// columns for students that don't have any guardian rows yet
+func backfillStudentGuardians() error {
+	result := DB.Exec(`
+		INSERT INTO student_guardian (student_id, name, phone, email, is_primary, created_on, updated_on)
+		SELECT s.id, COALESCE(s.guardian_name, ''), COALESCE(s.guardian_number, ''), COALESCE(s.guardian_email, ''), TRUE, NOW(), NOW()
+		FROM student s
+		WHERE (COALESCE(s.guardian_name, '') != '' OR COALESCE(s.guardian_number, '') != '' OR COALESCE(s.guardian_email, '') != '')
+		AND NOT EXISTS (SELECT 1 FROM student_guardian g WHERE g.student_id = s.id)
+	`)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		log.Printf("Backfilled guardians for %d students", result.RowsAffected)
+	}
+	return nil
+}
+
+// normalizePhones rewrites user, business and guardian phones the way new ones are
+// stored: digits only, with a leading + (or 00) kept as + and the default country
+// code put in front of numbers written without one. Values that still aren't a valid
+// number afterwards are left untouched.
+func normalizePhones() error {
+	const normalized = `CASE
+		WHEN cleaned LIKE '+%' THEN cleaned
+		WHEN cleaned LIKE '00%' THEN '+' || SUBSTRING(cleaned FROM 3)
+		WHEN @country = '' THEN cleaned
+		ELSE '+' || @country || REGEXP_REPLACE(cleaned, '^0', '')
+	END`
+
+	updates := []struct {
+		table  string
+		column string
+	}{
+		{"users", "phone"},
+		{"business", "phone"},
+		{"student_guardian", "phone"},
+		{"student", "guardian_number"},
+	}
+
+	country := utils.DefaultPhoneCountryCode()
+	for _, update := range updates {
+		result := DB.Exec(fmt.Sprintf(`
+			UPDATE %[1]s SET %[2]s = n.phone
+			FROM (
+				SELECT id, %[3]s AS phone
+				FROM (SELECT id, REGEXP_REPLACE(%[2]s, '[ .()-]', '', 'g') AS cleaned FROM %[1]s WHERE %[2]s IS NOT NULL AND %[2]s != '') c
+				WHERE LENGTH(LTRIM(cleaned, '+')) BETWEEN 7 AND 15
+			) n
+			WHERE %[1]s.id = n.id AND n.phone ~ '^\+?[0-9]{7,15}$' AND %[1]s.%[2]s != n.phone
+		`, update.table, update.column, normalized), map[string]interface{}{"country": country})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected > 0 {
+			log.Printf("Normalized %d phone numbers in %s.%s", result.RowsAffected, update.table, update.column)
+		}
+	}
+	return nil
+}
+
+// dropBusinessPasswords removes the business.password column, which duplicated the
+// owner's hash in users.password. Only users.password is read when signing in.
+func dropBusinessPasswords() error {
+	if !DB.Migrator().HasColumn(&models.Business{}, "password") {
+		return nil
+	}
+	if err := DB.Migrator().DropColumn(&models.Business{}, "password"); err != nil {
+		return err
+	}
+	log.Println("Dropped business.password")
+	return nil
+}
+
+// backfillPackageTerms copies the current price and validation period of their package
+// onto businesses assigned one before those were recorded at assignment; that's the best
+// guess left of what they were assigned on
+func backfillPackageTerms() error {
+	result := DB.Exec(`UPDATE business SET package_price = packages.price, package_validation_period = packages.validation_period
+		FROM packages WHERE packages.id = business.package_id AND business.package_price IS NULL`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Backfilled package terms for %d businesses", result.RowsAffected)
+	}
+	return nil
 }
 
 // Helper function to check database connection health
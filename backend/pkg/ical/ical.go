@@ -0,0 +1,140 @@
+// Package ical writes iCalendar (RFC 5545) documents for calendar subscriptions
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateFormat     = "20060102"
+	floatingFormat = "20060102T150405"
+	utcFormat      = "20060102T150405Z"
+	// maxLineOctets is the longest a content line may be before it is folded
+	maxLineOctets = 75
+)
+
+// Calendar is a published calendar of events
+type Calendar struct {
+	ProdID string // identifies the product that wrote the calendar
+	Name   string // shown by clients as the calendar's name
+	Events []Event
+}
+
+// Event is one entry of a calendar. All-day events use the dates of Start and End; other
+// events are written in UTC, or as floating wall-clock times that clients show in their
+// own time zone.
+type Event struct {
+	UID         string // globally unique and stable across refreshes, so clients update events in place
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time // exclusive; for all-day events, the day after the last one
+	AllDay      bool
+	Floating    bool
+	// RRule repeats the event, e.g. "FREQ=WEEKLY;UNTIL=20250101T000000Z". UNTIL must be
+	// floating too when the event is; see FormatFloating.
+	RRule string
+}
+
+// WriteTo writes the calendar as an iCalendar document
+func (c Calendar) WriteTo(w io.Writer) (int64, error) {
+	var b bytes.Buffer
+	stamp := time.Now().UTC().Format(utcFormat)
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+c.ProdID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+	for _, event := range c.Events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+escapeText(event.UID))
+		writeLine(&b, "DTSTAMP:"+stamp)
+		if event.AllDay {
+			writeLine(&b, "DTSTART;VALUE=DATE:"+event.Start.Format(dateFormat))
+			writeLine(&b, "DTEND;VALUE=DATE:"+event.End.Format(dateFormat))
+		} else {
+			writeLine(&b, "DTSTART:"+formatTime(event.Start, event.Floating))
+			writeLine(&b, "DTEND:"+formatTime(event.End, event.Floating))
+		}
+		if event.RRule != "" {
+			writeLine(&b, "RRULE:"+event.RRule)
+		}
+		writeLine(&b, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		if event.Location != "" {
+			writeLine(&b, "LOCATION:"+escapeText(event.Location))
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+	writeLine(&b, "END:VCALENDAR")
+
+	return b.WriteTo(w)
+}
+
+// Bytes returns the calendar as an iCalendar document
+func (c Calendar) Bytes() []byte {
+	var b bytes.Buffer
+	c.WriteTo(&b)
+	return b.Bytes()
+}
+
+// FormatFloating formats the wall-clock time of t as a floating date-time
+func FormatFloating(t time.Time) string {
+	return t.Format(floatingFormat)
+}
+
+func formatTime(t time.Time, floating bool) string {
+	if floating {
+		return t.Format(floatingFormat)
+	}
+	return t.UTC().Format(utcFormat)
+}
+
+// escapeText escapes a TEXT value: backslashes, semicolons, commas and newlines
+func escapeText(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch r {
+		case '\\', ';', ',':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// writeLine writes a content line ended by CRLF, folding it onto continuation lines that
+// start with a space so no line is longer than 75 octets. Lines are only folded between
+// characters, never inside a multi-byte one.
+func writeLine(b *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space of a continuation line counts towards its length
+		limit = maxLineOctets - 1
+	}
+	fmt.Fprintf(b, "%s\r\n", line)
+}
@@ -0,0 +1,186 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// lines splits a document into its physical lines, failing on any not ended by CRLF
+func lines(t *testing.T, document string) []string {
+	t.Helper()
+	if !strings.HasSuffix(document, "\r\n") {
+		t.Fatalf("document doesn't end with CRLF: %q", document)
+	}
+	physical := strings.Split(strings.TrimSuffix(document, "\r\n"), "\r\n")
+	for _, line := range physical {
+		if strings.ContainsAny(line, "\r\n") {
+			t.Errorf("bare CR or LF in line %q", line)
+		}
+	}
+	return physical
+}
+
+// unfold joins continuation lines back onto the lines they were folded from
+func unfold(physical []string) []string {
+	var logical []string
+	for _, line := range physical {
+		if strings.HasPrefix(line, " ") && len(logical) > 0 {
+			logical[len(logical)-1] += line[1:]
+			continue
+		}
+		logical = append(logical, line)
+	}
+	return logical
+}
+
+func TestCalendarStructure(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	calendar := Calendar{
+		ProdID: "-//Test//Calendar//EN",
+		Name:   "Sunrise Academy",
+		Events: []Event{
+			{
+				UID:     "batch-1@example.com",
+				Summary: "Physics",
+				// 16:00 in Kolkata is 10:30 UTC
+				Start: time.Date(2025, 4, 7, 16, 0, 0, 0, kolkata),
+				End:   time.Date(2025, 4, 7, 17, 30, 0, 0, kolkata),
+				RRule: "FREQ=WEEKLY;BYDAY=MO",
+			},
+			{
+				UID:     "exam-2@example.com",
+				Summary: "Finals",
+				Start:   time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC),
+				End:     time.Date(2025, 4, 11, 0, 0, 0, 0, time.UTC),
+				AllDay:  true,
+			},
+			{
+				UID:      "slot-3@example.com",
+				Summary:  "Office hours",
+				Start:    time.Date(2025, 4, 8, 9, 0, 0, 0, kolkata),
+				End:      time.Date(2025, 4, 8, 10, 0, 0, 0, kolkata),
+				Floating: true,
+			},
+		},
+	}
+	logical := unfold(lines(t, string(calendar.Bytes())))
+
+	var structure []string
+	properties := map[string][]string{}
+	for _, line := range logical {
+		name, value, _ := strings.Cut(line, ":")
+		if name == "BEGIN" || name == "END" {
+			structure = append(structure, line)
+		}
+		properties[name] = append(properties[name], value)
+	}
+	want := []string{"BEGIN:VCALENDAR", "BEGIN:VEVENT", "END:VEVENT", "BEGIN:VEVENT", "END:VEVENT", "BEGIN:VEVENT", "END:VEVENT", "END:VCALENDAR"}
+	if strings.Join(structure, ",") != strings.Join(want, ",") {
+		t.Errorf("structure = %v, want %v", structure, want)
+	}
+	if logical[1] != "VERSION:2.0" || logical[2] != "PRODID:-//Test//Calendar//EN" {
+		t.Errorf("header = %v, want VERSION then PRODID", logical[1:3])
+	}
+
+	checks := []struct {
+		property string
+		want     []string
+	}{
+		{"DTSTART", []string{"20250407T103000Z", "20250408T090000"}},
+		{"DTEND", []string{"20250407T120000Z", "20250408T100000"}},
+		{"DTSTART;VALUE=DATE", []string{"20250410"}},
+		{"DTEND;VALUE=DATE", []string{"20250411"}},
+		{"RRULE", []string{"FREQ=WEEKLY;BYDAY=MO"}},
+		{"UID", []string{"batch-1@example.com", "exam-2@example.com", "slot-3@example.com"}},
+		{"X-WR-CALNAME", []string{"Sunrise Academy"}},
+	}
+	for _, check := range checks {
+		if got := properties[check.property]; strings.Join(got, ",") != strings.Join(check.want, ",") {
+			t.Errorf("%s = %v, want %v", check.property, got, check.want)
+		}
+	}
+	if stamps := properties["DTSTAMP"]; len(stamps) != 3 || !strings.HasSuffix(stamps[0], "Z") {
+		t.Errorf("DTSTAMP = %v, want one UTC stamp per event", stamps)
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Physics, Chemistry; Biology", `Physics\, Chemistry\; Biology`},
+		{`C:\path`, `C:\\path`},
+		{"first line\r\nsecond line\nthird", `first line\nsecond line\nthird`},
+		{"plain", "plain"},
+	}
+	for _, tt := range tests {
+		if got := escapeText(tt.value); got != tt.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	calendar := Calendar{ProdID: "-//Test//EN", Events: []Event{{
+		UID: "1", Summary: "Maths; revision, week 2", Location: "Room 4\nFirst floor",
+		Start: time.Date(2025, 4, 7, 10, 0, 0, 0, time.UTC), End: time.Date(2025, 4, 7, 11, 0, 0, 0, time.UTC),
+	}}}
+	logical := unfold(lines(t, string(calendar.Bytes())))
+	document := strings.Join(logical, "\n")
+	for _, want := range []string{`SUMMARY:Maths\; revision\, week 2`, `LOCATION:Room 4\nFirst floor`} {
+		if !strings.Contains(document, want) {
+			t.Errorf("document lacks %q:\n%s", want, document)
+		}
+	}
+}
+
+// Long lines are folded into lines of at most 75 octets, never inside a character, and
+// unfold back to the original
+func TestLineFolding(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"short", "Physics"},
+		{"exactly at the limit", strings.Repeat("a", maxLineOctets-len("SUMMARY:"))},
+		{"one over the limit", strings.Repeat("a", maxLineOctets-len("SUMMARY:")+1)},
+		{"several lines of ASCII", strings.Repeat("Algebra and geometry ", 12)},
+		{"multi-byte characters", strings.Repeat("गणित कक्षा ", 20)},
+		{"emoji", strings.Repeat("📐📏", 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := Calendar{ProdID: "-//Test//EN", Events: []Event{{
+				UID: "1", Summary: tt.value,
+				Start: time.Date(2025, 4, 7, 10, 0, 0, 0, time.UTC), End: time.Date(2025, 4, 7, 11, 0, 0, 0, time.UTC),
+			}}}
+			physical := lines(t, string(calendar.Bytes()))
+			for _, line := range physical {
+				if len(line) > maxLineOctets {
+					t.Errorf("line of %d octets: %q", len(line), line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line folded inside a character: %q", line)
+				}
+			}
+
+			var summary string
+			for _, line := range unfold(physical) {
+				if strings.HasPrefix(line, "SUMMARY:") {
+					summary = strings.TrimPrefix(line, "SUMMARY:")
+				}
+			}
+			if summary != tt.value {
+				t.Errorf("unfolded summary = %q, want %q", summary, tt.value)
+			}
+			wantFolded := len("SUMMARY:"+tt.value) > maxLineOctets
+			if folded := strings.Contains(string(calendar.Bytes()), "\r\n "); folded != wantFolded {
+				t.Errorf("folded = %v, want %v", folded, wantFolded)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Message is an email with a plain text body and an optional HTML alternative
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer is the shared interface for sending email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns an SMTP mailer, or a dry-run mailer that only logs when no SMTP host is
+// configured
+func New(config SMTPConfig) Mailer {
+	if config.Host == "" {
+		slog.Info("SMTP host not set, emails will only be logged")
+		return DryRun{}
+	}
+	return NewSMTP(config)
+}
+
+// DryRun logs messages instead of sending them, for development and tests
+type DryRun struct{}
+
+func (DryRun) Send(ctx context.Context, msg Message) error {
+	slog.Info("Email not sent (dry run)", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
@@ -0,0 +1,148 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrQueueFull is returned by Queue.Send when the backlog is at capacity
+var ErrQueueFull = errors.New("email queue is full")
+
+// ErrQueueClosed is returned by Queue.Send after Close
+var ErrQueueClosed = errors.New("email queue is closed")
+
+// QueueConfig controls the workers of a Queue
+type QueueConfig struct {
+	Size       int           // messages waiting to be sent, 100 by default
+	Workers    int           // concurrent sends, 2 by default
+	MaxRetries int           // attempts after the first failure, 3 by default
+	Backoff    time.Duration // delay before the first retry, doubled for each retry, 2s by default
+}
+
+// Queue sends messages in the background through another mailer, so callers never wait
+// on SMTP. Failed sends are retried with exponential backoff and dropped with an error
+// log once the retries are used up.
+type Queue struct {
+	next    Mailer
+	config  QueueConfig
+	jobs    chan Message
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	mu      sync.RWMutex
+	closed  bool
+	closing sync.Once
+}
+
+func NewQueue(next Mailer, config QueueConfig) *Queue {
+	if config.Size <= 0 {
+		config.Size = 100
+	}
+	if config.Workers <= 0 {
+		config.Workers = 2
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	} else if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = 2 * time.Second
+	}
+
+	q := &Queue{
+		next:   next,
+		config: config,
+		jobs:   make(chan Message, config.Size),
+		stop:   make(chan struct{}),
+	}
+	for i := 0; i < config.Workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+// Send enqueues msg and returns without waiting for it to be sent. The error only reports
+// whether the message was accepted.
+func (q *Queue) Send(ctx context.Context, msg Message) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.jobs <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting messages and waits for the queued ones to be sent, giving up on
+// retries once ctx is done
+func (q *Queue) Close(ctx context.Context) error {
+	q.closing.Do(func() {
+		q.mu.Lock()
+		q.closed = true
+		close(q.jobs)
+		q.mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.abort()
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) abort() {
+	select {
+	case <-q.stop:
+	default:
+		close(q.stop)
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for msg := range q.jobs {
+		q.deliver(msg)
+	}
+}
+
+func (q *Queue) deliver(msg Message) {
+	backoff := q.config.Backoff
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		err := q.next.Send(ctx, msg)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		if attempt >= q.config.MaxRetries {
+			slog.Error("Giving up on email", "to", msg.To, "subject", msg.Subject, "attempts", attempt+1, "error", err)
+			return
+		}
+		slog.Warn("Email send failed, retrying", "to", msg.To, "subject", msg.Subject, "attempt", attempt+1, "retry_in", backoff.String(), "error", err)
+
+		select {
+		case <-time.After(backoff):
+		case <-q.stop:
+			slog.Error("Dropping email at shutdown", "to", msg.To, "subject", msg.Subject, "error", err)
+			return
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,80 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Templates live in templates/ as <name>.txt, which defines the "subject" block and the
+// plain text body, and an optional <name>.html with the HTML body
+//
+//go:embed templates
+var templateFS embed.FS
+
+type emailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// templates is parsed once at startup so a broken template fails fast. Every file gets its
+// own template set, since they all define "subject".
+var templates = mustParseTemplates()
+
+func mustParseTemplates() map[string]emailTemplate {
+	entries, err := fs.ReadDir(templateFS, "templates")
+	if err != nil {
+		panic(err)
+	}
+
+	parsed := make(map[string]emailTemplate)
+	for _, entry := range entries {
+		if path.Ext(entry.Name()) != ".txt" {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".txt")
+		t := emailTemplate{
+			text: texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/"+entry.Name())),
+		}
+		if _, err := fs.Stat(templateFS, "templates/"+name+".html"); err == nil {
+			t.html = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/"+name+".html"))
+		}
+		parsed[name] = t
+	}
+	return parsed
+}
+
+// Render builds a message to to from the named template, executed with data
+func Render(name string, to string, data interface{}) (Message, error) {
+	t, ok := templates[name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, body bytes.Buffer
+	if err := t.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("error rendering %s subject: %w", name, err)
+	}
+	if err := t.text.Execute(&body, data); err != nil {
+		return Message{}, fmt.Errorf("error rendering %s text: %w", name, err)
+	}
+
+	msg := Message{
+		To:      []string{to},
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    body.String(),
+	}
+	if t.html != nil {
+		var b bytes.Buffer
+		if err := t.html.Execute(&b, data); err != nil {
+			return Message{}, fmt.Errorf("error rendering %s html: %w", name, err)
+		}
+		msg.HTML = b.String()
+	}
+	return msg, nil
+}
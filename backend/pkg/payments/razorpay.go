@@ -0,0 +1,87 @@
+package payments
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Razorpay verifies and parses Razorpay webhooks
+type Razorpay struct {
+	secret string
+}
+
+func NewRazorpay(secret string) *Razorpay {
+	return &Razorpay{secret: secret}
+}
+
+type razorpayEvent struct {
+	Event   string `json:"event"`
+	Payload struct {
+		Payment struct {
+			Entity struct {
+				ID        string          `json:"id"`
+				Amount    int64           `json:"amount"` // in paise
+				Currency  string          `json:"currency"`
+				Method    string          `json:"method"`
+				Notes     json.RawMessage `json:"notes"`
+				CreatedAt int64           `json:"created_at"`
+			} `json:"entity"`
+		} `json:"payment"`
+	} `json:"payload"`
+}
+
+// ParseWebhook checks the X-Razorpay-Signature header, the hex HMAC-SHA256 of the body
+// keyed with the webhook secret. The event ID comes from the X-Razorpay-Event-Id header.
+func (r *Razorpay) ParseWebhook(header http.Header, body []byte) (Event, error) {
+	if !VerifyHMACSHA256(r.secret, body, header.Get("X-Razorpay-Signature")) {
+		return Event{}, ErrInvalidSignature
+	}
+
+	var payload razorpayEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return Event{}, fmt.Errorf("razorpay: invalid payload: %w", err)
+	}
+
+	event := Event{ID: header.Get("X-Razorpay-Event-Id"), Type: payload.Event, Kind: KindOther}
+	if event.ID == "" {
+		return Event{}, errors.New("razorpay: webhook has no X-Razorpay-Event-Id")
+	}
+
+	// order.paid and payment.captured both report the same payment
+	if payload.Event != "payment.captured" && payload.Event != "order.paid" {
+		return event, nil
+	}
+	entity := payload.Payload.Payment.Entity
+	if entity.ID == "" {
+		return Event{}, errors.New("razorpay: payment event has no payment")
+	}
+
+	notes := razorpayNotes(entity.Notes)
+	event.Kind = KindPaymentSucceeded
+	event.Payment = &Payment{
+		ID:        entity.ID,
+		Amount:    float64(entity.Amount) / 100,
+		Currency:  entity.Currency,
+		Method:    paymentMethod(entity.Method),
+		StudentID: notes["student_id"],
+		FeePlanID: notes["fee_plan_id"],
+		PaidAt:    unixTime(entity.CreatedAt),
+	}
+	return event, nil
+}
+
+// razorpayNotes reads the notes of a payment, which Razorpay sends as an empty array
+// rather than an object when there are none
+func razorpayNotes(raw json.RawMessage) map[string]string {
+	var notes map[string]interface{}
+	if err := json.Unmarshal(raw, &notes); err != nil {
+		return nil
+	}
+	values := make(map[string]string, len(notes))
+	for key, value := range notes {
+		values[key] = fmt.Sprint(value)
+	}
+	return values
+}
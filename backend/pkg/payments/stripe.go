@@ -0,0 +1,111 @@
+package payments
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stripeTolerance is how old a signed Stripe webhook may be, to limit replays of a
+// captured call
+const stripeTolerance = 5 * time.Minute
+
+// Stripe verifies and parses Stripe webhooks
+type Stripe struct {
+	secret string
+	now    func() time.Time
+}
+
+func NewStripe(secret string) *Stripe {
+	return &Stripe{secret: secret, now: time.Now}
+}
+
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object struct {
+			ID                 string            `json:"id"`
+			AmountReceived     int64             `json:"amount_received"` // in the currency's smallest unit
+			Currency           string            `json:"currency"`
+			PaymentMethodTypes []string          `json:"payment_method_types"`
+			Metadata           map[string]string `json:"metadata"`
+			Created            int64             `json:"created"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+// ParseWebhook checks the Stripe-Signature header, "t=<timestamp>,v1=<signature>,...",
+// where a v1 signature is the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the
+// endpoint secret. Only payment_intent.succeeded is acted on; amounts assume a currency
+// with two decimals.
+func (s *Stripe) ParseWebhook(header http.Header, body []byte) (Event, error) {
+	if err := s.verify(header.Get("Stripe-Signature"), body); err != nil {
+		return Event{}, err
+	}
+
+	var payload stripeEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return Event{}, fmt.Errorf("stripe: invalid payload: %w", err)
+	}
+	if payload.ID == "" {
+		return Event{}, errors.New("stripe: webhook has no event ID")
+	}
+
+	event := Event{ID: payload.ID, Type: payload.Type, Kind: KindOther}
+	if payload.Type != "payment_intent.succeeded" {
+		return event, nil
+	}
+
+	intent := payload.Data.Object
+	method := "card"
+	if len(intent.PaymentMethodTypes) > 0 {
+		method = intent.PaymentMethodTypes[0]
+	}
+	event.Kind = KindPaymentSucceeded
+	event.Payment = &Payment{
+		ID:        intent.ID,
+		Amount:    float64(intent.AmountReceived) / 100,
+		Currency:  strings.ToUpper(intent.Currency),
+		Method:    paymentMethod(method),
+		StudentID: intent.Metadata["student_id"],
+		FeePlanID: intent.Metadata["fee_plan_id"],
+		PaidAt:    unixTime(intent.Created),
+	}
+	return event, nil
+}
+
+func (s *Stripe) verify(header string, body []byte) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := s.now().Sub(time.Unix(seconds, 0)); age > stripeTolerance || age < -stripeTolerance {
+		return ErrInvalidSignature
+	}
+
+	payload := append([]byte(timestamp+"."), body...)
+	// Stripe sends one signature per active secret while a secret is being rolled
+	for _, signature := range signatures {
+		if VerifyHMACSHA256(s.secret, payload, signature) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
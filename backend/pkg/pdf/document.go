@@ -0,0 +1,279 @@
+// Package pdf writes simple text-and-table PDF documents such as report cards and
+// receipts. It only uses the standard Helvetica fonts and JPEG images, which every
+// viewer supports without embedding font programs, so it needs no third-party library.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"strconv"
+	"strings"
+
+	// Logos may be uploaded as PNG; they are re-encoded as JPEG
+	_ "image/png"
+)
+
+// A4 page size in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Document is a PDF being drawn page by page. Coordinates are in points with the
+// origin at the top-left corner of the page; text is positioned by its baseline.
+type Document struct {
+	pages   []*bytes.Buffer
+	current int // index of the page being drawn on
+	images  []pdfImage
+	font    Font
+	size    float64
+}
+
+type pdfImage struct {
+	data          []byte // JPEG
+	width, height int
+}
+
+// New returns an empty document; call AddPage before drawing
+func New() *Document {
+	return &Document{font: Regular, size: 10}
+}
+
+// AddPage starts a new page and makes it the one being drawn on
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.current = len(d.pages) - 1
+}
+
+// PageCount returns the number of pages added so far
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// SetPage makes an earlier page the one being drawn on, e.g. to add page numbers
+// once the page count is known
+func (d *Document) SetPage(n int) {
+	if n < 1 || n > len(d.pages) {
+		panic(fmt.Sprintf("pdf: page %d out of range", n))
+	}
+	d.current = n - 1
+}
+
+// SetFont sets the font and size of the text drawn next
+func (d *Document) SetFont(font Font, size float64) {
+	d.font = font
+	d.size = size
+}
+
+// Text draws s with its baseline starting at (x, y) in the given gray level, 0 being black
+func (d *Document) Text(x, y float64, s string, gray float64) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(d.page(), "%s g BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		num(gray), d.font.resourceName(), num(d.size), num(x), num(PageHeight-y), escapeText(s))
+}
+
+// TextRight draws s so that it ends at x
+func (d *Document) TextRight(x, y float64, s string, gray float64) {
+	d.Text(x-TextWidth(d.font, d.size, s), y, s, gray)
+}
+
+// TextCenter draws s centered on x
+func (d *Document) TextCenter(x, y float64, s string, gray float64) {
+	d.Text(x-TextWidth(d.font, d.size, s)/2, y, s, gray)
+}
+
+// Line draws a straight line of the given width and gray level
+func (d *Document) Line(x1, y1, x2, y2, width, gray float64) {
+	fmt.Fprintf(d.page(), "%s G %s w %s %s m %s %s l S\n",
+		num(gray), num(width), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// FillRect fills a rectangle whose top-left corner is (x, y) with a gray level
+func (d *Document) FillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(d.page(), "%s g %s %s %s %s re f\n",
+		num(gray), num(x), num(PageHeight-y-h), num(w), num(h))
+}
+
+// Image draws a JPEG or PNG image scaled to fit a w by h box whose top-left corner is
+// (x, y), keeping its aspect ratio. It returns the size actually drawn.
+func (d *Document) Image(data []byte, x, y, w, h float64) (float64, float64, error) {
+	if err := CheckImage(bytes.NewReader(data)); err != nil {
+		return 0, 0, fmt.Errorf("pdf: %w", err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, fmt.Errorf("pdf: decode image: %w", err)
+	}
+
+	// Flatten transparency onto white; JPEG has no alpha channel
+	bounds := img.Bounds()
+	flat := image.NewRGBA(bounds)
+	draw.Draw(flat, bounds, &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(flat, bounds, img, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: 90}); err != nil {
+		return 0, 0, fmt.Errorf("pdf: encode image: %w", err)
+	}
+
+	d.images = append(d.images, pdfImage{data: buf.Bytes(), width: bounds.Dx(), height: bounds.Dy()})
+
+	scale := w / float64(bounds.Dx())
+	if hs := h / float64(bounds.Dy()); hs < scale {
+		scale = hs
+	}
+	drawnW, drawnH := float64(bounds.Dx())*scale, float64(bounds.Dy())*scale
+
+	fmt.Fprintf(d.page(), "q %s 0 0 %s %s %s cm /Im%d Do Q\n",
+		num(drawnW), num(drawnH), num(x), num(PageHeight-y-drawnH), len(d.images))
+	return drawnW, drawnH, nil
+}
+
+// MaxImagePixels bounds the images Image accepts, since they are decoded in full
+const MaxImagePixels = 4096 * 4096
+
+// CheckImage returns an error unless r holds a JPEG or PNG image that Image can draw
+func CheckImage(r io.Reader) error {
+	config, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return fmt.Errorf("image must be a JPEG or PNG")
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxImagePixels {
+		return fmt.Errorf("image must be at most %d pixels", MaxImagePixels)
+	}
+	return nil
+}
+
+// WriteTo writes the finished document to w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	out := &pdfWriter{}
+	out.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	// Object numbers: 1 catalog, 2 page tree, 3-4 fonts, then images, then a page and
+	// its content stream for every page
+	const firstImage = 5
+	firstPage := firstImage + len(d.images)
+	pageRefs := make([]string, len(d.pages))
+	for i := range d.pages {
+		pageRefs[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	out.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	out.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(pageRefs, " "), len(d.pages)))
+	for i, font := range []Font{Regular, Bold} {
+		out.object(3+i, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font.baseName()))
+	}
+
+	var xObjects strings.Builder
+	for i, img := range d.images {
+		id := firstImage + i
+		fmt.Fprintf(&xObjects, " /Im%d %d 0 R", i+1, id)
+		out.stream(id, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode",
+			img.width, img.height), img.data)
+	}
+
+	resources := "<< /Font << /F1 3 0 R /F2 4 0 R >>"
+	if xObjects.Len() > 0 {
+		resources += " /XObject <<" + xObjects.String() + " >>"
+	}
+	resources += " >>"
+
+	for i, content := range d.pages {
+		id := firstPage + 2*i
+		out.object(id, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			num(PageWidth), num(PageHeight), resources, id+1))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(content.Bytes()); err != nil {
+			return 0, err
+		}
+		if err := zw.Close(); err != nil {
+			return 0, err
+		}
+		out.stream(id+1, "/Filter /FlateDecode", compressed.Bytes())
+	}
+
+	out.finish(1)
+	n, err := w.Write(out.buf.Bytes())
+	return int64(n), err
+}
+
+// Bytes returns the finished document
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *Document) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[d.current]
+}
+
+// num formats a coordinate or size to two decimals, dropping trailing zeros
+func num(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// pdfWriter tracks object offsets for the cross-reference table
+type pdfWriter struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+}
+
+func (w *pdfWriter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&w.buf, format, args...)
+}
+
+func (w *pdfWriter) begin(id int) {
+	if w.offsets == nil {
+		w.offsets = make(map[int]int)
+	}
+	w.offsets[id] = w.buf.Len()
+	w.printf("%d 0 obj\n", id)
+}
+
+func (w *pdfWriter) object(id int, body string) {
+	w.begin(id)
+	w.printf("%s\nendobj\n", body)
+}
+
+func (w *pdfWriter) stream(id int, dict string, data []byte) {
+	w.begin(id)
+	w.printf("<< %s /Length %d >>\nstream\n", dict, len(data))
+	w.buf.Write(data)
+	w.printf("\nendstream\nendobj\n")
+}
+
+func (w *pdfWriter) finish(root int) {
+	size := len(w.offsets) + 1
+	xref := w.buf.Len()
+	w.printf("xref\n0 %d\n0000000000 65535 f \n", size)
+	for id := 1; id < size; id++ {
+		w.printf("%010d 00000 n \n", w.offsets[id])
+	}
+	w.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", size, root, xref)
+}
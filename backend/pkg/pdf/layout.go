@@ -0,0 +1,305 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Layout is the printable content of a document, kept apart from drawing so what a
+// document says can be inspected without parsing the PDF
+type Layout struct {
+	Header   Header    `json:"header"`
+	Sections []Section `json:"sections"`
+	Footer   string    `json:"footer,omitempty"` // printed at the bottom of every page with the page number
+}
+
+// Header is the branding block at the top of the first page
+type Header struct {
+	Logo      []byte   `json:"-"` // JPEG or PNG, optional
+	Name      string   `json:"name"`
+	Lines     []string `json:"lines,omitempty"` // address and contact details under the name
+	Title     string   `json:"title"`           // document title on the right
+	TitleInfo []string `json:"title_info,omitempty"`
+}
+
+// Section is a headed block of label/value fields, an optional table and an optional note
+type Section struct {
+	Heading string  `json:"heading,omitempty"`
+	Fields  []Field `json:"fields,omitempty"`
+	Table   *Table  `json:"table,omitempty"`
+	Note    string  `json:"note,omitempty"`
+}
+
+type Field struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// Table is a grid of text cells; rows that do not fit on a page continue on the next
+// one under a repeated header row
+type Table struct {
+	Columns []Column   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+	Totals  []string   `json:"totals,omitempty"` // bold last row
+}
+
+type Column struct {
+	Title string  `json:"title"`
+	Width float64 `json:"width"` // share of the table width; shares are normalized
+	Right bool    `json:"right,omitempty"`
+}
+
+const (
+	margin        = 40.0
+	contentWidth  = PageWidth - 2*margin
+	contentBottom = PageHeight - margin - 24 // leaves room for the footer
+	logoSize      = 56.0
+	rowHeight     = 18.0
+	cellPadding   = 5.0
+	mutedGray     = 0.4
+)
+
+// Render draws layout as a PDF document
+func Render(layout Layout) ([]byte, error) {
+	r := &renderer{doc: New()}
+	r.doc.AddPage()
+	r.y = margin
+
+	if err := r.header(layout.Header); err != nil {
+		return nil, err
+	}
+	for _, section := range layout.Sections {
+		r.section(section)
+	}
+	r.footers(layout.Footer)
+
+	return r.doc.Bytes()
+}
+
+type renderer struct {
+	doc *Document
+	y   float64 // top of the free space on the current page
+}
+
+// ensure starts a new page unless height more points fit on the current one
+func (r *renderer) ensure(height float64) bool {
+	if r.y+height <= contentBottom {
+		return false
+	}
+	r.doc.AddPage()
+	r.y = margin
+	return true
+}
+
+func (r *renderer) header(h Header) error {
+	top := r.y
+	textX := margin
+	bottom := top
+
+	if len(h.Logo) > 0 {
+		_, drawnH, err := r.doc.Image(h.Logo, margin, top, logoSize, logoSize)
+		if err != nil {
+			return err
+		}
+		textX += logoSize + 12
+		bottom = top + drawnH
+	}
+
+	// Business name and details on the left, document title on the right
+	r.doc.SetFont(Bold, 16)
+	r.doc.Text(textX, top+16, fit(Bold, 16, h.Name, contentWidth*0.6-(textX-margin)), 0)
+	y := top + 18
+	r.doc.SetFont(Regular, 9)
+	for _, line := range h.Lines {
+		y += 12
+		r.doc.Text(textX, y, fit(Regular, 9, line, contentWidth*0.6-(textX-margin)), mutedGray)
+	}
+	if y > bottom {
+		bottom = y
+	}
+
+	right := PageWidth - margin
+	r.doc.SetFont(Bold, 14)
+	r.doc.TextRight(right, top+16, h.Title, 0)
+	y = top + 18
+	r.doc.SetFont(Regular, 9)
+	for _, line := range h.TitleInfo {
+		y += 12
+		r.doc.TextRight(right, y, line, mutedGray)
+	}
+	if y > bottom {
+		bottom = y
+	}
+
+	r.y = bottom + 12
+	r.doc.Line(margin, r.y, right, r.y, 1, 0)
+	r.y += 18
+	return nil
+}
+
+func (r *renderer) section(s Section) {
+	if s.Heading != "" {
+		// Keep the heading with at least the first line of its content
+		r.ensure(20 + rowHeight*2)
+		r.doc.SetFont(Bold, 11)
+		r.doc.Text(margin, r.y+11, s.Heading, 0)
+		r.y += 20
+	}
+
+	r.fields(s.Fields)
+	if s.Table != nil {
+		r.table(*s.Table)
+	}
+	if s.Note != "" {
+		r.note(s.Note)
+	}
+	r.y += 14
+}
+
+// fields prints label/value pairs in two columns
+func (r *renderer) fields(fields []Field) {
+	const columnWidth = contentWidth / 2
+	const labelWidth = 100.0
+
+	for i := 0; i < len(fields); i += 2 {
+		r.ensure(16)
+		for j := i; j < i+2 && j < len(fields); j++ {
+			x := margin + float64(j-i)*columnWidth
+			r.doc.SetFont(Regular, 9)
+			r.doc.Text(x, r.y+10, fit(Regular, 9, fields[j].Label, labelWidth-6), mutedGray)
+			r.doc.SetFont(Bold, 10)
+			r.doc.Text(x+labelWidth, r.y+10, fit(Bold, 10, fields[j].Value, columnWidth-labelWidth-10), 0)
+		}
+		r.y += 16
+	}
+	if len(fields) > 0 {
+		r.y += 4
+	}
+}
+
+func (r *renderer) table(t Table) {
+	widths := columnWidths(t.Columns)
+
+	r.ensure(rowHeight * 2)
+	r.tableRow(t.Columns, widths, columnTitles(t.Columns), Bold, 0.9)
+	for _, row := range t.Rows {
+		if r.ensure(rowHeight) {
+			r.tableRow(t.Columns, widths, columnTitles(t.Columns), Bold, 0.9)
+		}
+		r.tableRow(t.Columns, widths, row, Regular, -1)
+	}
+	if len(t.Totals) > 0 {
+		r.ensure(rowHeight)
+		r.tableRow(t.Columns, widths, t.Totals, Bold, 0.95)
+	}
+}
+
+// tableRow draws one row, filled with the given gray level unless it is negative
+func (r *renderer) tableRow(columns []Column, widths []float64, cells []string, font Font, fill float64) {
+	if fill >= 0 {
+		r.doc.FillRect(margin, r.y, contentWidth, rowHeight, fill)
+	}
+
+	r.doc.SetFont(font, 9)
+	x := margin
+	for i, column := range columns {
+		var cell string
+		if i < len(cells) {
+			cell = fit(font, 9, cells[i], widths[i]-2*cellPadding)
+		}
+		if column.Right {
+			r.doc.TextRight(x+widths[i]-cellPadding, r.y+12.5, cell, 0)
+		} else {
+			r.doc.Text(x+cellPadding, r.y+12.5, cell, 0)
+		}
+		x += widths[i]
+	}
+
+	r.y += rowHeight
+	r.doc.Line(margin, r.y, margin+contentWidth, r.y, 0.5, 0.75)
+}
+
+func (r *renderer) note(text string) {
+	r.y += 6
+	r.doc.SetFont(Regular, 9)
+	for _, line := range wrap(Regular, 9, text, contentWidth) {
+		r.ensure(13)
+		r.doc.Text(margin, r.y+9, line, mutedGray)
+		r.y += 13
+	}
+}
+
+// footers prints the footer text and page numbers once the page count is known
+func (r *renderer) footers(text string) {
+	pages := r.doc.PageCount()
+	for page := 1; page <= pages; page++ {
+		r.doc.SetPage(page)
+		y := PageHeight - margin
+		r.doc.Line(margin, y-14, PageWidth-margin, y-14, 0.5, 0.75)
+		r.doc.SetFont(Regular, 8)
+		r.doc.Text(margin, y, fit(Regular, 8, text, contentWidth-80), mutedGray)
+		r.doc.TextRight(PageWidth-margin, y, fmt.Sprintf("Page %d of %d", page, pages), mutedGray)
+	}
+}
+
+func columnWidths(columns []Column) []float64 {
+	var total float64
+	for _, column := range columns {
+		total += column.Width
+	}
+
+	widths := make([]float64, len(columns))
+	for i, column := range columns {
+		if total > 0 {
+			widths[i] = contentWidth * column.Width / total
+		} else {
+			widths[i] = contentWidth / float64(len(columns))
+		}
+	}
+	return widths
+}
+
+func columnTitles(columns []Column) []string {
+	titles := make([]string, len(columns))
+	for i, column := range columns {
+		titles[i] = column.Title
+	}
+	return titles
+}
+
+// fit shortens s with an ellipsis until it is at most width points wide
+func fit(font Font, size float64, s string, width float64) string {
+	if TextWidth(font, size, s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimRight(string(runes), " ") + "..."
+		if TextWidth(font, size, candidate) <= width {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// wrap breaks text into lines at most width points wide, honouring explicit newlines
+func wrap(font Font, size float64, text string, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && TextWidth(font, size, candidate) > width {
+				lines = append(lines, line)
+				candidate = word
+			}
+			line = fit(font, size, candidate, width)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,144 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// streamStart matches the dictionary and start of a page content stream
+var streamStart = regexp.MustCompile(`<< /Filter /FlateDecode /Length (\d+) >>\nstream\n`)
+
+// pageContents returns the decompressed content stream of every page of a rendered
+// document, which is what the layout drew
+func pageContents(t *testing.T, document []byte) string {
+	t.Helper()
+	var out strings.Builder
+	for i, match := range streamStart.FindAllSubmatchIndex(document, -1) {
+		length, err := strconv.Atoi(string(document[match[2]:match[3]]))
+		if err != nil {
+			t.Fatalf("bad stream length: %v", err)
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(document[match[1] : match[1]+length]))
+		if err != nil {
+			t.Fatalf("page %d: %v", i+1, err)
+		}
+		content, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("page %d: %v", i+1, err)
+		}
+		fmt.Fprintf(&out, "%% page %d\n%s", i+1, content)
+	}
+	return out.String()
+}
+
+// checkGolden compares got with testdata/name, or rewrites it with -update
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v; run with -update to create it", err)
+	}
+	if got != string(want) {
+		t.Errorf("rendered %s differs from the golden file; run with -update if the change is intended\ngot:\n%s", name, got)
+	}
+}
+
+func TestRenderGolden(t *testing.T) {
+	rows := make([][]string, 60)
+	for i := range rows {
+		rows[i] = []string{fmt.Sprintf("Unit test %d", i+1), "2025-01-15", strconv.Itoa(50 + i%50)}
+	}
+
+	tests := []struct {
+		name   string
+		layout Layout
+	}{
+		{"receipt.golden", Layout{
+			Header: Header{
+				Name:      "Sunrise Academy",
+				Lines:     []string{"12 Park Street, Pune", "+91 98000 00000  |  sunrise-academy@example.com"},
+				Title:     "Fee Receipt",
+				TitleInfo: []string{"Receipt No: R-000042", "Date: 2025-01-15"},
+			},
+			Sections: []Section{
+				{Heading: "Received From", Fields: []Field{{"Name", "Aarav"}, {"Student ID", "7"}, {"Grade", "10"}}},
+				{
+					Heading: "Payment",
+					Fields:  []Field{{"Payment mode", "UPI"}, {"Reference", "UPI-1234"}},
+					Table: &Table{
+						Columns: []Column{{Title: "Description", Width: 4}, {Title: "Amount", Width: 1.5, Right: true}},
+						Rows:    [][]string{{"Monthly tuition (Rs.)", "1500.00"}},
+						Totals:  []string{"Total paid", "1500.00"},
+					},
+					Note: "Note: paid for January (late fee waived)",
+				},
+				{Note: "This is a computer-generated receipt and does not require a signature."},
+			},
+			Footer: "Sunrise Academy  |  Generated on 2025-01-15",
+		}},
+		// The table runs over two pages, repeating its header row on the second
+		{"long-table.golden", Layout{
+			Header: Header{Name: "Sunrise Academy", Title: "Report Card"},
+			Sections: []Section{{
+				Heading: "Exam Results",
+				Table: &Table{
+					Columns: []Column{{Title: "Exam", Width: 3}, {Title: "Date", Width: 1.5}, {Title: "Marks", Width: 1, Right: true}},
+					Rows:    rows,
+				},
+				Note: strings.Repeat("A long remark that wraps over several lines of the page. ", 8),
+			}},
+			Footer: "Sunrise Academy  |  Generated on 2025-01-15",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := Render(tt.layout)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if !bytes.HasPrefix(document, []byte("%PDF-1.4")) || !bytes.HasSuffix(document, []byte("%%EOF\n")) {
+				t.Fatal("output is not a complete PDF document")
+			}
+			checkGolden(t, tt.name, pageContents(t, document))
+		})
+	}
+}
+
+func TestFitAndWrap(t *testing.T) {
+	if got := fit(Regular, 10, "short", 100); got != "short" {
+		t.Errorf("fit() = %q, want it unchanged", got)
+	}
+	long := "A business name far too long for its column"
+	got := fit(Regular, 10, long, 80)
+	if !strings.HasSuffix(got, "...") || TextWidth(Regular, 10, got) > 80 {
+		t.Errorf("fit() = %q (%v wide), want an ellipsis within 80", got, TextWidth(Regular, 10, got))
+	}
+
+	lines := wrap(Regular, 9, "first paragraph of several words\nsecond", 60)
+	if len(lines) < 3 || lines[len(lines)-1] != "second" {
+		t.Fatalf("wrap() = %q, want the first paragraph over lines and the second on its own", lines)
+	}
+	for _, line := range lines {
+		if TextWidth(Regular, 9, line) > 60 {
+			t.Errorf("line %q is wider than 60", line)
+		}
+	}
+}
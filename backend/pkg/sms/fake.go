@@ -0,0 +1,42 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// Fake records messages in memory instead of sending them, for tests. Set Err to make
+// every send fail.
+type Fake struct {
+	mu   sync.Mutex
+	sent []Message
+	Err  error
+}
+
+func (f *Fake) Send(ctx context.Context, msg Message) (Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return Result{}, f.Err
+	}
+	f.sent = append(f.sent, msg)
+	return Result{ProviderID: fmt.Sprintf("fake-%d", len(f.sent)), Status: StatusSent}, nil
+}
+
+// ParseStatusCallback accepts unsigned reports with "id", "status" and "error" fields
+func (f *Fake) ParseStatusCallback(form url.Values, signature string) (StatusUpdate, error) {
+	return StatusUpdate{
+		ProviderID: form.Get("id"),
+		Status:     form.Get("status"),
+		Error:      form.Get("error"),
+	}, nil
+}
+
+// Sent returns the messages sent so far
+func (f *Fake) Sent() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Message(nil), f.sent...)
+}
@@ -0,0 +1,94 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+)
+
+// Delivery statuses reported by providers, normalized across them
+const (
+	StatusSent      = "sent" // accepted by the provider, not yet confirmed delivered
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Message is a text message to one phone number in E.164 form, e.g. +919812345678
+type Message struct {
+	To   string
+	Body string
+}
+
+// Result is what the provider reported when it accepted a message
+type Result struct {
+	ProviderID string // the provider's message ID, which status callbacks refer to
+	Status     string
+}
+
+// StatusUpdate is a delivery report sent by the provider after the message was accepted
+type StatusUpdate struct {
+	ProviderID string
+	Status     string
+	Error      string
+}
+
+// Sender is the shared interface for SMS providers
+type Sender interface {
+	Send(ctx context.Context, msg Message) (Result, error)
+	// ParseStatusCallback verifies and parses a delivery report posted by the provider
+	ParseStatusCallback(form url.Values, signature string) (StatusUpdate, error)
+}
+
+// PermanentError is a send failure that retrying won't fix, such as an invalid number
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// IsPermanent reports whether err is a PermanentError
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+// ErrInvalidSignature is returned for status callbacks that weren't signed by the provider
+var ErrInvalidSignature = errors.New("invalid status callback signature")
+
+// Config picks the provider text messages are sent through
+type Config struct {
+	Provider string // twilio, or empty to only log messages
+	Twilio   TwilioConfig
+}
+
+// Providers are the values Config.Provider accepts besides empty
+var Providers = []string{"twilio"}
+
+// New returns the configured provider, or a dry-run sender that only logs when there
+// is none
+func New(config Config) Sender {
+	switch config.Provider {
+	case "":
+		slog.Info("SMS_PROVIDER not set, text messages will only be logged")
+		return DryRun{}
+	case "twilio":
+		return NewTwilio(config.Twilio)
+	default:
+		slog.Error("Unknown SMS_PROVIDER, text messages will only be logged", "provider", config.Provider)
+		return DryRun{}
+	}
+}
+
+// DryRun logs messages instead of sending them, for development
+type DryRun struct{}
+
+func (DryRun) Send(ctx context.Context, msg Message) (Result, error) {
+	slog.Info("SMS not sent (dry run)", "to", msg.To, "length", len(msg.Body))
+	return Result{Status: StatusSent}, nil
+}
+
+func (DryRun) ParseStatusCallback(form url.Values, signature string) (StatusUpdate, error) {
+	return StatusUpdate{}, errors.New("status callbacks are not supported in dry-run mode")
+}
@@ -0,0 +1,160 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+type TwilioConfig struct {
+	AccountSID     string
+	AuthToken      string
+	From           string // sender number, or a messaging service SID starting with MG
+	StatusCallback string // public URL delivery reports are posted to; none when empty
+	BaseURL        string // API root, https://api.twilio.com by default
+}
+
+// Twilio sends messages through the Twilio Messages API, or any provider compatible with it
+type Twilio struct {
+	config TwilioConfig
+	client *http.Client
+}
+
+func NewTwilio(config TwilioConfig) *Twilio {
+	if config.BaseURL == "" {
+		config.BaseURL = "https://api.twilio.com"
+	}
+	return &Twilio{
+		config: config,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type twilioMessage struct {
+	SID          string `json:"sid"`
+	Status       string `json:"status"`
+	ErrorCode    *int   `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+}
+
+type twilioError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (t *Twilio) Send(ctx context.Context, msg Message) (Result, error) {
+	form := url.Values{}
+	form.Set("To", msg.To)
+	form.Set("Body", msg.Body)
+	if strings.HasPrefix(t.config.From, "MG") {
+		form.Set("MessagingServiceSid", t.config.From)
+	} else {
+		form.Set("From", t.config.From)
+	}
+	if t.config.StatusCallback != "" {
+		form.Set("StatusCallback", t.config.StatusCallback)
+	}
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", strings.TrimRight(t.config.BaseURL, "/"), url.PathEscape(t.config.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Result{}, err
+	}
+	req.SetBasicAuth(t.config.AccountSID, t.config.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Result{}, err
+	}
+
+	if resp.StatusCode >= 300 {
+		var apiErr twilioError
+		json.Unmarshal(body, &apiErr)
+		err := fmt.Errorf("twilio: %s (code %d, HTTP %d)", apiErr.Message, apiErr.Code, resp.StatusCode)
+		// Rate limiting and server errors are worth retrying; other client errors aren't
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return Result{}, err
+		}
+		return Result{}, &PermanentError{Err: err}
+	}
+
+	var message twilioMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return Result{}, fmt.Errorf("twilio: invalid response: %w", err)
+	}
+	if status := twilioStatus(message.Status); status == StatusFailed {
+		return Result{}, &PermanentError{Err: fmt.Errorf("twilio: message %s failed: %s", message.SID, message.ErrorMessage)}
+	}
+	return Result{ProviderID: message.SID, Status: twilioStatus(message.Status)}, nil
+}
+
+// ParseStatusCallback checks the X-Twilio-Signature of a delivery report: the base64
+// HMAC-SHA1, keyed with the auth token, of the callback URL followed by every form
+// parameter name and value in name order
+func (t *Twilio) ParseStatusCallback(form url.Values, signature string) (StatusUpdate, error) {
+	if t.config.StatusCallback == "" {
+		return StatusUpdate{}, errors.New("SMS_STATUS_CALLBACK_URL is not configured")
+	}
+
+	names := make([]string, 0, len(form))
+	for name := range form {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var payload strings.Builder
+	payload.WriteString(t.config.StatusCallback)
+	for _, name := range names {
+		for _, value := range form[name] {
+			payload.WriteString(name)
+			payload.WriteString(value)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(t.config.AuthToken))
+	mac.Write([]byte(payload.String()))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return StatusUpdate{}, ErrInvalidSignature
+	}
+
+	update := StatusUpdate{
+		ProviderID: form.Get("MessageSid"),
+		Status:     twilioStatus(form.Get("MessageStatus")),
+	}
+	if update.ProviderID == "" {
+		return StatusUpdate{}, errors.New("status callback has no MessageSid")
+	}
+	if code := form.Get("ErrorCode"); code != "" {
+		update.Error = "twilio error " + code
+	}
+	return update, nil
+}
+
+// twilioStatus maps Twilio's message statuses to the normalized ones
+func twilioStatus(status string) string {
+	switch status {
+	case "delivered", "read":
+		return StatusDelivered
+	case "failed", "undelivered", "canceled":
+		return StatusFailed
+	default:
+		return StatusSent
+	}
+}
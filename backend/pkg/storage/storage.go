@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Storage is the shared interface for storing uploaded files
+type Storage interface {
+	Save(path string, content io.Reader) error
+	Open(path string) (io.ReadCloser, error)
+	Delete(path string) error
+}
+
+// UploadRules describes which uploads are accepted
+type UploadRules struct {
+	MaxSize      int64    // in bytes
+	AllowedTypes []string // detected MIME types, e.g. "application/pdf"
+}
+
+// Validate checks the size and sniffed content type of an uploaded file and returns the content type.
+// The file is rewound so it can be saved afterwards.
+func (r UploadRules) Validate(file multipart.File, header *multipart.FileHeader) (string, error) {
+	if header.Size <= 0 {
+		return "", fmt.Errorf("file is empty")
+	}
+	if r.MaxSize > 0 && header.Size > r.MaxSize {
+		return "", fmt.Errorf("file exceeds the maximum size of %d MB", r.MaxSize/(1<<20))
+	}
+
+	buf := make([]byte, 512)
+	n, err := file.Read(buf)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+
+	contentType := http.DetectContentType(buf[:n])
+	if idx := strings.Index(contentType, ";"); idx != -1 {
+		contentType = contentType[:idx]
+	}
+
+	if len(r.AllowedTypes) == 0 {
+		return contentType, nil
+	}
+	for _, allowed := range r.AllowedTypes {
+		if contentType == allowed {
+			return contentType, nil
+		}
+	}
+	return "", fmt.Errorf("file type %s is not allowed", contentType)
+}
+
+// LocalStorage stores files on the local filesystem below a root directory
+type LocalStorage struct {
+	root string
+}
+
+func NewLocalStorage(root string) *LocalStorage {
+	return &LocalStorage{root: root}
+}
+
+func (s *LocalStorage) Save(path string, content io.Reader) error {
+	fullPath, err := s.resolve(path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(fullPath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		os.Remove(fullPath)
+		return err
+	}
+	return file.Close()
+}
+
+func (s *LocalStorage) Open(path string) (io.ReadCloser, error) {
+	fullPath, err := s.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(fullPath)
+}
+
+func (s *LocalStorage) Delete(path string) error {
+	fullPath, err := s.resolve(path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// resolve maps a storage path to a file below the root, rejecting paths that escape it
+func (s *LocalStorage) resolve(path string) (string, error) {
+	cleaned := filepath.Clean("/" + path)
+	if cleaned == "/" {
+		return "", fmt.Errorf("invalid storage path")
+	}
+	return filepath.Join(s.root, cleaned), nil
+}
+
+// SafeFileName strips directory components and unusual characters from an uploaded file name
+func SafeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('_')
+		}
+	}
+
+	result := strings.Trim(b.String(), ".")
+	if result == "" {
+		return "file"
+	}
+	return result
+}
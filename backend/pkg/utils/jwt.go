@@ -1,36 +1,102 @@
 package utils
 
 import (
-	"os"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// JWTConfig is the secret tokens are signed with and how long user tokens last
+type JWTConfig struct {
+	Secret string
+	Expiry time.Duration
+}
+
+var (
+	jwtConfigMu sync.RWMutex
+	jwtConfig   = JWTConfig{Expiry: 24 * time.Hour}
+)
+
+// ConfigureJWT sets the signing secret and user token lifetime. It must be called
+// before any token is issued or checked.
+func ConfigureJWT(config JWTConfig) {
+	if config.Expiry <= 0 {
+		config.Expiry = 24 * time.Hour
+	}
+	jwtConfigMu.Lock()
+	defer jwtConfigMu.Unlock()
+	jwtConfig = config
+}
+
+func currentJWTConfig() JWTConfig {
+	jwtConfigMu.RLock()
+	defer jwtConfigMu.RUnlock()
+	return jwtConfig
+}
+
+// userSecret signs the tokens users log in with
+func userSecret() []byte {
+	return []byte(currentJWTConfig().Secret)
+}
+
+// TokenScope is the business and profile a user token is issued for, so requests don't
+// have to look them up again. The IDs are fixed when the token is issued: after a
+// profile is created or a business changes hands the user needs a new token, from
+// logging in again or from a token refresh, to carry them. Profile is set when the
+// token acts as another profile the user holds rather than their primary role.
+type TokenScope struct {
+	Profile    string // the role the token acts as, empty for the user's primary role
+	BusinessID uint   // the business the user owns or belongs to, zero for admins
+	TeacherID  uint   // the user's teacher profile, for teachers
+	StudentID  uint   // the user's student profile, for students
+}
+
+// Claims identify the user a token was issued for. The scope IDs are left out while
+// the user has no profile, and handlers then fall back to looking them up. Profile
+// is the role the token acts as after a profile switch; the scope IDs are then that
+// profile's.
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID     uint   `json:"user_id"`
+	Email      string `json:"email"`
+	Role       string `json:"role"`
+	Profile    string `json:"profile,omitempty"`
+	BusinessID uint   `json:"business_id,omitempty"`
+	TeacherID  uint   `json:"teacher_id,omitempty"`
+	StudentID  uint   `json:"student_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint, email, role string) (string, error) {
+func GenerateToken(userID uint, email, role string, scope TokenScope) (string, error) {
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:     userID,
+		Email:      email,
+		Role:       role,
+		Profile:    scope.Profile,
+		BusinessID: scope.BusinessID,
+		TeacherID:  scope.TeacherID,
+		StudentID:  scope.StudentID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(currentJWTConfig().Expiry)),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	return token.SignedString(userSecret())
+}
+
+// ActiveRole is the role the token acts as: the switched-to profile, or the user's
+// primary role
+func (c *Claims) ActiveRole() string {
+	if c.Profile != "" {
+		return c.Profile
+	}
+	return c.Role
 }
 
 func ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET")), nil
+		return userSecret(), nil
 	})
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
@@ -38,3 +104,81 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	}
 	return nil, err
 }
+
+// GuardianClaims identify the guardian link a guardian token was issued for
+type GuardianClaims struct {
+	StudentID uint `json:"student_id"`
+	jwt.RegisteredClaims
+}
+
+// guardianSecret keeps guardian tokens from ever passing as user tokens and vice versa
+func guardianSecret() []byte {
+	return []byte(currentJWTConfig().Secret + ":guardian")
+}
+
+func GenerateGuardianToken(studentID uint, linkID string, expiresAt time.Time) (string, error) {
+	claims := &GuardianClaims{
+		StudentID: studentID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        linkID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(guardianSecret())
+}
+
+func ValidateGuardianToken(tokenString string) (*GuardianClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &GuardianClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return guardianSecret(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*GuardianClaims); ok && token.Valid {
+		return claims, nil
+	}
+	return nil, jwt.ErrTokenInvalidClaims
+}
+
+// CalendarClaims identify the business a calendar feed token was issued for. The tokens
+// don't expire, since calendar clients keep polling the same URL; the business revokes
+// one by issuing another.
+type CalendarClaims struct {
+	BusinessID uint `json:"business_id"`
+	jwt.RegisteredClaims
+}
+
+// calendarSecret keeps calendar tokens from passing as any other kind of token
+func calendarSecret() []byte {
+	return []byte(currentJWTConfig().Secret + ":calendar")
+}
+
+func GenerateCalendarToken(businessID uint, tokenID string) (string, error) {
+	claims := &CalendarClaims{
+		BusinessID: businessID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       tokenID,
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(calendarSecret())
+}
+
+func ValidateCalendarToken(tokenString string) (*CalendarClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &CalendarClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return calendarSecret(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*CalendarClaims); ok && token.Valid {
+		return claims, nil
+	}
+	return nil, jwt.ErrTokenInvalidClaims
+}
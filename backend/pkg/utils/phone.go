@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Digits a phone number may have, as E.164 allows
+const (
+	minPhoneDigits = 7
+	maxPhoneDigits = 15
+)
+
+var (
+	ErrInvalidPhone     = errors.New("invalid phone number")
+	ErrPhoneDigitsCount = errors.New("phone number must have 7 to 15 digits")
+)
+
+var (
+	phoneConfigMu           sync.RWMutex
+	defaultPhoneCountryCode string
+)
+
+// ConfigurePhones sets the calling code, such as "91", given to numbers written without
+// one; a leading + is dropped. When it is empty such numbers are stored as bare digits.
+func ConfigurePhones(countryCode string) {
+	phoneConfigMu.Lock()
+	defer phoneConfigMu.Unlock()
+	defaultPhoneCountryCode = strings.TrimPrefix(strings.TrimSpace(countryCode), "+")
+}
+
+// DefaultPhoneCountryCode is the calling code set by ConfigurePhones
+func DefaultPhoneCountryCode() string {
+	phoneConfigMu.RLock()
+	defer phoneConfigMu.RUnlock()
+	return defaultPhoneCountryCode
+}
+
+// NormalizePhone formats a phone number with the default country code. See
+// NormalizePhoneForCountry.
+func NormalizePhone(value string) (string, error) {
+	return NormalizePhoneForCountry(value, DefaultPhoneCountryCode())
+}
+
+// NormalizePhoneForCountry strips spaces and punctuation from a phone number and
+// returns it as + and digits. A leading + or 00 means the number carries its own
+// country code; otherwise a leading trunk 0 is dropped and countryCode is put in
+// front, so "+91 98765 43210", "9876543210" and "09876543210" all become
+// "+919876543210" for country code 91.
+func NormalizePhoneForCountry(value, countryCode string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	var digits strings.Builder
+	international := false
+	for i, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+			international = true
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+			// formatting only
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+
+	number := digits.String()
+	if !international && strings.HasPrefix(number, "00") {
+		international = true
+		number = number[2:]
+	}
+	if len(number) < minPhoneDigits || len(number) > maxPhoneDigits {
+		return "", ErrPhoneDigitsCount
+	}
+
+	if international {
+		return "+" + number, nil
+	}
+	if countryCode == "" {
+		return number, nil
+	}
+
+	number = countryCode + strings.TrimPrefix(number, "0")
+	if len(number) > maxPhoneDigits {
+		return "", ErrPhoneDigitsCount
+	}
+	return "+" + number, nil
+}
@@ -0,0 +1,9 @@
+package version
+
+// Build information, set at compile time with
+//
+//	go build -ldflags "-X backend/pkg/version.Commit=$(git rev-parse --short HEAD) -X backend/pkg/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)